@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"log/slog"
@@ -10,12 +12,17 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+	_ "time/tzdata" // Daily digest timezones; the runtime image has no zoneinfo
 
 	"github-slack-notifier/internal/config"
+	"github-slack-notifier/internal/faults"
 	"github-slack-notifier/internal/handlers"
 	"github-slack-notifier/internal/log"
 	"github-slack-notifier/internal/middleware"
+	"github-slack-notifier/internal/routes"
+	"github-slack-notifier/internal/selfcheck"
 	"github-slack-notifier/internal/services"
+	"github-slack-notifier/internal/tracing"
 
 	"cloud.google.com/go/firestore"
 	"github.com/gin-gonic/gin"
@@ -23,8 +30,13 @@ import (
 
 const (
 	httpClientTimeout = 30 * time.Second
+	// drainRetryAfter is how long clients rejected during shutdown are asked to wait before retrying.
+	drainRetryAfter = 5 * time.Second
 )
 
+// errNoClientCACerts is returned when TLS_CLIENT_CA_FILE holds no PEM certificates.
+var errNoClientCACerts = errors.New("no certificates found in TLS_CLIENT_CA_FILE")
+
 // App represents the main application structure with all services and handlers.
 type App struct {
 	config            *config.Config
@@ -36,6 +48,8 @@ type App struct {
 	slackHandler      *handlers.SlackHandler
 	jobProcessor      *handlers.JobProcessor
 	oauthHandler      *handlers.OAuthHandler
+	adminHandler      *handlers.AdminHandler
+	healthHandler     *handlers.HealthHandler
 }
 
 func main() {
@@ -75,8 +89,30 @@ func main() {
 
 	ctx := context.Background()
 
+	// Simulated failures for testing retries; nil unless configured outside release mode
+	faultInjector := faults.NewInjector(cfg.FaultInjection)
+	if faultInjector != nil {
+		log.Warn(ctx, "Fault injection is enabled",
+			"slack_rate_limit_percent", cfg.FaultInjection.SlackRateLimitPercent,
+			"github_bad_gateway_percent", cfg.FaultInjection.GitHubBadGatewayPercent,
+			"firestore_timeout_percent", cfg.FaultInjection.FirestoreTimeoutPercent,
+		)
+	}
+
+	// Export traces of webhooks, jobs and outbound calls; trace context is propagated either way
+	shutdownTracing, err := tracing.Setup(ctx, cfg.Tracing)
+	if err != nil {
+		log.Error(ctx, "Failed to set up tracing", "component", "startup", "error", err)
+		os.Exit(1)
+	}
+	if cfg.Tracing.Enabled() {
+		log.Info(ctx, "Tracing is enabled", "otlp_endpoint", cfg.Tracing.OTLPEndpoint, "sample_percent", cfg.Tracing.SamplePercent)
+	}
+
 	log.Info(ctx, "Connecting to Firestore", "project_id", cfg.FirestoreProjectID, "database_id", cfg.FirestoreDatabaseID)
-	firestoreClient, err := firestore.NewClientWithDatabase(ctx, cfg.FirestoreProjectID, cfg.FirestoreDatabaseID)
+	firestoreOptions := append(faultInjector.FirestoreOptions(), tracing.FirestoreOptions(cfg.Tracing)...)
+	firestoreClient, err := firestore.NewClientWithDatabase(ctx, cfg.FirestoreProjectID, cfg.FirestoreDatabaseID,
+		firestoreOptions...)
 	if err != nil {
 		log.Error(ctx, "Failed to create Firestore client", "component", "startup", "error", err)
 		os.Exit(1)
@@ -88,11 +124,17 @@ func main() {
 	}()
 
 	firestoreService := services.NewFirestoreService(firestoreClient)
-	slackWorkspaceService := services.NewSlackWorkspaceService(firestoreClient)
+	tokenEncrypter, err := services.NewTokenEncrypterFromConfig(ctx, cfg)
+	if err != nil {
+		log.Error(ctx, "Failed to create token encrypter", "component", "startup", "error", err)
+		os.Exit(1)
+	}
+	slackWorkspaceService := services.NewSlackWorkspaceService(firestoreClient, tokenEncrypter)
 
 	// Create HTTP client for Slack service
-	slackHTTPClient := &http.Client{Timeout: httpClientTimeout}
+	slackHTTPClient := &http.Client{Timeout: httpClientTimeout, Transport: faultInjector.SlackTransport(http.DefaultTransport)}
 	slackService := services.NewSlackService(slackWorkspaceService, cfg.Emoji, cfg, slackHTTPClient)
+	slackService.SetNotificationLog(firestoreService, cfg.NotificationLogRetention)
 
 	// Initialize Cloud Tasks service
 	cloudTasksConfig := services.CloudTasksConfig{
@@ -114,12 +156,32 @@ func main() {
 	}()
 
 	// Create GitHub API service
-	githubService, err := services.NewGitHubService(cfg, firestoreService)
+	githubService, err := services.NewGitHubServiceWithTransport(cfg, firestoreService,
+		faultInjector.GitHubTransport(http.DefaultTransport))
 	if err != nil {
 		log.Error(context.Background(), "Failed to create GitHub service", "error", err)
 		panic(fmt.Sprintf("failed to initialize GitHub service: %v", err))
 	}
 
+	// Check dependencies up front rather than failing lazily on the first webhook
+	checkDeps := &selfCheckDependencies{
+		firestore:  firestoreService,
+		cloudTasks: cloudTasksService,
+		github:     githubService,
+		slack:      slackService,
+		workspaces: slackWorkspaceService,
+	}
+	if cfg.SelfCheckMode != config.SelfCheckModeOff {
+		report := selfcheck.Run(ctx, buildSelfChecks(ctx, checkDeps), cfg.SelfCheckTimeout)
+		report.Log(ctx)
+
+		if report.HasHardFailures() && cfg.SelfCheckMode == config.SelfCheckModeEnforce {
+			log.Error(ctx, "Refusing to serve traffic after failed self-check", "component", "startup")
+			os.Exit(1)
+		}
+		warnMissingIndexes(ctx, firestoreClient)
+	}
+
 	githubHandler := handlers.NewGitHubHandler(
 		cloudTasksService,
 		firestoreService,
@@ -127,8 +189,17 @@ func main() {
 		githubService,
 		cfg.GitHubWebhookSecret,
 		cfg.Emoji,
+		handlers.GitHubHandlerOptions{
+			PreviousWebhookSecret:    cfg.GitHubWebhookSecretPrevious,
+			EnterpriseWebhookSecrets: cfg.GitHubEnterpriseWebhookSecrets(),
+			StrictChannelMatching:    cfg.StrictChannelMatching,
+			PresentationRules:        cfg.PresentationRules,
+			PRUpdateBudget:           cfg.PRUpdateBudget,
+			WebhookEventRetention:    cfg.WebhookEventRetention,
+			SkipLabel:                cfg.SkipLabel,
+		},
 	)
-	githubAuthService := services.NewGitHubAuthService(cfg, firestoreService)
+	githubAuthService := services.NewGitHubAuthService(cfg, firestoreService, tokenEncrypter)
 
 	// Create HTTP client for OAuth handler
 	oauthHTTPClient := &http.Client{Timeout: httpClientTimeout}
@@ -140,6 +211,11 @@ func main() {
 
 	jobProcessor := handlers.NewJobProcessor(githubHandler, slackHandler, cfg)
 
+	// Readiness runs the same dependency checks as the startup self-check
+	healthHandler := handlers.NewHealthHandler(func(ctx context.Context) []selfcheck.Check {
+		return buildSelfChecks(ctx, checkDeps)
+	}, cfg.SelfCheckTimeout)
+
 	app := &App{
 		config:            cfg,
 		firestoreService:  firestoreService,
@@ -150,34 +226,38 @@ func main() {
 		slackHandler:      slackHandler,
 		jobProcessor:      jobProcessor,
 		oauthHandler:      oauthHandler,
+		adminHandler:      handlers.NewAdminHandler(firestoreService, slackWorkspaceService),
+		healthHandler:     healthHandler,
 	}
 
 	router := gin.Default()
 
+	// Only believe X-Forwarded-For from the configured proxies, so logged client IPs can't be spoofed
+	trustedProxies := make([]string, 0, len(cfg.TrustedProxies))
+	for _, prefix := range cfg.TrustedProxies {
+		trustedProxies = append(trustedProxies, prefix.String())
+	}
+	if err := router.SetTrustedProxies(trustedProxies); err != nil {
+		log.Error(ctx, "Failed to set trusted proxies", "component", "startup", "error", err)
+		os.Exit(1)
+	}
+
 	// Add middleware
 	router.Use(middleware.LoggingMiddleware())
 
-	// Configure webhook routes
-	router.POST("/webhooks/github", app.githubHandler.HandleWebhook)
+	// Track in-flight requests so shutdown can drain them, rejecting new requests once draining starts
+	drainer := middleware.NewDrainer(drainRetryAfter)
+	router.Use(drainer.Middleware())
 
-	// Configure job processing route with Cloud Tasks authentication
-	router.POST("/jobs/process", middleware.CloudTasksAuthMiddleware(cfg), app.jobProcessor.ProcessJob)
-
-	// Configure OAuth routes
-	router.GET("/auth/github/link", app.oauthHandler.HandleGitHubLink)
-	router.GET("/auth/github/callback", app.oauthHandler.HandleGitHubCallback)
-
-	// Configure Slack OAuth routes (if enabled)
-	if cfg.IsSlackOAuthEnabled() {
-		router.GET("/auth/slack/install", app.oauthHandler.HandleSlackInstall)
-		router.GET("/auth/slack/callback", app.oauthHandler.HandleSlackOAuthCallback)
-	}
-
-	router.POST("/webhooks/slack/events", app.slackHandler.HandleEvent)
-	router.POST("/webhooks/slack/interactions", app.slackHandler.HandleInteraction)
-	router.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"status": "healthy"})
-	})
+	// Configure routes under /v1, with the legacy unversioned paths aliased
+	routes.Register(router, &routes.Handlers{
+		GitHub: app.githubHandler,
+		Slack:  app.slackHandler,
+		Jobs:   app.jobProcessor,
+		OAuth:  app.oauthHandler,
+		Admin:  app.adminHandler,
+		Health: app.healthHandler,
+	}, cfg)
 
 	// Setup server logging context
 	serverCtx := log.WithFields(ctx, log.LogFields{
@@ -192,10 +272,24 @@ func main() {
 		ReadTimeout:  cfg.ServerReadTimeout,
 		WriteTimeout: cfg.ServerWriteTimeout,
 	}
+	if cfg.TLSCertFile != "" {
+		tlsConfig, err := serverTLSConfig(cfg)
+		if err != nil {
+			log.Error(serverCtx, "Failed to configure TLS", "error", err)
+			os.Exit(1)
+		}
+		server.TLSConfig = tlsConfig
+	}
 
 	// Start server in a goroutine
 	go func() {
-		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		var err error
+		if cfg.TLSCertFile != "" {
+			err = server.ListenAndServeTLS(cfg.TLSCertFile, cfg.TLSKeyFile)
+		} else {
+			err = server.ListenAndServe()
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
 			log.Error(serverCtx, "Server failed to start", "error", err)
 			os.Exit(1)
 		}
@@ -206,16 +300,50 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
-	log.Info(serverCtx, "Shutting down server...")
+	log.Info(serverCtx, "Shutting down server, draining in-flight requests...")
 
 	// Give outstanding requests time to complete
 	ctx, cancel := context.WithTimeout(context.Background(), cfg.ServerShutdownTimeout)
 	defer cancel()
 
+	// New webhooks and jobs get 503 with Retry-After from here on, so Cloud Tasks and Slack
+	// retry them against another instance rather than losing them mid-rotation
+	if err := drainer.Drain(ctx); err != nil {
+		log.Error(serverCtx, "Timed out draining in-flight requests", "error", err)
+	}
+
 	if err := server.Shutdown(ctx); err != nil {
 		log.Error(serverCtx, "Server forced to shutdown", "error", err)
-		os.Exit(1)
+		// Fall through rather than exiting, so traces are flushed and the deferred client closes still run
+	}
+
+	// There's no other state to flush: the notification and webhook event logs are written to Firestore before
+	// the request that produced them returns, and the repo and user caches only hold copies of Firestore documents
+
+	if err := shutdownTracing(ctx); err != nil {
+		log.Error(serverCtx, "Failed to flush traces", "error", err)
 	}
 
 	log.Info(serverCtx, "Server exited gracefully")
 }
+
+// serverTLSConfig returns the TLS configuration for serving HTTPS. Client certificates are requested but optional,
+// as only the job processor needs them when job queue callbacks are authenticated with mTLS.
+func serverTLSConfig(cfg *config.Config) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if cfg.TLSClientCAFile == "" {
+		return tlsConfig, nil
+	}
+
+	caPEM, err := os.ReadFile(cfg.TLSClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read TLS_CLIENT_CA_FILE: %w", err)
+	}
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("%w: %s", errNoClientCACerts, cfg.TLSClientCAFile)
+	}
+	tlsConfig.ClientCAs = clientCAs
+	tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	return tlsConfig, nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"cloud.google.com/go/firestore"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/migrations"
+	"github-slack-notifier/internal/selfcheck"
+	"github-slack-notifier/internal/services"
+)
+
+// selfCheckDependencies holds the services exercised by the startup self-check.
+type selfCheckDependencies struct {
+	firestore  *services.FirestoreService
+	cloudTasks *services.CloudTasksService
+	github     *services.GitHubService
+	slack      *services.SlackService
+	workspaces *services.SlackWorkspaceService
+}
+
+// buildSelfChecks returns the startup checks. Firestore, Cloud Tasks and GitHub App credentials
+// are hard requirements; each installed Slack workspace gets a soft check so one revoked
+// token doesn't stop the service from serving the others.
+func buildSelfChecks(ctx context.Context, deps *selfCheckDependencies) []selfcheck.Check {
+	checks := []selfcheck.Check{
+		{Name: "firestore", Hard: true, Run: deps.firestore.Ping},
+		{Name: "cloud_tasks_queue", Hard: true, Run: deps.cloudTasks.CheckQueue},
+		{Name: "github_app_credentials", Hard: true, Run: deps.github.CheckAppCredentials},
+	}
+
+	workspaces, err := deps.workspaces.ListWorkspaces(ctx)
+	if err != nil {
+		log.Warn(ctx, "Failed to list Slack workspaces for self-check", "error", err)
+		return append(checks, selfcheck.Check{
+			Name: "slack_workspaces",
+			Run: func(context.Context) error {
+				return fmt.Errorf("failed to list workspaces: %w", err)
+			},
+		})
+	}
+
+	for _, workspace := range workspaces {
+		teamID := workspace.ID
+		checks = append(checks, selfcheck.Check{
+			Name: "slack_auth:" + teamID,
+			Run: func(ctx context.Context) error {
+				return deps.slack.AuthTest(ctx, teamID)
+			},
+		})
+	}
+
+	return checks
+}
+
+// warnMissingIndexes logs a warning for each composite index in firestore.indexes.json that isn't built yet.
+// Queries needing them fail until they are, so this is reported at startup rather than on the first such query.
+func warnMissingIndexes(ctx context.Context, client *firestore.Client) {
+	missing, err := migrations.CheckIndexes(ctx, client)
+	if err != nil {
+		log.Warn(ctx, "Failed to check Firestore composite indexes", "error", err)
+		return
+	}
+	for _, index := range missing {
+		log.Warn(ctx, "Firestore composite index is missing or still building; deploy firestore.indexes.json "+
+			"with scripts/deploy-firestore-indexes.sh, or create it from create_url",
+			"index", index.String(),
+			"create_url", index.CreateURL,
+		)
+	}
+	if len(missing) == 0 {
+		log.Info(ctx, "Firestore composite indexes are built", "index_count", len(migrations.RequiredIndexes))
+	}
+}
@@ -0,0 +1,287 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+
+	"github-slack-notifier/internal/config"
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+)
+
+// Kinds of cross-workspace isolation issue found by audit-isolation.
+const (
+	issueUnconfiguredRepo      = "tracked_message_unconfigured_repo" // Bot message for a repo its workspace doesn't have
+	issueUnknownMessageTeam    = "tracked_message_unknown_workspace" // Message for a workspace that isn't installed
+	issueUnknownUserTeam       = "user_unknown_workspace"            // User in a workspace that isn't installed
+	issueUserIDMismatch        = "user_id_mismatch"                  // User document keyed by another Slack user ID
+	issueRepoIDMismatch        = "repo_id_mismatch"                  // Repo document keyed by another workspace or repo
+	issueRepoOtherInstallation = "repo_installation_other_workspace" // Repo whose GitHub App installations belong elsewhere
+)
+
+// isolationIssue is a document that breaks workspace isolation.
+type isolationIssue struct {
+	kind       string
+	collection string
+	docID      string
+	detail     string
+	repairable bool // Whether --repair deletes the document
+}
+
+// isolationSnapshot holds the documents audit-isolation checks, keyed by document ID.
+type isolationSnapshot struct {
+	workspaces      map[string]*models.SlackWorkspace
+	installations   map[string]*models.GitHubInstallation
+	repos           map[string]*models.Repo
+	users           map[string]*models.User
+	trackedMessages map[string]*models.TrackedMessage
+}
+
+func handleAuditIsolation() {
+	var workspaceID string
+	var repair bool
+
+	fs := flag.NewFlagSet("audit-isolation", flag.ExitOnError)
+	fs.StringVar(&workspaceID, "workspace", "", "Only report issues for this Slack team ID")
+	fs.BoolVar(&repair, "repair", false, "Delete leaked tracked messages")
+	_ = fs.Parse(os.Args[2:])
+
+	cfg := config.Load()
+	ctx := context.Background()
+
+	setupLogging(cfg)
+	firestoreClient := connectFirestore(ctx, cfg)
+	defer func() {
+		if err := firestoreClient.Close(); err != nil {
+			log.Error(context.Background(), "Error closing Firestore client", "error", err)
+		}
+	}()
+
+	snapshot, err := loadIsolationSnapshot(ctx, firestoreClient)
+	if err != nil {
+		log.Error(ctx, "Failed to load documents", "error", err)
+		os.Exit(1)
+	}
+
+	issues := snapshot.audit(workspaceID)
+	printIsolationReport(issues)
+
+	if !repair {
+		return
+	}
+	repaired, err := repairIsolationIssues(ctx, firestoreClient, issues)
+	if err != nil {
+		log.Error(ctx, "Failed to repair isolation issues", "error", err, "repaired", repaired)
+		os.Exit(1)
+	}
+	fmt.Printf("\nDeleted %d leaked tracked messages\n", repaired)
+}
+
+// loadIsolationSnapshot reads every document audit-isolation checks.
+func loadIsolationSnapshot(ctx context.Context, client *firestore.Client) (*isolationSnapshot, error) {
+	snapshot := &isolationSnapshot{}
+	var err error
+	if snapshot.workspaces, err = loadCollection[models.SlackWorkspace](ctx, client, "slack_workspaces"); err != nil {
+		return nil, err
+	}
+	if snapshot.installations, err = loadCollection[models.GitHubInstallation](ctx, client, "github_installations"); err != nil {
+		return nil, err
+	}
+	if snapshot.repos, err = loadCollection[models.Repo](ctx, client, "repos"); err != nil {
+		return nil, err
+	}
+	if snapshot.users, err = loadCollection[models.User](ctx, client, "users"); err != nil {
+		return nil, err
+	}
+	if snapshot.trackedMessages, err = loadCollection[models.TrackedMessage](ctx, client, "trackedmessages"); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// loadCollection reads every document in a collection, keyed by document ID.
+// Documents that can't be unmarshaled are logged and skipped.
+func loadCollection[T any](ctx context.Context, client *firestore.Client, collectionName string) (map[string]*T, error) {
+	iter := client.Collection(collectionName).Documents(ctx)
+	defer iter.Stop()
+
+	docs := make(map[string]*T)
+	for {
+		doc, err := iter.Next()
+		if errors.Is(err, iterator.Done) {
+			return docs, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read collection %s: %w", collectionName, err)
+		}
+
+		var value T
+		if err := doc.DataTo(&value); err != nil {
+			log.Warn(ctx, "Skipping document that can't be unmarshaled",
+				"collection", collectionName, "doc_id", doc.Ref.ID, "error", err)
+			continue
+		}
+		docs[doc.Ref.ID] = &value
+	}
+}
+
+// audit checks the snapshot for cross-workspace leaks, limited to one workspace if workspaceID is set.
+// A workspace is known if it has a Slack installation or configured repos, so deployments using a
+// single bot token without OAuth installations aren't reported wholesale.
+func (s *isolationSnapshot) audit(workspaceID string) []isolationIssue {
+	knownWorkspaces := make(map[string]bool)
+	for id := range s.workspaces {
+		knownWorkspaces[id] = true
+	}
+	workspaceRepos := make(map[string]bool) // {workspace}#{repo}, lowercased
+	for _, repo := range s.repos {
+		knownWorkspaces[repo.WorkspaceID] = true
+		workspaceRepos[strings.ToLower(repo.WorkspaceID+"#"+repo.RepoFullName)] = true
+	}
+	installationWorkspaces := make(map[string][]string) // Account login, lowercased, to owning workspaces
+	for _, installation := range s.installations {
+		if installation.SlackWorkspaceID == "" {
+			continue
+		}
+		login := strings.ToLower(installation.AccountLogin)
+		installationWorkspaces[login] = append(installationWorkspaces[login], installation.SlackWorkspaceID)
+	}
+
+	inScope := func(teamID string) bool {
+		return workspaceID == "" || teamID == workspaceID
+	}
+
+	var issues []isolationIssue
+	for docID, msg := range s.trackedMessages {
+		if !inScope(msg.SlackTeamID) {
+			continue
+		}
+		switch {
+		case msg.SlackTeamID == "" || !knownWorkspaces[msg.SlackTeamID]:
+			issues = append(issues, isolationIssue{
+				kind: issueUnknownMessageTeam, collection: "trackedmessages", docID: docID, repairable: true,
+				detail: fmt.Sprintf("%s#%d in channel %s of unknown workspace %q",
+					msg.RepoFullName, msg.PRNumber, msg.SlackChannel, msg.SlackTeamID),
+			})
+		case msg.MessageSource == models.MessageSourceBot &&
+			!workspaceRepos[strings.ToLower(msg.SlackTeamID+"#"+msg.RepoFullName)]:
+			issues = append(issues, isolationIssue{
+				kind: issueUnconfiguredRepo, collection: "trackedmessages", docID: docID, repairable: true,
+				detail: fmt.Sprintf("%s#%d posted to %s, but workspace %s doesn't have the repository",
+					msg.RepoFullName, msg.PRNumber, msg.SlackChannel, msg.SlackTeamID),
+			})
+		}
+	}
+
+	for docID, user := range s.users {
+		if !inScope(user.SlackTeamID) {
+			continue
+		}
+		if user.SlackTeamID == "" || !knownWorkspaces[user.SlackTeamID] {
+			issues = append(issues, isolationIssue{
+				kind: issueUnknownUserTeam, collection: "users", docID: docID,
+				detail: fmt.Sprintf("%s (GitHub %q) is in unknown workspace %q",
+					user.SlackUserID, user.GitHubUsername, user.SlackTeamID),
+			})
+		}
+		if user.SlackUserID != docID || (user.ID != "" && user.ID != docID) {
+			issues = append(issues, isolationIssue{
+				kind: issueUserIDMismatch, collection: "users", docID: docID,
+				detail: fmt.Sprintf("document holds user %q with Slack user ID %q", user.ID, user.SlackUserID),
+			})
+		}
+	}
+
+	for docID, repo := range s.repos {
+		if !inScope(repo.WorkspaceID) {
+			continue
+		}
+		if expected := repo.WorkspaceID + "#" + url.QueryEscape(repo.RepoFullName); docID != expected {
+			issues = append(issues, isolationIssue{
+				kind: issueRepoIDMismatch, collection: "repos", docID: docID,
+				detail: fmt.Sprintf("holds %s for workspace %s, expected document %s",
+					repo.RepoFullName, repo.WorkspaceID, expected),
+			})
+		}
+
+		owner, _, _ := strings.Cut(repo.RepoFullName, "/")
+		owners := installationWorkspaces[strings.ToLower(owner)]
+		if len(owners) > 0 && !slices.Contains(owners, repo.WorkspaceID) {
+			issues = append(issues, isolationIssue{
+				kind: issueRepoOtherInstallation, collection: "repos", docID: docID,
+				detail: fmt.Sprintf("%s is configured in workspace %s, but %s's GitHub App installations belong to %s",
+					repo.RepoFullName, repo.WorkspaceID, owner, strings.Join(owners, ", ")),
+			})
+		}
+	}
+
+	sort.Slice(issues, func(i, j int) bool {
+		if issues[i].kind != issues[j].kind {
+			return issues[i].kind < issues[j].kind
+		}
+		return issues[i].docID < issues[j].docID
+	})
+	return issues
+}
+
+// printIsolationReport prints each issue grouped by kind, followed by a count per kind.
+func printIsolationReport(issues []isolationIssue) {
+	if len(issues) == 0 {
+		fmt.Println("No workspace isolation issues found")
+		return
+	}
+
+	counts := make(map[string]int)
+	var kinds []string
+	for _, issue := range issues {
+		if counts[issue.kind] == 0 {
+			kinds = append(kinds, issue.kind)
+			fmt.Printf("\n%s:\n", issue.kind)
+		}
+		counts[issue.kind]++
+		fmt.Printf("  %s/%s: %s\n", issue.collection, issue.docID, issue.detail)
+	}
+
+	fmt.Println("\nSummary:")
+	for _, kind := range kinds {
+		fmt.Printf("  %-36s %d\n", kind, counts[kind])
+	}
+	fmt.Println("\nOnly tracked messages are repaired by --repair; other issues need to be fixed by hand.")
+}
+
+// repairIsolationIssues deletes the documents of repairable issues, returning how many were deleted.
+func repairIsolationIssues(ctx context.Context, client *firestore.Client, issues []isolationIssue) (int, error) {
+	bulkWriter := client.BulkWriter(ctx)
+	var jobs []*firestore.BulkWriterJob
+	for _, issue := range issues {
+		if !issue.repairable {
+			continue
+		}
+		job, err := bulkWriter.Delete(client.Collection(issue.collection).Doc(issue.docID))
+		if err != nil {
+			bulkWriter.End()
+			return 0, fmt.Errorf("failed to add delete to bulk writer: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+	bulkWriter.End()
+
+	deleted := 0
+	for _, job := range jobs {
+		if _, err := job.Results(); err != nil {
+			return deleted, fmt.Errorf("failed to delete document: %w", err)
+		}
+		deleted++
+	}
+	return deleted, nil
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github-slack-notifier/internal/models"
+)
+
+func TestIsolationSnapshotAudit(t *testing.T) {
+	workspaces := map[string]*models.SlackWorkspace{"T1": {ID: "T1"}}
+	repos := map[string]*models.Repo{
+		"T1#org%2Fapi": {WorkspaceID: "T1", RepoFullName: "org/api"},
+	}
+
+	tests := []struct {
+		name        string
+		snapshot    *isolationSnapshot
+		workspaceID string
+		expected    []isolationIssue
+	}{
+		{
+			name: "consistent documents",
+			snapshot: &isolationSnapshot{
+				workspaces: workspaces,
+				repos:      repos,
+				installations: map[string]*models.GitHubInstallation{
+					"1": {AccountLogin: "Org", SlackWorkspaceID: "T1"},
+				},
+				users: map[string]*models.User{"U1": {ID: "U1", SlackUserID: "U1", SlackTeamID: "T1"}},
+				trackedMessages: map[string]*models.TrackedMessage{
+					"m1": {SlackTeamID: "T1", RepoFullName: "ORG/api", MessageSource: models.MessageSourceBot},
+					"m2": {SlackTeamID: "T1", RepoFullName: "org/other", MessageSource: models.MessageSourceManual},
+				},
+			},
+		},
+		{
+			name: "tracked messages for unknown workspaces and unconfigured repos are repairable",
+			snapshot: &isolationSnapshot{
+				workspaces: workspaces,
+				repos:      repos,
+				trackedMessages: map[string]*models.TrackedMessage{
+					"m1": {SlackTeamID: "T2", RepoFullName: "org/api", PRNumber: 1, SlackChannel: "C1"},
+					"m2": {SlackTeamID: "T1", RepoFullName: "org/web", PRNumber: 2, SlackChannel: "C1",
+						MessageSource: models.MessageSourceBot},
+				},
+			},
+			expected: []isolationIssue{
+				{
+					kind: issueUnconfiguredRepo, collection: "trackedmessages", docID: "m2", repairable: true,
+					detail: "org/web#2 posted to C1, but workspace T1 doesn't have the repository",
+				},
+				{
+					kind: issueUnknownMessageTeam, collection: "trackedmessages", docID: "m1", repairable: true,
+					detail: `org/api#1 in channel C1 of unknown workspace "T2"`,
+				},
+			},
+		},
+		{
+			name: "workspaces with configured repos are known without an installation",
+			snapshot: &isolationSnapshot{
+				repos: repos,
+				users: map[string]*models.User{"U1": {SlackUserID: "U1", SlackTeamID: "T1"}},
+			},
+		},
+		{
+			name: "users in unknown workspaces or under another ID",
+			snapshot: &isolationSnapshot{
+				workspaces: workspaces,
+				users: map[string]*models.User{
+					"U1": {ID: "U1", SlackUserID: "U1", SlackTeamID: "T9", GitHubUsername: "octocat"},
+					"U2": {ID: "U3", SlackUserID: "U3", SlackTeamID: "T1"},
+				},
+			},
+			expected: []isolationIssue{
+				{
+					kind: issueUserIDMismatch, collection: "users", docID: "U2",
+					detail: `document holds user "U3" with Slack user ID "U3"`,
+				},
+				{
+					kind: issueUnknownUserTeam, collection: "users", docID: "U1",
+					detail: `U1 (GitHub "octocat") is in unknown workspace "T9"`,
+				},
+			},
+		},
+		{
+			name: "repos under the wrong ID or another workspace's installation",
+			snapshot: &isolationSnapshot{
+				workspaces: workspaces,
+				repos: map[string]*models.Repo{
+					"T1#org/api": {WorkspaceID: "T1", RepoFullName: "org/api"},
+				},
+				installations: map[string]*models.GitHubInstallation{
+					"1": {AccountLogin: "org", SlackWorkspaceID: "T2"},
+					"2": {AccountLogin: "org"}, // Not linked to a workspace
+				},
+			},
+			expected: []isolationIssue{
+				{
+					kind: issueRepoIDMismatch, collection: "repos", docID: "T1#org/api",
+					detail: "holds org/api for workspace T1, expected document T1#org%2Fapi",
+				},
+				{
+					kind: issueRepoOtherInstallation, collection: "repos", docID: "T1#org/api",
+					detail: "org/api is configured in workspace T1, but org's GitHub App installations belong to T2",
+				},
+			},
+		},
+		{
+			name: "issues are limited to the requested workspace",
+			snapshot: &isolationSnapshot{
+				workspaces: workspaces,
+				repos:      repos,
+				trackedMessages: map[string]*models.TrackedMessage{
+					"m1": {SlackTeamID: "T2", RepoFullName: "org/api"},
+				},
+				users: map[string]*models.User{"U1": {SlackUserID: "U1", SlackTeamID: "T2"}},
+			},
+			workspaceID: "T1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.snapshot.audit(tt.workspaceID))
+		})
+	}
+}
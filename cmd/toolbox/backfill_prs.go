@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/google/go-github/v74/github"
+	"github.com/google/uuid"
+
+	"github-slack-notifier/internal/config"
+	"github-slack-notifier/internal/handlers"
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+	"github-slack-notifier/internal/services"
+)
+
+const (
+	backfillDefaultInterval = 2 * time.Second
+	backfillSlackTimeout    = 30 * time.Second
+)
+
+func handleBackfillPRs() {
+	var workspaceID, repoFullName string
+	var dryRun bool
+	var interval time.Duration
+
+	fs := flag.NewFlagSet("backfill-prs", flag.ExitOnError)
+	fs.StringVar(&workspaceID, "workspace", "", "Slack team ID of the workspace")
+	fs.StringVar(&repoFullName, "repo", "", "Repository in owner/repo format")
+	fs.BoolVar(&dryRun, "dry-run", false, "List the PRs that would be posted without posting them")
+	fs.DurationVar(&interval, "interval", backfillDefaultInterval, "Time to wait between posting PRs")
+	_ = fs.Parse(os.Args[2:])
+
+	if workspaceID == "" || repoFullName == "" {
+		fmt.Println("Both --workspace and --repo are required")
+		os.Exit(1)
+	}
+
+	cfg := config.Load()
+	ctx := context.Background()
+
+	setupLogging(cfg)
+	firestoreClient := connectFirestore(ctx, cfg)
+	defer func() {
+		if err := firestoreClient.Close(); err != nil {
+			log.Error(context.Background(), "Error closing Firestore client", "error", err)
+		}
+	}()
+	firestoreService := services.NewFirestoreService(firestoreClient)
+
+	repo, err := firestoreService.GetRepo(ctx, repoFullName, workspaceID)
+	if err != nil {
+		log.Error(ctx, "Failed to get repository", "error", err)
+		os.Exit(1)
+	}
+	if repo == nil || !repo.Enabled {
+		fmt.Printf("Repository %s is not configured and enabled in workspace %s\n", repoFullName, workspaceID)
+		os.Exit(1)
+	}
+
+	githubService, err := services.NewGitHubService(cfg, firestoreService)
+	if err != nil {
+		log.Error(ctx, "Failed to create GitHub service", "error", err)
+		os.Exit(1)
+	}
+
+	prs, err := githubService.ListOpenPullRequests(ctx, repoFullName, workspaceID)
+	if err != nil {
+		log.Error(ctx, "Failed to list open PRs", "error", err)
+		os.Exit(1)
+	}
+
+	if dryRun {
+		for _, pr := range prs {
+			status := "would post"
+			if pr.GetDraft() {
+				status = "skip (draft)"
+			}
+			fmt.Printf("#%d %s by %s: %s\n", pr.GetNumber(), pr.GetTitle(), pr.GetUser().GetLogin(), status)
+		}
+		fmt.Printf("\n%d open PRs in %s (dry run, nothing posted)\n", len(prs), repoFullName)
+		return
+	}
+
+	slackWorkspaceService := newSlackWorkspaceService(ctx, cfg, firestoreClient)
+	slackService := services.NewSlackService(slackWorkspaceService, cfg.Emoji, cfg, &http.Client{Timeout: backfillSlackTimeout})
+	slackService.SetNotificationLog(firestoreService, cfg.NotificationLogRetention)
+	cloudTasksService, err := services.NewCloudTasksService(services.CloudTasksConfig{
+		ProjectID: cfg.GoogleCloudProject,
+		Location:  cfg.GCPRegion,
+		QueueName: cfg.CloudTasksQueue,
+		Config:    cfg,
+	})
+	if err != nil {
+		log.Error(ctx, "Failed to create Cloud Tasks service", "error", err)
+		os.Exit(1)
+	}
+	defer func() {
+		if err := cloudTasksService.Close(); err != nil {
+			log.Error(context.Background(), "Error closing Cloud Tasks client", "error", err)
+		}
+	}()
+
+	githubHandler := handlers.NewGitHubHandler(
+		cloudTasksService,
+		firestoreService,
+		slackService,
+		githubService,
+		cfg.GitHubWebhookSecret,
+		cfg.Emoji,
+		handlers.GitHubHandlerOptions{
+			PreviousWebhookSecret:    cfg.GitHubWebhookSecretPrevious,
+			EnterpriseWebhookSecrets: cfg.GitHubEnterpriseWebhookSecrets(),
+			StrictChannelMatching:    cfg.StrictChannelMatching,
+			PresentationRules:        cfg.PresentationRules,
+			PRUpdateBudget:           cfg.PRUpdateBudget,
+			WebhookEventRetention:    cfg.WebhookEventRetention,
+			SkipLabel:                cfg.SkipLabel,
+		},
+	)
+
+	posted, skipped, failed := 0, 0, 0
+	attempted := false
+	for _, pr := range prs {
+		if pr.GetDraft() {
+			fmt.Printf("#%d %s: skipped (draft)\n", pr.GetNumber(), pr.GetTitle())
+			skipped++
+			continue
+		}
+		if attempted {
+			time.Sleep(interval) // Stay well inside Slack's and GitHub's rate limits
+		}
+		attempted = true
+
+		job, err := backfillWorkspacePRJob(pr, workspaceID, slackService)
+		if err == nil {
+			err = githubHandler.ProcessWorkspacePRJob(ctx, job)
+		}
+		if err != nil {
+			fmt.Printf("#%d %s: failed: %v\n", pr.GetNumber(), pr.GetTitle(), err)
+			failed++
+			continue
+		}
+		fmt.Printf("#%d %s: posted\n", pr.GetNumber(), pr.GetTitle())
+		posted++
+	}
+
+	fmt.Printf("\nBackfilled %s: %d posted, %d skipped, %d failed\n", repoFullName, posted, skipped, failed)
+	fmt.Println("PRs already posted in their channel are left as they are.")
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// backfillWorkspacePRJob builds the workspace PR job that posts an open PR as if it had just been opened.
+func backfillWorkspacePRJob(pr *github.PullRequest, workspaceID string, slackService *services.SlackService) (*models.Job, error) {
+	payload := &github.PullRequestEvent{
+		Action:      github.Ptr(handlers.PRActionOpened),
+		Number:      github.Ptr(pr.GetNumber()),
+		PullRequest: pr,
+		Repo:        pr.GetBase().GetRepo(),
+		Sender:      pr.GetUser(),
+	}
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal PR payload: %w", err)
+	}
+
+	annotatedChannel, _ := slackService.ExtractChannelAndDirectives(pr.GetBody())
+	jobID := uuid.New().String()
+	workspacePRJob := &models.WorkspacePRJob{
+		ID:               jobID,
+		PRNumber:         pr.GetNumber(),
+		RepoFullName:     pr.GetBase().GetRepo().GetFullName(),
+		WorkspaceID:      workspaceID,
+		PRAction:         handlers.PRActionOpened,
+		GitHubUserID:     pr.GetUser().GetID(),
+		GitHubUsername:   pr.GetUser().GetLogin(),
+		AnnotatedChannel: annotatedChannel,
+		TraceID:          jobID,
+		PRPayload:        payloadBytes,
+	}
+	jobPayload, err := json.Marshal(workspacePRJob)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal workspace PR job: %w", err)
+	}
+
+	return &models.Job{
+		ID:      jobID,
+		Type:    models.JobTypeWorkspacePR,
+		TraceID: jobID,
+		Payload: jobPayload,
+	}, nil
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-github/v74/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github-slack-notifier/internal/config"
+	"github-slack-notifier/internal/handlers"
+	"github-slack-notifier/internal/models"
+	"github-slack-notifier/internal/services"
+)
+
+func TestBackfillWorkspacePRJob(t *testing.T) {
+	slackService := services.NewSlackService(nil, config.EmojiConfig{}, nil, nil)
+
+	tests := []struct {
+		name            string
+		body            string
+		expectedChannel string
+	}{
+		{
+			name:            "channel directive is annotated",
+			body:            "Adds retries\n\n!review: #dev-team",
+			expectedChannel: "dev-team",
+		},
+		{
+			name:            "no directive",
+			body:            "Adds retries",
+			expectedChannel: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pr := &github.PullRequest{
+				Number: github.Ptr(42),
+				Title:  github.Ptr("Add retries"),
+				Body:   github.Ptr(tt.body),
+				User:   &github.User{ID: github.Ptr(int64(1001)), Login: github.Ptr("octocat")},
+				Base: &github.PullRequestBranch{
+					Repo: &github.Repository{FullName: github.Ptr("testorg/testrepo")},
+				},
+			}
+
+			job, err := backfillWorkspacePRJob(pr, "T123", slackService)
+			require.NoError(t, err)
+			require.NoError(t, job.Validate())
+			assert.Equal(t, models.JobTypeWorkspacePR, job.Type)
+
+			// Decoded the same way as ProcessWorkspacePRJob
+			var workspacePRJob models.WorkspacePRJob
+			require.NoError(t, json.Unmarshal(job.Payload, &workspacePRJob))
+			assert.Equal(t, job.ID, workspacePRJob.ID)
+			assert.Equal(t, 42, workspacePRJob.PRNumber)
+			assert.Equal(t, "testorg/testrepo", workspacePRJob.RepoFullName)
+			assert.Equal(t, "T123", workspacePRJob.WorkspaceID)
+			assert.Equal(t, handlers.PRActionOpened, workspacePRJob.PRAction)
+			assert.Equal(t, int64(1001), workspacePRJob.GitHubUserID)
+			assert.Equal(t, "octocat", workspacePRJob.GitHubUsername)
+			assert.Equal(t, tt.expectedChannel, workspacePRJob.AnnotatedChannel)
+
+			var payload github.PullRequestEvent
+			require.NoError(t, json.Unmarshal(workspacePRJob.PRPayload, &payload))
+			assert.Equal(t, handlers.PRActionOpened, payload.GetAction())
+			assert.Equal(t, 42, payload.GetNumber())
+			assert.Equal(t, "testorg/testrepo", payload.GetRepo().GetFullName())
+			assert.Equal(t, tt.body, payload.GetPullRequest().GetBody())
+			assert.Equal(t, "octocat", payload.GetSender().GetLogin())
+		})
+	}
+}
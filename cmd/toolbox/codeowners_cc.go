@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github-slack-notifier/internal/config"
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/services"
+)
+
+func handleCodeOwnersCC() {
+	if len(os.Args) < minArgsRequired+1 {
+		fmt.Println("Usage: toolbox codeowners-cc <enable|disable|show> --workspace TEAM_ID --repo OWNER/REPO")
+		os.Exit(1)
+	}
+
+	subcommand := os.Args[2]
+	var workspaceID, repoFullName string
+
+	fs := flag.NewFlagSet("codeowners-cc "+subcommand, flag.ExitOnError)
+	fs.StringVar(&workspaceID, "workspace", "", "Slack team ID of the workspace")
+	fs.StringVar(&repoFullName, "repo", "", "Repository in owner/repo format")
+	_ = fs.Parse(os.Args[3:])
+
+	if workspaceID == "" || repoFullName == "" {
+		fmt.Println("Both --workspace and --repo are required")
+		os.Exit(1)
+	}
+
+	cfg := config.Load()
+	ctx := context.Background()
+
+	setupLogging(cfg)
+	firestoreClient := connectFirestore(ctx, cfg)
+	defer func() {
+		if err := firestoreClient.Close(); err != nil {
+			log.Error(context.Background(), "Error closing Firestore client", "error", err)
+		}
+	}()
+	firestoreService := services.NewFirestoreService(firestoreClient)
+
+	repo, err := firestoreService.GetRepo(ctx, repoFullName, workspaceID)
+	if err != nil {
+		log.Error(ctx, "Failed to get repository", "error", err)
+		os.Exit(1)
+	}
+	if repo == nil {
+		fmt.Printf("Repository %s is not configured in workspace %s\n", repoFullName, workspaceID)
+		os.Exit(1)
+	}
+
+	switch subcommand {
+	case "enable", "disable":
+		enabled := subcommand == "enable"
+		if err := firestoreService.UpdateRepoCodeOwnersCC(ctx, repoFullName, workspaceID, enabled); err != nil {
+			log.Error(ctx, "Failed to update CODEOWNERS CC", "error", err)
+			os.Exit(1)
+		}
+		fmt.Printf("CODEOWNERS CC %sd for %s\n", subcommand, repoFullName)
+	case "show":
+		state := "disabled"
+		if repo.CodeOwnersCC {
+			state = "enabled"
+		}
+		fmt.Printf("CODEOWNERS CC for %s is %s\n", repoFullName, state)
+	default:
+		fmt.Printf("Unknown codeowners-cc subcommand: %s\n\n", subcommand)
+		printUsage()
+		os.Exit(1)
+	}
+}
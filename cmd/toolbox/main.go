@@ -14,6 +14,8 @@ import (
 	"cloud.google.com/go/firestore"
 	"github-slack-notifier/internal/config"
 	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/migrations"
+	"github-slack-notifier/internal/services"
 	"google.golang.org/api/iterator"
 )
 
@@ -46,6 +48,30 @@ func main() {
 		handleWipeFirestore()
 	case "dump-firestore":
 		handleDumpFirestore()
+	case "migrate":
+		handleMigrate()
+	case "release-notes":
+		handleReleaseNotes()
+	case "repo-mode":
+		handleRepoMode()
+	case "mechanical-prs":
+		handleMechanicalPRs()
+	case "codeowners-cc":
+		handleCodeOwnersCC()
+	case "audit-isolation":
+		handleAuditIsolation()
+	case "send-test-webhook":
+		handleSendTestWebhook()
+	case "backfill-prs":
+		handleBackfillPRs()
+	case "replay-webhook":
+		handleReplayWebhook()
+	case "notifications":
+		handleNotifications()
+	case "rotate-encryption-key":
+		handleRotateEncryptionKey()
+	case "stats":
+		handleStats()
 	case "help", "-h", "--help":
 		printUsage()
 	default:
@@ -64,6 +90,19 @@ func printUsage() {
 	fmt.Println("Commands:")
 	fmt.Println("  wipe-firestore     Delete all documents from all Firestore collections")
 	fmt.Println("  dump-firestore     Export all documents from all Firestore collections as JSON")
+	fmt.Println("  migrate up         Apply pending Firestore schema migrations")
+	fmt.Println("  migrate status     Show applied and pending Firestore schema migrations, and missing composite indexes")
+	fmt.Println("  release-notes      Enable, disable, or show draft release notes posting for a repository")
+	fmt.Println("  repo-mode          Set or show a repository's notification mode (full, compact, digest_only)")
+	fmt.Println("  mechanical-prs     Set, clear, or show how a repository's revert and back-merge PRs are announced")
+	fmt.Println("  codeowners-cc      Enable, disable, or show CC'ing code owners from CODEOWNERS on a repository's PRs")
+	fmt.Println("  audit-isolation    Report, and optionally repair, data leaking between Slack workspaces")
+	fmt.Println("  send-test-webhook  Send a signed test GitHub webhook to a deployment")
+	fmt.Println("  backfill-prs       Post and track a repository's existing open PRs, e.g. when onboarding it")
+	fmt.Println("  replay-webhook     Process a recorded GitHub webhook again, e.g. to debug a missed notification")
+	fmt.Println("  notifications      List the Slack messages, DMs and reactions sent for a PR, from the notification log")
+	fmt.Println("  rotate-encryption-key  Re-encrypt stored Slack tokens with the current encryption key version")
+	fmt.Println("  stats              Report document counts, workspace activity, and data needing cleanup")
 	fmt.Println("  help               Show this help message")
 	fmt.Println("")
 	fmt.Println("Flags for wipe-firestore:")
@@ -73,20 +112,74 @@ func printUsage() {
 	fmt.Println("  --output FILE      Write output to file instead of stdout")
 	fmt.Println("  --pretty           Pretty-print JSON output")
 	fmt.Println("")
+	fmt.Println("Flags for migrate up:")
+	fmt.Println("  --dry-run          Log changes without writing documents or recording progress")
+	fmt.Println("  --batch-size N     Documents per batch/checkpoint (default 500)")
+	fmt.Println("  --to VERSION       Only apply migrations up to and including VERSION")
+	fmt.Println("")
+	fmt.Println("Flags for release-notes <enable|disable|show>:")
+	fmt.Println("  --workspace ID     Slack team ID of the workspace (required)")
+	fmt.Println("  --repo OWNER/REPO  Repository to configure (required)")
+	fmt.Println("  --channel ID       Slack channel ID that receives draft release notes (enable only)")
+	fmt.Println("  --tag-pattern GLOB Glob pattern for release tags (default \"v*\")")
+	fmt.Println("")
+	fmt.Println("Flags for repo-mode <set|show>:")
+	fmt.Println("  --workspace ID     Slack team ID of the workspace (required)")
+	fmt.Println("  --repo OWNER/REPO  Repository to configure (required)")
+	fmt.Println("  --mode MODE        full, compact, or digest_only (set only)")
+	fmt.Println("")
+	fmt.Println("Flags for mechanical-prs <set|clear|show>:")
+	fmt.Println("  --workspace ID     Slack team ID of the workspace (required)")
+	fmt.Println("  --repo OWNER/REPO  Repository to configure (required)")
+	fmt.Println("  --handling MODE    skip, compact, or channel (set only)")
+	fmt.Println("  --channel CHANNEL  Slack channel name or ID for the channel handling (set only)")
+	fmt.Println("")
+	fmt.Println("Flags for codeowners-cc <enable|disable|show>:")
+	fmt.Println("  --workspace ID     Slack team ID of the workspace (required)")
+	fmt.Println("  --repo OWNER/REPO  Repository to configure (required)")
+	fmt.Println("")
+	fmt.Println("Flags for audit-isolation:")
+	fmt.Println("  --workspace ID     Only report issues for this Slack team ID")
+	fmt.Println("  --repair           Delete leaked tracked messages")
+	fmt.Println("")
+	fmt.Println("Flags for send-test-webhook:")
+	fmt.Println("  --event EVENT      pull_request (default) or pull_request_review")
+	fmt.Println("  --repo OWNER/REPO  Repository in the payload (required)")
+	fmt.Println("  --pr NUMBER        Pull request number (required)")
+	fmt.Println("  --target URL       Deployment base URL or full webhook URL (required)")
+	fmt.Println("  --action ACTION    Event action (default opened, or submitted for reviews)")
+	fmt.Println("  --review-state S   approved, changes_requested, or commented (reviews only)")
+	fmt.Println("  --author LOGIN     GitHub login of the PR author or reviewer (default octocat)")
+	fmt.Println("  --body TEXT        PR description, e.g. to test PR directives")
+	fmt.Println("  --secret SECRET    Webhook secret (default GITHUB_WEBHOOK_SECRET)")
+	fmt.Println("")
+	fmt.Println("Flags for backfill-prs:")
+	fmt.Println("  --workspace ID     Slack team ID of the workspace (required)")
+	fmt.Println("  --repo OWNER/REPO  Repository to backfill (required)")
+	fmt.Println("  --dry-run          List the open PRs that would be posted without posting them")
+	fmt.Println("  --interval DUR     Time to wait between posting PRs (default 2s)")
+	fmt.Println("")
+	fmt.Println("Flags for replay-webhook:")
+	fmt.Println("  --delivery-id ID   X-GitHub-Delivery ID of the webhook to replay (required)")
+	fmt.Println("  --dry-run          Print the recorded headers and payload without replaying")
+	fmt.Println("")
+	fmt.Println("Flags for notifications:")
+	fmt.Println("  --pr REF           PR as OWNER/REPO#NUMBER, or OWNER/REPO for the whole repository")
+	fmt.Println("  --workspace ID     Only show notifications sent to this Slack team ID (required without --pr)")
+	fmt.Println("  --limit N          Most notifications to show, newest first (default 100)")
+	fmt.Println("")
+	fmt.Println("Flags for rotate-encryption-key:")
+	fmt.Println("  --dry-run          Report the tokens that would be re-encrypted without writing them")
+	fmt.Println("")
+	fmt.Println("Flags for stats:")
+	fmt.Println("  --days N           Activity window for active users and inactive repositories (default 30)")
+	fmt.Println("  --limit N          Maximum inactive repositories and OAuth states to list (default 10)")
+	fmt.Println("  --json             Print the report as JSON, e.g. for dashboards")
+	fmt.Println("")
 }
 
-func handleWipeFirestore() {
-	var force bool
-
-	// Parse flags for the wipe-firestore command
-	fs := flag.NewFlagSet("wipe-firestore", flag.ExitOnError)
-	fs.BoolVar(&force, "force", false, "Skip confirmation prompt (DANGEROUS!)")
-	_ = fs.Parse(os.Args[2:])
-
-	cfg := config.Load()
-	ctx := context.Background()
-
-	// Setup structured logging
+// setupLogging configures the default structured logger from configuration.
+func setupLogging(cfg *config.Config) {
 	var logger *slog.Logger
 	isDev := cfg.GinMode != ginModeRelease
 	var logLevel slog.Level
@@ -111,13 +204,81 @@ func handleWipeFirestore() {
 		}))
 	}
 	slog.SetDefault(logger)
+}
 
+// connectFirestore creates a Firestore client, exiting the process on failure.
+func connectFirestore(ctx context.Context, cfg *config.Config) *firestore.Client {
 	log.Info(ctx, "Connecting to Firestore", "project_id", cfg.FirestoreProjectID, "database_id", cfg.FirestoreDatabaseID)
 	firestoreClient, err := firestore.NewClientWithDatabase(ctx, cfg.FirestoreProjectID, cfg.FirestoreDatabaseID)
 	if err != nil {
 		log.Error(ctx, "Failed to create Firestore client", "error", err)
 		os.Exit(1)
 	}
+	return firestoreClient
+}
+
+// newSlackWorkspaceService creates the workspace service, decrypting stored tokens with the configured keys.
+func newSlackWorkspaceService(ctx context.Context, cfg *config.Config, client *firestore.Client) *services.SlackWorkspaceService {
+	return services.NewSlackWorkspaceService(client, newTokenEncrypter(ctx, cfg))
+}
+
+// newTokenEncrypter creates the encrypter for the configured token encryption keys, exiting on failure.
+func newTokenEncrypter(ctx context.Context, cfg *config.Config) *services.TokenEncrypter {
+	tokenEncrypter, err := services.NewTokenEncrypterFromConfig(ctx, cfg)
+	if err != nil {
+		log.Error(ctx, "Failed to create token encrypter", "error", err)
+		os.Exit(1)
+	}
+	return tokenEncrypter
+}
+
+// firestoreCollections returns every collection managed by the application.
+func firestoreCollections() []string {
+	return []string{
+		"users",
+		"repos",
+		"trackedmessages",
+		"thread_replies",
+		"oauth_states",
+		"channel_configs",
+		"github_installations",
+		"slack_workspaces",
+		"slack_enterprise_installations",
+		"slack_user_tokens",
+		"github_user_tokens",
+		"digest_entries",
+		"channel_digest_entries",
+		"directive_usage",
+		"onboarding_hints",
+		"notification_policies",
+		"pending_reviews",
+		"reviewer_rotations",
+		"team_user_groups",
+		"link_invites",
+		"pr_update_budgets",
+		"pr_sequences",
+		"reaction_sync_markers",
+		"webhook_deliveries",
+		"webhook_events",
+		"notification_log",
+		"cleanup_stats",
+		migrations.SchemaVersionsCollection,
+	}
+}
+
+func handleWipeFirestore() {
+	var force bool
+
+	// Parse flags for the wipe-firestore command
+	fs := flag.NewFlagSet("wipe-firestore", flag.ExitOnError)
+	fs.BoolVar(&force, "force", false, "Skip confirmation prompt (DANGEROUS!)")
+	_ = fs.Parse(os.Args[2:])
+
+	cfg := config.Load()
+	ctx := context.Background()
+
+	setupLogging(cfg)
+	firestoreClient := connectFirestore(ctx, cfg)
 	defer func() {
 		if err := firestoreClient.Close(); err != nil {
 			log.Error(context.Background(), "Error closing Firestore client", "error", err)
@@ -166,15 +327,7 @@ func confirmWipeOperation(cfg *config.Config) error {
 }
 
 func wipeAllCollections(ctx context.Context, client *firestore.Client) error {
-	collections := []string{
-		"users",
-		"repos",
-		"trackedmessages",
-		"oauth_states",
-		"channel_configs",
-		"github_installations",
-		"slack_workspaces",
-	}
+	collections := firestoreCollections()
 
 	for _, collection := range collections {
 		log.Info(ctx, "Wiping collection", "collection", collection)
@@ -247,38 +400,8 @@ func handleDumpFirestore() {
 	cfg := config.Load()
 	ctx := context.Background()
 
-	// Setup structured logging
-	var logger *slog.Logger
-	isDev := cfg.GinMode != ginModeRelease
-	var logLevel slog.Level
-	switch cfg.LogLevel {
-	case logLevelDebug:
-		logLevel = slog.LevelDebug
-	case logLevelWarn:
-		logLevel = slog.LevelWarn
-	case logLevelError:
-		logLevel = slog.LevelError
-	default:
-		logLevel = slog.LevelInfo
-	}
-
-	if isDev {
-		logger = slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
-			Level: logLevel,
-		}))
-	} else {
-		logger = slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
-			Level: logLevel,
-		}))
-	}
-	slog.SetDefault(logger)
-
-	log.Info(ctx, "Connecting to Firestore", "project_id", cfg.FirestoreProjectID, "database_id", cfg.FirestoreDatabaseID)
-	firestoreClient, err := firestore.NewClientWithDatabase(ctx, cfg.FirestoreProjectID, cfg.FirestoreDatabaseID)
-	if err != nil {
-		log.Error(ctx, "Failed to create Firestore client", "error", err)
-		os.Exit(1)
-	}
+	setupLogging(cfg)
+	firestoreClient := connectFirestore(ctx, cfg)
 	defer func() {
 		if err := firestoreClient.Close(); err != nil {
 			log.Error(context.Background(), "Error closing Firestore client", "error", err)
@@ -315,15 +438,7 @@ func handleDumpFirestore() {
 }
 
 func dumpAllCollections(ctx context.Context, client *firestore.Client) (map[string]interface{}, error) {
-	collections := []string{
-		"users",
-		"repos",
-		"trackedmessages",
-		"oauth_states",
-		"channel_configs",
-		"github_installations",
-		"slack_workspaces",
-	}
+	collections := firestoreCollections()
 
 	dump := make(map[string]interface{})
 
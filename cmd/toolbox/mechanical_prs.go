@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github-slack-notifier/internal/config"
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+	"github-slack-notifier/internal/services"
+)
+
+func handleMechanicalPRs() {
+	if len(os.Args) < minArgsRequired+1 {
+		fmt.Println("Usage: toolbox mechanical-prs <set|clear|show> --workspace TEAM_ID --repo OWNER/REPO [flags]")
+		os.Exit(1)
+	}
+
+	subcommand := os.Args[2]
+	var workspaceID, repoFullName, handling, channel string
+
+	fs := flag.NewFlagSet("mechanical-prs "+subcommand, flag.ExitOnError)
+	fs.StringVar(&workspaceID, "workspace", "", "Slack team ID of the workspace")
+	fs.StringVar(&repoFullName, "repo", "", "Repository in owner/repo format")
+	fs.StringVar(&handling, "handling", "", "skip, compact, or channel")
+	fs.StringVar(&channel, "channel", "", "Slack channel name or ID for the channel handling")
+	_ = fs.Parse(os.Args[3:])
+
+	if workspaceID == "" || repoFullName == "" {
+		fmt.Println("Both --workspace and --repo are required")
+		os.Exit(1)
+	}
+
+	cfg := config.Load()
+	ctx := context.Background()
+
+	setupLogging(cfg)
+	firestoreClient := connectFirestore(ctx, cfg)
+	defer func() {
+		if err := firestoreClient.Close(); err != nil {
+			log.Error(context.Background(), "Error closing Firestore client", "error", err)
+		}
+	}()
+	firestoreService := services.NewFirestoreService(firestoreClient)
+
+	repo, err := firestoreService.GetRepo(ctx, repoFullName, workspaceID)
+	if err != nil {
+		log.Error(ctx, "Failed to get repository", "error", err)
+		os.Exit(1)
+	}
+	if repo == nil {
+		fmt.Printf("Repository %s is not configured in workspace %s\n", repoFullName, workspaceID)
+		os.Exit(1)
+	}
+
+	switch subcommand {
+	case "set":
+		mechanicalPRs := &models.MechanicalPRConfig{Handling: handling}
+		if handling == models.MechanicalPRHandlingChannel {
+			mechanicalPRs.Channel = channel
+		}
+		if !mechanicalPRs.IsValid() {
+			fmt.Println("--handling must be skip, compact, or channel (with --channel)")
+			os.Exit(1)
+		}
+		if err := firestoreService.UpdateRepoMechanicalPRs(ctx, repoFullName, workspaceID, mechanicalPRs); err != nil {
+			log.Error(ctx, "Failed to set mechanical PR handling", "error", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Revert and back-merge PRs for %s: %s\n", repoFullName, describeMechanicalPRs(mechanicalPRs))
+	case "clear":
+		if err := firestoreService.UpdateRepoMechanicalPRs(ctx, repoFullName, workspaceID, nil); err != nil {
+			log.Error(ctx, "Failed to clear mechanical PR handling", "error", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Revert and back-merge PRs for %s are announced like any other PR\n", repoFullName)
+	case "show":
+		fmt.Printf("Revert and back-merge PRs for %s: %s\n", repoFullName, describeMechanicalPRs(repo.MechanicalPRs))
+	default:
+		fmt.Printf("Unknown mechanical-prs subcommand: %s\n\n", subcommand)
+		printUsage()
+		os.Exit(1)
+	}
+}
+
+// describeMechanicalPRs describes a mechanical PR handling config for display.
+func describeMechanicalPRs(mechanicalPRs *models.MechanicalPRConfig) string {
+	switch {
+	case mechanicalPRs == nil || !mechanicalPRs.IsValid():
+		return "announced like any other PR"
+	case mechanicalPRs.Handling == models.MechanicalPRHandlingChannel:
+		return "posted to " + mechanicalPRs.Channel
+	default:
+		return mechanicalPRs.Handling
+	}
+}
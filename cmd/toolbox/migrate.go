@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"cloud.google.com/go/firestore"
+
+	"github-slack-notifier/internal/config"
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/migrations"
+	"github-slack-notifier/internal/services"
+)
+
+const slackHTTPTimeout = 30 * time.Second
+
+func handleMigrate() {
+	if len(os.Args) < minArgsRequired+1 {
+		fmt.Println("Usage: toolbox migrate <up|status> [flags]")
+		os.Exit(1)
+	}
+
+	subcommand := os.Args[2]
+	var opts migrations.Options
+
+	fs := flag.NewFlagSet("migrate "+subcommand, flag.ExitOnError)
+	fs.BoolVar(&opts.DryRun, "dry-run", false, "Log changes without writing documents or recording progress")
+	fs.IntVar(&opts.BatchSize, "batch-size", migrations.DefaultBatchSize, "Documents per batch/checkpoint")
+	fs.IntVar(&opts.TargetVersion, "to", 0, "Only apply migrations up to and including this version")
+	_ = fs.Parse(os.Args[3:])
+
+	cfg := config.Load()
+	ctx := context.Background()
+
+	setupLogging(cfg)
+	firestoreClient := connectFirestore(ctx, cfg)
+	defer func() {
+		if err := firestoreClient.Close(); err != nil {
+			log.Error(context.Background(), "Error closing Firestore client", "error", err)
+		}
+	}()
+
+	// Migrations that resolve Slack channels use the workspace tokens stored in Firestore
+	slackHTTPClient := &http.Client{Timeout: slackHTTPTimeout}
+	slackService := services.NewSlackService(newSlackWorkspaceService(ctx, cfg, firestoreClient), cfg.Emoji, cfg, slackHTTPClient)
+	deps := migrations.Dependencies{Channels: slackService}
+
+	runner, err := migrations.NewRunner(firestoreClient, migrations.All(deps), opts)
+	if err != nil {
+		log.Error(ctx, "Invalid migration registry", "error", err)
+		os.Exit(1)
+	}
+
+	switch subcommand {
+	case "up":
+		if err := runner.Up(ctx); err != nil {
+			log.Error(ctx, "Migration failed, re-run to resume from the last checkpoint", "error", err)
+			os.Exit(1)
+		}
+		log.Info(ctx, "Migrations complete", "dry_run", opts.DryRun)
+	case "status":
+		printMigrationStatus(ctx, runner)
+		printIndexStatus(ctx, firestoreClient)
+	default:
+		fmt.Printf("Unknown migrate subcommand: %s\n\n", subcommand)
+		printUsage()
+		os.Exit(1)
+	}
+}
+
+func printMigrationStatus(ctx context.Context, runner *migrations.Runner) {
+	statuses, err := runner.Status(ctx)
+	if err != nil {
+		log.Error(ctx, "Failed to load migration status", "error", err)
+		os.Exit(1)
+	}
+
+	for _, status := range statuses {
+		state := "pending"
+		detail := ""
+		if status.State != nil {
+			state = status.State.Status
+			detail = fmt.Sprintf(" (scanned %d, updated %d)", status.State.DocumentsScanned, status.State.DocumentsUpdated)
+		}
+		fmt.Printf("%4d  %-30s  %-20s  %s%s\n",
+			status.Migration.Version, status.Migration.Name, status.Migration.Collection, state, detail)
+	}
+}
+
+// printIndexStatus reports the composite indexes from firestore.indexes.json that aren't built yet.
+func printIndexStatus(ctx context.Context, client *firestore.Client) {
+	missing, err := migrations.CheckIndexes(ctx, client)
+	if err != nil {
+		log.Error(ctx, "Failed to check Firestore composite indexes", "error", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("")
+	if len(missing) == 0 {
+		fmt.Printf("All %d composite indexes are built\n", len(migrations.RequiredIndexes))
+		return
+	}
+	fmt.Printf("%d composite indexes are missing or still building. Deploy them with scripts/deploy-firestore-indexes.sh:\n",
+		len(missing))
+	for _, index := range missing {
+		fmt.Printf("  %s\n", index)
+		if index.CreateURL != "" {
+			fmt.Printf("    create: %s\n", index.CreateURL)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github-slack-notifier/internal/config"
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+	"github-slack-notifier/internal/services"
+)
+
+var ErrInvalidPRReference = errors.New("PR must be given as OWNER/REPO#NUMBER")
+
+func handleNotifications() {
+	var prReference, workspaceID string
+	var limit int
+
+	fs := flag.NewFlagSet("notifications", flag.ExitOnError)
+	fs.StringVar(&prReference, "pr", "", "PR in OWNER/REPO#NUMBER format, or a repository in OWNER/REPO format")
+	fs.StringVar(&workspaceID, "workspace", "", "Only show notifications sent to this Slack team ID")
+	fs.IntVar(&limit, "limit", services.DefaultNotificationLogLimit, "Most notifications to show")
+	_ = fs.Parse(os.Args[2:])
+
+	if prReference == "" && workspaceID == "" {
+		fmt.Println("Usage: toolbox notifications --pr OWNER/REPO#NUMBER [--workspace TEAM_ID] [--limit N]")
+		os.Exit(1)
+	}
+
+	query := services.NotificationLogQuery{SlackTeamID: workspaceID, Limit: limit}
+	if prReference != "" {
+		var err error
+		if query.RepoFullName, query.PRNumber, err = parsePRReference(prReference); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	}
+
+	cfg := config.Load()
+	ctx := context.Background()
+
+	setupLogging(cfg)
+	firestoreClient := connectFirestore(ctx, cfg)
+	defer func() {
+		if err := firestoreClient.Close(); err != nil {
+			log.Error(context.Background(), "Error closing Firestore client", "error", err)
+		}
+	}()
+	firestoreService := services.NewFirestoreService(firestoreClient)
+
+	entries, err := firestoreService.QueryNotificationLog(ctx, query)
+	if err != nil {
+		log.Error(ctx, "Failed to query notification log", "error", err)
+		os.Exit(1)
+	}
+	if len(entries) == 0 {
+		fmt.Println("No notifications found")
+		return
+	}
+
+	for _, entry := range entries {
+		printNotification(entry)
+	}
+}
+
+// parsePRReference splits OWNER/REPO#NUMBER into the repository and PR number. The number is optional,
+// so a whole repository can be looked up.
+func parsePRReference(reference string) (string, int, error) {
+	repoFullName, number, hasNumber := strings.Cut(reference, "#")
+	owner, repo, _ := strings.Cut(repoFullName, "/")
+	if owner == "" || repo == "" {
+		return "", 0, ErrInvalidPRReference
+	}
+	if !hasNumber {
+		return repoFullName, 0, nil
+	}
+
+	prNumber, err := strconv.Atoi(number)
+	if err != nil || prNumber <= 0 {
+		return "", 0, ErrInvalidPRReference
+	}
+	return repoFullName, prNumber, nil
+}
+
+// printNotification prints a notification log entry on one line, with its error on the next if it failed.
+func printNotification(entry *models.NotificationLogEntry) {
+	target := entry.SlackTeamID + "/" + entry.SlackChannel
+	if entry.SlackMessageTS != "" {
+		target += "/" + entry.SlackMessageTS
+	}
+	if entry.SlackUserID != "" {
+		target += " to " + entry.SlackUserID
+	}
+	if entry.Emoji != "" {
+		target += " :" + entry.Emoji + ":"
+	}
+
+	pr := "-"
+	if entry.RepoFullName != "" {
+		pr = entry.RepoFullName
+		if entry.PRNumber != 0 {
+			pr += "#" + strconv.Itoa(entry.PRNumber)
+		}
+	}
+	job := "-"
+	if entry.JobID != "" {
+		job = entry.JobType + " " + entry.JobID
+	}
+
+	fmt.Printf("%s  %-6s  %-9s  %s  pr=%s  job=%s  trace=%s\n",
+		entry.SentAt.UTC().Format(time.RFC3339), entry.Outcome, entry.Kind, target, pr, job, entry.TraceID)
+	if entry.Error != "" {
+		fmt.Printf("    error: %s\n", entry.Error)
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github-slack-notifier/internal/config"
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+	"github-slack-notifier/internal/services"
+)
+
+func handleReleaseNotes() {
+	if len(os.Args) < minArgsRequired+1 {
+		fmt.Println("Usage: toolbox release-notes <enable|disable|show> --workspace TEAM_ID --repo OWNER/REPO [flags]")
+		os.Exit(1)
+	}
+
+	subcommand := os.Args[2]
+	var workspaceID, repoFullName, channel, tagPattern string
+
+	fs := flag.NewFlagSet("release-notes "+subcommand, flag.ExitOnError)
+	fs.StringVar(&workspaceID, "workspace", "", "Slack team ID of the workspace")
+	fs.StringVar(&repoFullName, "repo", "", "Repository in owner/repo format")
+	fs.StringVar(&channel, "channel", "", "Slack channel ID that receives draft release notes")
+	fs.StringVar(&tagPattern, "tag-pattern", models.DefaultReleaseTagPattern, "Glob pattern for release tags")
+	_ = fs.Parse(os.Args[3:])
+
+	if workspaceID == "" || repoFullName == "" {
+		fmt.Println("Both --workspace and --repo are required")
+		os.Exit(1)
+	}
+
+	cfg := config.Load()
+	ctx := context.Background()
+
+	setupLogging(cfg)
+	firestoreClient := connectFirestore(ctx, cfg)
+	defer func() {
+		if err := firestoreClient.Close(); err != nil {
+			log.Error(context.Background(), "Error closing Firestore client", "error", err)
+		}
+	}()
+	firestoreService := services.NewFirestoreService(firestoreClient)
+
+	repo, err := firestoreService.GetRepo(ctx, repoFullName, workspaceID)
+	if err != nil {
+		log.Error(ctx, "Failed to get repository", "error", err)
+		os.Exit(1)
+	}
+	if repo == nil {
+		fmt.Printf("Repository %s is not configured in workspace %s\n", repoFullName, workspaceID)
+		os.Exit(1)
+	}
+
+	switch subcommand {
+	case "enable":
+		if channel == "" {
+			fmt.Println("--channel is required to enable release notes")
+			os.Exit(1)
+		}
+		releaseNotes := &models.ReleaseNotesConfig{Enabled: true, SlackChannel: channel, TagPattern: tagPattern}
+		if err := firestoreService.UpdateRepoReleaseNotes(ctx, repoFullName, workspaceID, releaseNotes); err != nil {
+			log.Error(ctx, "Failed to enable release notes", "error", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Draft release notes enabled for %s tags matching %q, posting to %s\n", repoFullName, tagPattern, channel)
+	case "disable":
+		if err := firestoreService.UpdateRepoReleaseNotes(ctx, repoFullName, workspaceID, nil); err != nil {
+			log.Error(ctx, "Failed to disable release notes", "error", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Draft release notes disabled for %s\n", repoFullName)
+	case "show":
+		if repo.ReleaseNotes == nil || !repo.ReleaseNotes.Enabled {
+			fmt.Printf("Draft release notes are disabled for %s\n", repoFullName)
+			return
+		}
+		fmt.Printf("Draft release notes enabled for %s tags matching %q, posting to %s\n",
+			repoFullName, repo.ReleaseNotes.TagPattern, repo.ReleaseNotes.SlackChannel)
+	default:
+		fmt.Printf("Unknown release-notes subcommand: %s\n\n", subcommand)
+		printUsage()
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github-slack-notifier/internal/config"
+	"github-slack-notifier/internal/handlers"
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+	"github-slack-notifier/internal/services"
+)
+
+const replaySlackTimeout = 30 * time.Second
+
+func handleReplayWebhook() {
+	var deliveryID string
+	var dryRun bool
+
+	fs := flag.NewFlagSet("replay-webhook", flag.ExitOnError)
+	fs.StringVar(&deliveryID, "delivery-id", "", "X-GitHub-Delivery ID of the webhook to replay")
+	fs.BoolVar(&dryRun, "dry-run", false, "Print the recorded webhook without replaying it")
+	_ = fs.Parse(os.Args[2:])
+
+	if deliveryID == "" {
+		fmt.Println("--delivery-id is required")
+		os.Exit(1)
+	}
+
+	cfg := config.Load()
+	ctx := context.Background()
+
+	setupLogging(cfg)
+	firestoreClient := connectFirestore(ctx, cfg)
+	defer func() {
+		if err := firestoreClient.Close(); err != nil {
+			log.Error(context.Background(), "Error closing Firestore client", "error", err)
+		}
+	}()
+	firestoreService := services.NewFirestoreService(firestoreClient)
+
+	event, err := firestoreService.GetWebhookEvent(ctx, deliveryID)
+	if err != nil {
+		log.Error(ctx, "Failed to get webhook event", "error", err)
+		os.Exit(1)
+	}
+	if event == nil {
+		fmt.Printf("No webhook recorded for delivery %s (it may have expired, or the event log is disabled)\n", deliveryID)
+		os.Exit(1)
+	}
+
+	payload, err := event.DecodePayload()
+	if err != nil {
+		log.Error(ctx, "Failed to decode webhook payload", "error", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Delivery %s: %s event received %s (%d bytes)\n",
+		event.ID, event.EventType, event.ReceivedAt.Format(time.RFC3339), event.PayloadSize)
+	if dryRun {
+		printWebhookEvent(event, payload)
+		return
+	}
+
+	githubService, err := services.NewGitHubService(cfg, firestoreService)
+	if err != nil {
+		log.Error(ctx, "Failed to create GitHub service", "error", err)
+		os.Exit(1)
+	}
+	slackWorkspaceService := newSlackWorkspaceService(ctx, cfg, firestoreClient)
+	slackService := services.NewSlackService(slackWorkspaceService, cfg.Emoji, cfg, &http.Client{Timeout: replaySlackTimeout})
+	slackService.SetNotificationLog(firestoreService, cfg.NotificationLogRetention)
+	cloudTasksService, err := services.NewCloudTasksService(services.CloudTasksConfig{
+		ProjectID: cfg.GoogleCloudProject,
+		Location:  cfg.GCPRegion,
+		QueueName: cfg.CloudTasksQueue,
+		Config:    cfg,
+	})
+	if err != nil {
+		log.Error(ctx, "Failed to create Cloud Tasks service", "error", err)
+		os.Exit(1)
+	}
+	defer func() {
+		if err := cloudTasksService.Close(); err != nil {
+			log.Error(context.Background(), "Error closing Cloud Tasks client", "error", err)
+		}
+	}()
+
+	githubHandler := handlers.NewGitHubHandler(
+		cloudTasksService,
+		firestoreService,
+		slackService,
+		githubService,
+		cfg.GitHubWebhookSecret,
+		cfg.Emoji,
+		handlers.GitHubHandlerOptions{
+			PreviousWebhookSecret:    cfg.GitHubWebhookSecretPrevious,
+			EnterpriseWebhookSecrets: cfg.GitHubEnterpriseWebhookSecrets(),
+			StrictChannelMatching:    cfg.StrictChannelMatching,
+			PresentationRules:        cfg.PresentationRules,
+			PRUpdateBudget:           cfg.PRUpdateBudget,
+			WebhookEventRetention:    cfg.WebhookEventRetention,
+			SkipLabel:                cfg.SkipLabel,
+		},
+	)
+
+	job, err := replayWebhookJob(event, payload)
+	if err == nil {
+		err = githubHandler.ProcessWebhookJob(ctx, job)
+	}
+	if err != nil {
+		fmt.Printf("Replay failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Replayed delivery %s with trace ID %s\n", event.ID, job.TraceID)
+	fmt.Println("Jobs it enqueued run on the deployment's job queue. Workspaces already posted to for the delivery are skipped.")
+}
+
+// replayWebhookJob builds the webhook job that re-injects a recorded webhook, as if it had just been received.
+func replayWebhookJob(event *models.WebhookEvent, payload []byte) (*models.Job, error) {
+	jobID := uuid.New().String()
+	webhookJob := &models.WebhookJob{
+		ID:         jobID,
+		EventType:  event.EventType,
+		DeliveryID: event.ID,
+		TraceID:    jobID,
+		Payload:    payload,
+		ReceivedAt: event.ReceivedAt,
+		Status:     "queued",
+		Replay:     true,
+		GitHubHost: strings.ToLower(event.Headers["X-Github-Enterprise-Host"]),
+	}
+	jobPayload, err := json.Marshal(webhookJob)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal webhook job: %w", err)
+	}
+
+	return &models.Job{
+		ID:      jobID,
+		Type:    models.JobTypeGitHubWebhook,
+		TraceID: jobID,
+		Payload: jobPayload,
+	}, nil
+}
+
+// printWebhookEvent prints a recorded webhook's headers and payload.
+func printWebhookEvent(event *models.WebhookEvent, payload []byte) {
+	names := make([]string, 0, len(event.Headers))
+	for name := range event.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Println("")
+	for _, name := range names {
+		fmt.Printf("%s: %s\n", name, event.Headers[name])
+	}
+	fmt.Println("")
+	fmt.Println(string(payload))
+}
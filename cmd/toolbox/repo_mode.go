@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github-slack-notifier/internal/config"
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+	"github-slack-notifier/internal/services"
+)
+
+func handleRepoMode() {
+	if len(os.Args) < minArgsRequired+1 {
+		fmt.Println("Usage: toolbox repo-mode <set|show> --workspace TEAM_ID --repo OWNER/REPO [--mode MODE]")
+		os.Exit(1)
+	}
+
+	subcommand := os.Args[2]
+	var workspaceID, repoFullName, mode string
+
+	fs := flag.NewFlagSet("repo-mode "+subcommand, flag.ExitOnError)
+	fs.StringVar(&workspaceID, "workspace", "", "Slack team ID of the workspace")
+	fs.StringVar(&repoFullName, "repo", "", "Repository in owner/repo format")
+	fs.StringVar(&mode, "mode", "", "Notification mode: full, compact, or digest_only")
+	_ = fs.Parse(os.Args[3:])
+
+	if workspaceID == "" || repoFullName == "" {
+		fmt.Println("Both --workspace and --repo are required")
+		os.Exit(1)
+	}
+
+	cfg := config.Load()
+	ctx := context.Background()
+
+	setupLogging(cfg)
+	firestoreClient := connectFirestore(ctx, cfg)
+	defer func() {
+		if err := firestoreClient.Close(); err != nil {
+			log.Error(context.Background(), "Error closing Firestore client", "error", err)
+		}
+	}()
+	firestoreService := services.NewFirestoreService(firestoreClient)
+
+	repo, err := firestoreService.GetRepo(ctx, repoFullName, workspaceID)
+	if err != nil {
+		log.Error(ctx, "Failed to get repository", "error", err)
+		os.Exit(1)
+	}
+	if repo == nil {
+		fmt.Printf("Repository %s is not configured in workspace %s\n", repoFullName, workspaceID)
+		os.Exit(1)
+	}
+
+	switch subcommand {
+	case "set":
+		if !models.IsValidNotificationMode(mode) {
+			fmt.Printf("--mode must be one of %s, %s, or %s\n",
+				models.NotificationModeFull, models.NotificationModeCompact, models.NotificationModeDigestOnly)
+			os.Exit(1)
+		}
+		if err := firestoreService.UpdateRepoNotificationMode(ctx, repoFullName, workspaceID, mode); err != nil {
+			log.Error(ctx, "Failed to set notification mode", "error", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Notification mode for %s set to %s\n", repoFullName, mode)
+	case "show":
+		fmt.Printf("Notification mode for %s is %s\n", repoFullName, repo.GetNotificationMode())
+	default:
+		fmt.Printf("Unknown repo-mode subcommand: %s\n\n", subcommand)
+		printUsage()
+		os.Exit(1)
+	}
+}
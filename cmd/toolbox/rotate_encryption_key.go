@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+
+	"github-slack-notifier/internal/config"
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/services"
+)
+
+// tokenCollections are the Firestore collections whose documents store encrypted tokens.
+var tokenCollections = []string{"slack_workspaces", "slack_enterprise_installations", "slack_user_tokens", "github_user_tokens"}
+
+// tokenFields are the document fields that hold encrypted tokens. Only GitHub user tokens have a refresh_token.
+var tokenFields = []string{"access_token", "refresh_token"}
+
+// tokenRotationStats counts what happened to the tokens in a collection.
+type tokenRotationStats struct {
+	rotated int
+	current int
+	failed  int
+}
+
+func handleRotateEncryptionKey() {
+	var dryRun bool
+
+	fs := flag.NewFlagSet("rotate-encryption-key", flag.ExitOnError)
+	fs.BoolVar(&dryRun, "dry-run", false, "Report the tokens that would be re-encrypted without writing them")
+	_ = fs.Parse(os.Args[2:])
+
+	cfg := config.Load()
+	ctx := context.Background()
+
+	setupLogging(cfg)
+	tokenEncrypter := newTokenEncrypter(ctx, cfg)
+	if tokenEncrypter == nil {
+		fmt.Println("Set TOKEN_KMS_KEY or TOKEN_ENCRYPTION_KEY to the key tokens should be encrypted with")
+		os.Exit(1)
+	}
+	keyVersion, err := tokenEncrypter.CurrentKeyVersion(ctx)
+	if err != nil {
+		log.Error(ctx, "Failed to find current encryption key version", "error", err)
+		os.Exit(1)
+	}
+
+	firestoreClient := connectFirestore(ctx, cfg)
+	defer func() {
+		if err := firestoreClient.Close(); err != nil {
+			log.Error(context.Background(), "Error closing Firestore client", "error", err)
+		}
+	}()
+
+	failed := false
+	for _, collectionName := range tokenCollections {
+		stats, err := rotateCollectionTokens(ctx, firestoreClient, tokenEncrypter, collectionName, keyVersion, dryRun)
+		if err != nil {
+			log.Error(ctx, "Failed to rotate tokens", "collection", collectionName, "error", err)
+			os.Exit(1)
+		}
+		fmt.Printf("%s: %d re-encrypted, %d already current, %d failed\n", collectionName, stats.rotated, stats.current, stats.failed)
+		failed = failed || stats.failed > 0
+	}
+
+	if dryRun {
+		fmt.Println("Dry run: no tokens were written")
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// rotateCollectionTokens re-encrypts the tokens in a collection that aren't encrypted with keyVersion,
+// including tokens still stored in plain text. Tokens that can't be decrypted are logged and left as they are,
+// so one bad document doesn't stop the rotation; re-running it only touches the tokens that still need it.
+func rotateCollectionTokens(ctx context.Context, client *firestore.Client, tokenEncrypter *services.TokenEncrypter,
+	collectionName, keyVersion string, dryRun bool,
+) (tokenRotationStats, error) {
+	var stats tokenRotationStats
+
+	iter := client.Collection(collectionName).Documents(ctx)
+	defer iter.Stop()
+	for {
+		doc, err := iter.Next()
+		if errors.Is(err, iterator.Done) {
+			return stats, nil
+		}
+		if err != nil {
+			return stats, fmt.Errorf("failed to read collection %s: %w", collectionName, err)
+		}
+
+		docCtx := log.WithFields(ctx, log.LogFields{"collection": collectionName, "doc_id": doc.Ref.ID})
+		updates, err := reencryptTokenFields(docCtx, tokenEncrypter, doc.Data(), keyVersion, dryRun)
+		if err != nil {
+			stats.failed++
+			continue
+		}
+		if len(updates) == 0 {
+			stats.current++
+			continue
+		}
+		if dryRun {
+			stats.rotated++
+			continue
+		}
+
+		// Only write if the tokens haven't changed since they were read, e.g. by the workspace being reinstalled
+		_, err = doc.Ref.Update(docCtx, updates, firestore.LastUpdateTime(doc.UpdateTime))
+		if err != nil {
+			log.Error(docCtx, "Failed to save re-encrypted token", "error", err)
+			stats.failed++
+			continue
+		}
+		stats.rotated++
+	}
+}
+
+// reencryptTokenFields returns the updates re-encrypting the tokens in a document's data that aren't encrypted with
+// keyVersion, or none if they all are. In a dry run the updates hold no values.
+func reencryptTokenFields(ctx context.Context, tokenEncrypter *services.TokenEncrypter, data map[string]interface{},
+	keyVersion string, dryRun bool,
+) ([]firestore.Update, error) {
+	var updates []firestore.Update
+	for _, field := range tokenFields {
+		stored, _ := data[field].(string)
+		storedVersion := services.StoredTokenKeyVersion(stored)
+		if stored == "" || storedVersion == keyVersion {
+			continue
+		}
+
+		fieldCtx := log.WithFields(ctx, log.LogFields{"field": field})
+		token, err := tokenEncrypter.Decrypt(fieldCtx, stored)
+		if err != nil {
+			log.Error(fieldCtx, "Failed to decrypt token", "key_version", storedVersion, "error", err)
+			return nil, err
+		}
+		if dryRun {
+			log.Info(fieldCtx, "Would re-encrypt token", "from_key_version", storedVersion, "to_key_version", keyVersion)
+			updates = append(updates, firestore.Update{Path: field})
+			continue
+		}
+
+		encrypted, err := tokenEncrypter.Encrypt(fieldCtx, token)
+		if err != nil {
+			log.Error(fieldCtx, "Failed to encrypt token", "error", err)
+			return nil, err
+		}
+		log.Info(fieldCtx, "Re-encrypting token", "from_key_version", storedVersion, "to_key_version", keyVersion)
+		updates = append(updates, firestore.Update{Path: field, Value: encrypted})
+	}
+	return updates, nil
+}
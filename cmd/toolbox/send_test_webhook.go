@@ -0,0 +1,249 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v74/github"
+	"github.com/google/uuid"
+
+	"github-slack-notifier/internal/config"
+)
+
+const (
+	testWebhookPath    = "/webhooks/github"
+	testWebhookTimeout = 30 * time.Second
+	// Fake IDs used in test payloads, where the real ones aren't known.
+	testWebhookRepoID = 1296269
+	testWebhookPRID   = 1347
+	testWebhookUserID = 583231
+)
+
+// Test webhook errors.
+var (
+	ErrInvalidWebhookTarget       = errors.New("expected an http(s) URL")
+	ErrInvalidWebhookRepo         = errors.New("--repo must be in owner/repo format")
+	ErrUnsupportedTestWebhookType = errors.New("unsupported --event: use pull_request or pull_request_review")
+)
+
+// testWebhookOptions describes the webhook send-test-webhook sends.
+type testWebhookOptions struct {
+	event          string
+	repoFullName   string
+	prNumber       int
+	action         string // Defaults to opened, or submitted for reviews
+	reviewState    string
+	author         string
+	authorID       int64
+	title          string
+	body           string
+	draft          bool
+	installationID int64 // Left out of the payload when 0
+}
+
+func handleSendTestWebhook() {
+	var opts testWebhookOptions
+	var target, secret string
+
+	fs := flag.NewFlagSet("send-test-webhook", flag.ExitOnError)
+	fs.StringVar(&opts.event, "event", "pull_request", "Event type: pull_request or pull_request_review")
+	fs.StringVar(&opts.repoFullName, "repo", "", "Repository in owner/repo format")
+	fs.IntVar(&opts.prNumber, "pr", 0, "Pull request number")
+	fs.StringVar(&target, "target", "", "Base URL of the deployment, or the full webhook URL")
+	fs.StringVar(&opts.action, "action", "", "Event action (default opened, or submitted for reviews)")
+	fs.StringVar(&opts.reviewState, "review-state", "approved",
+		"Review state for pull_request_review: approved, changes_requested, or commented")
+	fs.StringVar(&opts.author, "author", "octocat", "GitHub login of the PR author (or the reviewer, for reviews)")
+	fs.Int64Var(&opts.authorID, "author-id", testWebhookUserID, "GitHub user ID of the author")
+	fs.StringVar(&opts.title, "title", "Test PR from toolbox", "Pull request title")
+	fs.StringVar(&opts.body, "body", "", "Pull request description, e.g. to include PR directives")
+	fs.BoolVar(&opts.draft, "draft", false, "Mark the pull request as a draft")
+	fs.Int64Var(&opts.installationID, "installation-id", 0, "GitHub App installation ID to include in the payload")
+	fs.StringVar(&secret, "secret", "", "Webhook secret to sign with (default GITHUB_WEBHOOK_SECRET)")
+	_ = fs.Parse(os.Args[2:])
+
+	if opts.repoFullName == "" || opts.prNumber <= 0 || target == "" {
+		fmt.Println("--repo, --pr and --target are required")
+		os.Exit(1)
+	}
+
+	webhookURL, err := testWebhookURL(target)
+	if err != nil {
+		fmt.Printf("Invalid --target: %v\n", err)
+		os.Exit(1)
+	}
+
+	payload, action, err := buildTestWebhookPayload(opts, time.Now())
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	if secret == "" {
+		secret = config.Load().GitHubWebhookSecret
+	}
+	if secret == "" {
+		fmt.Println("No webhook secret: set GITHUB_WEBHOOK_SECRET or pass --secret")
+		os.Exit(1)
+	}
+
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		fmt.Printf("Failed to marshal payload: %v\n", err)
+		os.Exit(1)
+	}
+
+	deliveryID := uuid.New().String()
+	status, respBody, err := sendTestWebhook(context.Background(), webhookURL, opts.event, deliveryID, secret, payloadBytes)
+	if err != nil {
+		fmt.Printf("Failed to send webhook: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Sent %s (%s) for %s#%d to %s\n", opts.event, action, opts.repoFullName, opts.prNumber, webhookURL)
+	fmt.Printf("Delivery ID: %s\n", deliveryID)
+	fmt.Printf("Response: %d %s\n", status, strings.TrimSpace(respBody))
+	if status < http.StatusOK || status >= http.StatusMultipleChoices {
+		os.Exit(1)
+	}
+}
+
+// buildTestWebhookPayload builds the event payload for the options, with fake IDs where the real ones aren't known.
+// Returns the payload and the action it carries.
+func buildTestWebhookPayload(opts testWebhookOptions, at time.Time) (any, string, error) {
+	owner, repoName, found := strings.Cut(opts.repoFullName, "/")
+	if !found || owner == "" || repoName == "" {
+		return nil, "", ErrInvalidWebhookRepo
+	}
+
+	repoFullName, prNumber := opts.repoFullName, opts.prNumber
+	repo := &github.Repository{
+		ID:       github.Ptr(int64(testWebhookRepoID)),
+		Name:     github.Ptr(repoName),
+		FullName: github.Ptr(repoFullName),
+		HTMLURL:  github.Ptr("https://github.com/" + repoFullName),
+		Owner:    &github.User{Login: github.Ptr(owner)},
+	}
+	user := &github.User{ID: github.Ptr(opts.authorID), Login: github.Ptr(opts.author), Type: github.Ptr("User")}
+	now := github.Timestamp{Time: at}
+	pr := &github.PullRequest{
+		ID:        github.Ptr(int64(testWebhookPRID)),
+		Number:    github.Ptr(prNumber),
+		State:     github.Ptr("open"),
+		Title:     github.Ptr(opts.title),
+		Body:      github.Ptr(opts.body),
+		Draft:     github.Ptr(opts.draft),
+		HTMLURL:   github.Ptr(fmt.Sprintf("https://github.com/%s/pull/%d", repoFullName, prNumber)),
+		User:      user,
+		Additions: github.Ptr(42),
+		Deletions: github.Ptr(7),
+		CreatedAt: &now,
+		UpdatedAt: &now,
+		Head:      &github.PullRequestBranch{Ref: github.Ptr("toolbox-test"), SHA: github.Ptr(strings.Repeat("a", 40))},
+		Base:      &github.PullRequestBranch{Ref: github.Ptr("main"), SHA: github.Ptr(strings.Repeat("b", 40))},
+	}
+	var installation *github.Installation
+	if opts.installationID != 0 {
+		installation = &github.Installation{ID: github.Ptr(opts.installationID)}
+	}
+
+	action := opts.action
+	switch opts.event {
+	case "pull_request":
+		if action == "" {
+			action = "opened"
+		}
+		if action == "closed" {
+			pr.State = github.Ptr("closed")
+			pr.ClosedAt = &now
+		}
+		return &github.PullRequestEvent{
+			Action:       github.Ptr(action),
+			Number:       github.Ptr(prNumber),
+			PullRequest:  pr,
+			Repo:         repo,
+			Sender:       user,
+			Installation: installation,
+		}, action, nil
+	case "pull_request_review":
+		if action == "" {
+			action = "submitted"
+		}
+		return &github.PullRequestReviewEvent{
+			Action: github.Ptr(action),
+			Review: &github.PullRequestReview{
+				ID:          github.Ptr(int64(1)),
+				User:        user,
+				State:       github.Ptr(opts.reviewState),
+				HTMLURL:     github.Ptr(pr.GetHTMLURL() + "#pullrequestreview-1"),
+				SubmittedAt: &now,
+			},
+			PullRequest:  pr,
+			Repo:         repo,
+			Sender:       user,
+			Installation: installation,
+		}, action, nil
+	default:
+		return nil, "", fmt.Errorf("%w (got %s)", ErrUnsupportedTestWebhookType, opts.event)
+	}
+}
+
+// testWebhookURL returns the webhook URL for a target, adding the GitHub webhook path to a bare base URL.
+func testWebhookURL(target string) (string, error) {
+	u, err := url.Parse(target)
+	if err != nil {
+		return "", err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("%w: %q", ErrInvalidWebhookTarget, target)
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = testWebhookPath
+	}
+	return u.String(), nil
+}
+
+// sendTestWebhook posts a payload signed like GitHub's webhooks, returning the response status and body.
+func sendTestWebhook(ctx context.Context, webhookURL, event, deliveryID, secret string, payload []byte) (int, string, error) {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	signature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	ctx, cancel := context.WithTimeout(ctx, testWebhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return 0, "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "GitHub-Hookshot/toolbox")
+	req.Header.Set("X-GitHub-Event", event)
+	req.Header.Set("X-GitHub-Delivery", deliveryID)
+	req.Header.Set("X-Hub-Signature-256", signature)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, "", err
+	}
+	return resp.StatusCode, string(respBody), nil
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v74/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestWebhookURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   string
+		expected string
+		wantErr  bool
+	}{
+		{"base URL gets the webhook path", "https://notifier.example.com", "https://notifier.example.com/webhooks/github", false},
+		{"trailing slash", "https://notifier.example.com/", "https://notifier.example.com/webhooks/github", false},
+		{"full webhook URL is kept", "http://localhost:8080/v1/webhooks/github", "http://localhost:8080/v1/webhooks/github", false},
+		{"missing scheme", "notifier.example.com", "", true},
+		{"unsupported scheme", "ftp://notifier.example.com", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			webhookURL, err := testWebhookURL(tt.target)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidWebhookTarget)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, webhookURL)
+		})
+	}
+}
+
+func TestBuildTestWebhookPayload(t *testing.T) {
+	at := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	base := testWebhookOptions{
+		event:        "pull_request",
+		repoFullName: "testorg/testrepo",
+		prNumber:     42,
+		reviewState:  "approved",
+		author:       "octocat",
+		authorID:     testWebhookUserID,
+		title:        "Test PR",
+		body:         "!review: #dev-team",
+	}
+
+	tests := []struct {
+		name           string
+		modify         func(*testWebhookOptions)
+		expectedAction string
+		expectedErr    error
+		check          func(t *testing.T, payload any)
+	}{
+		{
+			name:           "pull request defaults to opened",
+			modify:         func(*testWebhookOptions) {},
+			expectedAction: "opened",
+			check: func(t *testing.T, payload any) {
+				t.Helper()
+				event, ok := payload.(*github.PullRequestEvent)
+				require.True(t, ok)
+				assert.Equal(t, "open", event.GetPullRequest().GetState())
+				assert.Equal(t, "testorg/testrepo", event.GetRepo().GetFullName())
+				assert.Equal(t, "testorg", event.GetRepo().GetOwner().GetLogin())
+				assert.Equal(t, "!review: #dev-team", event.GetPullRequest().GetBody())
+				assert.Nil(t, event.Installation)
+			},
+		},
+		{
+			name: "closed pull request",
+			modify: func(o *testWebhookOptions) {
+				o.action = "closed"
+				o.installationID = 7
+			},
+			expectedAction: "closed",
+			check: func(t *testing.T, payload any) {
+				t.Helper()
+				event, ok := payload.(*github.PullRequestEvent)
+				require.True(t, ok)
+				assert.Equal(t, "closed", event.GetPullRequest().GetState())
+				assert.Equal(t, at, event.GetPullRequest().GetClosedAt().Time)
+				assert.Equal(t, int64(7), event.GetInstallation().GetID())
+			},
+		},
+		{
+			name:           "review defaults to submitted",
+			modify:         func(o *testWebhookOptions) { o.event = "pull_request_review" },
+			expectedAction: "submitted",
+			check: func(t *testing.T, payload any) {
+				t.Helper()
+				event, ok := payload.(*github.PullRequestReviewEvent)
+				require.True(t, ok)
+				assert.Equal(t, "approved", event.GetReview().GetState())
+				assert.Equal(t, "octocat", event.GetReview().GetUser().GetLogin())
+				assert.Equal(t, 42, event.GetPullRequest().GetNumber())
+			},
+		},
+		{
+			name:        "repo without owner",
+			modify:      func(o *testWebhookOptions) { o.repoFullName = "testrepo" },
+			expectedErr: ErrInvalidWebhookRepo,
+		},
+		{
+			name:        "unsupported event",
+			modify:      func(o *testWebhookOptions) { o.event = "issues" },
+			expectedErr: ErrUnsupportedTestWebhookType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := base
+			tt.modify(&opts)
+
+			payload, action, err := buildTestWebhookPayload(opts, at)
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedAction, action)
+			tt.check(t, payload)
+		})
+	}
+}
+
+func TestSendTestWebhook(t *testing.T) {
+	payload := []byte(`{"action":"opened"}`)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "pull_request", r.Header.Get("X-GitHub-Event"))
+		assert.Equal(t, "delivery-1", r.Header.Get("X-GitHub-Delivery"))
+
+		// Signed the way the webhook handler validates
+		body, err := github.ValidatePayload(r, []byte("test-secret"))
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = io.WriteString(w, string(body))
+	}))
+	defer server.Close()
+
+	status, respBody, err := sendTestWebhook(context.Background(), server.URL, "pull_request", "delivery-1", "test-secret", payload)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, string(payload), respBody)
+
+	status, _, err = sendTestWebhook(context.Background(), server.URL, "pull_request", "delivery-1", "wrong-secret", payload)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, status)
+}
@@ -0,0 +1,435 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+
+	"github-slack-notifier/internal/config"
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+	"github-slack-notifier/internal/services"
+)
+
+const (
+	defaultStatsActivityDays = 30
+	defaultStatsLimit        = 10
+	hoursPerDay              = 24
+)
+
+// statsReport is the operational summary printed by the stats command.
+type statsReport struct {
+	GeneratedAt           time.Time                `json:"generated_at"`
+	ActivityDays          int                      `json:"activity_days"`
+	Collections           []collectionCount        `json:"collections"`
+	Workspaces            []workspaceActivity      `json:"workspaces"`
+	OrphanedInstallations []orphanedInstallation   `json:"orphaned_installations"`
+	InactiveRepos         []inactiveRepo           `json:"inactive_repos"`
+	InactiveRepoCount     int                      `json:"inactive_repo_count"`
+	OAuthStates           []pendingOAuthState      `json:"oldest_oauth_states"`
+	OAuthStateCounts      pendingOAuthStateSummary `json:"oauth_state_counts"`
+}
+
+// collectionCount is the number of documents in a collection.
+type collectionCount struct {
+	Collection string `json:"collection"`
+	Documents  int64  `json:"documents"`
+}
+
+// workspaceActivity summarises a workspace's tracked messages and users.
+type workspaceActivity struct {
+	TeamID          string `json:"team_id"`
+	TeamName        string `json:"team_name,omitempty"`
+	TrackedMessages int    `json:"tracked_messages"`
+	RecentMessages  int    `json:"recent_messages"` // Tracked within the activity window
+	Users           int    `json:"users"`
+	LinkedUsers     int    `json:"linked_users"` // Connected a GitHub account
+	ActiveUsers     int    `json:"active_users"` // Linked users who authored a PR tracked within the activity window
+}
+
+// orphanedInstallation is a GitHub App installation no installed Slack workspace owns.
+type orphanedInstallation struct {
+	InstallationID int64     `json:"installation_id"`
+	AccountLogin   string    `json:"account_login"`
+	WorkspaceID    string    `json:"workspace_id,omitempty"`
+	InstalledAt    time.Time `json:"installed_at"`
+}
+
+// inactiveRepo is a configured repository with no PR tracked within the activity window.
+type inactiveRepo struct {
+	WorkspaceID  string     `json:"workspace_id"`
+	RepoFullName string     `json:"repo_full_name"`
+	Enabled      bool       `json:"enabled"`
+	LastActivity *time.Time `json:"last_activity,omitempty"` // nil if no PR was ever tracked
+}
+
+// pendingOAuthState is an OAuth state that hasn't been deleted, i.e. a GitHub connection that was never completed.
+type pendingOAuthState struct {
+	ID          string    `json:"id"`
+	SlackTeamID string    `json:"slack_team_id"`
+	SlackUserID string    `json:"slack_user_id"`
+	CreatedAt   time.Time `json:"created_at"`
+	Expired     bool      `json:"expired"`
+}
+
+// pendingOAuthStateSummary counts the OAuth states that haven't been deleted.
+type pendingOAuthStateSummary struct {
+	Total   int `json:"total"`
+	Expired int `json:"expired"`
+}
+
+// statsMessage holds the tracked message fields the stats command reads.
+type statsMessage struct {
+	SlackTeamID      string    `firestore:"slack_team_id"`
+	RepoFullName     string    `firestore:"repo_full_name"`
+	PRAuthorGitHubID *int64    `firestore:"pr_author_github_id"`
+	CreatedAt        time.Time `firestore:"created_at"`
+}
+
+func handleStats() {
+	var activityDays, limit int
+	var jsonOutput bool
+
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	fs.IntVar(&activityDays, "days", defaultStatsActivityDays, "Activity window in days for active users and inactive repositories")
+	fs.IntVar(&limit, "limit", defaultStatsLimit, "Maximum inactive repositories and OAuth states to list")
+	fs.BoolVar(&jsonOutput, "json", false, "Print the report as JSON")
+	_ = fs.Parse(os.Args[2:])
+
+	if activityDays <= 0 || limit <= 0 {
+		fmt.Println("--days and --limit must be positive")
+		os.Exit(1)
+	}
+
+	cfg := config.Load()
+	ctx := context.Background()
+
+	setupLogging(cfg)
+	firestoreClient := connectFirestore(ctx, cfg)
+	defer func() {
+		if err := firestoreClient.Close(); err != nil {
+			log.Error(context.Background(), "Error closing Firestore client", "error", err)
+		}
+	}()
+
+	report, err := buildStatsReport(ctx, firestoreClient, time.Now(), activityDays, limit)
+	if err != nil {
+		log.Error(ctx, "Failed to build stats", "error", err)
+		os.Exit(1)
+	}
+
+	if jsonOutput {
+		jsonData, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			log.Error(ctx, "Failed to marshal stats", "error", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(jsonData))
+		return
+	}
+	printStatsReport(report)
+}
+
+// statsSnapshot holds the documents the stats command summarises, keyed by document ID.
+type statsSnapshot struct {
+	workspaces    map[string]*models.SlackWorkspace
+	users         map[string]*models.User
+	repos         map[string]*models.Repo
+	installations map[string]*models.GitHubInstallation
+	oauthStates   map[string]*models.OAuthState
+}
+
+// messageActivity is what the tracked messages say about recent activity.
+type messageActivity struct {
+	lastPR        map[string]time.Time      // Latest tracked PR per {workspace}#{repo}, lowercased
+	recentAuthors map[string]map[int64]bool // GitHub IDs of authors with PRs tracked within the window, per workspace
+	workspaces    map[string]*workspaceActivity
+}
+
+// buildStatsReport counts every collection and reads the workspaces, users, repositories, installations,
+// tracked messages and OAuth states the report summarises.
+func buildStatsReport(ctx context.Context, client *firestore.Client, now time.Time, activityDays, limit int) (*statsReport, error) {
+	report := &statsReport{GeneratedAt: now, ActivityDays: activityDays}
+	since := now.Add(-time.Duration(activityDays) * hoursPerDay * time.Hour)
+
+	firestoreService := services.NewFirestoreService(client)
+	for _, collectionName := range firestoreCollections() {
+		count, err := firestoreService.CountDocuments(ctx, collectionName)
+		if err != nil {
+			return nil, err
+		}
+		report.Collections = append(report.Collections, collectionCount{Collection: collectionName, Documents: count})
+	}
+
+	snapshot := &statsSnapshot{}
+	var err error
+	if snapshot.workspaces, err = loadCollection[models.SlackWorkspace](ctx, client, "slack_workspaces"); err != nil {
+		return nil, err
+	}
+	if snapshot.users, err = loadCollection[models.User](ctx, client, "users"); err != nil {
+		return nil, err
+	}
+	if snapshot.repos, err = loadCollection[models.Repo](ctx, client, "repos"); err != nil {
+		return nil, err
+	}
+	if snapshot.installations, err = loadCollection[models.GitHubInstallation](ctx, client, "github_installations"); err != nil {
+		return nil, err
+	}
+	if snapshot.oauthStates, err = loadCollection[models.OAuthState](ctx, client, "oauth_states"); err != nil {
+		return nil, err
+	}
+
+	activity := newMessageActivity()
+	if err := forEachStatsMessage(ctx, client, func(msg *statsMessage) { activity.add(msg, since) }); err != nil {
+		return nil, err
+	}
+
+	snapshot.fillReport(report, activity, since, limit)
+	return report, nil
+}
+
+// fillReport summarises the snapshot and tracked message activity into the report, listing at most limit
+// inactive repositories and OAuth states.
+func (s *statsSnapshot) fillReport(report *statsReport, activity *messageActivity, since time.Time, limit int) {
+	report.Workspaces = s.workspaceActivity(activity)
+	report.OrphanedInstallations = s.orphanedInstallations()
+	report.InactiveRepos = s.inactiveRepos(activity, since)
+	report.InactiveRepoCount = len(report.InactiveRepos)
+	if len(report.InactiveRepos) > limit {
+		report.InactiveRepos = report.InactiveRepos[:limit]
+	}
+	report.OAuthStates, report.OAuthStateCounts = s.pendingOAuthStates(report.GeneratedAt)
+	if len(report.OAuthStates) > limit {
+		report.OAuthStates = report.OAuthStates[:limit]
+	}
+}
+
+// newMessageActivity returns an empty messageActivity to add tracked messages to.
+func newMessageActivity() *messageActivity {
+	return &messageActivity{
+		lastPR:        make(map[string]time.Time),
+		recentAuthors: make(map[string]map[int64]bool),
+		workspaces:    make(map[string]*workspaceActivity),
+	}
+}
+
+// workspace returns the activity for a workspace, adding it if this is its first message or user.
+func (a *messageActivity) workspace(teamID string) *workspaceActivity {
+	if a.workspaces[teamID] == nil {
+		a.workspaces[teamID] = &workspaceActivity{TeamID: teamID}
+	}
+	return a.workspaces[teamID]
+}
+
+// add counts a tracked message, and records its author as active if it was tracked since the window started.
+func (a *messageActivity) add(msg *statsMessage, since time.Time) {
+	workspace := a.workspace(msg.SlackTeamID)
+	workspace.TrackedMessages++
+
+	key := strings.ToLower(msg.SlackTeamID + "#" + msg.RepoFullName)
+	if msg.CreatedAt.After(a.lastPR[key]) {
+		a.lastPR[key] = msg.CreatedAt
+	}
+	if msg.CreatedAt.Before(since) {
+		return
+	}
+	workspace.RecentMessages++
+	if msg.PRAuthorGitHubID != nil {
+		if a.recentAuthors[msg.SlackTeamID] == nil {
+			a.recentAuthors[msg.SlackTeamID] = make(map[int64]bool)
+		}
+		a.recentAuthors[msg.SlackTeamID][*msg.PRAuthorGitHubID] = true
+	}
+}
+
+// workspaceActivity returns every workspace with an installation, tracked messages or users,
+// busiest first. Users are active if they've linked GitHub and authored a PR tracked within the window.
+func (s *statsSnapshot) workspaceActivity(activity *messageActivity) []workspaceActivity {
+	for id, workspace := range s.workspaces {
+		activity.workspace(id).TeamName = workspace.TeamName
+	}
+	for _, user := range s.users {
+		workspace := activity.workspace(user.SlackTeamID)
+		workspace.Users++
+		if user.Verified && user.GitHubUserID != 0 {
+			workspace.LinkedUsers++
+			if activity.recentAuthors[user.SlackTeamID][user.GitHubUserID] {
+				workspace.ActiveUsers++
+			}
+		}
+	}
+
+	workspaces := make([]workspaceActivity, 0, len(activity.workspaces))
+	for _, workspace := range activity.workspaces {
+		workspaces = append(workspaces, *workspace)
+	}
+	sort.Slice(workspaces, func(i, j int) bool {
+		if workspaces[i].TrackedMessages != workspaces[j].TrackedMessages {
+			return workspaces[i].TrackedMessages > workspaces[j].TrackedMessages
+		}
+		return workspaces[i].TeamID < workspaces[j].TeamID
+	})
+	return workspaces
+}
+
+// orphanedInstallations returns the GitHub App installations with no owning workspace, or whose workspace
+// isn't installed. As in audit-isolation, workspaces with configured repos count as installed, so deployments
+// using a single bot token aren't reported wholesale.
+func (s *statsSnapshot) orphanedInstallations() []orphanedInstallation {
+	knownWorkspaces := make(map[string]bool)
+	for id := range s.workspaces {
+		knownWorkspaces[id] = true
+	}
+	for _, repo := range s.repos {
+		knownWorkspaces[repo.WorkspaceID] = true
+	}
+
+	var orphaned []orphanedInstallation
+	for _, installation := range s.installations {
+		if installation.SlackWorkspaceID != "" && knownWorkspaces[installation.SlackWorkspaceID] {
+			continue
+		}
+		orphaned = append(orphaned, orphanedInstallation{
+			InstallationID: installation.ID,
+			AccountLogin:   installation.AccountLogin,
+			WorkspaceID:    installation.SlackWorkspaceID,
+			InstalledAt:    installation.InstalledAt,
+		})
+	}
+	sort.Slice(orphaned, func(i, j int) bool { return orphaned[i].InstallationID < orphaned[j].InstallationID })
+	return orphaned
+}
+
+// inactiveRepos returns the configured repositories with no PR tracked since the window started, longest
+// inactive first, with repositories that never had a PR tracked ahead of the rest.
+func (s *statsSnapshot) inactiveRepos(activity *messageActivity, since time.Time) []inactiveRepo {
+	var inactive []inactiveRepo
+	for _, repo := range s.repos {
+		last, ok := activity.lastPR[strings.ToLower(repo.WorkspaceID+"#"+repo.RepoFullName)]
+		if ok && !last.Before(since) {
+			continue
+		}
+		entry := inactiveRepo{WorkspaceID: repo.WorkspaceID, RepoFullName: repo.RepoFullName, Enabled: repo.Enabled}
+		if ok {
+			entry.LastActivity = &last
+		}
+		inactive = append(inactive, entry)
+	}
+
+	sort.Slice(inactive, func(i, j int) bool {
+		a, b := inactive[i], inactive[j]
+		if (a.LastActivity == nil) != (b.LastActivity == nil) {
+			return a.LastActivity == nil
+		}
+		if a.LastActivity != nil && !a.LastActivity.Equal(*b.LastActivity) {
+			return a.LastActivity.Before(*b.LastActivity)
+		}
+		return a.WorkspaceID+"#"+a.RepoFullName < b.WorkspaceID+"#"+b.RepoFullName
+	})
+	return inactive
+}
+
+// pendingOAuthStates returns the OAuth states that haven't been deleted, oldest first, and how many have expired.
+func (s *statsSnapshot) pendingOAuthStates(now time.Time) ([]pendingOAuthState, pendingOAuthStateSummary) {
+	var summary pendingOAuthStateSummary
+	states := make([]pendingOAuthState, 0, len(s.oauthStates))
+	for id, state := range s.oauthStates {
+		expired := !state.ExpiresAt.After(now)
+		summary.Total++
+		if expired {
+			summary.Expired++
+		}
+		states = append(states, pendingOAuthState{
+			ID:          id,
+			SlackTeamID: state.SlackTeamID,
+			SlackUserID: state.SlackUserID,
+			CreatedAt:   state.CreatedAt,
+			Expired:     expired,
+		})
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].CreatedAt.Before(states[j].CreatedAt) })
+	return states, summary
+}
+
+// forEachStatsMessage streams the tracked messages, reading only the fields the stats command needs.
+func forEachStatsMessage(ctx context.Context, client *firestore.Client, fn func(msg *statsMessage)) error {
+	iter := client.Collection("trackedmessages").
+		Select("slack_team_id", "repo_full_name", "pr_author_github_id", "created_at").
+		Documents(ctx)
+	defer iter.Stop()
+
+	for {
+		doc, err := iter.Next()
+		if errors.Is(err, iterator.Done) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read collection trackedmessages: %w", err)
+		}
+
+		var msg statsMessage
+		if err := doc.DataTo(&msg); err != nil {
+			log.Warn(ctx, "Skipping document that can't be unmarshaled",
+				"collection", "trackedmessages", "doc_id", doc.Ref.ID, "error", err)
+			continue
+		}
+		fn(&msg)
+	}
+}
+
+// printStatsReport prints the report as plain text sections.
+func printStatsReport(report *statsReport) {
+	fmt.Println("Collections:")
+	for _, collection := range report.Collections {
+		fmt.Printf("  %-24s %d\n", collection.Collection, collection.Documents)
+	}
+
+	fmt.Printf("\nWorkspaces (activity in the last %d days):\n", report.ActivityDays)
+	fmt.Printf("  %-14s %-24s %10s %8s %7s %8s %8s\n", "TEAM", "NAME", "MESSAGES", "RECENT", "USERS", "LINKED", "ACTIVE")
+	for _, workspace := range report.Workspaces {
+		fmt.Printf("  %-14s %-24s %10d %8d %7d %8d %8d\n", workspace.TeamID, workspace.TeamName,
+			workspace.TrackedMessages, workspace.RecentMessages, workspace.Users, workspace.LinkedUsers, workspace.ActiveUsers)
+	}
+
+	fmt.Printf("\nOrphaned GitHub installations: %d\n", len(report.OrphanedInstallations))
+	for _, installation := range report.OrphanedInstallations {
+		owner := "no workspace"
+		if installation.WorkspaceID != "" {
+			owner = "uninstalled workspace " + installation.WorkspaceID
+		}
+		fmt.Printf("  %d %s (%s, installed %s)\n", installation.InstallationID, installation.AccountLogin,
+			owner, installation.InstalledAt.Format(time.DateOnly))
+	}
+
+	fmt.Printf("\nRepositories with no PRs in the last %d days: %d\n", report.ActivityDays, report.InactiveRepoCount)
+	for _, repo := range report.InactiveRepos {
+		last := "never"
+		if repo.LastActivity != nil {
+			last = repo.LastActivity.Format(time.DateOnly)
+		}
+		disabled := ""
+		if !repo.Enabled {
+			disabled = ", disabled"
+		}
+		fmt.Printf("  %s %s (last PR %s%s)\n", repo.WorkspaceID, repo.RepoFullName, last, disabled)
+	}
+
+	fmt.Printf("\nUndeleted OAuth states: %d (%d expired)\n", report.OAuthStateCounts.Total, report.OAuthStateCounts.Expired)
+	for _, state := range report.OAuthStates {
+		expired := ""
+		if state.Expired {
+			expired = ", expired"
+		}
+		fmt.Printf("  %s %s/%s (created %s%s)\n", state.ID, state.SlackTeamID, state.SlackUserID,
+			state.CreatedAt.Format(time.RFC3339), expired)
+	}
+}
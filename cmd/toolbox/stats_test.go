@@ -0,0 +1,101 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github-slack-notifier/internal/models"
+)
+
+func TestStatsSnapshotFillReport(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	since := now.AddDate(0, 0, -30)
+	authorID := int64(101)
+
+	snapshot := &statsSnapshot{
+		workspaces: map[string]*models.SlackWorkspace{
+			"T1": {ID: "T1", TeamName: "Acme"},
+			"T3": {ID: "T3", TeamName: "Quiet"},
+		},
+		users: map[string]*models.User{
+			"U1": {SlackTeamID: "T1", Verified: true, GitHubUserID: authorID},
+			"U2": {SlackTeamID: "T1", Verified: true, GitHubUserID: 102},
+			"U3": {SlackTeamID: "T1"},
+		},
+		repos: map[string]*models.Repo{
+			"T1#org%2Fapi":  {WorkspaceID: "T1", RepoFullName: "org/api", Enabled: true},
+			"T1#org%2Fweb":  {WorkspaceID: "T1", RepoFullName: "org/web", Enabled: true},
+			"T1#org%2Fdocs": {WorkspaceID: "T1", RepoFullName: "org/docs"},
+			"T2#org%2Fapi":  {WorkspaceID: "T2", RepoFullName: "org/api", Enabled: true},
+		},
+		installations: map[string]*models.GitHubInstallation{
+			"1": {ID: 1, AccountLogin: "org", SlackWorkspaceID: "T1"},
+			"2": {ID: 2, AccountLogin: "org", SlackWorkspaceID: "T2"}, // Known from its repos
+			"3": {ID: 3, AccountLogin: "gone", SlackWorkspaceID: "T9"},
+			"4": {ID: 4, AccountLogin: "unlinked"},
+		},
+		oauthStates: map[string]*models.OAuthState{
+			"s1": {SlackTeamID: "T1", SlackUserID: "U1", CreatedAt: now.Add(-2 * time.Hour), ExpiresAt: now.Add(-time.Hour)},
+			"s2": {SlackTeamID: "T1", SlackUserID: "U2", CreatedAt: now.Add(-time.Minute), ExpiresAt: now.Add(time.Hour)},
+		},
+	}
+
+	activity := newMessageActivity()
+	messages := []*statsMessage{
+		{SlackTeamID: "T1", RepoFullName: "org/api", PRAuthorGitHubID: &authorID, CreatedAt: now.AddDate(0, 0, -1)},
+		{SlackTeamID: "T1", RepoFullName: "ORG/web", CreatedAt: now.AddDate(0, 0, -40)},
+		{SlackTeamID: "T1", RepoFullName: "org/web", CreatedAt: now.AddDate(0, 0, -60)},
+		{SlackTeamID: "T2", RepoFullName: "org/api", CreatedAt: now.AddDate(0, 0, -90)},
+	}
+	for _, msg := range messages {
+		activity.add(msg, since)
+	}
+
+	t.Run("full report", func(t *testing.T) {
+		report := &statsReport{GeneratedAt: now}
+		snapshot.fillReport(report, activity, since, 10)
+
+		assert.Equal(t, []workspaceActivity{
+			{TeamID: "T1", TeamName: "Acme", TrackedMessages: 3, RecentMessages: 1, Users: 3, LinkedUsers: 2, ActiveUsers: 1},
+			{TeamID: "T2", TrackedMessages: 1},
+			{TeamID: "T3", TeamName: "Quiet"},
+		}, report.Workspaces)
+
+		assert.Equal(t, []orphanedInstallation{
+			{InstallationID: 3, AccountLogin: "gone", WorkspaceID: "T9"},
+			{InstallationID: 4, AccountLogin: "unlinked"},
+		}, report.OrphanedInstallations)
+
+		// Never active first, then longest inactive; org/api in T1 is active
+		require.Len(t, report.InactiveRepos, 3)
+		assert.Equal(t, 3, report.InactiveRepoCount)
+		assert.Equal(t, "org/docs", report.InactiveRepos[0].RepoFullName)
+		assert.Nil(t, report.InactiveRepos[0].LastActivity)
+		assert.False(t, report.InactiveRepos[0].Enabled)
+		assert.Equal(t, "T2", report.InactiveRepos[1].WorkspaceID)
+		assert.Equal(t, now.AddDate(0, 0, -90), *report.InactiveRepos[1].LastActivity)
+		assert.Equal(t, "org/web", report.InactiveRepos[2].RepoFullName)
+		assert.Equal(t, now.AddDate(0, 0, -40), *report.InactiveRepos[2].LastActivity, "latest PR across name casing")
+
+		assert.Equal(t, pendingOAuthStateSummary{Total: 2, Expired: 1}, report.OAuthStateCounts)
+		require.Len(t, report.OAuthStates, 2)
+		assert.Equal(t, "s1", report.OAuthStates[0].ID)
+		assert.True(t, report.OAuthStates[0].Expired)
+		assert.False(t, report.OAuthStates[1].Expired)
+	})
+
+	t.Run("lists are limited but counted in full", func(t *testing.T) {
+		report := &statsReport{GeneratedAt: now}
+		snapshot.fillReport(report, activity, since, 1)
+
+		require.Len(t, report.InactiveRepos, 1)
+		assert.Equal(t, "org/docs", report.InactiveRepos[0].RepoFullName)
+		assert.Equal(t, 3, report.InactiveRepoCount)
+		require.Len(t, report.OAuthStates, 1)
+		assert.Equal(t, "s1", report.OAuthStates[0].ID)
+		assert.Equal(t, 2, report.OAuthStateCounts.Total)
+	})
+}
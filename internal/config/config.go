@@ -1,11 +1,18 @@
 package config
 
 import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
+	"net/netip"
 	"os"
+	"path"
 	"strconv"
 	"strings"
 	"time"
+
+	"github-slack-notifier/internal/presentation"
 )
 
 // EmojiConfig holds Slack emoji configuration for different PR states.
@@ -15,8 +22,89 @@ type EmojiConfig struct {
 	Commented        string
 	Merged           string
 	Closed           string
+	CIPassed         string
+	CIFailed         string
+	MergeConflict    string
+}
+
+// TruncationConfig controls how PR titles and descriptions are shortened to fit Slack messages.
+type TruncationConfig struct {
+	MaxTitleLength       int    // Max characters of the PR title shown in the message
+	MaxDescriptionLength int    // Max characters of the PR description expanded by "Show more"
+	Ellipsis             string // Appended to truncated text
+	ShowMoreButton       bool   // Attach a "Show more" button that expands the title and description into a thread
+}
+
+// LabelConfig controls how a PR's GitHub labels and milestone are shown on its messages.
+type LabelConfig struct {
+	Enabled bool              // Show label chips and the milestone on text PR messages; rich messages always show them
+	Emoji   map[string]string // Emoji for labels by lowercase name; other labels get a square in their GitHub color
+}
+
+// NoReviewersConfig controls the warning on messages for PRs posted without anyone asked to review them.
+type NoReviewersConfig struct {
+	Warning    bool          // Add a "No reviewers requested" line to messages for PRs without requested reviewers or CCs
+	NudgeAfter time.Duration // Nudge the author in the thread if there are still no reviewers this long after posting; 0 disables
+}
+
+// Actions a reaction on a PR message can take on GitHub as the reacting user, for REACTION_ACTIONS.
+const (
+	ReactionActionReviewing = "reviewing" // React to the PR with 👀, to show it's being reviewed
+	ReactionActionApprove   = "approve"   // Approve the PR
+)
+
+// FaultInjectionConfig sets how often simulated failures are injected into outbound calls, as percentages.
+// Fault injection is for integration tests and staging, and can't be enabled in release mode.
+type FaultInjectionConfig struct {
+	SlackRateLimitPercent   float64 // Slack API calls failing with HTTP 429
+	GitHubBadGatewayPercent float64 // GitHub API calls failing with HTTP 502
+	FirestoreTimeoutPercent float64 // Firestore calls failing with a deadline exceeded error
+}
+
+// Enabled returns true if any faults are injected.
+func (f FaultInjectionConfig) Enabled() bool {
+	return f.SlackRateLimitPercent > 0 || f.GitHubBadGatewayPercent > 0 || f.FirestoreTimeoutPercent > 0
+}
+
+// TracingConfig controls OpenTelemetry trace export. The OTLP endpoint, headers and timeout are read by the exporter
+// from the standard OTEL_EXPORTER_OTLP_* variables.
+type TracingConfig struct {
+	OTLPEndpoint  string  // OTLP/HTTP endpoint spans are exported to, e.g. a collector sidecar; tracing is off when empty
+	SamplePercent float64 // Percentage of traces started here that are sampled; traces started upstream keep their decision
+}
+
+// Enabled returns true if spans are exported.
+func (t TracingConfig) Enabled() bool {
+	return t.OTLPEndpoint != ""
+}
+
+// GitHubEnterpriseServer is the GitHub App registered on a GitHub Enterprise Server instance, which the bot
+// accepts webhooks from and calls alongside github.com.
+type GitHubEnterpriseServer struct {
+	Host             string // Hostname of the instance, e.g. github.example.com
+	AppID            int64
+	AppSlug          string // For installation URLs; defaults to GITHUB_APP_SLUG
+	PrivateKeyBase64 string
+	WebhookSecret    string
+	ClientID         string // OAuth client ID for linking accounts on the instance; defaults to GITHUB_CLIENT_ID
+	ClientSecret     string // Defaults to GITHUB_CLIENT_SECRET
+}
+
+// JobAuthConfig sets how the job processor authenticates the job queue's callbacks.
+type JobAuthConfig struct {
+	Method              string   // JobAuthSecret (default), JobAuthOIDC or JobAuthMTLS
+	OIDCServiceAccount  string   // Service account email OIDC tokens must be issued for
+	OIDCAudience        string   // Audience OIDC tokens must be issued for; the job processor URL when empty
+	MTLSAllowedSubjects []string // Client certificate common names or DNS names allowed; any verified certificate when empty
 }
 
+// Job processor authentication methods for JOB_AUTH_METHOD.
+const (
+	JobAuthSecret = "secret" // Static shared secret in the X-Cloud-Tasks-Secret header
+	JobAuthOIDC   = "oidc"   // Google-signed OIDC token, as sent by Cloud Tasks OIDC targets and Pub/Sub push subscriptions
+	JobAuthMTLS   = "mtls"   // Client certificate verified against TLS_CLIENT_CA_FILE, for self-hosted queues
+)
+
 // Config holds all application configuration.
 type Config struct {
 	// Core settings
@@ -25,11 +113,23 @@ type Config struct {
 	GitHubWebhookSecret string
 	SlackSigningSecret  string
 
+	// Webhook secret being rotated out, still accepted alongside GitHubWebhookSecret; unset once rotation is done
+	GitHubWebhookSecretPrevious string
+
 	// Slack OAuth settings (required)
 	SlackClientID     string
 	SlackClientSecret string
 	SlackAppID        string
 
+	// Base64 AES-256 key Slack bot and user tokens are encrypted with in Firestore; stored in plain text when empty
+	TokenEncryptionKey string
+	// Cloud KMS key new tokens are envelope encrypted with instead (projects/.../cryptoKeys/...); the local key still
+	// decrypts tokens saved with it until `toolbox rotate-encryption-key` re-encrypts them
+	TokenKMSKey string
+
+	// Offer posting PRs with the author's own Slack user token (needs the user callback URL registered in the Slack app)
+	SlackUserTokenPosting bool
+
 	// GitHub OAuth settings
 	GitHubClientID     string
 	GitHubClientSecret string
@@ -39,6 +139,9 @@ type Config struct {
 	GitHubAppSlug          string // GitHub App slug/name for installation URLs
 	GitHubPrivateKeyBase64 string // GitHub App private key (base64 encoded)
 
+	// GitHub Enterprise Server instances by hostname, each with its own GitHub App
+	GitHubEnterpriseServers map[string]GitHubEnterpriseServer
+
 	// Cloud Tasks settings
 	GoogleCloudProject string
 	BaseURL            string
@@ -46,6 +149,16 @@ type Config struct {
 	CloudTasksQueue    string
 	CloudTasksSecret   string
 
+	// Job processor authentication
+	JobAuth JobAuthConfig
+
+	// Admin API settings
+	AdminAPIKey       string         // Bearer token for /admin and /metrics; admin routes are disabled when no token is set
+	AdminAPIKeyHashes []string       // Hex SHA-256 hashes of accepted bearer tokens, so tokens needn't be stored in plain text
+	AdminAllowedIPs   []netip.Prefix // Client IPs/ranges allowed to call admin routes; any IP when empty
+	// Proxies whose X-Forwarded-For is believed when finding the client IP; the connection's address is used when empty
+	TrustedProxies []netip.Prefix
+
 	// Cloud Tasks retry configuration
 	CloudTasksMaxAttempts int32
 
@@ -56,17 +169,103 @@ type Config struct {
 	ServerReadTimeout     time.Duration
 	ServerWriteTimeout    time.Duration
 	ServerShutdownTimeout time.Duration
+	TLSCertFile           string // Serve HTTPS with this certificate; plain HTTP when empty
+	TLSKeyFile            string
+	TLSClientCAFile       string // CA bundle client certificates are verified against, for mTLS job authentication
+
+	// Startup self-check settings
+	SelfCheckMode    string        // "off", "log" (default), or "enforce" to exit on hard failures
+	SelfCheckTimeout time.Duration // Per-check timeout
 
 	// Processing settings
 	WebhookProcessingTimeout time.Duration
+	StrictChannelMatching    bool   // Match channels by ID only (requires channel IDs backfilled on tracked messages)
+	PRUpdateBudget           int    // Slack message updates (edits and reaction syncs) allowed per PR per hour; 0 disables the cap
+	SkipLabel                string // PRs with this GitHub label aren't posted, like with the skip directive; off when empty
+
+	// Received GitHub webhooks are kept for debugging and replay for WebhookEventRetention; 0 disables the log
+	WebhookEventRetention time.Duration
+	// Slack messages, DMs and reactions the bot sends are kept in the notification log for support investigations
+	// for NotificationLogRetention; 0 disables the log
+	NotificationLogRetention time.Duration
+	// The cleanup job deletes tracked messages for PRs merged or closed longer ago than TrackedMessageRetention;
+	// 0 keeps them
+	TrackedMessageRetention time.Duration
+
+	// Slack API rate limiting: calls are paced per workspace and method, and fail as rate limited
+	// (so jobs are retried) rather than waiting longer than SlackRateLimitMaxWait
+	SlackRateLimitEnabled bool
+	SlackRateLimitMaxWait time.Duration
 
 	// Emoji settings
 	Emoji EmojiConfig
+
+	// Changed files left out of the lines changed that pick a PR's size emoji, e.g. vendored or generated code;
+	// the PR's files are only listed when set
+	PRSizeExcludePaths []string
+
+	// Message truncation settings
+	Truncation TruncationConfig
+
+	// Attach an "Open PR" button to PR messages, recording clicks as engagement analytics
+	OpenPRButton bool
+
+	// Show a PR's approvals against the approvals it needs on its messages, e.g. "2/3 approvals"
+	ApprovalQuorum bool
+
+	// Label chips and milestone on PR messages
+	Labels LabelConfig
+
+	// Warning on messages for PRs posted without reviewers
+	NoReviewers NoReviewersConfig
+
+	// GitHub actions taken as the reacting user when they react to a PR message, by emoji name without colons;
+	// linking GitHub also stores the user's token when set
+	ReactionActions map[string]string
+
+	// State combinations that change how PR messages are presented, e.g. collapsing drafts with failing CI
+	PresentationRules []presentation.Rule
+
+	// Simulated failures for testing retries and deduplication; never enabled in release mode
+	FaultInjection FaultInjectionConfig
+
+	// OpenTelemetry trace export
+	Tracing TracingConfig
+}
+
+// maxTaskScheduleDelay is the furthest ahead Cloud Tasks can schedule a task.
+const maxTaskScheduleDelay = 30 * 24 * time.Hour
+
+// tokenEncryptionKeySize is the size of the AES-256 key in TOKEN_ENCRYPTION_KEY.
+const tokenEncryptionKeySize = 32
+
+// Startup self-check modes for SELF_CHECK_MODE.
+const (
+	SelfCheckModeOff     = "off"
+	SelfCheckModeLog     = "log"
+	SelfCheckModeEnforce = "enforce"
+)
+
+// JobAuthAudience returns the audience OIDC tokens for the job processor must be issued for.
+func (c *Config) JobAuthAudience() string {
+	if c.JobAuth.OIDCAudience != "" {
+		return c.JobAuth.OIDCAudience
+	}
+	return c.JobProcessorURL()
+}
+
+// GitHubEnterpriseWebhookSecrets returns the webhook secret of each GitHub Enterprise Server instance by hostname.
+func (c *Config) GitHubEnterpriseWebhookSecrets() map[string]string {
+	secrets := make(map[string]string, len(c.GitHubEnterpriseServers))
+	for host, server := range c.GitHubEnterpriseServers {
+		secrets[host] = server.WebhookSecret
+	}
+	return secrets
 }
 
 // JobProcessorURL returns the full URL for the job processor endpoint.
 func (c *Config) JobProcessorURL() string {
-	return c.BaseURL + "/jobs/process"
+	return c.BaseURL + "/v1/jobs/process"
 }
 
 // SlackRedirectURL returns the full URL for the Slack OAuth callback endpoint.
@@ -74,11 +273,21 @@ func (c *Config) SlackRedirectURL() string {
 	return c.BaseURL + "/auth/slack/callback"
 }
 
+// SlackUserTokenRedirectURL returns the full URL for the Slack user token OAuth callback endpoint.
+func (c *Config) SlackUserTokenRedirectURL() string {
+	return c.BaseURL + "/auth/slack/user/callback"
+}
+
 // GitHubOAuthRedirectURL returns the full URL for the GitHub OAuth callback endpoint.
 func (c *Config) GitHubOAuthRedirectURL() string {
 	return c.BaseURL + "/auth/github/callback"
 }
 
+// IsAdminAPIEnabled returns true if an admin API key or key hash is configured.
+func (c *Config) IsAdminAPIEnabled() bool {
+	return c.AdminAPIKey != "" || len(c.AdminAPIKeyHashes) > 0
+}
+
 // IsSlackOAuthEnabled returns true since Slack OAuth is now always enabled.
 func (c *Config) IsSlackOAuthEnabled() bool {
 	return true
@@ -94,11 +303,16 @@ func Load() *Config {
 		GitHubWebhookSecret: getEnvRequired("GITHUB_WEBHOOK_SECRET"),
 		SlackSigningSecret:  getEnvRequired("SLACK_SIGNING_SECRET"),
 
+		GitHubWebhookSecretPrevious: getEnvDefault("GITHUB_WEBHOOK_SECRET_PREVIOUS", ""),
+
 		// Slack OAuth settings (required)
 		SlackClientID:     getEnvRequired("SLACK_CLIENT_ID"),
 		SlackClientSecret: getEnvRequired("SLACK_CLIENT_SECRET"),
 		SlackAppID:        getEnvRequired("SLACK_APP_ID"),
 
+		TokenEncryptionKey: getEnvDefault("TOKEN_ENCRYPTION_KEY", ""),
+		TokenKMSKey:        getEnvDefault("TOKEN_KMS_KEY", ""),
+
 		// GitHub OAuth settings (required)
 		GitHubClientID:     getEnvRequired("GITHUB_CLIENT_ID"),
 		GitHubClientSecret: getEnvRequired("GITHUB_CLIENT_SECRET"),
@@ -108,12 +322,31 @@ func Load() *Config {
 		BaseURL:            getEnvRequired("BASE_URL"),
 		GCPRegion:          getEnvDefault("GCP_REGION", "europe-west1"),
 		CloudTasksQueue:    getEnvDefault("CLOUD_TASKS_QUEUE", "webhook-processing"),
-		CloudTasksSecret:   getEnvRequired("CLOUD_TASKS_SECRET"),
+		CloudTasksSecret:   getEnvDefault("CLOUD_TASKS_SECRET", ""),
+
+		// Job processor authentication
+		JobAuth: JobAuthConfig{
+			Method:              getEnvDefault("JOB_AUTH_METHOD", JobAuthSecret),
+			OIDCServiceAccount:  getEnvDefault("JOB_AUTH_OIDC_SERVICE_ACCOUNT", ""),
+			OIDCAudience:        getEnvDefault("JOB_AUTH_OIDC_AUDIENCE", ""),
+			MTLSAllowedSubjects: getEnvList("JOB_AUTH_MTLS_ALLOWED_SUBJECTS"),
+		},
+
+		// Admin API settings
+		AdminAPIKey: getEnvDefault("ADMIN_API_KEY", ""),
 
 		// Server settings
 		Port:     getEnvDefault("PORT", "8080"),
 		GinMode:  getEnvDefault("GIN_MODE", "release"),
 		LogLevel: getEnvDefault("LOG_LEVEL", "info"),
+
+		// Startup self-check settings
+		SelfCheckMode: getEnvDefault("SELF_CHECK_MODE", SelfCheckModeLog),
+
+		// HTTPS settings
+		TLSCertFile:     getEnvDefault("TLS_CERT_FILE", ""),
+		TLSKeyFile:      getEnvDefault("TLS_KEY_FILE", ""),
+		TLSClientCAFile: getEnvDefault("TLS_CLIENT_CA_FILE", ""),
 	}
 
 	// Parse duration values
@@ -121,6 +354,24 @@ func Load() *Config {
 	cfg.ServerWriteTimeout = getEnvDuration("SERVER_WRITE_TIMEOUT", 30*time.Second)
 	cfg.ServerShutdownTimeout = getEnvDuration("SERVER_SHUTDOWN_TIMEOUT", 30*time.Second)
 	cfg.WebhookProcessingTimeout = getEnvDuration("WEBHOOK_PROCESSING_TIMEOUT", 5*time.Minute)
+	cfg.SelfCheckTimeout = getEnvDuration("SELF_CHECK_TIMEOUT", 10*time.Second)
+
+	// Parse admin API hardening settings
+	cfg.AdminAPIKeyHashes = getEnvList("ADMIN_API_KEY_SHA256")
+	cfg.AdminAllowedIPs = getEnvPrefixes("ADMIN_ALLOWED_IPS")
+	cfg.TrustedProxies = getEnvPrefixes("TRUSTED_PROXIES")
+
+	cfg.StrictChannelMatching = getEnvBool("STRICT_CHANNEL_MATCHING", false)
+	cfg.SlackUserTokenPosting = getEnvBool("SLACK_USER_TOKEN_POSTING", false)
+
+	cfg.PRUpdateBudget = int(getEnvInt32("PR_UPDATE_BUDGET_PER_HOUR", 30))
+	cfg.SkipLabel = strings.TrimSpace(getEnvDefault("SKIP_LABEL", ""))
+	cfg.WebhookEventRetention = getEnvDuration("WEBHOOK_EVENT_RETENTION", 7*24*time.Hour)
+	cfg.NotificationLogRetention = getEnvDuration("NOTIFICATION_LOG_RETENTION", 30*24*time.Hour)
+	cfg.TrackedMessageRetention = getEnvDuration("TRACKED_MESSAGE_RETENTION", 90*24*time.Hour)
+
+	cfg.SlackRateLimitEnabled = getEnvBool("SLACK_RATE_LIMIT_ENABLED", true)
+	cfg.SlackRateLimitMaxWait = getEnvDuration("SLACK_RATE_LIMIT_MAX_WAIT", 5*time.Second)
 
 	// Parse Cloud Tasks retry configuration
 	cfg.CloudTasksMaxAttempts = getEnvInt32("CLOUD_TASKS_MAX_ATTEMPTS", 100)
@@ -129,6 +380,7 @@ func Load() *Config {
 	cfg.GitHubAppID = getEnvInt64Required("GITHUB_APP_ID")
 	cfg.GitHubAppSlug = getEnvRequired("GITHUB_APP_SLUG")
 	cfg.GitHubPrivateKeyBase64 = getEnvRequired("GITHUB_PRIVATE_KEY_BASE64")
+	cfg.GitHubEnterpriseServers = getEnvGitHubEnterpriseServers(cfg)
 
 	// Parse emoji configuration
 	cfg.Emoji = EmojiConfig{
@@ -137,6 +389,58 @@ func Load() *Config {
 		Commented:        getEnvDefault("EMOJI_COMMENTED", "speech_balloon"),
 		Merged:           getEnvDefault("EMOJI_MERGED", "tada"),
 		Closed:           getEnvDefault("EMOJI_CLOSED", "x"),
+		CIPassed:         getEnvDefault("EMOJI_CI_PASSED", "large_green_circle"),
+		CIFailed:         getEnvDefault("EMOJI_CI_FAILED", "red_circle"),
+		MergeConflict:    getEnvDefault("EMOJI_MERGE_CONFLICT", "warning"),
+	}
+
+	cfg.PRSizeExcludePaths = getEnvList("PR_SIZE_EXCLUDE_PATHS")
+
+	// Parse message truncation configuration
+	cfg.Truncation = TruncationConfig{
+		MaxTitleLength:       int(getEnvInt32("MESSAGE_TITLE_MAX_LENGTH", 150)),
+		MaxDescriptionLength: int(getEnvInt32("MESSAGE_DESCRIPTION_MAX_LENGTH", 1500)),
+		Ellipsis:             getEnvDefault("MESSAGE_TRUNCATION_ELLIPSIS", "…"),
+		ShowMoreButton:       getEnvBool("MESSAGE_SHOW_MORE_BUTTON", false),
+	}
+
+	cfg.OpenPRButton = getEnvBool("MESSAGE_OPEN_PR_BUTTON", false)
+	cfg.ApprovalQuorum = getEnvBool("MESSAGE_APPROVAL_QUORUM", false)
+
+	// Parse label chip configuration
+	cfg.Labels = LabelConfig{
+		Enabled: getEnvBool("MESSAGE_LABELS", false),
+		Emoji:   getEnvMap("MESSAGE_LABEL_EMOJI"),
+	}
+
+	// Parse missing reviewers warning configuration
+	cfg.NoReviewers = NoReviewersConfig{
+		Warning:    getEnvBool("MESSAGE_NO_REVIEWERS_WARNING", false),
+		NudgeAfter: getEnvDuration("NO_REVIEWERS_NUDGE_AFTER", 0),
+	}
+
+	cfg.ReactionActions = make(map[string]string)
+	for emoji, action := range getEnvMap("REACTION_ACTIONS") {
+		cfg.ReactionActions[strings.Trim(emoji, ":")] = action
+	}
+
+	// Parse message presentation rules
+	cfg.PresentationRules = getEnvPresentationRules("PRESENTATION_RULES")
+
+	// Parse fault injection configuration
+	cfg.FaultInjection = FaultInjectionConfig{
+		SlackRateLimitPercent:   getEnvPercent("FAULT_SLACK_RATE_LIMIT_PERCENT"),
+		GitHubBadGatewayPercent: getEnvPercent("FAULT_GITHUB_BAD_GATEWAY_PERCENT"),
+		FirestoreTimeoutPercent: getEnvPercent("FAULT_FIRESTORE_TIMEOUT_PERCENT"),
+	}
+
+	// Parse tracing configuration; all traces are sampled unless a percentage is set
+	cfg.Tracing = TracingConfig{
+		OTLPEndpoint:  getEnvDefault("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", getEnvDefault("OTEL_EXPORTER_OTLP_ENDPOINT", "")),
+		SamplePercent: 100,
+	}
+	if _, ok := os.LookupEnv("TRACING_SAMPLE_PERCENT"); ok {
+		cfg.Tracing.SamplePercent = getEnvPercent("TRACING_SAMPLE_PERCENT")
 	}
 
 	// Validate configuration
@@ -153,6 +457,15 @@ func (c *Config) validate() {
 	c.validateLogLevel()
 	c.validateTimeouts()
 	c.validateCloudTasksRetryConfig()
+	c.validateSelfCheck()
+	c.validateTruncation()
+	c.validatePRSizeExcludePaths()
+	c.validateNoReviewers()
+	c.validateReactionActions()
+	c.validateAdminAPIKeyHashes()
+	c.validateTokenEncryptionKey()
+	c.validateFaultInjection()
+	c.validateJobAuth()
 }
 
 // validateRequiredFields checks that all required fields are set.
@@ -169,7 +482,6 @@ func (c *Config) validateRequiredFields() {
 		"GITHUB_CLIENT_SECRET":  c.GitHubClientSecret,
 		"GOOGLE_CLOUD_PROJECT":  c.GoogleCloudProject,
 		"BASE_URL":              c.BaseURL,
-		"CLOUD_TASKS_SECRET":    c.CloudTasksSecret,
 	}
 
 	for name, value := range required {
@@ -229,6 +541,104 @@ func (c *Config) validateCloudTasksRetryConfig() {
 	}
 }
 
+// validateSelfCheck validates the startup self-check settings.
+func (c *Config) validateSelfCheck() {
+	if c.SelfCheckMode != SelfCheckModeOff && c.SelfCheckMode != SelfCheckModeLog && c.SelfCheckMode != SelfCheckModeEnforce {
+		panic(fmt.Sprintf("invalid SELF_CHECK_MODE: %s (must be off, log, or enforce)", c.SelfCheckMode))
+	}
+	if c.SelfCheckTimeout <= 0 {
+		panic("SELF_CHECK_TIMEOUT must be positive")
+	}
+}
+
+// validateTruncation validates the message truncation settings.
+func (c *Config) validateTruncation() {
+	if c.Truncation.MaxTitleLength < 1 {
+		panic("MESSAGE_TITLE_MAX_LENGTH must be at least 1")
+	}
+	if c.Truncation.MaxDescriptionLength < 1 {
+		panic("MESSAGE_DESCRIPTION_MAX_LENGTH must be at least 1")
+	}
+}
+
+// validatePRSizeExcludePaths validates that each PR size exclude pattern is a valid glob.
+func (c *Config) validatePRSizeExcludePaths() {
+	for _, pattern := range c.PRSizeExcludePaths {
+		if _, err := path.Match(strings.ReplaceAll(pattern, "**", "*"), ""); err != nil {
+			panic(fmt.Sprintf("invalid PR_SIZE_EXCLUDE_PATHS pattern: %s", pattern))
+		}
+	}
+}
+
+// validateNoReviewers validates that the missing reviewers nudge can be scheduled.
+func (c *Config) validateNoReviewers() {
+	if c.NoReviewers.NudgeAfter < 0 || c.NoReviewers.NudgeAfter > maxTaskScheduleDelay {
+		panic("NO_REVIEWERS_NUDGE_AFTER must be between 0 and 720h")
+	}
+}
+
+// validateReactionActions validates that each reaction maps to a supported action, and that the wastebasket,
+// which deletes PR messages, isn't mapped.
+func (c *Config) validateReactionActions() {
+	for emoji, action := range c.ReactionActions {
+		if action != ReactionActionReviewing && action != ReactionActionApprove {
+			panic(fmt.Sprintf("invalid REACTION_ACTIONS action for %s: %s (must be reviewing or approve)", emoji, action))
+		}
+		if emoji == "wastebasket" {
+			panic("REACTION_ACTIONS can't map wastebasket, which deletes PR messages")
+		}
+	}
+}
+
+// validateAdminAPIKeyHashes validates that each admin API key hash is a hex SHA-256 digest.
+func (c *Config) validateAdminAPIKeyHashes() {
+	for _, hash := range c.AdminAPIKeyHashes {
+		if decoded, err := hex.DecodeString(hash); err != nil || len(decoded) != sha256.Size {
+			panic(fmt.Sprintf("invalid ADMIN_API_KEY_SHA256 entry: %s (must be a hex SHA-256 digest)", hash))
+		}
+	}
+}
+
+// validateTokenEncryptionKey validates that the token encryption key, if set, is a base64 encoded 32 byte key.
+func (c *Config) validateTokenEncryptionKey() {
+	if c.TokenEncryptionKey == "" {
+		return
+	}
+	if decoded, err := base64.StdEncoding.DecodeString(c.TokenEncryptionKey); err != nil || len(decoded) != tokenEncryptionKeySize {
+		panic("TOKEN_ENCRYPTION_KEY must be a base64 encoded 32 byte key (e.g. openssl rand -base64 32)")
+	}
+}
+
+// validateFaultInjection checks that fault injection is off in release mode.
+func (c *Config) validateFaultInjection() {
+	if c.FaultInjection.Enabled() && c.GinMode == "release" {
+		panic("FAULT_* fault injection settings can't be used when GIN_MODE is release")
+	}
+}
+
+// validateJobAuth validates the job processor authentication settings each method needs.
+func (c *Config) validateJobAuth() {
+	switch c.JobAuth.Method {
+	case JobAuthSecret:
+		if c.CloudTasksSecret == "" {
+			panic("required environment variable CLOUD_TASKS_SECRET is not set")
+		}
+	case JobAuthOIDC:
+		if c.JobAuth.OIDCServiceAccount == "" {
+			panic("JOB_AUTH_OIDC_SERVICE_ACCOUNT is required when JOB_AUTH_METHOD is oidc")
+		}
+	case JobAuthMTLS:
+		if c.TLSCertFile == "" || c.TLSKeyFile == "" || c.TLSClientCAFile == "" {
+			panic("TLS_CERT_FILE, TLS_KEY_FILE and TLS_CLIENT_CA_FILE are required when JOB_AUTH_METHOD is mtls")
+		}
+	default:
+		panic(fmt.Sprintf("invalid JOB_AUTH_METHOD: %s (must be secret, oidc, or mtls)", c.JobAuth.Method))
+	}
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		panic("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+}
+
 // getEnvRequired gets an environment variable or returns empty string if not set.
 // The validate() function will panic if required values are missing.
 // Automatically trims whitespace from the value.
@@ -260,6 +670,21 @@ func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
 	return d
 }
 
+// getEnvBool gets a boolean environment variable with a default value.
+// Panics if the value cannot be parsed as a boolean.
+// Automatically trims whitespace from the value.
+func getEnvBool(key string, defaultValue bool) bool {
+	value := strings.TrimSpace(os.Getenv(key))
+	if value == "" {
+		return defaultValue
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		panic(fmt.Sprintf("invalid boolean value for %s: %s", key, value))
+	}
+	return b
+}
+
 // getEnvInt32 gets an int32 environment variable with a default value.
 // Panics if the value cannot be parsed as an int32.
 // Automatically trims whitespace from the value.
@@ -275,6 +700,128 @@ func getEnvInt32(key string, defaultValue int32) int32 {
 	return int32(i)
 }
 
+// getEnvPercent gets a percentage environment variable, defaulting to 0.
+// Panics if the value cannot be parsed as a number from 0 to 100.
+// Automatically trims whitespace from the value.
+func getEnvPercent(key string) float64 {
+	value := strings.TrimSpace(os.Getenv(key))
+	if value == "" {
+		return 0
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil || f < 0 || f > 100 {
+		panic(fmt.Sprintf("invalid percentage for %s: %s (must be from 0 to 100)", key, value))
+	}
+	return f
+}
+
+// getEnvList gets a comma-separated list environment variable, skipping empty entries.
+// Automatically trims whitespace from each entry.
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// getEnvMap gets a comma-separated list of key=value pairs, e.g. "bug=:bug:,security=:lock:".
+// Keys are lowercased. Panics if an entry has no key or value.
+func getEnvMap(key string) map[string]string {
+	values := make(map[string]string)
+	for _, entry := range getEnvList(key) {
+		name, value, ok := strings.Cut(entry, "=")
+		name, value = strings.ToLower(strings.TrimSpace(name)), strings.TrimSpace(value)
+		if !ok || name == "" || value == "" {
+			panic(fmt.Sprintf("invalid key=value pair for %s: %s", key, entry))
+		}
+		values[name] = value
+	}
+	return values
+}
+
+// getEnvGitHubEnterpriseServers gets the GitHub Enterprise Server instances from the GITHUB_ENTERPRISE_* host=value
+// maps. Every host needs an app ID, private key and webhook secret; the slug and OAuth client default to
+// the github.com app's, as apps created from the same manifest usually share them.
+// Panics if a host is missing a required setting or has an invalid app ID.
+func getEnvGitHubEnterpriseServers(cfg *Config) map[string]GitHubEnterpriseServer {
+	appIDs := getEnvMap("GITHUB_ENTERPRISE_APP_IDS")
+	privateKeys := getEnvMap("GITHUB_ENTERPRISE_PRIVATE_KEYS_BASE64")
+	webhookSecrets := getEnvMap("GITHUB_ENTERPRISE_WEBHOOK_SECRETS")
+	appSlugs := getEnvMap("GITHUB_ENTERPRISE_APP_SLUGS")
+	clientIDs := getEnvMap("GITHUB_ENTERPRISE_CLIENT_IDS")
+	clientSecrets := getEnvMap("GITHUB_ENTERPRISE_CLIENT_SECRETS")
+
+	servers := make(map[string]GitHubEnterpriseServer)
+	for _, hosts := range []map[string]string{appIDs, privateKeys, webhookSecrets, appSlugs, clientIDs, clientSecrets} {
+		for host := range hosts {
+			if _, ok := servers[host]; ok {
+				continue
+			}
+
+			appID, err := strconv.ParseInt(appIDs[host], 10, 64)
+			if err != nil || appID <= 0 {
+				panic(fmt.Sprintf("GITHUB_ENTERPRISE_APP_IDS must set a positive integer app ID for %s", host))
+			}
+			if privateKeys[host] == "" {
+				panic(fmt.Sprintf("GITHUB_ENTERPRISE_PRIVATE_KEYS_BASE64 must set a private key for %s", host))
+			}
+			if webhookSecrets[host] == "" {
+				panic(fmt.Sprintf("GITHUB_ENTERPRISE_WEBHOOK_SECRETS must set a webhook secret for %s", host))
+			}
+
+			servers[host] = GitHubEnterpriseServer{
+				Host:             host,
+				AppID:            appID,
+				AppSlug:          valueOrDefault(appSlugs[host], cfg.GitHubAppSlug),
+				PrivateKeyBase64: privateKeys[host],
+				WebhookSecret:    webhookSecrets[host],
+				ClientID:         valueOrDefault(clientIDs[host], cfg.GitHubClientID),
+				ClientSecret:     valueOrDefault(clientSecrets[host], cfg.GitHubClientSecret),
+			}
+		}
+	}
+	return servers
+}
+
+// valueOrDefault returns value, or defaultValue when it's empty.
+func valueOrDefault(value, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
+
+// getEnvPresentationRules gets message presentation rules, defaulting to presentation.DefaultRules.
+// Panics if the rules cannot be parsed.
+func getEnvPresentationRules(key string) []presentation.Rule {
+	rules, err := presentation.ParseRules(getEnvDefault(key, presentation.DefaultRules))
+	if err != nil {
+		panic(fmt.Sprintf("invalid %s: %v", key, err))
+	}
+	return rules
+}
+
+// getEnvPrefixes gets a comma-separated list of IP addresses and CIDR ranges as prefixes.
+// Single addresses become single-address prefixes. Panics if an entry cannot be parsed.
+func getEnvPrefixes(key string) []netip.Prefix {
+	var prefixes []netip.Prefix
+	for _, value := range getEnvList(key) {
+		if addr, err := netip.ParseAddr(value); err == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			panic(fmt.Sprintf("invalid IP address or CIDR range for %s: %s", key, value))
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes
+}
+
 // getEnvInt64Required gets a required int64 environment variable.
 // Panics if the variable is not set or cannot be parsed as an int64.
 // Automatically trims whitespace from the value.
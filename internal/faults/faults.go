@@ -0,0 +1,171 @@
+// Package faults injects simulated Slack, GitHub and Firestore failures, so retries and
+// deduplication can be exercised in integration tests and staging. It's never enabled in release mode.
+package faults
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github-slack-notifier/internal/config"
+	"github-slack-notifier/internal/log"
+)
+
+// slackRetryAfterSeconds is the Retry-After sent with simulated Slack rate limits.
+const slackRetryAfterSeconds = 1
+
+// Injector injects failures into outbound calls at the configured rates.
+// A nil Injector injects nothing, so callers needn't check whether fault injection is enabled.
+type Injector struct {
+	cfg config.FaultInjectionConfig
+
+	mu   sync.Mutex
+	roll func() float64 // Returns a value in [0, 100)
+}
+
+// NewInjector creates an injector for the configuration, or returns nil if it injects no faults.
+func NewInjector(cfg config.FaultInjectionConfig) *Injector {
+	if !cfg.Enabled() {
+		return nil
+	}
+	return &Injector{
+		cfg:  cfg,
+		roll: func() float64 { return rand.Float64() * 100 }, //nolint:gosec // Fault sampling needn't be secure
+	}
+}
+
+// inject reports whether a fault should be injected for a call failing at the given rate.
+func (i *Injector) inject(percent float64) bool {
+	if percent <= 0 {
+		return false
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.roll() < percent
+}
+
+// SlackTransport wraps a transport for Slack API calls, failing some with HTTP 429 rate limits.
+func (i *Injector) SlackTransport(base http.RoundTripper) http.RoundTripper {
+	if i == nil || i.cfg.SlackRateLimitPercent <= 0 {
+		return base
+	}
+	return &faultTransport{
+		base:     base,
+		percent:  i.cfg.SlackRateLimitPercent,
+		injector: i,
+		status:   http.StatusTooManyRequests,
+		header:   http.Header{"Retry-After": []string{strconv.Itoa(slackRetryAfterSeconds)}},
+		body:     `{"ok":false,"error":"ratelimited"}`,
+	}
+}
+
+// GitHubTransport wraps a transport for GitHub API calls, failing some with HTTP 502 Bad Gateway.
+func (i *Injector) GitHubTransport(base http.RoundTripper) http.RoundTripper {
+	if i == nil || i.cfg.GitHubBadGatewayPercent <= 0 {
+		return base
+	}
+	return &faultTransport{
+		base:     base,
+		percent:  i.cfg.GitHubBadGatewayPercent,
+		injector: i,
+		status:   http.StatusBadGateway,
+		body:     `{"message":"Server Error"}`,
+	}
+}
+
+// FirestoreOptions returns client options that fail some Firestore calls with a deadline exceeded error,
+// as a timed out call would.
+func (i *Injector) FirestoreOptions() []option.ClientOption {
+	if i == nil || i.cfg.FirestoreTimeoutPercent <= 0 {
+		return nil
+	}
+	return []option.ClientOption{
+		option.WithGRPCDialOption(grpc.WithChainUnaryInterceptor(i.firestoreUnaryInterceptor)),
+		option.WithGRPCDialOption(grpc.WithChainStreamInterceptor(i.firestoreStreamInterceptor)),
+	}
+}
+
+// firestoreUnaryInterceptor fails some unary Firestore calls, e.g. document writes and commits.
+func (i *Injector) firestoreUnaryInterceptor(
+	ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption,
+) error {
+	if err := i.firestoreFault(ctx, method); err != nil {
+		return err
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+// firestoreStreamInterceptor fails some streaming Firestore calls, e.g. queries and document reads.
+func (i *Injector) firestoreStreamInterceptor(
+	ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	if err := i.firestoreFault(ctx, method); err != nil {
+		return nil, err
+	}
+	return streamer(ctx, desc, cc, method, opts...)
+}
+
+// firestoreFault returns a simulated timeout for a Firestore call if one should be injected.
+func (i *Injector) firestoreFault(ctx context.Context, method string) error {
+	if !i.inject(i.cfg.FirestoreTimeoutPercent) {
+		return nil
+	}
+	log.Warn(ctx, "Injecting simulated Firestore timeout", "method", method)
+	return status.Error(codes.DeadlineExceeded, "injected fault: simulated Firestore timeout")
+}
+
+// faultTransport fails some requests with a canned error response instead of sending them.
+type faultTransport struct {
+	base     http.RoundTripper // Underlying transport; http.DefaultTransport if nil
+	percent  float64
+	injector *Injector
+	status   int
+	header   http.Header
+	body     string
+}
+
+// RoundTrip returns the canned error response for an injected fault, or performs the request.
+func (t *faultTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !t.injector.inject(t.percent) {
+		base := t.base
+		if base == nil {
+			base = http.DefaultTransport
+		}
+		return base.RoundTrip(req)
+	}
+
+	log.Warn(req.Context(), "Injecting simulated API failure",
+		"host", req.URL.Host,
+		"path", req.URL.Path,
+		"status", t.status,
+	)
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+
+	header := t.header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	header.Set("Content-Type", "application/json")
+	return &http.Response{
+		Status:        strconv.Itoa(t.status) + " " + http.StatusText(t.status),
+		StatusCode:    t.status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewBufferString(t.body)),
+		ContentLength: int64(len(t.body)),
+		Request:       req,
+	}, nil
+}
@@ -0,0 +1,85 @@
+package faults
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github-slack-notifier/internal/config"
+)
+
+// roundTripFunc adapts a function to http.RoundTripper.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func okTransport() http.RoundTripper {
+	return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+	})
+}
+
+func TestNewInjector_DisabledIsNil(t *testing.T) {
+	injector := NewInjector(config.FaultInjectionConfig{})
+	assert.Nil(t, injector)
+
+	base := okTransport()
+	assert.NotNil(t, injector.SlackTransport(base), "a nil injector passes transports through")
+	assert.Nil(t, injector.FirestoreOptions())
+}
+
+func TestSlackTransport(t *testing.T) {
+	injector := NewInjector(config.FaultInjectionConfig{SlackRateLimitPercent: 50})
+	rolls := []float64{10, 90}
+	injector.roll = func() float64 {
+		roll := rolls[0]
+		rolls = rolls[1:]
+		return roll
+	}
+	transport := injector.SlackTransport(okTransport())
+
+	req := httptest.NewRequest(http.MethodPost, "https://slack.com/api/chat.postMessage", nil)
+	resp, err := transport.RoundTrip(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "1", resp.Header.Get("Retry-After"))
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"ok":false,"error":"ratelimited"}`, string(body))
+
+	resp, err = transport.RoundTrip(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "rolls above the rate aren't failed")
+}
+
+func TestGitHubTransport(t *testing.T) {
+	injector := NewInjector(config.FaultInjectionConfig{GitHubBadGatewayPercent: 100})
+	transport := injector.GitHubTransport(okTransport())
+
+	req := httptest.NewRequest(http.MethodGet, "https://api.github.com/repos/org/repo", nil)
+	resp, err := transport.RoundTrip(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+
+	resp, err = injector.SlackTransport(okTransport()).RoundTrip(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "Slack calls aren't failed when only GitHub faults are set")
+}
+
+func TestFirestoreFault(t *testing.T) {
+	injector := NewInjector(config.FaultInjectionConfig{FirestoreTimeoutPercent: 100})
+	assert.Len(t, injector.FirestoreOptions(), 2)
+
+	err := injector.firestoreFault(context.Background(), "/google.firestore.v1.Firestore/Commit")
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+
+	injector.cfg.FirestoreTimeoutPercent = 0
+	assert.NoError(t, injector.firestoreFault(context.Background(), "/google.firestore.v1.Firestore/Commit"))
+}
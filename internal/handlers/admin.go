@@ -0,0 +1,384 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+	"github-slack-notifier/internal/policy"
+	"github-slack-notifier/internal/services"
+	"github-slack-notifier/internal/utils"
+)
+
+// AdminHandler serves the admin API and metrics endpoint for platform teams.
+type AdminHandler struct {
+	firestoreService *services.FirestoreService
+	workspaceService *services.SlackWorkspaceService
+}
+
+// NewAdminHandler creates a new admin handler.
+func NewAdminHandler(firestoreService *services.FirestoreService, workspaceService *services.SlackWorkspaceService) *AdminHandler {
+	return &AdminHandler{
+		firestoreService: firestoreService,
+		workspaceService: workspaceService,
+	}
+}
+
+// HandleDirectiveUsage returns directive usage aggregates as JSON.
+// GET /admin/directive-usage[?workspace=<slack_team_id>].
+func (h *AdminHandler) HandleDirectiveUsage(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	workspaceID := c.Query("workspace")
+	if workspaceID != "" {
+		usage, err := h.firestoreService.GetDirectiveUsage(ctx, workspaceID)
+		if err != nil {
+			log.Error(ctx, "Failed to get directive usage", "error", err, "slack_team_id", workspaceID)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get directive usage"})
+			return
+		}
+		if usage == nil {
+			usage = &models.DirectiveUsage{WorkspaceID: workspaceID}
+		}
+		c.JSON(http.StatusOK, gin.H{"workspaces": []*models.DirectiveUsage{usage}})
+		return
+	}
+
+	usages, err := h.firestoreService.ListDirectiveUsage(ctx)
+	if err != nil {
+		log.Error(ctx, "Failed to list directive usage", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list directive usage"})
+		return
+	}
+	if usages == nil {
+		usages = []*models.DirectiveUsage{}
+	}
+	c.JSON(http.StatusOK, gin.H{"workspaces": usages})
+}
+
+// HandleMetrics serves directive usage aggregates and cleanup job totals in the Prometheus text exposition format.
+// GET /metrics.
+func (h *AdminHandler) HandleMetrics(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	usages, err := h.firestoreService.ListDirectiveUsage(ctx)
+	if err != nil {
+		log.Error(ctx, "Failed to list directive usage for metrics", "error", err)
+		c.String(http.StatusInternalServerError, "failed to collect metrics\n")
+		return
+	}
+
+	cleanupStats, err := h.firestoreService.GetCleanupStats(ctx)
+	if err != nil {
+		// Directive usage is still worth serving without the cleanup totals
+		log.Warn(ctx, "Failed to get cleanup stats for metrics", "error", err)
+	}
+
+	metrics := utils.FormatDirectiveUsageMetrics(usages) + utils.FormatCleanupMetrics(cleanupStats)
+	c.Data(http.StatusOK, "text/plain; version=0.0.4; charset=utf-8", []byte(metrics))
+}
+
+// notificationPolicyRequest is the body of a notification policy update.
+type notificationPolicyRequest struct {
+	Skip      string `json:"skip"`
+	Channel   string `json:"channel"`
+	CC        string `json:"cc"`
+	Emoji     string `json:"emoji"`
+	UpdatedBy string `json:"updated_by"`
+}
+
+// HandleGetNotificationPolicy returns a workspace's notification policy.
+// GET /admin/workspaces/:workspace_id/policy.
+func (h *AdminHandler) HandleGetNotificationPolicy(c *gin.Context) {
+	ctx := c.Request.Context()
+	workspaceID := c.Param("workspace_id")
+
+	notificationPolicy, err := h.firestoreService.GetNotificationPolicy(ctx, workspaceID)
+	if err != nil {
+		log.Error(ctx, "Failed to get notification policy", "error", err, "slack_team_id", workspaceID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get notification policy"})
+		return
+	}
+	if notificationPolicy == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "no notification policy"})
+		return
+	}
+
+	c.JSON(http.StatusOK, notificationPolicy)
+}
+
+// HandlePutNotificationPolicy validates and saves a workspace's notification policy.
+// PUT /admin/workspaces/:workspace_id/policy.
+func (h *AdminHandler) HandlePutNotificationPolicy(c *gin.Context) {
+	ctx := c.Request.Context()
+	workspaceID := c.Param("workspace_id")
+
+	var req notificationPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	notificationPolicy := &models.NotificationPolicy{
+		WorkspaceID: workspaceID,
+		Skip:        req.Skip,
+		Channel:     req.Channel,
+		CC:          req.CC,
+		Emoji:       req.Emoji,
+		UpdatedBy:   req.UpdatedBy,
+	}
+
+	// Reject policies that don't compile or fail on a sample PR, so broken policies are never stored
+	if err := policy.Validate(notificationPolicy); err != nil {
+		log.Info(ctx, "Rejected invalid notification policy", "error", err, "slack_team_id", workspaceID)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid notification policy", "details": err.Error()})
+		return
+	}
+
+	if err := h.firestoreService.SaveNotificationPolicy(ctx, notificationPolicy); err != nil {
+		log.Error(ctx, "Failed to save notification policy", "error", err, "slack_team_id", workspaceID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save notification policy"})
+		return
+	}
+
+	log.Info(ctx, "Saved notification policy", "slack_team_id", workspaceID, "updated_by", req.UpdatedBy)
+	c.JSON(http.StatusOK, notificationPolicy)
+}
+
+// HandleDeleteNotificationPolicy removes a workspace's notification policy.
+// DELETE /admin/workspaces/:workspace_id/policy.
+func (h *AdminHandler) HandleDeleteNotificationPolicy(c *gin.Context) {
+	ctx := c.Request.Context()
+	workspaceID := c.Param("workspace_id")
+
+	if err := h.firestoreService.DeleteNotificationPolicy(ctx, workspaceID); err != nil {
+		log.Error(ctx, "Failed to delete notification policy", "error", err, "slack_team_id", workspaceID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete notification policy"})
+		return
+	}
+
+	log.Info(ctx, "Deleted notification policy", "slack_team_id", workspaceID)
+	c.Status(http.StatusNoContent)
+}
+
+// HandleExportUserData returns everything stored about a Slack user in a workspace, for data access requests.
+// GET /admin/workspaces/:workspace_id/users/:user_id/data.
+func (h *AdminHandler) HandleExportUserData(c *gin.Context) {
+	ctx := c.Request.Context()
+	workspaceID := c.Param("workspace_id")
+	userID := c.Param("user_id")
+
+	export, err := h.firestoreService.ExportUserData(ctx, workspaceID, userID)
+	if err != nil {
+		log.Error(ctx, "Failed to export user data", "error", err, "slack_team_id", workspaceID, "user_id", userID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to export user data"})
+		return
+	}
+
+	log.Info(ctx, "Exported user data", "slack_team_id", workspaceID, "user_id", userID)
+	c.JSON(http.StatusOK, export)
+}
+
+// HandleDeleteUserData deletes everything stored about a Slack user in a workspace, for erasure requests.
+// Unlike deletion from App Home, a Slack user token the user granted is deleted without being revoked.
+// DELETE /admin/workspaces/:workspace_id/users/:user_id/data.
+func (h *AdminHandler) HandleDeleteUserData(c *gin.Context) {
+	ctx := c.Request.Context()
+	workspaceID := c.Param("workspace_id")
+	userID := c.Param("user_id")
+
+	deletion, err := h.firestoreService.DeleteUserData(ctx, workspaceID, userID)
+	if err != nil {
+		log.Error(ctx, "Failed to delete user data", "error", err, "slack_team_id", workspaceID, "user_id", userID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete user data"})
+		return
+	}
+
+	log.Info(ctx, "Deleted user data",
+		"slack_team_id", workspaceID,
+		"user_id", userID,
+		"deleted", deletion.Deleted,
+		"anonymized", deletion.Anonymized,
+	)
+	c.JSON(http.StatusOK, deletion)
+}
+
+// trackedMessageSummary is a tracked message as listed by the admin API.
+type trackedMessageSummary struct {
+	ID             string    `json:"id"`
+	RepoFullName   string    `json:"repo_full_name"`
+	PRNumber       int       `json:"pr_number"`
+	SlackChannel   string    `json:"slack_channel"`
+	SlackMessageTS string    `json:"slack_message_ts"`
+	MessageSource  string    `json:"message_source"`
+	DeletedByUser  bool      `json:"deleted_by_user,omitempty"`
+	SupersededByPR int       `json:"superseded_by_pr,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// HandleListTrackedMessages returns a page of a workspace's tracked messages as JSON.
+// GET /admin/workspaces/:workspace_id/tracked-messages[?repo=&pr=&channel=&source=&state=&order=
+// &created_after=&created_before=&limit=&page_token=]. Times are RFC 3339.
+func (h *AdminHandler) HandleListTrackedMessages(c *gin.Context) {
+	ctx := c.Request.Context()
+	workspaceID := c.Param("workspace_id")
+
+	query, err := parseTrackedMessageQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid query", "details": err.Error()})
+		return
+	}
+	query.SlackTeamID = workspaceID
+
+	page, err := h.firestoreService.QueryTrackedMessages(ctx, query)
+	if errors.Is(err, services.ErrInvalidTrackedMessageQuery) || errors.Is(err, services.ErrInvalidPageToken) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid query", "details": err.Error()})
+		return
+	}
+	if err != nil {
+		log.Error(ctx, "Failed to list tracked messages", "error", err, "slack_team_id", workspaceID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list tracked messages"})
+		return
+	}
+
+	messages := make([]trackedMessageSummary, 0, len(page.Messages))
+	for _, msg := range page.Messages {
+		messages = append(messages, trackedMessageSummary{
+			ID:             msg.ID,
+			RepoFullName:   msg.RepoFullName,
+			PRNumber:       msg.PRNumber,
+			SlackChannel:   msg.SlackChannel,
+			SlackMessageTS: msg.SlackMessageTS,
+			MessageSource:  msg.MessageSource,
+			DeletedByUser:  msg.DeletedByUser,
+			SupersededByPR: msg.SupersededByPR,
+			CreatedAt:      msg.CreatedAt,
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"messages": messages, "next_page_token": page.NextPageToken})
+}
+
+// parseTrackedMessageQuery reads a tracked message query from the request's query parameters.
+func parseTrackedMessageQuery(c *gin.Context) (services.TrackedMessageQuery, error) {
+	query := services.TrackedMessageQuery{
+		RepoFullName:  c.Query("repo"),
+		SlackChannel:  c.Query("channel"),
+		MessageSource: c.Query("source"),
+		State:         c.Query("state"),
+		Order:         c.Query("order"),
+		PageToken:     c.Query("page_token"),
+	}
+
+	var err error
+	if pr := c.Query("pr"); pr != "" {
+		if query.PRNumber, err = strconv.Atoi(pr); err != nil {
+			return query, fmt.Errorf("pr must be a number: %w", err)
+		}
+	}
+	if limit := c.Query("limit"); limit != "" {
+		if query.Limit, err = strconv.Atoi(limit); err != nil {
+			return query, fmt.Errorf("limit must be a number: %w", err)
+		}
+	}
+	if after := c.Query("created_after"); after != "" {
+		if query.CreatedAfter, err = time.Parse(time.RFC3339, after); err != nil {
+			return query, fmt.Errorf("created_after must be an RFC 3339 time: %w", err)
+		}
+	}
+	if before := c.Query("created_before"); before != "" {
+		if query.CreatedBefore, err = time.Parse(time.RFC3339, before); err != nil {
+			return query, fmt.Errorf("created_before must be an RFC 3339 time: %w", err)
+		}
+	}
+	return query, nil
+}
+
+// HandleListNotifications returns the notifications the bot sent for a PR, repository or workspace as JSON, newest
+// first, for support investigations.
+// GET /admin/notifications?repo=<owner/repo>[&pr=&workspace=&limit=] or ?workspace=<slack_team_id>[&limit=].
+func (h *AdminHandler) HandleListNotifications(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	query := services.NotificationLogQuery{
+		RepoFullName: c.Query("repo"),
+		SlackTeamID:  c.Query("workspace"),
+	}
+	var err error
+	if pr := c.Query("pr"); pr != "" {
+		if query.PRNumber, err = strconv.Atoi(pr); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid query", "details": "pr must be a number"})
+			return
+		}
+	}
+	if limit := c.Query("limit"); limit != "" {
+		if query.Limit, err = strconv.Atoi(limit); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid query", "details": "limit must be a number"})
+			return
+		}
+	}
+
+	entries, err := h.firestoreService.QueryNotificationLog(ctx, query)
+	if errors.Is(err, services.ErrInvalidNotificationLogQuery) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid query", "details": err.Error()})
+		return
+	}
+	if err != nil {
+		log.Error(ctx, "Failed to list notifications", "error", err, "repo", query.RepoFullName, "slack_team_id", query.SlackTeamID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list notifications"})
+		return
+	}
+	if entries == nil {
+		entries = []*models.NotificationLogEntry{}
+	}
+	c.JSON(http.StatusOK, gin.H{"notifications": entries})
+}
+
+// workspaceSummary is a Slack workspace as listed by the admin API, without its token.
+type workspaceSummary struct {
+	ID            string     `json:"id"`
+	TeamName      string     `json:"team_name"`
+	EnterpriseID  string     `json:"enterprise_id,omitempty"`
+	InstalledAt   time.Time  `json:"installed_at"`
+	Revoked       bool       `json:"revoked"`
+	RevokedAt     *time.Time `json:"revoked_at,omitempty"`
+	RevokedReason string     `json:"revoked_reason,omitempty"`
+}
+
+// HandleListWorkspaces returns the installed Slack workspaces as JSON, with whether Slack has revoked their token.
+// GET /admin/workspaces[?revoked=true].
+func (h *AdminHandler) HandleListWorkspaces(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var workspaces []*models.SlackWorkspace
+	var err error
+	if c.Query("revoked") == "true" {
+		workspaces, err = h.workspaceService.ListRevokedWorkspaces(ctx)
+	} else {
+		workspaces, err = h.workspaceService.ListWorkspaces(ctx)
+	}
+	if err != nil {
+		log.Error(ctx, "Failed to list workspaces", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list workspaces"})
+		return
+	}
+
+	summaries := make([]workspaceSummary, 0, len(workspaces))
+	for _, workspace := range workspaces {
+		summaries = append(summaries, workspaceSummary{
+			ID:            workspace.ID,
+			TeamName:      workspace.TeamName,
+			EnterpriseID:  workspace.EnterpriseID,
+			InstalledAt:   workspace.InstalledAt,
+			Revoked:       workspace.IsRevoked(),
+			RevokedAt:     workspace.RevokedAt,
+			RevokedReason: workspace.RevokedReason,
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"workspaces": summaries})
+}
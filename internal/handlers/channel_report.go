@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+	"github-slack-notifier/internal/utils"
+)
+
+// ProcessChannelReportJob builds a PR report for a channel and delivers it to the requesting user.
+// The report is sent ephemerally unless the user asked for it to be posted to the channel.
+func (h *GitHubHandler) ProcessChannelReportJob(ctx context.Context, job *models.Job) error {
+	var reportJob models.ChannelReportJob
+	if err := json.Unmarshal(job.Payload, &reportJob); err != nil {
+		return fmt.Errorf("failed to unmarshal channel report job: %w", models.Permanent(err))
+	}
+
+	if err := reportJob.Validate(); err != nil {
+		return fmt.Errorf("invalid channel report job: %w", models.Permanent(err))
+	}
+
+	ctx = log.WithFields(ctx, log.LogFields{
+		"team_id":       reportJob.SlackTeamID,
+		"channel_id":    reportJob.SlackChannel,
+		"user_id":       reportJob.SlackUserID,
+		"report_job_id": reportJob.ID,
+	})
+
+	// Channel arguments may be names (e.g. "#general") rather than IDs
+	channelID, err := h.slackService.ResolveChannelID(ctx, reportJob.SlackTeamID, reportJob.SlackChannel)
+	if err != nil {
+		log.Warn(ctx, "Failed to resolve report channel", "error", err)
+		return h.slackService.SendEphemeralMessage(ctx, reportJob.SlackTeamID, reportJob.ReplyChannel, reportJob.SlackUserID,
+			fmt.Sprintf("❌ Couldn't find channel `%s`.", reportJob.SlackChannel))
+	}
+
+	// The user is in the channel they ran the command from, but reports on, or posted to, any other channel
+	// would show them the PR activity of private channels they aren't in
+	if channelID != reportJob.ReplyChannel {
+		isMember, err := h.slackService.IsChannelMember(ctx, reportJob.SlackTeamID, channelID, reportJob.SlackUserID)
+		if err != nil {
+			log.Error(ctx, "Failed to check report channel membership", "error", err)
+			return err
+		}
+		if !isMember {
+			log.Warn(ctx, "Rejected report on channel the user isn't a member of", "report_channel_id", channelID)
+			return h.slackService.SendEphemeralMessage(ctx, reportJob.SlackTeamID, reportJob.ReplyChannel, reportJob.SlackUserID,
+				fmt.Sprintf("❌ You can only report on channels you're a member of, and you aren't in `%s`.", reportJob.SlackChannel))
+		}
+	}
+
+	now := time.Now()
+	report, err := h.BuildChannelReport(ctx, reportJob.SlackTeamID, channelID, reportJob.Window, now)
+	if err != nil {
+		log.Error(ctx, "Failed to build channel report", "error", err)
+		return err
+	}
+
+	text := utils.FormatChannelReport(report, now)
+	if reportJob.Post {
+		_, err = h.slackService.PostMessage(ctx, reportJob.SlackTeamID, channelID, text)
+		return err
+	}
+
+	return h.slackService.SendEphemeralMessage(ctx, reportJob.SlackTeamID, reportJob.ReplyChannel, reportJob.SlackUserID, text)
+}
+
+// BuildChannelReport collects PR activity for PRs posted to a channel within the window.
+// PR state and review timing are fetched from GitHub; PRs that can't be fetched are skipped.
+func (h *GitHubHandler) BuildChannelReport(
+	ctx context.Context, teamID, channelID string, window time.Duration, now time.Time,
+) (*models.ChannelReport, error) {
+	since := now.Add(-window)
+	messages, err := h.firestoreService.GetRecentTrackedMessagesForChannel(ctx, teamID, channelID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.ChannelReport{
+		SlackChannel: channelID,
+		Window:       window,
+	}
+
+	if h.slackService.OpenPRButtonEnabled() {
+		report.Engagement = countReportEngagement(messages)
+	}
+
+	seen := make(map[string]bool)
+	for _, msg := range messages {
+		prKey := msg.RepoFullName + "#" + strconv.Itoa(msg.PRNumber)
+		if msg.DeletedByUser || seen[prKey] {
+			continue
+		}
+		seen[prKey] = true
+
+		h.addPRToChannelReport(ctx, report, msg.RepoFullName, msg.PRNumber, since)
+	}
+
+	// Oldest open PRs first, as they most need attention
+	sort.Slice(report.OpenPRs, func(i, j int) bool {
+		return report.OpenPRs[i].OpenedAt.Before(report.OpenPRs[j].OpenedAt)
+	})
+
+	log.Info(ctx, "Built channel report",
+		"pr_count", len(seen),
+		"open_count", len(report.OpenPRs),
+		"merged_count", report.MergedCount,
+		"closed_count", report.ClosedCount,
+	)
+
+	return report, nil
+}
+
+// countReportEngagement counts the bot's PR notifications and how many of them had their "Open PR" button clicked.
+func countReportEngagement(messages []*models.TrackedMessage) *models.ReportEngagement {
+	engagement := &models.ReportEngagement{}
+	for _, msg := range messages {
+		if msg.MessageSource != models.MessageSourceBot || msg.DeletedByUser {
+			continue
+		}
+		engagement.Notifications++
+		if msg.LinkClicks > 0 {
+			engagement.Clicked++
+		}
+	}
+	return engagement
+}
+
+// addPRToChannelReport fetches a PR from GitHub and records its state and review latency in the report.
+func (h *GitHubHandler) addPRToChannelReport(
+	ctx context.Context, report *models.ChannelReport, repoFullName string, prNumber int, since time.Time,
+) {
+	pr, reviewState, err := h.githubService.GetPullRequestWithReviews(ctx, repoFullName, prNumber)
+	if err != nil {
+		log.Warn(ctx, "Failed to fetch PR for channel report",
+			"error", err,
+			"repo", repoFullName,
+			"pr_number", prNumber,
+		)
+		return
+	}
+
+	switch {
+	case pr.GetMerged():
+		if pr.GetMergedAt().After(since) {
+			report.MergedCount++
+		}
+	case pr.GetState() == "closed":
+		if pr.GetClosedAt().After(since) {
+			report.ClosedCount++
+		}
+	default:
+		report.OpenPRs = append(report.OpenPRs, models.ChannelReportPR{
+			RepoFullName: repoFullName,
+			PRNumber:     prNumber,
+			Title:        pr.GetTitle(),
+			URL:          pr.GetHTMLURL(),
+			OpenedAt:     pr.GetCreatedAt().Time,
+			ReviewState:  reviewState,
+		})
+	}
+
+	firstReview, err := h.githubService.GetFirstReviewTime(ctx, repoFullName, prNumber, pr.GetUser().GetID())
+	if err != nil {
+		log.Warn(ctx, "Failed to fetch first review time for channel report",
+			"error", err,
+			"repo", repoFullName,
+			"pr_number", prNumber,
+		)
+		return
+	}
+	if firstReview != nil && firstReview.After(pr.GetCreatedAt().Time) {
+		report.ReviewLatencies = append(report.ReviewLatencies, firstReview.Sub(pr.GetCreatedAt().Time))
+	}
+}
@@ -1,10 +1,13 @@
 package handlers
 
 import (
+	"bytes"
+	"cmp"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"slices"
 	"strings"
@@ -13,12 +16,17 @@ import (
 	"github-slack-notifier/internal/config"
 	"github-slack-notifier/internal/log"
 	"github-slack-notifier/internal/models"
+	"github-slack-notifier/internal/policy"
+	"github-slack-notifier/internal/presentation"
 	"github-slack-notifier/internal/services"
+	"github-slack-notifier/internal/tracing"
 	"github-slack-notifier/internal/utils"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/go-github/v74/github"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var (
@@ -26,6 +34,22 @@ var (
 	ErrMissingAction        = errors.New("missing required field: action")
 	ErrMissingRepository    = errors.New("missing required field: repository")
 	ErrMissingInstallation  = errors.New("missing required field: installation")
+	ErrMissingRef           = errors.New("missing required field: ref")
+	ErrMissingSHA           = errors.New("missing required field: sha")
+
+	ErrUnknownGitHubEnterpriseHost = errors.New("webhook from unknown GitHub Enterprise Server host")
+)
+
+// githubEnterpriseHostHeader names the GitHub Enterprise Server instance a webhook came from; github.com doesn't send it.
+const githubEnterpriseHostHeader = "X-GitHub-Enterprise-Host"
+
+// Which webhook secret a delivery's signature matched, logged as webhook_secret to monitor secret rotations.
+const (
+	webhookSecretPrimary  = "primary"
+	webhookSecretPrevious = "previous"
+	webhookSecretNone     = "none" // No secret configured, so signatures aren't checked
+	// The secret of the GitHub Enterprise Server instance named in X-GitHub-Enterprise-Host
+	webhookSecretEnterprise = "enterprise"
 )
 
 const (
@@ -34,8 +58,23 @@ const (
 	PRActionClosed                        = "closed"
 	PRActionReopened                      = "reopened"
 	PRActionReadyForReview                = "ready_for_review"
+	PRActionConvertedToDraft              = "converted_to_draft"
+	PRActionMilestoned                    = "milestoned"
+	PRActionDemilestoned                  = "demilestoned"
+	PRActionLabeled                       = "labeled"
+	PRActionUnlabeled                     = "unlabeled"
+	PRActionAssigned                      = "assigned"
+	PRActionUnassigned                    = "unassigned"
+	PRActionReviewRequested               = "review_requested"
+	PRActionReviewRequestRemoved          = "review_request_removed"
 	PRReviewActionSubmitted               = "submitted"
 	PRReviewActionDismissed               = "dismissed"
+	PRReviewActionEdited                  = "edited"
+	IssueCommentActionCreated             = "created"
+	IssueCommentActionEdited              = "edited"
+	IssueCommentActionDeleted             = "deleted"
+	IssueActionClosed                     = "closed"
+	IssueActionReopened                   = "reopened"
 	InstallationActionCreated             = "created"
 	InstallationActionDeleted             = "deleted"
 	InstallationActionSuspend             = "suspend"
@@ -48,6 +87,18 @@ const (
 	EventTypeInstallation                 = "installation"
 	EventTypeInstallationRepositories     = "installation_repositories"
 	EventTypeGitHubAppAuth                = "github_app_authorization"
+	EventTypeCreate                       = "create"
+	EventTypeCheckSuite                   = "check_suite"
+	EventTypeStatus                       = "status"
+	EventTypeIssueComment                 = "issue_comment"
+	EventTypeIssues                       = "issues"
+	EventTypePush                         = "push"
+	EventTypeProjectsV2Item               = "projects_v2_item"
+	EventTypeRepository                   = "repository"
+	RepositoryActionRenamed               = "renamed"
+	RepositoryActionTransferred           = "transferred"
+	RepositoryActionDeleted               = "deleted"
+	CheckSuiteActionCompleted             = "completed"
 	RepositorySelectionSelected           = "selected"
 )
 
@@ -62,36 +113,43 @@ type PRUpdateChanges struct {
 	NewCC             []string
 	OldHasDirective   bool
 	NewHasDirective   bool
+	MetadataChanged   bool     // Labels or milestone differ from what the messages were last rendered with
+	NewLabels         []string // Names of the PR's labels
+	NewMilestone      string
+}
+
+// HasChanges reports whether any change needs to be reflected in Slack messages.
+func (c *PRUpdateChanges) HasChanges() bool {
+	return c.TitleChanged || c.CCChanged || c.DirectivesChanged || c.MetadataChanged
 }
 
 // Utility functions
 
 // Channel utility functions
 
-// isChannelID checks if a string looks like a Slack channel ID (e.g., "C0964H95F6C").
-func isChannelID(s string) bool {
-	return len(s) >= 9 && s[0] == 'C' && strings.ToUpper(s) == s
-}
-
 // getChannelNameForStorage determines what channel name to store (never store IDs as names).
 func getChannelNameForStorage(targetChannel, annotatedChannel string) string {
-	if !isChannelID(targetChannel) {
+	if !utils.IsChannelID(targetChannel) {
 		return targetChannel
 	}
-	if annotatedChannel != "" && !isChannelID(annotatedChannel) {
+	if annotatedChannel != "" && !utils.IsChannelID(annotatedChannel) {
 		return annotatedChannel
 	}
 	return "" // Can't determine name from ID
 }
 
 // channelsMatch checks if a stored channel matches a new channel reference.
-func channelsMatch(storedName, storedID, newChannel string) bool {
+// In strict mode only channel IDs are compared, so a renamed channel can't collide with another channel's old name.
+func channelsMatch(storedName, storedID, newChannel string, strict bool) bool {
+	if strict {
+		return storedID != "" && utils.IsChannelID(newChannel) && storedID == newChannel
+	}
 	// Prefer name comparison when available
-	if storedName != "" && !isChannelID(storedName) && !isChannelID(newChannel) {
+	if storedName != "" && !utils.IsChannelID(storedName) && !utils.IsChannelID(newChannel) {
 		return storedName == newChannel
 	}
 	// Fall back to ID comparison
-	if isChannelID(newChannel) && storedID != "" {
+	if utils.IsChannelID(newChannel) && storedID != "" {
 		return storedID == newChannel
 	}
 	return false
@@ -108,7 +166,31 @@ type GitHubHandler struct {
 	slackService      *services.SlackService
 	githubService     *services.GitHubService
 	webhookSecret     string
-	emojiConfig       config.EmojiConfig
+	// Secret being rotated out, still accepted until GitHub signs every delivery with webhookSecret; "" when not rotating
+	previousWebhookSecret string
+	// Webhook secret of each GitHub Enterprise Server instance by hostname
+	enterpriseWebhookSecrets map[string]string
+	emojiConfig              config.EmojiConfig
+	strictChannels           bool
+	policyEngine             *policy.Engine
+	presentations            *presentation.Resolver
+	prUpdateBudget           int    // Slack message updates allowed per PR per hour; 0 disables the cap
+	skipLabel                string // PRs with this label are treated as having the skip directive; off when empty
+	// How long received webhooks are kept for replay; 0 disables the webhook event log
+	eventRetention time.Duration
+}
+
+// GitHubHandlerOptions holds the optional settings of a GitHubHandler. The zero value turns them all off.
+type GitHubHandlerOptions struct {
+	// Secret being rotated out, still accepted until GitHub signs every delivery with the webhook secret
+	PreviousWebhookSecret string
+	// Webhook secret of each GitHub Enterprise Server instance by hostname
+	EnterpriseWebhookSecrets map[string]string
+	StrictChannelMatching    bool                // Compare channels by resolved ID rather than by name
+	PresentationRules        []presentation.Rule // Presentation of PR messages by draft, CI and approval state
+	PRUpdateBudget           int                 // Slack message updates allowed per PR per hour; 0 disables the cap
+	WebhookEventRetention    time.Duration       // How long received webhooks are kept for replay; 0 disables the log
+	SkipLabel                string              // PRs with this label are treated as having the skip directive
 }
 
 // NewGitHubHandler creates a new GitHubHandler with the provided services and configuration.
@@ -120,17 +202,88 @@ func NewGitHubHandler(
 	githubService *services.GitHubService,
 	webhookSecret string,
 	emojiConfig config.EmojiConfig,
+	opts GitHubHandlerOptions,
 ) *GitHubHandler {
 	return &GitHubHandler{
-		cloudTasksService: cloudTasksService,
-		firestoreService:  firestoreService,
-		slackService:      slackService,
-		githubService:     githubService,
-		webhookSecret:     webhookSecret,
-		emojiConfig:       emojiConfig,
+		cloudTasksService:        cloudTasksService,
+		firestoreService:         firestoreService,
+		slackService:             slackService,
+		githubService:            githubService,
+		webhookSecret:            webhookSecret,
+		previousWebhookSecret:    opts.PreviousWebhookSecret,
+		enterpriseWebhookSecrets: opts.EnterpriseWebhookSecrets,
+		emojiConfig:              emojiConfig,
+		strictChannels:           opts.StrictChannelMatching,
+		policyEngine:             policy.NewEngine(),
+		presentations:            presentation.NewResolver(opts.PresentationRules),
+		prUpdateBudget:           opts.PRUpdateBudget,
+		eventRetention:           opts.WebhookEventRetention,
+		skipLabel:                opts.SkipLabel,
 	}
 }
 
+// validateWebhookSignature validates a webhook's payload and signature with the go-github library, trying the
+// current webhook secret and then the previous one during a rotation. Returns the payload and which secret it was
+// signed with, or webhookSecretNone when no secret is configured and signatures aren't checked.
+// Webhooks from a GitHub Enterprise Server instance must be signed with that instance's secret.
+func (h *GitHubHandler) validateWebhookSignature(r *http.Request) ([]byte, string, error) {
+	if host := r.Header.Get(githubEnterpriseHostHeader); host != "" {
+		secret, ok := h.enterpriseWebhookSecrets[strings.ToLower(host)]
+		if !ok {
+			return nil, "", fmt.Errorf("%w: %s", ErrUnknownGitHubEnterpriseHost, host)
+		}
+		payload, err := github.ValidatePayload(r, []byte(secret))
+		return payload, webhookSecretEnterprise, err
+	}
+
+	if h.webhookSecret == "" {
+		payload, err := github.ValidatePayload(r, nil)
+		return payload, webhookSecretNone, err
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read webhook body: %w", err)
+	}
+
+	secrets := []struct{ name, secret string }{
+		{webhookSecretPrimary, h.webhookSecret},
+		{webhookSecretPrevious, h.previousWebhookSecret},
+	}
+	for _, candidate := range secrets {
+		if candidate.secret == "" {
+			continue
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		payload, validateErr := github.ValidatePayload(r, []byte(candidate.secret))
+		if validateErr == nil {
+			return payload, candidate.name, nil
+		}
+		err = validateErr
+	}
+	return nil, "", err
+}
+
+// resolveChannelForMatching resolves a channel reference to its ID when strict channel matching is enabled.
+// Returns the channel to compare against and whether strict comparison should be used.
+// Falls back to heuristic matching if the channel can't be resolved.
+func (h *GitHubHandler) resolveChannelForMatching(ctx context.Context, teamID, channel string) (string, bool) {
+	if !h.strictChannels || utils.IsChannelID(channel) {
+		return channel, h.strictChannels
+	}
+
+	channelID, err := h.slackService.ResolveChannelID(ctx, teamID, channel)
+	if err != nil {
+		log.Warn(ctx, "Failed to resolve channel for strict matching, falling back to name comparison",
+			"error", err,
+			"channel", channel,
+			"slack_team_id", teamID,
+		)
+		return channel, false
+	}
+	return channelID, true
+}
+
 // HandleWebhook processes incoming GitHub webhook events.
 // Validates payload signature, creates webhook jobs, and enqueues them for async processing.
 func (h *GitHubHandler) HandleWebhook(c *gin.Context) {
@@ -139,8 +292,15 @@ func (h *GitHubHandler) HandleWebhook(c *gin.Context) {
 
 	eventType := c.GetHeader("X-Github-Event")
 	deliveryID := c.GetHeader("X-Github-Delivery")
+	githubHost := strings.ToLower(c.GetHeader(githubEnterpriseHostHeader))
+
+	// Root span of the webhook's trace, which continues in the jobs it enqueues
+	ctx, span := tracing.Start(tracing.Extract(c.Request.Context(), c.Request.Header), "github.webhook", trace.SpanKindServer,
+		attribute.String("github.event", eventType),
+		attribute.String("github.delivery", deliveryID),
+	)
+	defer func() { tracing.EndHTTP(span, c.Writer.Status()) }()
 
-	ctx := c.Request.Context()
 	// Add request metadata to context for all log calls
 	ctx = log.WithFields(ctx, log.LogFields{
 		"trace_id":        traceID,
@@ -149,6 +309,9 @@ func (h *GitHubHandler) HandleWebhook(c *gin.Context) {
 		"github_event":    eventType,
 		"github_delivery": deliveryID,
 	})
+	if githubHost != "" {
+		ctx = log.WithFields(ctx, log.LogFields{"github_host": githubHost})
+	}
 
 	if eventType == "" || deliveryID == "" {
 		log.Error(ctx, "Missing required headers")
@@ -156,18 +319,19 @@ func (h *GitHubHandler) HandleWebhook(c *gin.Context) {
 		return
 	}
 
-	// Use go-github library to validate payload and signature
-	var secretToken []byte
-	if h.webhookSecret != "" {
-		secretToken = []byte(h.webhookSecret)
-	}
-
-	payload, err := github.ValidatePayload(c.Request, secretToken)
+	payload, matchedSecret, err := h.validateWebhookSignature(c.Request)
 	if err != nil {
 		log.Error(ctx, "Invalid webhook payload or signature", "error", err)
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid payload or signature"})
 		return
 	}
+	ctx = log.WithFields(ctx, log.LogFields{"webhook_secret": matchedSecret})
+	if matchedSecret == webhookSecretPrevious {
+		// Deliveries still signed with the old secret mean the GitHub App hasn't been switched to the new one yet
+		log.Info(ctx, "Webhook signed with previous webhook secret")
+	}
+
+	h.recordWebhookEvent(ctx, eventType, deliveryID, c.Request.Header, payload)
 
 	if err := h.validateWebhookPayload(eventType, payload); err != nil {
 		log.Error(ctx, "Invalid webhook payload", "error", err)
@@ -185,6 +349,7 @@ func (h *GitHubHandler) HandleWebhook(c *gin.Context) {
 		ReceivedAt: time.Now(),
 		Status:     "queued",
 		RetryCount: 0,
+		GitHubHost: githubHost,
 	}
 
 	// Marshal the WebhookJob as the payload for the Job
@@ -226,7 +391,7 @@ func (h *GitHubHandler) HandleWebhook(c *gin.Context) {
 // Ensures required fields are present for each supported webhook event type.
 func (h *GitHubHandler) validateWebhookPayload(eventType string, payload []byte) error {
 	switch eventType {
-	case "pull_request", "pull_request_review":
+	case "pull_request", "pull_request_review", "check_suite", "issue_comment", "issues", "repository":
 		return h.validateGitHubPayload(payload)
 	case "installation":
 		return h.validateInstallationPayload(payload)
@@ -235,6 +400,13 @@ func (h *GitHubHandler) validateWebhookPayload(eventType string, payload []byte)
 	case "github_app_authorization":
 		// GitHub app authorization events don't need special validation
 		return nil
+	case "create", "push":
+		// Push payloads need the same ref and repository fields as create payloads
+		return h.validateCreatePayload(payload)
+	case "projects_v2_item":
+		return h.validateProjectsV2ItemPayload(payload)
+	case "status":
+		return h.validateStatusPayload(payload)
 	default:
 		return fmt.Errorf("%w: %s", ErrUnsupportedEventType, eventType)
 	}
@@ -263,7 +435,7 @@ func (h *GitHubHandler) validateGitHubPayload(payload []byte) error {
 func (h *GitHubHandler) ProcessWebhookJob(ctx context.Context, job *models.Job) error {
 	var webhookJob models.WebhookJob
 	if err := json.Unmarshal(job.Payload, &webhookJob); err != nil {
-		return fmt.Errorf("failed to unmarshal webhook job: %w", err)
+		return fmt.Errorf("failed to unmarshal webhook job: %w", models.Permanent(err))
 	}
 
 	ctx = log.WithFields(ctx, log.LogFields{
@@ -274,17 +446,51 @@ func (h *GitHubHandler) ProcessWebhookJob(ctx context.Context, job *models.Job)
 
 	log.Debug(ctx, "Processing GitHub webhook job")
 
+	// GitHub redelivers webhooks, and deliveries are retried after partial failures; replays run regardless
+	if webhookJob.Replay {
+		log.Info(ctx, "Replaying webhook delivery from the webhook event log")
+	} else if delivery := h.getWebhookDelivery(ctx, webhookJob.DeliveryID); delivery != nil && delivery.Processed {
+		log.Info(ctx, "Skipping webhook delivery that was already processed")
+		return nil
+	}
+
+	if err := h.processWebhookEvent(withDeliveryID(ctx, webhookJob.DeliveryID), &webhookJob); err != nil {
+		return err
+	}
+
+	h.markWebhookDeliveryProcessed(ctx, &webhookJob)
+	return nil
+}
+
+// processWebhookEvent routes a GitHub webhook job to the processor for its event type.
+func (h *GitHubHandler) processWebhookEvent(ctx context.Context, webhookJob *models.WebhookJob) error {
 	switch webhookJob.EventType {
 	case EventTypePullRequest:
 		return h.processPullRequestEvent(ctx, webhookJob.Payload)
 	case EventTypePullRequestReview:
 		return h.processPullRequestReviewEvent(ctx, webhookJob.Payload, webhookJob.TraceID)
 	case EventTypeInstallation:
-		return h.processInstallationEvent(ctx, webhookJob.Payload)
+		return h.processInstallationEvent(ctx, webhookJob.Payload, webhookJob.GitHubHost)
 	case EventTypeInstallationRepositories:
 		return h.processInstallationRepositoriesEvent(ctx, webhookJob.Payload)
 	case EventTypeGitHubAppAuth:
 		return h.processGitHubAppAuthEvent(ctx, webhookJob.Payload)
+	case EventTypeCreate:
+		return h.processCreateEvent(ctx, webhookJob.Payload)
+	case EventTypeCheckSuite:
+		return h.processCheckSuiteEvent(ctx, webhookJob.Payload, webhookJob.TraceID)
+	case EventTypeStatus:
+		return h.processStatusEvent(ctx, webhookJob.Payload, webhookJob.TraceID)
+	case EventTypeIssueComment:
+		return h.processIssueCommentEvent(ctx, webhookJob.Payload, webhookJob.TraceID)
+	case EventTypeIssues:
+		return h.processIssuesEvent(ctx, webhookJob.Payload)
+	case EventTypePush:
+		return h.processPushEvent(ctx, webhookJob.Payload, webhookJob.TraceID)
+	case EventTypeProjectsV2Item:
+		return h.processProjectsV2ItemEvent(ctx, webhookJob.Payload)
+	case EventTypeRepository:
+		return h.processRepositoryEvent(ctx, webhookJob.Payload)
 	default:
 		return fmt.Errorf("%w: %s", ErrUnsupportedEventType, webhookJob.EventType)
 	}
@@ -292,10 +498,10 @@ func (h *GitHubHandler) ProcessWebhookJob(ctx context.Context, job *models.Job)
 
 // ProcessWorkspacePRJob processes a workspace-specific PR job from the job system.
 // This method handles PR notifications for a single workspace.
-func (h *GitHubHandler) ProcessWorkspacePRJob(ctx context.Context, job *models.Job) error {
+func (h *GitHubHandler) ProcessWorkspacePRJob(ctx context.Context, job *models.Job) (err error) {
 	var workspacePRJob models.WorkspacePRJob
 	if err := json.Unmarshal(job.Payload, &workspacePRJob); err != nil {
-		return fmt.Errorf("failed to unmarshal workspace PR job: %w", err)
+		return fmt.Errorf("failed to unmarshal workspace PR job: %w", models.Permanent(err))
 	}
 
 	// Add job metadata to context for all log calls
@@ -309,6 +515,13 @@ func (h *GitHubHandler) ProcessWorkspacePRJob(ctx context.Context, job *models.J
 
 	log.Debug(ctx, "Processing workspace PR job")
 
+	// Release the job's place in the PR's sequence once it's done; jobs being retried keep it
+	defer func() {
+		if err == nil || !isJobRetryableError(err) {
+			h.completePRSequenceJobs(ctx, workspacePRJob.RepoFullName, workspacePRJob.PRNumber, 1)
+		}
+	}()
+
 	// Unmarshal the GitHub payload
 	var githubPayload github.PullRequestEvent
 	if err := json.Unmarshal(workspacePRJob.PRPayload, &githubPayload); err != nil {
@@ -316,33 +529,28 @@ func (h *GitHubHandler) ProcessWorkspacePRJob(ctx context.Context, job *models.J
 			"error", err,
 			"payload_size", len(workspacePRJob.PRPayload),
 		)
-		return fmt.Errorf("failed to unmarshal GitHub payload from workspace PR job: %w", err)
+		return fmt.Errorf("failed to unmarshal GitHub payload from workspace PR job: %w", models.Permanent(err))
 	}
 
-	// Get user information
-	var user *models.User
-	var err error
-	if workspacePRJob.GitHubUserID > 0 {
-		user, err = h.firestoreService.GetUserByGitHubUserID(ctx, workspacePRJob.GitHubUserID)
-		if err != nil {
-			log.Error(ctx, "Failed to lookup user by GitHub user ID",
-				"error", err,
-				"github_user_id", workspacePRJob.GitHubUserID,
-			)
-			return err
-		}
-	}
-
-	// Get workspace repository configuration
-	repo, err := h.firestoreService.GetRepo(ctx, workspacePRJob.RepoFullName, workspacePRJob.WorkspaceID)
+	// Read the delivery record, repository configuration and author together
+	docs, err := h.firestoreService.GetWorkspacePRDocuments(ctx, workspacePRJob.DeliveryID,
+		workspacePRJob.RepoFullName, workspacePRJob.WorkspaceID, workspacePRJob.GitHubUserID)
 	if err != nil {
-		log.Error(ctx, "Failed to get repository configuration",
+		log.Error(ctx, "Failed to get workspace PR job documents",
 			"error", err,
-			"workspace_id", workspacePRJob.WorkspaceID,
-			"repo", workspacePRJob.RepoFullName,
+			"github_user_id", workspacePRJob.GitHubUserID,
 		)
 		return err
 	}
+	repo, user := docs.Repo, docs.User
+
+	// A retried or redelivered webhook is a no-op for workspaces its PR job already succeeded in
+	if docs.Delivery.WorkspaceCompleted(workspacePRJob.WorkspaceID) {
+		log.Info(ctx, "Skipping workspace PR job already completed for this webhook delivery",
+			"delivery_id", workspacePRJob.DeliveryID,
+		)
+		return nil
+	}
 
 	if repo == nil {
 		log.Warn(ctx, "Repository configuration not found for workspace",
@@ -356,11 +564,17 @@ func (h *GitHubHandler) ProcessWorkspacePRJob(ctx context.Context, job *models.J
 	_, directives := h.slackService.ExtractChannelAndDirectives(githubPayload.GetPullRequest().GetBody())
 
 	// Process the notification for this specific workspace
-	return h.processWorkspaceNotification(ctx, &githubPayload, repo, user, workspacePRJob.AnnotatedChannel, directives)
+	err = h.processWorkspaceNotification(ctx, &githubPayload, repo, user, workspacePRJob.AnnotatedChannel, directives)
+	if err != nil {
+		return err
+	}
+
+	h.markWebhookDeliveryWorkspaceCompleted(ctx, workspacePRJob.DeliveryID, workspacePRJob.WorkspaceID)
+	return nil
 }
 
 // processPullRequestEvent processes pull request webhook events.
-// Handles PR opened, edited, ready_for_review, and closed actions with appropriate notifications.
+// Handles PR opened, edited, ready_for_review, converted_to_draft, closed, and review request actions with appropriate notifications.
 func (h *GitHubHandler) processPullRequestEvent(ctx context.Context, payload []byte) error {
 	var githubPayload github.PullRequestEvent
 	if err := json.Unmarshal(payload, &githubPayload); err != nil {
@@ -368,7 +582,7 @@ func (h *GitHubHandler) processPullRequestEvent(ctx context.Context, payload []b
 			"error", err,
 			"payload_size", len(payload),
 		)
-		return fmt.Errorf("failed to unmarshal pull request payload: %w", err)
+		return fmt.Errorf("failed to unmarshal pull request payload: %w", models.Permanent(err))
 	}
 
 	// Add PR metadata to context for all subsequent log calls
@@ -383,6 +597,12 @@ func (h *GitHubHandler) processPullRequestEvent(ctx context.Context, payload []b
 		"is_draft", githubPayload.GetPullRequest().GetDraft(),
 	)
 
+	if waitsForPRSequence(githubPayload.GetAction()) {
+		if err := h.checkPRSequence(ctx, &githubPayload); err != nil {
+			return err
+		}
+	}
+
 	switch githubPayload.GetAction() {
 	case PRActionOpened:
 		return h.handlePROpened(ctx, &githubPayload)
@@ -390,10 +610,20 @@ func (h *GitHubHandler) processPullRequestEvent(ctx context.Context, payload []b
 		return h.handlePREdited(ctx, &githubPayload)
 	case PRActionReadyForReview:
 		return h.handlePRReadyForReview(ctx, &githubPayload)
+	case PRActionConvertedToDraft:
+		return h.handlePRConvertedToDraft(ctx, &githubPayload)
 	case PRActionClosed:
 		return h.handlePRClosed(ctx, &githubPayload)
 	case PRActionReopened:
 		return h.handlePRReopened(ctx, &githubPayload)
+	case PRActionMilestoned, PRActionDemilestoned:
+		return h.handlePRMilestoneChanged(ctx, &githubPayload)
+	case PRActionLabeled, PRActionUnlabeled:
+		return h.handlePRLabelsChanged(ctx, &githubPayload)
+	case PRActionAssigned, PRActionUnassigned:
+		return h.handlePRAssignmentChanged(ctx, &githubPayload)
+	case PRActionReviewRequested, PRActionReviewRequestRemoved:
+		return h.handlePRReviewRequestChanged(ctx, &githubPayload)
 	default:
 		log.Warn(ctx, "Pull request action not handled")
 		return nil
@@ -409,7 +639,7 @@ func (h *GitHubHandler) processPullRequestReviewEvent(ctx context.Context, paylo
 			"error", err,
 			"payload_size", len(payload),
 		)
-		return fmt.Errorf("failed to unmarshal pull request review payload: %w", err)
+		return fmt.Errorf("failed to unmarshal pull request review payload: %w", models.Permanent(err))
 	}
 
 	// Add PR metadata to context for all subsequent log calls
@@ -422,6 +652,10 @@ func (h *GitHubHandler) processPullRequestReviewEvent(ctx context.Context, paylo
 		"review_action": githubPayload.GetAction(),
 	})
 
+	if err := h.handleReviewCommentThread(ctx, &githubPayload, traceID); err != nil {
+		return err
+	}
+
 	if githubPayload.GetAction() != PRReviewActionSubmitted && githubPayload.GetAction() != PRReviewActionDismissed {
 		return nil
 	}
@@ -433,6 +667,7 @@ func (h *GitHubHandler) processPullRequestReviewEvent(ctx context.Context, paylo
 		PRNumber:     githubPayload.GetPullRequest().GetNumber(),
 		RepoFullName: githubPayload.GetRepo().GetFullName(),
 		TraceID:      traceID,
+		RequestedAt:  time.Now(),
 	}
 
 	// Marshal the ReactionSyncJob as the payload for the Job
@@ -460,6 +695,11 @@ func (h *GitHubHandler) processPullRequestReviewEvent(ctx context.Context, paylo
 		"job_id", reactionSyncJobID,
 		"review_action", githubPayload.Action)
 
+	if githubPayload.GetAction() == PRReviewActionSubmitted {
+		h.notifyAuthorOfChangesRequested(ctx, &githubPayload)
+		h.clearPendingReview(ctx, &githubPayload)
+	}
+
 	return nil
 }
 
@@ -480,10 +720,25 @@ func (h *GitHubHandler) handlePROpened(ctx context.Context, payload *github.Pull
 
 // getTraceIDFromContext extracts trace ID from context or returns empty string if not found.
 func getTraceIDFromContext(ctx context.Context) string {
-	if traceID, ok := ctx.Value(log.TraceIDKey).(string); ok {
+	return log.TraceID(ctx)
+}
+
+// traceIDForNewJob returns the request's trace ID so enqueued jobs keep the originating trace.
+// Falls back to a new ID when the context carries none.
+func traceIDForNewJob(ctx context.Context) string {
+	if traceID := log.TraceID(ctx); traceID != "" {
 		return traceID
 	}
-	return ""
+	return uuid.New().String()
+}
+
+// withTraceReference appends the trace ID to a user-facing error message so reports can be matched to logs.
+func withTraceReference(ctx context.Context, text string) string {
+	traceID := log.TraceID(ctx)
+	if traceID == "" {
+		return text
+	}
+	return fmt.Sprintf("%s\n_Trace ID: `%s`_", text, traceID)
 }
 
 // enqueueWorkspacePRJobs creates and enqueues WorkspacePR jobs for each workspace.
@@ -495,6 +750,7 @@ func (h *GitHubHandler) enqueueWorkspacePRJobs(
 	annotatedChannel string,
 	prAction string,
 ) error {
+	repos = h.withoutRevokedWorkspaces(ctx, repos)
 	if len(repos) == 0 {
 		log.Info(ctx, "No workspaces to process")
 		return nil
@@ -510,6 +766,11 @@ func (h *GitHubHandler) enqueueWorkspacePRJobs(
 		return fmt.Errorf("failed to marshal GitHub payload: %w", err)
 	}
 
+	// Hold later events for this PR until these jobs have posted its messages
+	repoFullName := payload.GetRepo().GetFullName()
+	prNumber := payload.GetPullRequest().GetNumber()
+	h.reservePRSequence(ctx, repoFullName, prNumber, len(repos))
+
 	// Track job enqueue failures
 	var enqueueErrors []error
 	enqueuedCount := 0
@@ -519,13 +780,14 @@ func (h *GitHubHandler) enqueueWorkspacePRJobs(
 		workspacePRJobID := uuid.New().String()
 		workspacePRJob := &models.WorkspacePRJob{
 			ID:               workspacePRJobID,
-			PRNumber:         payload.GetPullRequest().GetNumber(),
-			RepoFullName:     payload.GetRepo().GetFullName(),
+			PRNumber:         prNumber,
+			RepoFullName:     repoFullName,
 			WorkspaceID:      repo.WorkspaceID,
 			PRAction:         prAction,
 			GitHubUserID:     payload.GetPullRequest().GetUser().GetID(),
 			GitHubUsername:   payload.GetPullRequest().GetUser().GetLogin(),
 			AnnotatedChannel: annotatedChannel,
+			DeliveryID:       deliveryIDFromContext(ctx),
 			TraceID:          getTraceIDFromContext(ctx),
 			PRPayload:        githubPayloadBytes,
 		}
@@ -564,6 +826,11 @@ func (h *GitHubHandler) enqueueWorkspacePRJobs(
 		}
 	}
 
+	// Jobs that weren't enqueued will never complete
+	if len(enqueueErrors) > 0 {
+		h.completePRSequenceJobs(ctx, repoFullName, prNumber, len(enqueueErrors))
+	}
+
 	// Return error only if ALL enqueue operations failed
 	if len(enqueueErrors) == len(repos) {
 		return fmt.Errorf("%w: %v", models.ErrWorkspaceJobsEnqueueFailed, enqueueErrors)
@@ -614,6 +881,11 @@ func (h *GitHubHandler) postPRToAllWorkspaces(ctx context.Context, payload *gith
 	// Check if PR should be skipped
 	if directives.Skip {
 		log.Info(ctx, "Skipping PR notification due to skip directive")
+		h.recordSkipDirectiveUsage(ctx, payload, directives)
+		return nil
+	}
+	if h.hasSkipLabel(payload) {
+		log.Info(ctx, "Skipping PR notification due to skip label", "label", h.skipLabel)
 		return nil
 	}
 
@@ -646,29 +918,68 @@ func (h *GitHubHandler) postPRToAllWorkspaces(ctx context.Context, payload *gith
 	return h.enqueueWorkspacePRJobs(ctx, payload, repos, annotatedChannel, payload.GetAction())
 }
 
-// determineTargetChannel determines the target Slack channel for PR notifications.
-// Priority order: annotated channel from PR description -> user's default channel (if same workspace and notifications enabled).
-func (h *GitHubHandler) determineTargetChannel(
+// determineTargetChannels determines the target Slack channels for PR notifications.
+// Priority order: annotated channel from PR description, policy, or routing rules ->
+// user's channels for the repository -> the repository's default channel ->
+// user's default channels (if same workspace and notifications enabled).
+func (h *GitHubHandler) determineTargetChannels(
 	ctx context.Context,
 	repo *models.Repo,
 	user *models.User,
 	annotatedChannel string,
-) string {
+	trace *routingTrace,
+) []string {
 	if annotatedChannel != "" {
 		log.Debug(ctx, "Using annotated channel from PR description",
 			"channel", annotatedChannel,
 			"slack_team_id", repo.WorkspaceID)
-		return annotatedChannel
+		trace.add("Routed to #%s from the channel directive or policy", annotatedChannel)
+		return []string{annotatedChannel}
+	}
+
+	var channels []string
+	var overridden bool
+	if user != nil && user.SlackTeamID == repo.WorkspaceID {
+		channels, overridden = user.ChannelsForRepo(repo.RepoFullName)
 	}
 
-	if user != nil && user.SlackTeamID == repo.WorkspaceID && user.DefaultChannel != "" && user.NotificationsEnabled {
-		log.Debug(ctx, "Using user default channel",
-			"channel", user.DefaultChannel,
+	// Authors in the workspace who turned notifications off don't get posted to the repository's channel either
+	authorOptedOut := user != nil && user.SlackTeamID == repo.WorkspaceID && !user.NotificationsEnabled
+	if repo.DefaultChannel != "" && !overridden && !authorOptedOut {
+		log.Debug(ctx, "Using repository default channel",
+			"channel", repo.DefaultChannel,
 			"slack_team_id", repo.WorkspaceID)
-		return user.DefaultChannel
+		trace.add("Routed to the repository's default channel %s", repo.DefaultChannel)
+		return []string{repo.DefaultChannel}
 	}
 
-	return ""
+	if len(channels) > 0 && user.NotificationsEnabled {
+		log.Debug(ctx, "Using user channels",
+			"channels", channels,
+			"repo_override", overridden,
+			"slack_team_id", repo.WorkspaceID)
+		switch {
+		case overridden:
+			trace.add("Routed to the author's channels for %s: %s", repo.RepoFullName, strings.Join(channels, ", "))
+		case len(channels) == 1:
+			trace.add("Routed to the author's default channel %s", channels[0])
+		default:
+			trace.add("Routed to the author's default channels %s", strings.Join(channels, ", "))
+		}
+		return channels
+	}
+
+	switch {
+	case user == nil:
+		trace.add("No channel: the author hasn't connected their GitHub account and the PR has no channel directive")
+	case user.SlackTeamID != repo.WorkspaceID:
+		trace.add("No channel: the author's default channel is in another workspace")
+	case len(channels) == 0:
+		trace.add("No channel: the author has no default channel")
+	default:
+		trace.add("No channel: the author has notifications disabled")
+	}
+	return nil
 }
 
 // checkForDuplicateBotMessage checks if bot notification already exists for this PR in the target channel.
@@ -680,8 +991,12 @@ func (h *GitHubHandler) checkForDuplicateBotMessage(
 	workspaceID string,
 ) (bool, error) {
 	// Get all bot messages for this PR in the workspace (don't filter by channel initially)
-	allBotMessages, err := h.firestoreService.GetTrackedMessages(ctx,
-		payload.GetRepo().GetFullName(), payload.GetPullRequest().GetNumber(), "", workspaceID, models.MessageSourceBot)
+	allBotMessages, err := h.firestoreService.GetTrackedMessages(ctx, services.TrackedMessageQuery{
+		RepoFullName:  payload.GetRepo().GetFullName(),
+		PRNumber:      payload.GetPullRequest().GetNumber(),
+		SlackTeamID:   workspaceID,
+		MessageSource: models.MessageSourceBot,
+	})
 	if err != nil {
 		log.Error(ctx, "Failed to check for existing bot messages",
 			"error", err,
@@ -690,8 +1005,9 @@ func (h *GitHubHandler) checkForDuplicateBotMessage(
 	}
 
 	// Check if any existing bot message is in the same channel as targetChannel
+	matchChannel, strict := h.resolveChannelForMatching(ctx, workspaceID, targetChannel)
 	for _, msg := range allBotMessages {
-		if channelsMatch(msg.SlackChannelName, msg.SlackChannel, targetChannel) {
+		if channelsMatch(msg.SlackChannelName, msg.SlackChannel, matchChannel, strict) {
 			log.Info(ctx, "Bot message already exists for this PR in target channel, skipping duplicate notification",
 				"target_channel", targetChannel,
 				"existing_channel_name", msg.SlackChannelName,
@@ -708,6 +1024,16 @@ func (h *GitHubHandler) checkForDuplicateBotMessage(
 	return false, nil
 }
 
+// prSize returns the lines changed that pick a PR's size emoji, leaving out files matching PR_SIZE_EXCLUDE_PATHS.
+// Falls back to all of the PR's additions and deletions if its files can't be listed.
+func (h *GitHubHandler) prSize(ctx context.Context, repoFullName string, pr *github.PullRequest) int {
+	size, err := h.githubService.PullRequestSize(ctx, repoFullName, pr)
+	if err != nil {
+		log.Warn(ctx, "Failed to list PR files for PR size, counting every file", "error", err)
+	}
+	return size
+}
+
 // postAndTrackPRMessage posts PR notification to Slack and creates tracked message record.
 // Handles user preferences for tagging and impersonation, then saves tracking data to database.
 func (h *GitHubHandler) postAndTrackPRMessage(
@@ -723,8 +1049,7 @@ func (h *GitHubHandler) postAndTrackPRMessage(
 		"channel", targetChannel,
 		"slack_team_id", repo.WorkspaceID)
 
-	// Calculate PR size (additions + deletions)
-	prSize := payload.GetPullRequest().GetAdditions() + payload.GetPullRequest().GetDeletions()
+	prSize := h.prSize(ctx, payload.GetRepo().GetFullName(), payload.GetPullRequest())
 
 	// Get author's Slack user ID if they're in the same workspace and verified
 	var authorSlackUserID string
@@ -732,8 +1057,9 @@ func (h *GitHubHandler) postAndTrackPRMessage(
 		authorSlackUserID = user.SlackUserID
 	}
 
-	// Determine user tagging preference - disabled by default and null treated as disabled
-	userTaggingEnabled := user != nil && user.TaggingEnabled
+	// Determine user tagging preference - disabled by default and null treated as disabled, and off while the
+	// author has muted the repository
+	userTaggingEnabled := user != nil && user.TaggingEnabled && !user.RepoMuted(payload.GetRepo().GetFullName(), time.Now())
 
 	// Determine impersonation preference - default to enabled if user not found
 	impersonationEnabled := true
@@ -741,18 +1067,35 @@ func (h *GitHubHandler) postAndTrackPRMessage(
 		impersonationEnabled = user.GetImpersonationEnabled()
 	}
 
+	// Compact mode repos get one-line messages without reactions or extra annotation lines
+	compact := repo.GetNotificationMode() == models.NotificationModeCompact
+
 	// Resolve UsersToCC GitHub usernames to Slack user IDs if possible
 	var usersCCSlackIDs []string
+	ccDelegates := make(map[string]string)
 	for _, username := range directives.UsersToCC {
-		slackID := h.resolveUserMention(ctx, username, repo.WorkspaceID)
+		slackID := h.resolveCCMention(ctx, payload, username, repo.WorkspaceID, ccDelegates)
 		usersCCSlackIDs = append(usersCCSlackIDs, slackID)
 	}
 
-	timestamp, resolvedChannelID, err := h.slackService.PostPRMessage(
+	// Channels can opt into the rich Block Kit layout; compact repos stay on one line either way
+	postChannel, channelConfig := h.postChannelConfig(ctx, repo.WorkspaceID, targetChannel)
+	var rich *services.RichPRMessage
+	messageFormat := ""
+	if channelConfig.RichMessagesEnabled() && !compact {
+		rich = richPRMessage(payload)
+		messageFormat = models.MessageFormatRich
+	}
+
+	metadata := prMetadata(payload, channelConfig)
+	metadata.NoReviewers = h.slackService.NoReviewers().Warning && !compact &&
+		prHasNoReviewers(payload.GetPullRequest(), directives)
+
+	timestamp, resolvedChannelID, postedAsUserID, err := h.slackService.PostPRMessage(
 		ctx,
 		repo.WorkspaceID,
-		targetChannel,
-		payload.GetRepo().GetName(),
+		postChannel,
+		payload.GetRepo().GetFullName(),
 		payload.GetPullRequest().GetTitle(),
 		payload.GetPullRequest().GetUser().GetLogin(),
 		payload.GetPullRequest().GetBody(),
@@ -761,10 +1104,15 @@ func (h *GitHubHandler) postAndTrackPRMessage(
 		authorSlackUserID,
 		directives.UsersToCC,
 		usersCCSlackIDs,
-		directives.CustomEmoji,
+		h.validCustomEmoji(ctx, repo.WorkspaceID, cmp.Or(directives.CustomEmoji, repo.Emoji)),
 		impersonationEnabled,
 		userTaggingEnabled,
 		user,
+		channelConfig.GetPRSizeConfig(),
+		compact,
+		rich,
+		metadata,
+		ccDelegates,
 	)
 	if err != nil {
 		log.Error(ctx, "Failed to post PR message to Slack workspace",
@@ -799,6 +1147,19 @@ func (h *GitHubHandler) postAndTrackPRMessage(
 		PRAuthorGitHubID:   &prAuthorID,          // Store PR author GitHub ID for deletion authorization
 		UsersToCC:          directives.UsersToCC, // Store CC info for future updates
 		HasReviewDirective: &hasDirective,        // Track whether directive existed when message was created
+		SupersedesPRs: utils.ExtractSupersededPRNumbers(
+			payload.GetPullRequest().GetBody(), payload.GetPullRequest().GetNumber(),
+		),
+		Compact:        compact,
+		PostedAsUserID: postedAsUserID,
+		MessageFormat:  messageFormat,
+		PRLabels:       prLabelNames(payload),
+		PRMilestone:    prMilestone(payload),
+
+		NoReviewersWarning: metadata.NoReviewers,
+	}
+	if !compact {
+		initMessageDependencies(trackedMessage, payload.GetPullRequest().GetBody())
 	}
 
 	log.Debug(ctx, "Saving tracked message to database",
@@ -816,6 +1177,24 @@ func (h *GitHubHandler) postAndTrackPRMessage(
 	}
 	log.Debug(ctx, "Successfully saved tracked message to database")
 
+	// Explain the reactions and delete gesture the first time the author's PR is posted in the channel
+	if authorSlackUserID != "" && !compact {
+		h.sendOnboardingHint(ctx, repo.WorkspaceID, resolvedChannelID, authorSlackUserID)
+	}
+
+	// Show the status of any PRs this one depends on
+	if len(trackedMessage.Dependencies) > 0 {
+		if err := h.refreshMessageDependencies(ctx, trackedMessage, make(map[string]string), true); err != nil {
+			log.Warn(ctx, "Failed to show PR dependency status", "error", err)
+		}
+	}
+
+	h.inviteUnlinkedCCs(ctx, payload, repo.WorkspaceID, resolvedChannelID, directives.UsersToCC, usersCCSlackIDs)
+
+	if trackedMessage.NoReviewersWarning {
+		h.scheduleNoReviewersNudge(ctx, trackedMessage, authorSlackUserID)
+	}
+
 	return nil
 }
 
@@ -829,33 +1208,70 @@ func (h *GitHubHandler) processWorkspaceNotification(
 	annotatedChannel string,
 	directives *services.PRDirectives,
 ) error {
-	targetChannel := h.determineTargetChannel(ctx, repo, user, annotatedChannel)
-	if targetChannel == "" {
+	annotatedChannel, directives, skip := h.applyNotificationPolicy(ctx, payload, repo.WorkspaceID, annotatedChannel, directives, nil)
+	if skip {
+		return nil
+	}
+
+	repo, annotatedChannel, skip = h.applyMechanicalPRHandling(ctx, payload, repo, annotatedChannel, nil)
+	if skip {
+		return nil
+	}
+	annotatedChannel = h.applyRoutingRules(ctx, payload, repo, annotatedChannel, nil)
+
+	targetChannels := h.determineTargetChannels(ctx, repo, user, annotatedChannel, nil)
+	if len(targetChannels) == 0 {
 		log.Debug(ctx, "No target channel determined for workspace, skipping",
 			"slack_team_id", repo.WorkspaceID)
 		return nil
 	}
 
-	// Check for duplicate bot messages
-	isDuplicate, err := h.checkForDuplicateBotMessage(ctx, payload, targetChannel, repo.WorkspaceID)
-	if err != nil {
-		return err
-	}
-	if isDuplicate {
+	// Digest-only repos never post individual messages
+	if repo.GetNotificationMode() == models.NotificationModeDigestOnly {
+		for _, targetChannel := range targetChannels {
+			h.bufferChannelDigestEntry(ctx, payload, repo.WorkspaceID, targetChannel)
+		}
 		return nil
 	}
 
-	// Post message and track it
-	if err := h.postAndTrackPRMessage(ctx, payload, repo, user, targetChannel, annotatedChannel, directives); err != nil {
-		return err
+	directives = h.applyCodeOwnersCC(ctx, payload, repo, directives, nil)
+
+	// Post to every target channel, so one failing channel doesn't stop the others.
+	// Channels that already have the PR are skipped, so retries only post where it's missing.
+	var postErrors []error
+	postedCount := 0
+	for _, targetChannel := range targetChannels {
+		posted, err := h.postToTargetChannel(ctx, payload, repo, user, targetChannel, annotatedChannel, directives)
+		if err != nil {
+			postErrors = append(postErrors, err)
+		} else if posted {
+			postedCount++
+		}
+	}
+	if len(postErrors) > 0 {
+		if annotatedChannel != "" {
+			// A failing channel directive usually means a typo or a channel the bot isn't in
+			h.recordDirectiveUsage(ctx, payload, repo.WorkspaceID, directives, true)
+		}
+		return errors.Join(postErrors...)
+	}
+	if postedCount == 0 {
+		return nil
 	}
+	h.recordDirectiveUsage(ctx, payload, repo.WorkspaceID, directives, false)
+
+	// Link and annotate any PRs this one replaces (fork workflows that reopen under a new number)
+	h.annotateSupersededPRs(ctx, payload, repo.WorkspaceID)
 
 	// After posting, synchronize reactions with any existing manual messages for this PR in this workspace
-	allMessages, err := h.firestoreService.GetTrackedMessages(ctx,
-		payload.GetRepo().GetFullName(), payload.GetPullRequest().GetNumber(), targetChannel, repo.WorkspaceID, "")
+	allMessages, err := h.firestoreService.GetTrackedMessages(ctx, services.TrackedMessageQuery{
+		RepoFullName: payload.GetRepo().GetFullName(),
+		PRNumber:     payload.GetPullRequest().GetNumber(),
+		SlackTeamID:  repo.WorkspaceID,
+	})
 	if err != nil {
 		log.Error(ctx, "Failed to get all tracked messages for reaction sync", "error", err)
-	} else if len(allMessages) > 1 {
+	} else if len(allMessages) > postedCount {
 		// There are manual messages to sync with - we don't have current PR status yet, so we'll just log
 		log.Info(ctx, "Multiple tracked messages found for PR, reactions will be synced when status updates arrive",
 			"total_messages", len(allMessages))
@@ -864,6 +1280,41 @@ func (h *GitHubHandler) processWorkspaceNotification(
 	return nil
 }
 
+// postToTargetChannel posts and tracks the PR message in one target channel, unless it's already there
+// or the channel's filters keep it out. PRs posted without CCs are assigned the next reviewer from the
+// channel's rotation, if it has one. Returns whether a message was posted.
+func (h *GitHubHandler) postToTargetChannel(
+	ctx context.Context,
+	payload *github.PullRequestEvent,
+	repo *models.Repo,
+	user *models.User,
+	targetChannel string,
+	annotatedChannel string,
+	directives *services.PRDirectives,
+) (bool, error) {
+	if reason := h.channelFilterExclusion(ctx, payload, repo.WorkspaceID, targetChannel); reason != "" {
+		log.Info(ctx, "PR kept out of channel by its filters", "channel", targetChannel, "reason", reason)
+		return false, nil
+	}
+
+	isDuplicate, err := h.checkForDuplicateBotMessage(ctx, payload, targetChannel, repo.WorkspaceID)
+	if err != nil {
+		return false, err
+	}
+	if isDuplicate {
+		return false, nil
+	}
+
+	directives, rotationReviewer := h.assignRotationReviewer(ctx, payload, repo, targetChannel, directives)
+	if err := h.postAndTrackPRMessage(ctx, payload, repo, user, targetChannel, annotatedChannel, directives); err != nil {
+		return false, err
+	}
+	if rotationReviewer != "" {
+		h.requestRotationReview(ctx, payload, repo, rotationReviewer)
+	}
+	return true, nil
+}
+
 // handlePREdited handles pull request edited events.
 // Processes skip directive changes, channel changes, and re-posting logic.
 func (h *GitHubHandler) handlePREdited(ctx context.Context, payload *github.PullRequestEvent) error {
@@ -882,6 +1333,10 @@ func (h *GitHubHandler) handlePREdited(ctx context.Context, payload *github.Pull
 		log.Info(ctx, "Skip directive found, processing skip")
 		return h.processSkipDirective(ctx, payload)
 	}
+	if h.hasSkipLabel(payload) {
+		log.Info(ctx, "PR has the skip label, processing skip", "label", h.skipLabel)
+		return h.processSkipDirective(ctx, payload)
+	}
 
 	// Check if channel has changed - only for bot messages, not manual ones
 	if directives.Channel != "" {
@@ -904,13 +1359,18 @@ func (h *GitHubHandler) handlePREdited(ctx context.Context, payload *github.Pull
 		log.Info(ctx, "No channel directive found")
 	}
 
-	// Detect what has changed and update existing messages
+	// Detect what has changed and update existing messages, unless the PR has used up its update budget
 	changes := h.detectPRChanges(ctx, payload, directives)
-	if err := h.updateMessagesForPRChanges(ctx, payload, changes, directives); err != nil {
-		log.Error(ctx, "Failed to handle PR changes", "error", err)
-		return err
+	if !changes.HasChanges() || h.spendPRUpdateBudget(ctx, payload.GetRepo().GetFullName(), payload.GetPullRequest().GetNumber()) {
+		if err := h.updateMessagesForPRChanges(ctx, payload, changes, directives); err != nil {
+			log.Error(ctx, "Failed to handle PR changes", "error", err)
+			return err
+		}
 	}
 
+	// Re-parse "Depends on" references and refresh the dependency status line
+	h.syncPRDependencies(ctx, payload)
+
 	// No skip directive and no channel change - check if we need to re-post the PR
 	log.Info(ctx, "Processing unskip directive")
 	return h.handleUnskipDirective(ctx, payload)
@@ -925,8 +1385,19 @@ func (h *GitHubHandler) getAllTrackedMessagesForPRDirect(
 
 // compareChannelsForChange compares bot messages with new channel to detect changes.
 func (h *GitHubHandler) compareChannelsForChange(ctx context.Context, botMessages []*models.TrackedMessage, newChannel string) bool {
+	type resolvedChannel struct {
+		channel string
+		strict  bool
+	}
+	// Channel names resolve per workspace, so cache the resolution for each team
+	resolved := make(map[string]resolvedChannel)
 	for _, msg := range botMessages {
-		if !channelsMatch(msg.SlackChannelName, msg.SlackChannel, newChannel) {
+		target, ok := resolved[msg.SlackTeamID]
+		if !ok {
+			target.channel, target.strict = h.resolveChannelForMatching(ctx, msg.SlackTeamID, newChannel)
+			resolved[msg.SlackTeamID] = target
+		}
+		if !channelsMatch(msg.SlackChannelName, msg.SlackChannel, target.channel, target.strict) {
 			log.Info(ctx, "Channel change detected",
 				"stored_name", msg.SlackChannelName,
 				"stored_id", msg.SlackChannel,
@@ -983,8 +1454,11 @@ func (h *GitHubHandler) hasChannelChanged(ctx context.Context, payload *github.P
 		}
 	} else {
 		// Fallback to direct query (shouldn't be needed if retry worked above)
-		botMessages, err = h.firestoreService.GetTrackedMessages(ctx,
-			payload.GetRepo().GetFullName(), payload.GetPullRequest().GetNumber(), "", "", models.MessageSourceBot)
+		botMessages, err = h.firestoreService.GetTrackedMessages(ctx, services.TrackedMessageQuery{
+			RepoFullName:  payload.GetRepo().GetFullName(),
+			PRNumber:      payload.GetPullRequest().GetNumber(),
+			MessageSource: models.MessageSourceBot,
+		})
 		if err != nil {
 			log.Error(ctx, "Failed to get bot tracked messages for channel change check",
 				"error", err,
@@ -1003,10 +1477,28 @@ func (h *GitHubHandler) hasChannelChanged(ctx context.Context, payload *github.P
 		return false, nil
 	}
 
+	// Messages moved with the message shortcut stay where they were moved, unless the directive itself changes
+	if !h.channelDirectiveChanged(payload, newChannel) {
+		botMessages = slices.DeleteFunc(botMessages, func(msg *models.TrackedMessage) bool { return msg.MovedBy != "" })
+		if len(botMessages) == 0 {
+			log.Info(ctx, "All bot messages were moved manually, keeping them in place")
+			return false, nil
+		}
+	}
+
 	// Check if any bot message is in a different channel
 	return h.compareChannelsForChange(ctx, botMessages, newChannel), nil
 }
 
+// channelDirectiveChanged reports whether an edit changed the PR description's channel directive to newChannel.
+func (h *GitHubHandler) channelDirectiveChanged(payload *github.PullRequestEvent, newChannel string) bool {
+	oldBody := payload.GetChanges().GetBody()
+	if oldBody == nil {
+		return false
+	}
+	return h.slackService.ParsePRDirectives(oldBody.GetFrom()).Channel != newChannel
+}
+
 // handleChannelChange handles migration of PR notifications when channel directive changes.
 // Deletes bot messages from old channels and posts new message to the specified channel.
 func (h *GitHubHandler) handleChannelChange(
@@ -1017,8 +1509,11 @@ func (h *GitHubHandler) handleChannelChange(
 	)
 
 	// Get all bot messages for this PR across all workspaces
-	botMessages, err := h.firestoreService.GetTrackedMessages(ctx,
-		payload.GetRepo().GetFullName(), payload.GetPullRequest().GetNumber(), "", "", models.MessageSourceBot)
+	botMessages, err := h.firestoreService.GetTrackedMessages(ctx, services.TrackedMessageQuery{
+		RepoFullName:  payload.GetRepo().GetFullName(),
+		PRNumber:      payload.GetPullRequest().GetNumber(),
+		MessageSource: models.MessageSourceBot,
+	})
 	if err != nil {
 		log.Error(ctx, "Failed to get bot tracked messages for channel change",
 			"error", err,
@@ -1031,15 +1526,34 @@ func (h *GitHubHandler) handleChannelChange(
 		return h.postPRToAllWorkspaces(ctx, payload)
 	}
 
+	h.deleteMigratedMessages(ctx, botMessages)
+
+	// Post new message to the specified channel across all workspaces
+	err = h.postPRToAllWorkspaces(ctx, payload)
+	if err != nil {
+		log.Error(ctx, "Failed to post PR to new channel after migration",
+			"error", err,
+			"new_channel", directives.Channel,
+		)
+		return err
+	}
+
+	log.Info(ctx, "Successfully processed channel change",
+		"deleted_messages", len(botMessages),
+		"new_channel", directives.Channel,
+	)
+	return nil
+}
+
+// deleteMigratedMessages deletes bot messages being moved to another channel from Slack, along with their
+// tracking records. Failures are logged, so the PR is still posted to its new channel.
+func (h *GitHubHandler) deleteMigratedMessages(ctx context.Context, botMessages []*models.TrackedMessage) {
 	// Group messages by workspace for deletion
 	messagesByWorkspace := make(map[string][]services.MessageRef)
 	messageIDs := make([]string, 0, len(botMessages))
 
 	for _, msg := range botMessages {
-		messagesByWorkspace[msg.SlackTeamID] = append(messagesByWorkspace[msg.SlackTeamID], services.MessageRef{
-			Channel:   msg.SlackChannel,
-			Timestamp: msg.SlackMessageTS,
-		})
+		messagesByWorkspace[msg.SlackTeamID] = append(messagesByWorkspace[msg.SlackTeamID], trackedMessageRef(msg))
 		messageIDs = append(messageIDs, msg.ID)
 	}
 
@@ -1062,30 +1576,13 @@ func (h *GitHubHandler) handleChannelChange(
 	}
 
 	// Remove old tracking records from Firestore
-	err = h.firestoreService.DeleteTrackedMessages(ctx, messageIDs)
+	err := h.firestoreService.DeleteTrackedMessages(ctx, messageIDs)
 	if err != nil {
 		log.Error(ctx, "Failed to delete tracked messages from Firestore during channel change",
 			"error", err,
 			"message_count", len(messageIDs),
 		)
-		// Continue with posting new message even if cleanup failed
 	}
-
-	// Post new message to the specified channel across all workspaces
-	err = h.postPRToAllWorkspaces(ctx, payload)
-	if err != nil {
-		log.Error(ctx, "Failed to post PR to new channel after migration",
-			"error", err,
-			"new_channel", directives.Channel,
-		)
-		return err
-	}
-
-	log.Info(ctx, "Successfully processed channel change",
-		"deleted_messages", len(botMessages),
-		"new_channel", directives.Channel,
-	)
-	return nil
 }
 
 // processSkipDirective handles retroactive deletion of tracked messages when skip directive is added.
@@ -1116,10 +1613,7 @@ func (h *GitHubHandler) processSkipDirective(ctx context.Context, payload *githu
 	messageIDs := make([]string, 0, len(trackedMessages))
 
 	for _, msg := range trackedMessages {
-		messagesByWorkspace[msg.SlackTeamID] = append(messagesByWorkspace[msg.SlackTeamID], services.MessageRef{
-			Channel:   msg.SlackChannel,
-			Timestamp: msg.SlackMessageTS,
-		})
+		messagesByWorkspace[msg.SlackTeamID] = append(messagesByWorkspace[msg.SlackTeamID], trackedMessageRef(msg))
 		messageIDs = append(messageIDs, msg.ID)
 	}
 
@@ -1241,6 +1735,8 @@ func (h *GitHubHandler) detectPRChanges(
 		NewTitle:        payload.GetPullRequest().GetTitle(),
 		NewCC:           directives.UsersToCC,
 		NewHasDirective: directives.HasReviewDirective,
+		NewLabels:       prLabelNames(payload),
+		NewMilestone:    prMilestone(payload),
 	}
 
 	// Check if title changed
@@ -1255,8 +1751,11 @@ func (h *GitHubHandler) detectPRChanges(
 
 	// For CC and directive changes, we need to get existing bot messages to determine what was stored previously
 	// This is more complex because we need to check what the existing messages had
-	botMessages, err := h.firestoreService.GetTrackedMessages(ctx,
-		payload.GetRepo().GetFullName(), payload.GetPullRequest().GetNumber(), "", "", models.MessageSourceBot)
+	botMessages, err := h.firestoreService.GetTrackedMessages(ctx, services.TrackedMessageQuery{
+		RepoFullName:  payload.GetRepo().GetFullName(),
+		PRNumber:      payload.GetPullRequest().GetNumber(),
+		MessageSource: models.MessageSourceBot,
+	})
 	if err != nil {
 		log.Error(ctx, "Failed to get bot messages for change detection", "error", err)
 		// Continue without CC change detection if we can't get messages
@@ -1288,6 +1787,16 @@ func (h *GitHubHandler) detectPRChanges(
 				"new_has_directive", changes.NewHasDirective,
 			)
 		}
+
+		// Check if labels or milestone changed
+		if !slices.Equal(firstMsg.PRLabels, changes.NewLabels) || firstMsg.PRMilestone != changes.NewMilestone {
+			changes.MetadataChanged = true
+			log.Info(ctx, "Label or milestone change detected",
+				"old_labels", firstMsg.PRLabels,
+				"new_labels", changes.NewLabels,
+				"new_milestone", changes.NewMilestone,
+			)
+		}
 	} else if directives.HasReviewDirective {
 		// No existing bot messages, so any directive presence is a change
 		changes.DirectivesChanged = true
@@ -1304,14 +1813,17 @@ func (h *GitHubHandler) updateMessagesForPRChanges(
 	ctx context.Context, payload *github.PullRequestEvent, changes *PRUpdateChanges, directives *services.PRDirectives,
 ) error {
 	// If nothing changed, skip
-	if !changes.TitleChanged && !changes.CCChanged && !changes.DirectivesChanged {
+	if !changes.HasChanges() {
 		log.Debug(ctx, "No relevant changes detected, skipping message updates")
 		return nil
 	}
 
 	// Get all bot messages for this PR across all workspaces
-	botMessages, err := h.firestoreService.GetTrackedMessages(ctx,
-		payload.GetRepo().GetFullName(), payload.GetPullRequest().GetNumber(), "", "", models.MessageSourceBot)
+	botMessages, err := h.firestoreService.GetTrackedMessages(ctx, services.TrackedMessageQuery{
+		RepoFullName:  payload.GetRepo().GetFullName(),
+		PRNumber:      payload.GetPullRequest().GetNumber(),
+		MessageSource: models.MessageSourceBot,
+	})
 	if err != nil {
 		log.Error(ctx, "Failed to get bot messages for PR changes", "error", err)
 		return err
@@ -1409,6 +1921,13 @@ func (h *GitHubHandler) messageNeedsUpdate(
 		}
 	}
 
+	// Check if labels or milestone need updating, on messages that show them
+	if changes.MetadataChanged && h.showsPRMetadata(msg) &&
+		(!slices.Equal(msg.PRLabels, changes.NewLabels) || msg.PRMilestone != changes.NewMilestone) {
+		needsUpdate = true
+		changeReasons = append(changeReasons, "labels")
+	}
+
 	return needsUpdate, changeReasons
 }
 
@@ -1426,6 +1945,11 @@ func (h *GitHubHandler) createUpdatedMessage(msg *models.TrackedMessage, changes
 		updatedMsg.HasReviewDirective = &hasDirective
 	}
 
+	if changes.MetadataChanged {
+		updatedMsg.PRLabels = changes.NewLabels
+		updatedMsg.PRMilestone = changes.NewMilestone
+	}
+
 	return &updatedMsg
 }
 
@@ -1453,7 +1977,7 @@ func (h *GitHubHandler) performPRMessageUpdates(
 		}
 	}
 
-	prSize := payload.GetPullRequest().GetAdditions() + payload.GetPullRequest().GetDeletions()
+	prSize := h.prSize(ctx, payload.GetRepo().GetFullName(), payload.GetPullRequest())
 
 	// Update each message in Slack and database
 	for i, msg := range messagesToUpdate {
@@ -1480,6 +2004,52 @@ func (h *GitHubHandler) performPRMessageUpdates(
 	return nil
 }
 
+// validCustomEmoji returns the directive's custom emoji, or empty to fall back to the PR size emoji if the
+// emoji doesn't exist in the workspace, so messages never show a literal :unknown_emoji: alias.
+// If the workspace's emoji can't be listed, the emoji is used as-is.
+func (h *GitHubHandler) validCustomEmoji(ctx context.Context, teamID, customEmoji string) string {
+	if customEmoji == "" {
+		return ""
+	}
+
+	unknown, err := h.slackService.UnknownEmojiAliases(ctx, teamID, []string{customEmoji})
+	if err != nil {
+		log.Warn(ctx, "Failed to validate custom emoji against workspace emoji, using it as-is",
+			"error", err,
+			"custom_emoji", customEmoji,
+			"slack_team_id", teamID,
+		)
+		return customEmoji
+	}
+	if len(unknown) > 0 {
+		log.Warn(ctx, "Custom emoji directive not found in workspace, using PR size emoji instead",
+			"custom_emoji", customEmoji,
+			"slack_team_id", teamID,
+		)
+		return ""
+	}
+
+	return customEmoji
+}
+
+// messageEmoji returns the emoji to show on a PR's message in a workspace instead of the size emoji: the directive's
+// custom emoji, or else the repository's emoji. Empty means the size emoji.
+func (h *GitHubHandler) messageEmoji(ctx context.Context, repoFullName, teamID string, directives *services.PRDirectives) string {
+	if directives.CustomEmoji != "" {
+		return directives.CustomEmoji
+	}
+
+	repo, err := h.firestoreService.GetRepo(ctx, repoFullName, teamID)
+	if err != nil {
+		log.Warn(ctx, "Failed to get repository emoji, using the PR size emoji", "error", err, "slack_team_id", teamID)
+		return ""
+	}
+	if repo == nil {
+		return ""
+	}
+	return repo.Emoji
+}
+
 // updateSingleMessageForPRChanges updates a single message with the PR changes.
 func (h *GitHubHandler) updateSingleMessageForPRChanges(
 	ctx context.Context, payload *github.PullRequestEvent, msg *models.TrackedMessage,
@@ -1487,8 +2057,9 @@ func (h *GitHubHandler) updateSingleMessageForPRChanges(
 ) error {
 	// Resolve CC usernames to Slack user IDs if possible
 	var usersCCSlackIDs []string
+	ccDelegates := make(map[string]string)
 	for _, username := range directives.UsersToCC {
-		slackID := h.resolveUserMention(ctx, username, msg.SlackTeamID)
+		slackID := h.resolveUserMention(ctx, username, msg.SlackTeamID, ccDelegates)
 		usersCCSlackIDs = append(usersCCSlackIDs, slackID)
 	}
 
@@ -1501,6 +2072,25 @@ func (h *GitHubHandler) updateSingleMessageForPRChanges(
 	// Determine user tagging preference
 	userTaggingEnabled := user != nil && user.TaggingEnabled
 
+	// Rich messages keep their layout, whatever the channel uses now
+	var rich *services.RichPRMessage
+	if msg.MessageFormat == models.MessageFormatRich {
+		rich = richPRMessage(payload)
+	}
+
+	channelConfig, err := h.firestoreService.GetChannelConfig(ctx, msg.SlackTeamID, msg.SlackChannel)
+	if err != nil {
+		log.Warn(ctx, "Failed to get channel config for PR metadata, showing the milestone",
+			"error", err,
+			"channel_id", msg.SlackChannel,
+			"slack_team_id", msg.SlackTeamID,
+		)
+	}
+
+	metadata := prMetadata(payload, channelConfig)
+	metadata.ApprovalQuorum = h.approvalQuorumLine(payload, msg)
+	metadata.NoReviewers = msg.NoReviewersWarning && prHasNoReviewers(payload.GetPullRequest(), directives)
+
 	// Update the message in Slack with all changes
 	return h.slackService.UpdatePRMessage(
 		ctx,
@@ -1516,15 +2106,71 @@ func (h *GitHubHandler) updateSingleMessageForPRChanges(
 		authorSlackUserID,
 		directives.UsersToCC, // Use current CC
 		usersCCSlackIDs,
-		directives.CustomEmoji,
+		h.validCustomEmoji(ctx, msg.SlackTeamID, h.messageEmoji(ctx, payload.GetRepo().GetFullName(), msg.SlackTeamID, directives)),
 		userTaggingEnabled,
 		user,
+		channelConfig.GetPRSizeConfig(),
+		msg.Compact,
+		msg.PostedAsUserID,
+		msg.Presentation,
+		rich,
+		metadata,
+		ccDelegates,
+	)
+}
+
+// postChannelConfig resolves the channel a PR message is posted to and returns its config, which sets the
+// message's layout. If the channel can't be resolved or its config can't be read, the config is nil and the
+// message is posted to it as text.
+func (h *GitHubHandler) postChannelConfig(ctx context.Context, teamID, channel string) (string, *models.ChannelConfig) {
+	channelID := channel
+	if !utils.IsChannelID(channel) {
+		resolved, err := h.slackService.ResolveChannelID(ctx, teamID, channel)
+		if err != nil {
+			return channel, nil // Posting reports the unresolvable channel
+		}
+		channelID = resolved
+	}
+
+	channelConfig, err := h.firestoreService.GetChannelConfig(ctx, teamID, channelID)
+	if err != nil {
+		log.Warn(ctx, "Failed to get channel config for posting, using the defaults",
+			"error", err,
+			"channel_id", channelID,
+			"slack_team_id", teamID,
+		)
+		return channelID, nil
+	}
+	return channelID, channelConfig
+}
+
+// channelFilterExclusion returns why a channel's filters keep a PR out of it, or "" if the PR is posted there.
+// Channels whose config can't be loaded post every PR.
+func (h *GitHubHandler) channelFilterExclusion(
+	ctx context.Context, payload *github.PullRequestEvent, teamID, targetChannel string,
+) string {
+	_, channelConfig := h.postChannelConfig(ctx, teamID, targetChannel)
+	if channelConfig == nil {
+		return ""
+	}
+
+	pr := payload.GetPullRequest()
+	return channelConfig.Filters.Exclusion(
+		pr.GetUser().GetLogin(), pr.GetBase().GetRef(), pr.GetTitle(), pr.GetAdditions()+pr.GetDeletions(),
 	)
 }
 
+// richPRMessage returns the PR details shown in the rich message layout.
+func richPRMessage(payload *github.PullRequestEvent) *services.RichPRMessage {
+	return &services.RichPRMessage{AuthorAvatarURL: payload.GetPullRequest().GetUser().GetAvatarURL()}
+}
+
 // handlePRClosed handles pull request closed events.
-// Adds appropriate emoji reactions (merged/closed) to all tracked messages across workspaces.
+// Adds appropriate emoji reactions (merged/closed) to tracked messages across workspaces, respecting per-channel reaction sets.
 func (h *GitHubHandler) handlePRClosed(ctx context.Context, payload *github.PullRequestEvent) error {
+	// Unblock PRs that depend on this one, even if this PR itself was never posted
+	h.refreshDependentMessages(ctx, payload)
+
 	// Get all tracked messages for this PR across all workspaces and channels
 	trackedMessages, err := h.getAllTrackedMessagesForPR(ctx, payload.GetRepo().GetFullName(), payload.GetPullRequest().GetNumber())
 	if err != nil {
@@ -1541,25 +2187,25 @@ func (h *GitHubHandler) handlePRClosed(ctx context.Context, payload *github.Pull
 		return nil
 	}
 
-	// Add reaction to all tracked messages
-	emoji := utils.GetEmojiForPRState(PRActionClosed, payload.GetPullRequest().GetMerged(), h.emojiConfig)
-	if emoji != "" {
-		var messageRefs []services.MessageRef
-		for _, msg := range trackedMessages {
-			messageRefs = append(messageRefs, services.MessageRef{
-				Channel:   msg.SlackChannel,
-				Timestamp: msg.SlackMessageTS,
-			})
-		}
+	// Recorded so the cleanup job can delete the messages once they're past the retention period
+	closedAt := payload.GetPullRequest().GetClosedAt().Time
+	if closedAt.IsZero() {
+		closedAt = time.Now()
+	}
+	if err := h.firestoreService.SetTrackedMessagesClosedAt(ctx, trackedMessages, &closedAt); err != nil {
+		log.Warn(ctx, "Failed to record when PR was closed on tracked messages", "error", err)
+	}
 
-		// Group message refs by team ID for proper team-scoped API calls
-		messagesByTeam := make(map[string][]services.MessageRef)
-		for i, msg := range trackedMessages {
-			messagesByTeam[msg.SlackTeamID] = append(messagesByTeam[msg.SlackTeamID], messageRefs[i])
-		}
+	// Closed PRs go back to the default presentation, before any lifecycle state text is added
+	h.syncPresentation(ctx, payload.GetRepo().GetFullName(), payload.GetPullRequest(), trackedMessages,
+		func(*models.TrackedMessage) {})
 
-		// Add reactions for each team separately
-		for teamID, teamMessageRefs := range messagesByTeam {
+	// Add reaction to tracked messages in channels that allow PR state reactions
+	targets := h.resolveReactionTargets(ctx, trackedMessages)
+	// Add reactions for each team separately, as workspaces can choose their own emoji
+	for teamID, teamMessageRefs := range targets.prState {
+		emoji := utils.GetEmojiForPRState(PRActionClosed, payload.GetPullRequest().GetMerged(), h.slackService.EmojiConfig(ctx, teamID))
+		if emoji != "" {
 			err = h.slackService.AddReactionToMultipleMessages(ctx, teamID, teamMessageRefs, emoji)
 			if err != nil {
 				log.Error(ctx, "Failed to add PR closed reactions for team",
@@ -1574,9 +2220,11 @@ func (h *GitHubHandler) handlePRClosed(ctx context.Context, payload *github.Pull
 		}
 	}
 
+	// Channels without reactions show the closed/merged state in the message text instead
+	h.applyLifecycleStateText(ctx, targets, lifecycleStateLabel(payload.GetPullRequest().GetMerged()))
+
 	log.Info(ctx, "PR closed reactions synchronized across tracked messages",
 		"merged", payload.GetPullRequest().GetMerged(),
-		"emoji", emoji,
 		"message_count", len(trackedMessages),
 	)
 	return nil
@@ -1587,6 +2235,26 @@ func (h *GitHubHandler) handlePRClosed(ctx context.Context, payload *github.Pull
 func (h *GitHubHandler) handlePRReopened(ctx context.Context, payload *github.PullRequestEvent) error {
 	log.Info(ctx, "Processing PR reopened event")
 
+	// Reopened PRs are no longer eligible for retention cleanup
+	trackedMessages, err := h.getAllTrackedMessagesForPR(ctx, payload.GetRepo().GetFullName(), payload.GetPullRequest().GetNumber())
+	if err != nil {
+		log.Warn(ctx, "Failed to get tracked messages to clear closed time", "error", err)
+	} else if err := h.firestoreService.SetTrackedMessagesClosedAt(ctx, trackedMessages, nil); err != nil {
+		log.Warn(ctx, "Failed to clear when PR was closed on tracked messages", "error", err)
+	}
+
+	return h.enqueueReactionSync(ctx, payload)
+}
+
+// handlePRConvertedToDraft handles pull request converted_to_draft events.
+// Triggers a reaction sync job, which re-resolves the message presentation now that the PR is a draft.
+func (h *GitHubHandler) handlePRConvertedToDraft(ctx context.Context, payload *github.PullRequestEvent) error {
+	log.Info(ctx, "Processing PR converted to draft event")
+	return h.enqueueReactionSync(ctx, payload)
+}
+
+// enqueueReactionSync enqueues a job to sync a PR's reactions and message presentation with its current state.
+func (h *GitHubHandler) enqueueReactionSync(ctx context.Context, payload *github.PullRequestEvent) error {
 	// Create ReactionSyncJob to handle reaction syncing asynchronously
 	reactionSyncJobID := uuid.New().String()
 	reactionSyncJob := &models.ReactionSyncJob{
@@ -1594,6 +2262,7 @@ func (h *GitHubHandler) handlePRReopened(ctx context.Context, payload *github.Pu
 		PRNumber:     payload.GetPullRequest().GetNumber(),
 		RepoFullName: payload.GetRepo().GetFullName(),
 		TraceID:      getTraceIDFromContext(ctx),
+		RequestedAt:  time.Now(),
 	}
 
 	// Marshal the ReactionSyncJob as the payload for the Job
@@ -1617,8 +2286,9 @@ func (h *GitHubHandler) handlePRReopened(ctx context.Context, payload *github.Pu
 		return fmt.Errorf("failed to enqueue reaction sync job: %w", err)
 	}
 
-	log.Info(ctx, "Enqueued reaction sync job for PR reopened",
-		"job_id", reactionSyncJobID)
+	log.Info(ctx, "Enqueued reaction sync job",
+		"job_id", reactionSyncJobID,
+		"pr_action", payload.GetAction())
 
 	return nil
 }
@@ -1630,10 +2300,30 @@ func (h *GitHubHandler) handlePRReadyForReview(ctx context.Context, payload *git
 		"title", payload.GetPullRequest().GetTitle(),
 	)
 
+	// Messages posted while the PR was a draft may need a new presentation, e.g. no longer collapsed.
+	// The notification still goes out if the sync can't be enqueued.
+	if err := h.enqueueReactionSync(ctx, payload); err != nil {
+		log.Warn(ctx, "Failed to enqueue reaction sync for PR ready for review", "error", err)
+	}
+
 	// Delegate to shared logic using fan-out approach
 	return h.postPRToAllWorkspaces(ctx, payload)
 }
 
+// withoutRevokedWorkspaces drops the repositories of workspaces whose Slack token was revoked, pausing their
+// notifications until the app is reinstalled there. Workspaces that can't be looked up are kept, so their jobs
+// report the problem.
+func (h *GitHubHandler) withoutRevokedWorkspaces(ctx context.Context, repos []*models.Repo) []*models.Repo {
+	return slices.DeleteFunc(repos, func(repo *models.Repo) bool {
+		workspace, err := h.slackService.GetWorkspace(ctx, repo.WorkspaceID)
+		if err != nil || !workspace.IsRevoked() {
+			return false
+		}
+		log.Info(ctx, "Skipping workspace with revoked Slack token", "slack_team_id", repo.WorkspaceID)
+		return true
+	})
+}
+
 // getAllTrackedMessagesForPR retrieves all tracked messages for a specific PR across all configured workspaces.
 // Queries each workspace where the repository is configured and aggregates results.
 func (h *GitHubHandler) getAllTrackedMessagesForPR(
@@ -1649,8 +2339,11 @@ func (h *GitHubHandler) getAllTrackedMessagesForPR(
 
 	// Get tracked messages from each workspace
 	for _, repo := range repos {
-		messages, err := h.firestoreService.GetTrackedMessages(ctx,
-			repoFullName, prNumber, "", repo.WorkspaceID, "")
+		messages, err := h.firestoreService.GetTrackedMessages(ctx, services.TrackedMessageQuery{
+			RepoFullName: repoFullName,
+			PRNumber:     prNumber,
+			SlackTeamID:  repo.WorkspaceID,
+		})
 		if err != nil {
 			log.Error(ctx, "Failed to get tracked messages for workspace",
 				"error", err,
@@ -1664,6 +2357,30 @@ func (h *GitHubHandler) getAllTrackedMessagesForPR(
 	return allMessages, nil
 }
 
+// existingRepoForAutoRegistration returns the repository that already exists where auto-registration tried to
+// create one: either registered concurrently, or disabled by an admin, in which case nil skips the notification.
+func (h *GitHubHandler) existingRepoForAutoRegistration(ctx context.Context, repo *models.Repo) (*models.Repo, error) {
+	existing, err := h.firestoreService.GetRepo(ctx, repo.RepoFullName, repo.WorkspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get existing repository: %w", err)
+	}
+	if existing == nil {
+		// Deleted since the registration attempt; post with the repo we tried to create
+		return repo, nil
+	}
+	if !existing.Enabled {
+		log.Info(ctx, "Repository is disabled in this workspace, skipping notification",
+			"repo", repo.RepoFullName,
+			"slack_team_id", repo.WorkspaceID)
+		return nil, nil
+	}
+
+	log.Info(ctx, "Repository already registered during concurrent auto-registration attempt",
+		"repo", repo.RepoFullName,
+		"slack_team_id", repo.WorkspaceID)
+	return existing, nil
+}
+
 // attemptAutoRegistration attempts automatic repository registration for verified users.
 // Validates workspace membership and GitHub installation access before creating repository configuration.
 // Returns created repo on success, nil if not possible, or error if registration fails.
@@ -1688,7 +2405,7 @@ func (h *GitHubHandler) attemptAutoRegistration(
 		}
 
 		// Validate that the workspace has a GitHub installation for this repository
-		_, err := h.githubService.ValidateWorkspaceInstallationAccess(ctx, payload.GetRepo().GetFullName(), user.SlackTeamID)
+		installation, err := h.githubService.ValidateWorkspaceInstallationAccess(ctx, payload.GetRepo().GetFullName(), user.SlackTeamID)
 		if err != nil {
 			log.Warn(ctx, "Cannot auto-register repository - workspace lacks GitHub installation",
 				"error", err,
@@ -1708,16 +2425,14 @@ func (h *GitHubHandler) attemptAutoRegistration(
 			WorkspaceID:  user.SlackTeamID,
 			Enabled:      true,
 		}
+		// Start from the settings the admin configured for the installation's repositories
+		repo.InheritDefaults(installation.Defaults)
 
 		err = h.firestoreService.CreateRepoIfNotExists(ctx, repo)
 		if err != nil {
 			// Check if error is due to repository already existing
 			if errors.Is(err, services.ErrRepoAlreadyExists) {
-				log.Info(ctx, "Repository already registered during concurrent auto-registration attempt",
-					"repo", repo.ID,
-					"slack_team_id", repo.WorkspaceID)
-				// Return the repo struct even though we didn't create it, so notification can proceed
-				return repo, nil
+				return h.existingRepoForAutoRegistration(ctx, repo)
 			}
 			log.Error(ctx, "Failed to auto-register repository", "error", err)
 			return nil, fmt.Errorf("failed to auto-register repository: %w", err)
@@ -1727,7 +2442,8 @@ func (h *GitHubHandler) attemptAutoRegistration(
 			"repo", repo.ID,
 			"slack_team_id", repo.WorkspaceID,
 			"registration_type", "automatic",
-			"trigger_event", "pr_opened")
+			"trigger_event", "pr_opened",
+			"inherited_defaults", !installation.Defaults.IsEmpty())
 
 		return repo, nil
 	}
@@ -1828,14 +2544,15 @@ func (h *GitHubHandler) validateInstallationRepositoriesPayload(payload []byte)
 
 // processInstallationEvent processes GitHub App installation webhook events.
 // Handles installation created, deleted, suspended, unsuspended, and new_permissions_accepted actions.
-func (h *GitHubHandler) processInstallationEvent(ctx context.Context, payload []byte) error {
+// Installations on a GitHub Enterprise Server instance, named by githubHost, record the instance's API base URL.
+func (h *GitHubHandler) processInstallationEvent(ctx context.Context, payload []byte, githubHost string) error {
 	var githubPayload github.InstallationEvent
 	if err := json.Unmarshal(payload, &githubPayload); err != nil {
 		log.Error(ctx, "Failed to unmarshal installation payload",
 			"error", err,
 			"payload_size", len(payload),
 		)
-		return fmt.Errorf("failed to unmarshal installation payload: %w", err)
+		return fmt.Errorf("failed to unmarshal installation payload: %w", models.Permanent(err))
 	}
 
 	// Add installation metadata to context for all subsequent log calls
@@ -1850,11 +2567,11 @@ func (h *GitHubHandler) processInstallationEvent(ctx context.Context, payload []
 
 	switch githubPayload.GetAction() {
 	case InstallationActionCreated:
-		return h.handleInstallationCreated(ctx, &githubPayload)
+		return h.handleInstallationCreated(ctx, &githubPayload, githubHost)
 	case InstallationActionDeleted:
 		return h.handleInstallationDeleted(ctx, &githubPayload)
 	case InstallationActionSuspend:
-		return h.handleInstallationSuspend(ctx, &githubPayload)
+		return h.handleInstallationSuspend(ctx, &githubPayload, githubHost)
 	case InstallationActionUnsuspend:
 		return h.handleInstallationUnsuspend(ctx, &githubPayload)
 	case InstallationActionNewPermissions:
@@ -1867,7 +2584,7 @@ func (h *GitHubHandler) processInstallationEvent(ctx context.Context, payload []
 
 // handleInstallationCreated handles GitHub App installation created events.
 // Creates orphaned installation records for direct GitHub installs without workspace association.
-func (h *GitHubHandler) handleInstallationCreated(ctx context.Context, payload *github.InstallationEvent) error {
+func (h *GitHubHandler) handleInstallationCreated(ctx context.Context, payload *github.InstallationEvent, githubHost string) error {
 	log.Info(ctx, "Processing installation created event")
 
 	// Check if installation already exists and has workspace association
@@ -1906,6 +2623,7 @@ func (h *GitHubHandler) handleInstallationCreated(ctx context.Context, payload *
 		Repositories:        repositories,
 		InstalledAt:         time.Now(),
 		UpdatedAt:           time.Now(),
+		APIBaseURL:          models.GitHubEnterpriseAPIBaseURL(githubHost),
 	}
 
 	// Delete existing installation record if it exists (handles reinstalls from orphaned installations)
@@ -1984,7 +2702,7 @@ func (h *GitHubHandler) handleInstallationDeleted(ctx context.Context, payload *
 
 // handleInstallationSuspend handles GitHub App installation suspended events.
 // Updates installation record with suspension timestamp when access is suspended.
-func (h *GitHubHandler) handleInstallationSuspend(ctx context.Context, payload *github.InstallationEvent) error {
+func (h *GitHubHandler) handleInstallationSuspend(ctx context.Context, payload *github.InstallationEvent, githubHost string) error {
 	log.Info(ctx, "Processing installation suspend event")
 
 	// Get existing installation to update it
@@ -2002,6 +2720,7 @@ func (h *GitHubHandler) handleInstallationSuspend(ctx context.Context, payload *
 				RepositorySelection: payload.GetInstallation().GetRepositorySelection(),
 				InstalledAt:         time.Now(), // We don't have the original install time
 				UpdatedAt:           time.Now(),
+				APIBaseURL:          models.GitHubEnterpriseAPIBaseURL(githubHost),
 			}
 		} else {
 			log.Error(ctx, "Failed to get installation for suspend", "error", err)
@@ -2087,7 +2806,7 @@ func (h *GitHubHandler) processInstallationRepositoriesEvent(ctx context.Context
 			"error", err,
 			"payload_size", len(payload),
 		)
-		return fmt.Errorf("failed to unmarshal installation_repositories payload: %w", err)
+		return fmt.Errorf("failed to unmarshal installation_repositories payload: %w", models.Permanent(err))
 	}
 
 	// Add installation metadata to context for all subsequent log calls
@@ -2234,12 +2953,37 @@ func (h *GitHubHandler) processGitHubAppAuthEvent(ctx context.Context, payload [
 }
 
 // resolveUserMention attempts to resolve a GitHub username to a Slack user ID.
-// Returns the Slack user ID if the user is found, verified, and in the target workspace.
-// Returns empty string if no mapping is found, allowing fallback to plain text mention.
-func (h *GitHubHandler) resolveUserMention(ctx context.Context, githubUsername, workspaceID string) string {
-	if githubUsername == "" || workspaceID == "" {
+// Returns an empty string (plain text mention) for unknown users, users in digest mode, and users who are
+// out of office, who are recorded in ccDelegates.
+func (h *GitHubHandler) resolveUserMention(ctx context.Context, githubUsername, workspaceID string, ccDelegates map[string]string) string {
+	user := h.lookupUserForMention(ctx, githubUsername, workspaceID)
+	if user == nil || recordOutOfOffice(user, githubUsername, ccDelegates) || user.DigestMode {
 		return ""
 	}
+	return user.SlackUserID
+}
+
+// recordOutOfOffice records a CC'd user's delegate in ccDelegates if they're out of office, keyed by their
+// lowercase GitHub username, and reports whether they are. Users out of office without a delegate are recorded
+// with an empty delegate, so their CC is still noted as out of office.
+func recordOutOfOffice(user *models.User, githubUsername string, ccDelegates map[string]string) bool {
+	if !user.OutOfOffice {
+		return false
+	}
+	delegate := user.OOODelegate
+	if delegate == user.SlackUserID {
+		delegate = ""
+	}
+	ccDelegates[strings.ToLower(githubUsername)] = delegate
+	return true
+}
+
+// lookupUserForMention looks up the verified user for a GitHub username in a workspace.
+// Returns nil if the user isn't registered or verified.
+func (h *GitHubHandler) lookupUserForMention(ctx context.Context, githubUsername, workspaceID string) *models.User {
+	if githubUsername == "" || workspaceID == "" {
+		return nil
+	}
 
 	// Look up user by GitHub username and workspace ID
 	user, err := h.firestoreService.GetUserByGitHubUsernameAndWorkspace(ctx, githubUsername, workspaceID)
@@ -2249,7 +2993,7 @@ func (h *GitHubHandler) resolveUserMention(ctx context.Context, githubUsername,
 			"workspace_id", workspaceID,
 			"error", err,
 		)
-		return ""
+		return nil
 	}
 
 	// Ensure user is not nil and verified
@@ -2258,7 +3002,7 @@ func (h *GitHubHandler) resolveUserMention(ctx context.Context, githubUsername,
 			"github_username", githubUsername,
 			"workspace_id", workspaceID,
 		)
-		return ""
+		return nil
 	}
 
 	if !user.Verified {
@@ -2267,7 +3011,7 @@ func (h *GitHubHandler) resolveUserMention(ctx context.Context, githubUsername,
 			"workspace_id", workspaceID,
 			"verified", user.Verified,
 		)
-		return ""
+		return nil
 	}
 
 	log.Debug(ctx, "Resolved GitHub username to Slack user ID for mention",
@@ -2275,5 +3019,5 @@ func (h *GitHubHandler) resolveUserMention(ctx context.Context, githubUsername,
 		"slack_user_id", user.SlackUserID,
 		"workspace_id", workspaceID,
 	)
-	return user.SlackUserID
+	return user
 }
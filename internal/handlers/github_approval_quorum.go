@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/google/go-github/v74/github"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+	"github-slack-notifier/internal/services"
+	"github-slack-notifier/internal/utils"
+)
+
+// requiredApprovals returns how many approvals an open PR needs: the most required by the rulesets on its base
+// branch, or else one from each reviewer who has approved, requested changes, or is still requested.
+// Ruleset lookup failures are logged and fall back to the reviewers.
+func (h *GitHubHandler) requiredApprovals(
+	ctx context.Context, repoFullName string, pr *github.PullRequest, reviewSummary *services.PRReviewSummary,
+) int {
+	required, err := h.githubService.GetRequiredApprovals(ctx, repoFullName, pr.GetBase().GetRef())
+	if err != nil {
+		log.Warn(ctx, "Failed to get required approvals from rulesets, counting reviewers instead", "error", err)
+	}
+	if required > 0 {
+		return required
+	}
+	return reviewSummary.Reviewers + len(pr.RequestedReviewers) + len(pr.RequestedTeams)
+}
+
+// approvalQuorumChanges returns the bot messages whose approval count line would change to show the given
+// approvals and required approvals.
+func approvalQuorumChanges(trackedMessages []*models.TrackedMessage, approvals, required int) []*models.TrackedMessage {
+	var changed []*models.TrackedMessage
+	for _, msg := range trackedMessages {
+		if msg.MessageSource != models.MessageSourceBot || msg.DeletedByUser {
+			continue
+		}
+		if msg.Approvals != approvals || msg.RequiredApprovals != required {
+			changed = append(changed, msg)
+		}
+	}
+	return changed
+}
+
+// syncApprovalQuorum edits the approval count line on messages whose approvals or required approvals changed.
+// Messages shown on one line are left alone. Messages re-rendered since already show the line, so Slack
+// isn't asked to update them again. Failures are logged rather than returned, since the line is cosmetic.
+func (h *GitHubHandler) syncApprovalQuorum(ctx context.Context, changed []*models.TrackedMessage, approvals, required int) {
+	messagesByTeam := make(map[string][]services.MessageRef)
+	for _, msg := range changed {
+		if msg.Compact || msg.Presentation == models.PresentationCollapsed || msg.Presentation == models.PresentationArchived {
+			continue
+		}
+		messagesByTeam[msg.SlackTeamID] = append(messagesByTeam[msg.SlackTeamID], trackedMessageRef(msg))
+	}
+
+	line := utils.FormatApprovalQuorum(approvals, required)
+	for teamID, messageRefs := range messagesByTeam {
+		if err := h.slackService.SetApprovalQuorumText(ctx, teamID, messageRefs, line); err != nil {
+			log.Warn(ctx, "Failed to update approval count line",
+				"error", err,
+				"team_id", teamID,
+				"approvals", approvals,
+				"required_approvals", required,
+			)
+		}
+	}
+}
+
+// approvalQuorumLine returns the approval count line for a tracked message being rebuilt, or "" if messages don't
+// show it. Closed PRs don't, as their reviews are no longer synced.
+func (h *GitHubHandler) approvalQuorumLine(payload *github.PullRequestEvent, msg *models.TrackedMessage) string {
+	if !h.slackService.ApprovalQuorumEnabled() || payload.GetPullRequest().GetState() != "open" {
+		return ""
+	}
+	return utils.FormatApprovalQuorum(msg.Approvals, msg.RequiredApprovals)
+}
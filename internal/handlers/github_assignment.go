@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/go-github/v74/github"
+	"github.com/google/uuid"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+	"github-slack-notifier/internal/utils"
+)
+
+// handlePRAssignmentChanged handles pull request assigned and unassigned events.
+// Enqueues a job to notify the assignee, so Slack calls happen outside the webhook job.
+func (h *GitHubHandler) handlePRAssignmentChanged(ctx context.Context, payload *github.PullRequestEvent) error {
+	assignee := payload.GetAssignee()
+	if assignee == nil {
+		log.Debug(ctx, "Skipping assignment event without an assignee")
+		return nil
+	}
+
+	pr := payload.GetPullRequest()
+	jobID := uuid.New().String()
+	assignmentJob := &models.AssignmentJob{
+		ID:               jobID,
+		PRNumber:         pr.GetNumber(),
+		RepoFullName:     payload.GetRepo().GetFullName(),
+		PRTitle:          pr.GetTitle(),
+		PRURL:            pr.GetHTMLURL(),
+		PRAction:         payload.GetAction(),
+		AssigneeGitHubID: assignee.GetID(),
+		AssigneeLogin:    assignee.GetLogin(),
+		AssignedBy:       payload.GetSender().GetLogin(),
+		TraceID:          traceIDForNewJob(ctx),
+	}
+
+	jobPayload, err := json.Marshal(assignmentJob)
+	if err != nil {
+		log.Error(ctx, "Failed to marshal assignment job", "error", err)
+		return fmt.Errorf("failed to marshal assignment job: %w", err)
+	}
+
+	job := &models.Job{
+		ID:      jobID,
+		Type:    models.JobTypeAssignment,
+		TraceID: assignmentJob.TraceID,
+		Payload: jobPayload,
+	}
+	if err := h.cloudTasksService.EnqueueJob(ctx, job); err != nil {
+		log.Error(ctx, "Failed to enqueue assignment job", "error", err)
+		return fmt.Errorf("failed to enqueue assignment job: %w", err)
+	}
+
+	log.Info(ctx, "Enqueued assignment job",
+		"job_id", jobID,
+		"assignee", assignee.GetLogin(),
+	)
+	return nil
+}
+
+// ProcessAssignmentJob notes an assignment change in the thread of the PR's tracked messages, and DMs the
+// assignee if they've linked their GitHub account and opted in to assignment DMs.
+func (h *GitHubHandler) ProcessAssignmentJob(ctx context.Context, job *models.Job) error {
+	var assignmentJob models.AssignmentJob
+	if err := json.Unmarshal(job.Payload, &assignmentJob); err != nil {
+		return fmt.Errorf("failed to unmarshal assignment job: %w", models.Permanent(err))
+	}
+	if err := assignmentJob.Validate(); err != nil {
+		return fmt.Errorf("invalid assignment job: %w", models.Permanent(err))
+	}
+
+	ctx = log.WithFields(ctx, log.LogFields{
+		"repo":      assignmentJob.RepoFullName,
+		"pr_number": assignmentJob.PRNumber,
+		"pr_action": assignmentJob.PRAction,
+		"assignee":  assignmentJob.AssigneeLogin,
+	})
+
+	user, err := h.firestoreService.GetUserByGitHubUserID(ctx, assignmentJob.AssigneeGitHubID)
+	if err != nil {
+		log.Error(ctx, "Failed to look up assignee", "error", err)
+		return fmt.Errorf("failed to look up assignee: %w", err)
+	}
+	if !wantsAssignmentDM(user) || user.RepoMuted(assignmentJob.RepoFullName, time.Now()) {
+		user = nil // Unlinked, not opted in or muted the repository: no DM, and thread notes don't mention them
+	}
+
+	// Assigning yourself needs no DM
+	if user != nil && assignmentJob.AssignedBy != assignmentJob.AssigneeLogin {
+		h.sendAssignmentDM(ctx, user, &assignmentJob)
+	}
+	return h.postAssignmentThreadNotes(ctx, user, &assignmentJob)
+}
+
+// wantsAssignmentDM reports whether a user has a verified Slack account and opted in to assignment DMs.
+func wantsAssignmentDM(user *models.User) bool {
+	return user != nil && user.Verified && user.SlackUserID != "" && user.AssignmentDMs
+}
+
+// sendAssignmentDM DMs the assignee about the assignment, or buffers it for their digest.
+// A failed DM is logged, and doesn't stop the assignment being noted in the PR's threads.
+func (h *GitHubHandler) sendAssignmentDM(ctx context.Context, user *models.User, job *models.AssignmentJob) {
+	prLink := fmt.Sprintf("<%s|%s#%d %s>", job.PRURL, job.RepoFullName, job.PRNumber, utils.EscapeSlackText(job.PRTitle))
+	assignedBy := utils.EscapeSlackText(job.AssignedBy)
+
+	if job.PRAction == PRActionUnassigned {
+		// Digest users would only see the removal after the fact, so it's only sent as a DM
+		if user.DigestMode {
+			return
+		}
+		h.postAssignmentDM(ctx, user, fmt.Sprintf(":heavy_minus_sign: *%s* unassigned you from %s", assignedBy, prLink))
+		return
+	}
+
+	if user.DigestMode {
+		h.bufferDigestEntry(ctx, user, &models.DigestEntry{
+			Event:        models.DigestEventAssigned,
+			RepoFullName: job.RepoFullName,
+			PRNumber:     job.PRNumber,
+			PRTitle:      job.PRTitle,
+			PRURL:        job.PRURL,
+			Actor:        job.AssignedBy,
+		})
+		return
+	}
+	h.postAssignmentDM(ctx, user, fmt.Sprintf(":bust_in_silhouette: *%s* assigned you to %s", assignedBy, prLink))
+}
+
+// postAssignmentDM sends an assignment direct message, logging any failure.
+// It's deferred if the assignee is in quiet hours.
+func (h *GitHubHandler) postAssignmentDM(ctx context.Context, user *models.User, text string) {
+	if h.deferForQuietHours(ctx, user, "", "", text) {
+		return
+	}
+	if _, err := h.slackService.PostMessage(ctx, user.SlackTeamID, user.SlackUserID, text); err != nil {
+		log.Error(ctx, "Failed to send assignment DM",
+			"error", err,
+			"slack_user_id", user.SlackUserID,
+		)
+		return
+	}
+
+	log.Info(ctx, "Sent assignment DM", "slack_user_id", user.SlackUserID)
+}
+
+// postAssignmentThreadNotes notes the assignment change in the thread of each bot-posted PR message.
+// The assignee is mentioned in their own workspace if they opted in to assignment DMs (user set),
+// and named by their GitHub username everywhere else. Notes mentioning them are deferred during their quiet hours.
+func (h *GitHubHandler) postAssignmentThreadNotes(ctx context.Context, user *models.User, job *models.AssignmentJob) error {
+	trackedMessages, err := h.getAllTrackedMessagesForPR(ctx, job.RepoFullName, job.PRNumber)
+	if err != nil {
+		log.Error(ctx, "Failed to get tracked messages for assignment note", "error", err)
+		return err
+	}
+
+	for _, msg := range trackedMessages {
+		if msg.MessageSource != models.MessageSourceBot || msg.DeletedByUser {
+			continue
+		}
+
+		assignee := "@" + utils.EscapeSlackText(job.AssigneeLogin)
+		mentioned := user != nil && user.SlackTeamID == msg.SlackTeamID
+		if mentioned {
+			assignee = fmt.Sprintf("<@%s>", user.SlackUserID)
+		}
+		text := assignmentNoteText(job, assignee)
+		if mentioned && h.deferForQuietHours(ctx, user, msg.SlackChannel, msg.SlackMessageTS, text) {
+			continue
+		}
+
+		if _, err := h.slackService.PostThreadReply(ctx, msg.SlackTeamID, msg.SlackChannel, msg.SlackMessageTS, text); err != nil {
+			log.Warn(ctx, "Failed to post assignment thread note",
+				"error", err,
+				"channel", msg.SlackChannel,
+				"message_ts", msg.SlackMessageTS,
+			)
+		}
+	}
+	return nil
+}
+
+// assignmentNoteText returns the thread note for an assignment change, naming the assignee as given.
+func assignmentNoteText(job *models.AssignmentJob, assignee string) string {
+	assignedBy := utils.EscapeSlackText(job.AssignedBy)
+	if job.PRAction == PRActionUnassigned {
+		return fmt.Sprintf(":heavy_minus_sign: %s unassigned by %s", assignee, assignedBy)
+	}
+	return fmt.Sprintf(":bust_in_silhouette: Assigned to %s by %s", assignee, assignedBy)
+}
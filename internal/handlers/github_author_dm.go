@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v74/github"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+)
+
+// notifyAuthorOfChangesRequested DMs the PR author when a reviewer requests changes, if they opted in.
+func (h *GitHubHandler) notifyAuthorOfChangesRequested(ctx context.Context, payload *github.PullRequestReviewEvent) {
+	review := payload.GetReview()
+	if !strings.EqualFold(review.GetState(), string(models.ReviewStateChangesRequested)) {
+		return
+	}
+
+	pr := payload.GetPullRequest()
+	author := pr.GetUser()
+	if review.GetUser().GetID() == author.GetID() {
+		return
+	}
+
+	text := fmt.Sprintf(":warning: *%s* requested changes on your PR <%s|%s#%d %s>",
+		review.GetUser().GetLogin(), review.GetHTMLURL(), payload.GetRepo().GetFullName(), pr.GetNumber(), pr.GetTitle())
+	h.sendAuthorDM(ctx, author.GetID(), &models.DigestEntry{
+		Event:        models.AuthorDMEventChangesRequested,
+		RepoFullName: payload.GetRepo().GetFullName(),
+		PRNumber:     pr.GetNumber(),
+		PRTitle:      pr.GetTitle(),
+		PRURL:        review.GetHTMLURL(),
+		Actor:        review.GetUser().GetLogin(),
+	}, text)
+}
+
+// processCheckSuiteEvent processes check_suite webhook events.
+// Enqueues a CI reaction sync for the suite's commit, and DMs the author of each PR in a failed check suite,
+// if they opted in to CI failure DMs.
+func (h *GitHubHandler) processCheckSuiteEvent(ctx context.Context, payload []byte, traceID string) error {
+	var githubPayload github.CheckSuiteEvent
+	if err := json.Unmarshal(payload, &githubPayload); err != nil {
+		log.Error(ctx, "Failed to unmarshal check suite payload",
+			"error", err,
+			"payload_size", len(payload),
+		)
+		return fmt.Errorf("failed to unmarshal check suite payload: %w", models.Permanent(err))
+	}
+
+	checkSuite := githubPayload.GetCheckSuite()
+	// The suite's PR list is empty for PRs from forks, so the sync job looks the PRs up itself
+	if err := h.enqueueCIStatusSync(ctx, githubPayload.GetRepo().GetFullName(), checkSuite.GetHeadSHA(), traceID); err != nil {
+		return err
+	}
+
+	if githubPayload.GetAction() != CheckSuiteActionCompleted || !isFailedCheckConclusion(checkSuite.GetConclusion()) {
+		return nil
+	}
+
+	repoFullName := githubPayload.GetRepo().GetFullName()
+	ctx = log.WithFields(ctx, log.LogFields{
+		"repo":       repoFullName,
+		"check_app":  checkSuite.GetApp().GetName(),
+		"conclusion": checkSuite.GetConclusion(),
+	})
+
+	// Check suite payloads only include minimal PR details, so fetch each PR for its author and title
+	for _, suitePR := range checkSuite.PullRequests {
+		pr, err := h.githubService.GetPullRequest(ctx, repoFullName, suitePR.GetNumber())
+		if err != nil {
+			log.Warn(ctx, "Failed to fetch PR for CI failure DM",
+				"error", err,
+				"pr_number", suitePR.GetNumber(),
+			)
+			continue
+		}
+		if pr.GetState() != "open" {
+			continue
+		}
+
+		text := fmt.Sprintf(":x: CI failed (%s) on your PR <%s/checks|%s#%d %s>",
+			checkSuite.GetApp().GetName(), pr.GetHTMLURL(), repoFullName, pr.GetNumber(), pr.GetTitle())
+		h.sendAuthorDM(ctx, pr.GetUser().GetID(), &models.DigestEntry{
+			Event:        models.AuthorDMEventCIFailed,
+			RepoFullName: repoFullName,
+			PRNumber:     pr.GetNumber(),
+			PRTitle:      pr.GetTitle(),
+			PRURL:        pr.GetHTMLURL() + "/checks",
+			Actor:        checkSuite.GetApp().GetName(),
+		}, text)
+	}
+
+	return nil
+}
+
+// isFailedCheckConclusion reports whether a check suite conclusion should be reported as a CI failure.
+func isFailedCheckConclusion(conclusion string) bool {
+	return conclusion == "failure" || conclusion == "timed_out"
+}
+
+// sendAuthorDM sends a direct message to a PR author if they have a verified account and opted in to the event.
+// Authors in digest mode get the event in their next digest instead, and DMs during quiet hours are deferred.
+// Failures are logged rather than returned, since DMs are supplementary to channel notifications.
+func (h *GitHubHandler) sendAuthorDM(ctx context.Context, authorGitHubID int64, entry *models.DigestEntry, text string) {
+	event := entry.Event
+	user, err := h.firestoreService.GetUserByGitHubUserID(ctx, authorGitHubID)
+	if err != nil {
+		log.Error(ctx, "Failed to look up PR author for DM",
+			"error", err,
+			"github_user_id", authorGitHubID,
+			"dm_event", event,
+		)
+		return
+	}
+	if user == nil || !user.Verified || user.SlackUserID == "" || !user.WantsAuthorDM(event) {
+		return
+	}
+	if user.RepoMuted(entry.RepoFullName, time.Now()) {
+		log.Debug(ctx, "Skipping PR author DM for muted repository", "slack_user_id", user.SlackUserID, "dm_event", event)
+		return
+	}
+
+	if user.DigestMode {
+		h.bufferDigestEntry(ctx, user, entry)
+		return
+	}
+	if h.deferForQuietHours(ctx, user, "", "", text) {
+		return
+	}
+
+	if _, err := h.slackService.PostMessage(ctx, user.SlackTeamID, user.SlackUserID, text); err != nil {
+		log.Error(ctx, "Failed to send PR author DM",
+			"error", err,
+			"slack_user_id", user.SlackUserID,
+			"dm_event", event,
+		)
+		return
+	}
+
+	log.Info(ctx, "Sent PR author DM",
+		"slack_user_id", user.SlackUserID,
+		"dm_event", event,
+	)
+}
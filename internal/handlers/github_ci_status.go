@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/go-github/v74/github"
+	"github.com/google/uuid"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+)
+
+// validateStatusPayload validates commit status webhook payload structure.
+// Status events have no action field, so the commit SHA and repository are checked instead.
+func (h *GitHubHandler) validateStatusPayload(payload []byte) error {
+	var statusPayload map[string]interface{}
+	if err := json.Unmarshal(payload, &statusPayload); err != nil {
+		return fmt.Errorf("invalid JSON payload: %w", err)
+	}
+
+	if _, exists := statusPayload["sha"]; !exists {
+		return ErrMissingSHA
+	}
+
+	if _, exists := statusPayload["repository"]; !exists {
+		return ErrMissingRepository
+	}
+
+	return nil
+}
+
+// processStatusEvent processes commit status webhook events by enqueuing a CI reaction sync for the commit.
+// Status events don't say which PRs the commit belongs to, so the sync job looks them up.
+func (h *GitHubHandler) processStatusEvent(ctx context.Context, payload []byte, traceID string) error {
+	var githubPayload github.StatusEvent
+	if err := json.Unmarshal(payload, &githubPayload); err != nil {
+		log.Error(ctx, "Failed to unmarshal status payload",
+			"error", err,
+			"payload_size", len(payload),
+		)
+		return fmt.Errorf("failed to unmarshal status payload: %w", models.Permanent(err))
+	}
+
+	ctx = log.WithFields(ctx, log.LogFields{
+		"status_context": githubPayload.GetContext(),
+		"status_state":   githubPayload.GetState(),
+	})
+
+	return h.enqueueCIStatusSync(ctx, githubPayload.GetRepo().GetFullName(), githubPayload.GetSHA(), traceID)
+}
+
+// enqueueCIStatusSync enqueues a job to sync CI state reactions for the open PRs whose head is the commit.
+func (h *GitHubHandler) enqueueCIStatusSync(ctx context.Context, repoFullName, headSHA, traceID string) error {
+	jobID := uuid.New().String()
+	ciStatusSyncJob := &models.CIStatusSyncJob{
+		ID:           jobID,
+		RepoFullName: repoFullName,
+		HeadSHA:      headSHA,
+		TraceID:      traceID,
+	}
+
+	jobPayload, err := json.Marshal(ciStatusSyncJob)
+	if err != nil {
+		log.Error(ctx, "Failed to marshal CI status sync job", "error", err)
+		return fmt.Errorf("failed to marshal CI status sync job: %w", err)
+	}
+
+	job := &models.Job{
+		ID:      jobID,
+		Type:    models.JobTypeCIStatusSync,
+		TraceID: traceID,
+		Payload: jobPayload,
+	}
+	if err := h.cloudTasksService.EnqueueJob(ctx, job); err != nil {
+		log.Error(ctx, "Failed to enqueue CI status sync job", "error", err)
+		return fmt.Errorf("failed to enqueue CI status sync job: %w", err)
+	}
+
+	log.Info(ctx, "Enqueued CI status sync job",
+		"job_id", jobID,
+		"head_sha", headSHA,
+	)
+	return nil
+}
+
+// ProcessCIStatusSyncJob processes a CI status sync job from the job system.
+// Fetches the commit's combined CI state from GitHub and syncs the CI reaction on the tracked messages
+// of each open PR whose head is the commit. CI reactions follow the channel's review reaction setting.
+// The CI state is recorded on the messages, which may change their presentation.
+func (h *GitHubHandler) ProcessCIStatusSyncJob(ctx context.Context, job *models.Job) error {
+	var ciStatusSyncJob models.CIStatusSyncJob
+	if err := json.Unmarshal(job.Payload, &ciStatusSyncJob); err != nil {
+		return fmt.Errorf("failed to unmarshal CI status sync job: %w", models.Permanent(err))
+	}
+	if err := ciStatusSyncJob.Validate(); err != nil {
+		return fmt.Errorf("invalid CI status sync job: %w", models.Permanent(err))
+	}
+
+	ctx = log.WithFields(ctx, log.LogFields{
+		"repo":     ciStatusSyncJob.RepoFullName,
+		"head_sha": ciStatusSyncJob.HeadSHA,
+	})
+
+	prs, err := h.githubService.ListOpenPullRequestsForCommit(ctx, ciStatusSyncJob.RepoFullName, ciStatusSyncJob.HeadSHA)
+	if err != nil {
+		log.Error(ctx, "Failed to list PRs for commit", "error", err)
+		return fmt.Errorf("failed to list PRs for commit: %w", err)
+	}
+	if len(prs) == 0 {
+		log.Debug(ctx, "No open PRs with this head commit, skipping CI status sync")
+		return nil
+	}
+
+	state, err := h.githubService.GetCIState(ctx, ciStatusSyncJob.RepoFullName, ciStatusSyncJob.HeadSHA)
+	if err != nil {
+		log.Error(ctx, "Failed to get CI state", "error", err)
+		return fmt.Errorf("failed to get CI state: %w", err)
+	}
+
+	for _, pr := range prs {
+		trackedMessages, err := h.getAllTrackedMessagesForPR(ctx, ciStatusSyncJob.RepoFullName, pr.GetNumber())
+		if err != nil {
+			log.Error(ctx, "Failed to get tracked messages for CI status sync", "error", err, "pr_number", pr.GetNumber())
+			return err
+		}
+
+		targets := h.resolveReactionTargets(ctx, trackedMessages)
+		for teamID, teamMessageRefs := range targets.review {
+			if err := h.slackService.SyncCIReactions(ctx, teamID, teamMessageRefs, state); err != nil {
+				log.Error(ctx, "Failed to sync CI reactions",
+					"error", err,
+					"team_id", teamID,
+					"pr_number", pr.GetNumber(),
+					"ci_state", state,
+				)
+			}
+		}
+
+		h.syncPresentation(ctx, ciStatusSyncJob.RepoFullName, pr, trackedMessages, func(msg *models.TrackedMessage) {
+			msg.CIState = state
+		})
+	}
+
+	return nil
+}
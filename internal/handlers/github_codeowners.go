@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/google/go-github/v74/github"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+	"github-slack-notifier/internal/services"
+	"github-slack-notifier/internal/utils"
+)
+
+// applyCodeOwnersCC adds the code owners of the PR's changed files to the CC list, for repositories
+// that have CODEOWNERS CC enabled. The PR author is never CC'd on their own PR.
+// Failures are logged and the directives returned unchanged, since CC'ing owners is supplementary.
+func (h *GitHubHandler) applyCodeOwnersCC(
+	ctx context.Context, payload *github.PullRequestEvent, repo *models.Repo, directives *services.PRDirectives,
+	trace *routingTrace,
+) *services.PRDirectives {
+	if !repo.CodeOwnersCC {
+		return directives
+	}
+
+	repoFullName := payload.GetRepo().GetFullName()
+	content, err := h.githubService.GetCodeOwners(ctx, repoFullName)
+	if err != nil {
+		log.Warn(ctx, "Failed to get CODEOWNERS, not CC'ing code owners", "error", err)
+		trace.add("CODEOWNERS couldn't be loaded (%v), no code owners CC'd", err)
+		return directives
+	}
+	rules := utils.ParseCodeOwners(content)
+	if len(rules) == 0 {
+		trace.add("CODEOWNERS CC is enabled but the repository has no CODEOWNERS rules")
+		return directives
+	}
+
+	pr := payload.GetPullRequest()
+	files, err := h.githubService.ListPullRequestFiles(ctx, repoFullName, pr.GetNumber())
+	if err != nil {
+		log.Warn(ctx, "Failed to list PR files, not CC'ing code owners", "error", err)
+		trace.add("PR files couldn't be listed (%v), no code owners CC'd", err)
+		return directives
+	}
+
+	// Copy so the owners don't leak into other workspaces sharing the directives
+	modified := *directives
+	modified.UsersToCC = slices.Clone(directives.UsersToCC)
+	var added []string
+	for _, owner := range utils.CodeOwnersFor(rules, files) {
+		if strings.EqualFold(owner, pr.GetUser().GetLogin()) || slices.Contains(modified.UsersToCC, owner) {
+			continue
+		}
+		modified.UsersToCC = append(modified.UsersToCC, owner)
+		added = append(added, owner)
+	}
+
+	if len(added) == 0 {
+		trace.add("No code owners to CC")
+		return directives
+	}
+
+	log.Info(ctx, "CC'ing code owners", "code_owners", added, "slack_team_id", repo.WorkspaceID)
+	trace.add("CC'ing code owners %v", added)
+	return &modified
+}
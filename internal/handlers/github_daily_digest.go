@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+)
+
+// dailyDigestLookback limits a user's open PRs to those posted recently, bounding the GitHub lookups per digest.
+const dailyDigestLookback = 30 * 24 * time.Hour
+
+// ProcessDailyDigestJob DMs each user whose digest time has been reached a summary of their open PRs
+// and the reviews waiting on them. Triggered hourly by Cloud Scheduler; each user's digest is sent once
+// per local day, and a digest that fails is retried on the next run. Empty digests aren't sent.
+func (h *GitHubHandler) ProcessDailyDigestJob(ctx context.Context, _ *models.Job) error {
+	now := time.Now()
+	users, err := h.firestoreService.ListDailyDigestUsers(ctx)
+	if err != nil {
+		log.Error(ctx, "Failed to list daily digest users", "error", err)
+		return err
+	}
+
+	sent := 0
+	for _, user := range users {
+		if !user.Verified || user.SlackUserID == "" || !user.DailyDigest.IsDue(now) {
+			continue
+		}
+		userCtx := log.WithFields(ctx, log.LogFields{
+			"slack_team_id": user.SlackTeamID,
+			"slack_user_id": user.SlackUserID,
+		})
+
+		digest, err := h.buildDailyDigest(userCtx, user, now)
+		if err != nil {
+			log.Error(userCtx, "Failed to build daily digest", "error", err)
+			continue
+		}
+
+		if !digest.IsEmpty() {
+			if err := h.slackService.PostDailyDigest(userCtx, user.SlackTeamID, user.SlackUserID, digest, now); err != nil {
+				continue
+			}
+			sent++
+		}
+
+		if err := h.firestoreService.MarkDailyDigestSent(userCtx, user.ID, user.DailyDigest.LocalDate(now)); err != nil {
+			// The digest may be sent again on the next run
+			log.Error(userCtx, "Failed to mark daily digest sent", "error", err)
+		}
+	}
+
+	log.Info(ctx, "Daily digest job completed",
+		"user_count", len(users),
+		"sent_count", sent,
+	)
+	return nil
+}
+
+// buildDailyDigest collects a user's open PRs from their recently posted PR messages, and their pending
+// review requests. PR states are checked on GitHub; PRs that can't be fetched are left out, and pending
+// reviews on merged or closed PRs are removed.
+func (h *GitHubHandler) buildDailyDigest(ctx context.Context, user *models.User, now time.Time) (*models.DailyDigest, error) {
+	since := now.Add(-dailyDigestLookback)
+	messages, err := h.firestoreService.GetRecentTrackedMessagesByAuthor(ctx, user.SlackTeamID, user.GitHubUserID, since)
+	if err != nil {
+		return nil, err
+	}
+	reviews, err := h.firestoreService.ListPendingReviews(ctx, user.GitHubUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make(map[string]string)
+	prState := func(repoFullName string, prNumber int) string {
+		key := repoFullName + "#" + strconv.Itoa(prNumber)
+		if state, ok := states[key]; ok {
+			return state
+		}
+		state, err := h.githubService.GetPullRequestState(ctx, repoFullName, prNumber)
+		if err != nil {
+			log.Warn(ctx, "Failed to get PR state for daily digest", "error", err, "pr", key)
+		}
+		states[key] = state
+		return state
+	}
+
+	digest := &models.DailyDigest{}
+	seen := make(map[string]bool)
+	for _, msg := range messages {
+		key := msg.RepoFullName + "#" + strconv.Itoa(msg.PRNumber)
+		if seen[key] || prState(msg.RepoFullName, msg.PRNumber) != models.PRDependencyStateOpen {
+			continue
+		}
+		seen[key] = true
+		digest.OpenPRs = append(digest.OpenPRs, models.DailyDigestPR{
+			RepoFullName: msg.RepoFullName,
+			PRNumber:     msg.PRNumber,
+			Title:        msg.PRTitle,
+			URL:          fmt.Sprintf("https://github.com/%s/pull/%d", msg.RepoFullName, msg.PRNumber),
+			Since:        msg.CreatedAt,
+		})
+	}
+
+	for _, review := range reviews {
+		switch prState(review.RepoFullName, review.PRNumber) {
+		case models.PRDependencyStateOpen:
+			digest.PendingReviews = append(digest.PendingReviews, models.DailyDigestPR{
+				RepoFullName: review.RepoFullName,
+				PRNumber:     review.PRNumber,
+				Title:        review.PRTitle,
+				URL:          review.PRURL,
+				Since:        review.CreatedAt,
+				RequestedBy:  review.RequestedBy,
+			})
+		case models.PRDependencyStateMerged, models.PRDependencyStateClosed:
+			if err := h.firestoreService.DeletePendingReview(ctx, review.ReviewerGitHubID, review.RepoFullName, review.PRNumber); err != nil {
+				log.Warn(ctx, "Failed to delete pending review on finished PR", "error", err)
+			}
+		}
+	}
+
+	return digest, nil
+}
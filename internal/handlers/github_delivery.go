@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+)
+
+// webhookDeliveryTTL is how long processed webhook deliveries are remembered.
+// GitHub only offers redelivery of recent deliveries, so redeliveries older than this are rare.
+const webhookDeliveryTTL = 7 * 24 * time.Hour
+
+// deliveryIDKey is the context key of the GitHub delivery being processed.
+type deliveryIDKey struct{}
+
+// withDeliveryID returns ctx carrying the ID of the GitHub delivery being processed,
+// so jobs fanned out from the delivery can be deduplicated too.
+func withDeliveryID(ctx context.Context, deliveryID string) context.Context {
+	return context.WithValue(ctx, deliveryIDKey{}, deliveryID)
+}
+
+// deliveryIDFromContext returns the ID of the GitHub delivery being processed, or "" outside a webhook job.
+func deliveryIDFromContext(ctx context.Context) string {
+	deliveryID, _ := ctx.Value(deliveryIDKey{}).(string)
+	return deliveryID
+}
+
+// getWebhookDelivery returns the processing record of a GitHub delivery, or nil if it has none.
+// Lookup failures are logged and treated as no record, leaving duplicate message detection to catch redeliveries.
+func (h *GitHubHandler) getWebhookDelivery(ctx context.Context, deliveryID string) *models.WebhookDelivery {
+	if deliveryID == "" {
+		return nil
+	}
+
+	delivery, err := h.firestoreService.GetWebhookDelivery(ctx, deliveryID)
+	if err != nil {
+		log.Warn(ctx, "Failed to check webhook delivery, processing it anyway", "error", err)
+		return nil
+	}
+	return delivery
+}
+
+// markWebhookDeliveryProcessed records that a GitHub delivery's job succeeded, so redeliveries are no-ops.
+// Failures are logged rather than returned, as the delivery has been processed.
+func (h *GitHubHandler) markWebhookDeliveryProcessed(ctx context.Context, webhookJob *models.WebhookJob) {
+	if webhookJob.DeliveryID == "" {
+		return
+	}
+
+	err := h.firestoreService.MarkWebhookDeliveryProcessed(ctx, webhookJob.DeliveryID, webhookJob.EventType, webhookDeliveryTTL)
+	if err != nil {
+		log.Warn(ctx, "Failed to record processed webhook delivery", "error", err)
+	}
+}
+
+// markWebhookDeliveryWorkspaceCompleted records that a GitHub delivery's PR job succeeded for a workspace,
+// so the workspace isn't posted to again if the delivery is retried or redelivered.
+// Failures are logged rather than returned, as the notification has been posted.
+func (h *GitHubHandler) markWebhookDeliveryWorkspaceCompleted(ctx context.Context, deliveryID, workspaceID string) {
+	if deliveryID == "" {
+		return
+	}
+
+	err := h.firestoreService.MarkWebhookDeliveryWorkspaceCompleted(ctx, deliveryID, workspaceID, webhookDeliveryTTL)
+	if err != nil {
+		log.Warn(ctx, "Failed to record completed workspace for webhook delivery", "error", err)
+	}
+}
@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/go-github/v74/github"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+	"github-slack-notifier/internal/services"
+	"github-slack-notifier/internal/utils"
+)
+
+// dependencyRefreshMaxAge stops refreshing dependency status on messages older than this.
+const dependencyRefreshMaxAge = 30 * 24 * time.Hour
+
+// ProcessDependencyRefreshJob refreshes dependency status on PR messages with open dependencies.
+// Triggered on a schedule to catch dependencies whose merge wasn't observed via webhook,
+// e.g. PRs in repositories that aren't configured in any workspace.
+func (h *GitHubHandler) ProcessDependencyRefreshJob(ctx context.Context, _ *models.Job) error {
+	messages, err := h.firestoreService.ListTrackedMessagesWithOpenDependencies(ctx)
+	if err != nil {
+		log.Error(ctx, "Failed to list tracked messages with open dependencies", "error", err)
+		return err
+	}
+
+	now := time.Now()
+	stateCache := make(map[string]string)
+	refreshed := 0
+	for _, msg := range messages {
+		if msg.DeletedByUser || now.Sub(msg.CreatedAt) > dependencyRefreshMaxAge {
+			continue
+		}
+
+		msgCtx := log.WithFields(ctx, log.LogFields{
+			"repo":               msg.RepoFullName,
+			"pr_number":          msg.PRNumber,
+			"tracked_message_id": msg.ID,
+		})
+		if err := h.refreshMessageDependencies(msgCtx, msg, stateCache, false); err != nil {
+			// Continue with other messages even if one fails
+			log.Error(msgCtx, "Failed to refresh PR dependencies", "error", err)
+			continue
+		}
+		refreshed++
+	}
+
+	log.Info(ctx, "Dependency refresh job completed",
+		"message_count", len(messages),
+		"refreshed_count", refreshed,
+	)
+	return nil
+}
+
+// initMessageDependencies records the dependencies parsed from the PR description on a new tracked message.
+func initMessageDependencies(msg *models.TrackedMessage, body string) {
+	dependencies := utils.ExtractPRDependencies(body, msg.RepoFullName, msg.PRNumber)
+	if len(dependencies) == 0 {
+		return
+	}
+	msg.Dependencies = dependencies
+	msg.DependencyKeys = dependencyKeys(dependencies)
+	msg.HasOpenDependencies = true
+}
+
+// dependencyKeys returns the "owner/repo#N" keys for dependencies.
+func dependencyKeys(dependencies []models.PRDependency) []string {
+	keys := make([]string, 0, len(dependencies))
+	for _, dependency := range dependencies {
+		keys = append(keys, dependency.Key())
+	}
+	return keys
+}
+
+// refreshMessageDependencies looks up the current state of each dependency and, if anything changed
+// (or force is set), updates the dependency line on the Slack message and the stored states.
+// stateCache is shared across messages to avoid repeated GitHub lookups for the same dependency.
+func (h *GitHubHandler) refreshMessageDependencies(
+	ctx context.Context, msg *models.TrackedMessage, stateCache map[string]string, force bool,
+) error {
+	changed := force
+	hasOpen := false
+	updated := make([]models.PRDependency, len(msg.Dependencies))
+	for i, dependency := range msg.Dependencies {
+		if state := h.lookupDependencyState(ctx, dependency, stateCache); state != "" && state != dependency.State {
+			dependency.State = state
+			changed = true
+		}
+		if !dependency.IsResolved() {
+			hasOpen = true
+		}
+		updated[i] = dependency
+	}
+
+	if !changed && hasOpen == msg.HasOpenDependencies {
+		return nil
+	}
+
+	err := h.slackService.SetDependencyText(ctx, msg.SlackTeamID, []services.MessageRef{trackedMessageRef(msg)}, utils.FormatDependencyLine(updated))
+	if err != nil {
+		return fmt.Errorf("failed to update dependency line: %w", err)
+	}
+
+	msg.Dependencies = updated
+	msg.DependencyKeys = dependencyKeys(updated)
+	msg.HasOpenDependencies = hasOpen
+	return h.firestoreService.UpdateTrackedMessageDependencies(ctx, msg)
+}
+
+// lookupDependencyState returns the current state of a dependency PR, or an empty string if it can't be determined.
+func (h *GitHubHandler) lookupDependencyState(ctx context.Context, dependency models.PRDependency, stateCache map[string]string) string {
+	if state, cached := stateCache[dependency.Key()]; cached {
+		return state
+	}
+
+	state, err := h.githubService.GetPullRequestState(ctx, dependency.RepoFullName, dependency.PRNumber)
+	if err != nil {
+		log.Warn(ctx, "Failed to look up dependency PR state",
+			"error", err,
+			"dependency", dependency.Key(),
+		)
+	}
+	stateCache[dependency.Key()] = state
+	return state
+}
+
+// refreshDependentMessages updates messages for PRs that depend on a PR which just closed or merged.
+func (h *GitHubHandler) refreshDependentMessages(ctx context.Context, payload *github.PullRequestEvent) {
+	dependency := models.PRDependency{
+		RepoFullName: payload.GetRepo().GetFullName(),
+		PRNumber:     payload.GetPullRequest().GetNumber(),
+	}
+	dependents, err := h.firestoreService.GetTrackedMessagesByDependency(ctx, dependency.Key())
+	if err != nil {
+		log.Error(ctx, "Failed to get dependent PR messages", "error", err)
+		return
+	}
+
+	// The webhook already tells us the new state, so seed the cache to skip the GitHub lookup
+	state := models.PRDependencyStateClosed
+	if payload.GetPullRequest().GetMerged() {
+		state = models.PRDependencyStateMerged
+	}
+	stateCache := map[string]string{dependency.Key(): state}
+
+	for _, msg := range dependents {
+		if msg.DeletedByUser {
+			continue
+		}
+		if err := h.refreshMessageDependencies(ctx, msg, stateCache, false); err != nil {
+			log.Error(ctx, "Failed to refresh dependent PR message",
+				"error", err,
+				"dependent_repo", msg.RepoFullName,
+				"dependent_pr_number", msg.PRNumber,
+			)
+		}
+	}
+}
+
+// syncPRDependencies re-parses dependencies after a PR description edit and updates bot messages.
+// Messages are refreshed whenever they have dependencies, since other edits may have rebuilt the message text.
+func (h *GitHubHandler) syncPRDependencies(ctx context.Context, payload *github.PullRequestEvent) {
+	repoFullName := payload.GetRepo().GetFullName()
+	prNumber := payload.GetPullRequest().GetNumber()
+	dependencies := utils.ExtractPRDependencies(payload.GetPullRequest().GetBody(), repoFullName, prNumber)
+
+	botMessages, err := h.firestoreService.GetTrackedMessages(ctx, services.TrackedMessageQuery{
+		RepoFullName:  repoFullName,
+		PRNumber:      prNumber,
+		MessageSource: models.MessageSourceBot,
+	})
+	if err != nil {
+		log.Error(ctx, "Failed to get bot messages for dependency sync", "error", err)
+		return
+	}
+
+	stateCache := make(map[string]string)
+	for _, msg := range botMessages {
+		if msg.DeletedByUser || (len(msg.Dependencies) == 0 && len(dependencies) == 0) {
+			continue
+		}
+
+		// Carry over known states for dependencies that are still referenced
+		knownStates := make(map[string]string, len(msg.Dependencies))
+		for _, existing := range msg.Dependencies {
+			knownStates[existing.Key()] = existing.State
+		}
+		msg.Dependencies = make([]models.PRDependency, len(dependencies))
+		for i, dependency := range dependencies {
+			dependency.State = knownStates[dependency.Key()]
+			msg.Dependencies[i] = dependency
+		}
+
+		keysChanged := !slices.Equal(msg.DependencyKeys, dependencyKeys(dependencies))
+		if err := h.refreshMessageDependencies(ctx, msg, stateCache, true); err != nil {
+			log.Error(ctx, "Failed to sync PR dependencies",
+				"error", err,
+				"tracked_message_id", msg.ID,
+				"dependencies_changed", keysChanged,
+			)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/go-github/v74/github"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+	"github-slack-notifier/internal/utils"
+)
+
+// digestEntryMaxAge drops buffered entries that couldn't be delivered for this long (e.g. uninstalled workspaces).
+const digestEntryMaxAge = 7 * 24 * time.Hour
+
+// ProcessUserDigestJob flushes buffered events to each digest mode user as a single DM.
+// Triggered hourly by Cloud Scheduler. Entries are only deleted once their digest is delivered,
+// so a failed DM is retried on the next flush, and users in quiet hours get theirs on the first flush after.
+func (h *GitHubHandler) ProcessUserDigestJob(ctx context.Context, _ *models.Job) error {
+	now := time.Now()
+	entries, err := h.firestoreService.ListDigestEntries(ctx, now)
+	if err != nil {
+		log.Error(ctx, "Failed to list digest entries", "error", err)
+		return err
+	}
+
+	entriesByUser := make(map[string][]*models.DigestEntry)
+	for _, entry := range entries {
+		key := entry.SlackTeamID + "#" + entry.SlackUserID
+		entriesByUser[key] = append(entriesByUser[key], entry)
+	}
+
+	delivered := 0
+	for _, userEntries := range entriesByUser {
+		teamID := userEntries[0].SlackTeamID
+		userID := userEntries[0].SlackUserID
+		userCtx := log.WithFields(ctx, log.LogFields{
+			"slack_team_id": teamID,
+			"slack_user_id": userID,
+		})
+		if h.userInQuietHours(userCtx, userID, now) {
+			log.Debug(userCtx, "Holding digest until quiet hours end", "entry_count", len(userEntries))
+			continue
+		}
+
+		entryIDs := make([]string, 0, len(userEntries))
+		for _, entry := range userEntries {
+			entryIDs = append(entryIDs, entry.ID)
+		}
+
+		if _, err := h.slackService.PostMessage(userCtx, teamID, userID, utils.FormatUserDigest(userEntries)); err != nil {
+			log.Error(userCtx, "Failed to deliver user digest", "error", err, "entry_count", len(userEntries))
+			entryIDs = expiredDigestEntryIDs(userEntries, now)
+		} else {
+			delivered++
+		}
+
+		if err := h.firestoreService.DeleteDigestEntries(userCtx, entryIDs); err != nil {
+			// Entries will be included again in the next digest
+			log.Error(userCtx, "Failed to delete flushed digest entries", "error", err)
+		}
+	}
+
+	log.Info(ctx, "User digest job completed",
+		"entry_count", len(entries),
+		"user_count", len(entriesByUser),
+		"delivered_count", delivered,
+	)
+	return nil
+}
+
+// ProcessChannelDigestJob posts each channel's daily digest of new PRs from digest-only repositories.
+// Triggered daily by Cloud Scheduler. As with user digests, entries are only deleted once posted.
+func (h *GitHubHandler) ProcessChannelDigestJob(ctx context.Context, _ *models.Job) error {
+	now := time.Now()
+	entries, err := h.firestoreService.ListChannelDigestEntries(ctx, now)
+	if err != nil {
+		log.Error(ctx, "Failed to list channel digest entries", "error", err)
+		return err
+	}
+
+	entriesByChannel := make(map[string][]*models.DigestEntry)
+	for _, entry := range entries {
+		key := entry.SlackTeamID + "#" + entry.SlackChannel
+		entriesByChannel[key] = append(entriesByChannel[key], entry)
+	}
+
+	posted := 0
+	for _, channelEntries := range entriesByChannel {
+		teamID := channelEntries[0].SlackTeamID
+		channel := channelEntries[0].SlackChannel
+		channelCtx := log.WithFields(ctx, log.LogFields{
+			"slack_team_id": teamID,
+			"channel":       channel,
+		})
+
+		entryIDs := make([]string, 0, len(channelEntries))
+		for _, entry := range channelEntries {
+			entryIDs = append(entryIDs, entry.ID)
+		}
+
+		date := h.slackService.GetWorkspaceTimeFormat(channelCtx, teamID).Date(now)
+		if _, err := h.slackService.PostMessage(channelCtx, teamID, channel, utils.FormatChannelDigest(channelEntries, date)); err != nil {
+			log.Error(channelCtx, "Failed to post channel digest", "error", err, "entry_count", len(channelEntries))
+			entryIDs = expiredDigestEntryIDs(channelEntries, now)
+		} else {
+			posted++
+		}
+
+		if err := h.firestoreService.DeleteChannelDigestEntries(channelCtx, entryIDs); err != nil {
+			// Entries will be included again in the next digest
+			log.Error(channelCtx, "Failed to delete posted channel digest entries", "error", err)
+		}
+	}
+
+	log.Info(ctx, "Channel digest job completed",
+		"entry_count", len(entries),
+		"channel_count", len(entriesByChannel),
+		"posted_count", posted,
+	)
+	return nil
+}
+
+// bufferChannelDigestEntry stores a new PR from a digest-only repository for the channel's next daily digest.
+// Failures are logged, matching how a failed post would otherwise be retried by the webhook job.
+func (h *GitHubHandler) bufferChannelDigestEntry(
+	ctx context.Context, payload *github.PullRequestEvent, workspaceID, channel string,
+) {
+	err := h.firestoreService.AddChannelDigestEntry(ctx, &models.DigestEntry{
+		SlackTeamID:  workspaceID,
+		SlackChannel: channel,
+		Event:        models.DigestEventPROpened,
+		RepoFullName: payload.GetRepo().GetFullName(),
+		PRNumber:     payload.GetPullRequest().GetNumber(),
+		PRTitle:      payload.GetPullRequest().GetTitle(),
+		PRURL:        payload.GetPullRequest().GetHTMLURL(),
+		Actor:        payload.GetPullRequest().GetUser().GetLogin(),
+	})
+	if err != nil {
+		log.Error(ctx, "Failed to buffer PR for channel digest", "error", err, "channel", channel)
+		return
+	}
+
+	log.Info(ctx, "Buffered PR for channel digest (digest-only repository)",
+		"channel", channel,
+		"slack_team_id", workspaceID,
+	)
+}
+
+// expiredDigestEntryIDs returns the IDs of undelivered entries that are too old to keep retrying.
+func expiredDigestEntryIDs(entries []*models.DigestEntry, now time.Time) []string {
+	var entryIDs []string
+	for _, entry := range entries {
+		if now.Sub(entry.CreatedAt) > digestEntryMaxAge {
+			entryIDs = append(entryIDs, entry.ID)
+		}
+	}
+	return entryIDs
+}
+
+// resolveCCMention resolves a CC'd GitHub username to a Slack user ID for a new PR message.
+// Users in digest mode aren't pinged; the CC is buffered for their next digest instead.
+// Users who are out of office aren't pinged either, and are recorded in ccDelegates. Users who have muted the
+// repository aren't pinged or sent the CC at all.
+func (h *GitHubHandler) resolveCCMention(
+	ctx context.Context, payload *github.PullRequestEvent, githubUsername, workspaceID string, ccDelegates map[string]string,
+) string {
+	user := h.lookupUserForMention(ctx, githubUsername, workspaceID)
+	if user == nil || user.RepoMuted(payload.GetRepo().GetFullName(), time.Now()) || recordOutOfOffice(user, githubUsername, ccDelegates) {
+		return ""
+	}
+	if !user.DigestMode {
+		return user.SlackUserID
+	}
+
+	h.bufferDigestEntry(ctx, user, &models.DigestEntry{
+		Event:        models.DigestEventCC,
+		RepoFullName: payload.GetRepo().GetFullName(),
+		PRNumber:     payload.GetPullRequest().GetNumber(),
+		PRTitle:      payload.GetPullRequest().GetTitle(),
+		PRURL:        payload.GetPullRequest().GetHTMLURL(),
+		Actor:        payload.GetPullRequest().GetUser().GetLogin(),
+	})
+	return ""
+}
+
+// bufferDigestEntry stores an event for a digest mode user. Failures are logged, as the event is non-critical.
+func (h *GitHubHandler) bufferDigestEntry(ctx context.Context, user *models.User, entry *models.DigestEntry) {
+	entry.SlackTeamID = user.SlackTeamID
+	entry.SlackUserID = user.SlackUserID
+	if err := h.firestoreService.AddDigestEntry(ctx, entry); err != nil {
+		log.Error(ctx, "Failed to buffer digest entry",
+			"error", err,
+			"slack_user_id", user.SlackUserID,
+			"event", entry.Event,
+		)
+		return
+	}
+
+	log.Debug(ctx, "Buffered digest entry",
+		"slack_user_id", user.SlackUserID,
+		"event", entry.Event,
+	)
+}
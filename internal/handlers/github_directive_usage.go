@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/google/go-github/v74/github"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+	"github-slack-notifier/internal/services"
+)
+
+// recordDirectiveUsage records the directives used by a PR notification in a workspace.
+// Failures are logged rather than returned, since metrics must never block notifications.
+func (h *GitHubHandler) recordDirectiveUsage(
+	ctx context.Context, payload *github.PullRequestEvent, workspaceID string, directives *services.PRDirectives, channelFailed bool,
+) {
+	event := &models.DirectiveUsageEvent{
+		RepoFullName:  payload.GetRepo().GetFullName(),
+		Types:         directives.UsedTypes(),
+		Channel:       directives.Channel,
+		CustomEmoji:   directives.CustomEmoji,
+		ChannelFailed: channelFailed,
+	}
+
+	if err := h.firestoreService.RecordDirectiveUsage(ctx, workspaceID, event); err != nil {
+		log.Warn(ctx, "Failed to record directive usage",
+			"error", err,
+			"slack_team_id", workspaceID,
+		)
+	}
+}
+
+// recordSkipDirectiveUsage records a skip directive against every workspace tracking the repository.
+// Only counted for newly opened PRs, so edits to an already skipped PR aren't counted again.
+func (h *GitHubHandler) recordSkipDirectiveUsage(
+	ctx context.Context, payload *github.PullRequestEvent, directives *services.PRDirectives,
+) {
+	if payload.GetAction() != PRActionOpened {
+		return
+	}
+
+	repos, err := h.firestoreService.GetReposForAllWorkspaces(ctx, payload.GetRepo().GetFullName())
+	if err != nil {
+		log.Warn(ctx, "Failed to look up workspaces for skip directive usage", "error", err)
+		return
+	}
+
+	for _, repo := range repos {
+		h.recordDirectiveUsage(ctx, payload, repo.WorkspaceID, directives, false)
+	}
+}
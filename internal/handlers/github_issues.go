@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/go-github/v74/github"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+)
+
+// issueStateReasonCompleted is GitHub's state reason for an issue closed as completed, rather than not planned.
+const issueStateReasonCompleted = "completed"
+
+// processIssuesEvent processes issues webhook events for issue links tracked in Slack.
+// Closing an issue adds the merged reaction if it was completed, or the closed reaction otherwise;
+// reopening it removes them.
+func (h *GitHubHandler) processIssuesEvent(ctx context.Context, payload []byte) error {
+	var githubPayload github.IssuesEvent
+	if err := json.Unmarshal(payload, &githubPayload); err != nil {
+		log.Error(ctx, "Failed to unmarshal issues payload",
+			"error", err,
+			"payload_size", len(payload),
+		)
+		return fmt.Errorf("failed to unmarshal issues payload: %w", models.Permanent(err))
+	}
+
+	action := githubPayload.GetAction()
+	if action != IssueActionClosed && action != IssueActionReopened {
+		return nil
+	}
+
+	issue := githubPayload.GetIssue()
+	ctx = log.WithFields(ctx, log.LogFields{
+		"issue_number": issue.GetNumber(),
+		"repo":         githubPayload.GetRepo().GetFullName(),
+		"issue_action": action,
+	})
+
+	log.Info(ctx, "Processing issue state change")
+	return h.applyIssueStateReactions(ctx, githubPayload.GetRepo().GetFullName(), issue)
+}
+
+// syncIssueReactions syncs the state reaction of a newly tracked issue link with the issue's current state.
+func (h *GitHubHandler) syncIssueReactions(ctx context.Context, repoFullName string, issueNumber int) error {
+	issue, err := h.githubService.GetIssue(ctx, repoFullName, issueNumber)
+	if err != nil {
+		log.Error(ctx, "Failed to fetch issue details from GitHub", "error", err)
+		return fmt.Errorf("failed to fetch issue details: %w", err)
+	}
+	if issue.GetState() != "closed" {
+		return nil
+	}
+	return h.applyIssueStateReactions(ctx, repoFullName, issue)
+}
+
+// applyIssueStateReactions adds the state reaction for a closed issue to its tracked links, or removes
+// state reactions from an open one, in channels that allow state reactions.
+func (h *GitHubHandler) applyIssueStateReactions(ctx context.Context, repoFullName string, issue *github.Issue) error {
+	trackedMessages, err := h.getAllTrackedMessagesForPR(ctx, repoFullName, issue.GetNumber())
+	if err != nil {
+		log.Error(ctx, "Failed to get tracked messages for issue state reaction", "error", err)
+		return err
+	}
+
+	issueMessages := make([]*models.TrackedMessage, 0, len(trackedMessages))
+	for _, msg := range trackedMessages {
+		if msg.IsIssue() && !msg.DeletedByUser {
+			issueMessages = append(issueMessages, msg)
+		}
+	}
+	if len(issueMessages) == 0 {
+		log.Debug(ctx, "No tracked messages found for issue")
+		return nil
+	}
+
+	closed := issue.GetState() == "closed"
+	completed := issue.GetStateReason() == issueStateReasonCompleted
+	targets := h.resolveReactionTargets(ctx, issueMessages)
+	for teamID, teamMessageRefs := range targets.prState {
+		if !closed {
+			if err := h.slackService.RemovePRStateReactions(ctx, teamID, teamMessageRefs); err != nil {
+				log.Error(ctx, "Failed to remove issue state reactions for team", "error", err, "team_id", teamID)
+			}
+			continue
+		}
+
+		emojiConfig := h.slackService.EmojiConfig(ctx, teamID)
+		emoji := emojiConfig.Closed
+		if completed {
+			emoji = emojiConfig.Merged
+		}
+		if err := h.slackService.AddReactionToMultipleMessages(ctx, teamID, teamMessageRefs, emoji); err != nil {
+			log.Error(ctx, "Failed to add issue state reactions for team",
+				"error", err,
+				"team_id", teamID,
+				"emoji", emoji,
+				"message_count", len(teamMessageRefs),
+			)
+			// Continue with other teams even if one fails
+		}
+	}
+
+	log.Info(ctx, "Issue state reactions synchronized across tracked messages",
+		"closed", closed,
+		"completed", completed,
+		"message_count", len(issueMessages),
+	)
+	return nil
+}
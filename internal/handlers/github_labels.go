@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/go-github/v74/github"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+	"github-slack-notifier/internal/services"
+)
+
+// handlePRLabelsChanged handles PR labeled and unlabeled events.
+// Updates the label chips on messages that show them, or skips or unskips the PR when it's the skip label.
+func (h *GitHubHandler) handlePRLabelsChanged(ctx context.Context, payload *github.PullRequestEvent) error {
+	log.Info(ctx, "Pull request labels changed",
+		"label", payload.GetLabel().GetName(),
+		"labels", prLabelNames(payload),
+	)
+
+	if h.isSkipLabel(payload.GetLabel().GetName()) {
+		return h.handleSkipLabelChanged(ctx, payload)
+	}
+	return h.refreshPRMetadata(ctx, payload)
+}
+
+// handleSkipLabelChanged deletes a PR's messages when the skip label is added, and posts the PR again when it's
+// removed, as adding and removing the skip directive in its description does. A skip directive still in the
+// description keeps the PR skipped, and closed PRs aren't posted again.
+func (h *GitHubHandler) handleSkipLabelChanged(ctx context.Context, payload *github.PullRequestEvent) error {
+	if payload.GetAction() == PRActionLabeled {
+		log.Info(ctx, "Skip label added, processing skip")
+		return h.processSkipDirective(ctx, payload)
+	}
+
+	if h.slackService.ParsePRDirectives(payload.GetPullRequest().GetBody()).Skip {
+		log.Info(ctx, "Skip label removed, but the PR still has a skip directive")
+		return nil
+	}
+	if payload.GetPullRequest().GetState() == "closed" {
+		log.Debug(ctx, "Skip label removed from closed PR, not posting it")
+		return nil
+	}
+
+	log.Info(ctx, "Skip label removed, processing unskip")
+	return h.handleUnskipDirective(ctx, payload)
+}
+
+// isSkipLabel reports whether a label is SKIP_LABEL. GitHub label names are case-insensitive.
+func (h *GitHubHandler) isSkipLabel(name string) bool {
+	return h.skipLabel != "" && strings.EqualFold(name, h.skipLabel)
+}
+
+// hasSkipLabel reports whether a PR has SKIP_LABEL, which keeps it out of Slack like the skip directive.
+func (h *GitHubHandler) hasSkipLabel(payload *github.PullRequestEvent) bool {
+	for _, label := range payload.GetPullRequest().Labels {
+		if h.isSkipLabel(label.GetName()) {
+			return true
+		}
+	}
+	return false
+}
+
+// refreshPRMetadata rebuilds a PR's messages whose label chips or milestone are out of date, catching up on any
+// other changes at the same time, unless the PR has used up its update budget.
+// Closed PRs are left alone, as rebuilding their messages would drop the lifecycle state they show.
+func (h *GitHubHandler) refreshPRMetadata(ctx context.Context, payload *github.PullRequestEvent) error {
+	if payload.GetPullRequest().GetState() == "closed" {
+		log.Debug(ctx, "Skipping label and milestone update for closed PR")
+		return nil
+	}
+
+	directives := h.slackService.ParsePRDirectives(payload.GetPullRequest().GetBody())
+	changes := h.detectPRChanges(ctx, payload, directives)
+	if !changes.HasChanges() || !h.spendPRUpdateBudget(ctx, payload.GetRepo().GetFullName(), payload.GetPullRequest().GetNumber()) {
+		return nil
+	}
+
+	if err := h.updateMessagesForPRChanges(ctx, payload, changes, directives); err != nil {
+		log.Error(ctx, "Failed to update PR labels and milestone", "error", err)
+		return err
+	}
+	return nil
+}
+
+// showsPRMetadata reports whether a tracked message shows its PR's label chips and milestone.
+// Rich messages always do, and text messages do when label chips are enabled; one-line messages never do.
+func (h *GitHubHandler) showsPRMetadata(msg *models.TrackedMessage) bool {
+	if msg.Compact {
+		return false
+	}
+	return msg.MessageFormat == models.MessageFormatRich || h.slackService.MessageLabelsEnabled()
+}
+
+// prMetadata returns the labels and milestone shown on a PR's message in a channel.
+// Channels with project context enabled already show the milestone on its own line, so it's left out there.
+func prMetadata(payload *github.PullRequestEvent, channelConfig *models.ChannelConfig) *services.PRMetadata {
+	metadata := &services.PRMetadata{Milestone: prMilestone(payload)}
+	if channelConfig != nil && channelConfig.ProjectContext {
+		metadata.Milestone = ""
+	}
+	for _, label := range payload.GetPullRequest().Labels {
+		metadata.Labels = append(metadata.Labels, services.PRLabel{Name: label.GetName(), Color: label.GetColor()})
+	}
+	return metadata
+}
+
+// prLabelNames returns the names of a PR's labels, as stored on its tracked messages.
+func prLabelNames(payload *github.PullRequestEvent) []string {
+	var names []string
+	for _, label := range payload.GetPullRequest().Labels {
+		names = append(names, label.GetName())
+	}
+	return names
+}
+
+// prMilestone returns a PR's milestone title, or empty if the event removed it.
+func prMilestone(payload *github.PullRequestEvent) string {
+	if payload.GetAction() == PRActionDemilestoned {
+		return ""
+	}
+	return payload.GetPullRequest().GetMilestone().GetTitle()
+}
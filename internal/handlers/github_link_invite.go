@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/go-github/v74/github"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+)
+
+const (
+	// linkInviteCooldown is the minimum time between invitations for the same GitHub username in a workspace.
+	linkInviteCooldown = 30 * 24 * time.Hour
+	// maxLinkInvites is how many times a GitHub username is invited at most, so nobody is nagged indefinitely.
+	maxLinkInvites = 3
+)
+
+// inviteUnlinkedCCs invites CC'd GitHub users without a linked Slack account to link it, if the workspace opted in.
+// The Slack user is found by matching their names against the GitHub username, and only a unique match is invited.
+// Invitations are rate limited per username and can be opted out of. Failures are logged, as invitations are best effort.
+func (h *GitHubHandler) inviteUnlinkedCCs(
+	ctx context.Context, payload *github.PullRequestEvent, workspaceID, channel string, usernames, slackIDs []string,
+) {
+	var unlinked []string
+	for i, username := range usernames {
+		if i < len(slackIDs) && slackIDs[i] == "" {
+			unlinked = append(unlinked, username)
+		}
+	}
+	if len(unlinked) == 0 {
+		return
+	}
+
+	workspace, err := h.slackService.GetWorkspace(ctx, workspaceID)
+	if err != nil {
+		log.Debug(ctx, "Failed to get workspace for link invites", "error", err)
+		return
+	}
+	mode := workspace.GetLinkInvites()
+	if mode == models.LinkInvitesOff {
+		return
+	}
+
+	for _, username := range unlinked {
+		h.inviteUnlinkedCC(ctx, payload, workspaceID, channel, username, mode == models.LinkInvitesEphemeral)
+	}
+}
+
+// inviteUnlinkedCC invites the Slack user likely to be a CC'd GitHub username to link their account.
+func (h *GitHubHandler) inviteUnlinkedCC(
+	ctx context.Context, payload *github.PullRequestEvent, workspaceID, channel, username string, ephemeral bool,
+) {
+	ctx = log.WithFields(ctx, log.LogFields{
+		"github_username": username,
+		"ephemeral":       ephemeral,
+	})
+
+	// CC'd users in digest mode, or who haven't verified their account yet, already have a user record
+	user, err := h.firestoreService.GetUserByGitHubUsernameAndWorkspace(ctx, username, workspaceID)
+	if err != nil || user != nil {
+		return
+	}
+
+	claimed, err := h.firestoreService.ClaimLinkInvite(ctx, workspaceID, username, linkInviteCooldown, maxLinkInvites)
+	if err != nil {
+		log.Warn(ctx, "Failed to claim link invite", "error", err)
+		return
+	}
+	if !claimed {
+		log.Debug(ctx, "Link invite skipped by rate limit or opt-out")
+		return
+	}
+
+	slackUserID, err := h.slackService.FindUserByGitHubUsername(ctx, workspaceID, username)
+	if err != nil {
+		log.Warn(ctx, "Failed to find Slack user for link invite", "error", err)
+		return
+	}
+	if slackUserID == "" {
+		log.Debug(ctx, "No unique Slack user matches CC'd GitHub username, not inviting")
+		return
+	}
+
+	// Someone who linked a different GitHub account isn't this user
+	existing, err := h.firestoreService.GetUserBySlackID(ctx, slackUserID)
+	if err != nil || existing != nil {
+		return
+	}
+
+	pr := payload.GetPullRequest()
+	if err := h.slackService.PostLinkInvite(ctx, workspaceID, channel, slackUserID, username,
+		pr.GetTitle(), pr.GetHTMLURL(), ephemeral); err != nil {
+		log.Warn(ctx, "Failed to send link invite", "error", err, "slack_user_id", slackUserID)
+		return
+	}
+	log.Info(ctx, "Invited CC'd user to link their GitHub account", "slack_user_id", slackUserID)
+}
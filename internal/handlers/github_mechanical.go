@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/go-github/v74/github"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+	"github-slack-notifier/internal/utils"
+)
+
+// applyMechanicalPRHandling applies the repository's handling of revert and automated back-merge PRs.
+// Returns the repo and annotated channel to post with, and whether the notification should be skipped.
+// Compact handling returns a copy of the repo in compact mode, so the stored config isn't changed.
+func (h *GitHubHandler) applyMechanicalPRHandling(
+	ctx context.Context, payload *github.PullRequestEvent, repo *models.Repo, annotatedChannel string,
+	trace *routingTrace,
+) (*models.Repo, string, bool) {
+	config := repo.MechanicalPRs
+	if config == nil || !config.IsValid() {
+		return repo, annotatedChannel, false
+	}
+
+	pr := payload.GetPullRequest()
+	kind := utils.DetectMechanicalPR(
+		pr.GetTitle(),
+		pr.GetBody(),
+		pr.GetHead().GetRef(),
+		pr.GetBase().GetRef(),
+		payload.GetRepo().GetDefaultBranch(),
+		isAutomatedAuthor(pr.GetUser()),
+	)
+	if kind == "" {
+		return repo, annotatedChannel, false
+	}
+
+	log.Info(ctx, "Applying mechanical PR handling",
+		"mechanical_pr_kind", kind,
+		"handling", config.Handling,
+		"slack_team_id", repo.WorkspaceID,
+	)
+
+	switch config.Handling {
+	case models.MechanicalPRHandlingSkip:
+		trace.add("Skipped: %s PRs are skipped for this repository", kind)
+		return repo, annotatedChannel, true
+	case models.MechanicalPRHandlingCompact:
+		trace.add("Posted compact: %s PRs are compact for this repository", kind)
+		compactRepo := *repo
+		compactRepo.NotificationMode = models.NotificationModeCompact
+		return &compactRepo, annotatedChannel, false
+	default:
+		channel := strings.TrimPrefix(config.Channel, "#")
+		trace.add("Routed to #%s: %s PRs go to a dedicated channel for this repository", channel, kind)
+		return repo, channel, false
+	}
+}
+
+// isAutomatedAuthor reports whether a PR author is a bot account, such as a GitHub App or dependency bot.
+func isAutomatedAuthor(user *github.User) bool {
+	return user.GetType() == "Bot" || strings.HasSuffix(user.GetLogin(), "[bot]")
+}
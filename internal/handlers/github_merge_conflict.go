@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v74/github"
+	"github.com/google/uuid"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+)
+
+// mergeConflictSweepMaxAge limits the scheduled merge conflict sweep to PRs tracked recently.
+const mergeConflictSweepMaxAge = 14 * 24 * time.Hour
+
+// branchRefPrefix prefixes the refs of branches, as opposed to tags, in push events.
+const branchRefPrefix = "refs/heads/"
+
+// processPushEvent processes push webhook events by enqueuing a merge conflict check for the pushed branch.
+// Pushes to a base branch can make its PRs conflict, and pushes to a PR's branch can resolve a conflict.
+func (h *GitHubHandler) processPushEvent(ctx context.Context, payload []byte, traceID string) error {
+	var githubPayload github.PushEvent
+	if err := json.Unmarshal(payload, &githubPayload); err != nil {
+		log.Error(ctx, "Failed to unmarshal push payload",
+			"error", err,
+			"payload_size", len(payload),
+		)
+		return fmt.Errorf("failed to unmarshal push payload: %w", models.Permanent(err))
+	}
+
+	branch, isBranch := strings.CutPrefix(githubPayload.GetRef(), branchRefPrefix)
+	if !isBranch || githubPayload.GetDeleted() {
+		return nil
+	}
+
+	repoFullName := githubPayload.GetRepo().GetFullName()
+	ctx = log.WithFields(ctx, log.LogFields{
+		"repo":   repoFullName,
+		"branch": branch,
+	})
+
+	// Pushes arrive for every repository in the installation, so skip those no workspace has configured
+	repos, err := h.firestoreService.GetReposForAllWorkspaces(ctx, repoFullName)
+	if err != nil {
+		log.Error(ctx, "Failed to get repository configurations for push", "error", err)
+		return fmt.Errorf("failed to get repository configurations: %w", err)
+	}
+	if len(repos) == 0 {
+		return nil
+	}
+
+	jobPayload, err := json.Marshal(&models.MergeConflictSyncJob{RepoFullName: repoFullName, Branch: branch})
+	if err != nil {
+		log.Error(ctx, "Failed to marshal merge conflict sync job", "error", err)
+		return fmt.Errorf("failed to marshal merge conflict sync job: %w", err)
+	}
+
+	job := &models.Job{
+		ID:      uuid.New().String(),
+		Type:    models.JobTypeMergeConflictSync,
+		TraceID: traceID,
+		Payload: jobPayload,
+	}
+	if err := h.cloudTasksService.EnqueueJob(ctx, job); err != nil {
+		log.Error(ctx, "Failed to enqueue merge conflict sync job", "error", err)
+		return fmt.Errorf("failed to enqueue merge conflict sync job: %w", err)
+	}
+
+	log.Info(ctx, "Enqueued merge conflict sync job", "job_id", job.ID)
+	return nil
+}
+
+// ProcessMergeConflictSyncJob checks open PRs for merge conflicts, flagging tracked messages of conflicting PRs
+// with a reaction and DMing authors who opted in. A job for a pushed branch checks the PRs based on or headed by it;
+// the scheduled job, with an empty payload, checks the PRs of recently tracked messages.
+func (h *GitHubHandler) ProcessMergeConflictSyncJob(ctx context.Context, job *models.Job) error {
+	var syncJob models.MergeConflictSyncJob
+	if len(job.Payload) > 0 {
+		if err := json.Unmarshal(job.Payload, &syncJob); err != nil {
+			return fmt.Errorf("failed to unmarshal merge conflict sync job: %w", models.Permanent(err))
+		}
+	}
+
+	if syncJob.RepoFullName != "" {
+		prs, err := h.githubService.ListOpenPullRequestsForBranch(ctx, syncJob.RepoFullName, syncJob.Branch)
+		if err != nil {
+			log.Error(ctx, "Failed to list PRs for pushed branch", "error", err)
+			return fmt.Errorf("failed to list PRs for branch: %w", err)
+		}
+		for _, pr := range prs {
+			h.checkMergeConflict(ctx, syncJob.RepoFullName, pr.GetNumber())
+		}
+		return nil
+	}
+
+	messages, err := h.firestoreService.ListRecentTrackedMessages(ctx, time.Now().Add(-mergeConflictSweepMaxAge))
+	if err != nil {
+		log.Error(ctx, "Failed to list recent tracked messages for merge conflict sweep", "error", err)
+		return err
+	}
+
+	checked := make(map[string]bool)
+	for _, msg := range messages {
+		prKey := msg.RepoFullName + "#" + strconv.Itoa(msg.PRNumber)
+		if msg.DeletedByUser || checked[prKey] {
+			continue
+		}
+		checked[prKey] = true
+		h.checkMergeConflict(ctx, msg.RepoFullName, msg.PRNumber)
+	}
+
+	log.Info(ctx, "Merge conflict sweep completed",
+		"message_count", len(messages),
+		"pr_count", len(checked),
+	)
+	return nil
+}
+
+// checkMergeConflict updates a PR's tracked messages when it starts or stops conflicting with its base branch.
+// PRs whose mergeability GitHub hasn't computed yet are skipped until the next check.
+// Failures are logged rather than returned, so one PR doesn't stop the others being checked.
+func (h *GitHubHandler) checkMergeConflict(ctx context.Context, repoFullName string, prNumber int) {
+	ctx = log.WithFields(ctx, log.LogFields{
+		"repo":      repoFullName,
+		"pr_number": prNumber,
+	})
+
+	trackedMessages, err := h.getAllTrackedMessagesForPR(ctx, repoFullName, prNumber)
+	if err != nil || len(trackedMessages) == 0 {
+		return
+	}
+
+	pr, err := h.githubService.GetPullRequest(ctx, repoFullName, prNumber)
+	if err != nil {
+		log.Warn(ctx, "Failed to fetch PR for merge conflict check", "error", err)
+		return
+	}
+	if pr.GetState() != "open" || pr.Mergeable == nil {
+		return
+	}
+	conflicting := !pr.GetMergeable()
+
+	wasConflicting := false
+	var changed []*models.TrackedMessage
+	for _, msg := range trackedMessages {
+		wasConflicting = wasConflicting || msg.MergeConflict
+		if msg.MergeConflict != conflicting && !msg.DeletedByUser {
+			changed = append(changed, msg)
+		}
+	}
+	if len(changed) == 0 {
+		return
+	}
+
+	targets := h.resolveReactionTargets(ctx, changed)
+	for teamID, teamMessageRefs := range targets.review {
+		if conflicting {
+			err = h.slackService.AddReactionToMultipleMessages(ctx, teamID, teamMessageRefs, h.emojiConfig.MergeConflict)
+		} else {
+			err = h.slackService.RemoveReactionFromMultipleMessages(ctx, teamID, teamMessageRefs, h.emojiConfig.MergeConflict)
+		}
+		if err != nil {
+			log.Warn(ctx, "Failed to update merge conflict reaction", "error", err, "team_id", teamID)
+		}
+	}
+	for _, msg := range changed {
+		// Logged by UpdateTrackedMessageMergeConflict; the next check retries
+		_ = h.firestoreService.UpdateTrackedMessageMergeConflict(ctx, msg.ID, conflicting)
+	}
+
+	log.Info(ctx, "Merge conflict state changed",
+		"merge_conflict", conflicting,
+		"message_count", len(changed),
+	)
+
+	if conflicting && !wasConflicting {
+		text := fmt.Sprintf(":warning: Your PR <%s|%s#%d %s> has merge conflicts with `%s`",
+			pr.GetHTMLURL(), repoFullName, prNumber, pr.GetTitle(), pr.GetBase().GetRef())
+		h.sendAuthorDM(ctx, pr.GetUser().GetID(), &models.DigestEntry{
+			Event:        models.AuthorDMEventMergeConflict,
+			RepoFullName: repoFullName,
+			PRNumber:     prNumber,
+			PRTitle:      pr.GetTitle(),
+			PRURL:        pr.GetHTMLURL(),
+		}, text)
+	}
+}
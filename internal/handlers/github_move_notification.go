@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/go-github/v74/github"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+	"github-slack-notifier/internal/services"
+)
+
+// ProcessMovePRNotificationJob moves a bot-posted PR message to another channel, as requested with the
+// "Move to another channel" message shortcut. The PR is posted and tracked in the new channel first, so a
+// failure never leaves it without a message, then the old message is deleted as for a channel directive change.
+func (h *GitHubHandler) ProcessMovePRNotificationJob(ctx context.Context, job *models.Job) error {
+	var moveJob models.MovePRNotificationJob
+	if err := json.Unmarshal(job.Payload, &moveJob); err != nil {
+		return fmt.Errorf("failed to unmarshal move PR notification job: %w", models.Permanent(err))
+	}
+	if err := moveJob.Validate(); err != nil {
+		return fmt.Errorf("invalid move PR notification job: %w", models.Permanent(err))
+	}
+
+	ctx = log.WithFields(ctx, log.LogFields{
+		"tracked_message_id": moveJob.TrackedMessageID,
+		"team_id":            moveJob.SlackTeamID,
+		"channel":            moveJob.SlackChannel,
+		"target_channel":     moveJob.TargetChannel,
+		"moved_by":           moveJob.MovedBy,
+	})
+
+	msg, err := h.firestoreService.GetTrackedMessageBySlackMessage(ctx, moveJob.SlackTeamID, moveJob.SlackChannel, moveJob.SlackMessageTS)
+	if err != nil {
+		log.Error(ctx, "Failed to get tracked message to move", "error", err)
+		return err
+	}
+	if msg == nil || msg.ID != moveJob.TrackedMessageID || msg.DeletedByUser {
+		log.Info(ctx, "Tracked message no longer exists, nothing to move")
+		return nil
+	}
+	if msg.SlackChannel == moveJob.TargetChannel {
+		log.Info(ctx, "Tracked message is already in the target channel")
+		return nil
+	}
+
+	ctx = log.WithFields(ctx, log.LogFields{
+		"repo":      msg.RepoFullName,
+		"pr_number": msg.PRNumber,
+	})
+
+	payload, repo, user, err := h.loadPRForRepost(ctx, msg.SlackTeamID, msg.RepoFullName, msg.PRNumber)
+	if err != nil {
+		return err
+	}
+
+	if err := h.repostPRMessage(ctx, payload, repo, user, msg, moveJob.TargetChannel, moveJob.MovedBy); err != nil {
+		return err
+	}
+
+	h.deleteMigratedMessages(ctx, []*models.TrackedMessage{msg})
+
+	// Carry the review and merged/closed reactions over to the new message
+	if err := h.enqueueReactionSync(ctx, payload); err != nil {
+		log.Warn(ctx, "Failed to enqueue reaction sync for moved PR message", "error", err)
+	}
+
+	log.Info(ctx, "Moved PR notification to another channel")
+	return nil
+}
+
+// loadPRForRepost fetches the current state of a PR, with its repository configuration in a workspace and its author,
+// to post it again or on demand.
+func (h *GitHubHandler) loadPRForRepost(
+	ctx context.Context, teamID, repoFullName string, prNumber int,
+) (*github.PullRequestEvent, *models.Repo, *models.User, error) {
+	repo, err := h.firestoreService.GetRepo(ctx, repoFullName, teamID)
+	if err != nil {
+		log.Error(ctx, "Failed to get repository configuration", "error", err)
+		return nil, nil, nil, err
+	}
+	if repo == nil {
+		return nil, nil, nil, fmt.Errorf("%w for workspace %s, repo %s", models.ErrRepoConfigNotFound, teamID, repoFullName)
+	}
+
+	pr, err := h.githubService.GetPullRequest(ctx, repoFullName, prNumber)
+	if err != nil {
+		log.Error(ctx, "Failed to fetch PR to repost", "error", err)
+		return nil, nil, nil, fmt.Errorf("failed to fetch PR: %w", err)
+	}
+	payload := &github.PullRequestEvent{
+		Action:      github.Ptr(PRActionEdited),
+		Number:      github.Ptr(pr.GetNumber()),
+		PullRequest: pr,
+		Repo:        pr.GetBase().GetRepo(),
+	}
+
+	user, err := h.firestoreService.GetUserByGitHubUserID(ctx, pr.GetUser().GetID())
+	if err != nil {
+		log.Error(ctx, "Failed to look up PR author", "error", err)
+		return nil, nil, nil, err
+	}
+	return payload, repo, user, nil
+}
+
+// repostPRMessage posts and tracks the PR in targetChannel, keeping the old message's CCs. movedBy records who
+// moved it there with the message shortcut, if anyone, so an unchanged channel directive doesn't move it back.
+// Nothing is posted if the channel already has the PR, e.g. when retrying a job that failed to delete the old message.
+func (h *GitHubHandler) repostPRMessage(
+	ctx context.Context, payload *github.PullRequestEvent, repo *models.Repo, user *models.User,
+	msg *models.TrackedMessage, targetChannel, movedBy string,
+) error {
+	isDuplicate, err := h.checkForDuplicateBotMessage(ctx, payload, targetChannel, repo.WorkspaceID)
+	if err != nil {
+		return err
+	}
+	if !isDuplicate {
+		_, directives := h.slackService.ExtractChannelAndDirectives(payload.GetPullRequest().GetBody())
+		directives.UsersToCC = msg.UsersToCC
+		if err := h.postAndTrackPRMessage(ctx, payload, repo, user, targetChannel, "", directives); err != nil {
+			log.Error(ctx, "Failed to repost PR", "error", err, "target_channel", targetChannel)
+			return err
+		}
+	}
+	if movedBy == "" {
+		return nil
+	}
+
+	moved, err := h.firestoreService.GetTrackedMessages(ctx, services.TrackedMessageQuery{
+		RepoFullName:  msg.RepoFullName,
+		PRNumber:      msg.PRNumber,
+		SlackTeamID:   msg.SlackTeamID,
+		SlackChannel:  targetChannel,
+		MessageSource: models.MessageSourceBot,
+	})
+	if err != nil {
+		log.Warn(ctx, "Failed to find moved PR message to record who moved it", "error", err)
+		return nil
+	}
+	for _, movedMsg := range moved {
+		if err := h.firestoreService.UpdateTrackedMessageMovedBy(ctx, movedMsg.ID, movedBy); err != nil {
+			log.Warn(ctx, "Failed to record who moved PR message", "error", err)
+		}
+	}
+	return nil
+}
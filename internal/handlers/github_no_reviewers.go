@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/go-github/v74/github"
+	"github.com/google/uuid"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+	"github-slack-notifier/internal/services"
+)
+
+// prHasNoReviewers reports whether nobody has been asked to review an open PR: no reviewers or teams are requested
+// on GitHub and its description CCs nobody. Drafts aren't expected to have reviewers yet.
+func prHasNoReviewers(pr *github.PullRequest, directives *services.PRDirectives) bool {
+	return pr.GetState() == "open" && !pr.GetDraft() &&
+		len(pr.RequestedReviewers) == 0 && len(pr.RequestedTeams) == 0 && len(directives.UsersToCC) == 0
+}
+
+// scheduleNoReviewersNudge schedules a check of a message posted with the "No reviewers requested" line for when
+// NO_REVIEWERS_NUDGE_AFTER has passed. Failures are logged, since the nudge is a reminder on top of the line.
+func (h *GitHubHandler) scheduleNoReviewersNudge(ctx context.Context, msg *models.TrackedMessage, authorSlackUserID string) {
+	nudgeAfter := h.slackService.NoReviewers().NudgeAfter
+	if nudgeAfter <= 0 {
+		return
+	}
+
+	nudgeJob := &models.NoReviewersNudgeJob{
+		ID:                uuid.New().String(),
+		RepoFullName:      msg.RepoFullName,
+		PRNumber:          msg.PRNumber,
+		SlackTeamID:       msg.SlackTeamID,
+		SlackChannel:      msg.SlackChannel,
+		SlackMessageTS:    msg.SlackMessageTS,
+		AuthorSlackUserID: authorSlackUserID,
+		TraceID:           traceIDForNewJob(ctx),
+	}
+
+	jobPayload, err := json.Marshal(nudgeJob)
+	if err != nil {
+		log.Error(ctx, "Failed to marshal no reviewers nudge job", "error", err)
+		return
+	}
+
+	nudgeAt := time.Now().Add(nudgeAfter)
+	job := &models.Job{
+		ID:        nudgeJob.ID,
+		Type:      models.JobTypeNoReviewersNudge,
+		TraceID:   nudgeJob.TraceID,
+		Payload:   jobPayload,
+		NotBefore: nudgeAt,
+	}
+	if err := h.cloudTasksService.EnqueueJob(ctx, job); err != nil {
+		log.Error(ctx, "Failed to schedule no reviewers nudge", "error", err, "channel", msg.SlackChannel)
+		return
+	}
+
+	log.Info(ctx, "Scheduled no reviewers nudge",
+		"job_id", job.ID,
+		"channel", msg.SlackChannel,
+		"nudge_at", nudgeAt,
+	)
+}
+
+// clearNoReviewersWarning removes the "No reviewers requested" line from a PR's messages once a review is requested.
+// Failures are logged rather than returned, since the line is cosmetic.
+func (h *GitHubHandler) clearNoReviewersWarning(ctx context.Context, repoFullName string, prNumber int) {
+	trackedMessages, err := h.getAllTrackedMessagesForPR(ctx, repoFullName, prNumber)
+	if err != nil {
+		log.Warn(ctx, "Failed to get tracked messages to clear no reviewers line", "error", err)
+		return
+	}
+
+	messagesByTeam := make(map[string][]services.MessageRef)
+	for _, msg := range trackedMessages {
+		if !msg.NoReviewersWarning {
+			continue
+		}
+		if err := h.firestoreService.UpdateTrackedMessageNoReviewersWarning(ctx, msg.ID, false); err != nil {
+			log.Warn(ctx, "Failed to record cleared no reviewers line", "error", err, "message_id", msg.ID)
+		}
+		if !msg.DeletedByUser {
+			messagesByTeam[msg.SlackTeamID] = append(messagesByTeam[msg.SlackTeamID], trackedMessageRef(msg))
+		}
+	}
+
+	for teamID, messageRefs := range messagesByTeam {
+		if err := h.slackService.SetNoReviewersWarningText(ctx, teamID, messageRefs, false); err != nil {
+			log.Warn(ctx, "Failed to remove no reviewers line", "error", err, "team_id", teamID)
+		}
+	}
+}
+
+// ProcessNoReviewersNudgeJob nudges a PR's author in the thread of a message posted without reviewers, if the PR
+// is still open and nobody has been asked to review it since. Nudges mentioning an author in their quiet hours
+// are deferred until they end, and authors who have muted the repository aren't mentioned.
+func (h *GitHubHandler) ProcessNoReviewersNudgeJob(ctx context.Context, job *models.Job) error {
+	var nudgeJob models.NoReviewersNudgeJob
+	if err := json.Unmarshal(job.Payload, &nudgeJob); err != nil {
+		return fmt.Errorf("failed to unmarshal no reviewers nudge job: %w", models.Permanent(err))
+	}
+	if err := nudgeJob.Validate(); err != nil {
+		return fmt.Errorf("invalid no reviewers nudge job: %w", models.Permanent(err))
+	}
+
+	ctx = log.WithFields(ctx, log.LogFields{
+		"repo":          nudgeJob.RepoFullName,
+		"pr_number":     nudgeJob.PRNumber,
+		"slack_team_id": nudgeJob.SlackTeamID,
+		"channel":       nudgeJob.SlackChannel,
+	})
+
+	msg, err := h.firestoreService.GetTrackedMessageBySlackMessage(
+		ctx, nudgeJob.SlackTeamID, nudgeJob.SlackChannel, nudgeJob.SlackMessageTS,
+	)
+	if err != nil {
+		return err
+	}
+	if msg == nil || msg.DeletedByUser || !msg.NoReviewersWarning {
+		log.Debug(ctx, "Skipping no reviewers nudge: message gone or a review was requested")
+		return nil
+	}
+
+	pr, err := h.githubService.GetPullRequest(ctx, nudgeJob.RepoFullName, nudgeJob.PRNumber)
+	if err != nil {
+		return err
+	}
+	if !prHasNoReviewers(pr, h.slackService.ParsePRDirectives(pr.GetBody())) {
+		log.Debug(ctx, "Skipping no reviewers nudge: PR has reviewers or is no longer open")
+		return nil
+	}
+
+	text := "This PR has no reviewers yet. Request a review on GitHub, " +
+		"or CC someone with a `!review @username` line in the description."
+	if nudgeJob.AuthorSlackUserID != "" {
+		author, err := h.firestoreService.GetUserBySlackID(ctx, nudgeJob.AuthorSlackUserID)
+		if err != nil {
+			log.Warn(ctx, "Failed to look up PR author for quiet hours", "error", err)
+		}
+		// Authors who have muted the repository still get the nudge in the thread, without the mention
+		if !author.RepoMuted(nudgeJob.RepoFullName, time.Now()) {
+			text = fmt.Sprintf("<@%s> %s", nudgeJob.AuthorSlackUserID, text)
+			if author != nil && h.deferForQuietHours(ctx, author, nudgeJob.SlackChannel, nudgeJob.SlackMessageTS, text) {
+				return nil
+			}
+		}
+	}
+
+	if _, err := h.slackService.PostThreadReply(
+		ctx, nudgeJob.SlackTeamID, nudgeJob.SlackChannel, nudgeJob.SlackMessageTS, text,
+	); err != nil {
+		log.Error(ctx, "Failed to post no reviewers nudge", "error", err)
+		return err
+	}
+
+	log.Info(ctx, "Nudged PR author about missing reviewers")
+	return nil
+}
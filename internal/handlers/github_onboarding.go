@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"context"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/utils"
+)
+
+// sendOnboardingHint shows the PR author an ephemeral hint explaining the message's reactions and delete gesture,
+// once per user per channel. Failures are logged, as the hint is supplementary to the notification.
+func (h *GitHubHandler) sendOnboardingHint(ctx context.Context, teamID, channel, slackUserID string) {
+	claimed, err := h.firestoreService.ClaimOnboardingHint(ctx, teamID, channel, slackUserID)
+	if err != nil {
+		log.Warn(ctx, "Failed to check onboarding hint", "error", err)
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	hint := utils.FormatOnboardingHint(h.slackService.EmojiConfig(ctx, teamID))
+	if err := h.slackService.SendEphemeralMessage(ctx, teamID, channel, slackUserID, hint); err != nil {
+		log.Warn(ctx, "Failed to send onboarding hint", "error", err)
+		// Show the hint next time instead
+		if err := h.firestoreService.ReleaseOnboardingHint(ctx, teamID, channel, slackUserID); err != nil {
+			log.Warn(ctx, "Failed to release onboarding hint", "error", err)
+		}
+		return
+	}
+
+	log.Info(ctx, "Sent onboarding hint", "channel", channel, "slack_user_id", slackUserID)
+}
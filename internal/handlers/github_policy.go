@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/go-github/v74/github"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/policy"
+	"github-slack-notifier/internal/services"
+)
+
+// applyNotificationPolicy evaluates the workspace's notification policy, if any, before a PR is posted.
+// Returns the annotated channel and directives to use, and whether the notification should be skipped.
+// Policy failures fall back to the built-in rules so a broken policy never drops notifications.
+func (h *GitHubHandler) applyNotificationPolicy(
+	ctx context.Context, payload *github.PullRequestEvent, workspaceID, annotatedChannel string,
+	directives *services.PRDirectives,
+	trace *routingTrace,
+) (string, *services.PRDirectives, bool) {
+	if h.policyEngine == nil {
+		return annotatedChannel, directives, false
+	}
+
+	notificationPolicy, err := h.firestoreService.GetNotificationPolicy(ctx, workspaceID)
+	if err != nil {
+		log.Warn(ctx, "Failed to get notification policy, using built-in rules", "error", err)
+		trace.add("Notification policy couldn't be loaded (%v), using built-in rules", err)
+		return annotatedChannel, directives, false
+	}
+	if notificationPolicy == nil || notificationPolicy.IsEmpty() {
+		trace.add("No notification policy configured")
+		return annotatedChannel, directives, false
+	}
+
+	compiled, err := h.policyEngine.Get(notificationPolicy)
+	if err != nil {
+		log.Error(ctx, "Stored notification policy doesn't compile, using built-in rules", "error", err)
+		trace.add("Notification policy doesn't compile (%v), using built-in rules", err)
+		return annotatedChannel, directives, false
+	}
+
+	decision, err := compiled.Evaluate(h.buildPolicyInput(ctx, payload, compiled.UsesFiles()))
+	if err != nil {
+		log.Error(ctx, "Failed to evaluate notification policy, using built-in rules", "error", err)
+		trace.add("Notification policy failed to evaluate (%v), using built-in rules", err)
+		return annotatedChannel, directives, false
+	}
+
+	log.Info(ctx, "Evaluated notification policy",
+		"skip", decision.Skip,
+		"channel", decision.Channel,
+		"cc", decision.CC,
+		"emoji", decision.Emoji,
+	)
+
+	if decision.Skip {
+		log.Info(ctx, "Skipping PR notification due to workspace notification policy")
+		trace.add("Skipped by the notification policy's skip expression")
+		return annotatedChannel, directives, true
+	}
+
+	if decision.Channel != "" {
+		trace.add("Notification policy routes to #%s", decision.Channel)
+		annotatedChannel = decision.Channel
+	}
+
+	if len(decision.CC) > 0 || decision.Emoji != "" {
+		// Copy so the policy's changes don't leak into other workspaces sharing the directives
+		modified := *directives
+		modified.UsersToCC = slices.Clone(directives.UsersToCC)
+		for _, username := range decision.CC {
+			if !slices.Contains(modified.UsersToCC, username) {
+				modified.UsersToCC = append(modified.UsersToCC, username)
+			}
+		}
+		if decision.Emoji != "" {
+			modified.CustomEmoji = decision.Emoji
+		}
+		directives = &modified
+		trace.add("Notification policy adds CC %v and emoji %q", decision.CC, decision.Emoji)
+	}
+
+	return annotatedChannel, directives, false
+}
+
+// buildPolicyInput collects the PR attributes a policy can reference.
+// The changed file list is only fetched when the policy uses it.
+func (h *GitHubHandler) buildPolicyInput(ctx context.Context, payload *github.PullRequestEvent, withFiles bool) *policy.Input {
+	pr := payload.GetPullRequest()
+
+	labels := make([]string, 0, len(pr.Labels))
+	for _, label := range pr.Labels {
+		labels = append(labels, label.GetName())
+	}
+
+	input := &policy.Input{
+		Repo:         payload.GetRepo().GetFullName(),
+		Author:       pr.GetUser().GetLogin(),
+		Title:        pr.GetTitle(),
+		BaseBranch:   pr.GetBase().GetRef(),
+		Labels:       labels,
+		Additions:    pr.GetAdditions(),
+		Deletions:    pr.GetDeletions(),
+		ChangedFiles: pr.GetChangedFiles(),
+		Draft:        pr.GetDraft(),
+		Time:         time.Now(),
+	}
+
+	if withFiles {
+		files, err := h.githubService.ListPullRequestFiles(ctx, input.Repo, pr.GetNumber())
+		if err != nil {
+			log.Warn(ctx, "Failed to list PR files for notification policy, evaluating with no files", "error", err)
+		}
+		input.Files = files
+	}
+
+	return input
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+)
+
+// ProcessPostPRJob posts a PR's notification on demand, as requested with the "Post a PR" shortcut.
+// The PR is fetched from GitHub and posted as if it had just been opened, with the CCs picked in the form
+// replacing the description's. The requester is told with an ephemeral message if it can't be posted.
+func (h *GitHubHandler) ProcessPostPRJob(ctx context.Context, job *models.Job) error {
+	var postJob models.PostPRJob
+	if err := json.Unmarshal(job.Payload, &postJob); err != nil {
+		return fmt.Errorf("failed to unmarshal post PR job: %w", models.Permanent(err))
+	}
+	if err := postJob.Validate(); err != nil {
+		return fmt.Errorf("invalid post PR job: %w", models.Permanent(err))
+	}
+
+	ctx = log.WithFields(ctx, log.LogFields{
+		"repo":         postJob.RepoFullName,
+		"pr_number":    postJob.PRNumber,
+		"team_id":      postJob.SlackTeamID,
+		"channel":      postJob.SlackChannel,
+		"requested_by": postJob.RequestedBy,
+	})
+
+	payload, repo, user, err := h.loadPRForRepost(ctx, postJob.SlackTeamID, postJob.RepoFullName, postJob.PRNumber)
+	if errors.Is(err, models.ErrRepoConfigNotFound) {
+		h.explainPostPRFailure(ctx, &postJob, "isn't in a repository set up for PR notifications in this workspace")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if payload.GetPullRequest().GetState() != "open" {
+		h.explainPostPRFailure(ctx, &postJob, "is already closed")
+		return nil
+	}
+
+	isDuplicate, err := h.checkForDuplicateBotMessage(ctx, payload, postJob.SlackChannel, postJob.SlackTeamID)
+	if err != nil {
+		return err
+	}
+	if isDuplicate {
+		h.explainPostPRFailure(ctx, &postJob, "is already posted in this channel")
+		return nil
+	}
+
+	_, directives := h.slackService.ExtractChannelAndDirectives(payload.GetPullRequest().GetBody())
+	if len(postJob.UsersToCC) > 0 {
+		directives.UsersToCC = postJob.UsersToCC
+	}
+	if err := h.postAndTrackPRMessage(ctx, payload, repo, user, postJob.SlackChannel, "", directives); err != nil {
+		log.Error(ctx, "Failed to post PR on demand", "error", err)
+		return err
+	}
+
+	if err := h.enqueueReactionSync(ctx, payload); err != nil {
+		log.Warn(ctx, "Failed to enqueue reaction sync for PR posted on demand", "error", err)
+	}
+
+	log.Info(ctx, "Posted PR notification on demand")
+	return nil
+}
+
+// explainPostPRFailure tells the user who used the "Post a PR" shortcut why the PR wasn't posted, with an
+// ephemeral message in the channel they picked. Failures are logged, as the job has nothing left to do.
+func (h *GitHubHandler) explainPostPRFailure(ctx context.Context, postJob *models.PostPRJob, reason string) {
+	log.Info(ctx, "PR not posted on demand", "reason", reason)
+
+	text := fmt.Sprintf("%s#%d wasn't posted: it %s.", postJob.RepoFullName, postJob.PRNumber, reason)
+	if err := h.slackService.SendEphemeralMessage(ctx, postJob.SlackTeamID, postJob.SlackChannel, postJob.RequestedBy, text); err != nil {
+		log.Warn(ctx, "Failed to explain why the PR wasn't posted", "error", err)
+	}
+}
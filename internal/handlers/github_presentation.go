@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/google/go-github/v74/github"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+	"github-slack-notifier/internal/presentation"
+)
+
+// syncPresentation re-resolves the presentation of a PR's bot messages after one of the PR's states changed,
+// e.g. CI failing on a draft, and re-renders the messages whose presentation changed.
+// update records the changed state on each message before it's resolved. Messages of closed PRs in channels
+// that archive closed PRs are condensed to a struck-through line, and re-rendered in full if the PR is reopened.
+// Failures are logged rather than returned, since the presentation is cosmetic.
+func (h *GitHubHandler) syncPresentation(
+	ctx context.Context, repoFullName string, pr *github.PullRequest,
+	trackedMessages []*models.TrackedMessage, update func(msg *models.TrackedMessage),
+) {
+	renderer := &presentationRenderer{h: h, repoFullName: repoFullName, pr: pr}
+	archivingChannels := make(map[string]bool)
+
+	for _, msg := range trackedMessages {
+		if msg.MessageSource != models.MessageSourceBot || msg.DeletedByUser {
+			continue
+		}
+
+		updated := *msg
+		update(&updated)
+		updated.Presentation = h.presentations.Resolve(presentation.State{
+			Closed:    pr.GetState() == "closed",
+			Draft:     pr.GetDraft(),
+			CIState:   updated.CIState,
+			Approvals: updated.Approvals,
+		})
+		if pr.GetState() == "closed" && h.channelArchivesClosedPRs(ctx, msg, archivingChannels) {
+			updated.Presentation = models.PresentationArchived
+		}
+
+		if updated.Presentation != msg.Presentation {
+			if err := renderer.render(ctx, &updated); err != nil {
+				log.Error(ctx, "Failed to render message presentation",
+					"error", err,
+					"channel", msg.SlackChannel,
+					"message_ts", msg.SlackMessageTS,
+					"presentation", updated.Presentation,
+				)
+				updated.Presentation = msg.Presentation
+			} else {
+				log.Info(ctx, "Changed message presentation",
+					"channel", msg.SlackChannel,
+					"message_ts", msg.SlackMessageTS,
+					"from", msg.Presentation,
+					"to", updated.Presentation,
+				)
+			}
+		}
+
+		if updated.CIState == msg.CIState && updated.Approvals == msg.Approvals &&
+			updated.RequiredApprovals == msg.RequiredApprovals && updated.Presentation == msg.Presentation {
+			continue
+		}
+		if err := h.firestoreService.UpdateTrackedMessagePresentation(ctx, &updated); err != nil {
+			log.Warn(ctx, "Failed to record message presentation", "error", err, "message_id", msg.ID)
+			continue
+		}
+		*msg = updated
+	}
+}
+
+// channelArchivesClosedPRs returns whether the message's channel condenses messages of closed PRs, caching
+// the answer per channel in archiving. Lookup failures are logged and keep messages already archived that way.
+func (h *GitHubHandler) channelArchivesClosedPRs(ctx context.Context, msg *models.TrackedMessage, archiving map[string]bool) bool {
+	key := msg.SlackTeamID + "#" + msg.SlackChannel
+	if archives, ok := archiving[key]; ok {
+		return archives
+	}
+
+	channelConfig, err := h.firestoreService.GetChannelConfig(ctx, msg.SlackTeamID, msg.SlackChannel)
+	if err != nil {
+		log.Warn(ctx, "Failed to get channel config for archiving closed PRs", "error", err, "channel", msg.SlackChannel)
+		return msg.Presentation == models.PresentationArchived
+	}
+	archiving[key] = channelConfig.ArchivesClosedPRs()
+	return archiving[key]
+}
+
+// presentationRenderer re-renders PR messages in a new presentation.
+// The PR's details and author are looked up once, when the first message needs re-rendering.
+type presentationRenderer struct {
+	h            *GitHubHandler
+	repoFullName string
+	pr           *github.PullRequest
+	loaded       bool
+	user         *models.User
+}
+
+// render updates the message in Slack using its resolved presentation.
+func (r *presentationRenderer) render(ctx context.Context, msg *models.TrackedMessage) error {
+	if !r.loaded {
+		// PRs listed for a commit lack the size and body details, so fetch the full PR
+		if r.pr.Additions == nil {
+			pr, err := r.h.githubService.GetPullRequest(ctx, r.repoFullName, r.pr.GetNumber())
+			if err != nil {
+				return err
+			}
+			r.pr = pr
+		}
+		if authorID := r.pr.GetUser().GetID(); authorID > 0 {
+			user, err := r.h.firestoreService.GetUserByGitHubUserID(ctx, authorID)
+			if err != nil {
+				log.Warn(ctx, "Failed to look up PR author for message presentation", "error", err)
+			}
+			r.user = user
+		}
+		r.loaded = true
+	}
+
+	payload := &github.PullRequestEvent{
+		PullRequest: r.pr,
+		Repo:        &github.Repository{FullName: github.Ptr(r.repoFullName)},
+	}
+	directives := r.h.slackService.ParsePRDirectives(r.pr.GetBody())
+	prSize := r.h.prSize(ctx, r.repoFullName, r.pr)
+	return r.h.updateSingleMessageForPRChanges(ctx, payload, msg, directives, r.user, prSize)
+}
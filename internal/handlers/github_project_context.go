@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/go-github/v74/github"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+	"github-slack-notifier/internal/services"
+	"github-slack-notifier/internal/utils"
+)
+
+// projectColumnFieldName is the project field treated as the board column.
+// GitHub Projects board views group by the built-in Status field by default.
+const projectColumnFieldName = "Status"
+
+// Project item actions that affect the board column.
+const (
+	ProjectItemActionEdited   = "edited"
+	ProjectItemActionArchived = "archived"
+	ProjectItemActionDeleted  = "deleted"
+)
+
+// projectItemContentTypePR is the content type of project items backed by pull requests.
+const projectItemContentTypePR = "PullRequest"
+
+// projectsV2ItemEvent is the subset of a projects_v2_item webhook payload used for board column tracking.
+// go-github doesn't model the field value changes, so the payload is decoded directly.
+type projectsV2ItemEvent struct {
+	Action string `json:"action"`
+	Item   struct {
+		ContentNodeID string `json:"content_node_id"`
+		ContentType   string `json:"content_type"`
+	} `json:"projects_v2_item"`
+	Changes struct {
+		FieldValue *struct {
+			FieldName string          `json:"field_name"`
+			To        json.RawMessage `json:"to"`
+		} `json:"field_value"`
+	} `json:"changes"`
+	Installation struct {
+		ID int64 `json:"id"`
+	} `json:"installation"`
+}
+
+// validateProjectsV2ItemPayload validates projects_v2_item webhook payload structure.
+// Project items belong to an organization rather than a repository, so the installation is required instead.
+func (h *GitHubHandler) validateProjectsV2ItemPayload(payload []byte) error {
+	var itemPayload map[string]interface{}
+	if err := json.Unmarshal(payload, &itemPayload); err != nil {
+		return fmt.Errorf("invalid JSON payload: %w", err)
+	}
+
+	if _, exists := itemPayload["action"]; !exists {
+		return ErrMissingAction
+	}
+
+	if _, exists := itemPayload["installation"]; !exists {
+		return ErrMissingInstallation
+	}
+
+	return nil
+}
+
+// processProjectsV2ItemEvent processes projects_v2_item webhook events.
+// When a PR moves between board columns, or leaves the board, its messages are annotated with the new column.
+func (h *GitHubHandler) processProjectsV2ItemEvent(ctx context.Context, payload []byte) error {
+	var event projectsV2ItemEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		log.Error(ctx, "Failed to unmarshal projects v2 item payload",
+			"error", err,
+			"payload_size", len(payload),
+		)
+		return fmt.Errorf("failed to unmarshal projects v2 item payload: %w", models.Permanent(err))
+	}
+
+	if event.Item.ContentType != projectItemContentTypePR {
+		return nil
+	}
+
+	column, changed := projectColumnChange(&event)
+	if !changed {
+		return nil
+	}
+
+	node, err := h.githubService.ResolvePullRequestNode(ctx, event.Installation.ID, event.Item.ContentNodeID)
+	if err != nil {
+		log.Error(ctx, "Failed to resolve pull request for project item",
+			"error", err,
+			"content_node_id", event.Item.ContentNodeID,
+		)
+		return err
+	}
+
+	ctx = log.WithFields(ctx, log.LogFields{
+		"repo":           node.RepoFullName,
+		"pr_number":      node.Number,
+		"project_column": column,
+	})
+	log.Info(ctx, "Pull request moved on project board", "project_item_action", event.Action)
+
+	return h.updateProjectContext(ctx, node.RepoFullName, node.Number, node.Milestone, &column)
+}
+
+// projectColumnChange returns the new board column for a project item event, and whether the column changed.
+// Archiving or removing the item clears the column.
+func projectColumnChange(event *projectsV2ItemEvent) (string, bool) {
+	switch event.Action {
+	case ProjectItemActionArchived, ProjectItemActionDeleted:
+		return "", true
+	case ProjectItemActionEdited:
+		fieldValue := event.Changes.FieldValue
+		if fieldValue == nil || fieldValue.FieldName != projectColumnFieldName {
+			return "", false
+		}
+
+		// Single select values are objects with a name; a null value means the column was cleared
+		var to struct {
+			Name string `json:"name"`
+		}
+		if len(fieldValue.To) > 0 && json.Unmarshal(fieldValue.To, &to) != nil {
+			return "", false
+		}
+		return to.Name, true
+	default:
+		return "", false
+	}
+}
+
+// handlePRMilestoneChanged handles PR milestoned and demilestoned events.
+// Updates the milestone shown next to label chips, then on messages in channels with project context enabled.
+func (h *GitHubHandler) handlePRMilestoneChanged(ctx context.Context, payload *github.PullRequestEvent) error {
+	if err := h.refreshPRMetadata(ctx, payload); err != nil {
+		return err
+	}
+
+	return h.updateProjectContext(ctx, payload.GetRepo().GetFullName(), payload.GetPullRequest().GetNumber(), prMilestone(payload), nil)
+}
+
+// updateProjectContext refreshes the milestone and board column line on a PR's tracked messages.
+// A nil column keeps each message's stored column. Only channels with project context enabled are edited,
+// but a changed column is stored for every message so enabling the setting later picks it up.
+func (h *GitHubHandler) updateProjectContext(
+	ctx context.Context, repoFullName string, prNumber int, milestone string, column *string,
+) error {
+	trackedMessages, err := h.getAllTrackedMessagesForPR(ctx, repoFullName, prNumber)
+	if err != nil {
+		log.Error(ctx, "Failed to get tracked messages for project context", "error", err)
+		return err
+	}
+
+	configCache := make(map[string]*models.ChannelConfig)
+	for _, msg := range trackedMessages {
+		if msg.DeletedByUser || msg.Compact {
+			continue
+		}
+
+		if column != nil && *column != msg.ProjectColumn {
+			if err := h.firestoreService.UpdateTrackedMessageProjectColumn(ctx, msg.ID, *column); err != nil {
+				continue
+			}
+			msg.ProjectColumn = *column
+		}
+
+		cacheKey := msg.SlackTeamID + "#" + msg.SlackChannel
+		channelConfig, cached := configCache[cacheKey]
+		if !cached {
+			channelConfig, err = h.firestoreService.GetChannelConfig(ctx, msg.SlackTeamID, msg.SlackChannel)
+			if err != nil {
+				log.Warn(ctx, "Failed to get channel config for project context",
+					"error", err,
+					"team_id", msg.SlackTeamID,
+					"channel", msg.SlackChannel,
+				)
+			}
+			configCache[cacheKey] = channelConfig
+		}
+		if channelConfig == nil || !channelConfig.ProjectContext {
+			continue
+		}
+
+		line := utils.FormatProjectContext(milestone, msg.ProjectColumn)
+		err := h.slackService.SetProjectContextText(ctx, msg.SlackTeamID, []services.MessageRef{trackedMessageRef(msg)}, line)
+		if err != nil {
+			log.Warn(ctx, "Failed to update project context line",
+				"error", err,
+				"team_id", msg.SlackTeamID,
+				"channel", msg.SlackChannel,
+			)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+)
+
+// deferForQuietHours schedules a DM to a user, or a thread note mentioning them when channel and threadTS are set,
+// for when their quiet hours end. Reports whether it was deferred: it isn't outside quiet hours, and if it can't
+// be scheduled it's left to be sent now rather than lost.
+func (h *GitHubHandler) deferForQuietHours(ctx context.Context, user *models.User, channel, threadTS, text string) bool {
+	endsAt, quiet := user.QuietHours.EndsAt(time.Now())
+	if !quiet {
+		return false
+	}
+
+	deferred := &models.DeferredNotificationJob{
+		ID:           uuid.New().String(),
+		SlackTeamID:  user.SlackTeamID,
+		SlackUserID:  user.SlackUserID,
+		SlackChannel: channel,
+		ThreadTS:     threadTS,
+		Text:         text,
+		TraceID:      traceIDForNewJob(ctx),
+	}
+	if err := h.enqueueDeferredNotification(ctx, deferred, endsAt); err != nil {
+		log.Error(ctx, "Failed to defer notification for quiet hours, sending it now",
+			"error", err,
+			"slack_user_id", user.SlackUserID,
+		)
+		return false
+	}
+
+	log.Info(ctx, "Deferred notification until quiet hours end",
+		"job_id", deferred.ID,
+		"slack_user_id", user.SlackUserID,
+		"channel", channel,
+		"deliver_at", endsAt,
+	)
+	return true
+}
+
+// userInQuietHours reports whether a Slack user is in their quiet hours at now.
+// Lookup failures are logged and treated as not quiet, so notifications aren't held back because of them.
+func (h *GitHubHandler) userInQuietHours(ctx context.Context, slackUserID string, now time.Time) bool {
+	user, err := h.firestoreService.GetUserBySlackID(ctx, slackUserID)
+	if err != nil {
+		log.Warn(ctx, "Failed to look up user for quiet hours", "error", err)
+		return false
+	}
+	if user == nil {
+		return false
+	}
+	_, quiet := user.QuietHours.EndsAt(now)
+	return quiet
+}
+
+// enqueueDeferredNotification schedules a deferred notification job to run at the given time.
+func (h *GitHubHandler) enqueueDeferredNotification(ctx context.Context, deferred *models.DeferredNotificationJob, at time.Time) error {
+	jobPayload, err := json.Marshal(deferred)
+	if err != nil {
+		return fmt.Errorf("failed to marshal deferred notification job: %w", err)
+	}
+
+	job := &models.Job{
+		ID:        deferred.ID,
+		Type:      models.JobTypeDeferredNotification,
+		TraceID:   deferred.TraceID,
+		Payload:   jobPayload,
+		NotBefore: at,
+	}
+	if err := h.cloudTasksService.EnqueueJob(ctx, job); err != nil {
+		return fmt.Errorf("failed to enqueue deferred notification job: %w", err)
+	}
+	return nil
+}
+
+// ProcessDeferredNotificationJob delivers a DM or thread note held back for a user's quiet hours.
+// If the user has since moved their quiet hours so they're still in them, it's deferred again.
+func (h *GitHubHandler) ProcessDeferredNotificationJob(ctx context.Context, job *models.Job) error {
+	var deferred models.DeferredNotificationJob
+	if err := json.Unmarshal(job.Payload, &deferred); err != nil {
+		return fmt.Errorf("failed to unmarshal deferred notification job: %w", models.Permanent(err))
+	}
+	if err := deferred.Validate(); err != nil {
+		return fmt.Errorf("invalid deferred notification job: %w", models.Permanent(err))
+	}
+
+	ctx = log.WithFields(ctx, log.LogFields{
+		"slack_team_id": deferred.SlackTeamID,
+		"slack_user_id": deferred.SlackUserID,
+		"channel":       deferred.SlackChannel,
+	})
+
+	user, err := h.firestoreService.GetUserBySlackID(ctx, deferred.SlackUserID)
+	if err != nil {
+		log.Error(ctx, "Failed to look up user for deferred notification", "error", err)
+		return fmt.Errorf("failed to look up user for deferred notification: %w", err)
+	}
+	if user != nil {
+		if endsAt, quiet := user.QuietHours.EndsAt(time.Now()); quiet {
+			deferred.ID = uuid.New().String()
+			if err := h.enqueueDeferredNotification(ctx, &deferred, endsAt); err != nil {
+				log.Error(ctx, "Failed to defer notification again", "error", err)
+				return err
+			}
+			log.Info(ctx, "Quiet hours changed, deferred notification again", "deliver_at", endsAt)
+			return nil
+		}
+	}
+
+	if deferred.SlackChannel == "" {
+		_, err = h.slackService.PostMessage(ctx, deferred.SlackTeamID, deferred.SlackUserID, deferred.Text)
+	} else {
+		_, err = h.slackService.PostThreadReply(ctx, deferred.SlackTeamID, deferred.SlackChannel, deferred.ThreadTS, deferred.Text)
+	}
+	if err != nil {
+		log.Error(ctx, "Failed to deliver deferred notification", "error", err)
+		return fmt.Errorf("failed to deliver deferred notification: %w", err)
+	}
+
+	log.Info(ctx, "Delivered deferred notification")
+	return nil
+}
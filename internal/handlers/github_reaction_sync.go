@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/google/go-github/v74/github"
 
@@ -14,16 +15,17 @@ import (
 )
 
 // ProcessReactionSyncJob processes a reaction sync job from the job system.
-// Fetches PR details from GitHub, gets tracked messages, and syncs emoji reactions based on current state.
+// Fetches PR details from GitHub, gets tracked messages, and syncs emoji reactions and message presentation
+// based on current state. PR syncs already covered by a later sync are skipped.
 func (h *GitHubHandler) ProcessReactionSyncJob(ctx context.Context, job *models.Job) error {
 	var reactionSyncJob models.ReactionSyncJob
 	if err := json.Unmarshal(job.Payload, &reactionSyncJob); err != nil {
-		return fmt.Errorf("failed to unmarshal reaction sync job: %w", err)
+		return fmt.Errorf("failed to unmarshal reaction sync job: %w", models.Permanent(err))
 	}
 
 	// Validate the reaction sync job
 	if err := reactionSyncJob.Validate(); err != nil {
-		return fmt.Errorf("invalid reaction sync job: %w", err)
+		return fmt.Errorf("invalid reaction sync job: %w", models.Permanent(err))
 	}
 
 	ctx = log.WithFields(ctx, log.LogFields{
@@ -34,8 +36,29 @@ func (h *GitHubHandler) ProcessReactionSyncJob(ctx context.Context, job *models.
 
 	log.Debug(ctx, "Processing reaction sync job")
 
+	if reactionSyncJob.ItemType == models.TrackedItemTypeIssue {
+		return h.syncIssueReactions(ctx, reactionSyncJob.RepoFullName, reactionSyncJob.PRNumber)
+	}
+
+	// Bursts of review activity enqueue a sync per event; one that started since this was requested covers it
+	startedAt := time.Now()
+	if !h.claimReactionSync(ctx, &reactionSyncJob, startedAt) {
+		return nil
+	}
+	err := h.syncPRReactions(ctx, &reactionSyncJob)
+	h.releaseReactionSync(ctx, &reactionSyncJob, startedAt, err == nil)
+	return err
+}
+
+// syncPRReactions syncs the reactions and message presentation of a PR's tracked messages with its current state.
+func (h *GitHubHandler) syncPRReactions(ctx context.Context, reactionSyncJob *models.ReactionSyncJob) error {
+	// Review bots can trigger dozens of syncs; past the PR's budget they wait for the reconciliation pass
+	if !reactionSyncJob.Reconcile && !h.spendPRUpdateBudget(ctx, reactionSyncJob.RepoFullName, reactionSyncJob.PRNumber) {
+		return nil
+	}
+
 	// Fetch PR details and current review state from GitHub
-	pr, currentReviewState, err := h.githubService.GetPullRequestWithReviews(
+	pr, reviewSummary, err := h.githubService.GetPullRequestReviewSummary(
 		ctx, reactionSyncJob.RepoFullName, reactionSyncJob.PRNumber,
 	)
 	if err != nil {
@@ -55,30 +78,135 @@ func (h *GitHubHandler) ProcessReactionSyncJob(ctx context.Context, job *models.
 		return nil
 	}
 
-	// Convert tracked messages to message refs and group by team
-	messagesByTeam := h.groupMessagesByTeam(trackedMessages)
+	// Closed PRs keep the approval count they were last shown with
+	quorum := h.slackService.ApprovalQuorumEnabled() && pr.GetState() == "open"
+	var required int
+	var quorumChanged []*models.TrackedMessage
+	if quorum {
+		required = h.requiredApprovals(ctx, reactionSyncJob.RepoFullName, pr, reviewSummary)
+		quorumChanged = approvalQuorumChanges(trackedMessages, reviewSummary.Approvals, required)
+	}
+
+	// Re-render messages whose presentation changed first, since re-rendering replaces any lifecycle state text
+	h.syncPresentation(ctx, reactionSyncJob.RepoFullName, pr, trackedMessages, func(msg *models.TrackedMessage) {
+		msg.Approvals = reviewSummary.Approvals
+		if quorum {
+			msg.RequiredApprovals = required
+		}
+	})
+	if quorum {
+		h.syncApprovalQuorum(ctx, quorumChanged, reviewSummary.Approvals, required)
+	}
+
+	// Catch up on edits dropped while the update budget was spent, before lifecycle state text is applied
+	if reactionSyncJob.Reconcile {
+		h.reconcilePRContent(ctx, reactionSyncJob.RepoFullName, pr, trackedMessages)
+	}
+
+	// Convert tracked messages to message refs and group by team, honoring per-channel reaction sets
+	targets := h.resolveReactionTargets(ctx, trackedMessages)
 
 	// Sync reactions based on current PR state
-	return h.syncReactions(ctx, pr, currentReviewState, messagesByTeam, trackedMessages)
+	return h.syncReactions(ctx, pr, reviewSummary.State, targets, trackedMessages)
+}
+
+// trackedMessageRef returns the Slack message reference for a tracked message.
+func trackedMessageRef(msg *models.TrackedMessage) services.MessageRef {
+	return services.MessageRef{
+		Channel:   msg.SlackChannel,
+		Timestamp: msg.SlackMessageTS,
+		PostedBy:  msg.PostedAsUserID,
+	}
 }
 
 // groupMessagesByTeam groups tracked messages by Slack team ID for team-scoped API calls.
 // Converts tracked messages to MessageRef format and organizes by team.
 func (h *GitHubHandler) groupMessagesByTeam(trackedMessages []*models.TrackedMessage) map[string][]services.MessageRef {
-	messageRefs := make([]services.MessageRef, len(trackedMessages))
-	for i, msg := range trackedMessages {
-		messageRefs[i] = services.MessageRef{
-			Channel:   msg.SlackChannel,
-			Timestamp: msg.SlackMessageTS,
+	messagesByTeam := make(map[string][]services.MessageRef)
+	for _, msg := range trackedMessages {
+		messagesByTeam[msg.SlackTeamID] = append(messagesByTeam[msg.SlackTeamID], trackedMessageRef(msg))
+	}
+
+	return messagesByTeam
+}
+
+// reactionTargets holds tracked message refs grouped by team, split by each channel's reaction set.
+type reactionTargets struct {
+	all      map[string][]services.MessageRef // Every tracked message (used for reaction removal)
+	review   map[string][]services.MessageRef // Channels that receive review state reactions
+	prState  map[string][]services.MessageRef // Channels that receive merged/closed reactions
+	textEdit map[string][]services.MessageRef // Channels that show lifecycle state as message text
+}
+
+// resolveReactionTargets looks up the channel config for each tracked message and groups
+// message refs by which lifecycle reactions the channel allows.
+// Channel config lookup failures fall back to the default reaction set.
+func (h *GitHubHandler) resolveReactionTargets(ctx context.Context, trackedMessages []*models.TrackedMessage) *reactionTargets {
+	targets := &reactionTargets{
+		all:      h.groupMessagesByTeam(trackedMessages),
+		review:   make(map[string][]services.MessageRef),
+		prState:  make(map[string][]services.MessageRef),
+		textEdit: make(map[string][]services.MessageRef),
+	}
+
+	configCache := make(map[string]*models.ChannelConfig)
+	for _, msg := range trackedMessages {
+		ref := trackedMessageRef(msg)
+		if msg.Compact {
+			// Compact messages never get reactions, regardless of the channel's reaction set
+			targets.textEdit[msg.SlackTeamID] = append(targets.textEdit[msg.SlackTeamID], ref)
+			continue
+		}
+
+		cacheKey := msg.SlackTeamID + "#" + msg.SlackChannel
+		channelConfig, cached := configCache[cacheKey]
+		if !cached {
+			var err error
+			channelConfig, err = h.firestoreService.GetChannelConfig(ctx, msg.SlackTeamID, msg.SlackChannel)
+			if err != nil {
+				log.Warn(ctx, "Failed to get channel config for reactions, using defaults",
+					"error", err,
+					"team_id", msg.SlackTeamID,
+					"channel", msg.SlackChannel,
+				)
+			}
+			configCache[cacheKey] = channelConfig
+		}
+
+		if channelConfig.ReviewReactionsEnabled() {
+			targets.review[msg.SlackTeamID] = append(targets.review[msg.SlackTeamID], ref)
+		}
+		if channelConfig.PRStateReactionsEnabled() {
+			targets.prState[msg.SlackTeamID] = append(targets.prState[msg.SlackTeamID], ref)
+		}
+		if channelConfig.UsesTextLifecycleState() {
+			targets.textEdit[msg.SlackTeamID] = append(targets.textEdit[msg.SlackTeamID], ref)
 		}
 	}
 
-	messagesByTeam := make(map[string][]services.MessageRef)
-	for i, msg := range trackedMessages {
-		messagesByTeam[msg.SlackTeamID] = append(messagesByTeam[msg.SlackTeamID], messageRefs[i])
+	return targets
+}
+
+// lifecycleStateLabel returns the text label shown for a closed PR in channels without reactions.
+func lifecycleStateLabel(merged bool) string {
+	if merged {
+		return "merged"
 	}
+	return "closed"
+}
 
-	return messagesByTeam
+// applyLifecycleStateText edits messages in text-only channels to show the given lifecycle state.
+func (h *GitHubHandler) applyLifecycleStateText(ctx context.Context, targets *reactionTargets, state string) {
+	for teamID, teamMessageRefs := range targets.textEdit {
+		err := h.slackService.SetLifecycleStateText(ctx, teamID, teamMessageRefs, state)
+		if err != nil {
+			log.Error(ctx, "Failed to set lifecycle state text",
+				"error", err,
+				"team_id", teamID,
+				"state", state,
+			)
+		}
+	}
 }
 
 // syncReactions syncs emoji reactions for pull requests based on current state.
@@ -86,14 +214,14 @@ func (h *GitHubHandler) groupMessagesByTeam(trackedMessages []*models.TrackedMes
 // For closed PRs: syncs review reactions, then adds closed/merged emoji.
 func (h *GitHubHandler) syncReactions(
 	ctx context.Context, pr *github.PullRequest, currentReviewState string,
-	messagesByTeam map[string][]services.MessageRef, trackedMessages []*models.TrackedMessage,
+	targets *reactionTargets, trackedMessages []*models.TrackedMessage,
 ) error {
 	isClosed := pr.GetState() == "closed"
 
-	for teamID, teamMessageRefs := range messagesByTeam {
+	for teamID, teamMessageRefs := range targets.all {
 		if isClosed {
 			// For closed PRs: sync review reactions, then add closed/merged emoji
-			err := h.slackService.SyncReviewReactions(ctx, teamID, teamMessageRefs, currentReviewState)
+			err := h.slackService.SyncReviewReactions(ctx, teamID, targets.review[teamID], currentReviewState)
 			if err != nil {
 				log.Error(ctx, "Failed to sync review reactions for closed PR",
 					"error", err,
@@ -103,9 +231,9 @@ func (h *GitHubHandler) syncReactions(
 			}
 
 			// Add the appropriate closed/merged emoji
-			emoji := utils.GetEmojiForPRState(PRActionClosed, pr.GetMerged(), h.emojiConfig)
+			emoji := utils.GetEmojiForPRState(PRActionClosed, pr.GetMerged(), h.slackService.EmojiConfig(ctx, teamID))
 			if emoji != "" {
-				err = h.slackService.AddReactionToMultipleMessages(ctx, teamID, teamMessageRefs, emoji)
+				err = h.slackService.AddReactionToMultipleMessages(ctx, teamID, targets.prState[teamID], emoji)
 				if err != nil {
 					log.Error(ctx, "Failed to add PR state reaction",
 						"error", err,
@@ -125,7 +253,7 @@ func (h *GitHubHandler) syncReactions(
 				)
 			}
 
-			err = h.slackService.SyncReviewReactions(ctx, teamID, teamMessageRefs, currentReviewState)
+			err = h.slackService.SyncReviewReactions(ctx, teamID, targets.review[teamID], currentReviewState)
 			if err != nil {
 				log.Error(ctx, "Failed to sync review reactions for open PR",
 					"error", err,
@@ -136,6 +264,13 @@ func (h *GitHubHandler) syncReactions(
 		}
 	}
 
+	// Reflect lifecycle state in message text for channels without reactions
+	if isClosed {
+		h.applyLifecycleStateText(ctx, targets, lifecycleStateLabel(pr.GetMerged()))
+	} else {
+		h.applyLifecycleStateText(ctx, targets, "")
+	}
+
 	// Log final state
 	if isClosed {
 		log.Info(ctx, "PR is closed, synced reactions",
@@ -146,7 +281,7 @@ func (h *GitHubHandler) syncReactions(
 		log.Info(ctx, "Reaction sync completed for open PR",
 			"review_state", currentReviewState,
 			"total_messages", len(trackedMessages),
-			"workspace_count", len(messagesByTeam))
+			"workspace_count", len(targets.all))
 	}
 
 	return nil
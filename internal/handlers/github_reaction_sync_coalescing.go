@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+)
+
+// reactionSyncClaimLease bounds how long a running reaction sync lets later jobs for the PR skip,
+// in case the sync never finishes.
+const reactionSyncClaimLease = 2 * time.Minute
+
+// coalescesReactionSync reports whether a reaction sync job can be skipped in favour of a later sync.
+// Reconciliation passes also refresh message content, so they always run, as do jobs without a request time.
+func coalescesReactionSync(job *models.ReactionSyncJob) bool {
+	return !job.Reconcile && !job.RequestedAt.IsZero()
+}
+
+// claimReactionSync returns false if a sync that started after the job was requested has completed or is still
+// running, so the job has nothing left to do. Otherwise the job is recorded as the PR's running sync.
+// Lookup failures are logged and let the sync run, since coalescing is best effort.
+func (h *GitHubHandler) claimReactionSync(ctx context.Context, job *models.ReactionSyncJob, startedAt time.Time) bool {
+	if !coalescesReactionSync(job) {
+		return true
+	}
+
+	claimed, err := h.firestoreService.ClaimReactionSync(
+		ctx, job.RepoFullName, job.PRNumber, job.ID, job.RequestedAt, startedAt, reactionSyncClaimLease,
+	)
+	if err != nil {
+		log.Warn(ctx, "Failed to check for a newer reaction sync, syncing anyway", "error", err)
+		return true
+	}
+	if !claimed {
+		log.Info(ctx, "Skipping reaction sync covered by a newer sync", "requested_at", job.RequestedAt)
+	}
+	return claimed
+}
+
+// releaseReactionSync records that a claimed reaction sync has finished. Once it succeeds, jobs requested before it
+// started are skipped. Failures are logged, as the claim lapses anyway.
+func (h *GitHubHandler) releaseReactionSync(ctx context.Context, job *models.ReactionSyncJob, startedAt time.Time, succeeded bool) {
+	if !coalescesReactionSync(job) {
+		return
+	}
+
+	if err := h.firestoreService.ReleaseReactionSync(ctx, job.RepoFullName, job.PRNumber, job.ID, startedAt, succeeded); err != nil {
+		log.Warn(ctx, "Failed to record reaction sync completion", "error", err)
+	}
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/go-github/v74/github"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+	"github-slack-notifier/internal/utils"
+)
+
+// refTypeTag is the create event ref type for tag pushes.
+const refTypeTag = "tag"
+
+// validateCreatePayload validates create webhook payload structure.
+// Create events have no action field, so only the ref and repository are required.
+func (h *GitHubHandler) validateCreatePayload(payload []byte) error {
+	var createPayload map[string]interface{}
+	if err := json.Unmarshal(payload, &createPayload); err != nil {
+		return fmt.Errorf("invalid JSON payload: %w", err)
+	}
+
+	if _, exists := createPayload["ref"]; !exists {
+		return ErrMissingRef
+	}
+
+	if _, exists := createPayload["repository"]; !exists {
+		return ErrMissingRepository
+	}
+
+	return nil
+}
+
+// processCreateEvent processes create webhook events.
+// For tag pushes matching a repository's release notes pattern, generates draft release notes
+// and posts them to the configured release channel for human review.
+func (h *GitHubHandler) processCreateEvent(ctx context.Context, payload []byte) error {
+	var githubPayload github.CreateEvent
+	if err := json.Unmarshal(payload, &githubPayload); err != nil {
+		log.Error(ctx, "Failed to unmarshal create payload",
+			"error", err,
+			"payload_size", len(payload),
+		)
+		return fmt.Errorf("failed to unmarshal create payload: %w", models.Permanent(err))
+	}
+
+	if githubPayload.GetRefType() != refTypeTag {
+		log.Debug(ctx, "Ignoring non-tag create event", "ref_type", githubPayload.GetRefType())
+		return nil
+	}
+
+	repoFullName := githubPayload.GetRepo().GetFullName()
+	tagName := githubPayload.GetRef()
+	ctx = log.WithFields(ctx, log.LogFields{
+		"repo": repoFullName,
+		"tag":  tagName,
+	})
+
+	repos, err := h.firestoreService.GetReposForAllWorkspaces(ctx, repoFullName)
+	if err != nil {
+		log.Error(ctx, "Failed to lookup repository configurations for tag push", "error", err)
+		return err
+	}
+
+	// Only generate release notes if at least one workspace has opted in for this tag
+	var matching []int
+	for i, repo := range repos {
+		if repo.ReleaseNotes.MatchesTag(tagName) {
+			matching = append(matching, i)
+		}
+	}
+	if len(matching) == 0 {
+		log.Debug(ctx, "No workspaces configured for release notes on this tag")
+		return nil
+	}
+
+	notes, err := h.githubService.GenerateReleaseNotes(ctx, repoFullName, tagName)
+	if err != nil {
+		log.Error(ctx, "Failed to generate release notes", "error", err)
+		return err
+	}
+
+	text := utils.FormatReleaseNotes(repoFullName, githubPayload.GetRepo().GetHTMLURL(), tagName, notes.Body)
+
+	// Retrying after a partial failure would re-post to workspaces that succeeded,
+	// so only report an error when nothing was posted
+	var lastErr error
+	posted := 0
+	for _, i := range matching {
+		repo := repos[i]
+		_, err := h.slackService.PostMessage(ctx, repo.WorkspaceID, repo.ReleaseNotes.SlackChannel, text)
+		if err != nil {
+			log.Error(ctx, "Failed to post draft release notes",
+				"error", err,
+				"slack_team_id", repo.WorkspaceID,
+				"channel", repo.ReleaseNotes.SlackChannel,
+			)
+			lastErr = err
+			continue
+		}
+		posted++
+		log.Info(ctx, "Posted draft release notes",
+			"slack_team_id", repo.WorkspaceID,
+			"channel", repo.ReleaseNotes.SlackChannel,
+		)
+	}
+
+	if posted == 0 {
+		return lastErr
+	}
+	return nil
+}
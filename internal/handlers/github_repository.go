@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/go-github/v74/github"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+	"github-slack-notifier/internal/services"
+)
+
+// repositoryMessageBatchSize is how many tracked messages are updated or deleted per transaction,
+// within Firestore's limit of 500 writes.
+const repositoryMessageBatchSize = 400
+
+// deletedRepositoryState is the lifecycle state shown on messages of PRs whose repository was deleted.
+const deletedRepositoryState = "deleted"
+
+// processRepositoryEvent processes repository webhook events. Renamed and transferred repositories have their
+// configuration and tracked messages moved to the new name, so later PR events still find them, and the
+// messages of deleted repositories are marked deleted and no longer tracked.
+func (h *GitHubHandler) processRepositoryEvent(ctx context.Context, payload []byte) error {
+	var githubPayload github.RepositoryEvent
+	if err := json.Unmarshal(payload, &githubPayload); err != nil {
+		log.Error(ctx, "Failed to unmarshal repository payload",
+			"error", err,
+			"payload_size", len(payload),
+		)
+		return fmt.Errorf("failed to unmarshal repository payload: %w", models.Permanent(err))
+	}
+
+	repoFullName := githubPayload.GetRepo().GetFullName()
+	ctx = log.WithFields(ctx, log.LogFields{
+		"repo":              repoFullName,
+		"repository_action": githubPayload.GetAction(),
+	})
+
+	switch githubPayload.GetAction() {
+	case RepositoryActionRenamed, RepositoryActionTransferred:
+		oldFullName := previousRepoFullName(&githubPayload)
+		if oldFullName == "" {
+			log.Warn(ctx, "Repository event doesn't say what the repository was called before")
+			return nil
+		}
+		return h.handleRepositoryRenamed(ctx, oldFullName, repoFullName)
+	case RepositoryActionDeleted:
+		return h.handleRepositoryDeleted(ctx, repoFullName)
+	default:
+		log.Debug(ctx, "Repository action not handled")
+		return nil
+	}
+}
+
+// previousRepoFullName returns a renamed or transferred repository's full name before the change,
+// or "" if the event doesn't record one.
+func previousRepoFullName(payload *github.RepositoryEvent) string {
+	owner := payload.GetRepo().GetOwner().GetLogin()
+	name := payload.GetRepo().GetName()
+
+	changes := payload.GetChanges()
+	if from := changes.GetRepo().GetName().GetFrom(); from != "" {
+		name = from
+	}
+	if previousOwner := changes.GetOwner().GetOwnerInfo(); previousOwner != nil {
+		if login := previousOwner.GetOrg().GetLogin(); login != "" {
+			owner = login
+		} else if login := previousOwner.GetUser().GetLogin(); login != "" {
+			owner = login
+		}
+	}
+
+	fullName := owner + "/" + name
+	if owner == "" || name == "" || fullName == payload.GetRepo().GetFullName() {
+		return ""
+	}
+	return fullName
+}
+
+// handleRepositoryRenamed moves a repository's configuration and tracked messages to its new name.
+// Both steps are idempotent, so a failed delivery can be retried.
+func (h *GitHubHandler) handleRepositoryRenamed(ctx context.Context, oldFullName, newFullName string) error {
+	ctx = log.WithFields(ctx, log.LogFields{"old_repo": oldFullName})
+
+	workspaceIDs, err := h.firestoreService.RenameRepo(ctx, oldFullName, newFullName)
+	if err != nil {
+		log.Error(ctx, "Failed to rename repository configuration", "error", err)
+		return err
+	}
+
+	trackedMessages, err := h.firestoreService.GetTrackedMessages(ctx, services.TrackedMessageQuery{RepoFullName: oldFullName})
+	if err != nil {
+		log.Error(ctx, "Failed to get tracked messages for renamed repository", "error", err)
+		return err
+	}
+	for _, messageIDs := range batchTrackedMessageIDs(trackedMessages) {
+		if err := h.firestoreService.UpdateTrackedMessagesRepo(ctx, messageIDs, newFullName); err != nil {
+			log.Error(ctx, "Failed to move tracked messages to renamed repository", "error", err)
+			return err
+		}
+	}
+
+	log.Info(ctx, "Moved repository to its new name",
+		"workspaces", workspaceIDs,
+		"message_count", len(trackedMessages),
+	)
+	return nil
+}
+
+// handleRepositoryDeleted marks the bot messages of a deleted repository's PRs deleted, since their links no longer
+// work, then stops tracking the repository's messages. The repository's configuration is kept, in case it's restored.
+// Failures to edit messages are logged, as the tracking is dropped either way.
+func (h *GitHubHandler) handleRepositoryDeleted(ctx context.Context, repoFullName string) error {
+	trackedMessages, err := h.firestoreService.GetTrackedMessages(ctx, services.TrackedMessageQuery{RepoFullName: repoFullName})
+	if err != nil {
+		log.Error(ctx, "Failed to get tracked messages for deleted repository", "error", err)
+		return err
+	}
+
+	marked := 0
+	messagesByTeam := make(map[string][]services.MessageRef)
+	for _, msg := range trackedMessages {
+		if msg.MessageSource == models.MessageSourceBot && !msg.DeletedByUser && msg.SupersededByPR == 0 {
+			marked++
+			messagesByTeam[msg.SlackTeamID] = append(messagesByTeam[msg.SlackTeamID], trackedMessageRef(msg))
+		}
+	}
+	for teamID, messageRefs := range messagesByTeam {
+		if err := h.slackService.SetLifecycleStateText(ctx, teamID, messageRefs, deletedRepositoryState); err != nil {
+			log.Warn(ctx, "Failed to mark messages of deleted repository",
+				"error", err,
+				"team_id", teamID,
+			)
+		}
+	}
+
+	for _, messageIDs := range batchTrackedMessageIDs(trackedMessages) {
+		if err := h.firestoreService.DeleteTrackedMessages(ctx, messageIDs); err != nil {
+			log.Error(ctx, "Failed to delete tracked messages of deleted repository", "error", err)
+			return err
+		}
+	}
+
+	log.Info(ctx, "Stopped tracking messages of deleted repository",
+		"marked_count", marked,
+		"message_count", len(trackedMessages),
+	)
+	return nil
+}
+
+// batchTrackedMessageIDs splits tracked message IDs into batches small enough to write in one transaction.
+func batchTrackedMessageIDs(trackedMessages []*models.TrackedMessage) [][]string {
+	var batches [][]string
+	for start := 0; start < len(trackedMessages); start += repositoryMessageBatchSize {
+		end := min(start+repositoryMessageBatchSize, len(trackedMessages))
+		batch := make([]string, 0, end-start)
+		for _, msg := range trackedMessages[start:end] {
+			batch = append(batch, msg.ID)
+		}
+		batches = append(batches, batch)
+	}
+	return batches
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+)
+
+// retentionSweepMaxAge is how far back the retention sweep looks for tracked messages.
+// Reposted messages are tracked afresh, so open PRs stay within it.
+const retentionSweepMaxAge = 30 * 24 * time.Hour
+
+// ProcessRetentionSyncJob finds tracked messages that Slack's message retention policy has deleted.
+// Deletions by users arrive as message_deleted events and mark the message deleted, so a message that's
+// missing from its channel without being marked was pruned by retention. Open PRs are reposted in channels
+// that opt in; otherwise tracking is silently dropped. Messages whose existence can't be checked are left tracked,
+// and the job fails once the others are handled, so temporary Slack errors are retried.
+func (h *GitHubHandler) ProcessRetentionSyncJob(ctx context.Context, job *models.Job) error {
+	var syncJob models.RetentionSyncJob
+	if len(job.Payload) > 0 {
+		if err := json.Unmarshal(job.Payload, &syncJob); err != nil {
+			return fmt.Errorf("failed to unmarshal retention sync job: %w", models.Permanent(err))
+		}
+	}
+
+	messages, err := h.firestoreService.ListRecentTrackedMessages(ctx, time.Now().Add(-retentionSweepMaxAge))
+	if err != nil {
+		log.Error(ctx, "Failed to list recent tracked messages for retention sweep", "error", err)
+		return err
+	}
+
+	configCache := make(map[string]*models.ChannelConfig)
+	var lookupErrors []error
+	checked, pruned, reposted := 0, 0, 0
+	for _, msg := range messages {
+		if msg.DeletedByUser || (syncJob.SlackTeamID != "" && msg.SlackTeamID != syncJob.SlackTeamID) {
+			continue
+		}
+
+		msgCtx := log.WithFields(ctx, log.LogFields{
+			"tracked_message_id": msg.ID,
+			"team_id":            msg.SlackTeamID,
+			"channel":            msg.SlackChannel,
+			"message_ts":         msg.SlackMessageTS,
+			"repo":               msg.RepoFullName,
+			"pr_number":          msg.PRNumber,
+		})
+
+		checked++
+		exists, err := h.slackService.MessageExists(msgCtx, msg.SlackTeamID, msg.SlackChannel, msg.SlackMessageTS)
+		if err != nil {
+			// Continue with other messages; the channel may have been archived or the bot removed
+			log.Warn(msgCtx, "Failed to check whether tracked message still exists", "error", err)
+			lookupErrors = append(lookupErrors, err)
+			continue
+		}
+		if exists {
+			continue
+		}
+		pruned++
+
+		if h.handlePrunedMessage(msgCtx, msg, h.repostPrunedEnabled(msgCtx, msg, configCache)) {
+			reposted++
+		}
+	}
+
+	log.Info(ctx, "Retention sweep completed",
+		"message_count", len(messages),
+		"checked_count", checked,
+		"pruned_count", pruned,
+		"reposted_count", reposted,
+		"failed_count", len(lookupErrors),
+	)
+	if len(lookupErrors) > 0 {
+		return fmt.Errorf("failed to check %d tracked messages: %w", len(lookupErrors), errors.Join(lookupErrors...))
+	}
+	return nil
+}
+
+// repostPrunedEnabled reports whether the channel of a pruned message opts in to reposting, caching channel configs
+// by workspace and channel for the rest of the sweep.
+func (h *GitHubHandler) repostPrunedEnabled(
+	ctx context.Context, msg *models.TrackedMessage, configCache map[string]*models.ChannelConfig,
+) bool {
+	cacheKey := msg.SlackTeamID + "#" + msg.SlackChannel
+	channelConfig, cached := configCache[cacheKey]
+	if !cached {
+		var err error
+		channelConfig, err = h.firestoreService.GetChannelConfig(ctx, msg.SlackTeamID, msg.SlackChannel)
+		if err != nil {
+			log.Warn(ctx, "Failed to get channel config for pruned message, dropping tracking", "error", err)
+		}
+		configCache[cacheKey] = channelConfig
+	}
+	return channelConfig != nil && channelConfig.RepostPruned
+}
+
+// handlePrunedMessage stops tracking a message pruned by retention, reposting the PR first if it's a bot message
+// for a PR that's still open and repost is enabled. Returns whether the PR was reposted.
+// Failures are logged rather than returned, so one message doesn't stop the others being handled.
+func (h *GitHubHandler) handlePrunedMessage(ctx context.Context, msg *models.TrackedMessage, repost bool) bool {
+	// The old tracking is dropped first either way, so the repost isn't skipped as a duplicate
+	if err := h.firestoreService.DeleteTrackedMessages(ctx, []string{msg.ID}); err != nil {
+		return false
+	}
+
+	if !repost || msg.MessageSource != models.MessageSourceBot || msg.IsIssue() {
+		log.Info(ctx, "Dropped tracking of message pruned by retention")
+		return false
+	}
+
+	payload, repo, user, err := h.loadPRForRepost(ctx, msg.SlackTeamID, msg.RepoFullName, msg.PRNumber)
+	if err != nil {
+		log.Warn(ctx, "Failed to load PR to repost after retention pruned its message", "error", err)
+		return false
+	}
+	if payload.GetPullRequest().GetState() != "open" {
+		log.Info(ctx, "Dropped tracking of closed PR message pruned by retention")
+		return false
+	}
+
+	if err := h.repostPRMessage(ctx, payload, repo, user, msg, msg.SlackChannel, msg.MovedBy); err != nil {
+		log.Error(ctx, "Failed to repost PR after retention pruned its message", "error", err)
+		return false
+	}
+	if err := h.enqueueReactionSync(ctx, payload); err != nil {
+		log.Warn(ctx, "Failed to enqueue reaction sync for reposted PR message", "error", err)
+	}
+
+	log.Info(ctx, "Reposted open PR after retention pruned its message")
+	return true
+}
@@ -0,0 +1,259 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/go-github/v74/github"
+	"github.com/google/uuid"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+	"github-slack-notifier/internal/utils"
+)
+
+// processIssueCommentEvent processes issue_comment webhook events.
+// Comments on PR conversations are threaded under the PR's tracked messages, and comments on issues
+// under the issue's links tracked in Slack.
+func (h *GitHubHandler) processIssueCommentEvent(ctx context.Context, payload []byte, traceID string) error {
+	var githubPayload github.IssueCommentEvent
+	if err := json.Unmarshal(payload, &githubPayload); err != nil {
+		log.Error(ctx, "Failed to unmarshal issue comment payload",
+			"error", err,
+			"payload_size", len(payload),
+		)
+		return fmt.Errorf("failed to unmarshal issue comment payload: %w", models.Permanent(err))
+	}
+
+	issue := githubPayload.GetIssue()
+	comment := githubPayload.GetComment()
+	if isAutomatedAuthor(comment.GetUser()) {
+		return nil
+	}
+
+	ctx = log.WithFields(ctx, log.LogFields{
+		"pr_number":      issue.GetNumber(),
+		"repo":           githubPayload.GetRepo().GetFullName(),
+		"comment_action": githubPayload.GetAction(),
+	})
+
+	itemType := models.TrackedItemTypePR
+	if !issue.IsPullRequest() {
+		itemType = models.TrackedItemTypeIssue
+	}
+
+	return h.enqueueReviewCommentJob(ctx, &models.ReviewCommentJob{
+		PRNumber:         issue.GetNumber(),
+		RepoFullName:     githubPayload.GetRepo().GetFullName(),
+		ItemType:         itemType,
+		PRAuthorGitHubID: issue.GetUser().GetID(),
+		CommentKind:      models.ReviewCommentKindComment,
+		CommentID:        comment.GetID(),
+		CommentAction:    githubPayload.GetAction(),
+		CommentAuthor:    comment.GetUser().GetLogin(),
+		CommentBody:      comment.GetBody(),
+		CommentURL:       comment.GetHTMLURL(),
+		TraceID:          traceID,
+	})
+}
+
+// handleReviewCommentThread enqueues a job to thread a submitted review's body, or update it when edited.
+// Reviews submitted without a body, such as plain approvals, are left to the review reactions.
+func (h *GitHubHandler) handleReviewCommentThread(ctx context.Context, payload *github.PullRequestReviewEvent, traceID string) error {
+	review := payload.GetReview()
+
+	var action string
+	switch payload.GetAction() {
+	case PRReviewActionSubmitted:
+		if review.GetBody() == "" {
+			return nil
+		}
+		action = IssueCommentActionCreated
+	case PRReviewActionEdited:
+		action = IssueCommentActionEdited
+	default:
+		return nil
+	}
+	if isAutomatedAuthor(review.GetUser()) {
+		return nil
+	}
+
+	return h.enqueueReviewCommentJob(ctx, &models.ReviewCommentJob{
+		PRNumber:         payload.GetPullRequest().GetNumber(),
+		RepoFullName:     payload.GetRepo().GetFullName(),
+		PRAuthorGitHubID: payload.GetPullRequest().GetUser().GetID(),
+		CommentKind:      models.ReviewCommentKindReview,
+		CommentID:        review.GetID(),
+		CommentAction:    action,
+		CommentAuthor:    review.GetUser().GetLogin(),
+		CommentBody:      review.GetBody(),
+		CommentURL:       review.GetHTMLURL(),
+		ReviewState:      review.GetState(),
+		TraceID:          traceID,
+	})
+}
+
+// enqueueReviewCommentJob enqueues a job to thread a comment, so Slack calls happen outside the webhook job.
+func (h *GitHubHandler) enqueueReviewCommentJob(ctx context.Context, reviewCommentJob *models.ReviewCommentJob) error {
+	reviewCommentJob.ID = uuid.New().String()
+
+	jobPayload, err := json.Marshal(reviewCommentJob)
+	if err != nil {
+		log.Error(ctx, "Failed to marshal review comment job", "error", err)
+		return fmt.Errorf("failed to marshal review comment job: %w", err)
+	}
+
+	job := &models.Job{
+		ID:      reviewCommentJob.ID,
+		Type:    models.JobTypeReviewComment,
+		TraceID: reviewCommentJob.TraceID,
+		Payload: jobPayload,
+	}
+	if err := h.cloudTasksService.EnqueueJob(ctx, job); err != nil {
+		log.Error(ctx, "Failed to enqueue review comment job", "error", err)
+		return fmt.Errorf("failed to enqueue review comment job: %w", err)
+	}
+
+	log.Info(ctx, "Enqueued review comment job",
+		"job_id", reviewCommentJob.ID,
+		"comment_kind", reviewCommentJob.CommentKind,
+	)
+	return nil
+}
+
+// ProcessReviewCommentJob posts, edits or deletes a comment's reply in the threads of the PR's bot-posted messages,
+// or of an issue's tracked links.
+// New comments are only threaded in channels with review comment threads enabled, and only if the PR author
+// hasn't turned them off. Edits and deletions apply wherever the comment was already threaded.
+func (h *GitHubHandler) ProcessReviewCommentJob(ctx context.Context, job *models.Job) error {
+	var reviewCommentJob models.ReviewCommentJob
+	if err := json.Unmarshal(job.Payload, &reviewCommentJob); err != nil {
+		return fmt.Errorf("failed to unmarshal review comment job: %w", models.Permanent(err))
+	}
+	if err := reviewCommentJob.Validate(); err != nil {
+		return fmt.Errorf("invalid review comment job: %w", models.Permanent(err))
+	}
+
+	ctx = log.WithFields(ctx, log.LogFields{
+		"repo":           reviewCommentJob.RepoFullName,
+		"pr_number":      reviewCommentJob.PRNumber,
+		"item_type":      reviewCommentJob.ItemType,
+		"comment_kind":   reviewCommentJob.CommentKind,
+		"comment_id":     reviewCommentJob.CommentID,
+		"comment_action": reviewCommentJob.CommentAction,
+	})
+
+	if reviewCommentJob.CommentAction == IssueCommentActionCreated {
+		author, err := h.firestoreService.GetUserByGitHubUserID(ctx, reviewCommentJob.PRAuthorGitHubID)
+		if err != nil {
+			log.Error(ctx, "Failed to look up PR author for review comment threads", "error", err)
+			return fmt.Errorf("failed to look up PR author: %w", err)
+		}
+		if !author.GetReviewCommentThreadsEnabled() {
+			log.Debug(ctx, "PR author turned off review comment threads")
+			return nil
+		}
+	}
+
+	trackedMessages, err := h.getAllTrackedMessagesForPR(ctx, reviewCommentJob.RepoFullName, reviewCommentJob.PRNumber)
+	if err != nil {
+		log.Error(ctx, "Failed to get tracked messages for review comment", "error", err)
+		return err
+	}
+
+	text := utils.FormatReviewCommentReply(
+		reviewCommentJob.CommentAuthor, reviewCommentJob.ReviewState, reviewCommentJob.CommentBody, reviewCommentJob.CommentURL,
+	)
+
+	configCache := make(map[string]*models.ChannelConfig)
+	for _, msg := range trackedMessages {
+		// Issues are only ever tracked from links pasted in Slack, so their threads are on people's messages
+		if (msg.MessageSource != models.MessageSourceBot && !msg.IsIssue()) || msg.DeletedByUser || msg.Compact {
+			continue
+		}
+
+		cacheKey := msg.SlackTeamID + "#" + msg.SlackChannel
+		channelConfig, cached := configCache[cacheKey]
+		if !cached {
+			channelConfig, err = h.firestoreService.GetChannelConfig(ctx, msg.SlackTeamID, msg.SlackChannel)
+			if err != nil {
+				log.Warn(ctx, "Failed to get channel config for review comment threads",
+					"error", err,
+					"team_id", msg.SlackTeamID,
+					"channel", msg.SlackChannel,
+				)
+			}
+			configCache[cacheKey] = channelConfig
+		}
+		if reviewCommentJob.CommentAction == IssueCommentActionCreated &&
+			(channelConfig == nil || !channelConfig.ReviewCommentThreads) {
+			continue
+		}
+
+		h.syncReviewCommentReply(ctx, msg, &reviewCommentJob, text)
+	}
+
+	return nil
+}
+
+// syncReviewCommentReply posts, edits or deletes the comment's reply in one tracked message's thread.
+// Replies are recorded so retried jobs don't post twice. Failures are logged, as threads are supplementary.
+func (h *GitHubHandler) syncReviewCommentReply(
+	ctx context.Context, msg *models.TrackedMessage, job *models.ReviewCommentJob, text string,
+) {
+	ctx = log.WithFields(ctx, log.LogFields{
+		"team_id":    msg.SlackTeamID,
+		"channel":    msg.SlackChannel,
+		"message_ts": msg.SlackMessageTS,
+	})
+
+	reply, err := h.firestoreService.GetThreadReply(ctx, msg.ID, job.CommentKind, job.CommentID)
+	if err != nil {
+		log.Warn(ctx, "Failed to look up review comment reply", "error", err)
+		return
+	}
+
+	switch job.CommentAction {
+	case IssueCommentActionDeleted:
+		if reply == nil {
+			return
+		}
+		if err := h.slackService.DeleteMessage(ctx, reply.SlackTeamID, reply.SlackChannel, reply.ReplyTS, ""); err != nil {
+			log.Warn(ctx, "Failed to delete review comment reply", "error", err)
+			return
+		}
+		if err := h.firestoreService.DeleteThreadReply(ctx, reply.ID); err != nil {
+			log.Warn(ctx, "Failed to delete review comment reply record", "error", err)
+		}
+	case IssueCommentActionEdited:
+		if reply == nil {
+			return
+		}
+		if err := h.slackService.UpdateThreadReply(ctx, reply.SlackTeamID, reply.SlackChannel, reply.ReplyTS, text); err != nil {
+			log.Warn(ctx, "Failed to update review comment reply", "error", err)
+		}
+	default:
+		if reply != nil {
+			log.Debug(ctx, "Review comment already threaded")
+			return
+		}
+		replyTS, err := h.slackService.PostThreadReply(ctx, msg.SlackTeamID, msg.SlackChannel, msg.SlackMessageTS, text)
+		if err != nil {
+			log.Warn(ctx, "Failed to post review comment reply", "error", err)
+			return
+		}
+		err = h.firestoreService.SaveThreadReply(ctx, &models.ThreadReply{
+			TrackedMessageID: msg.ID,
+			SlackTeamID:      msg.SlackTeamID,
+			SlackChannel:     msg.SlackChannel,
+			ThreadTS:         msg.SlackMessageTS,
+			ReplyTS:          replyTS,
+			CommentKind:      job.CommentKind,
+			CommentID:        job.CommentID,
+		})
+		if err != nil {
+			log.Warn(ctx, "Failed to record review comment reply", "error", err)
+		}
+	}
+}
@@ -0,0 +1,306 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v74/github"
+	"github.com/google/uuid"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+)
+
+// handlePRReviewRequestChanged handles pull request review_requested and review_request_removed events.
+// Enqueues a job to notify the requested reviewer or team, so Slack calls happen outside the webhook job,
+// and a reaction sync to update the approval count on the PR's messages.
+func (h *GitHubHandler) handlePRReviewRequestChanged(ctx context.Context, payload *github.PullRequestEvent) error {
+	// Requested reviewers count towards the approvals PR messages show the PR needs
+	if h.slackService.ApprovalQuorumEnabled() {
+		if err := h.enqueueReactionSync(ctx, payload); err != nil {
+			log.Warn(ctx, "Failed to enqueue reaction sync for review request change", "error", err)
+		}
+	}
+
+	reviewer := payload.GetRequestedReviewer()
+	requestedTeam := ""
+	if reviewer == nil {
+		requestedTeam = requestedTeamName(payload)
+		if requestedTeam == "" {
+			log.Debug(ctx, "Skipping review request without a reviewer or team")
+			return nil
+		}
+	}
+
+	pr := payload.GetPullRequest()
+	jobID := uuid.New().String()
+	reviewRequestJob := &models.ReviewRequestJob{
+		ID:               jobID,
+		PRNumber:         pr.GetNumber(),
+		RepoFullName:     payload.GetRepo().GetFullName(),
+		PRTitle:          pr.GetTitle(),
+		PRURL:            pr.GetHTMLURL(),
+		PRAction:         payload.GetAction(),
+		ReviewerGitHubID: reviewer.GetID(),
+		ReviewerLogin:    reviewer.GetLogin(),
+		RequestedTeam:    requestedTeam,
+		RequestedBy:      payload.GetSender().GetLogin(),
+		TraceID:          traceIDForNewJob(ctx),
+	}
+
+	jobPayload, err := json.Marshal(reviewRequestJob)
+	if err != nil {
+		log.Error(ctx, "Failed to marshal review request job", "error", err)
+		return fmt.Errorf("failed to marshal review request job: %w", err)
+	}
+
+	job := &models.Job{
+		ID:      jobID,
+		Type:    models.JobTypeReviewRequest,
+		TraceID: reviewRequestJob.TraceID,
+		Payload: jobPayload,
+	}
+	if err := h.cloudTasksService.EnqueueJob(ctx, job); err != nil {
+		log.Error(ctx, "Failed to enqueue review request job", "error", err)
+		return fmt.Errorf("failed to enqueue review request job: %w", err)
+	}
+
+	log.Info(ctx, "Enqueued review request job",
+		"job_id", jobID,
+		"reviewer", reviewer.GetLogin(),
+		"requested_team", requestedTeam,
+	)
+	return nil
+}
+
+// requestedTeamName returns a team review request's team as "org/team-slug", lowercase, or "" if there's none.
+// Team payloads don't always include the organization, which is then the repository's owner.
+func requestedTeamName(payload *github.PullRequestEvent) string {
+	team := payload.GetRequestedTeam()
+	if team.GetSlug() == "" {
+		return ""
+	}
+	org := team.GetOrganization().GetLogin()
+	if org == "" {
+		org = payload.GetRepo().GetOwner().GetLogin()
+	}
+	return strings.ToLower(org + "/" + team.GetSlug())
+}
+
+// ProcessReviewRequestJob notifies a requested reviewer who opted in, by DM and/or a note in the thread
+// of the tracked PR message in their workspace. Team review requests are noted in the thread instead,
+// mentioning the team's Slack user group.
+func (h *GitHubHandler) ProcessReviewRequestJob(ctx context.Context, job *models.Job) error {
+	var reviewRequestJob models.ReviewRequestJob
+	if err := json.Unmarshal(job.Payload, &reviewRequestJob); err != nil {
+		return fmt.Errorf("failed to unmarshal review request job: %w", models.Permanent(err))
+	}
+	if err := reviewRequestJob.Validate(); err != nil {
+		return fmt.Errorf("invalid review request job: %w", models.Permanent(err))
+	}
+
+	ctx = log.WithFields(ctx, log.LogFields{
+		"repo":      reviewRequestJob.RepoFullName,
+		"pr_number": reviewRequestJob.PRNumber,
+		"pr_action": reviewRequestJob.PRAction,
+		"reviewer":  reviewRequestJob.ReviewerLogin,
+	})
+
+	if reviewRequestJob.PRAction == PRActionReviewRequested {
+		h.clearNoReviewersWarning(ctx, reviewRequestJob.RepoFullName, reviewRequestJob.PRNumber)
+	}
+
+	if reviewRequestJob.RequestedTeam != "" {
+		ctx = log.WithFields(ctx, log.LogFields{"requested_team": reviewRequestJob.RequestedTeam})
+		return h.postTeamReviewRequestThreadNotes(ctx, &reviewRequestJob)
+	}
+
+	user, err := h.firestoreService.GetUserByGitHubUserID(ctx, reviewRequestJob.ReviewerGitHubID)
+	if err != nil {
+		log.Error(ctx, "Failed to look up requested reviewer", "error", err)
+		return fmt.Errorf("failed to look up requested reviewer: %w", err)
+	}
+	if user == nil || !user.Verified || user.SlackUserID == "" {
+		log.Debug(ctx, "Requested reviewer has no verified Slack account")
+		return nil
+	}
+
+	h.recordPendingReview(ctx, &reviewRequestJob)
+
+	if user.RepoMuted(reviewRequestJob.RepoFullName, time.Now()) {
+		log.Debug(ctx, "Skipping review request notifications for muted repository", "slack_user_id", user.SlackUserID)
+		return nil
+	}
+	if user.WantsReviewRequestNotification(models.ReviewRequestNotifyDM) {
+		h.sendReviewRequestDM(ctx, user, &reviewRequestJob)
+	}
+	if user.WantsReviewRequestNotification(models.ReviewRequestNotifyThreadNote) {
+		return h.postReviewRequestThreadNotes(ctx, user, &reviewRequestJob)
+	}
+	return nil
+}
+
+// sendReviewRequestDM DMs the reviewer about the review request, or buffers it for their digest.
+// A failed DM is logged, so reviewers who also want thread notes still get them.
+func (h *GitHubHandler) sendReviewRequestDM(ctx context.Context, user *models.User, job *models.ReviewRequestJob) {
+	prLink := fmt.Sprintf("<%s|%s#%d %s>", job.PRURL, job.RepoFullName, job.PRNumber, job.PRTitle)
+
+	if job.PRAction == PRActionReviewRequestRemoved {
+		// Digest users would only see the removal after the fact, so it's only sent as a DM
+		if user.DigestMode {
+			return
+		}
+		h.postReviewRequestDM(ctx, user, fmt.Sprintf(":heavy_minus_sign: *%s* removed your review request on %s", job.RequestedBy, prLink))
+		return
+	}
+
+	if user.DigestMode {
+		h.bufferDigestEntry(ctx, user, &models.DigestEntry{
+			Event:        models.DigestEventReviewRequested,
+			RepoFullName: job.RepoFullName,
+			PRNumber:     job.PRNumber,
+			PRTitle:      job.PRTitle,
+			PRURL:        job.PRURL,
+			Actor:        job.RequestedBy,
+		})
+		return
+	}
+	h.postReviewRequestDM(ctx, user, fmt.Sprintf(":eyes: *%s* requested your review on %s", job.RequestedBy, prLink))
+}
+
+// postReviewRequestDM sends a review request direct message, logging any failure.
+// It's deferred if the reviewer is in quiet hours.
+func (h *GitHubHandler) postReviewRequestDM(ctx context.Context, user *models.User, text string) {
+	if h.deferForQuietHours(ctx, user, "", "", text) {
+		return
+	}
+	if _, err := h.slackService.PostMessage(ctx, user.SlackTeamID, user.SlackUserID, text); err != nil {
+		log.Error(ctx, "Failed to send review request DM",
+			"error", err,
+			"slack_user_id", user.SlackUserID,
+		)
+		return
+	}
+
+	log.Info(ctx, "Sent review request DM", "slack_user_id", user.SlackUserID)
+}
+
+// postReviewRequestThreadNotes notes the review request change in the thread of each bot-posted
+// PR message in the reviewer's workspace. Notes mention the reviewer, so they're deferred during their quiet hours.
+func (h *GitHubHandler) postReviewRequestThreadNotes(ctx context.Context, user *models.User, job *models.ReviewRequestJob) error {
+	trackedMessages, err := h.getAllTrackedMessagesForPR(ctx, job.RepoFullName, job.PRNumber)
+	if err != nil {
+		log.Error(ctx, "Failed to get tracked messages for review request note", "error", err)
+		return err
+	}
+
+	text := fmt.Sprintf(":eyes: Review requested from <@%s> by %s", user.SlackUserID, job.RequestedBy)
+	if job.PRAction == PRActionReviewRequestRemoved {
+		text = fmt.Sprintf(":heavy_minus_sign: Review request for <@%s> removed by %s", user.SlackUserID, job.RequestedBy)
+	}
+
+	for _, msg := range trackedMessages {
+		if msg.SlackTeamID != user.SlackTeamID || msg.MessageSource != models.MessageSourceBot {
+			continue
+		}
+		if h.deferForQuietHours(ctx, user, msg.SlackChannel, msg.SlackMessageTS, text) {
+			continue
+		}
+		if _, err := h.slackService.PostThreadReply(ctx, msg.SlackTeamID, msg.SlackChannel, msg.SlackMessageTS, text); err != nil {
+			log.Warn(ctx, "Failed to post review request thread note",
+				"error", err,
+				"channel", msg.SlackChannel,
+				"message_ts", msg.SlackMessageTS,
+			)
+		}
+	}
+	return nil
+}
+
+// postTeamReviewRequestThreadNotes notes a team review request change in the thread of each bot-posted PR message
+// in workspaces that map the team to a Slack user group. Requests mention the group; removals only name the team.
+// Workspaces without a mapping for the team get no note.
+func (h *GitHubHandler) postTeamReviewRequestThreadNotes(ctx context.Context, job *models.ReviewRequestJob) error {
+	trackedMessages, err := h.getAllTrackedMessagesForPR(ctx, job.RepoFullName, job.PRNumber)
+	if err != nil {
+		log.Error(ctx, "Failed to get tracked messages for team review request note", "error", err)
+		return err
+	}
+
+	// User group mapped to the team in each workspace, or nil if it isn't mapped there
+	userGroups := make(map[string]*models.TeamUserGroup)
+	for _, msg := range trackedMessages {
+		if msg.MessageSource != models.MessageSourceBot {
+			continue
+		}
+		userGroup, looked := userGroups[msg.SlackTeamID]
+		if !looked {
+			userGroup = h.teamUserGroup(ctx, msg.SlackTeamID, job.RequestedTeam)
+			userGroups[msg.SlackTeamID] = userGroup
+		}
+		if userGroup == nil {
+			continue
+		}
+
+		text := fmt.Sprintf(":eyes: Review requested from <!subteam^%s> (%s) by %s",
+			userGroup.SlackUserGroupID, job.RequestedTeam, job.RequestedBy)
+		if job.PRAction == PRActionReviewRequestRemoved {
+			text = fmt.Sprintf(":heavy_minus_sign: Review request for team %s removed by %s", job.RequestedTeam, job.RequestedBy)
+		}
+		if _, err := h.slackService.PostThreadReply(ctx, msg.SlackTeamID, msg.SlackChannel, msg.SlackMessageTS, text); err != nil {
+			log.Warn(ctx, "Failed to post team review request thread note",
+				"error", err,
+				"channel", msg.SlackChannel,
+				"message_ts", msg.SlackMessageTS,
+			)
+		}
+	}
+	return nil
+}
+
+// teamUserGroup returns the Slack user group mapped to a GitHub team in a workspace, or nil if it isn't mapped.
+// Lookup failures are logged and treated as unmapped, so one workspace doesn't hold up the others' notes.
+func (h *GitHubHandler) teamUserGroup(ctx context.Context, teamID, githubTeam string) *models.TeamUserGroup {
+	groups, err := h.firestoreService.GetTeamUserGroups(ctx, teamID)
+	if err != nil {
+		log.Warn(ctx, "Failed to get team user groups", "error", err, "team_id", teamID)
+		return nil
+	}
+	if groups == nil {
+		return nil
+	}
+	return groups.UserGroupFor(githubTeam)
+}
+
+// recordPendingReview tracks an outstanding review request for the reviewer's daily digest,
+// or removes it when the request is removed. Failures are logged, as the digest is supplementary.
+func (h *GitHubHandler) recordPendingReview(ctx context.Context, job *models.ReviewRequestJob) {
+	var err error
+	if job.PRAction == PRActionReviewRequestRemoved {
+		err = h.firestoreService.DeletePendingReview(ctx, job.ReviewerGitHubID, job.RepoFullName, job.PRNumber)
+	} else {
+		err = h.firestoreService.SavePendingReview(ctx, &models.PendingReview{
+			ReviewerGitHubID: job.ReviewerGitHubID,
+			RepoFullName:     job.RepoFullName,
+			PRNumber:         job.PRNumber,
+			PRTitle:          job.PRTitle,
+			PRURL:            job.PRURL,
+			RequestedBy:      job.RequestedBy,
+		})
+	}
+	if err != nil {
+		log.Warn(ctx, "Failed to record pending review", "error", err)
+	}
+}
+
+// clearPendingReview removes the reviewer's pending review once they've submitted a review.
+func (h *GitHubHandler) clearPendingReview(ctx context.Context, payload *github.PullRequestReviewEvent) {
+	err := h.firestoreService.DeletePendingReview(ctx,
+		payload.GetReview().GetUser().GetID(), payload.GetRepo().GetFullName(), payload.GetPullRequest().GetNumber())
+	if err != nil {
+		log.Warn(ctx, "Failed to clear pending review", "error", err)
+	}
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/google/go-github/v74/github"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+	"github-slack-notifier/internal/services"
+)
+
+// assignRotationReviewer assigns the next reviewer from the target channel's rotation to a PR posted without CCs.
+// Returns directives CC'ing the reviewer and their GitHub login, or the directives unchanged and "" when the PR
+// already CCs someone, the channel has no rotation, or no reviewer could be assigned.
+// Failures are logged, since assigning a reviewer is supplementary to posting the PR.
+func (h *GitHubHandler) assignRotationReviewer(
+	ctx context.Context, payload *github.PullRequestEvent, repo *models.Repo, targetChannel string,
+	directives *services.PRDirectives,
+) (*services.PRDirectives, string) {
+	if len(directives.UsersToCC) > 0 {
+		return directives, ""
+	}
+
+	channelID, err := h.slackService.ResolveChannelID(ctx, repo.WorkspaceID, targetChannel)
+	if err != nil {
+		log.Warn(ctx, "Failed to resolve channel for reviewer rotation", "error", err, "channel", targetChannel)
+		return directives, ""
+	}
+
+	// Never assign authors their own PR
+	var authorSlackID string
+	if author := h.lookupUserForMention(ctx, payload.GetPullRequest().GetUser().GetLogin(), repo.WorkspaceID); author != nil {
+		authorSlackID = author.SlackUserID
+	}
+	reviewerSlackID, err := h.firestoreService.AssignNextReviewer(ctx, repo.WorkspaceID, channelID,
+		func(slackUserID string) bool { return slackUserID == authorSlackID })
+	if err != nil {
+		log.Warn(ctx, "Failed to assign reviewer from rotation", "error", err, "channel", channelID)
+		return directives, ""
+	}
+	if reviewerSlackID == "" {
+		return directives, ""
+	}
+
+	reviewer, err := h.firestoreService.GetUserBySlackID(ctx, reviewerSlackID)
+	if err != nil || reviewer == nil || reviewer.GitHubUsername == "" || reviewer.SlackTeamID != repo.WorkspaceID {
+		log.Warn(ctx, "Rotation reviewer has no connected GitHub account, not assigning",
+			"error", err, "reviewer_slack_id", reviewerSlackID, "channel", channelID)
+		return directives, ""
+	}
+
+	log.Info(ctx, "Assigned reviewer from rotation",
+		"reviewer", reviewer.GitHubUsername, "reviewer_slack_id", reviewerSlackID, "channel", channelID)
+	modified := *directives
+	modified.UsersToCC = []string{reviewer.GitHubUsername}
+	return &modified, reviewer.GitHubUsername
+}
+
+// requestRotationReview formally requests a review on GitHub from a reviewer assigned by rotation.
+// Failures are logged, since the reviewer is already mentioned on the posted message.
+func (h *GitHubHandler) requestRotationReview(ctx context.Context, payload *github.PullRequestEvent, repo *models.Repo, login string) {
+	err := h.githubService.RequestReviewers(ctx, payload.GetRepo().GetFullName(), repo.WorkspaceID,
+		payload.GetPullRequest().GetNumber(), []string{login})
+	if err != nil {
+		log.Warn(ctx, "Failed to request review from rotation reviewer", "error", err, "reviewer", login)
+	}
+}
+
+// previewReviewerRotation traces who each target channel's rotation would assign, without advancing it.
+func (h *GitHubHandler) previewReviewerRotation(
+	ctx context.Context, payload *github.PullRequestEvent, repo *models.Repo, channels []string,
+	directives *services.PRDirectives, trace *routingTrace,
+) {
+	if len(directives.UsersToCC) > 0 {
+		return
+	}
+
+	var authorSlackID string
+	if author := h.lookupUserForMention(ctx, payload.GetPullRequest().GetUser().GetLogin(), repo.WorkspaceID); author != nil {
+		authorSlackID = author.SlackUserID
+	}
+	for _, channel := range channels {
+		channelID, err := h.slackService.ResolveChannelID(ctx, repo.WorkspaceID, channel)
+		if err != nil {
+			continue
+		}
+		rotation, err := h.firestoreService.GetReviewerRotation(ctx, repo.WorkspaceID, channelID)
+		if err != nil || rotation == nil {
+			continue
+		}
+		if reviewer, ok := rotation.Assign(func(id string) bool { return id == authorSlackID }); ok {
+			trace.add("Reviewer rotation in %s would assign %s", channel, reviewer)
+		} else {
+			trace.add("Reviewer rotation in %s has no one to assign besides the author", channel)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/google/go-github/v74/github"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+	"github-slack-notifier/internal/utils"
+)
+
+// applyRoutingRules routes a PR by the repository's path and label rules when nothing more specific has.
+// A channel from a directive, the notification policy, or mechanical PR handling takes precedence,
+// and when no rule matches the author's default channel is used as before.
+// The PR's changed files are only listed when a rule matches on paths.
+func (h *GitHubHandler) applyRoutingRules(
+	ctx context.Context, payload *github.PullRequestEvent, repo *models.Repo, annotatedChannel string,
+	trace *routingTrace,
+) string {
+	if len(repo.RoutingRules) == 0 {
+		return annotatedChannel
+	}
+	if annotatedChannel != "" {
+		trace.add("Routing rules not evaluated: a channel was already chosen")
+		return annotatedChannel
+	}
+
+	pr := payload.GetPullRequest()
+	labels := make([]string, 0, len(pr.Labels))
+	for _, label := range pr.Labels {
+		labels = append(labels, label.GetName())
+	}
+
+	var files []string
+	if utils.RoutingRulesNeedFiles(repo.RoutingRules) {
+		var err error
+		files, err = h.githubService.ListPullRequestFiles(ctx, payload.GetRepo().GetFullName(), pr.GetNumber())
+		if err != nil {
+			log.Warn(ctx, "Failed to list PR files for routing rules, evaluating with no files", "error", err)
+		}
+	}
+
+	rule := utils.MatchRoutingRule(repo.RoutingRules, labels, files)
+	if rule == nil {
+		trace.add("No routing rule matched")
+		return ""
+	}
+
+	log.Debug(ctx, "Routing rule matched",
+		"channel", rule.Channel,
+		"label", rule.Label,
+		"paths", rule.Paths,
+		"slack_team_id", repo.WorkspaceID,
+	)
+	if rule.Label != "" {
+		trace.add("Routing rule for label %q routes to #%s", rule.Label, rule.Channel)
+	} else {
+		trace.add("Routing rule for paths %v routes to #%s", rule.Paths, rule.Channel)
+	}
+	return rule.Channel
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/go-github/v74/github"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+)
+
+// prSequenceLease bounds how long pending workspace PR jobs hold up later events for a PR,
+// in case a job is dropped or exhausts its retries.
+const prSequenceLease = 2 * time.Minute
+
+// waitsForPRSequence reports whether a pull request action updates or depends on the PR's posted messages,
+// and so must wait for any workspace PR jobs still posting them.
+func waitsForPRSequence(action string) bool {
+	switch action {
+	case PRActionEdited, PRActionReadyForReview, PRActionConvertedToDraft, PRActionClosed, PRActionReopened,
+		PRActionMilestoned, PRActionDemilestoned, PRActionLabeled, PRActionUnlabeled, PRActionAssigned, PRActionUnassigned,
+		PRActionReviewRequested, PRActionReviewRequestRemoved:
+		return true
+	default:
+		return false
+	}
+}
+
+// checkPRSequence returns ErrPRNotificationInFlight while workspace PR jobs for the PR are still pending,
+// so the event's job is retried after the PR's messages have been posted rather than racing them.
+// Lookup failures are logged and let the event through, since ordering is best effort.
+func (h *GitHubHandler) checkPRSequence(ctx context.Context, payload *github.PullRequestEvent) error {
+	repoFullName := payload.GetRepo().GetFullName()
+	prNumber := payload.GetPullRequest().GetNumber()
+
+	sequence, err := h.firestoreService.GetPRSequence(ctx, repoFullName, prNumber)
+	if err != nil {
+		log.Warn(ctx, "Failed to check PR notification sequence", "error", err)
+		return nil
+	}
+	if !sequence.InFlight(time.Now()) {
+		return nil
+	}
+
+	log.Info(ctx, "Deferring PR event until pending notifications are posted",
+		"pending_jobs", sequence.PendingJobs,
+		"expires_at", sequence.ExpiresAt,
+	)
+	return fmt.Errorf("%w: %s#%d", models.ErrPRNotificationInFlight, repoFullName, prNumber)
+}
+
+// reservePRSequence records workspace PR jobs about to be enqueued for a PR.
+// Failures are logged rather than returned, so notifications are still posted without ordering.
+func (h *GitHubHandler) reservePRSequence(ctx context.Context, repoFullName string, prNumber, jobs int) {
+	if err := h.firestoreService.ReservePRSequence(ctx, repoFullName, prNumber, jobs, prSequenceLease); err != nil {
+		log.Warn(ctx, "Failed to reserve PR notification sequence", "error", err)
+	}
+}
+
+// completePRSequenceJobs records that workspace PR jobs for a PR have finished or won't run.
+// Failures are logged, as the lease releases the sequence anyway.
+func (h *GitHubHandler) completePRSequenceJobs(ctx context.Context, repoFullName string, prNumber, jobs int) {
+	if err := h.firestoreService.CompletePRSequenceJobs(ctx, repoFullName, prNumber, jobs); err != nil {
+		log.Warn(ctx, "Failed to complete PR notification sequence jobs", "error", err)
+	}
+}
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/go-github/v74/github"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+)
+
+// routingTrace collects human-readable routing steps for the simulation endpoint.
+// A nil trace is a no-op, so the real notification path passes nil.
+type routingTrace []string
+
+// add appends a formatted step to the trace.
+func (t *routingTrace) add(format string, args ...any) {
+	if t == nil {
+		return
+	}
+	*t = append(*t, fmt.Sprintf(format, args...))
+}
+
+// RoutingSimulation is the result of simulating where a PR notification would be posted.
+type RoutingSimulation struct {
+	RepoFullName string             `json:"repo_full_name"`
+	PRNumber     int                `json:"pr_number"`
+	Skipped      bool               `json:"skipped"`
+	Trace        []string           `json:"trace"`
+	Workspaces   []WorkspaceRouting `json:"workspaces"`
+}
+
+// WorkspaceRouting is the simulated outcome for a single workspace.
+type WorkspaceRouting struct {
+	WorkspaceID string   `json:"workspace_id"`
+	Channels    []string `json:"channels,omitempty"`
+	Skipped     bool     `json:"skipped"`
+	UsersToCC   []string `json:"users_to_cc,omitempty"`
+	CustomEmoji string   `json:"custom_emoji,omitempty"`
+	Trace       []string `json:"trace"`
+}
+
+// HandleSimulateRouting reports which workspaces and channels a pull_request event would be routed to, and why.
+// Nothing is posted or stored; duplicate detection against existing messages is not simulated.
+// POST /api/simulate-routing with a GitHub pull_request webhook payload as the body.
+func (h *GitHubHandler) HandleSimulateRouting(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var payload github.PullRequestEvent
+	if err := c.ShouldBindJSON(&payload); err != nil || payload.GetPullRequest() == nil || payload.GetRepo() == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "body must be a pull_request webhook payload"})
+		return
+	}
+
+	simulation, err := h.simulateRouting(ctx, &payload)
+	if err != nil {
+		log.Error(ctx, "Failed to simulate routing", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to simulate routing"})
+		return
+	}
+
+	c.JSON(http.StatusOK, simulation)
+}
+
+// simulateRouting mirrors postPRToAllWorkspaces and processWorkspaceNotification without side effects.
+func (h *GitHubHandler) simulateRouting(ctx context.Context, payload *github.PullRequestEvent) (*RoutingSimulation, error) {
+	trace := &routingTrace{}
+	simulation := &RoutingSimulation{
+		RepoFullName: payload.GetRepo().GetFullName(),
+		PRNumber:     payload.GetPullRequest().GetNumber(),
+		Workspaces:   []WorkspaceRouting{},
+	}
+
+	user, err := h.firestoreService.GetUserByGitHubUserID(ctx, payload.GetPullRequest().GetUser().GetID())
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		trace.add("Author %s hasn't connected their GitHub account", payload.GetPullRequest().GetUser().GetLogin())
+	} else {
+		trace.add("Author %s is linked to Slack user %s in workspace %s",
+			payload.GetPullRequest().GetUser().GetLogin(), user.SlackUserID, user.SlackTeamID)
+	}
+
+	annotatedChannel, directives := h.slackService.ExtractChannelAndDirectives(payload.GetPullRequest().GetBody())
+	switch {
+	case directives.Skip:
+		trace.add("Skipped by a skip directive in the PR description")
+	case directives.HasReviewDirective:
+		trace.add("Directives: channel=%q cc=%v emoji=%q", directives.Channel, directives.UsersToCC, directives.CustomEmoji)
+	default:
+		trace.add("No directives in the PR description")
+	}
+	skipLabeled := h.hasSkipLabel(payload)
+	if skipLabeled {
+		trace.add("Skipped by the %s label", h.skipLabel)
+	}
+	if directives.Skip || skipLabeled {
+		simulation.Skipped = true
+		simulation.Trace = *trace
+		return simulation, nil
+	}
+
+	repos, err := h.firestoreService.GetReposForAllWorkspaces(ctx, simulation.RepoFullName)
+	if err != nil {
+		return nil, err
+	}
+	if len(repos) == 0 {
+		trace.add("No workspace has %s configured; a real event would try auto-registration for verified authors",
+			simulation.RepoFullName)
+	}
+	simulation.Trace = *trace
+
+	for _, repo := range repos {
+		workspaceTrace := &routingTrace{}
+		workspaceChannel, workspaceDirectives, skip := h.applyNotificationPolicy(
+			ctx, payload, repo.WorkspaceID, annotatedChannel, directives, workspaceTrace)
+		if !skip {
+			repo, workspaceChannel, skip = h.applyMechanicalPRHandling(ctx, payload, repo, workspaceChannel, workspaceTrace)
+		}
+		if !skip {
+			workspaceChannel = h.applyRoutingRules(ctx, payload, repo, workspaceChannel, workspaceTrace)
+		}
+
+		routing := WorkspaceRouting{WorkspaceID: repo.WorkspaceID, Skipped: skip}
+		if !skip {
+			routing.Channels = h.determineTargetChannels(ctx, repo, user, workspaceChannel, workspaceTrace)
+			routing.Skipped = len(routing.Channels) == 0
+			if mode := repo.GetNotificationMode(); !routing.Skipped && mode != models.NotificationModeFull {
+				workspaceTrace.add("Repository uses %s notification mode", mode)
+			}
+			if !routing.Skipped {
+				workspaceDirectives = h.applyCodeOwnersCC(ctx, payload, repo, workspaceDirectives, workspaceTrace)
+				h.previewReviewerRotation(ctx, payload, repo, routing.Channels, workspaceDirectives, workspaceTrace)
+			}
+			routing.UsersToCC = workspaceDirectives.UsersToCC
+			routing.CustomEmoji = workspaceDirectives.CustomEmoji
+		}
+		routing.Trace = *workspaceTrace
+		simulation.Workspaces = append(simulation.Workspaces, routing)
+	}
+
+	return simulation, nil
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+	"github-slack-notifier/internal/utils"
+)
+
+// slackCommentPostedEmoji is the reaction added to a "!to-github" reply once it's been posted to GitHub.
+const slackCommentPostedEmoji = "outbox_tray"
+
+// ProcessSlackCommentJob posts a "!to-github" thread reply as a comment on the PR of the message it's threaded
+// under, through the app's installation, attributed to the Slack user and linking back to the reply. Failures to
+// comment aren't retried, so a comment never turns up long after its author was told it failed.
+func (h *GitHubHandler) ProcessSlackCommentJob(ctx context.Context, job *models.Job) error {
+	var commentJob models.SlackCommentJob
+	if err := json.Unmarshal(job.Payload, &commentJob); err != nil {
+		return fmt.Errorf("failed to unmarshal Slack comment job: %w", models.Permanent(err))
+	}
+	if err := commentJob.Validate(); err != nil {
+		return fmt.Errorf("invalid Slack comment job: %w", models.Permanent(err))
+	}
+
+	ctx = log.WithFields(ctx, log.LogFields{
+		"slack_team_id": commentJob.SlackTeamID,
+		"channel":       commentJob.SlackChannel,
+		"thread_ts":     commentJob.SlackThreadTS,
+		"user_id":       commentJob.SlackUserID,
+	})
+
+	msg, err := h.firestoreService.GetTrackedMessageBySlackMessage(
+		ctx, commentJob.SlackTeamID, commentJob.SlackChannel, commentJob.SlackThreadTS,
+	)
+	if err != nil {
+		return err
+	}
+	if msg == nil || msg.DeletedByUser {
+		h.sendSlackCommentFailure(ctx, &commentJob,
+			"Only replies in the thread of a PR message I'm tracking can be posted to GitHub.")
+		return nil
+	}
+	ctx = log.WithFields(ctx, log.LogFields{
+		"repo":      msg.RepoFullName,
+		"pr_number": msg.PRNumber,
+	})
+
+	authorName := "A Slack user"
+	if author, err := h.slackService.GetUserInfo(ctx, commentJob.SlackTeamID, commentJob.SlackUserID); err != nil {
+		log.Warn(ctx, "Failed to get Slack comment author", "error", err)
+	} else if author.Profile.DisplayName != "" {
+		authorName = author.Profile.DisplayName
+	} else if author.RealName != "" {
+		authorName = author.RealName
+	}
+
+	permalink, err := h.slackService.GetPermalink(ctx, commentJob.SlackTeamID, commentJob.SlackChannel, commentJob.SlackMessageTS)
+	if err != nil {
+		log.Warn(ctx, "Failed to get permalink for Slack comment", "error", err)
+	}
+
+	body := utils.FormatSlackCommentForGitHub(authorName, commentJob.Text, permalink)
+	commentURL, err := h.githubService.CreateIssueComment(ctx, msg.RepoFullName, commentJob.SlackTeamID, msg.PRNumber, body)
+	if err != nil {
+		log.Error(ctx, "Failed to post Slack comment to GitHub", "error", err)
+		h.sendSlackCommentFailure(ctx, &commentJob,
+			fmt.Sprintf("I couldn't post your comment to %s#%d on GitHub. Check that the GitHub App can write to pull requests.",
+				msg.RepoFullName, msg.PRNumber))
+		return nil
+	}
+
+	if err := h.slackService.AddReaction(
+		ctx, commentJob.SlackTeamID, commentJob.SlackChannel, commentJob.SlackMessageTS, slackCommentPostedEmoji,
+	); err != nil {
+		log.Warn(ctx, "Failed to react to posted Slack comment", "error", err)
+	}
+
+	log.Info(ctx, "Posted Slack comment to GitHub", "comment_url", commentURL)
+	return nil
+}
+
+// sendSlackCommentFailure tells the author of a "!to-github" reply it wasn't posted, in a message only they see.
+func (h *GitHubHandler) sendSlackCommentFailure(ctx context.Context, commentJob *models.SlackCommentJob, text string) {
+	if err := h.slackService.SendEphemeralMessage(
+		ctx, commentJob.SlackTeamID, commentJob.SlackChannel, commentJob.SlackUserID, text,
+	); err != nil {
+		log.Warn(ctx, "Failed to tell user their Slack comment wasn't posted", "error", err)
+	}
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/google/go-github/v74/github"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+	"github-slack-notifier/internal/services"
+	"github-slack-notifier/internal/utils"
+)
+
+// annotateSupersededPRs links bot messages for PRs referenced by "Replaces #N" in the PR description
+// to the new PR, and edits them to point at it instead of leaving them dangling.
+// Failures are logged rather than returned since the new PR notification has already been posted.
+func (h *GitHubHandler) annotateSupersededPRs(ctx context.Context, payload *github.PullRequestEvent, workspaceID string) {
+	newPRNumber := payload.GetPullRequest().GetNumber()
+	supersededPRs := utils.ExtractSupersededPRNumbers(payload.GetPullRequest().GetBody(), newPRNumber)
+	if len(supersededPRs) == 0 {
+		return
+	}
+
+	repoFullName := payload.GetRepo().GetFullName()
+	newPRURL := payload.GetPullRequest().GetHTMLURL()
+
+	for _, oldPRNumber := range supersededPRs {
+		oldMessages, err := h.firestoreService.GetTrackedMessages(ctx, services.TrackedMessageQuery{
+			RepoFullName:  repoFullName,
+			PRNumber:      oldPRNumber,
+			SlackTeamID:   workspaceID,
+			MessageSource: models.MessageSourceBot,
+		})
+		if err != nil {
+			log.Error(ctx, "Failed to get tracked messages for superseded PR",
+				"error", err,
+				"superseded_pr_number", oldPRNumber,
+				"slack_team_id", workspaceID,
+			)
+			continue
+		}
+
+		var messageRefs []services.MessageRef
+		for _, msg := range oldMessages {
+			if msg.DeletedByUser || msg.SupersededByPR == newPRNumber {
+				continue
+			}
+
+			if err := h.firestoreService.MarkTrackedMessageSuperseded(ctx, msg.ID, newPRNumber); err != nil {
+				log.Error(ctx, "Failed to link superseded tracked message",
+					"error", err,
+					"tracked_message_id", msg.ID,
+					"superseded_pr_number", oldPRNumber,
+				)
+				continue
+			}
+			messageRefs = append(messageRefs, trackedMessageRef(msg))
+		}
+
+		if len(messageRefs) == 0 {
+			continue
+		}
+
+		if err := h.slackService.SetSupersededText(ctx, workspaceID, messageRefs, newPRURL, newPRNumber); err != nil {
+			log.Error(ctx, "Failed to annotate superseded PR messages",
+				"error", err,
+				"superseded_pr_number", oldPRNumber,
+				"slack_team_id", workspaceID,
+			)
+			continue
+		}
+
+		log.Info(ctx, "Annotated superseded PR messages",
+			"superseded_pr_number", oldPRNumber,
+			"message_count", len(messageRefs),
+			"slack_team_id", workspaceID,
+		)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -14,7 +16,9 @@ import (
 
 	"github-slack-notifier/internal/config"
 	"github-slack-notifier/internal/models"
+	"github-slack-notifier/internal/services"
 	"github.com/gin-gonic/gin"
+	"github.com/google/go-github/v74/github"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -123,7 +127,7 @@ func TestGitHubHandler_HandleWebhook_GitHubLibraryIntegration(t *testing.T) {
 			if !tt.expectError {
 				cloudTasksService = &mockCloudTasksService{}
 			}
-			handler := NewGitHubHandler(cloudTasksService, nil, nil, nil, tt.webhookSecret, testEmojiConfig())
+			handler := NewGitHubHandler(cloudTasksService, nil, nil, nil, tt.webhookSecret, testEmojiConfig(), GitHubHandlerOptions{})
 
 			req, _ := http.NewRequestWithContext(context.Background(), http.MethodPost, "/webhooks/github", bytes.NewBufferString(tt.body))
 			for key, values := range tt.setupHeaders() {
@@ -147,6 +151,100 @@ func TestGitHubHandler_HandleWebhook_GitHubLibraryIntegration(t *testing.T) {
 	}
 }
 
+// TestGitHubHandler_validateWebhookSignature tests accepting deliveries signed with the previous webhook secret
+// while it's being rotated out, and reporting which secret matched.
+func TestGitHubHandler_validateWebhookSignature(t *testing.T) {
+	body := `{"action":"opened","repository":{"name":"test"}}`
+	sign := func(secret string) string {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(body))
+		return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	}
+
+	tests := []struct {
+		name           string
+		secret         string
+		previousSecret string
+		signature      string
+		expectedSecret string
+		expectError    bool
+	}{
+		{"Current secret", "new-secret", "old-secret", sign("new-secret"), webhookSecretPrimary, false},
+		{"Previous secret during rotation", "new-secret", "old-secret", sign("old-secret"), webhookSecretPrevious, false},
+		{"Previous secret after rotation", "new-secret", "", sign("old-secret"), "", true},
+		{"Unknown secret", "new-secret", "old-secret", sign("other-secret"), "", true},
+		{"No secret configured", "", "", "", webhookSecretNone, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewGitHubHandler(nil, nil, nil, nil, tt.secret, testEmojiConfig(), GitHubHandlerOptions{
+				PreviousWebhookSecret: tt.previousSecret,
+			})
+
+			req, _ := http.NewRequestWithContext(context.Background(), http.MethodPost, "/webhooks/github", bytes.NewBufferString(body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.signature != "" {
+				req.Header.Set("X-Hub-Signature-256", tt.signature)
+			}
+
+			payload, matchedSecret, err := handler.validateWebhookSignature(req)
+			if tt.expectError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedSecret, matchedSecret)
+			assert.JSONEq(t, body, string(payload))
+		})
+	}
+}
+
+// TestGitHubHandler_validateWebhookSignature_EnterpriseServer tests that webhooks naming a GitHub Enterprise Server
+// instance are checked against that instance's secret only.
+func TestGitHubHandler_validateWebhookSignature_EnterpriseServer(t *testing.T) {
+	body := `{"action":"opened","repository":{"name":"test"}}`
+	sign := func(secret string) string {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(body))
+		return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	}
+
+	tests := []struct {
+		name        string
+		host        string
+		signature   string
+		expectError bool
+	}{
+		{"Instance secret", "github.example.com", sign("ghes-secret"), false},
+		{"Host header is case insensitive", "GitHub.Example.com", sign("ghes-secret"), false},
+		{"github.com secret", "github.example.com", sign("dotcom-secret"), true},
+		{"Unknown instance", "other.example.com", sign("ghes-secret"), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewGitHubHandler(nil, nil, nil, nil, "dotcom-secret", testEmojiConfig(), GitHubHandlerOptions{
+				EnterpriseWebhookSecrets: map[string]string{"github.example.com": "ghes-secret"},
+			})
+
+			req, _ := http.NewRequestWithContext(context.Background(), http.MethodPost, "/webhooks/github", bytes.NewBufferString(body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-Hub-Signature-256", tt.signature)
+			req.Header.Set("X-GitHub-Enterprise-Host", tt.host)
+
+			payload, matchedSecret, err := handler.validateWebhookSignature(req)
+			if tt.expectError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, webhookSecretEnterprise, matchedSecret)
+			assert.JSONEq(t, body, string(payload))
+		})
+	}
+}
+
 // TestGitHubHandler_HandleWebhook_SecurityHeaders tests the HTTP-level header validation
 // in the GitHub webhook handler. This ensures required headers (X-Github-Event, X-Github-Delivery)
 // are present and properly validated before processing.
@@ -207,7 +305,7 @@ func TestGitHubHandler_HandleWebhook_SecurityHeaders(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			handler := NewGitHubHandler(nil, nil, nil, nil, "", testEmojiConfig())
+			handler := NewGitHubHandler(nil, nil, nil, nil, "", testEmojiConfig(), GitHubHandlerOptions{})
 
 			body := `{"action":"opened","repository":{"name":"test"}}`
 			req, _ := http.NewRequestWithContext(context.Background(), http.MethodPost, "/webhooks/github", bytes.NewBufferString(body))
@@ -236,7 +334,7 @@ func TestGitHubHandler_HandleWebhook_SecurityHeaders(t *testing.T) {
 func TestGitHubHandler_HandleWebhook_BodyReading(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	handler := NewGitHubHandler(nil, nil, nil, nil, "", testEmojiConfig())
+	handler := NewGitHubHandler(nil, nil, nil, nil, "", testEmojiConfig(), GitHubHandlerOptions{})
 
 	// Create request with body that causes read error
 	req, _ := http.NewRequestWithContext(context.Background(), http.MethodPost, "/webhooks/github", &errorReader{})
@@ -290,10 +388,76 @@ func TestGitHubHandler_validateWebhookPayload(t *testing.T) {
 			expectedErr: "",
 		},
 		{
-			name:        "Unsupported event type",
+			name:        "Valid repository event",
+			eventType:   "repository",
+			payload:     []byte(`{"action":"renamed","repository":{"name":"test"}}`),
+			expectedErr: "",
+		},
+		{
+			name:        "Valid check_suite event",
+			eventType:   "check_suite",
+			payload:     []byte(`{"action":"completed","check_suite":{"conclusion":"failure"},"repository":{"name":"test"}}`),
+			expectedErr: "",
+		},
+		{
+			name:        "Valid issue_comment event",
+			eventType:   "issue_comment",
+			payload:     []byte(`{"action":"created","issue":{"number":1},"comment":{"id":2},"repository":{"name":"test"}}`),
+			expectedErr: "",
+		},
+		{
+			name:        "Valid issues event",
+			eventType:   "issues",
+			payload:     []byte(`{"action":"closed","issue":{"number":1,"state":"closed"},"repository":{"name":"test"}}`),
+			expectedErr: "",
+		},
+		{
+			name:        "Valid push event",
 			eventType:   "push",
-			payload:     []byte(`{"ref":"refs/heads/main"}`),
-			expectedErr: "unsupported event type: push",
+			payload:     []byte(`{"ref":"refs/heads/main","after":"abc123","repository":{"name":"test"}}`),
+			expectedErr: "",
+		},
+		{
+			name:        "Valid status event",
+			eventType:   "status",
+			payload:     []byte(`{"sha":"abc123","state":"success","repository":{"name":"test"}}`),
+			expectedErr: "",
+		},
+		{
+			name:        "Status event missing sha",
+			eventType:   "status",
+			payload:     []byte(`{"state":"success","repository":{"name":"test"}}`),
+			expectedErr: "missing required field: sha",
+		},
+		{
+			name:        "Valid create event",
+			eventType:   "create",
+			payload:     []byte(`{"ref":"v1.0.0","ref_type":"tag","repository":{"name":"test"}}`),
+			expectedErr: "",
+		},
+		{
+			name:        "Create event missing ref",
+			eventType:   "create",
+			payload:     []byte(`{"ref_type":"tag","repository":{"name":"test"}}`),
+			expectedErr: "missing required field: ref",
+		},
+		{
+			name:        "Valid projects v2 item event",
+			eventType:   "projects_v2_item",
+			payload:     []byte(`{"action":"edited","projects_v2_item":{"content_type":"PullRequest"},"installation":{"id":1}}`),
+			expectedErr: "",
+		},
+		{
+			name:        "Projects v2 item event missing installation",
+			eventType:   "projects_v2_item",
+			payload:     []byte(`{"action":"edited","projects_v2_item":{"content_type":"PullRequest"}}`),
+			expectedErr: "missing required field: installation",
+		},
+		{
+			name:        "Unsupported event type",
+			eventType:   "fork",
+			payload:     []byte(`{"forkee":{"name":"test"}}`),
+			expectedErr: "unsupported event type: fork",
 		},
 		{
 			name:        "Invalid JSON payload",
@@ -382,3 +546,517 @@ func TestGitHubHandler_validateWebhookPayload(t *testing.T) {
 		})
 	}
 }
+
+func TestGitHubHandler_processIssuesEvent_IgnoresOtherActions(t *testing.T) {
+	handler := &GitHubHandler{}
+
+	for _, action := range []string{"opened", "edited", "labeled", "assigned"} {
+		payload := []byte(`{"action":"` + action + `","issue":{"number":7},"repository":{"full_name":"owner/repo"}}`)
+		require.NoError(t, handler.processIssuesEvent(context.Background(), payload), action)
+	}
+
+	require.Error(t, handler.processIssuesEvent(context.Background(), []byte(`{`)))
+}
+
+func TestGitHubHandler_processRepositoryEvent_IgnoresOtherActions(t *testing.T) {
+	handler := &GitHubHandler{}
+
+	for _, action := range []string{"created", "edited", "archived", "publicized"} {
+		payload := []byte(`{"action":"` + action + `","repository":{"name":"repo","full_name":"owner/repo","owner":{"login":"owner"}}}`)
+		require.NoError(t, handler.processRepositoryEvent(context.Background(), payload), action)
+	}
+
+	// Renames that don't say what the repository was called are skipped
+	payload := []byte(`{"action":"renamed","repository":{"name":"repo","full_name":"owner/repo","owner":{"login":"owner"}}}`)
+	require.NoError(t, handler.processRepositoryEvent(context.Background(), payload))
+
+	require.Error(t, handler.processRepositoryEvent(context.Background(), []byte(`{`)))
+}
+
+func TestPreviousRepoFullName(t *testing.T) {
+	tests := []struct {
+		name     string
+		payload  string
+		expected string
+	}{
+		{
+			name:     "renamed",
+			payload:  `{"action":"renamed","changes":{"repository":{"name":{"from":"old-name"}}}}`,
+			expected: "owner/old-name",
+		},
+		{
+			name:     "transferred from an organization",
+			payload:  `{"action":"transferred","changes":{"owner":{"from":{"organization":{"login":"old-org"}}}}}`,
+			expected: "old-org/repo",
+		},
+		{
+			name:     "transferred from a user",
+			payload:  `{"action":"transferred","changes":{"owner":{"from":{"user":{"login":"alice"}}}}}`,
+			expected: "alice/repo",
+		},
+		{
+			name:     "no changes",
+			payload:  `{"action":"renamed"}`,
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var payload github.RepositoryEvent
+			require.NoError(t, json.Unmarshal([]byte(tt.payload), &payload))
+			payload.Repo = &github.Repository{
+				Name:     github.Ptr("repo"),
+				FullName: github.Ptr("owner/repo"),
+				Owner:    &github.User{Login: github.Ptr("owner")},
+			}
+			assert.Equal(t, tt.expected, previousRepoFullName(&payload))
+		})
+	}
+}
+
+func TestBatchTrackedMessageIDs(t *testing.T) {
+	assert.Empty(t, batchTrackedMessageIDs(nil))
+
+	messages := make([]*models.TrackedMessage, repositoryMessageBatchSize+1)
+	for i := range messages {
+		messages[i] = &models.TrackedMessage{ID: fmt.Sprintf("msg-%d", i)}
+	}
+	batches := batchTrackedMessageIDs(messages)
+	require.Len(t, batches, 2)
+	assert.Len(t, batches[0], repositoryMessageBatchSize)
+	assert.Equal(t, []string{fmt.Sprintf("msg-%d", repositoryMessageBatchSize)}, batches[1])
+}
+
+func TestGitHubHandler_channelDirectiveChanged(t *testing.T) {
+	handler := &GitHubHandler{slackService: &services.SlackService{}}
+
+	edit := func(oldBody string) *github.PullRequestEvent {
+		return &github.PullRequestEvent{
+			Changes: &github.EditChange{Body: &github.EditBody{From: github.Ptr(oldBody)}},
+		}
+	}
+
+	assert.False(t, handler.channelDirectiveChanged(&github.PullRequestEvent{}, "backend"), "title-only edit")
+	assert.False(t, handler.channelDirectiveChanged(edit("Fix bug\n!review: #backend"), "backend"), "directive unchanged")
+	assert.True(t, handler.channelDirectiveChanged(edit("Fix bug\n!review: #frontend"), "backend"), "directive changed")
+	assert.True(t, handler.channelDirectiveChanged(edit("Fix bug"), "backend"), "directive added")
+}
+
+func TestChannelsMatch(t *testing.T) {
+	tests := []struct {
+		name       string
+		storedName string
+		storedID   string
+		newChannel string
+		strict     bool
+		expected   bool
+	}{
+		{
+			name:       "heuristic matches by name",
+			storedName: "engineering",
+			storedID:   "C1234567890",
+			newChannel: "engineering",
+			expected:   true,
+		},
+		{
+			name:       "heuristic matches by ID",
+			storedName: "engineering",
+			storedID:   "C1234567890",
+			newChannel: "C1234567890",
+			expected:   true,
+		},
+		{
+			name:       "heuristic matches reused name from another channel",
+			storedName: "old-name",
+			storedID:   "C1234567890",
+			newChannel: "old-name",
+			expected:   true,
+		},
+		{
+			name:       "strict matches by ID",
+			storedName: "engineering",
+			storedID:   "C1234567890",
+			newChannel: "C1234567890",
+			strict:     true,
+			expected:   true,
+		},
+		{
+			name:       "strict ignores matching name",
+			storedName: "old-name",
+			storedID:   "C1234567890",
+			newChannel: "old-name",
+			strict:     true,
+			expected:   false,
+		},
+		{
+			name:       "strict rejects different ID",
+			storedName: "engineering",
+			storedID:   "C1234567890",
+			newChannel: "C0987654321",
+			strict:     true,
+			expected:   false,
+		},
+		{
+			name:       "strict requires stored ID",
+			storedName: "engineering",
+			newChannel: "C1234567890",
+			strict:     true,
+			expected:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, channelsMatch(tt.storedName, tt.storedID, tt.newChannel, tt.strict))
+		})
+	}
+}
+
+func TestGitHubHandler_determineTargetChannels_Trace(t *testing.T) {
+	repo := &models.Repo{WorkspaceID: "T123", RepoFullName: "org/api"}
+	tests := []struct {
+		name             string
+		user             *models.User
+		annotatedChannel string
+		expectChannels   []string
+		expectTrace      string
+	}{
+		{
+			name:             "annotated channel wins",
+			user:             &models.User{SlackTeamID: "T123", DefaultChannel: "C1", NotificationsEnabled: true},
+			annotatedChannel: "frontend",
+			expectChannels:   []string{"frontend"},
+			expectTrace:      "Routed to #frontend from the channel directive or policy",
+		},
+		{
+			name:           "author default channel",
+			user:           &models.User{SlackTeamID: "T123", DefaultChannel: "C1", NotificationsEnabled: true},
+			expectChannels: []string{"C1"},
+			expectTrace:    "Routed to the author's default channel C1",
+		},
+		{
+			name: "author default channels fan out",
+			user: &models.User{
+				SlackTeamID: "T123", DefaultChannel: "C1", DefaultChannels: []string{"C1", "C2"}, NotificationsEnabled: true,
+			},
+			expectChannels: []string{"C1", "C2"},
+			expectTrace:    "Routed to the author's default channels C1, C2",
+		},
+		{
+			name: "author channels for the repository replace the defaults",
+			user: &models.User{
+				SlackTeamID: "T123", DefaultChannel: "C1", NotificationsEnabled: true,
+				RepoChannels: []models.RepoChannelOverride{{RepoFullName: "org/api", Channels: []string{"backend", "C3"}}},
+			},
+			expectChannels: []string{"backend", "C3"},
+			expectTrace:    "Routed to the author's channels for org/api: backend, C3",
+		},
+		{
+			name:        "no default channel",
+			user:        &models.User{SlackTeamID: "T123", NotificationsEnabled: true},
+			expectTrace: "No channel: the author has no default channel",
+		},
+		{
+			name:        "unknown author",
+			expectTrace: "No channel: the author hasn't connected their GitHub account and the PR has no channel directive",
+		},
+		{
+			name:        "author in another workspace",
+			user:        &models.User{SlackTeamID: "T999", DefaultChannel: "C1", NotificationsEnabled: true},
+			expectTrace: "No channel: the author's default channel is in another workspace",
+		},
+		{
+			name:        "notifications disabled",
+			user:        &models.User{SlackTeamID: "T123", DefaultChannel: "C1"},
+			expectTrace: "No channel: the author has notifications disabled",
+		},
+	}
+
+	h := &GitHubHandler{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trace := &routingTrace{}
+			channels := h.determineTargetChannels(context.Background(), repo, tt.user, tt.annotatedChannel, trace)
+			assert.Equal(t, tt.expectChannels, channels)
+			assert.Equal(t, []string{tt.expectTrace}, []string(*trace))
+
+			// A nil trace must be safe for the real notification path
+			assert.Equal(t, tt.expectChannels, h.determineTargetChannels(context.Background(), repo, tt.user, tt.annotatedChannel, nil))
+		})
+	}
+}
+
+func TestGitHubHandler_determineTargetChannels_RepoDefaultChannel(t *testing.T) {
+	repo := &models.Repo{WorkspaceID: "T123", RepoFullName: "org/api", DefaultChannel: "C9"}
+	tests := []struct {
+		name             string
+		user             *models.User
+		annotatedChannel string
+		expectChannels   []string
+		expectTrace      string
+	}{
+		{
+			name:             "annotated channel wins",
+			user:             &models.User{SlackTeamID: "T123", DefaultChannel: "C1", NotificationsEnabled: true},
+			annotatedChannel: "frontend",
+			expectChannels:   []string{"frontend"},
+			expectTrace:      "Routed to #frontend from the channel directive or policy",
+		},
+		{
+			name:           "replaces the author's default channel",
+			user:           &models.User{SlackTeamID: "T123", DefaultChannel: "C1", NotificationsEnabled: true},
+			expectChannels: []string{"C9"},
+			expectTrace:    "Routed to the repository's default channel C9",
+		},
+		{
+			name: "author channels for the repository win",
+			user: &models.User{
+				SlackTeamID: "T123", DefaultChannel: "C1", NotificationsEnabled: true,
+				RepoChannels: []models.RepoChannelOverride{{RepoFullName: "org/api", Channels: []string{"C3"}}},
+			},
+			expectChannels: []string{"C3"},
+			expectTrace:    "Routed to the author's channels for org/api: C3",
+		},
+		{
+			name:           "unknown author",
+			expectChannels: []string{"C9"},
+			expectTrace:    "Routed to the repository's default channel C9",
+		},
+		{
+			name:        "notifications disabled",
+			user:        &models.User{SlackTeamID: "T123", DefaultChannel: "C1"},
+			expectTrace: "No channel: the author has notifications disabled",
+		},
+	}
+
+	h := &GitHubHandler{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trace := &routingTrace{}
+			channels := h.determineTargetChannels(context.Background(), repo, tt.user, tt.annotatedChannel, trace)
+			assert.Equal(t, tt.expectChannels, channels)
+			assert.Equal(t, []string{tt.expectTrace}, []string(*trace))
+
+			// A nil trace must be safe for the real notification path
+			assert.Equal(t, tt.expectChannels, h.determineTargetChannels(context.Background(), repo, tt.user, tt.annotatedChannel, nil))
+		})
+	}
+}
+
+func TestGitHubHandler_applyRoutingRules_Precedence(t *testing.T) {
+	repo := &models.Repo{
+		WorkspaceID: "T123",
+		RoutingRules: []models.RoutingRule{
+			{Label: "infra", Channel: "platform"},
+			{Label: "docs", Channel: "writers"},
+		},
+	}
+	author := &models.User{SlackTeamID: "T123", DefaultChannel: "C1", NotificationsEnabled: true}
+
+	tests := []struct {
+		name             string
+		repo             *models.Repo
+		labels           []string
+		annotatedChannel string
+		expectChannel    string
+		expectTrace      []string
+	}{
+		{
+			name:             "annotated channel wins over a matching rule",
+			repo:             repo,
+			labels:           []string{"infra"},
+			annotatedChannel: "frontend",
+			expectChannel:    "frontend",
+			expectTrace: []string{
+				"Routing rules not evaluated: a channel was already chosen",
+				"Routed to #frontend from the channel directive or policy",
+			},
+		},
+		{
+			name:          "matching rule wins over the author's default channel",
+			repo:          repo,
+			labels:        []string{"Docs", "infra"},
+			expectChannel: "platform",
+			expectTrace: []string{
+				`Routing rule for label "infra" routes to #platform`,
+				"Routed to #platform from the channel directive or policy",
+			},
+		},
+		{
+			name:          "no matching rule falls back to the author's default channel",
+			repo:          repo,
+			labels:        []string{"bug"},
+			expectChannel: "C1",
+			expectTrace:   []string{"No routing rule matched", "Routed to the author's default channel C1"},
+		},
+		{
+			name:          "no rules",
+			repo:          &models.Repo{WorkspaceID: "T123"},
+			labels:        []string{"infra"},
+			expectChannel: "C1",
+			expectTrace:   []string{"Routed to the author's default channel C1"},
+		},
+	}
+
+	h := &GitHubHandler{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pr := &github.PullRequest{Number: github.Ptr(1)}
+			for _, label := range tt.labels {
+				pr.Labels = append(pr.Labels, &github.Label{Name: github.Ptr(label)})
+			}
+			payload := &github.PullRequestEvent{PullRequest: pr}
+
+			trace := &routingTrace{}
+			channel := h.applyRoutingRules(context.Background(), payload, tt.repo, tt.annotatedChannel, trace)
+			channels := h.determineTargetChannels(context.Background(), tt.repo, author, channel, trace)
+			assert.Equal(t, []string{tt.expectChannel}, channels)
+			assert.Equal(t, tt.expectTrace, []string(*trace))
+		})
+	}
+}
+
+func TestProjectColumnChange(t *testing.T) {
+	tests := []struct {
+		name          string
+		payload       string
+		expectColumn  string
+		expectChanged bool
+	}{
+		{
+			name: "status moved to another column",
+			payload: `{"action":"edited","changes":{"field_value":{"field_name":"Status","field_type":"single_select",` +
+				`"from":{"name":"Todo"},"to":{"id":"abc","name":"In Review"}}}}`,
+			expectColumn:  "In Review",
+			expectChanged: true,
+		},
+		{
+			name:          "status cleared",
+			payload:       `{"action":"edited","changes":{"field_value":{"field_name":"Status","to":null}}}`,
+			expectColumn:  "",
+			expectChanged: true,
+		},
+		{
+			name:          "other field edited",
+			payload:       `{"action":"edited","changes":{"field_value":{"field_name":"Priority","to":{"name":"High"}}}}`,
+			expectChanged: false,
+		},
+		{
+			name:          "edit without field value change",
+			payload:       `{"action":"edited","changes":{}}`,
+			expectChanged: false,
+		},
+		{
+			name:          "item archived",
+			payload:       `{"action":"archived"}`,
+			expectColumn:  "",
+			expectChanged: true,
+		},
+		{
+			name:          "item reordered",
+			payload:       `{"action":"reordered"}`,
+			expectChanged: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var event projectsV2ItemEvent
+			require.NoError(t, json.Unmarshal([]byte(tt.payload), &event))
+
+			column, changed := projectColumnChange(&event)
+			assert.Equal(t, tt.expectChanged, changed)
+			assert.Equal(t, tt.expectColumn, column)
+		})
+	}
+}
+
+func TestPRMetadata(t *testing.T) {
+	var payload github.PullRequestEvent
+	require.NoError(t, json.Unmarshal([]byte(`{"action":"labeled","pull_request":{
+		"labels":[{"name":"bug","color":"d73a4a"},{"name":"security","color":"ffffff"}],
+		"milestone":{"title":"v2.0"}}}`), &payload))
+
+	metadata := prMetadata(&payload, nil)
+	assert.Equal(t, []services.PRLabel{{Name: "bug", Color: "d73a4a"}, {Name: "security", Color: "ffffff"}}, metadata.Labels)
+	assert.Equal(t, "v2.0", metadata.Milestone)
+	assert.Equal(t, []string{"bug", "security"}, prLabelNames(&payload))
+
+	metadata = prMetadata(&payload, &models.ChannelConfig{ProjectContext: true})
+	assert.Empty(t, metadata.Milestone, "project context channels show the milestone on their own line")
+
+	payload.Action = github.Ptr(PRActionDemilestoned)
+	assert.Empty(t, prMilestone(&payload), "demilestoned events clear the milestone")
+}
+
+func TestGitHubHandler_hasSkipLabel(t *testing.T) {
+	var payload github.PullRequestEvent
+	require.NoError(t, json.Unmarshal([]byte(`{"action":"labeled","pull_request":{
+		"labels":[{"name":"bug"},{"name":"No-Slack"}]}}`), &payload))
+
+	assert.False(t, (&GitHubHandler{}).hasSkipLabel(&payload), "no skip label is configured")
+	assert.False(t, (&GitHubHandler{skipLabel: "skip-slack"}).hasSkipLabel(&payload))
+
+	handler := &GitHubHandler{skipLabel: "no-slack"}
+	assert.True(t, handler.hasSkipLabel(&payload), "label names are case-insensitive")
+	assert.True(t, handler.isSkipLabel("NO-SLACK"))
+	assert.False(t, handler.isSkipLabel("bug"))
+}
+
+func TestPRHasNoReviewers(t *testing.T) {
+	pr := &github.PullRequest{State: github.Ptr("open")}
+	assert.True(t, prHasNoReviewers(pr, &services.PRDirectives{}))
+	assert.False(t, prHasNoReviewers(pr, &services.PRDirectives{UsersToCC: []string{"octocat"}}), "CCs count as reviewers")
+
+	pr.RequestedTeams = []*github.Team{{Slug: github.Ptr("backend")}}
+	assert.False(t, prHasNoReviewers(pr, &services.PRDirectives{}), "requested teams count as reviewers")
+
+	draft := &github.PullRequest{State: github.Ptr("open"), Draft: github.Ptr(true)}
+	assert.False(t, prHasNoReviewers(draft, &services.PRDirectives{}), "drafts aren't expected to have reviewers")
+}
+
+func TestGitHubHandler_messageNeedsUpdate_Labels(t *testing.T) {
+	h := &GitHubHandler{}
+	changes := &PRUpdateChanges{MetadataChanged: true, NewLabels: []string{"bug"}, NewMilestone: "v2.0"}
+
+	rich := &models.TrackedMessage{MessageFormat: models.MessageFormatRich, PRLabels: []string{"bug"}}
+	needsUpdate, reasons := h.messageNeedsUpdate(rich, changes)
+	assert.True(t, needsUpdate)
+	assert.Equal(t, []string{"labels"}, reasons)
+
+	rich.PRMilestone = "v2.0"
+	needsUpdate, _ = h.messageNeedsUpdate(rich, changes)
+	assert.False(t, needsUpdate, "the message already shows the PR's labels and milestone")
+
+	compact := &models.TrackedMessage{MessageFormat: models.MessageFormatRich, Compact: true}
+	needsUpdate, _ = h.messageNeedsUpdate(compact, changes)
+	assert.False(t, needsUpdate, "one-line messages don't show labels")
+
+	updated := h.createUpdatedMessage(&models.TrackedMessage{}, changes)
+	assert.Equal(t, []string{"bug"}, updated.PRLabels)
+	assert.Equal(t, "v2.0", updated.PRMilestone)
+}
+
+func TestAssignmentNotifications(t *testing.T) {
+	job := &models.AssignmentJob{PRAction: PRActionAssigned, AssigneeLogin: "alice", AssignedBy: "bob_<x>"}
+	assert.Equal(t, ":bust_in_silhouette: Assigned to <@U1> by bob_&lt;x&gt;", assignmentNoteText(job, "<@U1>"))
+
+	job.PRAction = PRActionUnassigned
+	assert.Equal(t, ":heavy_minus_sign: @alice unassigned by bob_&lt;x&gt;", assignmentNoteText(job, "@alice"))
+
+	assert.False(t, wantsAssignmentDM(nil))
+	assert.False(t, wantsAssignmentDM(&models.User{Verified: true, SlackUserID: "U1"}), "assignment DMs are opt-in")
+	assert.False(t, wantsAssignmentDM(&models.User{SlackUserID: "U1", AssignmentDMs: true}), "unverified users aren't DMed")
+	assert.True(t, wantsAssignmentDM(&models.User{Verified: true, SlackUserID: "U1", AssignmentDMs: true}))
+}
+
+func TestRecordOutOfOffice(t *testing.T) {
+	ccDelegates := make(map[string]string)
+	assert.False(t, recordOutOfOffice(&models.User{SlackUserID: "U1", OOODelegate: "U2"}, "Alice", ccDelegates))
+	assert.Empty(t, ccDelegates, "users who aren't out of office keep their mention")
+
+	assert.True(t, recordOutOfOffice(&models.User{SlackUserID: "U1", OutOfOffice: true, OOODelegate: "U2"}, "Alice", ccDelegates))
+	assert.True(t, recordOutOfOffice(&models.User{SlackUserID: "U3", OutOfOffice: true, OOODelegate: "U3"}, "bob", ccDelegates))
+	assert.Equal(t, map[string]string{"alice": "U2", "bob": ""}, ccDelegates, "users can't delegate to themselves")
+}
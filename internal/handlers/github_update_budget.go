@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/go-github/v74/github"
+	"github.com/google/uuid"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+)
+
+// prUpdateBudgetWindow is how long a PR's update budget lasts before it's replenished.
+const prUpdateBudgetWindow = time.Hour
+
+// spendPRUpdateBudget records a Slack message update for a PR, returning false if the PR has used up its
+// update budget and the update should be dropped. The first update dropped in a window schedules a
+// reconciliation pass for when the window ends, which brings the PR's messages up to date in one go.
+// Lookup failures are logged and let the update through, since the budget is best effort.
+func (h *GitHubHandler) spendPRUpdateBudget(ctx context.Context, repoFullName string, prNumber int) bool {
+	if h.prUpdateBudget <= 0 {
+		return true
+	}
+
+	allowed, reconcileAt, err := h.firestoreService.SpendPRUpdateBudget(
+		ctx, repoFullName, prNumber, h.prUpdateBudget, prUpdateBudgetWindow,
+	)
+	if err != nil {
+		log.Warn(ctx, "Failed to check PR update budget, updating anyway", "error", err)
+		return true
+	}
+	if allowed {
+		return true
+	}
+
+	log.Info(ctx, "PR update budget spent, dropping update until the reconciliation pass",
+		"budget_per_hour", h.prUpdateBudget,
+	)
+	if !reconcileAt.IsZero() {
+		h.scheduleUpdateReconciliation(ctx, repoFullName, prNumber, reconcileAt)
+	}
+	return false
+}
+
+// scheduleUpdateReconciliation enqueues the reaction sync job that reconciles a PR's messages once its
+// update budget is replenished.
+func (h *GitHubHandler) scheduleUpdateReconciliation(ctx context.Context, repoFullName string, prNumber int, at time.Time) {
+	reactionSyncJob := &models.ReactionSyncJob{
+		ID:           uuid.New().String(),
+		PRNumber:     prNumber,
+		RepoFullName: repoFullName,
+		Reconcile:    true,
+		TraceID:      traceIDForNewJob(ctx),
+	}
+
+	jobPayload, err := json.Marshal(reactionSyncJob)
+	if err != nil {
+		log.Error(ctx, "Failed to marshal update reconciliation job", "error", err)
+		return
+	}
+
+	job := &models.Job{
+		ID:        reactionSyncJob.ID,
+		Type:      models.JobTypeReactionSync,
+		TraceID:   reactionSyncJob.TraceID,
+		Payload:   jobPayload,
+		NotBefore: at,
+	}
+	if err := h.cloudTasksService.EnqueueJob(ctx, job); err != nil {
+		log.Error(ctx, "Failed to schedule update reconciliation, messages stay stale until the PR's next update",
+			"error", err,
+		)
+		return
+	}
+
+	log.Info(ctx, "Scheduled update reconciliation for PR",
+		"job_id", job.ID,
+		"reconcile_at", at,
+	)
+}
+
+// reconcilePRContent brings the title and CCs of a PR's messages up to date with the PR, catching up on edits
+// dropped while its update budget was spent.
+func (h *GitHubHandler) reconcilePRContent(
+	ctx context.Context, repoFullName string, pr *github.PullRequest, trackedMessages []*models.TrackedMessage,
+) {
+	payload := &github.PullRequestEvent{
+		Action:      github.Ptr(PRActionEdited),
+		PullRequest: pr,
+		Repo:        &github.Repository{FullName: github.Ptr(repoFullName)},
+	}
+
+	// Edit events carry the previous title; the title the messages were last rendered with stands in for it
+	for _, msg := range trackedMessages {
+		if msg.MessageSource == models.MessageSourceBot && !msg.DeletedByUser && msg.PRTitle != "" && msg.PRTitle != pr.GetTitle() {
+			payload.Changes = &github.EditChange{Title: &github.EditTitle{From: github.Ptr(msg.PRTitle)}}
+			break
+		}
+	}
+
+	directives := h.slackService.ParsePRDirectives(pr.GetBody())
+	changes := h.detectPRChanges(ctx, payload, directives)
+	if err := h.updateMessagesForPRChanges(ctx, payload, changes, directives); err != nil {
+		log.Error(ctx, "Failed to reconcile PR message content", "error", err)
+	}
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+)
+
+// recordWebhookEvent keeps a received GitHub webhook in the webhook event log, so it can be inspected and
+// replayed with the toolbox when debugging missed notifications.
+// Failures are logged rather than returned, as the log mustn't stop webhooks being processed.
+func (h *GitHubHandler) recordWebhookEvent(ctx context.Context, eventType, deliveryID string, header http.Header, payload []byte) {
+	if h.eventRetention <= 0 {
+		return
+	}
+
+	now := time.Now()
+	event := &models.WebhookEvent{
+		ID:         deliveryID,
+		EventType:  eventType,
+		Headers:    webhookEventHeaders(header),
+		ReceivedAt: now,
+		ExpiresAt:  now.Add(h.eventRetention),
+	}
+	if err := event.SetPayload(payload); err != nil {
+		log.Warn(ctx, "Failed to compress webhook for the event log", "error", err)
+		return
+	}
+
+	if err := h.firestoreService.RecordWebhookEvent(ctx, event); err != nil {
+		log.Warn(ctx, "Failed to record webhook in the event log", "error", err, "payload_size", event.PayloadSize)
+	}
+}
+
+// webhookEventHeaders returns the request headers kept with a webhook event: GitHub's own headers, which identify
+// the delivery, hook and installation target, plus the user agent and content type.
+func webhookEventHeaders(header http.Header) map[string]string {
+	headers := make(map[string]string)
+	for name, values := range header {
+		if len(values) == 0 {
+			continue
+		}
+		if strings.HasPrefix(name, "X-Github-") || strings.HasPrefix(name, "X-Hub-") || name == "User-Agent" || name == "Content-Type" {
+			headers[name] = values[0]
+		}
+	}
+	return headers
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/selfcheck"
+)
+
+// readinessCacheTTL is how long a readiness report is reused, so frequent load balancer probes don't
+// call every dependency (and Slack's rate-limited auth.test) on each request.
+const readinessCacheTTL = 10 * time.Second
+
+// Overall readiness statuses reported by /readyz.
+const (
+	readinessReady    = "ready"
+	readinessDegraded = "degraded" // Only soft checks failed, e.g. one workspace's revoked Slack token
+	readinessNotReady = "not_ready"
+)
+
+// HealthHandler serves the liveness and readiness endpoints.
+type HealthHandler struct {
+	checks  func(ctx context.Context) []selfcheck.Check
+	timeout time.Duration
+
+	mu        sync.Mutex
+	report    *selfcheck.Report
+	checkedAt time.Time
+}
+
+// NewHealthHandler creates a HealthHandler that runs the given dependency checks for readiness,
+// each limited to timeout.
+func NewHealthHandler(checks func(ctx context.Context) []selfcheck.Check, timeout time.Duration) *HealthHandler {
+	return &HealthHandler{
+		checks:  checks,
+		timeout: timeout,
+	}
+}
+
+// readinessCheck is one dependency's status in the readiness response.
+type readinessCheck struct {
+	Name       string           `json:"name"`
+	Status     selfcheck.Status `json:"status"`
+	Detail     string           `json:"detail,omitempty"`
+	DurationMS int64            `json:"duration_ms"`
+}
+
+// HandleLiveness reports that the process is up and serving requests, without checking dependencies,
+// so an unavailable dependency doesn't get healthy instances restarted.
+// GET /healthz.
+func HandleLiveness(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "healthy"})
+}
+
+// HandleReadiness checks the service's dependencies concurrently and reports each one's status.
+// Responds 503 when a hard dependency (Firestore, Cloud Tasks, GitHub App credentials) is failing,
+// and 200 when only soft checks fail, so one broken Slack workspace doesn't take the service out of rotation.
+// GET /readyz.
+func (h *HealthHandler) HandleReadiness(c *gin.Context) {
+	ctx := c.Request.Context()
+	report := h.readinessReport(ctx)
+
+	status := readinessReady
+	httpStatus := http.StatusOK
+	checks := make([]readinessCheck, 0, len(report.Results))
+	for _, result := range report.Results {
+		checks = append(checks, readinessCheck{
+			Name:       result.Name,
+			Status:     result.Status,
+			Detail:     result.Detail,
+			DurationMS: result.Duration.Milliseconds(),
+		})
+		switch result.Status {
+		case selfcheck.StatusFail:
+			status = readinessNotReady
+			httpStatus = http.StatusServiceUnavailable
+		case selfcheck.StatusWarn:
+			if status == readinessReady {
+				status = readinessDegraded
+			}
+		case selfcheck.StatusOK:
+		}
+	}
+
+	if status != readinessReady {
+		log.Warn(ctx, "Readiness check found failing dependencies", "status", status)
+	}
+	c.JSON(httpStatus, gin.H{"status": status, "checks": checks})
+}
+
+// readinessReport returns the latest readiness report, running the checks again once it's older than
+// readinessCacheTTL. Concurrent probes wait for one run rather than each checking the dependencies.
+func (h *HealthHandler) readinessReport(ctx context.Context) *selfcheck.Report {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.report != nil && time.Since(h.checkedAt) < readinessCacheTTL {
+		return h.report
+	}
+	// Detached from the request so a probe that gives up doesn't cache its cancelled checks
+	checkCtx := context.WithoutCancel(ctx)
+	listCtx, cancel := context.WithTimeout(checkCtx, h.timeout)
+	checks := h.checks(listCtx)
+	cancel()
+
+	h.report = selfcheck.RunConcurrent(checkCtx, checks, h.timeout)
+	h.checkedAt = time.Now()
+	return h.report
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github-slack-notifier/internal/selfcheck"
+)
+
+var errDependencyDown = errors.New("dependency down")
+
+func TestHealthHandler_HandleReadiness(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	passing := func(context.Context) error { return nil }
+	failing := func(context.Context) error { return errDependencyDown }
+
+	tests := []struct {
+		name           string
+		checks         []selfcheck.Check
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "All dependencies healthy",
+			checks:         []selfcheck.Check{{Name: "firestore", Hard: true, Run: passing}},
+			expectedStatus: http.StatusOK,
+			expectedBody:   readinessReady,
+		},
+		{
+			name: "Soft dependency failing",
+			checks: []selfcheck.Check{
+				{Name: "firestore", Hard: true, Run: passing},
+				{Name: "slack_auth:T123", Run: failing},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   readinessDegraded,
+		},
+		{
+			name: "Hard dependency failing",
+			checks: []selfcheck.Check{
+				{Name: "firestore", Hard: true, Run: failing},
+				{Name: "slack_auth:T123", Run: failing},
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   readinessNotReady,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewHealthHandler(func(context.Context) []selfcheck.Check { return tt.checks }, time.Second)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/readyz", nil)
+			handler.HandleReadiness(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			var body struct {
+				Status string           `json:"status"`
+				Checks []readinessCheck `json:"checks"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, tt.expectedBody, body.Status)
+			require.Len(t, body.Checks, len(tt.checks))
+			for i, check := range tt.checks {
+				assert.Equal(t, check.Name, body.Checks[i].Name)
+			}
+		})
+	}
+}
+
+func TestHealthHandler_ReadinessIsCached(t *testing.T) {
+	runs := 0
+	handler := NewHealthHandler(func(context.Context) []selfcheck.Check {
+		runs++
+		return []selfcheck.Check{{Name: "firestore", Hard: true, Run: func(context.Context) error { return nil }}}
+	}, time.Second)
+
+	handler.readinessReport(context.Background())
+	handler.readinessReport(context.Background())
+	assert.Equal(t, 1, runs)
+
+	handler.checkedAt = time.Now().Add(-readinessCacheTTL)
+	handler.readinessReport(context.Background())
+	assert.Equal(t, 2, runs)
+}
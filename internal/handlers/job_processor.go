@@ -2,17 +2,22 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"math"
 	"net/http"
 	"strconv"
-	"strings"
 	"time"
 
 	"github-slack-notifier/internal/config"
 	"github-slack-notifier/internal/log"
 	"github-slack-notifier/internal/models"
+	"github-slack-notifier/internal/tracing"
 	"github.com/gin-gonic/gin"
+	"github.com/google/go-github/v74/github"
 	"github.com/slack-go/slack"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -52,7 +57,8 @@ func (jp *JobProcessor) ProcessJob(c *gin.Context) {
 			"content_type", c.ContentType(),
 			"content_length", c.Request.ContentLength,
 		)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid job payload"})
+		// Cloud Tasks would redeliver the same malformed payload, so it's acknowledged rather than retried
+		c.JSON(http.StatusOK, gin.H{"status": "failed_permanently", "error": "invalid job payload", "retryable": false})
 		return
 	}
 
@@ -73,11 +79,22 @@ func (jp *JobProcessor) ProcessJob(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), jp.config.WebhookProcessingTimeout)
 	defer cancel()
 
+	// Carry the originating webhook's trace through job processing and outbound API calls,
+	// rather than the trace Cloud Run assigns to the Cloud Tasks delivery
+	if job.TraceID != "" {
+		ctx = log.WithTraceID(ctx, job.TraceID)
+	}
+	ctx, span := tracing.Start(tracing.Extract(ctx, c.Request.Header), "job "+job.Type, trace.SpanKindConsumer,
+		attribute.String("job.id", job.ID),
+		attribute.String("job.type", job.Type),
+		attribute.String("job.retry_count", actualRetryCount),
+	)
+	defer func() { tracing.EndHTTP(span, c.Writer.Status()) }()
+
 	// Add job metadata to context for all log calls
 	ctx = log.WithFields(ctx, log.LogFields{
 		"job_id":               job.ID,
 		"job_type":             job.Type,
-		"trace_id":             job.TraceID,
 		"retry_count":          actualRetryCount,
 		"task_execution_count": c.GetHeader("X-Cloudtasks-Taskexecutioncount"),
 	})
@@ -109,19 +126,33 @@ func (jp *JobProcessor) ProcessJob(c *gin.Context) {
 
 	if err := jp.RouteJob(ctx, &job); err != nil {
 		processingTime := time.Since(startTime)
+		retryable := isJobRetryableError(err)
 		log.Error(ctx, "Failed to process job",
 			"error", err,
+			"retryable", retryable,
 			"processing_time_ms", processingTime.Milliseconds(),
 		)
 
-		if isJobRetryableError(err) {
-			c.JSON(http.StatusInternalServerError, gin.H{
+		// Slack rate limits are passed on as backpressure, for queues that honor Retry-After
+		var rateLimitErr *slack.RateLimitedError
+		switch {
+		case errors.As(err, &rateLimitErr):
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(rateLimitErr.RetryAfter.Seconds()))))
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"error":              "rate limited",
+				"retryable":          true,
+				"processing_time_ms": processingTime.Milliseconds(),
+			})
+		case retryable:
+			c.JSON(http.StatusServiceUnavailable, gin.H{
 				"error":              "processing failed",
 				"retryable":          true,
 				"processing_time_ms": processingTime.Milliseconds(),
 			})
-		} else {
-			c.JSON(http.StatusBadRequest, gin.H{
+		default:
+			// Cloud Tasks retries any non-2xx response, so permanent failures are acknowledged to stop retries
+			c.JSON(http.StatusOK, gin.H{
+				"status":             "failed_permanently",
 				"error":              "processing failed",
 				"retryable":          false,
 				"processing_time_ms": processingTime.Milliseconds(),
@@ -155,51 +186,116 @@ func (jp *JobProcessor) RouteJob(ctx context.Context, job *models.Job) error {
 		return jp.githubHandler.ProcessWorkspacePRJob(ctx, job)
 	case models.JobTypeDeleteTrackedMessage:
 		return jp.slackHandler.ProcessDeleteTrackedMessageJob(ctx, job)
+	case models.JobTypeReleaseCountdown:
+		return jp.githubHandler.ProcessReleaseCountdownJob(ctx, job)
+	case models.JobTypeChannelReport:
+		return jp.githubHandler.ProcessChannelReportJob(ctx, job)
+	case models.JobTypeDependencyRefresh:
+		return jp.githubHandler.ProcessDependencyRefreshJob(ctx, job)
+	case models.JobTypeUserDigest:
+		return jp.githubHandler.ProcessUserDigestJob(ctx, job)
+	case models.JobTypeChannelDigest:
+		return jp.githubHandler.ProcessChannelDigestJob(ctx, job)
+	case models.JobTypeReviewRequest:
+		return jp.githubHandler.ProcessReviewRequestJob(ctx, job)
+	case models.JobTypeAssignment:
+		return jp.githubHandler.ProcessAssignmentJob(ctx, job)
+	case models.JobTypeDailyDigest:
+		return jp.githubHandler.ProcessDailyDigestJob(ctx, job)
+	case models.JobTypeCIStatusSync:
+		return jp.githubHandler.ProcessCIStatusSyncJob(ctx, job)
+	case models.JobTypeReviewComment:
+		return jp.githubHandler.ProcessReviewCommentJob(ctx, job)
+	case models.JobTypeMergeConflictSync:
+		return jp.githubHandler.ProcessMergeConflictSyncJob(ctx, job)
+	case models.JobTypeMovePRNotification:
+		return jp.githubHandler.ProcessMovePRNotificationJob(ctx, job)
+	case models.JobTypeRetentionSync:
+		return jp.githubHandler.ProcessRetentionSyncJob(ctx, job)
+	case models.JobTypeDeferredNotification:
+		return jp.githubHandler.ProcessDeferredNotificationJob(ctx, job)
+	case models.JobTypePostPR:
+		return jp.githubHandler.ProcessPostPRJob(ctx, job)
+	case models.JobTypeCleanup:
+		return jp.slackHandler.ProcessCleanupJob(ctx, job)
+	case models.JobTypeUserDataExport:
+		return jp.slackHandler.ProcessUserDataExportJob(ctx, job)
+	case models.JobTypeUserDataDeletion:
+		return jp.slackHandler.ProcessUserDataDeletionJob(ctx, job)
+	case models.JobTypeNoReviewersNudge:
+		return jp.githubHandler.ProcessNoReviewersNudgeJob(ctx, job)
+	case models.JobTypeSlackComment:
+		return jp.githubHandler.ProcessSlackCommentJob(ctx, job)
+	case models.JobTypeReactionAction:
+		return jp.slackHandler.ProcessReactionActionJob(ctx, job)
 	default:
 		return models.ErrUnsupportedJobType
 	}
 }
 
-// isJobRetryableError determines whether an error should trigger a job retry.
-// Returns true for temporary errors like timeouts, rate limits, and network issues,
-// and false for permanent errors like invalid channels or authentication failures.
+// isJobRetryableError determines whether a failed job should be retried.
+// Errors marked models.ErrRetryable or models.ErrPermanent are classified as marked. Otherwise malformed payloads,
+// unsupported jobs, revoked or missing credentials and requests Slack or GitHub reject are permanent, and
+// everything else, e.g. timeouts, outages and network errors, is retried up to CLOUD_TASKS_MAX_ATTEMPTS times.
 func isJobRetryableError(err error) bool {
-	if errors.Is(err, context.DeadlineExceeded) {
+	switch {
+	case errors.Is(err, models.ErrPermanent):
+		return false
+	case errors.Is(err, models.ErrRetryable):
+		return true
+	case errors.Is(err, context.DeadlineExceeded):
+		return true
+	case errors.Is(err, models.ErrPRNotificationInFlight):
+		// Events deferred until a PR's notifications are posted are retried with backoff
 		return true
+	case errors.Is(err, models.ErrUnsupportedJobType), errors.Is(err, ErrUnsupportedEventType):
+		return false
+	}
+
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+		return false
 	}
 
-	var slackErr *slack.RateLimitedError
+	if retryable, ok := isAPIErrorRetryable(err); ok {
+		return retryable
+	}
+	return true
+}
+
+// isAPIErrorRetryable classifies errors returned by the Slack and GitHub APIs. ok is false for other errors.
+func isAPIErrorRetryable(err error) (retryable, ok bool) {
+	var slackRateLimitErr *slack.RateLimitedError
+	if errors.As(err, &slackRateLimitErr) {
+		return true, true
+	}
+	var slackErr *slack.SlackErrorResponse
 	if errors.As(err, &slackErr) {
-		return true
+		return isSlackErrorRetryable(slackErr.Err), true
 	}
 
-	// Check for specific Slack errors that should be retried
-	var slackErrorResp *slack.SlackErrorResponse
-	if errors.As(err, &slackErrorResp) {
-		switch slackErrorResp.Err {
-		case "already_reacted":
-			// This should have been handled in SlackService, but if it gets here, don't retry
-			return false
-		case "channel_not_found", "invalid_channel", "invalid_auth", "account_inactive":
-			// These are permanent errors, don't retry
-			return false
-		case "internal_error", "service_unavailable":
-			// These are temporary Slack issues, retry them
-			return true
-		default:
-			// For unknown Slack errors, err on the side of not retrying to avoid infinite loops
-			return false
-		}
+	var githubRateLimitErr *github.RateLimitError
+	var githubAbuseErr *github.AbuseRateLimitError
+	if errors.As(err, &githubRateLimitErr) || errors.As(err, &githubAbuseErr) {
+		return true, true
+	}
+	var githubErr *github.ErrorResponse
+	if errors.As(err, &githubErr) && githubErr.Response != nil {
+		// Server errors are GitHub outages; anything else GitHub rejected will be rejected again
+		return githubErr.Response.StatusCode >= http.StatusInternalServerError, true
 	}
+	return false, false
+}
 
-	// Check for network/connection errors (should be retried)
-	errStr := err.Error()
-	if strings.Contains(errStr, "connection") ||
-		strings.Contains(errStr, "timeout") ||
-		strings.Contains(errStr, "dial") {
+// isSlackErrorRetryable reports whether a Slack API error is temporary. Unknown errors aren't retried, since
+// Slack reports temporary failures with a few well-known codes.
+func isSlackErrorRetryable(code string) bool {
+	switch code {
+	case "internal_error", "service_unavailable", "fatal_error", "request_timeout", "ratelimited":
 		return true
+	default:
+		// e.g. invalid_auth, account_inactive and token_revoked for revoked tokens, or channel_not_found
+		return false
 	}
-
-	// Default to not retrying for unknown errors
-	return false
 }
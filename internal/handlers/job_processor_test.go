@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-github/v74/github"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+
+	"github-slack-notifier/internal/models"
+	"github-slack-notifier/internal/services"
+)
+
+var errFirestoreUnavailable = errors.New("rpc error: code = Unavailable")
+
+func TestIsJobRetryableError(t *testing.T) {
+	var payload struct{}
+	syntaxErr := json.Unmarshal([]byte("{"), &payload)
+
+	tests := []struct {
+		name      string
+		err       error
+		retryable bool
+	}{
+		{"marked permanent", models.Permanent(errFirestoreUnavailable), false},
+		{"marked retryable", models.Retryable(&slack.SlackErrorResponse{Err: "channel_not_found"}), true},
+		{"malformed payload", fmt.Errorf("failed to unmarshal job: %w", syntaxErr), false},
+		{"invalid job", fmt.Errorf("invalid job: %w", models.Permanent(models.ErrPRNumberRequired)), false},
+		{"unsupported job type", models.ErrUnsupportedJobType, false},
+		{"workspace not installed", models.Permanent(services.ErrWorkspaceNotInstalled), false},
+		{"deadline exceeded", fmt.Errorf("failed to post: %w", context.DeadlineExceeded), true},
+		{"PR notification in flight", models.ErrPRNotificationInFlight, true},
+		{"Slack rate limit", &slack.RateLimitedError{}, true},
+		{"revoked Slack token", fmt.Errorf("failed to post: %w", &slack.SlackErrorResponse{Err: "token_revoked"}), false},
+		{"inactive Slack account", &slack.SlackErrorResponse{Err: "account_inactive"}, false},
+		{"Slack outage", &slack.SlackErrorResponse{Err: "service_unavailable"}, true},
+		{"GitHub rate limit", &github.RateLimitError{Response: &http.Response{StatusCode: http.StatusForbidden}}, true},
+		{"GitHub not found", &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusNotFound}}, false},
+		{"GitHub outage", &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusBadGateway}}, true},
+		{"unknown error", errFirestoreUnavailable, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.retryable, isJobRetryableError(tt.err))
+		})
+	}
+}
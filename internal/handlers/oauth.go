@@ -3,6 +3,7 @@ package handlers
 import (
 	"context"
 	"fmt"
+	"html"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -12,6 +13,7 @@ import (
 	"github-slack-notifier/internal/log"
 	"github-slack-notifier/internal/models"
 	"github-slack-notifier/internal/services"
+	"github-slack-notifier/internal/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/slack-go/slack"
 )
@@ -20,6 +22,10 @@ var (
 	ErrInstallationNotFoundAfterRetries = fmt.Errorf("installation not found after retries")
 )
 
+// slackBotScopes are the bot scopes requested when a workspace installs the app, matching slack-app-manifest.template.yaml.
+const slackBotScopes = "channels:read,channels:join,groups:read,chat:write,chat:write.customize,reactions:write,reactions:read," +
+	"links:read,channels:history,users:read,commands,emoji:read,usergroups:read"
+
 // OAuthHandler handles GitHub and Slack OAuth endpoints.
 type OAuthHandler struct {
 	githubAuthService     *services.GitHubAuthService
@@ -60,6 +66,34 @@ func (h *OAuthHandler) HandleGitHubLink(c *gin.Context) {
 		"handler":  "oauth_github_link",
 	})
 
+	state, ok := h.loadPendingOAuthState(ctx, c)
+	if !ok {
+		return
+	}
+	ctx = log.WithFields(ctx, log.LogFields{"state_id": state.ID})
+
+	if !h.chooseGitHubHost(ctx, c, state) {
+		return
+	}
+
+	// Generate OAuth URL and redirect
+	oauthURL, err := h.githubAuthService.GetOAuthURL(state)
+	if err != nil {
+		log.Error(ctx, "Failed to build GitHub OAuth URL", "error", err, "github_host", state.GitHubHost)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid Request",
+			"message": "Unknown GitHub host",
+		})
+		return
+	}
+
+	log.Info(ctx, "Redirecting to GitHub OAuth", "slack_user_id", state.SlackUserID, "github_host", state.GitHubHost)
+	c.Redirect(http.StatusFound, oauthURL)
+}
+
+// loadPendingOAuthState reads the OAuth state in the state query parameter, checking that it exists and hasn't
+// expired without consuming it. Responds with an error and returns false if it's missing or invalid.
+func (h *OAuthHandler) loadPendingOAuthState(ctx context.Context, c *gin.Context) (*models.OAuthState, bool) {
 	stateID := c.Query("state")
 	if stateID == "" {
 		log.Error(ctx, "Missing state parameter in OAuth link request")
@@ -67,7 +101,7 @@ func (h *OAuthHandler) HandleGitHubLink(c *gin.Context) {
 			"error":   "Invalid Request",
 			"message": "Missing required state parameter",
 		})
-		return
+		return nil, false
 	}
 
 	ctx = log.WithFields(ctx, log.LogFields{
@@ -82,7 +116,7 @@ func (h *OAuthHandler) HandleGitHubLink(c *gin.Context) {
 			"error":   "Invalid Request",
 			"message": "Invalid or expired authorization request",
 		})
-		return
+		return nil, false
 	}
 
 	// Check if state is expired
@@ -93,14 +127,10 @@ func (h *OAuthHandler) HandleGitHubLink(c *gin.Context) {
 			"error":   "Expired Request",
 			"message": "Authorization request has expired. Please try again from Slack",
 		})
-		return
+		return nil, false
 	}
 
-	// Generate OAuth URL and redirect
-	oauthURL := h.githubAuthService.GetOAuthURL(stateID)
-
-	log.Info(ctx, "Redirecting to GitHub OAuth", "slack_user_id", state.SlackUserID)
-	c.Redirect(http.StatusFound, oauthURL)
+	return state, true
 }
 
 // validateGitHubCallbackParams validates and extracts GitHub OAuth callback parameters.
@@ -198,6 +228,9 @@ func (h *OAuthHandler) createOrUpdateUserFromGitHub(
 	if err == nil && existingUser != nil {
 		// Update existing user - preserve user preferences but update GitHub data
 		user.DefaultChannel = existingUser.DefaultChannel
+		user.DefaultChannels = existingUser.DefaultChannels
+		user.RepoChannels = existingUser.RepoChannels
+		user.MutedRepos = existingUser.MutedRepos
 		user.CreatedAt = existingUser.CreatedAt
 		user.NotificationsEnabled = existingUser.NotificationsEnabled
 		user.TaggingEnabled = existingUser.TaggingEnabled
@@ -306,7 +339,7 @@ func (h *OAuthHandler) HandleGitHubCallback(c *gin.Context) {
 	installationID := c.Query("installation_id")
 	if installationID != "" {
 		log.Info(ctx, "Processing combined OAuth + installation flow", "installation_id", installationID)
-		err := h.processGitHubAppInstallation(ctx, code, stateID, installationID, state)
+		installation, githubUsername, err := h.processGitHubAppInstallation(ctx, code, stateID, installationID, state)
 		if err != nil {
 			log.Error(ctx, "Failed to process GitHub App installation", "error", err)
 			c.JSON(http.StatusInternalServerError, gin.H{
@@ -317,7 +350,7 @@ func (h *OAuthHandler) HandleGitHubCallback(c *gin.Context) {
 		}
 
 		// Redirect to success page for installation
-		h.redirectToInstallationSuccessPage(c, state.SlackTeamID, installationID)
+		h.redirectToInstallationSuccessPage(c, state.SlackTeamID, installation, githubUsername)
 		return
 	}
 
@@ -340,7 +373,7 @@ func (h *OAuthHandler) HandleGitHubCallback(c *gin.Context) {
 // Exchanges OAuth code for user info, creates/updates user record, and handles post-OAuth actions.
 func (h *OAuthHandler) processUserOAuth(ctx context.Context, code, _ string, state *models.OAuthState) (string, error) {
 	// Exchange code for GitHub user info
-	githubUser, err := h.githubAuthService.ExchangeCodeForUser(ctx, code)
+	githubUser, err := h.githubAuthService.ExchangeCodeForUserAndSaveToken(ctx, code, state)
 	if err != nil {
 		return "", fmt.Errorf("failed to exchange OAuth code for user info: %w", err)
 	}
@@ -366,13 +399,14 @@ func (h *OAuthHandler) processUserOAuth(ctx context.Context, code, _ string, sta
 
 // processGitHubAppInstallation processes combined OAuth + GitHub App installation flow.
 // Associates GitHub installation with Slack workspace and creates/updates user record.
+// Returns the installation and the GitHub username of the user who installed it.
 func (h *OAuthHandler) processGitHubAppInstallation(
 	ctx context.Context, code, _, installationID string, state *models.OAuthState,
-) error {
+) (*models.GitHubInstallation, string, error) {
 	// Parse installation ID
 	installationIDInt, err := strconv.ParseInt(installationID, 10, 64)
 	if err != nil {
-		return fmt.Errorf("invalid installation ID: %w", err)
+		return nil, "", fmt.Errorf("invalid installation ID: %w", err)
 	}
 
 	ctx = log.WithFields(ctx, log.LogFields{
@@ -381,9 +415,9 @@ func (h *OAuthHandler) processGitHubAppInstallation(
 
 	// Exchange code for GitHub user info
 	// Note: We only need user info for workspace association, not for installation access verification
-	githubUser, err := h.githubAuthService.ExchangeCodeForUser(ctx, code)
+	githubUser, err := h.githubAuthService.ExchangeCodeForUserAndSaveToken(ctx, code, state)
 	if err != nil {
-		return fmt.Errorf("failed to exchange OAuth code for user info: %w", err)
+		return nil, "", fmt.Errorf("failed to exchange OAuth code for user info: %w", err)
 	}
 
 	ctx = log.WithFields(ctx, log.LogFields{
@@ -400,7 +434,7 @@ func (h *OAuthHandler) processGitHubAppInstallation(
 	// Use retry logic to handle race condition with installation webhook
 	installation, err := h.waitForInstallationInDatabase(ctx, installationIDInt)
 	if err != nil {
-		return fmt.Errorf("installation not found in database after retries: %w", err)
+		return nil, "", fmt.Errorf("installation not found in database after retries: %w", err)
 	}
 
 	// Update installation with workspace association
@@ -412,13 +446,13 @@ func (h *OAuthHandler) processGitHubAppInstallation(
 	// Save updated installation
 	err = h.firestoreService.UpdateGitHubInstallation(ctx, installation)
 	if err != nil {
-		return fmt.Errorf("failed to update installation with workspace association: %w", err)
+		return nil, "", fmt.Errorf("failed to update installation with workspace association: %w", err)
 	}
 
 	// Create or update user
 	user, err := h.createOrUpdateUserFromGitHub(ctx, state, githubUser)
 	if err != nil {
-		return fmt.Errorf("failed to save user after installation: %w", err)
+		return nil, "", fmt.Errorf("failed to save user after installation: %w", err)
 	}
 
 	log.Info(ctx, "GitHub App installation successfully associated with workspace",
@@ -430,8 +464,22 @@ func (h *OAuthHandler) processGitHubAppInstallation(
 
 	// Handle post-OAuth actions (Slack notifications, App Home refresh)
 	h.handlePostOAuthActions(ctx, state, user, githubUser.Login)
+	h.sendInstallationConfirmation(ctx, state, installation, githubUser.Login)
 
-	return nil
+	return installation, githubUser.Login, nil
+}
+
+// sendInstallationConfirmation DMs the Slack user who completed a GitHub App installation a summary of what was
+// installed and linked, with next steps. Failures are logged, since the installation itself succeeded.
+func (h *OAuthHandler) sendInstallationConfirmation(
+	ctx context.Context, state *models.OAuthState, installation *models.GitHubInstallation, githubUsername string,
+) {
+	text := utils.FormatInstallationConfirmation(installation, githubUsername, h.slackAppHomeWebLink(state.SlackTeamID))
+	if _, err := h.slackService.PostMessage(ctx, state.SlackTeamID, state.SlackUserID, text); err != nil {
+		log.Warn(ctx, "Failed to send installation confirmation DM",
+			"error", err,
+			"user_id", state.SlackUserID)
+	}
 }
 
 // waitForInstallationInDatabase waits for GitHub installation to be created by webhook with exponential backoff.
@@ -478,9 +526,18 @@ func (h *OAuthHandler) waitForInstallationInDatabase(ctx context.Context, instal
 	return nil, fmt.Errorf("%w: installation %d not found after %d retries", ErrInstallationNotFoundAfterRetries, installationID, maxRetries)
 }
 
+// slackAppHomeWebLink returns a link that opens the app's App Home in a workspace through the browser,
+// for users without the Slack desktop app and for links in Slack messages.
+func (h *OAuthHandler) slackAppHomeWebLink(teamID string) string {
+	return fmt.Sprintf("https://slack.com/app_redirect?app=%s&team=%s", url.QueryEscape(h.config.SlackAppID), url.QueryEscape(teamID))
+}
+
 // redirectToInstallationSuccessPage creates and returns HTML success page for GitHub App installation flow.
-// Includes automatic redirect to Slack App Home after 2 seconds.
-func (h *OAuthHandler) redirectToInstallationSuccessPage(c *gin.Context, teamID, _ string) {
+// Summarises the installed account, its repositories and the linked GitHub user, lists next steps,
+// and includes automatic redirect to Slack App Home after 2 seconds.
+func (h *OAuthHandler) redirectToInstallationSuccessPage(
+	c *gin.Context, teamID string, installation *models.GitHubInstallation, githubUsername string,
+) {
 	slackDeepLink := fmt.Sprintf("slack://app?team=%s&id=%s&tab=home", teamID, h.config.SlackAppID)
 	successHTML := fmt.Sprintf(`
 <!DOCTYPE html>
@@ -500,6 +557,18 @@ func (h *OAuthHandler) redirectToInstallationSuccessPage(c *gin.Context, teamID,
         .success-icon { font-size: 48px; margin-bottom: 20px; }
         .success-message { color: #28a745; font-size: 20px; margin-bottom: 15px; }
         .details { color: #6c757d; margin-bottom: 30px; }
+        .summary, .next-steps {
+            text-align: left;
+            background-color: white;
+            border: 1px solid #dee2e6;
+            border-radius: 6px;
+            padding: 15px 20px;
+            margin-bottom: 20px;
+        }
+        .summary dt { font-weight: bold; }
+        .summary dd { margin: 0 0 10px 0; color: #6c757d; }
+        .next-steps h2 { font-size: 16px; margin-top: 0; }
+        .next-steps li { margin-bottom: 8px; color: #495057; }
         .btn {
             background-color: #611f69;
             color: white;
@@ -524,13 +593,39 @@ func (h *OAuthHandler) redirectToInstallationSuccessPage(c *gin.Context, teamID,
     <div class="success-icon">🎉</div>
     <div class="success-message">GitHub App Installed!</div>
     <div class="details">
-        Successfully installed PR Bot and linked your GitHub account.
-        Your Slack workspace can now receive GitHub PR notifications!
+        Your Slack workspace can now receive GitHub PR notifications. We've also sent you a DM in Slack with these details.
+    </div>
+    <dl class="summary">
+        <dt>Installed on</dt>
+        <dd>%s (%s)</dd>
+        <dt>Repositories</dt>
+        <dd>%s</dd>
+        <dt>GitHub account</dt>
+        <dd>@%s, linked to your Slack account</dd>
+    </dl>
+    <div class="next-steps">
+        <h2>Next steps</h2>
+        <ol>
+            <li>Choose the channel your PRs are posted to in the PR Bot App Home.</li>
+            <li>Invite teammates to link their GitHub accounts there too, so they're mentioned on reviews.</li>
+            <li>Open a PR in one of these repositories and it will be posted to your channel.</li>
+        </ol>
     </div>
     <a href="%s" class="btn">Return to Slack</a>
-    <div class="auto-redirect">Automatically redirecting to Slack in 2 seconds...</div>
+    <div class="auto-redirect">
+        Automatically redirecting to Slack in 2 seconds...
+        Slack not opening? <a href="%s">Open it in your browser</a>.
+    </div>
 </body>
-</html>`, slackDeepLink, slackDeepLink)
+</html>`,
+		slackDeepLink,
+		html.EscapeString(installation.AccountLogin),
+		html.EscapeString(installation.AccountType),
+		html.EscapeString(utils.DescribeInstallationRepositories(installation)),
+		html.EscapeString(githubUsername),
+		slackDeepLink,
+		html.EscapeString(h.slackAppHomeWebLink(teamID)),
+	)
 
 	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(successHTML))
 }
@@ -616,7 +711,7 @@ func (h *OAuthHandler) HandleSlackInstall(c *gin.Context) {
 	oauthURL := fmt.Sprintf(
 		"https://slack.com/oauth/v2/authorize?client_id=%s&scope=%s&redirect_uri=%s",
 		url.QueryEscape(h.config.SlackClientID),
-		url.QueryEscape("channels:read,chat:write,links:read,channels:history"),
+		url.QueryEscape(slackBotScopes),
 		url.QueryEscape(h.config.SlackRedirectURL()),
 	)
 
@@ -674,6 +769,12 @@ func (h *OAuthHandler) HandleSlackOAuthCallback(c *gin.Context) {
 		return
 	}
 
+	// An org-wide install in an Enterprise Grid organization has no team, only the enterprise
+	if token.Team.ID == "" && token.Enterprise.ID != "" {
+		h.completeEnterpriseInstall(ctx, c, token)
+		return
+	}
+
 	ctx = log.WithFields(ctx, log.LogFields{
 		"team_id":   token.Team.ID,
 		"team_name": token.Team.Name,
@@ -693,6 +794,22 @@ func (h *OAuthHandler) HandleSlackOAuthCallback(c *gin.Context) {
 		EnterpriseID: token.Enterprise.ID,
 	}
 
+	// Keep the timezone, locale, reaction emoji and message template an admin chose when the app is reinstalled.
+	// The new token replaces one Slack revoked, so posting to the workspace resumes.
+	if existing, err := h.slackWorkspaceService.GetWorkspace(ctx, workspace.ID); err == nil {
+		if existing.IsRevoked() {
+			log.Info(ctx, "Reinstall restores workspace with revoked Slack token",
+				"revoked_at", existing.RevokedAt,
+				"revoked_reason", existing.RevokedReason,
+			)
+		}
+		workspace.Timezone = existing.Timezone
+		workspace.Locale = existing.Locale
+		workspace.ReactionEmoji = existing.ReactionEmoji
+		workspace.MessageTemplate = existing.MessageTemplate
+		workspace.PRSizeConfig = existing.PRSizeConfig
+	}
+
 	if err := h.slackWorkspaceService.SaveWorkspace(ctx, workspace); err != nil {
 		log.Error(ctx, "Failed to save Slack workspace", "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -702,10 +819,64 @@ func (h *OAuthHandler) HandleSlackOAuthCallback(c *gin.Context) {
 		return
 	}
 
+	if workspace.Timezone == "" {
+		h.detectWorkspaceLocale(ctx, workspace)
+	}
+
 	log.Info(ctx, "Slack workspace installed successfully")
 
-	// Create success page with deep link to app home
 	slackDeepLink := fmt.Sprintf("slack://app?team=%s&id=%s&tab=home", token.Team.ID, h.config.SlackAppID)
+	renderSlackInstallSuccess(c, slackDeepLink, fmt.Sprintf("<strong>%s</strong> workspace", html.EscapeString(token.Team.Name)))
+}
+
+// completeEnterpriseInstall saves an org-wide install and the workspaces it covers, then shows the success page.
+func (h *OAuthHandler) completeEnterpriseInstall(ctx context.Context, c *gin.Context, token *slack.OAuthV2Response) {
+	ctx = log.WithFields(ctx, log.LogFields{
+		"enterprise_id":   token.Enterprise.ID,
+		"enterprise_name": token.Enterprise.Name,
+	})
+
+	installation := &models.SlackEnterpriseInstallation{
+		ID:             token.Enterprise.ID,
+		EnterpriseName: token.Enterprise.Name,
+		AccessToken:    token.AccessToken,
+		Scope:          token.Scope,
+		InstalledBy:    token.AuthedUser.ID,
+		InstalledAt:    time.Now(),
+		AppID:          token.AppID,
+		BotUserID:      token.BotUserID,
+	}
+
+	added, err := h.slackService.SaveEnterpriseInstallation(ctx, installation)
+	if err != nil {
+		// Workspaces that weren't added are added when their first Slack event arrives
+		log.Error(ctx, "Failed to save Slack enterprise installation", "error", err, "added_workspaces", len(added))
+		if len(added) == 0 {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Installation Failed",
+				"message": "Failed to save organization installation",
+			})
+			return
+		}
+	}
+
+	for _, workspace := range added {
+		h.detectWorkspaceLocale(ctx, workspace)
+	}
+
+	log.Info(ctx, "Slack organization installed successfully", "added_workspaces", len(added))
+
+	slackDeepLink := "slack://open"
+	if len(added) > 0 {
+		slackDeepLink = fmt.Sprintf("slack://app?team=%s&id=%s&tab=home", added[0].ID, h.config.SlackAppID)
+	}
+	renderSlackInstallSuccess(c, slackDeepLink,
+		fmt.Sprintf("the <strong>%s</strong> organization", html.EscapeString(token.Enterprise.Name)))
+}
+
+// renderSlackInstallSuccess shows the page Slack installs finish on, which opens the App Home via a deep link.
+// installedIn names where the app was installed, as HTML.
+func renderSlackInstallSuccess(c *gin.Context, slackDeepLink, installedIn string) {
 	successHTML := fmt.Sprintf(`
 <!DOCTYPE html>
 <html>
@@ -748,17 +919,171 @@ func (h *OAuthHandler) HandleSlackOAuthCallback(c *gin.Context) {
     <div class="success-icon">🎉</div>
     <div class="success-message">PR Bot Installed!</div>
     <div class="details">
-        Successfully installed PR Bot in <strong>%s</strong> workspace.<br>
+        Successfully installed PR Bot in %s.<br>
         You can now receive GitHub PR notifications in Slack!
     </div>
     <a href="%s" class="btn">Open Slack</a>
     <div class="auto-redirect">Automatically redirecting to Slack in 2 seconds...</div>
 </body>
-</html>`, slackDeepLink, token.Team.Name, slackDeepLink)
+</html>`, slackDeepLink, installedIn, slackDeepLink)
 
 	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(successHTML))
 }
 
+// HandleSlackUserTokenAuthorize redirects a user to Slack to grant a user token for posting PRs as them.
+// GET /auth/slack/user?state=<state_id>.
+func (h *OAuthHandler) HandleSlackUserTokenAuthorize(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceID := c.GetString("trace_id")
+
+	ctx = log.WithFields(ctx, log.LogFields{
+		"trace_id": traceID,
+		"handler":  "slack_user_token_authorize",
+	})
+
+	stateID := c.Query("state")
+	if stateID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid Request",
+			"message": "Missing required state parameter",
+		})
+		return
+	}
+
+	// Validate state exists and is not expired (consumed on callback)
+	state, err := h.firestoreService.GetOAuthState(ctx, stateID)
+	if err != nil || time.Now().After(state.ExpiresAt) {
+		log.Warn(ctx, "Invalid or expired OAuth state for Slack user token", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid Request",
+			"message": "Invalid or expired authorization request. Please try again from Slack",
+		})
+		return
+	}
+
+	params := url.Values{
+		"client_id":    {h.config.SlackClientID},
+		"user_scope":   {"chat:write"},
+		"redirect_uri": {h.config.SlackUserTokenRedirectURL()},
+		"state":        {stateID},
+		"team":         {state.SlackTeamID},
+	}
+
+	log.Info(ctx, "Redirecting to Slack user token authorization", "slack_user_id", state.SlackUserID)
+	c.Redirect(http.StatusFound, "https://slack.com/oauth/v2/authorize?"+params.Encode())
+}
+
+// HandleSlackUserTokenCallback stores the Slack user token granted for posting PRs as the user.
+// GET /auth/slack/user/callback?code=<code>&state=<state_id>.
+func (h *OAuthHandler) HandleSlackUserTokenCallback(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceID := c.GetString("trace_id")
+
+	ctx = log.WithFields(ctx, log.LogFields{
+		"trace_id": traceID,
+		"handler":  "slack_user_token_callback",
+	})
+
+	if errorParam := c.Query("error"); errorParam != "" {
+		log.Warn(ctx, "Slack user token authorization error", "error", errorParam)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Authorization Failed",
+			"message": fmt.Sprintf("Slack authorization failed: %s", errorParam),
+		})
+		return
+	}
+
+	code := c.Query("code")
+	state, err := h.githubAuthService.ValidateAndConsumeState(ctx, c.Query("state"))
+	if code == "" || err != nil {
+		log.Warn(ctx, "Invalid Slack user token callback", "error", err, "has_code", code != "")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid Request",
+			"message": "Invalid or expired authorization request",
+		})
+		return
+	}
+
+	ctx = log.WithFields(ctx, log.LogFields{
+		"slack_user_id": state.SlackUserID,
+		"slack_team_id": state.SlackTeamID,
+	})
+
+	resp, err := slack.GetOAuthV2ResponseContext(
+		ctx, h.httpClient, h.config.SlackClientID, h.config.SlackClientSecret, code, h.config.SlackUserTokenRedirectURL(),
+	)
+	if err != nil {
+		log.Error(ctx, "Failed to exchange Slack user token code", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Authorization Failed",
+			"message": "Failed to complete Slack authorization",
+		})
+		return
+	}
+
+	// The token must belong to the user who started the flow, in the same workspace
+	if resp.AuthedUser.ID != state.SlackUserID || resp.Team.ID != state.SlackTeamID || resp.AuthedUser.AccessToken == "" {
+		log.Warn(ctx, "Slack user token does not match the authorizing user",
+			"authed_user_id", resp.AuthedUser.ID,
+			"authed_team_id", resp.Team.ID,
+		)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Authorization Failed",
+			"message": "Please authorize with the Slack account you started from",
+		})
+		return
+	}
+
+	err = h.slackWorkspaceService.SaveUserToken(ctx, &models.SlackUserToken{
+		SlackUserID: state.SlackUserID,
+		SlackTeamID: state.SlackTeamID,
+		AccessToken: resp.AuthedUser.AccessToken,
+		Scope:       resp.AuthedUser.Scope,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Authorization Failed",
+			"message": "Failed to save Slack authorization",
+		})
+		return
+	}
+
+	user, err := h.firestoreService.GetUserBySlackID(ctx, state.SlackUserID)
+	if err != nil || user == nil {
+		log.Error(ctx, "Failed to get user after Slack user token authorization", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Authorization Failed",
+			"message": "Failed to update your settings",
+		})
+		return
+	}
+
+	user.UserTokenPosting = true
+	if err := h.firestoreService.CreateOrUpdateUser(ctx, user); err != nil {
+		log.Error(ctx, "Failed to enable user token posting", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Authorization Failed",
+			"message": "Failed to update your settings",
+		})
+		return
+	}
+
+	log.Info(ctx, "Slack user token posting enabled")
+
+	installations, err := h.firestoreService.GetGitHubInstallationsByWorkspace(ctx, state.SlackTeamID)
+	if err != nil {
+		log.Error(ctx, "Failed to get GitHub installations for App Home refresh", "error", err)
+		installations = nil
+	}
+	homeView := h.slackService.BuildHomeView(user, len(installations) > 0, installations)
+	if err := h.slackService.PublishHomeViewAndCloseModals(ctx, state.SlackTeamID, state.SlackUserID, homeView); err != nil {
+		log.Warn(ctx, "Failed to refresh App Home after Slack user token authorization", "error", err)
+	}
+
+	slackDeepLink := fmt.Sprintf("slack://app?team=%s&id=%s&tab=home", state.SlackTeamID, h.config.SlackAppID)
+	c.Redirect(http.StatusFound, slackDeepLink)
+}
+
 // exchangeSlackOAuthCode exchanges Slack OAuth authorization code for workspace access token.
 // Uses slack-go library to perform the token exchange with Slack's OAuth v2 endpoint.
 func (h *OAuthHandler) exchangeSlackOAuthCode(ctx context.Context, code string) (*slack.OAuthV2Response, error) {
@@ -777,3 +1102,23 @@ func (h *OAuthHandler) exchangeSlackOAuthCode(ctx context.Context, code string)
 
 	return resp, nil
 }
+
+// detectWorkspaceLocale defaults a new workspace's timezone and locale from the installing user's Slack profile,
+// since Slack's team info doesn't include them. Failures are logged, leaving the UTC and en-US defaults.
+func (h *OAuthHandler) detectWorkspaceLocale(ctx context.Context, workspace *models.SlackWorkspace) {
+	installer, err := h.slackService.GetUserInfo(ctx, workspace.ID, workspace.InstalledBy)
+	if err != nil {
+		log.Warn(ctx, "Failed to detect workspace timezone from installing user", "error", err)
+		return
+	}
+	if installer.TZ == "" && installer.Locale == "" {
+		return
+	}
+
+	if err := h.slackWorkspaceService.UpdateWorkspaceLocale(ctx, workspace.ID, installer.TZ, installer.Locale); err != nil {
+		log.Warn(ctx, "Failed to save detected workspace timezone", "error", err)
+		return
+	}
+	workspace.Timezone = installer.TZ
+	workspace.Locale = installer.Locale
+}
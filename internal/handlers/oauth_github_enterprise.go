@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+)
+
+// HandleGitHubInstall starts a GitHub App installation once the user has chosen between github.com and the
+// configured GitHub Enterprise Server instances. Only registered when instances are configured; otherwise
+// Slack links straight to the github.com installation page.
+// GET /auth/github/install?state=<state_id>.
+func (h *OAuthHandler) HandleGitHubInstall(c *gin.Context) {
+	ctx := log.WithFields(c.Request.Context(), log.LogFields{
+		"trace_id": c.GetString("trace_id"),
+		"handler":  "oauth_github_install",
+	})
+
+	state, ok := h.loadPendingOAuthState(ctx, c)
+	if !ok {
+		return
+	}
+	ctx = log.WithFields(ctx, log.LogFields{"state_id": state.ID})
+
+	if !h.chooseGitHubHost(ctx, c, state) {
+		return
+	}
+
+	installURL, err := h.githubAuthService.GetAppInstallationURL(state)
+	if err != nil {
+		log.Error(ctx, "Failed to build GitHub App installation URL", "error", err, "github_host", state.GitHubHost)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid Request",
+			"message": "Unknown GitHub host",
+		})
+		return
+	}
+
+	log.Info(ctx, "Redirecting to GitHub App installation", "slack_user_id", state.SlackUserID, "github_host", state.GitHubHost)
+	c.Redirect(http.StatusFound, installURL)
+}
+
+// chooseGitHubHost records the GitHub host chosen in the host query parameter on a pending OAuth state, empty
+// meaning github.com. When GitHub Enterprise Server instances are configured and no host has been chosen, it shows
+// a page to choose one instead. Returns whether the flow can continue to GitHub.
+func (h *OAuthHandler) chooseGitHubHost(ctx context.Context, c *gin.Context, state *models.OAuthState) bool {
+	hosts := h.githubAuthService.GitHubEnterpriseHosts()
+	if len(hosts) == 0 {
+		return true
+	}
+
+	host, chosen := c.GetQuery("host")
+	if !chosen {
+		renderGitHubHostChooser(c, state.ID, hosts)
+		return false
+	}
+
+	host = strings.ToLower(host)
+	if host != "" && !slices.Contains(hosts, host) {
+		log.Warn(ctx, "Unknown GitHub host chosen", "github_host", host)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid Request",
+			"message": "Unknown GitHub host",
+		})
+		return false
+	}
+
+	if host != state.GitHubHost {
+		state.GitHubHost = host
+		if err := h.githubAuthService.SaveOAuthState(ctx, state); err != nil {
+			log.Error(ctx, "Failed to save chosen GitHub host on OAuth state", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Internal Error",
+				"message": "Failed to continue authorization. Please try again from Slack",
+			})
+			return false
+		}
+	}
+	return true
+}
+
+// renderGitHubHostChooser shows a page linking back to the current flow once for github.com and once for each
+// GitHub Enterprise Server instance.
+func renderGitHubHostChooser(c *gin.Context, stateID string, hosts []string) {
+	link := func(host string) string {
+		params := url.Values{"state": {stateID}, "host": {host}}
+		return html.EscapeString(c.Request.URL.Path + "?" + params.Encode())
+	}
+
+	var buttons strings.Builder
+	fmt.Fprintf(&buttons, `    <a href="%s" class="btn">GitHub.com</a>`+"\n", link(""))
+	for _, host := range hosts {
+		fmt.Fprintf(&buttons, `    <a href="%s" class="btn">%s</a>`+"\n", link(host), html.EscapeString(host))
+	}
+
+	chooserHTML := fmt.Sprintf(`
+<!DOCTYPE html>
+<html>
+<head>
+    <title>Choose GitHub</title>
+    <meta name="viewport" content="width=device-width, initial-scale=1">
+    <style>
+        body {
+            font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif;
+            max-width: 500px;
+            margin: 50px auto;
+            padding: 20px;
+            text-align: center;
+            background-color: #f8f9fa;
+        }
+        .message { font-size: 20px; margin-bottom: 15px; }
+        .details { color: #6c757d; margin-bottom: 30px; }
+        .btn {
+            background-color: #611f69;
+            color: white;
+            padding: 12px 24px;
+            text-decoration: none;
+            border-radius: 6px;
+            font-weight: bold;
+            display: block;
+            margin: 10px 0;
+        }
+        .btn:hover { background-color: #4a154b; }
+    </style>
+</head>
+<body>
+    <div class="message">Where is your code hosted?</div>
+    <div class="details">Choose GitHub.com or your GitHub Enterprise Server instance.</div>
+%s</body>
+</html>`, buttons.String())
+
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(chooserHTML))
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+	"github-slack-notifier/internal/services"
+	"github-slack-notifier/internal/utils"
+)
+
+const (
+	// releaseCountdownGracePeriod keeps refreshing channels shortly after the cut so lines show it was missed.
+	releaseCountdownGracePeriod = 2 * time.Hour
+	// releaseCountdownMessageWindow limits which tracked messages are considered for countdown lines.
+	releaseCountdownMessageWindow = 14 * 24 * time.Hour
+)
+
+// ProcessReleaseCountdownJob refreshes the release cut countdown line on open PR messages.
+// Triggered on a schedule; only channels with a release cut deadline are considered.
+func (h *GitHubHandler) ProcessReleaseCountdownJob(ctx context.Context, job *models.Job) error {
+	var countdownJob models.ReleaseCountdownJob
+	if len(job.Payload) > 0 {
+		if err := json.Unmarshal(job.Payload, &countdownJob); err != nil {
+			return fmt.Errorf("failed to unmarshal release countdown job: %w", models.Permanent(err))
+		}
+	}
+
+	now := time.Now()
+	configs, err := h.firestoreService.ListReleaseCutChannelConfigs(ctx, now.Add(-releaseCountdownGracePeriod))
+	if err != nil {
+		log.Error(ctx, "Failed to list release cut channel configs", "error", err)
+		return err
+	}
+
+	refreshed := 0
+	for _, channelConfig := range configs {
+		if countdownJob.SlackTeamID != "" && channelConfig.SlackTeamID != countdownJob.SlackTeamID {
+			continue
+		}
+
+		channelCtx := log.WithFields(ctx, log.LogFields{
+			"team_id":    channelConfig.SlackTeamID,
+			"channel_id": channelConfig.SlackChannelID,
+		})
+		if err := h.refreshChannelCountdowns(channelCtx, channelConfig, now); err != nil {
+			// Continue with other channels even if one fails
+			log.Error(channelCtx, "Failed to refresh release countdowns for channel", "error", err)
+			continue
+		}
+		refreshed++
+	}
+
+	log.Info(ctx, "Release countdown job completed",
+		"channel_count", len(configs),
+		"refreshed_count", refreshed,
+	)
+	return nil
+}
+
+// refreshChannelCountdowns updates the countdown line on recent PR messages in a release cut channel.
+// Open PRs get the current countdown; closed PRs have the countdown removed.
+func (h *GitHubHandler) refreshChannelCountdowns(ctx context.Context, channelConfig *models.ChannelConfig, now time.Time) error {
+	messages, err := h.firestoreService.GetRecentTrackedMessagesForChannel(
+		ctx, channelConfig.SlackTeamID, channelConfig.SlackChannelID, now.Add(-releaseCountdownMessageWindow),
+	)
+	if err != nil {
+		return err
+	}
+
+	// Show the cut time in the workspace's timezone
+	timeFormat := h.slackService.GetWorkspaceTimeFormat(ctx, channelConfig.SlackTeamID)
+
+	// Cache countdown lines per PR, as a PR may be tracked by several messages in the channel
+	linesByPR := make(map[string]string)
+	for _, msg := range messages {
+		if msg.DeletedByUser || msg.Compact {
+			continue
+		}
+
+		prKey := msg.RepoFullName + "#" + strconv.Itoa(msg.PRNumber)
+		line, cached := linesByPR[prKey]
+		if !cached {
+			pr, reviewState, err := h.githubService.GetPullRequestWithReviews(ctx, msg.RepoFullName, msg.PRNumber)
+			if err != nil {
+				log.Warn(ctx, "Failed to fetch PR for release countdown",
+					"error", err,
+					"repo", msg.RepoFullName,
+					"pr_number", msg.PRNumber,
+				)
+				continue
+			}
+
+			if pr.GetState() != "closed" {
+				line = utils.FormatReleaseCountdown(*channelConfig.ReleaseCutDeadline, now, reviewState, timeFormat)
+			}
+			linesByPR[prKey] = line
+		}
+
+		err := h.slackService.SetCountdownText(ctx, msg.SlackTeamID, []services.MessageRef{trackedMessageRef(msg)}, line)
+		if err != nil {
+			log.Warn(ctx, "Failed to update release countdown line",
+				"error", err,
+				"repo", msg.RepoFullName,
+				"pr_number", msg.PRNumber,
+			)
+		}
+	}
+
+	return nil
+}
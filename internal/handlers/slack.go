@@ -8,13 +8,14 @@ import (
 	"io"
 	"net/http"
 	"net/url"
-	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github-slack-notifier/internal/config"
 	"github-slack-notifier/internal/log"
 	"github-slack-notifier/internal/models"
+	"github-slack-notifier/internal/prsize"
 	"github-slack-notifier/internal/services"
 	"github-slack-notifier/internal/utils"
 	"github.com/gin-gonic/gin"
@@ -36,6 +37,7 @@ type SlackHandler struct {
 	slackService      *services.SlackService
 	cloudTasksService CloudTasksServiceInterface
 	githubAuthService *services.GitHubAuthService
+	workspaceStats    *services.WorkspaceStatsService
 	signingSecret     string
 	config            *config.Config
 }
@@ -53,6 +55,7 @@ func NewSlackHandler(
 		slackService:      slack,
 		cloudTasksService: cloudTasks,
 		githubAuthService: githubAuth,
+		workspaceStats:    services.NewWorkspaceStatsService(fs),
 		signingSecret:     cfg.SlackSigningSecret,
 		config:            cfg,
 	}
@@ -116,27 +119,58 @@ func (sh *SlackHandler) HandleEvent(c *gin.Context) {
 			"event_type", innerEvent.Type,
 			"team_id", eventsAPIEvent.TeamID)
 
+		// Act as the installing workspace, which differs from the event's team in Slack Connect channels
+		teamID, sharedChannel := installationTeamID(body)
+		if teamID == "" {
+			teamID = eventsAPIEvent.TeamID
+		}
+		sh.ensureEnterpriseWorkspace(ctx, teamID, eventsAPIEvent.EnterpriseID)
+		if sharedChannel {
+			ctx = log.WithFields(ctx, log.LogFields{
+				"ext_shared_channel": true,
+				"event_team_id":      eventsAPIEvent.TeamID,
+			})
+			if !sh.acceptSharedChannelEvent(ctx, eventsAPIEvent.TeamID, teamID) {
+				c.JSON(http.StatusOK, gin.H{"ok": true})
+				return
+			}
+		}
+
 		switch ev := innerEvent.Data.(type) {
 		case *slackevents.MessageEvent:
-			sh.handleMessageEvent(ctx, ev, eventsAPIEvent.TeamID)
+			sh.handleMessageEvent(ctx, ev, teamID)
 		case *slackevents.AppHomeOpenedEvent:
-			sh.handleAppHomeOpened(ctx, ev, eventsAPIEvent.TeamID)
+			sh.handleAppHomeOpened(ctx, ev, teamID)
 		case *slackevents.ReactionAddedEvent:
-			sh.handleReactionAddedEvent(ctx, ev, eventsAPIEvent.TeamID)
+			sh.handleReactionAddedEvent(ctx, ev, teamID)
+		case *slackevents.MemberJoinedChannelEvent:
+			sh.handleMemberJoinedChannelEvent(ctx, ev, teamID)
 		}
 	}
 
 	c.JSON(http.StatusOK, gin.H{"ok": true})
 }
 
-// handleMessageEvent processes Slack message events to detect and track GitHub PR links.
+// handleMessageEvent processes Slack message events to detect and track GitHub PR and issue links.
 // Skips bot messages, edited messages, and channels with disabled tracking. Queues manual PR link jobs for processing.
 func (sh *SlackHandler) handleMessageEvent(ctx context.Context, event *slackevents.MessageEvent, teamID string) {
+	if event.SubType == slack.MsgSubTypeMessageDeleted {
+		sh.handleMessageDeleted(ctx, event, teamID)
+		return
+	}
+
 	// Skip bot messages, edited messages, and messages without text
 	if event.BotID != "" || event.SubType == "message_changed" || event.Text == "" {
 		return
 	}
 
+	if event.ThreadTimeStamp != "" && event.ThreadTimeStamp != event.TimeStamp {
+		if text, ok := utils.ParseSlackComment(event.Text); ok {
+			sh.handleSlackComment(ctx, event, teamID, text)
+			return
+		}
+	}
+
 	// Check if manual tracking is enabled for this channel
 	channelConfig, err := sh.firestoreService.GetChannelConfig(ctx, teamID, event.Channel)
 	if err != nil {
@@ -157,7 +191,7 @@ func (sh *SlackHandler) handleMessageEvent(ctx context.Context, event *slackeven
 		return
 	}
 
-	// Extract PR links from message text
+	// Extract PR and issue links from message text
 	prLinks := utils.ExtractPRLinks(event.Text)
 	if len(prLinks) == 0 {
 		return
@@ -166,12 +200,13 @@ func (sh *SlackHandler) handleMessageEvent(ctx context.Context, event *slackeven
 	// Process each PR link found (though we expect only one based on our utility logic)
 	for _, prLink := range prLinks {
 		jobID := uuid.New().String()
-		traceID := uuid.New().String()
+		traceID := traceIDForNewJob(ctx)
 
 		// Add PR and Slack context for this iteration
 		linkCtx := log.WithFields(ctx, log.LogFields{
 			"repo":             prLink.FullRepoName,
 			"pr_number":        prLink.PRNumber,
+			"item_type":        prLink.ItemType,
 			"slack_channel":    event.Channel,
 			"slack_message_ts": event.TimeStamp,
 			"job_id":           jobID,
@@ -181,6 +216,7 @@ func (sh *SlackHandler) handleMessageEvent(ctx context.Context, event *slackeven
 			ID:             jobID,
 			PRNumber:       prLink.PRNumber,
 			RepoFullName:   prLink.FullRepoName,
+			ItemType:       prLink.ItemType,
 			SlackChannel:   event.Channel,
 			SlackMessageTS: event.TimeStamp,
 			SlackTeamID:    teamID,
@@ -212,9 +248,42 @@ func (sh *SlackHandler) handleMessageEvent(ctx context.Context, event *slackeven
 	}
 }
 
+// handleMessageDeleted marks a tracked message deleted when someone deletes it in Slack.
+// Slack doesn't send these events for messages removed by a retention policy, which is how the
+// retention sweep tells the two apart.
+func (sh *SlackHandler) handleMessageDeleted(ctx context.Context, event *slackevents.MessageEvent, teamID string) {
+	if event.PreviousMessage == nil {
+		return
+	}
+
+	trackedMessage, err := sh.firestoreService.GetTrackedMessageBySlackMessage(ctx, teamID, event.Channel, event.PreviousMessage.TimeStamp)
+	if err != nil {
+		log.Error(ctx, "Failed to look up deleted message", "error", err)
+		return
+	}
+	if trackedMessage == nil || trackedMessage.DeletedByUser {
+		return
+	}
+
+	if err := sh.firestoreService.MarkTrackedMessageDeleted(ctx, trackedMessage.ID); err != nil {
+		return // Logged by MarkTrackedMessageDeleted
+	}
+	log.Info(ctx, "Tracked message deleted in Slack",
+		"tracked_message_id", trackedMessage.ID,
+		"channel", event.Channel,
+		"message_ts", event.PreviousMessage.TimeStamp,
+	)
+}
+
 // handleReactionAddedEvent processes reaction_added events to detect wastebasket emoji for message deletion.
-// Only processes wastebasket reactions on bot messages from tracked PR notifications.
+// Only processes wastebasket reactions on bot messages from tracked PR notifications. Reactions mapped in
+// REACTION_ACTIONS queue their GitHub action instead.
 func (sh *SlackHandler) handleReactionAddedEvent(ctx context.Context, event *slackevents.ReactionAddedEvent, teamID string) {
+	if action, ok := sh.config.ReactionActions[baseReactionName(event.Reaction)]; ok {
+		sh.enqueueReactionAction(ctx, event, teamID, action)
+		return
+	}
+
 	// Only handle wastebasket emoji reactions
 	if event.Reaction != "wastebasket" {
 		return
@@ -307,7 +376,7 @@ func (sh *SlackHandler) handleReactionAddedEvent(ctx context.Context, event *sla
 
 	// Queue deletion job
 	jobID := uuid.New().String()
-	traceID := uuid.New().String()
+	traceID := traceIDForNewJob(ctx)
 
 	deleteJob := &models.DeleteTrackedMessageJob{
 		ID:               jobID,
@@ -315,6 +384,7 @@ func (sh *SlackHandler) handleReactionAddedEvent(ctx context.Context, event *sla
 		SlackChannel:     event.Item.Channel,
 		SlackMessageTS:   event.Item.Timestamp,
 		SlackTeamID:      teamID,
+		PostedAsUserID:   trackedMessage.PostedAsUserID,
 		TraceID:          traceID,
 	}
 
@@ -392,19 +462,23 @@ func (sh *SlackHandler) HandleInteraction(c *gin.Context) {
 		"user_id", interaction.User.ID,
 	)
 
+	sh.ensureEnterpriseWorkspace(ctx, interaction.Team.ID, interaction.Enterprise.ID)
+
 	switch interaction.Type {
 	case slack.InteractionTypeBlockActions:
 		sh.handleBlockAction(ctx, &interaction, c)
 	case slack.InteractionTypeViewSubmission:
 		sh.handleViewSubmission(ctx, &interaction, c)
+	case slack.InteractionTypeMessageAction:
+		sh.handleMessageAction(ctx, &interaction, c)
+	case slack.InteractionTypeShortcut:
+		sh.handleShortcut(ctx, &interaction, c)
 	case slack.InteractionTypeDialogCancellation,
 		slack.InteractionTypeDialogSubmission,
 		slack.InteractionTypeDialogSuggestion,
 		slack.InteractionTypeInteractionMessage,
-		slack.InteractionTypeMessageAction,
 		slack.InteractionTypeBlockSuggestion,
 		slack.InteractionTypeViewClosed,
-		slack.InteractionTypeShortcut,
 		slack.InteractionTypeWorkflowStepEdit:
 		// Not handled for App Home implementation
 		c.JSON(http.StatusOK, gin.H{})
@@ -430,12 +504,18 @@ func (sh *SlackHandler) handleBlockAction(ctx context.Context, interaction *slac
 		sh.handleConnectGitHubAction(ctx, userID, teamID, interaction.TriggerID, c)
 	case "disconnect_github":
 		sh.handleDisconnectGitHubAction(ctx, userID, c)
+	case "export_my_data":
+		sh.handleUserDataAction(ctx, models.JobTypeUserDataExport, userID, teamID, c)
+	case "delete_my_data":
+		sh.handleUserDataAction(ctx, models.JobTypeUserDataDeletion, userID, teamID, c)
 	case "install_github_app":
 		sh.handleInstallGitHubAppFromHomeAction(ctx, userID, teamID, interaction.TriggerID, c)
 	case "select_channel":
 		sh.handleSelectChannelAction(ctx, userID, teamID, interaction.TriggerID, c)
 	case "refresh_view":
 		sh.handleRefreshViewAction(ctx, userID, c)
+	case "active_prs_previous", "active_prs_next":
+		sh.handleActivePRsPageAction(ctx, userID, teamID, action.Value, c)
 	case "manage_channel_tracking":
 		sh.handleManageChannelTrackingAction(ctx, userID, teamID, interaction.TriggerID, c)
 	case "toggle_notifications":
@@ -444,12 +524,78 @@ func (sh *SlackHandler) handleBlockAction(ctx context.Context, interaction *slac
 		sh.handleToggleUserTaggingAction(ctx, userID, c)
 	case "toggle_impersonation":
 		sh.handleToggleImpersonationAction(ctx, userID, c)
+	case "connect_slack_user_token":
+		sh.handleConnectSlackUserTokenAction(ctx, userID, teamID, interaction.TriggerID, c)
+	case "disconnect_slack_user_token":
+		sh.handleDisconnectSlackUserTokenAction(ctx, userID, teamID, c)
+	case "author_dm_preferences":
+		sh.handleAuthorDMPreferencesAction(ctx, userID, action.SelectedOptions, c)
+	case "review_request_preferences":
+		sh.handleReviewRequestPreferencesAction(ctx, userID, action.SelectedOptions, c)
+	case "toggle_assignment_dms":
+		sh.handleToggleAssignmentDMsAction(ctx, userID, c)
+	case "toggle_review_comment_threads":
+		sh.handleToggleReviewCommentThreadsAction(ctx, userID, c)
+	case "toggle_digest_mode":
+		sh.handleToggleDigestModeAction(ctx, userID, c)
+	case "toggle_daily_digest":
+		sh.handleToggleDailyDigestAction(ctx, userID, teamID, c)
+	case "daily_digest_hour", "daily_digest_timezone":
+		sh.handleDailyDigestScheduleAction(ctx, userID, action.ActionID, action.SelectedOption.Value, c)
+	case "toggle_out_of_office":
+		sh.handleToggleOutOfOfficeAction(ctx, userID, c)
+	case "ooo_delegate":
+		sh.handleOutOfOfficeDelegateAction(ctx, userID, action.SelectedUser, c)
+	case "toggle_quiet_hours":
+		sh.handleToggleQuietHoursAction(ctx, userID, teamID, c)
+	case "open_mute_repo":
+		sh.handleOpenMuteRepoAction(ctx, teamID, interaction.TriggerID, c)
+	case "unmute_repo":
+		sh.handleUnmuteRepoAction(ctx, userID, action.SelectedOption.Value, c)
+	case "quiet_hours_start", "quiet_hours_end", "quiet_hours_timezone":
+		sh.handleQuietHoursScheduleAction(ctx, userID, action.ActionID, action.SelectedOption.Value, c)
+	case "workspace_timezone", "workspace_locale":
+		sh.handleWorkspaceLocaleAction(ctx, userID, teamID, action.ActionID, action.SelectedOption.Value, c)
+	case "workspace_link_invites":
+		sh.handleWorkspaceLinkInvitesAction(ctx, userID, teamID, action.SelectedOption.Value, c)
+	case "toggle_workspace_slack_comments":
+		sh.handleToggleWorkspaceSlackCommentsAction(ctx, userID, teamID, c)
+	case services.LinkInviteOptOutActionID:
+		sh.handleLinkInviteOptOutAction(ctx, interaction, action.Value, c)
+	case services.ChannelIntroConfigureActionID:
+		sh.handleChannelIntroConfigureAction(ctx, teamID, action.Value, interaction.TriggerID, c)
+	case "manage_routing_rules":
+		sh.handleManageRoutingRulesAction(ctx, userID, teamID, interaction.TriggerID, c)
+	case "manage_reviewer_rotation":
+		sh.handleManageReviewerRotationAction(ctx, userID, teamID, interaction.TriggerID, c)
+	case "manage_team_user_groups":
+		sh.handleManageTeamUserGroupsAction(ctx, userID, teamID, interaction.TriggerID, c)
+	case "manage_reaction_emoji":
+		sh.handleManageReactionEmojiAction(ctx, userID, teamID, interaction.TriggerID, c)
+	case "manage_message_template":
+		sh.handleManageMessageTemplateAction(ctx, userID, teamID, interaction.TriggerID, c)
+	case "manage_workspace_pr_size":
+		sh.handleManageWorkspacePRSizeAction(ctx, userID, teamID, interaction.TriggerID, c)
+	case "edit_repo_settings":
+		sh.handleEditRepoSettingsAction(ctx, userID, teamID, interaction.TriggerID, action.SelectedOption.Value, c)
+	case "delete_repo":
+		sh.handleDeleteRepoAction(ctx, userID, teamID, interaction.TriggerID, action.SelectedOption.Value, c)
+	case "edit_channel_filters":
+		sh.handleEditChannelFiltersAction(ctx, teamID, interaction.TriggerID, action.Value, c)
+	case "edit_channel_pr_size":
+		sh.handleEditChannelPRSizeAction(ctx, teamID, interaction.TriggerID, action.Value, c)
+	case "edit_installation_defaults":
+		sh.handleEditInstallationDefaultsAction(ctx, userID, teamID, interaction.TriggerID, action.Value, c)
 	case "manage_github_installations":
 		sh.handleManageGitHubInstallationsAction(ctx, userID, teamID, interaction.TriggerID, c)
 	case "add_github_installation":
 		sh.handleAddGitHubInstallationFromModalAction(ctx, userID, teamID, interaction.TriggerID, c)
 	case "configure_pr_size_emojis":
 		sh.handleConfigurePRSizeEmojisAction(ctx, userID, teamID, interaction.TriggerID, c)
+	case services.ShowPRDetailsActionID:
+		sh.handleShowPRDetailsAction(ctx, interaction, action.Value, c)
+	case services.OpenPRActionID:
+		sh.handleOpenPRAction(ctx, interaction, c)
 	default:
 		c.JSON(http.StatusOK, gin.H{})
 	}
@@ -465,8 +611,40 @@ func (sh *SlackHandler) handleViewSubmission(ctx context.Context, interaction *s
 		sh.handleChannelTrackingSelection(ctx, interaction, c)
 	case "save_channel_tracking":
 		sh.handleSaveChannelTracking(ctx, interaction, c)
+	case "save_channel_filters":
+		sh.handleSaveChannelFilters(ctx, interaction, c)
+	case "save_channel_pr_size":
+		sh.handleSaveChannelPRSize(ctx, interaction, c)
+	case "save_workspace_pr_size":
+		sh.handleSaveWorkspacePRSize(ctx, interaction, c)
 	case "pr_size_config":
 		sh.handlePRSizeConfigSubmission(ctx, interaction, c)
+	case "routing_rules_repo_selector":
+		sh.handleRoutingRulesRepoSelection(ctx, interaction, c)
+	case "save_routing_rules":
+		sh.handleSaveRoutingRules(ctx, interaction, c)
+	case "save_team_user_groups":
+		sh.handleSaveTeamUserGroups(ctx, interaction, c)
+	case "reviewer_rotation_channel_selector":
+		sh.handleReviewerRotationChannelSelection(ctx, interaction, c)
+	case "save_reviewer_rotation":
+		sh.handleSaveReviewerRotation(ctx, interaction, c)
+	case "save_reaction_emoji":
+		sh.handleSaveReactionEmoji(ctx, interaction, c)
+	case "save_message_template":
+		sh.handleSaveMessageTemplate(ctx, interaction, c)
+	case postPRCallbackID:
+		sh.handlePostPRSubmission(ctx, interaction, c)
+	case movePRNotificationCallbackID:
+		sh.handleMovePRNotificationSubmission(ctx, interaction, c)
+	case muteRepoCallbackID:
+		sh.handleMuteRepoSubmission(ctx, interaction, c)
+	case "save_repo_settings":
+		sh.handleSaveRepoSettings(ctx, interaction, c)
+	case "confirm_delete_repo":
+		sh.handleConfirmDeleteRepo(ctx, interaction, c)
+	case "save_installation_defaults":
+		sh.handleSaveInstallationDefaults(ctx, interaction, c)
 	default:
 		log.Warn(ctx, "Unknown view submission callback ID",
 			"callback_id", interaction.View.CallbackID)
@@ -487,7 +665,16 @@ func (sh *SlackHandler) handleAppHomeOpened(ctx context.Context, event *slackeve
 	})
 
 	log.Info(ctx, "App Home opened")
+	sh.publishHomeView(ctx, teamID, userID)
+}
+
+// publishHomeView builds and publishes a user's App Home view, including users PR Bot has no data about yet.
+func (sh *SlackHandler) publishHomeView(ctx context.Context, teamID, userID string) {
+	sh.publishHomeViewPage(ctx, teamID, userID, 0)
+}
 
+// publishHomeViewPage builds and publishes a user's App Home view showing the given page of their Active PRs.
+func (sh *SlackHandler) publishHomeViewPage(ctx context.Context, teamID, userID string, activePRsPage int) {
 	// Get user data
 	user, err := sh.firestoreService.GetUserBySlackID(ctx, userID)
 	if err != nil {
@@ -505,12 +692,196 @@ func (sh *SlackHandler) handleAppHomeOpened(ctx context.Context, event *slackeve
 
 	// Build and publish home view
 	view := sh.slackService.BuildHomeView(user, hasInstallations, installations)
+	sh.addWorkspaceAdminSections(ctx, teamID, userID, &view)
+	sh.addActivePRsSection(ctx, user, activePRsPage, &view)
 	err = sh.slackService.PublishHomeView(ctx, teamID, userID, view)
 	if err != nil {
 		log.Error(ctx, "Failed to publish App Home view", "error", err)
 	}
 }
 
+// addWorkspaceAdminSections appends the workspace activity and settings sections to the App Home view for workspace admins.
+// Failures are logged and leave the sections out, so they never block the App Home from rendering.
+func (sh *SlackHandler) addWorkspaceAdminSections(ctx context.Context, teamID, userID string, view *slack.HomeTabViewRequest) {
+	isAdmin, err := sh.slackService.IsWorkspaceAdmin(ctx, teamID, userID)
+	if err != nil {
+		log.Warn(ctx, "Failed to check workspace admin status for App Home", "error", err)
+		return
+	}
+	if !isAdmin {
+		return
+	}
+
+	workspace, err := sh.slackService.GetWorkspace(ctx, teamID)
+	if err != nil {
+		log.Warn(ctx, "Failed to get workspace settings for App Home", "error", err)
+		workspace = nil
+	}
+
+	stats, err := sh.workspaceStats.GetWorkspaceStats(ctx, teamID)
+	if err != nil {
+		log.Warn(ctx, "Failed to get workspace stats for App Home", "error", err)
+	} else {
+		view.Blocks.BlockSet = append(view.Blocks.BlockSet,
+			sh.slackService.BuildWorkspaceStatsSection(stats, utils.WorkspaceTimeFormat(workspace))...)
+	}
+
+	if workspace != nil {
+		view.Blocks.BlockSet = append(view.Blocks.BlockSet, sh.slackService.BuildWorkspaceSettingsSection(workspace)...)
+	}
+
+	repos, err := sh.firestoreService.ListReposForWorkspace(ctx, teamID)
+	if err != nil {
+		log.Warn(ctx, "Failed to list repositories for App Home", "error", err)
+	} else {
+		view.Blocks.BlockSet = append(view.Blocks.BlockSet, sh.slackService.BuildRepositoriesSection(repos)...)
+		sh.addRevokedWorkspacesSection(ctx, teamID, repos, view)
+	}
+}
+
+// addRevokedWorkspacesSection appends a warning about other workspaces sharing the workspace's repositories
+// whose Slack token was revoked, so the admin can let them know to reinstall the app.
+func (sh *SlackHandler) addRevokedWorkspacesSection(
+	ctx context.Context, teamID string, repos []*models.Repo, view *slack.HomeTabViewRequest,
+) {
+	if len(repos) == 0 {
+		return
+	}
+	revoked, err := sh.slackService.ListRevokedWorkspaces(ctx)
+	if err != nil {
+		log.Warn(ctx, "Failed to list revoked workspaces for App Home", "error", err)
+		return
+	}
+
+	ownRepos := make(map[string]bool, len(repos))
+	for _, repo := range repos {
+		ownRepos[repo.RepoFullName] = true
+	}
+
+	var sharing []*models.SlackWorkspace
+	sharedRepos := make(map[string][]string)
+	for _, workspace := range revoked {
+		if workspace.ID == teamID {
+			continue
+		}
+		theirRepos, err := sh.firestoreService.ListReposForWorkspace(ctx, workspace.ID)
+		if err != nil {
+			log.Warn(ctx, "Failed to list repositories of revoked workspace for App Home", "error", err, "revoked_team_id", workspace.ID)
+			continue
+		}
+		for _, repo := range theirRepos {
+			if ownRepos[repo.RepoFullName] {
+				sharedRepos[workspace.ID] = append(sharedRepos[workspace.ID], repo.RepoFullName)
+			}
+		}
+		if len(sharedRepos[workspace.ID]) > 0 {
+			sharing = append(sharing, workspace)
+		}
+	}
+
+	reinstallURL := ""
+	if sh.config.IsSlackOAuthEnabled() {
+		reinstallURL = sh.config.BaseURL + "/auth/slack/install"
+	}
+	view.Blocks.BlockSet = append(view.Blocks.BlockSet, sh.slackService.BuildRevokedWorkspacesSection(sharing, sharedRepos, reinstallURL)...)
+}
+
+// handleWorkspaceLocaleAction handles changes to the workspace timezone and date format selects.
+// Only workspace admins can change them, and the App Home is refreshed to show the new format.
+func (sh *SlackHandler) handleWorkspaceLocaleAction(ctx context.Context, userID, teamID, actionID, value string, c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{})
+
+	isAdmin, err := sh.slackService.IsWorkspaceAdmin(ctx, teamID, userID)
+	if err != nil || !isAdmin {
+		log.Warn(ctx, "Ignoring workspace locale change from non-admin", "error", err, "user_id", userID)
+		return
+	}
+
+	workspace, err := sh.slackService.GetWorkspace(ctx, teamID)
+	if err != nil {
+		log.Error(ctx, "Failed to get workspace for locale change", "error", err)
+		return
+	}
+
+	timezone, locale := workspace.Timezone, workspace.Locale
+	switch actionID {
+	case "workspace_timezone":
+		if _, err := time.LoadLocation(value); err != nil {
+			log.Warn(ctx, "Ignoring unknown workspace timezone", "timezone", value)
+			return
+		}
+		timezone = value
+	case "workspace_locale":
+		if !models.IsValidWorkspaceLocale(value) && value != workspace.Locale {
+			log.Warn(ctx, "Ignoring unknown workspace locale", "locale", value)
+			return
+		}
+		locale = value
+	}
+
+	if err := sh.slackService.UpdateWorkspaceLocale(ctx, teamID, timezone, locale); err != nil {
+		log.Error(ctx, "Failed to update workspace locale", "error", err)
+		return
+	}
+
+	log.Info(ctx, "Workspace locale updated from App Home", "user_id", userID, "timezone", timezone, "locale", locale)
+	sh.refreshHomeView(ctx, userID)
+}
+
+// handleWorkspaceLinkInvitesAction sets how unlinked CC'd users are invited to link their account, from App Home.
+// Only workspace admins can change it.
+func (sh *SlackHandler) handleWorkspaceLinkInvitesAction(ctx context.Context, userID, teamID, mode string, c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{})
+
+	isAdmin, err := sh.slackService.IsWorkspaceAdmin(ctx, teamID, userID)
+	if err != nil || !isAdmin {
+		log.Warn(ctx, "Ignoring workspace link invites change from non-admin", "error", err, "user_id", userID)
+		return
+	}
+	if !models.IsValidLinkInvitesMode(mode) {
+		log.Warn(ctx, "Ignoring unknown link invites mode", "link_invites", mode)
+		return
+	}
+
+	if err := sh.slackService.UpdateWorkspaceLinkInvites(ctx, teamID, mode); err != nil {
+		log.Error(ctx, "Failed to update workspace link invites", "error", err)
+		return
+	}
+
+	log.Info(ctx, "Workspace link invites updated from App Home", "user_id", userID, "link_invites", mode)
+	sh.refreshHomeView(ctx, userID)
+}
+
+// handleLinkInviteOptOutAction handles the "Don't ask again" button on an invitation to link a GitHub account.
+// Stops invitations for the GitHub username in the workspace, whoever they'd be sent to.
+func (sh *SlackHandler) handleLinkInviteOptOutAction(
+	ctx context.Context, interaction *slack.InteractionCallback, githubUsername string, c *gin.Context,
+) {
+	c.JSON(http.StatusOK, gin.H{})
+
+	userID := interaction.User.ID
+	teamID := interaction.Team.ID
+	ctx = log.WithFields(ctx, log.LogFields{
+		"user_id":         userID,
+		"team_id":         teamID,
+		"github_username": githubUsername,
+	})
+	if githubUsername == "" {
+		return
+	}
+
+	if err := sh.firestoreService.OptOutLinkInvites(ctx, teamID, githubUsername, userID); err != nil {
+		log.Error(ctx, "Failed to opt out of link invites", "error", err)
+		return
+	}
+	log.Info(ctx, "User opted out of link invites")
+
+	message := "Got it, I won't ask about *@" + githubUsername + "* again. You can still link your GitHub account in my App Home."
+	if err := sh.slackService.SendEphemeralMessage(ctx, teamID, interaction.Channel.ID, userID, message); err != nil {
+		log.Warn(ctx, "Failed to confirm link invite opt-out", "error", err)
+	}
+}
+
 // handleConnectGitHubAction handles the "Connect GitHub Account" button from App Home.
 // Creates OAuth state, marks it for home return, and opens OAuth modal with GitHub link.
 func (sh *SlackHandler) handleConnectGitHubAction(ctx context.Context, userID, teamID, triggerID string, c *gin.Context) {
@@ -609,8 +980,21 @@ func (sh *SlackHandler) handleGitHubAppInstallation(
 		return
 	}
 
-	// Generate GitHub App installation URL (will trigger combined OAuth + installation flow)
-	oauthURL := sh.githubAuthService.GetAppInstallationURL(state.ID)
+	// Generate GitHub App installation URL (will trigger combined OAuth + installation flow). With GitHub
+	// Enterprise Server instances configured, the user first chooses where to install it
+	var oauthURL string
+	if len(sh.config.GitHubEnterpriseServers) > 0 {
+		oauthURL = fmt.Sprintf("%s/auth/github/install?state=%s", sh.config.BaseURL, state.ID)
+	} else if oauthURL, err = sh.githubAuthService.GetAppInstallationURL(state); err != nil {
+		log.Error(ctx, "Failed to build GitHub App installation URL", "error", err)
+		c.JSON(http.StatusOK, gin.H{
+			"response_action": "errors",
+			"errors": map[string]string{
+				errorKey: "Failed to initiate GitHub App installation. Please try again.",
+			},
+		})
+		return
+	}
 
 	if fromModal {
 		log.Info(ctx, "Generated GitHub OAuth URL for App installation from modal", "state_id", state.ID)
@@ -721,6 +1105,9 @@ func (sh *SlackHandler) handleDisconnectGitHubAction(ctx context.Context, userID
 		c.JSON(http.StatusOK, gin.H{})
 		return
 	}
+	if err := sh.githubAuthService.DeleteUserToken(ctx, user.SlackTeamID, userID); err != nil {
+		log.Warn(ctx, "Failed to delete GitHub user token on disconnect", "error", err)
+	}
 
 	// Refresh the home view to show disconnected state
 	sh.refreshHomeView(ctx, userID)
@@ -734,9 +1121,15 @@ func (sh *SlackHandler) handleSelectChannelAction(ctx context.Context, userID, t
 		"user_id": userID,
 	})
 
-	modalView := sh.slackService.BuildChannelSelectorModal()
+	// The user may not exist yet, in which case the modal starts empty
+	user, err := sh.firestoreService.GetUserBySlackID(ctx, userID)
+	if err != nil {
+		log.Warn(ctx, "Failed to get user for channel selection modal", "error", err)
+	}
+
+	modalView := sh.slackService.BuildChannelSelectorModal(user)
 
-	_, err := sh.slackService.OpenView(ctx, teamID, triggerID, modalView)
+	_, err = sh.slackService.OpenView(ctx, teamID, triggerID, modalView)
 	if err != nil {
 		log.Error(ctx, "Failed to open channel selection modal", "error", err)
 	}
@@ -744,15 +1137,22 @@ func (sh *SlackHandler) handleSelectChannelAction(ctx context.Context, userID, t
 	c.JSON(http.StatusOK, gin.H{})
 }
 
-// extractChannelSelection extracts the selected channel ID from modal interaction state.
-// Returns empty string if no valid channel selection is found.
-func (sh *SlackHandler) extractChannelSelection(interaction *slack.InteractionCallback) string {
+// extractChannelSelection extracts the selected channel IDs and per-repository channels text from modal interaction state.
+func (sh *SlackHandler) extractChannelSelection(interaction *slack.InteractionCallback) ([]string, string) {
+	var channelIDs []string
 	if values, ok := interaction.View.State.Values["channel_input"]; ok {
 		if channelSelect, ok := values["channel_select"]; ok {
-			return channelSelect.SelectedChannel
+			channelIDs = channelSelect.SelectedChannels
 		}
 	}
-	return ""
+
+	var repoChannels string
+	if values, ok := interaction.View.State.Values["repo_channels_input"]; ok {
+		if text, ok := values["repo_channels_text"]; ok {
+			repoChannels = text.Value
+		}
+	}
+	return channelIDs, repoChannels
 }
 
 // validateChannelSelection validates the selected channel and returns user-friendly error message.
@@ -817,7 +1217,7 @@ func (sh *SlackHandler) createOrGetUserWithDisplayName(ctx context.Context, user
 }
 
 // handleChannelSelection processes channel selection submission from modal.
-// Validates selected channel, updates user's default channel preference, and refreshes App Home.
+// Validates selected channels and per-repository channels, updates the user's channel preferences, and refreshes App Home.
 func (sh *SlackHandler) handleChannelSelection(ctx context.Context, interaction *slack.InteractionCallback, c *gin.Context) {
 	userID := interaction.User.ID
 	teamID := interaction.Team.ID
@@ -826,24 +1226,37 @@ func (sh *SlackHandler) handleChannelSelection(ctx context.Context, interaction
 		"user_id": userID,
 	})
 
-	// Extract selected channel
-	channelID := sh.extractChannelSelection(interaction)
-	if channelID == "" {
+	// Extract selected channels
+	channelIDs, repoChannelsText := sh.extractChannelSelection(interaction)
+	if len(channelIDs) == 0 {
 		c.JSON(http.StatusOK, map[string]interface{}{
 			"response_action": "errors",
 			"errors": map[string]string{
-				"channel_input": "Please select a channel.",
+				"channel_input": "Please select at least one channel.",
 			},
 		})
 		return
 	}
 
-	// Validate the channel
-	if errorMsg, err := sh.validateChannelSelection(ctx, teamID, channelID); err != nil {
+	// Validate the channels
+	for _, channelID := range channelIDs {
+		if errorMsg, err := sh.validateChannelSelection(ctx, teamID, channelID); err != nil {
+			c.JSON(http.StatusOK, map[string]interface{}{
+				"response_action": "errors",
+				"errors": map[string]string{
+					"channel_input": errorMsg,
+				},
+			})
+			return
+		}
+	}
+
+	repoChannels, err := utils.ParseRepoChannelOverrides(repoChannelsText)
+	if err != nil {
 		c.JSON(http.StatusOK, map[string]interface{}{
 			"response_action": "errors",
 			"errors": map[string]string{
-				"channel_input": errorMsg,
+				"repo_channels_input": err.Error(),
 			},
 		})
 		return
@@ -857,11 +1270,12 @@ func (sh *SlackHandler) handleChannelSelection(ctx context.Context, interaction
 		return
 	}
 
-	// Update user's default channel
-	user.DefaultChannel = channelID
+	// Update user's default channels
+	user.SetDefaultChannels(channelIDs)
+	user.RepoChannels = repoChannels
 	err = sh.firestoreService.CreateOrUpdateUser(ctx, user)
 	if err != nil {
-		log.Error(ctx, "Failed to update user channel", "error", err)
+		log.Error(ctx, "Failed to update user channels", "error", err)
 		c.JSON(http.StatusOK, gin.H{})
 		return
 	}
@@ -871,6 +1285,76 @@ func (sh *SlackHandler) handleChannelSelection(ctx context.Context, interaction
 	c.JSON(http.StatusOK, gin.H{"response_action": "clear"})
 }
 
+// handleShowPRDetailsAction handles the "Show more" button on a PR message by expanding
+// the full title and description into the message's thread.
+func (sh *SlackHandler) handleShowPRDetailsAction(
+	ctx context.Context, interaction *slack.InteractionCallback, details string, c *gin.Context,
+) {
+	messageTS := interaction.Container.MessageTs
+	if messageTS == "" {
+		messageTS = interaction.Message.Timestamp
+	}
+
+	err := sh.slackService.PostPRDetails(ctx, interaction.Team.ID, interaction.Channel.ID, messageTS, details,
+		openPRButtonURL(interaction.Message.Attachments))
+	if err != nil {
+		log.Error(ctx, "Failed to expand PR details", "error", err, "channel_id", interaction.Channel.ID, "message_ts", messageTS)
+	}
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+// openPRButtonURL returns the URL of the "Open PR" button among a message's attachments, or "" if it has none.
+func openPRButtonURL(attachments []slack.Attachment) string {
+	for _, attachment := range attachments {
+		for _, block := range attachment.Blocks.BlockSet {
+			actions, ok := block.(*slack.ActionBlock)
+			if !ok || actions.Elements == nil {
+				continue
+			}
+			for _, element := range actions.Elements.ElementSet {
+				if button, ok := element.(*slack.ButtonBlockElement); ok && button.ActionID == services.OpenPRActionID {
+					return button.URL
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// handleOpenPRAction records a click on a PR message's "Open PR" button. Slack opens the PR itself,
+// so this only counts the click towards the message's engagement.
+func (sh *SlackHandler) handleOpenPRAction(ctx context.Context, interaction *slack.InteractionCallback, c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{})
+
+	messageTS := interaction.Container.MessageTs
+	if messageTS == "" {
+		messageTS = interaction.Message.Timestamp
+	}
+	ctx = log.WithFields(ctx, log.LogFields{
+		"channel_id": interaction.Channel.ID,
+		"message_ts": messageTS,
+	})
+
+	message, err := sh.firestoreService.GetTrackedMessageBySlackMessage(ctx, interaction.Team.ID, interaction.Channel.ID, messageTS)
+	if err != nil {
+		log.Error(ctx, "Failed to look up clicked PR message", "error", err)
+		return
+	}
+	if message == nil {
+		log.Debug(ctx, "Ignoring click on untracked PR message")
+		return
+	}
+
+	if err := sh.firestoreService.RecordTrackedMessageClick(ctx, message, time.Now()); err != nil {
+		return // Logged by RecordTrackedMessageClick
+	}
+	log.Debug(ctx, "Recorded PR message click",
+		"repo", message.RepoFullName,
+		"pr_number", message.PRNumber,
+		"user_id", interaction.User.ID,
+	)
+}
+
 // handleRefreshViewAction handles the refresh button action from App Home.
 // Triggers immediate refresh of the user's App Home view with current data.
 func (sh *SlackHandler) handleRefreshViewAction(ctx context.Context, userID string, c *gin.Context) {
@@ -905,6 +1389,8 @@ func (sh *SlackHandler) refreshHomeView(ctx context.Context, userID string) {
 	hasInstallations := len(installations) > 0
 
 	view := sh.slackService.BuildHomeView(user, hasInstallations, installations)
+	sh.addWorkspaceAdminSections(ctx, user.SlackTeamID, userID, &view)
+	sh.addActivePRsSection(ctx, user, 0, &view)
 	err = sh.slackService.PublishHomeView(ctx, user.SlackTeamID, userID, view)
 	if err != nil {
 		log.Error(ctx, "Failed to refresh App Home view", "error", err)
@@ -952,6 +1438,290 @@ func (sh *SlackHandler) handleToggleImpersonationAction(ctx context.Context, use
 	})
 }
 
+// handleConnectSlackUserTokenAction handles the "Post with your Slack account" connect button from App Home.
+// Creates OAuth state and opens a modal linking to the Slack user token authorization.
+func (sh *SlackHandler) handleConnectSlackUserTokenAction(ctx context.Context, userID, teamID, triggerID string, c *gin.Context) {
+	ctx = log.WithFields(ctx, log.LogFields{
+		"user_id": userID,
+	})
+
+	state, err := sh.githubAuthService.CreateOAuthState(ctx, userID, teamID, "")
+	if err != nil {
+		log.Error(ctx, "Failed to create OAuth state for Slack user token", "error", err)
+		c.JSON(http.StatusOK, gin.H{})
+		return
+	}
+
+	oauthURL := fmt.Sprintf("%s/auth/slack/user?state=%s", sh.config.BaseURL, state.ID)
+	modalView := sh.slackService.BuildSlackUserTokenModal(oauthURL)
+
+	_, err = sh.slackService.OpenView(ctx, teamID, triggerID, modalView)
+	if err != nil {
+		log.Error(ctx, "Failed to open Slack user token modal", "error", err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+// handleDisconnectSlackUserTokenAction handles the "Post with your Slack account" disconnect button from App Home.
+// Revokes and deletes the stored user token before switching back to username/icon impersonation.
+func (sh *SlackHandler) handleDisconnectSlackUserTokenAction(ctx context.Context, userID, teamID string, c *gin.Context) {
+	if err := sh.slackService.RevokeUserToken(ctx, teamID, userID); err != nil {
+		log.Error(ctx, "Failed to delete Slack user token", "error", err, "user_id", userID)
+		c.JSON(http.StatusOK, gin.H{})
+		return
+	}
+
+	sh.handleUserSettingToggle(ctx, userID, c, "user token posting", func(user *models.User) {
+		user.UserTokenPosting = false
+	}, func(user *models.User) map[string]interface{} {
+		return map[string]interface{}{
+			"user_token_posting": user.UserTokenPosting,
+			"github_username":    user.GitHubUsername,
+		}
+	})
+}
+
+// handleToggleAssignmentDMsAction handles the toggle for DMs when the user is assigned to a PR or unassigned.
+func (sh *SlackHandler) handleToggleAssignmentDMsAction(ctx context.Context, userID string, c *gin.Context) {
+	sh.handleUserSettingToggle(ctx, userID, c, "assignment DMs", func(user *models.User) {
+		user.AssignmentDMs = !user.AssignmentDMs
+	}, func(user *models.User) map[string]interface{} {
+		return map[string]interface{}{
+			"assignment_dms":  user.AssignmentDMs,
+			"github_username": user.GitHubUsername,
+		}
+	})
+}
+
+// handleToggleOutOfOfficeAction handles the out of office toggle. The delegate is kept for next time.
+func (sh *SlackHandler) handleToggleOutOfOfficeAction(ctx context.Context, userID string, c *gin.Context) {
+	sh.handleUserSettingToggle(ctx, userID, c, "out of office", func(user *models.User) {
+		user.OutOfOffice = !user.OutOfOffice
+	}, func(user *models.User) map[string]interface{} {
+		return map[string]interface{}{
+			"out_of_office":   user.OutOfOffice,
+			"ooo_delegate":    user.OOODelegate,
+			"github_username": user.GitHubUsername,
+		}
+	})
+}
+
+// handleOutOfOfficeDelegateAction handles picking the user CC'd in the user's place while they're out of office.
+// Picking yourself clears the delegate.
+func (sh *SlackHandler) handleOutOfOfficeDelegateAction(ctx context.Context, userID, delegateID string, c *gin.Context) {
+	sh.handleUserSettingToggle(ctx, userID, c, "out of office delegate", func(user *models.User) {
+		if delegateID == userID {
+			delegateID = ""
+		}
+		user.OOODelegate = delegateID
+	}, func(user *models.User) map[string]interface{} {
+		return map[string]interface{}{
+			"ooo_delegate":    user.OOODelegate,
+			"github_username": user.GitHubUsername,
+		}
+	})
+}
+
+// handleToggleReviewCommentThreadsAction handles the toggle for threading review comments under the user's PR messages.
+func (sh *SlackHandler) handleToggleReviewCommentThreadsAction(ctx context.Context, userID string, c *gin.Context) {
+	sh.handleUserSettingToggle(ctx, userID, c, "review comment threads", func(user *models.User) {
+		newValue := !user.GetReviewCommentThreadsEnabled()
+		user.ReviewCommentThreads = &newValue
+	}, func(user *models.User) map[string]interface{} {
+		return map[string]interface{}{
+			"review_comment_threads": user.GetReviewCommentThreadsEnabled(),
+		}
+	})
+}
+
+// handleToggleDigestModeAction handles the digest mode enable/disable toggle.
+// Updates whether CC mentions and author DMs are batched into an hourly digest and refreshes App Home view.
+func (sh *SlackHandler) handleToggleDigestModeAction(ctx context.Context, userID string, c *gin.Context) {
+	sh.handleUserSettingToggle(ctx, userID, c, "digest mode", func(user *models.User) {
+		user.DigestMode = !user.DigestMode
+	}, func(user *models.User) map[string]interface{} {
+		return map[string]interface{}{
+			"digest_mode":     user.DigestMode,
+			"github_username": user.GitHubUsername,
+		}
+	})
+}
+
+// handleToggleDailyDigestAction handles the daily PR digest enable/disable toggle.
+// The digest starts at the default hour in the user's Slack timezone, or the workspace's when that's unavailable,
+// and refreshes App Home view.
+func (sh *SlackHandler) handleToggleDailyDigestAction(ctx context.Context, userID, teamID string, c *gin.Context) {
+	timezone := sh.defaultUserTimezone(ctx, userID, teamID)
+
+	sh.handleUserSettingToggle(ctx, userID, c, "daily digest", func(user *models.User) {
+		if user.DailyDigest == nil {
+			user.DailyDigest = &models.DailyDigestPreferences{Hour: models.DefaultDailyDigestHour}
+		}
+		if user.DailyDigest.Timezone == "" {
+			user.DailyDigest.Timezone = timezone
+		}
+		user.DailyDigest.Enabled = !user.DailyDigest.Enabled
+	}, func(user *models.User) map[string]interface{} {
+		return map[string]interface{}{
+			"daily_digest":    user.DailyDigest.Enabled,
+			"timezone":        user.DailyDigest.Timezone,
+			"github_username": user.GitHubUsername,
+		}
+	})
+}
+
+// handleDailyDigestScheduleAction handles changes to the daily digest's time or timezone selects.
+func (sh *SlackHandler) handleDailyDigestScheduleAction(ctx context.Context, userID, actionID, value string, c *gin.Context) {
+	sh.handleUserSettingToggle(ctx, userID, c, "daily digest schedule", func(user *models.User) {
+		if user.DailyDigest == nil {
+			user.DailyDigest = &models.DailyDigestPreferences{Hour: models.DefaultDailyDigestHour}
+		}
+		if actionID == "daily_digest_timezone" {
+			if _, err := time.LoadLocation(value); err == nil {
+				user.DailyDigest.Timezone = value
+			}
+			return
+		}
+		if hour, err := strconv.Atoi(value); err == nil && models.IsValidLocalHour(hour) {
+			user.DailyDigest.Hour = hour
+		}
+	}, func(user *models.User) map[string]interface{} {
+		return map[string]interface{}{
+			"daily_digest_hour": user.DailyDigest.Hour,
+			"timezone":          user.DailyDigest.Timezone,
+			"github_username":   user.GitHubUsername,
+		}
+	})
+}
+
+// handleToggleQuietHoursAction handles the quiet hours enable/disable toggle.
+// Quiet hours start at the default hours in the user's Slack timezone, or the workspace's when that's unavailable,
+// and refreshes App Home view.
+func (sh *SlackHandler) handleToggleQuietHoursAction(ctx context.Context, userID, teamID string, c *gin.Context) {
+	timezone := sh.defaultUserTimezone(ctx, userID, teamID)
+
+	sh.handleUserSettingToggle(ctx, userID, c, "quiet hours", func(user *models.User) {
+		if user.QuietHours == nil {
+			user.QuietHours = &models.QuietHoursPreferences{
+				StartHour: models.DefaultQuietHoursStart,
+				EndHour:   models.DefaultQuietHoursEnd,
+			}
+		}
+		if user.QuietHours.Timezone == "" {
+			user.QuietHours.Timezone = timezone
+		}
+		user.QuietHours.Enabled = !user.QuietHours.Enabled
+	}, func(user *models.User) map[string]interface{} {
+		return map[string]interface{}{
+			"quiet_hours":     user.QuietHours.Enabled,
+			"timezone":        user.QuietHours.Timezone,
+			"github_username": user.GitHubUsername,
+		}
+	})
+}
+
+// handleQuietHoursScheduleAction handles changes to the quiet hours' start, end or timezone selects.
+func (sh *SlackHandler) handleQuietHoursScheduleAction(ctx context.Context, userID, actionID, value string, c *gin.Context) {
+	sh.handleUserSettingToggle(ctx, userID, c, "quiet hours schedule", func(user *models.User) {
+		if user.QuietHours == nil {
+			user.QuietHours = &models.QuietHoursPreferences{
+				StartHour: models.DefaultQuietHoursStart,
+				EndHour:   models.DefaultQuietHoursEnd,
+			}
+		}
+		if actionID == "quiet_hours_timezone" {
+			if _, err := time.LoadLocation(value); err == nil {
+				user.QuietHours.Timezone = value
+			}
+			return
+		}
+		hour, err := strconv.Atoi(value)
+		if err != nil || !models.IsValidLocalHour(hour) {
+			return
+		}
+		if actionID == "quiet_hours_start" {
+			user.QuietHours.StartHour = hour
+		} else {
+			user.QuietHours.EndHour = hour
+		}
+	}, func(user *models.User) map[string]interface{} {
+		return map[string]interface{}{
+			"quiet_hours_start": user.QuietHours.StartHour,
+			"quiet_hours_end":   user.QuietHours.EndHour,
+			"timezone":          user.QuietHours.Timezone,
+			"github_username":   user.GitHubUsername,
+		}
+	})
+}
+
+// defaultUserTimezone returns the timezone new schedules start in: the user's Slack timezone, or the
+// workspace's when that's unavailable. Empty means UTC.
+func (sh *SlackHandler) defaultUserTimezone(ctx context.Context, userID, teamID string) string {
+	if slackUser, err := sh.slackService.GetUserInfo(ctx, teamID, userID); err == nil && slackUser.TZ != "" {
+		return slackUser.TZ
+	} else if err != nil {
+		log.Warn(ctx, "Failed to get Slack timezone, using the workspace timezone", "error", err)
+	}
+	if workspace, err := sh.slackService.GetWorkspace(ctx, teamID); err == nil {
+		return workspace.Timezone
+	}
+	return ""
+}
+
+// handleAuthorDMPreferencesAction handles changes to the author DM checkboxes.
+// Stores which events on the user's own PRs should trigger a direct message and refreshes App Home view.
+func (sh *SlackHandler) handleAuthorDMPreferencesAction(
+	ctx context.Context, userID string, selectedOptions []slack.OptionBlockObject, c *gin.Context,
+) {
+	sh.handleUserSettingToggle(ctx, userID, c, "author DM", func(user *models.User) {
+		preferences := &models.AuthorDMPreferences{}
+		for _, option := range selectedOptions {
+			switch option.Value {
+			case models.AuthorDMEventChangesRequested:
+				preferences.ChangesRequested = true
+			case models.AuthorDMEventCIFailed:
+				preferences.CIFailed = true
+			case models.AuthorDMEventMergeConflict:
+				preferences.MergeConflict = true
+			}
+		}
+		user.AuthorDMs = preferences
+	}, func(user *models.User) map[string]interface{} {
+		return map[string]interface{}{
+			"dm_changes_requested": user.AuthorDMs.ChangesRequested,
+			"dm_ci_failed":         user.AuthorDMs.CIFailed,
+			"dm_merge_conflict":    user.AuthorDMs.MergeConflict,
+			"github_username":      user.GitHubUsername,
+		}
+	})
+}
+
+// handleReviewRequestPreferencesAction handles changes to the review request notification checkboxes.
+// Stores how the user wants to hear about review requests and refreshes App Home view.
+func (sh *SlackHandler) handleReviewRequestPreferencesAction(
+	ctx context.Context, userID string, selectedOptions []slack.OptionBlockObject, c *gin.Context,
+) {
+	sh.handleUserSettingToggle(ctx, userID, c, "review request notification", func(user *models.User) {
+		preferences := &models.ReviewRequestPreferences{}
+		for _, option := range selectedOptions {
+			switch option.Value {
+			case models.ReviewRequestNotifyDM:
+				preferences.DM = true
+			case models.ReviewRequestNotifyThreadNote:
+				preferences.ThreadNote = true
+			}
+		}
+		user.ReviewRequests = preferences
+	}, func(user *models.User) map[string]interface{} {
+		return map[string]interface{}{
+			"review_request_dm":          user.ReviewRequests.DM,
+			"review_request_thread_note": user.ReviewRequests.ThreadNote,
+			"github_username":            user.GitHubUsername,
+		}
+	})
+}
+
 // handleUserSettingToggle provides common implementation for user setting toggles.
 // Applies toggle function, saves user changes, logs update, and refreshes App Home view.
 func (sh *SlackHandler) handleUserSettingToggle(
@@ -1069,14 +1839,8 @@ func (sh *SlackHandler) handleChannelTrackingSelection(ctx context.Context, inte
 		log.Error(ctx, "Failed to get channel config", "error", err)
 	}
 
-	// Default to enabled if no config exists
-	currentlyEnabled := true
-	if currentConfig != nil {
-		currentlyEnabled = currentConfig.ManualTrackingEnabled
-	}
-
-	// Build the configuration modal for the selected channel
-	configModal := sh.slackService.BuildChannelTrackingConfigModal(channelID, channelName, currentlyEnabled)
+	// Build the configuration modal for the selected channel (nil config uses defaults)
+	configModal := sh.slackService.BuildChannelTrackingConfigModal(channelID, channelName, currentConfig)
 
 	// Push the configuration modal as a new view
 	c.JSON(http.StatusOK, map[string]interface{}{
@@ -1108,6 +1872,85 @@ func (sh *SlackHandler) handleSaveChannelTracking(ctx context.Context, interacti
 		}
 	}
 
+	// Extract reaction set setting
+	reactionSet := models.ReactionSetAll // Default to all reactions
+	if values, ok := interaction.View.State.Values["reaction_set_input"]; ok {
+		if radioButtons, ok := values["reaction_set_radio"]; ok {
+			switch radioButtons.SelectedOption.Value {
+			case models.ReactionSetAll, models.ReactionSetStateOnly, models.ReactionSetNone:
+				reactionSet = radioButtons.SelectedOption.Value
+			}
+		}
+	}
+
+	// Extract optional release cut deadline
+	var releaseCutDeadline *time.Time
+	if values, ok := interaction.View.State.Values["release_cut_input"]; ok {
+		if picker, ok := values["release_cut_datetime"]; ok && picker.SelectedDateTime > 0 {
+			deadline := time.Unix(picker.SelectedDateTime, 0)
+			releaseCutDeadline = &deadline
+		}
+	}
+
+	// Extract project context setting
+	projectContext := false
+	if values, ok := interaction.View.State.Values["project_context_input"]; ok {
+		if checkboxes, ok := values["project_context_checkbox"]; ok {
+			projectContext = len(checkboxes.SelectedOptions) > 0
+		}
+	}
+
+	// Extract review comment threads setting
+	reviewCommentThreads := false
+	if values, ok := interaction.View.State.Values["review_comment_threads_input"]; ok {
+		if checkboxes, ok := values["review_comment_threads_checkbox"]; ok {
+			reviewCommentThreads = len(checkboxes.SelectedOptions) > 0
+		}
+	}
+
+	// Extract retention repost setting
+	repostPruned := false
+	if values, ok := interaction.View.State.Values["repost_pruned_input"]; ok {
+		if checkboxes, ok := values["repost_pruned_checkbox"]; ok {
+			repostPruned = len(checkboxes.SelectedOptions) > 0
+		}
+	}
+
+	// Extract closed PR archiving setting
+	archiveClosedPRs := false
+	if values, ok := interaction.View.State.Values["archive_closed_prs_input"]; ok {
+		if checkboxes, ok := values["archive_closed_prs_checkbox"]; ok {
+			archiveClosedPRs = len(checkboxes.SelectedOptions) > 0
+		}
+	}
+
+	// Extract message layout setting
+	messageFormat := models.MessageFormatText
+	if values, ok := interaction.View.State.Values["message_format_input"]; ok {
+		if checkboxes, ok := values["message_format_checkbox"]; ok && len(checkboxes.SelectedOptions) > 0 {
+			messageFormat = models.MessageFormatRich
+		}
+	}
+
+	// Filters and PR size emoji are edited in their own modals, so they're carried over as they are
+	currentConfig, err := sh.firestoreService.GetChannelConfig(ctx, teamID, channelID)
+	if err != nil {
+		log.Error(ctx, "Failed to get channel config", "error", err)
+		c.JSON(http.StatusOK, map[string]interface{}{
+			"response_action": "errors",
+			"errors": map[string]string{
+				"tracking_enabled_input": "Failed to save configuration. Please try again.",
+			},
+		})
+		return
+	}
+	var filters *models.PRFilters
+	var prSizeConfig *models.PRSizeConfiguration
+	if currentConfig != nil {
+		filters = currentConfig.Filters
+		prSizeConfig = currentConfig.PRSizeConfig
+	}
+
 	// Get channel name for the config
 	channelName, err := sh.slackService.GetChannelName(ctx, teamID, channelID)
 	if err != nil {
@@ -1122,6 +1965,15 @@ func (sh *SlackHandler) handleSaveChannelTracking(ctx context.Context, interacti
 		SlackChannelID:        channelID,
 		SlackChannelName:      channelName,
 		ManualTrackingEnabled: trackingEnabled,
+		ReactionSet:           reactionSet,
+		ReleaseCutDeadline:    releaseCutDeadline,
+		ProjectContext:        projectContext,
+		ReviewCommentThreads:  reviewCommentThreads,
+		RepostPruned:          repostPruned,
+		ArchiveClosedPRs:      archiveClosedPRs,
+		MessageFormat:         messageFormat,
+		Filters:               filters,
+		PRSizeConfig:          prSizeConfig,
 		ConfiguredBy:          userID,
 	}
 
@@ -1139,6 +1991,13 @@ func (sh *SlackHandler) handleSaveChannelTracking(ctx context.Context, interacti
 
 	log.Info(ctx, "Channel tracking configuration saved",
 		"tracking_enabled", trackingEnabled,
+		"reaction_set", reactionSet,
+		"release_cut_deadline", releaseCutDeadline,
+		"project_context", projectContext,
+		"review_comment_threads", reviewCommentThreads,
+		"repost_pruned", repostPruned,
+		"archive_closed_prs", archiveClosedPRs,
+		"message_format", messageFormat,
 		"channel_name", channelName)
 
 	// Close the modal with success
@@ -1203,8 +2062,8 @@ func (sh *SlackHandler) verifySignature(header http.Header, body []byte) error {
 	return nil
 }
 
-// ProcessManualPRLinkJob processes a manual PR link job from the job system.
-// Creates tracked message for manual PR link and enqueues reaction sync job for initial state.
+// ProcessManualPRLinkJob processes a manual PR or issue link job from the job system.
+// Creates tracked message for the link and enqueues reaction sync job for initial state.
 func (sh *SlackHandler) ProcessManualPRLinkJob(ctx context.Context, job *models.Job) error {
 	// Parse the ManualLinkJob from the job payload
 	var manualLinkJob models.ManualLinkJob
@@ -1213,7 +2072,7 @@ func (sh *SlackHandler) ProcessManualPRLinkJob(ctx context.Context, job *models.
 			"error", err,
 			"job_id", job.ID,
 		)
-		return fmt.Errorf("failed to unmarshal manual link job: %w", err)
+		return fmt.Errorf("failed to unmarshal manual link job: %w", models.Permanent(err))
 	}
 
 	// Validate the manual link job
@@ -1222,7 +2081,7 @@ func (sh *SlackHandler) ProcessManualPRLinkJob(ctx context.Context, job *models.
 			"error", err,
 			"job_id", job.ID,
 		)
-		return fmt.Errorf("invalid manual link job: %w", err)
+		return fmt.Errorf("invalid manual link job: %w", models.Permanent(err))
 	}
 
 	// Resolve channel name to ID if needed (though should already be ID from Slack events)
@@ -1239,6 +2098,7 @@ func (sh *SlackHandler) ProcessManualPRLinkJob(ctx context.Context, job *models.
 	trackedMessage := &models.TrackedMessage{
 		PRNumber:         manualLinkJob.PRNumber,
 		RepoFullName:     manualLinkJob.RepoFullName,
+		ItemType:         manualLinkJob.ItemType,
 		SlackChannel:     channelID,
 		SlackChannelName: manualLinkJob.SlackChannel, // Store original for logging if it was a name
 		SlackMessageTS:   manualLinkJob.SlackMessageTS,
@@ -1256,17 +2116,20 @@ func (sh *SlackHandler) ProcessManualPRLinkJob(ctx context.Context, job *models.
 	log.Info(ctx, "Manual PR link tracked successfully",
 		"repo", manualLinkJob.RepoFullName,
 		"pr_number", manualLinkJob.PRNumber,
+		"item_type", manualLinkJob.ItemType,
 		"slack_channel", manualLinkJob.SlackChannel,
 		"slack_team_id", manualLinkJob.SlackTeamID,
 		"message_ts", manualLinkJob.SlackMessageTS)
 
-	// Enqueue a reaction sync job to sync initial reactions for this PR
+	// Enqueue a reaction sync job to sync initial reactions for this PR or issue
 	reactionSyncJobID := uuid.New().String()
 	reactionSyncJob := &models.ReactionSyncJob{
 		ID:           reactionSyncJobID,
 		PRNumber:     manualLinkJob.PRNumber,
 		RepoFullName: manualLinkJob.RepoFullName,
+		ItemType:     manualLinkJob.ItemType,
 		TraceID:      manualLinkJob.TraceID,
+		RequestedAt:  time.Now(),
 	}
 
 	// Marshal the ReactionSyncJob as the payload for the Job
@@ -1368,6 +2231,18 @@ func (sh *SlackHandler) handlePRSizeConfigSubmission(ctx context.Context, intera
 		return
 	}
 
+	// Reject aliases the workspace doesn't have, rather than posting them as literal :alias: text
+	if unknown := sh.unknownPRSizeEmojis(ctx, interaction.Team.ID, prSizeConfig); len(unknown) > 0 {
+		log.Warn(ctx, "PR size configuration uses emoji not in workspace", "unknown_emoji", unknown)
+		c.JSON(http.StatusOK, map[string]interface{}{
+			"response_action": "errors",
+			"errors": map[string]string{
+				"pr_size_config_input": "Unknown emoji in this workspace: " + strings.Join(unknown, ", "),
+			},
+		})
+		return
+	}
+
 	// Get user data
 	user, err := sh.firestoreService.GetUserBySlackID(ctx, userID)
 	if err != nil {
@@ -1408,98 +2283,34 @@ func (sh *SlackHandler) handlePRSizeConfigSubmission(ctx context.Context, intera
 	c.JSON(http.StatusOK, gin.H{})
 }
 
-// parsePRSizeConfig parses and validates PR size emoji configuration from text input.
-// Returns the parsed configuration or validation errors.
-func (sh *SlackHandler) parsePRSizeConfig(configText string) (*models.PRSizeConfiguration, map[string]string) {
-	configText = strings.TrimSpace(configText)
-
-	// If empty, disable custom configuration
-	if configText == "" {
-		return &models.PRSizeConfiguration{Enabled: false}, nil
+// unknownPRSizeEmojis returns the emoji aliases in a PR size configuration that don't exist in the workspace.
+// If the workspace's emoji can't be listed, validation is skipped so the configuration can still be saved.
+func (sh *SlackHandler) unknownPRSizeEmojis(ctx context.Context, teamID string, prSizeConfig *models.PRSizeConfiguration) []string {
+	if prSizeConfig == nil || !prSizeConfig.Enabled {
+		return nil
 	}
 
-	lines := strings.Split(configText, "\n")
-	const maxExpectedThresholds = 10
-	thresholds := make([]models.PRSizeThreshold, 0, maxExpectedThresholds) // Pre-allocate with reasonable capacity
-	errors := make(map[string]string)
-
-	lineNum := 0
-	lastMaxLines := 0
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue // Skip empty lines
-		}
-
-		lineNum++
-		parts := strings.Fields(line)
-		const expectedParts = 2
-		if len(parts) != expectedParts {
-			errors["pr_size_config_input"] = fmt.Sprintf("Line %d: Format must be 'emoji max_lines' (e.g., ':ant: 5')", lineNum)
-			return nil, errors
-		}
-
-		emoji := parts[0]
-		maxLinesStr := parts[1]
-
-		// Validate emoji format
-		if !sh.isValidEmoji(emoji) {
-			errors["pr_size_config_input"] = fmt.Sprintf("Line %d: Invalid emoji format. Use ':emoji_name:' or Unicode emoji", lineNum)
-			return nil, errors
-		}
-
-		// Parse and validate max lines
-		maxLines, err := strconv.Atoi(maxLinesStr)
-		if err != nil || maxLines <= 0 {
-			errors["pr_size_config_input"] = fmt.Sprintf("Line %d: Max lines must be a positive number", lineNum)
-			return nil, errors
-		}
-
-		// Validate ascending order
-		if maxLines <= lastMaxLines {
-			errors["pr_size_config_input"] = fmt.Sprintf(
-				"Line %d: Max lines (%d) must be greater than previous (%d)",
-				lineNum, maxLines, lastMaxLines,
-			)
-			return nil, errors
-		}
-
-		thresholds = append(thresholds, models.PRSizeThreshold{
-			MaxLines: maxLines,
-			Emoji:    emoji,
-		})
-
-		lastMaxLines = maxLines
+	emojis := make([]string, 0, len(prSizeConfig.Thresholds))
+	for _, threshold := range prSizeConfig.Thresholds {
+		emojis = append(emojis, threshold.Emoji)
 	}
 
-	if len(thresholds) == 0 {
-		return &models.PRSizeConfiguration{Enabled: false}, nil
+	unknown, err := sh.slackService.UnknownEmojiAliases(ctx, teamID, emojis)
+	if err != nil {
+		log.Warn(ctx, "Failed to validate PR size emoji against workspace emoji", "error", err)
+		return nil
 	}
-
-	return &models.PRSizeConfiguration{
-		Enabled:    true,
-		Thresholds: thresholds,
-	}, nil
+	return unknown
 }
 
-// isValidEmoji checks if a string is a valid emoji (either :emoji_name: format or Unicode emoji).
-func (sh *SlackHandler) isValidEmoji(emoji string) bool {
-	// Check for :emoji_name: format
-	if strings.HasPrefix(emoji, ":") && strings.HasSuffix(emoji, ":") && len(emoji) > 2 {
-		emojiName := strings.Trim(emoji, ":")
-		if emojiName != "" {
-			return true
-		}
+// parsePRSizeConfig parses and validates PR size emoji configuration from text input.
+// Returns the parsed configuration, or validation errors keyed by the modal's input block.
+func (sh *SlackHandler) parsePRSizeConfig(configText string) (*models.PRSizeConfiguration, map[string]string) {
+	prSizeConfig, err := prsize.Parse(configText)
+	if err != nil {
+		return nil, map[string]string{"pr_size_config_input": err.Error()}
 	}
-
-	// Check for Unicode emoji using a simple regex
-	// This matches most common Unicode emoji ranges
-	emojiRegex := regexp.MustCompile(
-		`^[\x{1F600}-\x{1F64F}]|[\x{1F300}-\x{1F5FF}]|[\x{1F680}-\x{1F6FF}]|` +
-			`[\x{1F1E0}-\x{1F1FF}]|[\x{2600}-\x{26FF}]|[\x{2700}-\x{27BF}]$`,
-	)
-	return emojiRegex.MatchString(emoji)
+	return prSizeConfig, nil
 }
 
 // ProcessDeleteTrackedMessageJob processes a job to delete a tracked message.
@@ -1512,7 +2323,7 @@ func (sh *SlackHandler) ProcessDeleteTrackedMessageJob(ctx context.Context, job
 			"error", err,
 			"job_id", job.ID,
 		)
-		return fmt.Errorf("failed to unmarshal delete tracked message job: %w", err)
+		return fmt.Errorf("failed to unmarshal delete tracked message job: %w", models.Permanent(err))
 	}
 
 	// Validate the delete job
@@ -1521,7 +2332,7 @@ func (sh *SlackHandler) ProcessDeleteTrackedMessageJob(ctx context.Context, job
 			"error", err,
 			"job_id", job.ID,
 		)
-		return fmt.Errorf("invalid delete tracked message job: %w", err)
+		return fmt.Errorf("invalid delete tracked message job: %w", models.Permanent(err))
 	}
 
 	// Add context for logging
@@ -1535,7 +2346,9 @@ func (sh *SlackHandler) ProcessDeleteTrackedMessageJob(ctx context.Context, job
 	log.Info(ctx, "Processing tracked message deletion job")
 
 	// Delete the Slack message
-	err := sh.slackService.DeleteMessage(ctx, deleteJob.SlackTeamID, deleteJob.SlackChannel, deleteJob.SlackMessageTS)
+	err := sh.slackService.DeleteMessage(
+		ctx, deleteJob.SlackTeamID, deleteJob.SlackChannel, deleteJob.SlackMessageTS, deleteJob.PostedAsUserID,
+	)
 	if err != nil {
 		log.Error(ctx, "Failed to delete Slack message", "error", err)
 		return fmt.Errorf("failed to delete Slack message: %w", err)
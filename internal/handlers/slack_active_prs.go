@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/slack-go/slack"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+)
+
+// activePRsLookback is how far back the App Home "Active PRs" section looks for a user's PR notifications.
+const activePRsLookback = 90 * 24 * time.Hour
+
+// addActivePRsSection inserts one page of the "Active PRs" section, listing the user's open PRs the bot has posted,
+// before the App Home "App setup" header. The page size is limited by how many blocks the view has left, so it's
+// added after every other section. Failures are logged and leave the section out.
+func (sh *SlackHandler) addActivePRsSection(ctx context.Context, user *models.User, page int, view *slack.HomeTabViewRequest) {
+	if user == nil || !user.Verified || user.GitHubUserID == 0 {
+		return
+	}
+
+	pageSize := sh.slackService.ActivePRsPageSize(len(view.Blocks.BlockSet))
+	if pageSize == 0 {
+		log.Warn(ctx, "No room left in App Home for the Active PRs section")
+		return
+	}
+
+	since := time.Now().Add(-activePRsLookback)
+	messages, err := sh.firestoreService.GetRecentTrackedMessagesByAuthor(ctx, user.SlackTeamID, user.GitHubUserID, since)
+	if err != nil {
+		log.Warn(ctx, "Failed to get tracked messages for App Home Active PRs", "error", err)
+		return
+	}
+
+	prs := groupActivePRs(messages)
+	pageCount := max((len(prs)+pageSize-1)/pageSize, 1)
+	page = min(max(page, 0), pageCount-1)
+	prs = prs[page*pageSize : min((page+1)*pageSize, len(prs))]
+
+	for i := range prs {
+		msg := prs[i].Message
+		permalink, err := sh.slackService.GetPermalink(ctx, msg.SlackTeamID, msg.SlackChannel, msg.SlackMessageTS)
+		if err != nil {
+			log.Warn(ctx, "Failed to get permalink for App Home Active PRs", "error", err, "channel", msg.SlackChannel)
+			continue
+		}
+		prs[i].Permalink = permalink
+	}
+
+	sh.slackService.AddActivePRsSection(view, prs, page, pageCount, time.Now())
+}
+
+// groupActivePRs groups a user's bot messages by PR, keeping PRs that are still open, newest first. Each PR links
+// to its first message and lists every channel it was posted to.
+func groupActivePRs(messages []*models.TrackedMessage) []models.ActivePR {
+	byPR := make(map[string]*models.ActivePR)
+	for _, msg := range messages {
+		if msg.MessageSource != models.MessageSourceBot || msg.DeletedByUser || msg.IsIssue() || msg.ClosedAt != nil {
+			continue
+		}
+
+		key := msg.RepoFullName + "#" + strconv.Itoa(msg.PRNumber)
+		pr, ok := byPR[key]
+		if !ok {
+			byPR[key] = &models.ActivePR{Message: msg, Channels: []string{msg.SlackChannel}}
+			continue
+		}
+		if !slices.Contains(pr.Channels, msg.SlackChannel) {
+			pr.Channels = append(pr.Channels, msg.SlackChannel)
+		}
+		if msg.CreatedAt.Before(pr.Message.CreatedAt) {
+			pr.Message = msg
+		}
+	}
+
+	prs := make([]models.ActivePR, 0, len(byPR))
+	for _, pr := range byPR {
+		prs = append(prs, *pr)
+	}
+	sort.Slice(prs, func(i, j int) bool {
+		return prs[i].Message.CreatedAt.After(prs[j].Message.CreatedAt)
+	})
+	return prs
+}
+
+// handleActivePRsPageAction shows another page of the App Home "Active PRs" section.
+func (sh *SlackHandler) handleActivePRsPageAction(ctx context.Context, userID, teamID, value string, c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{})
+
+	page, err := strconv.Atoi(value)
+	if err != nil {
+		log.Warn(ctx, "Ignoring invalid Active PRs page", "value", value)
+		return
+	}
+	sh.publishHomeViewPage(ctx, teamID, userID, page)
+}
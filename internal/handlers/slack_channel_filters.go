@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+	"github.com/slack-go/slack"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+)
+
+// handleEditChannelFiltersAction handles the "Edit filters" button in a channel's tracking settings.
+// Pushes the editor for the filters limiting which PRs are posted to the channel.
+func (sh *SlackHandler) handleEditChannelFiltersAction(ctx context.Context, teamID, triggerID, channelID string, c *gin.Context) {
+	ctx = log.WithFields(ctx, log.LogFields{
+		"team_id":    teamID,
+		"channel_id": channelID,
+	})
+
+	channelConfig, err := sh.firestoreService.GetChannelConfig(ctx, teamID, channelID)
+	if err != nil {
+		log.Error(ctx, "Failed to get channel config for filters", "error", err)
+		c.JSON(http.StatusOK, gin.H{})
+		return
+	}
+	var filters *models.PRFilters
+	if channelConfig != nil {
+		filters = channelConfig.Filters
+	}
+
+	if _, err := sh.slackService.PushView(ctx, teamID, triggerID, sh.slackService.BuildChannelFiltersModal(channelID, filters)); err != nil {
+		log.Error(ctx, "Failed to push channel filters modal", "error", err)
+	}
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+// handleSaveChannelFilters validates and saves a channel's PR filters from the editor modal.
+// Channels without a config get one with the default settings, so only the filters change.
+func (sh *SlackHandler) handleSaveChannelFilters(ctx context.Context, interaction *slack.InteractionCallback, c *gin.Context) {
+	userID := interaction.User.ID
+	teamID := interaction.Team.ID
+	channelID := interaction.View.PrivateMetadata // Channel ID stored in private metadata
+	values := interaction.View.State.Values
+
+	ctx = log.WithFields(ctx, log.LogFields{
+		"user_id":    userID,
+		"team_id":    teamID,
+		"channel_id": channelID,
+	})
+
+	respondWithError := func(blockID, message string) {
+		c.JSON(http.StatusOK, map[string]interface{}{
+			"response_action": "errors",
+			"errors": map[string]string{
+				blockID: message,
+			},
+		})
+	}
+
+	minSize, ok := parsePRSizeLimit(values["channel_filters_min_size_input"]["channel_filters_min_size"].Value)
+	if !ok {
+		respondWithError("channel_filters_min_size_input", "Enter a whole number of lines.")
+		return
+	}
+	maxSize, ok := parsePRSizeLimit(values["channel_filters_max_size_input"]["channel_filters_max_size"].Value)
+	if !ok {
+		respondWithError("channel_filters_max_size_input", "Enter a whole number of lines.")
+		return
+	}
+
+	filters := &models.PRFilters{
+		MinPRSize:      minSize,
+		MaxPRSize:      maxSize,
+		AllowAuthors:   parseFilterList(values["channel_filters_allow_authors_input"]["channel_filters_allow_authors"].Value, "@"),
+		DenyAuthors:    parseFilterList(values["channel_filters_deny_authors_input"]["channel_filters_deny_authors"].Value, "@"),
+		BranchPatterns: parseFilterList(values["channel_filters_branches_input"]["channel_filters_branches"].Value, ""),
+		TitlePattern:   strings.TrimSpace(values["channel_filters_title_input"]["channel_filters_title"].Value),
+	}
+	if err := filters.Validate(); err != nil {
+		switch {
+		case errors.Is(err, models.ErrInvalidPRSizeRange):
+			respondWithError("channel_filters_max_size_input", "The maximum size must be at least the minimum size.")
+		case errors.Is(err, models.ErrInvalidBranchPattern):
+			respondWithError("channel_filters_branches_input", "Check the branch patterns: "+err.Error())
+		default:
+			respondWithError("channel_filters_title_input", "This isn't a valid regular expression.")
+		}
+		return
+	}
+	if filters.IsEmpty() {
+		filters = nil
+	}
+
+	channelConfig, err := sh.firestoreService.GetChannelConfig(ctx, teamID, channelID)
+	if err != nil {
+		log.Error(ctx, "Failed to get channel config for filters", "error", err)
+		respondWithError("channel_filters_min_size_input", "Failed to save the filters. Please try again.")
+		return
+	}
+	if channelConfig == nil {
+		channelName, err := sh.slackService.GetChannelName(ctx, teamID, channelID)
+		if err != nil {
+			log.Warn(ctx, "Failed to get channel name", "error", err)
+			channelName = channelID // Fallback to ID
+		}
+		channelConfig = &models.ChannelConfig{
+			ID:                    teamID + "#" + channelID,
+			SlackTeamID:           teamID,
+			SlackChannelID:        channelID,
+			SlackChannelName:      channelName,
+			ManualTrackingEnabled: true,
+		}
+	}
+	channelConfig.Filters = filters
+	channelConfig.ConfiguredBy = userID
+
+	if err := sh.firestoreService.SaveChannelConfig(ctx, channelConfig); err != nil {
+		log.Error(ctx, "Failed to save channel filters", "error", err)
+		respondWithError("channel_filters_min_size_input", "Failed to save the filters. Please try again.")
+		return
+	}
+
+	log.Info(ctx, "Channel filters saved", "cleared", filters == nil)
+
+	// Closing this modal returns to the channel's tracking settings, whose summary shows the filters when reopened
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+// parsePRSizeLimit reads a PR size filter from a text input, returning 0 when it's empty.
+// Reports false if the input isn't a non-negative whole number.
+func parsePRSizeLimit(value string) (int, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, true
+	}
+	size, err := strconv.Atoi(value)
+	if err != nil || size < 0 {
+		return 0, false
+	}
+	return size, true
+}
+
+// parseFilterList splits a filter list entered separated by commas or spaces, trimming prefix from each entry.
+func parseFilterList(value, prefix string) []string {
+	var entries []string
+	for _, entry := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || unicode.IsSpace(r) }) {
+		if entry = strings.TrimPrefix(entry, prefix); entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
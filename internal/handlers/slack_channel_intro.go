@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/slack-go/slack/slackevents"
+
+	"github-slack-notifier/internal/log"
+)
+
+// handleMemberJoinedChannelEvent introduces the bot when someone invites it to a channel. Channels the bot joins by
+// itself to post a PR have no inviter and are skipped, since they're already set up to receive PRs.
+func (sh *SlackHandler) handleMemberJoinedChannelEvent(ctx context.Context, event *slackevents.MemberJoinedChannelEvent, teamID string) {
+	if event.Inviter == "" {
+		return
+	}
+
+	workspace, err := sh.slackService.GetWorkspace(ctx, teamID)
+	if err != nil {
+		log.Error(ctx, "Failed to get workspace for channel join", "error", err)
+		return
+	}
+	if workspace == nil || workspace.BotUserID == "" || event.User != workspace.BotUserID {
+		return
+	}
+
+	ctx = log.WithFields(ctx, log.LogFields{
+		"channel": event.Channel,
+		"inviter": event.Inviter,
+	})
+
+	channelName, err := sh.slackService.GetChannelName(ctx, teamID, event.Channel)
+	if err != nil {
+		log.Warn(ctx, "Failed to get channel name for intro", "error", err)
+		channelName = "channel-name"
+	}
+
+	if err := sh.slackService.PostChannelIntro(ctx, teamID, event.Channel, channelName); err != nil {
+		return
+	}
+	log.Info(ctx, "Posted intro to channel the bot was invited to")
+}
+
+// handleChannelIntroConfigureAction opens the channel configuration modal from the bot's intro message.
+func (sh *SlackHandler) handleChannelIntroConfigureAction(ctx context.Context, teamID, channelID, triggerID string, c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{})
+
+	ctx = log.WithFields(ctx, log.LogFields{
+		"team_id":    teamID,
+		"channel_id": channelID,
+	})
+
+	channelName, err := sh.slackService.GetChannelName(ctx, teamID, channelID)
+	if err != nil {
+		log.Error(ctx, "Failed to get channel name", "error", err)
+		channelName = channelID
+	}
+
+	currentConfig, err := sh.firestoreService.GetChannelConfig(ctx, teamID, channelID)
+	if err != nil {
+		log.Error(ctx, "Failed to get channel config", "error", err)
+	}
+
+	configModal := sh.slackService.BuildChannelTrackingConfigModal(channelID, channelName, currentConfig)
+	if _, err := sh.slackService.OpenView(ctx, teamID, triggerID, configModal); err != nil {
+		log.Error(ctx, "Failed to open channel configuration modal", "error", err)
+	}
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// cleanupMaxDeletesPerCollection caps the documents one cleanup run deletes from each collection, so a large
+// backlog is worked through over several scheduled runs rather than one job exceeding its deadline.
+const cleanupMaxDeletesPerCollection = 2000
+
+// ProcessCleanupJob deletes data that's no longer needed: expired OAuth states, tracked messages for PRs
+// merged or closed longer ago than TRACKED_MESSAGE_RETENTION, and GitHub installations of uninstalled workspaces.
+// Each collection is cleaned independently, so one failing doesn't stop the others; the job is retried if any failed.
+// Deletions are logged and added to the totals exported on /metrics, except in dry runs, which only count.
+func (sh *SlackHandler) ProcessCleanupJob(ctx context.Context, job *models.Job) error {
+	var cleanupJob models.CleanupJob
+	if len(job.Payload) > 0 {
+		if err := json.Unmarshal(job.Payload, &cleanupJob); err != nil {
+			return fmt.Errorf("failed to unmarshal cleanup job: %w", models.Permanent(err))
+		}
+	}
+	ctx = log.WithFields(ctx, log.LogFields{"dry_run": cleanupJob.DryRun})
+
+	now := time.Now()
+	deleted := make(map[string]int)
+	var errs []error
+
+	count, err := sh.firestoreService.DeleteExpiredOAuthStates(ctx, now, cleanupMaxDeletesPerCollection, cleanupJob.DryRun)
+	deleted[models.CleanupCollectionOAuthStates] = count
+	errs = append(errs, err)
+
+	if retention := sh.config.TrackedMessageRetention; retention > 0 {
+		count, err = sh.firestoreService.DeleteClosedTrackedMessages(ctx, now.Add(-retention),
+			cleanupMaxDeletesPerCollection, cleanupJob.DryRun)
+		deleted[models.CleanupCollectionTrackedMessages] = count
+		errs = append(errs, err)
+	}
+
+	count, err = sh.firestoreService.DeleteOrphanedInstallations(ctx, cleanupMaxDeletesPerCollection, cleanupJob.DryRun)
+	deleted[models.CleanupCollectionInstallations] = count
+	errs = append(errs, err)
+
+	log.Info(ctx, "Cleanup completed",
+		"deleted_oauth_states", deleted[models.CleanupCollectionOAuthStates],
+		"deleted_tracked_messages", deleted[models.CleanupCollectionTrackedMessages],
+		"deleted_installations", deleted[models.CleanupCollectionInstallations],
+		"tracked_message_retention", sh.config.TrackedMessageRetention.String(),
+	)
+
+	if !cleanupJob.DryRun {
+		if err := sh.firestoreService.RecordCleanup(ctx, deleted, time.Now()); err != nil {
+			// The deletions happened; only the metrics are behind
+			log.Warn(ctx, "Failed to record cleanup stats", "error", err)
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		log.Error(ctx, "Cleanup failed for some collections", "error", err)
+		return err
+	}
+	return nil
+}
+
+// HandleCleanupTrigger queues a cleanup job, so Cloud Scheduler can start cleanup runs by posting to /jobs/cleanup
+// without building a job body. The optional JSON body is the cleanup job's payload, e.g. {"dry_run": true}.
+func (sh *SlackHandler) HandleCleanupTrigger(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read request body"})
+		return
+	}
+	var cleanupJob models.CleanupJob
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &cleanupJob); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid cleanup options"})
+			return
+		}
+	}
+
+	jobPayload, err := json.Marshal(cleanupJob)
+	if err != nil {
+		log.Error(ctx, "Failed to marshal cleanup job", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to marshal job"})
+		return
+	}
+	job := &models.Job{
+		ID:      uuid.New().String(),
+		Type:    models.JobTypeCleanup,
+		TraceID: c.GetString("trace_id"),
+		Payload: jobPayload,
+	}
+
+	if err := sh.cloudTasksService.EnqueueJob(ctx, job); err != nil {
+		log.Error(ctx, "Failed to enqueue cleanup job", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to queue cleanup job"})
+		return
+	}
+
+	log.Info(ctx, "Cleanup job queued", "job_id", job.ID, "dry_run", cleanupJob.DryRun)
+	c.JSON(http.StatusOK, gin.H{
+		"status": "queued",
+		"job_id": job.ID,
+	})
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github-slack-notifier/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errQueueUnavailable = errors.New("queue unavailable")
+
+// recordingCloudTasksService records the jobs enqueued with it, failing with err if set.
+type recordingCloudTasksService struct {
+	jobs []*models.Job
+	err  error
+}
+
+func (r *recordingCloudTasksService) EnqueueJob(_ context.Context, job *models.Job) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.jobs = append(r.jobs, job)
+	return nil
+}
+
+func TestSlackHandler_HandleCleanupTrigger(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		body           string
+		enqueueErr     error
+		expectedStatus int
+		expectedDryRun bool
+		expectQueued   bool
+	}{
+		{
+			name:           "empty body queues a cleanup",
+			body:           "",
+			expectedStatus: http.StatusOK,
+			expectQueued:   true,
+		},
+		{
+			name:           "dry run is passed on",
+			body:           `{"dry_run": true}`,
+			expectedStatus: http.StatusOK,
+			expectedDryRun: true,
+			expectQueued:   true,
+		},
+		{
+			name:           "invalid body is rejected",
+			body:           `{"dry_run": "yes"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "enqueue failure",
+			body:           "",
+			enqueueErr:     errQueueUnavailable,
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cloudTasks := &recordingCloudTasksService{err: tt.enqueueErr}
+			handler := &SlackHandler{cloudTasksService: cloudTasks}
+
+			router := gin.New()
+			router.POST("/jobs/cleanup", handler.HandleCleanupTrigger)
+			req := httptest.NewRequest(http.MethodPost, "/jobs/cleanup", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if !tt.expectQueued {
+				assert.Empty(t, cloudTasks.jobs)
+				return
+			}
+
+			require.Len(t, cloudTasks.jobs, 1)
+			job := cloudTasks.jobs[0]
+			assert.Equal(t, models.JobTypeCleanup, job.Type)
+			require.NoError(t, job.Validate())
+
+			var cleanupJob models.CleanupJob
+			require.NoError(t, json.Unmarshal(job.Payload, &cleanupJob))
+			assert.Equal(t, tt.expectedDryRun, cleanupJob.DryRun)
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/slack-go/slack"
+)
+
+const (
+	// defaultReportWindow is used when /pr-report is run without a window argument.
+	defaultReportWindow = 7 * 24 * time.Hour
+	// maxReportWindow bounds how far back /pr-report will look.
+	maxReportWindow = 90 * 24 * time.Hour
+)
+
+var (
+	// ErrInvalidReportArgument indicates an unrecognized /pr-report argument.
+	ErrInvalidReportArgument = errors.New("invalid report argument")
+	// ErrReportWindowTooLong indicates the requested report window exceeds the maximum.
+	ErrReportWindowTooLong = errors.New("report window too long")
+
+	reportWindowRegex     = regexp.MustCompile(`^(\d+)([hdw])$`)
+	escapedChannelRegex   = regexp.MustCompile(`^<#([A-Z0-9]+)(?:\|[^>]*)?>$`)
+	reportWindowUnitHours = map[string]int{"h": 1, "d": 24, "w": 7 * 24}
+)
+
+// reportArgs holds the parsed arguments of the /pr-report command.
+type reportArgs struct {
+	Channel string
+	Window  time.Duration
+	Post    bool
+}
+
+// HandleSlashCommand processes incoming Slack slash command requests.
+// Verifies the request signature and dispatches to the handler for the command.
+func (sh *SlackHandler) HandleSlashCommand(c *gin.Context) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read body"})
+		return
+	}
+
+	if err := sh.verifySignature(c.Request.Header, body); err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid signature"})
+		return
+	}
+
+	// Restore the body so the form can be parsed
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	cmd, err := slack.SlashCommandParse(c.Request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to parse slash command"})
+		return
+	}
+
+	ctx := log.WithFields(c.Request.Context(), log.LogFields{
+		"command": cmd.Command,
+		"user_id": cmd.UserID,
+		"team_id": cmd.TeamID,
+	})
+
+	log.Info(ctx, "Processing Slack slash command")
+	sh.ensureEnterpriseWorkspace(ctx, cmd.TeamID, cmd.EnterpriseID)
+
+	switch cmd.Command {
+	case "/pr-report":
+		sh.handlePRReportCommand(ctx, &cmd, c)
+	case "/pr-bot":
+		sh.handlePRBotCommand(ctx, &cmd, c)
+	default:
+		log.Warn(ctx, "Unknown slash command")
+		c.JSON(http.StatusOK, ephemeralResponse("Unknown command: "+cmd.Command))
+	}
+}
+
+// handlePRReportCommand handles `/pr-report [#channel] [7d] [post]` by queueing a channel report job.
+func (sh *SlackHandler) handlePRReportCommand(ctx context.Context, cmd *slack.SlashCommand, c *gin.Context) {
+	args, err := parseReportArgs(cmd.Text, cmd.ChannelID)
+	if err != nil {
+		c.JSON(http.StatusOK, ephemeralResponse(
+			fmt.Sprintf("❌ %s\nUsage: `/pr-report [#channel] [7d] [post]`", err.Error())))
+		return
+	}
+
+	jobID := uuid.New().String()
+	traceID := traceIDForNewJob(ctx)
+	reportJob := &models.ChannelReportJob{
+		ID:           jobID,
+		SlackTeamID:  cmd.TeamID,
+		SlackChannel: args.Channel,
+		SlackUserID:  cmd.UserID,
+		ReplyChannel: cmd.ChannelID,
+		Window:       args.Window,
+		Post:         args.Post,
+		TraceID:      traceID,
+	}
+
+	jobPayload, err := json.Marshal(reportJob)
+	if err != nil {
+		log.Error(ctx, "Failed to marshal channel report job", "error", err)
+		c.JSON(http.StatusOK, ephemeralResponse(withTraceReference(ctx, "❌ Failed to generate report. Please try again.")))
+		return
+	}
+
+	job := &models.Job{
+		ID:      jobID,
+		Type:    models.JobTypeChannelReport,
+		TraceID: traceID,
+		Payload: jobPayload,
+	}
+
+	if err := sh.cloudTasksService.EnqueueJob(ctx, job); err != nil {
+		log.Error(ctx, "Failed to enqueue channel report job", "error", err)
+		c.JSON(http.StatusOK, ephemeralResponse(withTraceReference(ctx, "❌ Failed to generate report. Please try again.")))
+		return
+	}
+
+	log.Info(ctx, "Channel report queued",
+		"report_channel", args.Channel,
+		"window", args.Window.String(),
+		"post", args.Post,
+	)
+	c.JSON(http.StatusOK, ephemeralResponse("⏳ Generating PR report…"))
+}
+
+// parseReportArgs parses `/pr-report` arguments in any order: an optional channel
+// (`<#C123|name>` or `#name`), an optional window (e.g. `24h`, `7d`, `2w`) and an optional `post` flag.
+func parseReportArgs(text, defaultChannel string) (*reportArgs, error) {
+	args := &reportArgs{
+		Channel: defaultChannel,
+		Window:  defaultReportWindow,
+	}
+
+	for _, token := range strings.Fields(text) {
+		if matches := escapedChannelRegex.FindStringSubmatch(token); matches != nil {
+			args.Channel = matches[1]
+			continue
+		}
+		if strings.HasPrefix(token, "#") && len(token) > 1 {
+			args.Channel = strings.TrimPrefix(token, "#")
+			continue
+		}
+		if strings.EqualFold(token, "post") {
+			args.Post = true
+			continue
+		}
+		if matches := reportWindowRegex.FindStringSubmatch(strings.ToLower(token)); matches != nil {
+			amount, err := strconv.Atoi(matches[1])
+			if err != nil || amount <= 0 {
+				return nil, fmt.Errorf("%w: %s", ErrInvalidReportArgument, token)
+			}
+			hours := amount * reportWindowUnitHours[matches[2]]
+			if amount > int(maxReportWindow/time.Hour) || time.Duration(hours)*time.Hour > maxReportWindow {
+				return nil, fmt.Errorf("%w: maximum is 90d", ErrReportWindowTooLong)
+			}
+			args.Window = time.Duration(hours) * time.Hour
+			continue
+		}
+		return nil, fmt.Errorf("%w: %s", ErrInvalidReportArgument, token)
+	}
+
+	return args, nil
+}
+
+// ephemeralResponse builds a slash command response visible only to the invoking user.
+func ephemeralResponse(text string) gin.H {
+	return gin.H{
+		"response_type": slack.ResponseTypeEphemeral,
+		"text":          text,
+	}
+}
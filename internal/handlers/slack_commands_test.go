@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github-slack-notifier/internal/models"
+	"github-slack-notifier/internal/utils"
+)
+
+func TestParseReportArgs(t *testing.T) {
+	tests := []struct {
+		name        string
+		text        string
+		expected    *reportArgs
+		expectedErr error
+	}{
+		{
+			name:     "no arguments uses current channel and default window",
+			text:     "",
+			expected: &reportArgs{Channel: "CDEFAULT", Window: 7 * 24 * time.Hour},
+		},
+		{
+			name:     "escaped channel and window",
+			text:     "<#C123ABC|eng-prs> 14d",
+			expected: &reportArgs{Channel: "C123ABC", Window: 14 * 24 * time.Hour},
+		},
+		{
+			name:     "channel name, hours and post in any order",
+			text:     "post 24h #eng-prs",
+			expected: &reportArgs{Channel: "eng-prs", Window: 24 * time.Hour, Post: true},
+		},
+		{
+			name:     "weeks window",
+			text:     "2w",
+			expected: &reportArgs{Channel: "CDEFAULT", Window: 14 * 24 * time.Hour},
+		},
+		{
+			name:        "window too long",
+			text:        "91d",
+			expectedErr: ErrReportWindowTooLong,
+		},
+		{
+			name:        "huge window does not overflow",
+			text:        "99999999999999999w",
+			expectedErr: ErrReportWindowTooLong,
+		},
+		{
+			name:        "zero window",
+			text:        "0d",
+			expectedErr: ErrInvalidReportArgument,
+		},
+		{
+			name:        "unknown argument",
+			text:        "yesterday",
+			expectedErr: ErrInvalidReportArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args, err := parseReportArgs(tt.text, "CDEFAULT")
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, args)
+		})
+	}
+}
+
+func TestParsePRBotCommand(t *testing.T) {
+	prURL := "https://github.com/owner/repo/pull/42"
+
+	tests := []struct {
+		name        string
+		text        string
+		expected    *prBotCommand
+		expectedErr error
+	}{
+		{
+			name:     "no subcommand shows help",
+			text:     "",
+			expected: &prBotCommand{Subcommand: prBotSubcommandHelp},
+		},
+		{
+			name:     "link",
+			text:     "link",
+			expected: &prBotCommand{Subcommand: prBotSubcommandLink},
+		},
+		{
+			name: "status with escaped URL",
+			text: "status <" + prURL + ">",
+			expected: &prBotCommand{Subcommand: prBotSubcommandStatus, PRLink: &utils.PRLink{
+				URL: prURL, Owner: "owner", Repo: "repo", PRNumber: 42, FullRepoName: "owner/repo",
+				ItemType: models.TrackedItemTypePR,
+			}},
+		},
+		{
+			name:        "status with issue URL",
+			text:        "status https://github.com/owner/repo/issues/42",
+			expectedErr: ErrInvalidPRBotArgument,
+		},
+		{
+			name:        "status without URL",
+			text:        "status",
+			expectedErr: ErrInvalidPRBotArgument,
+		},
+		{
+			name:     "set-channel defaults to current channel",
+			text:     "set-channel",
+			expected: &prBotCommand{Subcommand: prBotSubcommandSetChannel, Channel: "CDEFAULT"},
+		},
+		{
+			name:     "set-channel with escaped channel",
+			text:     "Set-Channel <#C123ABC|eng-prs>",
+			expected: &prBotCommand{Subcommand: prBotSubcommandSetChannel, Channel: "C123ABC"},
+		},
+		{
+			name:        "set-channel with plain name",
+			text:        "set-channel #eng-prs",
+			expectedErr: ErrInvalidPRBotArgument,
+		},
+		{
+			name:     "mute",
+			text:     "mute",
+			expected: &prBotCommand{Subcommand: prBotSubcommandMute},
+		},
+		{
+			name:     "mute off",
+			text:     "mute OFF",
+			expected: &prBotCommand{Subcommand: prBotSubcommandMute, Unmute: true},
+		},
+		{
+			name:        "unknown subcommand",
+			text:        "unlink",
+			expectedErr: ErrUnknownPRBotSubcommand,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, err := parsePRBotCommand(tt.text, "CDEFAULT")
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, cmd)
+		})
+	}
+}
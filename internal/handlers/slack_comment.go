@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/slack-go/slack/slackevents"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+)
+
+// handleSlackComment queues a thread reply starting with "!to-github" to be posted as a comment on the PR of the
+// message it's threaded under, in workspaces that have turned this on. The reply's author is told privately when it
+// can't be posted.
+func (sh *SlackHandler) handleSlackComment(ctx context.Context, event *slackevents.MessageEvent, teamID, text string) {
+	ctx = log.WithFields(ctx, log.LogFields{
+		"channel":   event.Channel,
+		"thread_ts": event.ThreadTimeStamp,
+		"user_id":   event.User,
+	})
+
+	workspace, err := sh.slackService.GetWorkspace(ctx, teamID)
+	if err != nil {
+		log.Error(ctx, "Failed to get workspace for Slack comment", "error", err)
+		return
+	}
+	if !workspace.SlackToGitHubComments {
+		sh.sendSlackCommentNotice(ctx, teamID, event,
+			"Posting replies to GitHub with `!to-github` is turned off in this workspace. "+
+				"A workspace admin can turn it on in my App Home.")
+		return
+	}
+	if text == "" {
+		sh.sendSlackCommentNotice(ctx, teamID, event,
+			"Add your comment after `!to-github`, e.g. `!to-github Could we split the migration into its own PR?`")
+		return
+	}
+
+	commentJob := &models.SlackCommentJob{
+		ID:             uuid.New().String(),
+		SlackTeamID:    teamID,
+		SlackChannel:   event.Channel,
+		SlackThreadTS:  event.ThreadTimeStamp,
+		SlackMessageTS: event.TimeStamp,
+		SlackUserID:    event.User,
+		Text:           text,
+		TraceID:        traceIDForNewJob(ctx),
+	}
+
+	jobPayload, err := json.Marshal(commentJob)
+	if err != nil {
+		log.Error(ctx, "Failed to marshal Slack comment job", "error", err)
+		return
+	}
+
+	job := &models.Job{
+		ID:      commentJob.ID,
+		Type:    models.JobTypeSlackComment,
+		TraceID: commentJob.TraceID,
+		Payload: jobPayload,
+	}
+	if err := sh.cloudTasksService.EnqueueJob(ctx, job); err != nil {
+		log.Error(ctx, "Failed to enqueue Slack comment", "error", err)
+		return
+	}
+
+	log.Info(ctx, "Slack comment queued for posting to GitHub", "job_id", job.ID)
+}
+
+// sendSlackCommentNotice tells the author of a "!to-github" reply why it wasn't posted, in a message only they see.
+func (sh *SlackHandler) sendSlackCommentNotice(ctx context.Context, teamID string, event *slackevents.MessageEvent, text string) {
+	if err := sh.slackService.SendEphemeralMessage(ctx, teamID, event.Channel, event.User, text); err != nil {
+		log.Warn(ctx, "Failed to send Slack comment notice", "error", err)
+	}
+}
+
+// handleToggleWorkspaceSlackCommentsAction turns posting "!to-github" thread replies as PR comments on or off,
+// from App Home. Only workspace admins can change it.
+func (sh *SlackHandler) handleToggleWorkspaceSlackCommentsAction(ctx context.Context, userID, teamID string, c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{})
+
+	isAdmin, err := sh.slackService.IsWorkspaceAdmin(ctx, teamID, userID)
+	if err != nil || !isAdmin {
+		log.Warn(ctx, "Ignoring workspace Slack comments change from non-admin", "error", err, "user_id", userID)
+		return
+	}
+
+	workspace, err := sh.slackService.GetWorkspace(ctx, teamID)
+	if err != nil {
+		log.Error(ctx, "Failed to get workspace for Slack comments change", "error", err)
+		return
+	}
+
+	enabled := !workspace.SlackToGitHubComments
+	if err := sh.slackService.UpdateWorkspaceSlackToGitHubComments(ctx, teamID, enabled); err != nil {
+		log.Error(ctx, "Failed to update workspace Slack comments", "error", err)
+		return
+	}
+
+	log.Info(ctx, "Workspace Slack comments updated from App Home", "user_id", userID, "enabled", enabled)
+	sh.refreshHomeView(ctx, userID)
+}
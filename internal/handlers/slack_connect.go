@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+
+	"github-slack-notifier/internal/log"
+)
+
+// slackEventEnvelope holds the outer Events API fields slackevents doesn't parse,
+// which are needed to route events from Slack Connect (externally shared) channels.
+type slackEventEnvelope struct {
+	TeamID             string `json:"team_id"`
+	IsExtSharedChannel bool   `json:"is_ext_shared_channel"`
+	Authorizations     []struct {
+		TeamID string `json:"team_id"`
+	} `json:"authorizations"`
+}
+
+// installationTeamID returns the team ID of the workspace an event was delivered to, and whether the event
+// came from an externally shared channel. In Slack Connect channels the outer team_id is the workspace where
+// the event happened, which can belong to the other organization, while our tokens and tracked messages are
+// keyed by the installing workspace, named in the event's authorizations.
+func installationTeamID(body []byte) (string, bool) {
+	var envelope slackEventEnvelope
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return "", false
+	}
+
+	if len(envelope.Authorizations) > 0 && envelope.Authorizations[0].TeamID != "" {
+		return envelope.Authorizations[0].TeamID, envelope.IsExtSharedChannel
+	}
+	return envelope.TeamID, envelope.IsExtSharedChannel
+}
+
+// acceptSharedChannelEvent decides whether an event from a Slack Connect channel can be handled.
+// Events are handled as the installing workspace; they're declined when that workspace isn't installed,
+// since there's no token to act with and nothing tracked under it.
+func (sh *SlackHandler) acceptSharedChannelEvent(ctx context.Context, eventTeamID, teamID string) bool {
+	if teamID == eventTeamID {
+		return true
+	}
+
+	installed, err := sh.slackService.IsWorkspaceInstalled(ctx, teamID)
+	if err != nil {
+		log.Error(ctx, "Failed to check installation for Slack Connect event", "error", err)
+		return false
+	}
+	if !installed {
+		log.Warn(ctx, "Declining Slack Connect event: the receiving workspace has no installation",
+			"installation_team_id", teamID,
+			"event_team_id", eventTeamID,
+		)
+		return false
+	}
+
+	log.Debug(ctx, "Routing Slack Connect event via installing workspace",
+		"installation_team_id", teamID,
+		"event_team_id", eventTeamID,
+	)
+	return true
+}
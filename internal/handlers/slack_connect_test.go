@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstallationTeamID(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		expectedTeamID string
+		expectedShared bool
+	}{
+		{
+			name:           "regular channel uses the event team",
+			body:           `{"type":"event_callback","team_id":"T1","authorizations":[{"team_id":"T1"}]}`,
+			expectedTeamID: "T1",
+		},
+		{
+			name:           "shared channel uses the installing team",
+			body:           `{"type":"event_callback","team_id":"TEXT","is_ext_shared_channel":true,"authorizations":[{"team_id":"T1"}]}`,
+			expectedTeamID: "T1",
+			expectedShared: true,
+		},
+		{
+			name:           "falls back to the event team without authorizations",
+			body:           `{"type":"event_callback","team_id":"T1"}`,
+			expectedTeamID: "T1",
+		},
+		{
+			name: "invalid body",
+			body: `not json`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			teamID, shared := installationTeamID([]byte(tt.body))
+			assert.Equal(t, tt.expectedTeamID, teamID)
+			assert.Equal(t, tt.expectedShared, shared)
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package handlers
+
+import (
+	"context"
+
+	"github-slack-notifier/internal/log"
+)
+
+// ensureEnterpriseWorkspace makes sure a workspace in an Enterprise Grid organization that installed the app
+// org-wide has its own installation record before a request from it is handled, since tokens are looked up by
+// team ID. Failures are logged; the request is still handled, and fails later if there's no token.
+func (sh *SlackHandler) ensureEnterpriseWorkspace(ctx context.Context, teamID, enterpriseID string) {
+	if err := sh.slackService.EnsureEnterpriseWorkspace(ctx, teamID, enterpriseID); err != nil {
+		log.Error(ctx, "Failed to add workspace covered by org-wide install",
+			"error", err,
+			"team_id", teamID,
+			"enterprise_id", enterpriseID,
+		)
+	}
+}
@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/slack-go/slack"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+)
+
+// ErrInstallationNotInWorkspace indicates a GitHub installation belongs to another Slack workspace.
+var ErrInstallationNotInWorkspace = errors.New("GitHub installation belongs to another workspace")
+
+// handleEditInstallationDefaultsAction handles the "Defaults" button next to an installation in the installations modal.
+// Pushes the editor for the settings the installation's new repositories start with. Only workspace admins can edit them.
+func (sh *SlackHandler) handleEditInstallationDefaultsAction(
+	ctx context.Context, userID, teamID, triggerID, installationIDValue string, c *gin.Context,
+) {
+	ctx = log.WithFields(ctx, log.LogFields{
+		"user_id":         userID,
+		"team_id":         teamID,
+		"installation_id": installationIDValue,
+	})
+
+	isAdmin, err := sh.slackService.IsWorkspaceAdmin(ctx, teamID, userID)
+	if err != nil || !isAdmin {
+		log.Warn(ctx, "Ignoring installation defaults request from non-admin", "error", err)
+		c.JSON(http.StatusOK, gin.H{})
+		return
+	}
+
+	installation, err := sh.workspaceInstallation(ctx, teamID, installationIDValue)
+	if err != nil {
+		log.Error(ctx, "Failed to get installation for defaults", "error", err)
+		c.JSON(http.StatusOK, gin.H{})
+		return
+	}
+
+	if _, err := sh.slackService.PushView(ctx, teamID, triggerID, sh.slackService.BuildInstallationDefaultsModal(installation)); err != nil {
+		log.Error(ctx, "Failed to push installation defaults modal", "error", err)
+	}
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+// handleSaveInstallationDefaults validates and saves an installation's defaults from the editor modal,
+// then refreshes the installations modal underneath it. The default channel must be one the bot can post in,
+// and the emoji must exist in the workspace.
+func (sh *SlackHandler) handleSaveInstallationDefaults(ctx context.Context, interaction *slack.InteractionCallback, c *gin.Context) {
+	userID := interaction.User.ID
+	teamID := interaction.Team.ID
+
+	ctx = log.WithFields(ctx, log.LogFields{
+		"user_id":         userID,
+		"team_id":         teamID,
+		"installation_id": interaction.View.PrivateMetadata,
+	})
+
+	respondWithError := func(blockID, message string) {
+		c.JSON(http.StatusOK, map[string]interface{}{
+			"response_action": "errors",
+			"errors": map[string]string{
+				blockID: message,
+			},
+		})
+	}
+
+	isAdmin, err := sh.slackService.IsWorkspaceAdmin(ctx, teamID, userID)
+	if err != nil || !isAdmin {
+		log.Warn(ctx, "Rejecting installation defaults from non-admin", "error", err)
+		respondWithError("installation_defaults_mode_input", "Only workspace admins can edit installation defaults.")
+		return
+	}
+
+	installation, err := sh.workspaceInstallation(ctx, teamID, interaction.View.PrivateMetadata)
+	if err != nil {
+		log.Error(ctx, "Failed to get installation for defaults", "error", err)
+		respondWithError("installation_defaults_mode_input", "This installation no longer exists. It may have been removed.")
+		return
+	}
+
+	values := interaction.View.State.Values
+	defaults := &models.InstallationDefaults{
+		DefaultChannel:   values["installation_defaults_channel_input"]["installation_defaults_channel_select"].SelectedChannel,
+		NotificationMode: values["installation_defaults_mode_input"]["installation_defaults_mode_select"].SelectedOption.Value,
+	}
+
+	if defaults.DefaultChannel != "" {
+		if errorMsg, err := sh.validateChannelSelection(ctx, teamID, defaults.DefaultChannel); err != nil {
+			log.Warn(ctx, "Installation default channel validation failed", "error", err, "channel_id", defaults.DefaultChannel)
+			respondWithError("installation_defaults_channel_input", errorMsg)
+			return
+		}
+	}
+
+	emoji, errorMsg := sh.parseMessageEmoji(ctx, teamID,
+		values["installation_defaults_emoji_input"]["installation_defaults_emoji"].Value)
+	if errorMsg != "" {
+		respondWithError("installation_defaults_emoji_input", errorMsg)
+		return
+	}
+	defaults.Emoji = emoji
+
+	if defaults.IsEmpty() {
+		defaults = nil
+	} else {
+		defaults.UpdatedBy = userID
+		defaults.UpdatedAt = time.Now()
+	}
+	if err := sh.firestoreService.UpdateGitHubInstallationDefaults(ctx, installation.ID, defaults); err != nil {
+		log.Error(ctx, "Failed to save installation defaults", "error", err)
+		respondWithError("installation_defaults_mode_input", "Failed to save the installation defaults. Please try again.")
+		return
+	}
+
+	log.Info(ctx, "Installation defaults saved from installations modal", "cleared", defaults == nil)
+
+	// Closing this modal returns to the installations modal, which is refreshed to show the new defaults
+	c.JSON(http.StatusOK, gin.H{})
+
+	installations, err := sh.firestoreService.GetGitHubInstallationsByWorkspace(ctx, teamID)
+	if err != nil {
+		log.Warn(ctx, "Failed to get GitHub installations to refresh modal", "error", err)
+		return
+	}
+	modalView := sh.slackService.BuildGitHubInstallationsModal(installations, sh.config.BaseURL, sh.config.GitHubAppSlug)
+	if _, err := sh.slackService.UpdateView(ctx, teamID, interaction.View.PreviousViewID, modalView); err != nil {
+		log.Warn(ctx, "Failed to refresh GitHub installations modal", "error", err)
+	}
+}
+
+// workspaceInstallation returns the GitHub installation with the given ID if it belongs to the workspace.
+func (sh *SlackHandler) workspaceInstallation(
+	ctx context.Context, teamID, installationIDValue string,
+) (*models.GitHubInstallation, error) {
+	installationID, err := strconv.ParseInt(installationIDValue, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	installation, err := sh.firestoreService.GetGitHubInstallationByID(ctx, installationID)
+	if err != nil {
+		return nil, err
+	}
+	if installation.SlackWorkspaceID != teamID {
+		return nil, ErrInstallationNotInWorkspace
+	}
+	return installation, nil
+}
+
+// parseMessageEmoji reads an emoji shown on PR messages instead of the size emoji, from a settings modal's text input.
+// The emoji may be entered with or without colons, and is returned in :name: format, or empty if none was entered.
+// Returns an error message for the input if it isn't a single emoji that exists in the workspace.
+// If the workspace's emoji can't be listed, the emoji is accepted without checking it exists.
+func (sh *SlackHandler) parseMessageEmoji(ctx context.Context, teamID, value string) (string, string) {
+	name := strings.ToLower(strings.Trim(strings.TrimSpace(value), ":"))
+	if name == "" {
+		return "", ""
+	}
+	if !emojiNameRegex.MatchString(name) {
+		return "", "Enter a single emoji name, e.g. :rocket:"
+	}
+
+	emoji := ":" + name + ":"
+	unknown, err := sh.slackService.UnknownEmojiAliases(ctx, teamID, []string{emoji})
+	if err != nil {
+		log.Warn(ctx, "Failed to validate emoji against workspace emoji", "error", err)
+		return emoji, ""
+	}
+	if len(unknown) > 0 {
+		return "", "This emoji doesn't exist in this workspace."
+	}
+	return emoji, ""
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/slack-go/slack"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/utils"
+)
+
+// maxModalErrorLength is the longest validation error shown under a modal input.
+const maxModalErrorLength = 150
+
+// handleManageMessageTemplateAction handles the "Edit message format" button from the App Home workspace settings.
+// Opens the editor for the workspace's PR message template. Only workspace admins can edit it.
+func (sh *SlackHandler) handleManageMessageTemplateAction(ctx context.Context, userID, teamID, triggerID string, c *gin.Context) {
+	ctx = log.WithFields(ctx, log.LogFields{
+		"user_id": userID,
+		"team_id": teamID,
+	})
+
+	isAdmin, err := sh.slackService.IsWorkspaceAdmin(ctx, teamID, userID)
+	if err != nil || !isAdmin {
+		log.Warn(ctx, "Ignoring message template request from non-admin", "error", err)
+		c.JSON(http.StatusOK, gin.H{})
+		return
+	}
+
+	workspace, err := sh.slackService.GetWorkspace(ctx, teamID)
+	if err != nil {
+		log.Error(ctx, "Failed to get workspace for message template", "error", err)
+		c.JSON(http.StatusOK, gin.H{})
+		return
+	}
+
+	modalView := sh.slackService.BuildMessageTemplateModal(workspace.MessageTemplate)
+	if _, err := sh.slackService.OpenView(ctx, teamID, triggerID, modalView); err != nil {
+		log.Error(ctx, "Failed to open message template modal", "error", err)
+	}
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+// handleSaveMessageTemplate validates and saves the workspace's PR message template from the editor modal.
+// The template must render a sample PR message that links to the PR. An empty template, or the default one,
+// clears the workspace's template so messages follow the default layout.
+func (sh *SlackHandler) handleSaveMessageTemplate(ctx context.Context, interaction *slack.InteractionCallback, c *gin.Context) {
+	userID := interaction.User.ID
+	teamID := interaction.Team.ID
+
+	ctx = log.WithFields(ctx, log.LogFields{
+		"user_id": userID,
+		"team_id": teamID,
+	})
+
+	respondWithError := func(message string) {
+		c.JSON(http.StatusOK, map[string]interface{}{
+			"response_action": "errors",
+			"errors": map[string]string{
+				"message_template_input": message,
+			},
+		})
+	}
+
+	isAdmin, err := sh.slackService.IsWorkspaceAdmin(ctx, teamID, userID)
+	if err != nil || !isAdmin {
+		log.Warn(ctx, "Rejecting message template from non-admin", "error", err)
+		respondWithError("Only workspace admins can edit the message format.")
+		return
+	}
+
+	messageTemplate := strings.TrimSpace(interaction.View.State.Values["message_template_input"]["message_template"].Value)
+	if messageTemplate == utils.DefaultMessageTemplate {
+		messageTemplate = ""
+	}
+	if messageTemplate != "" {
+		if _, err := utils.ParseMessageTemplate(messageTemplate); err != nil {
+			log.Warn(ctx, "Message template validation failed", "error", err)
+			respondWithError(messageTemplateError(err))
+			return
+		}
+	}
+
+	if err := sh.slackService.UpdateWorkspaceMessageTemplate(ctx, teamID, messageTemplate); err != nil {
+		respondWithError("Failed to save the message format. Please try again.")
+		return
+	}
+
+	log.Info(ctx, "Message template saved from App Home", "cleared", messageTemplate == "")
+
+	c.JSON(http.StatusOK, gin.H{
+		"response_action": "clear",
+	})
+
+	sh.refreshHomeView(ctx, userID)
+}
+
+// messageTemplateError returns the validation error shown for a message template that can't be used.
+func messageTemplateError(err error) string {
+	switch {
+	case errors.Is(err, utils.ErrMessageTemplateEmpty):
+		return "This template produces an empty message."
+	case errors.Is(err, utils.ErrMessageTemplateMissingLink):
+		return "The template must link to the PR with {{.Link}} or {{.URL}}."
+	case errors.Is(err, utils.ErrMessageTemplateTooLong):
+		return "The template is too long."
+	default:
+		// Show the template package's own error, which says where the problem is
+		if cause := errors.Unwrap(err); cause != nil {
+			err = cause
+		}
+		message, _ := utils.TruncateText("This template isn't valid: "+err.Error(), maxModalErrorLength, "…")
+		return message
+	}
+}
@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/slack-go/slack"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+)
+
+// movePRNotificationCallbackID is the callback ID of the "Move to another channel" message shortcut,
+// and of the channel picker it opens.
+const movePRNotificationCallbackID = "move_pr_notification"
+
+// handleMessageAction processes message shortcut interactions, routed by callback_id.
+func (sh *SlackHandler) handleMessageAction(ctx context.Context, interaction *slack.InteractionCallback, c *gin.Context) {
+	switch interaction.CallbackID {
+	case movePRNotificationCallbackID:
+		sh.handleMovePRNotificationShortcut(ctx, interaction, c)
+	case muteRepoCallbackID:
+		sh.handleMuteRepoShortcut(ctx, interaction, c)
+	default:
+		log.Warn(ctx, "Unknown message shortcut callback ID", "callback_id", interaction.CallbackID)
+		c.JSON(http.StatusOK, gin.H{})
+	}
+}
+
+// handleMovePRNotificationShortcut handles the "Move to another channel" message shortcut on a bot-posted PR message.
+// Opens a channel picker for the PR author or a workspace admin; anyone else gets an ephemeral explanation.
+func (sh *SlackHandler) handleMovePRNotificationShortcut(ctx context.Context, interaction *slack.InteractionCallback, c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{})
+
+	userID := interaction.User.ID
+	teamID := interaction.Team.ID
+	channelID := interaction.Channel.ID
+	messageTS := interaction.MessageTs
+	if messageTS == "" {
+		messageTS = interaction.Message.Timestamp
+	}
+
+	ctx = log.WithFields(ctx, log.LogFields{
+		"user_id":    userID,
+		"team_id":    teamID,
+		"channel":    channelID,
+		"message_ts": messageTS,
+	})
+
+	trackedMessage, errorMsg := sh.movablePRNotification(ctx, teamID, channelID, messageTS, userID)
+	if errorMsg != "" {
+		if err := sh.slackService.SendEphemeralMessage(ctx, teamID, channelID, userID, errorMsg); err != nil {
+			log.Warn(ctx, "Failed to explain why the PR notification can't be moved", "error", err)
+		}
+		return
+	}
+
+	modal := sh.slackService.BuildMovePRNotificationModal(trackedMessage)
+	modal.PrivateMetadata = channelID + "/" + messageTS // Message being moved
+	if _, err := sh.slackService.OpenView(ctx, teamID, interaction.TriggerID, modal); err != nil {
+		log.Error(ctx, "Failed to open move PR notification modal", "error", err)
+	}
+}
+
+// handleMovePRNotificationSubmission validates the channel picked to move a PR message to,
+// and enqueues a job to move it.
+func (sh *SlackHandler) handleMovePRNotificationSubmission(
+	ctx context.Context, interaction *slack.InteractionCallback, c *gin.Context,
+) {
+	userID := interaction.User.ID
+	teamID := interaction.Team.ID
+	channelID, messageTS, _ := strings.Cut(interaction.View.PrivateMetadata, "/")
+
+	ctx = log.WithFields(ctx, log.LogFields{
+		"user_id":    userID,
+		"team_id":    teamID,
+		"channel":    channelID,
+		"message_ts": messageTS,
+	})
+
+	respondWithError := func(message string) {
+		c.JSON(http.StatusOK, map[string]interface{}{
+			"response_action": "errors",
+			"errors": map[string]string{
+				"move_pr_channel_input": message,
+			},
+		})
+	}
+
+	targetChannel := interaction.View.State.Values["move_pr_channel_input"]["move_pr_channel_select"].SelectedChannel
+	if targetChannel == "" {
+		respondWithError("Please select a channel.")
+		return
+	}
+	if targetChannel == channelID {
+		respondWithError("The notification is already in this channel.")
+		return
+	}
+
+	trackedMessage, errorMsg := sh.movablePRNotification(ctx, teamID, channelID, messageTS, userID)
+	if errorMsg != "" {
+		respondWithError(errorMsg)
+		return
+	}
+
+	if errorMsg, err := sh.validateChannelSelection(ctx, teamID, targetChannel); err != nil {
+		log.Warn(ctx, "Move target channel validation failed", "error", err, "target_channel", targetChannel)
+		respondWithError(errorMsg)
+		return
+	}
+
+	jobID := uuid.New().String()
+	traceID := traceIDForNewJob(ctx)
+	moveJob := &models.MovePRNotificationJob{
+		ID:               jobID,
+		TrackedMessageID: trackedMessage.ID,
+		SlackChannel:     channelID,
+		SlackMessageTS:   messageTS,
+		SlackTeamID:      teamID,
+		TargetChannel:    targetChannel,
+		MovedBy:          userID,
+		TraceID:          traceID,
+	}
+
+	jobPayload, err := json.Marshal(moveJob)
+	if err != nil {
+		log.Error(ctx, "Failed to marshal move PR notification job", "error", err)
+		respondWithError("Failed to move the notification. Please try again.")
+		return
+	}
+
+	job := &models.Job{
+		ID:      jobID,
+		Type:    models.JobTypeMovePRNotification,
+		TraceID: traceID,
+		Payload: jobPayload,
+	}
+	if err := sh.cloudTasksService.EnqueueJob(ctx, job); err != nil {
+		log.Error(ctx, "Failed to enqueue move PR notification job", "error", err)
+		respondWithError("Failed to move the notification. Please try again.")
+		return
+	}
+
+	log.Info(ctx, "Queued PR notification move",
+		"job_id", jobID,
+		"target_channel", targetChannel,
+		"repo", trackedMessage.RepoFullName,
+		"pr_number", trackedMessage.PRNumber,
+	)
+
+	c.JSON(http.StatusOK, gin.H{
+		"response_action": "clear",
+	})
+}
+
+// movablePRNotification returns the tracked bot message the user wants to move, or an explanation for the
+// user if it can't be moved. Only the PR author and workspace admins can move a PR's notification.
+func (sh *SlackHandler) movablePRNotification(
+	ctx context.Context, teamID, channelID, messageTS, userID string,
+) (*models.TrackedMessage, string) {
+	trackedMessage, err := sh.firestoreService.GetTrackedMessageBySlackMessage(ctx, teamID, channelID, messageTS)
+	if err != nil {
+		log.Error(ctx, "Failed to look up tracked message to move", "error", err)
+		return nil, "Something went wrong looking up this message. Please try again."
+	}
+	if trackedMessage == nil || trackedMessage.MessageSource != models.MessageSourceBot || trackedMessage.DeletedByUser {
+		return nil, "Only PR notifications posted by the bot can be moved."
+	}
+
+	if trackedMessage.PRAuthorGitHubID != nil {
+		user, err := sh.firestoreService.GetUserBySlackID(ctx, userID)
+		if err != nil {
+			log.Error(ctx, "Failed to look up user to authorize moving PR notification", "error", err)
+			return nil, "Something went wrong looking up your account. Please try again."
+		}
+		if user != nil && user.GitHubUserID == *trackedMessage.PRAuthorGitHubID {
+			return trackedMessage, ""
+		}
+	}
+
+	isAdmin, err := sh.slackService.IsWorkspaceAdmin(ctx, teamID, userID)
+	if err != nil || !isAdmin {
+		log.Info(ctx, "User is not the PR author or a workspace admin, move denied", "error", err)
+		return nil, "Only the PR author or a workspace admin can move this notification."
+	}
+	return trackedMessage, ""
+}
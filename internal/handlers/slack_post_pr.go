@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/slack-go/slack"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+	"github-slack-notifier/internal/utils"
+)
+
+// postPRCallbackID is the callback ID of the "Post a PR" global shortcut, and of the form it opens.
+const postPRCallbackID = "post_pr"
+
+// handleShortcut processes global shortcut interactions, routed by callback_id.
+func (sh *SlackHandler) handleShortcut(ctx context.Context, interaction *slack.InteractionCallback, c *gin.Context) {
+	switch interaction.CallbackID {
+	case postPRCallbackID:
+		sh.handlePostPRShortcut(ctx, interaction, c)
+	default:
+		log.Warn(ctx, "Unknown global shortcut callback ID", "callback_id", interaction.CallbackID)
+		c.JSON(http.StatusOK, gin.H{})
+	}
+}
+
+// handlePostPRShortcut handles the "Post a PR" global shortcut, opening the form to pick the PR, channel and CCs.
+func (sh *SlackHandler) handlePostPRShortcut(ctx context.Context, interaction *slack.InteractionCallback, c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{})
+
+	if _, err := sh.slackService.OpenView(ctx, interaction.Team.ID, interaction.TriggerID, sh.slackService.BuildPostPRModal()); err != nil {
+		log.Error(ctx, "Failed to open post PR modal", "error", err, "user_id", interaction.User.ID)
+	}
+}
+
+// handlePostPRSubmission validates the "Post a PR" form and enqueues a job to post the PR's notification.
+// The PR must be in a repository configured for the workspace, and CC'd people must have connected GitHub,
+// since CCs are resolved from GitHub usernames like the description's CCs.
+func (sh *SlackHandler) handlePostPRSubmission(ctx context.Context, interaction *slack.InteractionCallback, c *gin.Context) {
+	userID := interaction.User.ID
+	teamID := interaction.Team.ID
+	values := interaction.View.State.Values
+
+	ctx = log.WithFields(ctx, log.LogFields{
+		"user_id": userID,
+		"team_id": teamID,
+	})
+
+	respondWithError := func(blockID, message string) {
+		c.JSON(http.StatusOK, map[string]interface{}{
+			"response_action": "errors",
+			"errors": map[string]string{
+				blockID: message,
+			},
+		})
+	}
+
+	links := utils.ExtractPRLinks(strings.TrimSpace(values["post_pr_url_input"]["post_pr_url"].Value))
+	if len(links) != 1 || links[0].IsIssue() {
+		respondWithError("post_pr_url_input", "Please enter a single GitHub pull request URL.")
+		return
+	}
+	link := links[0]
+
+	repo, err := sh.firestoreService.GetRepo(ctx, link.FullRepoName, teamID)
+	if err != nil {
+		log.Error(ctx, "Failed to get repository for post PR shortcut", "error", err, "repo", link.FullRepoName)
+		respondWithError("post_pr_url_input", "Something went wrong looking up the repository. Please try again.")
+		return
+	}
+	if repo == nil || !repo.Enabled {
+		respondWithError("post_pr_url_input", "This repository isn't set up for PR notifications in this workspace.")
+		return
+	}
+
+	channelID := values["post_pr_channel_input"]["post_pr_channel_select"].SelectedChannel
+	if channelID == "" {
+		respondWithError("post_pr_channel_input", "Please select a channel.")
+		return
+	}
+	if errorMsg, err := sh.validateChannelSelection(ctx, teamID, channelID); err != nil {
+		log.Warn(ctx, "Post PR channel validation failed", "error", err, "channel", channelID)
+		respondWithError("post_pr_channel_input", errorMsg)
+		return
+	}
+
+	var usersToCC []string
+	for _, ccUserID := range values["post_pr_cc_input"]["post_pr_cc_select"].SelectedUsers {
+		ccUser, err := sh.firestoreService.GetUserBySlackID(ctx, ccUserID)
+		if err != nil {
+			log.Error(ctx, "Failed to look up CC'd user for post PR shortcut", "error", err, "cc_user_id", ccUserID)
+			respondWithError("post_pr_cc_input", "Something went wrong looking up the people to CC. Please try again.")
+			return
+		}
+		if ccUser == nil || !ccUser.Verified || ccUser.GitHubUsername == "" {
+			respondWithError("post_pr_cc_input", "Everyone you CC must have connected their GitHub account in the PR Bot App Home.")
+			return
+		}
+		usersToCC = append(usersToCC, ccUser.GitHubUsername)
+	}
+
+	jobID := uuid.New().String()
+	traceID := traceIDForNewJob(ctx)
+	postJob := &models.PostPRJob{
+		ID:           jobID,
+		SlackTeamID:  teamID,
+		SlackChannel: channelID,
+		RepoFullName: link.FullRepoName,
+		PRNumber:     link.PRNumber,
+		UsersToCC:    usersToCC,
+		RequestedBy:  userID,
+		TraceID:      traceID,
+	}
+
+	jobPayload, err := json.Marshal(postJob)
+	if err != nil {
+		log.Error(ctx, "Failed to marshal post PR job", "error", err)
+		respondWithError("post_pr_url_input", "Failed to post the PR. Please try again.")
+		return
+	}
+
+	job := &models.Job{
+		ID:      jobID,
+		Type:    models.JobTypePostPR,
+		TraceID: traceID,
+		Payload: jobPayload,
+	}
+	if err := sh.cloudTasksService.EnqueueJob(ctx, job); err != nil {
+		log.Error(ctx, "Failed to enqueue post PR job", "error", err)
+		respondWithError("post_pr_url_input", "Failed to post the PR. Please try again.")
+		return
+	}
+
+	log.Info(ctx, "Queued on-demand PR notification",
+		"job_id", jobID,
+		"repo", link.FullRepoName,
+		"pr_number", link.PRNumber,
+		"channel", channelID,
+	)
+
+	c.JSON(http.StatusOK, gin.H{
+		"response_action": "clear",
+	})
+}
@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/services"
+	"github-slack-notifier/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/slack-go/slack"
+)
+
+// /pr-bot subcommands.
+const (
+	prBotSubcommandLink       = "link"
+	prBotSubcommandStatus     = "status"
+	prBotSubcommandSetChannel = "set-channel"
+	prBotSubcommandMute       = "mute"
+	prBotSubcommandHelp       = "help"
+)
+
+// prBotUsage lists the /pr-bot subcommands.
+const prBotUsage = "*`/pr-bot` commands*\n" +
+	"• `/pr-bot link` - Connect your GitHub account\n" +
+	"• `/pr-bot status <pr-url>` - Show where a PR is tracked in this workspace\n" +
+	"• `/pr-bot set-channel [#channel]` - Post your PRs to a single channel (defaults to this channel)\n" +
+	"• `/pr-bot mute [off]` - Stop (or resume) posting your PRs"
+
+var (
+	// ErrUnknownPRBotSubcommand indicates an unrecognized /pr-bot subcommand.
+	ErrUnknownPRBotSubcommand = errors.New("unknown subcommand")
+	// ErrInvalidPRBotArgument indicates an invalid argument to a /pr-bot subcommand.
+	ErrInvalidPRBotArgument = errors.New("invalid argument")
+)
+
+// prBotCommand is a parsed /pr-bot invocation.
+type prBotCommand struct {
+	Subcommand string
+	PRLink     *utils.PRLink // For status
+	Channel    string        // For set-channel: a channel ID
+	Unmute     bool          // For mute off
+}
+
+// parsePRBotCommand parses `/pr-bot` text into a subcommand and its arguments.
+// An empty command shows the help text. set-channel without a channel uses defaultChannel.
+func parsePRBotCommand(text, defaultChannel string) (*prBotCommand, error) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return &prBotCommand{Subcommand: prBotSubcommandHelp}, nil
+	}
+
+	cmd := &prBotCommand{Subcommand: strings.ToLower(fields[0])}
+	args := fields[1:]
+
+	switch cmd.Subcommand {
+	case prBotSubcommandLink, prBotSubcommandHelp:
+		if len(args) > 0 {
+			return nil, fmt.Errorf("%w: %s takes no arguments", ErrInvalidPRBotArgument, cmd.Subcommand)
+		}
+	case prBotSubcommandStatus:
+		links := utils.ExtractPRLinks(strings.Join(args, " "))
+		if len(args) != 1 || len(links) != 1 || links[0].IsIssue() {
+			return nil, fmt.Errorf("%w: status needs a single GitHub PR URL", ErrInvalidPRBotArgument)
+		}
+		cmd.PRLink = &links[0]
+	case prBotSubcommandSetChannel:
+		switch len(args) {
+		case 0:
+			cmd.Channel = defaultChannel
+		case 1:
+			matches := escapedChannelRegex.FindStringSubmatch(args[0])
+			if matches == nil {
+				return nil, fmt.Errorf("%w: pick the channel from the autocomplete list, e.g. #eng-prs", ErrInvalidPRBotArgument)
+			}
+			cmd.Channel = matches[1]
+		default:
+			return nil, fmt.Errorf("%w: set-channel takes at most one channel", ErrInvalidPRBotArgument)
+		}
+	case prBotSubcommandMute:
+		switch {
+		case len(args) == 0:
+		case len(args) == 1 && strings.EqualFold(args[0], "off"):
+			cmd.Unmute = true
+		default:
+			return nil, fmt.Errorf("%w: use `mute` or `mute off`", ErrInvalidPRBotArgument)
+		}
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownPRBotSubcommand, fields[0])
+	}
+
+	return cmd, nil
+}
+
+// handlePRBotCommand handles `/pr-bot <subcommand>`, letting users manage settings without opening the App Home.
+// All responses are ephemeral.
+func (sh *SlackHandler) handlePRBotCommand(ctx context.Context, slashCmd *slack.SlashCommand, c *gin.Context) {
+	cmd, err := parsePRBotCommand(slashCmd.Text, slashCmd.ChannelID)
+	if err != nil {
+		c.JSON(http.StatusOK, ephemeralResponse(fmt.Sprintf("❌ %s\n\n%s", err.Error(), prBotUsage)))
+		return
+	}
+
+	ctx = log.WithFields(ctx, log.LogFields{"subcommand": cmd.Subcommand})
+
+	var text string
+	switch cmd.Subcommand {
+	case prBotSubcommandLink:
+		text = sh.prBotLink(ctx, slashCmd)
+	case prBotSubcommandStatus:
+		text = sh.prBotStatus(ctx, slashCmd.TeamID, cmd.PRLink)
+	case prBotSubcommandSetChannel:
+		text = sh.prBotSetChannel(ctx, slashCmd.UserID, slashCmd.TeamID, cmd.Channel)
+	case prBotSubcommandMute:
+		text = sh.prBotMute(ctx, slashCmd.UserID, slashCmd.TeamID, cmd.Unmute)
+	default:
+		text = prBotUsage
+	}
+
+	c.JSON(http.StatusOK, ephemeralResponse(text))
+}
+
+// prBotLink creates an OAuth state for linking the user's GitHub account and returns the link.
+// The state records the channel, so a confirmation is posted there once linking completes.
+func (sh *SlackHandler) prBotLink(ctx context.Context, slashCmd *slack.SlashCommand) string {
+	state, err := sh.githubAuthService.CreateOAuthState(ctx, slashCmd.UserID, slashCmd.TeamID, slashCmd.ChannelID)
+	if err != nil {
+		log.Error(ctx, "Failed to create OAuth state for /pr-bot link", "error", err)
+		return withTraceReference(ctx, "❌ Failed to generate a GitHub link. Please try again.")
+	}
+
+	oauthURL := fmt.Sprintf("%s/auth/github/link?state=%s", sh.config.BaseURL, state.ID)
+	return fmt.Sprintf("🔗 <%s|Connect your GitHub account>\n_This link expires in 15 minutes and can only be used once._", oauthURL)
+}
+
+// prBotStatus describes where a PR is tracked in the workspace, with links to each message.
+func (sh *SlackHandler) prBotStatus(ctx context.Context, teamID string, link *utils.PRLink) string {
+	prName := fmt.Sprintf("%s#%d", link.FullRepoName, link.PRNumber)
+
+	messages, err := sh.firestoreService.GetTrackedMessages(ctx, services.TrackedMessageQuery{
+		RepoFullName: link.FullRepoName,
+		PRNumber:     link.PRNumber,
+		SlackTeamID:  teamID,
+	})
+	if err != nil {
+		log.Error(ctx, "Failed to get tracked messages for /pr-bot status", "error", err, "pr", prName)
+		return withTraceReference(ctx, "❌ Failed to look up the PR. Please try again.")
+	}
+
+	var lines []string
+	for _, msg := range messages {
+		if msg.DeletedByUser {
+			continue
+		}
+		line := fmt.Sprintf("• <#%s> (%s)", msg.SlackChannel, msg.MessageSource)
+		if permalink, err := sh.slackService.GetPermalink(ctx, teamID, msg.SlackChannel, msg.SlackMessageTS); err == nil {
+			line += fmt.Sprintf(" - <%s|view message>", permalink)
+		}
+		lines = append(lines, line)
+	}
+
+	if len(lines) == 0 {
+		return fmt.Sprintf("<%s|%s> isn't tracked in this workspace.", link.URL, prName)
+	}
+	return fmt.Sprintf("<%s|%s> is tracked in %d message(s):\n%s", link.URL, prName, len(lines), strings.Join(lines, "\n"))
+}
+
+// prBotSetChannel makes a channel the user's only default notification channel after checking the bot can post there.
+func (sh *SlackHandler) prBotSetChannel(ctx context.Context, userID, teamID, channelID string) string {
+	if errorMsg, err := sh.validateChannelSelection(ctx, teamID, channelID); err != nil {
+		return "❌ " + errorMsg
+	}
+
+	user, err := sh.createOrGetUserWithDisplayName(ctx, userID, teamID)
+	if err != nil {
+		log.Error(ctx, "Failed to get user for /pr-bot set-channel", "error", err)
+		return withTraceReference(ctx, "❌ Failed to update your channel. Please try again.")
+	}
+
+	user.SetDefaultChannels([]string{channelID})
+	if err := sh.firestoreService.CreateOrUpdateUser(ctx, user); err != nil {
+		log.Error(ctx, "Failed to update user channel", "error", err)
+		return withTraceReference(ctx, "❌ Failed to update your channel. Please try again.")
+	}
+
+	log.Info(ctx, "Updated default channel via /pr-bot", "channel_id", channelID)
+	sh.refreshHomeView(ctx, userID)
+	return fmt.Sprintf("✅ Your PRs will be posted to <#%s> by default.", channelID)
+}
+
+// prBotMute turns posting of the user's PRs off, or back on with `mute off`.
+func (sh *SlackHandler) prBotMute(ctx context.Context, userID, teamID string, unmute bool) string {
+	user, err := sh.createOrGetUserWithDisplayName(ctx, userID, teamID)
+	if err != nil {
+		log.Error(ctx, "Failed to get user for /pr-bot mute", "error", err)
+		return withTraceReference(ctx, "❌ Failed to update your notifications. Please try again.")
+	}
+
+	user.NotificationsEnabled = unmute
+	if err := sh.firestoreService.CreateOrUpdateUser(ctx, user); err != nil {
+		log.Error(ctx, "Failed to update user notifications", "error", err)
+		return withTraceReference(ctx, "❌ Failed to update your notifications. Please try again.")
+	}
+
+	log.Info(ctx, "Updated notifications via /pr-bot", "notifications_enabled", user.NotificationsEnabled)
+	sh.refreshHomeView(ctx, userID)
+	if unmute {
+		return "🔔 Your PRs will be posted to Slack again."
+	}
+	return "🔕 Your PRs won't be posted to Slack. Use `/pr-bot mute off` to resume."
+}
@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/slack-go/slack"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+	"github-slack-notifier/internal/prsize"
+)
+
+// handleEditChannelPRSizeAction handles the "Edit PR size emoji" button in a channel's tracking settings.
+// Pushes the editor for the PR size emoji used on PRs posted to the channel.
+func (sh *SlackHandler) handleEditChannelPRSizeAction(ctx context.Context, teamID, triggerID, channelID string, c *gin.Context) {
+	ctx = log.WithFields(ctx, log.LogFields{
+		"team_id":    teamID,
+		"channel_id": channelID,
+	})
+
+	channelConfig, err := sh.firestoreService.GetChannelConfig(ctx, teamID, channelID)
+	if err != nil {
+		log.Error(ctx, "Failed to get channel config for PR size emoji", "error", err)
+		c.JSON(http.StatusOK, gin.H{})
+		return
+	}
+
+	modalView := sh.slackService.BuildChannelPRSizeConfigModal(channelID, channelConfig.GetPRSizeConfig())
+	if _, err := sh.slackService.PushView(ctx, teamID, triggerID, modalView); err != nil {
+		log.Error(ctx, "Failed to push channel PR size emoji modal", "error", err)
+	}
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+// handleSaveChannelPRSize validates and saves a channel's PR size emoji from the editor modal.
+// Clearing the configuration makes the channel use the workspace's emoji again.
+func (sh *SlackHandler) handleSaveChannelPRSize(ctx context.Context, interaction *slack.InteractionCallback, c *gin.Context) {
+	userID := interaction.User.ID
+	teamID := interaction.Team.ID
+	channelID := interaction.View.PrivateMetadata // Channel ID stored in private metadata
+
+	ctx = log.WithFields(ctx, log.LogFields{
+		"user_id":    userID,
+		"team_id":    teamID,
+		"channel_id": channelID,
+	})
+
+	prSizeConfig, ok := sh.validatePRSizeSubmission(ctx, interaction, c)
+	if !ok {
+		return
+	}
+
+	channelConfig, err := sh.firestoreService.GetChannelConfig(ctx, teamID, channelID)
+	if err != nil {
+		log.Error(ctx, "Failed to get channel config for PR size emoji", "error", err)
+		respondWithPRSizeError(c, "Failed to save configuration. Please try again.")
+		return
+	}
+	if channelConfig == nil {
+		channelName, err := sh.slackService.GetChannelName(ctx, teamID, channelID)
+		if err != nil {
+			log.Warn(ctx, "Failed to get channel name", "error", err)
+			channelName = channelID // Fallback to ID
+		}
+		channelConfig = &models.ChannelConfig{
+			ID:                    teamID + "#" + channelID,
+			SlackTeamID:           teamID,
+			SlackChannelID:        channelID,
+			SlackChannelName:      channelName,
+			ManualTrackingEnabled: true,
+		}
+	}
+	channelConfig.PRSizeConfig = prSizeConfig
+	channelConfig.ConfiguredBy = userID
+
+	if err := sh.firestoreService.SaveChannelConfig(ctx, channelConfig); err != nil {
+		log.Error(ctx, "Failed to save channel PR size emoji", "error", err)
+		respondWithPRSizeError(c, "Failed to save configuration. Please try again.")
+		return
+	}
+
+	log.Info(ctx, "Channel PR size emoji saved", "cleared", prSizeConfig == nil)
+
+	// Closing this modal returns to the channel's tracking settings, whose summary shows the emoji when reopened
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+// handleManageWorkspacePRSizeAction handles the "Edit PR size emoji" button from the App Home workspace settings.
+// Opens the editor for the PR size emoji used across the workspace. Only workspace admins can edit it.
+func (sh *SlackHandler) handleManageWorkspacePRSizeAction(ctx context.Context, userID, teamID, triggerID string, c *gin.Context) {
+	ctx = log.WithFields(ctx, log.LogFields{
+		"user_id": userID,
+		"team_id": teamID,
+	})
+
+	isAdmin, err := sh.slackService.IsWorkspaceAdmin(ctx, teamID, userID)
+	if err != nil || !isAdmin {
+		log.Warn(ctx, "Ignoring workspace PR size emoji request from non-admin", "error", err)
+		c.JSON(http.StatusOK, gin.H{})
+		return
+	}
+
+	workspace, err := sh.slackService.GetWorkspace(ctx, teamID)
+	if err != nil {
+		log.Error(ctx, "Failed to get workspace for PR size emoji", "error", err)
+		c.JSON(http.StatusOK, gin.H{})
+		return
+	}
+
+	modalView := sh.slackService.BuildWorkspacePRSizeConfigModal(workspace.PRSizeConfig)
+	if _, err := sh.slackService.OpenView(ctx, teamID, triggerID, modalView); err != nil {
+		log.Error(ctx, "Failed to open workspace PR size emoji modal", "error", err)
+	}
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+// handleSaveWorkspacePRSize validates and saves the workspace's PR size emoji from the editor modal.
+// Clearing the configuration makes PRs without a user or channel configuration use the default animal emoji.
+func (sh *SlackHandler) handleSaveWorkspacePRSize(ctx context.Context, interaction *slack.InteractionCallback, c *gin.Context) {
+	userID := interaction.User.ID
+	teamID := interaction.Team.ID
+
+	ctx = log.WithFields(ctx, log.LogFields{
+		"user_id": userID,
+		"team_id": teamID,
+	})
+
+	isAdmin, err := sh.slackService.IsWorkspaceAdmin(ctx, teamID, userID)
+	if err != nil || !isAdmin {
+		log.Warn(ctx, "Rejecting workspace PR size emoji from non-admin", "error", err)
+		respondWithPRSizeError(c, "Only workspace admins can edit the workspace's PR size emoji.")
+		return
+	}
+
+	prSizeConfig, ok := sh.validatePRSizeSubmission(ctx, interaction, c)
+	if !ok {
+		return
+	}
+
+	if err := sh.slackService.UpdateWorkspacePRSizeConfig(ctx, teamID, prSizeConfig); err != nil {
+		respondWithPRSizeError(c, "Failed to save configuration. Please try again.")
+		return
+	}
+
+	log.Info(ctx, "Workspace PR size emoji saved from App Home", "cleared", prSizeConfig == nil)
+
+	c.JSON(http.StatusOK, gin.H{
+		"response_action": "clear",
+	})
+
+	sh.refreshHomeView(ctx, userID)
+}
+
+// validatePRSizeSubmission parses a channel or workspace PR size emoji modal and checks its emoji exist in the workspace,
+// responding with the validation error if not. Returns nil for an empty configuration, which inherits the next level's.
+func (sh *SlackHandler) validatePRSizeSubmission(
+	ctx context.Context, interaction *slack.InteractionCallback, c *gin.Context,
+) (*models.PRSizeConfiguration, bool) {
+	configText := extractTextInput(interaction, "pr_size_config_input", "pr_size_config_text")
+	prSizeConfig, errors := sh.parsePRSizeConfig(configText)
+	if len(errors) > 0 {
+		log.Warn(ctx, "Invalid PR size configuration submitted", "errors", errors)
+		c.JSON(http.StatusOK, map[string]interface{}{
+			"response_action": "errors",
+			"errors":          errors,
+		})
+		return nil, false
+	}
+
+	// Reject aliases the workspace doesn't have, rather than posting them as literal :alias: text
+	if unknown := sh.unknownPRSizeEmojis(ctx, interaction.Team.ID, prSizeConfig); len(unknown) > 0 {
+		log.Warn(ctx, "PR size configuration uses emoji not in workspace", "unknown_emoji", unknown)
+		respondWithPRSizeError(c, "Unknown emoji in this workspace: "+strings.Join(unknown, ", "))
+		return nil, false
+	}
+
+	if !prsize.IsSet(prSizeConfig) {
+		return nil, true
+	}
+	return prSizeConfig, true
+}
+
+// respondWithPRSizeError shows an error under a PR size emoji modal's configuration input.
+func respondWithPRSizeError(c *gin.Context, message string) {
+	c.JSON(http.StatusOK, map[string]interface{}{
+		"response_action": "errors",
+		"errors": map[string]string{
+			"pr_size_config_input": message,
+		},
+	})
+}
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v74/github"
+	"github.com/google/uuid"
+	"github.com/slack-go/slack/slackevents"
+
+	"github-slack-notifier/internal/config"
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+	"github-slack-notifier/internal/services"
+)
+
+// reviewingGitHubReaction is the reaction left on a PR on GitHub for the "reviewing" reaction action.
+const reviewingGitHubReaction = "eyes"
+
+// baseReactionName returns a reaction's emoji name without its skin tone, e.g. "+1" for "+1::skin-tone-2".
+func baseReactionName(reaction string) string {
+	name, _, _ := strings.Cut(reaction, "::")
+	return name
+}
+
+// enqueueReactionAction queues the GitHub action REACTION_ACTIONS maps a reaction to, when it's added to a tracked
+// PR message by someone other than the bot. The bot's own status reactions can use the same emoji.
+func (sh *SlackHandler) enqueueReactionAction(ctx context.Context, event *slackevents.ReactionAddedEvent, teamID, action string) {
+	if event.Item.Type != "message" {
+		return
+	}
+	ctx = log.WithFields(ctx, log.LogFields{
+		"user":       event.User,
+		"channel":    event.Item.Channel,
+		"message_ts": event.Item.Timestamp,
+		"reaction":   event.Reaction,
+		"action":     action,
+	})
+
+	workspace, err := sh.slackService.GetWorkspace(ctx, teamID)
+	if err != nil {
+		log.Error(ctx, "Failed to get workspace for reaction action", "error", err)
+		return
+	}
+	if workspace != nil && event.User == workspace.BotUserID {
+		return
+	}
+
+	trackedMessage, err := sh.firestoreService.GetTrackedMessageBySlackMessage(ctx, teamID, event.Item.Channel, event.Item.Timestamp)
+	if err != nil {
+		log.Error(ctx, "Failed to lookup tracked message for reaction action", "error", err)
+		return
+	}
+	if trackedMessage == nil || trackedMessage.DeletedByUser || trackedMessage.IsIssue() {
+		log.Debug(ctx, "Reaction action not on a tracked PR message, ignoring")
+		return
+	}
+
+	actionJob := &models.ReactionActionJob{
+		ID:             uuid.New().String(),
+		RepoFullName:   trackedMessage.RepoFullName,
+		PRNumber:       trackedMessage.PRNumber,
+		SlackTeamID:    teamID,
+		SlackChannel:   event.Item.Channel,
+		SlackMessageTS: event.Item.Timestamp,
+		SlackUserID:    event.User,
+		Action:         action,
+		TraceID:        traceIDForNewJob(ctx),
+	}
+	jobPayload, err := json.Marshal(actionJob)
+	if err != nil {
+		log.Error(ctx, "Failed to marshal reaction action job", "error", err)
+		return
+	}
+
+	job := &models.Job{
+		ID:      actionJob.ID,
+		Type:    models.JobTypeReactionAction,
+		TraceID: actionJob.TraceID,
+		Payload: jobPayload,
+	}
+	if err := sh.cloudTasksService.EnqueueJob(ctx, job); err != nil {
+		log.Error(ctx, "Failed to enqueue reaction action job", "error", err)
+		return
+	}
+
+	log.Info(ctx, "Reaction action queued", "job_id", job.ID)
+}
+
+// ProcessReactionActionJob takes a reaction's GitHub action on a PR as the user who reacted, with the GitHub
+// user token they granted when linking their account, and tells them how it went by DM. Failed actions aren't
+// retried, so a PR is never approved long after its reviewer was told it wasn't.
+func (sh *SlackHandler) ProcessReactionActionJob(ctx context.Context, job *models.Job) error {
+	var actionJob models.ReactionActionJob
+	if err := json.Unmarshal(job.Payload, &actionJob); err != nil {
+		return fmt.Errorf("failed to unmarshal reaction action job: %w", models.Permanent(err))
+	}
+	if err := actionJob.Validate(); err != nil {
+		return fmt.Errorf("invalid reaction action job: %w", models.Permanent(err))
+	}
+
+	ctx = log.WithFields(ctx, log.LogFields{
+		"repo":          actionJob.RepoFullName,
+		"pr_number":     actionJob.PRNumber,
+		"slack_team_id": actionJob.SlackTeamID,
+		"user_id":       actionJob.SlackUserID,
+		"action":        actionJob.Action,
+	})
+	prName := fmt.Sprintf("%s#%d", actionJob.RepoFullName, actionJob.PRNumber)
+
+	client, err := sh.githubAuthService.UserClient(ctx, actionJob.SlackTeamID, actionJob.SlackUserID)
+	if errors.Is(err, services.ErrGitHubUserTokenNotFound) || errors.Is(err, services.ErrGitHubUserTokenExpired) {
+		log.Info(ctx, "No usable GitHub user token for reaction action", "error", err)
+		sh.sendReactionActionResult(ctx, &actionJob, fmt.Sprintf(
+			"I couldn't act on %s for you because I don't have access to your GitHub account. "+
+				"Connect your GitHub account again from my App Home, then react again.", prName))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	owner, repo, _ := strings.Cut(actionJob.RepoFullName, "/")
+	var text string
+	switch actionJob.Action {
+	case config.ReactionActionApprove:
+		_, _, err = client.PullRequests.CreateReview(ctx, owner, repo, actionJob.PRNumber,
+			&github.PullRequestReviewRequest{Event: github.Ptr("APPROVE")})
+		text = fmt.Sprintf("Approved %s on GitHub.", prName)
+	case config.ReactionActionReviewing:
+		_, _, err = client.Reactions.CreateIssueReaction(ctx, owner, repo, actionJob.PRNumber, reviewingGitHubReaction)
+		text = fmt.Sprintf("Reacted with :%s: on %s on GitHub to show you're reviewing it.", reviewingGitHubReaction, prName)
+	default:
+		log.Warn(ctx, "Ignoring unsupported reaction action")
+		return nil
+	}
+
+	if err != nil {
+		log.Warn(ctx, "Failed to take reaction action on GitHub", "error", err)
+		reason := "GitHub rejected the request"
+		var ghErr *github.ErrorResponse
+		if errors.As(err, &ghErr) && ghErr.Message != "" {
+			reason = ghErr.Message
+		}
+		text = fmt.Sprintf("I couldn't %s %s on GitHub for you: %s.", reactionActionVerb(actionJob.Action), prName, reason)
+	} else {
+		log.Info(ctx, "Took reaction action on GitHub")
+	}
+
+	sh.sendReactionActionResult(ctx, &actionJob, text)
+	return nil
+}
+
+// reactionActionVerb describes a reaction action for messages saying it failed.
+func reactionActionVerb(action string) string {
+	if action == config.ReactionActionApprove {
+		return "approve"
+	}
+	return "mark you as reviewing"
+}
+
+// sendReactionActionResult DMs the user who reacted how their reaction action went.
+func (sh *SlackHandler) sendReactionActionResult(ctx context.Context, actionJob *models.ReactionActionJob, text string) {
+	if _, err := sh.slackService.PostMessage(ctx, actionJob.SlackTeamID, actionJob.SlackUserID, text); err != nil {
+		log.Warn(ctx, "Failed to send reaction action result", "error", err)
+	}
+}
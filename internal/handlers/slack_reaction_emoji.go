@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/slack-go/slack"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+)
+
+// emojiNameRegex matches a Slack emoji name, without colons.
+var emojiNameRegex = regexp.MustCompile(`^[a-z0-9_+'-]+$`)
+
+// handleManageReactionEmojiAction handles the "Edit reaction emoji" button from the App Home workspace settings.
+// Opens the editor for the workspace's reaction emoji. Only workspace admins can edit them.
+func (sh *SlackHandler) handleManageReactionEmojiAction(ctx context.Context, userID, teamID, triggerID string, c *gin.Context) {
+	ctx = log.WithFields(ctx, log.LogFields{
+		"user_id": userID,
+		"team_id": teamID,
+	})
+
+	isAdmin, err := sh.slackService.IsWorkspaceAdmin(ctx, teamID, userID)
+	if err != nil || !isAdmin {
+		log.Warn(ctx, "Ignoring reaction emoji request from non-admin", "error", err)
+		c.JSON(http.StatusOK, gin.H{})
+		return
+	}
+
+	workspace, err := sh.slackService.GetWorkspace(ctx, teamID)
+	if err != nil {
+		log.Error(ctx, "Failed to get workspace for reaction emoji", "error", err)
+		c.JSON(http.StatusOK, gin.H{})
+		return
+	}
+
+	if _, err := sh.slackService.OpenView(ctx, teamID, triggerID, sh.slackService.BuildReactionEmojiModal(workspace.ReactionEmoji)); err != nil {
+		log.Error(ctx, "Failed to open reaction emoji modal", "error", err)
+	}
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+// handleSaveReactionEmoji validates and saves the workspace's reaction emoji from the editor modal.
+// Emoji may be entered with or without colons, and must exist in the workspace.
+func (sh *SlackHandler) handleSaveReactionEmoji(ctx context.Context, interaction *slack.InteractionCallback, c *gin.Context) {
+	userID := interaction.User.ID
+	teamID := interaction.Team.ID
+
+	ctx = log.WithFields(ctx, log.LogFields{
+		"user_id": userID,
+		"team_id": teamID,
+	})
+
+	isAdmin, err := sh.slackService.IsWorkspaceAdmin(ctx, teamID, userID)
+	if err != nil || !isAdmin {
+		log.Warn(ctx, "Rejecting reaction emoji from non-admin", "error", err)
+		c.JSON(http.StatusOK, map[string]interface{}{
+			"response_action": "errors",
+			"errors": map[string]string{
+				"reaction_emoji_approved_input": "Only workspace admins can edit reaction emoji.",
+			},
+		})
+		return
+	}
+
+	emoji, validationErrors := parseReactionEmoji(interaction.View.State.Values)
+	if len(validationErrors) == 0 {
+		validationErrors = sh.unknownReactionEmoji(ctx, teamID, emoji)
+	}
+	if len(validationErrors) > 0 {
+		c.JSON(http.StatusOK, map[string]interface{}{
+			"response_action": "errors",
+			"errors":          validationErrors,
+		})
+		return
+	}
+
+	var overrides *models.WorkspaceEmoji
+	if !emoji.IsEmpty() {
+		emoji.UpdatedBy = userID
+		emoji.UpdatedAt = time.Now()
+		overrides = emoji
+	}
+	if err := sh.slackService.UpdateWorkspaceReactionEmoji(ctx, teamID, overrides); err != nil {
+		c.JSON(http.StatusOK, map[string]interface{}{
+			"response_action": "errors",
+			"errors": map[string]string{
+				"reaction_emoji_approved_input": "Failed to save the reaction emoji. Please try again.",
+			},
+		})
+		return
+	}
+
+	log.Info(ctx, "Reaction emoji saved from App Home", "cleared", overrides == nil)
+
+	c.JSON(http.StatusOK, gin.H{
+		"response_action": "clear",
+	})
+
+	sh.refreshHomeView(ctx, userID)
+}
+
+// parseReactionEmoji reads the reaction emoji modal's inputs, stripping surrounding colons.
+// Returns the emoji and validation errors keyed by block ID.
+func parseReactionEmoji(values map[string]map[string]slack.BlockAction) (*models.WorkspaceEmoji, map[string]string) {
+	emoji := &models.WorkspaceEmoji{}
+	validationErrors := make(map[string]string)
+	for _, state := range models.WorkspaceEmojiStates {
+		blockID := "reaction_emoji_" + state + "_input"
+		name := strings.Trim(strings.TrimSpace(values[blockID]["reaction_emoji_"+state].Value), ":")
+		if name == "" {
+			continue
+		}
+		name = strings.ToLower(name)
+		if !emojiNameRegex.MatchString(name) {
+			validationErrors[blockID] = "Enter a single emoji name, e.g. :white_check_mark:"
+			continue
+		}
+		*emoji.Field(state) = name
+	}
+	return emoji, validationErrors
+}
+
+// unknownReactionEmoji returns validation errors for reaction emoji that don't exist in the workspace.
+// If the workspace's emoji can't be listed, validation is skipped so the emoji can still be saved.
+func (sh *SlackHandler) unknownReactionEmoji(ctx context.Context, teamID string, emoji *models.WorkspaceEmoji) map[string]string {
+	validationErrors := make(map[string]string)
+	for _, state := range models.WorkspaceEmojiStates {
+		name := *emoji.Field(state)
+		if name == "" {
+			continue
+		}
+		unknown, err := sh.slackService.UnknownEmojiAliases(ctx, teamID, []string{":" + name + ":"})
+		if err != nil {
+			log.Warn(ctx, "Failed to validate reaction emoji against workspace emoji", "error", err)
+			return nil
+		}
+		if len(unknown) > 0 {
+			validationErrors["reaction_emoji_"+state+"_input"] = "This emoji doesn't exist in this workspace."
+		}
+	}
+	return validationErrors
+}
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/slack-go/slack"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+)
+
+// muteRepoCallbackID is the callback ID of the "Mute this repository" message shortcut, and of the form it opens.
+const muteRepoCallbackID = "mute_repo"
+
+// handleMuteRepoShortcut handles the "Mute this repository" message shortcut on a PR message, opening the form to
+// mute the PR's repository. Anyone can mute a repository for themselves.
+func (sh *SlackHandler) handleMuteRepoShortcut(ctx context.Context, interaction *slack.InteractionCallback, c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{})
+
+	userID := interaction.User.ID
+	teamID := interaction.Team.ID
+	channelID := interaction.Channel.ID
+	messageTS := interaction.MessageTs
+	if messageTS == "" {
+		messageTS = interaction.Message.Timestamp
+	}
+
+	ctx = log.WithFields(ctx, log.LogFields{
+		"user_id":    userID,
+		"team_id":    teamID,
+		"channel":    channelID,
+		"message_ts": messageTS,
+	})
+
+	errorMsg := ""
+	trackedMessage, err := sh.firestoreService.GetTrackedMessageBySlackMessage(ctx, teamID, channelID, messageTS)
+	if err != nil {
+		log.Error(ctx, "Failed to look up tracked message to mute its repository", "error", err)
+		errorMsg = "Something went wrong looking up this message. Please try again."
+	} else if trackedMessage == nil || trackedMessage.DeletedByUser {
+		errorMsg = "Repositories can only be muted from PR notifications I'm tracking, or from my App Home."
+	}
+	if errorMsg != "" {
+		if err := sh.slackService.SendEphemeralMessage(ctx, teamID, channelID, userID, errorMsg); err != nil {
+			log.Warn(ctx, "Failed to explain why the repository can't be muted", "error", err)
+		}
+		return
+	}
+
+	modal := sh.slackService.BuildMuteRepoModal(trackedMessage.RepoFullName)
+	if _, err := sh.slackService.OpenView(ctx, teamID, interaction.TriggerID, modal); err != nil {
+		log.Error(ctx, "Failed to open mute repository modal", "error", err)
+	}
+}
+
+// handleOpenMuteRepoAction opens the form to mute a repository from App Home, asking for its name.
+func (sh *SlackHandler) handleOpenMuteRepoAction(ctx context.Context, teamID, triggerID string, c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{})
+
+	modal := sh.slackService.BuildMuteRepoModal("")
+	if _, err := sh.slackService.OpenView(ctx, teamID, triggerID, modal); err != nil {
+		log.Error(ctx, "Failed to open mute repository modal", "error", err)
+	}
+}
+
+// handleMuteRepoSubmission mutes a repository for the user for the chosen duration, replacing any mute of it.
+// The repository comes from the PR message the form was opened from, or the form itself when opened from App Home.
+func (sh *SlackHandler) handleMuteRepoSubmission(ctx context.Context, interaction *slack.InteractionCallback, c *gin.Context) {
+	userID := interaction.User.ID
+	teamID := interaction.Team.ID
+	values := interaction.View.State.Values
+
+	respondWithError := func(blockID, message string) {
+		c.JSON(http.StatusOK, map[string]interface{}{
+			"response_action": "errors",
+			"errors": map[string]string{
+				blockID: message,
+			},
+		})
+	}
+
+	repoFullName := interaction.View.PrivateMetadata
+	if repoFullName == "" {
+		repoFullName = strings.TrimSpace(values["mute_repo_name_input"]["mute_repo_name"].Value)
+		owner, repo, _ := strings.Cut(repoFullName, "/")
+		if owner == "" || repo == "" || strings.Contains(repo, "/") || strings.ContainsAny(repoFullName, " \t") {
+			respondWithError("mute_repo_name_input", "Enter a repository as owner/repo.")
+			return
+		}
+	}
+
+	duration, err := time.ParseDuration(values["mute_repo_duration_input"]["mute_repo_duration"].SelectedOption.Value)
+	if err != nil || !slices.Contains(models.RepoMuteDurations, duration) {
+		respondWithError("mute_repo_duration_input", "Choose how long to mute the repository for.")
+		return
+	}
+
+	ctx = log.WithFields(ctx, log.LogFields{
+		"user_id": userID,
+		"repo":    repoFullName,
+	})
+
+	user, err := sh.createOrGetUserWithDisplayName(ctx, userID, teamID)
+	if err != nil {
+		log.Error(ctx, "Failed to get user to mute repository", "error", err)
+		respondWithError("mute_repo_duration_input", "Failed to mute the repository. Please try again.")
+		return
+	}
+
+	now := time.Now()
+	user.MuteRepo(repoFullName, now.Add(duration), now)
+	if err := sh.firestoreService.CreateOrUpdateUser(ctx, user); err != nil {
+		log.Error(ctx, "Failed to save repository mute", "error", err)
+		respondWithError("mute_repo_duration_input", "Failed to mute the repository. Please try again.")
+		return
+	}
+
+	log.Info(ctx, "User muted repository", "muted_for", duration)
+	sh.refreshHomeView(ctx, userID)
+	c.JSON(http.StatusOK, gin.H{"response_action": "clear"})
+}
+
+// handleUnmuteRepoAction handles picking a repository to unmute in App Home.
+func (sh *SlackHandler) handleUnmuteRepoAction(ctx context.Context, userID, repoFullName string, c *gin.Context) {
+	sh.handleUserSettingToggle(ctx, userID, c, "repository mute", func(user *models.User) {
+		user.UnmuteRepo(repoFullName, time.Now())
+	}, func(user *models.User) map[string]interface{} {
+		return map[string]interface{}{
+			"unmuted_repo":    repoFullName,
+			"muted_repos":     len(user.MutedRepos),
+			"github_username": user.GitHubUsername,
+		}
+	})
+}
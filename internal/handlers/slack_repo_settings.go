@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/slack-go/slack"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+)
+
+// mechanicalPRAnnounce is the repo settings option for announcing revert and back-merge PRs like any other PR.
+const mechanicalPRAnnounce = "announce"
+
+// handleEditRepoSettingsAction handles a repository picked from the App Home's "Edit a repository" select.
+// Opens the repository's settings editor. Only workspace admins can edit repositories.
+func (sh *SlackHandler) handleEditRepoSettingsAction(ctx context.Context, userID, teamID, triggerID, repoFullName string, c *gin.Context) {
+	ctx = log.WithFields(ctx, log.LogFields{
+		"user_id": userID,
+		"team_id": teamID,
+		"repo":    repoFullName,
+	})
+
+	isAdmin, err := sh.slackService.IsWorkspaceAdmin(ctx, teamID, userID)
+	if err != nil || !isAdmin {
+		log.Warn(ctx, "Ignoring repository settings request from non-admin", "error", err)
+		c.JSON(http.StatusOK, gin.H{})
+		return
+	}
+
+	repo, err := sh.firestoreService.GetRepo(ctx, repoFullName, teamID)
+	if err != nil || repo == nil {
+		log.Error(ctx, "Failed to get repository for settings", "error", err)
+		c.JSON(http.StatusOK, gin.H{})
+		return
+	}
+
+	if _, err := sh.slackService.OpenView(ctx, teamID, triggerID, sh.slackService.BuildRepoSettingsModal(repo)); err != nil {
+		log.Error(ctx, "Failed to open repository settings modal", "error", err)
+	}
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+// handleDeleteRepoAction handles a repository picked from the App Home's "Delete a repository" select.
+// Opens a confirmation before deleting it. Only workspace admins can delete repositories.
+func (sh *SlackHandler) handleDeleteRepoAction(ctx context.Context, userID, teamID, triggerID, repoFullName string, c *gin.Context) {
+	ctx = log.WithFields(ctx, log.LogFields{
+		"user_id": userID,
+		"team_id": teamID,
+		"repo":    repoFullName,
+	})
+
+	isAdmin, err := sh.slackService.IsWorkspaceAdmin(ctx, teamID, userID)
+	if err != nil || !isAdmin {
+		log.Warn(ctx, "Ignoring repository delete request from non-admin", "error", err)
+		c.JSON(http.StatusOK, gin.H{})
+		return
+	}
+
+	if _, err := sh.slackService.OpenView(ctx, teamID, triggerID, sh.slackService.BuildDeleteRepoModal(repoFullName)); err != nil {
+		log.Error(ctx, "Failed to open repository delete modal", "error", err)
+	}
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+// handleSaveRepoSettings validates and saves a repository's settings from the editor modal.
+// The default channel must be one the bot can post in, and the emoji must exist in the workspace.
+func (sh *SlackHandler) handleSaveRepoSettings(ctx context.Context, interaction *slack.InteractionCallback, c *gin.Context) {
+	userID := interaction.User.ID
+	teamID := interaction.Team.ID
+	repoFullName := interaction.View.PrivateMetadata // Repo name stored in private metadata
+
+	ctx = log.WithFields(ctx, log.LogFields{
+		"user_id": userID,
+		"team_id": teamID,
+		"repo":    repoFullName,
+	})
+
+	respondWithError := func(blockID, message string) {
+		c.JSON(http.StatusOK, map[string]interface{}{
+			"response_action": "errors",
+			"errors": map[string]string{
+				blockID: message,
+			},
+		})
+	}
+
+	isAdmin, err := sh.slackService.IsWorkspaceAdmin(ctx, teamID, userID)
+	if err != nil || !isAdmin {
+		log.Warn(ctx, "Rejecting repository settings from non-admin", "error", err)
+		respondWithError("repo_settings_mode_input", "Only workspace admins can edit repositories.")
+		return
+	}
+
+	repo, err := sh.firestoreService.GetRepo(ctx, repoFullName, teamID)
+	if err != nil || repo == nil {
+		log.Error(ctx, "Failed to get repository for settings", "error", err)
+		respondWithError("repo_settings_mode_input", "This repository no longer exists. It may have been deleted.")
+		return
+	}
+
+	applyRepoSettings(repo, interaction.View.State.Values)
+
+	if repo.DefaultChannel != "" {
+		if errorMsg, err := sh.validateChannelSelection(ctx, teamID, repo.DefaultChannel); err != nil {
+			log.Warn(ctx, "Repository default channel validation failed", "error", err, "channel_id", repo.DefaultChannel)
+			respondWithError("repo_settings_channel_input", errorMsg)
+			return
+		}
+	}
+
+	emoji, errorMsg := sh.parseMessageEmoji(ctx, teamID,
+		interaction.View.State.Values["repo_settings_emoji_input"]["repo_settings_emoji"].Value)
+	if errorMsg != "" {
+		respondWithError("repo_settings_emoji_input", errorMsg)
+		return
+	}
+	repo.Emoji = emoji
+
+	if err := sh.firestoreService.UpdateRepoSettings(ctx, repo); err != nil {
+		log.Error(ctx, "Failed to save repository settings", "error", err)
+		respondWithError("repo_settings_mode_input", "Failed to save the repository settings. Please try again.")
+		return
+	}
+
+	log.Info(ctx, "Repository settings saved from App Home")
+
+	c.JSON(http.StatusOK, gin.H{
+		"response_action": "clear",
+	})
+
+	sh.refreshHomeView(ctx, userID)
+}
+
+// handleConfirmDeleteRepo deletes a repository's configuration once the admin confirms.
+func (sh *SlackHandler) handleConfirmDeleteRepo(ctx context.Context, interaction *slack.InteractionCallback, c *gin.Context) {
+	userID := interaction.User.ID
+	teamID := interaction.Team.ID
+	repoFullName := interaction.View.PrivateMetadata // Repo name stored in private metadata
+
+	ctx = log.WithFields(ctx, log.LogFields{
+		"user_id": userID,
+		"team_id": teamID,
+		"repo":    repoFullName,
+	})
+
+	isAdmin, err := sh.slackService.IsWorkspaceAdmin(ctx, teamID, userID)
+	if err != nil || !isAdmin {
+		log.Warn(ctx, "Rejecting repository delete from non-admin", "error", err)
+		c.JSON(http.StatusOK, gin.H{})
+		return
+	}
+
+	if err := sh.firestoreService.DeleteRepo(ctx, repoFullName, teamID); err != nil {
+		log.Error(ctx, "Failed to delete repository", "error", err)
+		c.JSON(http.StatusOK, gin.H{})
+		return
+	}
+
+	log.Info(ctx, "Repository deleted from App Home")
+
+	c.JSON(http.StatusOK, gin.H{
+		"response_action": "clear",
+	})
+
+	sh.refreshHomeView(ctx, userID)
+}
+
+// applyRepoSettings applies the repository settings modal's inputs to the repository.
+// A mechanical PR channel set with the toolbox is kept if it's still selected.
+func applyRepoSettings(repo *models.Repo, values map[string]map[string]slack.BlockAction) {
+	repo.Enabled = len(values["repo_settings_enabled_input"]["repo_settings_enabled"].SelectedOptions) > 0
+	repo.DefaultChannel = values["repo_settings_channel_input"]["repo_settings_channel_select"].SelectedChannel
+
+	if mode := values["repo_settings_mode_input"]["repo_settings_mode"].SelectedOption.Value; mode != "" {
+		repo.NotificationMode = mode
+	}
+
+	switch handling := values["repo_settings_mechanical_input"]["repo_settings_mechanical_select"].SelectedOption.Value; handling {
+	case models.MechanicalPRHandlingSkip, models.MechanicalPRHandlingCompact:
+		repo.MechanicalPRs = &models.MechanicalPRConfig{Handling: handling}
+	case models.MechanicalPRHandlingChannel:
+		// Unchanged from the toolbox's configuration
+	case mechanicalPRAnnounce:
+		repo.MechanicalPRs = nil
+	}
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/slack-go/slack"
+
+	"github-slack-notifier/internal/log"
+)
+
+// handleManageReviewerRotationAction handles the "Edit reviewer rotation" button from the App Home workspace settings.
+// Opens a modal for picking the channel. Only workspace admins can edit reviewer rotations.
+func (sh *SlackHandler) handleManageReviewerRotationAction(ctx context.Context, userID, teamID, triggerID string, c *gin.Context) {
+	ctx = log.WithFields(ctx, log.LogFields{
+		"user_id": userID,
+		"team_id": teamID,
+	})
+
+	isAdmin, err := sh.slackService.IsWorkspaceAdmin(ctx, teamID, userID)
+	if err != nil || !isAdmin {
+		log.Warn(ctx, "Ignoring reviewer rotation request from non-admin", "error", err)
+		c.JSON(http.StatusOK, gin.H{})
+		return
+	}
+
+	if _, err := sh.slackService.OpenView(ctx, teamID, triggerID, sh.slackService.BuildReviewerRotationChannelModal()); err != nil {
+		log.Error(ctx, "Failed to open reviewer rotation modal", "error", err)
+	}
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+// handleReviewerRotationChannelSelection processes the channel picked in the reviewer rotation modal
+// and pushes the reviewer pool editor for it onto the modal stack.
+func (sh *SlackHandler) handleReviewerRotationChannelSelection(
+	ctx context.Context, interaction *slack.InteractionCallback, c *gin.Context,
+) {
+	teamID := interaction.Team.ID
+
+	channelID := ""
+	if values, ok := interaction.View.State.Values["reviewer_rotation_channel_input"]; ok {
+		if channelSelect, ok := values["reviewer_rotation_channel_select"]; ok {
+			channelID = channelSelect.SelectedChannel
+		}
+	}
+	if channelID == "" {
+		c.JSON(http.StatusOK, map[string]interface{}{
+			"response_action": "errors",
+			"errors": map[string]string{
+				"reviewer_rotation_channel_input": "Please select a channel.",
+			},
+		})
+		return
+	}
+
+	rotation, err := sh.firestoreService.GetReviewerRotation(ctx, teamID, channelID)
+	if err != nil {
+		log.Error(ctx, "Failed to get reviewer rotation", "error", err, "channel_id", channelID)
+		c.JSON(http.StatusOK, map[string]interface{}{
+			"response_action": "errors",
+			"errors": map[string]string{
+				"reviewer_rotation_channel_input": "Couldn't load this channel's rotation. Please try again.",
+			},
+		})
+		return
+	}
+
+	channelName, err := sh.slackService.GetChannelName(ctx, teamID, channelID)
+	if err != nil {
+		log.Error(ctx, "Failed to get channel name", "error", err, "channel_id", channelID)
+		channelName = channelID // Fallback to ID if name lookup fails
+	}
+
+	c.JSON(http.StatusOK, map[string]interface{}{
+		"response_action": "push",
+		"view":            sh.slackService.BuildReviewerRotationModal(channelID, channelName, rotation),
+	})
+}
+
+// handleSaveReviewerRotation validates and saves a channel's reviewer pool from the editor modal.
+// Every reviewer must have connected their GitHub account in this workspace, so reviews can be requested.
+func (sh *SlackHandler) handleSaveReviewerRotation(ctx context.Context, interaction *slack.InteractionCallback, c *gin.Context) {
+	userID := interaction.User.ID
+	teamID := interaction.Team.ID
+	channelID := interaction.View.PrivateMetadata // Channel ID stored in private metadata
+
+	ctx = log.WithFields(ctx, log.LogFields{
+		"user_id":    userID,
+		"team_id":    teamID,
+		"channel_id": channelID,
+	})
+
+	respondWithError := func(message string) {
+		c.JSON(http.StatusOK, map[string]interface{}{
+			"response_action": "errors",
+			"errors": map[string]string{
+				"reviewer_rotation_members_input": message,
+			},
+		})
+	}
+
+	isAdmin, err := sh.slackService.IsWorkspaceAdmin(ctx, teamID, userID)
+	if err != nil || !isAdmin {
+		log.Warn(ctx, "Rejecting reviewer rotation from non-admin", "error", err)
+		respondWithError("Only workspace admins can edit reviewer rotations.")
+		return
+	}
+
+	var members []string
+	if values, ok := interaction.View.State.Values["reviewer_rotation_members_input"]; ok {
+		if usersSelect, ok := values["reviewer_rotation_members_select"]; ok {
+			members = usersSelect.SelectedUsers
+		}
+	}
+
+	for _, member := range members {
+		user, err := sh.firestoreService.GetUserBySlackID(ctx, member)
+		if err != nil {
+			log.Error(ctx, "Failed to get reviewer", "error", err, "reviewer_slack_id", member)
+			respondWithError("Failed to check the reviewers. Please try again.")
+			return
+		}
+		if user == nil || user.GitHubUsername == "" || !user.Verified || user.SlackTeamID != teamID {
+			respondWithError(fmt.Sprintf("<@%s> hasn't connected their GitHub account yet.", member))
+			return
+		}
+	}
+
+	if err := sh.firestoreService.SaveReviewerRotationMembers(ctx, teamID, channelID, members, userID); err != nil {
+		log.Error(ctx, "Failed to save reviewer rotation", "error", err)
+		respondWithError("Failed to save the reviewer rotation. Please try again.")
+		return
+	}
+
+	log.Info(ctx, "Reviewer rotation saved from App Home", "member_count", len(members))
+
+	// Close the modal stack with success
+	c.JSON(http.StatusOK, gin.H{
+		"response_action": "clear",
+	})
+
+	sh.refreshHomeView(ctx, userID)
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/slack-go/slack"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/utils"
+)
+
+// handleManageRoutingRulesAction handles the "Edit routing rules" button from the App Home workspace settings.
+// Opens a modal listing the workspace's repositories. Only workspace admins can edit routing rules.
+func (sh *SlackHandler) handleManageRoutingRulesAction(ctx context.Context, userID, teamID, triggerID string, c *gin.Context) {
+	ctx = log.WithFields(ctx, log.LogFields{
+		"user_id": userID,
+		"team_id": teamID,
+	})
+
+	isAdmin, err := sh.slackService.IsWorkspaceAdmin(ctx, teamID, userID)
+	if err != nil || !isAdmin {
+		log.Warn(ctx, "Ignoring routing rules request from non-admin", "error", err)
+		c.JSON(http.StatusOK, gin.H{})
+		return
+	}
+
+	repos, err := sh.firestoreService.ListReposForWorkspace(ctx, teamID)
+	if err != nil {
+		log.Error(ctx, "Failed to list repos for routing rules", "error", err)
+		c.JSON(http.StatusOK, gin.H{})
+		return
+	}
+
+	if _, err := sh.slackService.OpenView(ctx, teamID, triggerID, sh.slackService.BuildRoutingRulesRepoModal(repos)); err != nil {
+		log.Error(ctx, "Failed to open routing rules modal", "error", err)
+	}
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+// handleRoutingRulesRepoSelection processes the repository picked in the routing rules modal
+// and pushes the rules editor for it onto the modal stack.
+func (sh *SlackHandler) handleRoutingRulesRepoSelection(ctx context.Context, interaction *slack.InteractionCallback, c *gin.Context) {
+	teamID := interaction.Team.ID
+
+	repoFullName := ""
+	if values, ok := interaction.View.State.Values["routing_rules_repo_input"]; ok {
+		if repoSelect, ok := values["routing_rules_repo_select"]; ok {
+			repoFullName = repoSelect.SelectedOption.Value
+		}
+	}
+
+	repo, err := sh.firestoreService.GetRepo(ctx, repoFullName, teamID)
+	if err != nil || repo == nil {
+		log.Error(ctx, "Failed to get repo for routing rules", "error", err, "repo", repoFullName)
+		c.JSON(http.StatusOK, map[string]interface{}{
+			"response_action": "errors",
+			"errors": map[string]string{
+				"routing_rules_repo_input": "Couldn't load this repository. Please try again.",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, map[string]interface{}{
+		"response_action": "push",
+		"view":            sh.slackService.BuildRoutingRulesModal(repo),
+	})
+}
+
+// handleSaveRoutingRules validates and saves a repository's routing rules from the editor modal.
+// Parse errors are shown on the input, so the admin can fix the offending line.
+func (sh *SlackHandler) handleSaveRoutingRules(ctx context.Context, interaction *slack.InteractionCallback, c *gin.Context) {
+	userID := interaction.User.ID
+	teamID := interaction.Team.ID
+	repoFullName := interaction.View.PrivateMetadata // Repo name stored in private metadata
+
+	ctx = log.WithFields(ctx, log.LogFields{
+		"user_id": userID,
+		"team_id": teamID,
+		"repo":    repoFullName,
+	})
+
+	respondWithError := func(message string) {
+		c.JSON(http.StatusOK, map[string]interface{}{
+			"response_action": "errors",
+			"errors": map[string]string{
+				"routing_rules_input": message,
+			},
+		})
+	}
+
+	isAdmin, err := sh.slackService.IsWorkspaceAdmin(ctx, teamID, userID)
+	if err != nil || !isAdmin {
+		log.Warn(ctx, "Rejecting routing rules from non-admin", "error", err)
+		respondWithError("Only workspace admins can edit routing rules.")
+		return
+	}
+
+	rulesText := ""
+	if values, ok := interaction.View.State.Values["routing_rules_input"]; ok {
+		if textInput, ok := values["routing_rules_text"]; ok {
+			rulesText = textInput.Value
+		}
+	}
+
+	rules, err := utils.ParseRoutingRules(rulesText)
+	if err != nil {
+		respondWithError(err.Error())
+		return
+	}
+
+	if err := sh.firestoreService.UpdateRepoRoutingRules(ctx, repoFullName, teamID, rules); err != nil {
+		log.Error(ctx, "Failed to save routing rules", "error", err)
+		respondWithError("Failed to save routing rules. Please try again.")
+		return
+	}
+
+	log.Info(ctx, "Routing rules saved from App Home", "rule_count", len(rules))
+
+	// Close the modal stack with success
+	c.JSON(http.StatusOK, gin.H{
+		"response_action": "clear",
+	})
+
+	sh.refreshHomeView(ctx, userID)
+}
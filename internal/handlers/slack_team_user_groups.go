@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/slack-go/slack"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+	"github-slack-notifier/internal/utils"
+)
+
+// handleManageTeamUserGroupsAction handles the "Edit team mappings" button from the App Home workspace settings.
+// Opens the editor mapping GitHub teams to Slack user groups. Only workspace admins can edit the mappings.
+func (sh *SlackHandler) handleManageTeamUserGroupsAction(ctx context.Context, userID, teamID, triggerID string, c *gin.Context) {
+	ctx = log.WithFields(ctx, log.LogFields{
+		"user_id": userID,
+		"team_id": teamID,
+	})
+
+	isAdmin, err := sh.slackService.IsWorkspaceAdmin(ctx, teamID, userID)
+	if err != nil || !isAdmin {
+		log.Warn(ctx, "Ignoring team mappings request from non-admin", "error", err)
+		c.JSON(http.StatusOK, gin.H{})
+		return
+	}
+
+	groups, err := sh.firestoreService.GetTeamUserGroups(ctx, teamID)
+	if err != nil {
+		log.Error(ctx, "Failed to get team user groups", "error", err)
+		c.JSON(http.StatusOK, gin.H{})
+		return
+	}
+	var mappings []models.TeamUserGroup
+	if groups != nil {
+		mappings = groups.Mappings
+	}
+
+	if _, err := sh.slackService.OpenView(ctx, teamID, triggerID, sh.slackService.BuildTeamUserGroupsModal(mappings)); err != nil {
+		log.Error(ctx, "Failed to open team mappings modal", "error", err)
+	}
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+// handleSaveTeamUserGroups validates and saves the workspace's GitHub team to Slack user group mappings.
+// User group handles are resolved to IDs when saving, so mentions keep working if a group is renamed.
+func (sh *SlackHandler) handleSaveTeamUserGroups(ctx context.Context, interaction *slack.InteractionCallback, c *gin.Context) {
+	userID := interaction.User.ID
+	teamID := interaction.Team.ID
+
+	ctx = log.WithFields(ctx, log.LogFields{
+		"user_id": userID,
+		"team_id": teamID,
+	})
+
+	respondWithError := func(message string) {
+		c.JSON(http.StatusOK, map[string]interface{}{
+			"response_action": "errors",
+			"errors": map[string]string{
+				"team_user_groups_input": message,
+			},
+		})
+	}
+
+	isAdmin, err := sh.slackService.IsWorkspaceAdmin(ctx, teamID, userID)
+	if err != nil || !isAdmin {
+		log.Warn(ctx, "Rejecting team mappings from non-admin", "error", err)
+		respondWithError("Only workspace admins can edit team mappings.")
+		return
+	}
+
+	mappings, err := utils.ParseTeamUserGroups(interaction.View.State.Values["team_user_groups_input"]["team_user_groups_text"].Value)
+	if err != nil {
+		respondWithError(err.Error())
+		return
+	}
+
+	if len(mappings) > 0 {
+		userGroups, err := sh.slackService.ListUserGroups(ctx, teamID)
+		if err != nil {
+			log.Error(ctx, "Failed to list user groups for team mappings", "error", err)
+			respondWithError("Couldn't list the workspace's user groups. The app may need reinstalling to allow it.")
+			return
+		}
+		if message := resolveTeamUserGroups(mappings, userGroups); message != "" {
+			respondWithError(message)
+			return
+		}
+	}
+
+	err = sh.firestoreService.SaveTeamUserGroups(ctx, &models.TeamUserGroups{
+		SlackTeamID:  teamID,
+		Mappings:     mappings,
+		ConfiguredBy: userID,
+	})
+	if err != nil {
+		log.Error(ctx, "Failed to save team user groups", "error", err)
+		respondWithError("Failed to save team mappings. Please try again.")
+		return
+	}
+
+	log.Info(ctx, "Team mappings saved from App Home", "mapping_count", len(mappings))
+
+	c.JSON(http.StatusOK, gin.H{
+		"response_action": "clear",
+	})
+}
+
+// resolveTeamUserGroups fills in each mapping's user group ID from the workspace's user groups, matching handles
+// ignoring case. Returns an error message naming the first handle that isn't an enabled user group.
+func resolveTeamUserGroups(mappings []models.TeamUserGroup, userGroups []slack.UserGroup) string {
+	for i := range mappings {
+		for _, group := range userGroups {
+			if group.DateDelete == 0 && strings.EqualFold(group.Handle, mappings[i].SlackUserGroupHandle) {
+				mappings[i].SlackUserGroupID = group.ID
+				mappings[i].SlackUserGroupHandle = group.Handle
+				break
+			}
+		}
+		if mappings[i].SlackUserGroupID == "" {
+			return fmt.Sprintf("There's no user group @%s in this workspace.", mappings[i].SlackUserGroupHandle)
+		}
+	}
+	return ""
+}
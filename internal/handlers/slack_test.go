@@ -2,8 +2,12 @@ package handlers
 
 import (
 	"testing"
+	"unicode/utf8"
 
 	"github-slack-notifier/internal/models"
+	"github-slack-notifier/internal/services"
+	"github-slack-notifier/internal/utils"
+	"github.com/slack-go/slack"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -240,3 +244,95 @@ func TestSlackHandler_parsePRSizeConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestOpenPRButtonURL(t *testing.T) {
+	openPR := slack.NewButtonBlockElement(services.OpenPRActionID, "", slack.NewTextBlockObject(slack.PlainTextType, "Open PR", false, false))
+	openPR.URL = "https://github.com/o/r/pull/1"
+	showMore := slack.NewButtonBlockElement(services.ShowPRDetailsActionID, "details",
+		slack.NewTextBlockObject(slack.PlainTextType, "Show more", false, false))
+
+	attachments := []slack.Attachment{{
+		Blocks: slack.Blocks{BlockSet: []slack.Block{slack.NewActionBlock("", openPR, showMore)}},
+	}}
+	assert.Equal(t, "https://github.com/o/r/pull/1", openPRButtonURL(attachments))
+
+	attachments[0].Blocks.BlockSet = []slack.Block{slack.NewActionBlock("", showMore)}
+	assert.Empty(t, openPRButtonURL(attachments), "no Open PR button")
+	assert.Empty(t, openPRButtonURL(nil))
+}
+
+func TestParseReactionEmoji(t *testing.T) {
+	input := func(state, value string) (string, map[string]slack.BlockAction) {
+		return "reaction_emoji_" + state + "_input", map[string]slack.BlockAction{
+			"reaction_emoji_" + state: {Value: value},
+		}
+	}
+	values := make(map[string]map[string]slack.BlockAction)
+	for state, value := range map[string]string{
+		"approved":          " :ShipIt: ",
+		"changes_requested": "no_entry",
+		"commented":         "",
+		"merged":            "two words",
+	} {
+		blockID, actions := input(state, value)
+		values[blockID] = actions
+	}
+
+	emoji, validationErrors := parseReactionEmoji(values)
+	assert.Equal(t, "shipit", emoji.Approved)
+	assert.Equal(t, "no_entry", emoji.ChangesRequested)
+	assert.Empty(t, emoji.Commented)
+	assert.Empty(t, emoji.Closed, "missing inputs are left unset")
+	assert.Equal(t, map[string]string{
+		"reaction_emoji_merged_input": "Enter a single emoji name, e.g. :white_check_mark:",
+	}, validationErrors)
+}
+
+func TestBaseReactionName(t *testing.T) {
+	assert.Equal(t, "white_check_mark", baseReactionName("white_check_mark"))
+	assert.Equal(t, "+1", baseReactionName("+1::skin-tone-2"))
+}
+
+func TestMessageTemplateError(t *testing.T) {
+	_, err := utils.ParseMessageTemplate("{{.Title}} by {{.Author}}")
+	assert.Equal(t, "The template must link to the PR with {{.Link}} or {{.URL}}.", messageTemplateError(err))
+
+	_, err = utils.ParseMessageTemplate("{{.Link}} {{.Reviewers}}")
+	assert.Contains(t, messageTemplateError(err), "This template isn't valid: template: message:1:")
+	assert.LessOrEqual(t, utf8.RuneCountInString(messageTemplateError(err)), maxModalErrorLength)
+}
+
+func TestApplyRepoSettings(t *testing.T) {
+	values := func(enabled bool, channel, mode, mechanical string) map[string]map[string]slack.BlockAction {
+		var selected []slack.OptionBlockObject
+		if enabled {
+			selected = []slack.OptionBlockObject{{Value: "enabled"}}
+		}
+		return map[string]map[string]slack.BlockAction{
+			"repo_settings_enabled_input": {"repo_settings_enabled": {SelectedOptions: selected}},
+			"repo_settings_channel_input": {"repo_settings_channel_select": {SelectedChannel: channel}},
+			"repo_settings_mode_input":    {"repo_settings_mode": {SelectedOption: slack.OptionBlockObject{Value: mode}}},
+			"repo_settings_mechanical_input": {
+				"repo_settings_mechanical_select": {SelectedOption: slack.OptionBlockObject{Value: mechanical}},
+			},
+		}
+	}
+
+	repo := &models.Repo{RepoFullName: "org/api", Enabled: true, DefaultChannel: "C1"}
+	applyRepoSettings(repo, values(false, "", models.NotificationModeCompact, models.MechanicalPRHandlingSkip))
+	assert.False(t, repo.Enabled)
+	assert.Empty(t, repo.DefaultChannel)
+	assert.Equal(t, models.NotificationModeCompact, repo.NotificationMode)
+	assert.Equal(t, &models.MechanicalPRConfig{Handling: models.MechanicalPRHandlingSkip}, repo.MechanicalPRs)
+
+	applyRepoSettings(repo, values(true, "C2", models.NotificationModeFull, mechanicalPRAnnounce))
+	assert.True(t, repo.Enabled)
+	assert.Equal(t, "C2", repo.DefaultChannel)
+	assert.Equal(t, models.NotificationModeFull, repo.NotificationMode)
+	assert.Nil(t, repo.MechanicalPRs)
+
+	channelConfig := &models.MechanicalPRConfig{Handling: models.MechanicalPRHandlingChannel, Channel: "#reverts"}
+	repo.MechanicalPRs = channelConfig
+	applyRepoSettings(repo, values(true, "", models.NotificationModeFull, models.MechanicalPRHandlingChannel))
+	assert.Same(t, channelConfig, repo.MechanicalPRs, "a channel set with the toolbox is kept")
+}
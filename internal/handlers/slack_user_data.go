@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+)
+
+// userDataExportMaxLength is the longest export sent as a DM, leaving room under Slack's 40,000 character
+// message limit for the surrounding text. Larger exports have to be requested through the admin API.
+const userDataExportMaxLength = 39000
+
+// handleUserDataAction queues a user's request from App Home to export or delete their data.
+func (sh *SlackHandler) handleUserDataAction(ctx context.Context, jobType, userID, teamID string, c *gin.Context) {
+	ctx = log.WithFields(ctx, log.LogFields{
+		"user_id":  userID,
+		"job_type": jobType,
+	})
+
+	jobID := uuid.New().String()
+	traceID := traceIDForNewJob(ctx)
+	jobPayload, err := json.Marshal(&models.UserDataJob{
+		ID:          jobID,
+		SlackTeamID: teamID,
+		SlackUserID: userID,
+		TraceID:     traceID,
+	})
+	if err != nil {
+		log.Error(ctx, "Failed to marshal user data job", "error", err)
+		c.JSON(http.StatusOK, gin.H{})
+		return
+	}
+
+	job := &models.Job{
+		ID:      jobID,
+		Type:    jobType,
+		TraceID: traceID,
+		Payload: jobPayload,
+	}
+	if err := sh.cloudTasksService.EnqueueJob(ctx, job); err != nil {
+		log.Error(ctx, "Failed to enqueue user data job", "error", err)
+	} else {
+		log.Info(ctx, "User data job queued", "job_id", jobID)
+	}
+
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+// unmarshalUserDataJob reads and validates a user data job's payload.
+func unmarshalUserDataJob(job *models.Job) (*models.UserDataJob, error) {
+	var userDataJob models.UserDataJob
+	if err := json.Unmarshal(job.Payload, &userDataJob); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal user data job: %w", models.Permanent(err))
+	}
+	if err := userDataJob.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid user data job: %w", models.Permanent(err))
+	}
+	return &userDataJob, nil
+}
+
+// ProcessUserDataExportJob DMs a user everything PR Bot stores about them, as JSON.
+func (sh *SlackHandler) ProcessUserDataExportJob(ctx context.Context, job *models.Job) error {
+	exportJob, err := unmarshalUserDataJob(job)
+	if err != nil {
+		return err
+	}
+	ctx = log.WithFields(ctx, log.LogFields{
+		"team_id": exportJob.SlackTeamID,
+		"user_id": exportJob.SlackUserID,
+	})
+
+	export, err := sh.firestoreService.ExportUserData(ctx, exportJob.SlackTeamID, exportJob.SlackUserID)
+	if err != nil {
+		return err
+	}
+
+	exportJSON, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal user data export: %w", err)
+	}
+
+	var text string
+	switch {
+	case export.IsEmpty():
+		text = "PR Bot doesn't store any data about you."
+	case len(exportJSON) > userDataExportMaxLength:
+		text = "Your PR Bot data export is too large to send in Slack. Ask a workspace admin to export it for you."
+	default:
+		text = fmt.Sprintf("Here's everything PR Bot stores about you:\n```%s```", exportJSON)
+	}
+
+	if _, err := sh.slackService.PostMessage(ctx, exportJob.SlackTeamID, exportJob.SlackUserID, text); err != nil {
+		return err
+	}
+
+	log.Info(ctx, "Sent user data export",
+		"collections", len(export.Collections),
+		"export_bytes", len(exportJSON),
+		"sent", len(exportJSON) <= userDataExportMaxLength,
+	)
+	return nil
+}
+
+// ProcessUserDataDeletionJob deletes everything PR Bot stores about a user, revoking their Slack user token
+// if they granted one, then refreshes their App Home and confirms by DM.
+func (sh *SlackHandler) ProcessUserDataDeletionJob(ctx context.Context, job *models.Job) error {
+	deletionJob, err := unmarshalUserDataJob(job)
+	if err != nil {
+		return err
+	}
+	ctx = log.WithFields(ctx, log.LogFields{
+		"team_id": deletionJob.SlackTeamID,
+		"user_id": deletionJob.SlackUserID,
+	})
+
+	if err := sh.slackService.RevokeUserToken(ctx, deletionJob.SlackTeamID, deletionJob.SlackUserID); err != nil {
+		return err
+	}
+
+	deletion, err := sh.firestoreService.DeleteUserData(ctx, deletionJob.SlackTeamID, deletionJob.SlackUserID)
+	if err != nil {
+		log.Error(ctx, "Failed to delete user data", "error", err, "deleted", deletion)
+		return err
+	}
+
+	log.Info(ctx, "Deleted user data",
+		"deleted", deletion.Deleted,
+		"anonymized", deletion.Anonymized,
+	)
+
+	sh.publishHomeView(ctx, deletionJob.SlackTeamID, deletionJob.SlackUserID)
+
+	text := "Your PR Bot data has been deleted. Your PRs are no longer posted or linked to you. " +
+		"To use PR Bot again, connect your GitHub account from the App Home."
+	if _, err := sh.slackService.PostMessage(ctx, deletionJob.SlackTeamID, deletionJob.SlackUserID, text); err != nil {
+		// The data is gone; only the confirmation is missing
+		log.Warn(ctx, "Failed to confirm user data deletion", "error", err)
+	}
+	return nil
+}
@@ -43,3 +43,17 @@ func GetLogFields(ctx context.Context) LogFields {
 	}
 	return make(LogFields)
 }
+
+// WithTraceID returns a context carrying the given trace ID, which is attached to every log line.
+func WithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, TraceIDKey, traceID)
+}
+
+// TraceID retrieves the trace ID from the context.
+// Returns an empty string if none is set.
+func TraceID(ctx context.Context) string {
+	if traceID, ok := ctx.Value(TraceIDKey).(string); ok {
+		return traceID
+	}
+	return ""
+}
@@ -0,0 +1,139 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github-slack-notifier/internal/config"
+	"github-slack-notifier/internal/log"
+	"github.com/gin-gonic/gin"
+)
+
+// Reasons logged when an admin request is denied.
+const (
+	adminDenyIPNotAllowed = "ip_not_allowed"
+	adminDenyMissingToken = "missing_token"
+	adminDenyInvalidToken = "invalid_token"
+)
+
+// AdminAuthMiddleware creates middleware that restricts admin routes to allowed client IPs
+// and verifies the bearer token. Tokens are checked against ADMIN_API_KEY and the ADMIN_API_KEY_SHA256
+// hashes by comparing SHA-256 digests in constant time, checking every configured token so timing
+// doesn't reveal which one matched. Denied requests are logged with the client IP and reason for auditing.
+// The client IP only comes from X-Forwarded-For when TRUSTED_PROXIES forwarded the request, whatever proxies
+// the router trusts, so callers can't spoof an allowed address.
+func AdminAuthMiddleware(cfg *config.Config) gin.HandlerFunc {
+	tokenHashes := adminTokenHashes(cfg)
+
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		clientIP := adminClientIP(c, cfg.TrustedProxies)
+
+		if !isAllowedIP(clientIP, cfg.AdminAllowedIPs) {
+			denyAdminRequest(c, clientIP, http.StatusForbidden, adminDenyIPNotAllowed, "access denied")
+			return
+		}
+
+		providedKey, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !found || providedKey == "" {
+			denyAdminRequest(c, clientIP, http.StatusUnauthorized, adminDenyMissingToken, "authentication required")
+			return
+		}
+
+		if !matchesTokenHash(providedKey, tokenHashes) {
+			denyAdminRequest(c, clientIP, http.StatusUnauthorized, adminDenyInvalidToken, "authentication failed")
+			return
+		}
+
+		log.Debug(ctx, "Admin authentication successful")
+		c.Next()
+	}
+}
+
+// adminTokenHashes returns the SHA-256 digests of all accepted admin tokens.
+// Hashes were validated when the configuration was loaded.
+func adminTokenHashes(cfg *config.Config) [][]byte {
+	var hashes [][]byte
+	if cfg.AdminAPIKey != "" {
+		sum := sha256.Sum256([]byte(cfg.AdminAPIKey))
+		hashes = append(hashes, sum[:])
+	}
+	for _, hash := range cfg.AdminAPIKeyHashes {
+		if decoded, err := hex.DecodeString(hash); err == nil {
+			hashes = append(hashes, decoded)
+		}
+	}
+	return hashes
+}
+
+// matchesTokenHash reports whether a token's SHA-256 digest matches any of the accepted hashes.
+// Every hash is compared, in constant time, so the timing is the same whichever matches.
+func matchesTokenHash(token string, hashes [][]byte) bool {
+	sum := sha256.Sum256([]byte(token))
+	matched := 0
+	for _, hash := range hashes {
+		matched |= subtle.ConstantTimeCompare(sum[:], hash)
+	}
+	return matched == 1
+}
+
+// adminClientIP returns the IP address an admin request came from. X-Forwarded-For is only read when the
+// connection is from a trusted proxy, and then from the right, stopping at the first address that isn't a
+// trusted proxy, since entries further left are whatever the caller sent.
+func adminClientIP(c *gin.Context, trustedProxies []netip.Prefix) string {
+	clientIP := c.RemoteIP()
+	if !inPrefixes(clientIP, trustedProxies) {
+		return clientIP
+	}
+
+	forwarded := strings.Split(c.GetHeader("X-Forwarded-For"), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(forwarded[i])
+		if _, err := netip.ParseAddr(hop); err != nil {
+			break
+		}
+		clientIP = hop
+		if !inPrefixes(clientIP, trustedProxies) {
+			break
+		}
+	}
+	return clientIP
+}
+
+// isAllowedIP reports whether a client IP is within the allowlist. An empty allowlist allows any IP.
+func isAllowedIP(clientIP string, allowed []netip.Prefix) bool {
+	return len(allowed) == 0 || inPrefixes(clientIP, allowed)
+}
+
+// inPrefixes reports whether an IP address is within any of the prefixes.
+func inPrefixes(ip string, prefixes []netip.Prefix) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// denyAdminRequest logs a denied admin request for auditing and aborts it with an error response.
+func denyAdminRequest(c *gin.Context, clientIP string, status int, reason, message string) {
+	log.Warn(c.Request.Context(), "Admin request denied",
+		"reason", reason,
+		"status", status,
+		"client_ip", clientIP,
+		"method", c.Request.Method,
+		"path", c.Request.URL.Path,
+		"user_agent", c.Request.UserAgent(),
+	)
+	c.JSON(status, gin.H{"error": message})
+	c.Abort()
+}
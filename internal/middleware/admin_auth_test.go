@@ -0,0 +1,132 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github-slack-notifier/internal/config"
+)
+
+func TestAdminAuthMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	hashedToken := "rotated-token"
+	sum := sha256.Sum256([]byte(hashedToken))
+
+	tests := []struct {
+		name           string
+		cfg            *config.Config
+		remoteAddr     string
+		forwardedFor   string
+		authorization  string
+		expectedStatus int
+	}{
+		{
+			name:           "plain key",
+			cfg:            &config.Config{AdminAPIKey: "secret"},
+			authorization:  "Bearer secret",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "hashed key",
+			cfg:            &config.Config{AdminAPIKey: "secret", AdminAPIKeyHashes: []string{hex.EncodeToString(sum[:])}},
+			authorization:  "Bearer " + hashedToken,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "wrong key",
+			cfg:            &config.Config{AdminAPIKey: "secret"},
+			authorization:  "Bearer guess",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "missing key",
+			cfg:            &config.Config{AdminAPIKey: "secret"},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "allowed IP range",
+			cfg: &config.Config{
+				AdminAPIKey:     "secret",
+				AdminAllowedIPs: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+			},
+			remoteAddr:     "10.1.2.3:4567",
+			authorization:  "Bearer secret",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "disallowed IP is rejected before the token is checked",
+			cfg: &config.Config{
+				AdminAPIKey:     "secret",
+				AdminAllowedIPs: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+			},
+			remoteAddr:     "192.0.2.1:4567",
+			authorization:  "Bearer secret",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name: "spoofed X-Forwarded-For from an untrusted address is ignored",
+			cfg: &config.Config{
+				AdminAPIKey:     "secret",
+				AdminAllowedIPs: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+			},
+			remoteAddr:     "192.0.2.1:4567",
+			forwardedFor:   "10.1.2.3",
+			authorization:  "Bearer secret",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name: "X-Forwarded-For from a trusted proxy is used",
+			cfg: &config.Config{
+				AdminAPIKey:     "secret",
+				AdminAllowedIPs: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+				TrustedProxies:  []netip.Prefix{netip.MustParsePrefix("172.16.0.0/12")},
+			},
+			remoteAddr:     "172.16.0.5:4567",
+			forwardedFor:   "10.1.2.3",
+			authorization:  "Bearer secret",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "X-Forwarded-For entries left of the trusted proxy's are ignored",
+			cfg: &config.Config{
+				AdminAPIKey:     "secret",
+				AdminAllowedIPs: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+				TrustedProxies:  []netip.Prefix{netip.MustParsePrefix("172.16.0.0/12")},
+			},
+			remoteAddr:     "172.16.0.5:4567",
+			forwardedFor:   "10.1.2.3, 192.0.2.1",
+			authorization:  "Bearer secret",
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/admin", AdminAuthMiddleware(tt.cfg), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+			if tt.remoteAddr != "" {
+				req.RemoteAddr = tt.remoteAddr
+			}
+			if tt.forwardedFor != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwardedFor)
+			}
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"fmt"
+
+	"github-slack-notifier/internal/log"
+	"github.com/gin-gonic/gin"
+)
+
+// DeprecationMiddleware creates middleware for legacy unversioned routes.
+// Responses carry a Deprecation header and a Link to the versioned successor path.
+func DeprecationMiddleware(versionPrefix string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		successor := versionPrefix + c.Request.URL.Path
+
+		c.Header("Deprecation", "true")
+		c.Header("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", successor))
+
+		log.Debug(c.Request.Context(), "Request to deprecated unversioned route",
+			"path", c.Request.URL.Path,
+			"successor_path", successor,
+		)
+		c.Next()
+	}
+}
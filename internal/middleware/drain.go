@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github-slack-notifier/internal/log"
+	"github.com/gin-gonic/gin"
+)
+
+// Drainer tracks in-flight requests so shutdown can wait for them, and rejects new requests once draining starts.
+type Drainer struct {
+	retryAfter time.Duration
+
+	mu       sync.Mutex
+	draining bool
+	inFlight int
+	idle     chan struct{} // Closed once draining and no requests are in flight
+}
+
+// NewDrainer creates a Drainer that asks rejected clients to retry after the given duration.
+func NewDrainer(retryAfter time.Duration) *Drainer {
+	return &Drainer{
+		retryAfter: retryAfter,
+		idle:       make(chan struct{}),
+	}
+}
+
+// Middleware creates middleware that tracks in-flight requests and responds 503 with Retry-After while draining.
+// Applied globally, so /health also reports the instance as not ready once draining starts.
+func (d *Drainer) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !d.begin() {
+			log.Warn(c.Request.Context(), "Rejecting request during shutdown", "path", c.Request.URL.Path)
+			c.Header("Retry-After", strconv.Itoa(int(d.retryAfter.Seconds())))
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "server is shutting down"})
+			c.Abort()
+			return
+		}
+		defer d.end()
+
+		c.Next()
+	}
+}
+
+// IsDraining returns whether shutdown has started.
+func (d *Drainer) IsDraining() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.draining
+}
+
+// Drain stops accepting new requests and waits for in-flight requests to finish.
+// Returns an error if the context expires first.
+func (d *Drainer) Drain(ctx context.Context) error {
+	d.mu.Lock()
+	if !d.draining {
+		d.draining = true
+		if d.inFlight == 0 {
+			close(d.idle)
+		}
+	}
+	d.mu.Unlock()
+
+	select {
+	case <-d.idle:
+		return nil
+	case <-ctx.Done():
+		d.mu.Lock()
+		remaining := d.inFlight
+		d.mu.Unlock()
+		return fmt.Errorf("%d requests still in flight: %w", remaining, ctx.Err())
+	}
+}
+
+// begin registers a new in-flight request, returning false if draining has started.
+func (d *Drainer) begin() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.draining {
+		return false
+	}
+	d.inFlight++
+	return true
+}
+
+// end marks an in-flight request as finished.
+func (d *Drainer) end() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.inFlight--
+	if d.draining && d.inFlight == 0 {
+		close(d.idle)
+	}
+}
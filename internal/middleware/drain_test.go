@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDrainer(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("waits for in-flight requests and rejects new ones", func(t *testing.T) {
+		drainer := NewDrainer(5 * time.Second)
+		started := make(chan struct{})
+		release := make(chan struct{})
+
+		router := gin.New()
+		router.Use(drainer.Middleware())
+		router.POST("/jobs/process", func(c *gin.Context) {
+			close(started)
+			<-release
+			c.Status(http.StatusOK)
+		})
+		router.GET("/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+		inFlight := httptest.NewRecorder()
+		done := make(chan struct{})
+		go func() {
+			router.ServeHTTP(inFlight, httptest.NewRequest(http.MethodPost, "/jobs/process", nil))
+			close(done)
+		}()
+		<-started
+
+		drained := make(chan error, 1)
+		go func() { drained <- drainer.Drain(context.Background()) }()
+		require.Eventually(t, drainer.IsDraining, time.Second, time.Millisecond)
+
+		rejected := httptest.NewRecorder()
+		router.ServeHTTP(rejected, httptest.NewRequest(http.MethodGet, "/health", nil))
+		assert.Equal(t, http.StatusServiceUnavailable, rejected.Code)
+		assert.Equal(t, "5", rejected.Header().Get("Retry-After"))
+
+		select {
+		case <-drained:
+			t.Fatal("drain finished while a request was in flight")
+		default:
+		}
+
+		close(release)
+		<-done
+		require.NoError(t, <-drained)
+		assert.Equal(t, http.StatusOK, inFlight.Code)
+	})
+
+	t.Run("times out when requests don't finish", func(t *testing.T) {
+		drainer := NewDrainer(time.Second)
+		require.True(t, drainer.begin())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		err := drainer.Drain(ctx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Contains(t, err.Error(), "1 requests still in flight")
+	})
+
+	t.Run("idle drain returns immediately", func(t *testing.T) {
+		drainer := NewDrainer(time.Second)
+		require.NoError(t, drainer.Drain(context.Background()))
+		require.NoError(t, drainer.Drain(context.Background()))
+	})
+}
@@ -0,0 +1,141 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github-slack-notifier/internal/config"
+	"github-slack-notifier/internal/log"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/api/idtoken"
+)
+
+// Job authentication errors. ErrJobAuthMissing means the request carried no credentials at all.
+var (
+	ErrJobAuthMissing = errors.New("missing job queue credentials")
+	ErrJobAuthInvalid = errors.New("invalid job queue credentials")
+)
+
+// JobVerifier verifies that a request to the job processor was sent by the job queue.
+// Each queue backend authenticates its callbacks differently, so the verifier is chosen by JOB_AUTH_METHOD.
+type JobVerifier interface {
+	// Verify returns nil if the request is authentic, or an error wrapping ErrJobAuthMissing or ErrJobAuthInvalid.
+	Verify(r *http.Request) error
+}
+
+// NewJobVerifier returns the verifier for the configured job authentication method.
+// Settings were validated when the configuration was loaded.
+func NewJobVerifier(cfg *config.Config) JobVerifier {
+	switch cfg.JobAuth.Method {
+	case config.JobAuthOIDC:
+		return &oidcJobVerifier{
+			audience:       cfg.JobAuthAudience(),
+			serviceAccount: cfg.JobAuth.OIDCServiceAccount,
+			validate:       idtoken.Validate,
+		}
+	case config.JobAuthMTLS:
+		return &mtlsJobVerifier{allowedSubjects: cfg.JobAuth.MTLSAllowedSubjects}
+	default:
+		return &secretJobVerifier{secretHash: sha256.Sum256([]byte(cfg.CloudTasksSecret))}
+	}
+}
+
+// JobAuthMiddleware creates middleware that rejects job processor requests the verifier doesn't accept.
+func JobAuthMiddleware(verifier JobVerifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		if err := verifier.Verify(c.Request); err != nil {
+			log.Error(ctx, "Job queue authentication failed", "error", err)
+			message := "authentication failed"
+			if errors.Is(err, ErrJobAuthMissing) {
+				message = "authentication required"
+			}
+			c.JSON(http.StatusUnauthorized, gin.H{"error": message})
+			c.Abort()
+			return
+		}
+
+		log.Debug(ctx, "Job queue authentication successful")
+		c.Next()
+	}
+}
+
+// secretJobVerifier accepts requests carrying the static shared secret in X-Cloud-Tasks-Secret,
+// which the Cloud Tasks service adds to every task it enqueues.
+type secretJobVerifier struct {
+	secretHash [sha256.Size]byte
+}
+
+// Verify compares the provided secret's digest with the configured one in constant time.
+func (v *secretJobVerifier) Verify(r *http.Request) error {
+	providedSecret := r.Header.Get("X-Cloud-Tasks-Secret")
+	if providedSecret == "" {
+		return fmt.Errorf("%w: no X-Cloud-Tasks-Secret header", ErrJobAuthMissing)
+	}
+	providedHash := sha256.Sum256([]byte(providedSecret))
+	if subtle.ConstantTimeCompare(providedHash[:], v.secretHash[:]) != 1 {
+		return fmt.Errorf("%w: secret doesn't match", ErrJobAuthInvalid)
+	}
+	return nil
+}
+
+// oidcJobVerifier accepts requests with a Google-signed OIDC token in the Authorization header, issued to the
+// configured service account for the job processor. Cloud Tasks HTTP targets with an OIDC token and Pub/Sub push
+// subscriptions with authentication both send such tokens.
+type oidcJobVerifier struct {
+	audience       string
+	serviceAccount string
+	validate       func(ctx context.Context, token, audience string) (*idtoken.Payload, error)
+}
+
+// Verify validates the token's signature, expiry and audience, then checks which service account it was issued to.
+func (v *oidcJobVerifier) Verify(r *http.Request) error {
+	token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !found || token == "" {
+		return fmt.Errorf("%w: no bearer token", ErrJobAuthMissing)
+	}
+
+	payload, err := v.validate(r.Context(), token, v.audience)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrJobAuthInvalid, err)
+	}
+
+	email, _ := payload.Claims["email"].(string)
+	verified, _ := payload.Claims["email_verified"].(bool)
+	if !verified || !strings.EqualFold(email, v.serviceAccount) {
+		return fmt.Errorf("%w: token issued for %q, not the configured service account", ErrJobAuthInvalid, email)
+	}
+	return nil
+}
+
+// mtlsJobVerifier accepts requests whose TLS client certificate was verified against TLS_CLIENT_CA_FILE,
+// for self-hosted queues calling the job processor directly over HTTPS.
+type mtlsJobVerifier struct {
+	allowedSubjects []string // Common names or DNS names allowed; any verified certificate when empty
+}
+
+// Verify checks the verified client certificate's common name and DNS names against the allowed subjects.
+func (v *mtlsJobVerifier) Verify(r *http.Request) error {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return fmt.Errorf("%w: no verified client certificate", ErrJobAuthMissing)
+	}
+	if len(v.allowedSubjects) == 0 {
+		return nil
+	}
+
+	cert := r.TLS.VerifiedChains[0][0]
+	subjects := append([]string{cert.Subject.CommonName}, cert.DNSNames...)
+	for _, subject := range subjects {
+		if slices.Contains(v.allowedSubjects, subject) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: client certificate %q isn't allowed", ErrJobAuthInvalid, cert.Subject.CommonName)
+}
@@ -0,0 +1,125 @@
+package middleware
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/api/idtoken"
+
+	"github-slack-notifier/internal/config"
+)
+
+func TestJobAuthMiddleware_Secret(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	verifier := NewJobVerifier(&config.Config{CloudTasksSecret: "secret"})
+
+	tests := []struct {
+		name           string
+		secret         string
+		expectedStatus int
+		expectedError  string
+	}{
+		{name: "matching secret", secret: "secret", expectedStatus: http.StatusOK},
+		{name: "wrong secret", secret: "guess", expectedStatus: http.StatusUnauthorized, expectedError: "authentication failed"},
+		{name: "missing secret", expectedStatus: http.StatusUnauthorized, expectedError: "authentication required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.POST("/jobs/process", JobAuthMiddleware(verifier), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/jobs/process", nil)
+			if tt.secret != "" {
+				req.Header.Set("X-Cloud-Tasks-Secret", tt.secret)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedError != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestOIDCJobVerifier(t *testing.T) {
+	verifier := &oidcJobVerifier{
+		audience:       "https://notifier.example.com/v1/jobs/process",
+		serviceAccount: "tasks@project.iam.gserviceaccount.com",
+		validate: func(_ context.Context, token, audience string) (*idtoken.Payload, error) {
+			if audience != "https://notifier.example.com/v1/jobs/process" {
+				return nil, errors.New("wrong audience")
+			}
+			switch token {
+			case "tasks-token":
+				return &idtoken.Payload{Claims: map[string]interface{}{
+					"email": "tasks@project.iam.gserviceaccount.com", "email_verified": true,
+				}}, nil
+			case "other-account-token":
+				return &idtoken.Payload{Claims: map[string]interface{}{
+					"email": "other@project.iam.gserviceaccount.com", "email_verified": true,
+				}}, nil
+			default:
+				return nil, errors.New("invalid signature")
+			}
+		},
+	}
+
+	tests := []struct {
+		name          string
+		authorization string
+		expectedErr   error
+	}{
+		{name: "token for the service account", authorization: "Bearer tasks-token"},
+		{name: "token for another account", authorization: "Bearer other-account-token", expectedErr: ErrJobAuthInvalid},
+		{name: "invalid token", authorization: "Bearer forged", expectedErr: ErrJobAuthInvalid},
+		{name: "missing token", expectedErr: ErrJobAuthMissing},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/jobs/process", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+
+			err := verifier.Verify(req)
+			if tt.expectedErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			}
+		})
+	}
+}
+
+func TestMTLSJobVerifier(t *testing.T) {
+	withClientCert := func(commonName string, dnsNames ...string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/jobs/process", nil)
+		cert := &x509.Certificate{Subject: pkix.Name{CommonName: commonName}, DNSNames: dnsNames}
+		req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+		return req
+	}
+
+	anyCert := &mtlsJobVerifier{}
+	assert.NoError(t, anyCert.Verify(withClientCert("queue-worker")))
+	assert.ErrorIs(t, anyCert.Verify(httptest.NewRequest(http.MethodPost, "/jobs/process", nil)), ErrJobAuthMissing,
+		"plain HTTP has no client certificate")
+
+	allowlisted := &mtlsJobVerifier{allowedSubjects: []string{"queue.internal"}}
+	assert.NoError(t, allowlisted.Verify(withClientCert("queue.internal")))
+	assert.NoError(t, allowlisted.Verify(withClientCert("worker-1", "queue.internal")), "DNS names are matched too")
+	assert.ErrorIs(t, allowlisted.Verify(withClientCert("someone-else")), ErrJobAuthInvalid)
+}
@@ -0,0 +1,169 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"cloud.google.com/go/firestore"
+	"github.com/slack-go/slack"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+	"github-slack-notifier/internal/services"
+	"github-slack-notifier/internal/utils"
+)
+
+// backfillTrackedMessageChannels resolves legacy tracked messages that stored a channel name in
+// slack_channel to the channel ID, and fills in slack_channel_name for records that only have an ID.
+// Documents whose channel can't be resolved (e.g. deleted or archived channels) are left unchanged.
+// Failed lookups that may succeed later, e.g. when Slack rate limits the backfill, stop the migration,
+// so it resumes from its last checkpoint rather than skipping those documents.
+func backfillTrackedMessageChannels(channels *cachingChannelResolver) Migration {
+	return Migration{
+		Version:    2,
+		Name:       "backfill_tracked_message_channels",
+		Collection: "trackedmessages",
+		Migrate: func(ctx context.Context, docID string, data map[string]interface{}) ([]firestore.Update, error) {
+			teamID, _ := data["slack_team_id"].(string)
+			channel, _ := data["slack_channel"].(string)
+			channelName, _ := data["slack_channel_name"].(string)
+			if teamID == "" || channel == "" {
+				return nil, nil
+			}
+
+			var updates []firestore.Update
+			if !utils.IsChannelID(channel) {
+				channelID, ok, err := channels.resolveID(ctx, teamID, channel)
+				if err != nil {
+					return nil, err
+				}
+				if !ok {
+					log.Warn(ctx, "Could not resolve channel name to ID, leaving document unchanged",
+						"doc_id", docID,
+						"channel", channel,
+						"team_id", teamID,
+					)
+					return nil, nil
+				}
+				updates = append(updates, firestore.Update{Path: "slack_channel", Value: channelID})
+				if channelName == "" {
+					updates = append(updates, firestore.Update{Path: "slack_channel_name", Value: channel})
+				}
+				return updates, nil
+			}
+
+			if channelName == "" {
+				name, ok, err := channels.lookupName(ctx, teamID, channel)
+				if err != nil {
+					return nil, err
+				}
+				if ok {
+					updates = append(updates, firestore.Update{Path: "slack_channel_name", Value: name})
+				}
+			}
+			return updates, nil
+		},
+	}
+}
+
+// backfillChannelConfigNames fills in the cached channel name for channel configs that are missing it.
+func backfillChannelConfigNames(channels *cachingChannelResolver) Migration {
+	return Migration{
+		Version:    3,
+		Name:       "backfill_channel_config_names",
+		Collection: "channel_configs",
+		Migrate: func(ctx context.Context, _ string, data map[string]interface{}) ([]firestore.Update, error) {
+			teamID, _ := data["slack_team_id"].(string)
+			channelID, _ := data["slack_channel_id"].(string)
+			channelName, _ := data["slack_channel_name"].(string)
+			if teamID == "" || !utils.IsChannelID(channelID) {
+				return nil, nil
+			}
+			if channelName != "" && channelName != channelID {
+				return nil, nil
+			}
+
+			name, ok, err := channels.lookupName(ctx, teamID, channelID)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				return nil, nil
+			}
+			return []firestore.Update{{Path: "slack_channel_name", Value: name}}, nil
+		},
+	}
+}
+
+// cachingChannelResolver memoizes channel lookups so large backfills don't repeat Slack API calls.
+// Channels that don't exist are cached too, so an unresolvable channel is only queried once. Other failed
+// lookups aren't cached, and are returned.
+type cachingChannelResolver struct {
+	resolver ChannelResolver
+	ids      map[string]string // "{team}#{name}" -> ID ("" if unresolvable)
+	names    map[string]string // "{team}#{id}" -> name ("" if unresolvable)
+}
+
+func newCachingChannelResolver(resolver ChannelResolver) *cachingChannelResolver {
+	return &cachingChannelResolver{
+		resolver: resolver,
+		ids:      make(map[string]string),
+		names:    make(map[string]string),
+	}
+}
+
+// resolveID returns the channel ID for a channel name, and whether it could be resolved.
+// Returns an error if the lookup failed for a reason other than the channel not existing.
+func (c *cachingChannelResolver) resolveID(ctx context.Context, teamID, channelName string) (string, bool, error) {
+	key := teamID + "#" + channelName
+	if id, cached := c.ids[key]; cached {
+		return id, id != "", nil
+	}
+	if c.resolver == nil {
+		return "", false, nil
+	}
+
+	id, err := c.resolver.ResolveChannelID(ctx, teamID, channelName)
+	if err != nil && !isChannelMissing(err) {
+		return "", false, fmt.Errorf("failed to resolve channel %s in team %s: %w", channelName, teamID, err)
+	}
+	if err != nil || !utils.IsChannelID(id) {
+		id = ""
+	}
+	c.ids[key] = id
+	return id, id != "", nil
+}
+
+// lookupName returns the channel name for a channel ID, and whether it could be found.
+// Returns an error if the lookup failed for a reason other than the channel not existing.
+func (c *cachingChannelResolver) lookupName(ctx context.Context, teamID, channelID string) (string, bool, error) {
+	key := teamID + "#" + channelID
+	if name, cached := c.names[key]; cached {
+		return name, name != "", nil
+	}
+	if c.resolver == nil {
+		return "", false, nil
+	}
+
+	name, err := c.resolver.GetChannelName(ctx, teamID, channelID)
+	if err != nil && !isChannelMissing(err) {
+		return "", false, fmt.Errorf("failed to look up channel %s in team %s: %w", channelID, teamID, err)
+	}
+	if err != nil {
+		name = ""
+	}
+	c.names[key] = name
+	return name, name != "", nil
+}
+
+// isChannelMissing reports whether a failed lookup means the channel can't be resolved, however often it's
+// retried: the channel doesn't exist or the bot can't see it, or the workspace uninstalled the app or revoked
+// its token.
+func isChannelMissing(err error) bool {
+	if errors.Is(err, services.ErrChannelNotFound) || errors.Is(err, models.ErrPermanent) {
+		return true
+	}
+	var slackErr slack.SlackErrorResponse
+	return errors.As(err, &slackErr) && slackErr.Err == "channel_not_found"
+}
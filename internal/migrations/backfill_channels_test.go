@@ -0,0 +1,170 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github-slack-notifier/internal/models"
+	"github-slack-notifier/internal/services"
+)
+
+// fakeChannelResolver fails lookups the way SlackService does: with ErrChannelNotFound for unknown names, and
+// Slack's channel_not_found for unknown IDs. Lookups fail with err instead when it's set.
+type fakeChannelResolver struct {
+	ids         map[string]string
+	names       map[string]string
+	err         error
+	lookupCount int
+}
+
+func (f *fakeChannelResolver) ResolveChannelID(_ context.Context, _, channel string) (string, error) {
+	f.lookupCount++
+	if f.err != nil {
+		return "", f.err
+	}
+	if id, ok := f.ids[channel]; ok {
+		return id, nil
+	}
+	return "", fmt.Errorf("%w: %s", services.ErrChannelNotFound, channel)
+}
+
+func (f *fakeChannelResolver) GetChannelName(_ context.Context, _, channelID string) (string, error) {
+	f.lookupCount++
+	if f.err != nil {
+		return "", f.err
+	}
+	if name, ok := f.names[channelID]; ok {
+		return name, nil
+	}
+	return "", fmt.Errorf("failed to get channel info: %w", slack.SlackErrorResponse{Err: "channel_not_found"})
+}
+
+func TestBackfillTrackedMessageChannels(t *testing.T) {
+	resolver := &fakeChannelResolver{
+		ids:   map[string]string{"eng-prs": "C0123456789"},
+		names: map[string]string{"C0123456789": "eng-prs"},
+	}
+	migrate := backfillTrackedMessageChannels(newCachingChannelResolver(resolver)).Migrate
+
+	tests := []struct {
+		name     string
+		data     map[string]interface{}
+		expected []firestore.Update
+	}{
+		{
+			name: "legacy name is resolved to ID and name preserved",
+			data: map[string]interface{}{"slack_team_id": "T1", "slack_channel": "eng-prs"},
+			expected: []firestore.Update{
+				{Path: "slack_channel", Value: "C0123456789"},
+				{Path: "slack_channel_name", Value: "eng-prs"},
+			},
+		},
+		{
+			name: "ID-only record gets name",
+			data: map[string]interface{}{"slack_team_id": "T1", "slack_channel": "C0123456789"},
+			expected: []firestore.Update{
+				{Path: "slack_channel_name", Value: "eng-prs"},
+			},
+		},
+		{
+			name:     "complete record is unchanged",
+			data:     map[string]interface{}{"slack_team_id": "T1", "slack_channel": "C0123456789", "slack_channel_name": "eng-prs"},
+			expected: nil,
+		},
+		{
+			name:     "unresolvable channel is left unchanged",
+			data:     map[string]interface{}{"slack_team_id": "T1", "slack_channel": "deleted-channel"},
+			expected: nil,
+		},
+		{
+			name:     "ID of a deleted channel is left unchanged",
+			data:     map[string]interface{}{"slack_team_id": "T1", "slack_channel": "C0DELETED01"},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updates, err := migrate(context.Background(), "doc", tt.data)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, updates)
+		})
+	}
+}
+
+func TestCachingChannelResolver_CachesLookups(t *testing.T) {
+	resolver := &fakeChannelResolver{ids: map[string]string{"eng-prs": "C0123456789"}}
+	cache := newCachingChannelResolver(resolver)
+
+	for range 3 {
+		id, ok, err := cache.resolveID(context.Background(), "T1", "eng-prs")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "C0123456789", id)
+
+		_, ok, err = cache.resolveID(context.Background(), "T1", "missing")
+		require.NoError(t, err)
+		assert.False(t, ok)
+	}
+
+	assert.Equal(t, 2, resolver.lookupCount)
+}
+
+func TestBackfillChannels_TransientLookupErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"rate limited", &slack.RateLimitedError{RetryAfter: time.Second}},
+		{"Slack outage", slack.SlackErrorResponse{Err: "internal_error"}},
+		{"timeout", context.DeadlineExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver := &fakeChannelResolver{err: tt.err}
+			channels := newCachingChannelResolver(resolver)
+			backfillMessages := backfillTrackedMessageChannels(channels).Migrate
+			backfillConfigs := backfillChannelConfigNames(channels).Migrate
+
+			// The migration stops rather than skipping the document, and the failure isn't cached
+			for range 2 {
+				_, err := backfillMessages(context.Background(), "doc", map[string]interface{}{
+					"slack_team_id": "T1", "slack_channel": "eng-prs",
+				})
+				require.ErrorContains(t, err, tt.err.Error())
+
+				_, err = backfillConfigs(context.Background(), "doc", map[string]interface{}{
+					"slack_team_id": "T1", "slack_channel_id": "C0123456789",
+				})
+				require.ErrorContains(t, err, tt.err.Error())
+			}
+			assert.Equal(t, 4, resolver.lookupCount)
+
+			// Once Slack recovers, the same channels resolve
+			resolver.err = nil
+			resolver.ids = map[string]string{"eng-prs": "C0123456789"}
+			resolver.names = map[string]string{"C0123456789": "eng-prs"}
+			updates, err := backfillConfigs(context.Background(), "doc", map[string]interface{}{
+				"slack_team_id": "T1", "slack_channel_id": "C0123456789",
+			})
+			require.NoError(t, err)
+			assert.Equal(t, []firestore.Update{{Path: "slack_channel_name", Value: "eng-prs"}}, updates)
+		})
+	}
+}
+
+func TestIsChannelMissing(t *testing.T) {
+	assert.True(t, isChannelMissing(fmt.Errorf("%w: eng-prs", services.ErrChannelNotFound)))
+	assert.True(t, isChannelMissing(fmt.Errorf("wrapped: %w", slack.SlackErrorResponse{Err: "channel_not_found"})))
+	assert.True(t, isChannelMissing(models.Permanent(services.ErrWorkspaceRevoked)))
+	assert.False(t, isChannelMissing(slack.SlackErrorResponse{Err: "ratelimited"}))
+	assert.False(t, isChannelMissing(context.DeadlineExceeded))
+}
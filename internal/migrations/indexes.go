@@ -0,0 +1,85 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Index is a composite index the service's queries need. Every field is ascending.
+// Keep RequiredIndexes in step with firestore.indexes.json, which is what gets deployed.
+type Index struct {
+	Collection string
+	Fields     []string // In index order
+}
+
+func (i Index) String() string {
+	return fmt.Sprintf("%s(%s)", i.Collection, strings.Join(i.Fields, ", "))
+}
+
+// RequiredIndexes are the composite indexes declared in firestore.indexes.json.
+var RequiredIndexes = []Index{
+	{Collection: "messages", Fields: []string{"repo_full_name", "pr_number"}},
+	{Collection: "repos", Fields: []string{"repo_full_name", "enabled"}},
+	{Collection: "trackedmessages", Fields: []string{"slack_team_id", "slack_channel", "created_at"}},
+	{Collection: "trackedmessages", Fields: []string{"slack_team_id", "message_source", "created_at"}},
+	{Collection: "trackedmessages", Fields: []string{"slack_team_id", "pr_author_github_id", "created_at"}},
+	{Collection: "trackedmessages", Fields: []string{"slack_team_id", "created_at"}},
+	{Collection: "trackedmessages", Fields: []string{"repo_full_name", "created_at"}},
+	{Collection: "notification_log", Fields: []string{"repo_full_name", "pr_number", "sent_at"}},
+	{Collection: "notification_log", Fields: []string{"repo_full_name", "sent_at"}},
+	{Collection: "notification_log", Fields: []string{"slack_team_id", "sent_at"}},
+}
+
+// MissingIndex is a required index that Firestore reported isn't built.
+type MissingIndex struct {
+	Index
+	CreateURL string // Console link that creates the index, when Firestore gave one
+}
+
+// createIndexURLPattern finds the console link Firestore includes in its missing index errors.
+var createIndexURLPattern = regexp.MustCompile(`https://console\.firebase\.google\.com/\S+`)
+
+// CheckIndexes runs a one-document query that needs each required index, and returns the indexes
+// Firestore rejects the query for. Indexes that are still building are reported as missing too.
+func CheckIndexes(ctx context.Context, client *firestore.Client) ([]MissingIndex, error) {
+	var missing []MissingIndex
+	for _, index := range RequiredIndexes {
+		err := probeIndex(ctx, client, index)
+		if err == nil {
+			continue
+		}
+		if status.Code(err) != codes.FailedPrecondition {
+			return nil, fmt.Errorf("failed to check index %s: %w", index, err)
+		}
+		missing = append(missing, MissingIndex{
+			Index:     index,
+			CreateURL: createIndexURLPattern.FindString(status.Convert(err).Message()),
+		})
+	}
+	return missing, nil
+}
+
+// probeIndex runs a query that can only be served by the index: equality on every field but the last,
+// ordered by the last. Matching no documents is fine, since Firestore checks the index before reading.
+func probeIndex(ctx context.Context, client *firestore.Client, index Index) error {
+	last := len(index.Fields) - 1
+	query := client.Collection(index.Collection).Query
+	for _, field := range index.Fields[:last] {
+		query = query.Where(field, "==", "")
+	}
+	iter := query.OrderBy(index.Fields[last], firestore.Asc).Limit(1).Documents(ctx)
+	defer iter.Stop()
+
+	if _, err := iter.Next(); err != nil && !errors.Is(err, iterator.Done) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package migrations
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRequiredIndexes_MatchIndexFile keeps the startup index check in step with the indexes that get deployed.
+func TestRequiredIndexes_MatchIndexFile(t *testing.T) {
+	data, err := os.ReadFile("../../firestore.indexes.json")
+	require.NoError(t, err)
+
+	var file struct {
+		Indexes []struct {
+			CollectionGroup string `json:"collectionGroup"`
+			Fields          []struct {
+				FieldPath string `json:"fieldPath"`
+				Order     string `json:"order"`
+			} `json:"fields"`
+		} `json:"indexes"`
+	}
+	require.NoError(t, json.Unmarshal(data, &file))
+
+	deployed := make([]Index, 0, len(file.Indexes))
+	for _, index := range file.Indexes {
+		fields := make([]string, 0, len(index.Fields))
+		for _, field := range index.Fields {
+			assert.Equal(t, "ASCENDING", field.Order, "probe queries only order ascending")
+			fields = append(fields, field.FieldPath)
+		}
+		deployed = append(deployed, Index{Collection: index.CollectionGroup, Fields: fields})
+	}
+	assert.ElementsMatch(t, deployed, RequiredIndexes)
+}
+
+func TestCreateIndexURLPattern(t *testing.T) {
+	message := "The query requires an index. You can create it here: " +
+		"https://console.firebase.google.com/v1/r/project/demo/firestore/indexes?create_composite=Cg5wcm9q"
+	assert.Equal(t, "https://console.firebase.google.com/v1/r/project/demo/firestore/indexes?create_composite=Cg5wcm9q",
+		createIndexURLPattern.FindString(message))
+}
@@ -0,0 +1,311 @@
+// Package migrations provides a versioned framework for Firestore schema changes.
+// Migrations are registered in code (see All) and applied with `toolbox migrate up`.
+// Progress is recorded per migration in the schema_versions collection so interrupted
+// runs resume from the last processed document.
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+)
+
+const (
+	// SchemaVersionsCollection stores one document per migration, keyed by zero-padded version.
+	SchemaVersionsCollection = "schema_versions"
+	// DefaultBatchSize is the number of documents processed between progress checkpoints.
+	DefaultBatchSize = 500
+	// versionDocIDWidth zero-pads version document IDs so they sort naturally.
+	versionDocIDWidth = 4
+)
+
+var (
+	// ErrInvalidMigration indicates a migration is missing required fields.
+	ErrInvalidMigration = errors.New("invalid migration")
+	// ErrDuplicateMigrationVersion indicates two migrations share a version number.
+	ErrDuplicateMigrationVersion = errors.New("duplicate migration version")
+)
+
+// Migration is a versioned change applied to every document in a collection.
+type Migration struct {
+	Version    int    // Unique, increasing version number
+	Name       string // Short identifier, e.g. "normalize_users_to_cc"
+	Collection string // Firestore collection whose documents are migrated
+
+	// Migrate returns the field updates for a single document, or nil if the document needs no change.
+	Migrate func(ctx context.Context, docID string, data map[string]interface{}) ([]firestore.Update, error)
+}
+
+// Options controls how migrations are applied.
+type Options struct {
+	DryRun        bool // Log changes without writing documents or recording progress
+	BatchSize     int  // Documents per batch (defaults to DefaultBatchSize)
+	TargetVersion int  // Apply migrations up to and including this version (0 means all)
+}
+
+// Status describes the state of a registered migration.
+type Status struct {
+	Migration Migration
+	State     *models.SchemaVersion // nil if the migration has never run
+}
+
+// Runner applies registered migrations to Firestore.
+type Runner struct {
+	client     *firestore.Client
+	migrations []Migration
+	opts       Options
+}
+
+// NewRunner creates a Runner after validating and sorting the migrations by version.
+func NewRunner(client *firestore.Client, migrations []Migration, opts Options) (*Runner, error) {
+	sorted, err := sortAndValidate(migrations)
+	if err != nil {
+		return nil, err
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultBatchSize
+	}
+
+	return &Runner{
+		client:     client,
+		migrations: sorted,
+		opts:       opts,
+	}, nil
+}
+
+// sortAndValidate returns the migrations sorted by version, rejecting invalid or duplicate entries.
+func sortAndValidate(migrations []Migration) ([]Migration, error) {
+	sorted := make([]Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+
+	for i, m := range sorted {
+		if m.Version <= 0 || m.Name == "" || m.Collection == "" || m.Migrate == nil {
+			return nil, fmt.Errorf("%w: version %d (%s)", ErrInvalidMigration, m.Version, m.Name)
+		}
+		if i > 0 && sorted[i-1].Version == m.Version {
+			return nil, fmt.Errorf("%w: %d", ErrDuplicateMigrationVersion, m.Version)
+		}
+	}
+
+	return sorted, nil
+}
+
+// versionDocID returns the schema_versions document ID for a migration version.
+func versionDocID(version int) string {
+	id := strconv.Itoa(version)
+	for len(id) < versionDocIDWidth {
+		id = "0" + id
+	}
+	return id
+}
+
+// Status returns the recorded state of every registered migration.
+func (r *Runner) Status(ctx context.Context) ([]Status, error) {
+	statuses := make([]Status, 0, len(r.migrations))
+	for _, m := range r.migrations {
+		state, err := r.loadState(ctx, m)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, Status{Migration: m, State: state})
+	}
+	return statuses, nil
+}
+
+// Up applies all pending migrations in version order, resuming any partially applied migration.
+func (r *Runner) Up(ctx context.Context) error {
+	for _, m := range r.migrations {
+		if r.opts.TargetVersion > 0 && m.Version > r.opts.TargetVersion {
+			break
+		}
+
+		migrationCtx := log.WithFields(ctx, log.LogFields{
+			"migration_version": m.Version,
+			"migration_name":    m.Name,
+			"collection":        m.Collection,
+			"dry_run":           r.opts.DryRun,
+		})
+
+		state, err := r.loadState(migrationCtx, m)
+		if err != nil {
+			return err
+		}
+		if state != nil && state.Status == models.SchemaVersionStatusApplied {
+			log.Debug(migrationCtx, "Migration already applied")
+			continue
+		}
+
+		if err := r.apply(migrationCtx, m, state); err != nil {
+			return fmt.Errorf("migration %d (%s) failed: %w", m.Version, m.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// loadState reads the schema_versions document for a migration, returning nil if it has never run.
+func (r *Runner) loadState(ctx context.Context, m Migration) (*models.SchemaVersion, error) {
+	doc, err := r.client.Collection(SchemaVersionsCollection).Doc(versionDocID(m.Version)).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to load schema version %d: %w", m.Version, err)
+	}
+
+	var state models.SchemaVersion
+	if err := doc.DataTo(&state); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal schema version %d: %w", m.Version, err)
+	}
+	return &state, nil
+}
+
+// apply runs a single migration in batches ordered by document ID, checkpointing after each batch.
+func (r *Runner) apply(ctx context.Context, m Migration, state *models.SchemaVersion) error {
+	now := time.Now()
+	if state == nil {
+		state = &models.SchemaVersion{
+			Version:   m.Version,
+			Name:      m.Name,
+			StartedAt: now,
+		}
+	} else {
+		log.Info(ctx, "Resuming migration",
+			"last_doc_id", state.LastDocID,
+			"documents_scanned", state.DocumentsScanned,
+		)
+	}
+	state.Status = models.SchemaVersionStatusRunning
+
+	log.Info(ctx, "Applying migration")
+
+	for {
+		scanned, err := r.applyBatch(ctx, m, state)
+		if err != nil {
+			return err
+		}
+		if scanned < r.opts.BatchSize {
+			break
+		}
+	}
+
+	appliedAt := time.Now()
+	state.Status = models.SchemaVersionStatusApplied
+	state.AppliedAt = &appliedAt
+	if err := r.saveState(ctx, state); err != nil {
+		return err
+	}
+
+	log.Info(ctx, "Migration applied",
+		"documents_scanned", state.DocumentsScanned,
+		"documents_updated", state.DocumentsUpdated,
+	)
+	return nil
+}
+
+// applyBatch migrates the next batch of documents after the state's cursor and records progress.
+// Returns the number of documents scanned in the batch.
+func (r *Runner) applyBatch(ctx context.Context, m Migration, state *models.SchemaVersion) (int, error) {
+	query := r.client.Collection(m.Collection).OrderBy(firestore.DocumentID, firestore.Asc).Limit(r.opts.BatchSize)
+	if state.LastDocID != "" {
+		query = query.StartAfter(state.LastDocID)
+	}
+
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	var bulkWriter *firestore.BulkWriter
+	if !r.opts.DryRun {
+		bulkWriter = r.client.BulkWriter(ctx)
+		defer bulkWriter.End()
+	}
+
+	scanned := 0
+	var jobs []*firestore.BulkWriterJob
+	var jobDocIDs []string
+	for {
+		doc, err := iter.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return scanned, fmt.Errorf("failed to iterate %s: %w", m.Collection, err)
+		}
+		scanned++
+		state.DocumentsScanned++
+		state.LastDocID = doc.Ref.ID
+
+		updates, err := m.Migrate(ctx, doc.Ref.ID, doc.Data())
+		if err != nil {
+			return scanned, fmt.Errorf("failed to migrate document %s: %w", doc.Ref.ID, err)
+		}
+		if len(updates) == 0 {
+			continue
+		}
+
+		state.DocumentsUpdated++
+		if r.opts.DryRun {
+			log.Info(ctx, "Dry run: would update document", "doc_id", doc.Ref.ID, "field_count", len(updates))
+			continue
+		}
+		job, err := bulkWriter.Update(doc.Ref, updates)
+		if err != nil {
+			return scanned, fmt.Errorf("failed to queue update for document %s: %w", doc.Ref.ID, err)
+		}
+		jobs = append(jobs, job)
+		jobDocIDs = append(jobDocIDs, doc.Ref.ID)
+	}
+
+	if bulkWriter != nil {
+		bulkWriter.Flush()
+	}
+
+	// Only checkpoint once every update in the batch is written, so a rejected write is retried on resume
+	// rather than the migration moving past it
+	var writeErrors []error
+	for i, job := range jobs {
+		if _, err := job.Results(); err != nil {
+			writeErrors = append(writeErrors, fmt.Errorf("failed to update document %s: %w", jobDocIDs[i], err))
+		}
+	}
+	if len(writeErrors) > 0 {
+		return scanned, fmt.Errorf("failed to write %d of %d updates in batch: %w", len(writeErrors), len(jobs), errors.Join(writeErrors...))
+	}
+
+	if err := r.saveState(ctx, state); err != nil {
+		return scanned, err
+	}
+
+	log.Debug(ctx, "Migration batch processed",
+		"batch_size", scanned,
+		"documents_scanned", state.DocumentsScanned,
+		"documents_updated", state.DocumentsUpdated,
+	)
+	return scanned, nil
+}
+
+// saveState checkpoints migration progress. Dry runs never record progress.
+func (r *Runner) saveState(ctx context.Context, state *models.SchemaVersion) error {
+	if r.opts.DryRun {
+		return nil
+	}
+
+	state.UpdatedAt = time.Now()
+	_, err := r.client.Collection(SchemaVersionsCollection).Doc(versionDocID(state.Version)).Set(ctx, state)
+	if err != nil {
+		return fmt.Errorf("failed to save schema version %d: %w", state.Version, err)
+	}
+	return nil
+}
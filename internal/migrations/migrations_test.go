@@ -0,0 +1,86 @@
+package migrations
+
+import (
+	"context"
+	"testing"
+
+	"cloud.google.com/go/firestore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func noopMigrate(context.Context, string, map[string]interface{}) ([]firestore.Update, error) {
+	return nil, nil
+}
+
+func TestAll_IsValid(t *testing.T) {
+	_, err := sortAndValidate(All(Dependencies{}))
+	require.NoError(t, err)
+}
+
+func TestSortAndValidate(t *testing.T) {
+	t.Run("sorts by version", func(t *testing.T) {
+		sorted, err := sortAndValidate([]Migration{
+			{Version: 2, Name: "b", Collection: "c", Migrate: noopMigrate},
+			{Version: 1, Name: "a", Collection: "c", Migrate: noopMigrate},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 1, sorted[0].Version)
+		assert.Equal(t, 2, sorted[1].Version)
+	})
+
+	t.Run("rejects duplicate versions", func(t *testing.T) {
+		_, err := sortAndValidate([]Migration{
+			{Version: 1, Name: "a", Collection: "c", Migrate: noopMigrate},
+			{Version: 1, Name: "b", Collection: "c", Migrate: noopMigrate},
+		})
+		require.ErrorIs(t, err, ErrDuplicateMigrationVersion)
+	})
+
+	t.Run("rejects missing fields", func(t *testing.T) {
+		_, err := sortAndValidate([]Migration{{Version: 1, Name: "a", Collection: "c"}})
+		require.ErrorIs(t, err, ErrInvalidMigration)
+	})
+}
+
+func TestVersionDocID(t *testing.T) {
+	assert.Equal(t, "0001", versionDocID(1))
+	assert.Equal(t, "0042", versionDocID(42))
+	assert.Equal(t, "12345", versionDocID(12345))
+}
+
+func TestNormalizeUsersToCC(t *testing.T) {
+	migrate := normalizeUsersToCC().Migrate
+
+	tests := []struct {
+		name     string
+		data     map[string]interface{}
+		expected []firestore.Update
+	}{
+		{
+			name:     "no CC field",
+			data:     map[string]interface{}{},
+			expected: nil,
+		},
+		{
+			name:     "already normalized",
+			data:     map[string]interface{}{"users_to_cc": []interface{}{"alice", "bob"}},
+			expected: nil,
+		},
+		{
+			name: "strips @ and duplicates",
+			data: map[string]interface{}{"users_to_cc": []interface{}{"@alice", "bob", "alice"}},
+			expected: []firestore.Update{
+				{Path: "users_to_cc", Value: []string{"alice", "bob"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updates, err := migrate(context.Background(), "doc", tt.data)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, updates)
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package migrations
+
+import (
+	"context"
+	"strings"
+
+	"cloud.google.com/go/firestore"
+)
+
+// normalizeUsersToCC strips leading "@" characters and duplicates from tracked message CC lists,
+// so stored GitHub usernames match the format produced by directive parsing.
+func normalizeUsersToCC() Migration {
+	return Migration{
+		Version:    1,
+		Name:       "normalize_users_to_cc",
+		Collection: "trackedmessages",
+		Migrate: func(_ context.Context, _ string, data map[string]interface{}) ([]firestore.Update, error) {
+			raw, ok := data["users_to_cc"].([]interface{})
+			if !ok || len(raw) == 0 {
+				return nil, nil
+			}
+
+			changed := false
+			seen := make(map[string]bool, len(raw))
+			normalized := make([]string, 0, len(raw))
+			for _, value := range raw {
+				username, ok := value.(string)
+				if !ok {
+					changed = true
+					continue
+				}
+				trimmed := strings.TrimPrefix(strings.TrimSpace(username), "@")
+				if trimmed != username {
+					changed = true
+				}
+				if trimmed == "" || seen[trimmed] {
+					changed = true
+					continue
+				}
+				seen[trimmed] = true
+				normalized = append(normalized, trimmed)
+			}
+
+			if !changed {
+				return nil, nil
+			}
+			return []firestore.Update{{Path: "users_to_cc", Value: normalized}}, nil
+		},
+	}
+}
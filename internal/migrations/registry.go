@@ -0,0 +1,26 @@
+package migrations
+
+import "context"
+
+// ChannelResolver resolves Slack channel names and IDs within a workspace.
+type ChannelResolver interface {
+	ResolveChannelID(ctx context.Context, teamID, channel string) (string, error)
+	GetChannelName(ctx context.Context, teamID, channelID string) (string, error)
+}
+
+// Dependencies holds the external services that migrations may use.
+type Dependencies struct {
+	Channels ChannelResolver
+}
+
+// All returns every registered migration. Add new migrations to the end of this list
+// with the next version number; never renumber or remove an applied migration.
+func All(deps Dependencies) []Migration {
+	channels := newCachingChannelResolver(deps.Channels)
+
+	return []Migration{
+		normalizeUsersToCC(),
+		backfillTrackedMessageChannels(channels),
+		backfillChannelConfigNames(channels),
+	}
+}
@@ -1,8 +1,16 @@
 package models
 
 import (
+	"bytes"
+	"compress/gzip"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
 	"time"
 )
 
@@ -20,6 +28,7 @@ var (
 	ErrTraceIDRequired             = errors.New("trace ID is required")
 	ErrAccessTokenRequired         = errors.New("access token is required")
 	ErrTeamNameRequired            = errors.New("team name is required")
+	ErrEnterpriseIDRequired        = errors.New("enterprise ID is required")
 	ErrSlackOAuthFailed            = errors.New("slack OAuth failed")
 	ErrInstallationIDRequired      = errors.New("installation ID is required")
 	ErrAccountLoginRequired        = errors.New("account login is required")
@@ -32,24 +41,90 @@ var (
 	ErrPRActionRequired            = errors.New("PR action is required")
 	ErrRepoConfigNotFound          = errors.New("repository configuration not found")
 	ErrWorkspaceJobsEnqueueFailed  = errors.New("failed to enqueue workspace PR jobs")
+	ErrPRNotificationInFlight      = errors.New("PR notification still being posted")
 	ErrTrackedMessageIDRequired    = errors.New("tracked message ID is required")
+	ErrSlackUserIDRequired         = errors.New("slack user ID is required")
+	ErrReportWindowRequired        = errors.New("report window is required")
+	ErrReviewerRequired            = errors.New("requested reviewer is required")
+	ErrAssigneeRequired            = errors.New("assignee is required")
+	ErrHeadSHARequired             = errors.New("head commit SHA is required")
+	ErrCommentIDRequired           = errors.New("comment ID is required")
+	ErrTargetChannelRequired       = errors.New("target channel is required")
+	ErrNotificationTextRequired    = errors.New("notification text is required")
+	ErrInvalidPRSizeRange          = errors.New("minimum PR size must not be above the maximum")
+	ErrInvalidBranchPattern        = errors.New("invalid branch pattern")
+	ErrInvalidTitlePattern         = errors.New("invalid title pattern")
+	ErrCommentTextRequired         = errors.New("comment text is required")
+	ErrReactionActionRequired      = errors.New("reaction action is required")
 )
 
+// Job failure classes, which decide whether Cloud Tasks retries a failed job. Errors are classified by wrapping
+// them with Retryable or Permanent, and errors.Is finds the class through any further wrapping.
+var (
+	// ErrRetryable marks failures that can succeed on a later attempt, e.g. an API outage.
+	ErrRetryable = errors.New("retryable job failure")
+	// ErrPermanent marks failures that retrying can't fix, e.g. a malformed payload or a revoked token.
+	ErrPermanent = errors.New("permanent job failure")
+)
+
+// classifiedError is an error marked ErrRetryable or ErrPermanent, keeping its message unchanged.
+type classifiedError struct {
+	err   error
+	class error
+}
+
+func (e *classifiedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *classifiedError) Unwrap() []error {
+	return []error{e.err, e.class}
+}
+
+// Retryable marks err as a job failure that can succeed on a later attempt. Returns nil for a nil error.
+func Retryable(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &classifiedError{err: err, class: ErrRetryable}
+}
+
+// Permanent marks err as a job failure that retrying can't fix. Returns nil for a nil error.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &classifiedError{err: err, class: ErrPermanent}
+}
+
 type User struct {
-	ID                   string               `firestore:"id"`
-	GitHubUsername       string               `firestore:"github_username"`
-	GitHubUserID         int64                `firestore:"github_user_id"` // GitHub numeric ID
-	Verified             bool                 `firestore:"verified"`       // OAuth verification status
-	SlackUserID          string               `firestore:"slack_user_id"`  // Slack user ID
-	SlackTeamID          string               `firestore:"slack_team_id"`
-	SlackDisplayName     string               `firestore:"slack_display_name"` // Slack display name for debugging
-	DefaultChannel       string               `firestore:"default_channel"`
-	NotificationsEnabled bool                 `firestore:"notifications_enabled"`           // Whether to post PRs for this user
-	TaggingEnabled       bool                 `firestore:"tagging_enabled"`                 // Whether to tag user in PR messages
-	ImpersonationEnabled *bool                `firestore:"impersonation_enabled,omitempty"` // Whether to post PRs appearing from the user
-	PRSizeConfig         *PRSizeConfiguration `firestore:"pr_size_config,omitempty"`        // Custom PR size emoji configuration
-	CreatedAt            time.Time            `firestore:"created_at"`
-	UpdatedAt            time.Time            `firestore:"updated_at"`
+	ID                   string                    `firestore:"id"`
+	GitHubUsername       string                    `firestore:"github_username"`
+	GitHubUserID         int64                     `firestore:"github_user_id"` // GitHub numeric ID
+	Verified             bool                      `firestore:"verified"`       // OAuth verification status
+	SlackUserID          string                    `firestore:"slack_user_id"`  // Slack user ID
+	SlackTeamID          string                    `firestore:"slack_team_id"`
+	SlackDisplayName     string                    `firestore:"slack_display_name"`               // Slack display name for debugging
+	DefaultChannel       string                    `firestore:"default_channel"`                  // First of DefaultChannels
+	DefaultChannels      []string                  `firestore:"default_channels,omitempty"`       // Channels the user's PRs are posted to
+	RepoChannels         []RepoChannelOverride     `firestore:"repo_channels,omitempty"`          // Per-repo channels replacing the defaults
+	NotificationsEnabled bool                      `firestore:"notifications_enabled"`            // Whether to post PRs for this user
+	TaggingEnabled       bool                      `firestore:"tagging_enabled"`                  // Whether to tag user in PR messages
+	ImpersonationEnabled *bool                     `firestore:"impersonation_enabled,omitempty"`  // Whether to post PRs appearing from the user
+	PRSizeConfig         *PRSizeConfiguration      `firestore:"pr_size_config,omitempty"`         // Custom PR size emoji configuration
+	AuthorDMs            *AuthorDMPreferences      `firestore:"author_dms,omitempty"`             // Opt-in DMs about events on the user's own PRs
+	ReviewRequests       *ReviewRequestPreferences `firestore:"review_requests,omitempty"`        // Opt-in notifications when the user's review is requested
+	DigestMode           bool                      `firestore:"digest_mode"`                      // Batch CC mentions and author DMs into an hourly digest
+	DailyDigest          *DailyDigestPreferences   `firestore:"daily_digest,omitempty"`           // Opt-in daily DM of open PRs and pending reviews
+	UserTokenPosting     bool                      `firestore:"user_token_posting,omitempty"`     // Post PRs with the user's own Slack token (stored in slack_user_tokens)
+	ReviewCommentThreads *bool                     `firestore:"review_comment_threads,omitempty"` // Whether review comments on the user's PRs are threaded
+	AssignmentDMs        bool                      `firestore:"assignment_dms,omitempty"`         // Opt-in DMs about PR assignments
+	QuietHours           *QuietHoursPreferences    `firestore:"quiet_hours,omitempty"`            // Hours when DMs and mentions are held back
+	OutOfOffice          bool                      `firestore:"out_of_office,omitempty"`          // CCs tag OOODelegate instead
+	OOODelegate          string                    `firestore:"ooo_delegate,omitempty"`           // Slack user ID CC'd in the user's place
+	MutedRepos           []RepoMute                `firestore:"muted_repos,omitempty"`            // Repos the user isn't tagged or DMed about
+	CreatedAt            time.Time                 `firestore:"created_at"`
+	UpdatedAt            time.Time                 `firestore:"updated_at"`
 }
 
 // GetImpersonationEnabled returns the impersonation preference, defaulting to true if not set.
@@ -60,7 +135,337 @@ func (u *User) GetImpersonationEnabled() bool {
 	return *u.ImpersonationEnabled
 }
 
-// PRSizeConfiguration represents a user's custom PR size emoji configuration.
+// MaxDefaultChannels is the most default channels a user can post their PRs to.
+const MaxDefaultChannels = 5
+
+// RepoChannelOverride posts a user's PRs in one repository to its own channels instead of the defaults.
+type RepoChannelOverride struct {
+	RepoFullName string   `firestore:"repo_full_name"` // e.g., "owner/repo"
+	Channels     []string `firestore:"channels"`       // Channel names or IDs
+}
+
+// GetDefaultChannels returns the user's default channels, falling back to DefaultChannel for users
+// who picked a single channel before multiple channels were supported.
+func (u *User) GetDefaultChannels() []string {
+	if len(u.DefaultChannels) > 0 {
+		return u.DefaultChannels
+	}
+	if u.DefaultChannel != "" {
+		return []string{u.DefaultChannel}
+	}
+	return nil
+}
+
+// SetDefaultChannels replaces the user's default channels, keeping DefaultChannel as the first of them.
+func (u *User) SetDefaultChannels(channels []string) {
+	u.DefaultChannels = channels
+	u.DefaultChannel = ""
+	if len(channels) > 0 {
+		u.DefaultChannel = channels[0]
+	}
+}
+
+// ChannelsForRepo returns the channels the user's PRs in a repository are posted to, and whether they come
+// from a per-repository override rather than the defaults. Repository names are matched ignoring case.
+func (u *User) ChannelsForRepo(repoFullName string) ([]string, bool) {
+	for _, override := range u.RepoChannels {
+		if strings.EqualFold(override.RepoFullName, repoFullName) && len(override.Channels) > 0 {
+			return override.Channels, true
+		}
+	}
+	return u.GetDefaultChannels(), false
+}
+
+// RepoMute stops a user being mentioned or DMed about a repository's PRs until it expires.
+type RepoMute struct {
+	RepoFullName string    `firestore:"repo_full_name"` // e.g., "owner/repo"
+	Until        time.Time `firestore:"until"`
+}
+
+// Durations a user can mute a repository for.
+const (
+	RepoMuteDay   = 24 * time.Hour
+	RepoMuteWeek  = 7 * RepoMuteDay
+	RepoMuteMonth = 30 * RepoMuteDay
+)
+
+// RepoMuteDurations are the durations a user can mute a repository for, shortest first.
+var RepoMuteDurations = []time.Duration{RepoMuteDay, RepoMuteWeek, RepoMuteMonth}
+
+// RepoMuted reports whether the user has muted a repository at now. Repository names are matched ignoring case.
+func (u *User) RepoMuted(repoFullName string, now time.Time) bool {
+	if u == nil {
+		return false
+	}
+	for _, mute := range u.MutedRepos {
+		if strings.EqualFold(mute.RepoFullName, repoFullName) && now.Before(mute.Until) {
+			return true
+		}
+	}
+	return false
+}
+
+// MuteRepo mutes a repository for the user until until, replacing any mute of it and dropping expired mutes.
+func (u *User) MuteRepo(repoFullName string, until, now time.Time) {
+	u.UnmuteRepo(repoFullName, now)
+	u.MutedRepos = append(u.MutedRepos, RepoMute{RepoFullName: repoFullName, Until: until})
+}
+
+// UnmuteRepo removes the user's mute of a repository, dropping expired mutes.
+func (u *User) UnmuteRepo(repoFullName string, now time.Time) {
+	u.MutedRepos = slices.DeleteFunc(u.MutedRepos, func(mute RepoMute) bool {
+		return strings.EqualFold(mute.RepoFullName, repoFullName) || !now.Before(mute.Until)
+	})
+}
+
+// ActiveRepoMutes returns the user's mutes that haven't expired at now, soonest to expire first.
+func (u *User) ActiveRepoMutes(now time.Time) []RepoMute {
+	var active []RepoMute
+	for _, mute := range u.MutedRepos {
+		if now.Before(mute.Until) {
+			active = append(active, mute)
+		}
+	}
+	slices.SortFunc(active, func(a, b RepoMute) int {
+		return a.Until.Compare(b.Until)
+	})
+	return active
+}
+
+// GetReviewCommentThreadsEnabled returns whether review comments on the user's PRs may be posted as thread replies,
+// defaulting to true. Channels must also enable review comment threads.
+func (u *User) GetReviewCommentThreadsEnabled() bool {
+	if u == nil || u.ReviewCommentThreads == nil {
+		return true
+	}
+	return *u.ReviewCommentThreads
+}
+
+// Author DM event types a user can opt in to.
+const (
+	AuthorDMEventChangesRequested = "changes_requested"
+	AuthorDMEventCIFailed         = "ci_failed"
+	AuthorDMEventMergeConflict    = "merge_conflict"
+)
+
+// AuthorDMPreferences holds which events on a user's own PRs trigger a direct message.
+type AuthorDMPreferences struct {
+	ChangesRequested bool `firestore:"changes_requested"` // DM when a reviewer requests changes
+	CIFailed         bool `firestore:"ci_failed"`         // DM when a check suite fails
+	MergeConflict    bool `firestore:"merge_conflict"`    // DM when the PR starts conflicting with its base branch
+}
+
+// WantsAuthorDM returns whether the user opted in to a DM for the given author DM event.
+// DMs are off by default.
+func (u *User) WantsAuthorDM(event string) bool {
+	if u == nil || u.AuthorDMs == nil {
+		return false
+	}
+	switch event {
+	case AuthorDMEventChangesRequested:
+		return u.AuthorDMs.ChangesRequested
+	case AuthorDMEventCIFailed:
+		return u.AuthorDMs.CIFailed
+	case AuthorDMEventMergeConflict:
+		return u.AuthorDMs.MergeConflict
+	default:
+		return false
+	}
+}
+
+// Review request notification types a user can opt in to.
+const (
+	ReviewRequestNotifyDM         = "dm"
+	ReviewRequestNotifyThreadNote = "thread_note"
+)
+
+// ReviewRequestPreferences holds how a user is notified when their review is requested on a PR, or the request is removed.
+type ReviewRequestPreferences struct {
+	DM         bool `firestore:"dm"`          // DM the reviewer
+	ThreadNote bool `firestore:"thread_note"` // Note the request in the thread of the tracked PR message
+}
+
+// WantsReviewRequestNotification returns whether the user opted in to the given review request notification type.
+// Notifications are off by default.
+func (u *User) WantsReviewRequestNotification(kind string) bool {
+	if u == nil || u.ReviewRequests == nil {
+		return false
+	}
+	switch kind {
+	case ReviewRequestNotifyDM:
+		return u.ReviewRequests.DM
+	case ReviewRequestNotifyThreadNote:
+		return u.ReviewRequests.ThreadNote
+	default:
+		return false
+	}
+}
+
+// Digest event types. Author DM events (changes requested, CI failed) are also buffered for digest users.
+const (
+	DigestEventCC = "cc"
+	// DigestEventPROpened is a new PR in a digest-only repository, listed in the channel's daily digest.
+	DigestEventPROpened = "pr_opened"
+	// DigestEventReviewRequested is a review request DM, buffered for reviewers in digest mode.
+	DigestEventReviewRequested = "review_requested"
+	// DigestEventAssigned is an assignment DM, buffered for assignees in digest mode.
+	DigestEventAssigned = "assigned"
+)
+
+// DigestEntry is an event concerning a user in digest mode, buffered until the next hourly digest.
+type DigestEntry struct {
+	ID           string    `firestore:"id"`
+	SlackTeamID  string    `firestore:"slack_team_id"`
+	SlackUserID  string    `firestore:"slack_user_id"`
+	SlackChannel string    `firestore:"slack_channel,omitempty"` // Channel for digest-only repository entries
+	Event        string    `firestore:"event"`                   // "cc", "changes_requested", "ci_failed", "review_requested", or "assigned"
+	RepoFullName string    `firestore:"repo_full_name"`
+	PRNumber     int       `firestore:"pr_number"`
+	PRTitle      string    `firestore:"pr_title"`
+	PRURL        string    `firestore:"pr_url"`
+	Actor        string    `firestore:"actor,omitempty"` // Who triggered the event (PR author, reviewer, or CI app)
+	CreatedAt    time.Time `firestore:"created_at"`
+}
+
+const (
+	// DefaultDailyDigestHour is the local hour the daily PR digest is sent when the user hasn't picked one.
+	DefaultDailyDigestHour = 9
+	// hoursPerDay bounds the local hours of the daily digest and quiet hours.
+	hoursPerDay = 24
+)
+
+// IsValidLocalHour reports whether hour is a valid local hour (0-23) for the daily digest or quiet hours.
+func IsValidLocalHour(hour int) bool {
+	return hour >= 0 && hour < hoursPerDay
+}
+
+// DailyDigestPreferences configures the daily DM summarizing a user's open PRs and pending reviews.
+type DailyDigestPreferences struct {
+	Enabled      bool   `firestore:"enabled"`
+	Hour         int    `firestore:"hour"`                     // Local hour (0-23) to send the digest
+	Timezone     string `firestore:"timezone,omitempty"`       // IANA timezone, e.g. "Europe/London"; UTC when empty
+	LastSentDate string `firestore:"last_sent_date,omitempty"` // Local date (YYYY-MM-DD) of the last digest sent
+}
+
+// Location returns the digest's timezone, falling back to UTC for empty or unknown timezones.
+func (p *DailyDigestPreferences) Location() *time.Location {
+	return loadLocationOrUTC(p.Timezone)
+}
+
+// loadLocationOrUTC loads an IANA timezone, falling back to UTC when it's empty or unknown.
+func loadLocationOrUTC(timezone string) *time.Location {
+	if timezone == "" {
+		return time.UTC
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// LocalDate returns the date at now in the digest's timezone, as YYYY-MM-DD.
+func (p *DailyDigestPreferences) LocalDate(now time.Time) string {
+	return now.In(p.Location()).Format(time.DateOnly)
+}
+
+// IsDue reports whether the digest should be sent at now: it's enabled, the configured local hour has
+// been reached, and it hasn't been sent yet today. Checking "reached" rather than an exact hour means
+// a missed scheduler run is caught up by the next one.
+func (p *DailyDigestPreferences) IsDue(now time.Time) bool {
+	if p == nil || !p.Enabled {
+		return false
+	}
+	return now.In(p.Location()).Hour() >= p.Hour && p.LastSentDate != p.LocalDate(now)
+}
+
+// Default quiet hours, used when a user first turns them on.
+const (
+	DefaultQuietHoursStart = 18
+	DefaultQuietHoursEnd   = 9
+)
+
+// QuietHoursPreferences configures the hours a user doesn't want to be DMed or mentioned.
+// DMs and thread notes mentioning the user during quiet hours are delivered when they end.
+type QuietHoursPreferences struct {
+	Enabled   bool   `firestore:"enabled"`
+	StartHour int    `firestore:"start_hour"`         // Local hour (0-23) quiet hours start
+	EndHour   int    `firestore:"end_hour"`           // Local hour (0-23) quiet hours end; before StartHour for overnight hours
+	Timezone  string `firestore:"timezone,omitempty"` // IANA timezone, e.g. "Europe/London"; UTC when empty
+}
+
+// Location returns the quiet hours' timezone, falling back to UTC for empty or unknown timezones.
+func (p *QuietHoursPreferences) Location() *time.Location {
+	return loadLocationOrUTC(p.Timezone)
+}
+
+// EndsAt returns when the quiet hours in progress at now end, or false if now isn't in quiet hours.
+// Equal start and end hours are an empty window, so never quiet.
+func (p *QuietHoursPreferences) EndsAt(now time.Time) (time.Time, bool) {
+	if p == nil || !p.Enabled || p.StartHour == p.EndHour {
+		return time.Time{}, false
+	}
+
+	local := now.In(p.Location())
+	hour := local.Hour()
+	quiet := hour >= p.StartHour && hour < p.EndHour
+	if p.StartHour > p.EndHour {
+		quiet = hour >= p.StartHour || hour < p.EndHour
+	}
+	if !quiet {
+		return time.Time{}, false
+	}
+
+	end := time.Date(local.Year(), local.Month(), local.Day(), p.EndHour, 0, 0, 0, local.Location())
+	if !end.After(local) {
+		end = time.Date(local.Year(), local.Month(), local.Day()+1, p.EndHour, 0, 0, 0, local.Location())
+	}
+	return end, true
+}
+
+// PendingReview is an outstanding review request for a connected user, listed in their daily digest.
+// It's removed when the request is removed or the reviewer submits a review.
+type PendingReview struct {
+	ID               string    `firestore:"id"` // {reviewer_github_id}#{encoded_repo}#{pr_number}
+	ReviewerGitHubID int64     `firestore:"reviewer_github_id"`
+	RepoFullName     string    `firestore:"repo_full_name"`
+	PRNumber         int       `firestore:"pr_number"`
+	PRTitle          string    `firestore:"pr_title"`
+	PRURL            string    `firestore:"pr_url"`
+	RequestedBy      string    `firestore:"requested_by"`
+	CreatedAt        time.Time `firestore:"created_at"`
+}
+
+// DailyDigest is the content of a user's daily PR digest.
+type DailyDigest struct {
+	OpenPRs        []DailyDigestPR // The user's own open PRs
+	PendingReviews []DailyDigestPR // Open PRs waiting on the user's review
+}
+
+// DailyDigestPR is a PR listed in a daily digest.
+type DailyDigestPR struct {
+	RepoFullName string
+	PRNumber     int
+	Title        string
+	URL          string
+	Since        time.Time // When the PR was posted, or the review was requested
+	RequestedBy  string    // Who requested the review, for pending reviews
+}
+
+// IsEmpty returns whether the digest has nothing to list.
+func (d *DailyDigest) IsEmpty() bool {
+	return len(d.OpenPRs) == 0 && len(d.PendingReviews) == 0
+}
+
+// ActivePR is one of a user's open PRs listed in the App Home "Active PRs" section.
+type ActivePR struct {
+	Message   *TrackedMessage // The PR's first bot message
+	Channels  []string        // Channels the PR was posted to
+	Permalink string          // Link to the first message; empty if it couldn't be fetched
+}
+
+// PRSizeConfiguration represents a custom PR size emoji configuration, set by a user, a channel or a workspace.
+// See the prsize package for which one applies to a message.
 type PRSizeConfiguration struct {
 	Enabled    bool              `firestore:"enabled"`    // Whether to use custom configuration
 	Thresholds []PRSizeThreshold `firestore:"thresholds"` // Custom size thresholds and emojis
@@ -103,6 +508,9 @@ type OAuthState struct {
 	ReturnToHome bool      `firestore:"return_to_home"` // Whether to refresh App Home after OAuth
 	CreatedAt    time.Time `firestore:"created_at"`     // When state was created
 	ExpiresAt    time.Time `firestore:"expires_at"`     // When state expires (15 minutes)
+
+	// GitHub Enterprise Server host to authorize with; empty for github.com
+	GitHubHost string `firestore:"github_host,omitempty"`
 }
 
 // SlackWorkspace represents a Slack workspace installation with OAuth tokens.
@@ -117,6 +525,160 @@ type SlackWorkspace struct {
 	AppID        string    `firestore:"app_id"`                  // Slack app ID from installation
 	BotUserID    string    `firestore:"bot_user_id"`             // Bot user ID in workspace
 	EnterpriseID string    `firestore:"enterprise_id,omitempty"` // Enterprise Grid ID
+	Timezone     string    `firestore:"timezone,omitempty"`      // IANA timezone for digests, countdowns and dates; UTC when empty
+	Locale       string    `firestore:"locale,omitempty"`        // Slack locale for date formatting, e.g. "en-GB"; en-US when empty
+
+	// Whether the workspace is covered by an org-wide install of EnterpriseID, so its token is the organization's
+	IsEnterpriseInstall bool `firestore:"is_enterprise_install,omitempty"`
+
+	ReactionEmoji *WorkspaceEmoji `firestore:"reaction_emoji,omitempty"` // Review state reaction overrides; env defaults when nil
+	LinkInvites   string          `firestore:"link_invites,omitempty"`   // How to invite unlinked CC'd users to link; off when empty
+
+	// Whether thread replies to PR messages starting with "!to-github" are posted as comments on the PR
+	SlackToGitHubComments bool `firestore:"slack_to_github_comments,omitempty"`
+
+	// PR message layout as a text/template, e.g. "{{.Emoji}} {{.Link}} by {{.Author}}"; the default layout when empty
+	MessageTemplate string `firestore:"message_template,omitempty"`
+
+	// PR size emoji for channels and authors that haven't set their own; nil uses the default animal emoji
+	PRSizeConfig *PRSizeConfiguration `firestore:"pr_size_config,omitempty"`
+
+	// When Slack stopped accepting the bot token, e.g. because the app was uninstalled; nil while it works.
+	// Nothing is posted to a revoked workspace until the app is reinstalled there, which clears it.
+	RevokedAt     *time.Time `firestore:"revoked_at,omitempty"`
+	RevokedReason string     `firestore:"revoked_reason,omitempty"` // Slack error that revoked it, e.g. "account_inactive"
+}
+
+// Link invite modes for SlackWorkspace.LinkInvites.
+const (
+	LinkInvitesOff       = "off"       // Don't invite unlinked users (default)
+	LinkInvitesDM        = "dm"        // DM the Slack user whose name matches the CC'd GitHub username
+	LinkInvitesEphemeral = "ephemeral" // Show the matching Slack user a private prompt in the PR's channel
+)
+
+// IsValidLinkInvitesMode checks if a link invite mode is supported.
+func IsValidLinkInvitesMode(mode string) bool {
+	return mode == LinkInvitesOff || mode == LinkInvitesDM || mode == LinkInvitesEphemeral
+}
+
+// WorkspaceEmoji overrides the reaction emoji for PR review states in a workspace, as emoji names without colons.
+// Empty fields fall back to the EMOJI_* environment defaults.
+type WorkspaceEmoji struct {
+	Approved         string    `firestore:"approved,omitempty"`
+	ChangesRequested string    `firestore:"changes_requested,omitempty"`
+	Commented        string    `firestore:"commented,omitempty"`
+	Merged           string    `firestore:"merged,omitempty"`
+	Closed           string    `firestore:"closed,omitempty"`
+	UpdatedBy        string    `firestore:"updated_by,omitempty"` // Slack user ID of the admin who last saved them
+	UpdatedAt        time.Time `firestore:"updated_at"`
+}
+
+// WorkspaceEmojiStates are the states whose reaction emoji a workspace can override, in display order.
+var WorkspaceEmojiStates = []string{"approved", "changes_requested", "commented", "merged", "closed"}
+
+// Field returns the emoji for one of WorkspaceEmojiStates, or nil for any other state.
+func (we *WorkspaceEmoji) Field(state string) *string {
+	switch state {
+	case "approved":
+		return &we.Approved
+	case "changes_requested":
+		return &we.ChangesRequested
+	case "commented":
+		return &we.Commented
+	case "merged":
+		return &we.Merged
+	case "closed":
+		return &we.Closed
+	default:
+		return nil
+	}
+}
+
+// IsEmpty returns whether no reaction emoji are overridden.
+func (we *WorkspaceEmoji) IsEmpty() bool {
+	return we.Approved == "" && we.ChangesRequested == "" && we.Commented == "" && we.Merged == "" && we.Closed == ""
+}
+
+// DefaultWorkspaceLocale is the locale used for date formatting when a workspace has none set.
+const DefaultWorkspaceLocale = "en-US"
+
+// WorkspaceLocales are the locales offered for a workspace's date formatting, with their display names.
+var WorkspaceLocales = []struct {
+	Code string
+	Name string
+}{
+	{"en-US", "English (US)"},
+	{"en-GB", "English (UK)"},
+	{"de-DE", "Deutsch"},
+	{"es-ES", "Español"},
+	{"fr-FR", "Français"},
+	{"pt-BR", "Português (Brasil)"},
+	{"ja-JP", "日本語"},
+	{"ko-KR", "한국어"},
+	{"zh-CN", "简体中文"},
+	{"zh-TW", "繁體中文"},
+}
+
+// IsValidWorkspaceLocale reports whether a locale is one of the offered WorkspaceLocales.
+func IsValidWorkspaceLocale(locale string) bool {
+	for _, choice := range WorkspaceLocales {
+		if choice.Code == locale {
+			return true
+		}
+	}
+	return false
+}
+
+// Location returns the workspace's timezone, falling back to UTC for empty or unknown timezones.
+func (sw *SlackWorkspace) Location() *time.Location {
+	return loadLocationOrUTC(sw.Timezone)
+}
+
+// GetLocale returns the workspace's locale, or DefaultWorkspaceLocale when none is set.
+func (sw *SlackWorkspace) GetLocale() string {
+	if sw.Locale == "" {
+		return DefaultWorkspaceLocale
+	}
+	return sw.Locale
+}
+
+// GetLinkInvites returns how the workspace invites unlinked CC'd users to link their GitHub account.
+func (sw *SlackWorkspace) GetLinkInvites() string {
+	if sw == nil || sw.LinkInvites == "" {
+		return LinkInvitesOff
+	}
+	return sw.LinkInvites
+}
+
+// IsRevoked reports whether Slack has revoked the workspace's bot token, so it can't be posted to.
+func (sw *SlackWorkspace) IsRevoked() bool {
+	return sw != nil && sw.RevokedAt != nil
+}
+
+// SlackUserToken is a Slack user token a user granted so PR notifications can be posted as them.
+// Stored apart from User so the token is only read when posting or editing such messages.
+type SlackUserToken struct {
+	ID          string    `firestore:"id"`            // {team_id}#{user_id}
+	SlackUserID string    `firestore:"slack_user_id"` // Slack user who granted the token
+	SlackTeamID string    `firestore:"slack_team_id"` // Slack workspace the token belongs to
+	AccessToken string    `firestore:"access_token"`  // Slack user OAuth token (xoxp-)
+	Scope       string    `firestore:"scope"`         // Granted user scopes
+	CreatedAt   time.Time `firestore:"created_at"`
+}
+
+// GitHubUserToken is a user's GitHub App user access token, stored when they link their GitHub account so
+// reactions on PR messages can act on GitHub as them. Tokens are encrypted at rest.
+type GitHubUserToken struct {
+	ID               string     `firestore:"id"`                           // {team_id}#{user_id}
+	SlackUserID      string     `firestore:"slack_user_id"`                // Slack user who linked their GitHub account
+	SlackTeamID      string     `firestore:"slack_team_id"`                // Slack workspace the token belongs to
+	GitHubUserID     int64      `firestore:"github_user_id"`               // GitHub account the token acts as
+	GitHubHost       string     `firestore:"github_host,omitempty"`        // GitHub Enterprise Server host; empty for github.com
+	AccessToken      string     `firestore:"access_token"`                 // User access token (ghu_)
+	RefreshToken     string     `firestore:"refresh_token,omitempty"`      // Refreshes the access token; empty if tokens don't expire
+	ExpiresAt        *time.Time `firestore:"expires_at,omitempty"`         // When the access token expires; nil if it doesn't
+	RefreshExpiresAt *time.Time `firestore:"refresh_expires_at,omitempty"` // When the refresh token expires
+	CreatedAt        time.Time  `firestore:"created_at"`
 }
 
 // Validate validates required fields for SlackWorkspace.
@@ -133,6 +695,51 @@ func (sw *SlackWorkspace) Validate() error {
 	return nil
 }
 
+// SlackEnterpriseInstallation represents an org-wide installation of the app in an Enterprise Grid organization.
+// One bot token covers every workspace in the organization the app is granted to; each of those gets its own
+// SlackWorkspace, sharing this token, so settings and lookups stay keyed by team ID.
+type SlackEnterpriseInstallation struct {
+	ID             string    `firestore:"id"`              // Enterprise Grid ID (primary key)
+	EnterpriseName string    `firestore:"enterprise_name"` // Organization name
+	AccessToken    string    `firestore:"access_token"`    // Org-level bot token
+	Scope          string    `firestore:"scope"`           // Granted scopes
+	InstalledBy    string    `firestore:"installed_by"`    // Slack user ID who installed the app
+	InstalledAt    time.Time `firestore:"installed_at"`    // Installation timestamp
+	UpdatedAt      time.Time `firestore:"updated_at"`      // Last update timestamp
+	AppID          string    `firestore:"app_id"`          // Slack app ID from installation
+	BotUserID      string    `firestore:"bot_user_id"`     // Bot user ID in the organization
+}
+
+// Validate validates required fields for SlackEnterpriseInstallation.
+func (sei *SlackEnterpriseInstallation) Validate() error {
+	if sei.ID == "" {
+		return ErrEnterpriseIDRequired
+	}
+	if sei.AccessToken == "" {
+		return ErrAccessTokenRequired
+	}
+	return nil
+}
+
+// WorkspaceFor returns the installation record for a workspace the org-wide install covers.
+func (sei *SlackEnterpriseInstallation) WorkspaceFor(teamID, teamName string) *SlackWorkspace {
+	if teamName == "" {
+		teamName = teamID
+	}
+	return &SlackWorkspace{
+		ID:                  teamID,
+		TeamName:            teamName,
+		AccessToken:         sei.AccessToken,
+		Scope:               sei.Scope,
+		InstalledBy:         sei.InstalledBy,
+		InstalledAt:         time.Now(),
+		AppID:               sei.AppID,
+		BotUserID:           sei.BotUserID,
+		EnterpriseID:        sei.ID,
+		IsEnterpriseInstall: true,
+	}
+}
+
 // GitHubInstallation represents a GitHub App installation.
 type GitHubInstallation struct {
 	ID                  int64     `firestore:"id"`                     // GitHub installation ID
@@ -149,11 +756,50 @@ type GitHubInstallation struct {
 	InstalledBySlackUser  string `firestore:"installed_by_slack_user,omitempty"`  // Slack user ID who installed it
 	InstalledByGitHubUser int64  `firestore:"installed_by_github_user,omitempty"` // GitHub user ID who installed it
 
+	Defaults *InstallationDefaults `firestore:"defaults,omitempty"` // Settings auto-registered repositories inherit
+
+	// API base URL of the GitHub Enterprise Server instance the app is installed on, e.g.
+	// "https://github.example.com/api/v3/"; empty for github.com
+	APIBaseURL string `firestore:"api_base_url,omitempty"`
+
 	// Fields below reserved for future implementation
 	SuspendedAt *time.Time `firestore:"suspended_at,omitempty"`
 	SuspendedBy string     `firestore:"suspended_by,omitempty"`
 }
 
+// InstallationDefaults are the notification settings that repositories auto-registered from an installation
+// start with, so admins can configure an organization once instead of each repository.
+// Settings edited on a repository afterwards take precedence; empty fields leave the workspace-wide behaviour.
+type InstallationDefaults struct {
+	DefaultChannel   string    `firestore:"default_channel,omitempty"`   // Channel ID for PRs, ahead of authors' default channels
+	NotificationMode string    `firestore:"notification_mode,omitempty"` // Repository notification mode
+	Emoji            string    `firestore:"emoji,omitempty"`             // Emoji shown on PR messages instead of the size emoji
+	UpdatedBy        string    `firestore:"updated_by,omitempty"`        // Slack user ID of the admin who last saved them
+	UpdatedAt        time.Time `firestore:"updated_at"`
+}
+
+// IsEmpty reports whether no default is set.
+func (d *InstallationDefaults) IsEmpty() bool {
+	return d == nil || (d.DefaultChannel == "" && d.NotificationMode == "" && d.Emoji == "")
+}
+
+// GitHubEnterpriseAPIBaseURL returns the REST API base URL of the GitHub Enterprise Server instance at host,
+// or "" for github.com.
+func GitHubEnterpriseAPIBaseURL(host string) string {
+	if host == "" {
+		return ""
+	}
+	return "https://" + host + "/api/v3/"
+}
+
+// GitHubHost returns the hostname of the GitHub Enterprise Server instance the app is installed on,
+// or "" for github.com.
+func (gi *GitHubInstallation) GitHubHost() string {
+	host := strings.TrimPrefix(gi.APIBaseURL, "https://")
+	host, _, _ = strings.Cut(host, "/")
+	return host
+}
+
 // Validate validates required fields for GitHubInstallation.
 func (gi *GitHubInstallation) Validate() error {
 	if gi.ID <= 0 {
@@ -177,8 +823,9 @@ func (gi *GitHubInstallation) Validate() error {
 // TrackedMessage represents a tracked PR message in Slack (replaces old Message model).
 type TrackedMessage struct {
 	ID                 string    `firestore:"id"`                             // Auto-generated document ID
-	PRNumber           int       `firestore:"pr_number"`                      // GitHub PR number
+	PRNumber           int       `firestore:"pr_number"`                      // GitHub PR number, or issue number for issue links
 	RepoFullName       string    `firestore:"repo_full_name"`                 // e.g., "owner/repo"
+	ItemType           string    `firestore:"item_type,omitempty"`            // TrackedItemTypePR (default) or TrackedItemTypeIssue
 	PRTitle            string    `firestore:"pr_title,omitempty"`             // PR title when message was created/updated
 	SlackChannel       string    `firestore:"slack_channel"`                  // Slack channel ID
 	SlackChannelName   string    `firestore:"slack_channel_name,omitempty"`   // Channel name for logging (optional)
@@ -189,15 +836,204 @@ type TrackedMessage struct {
 	UsersToCC          []string  `firestore:"users_to_cc,omitempty"`          // GitHub usernames mentioned in CC directives
 	HasReviewDirective *bool     `firestore:"has_review_directive,omitempty"` // Whether message had directive
 	DeletedByUser      bool      `firestore:"deleted_by_user,omitempty"`      // Whether user deleted this message
+	SupersedesPRs      []int     `firestore:"supersedes_prs,omitempty"`       // PR numbers this PR replaces ("Replaces #N")
+	SupersededByPR     int       `firestore:"superseded_by_pr,omitempty"`     // PR number that replaced this PR
 	CreatedAt          time.Time `firestore:"created_at"`                     // When we started tracking this message
+
+	Dependencies        []PRDependency `firestore:"dependencies,omitempty"`          // PRs this PR depends on ("Depends on org/repo#12")
+	DependencyKeys      []string       `firestore:"dependency_keys,omitempty"`       // Dependency keys for array-contains lookups
+	HasOpenDependencies bool           `firestore:"has_open_dependencies,omitempty"` // Whether any dependency is still open
+
+	ProjectColumn string `firestore:"project_column,omitempty"` // Project board Status column, e.g. "In Review"
+	Compact       bool   `firestore:"compact,omitempty"`        // Posted for a compact mode repo: one line, no reactions
+	MergeConflict bool   `firestore:"merge_conflict,omitempty"` // Whether the PR was last seen conflicting with its base branch
+
+	NoReviewersWarning bool `firestore:"no_reviewers_warning,omitempty"` // Shows "No reviewers requested" until a review is requested
+
+	CIState           CIState `firestore:"ci_state,omitempty"`           // CI state of the PR's head commit when last synced
+	Approvals         int     `firestore:"approvals,omitempty"`          // Number of approving reviewers when last synced
+	RequiredApprovals int     `firestore:"required_approvals,omitempty"` // Approvals the PR needed when last synced, for the quorum
+	Presentation      string  `firestore:"presentation,omitempty"`       // Presentation the message is rendered with, e.g. "collapsed"
+
+	PostedAsUserID string `firestore:"posted_as_user_id,omitempty"` // Slack user whose token posted the message; edits must use that token
+	MessageFormat  string `firestore:"message_format,omitempty"`    // Layout the message was posted with, e.g. "rich"; text when empty
+
+	PRLabels    []string `firestore:"pr_labels,omitempty"`    // Names of the PR's labels when the message was created/updated
+	PRMilestone string   `firestore:"pr_milestone,omitempty"` // PR's milestone title when the message was created/updated
+
+	LinkClicks     int64      `firestore:"link_clicks,omitempty"`      // Clicks on the message's "Open PR" button
+	FirstClickedAt *time.Time `firestore:"first_clicked_at,omitempty"` // When the "Open PR" button was first clicked
+
+	MovedBy string `firestore:"moved_by,omitempty"` // Slack user who moved the message to its channel with the message shortcut
+
+	ClosedAt *time.Time `firestore:"closed_at,omitempty"` // When the PR was merged or closed; cleared when it's reopened
+}
+
+// Tracked item types for TrackedMessage.ItemType.
+const (
+	TrackedItemTypePR    = "pr"
+	TrackedItemTypeIssue = "issue"
+)
+
+// IsIssue reports whether the message links a GitHub issue rather than a pull request.
+// Issues share their repository's number sequence with PRs, so the number alone identifies the item.
+func (tm *TrackedMessage) IsIssue() bool {
+	return tm.ItemType == TrackedItemTypeIssue
+}
+
+// PR dependency states.
+const (
+	PRDependencyStateOpen   = "open"
+	PRDependencyStateMerged = "merged"
+	PRDependencyStateClosed = "closed"
+)
+
+// PRDependency is a pull request that another PR depends on, possibly in a different repository.
+type PRDependency struct {
+	RepoFullName string `firestore:"repo_full_name"`
+	PRNumber     int    `firestore:"pr_number"`
+	State        string `firestore:"state,omitempty"` // "open", "merged", "closed", or empty if unknown
+}
+
+// Key returns the "owner/repo#N" key identifying the dependency.
+func (d PRDependency) Key() string {
+	return fmt.Sprintf("%s#%d", d.RepoFullName, d.PRNumber)
+}
+
+// URL returns the GitHub URL of the dependency PR.
+func (d PRDependency) URL() string {
+	return fmt.Sprintf("https://github.com/%s/pull/%d", d.RepoFullName, d.PRNumber)
+}
+
+// IsResolved reports whether the dependency no longer blocks (merged or closed).
+func (d PRDependency) IsResolved() bool {
+	return d.State == PRDependencyStateMerged || d.State == PRDependencyStateClosed
 }
 
 type Repo struct {
 	ID           string    `firestore:"id"`             // {workspace_id}#{repo_full_name} (for backward compatibility)
 	RepoFullName string    `firestore:"repo_full_name"` // e.g., "owner/repo" (denormalized for queries)
 	WorkspaceID  string    `firestore:"workspace_id"`   // Slack team ID (denormalized for queries)
-	Enabled      bool      `firestore:"enabled"`        // Used in GetReposForAllWorkspaces() query (no UI to disable yet)
+	Enabled      bool      `firestore:"enabled"`        // Disabled repos get no notifications; set from the App Home
 	CreatedAt    time.Time `firestore:"created_at"`
+
+	DefaultChannel string `firestore:"default_channel,omitempty"` // Channel ID for PRs, ahead of authors' default channels
+
+	ReleaseNotes     *ReleaseNotesConfig `firestore:"release_notes,omitempty"`     // Opt-in draft release notes posting
+	NotificationMode string              `firestore:"notification_mode,omitempty"` // "full" (default), "compact", or "digest_only"
+	MechanicalPRs    *MechanicalPRConfig `firestore:"mechanical_prs,omitempty"`    // Handling of revert and back-merge PRs
+	RoutingRules     []RoutingRule       `firestore:"routing_rules,omitempty"`     // Channel routing by changed paths or labels, in order
+	CodeOwnersCC     bool                `firestore:"codeowners_cc,omitempty"`     // CC the owners of changed files from CODEOWNERS
+	Emoji            string              `firestore:"emoji,omitempty"`             // Emoji shown on PR messages instead of the size emoji
+}
+
+// InheritDefaults fills the repository's unset settings from its installation's defaults.
+func (r *Repo) InheritDefaults(defaults *InstallationDefaults) {
+	if defaults == nil {
+		return
+	}
+	if r.DefaultChannel == "" {
+		r.DefaultChannel = defaults.DefaultChannel
+	}
+	if r.NotificationMode == "" {
+		r.NotificationMode = defaults.NotificationMode
+	}
+	if r.Emoji == "" {
+		r.Emoji = defaults.Emoji
+	}
+}
+
+// Repository notification modes for Repo.NotificationMode.
+const (
+	NotificationModeFull       = "full"        // Regular PR messages with reactions (default)
+	NotificationModeCompact    = "compact"     // One-line PR messages without reactions
+	NotificationModeDigestOnly = "digest_only" // No PR messages; new PRs appear in the channel's daily digest
+)
+
+// IsValidNotificationMode reports whether mode is a known repository notification mode.
+func IsValidNotificationMode(mode string) bool {
+	switch mode {
+	case NotificationModeFull, NotificationModeCompact, NotificationModeDigestOnly:
+		return true
+	default:
+		return false
+	}
+}
+
+// GetNotificationMode returns the repository's notification mode, defaulting to full.
+func (r *Repo) GetNotificationMode() string {
+	if r == nil || !IsValidNotificationMode(r.NotificationMode) {
+		return NotificationModeFull
+	}
+	return r.NotificationMode
+}
+
+// Handling modes for MechanicalPRConfig.Handling.
+const (
+	MechanicalPRHandlingSkip    = "skip"    // Don't announce the PR
+	MechanicalPRHandlingCompact = "compact" // Announce as a one-line message without reactions
+	MechanicalPRHandlingChannel = "channel" // Announce in MechanicalPRConfig.Channel instead of the usual channel
+)
+
+// MechanicalPRConfig controls how routine mechanical PRs (reverts and automated back-merges) are announced.
+// A nil config announces them like any other PR.
+type MechanicalPRConfig struct {
+	Handling string `firestore:"handling"          json:"handling"`
+	Channel  string `firestore:"channel,omitempty" json:"channel,omitempty"` // Channel name or ID for the "channel" handling
+}
+
+// IsValid reports whether the config has a known handling, with a channel when routing to one.
+func (c *MechanicalPRConfig) IsValid() bool {
+	switch c.Handling {
+	case MechanicalPRHandlingSkip, MechanicalPRHandlingCompact:
+		return true
+	case MechanicalPRHandlingChannel:
+		return strings.TrimPrefix(c.Channel, "#") != ""
+	default:
+		return false
+	}
+}
+
+// RoutingRule routes a repository's PRs to a channel when they change matching files or carry a label.
+// Rules apply in order, after PR directives and before the author's default channel.
+type RoutingRule struct {
+	Paths   []string `firestore:"paths,omitempty" json:"paths,omitempty"` // Globs matched against changed file paths; ** spans directories
+	Label   string   `firestore:"label,omitempty" json:"label,omitempty"` // PR label, matched case-insensitively
+	Channel string   `firestore:"channel"         json:"channel"`         // Slack channel name or ID
+}
+
+// IsValid reports whether the rule has a channel and exactly one of paths or a label to match.
+func (r *RoutingRule) IsValid() bool {
+	if strings.TrimPrefix(r.Channel, "#") == "" {
+		return false
+	}
+	return (len(r.Paths) > 0) != (r.Label != "")
+}
+
+// DefaultReleaseTagPattern matches semver-style release tags when no pattern is configured.
+const DefaultReleaseTagPattern = "v*"
+
+// ReleaseNotesConfig controls posting generated draft release notes when a release tag is pushed.
+type ReleaseNotesConfig struct {
+	Enabled      bool   `firestore:"enabled"`
+	SlackChannel string `firestore:"slack_channel"`         // Channel ID that receives draft release notes
+	TagPattern   string `firestore:"tag_pattern,omitempty"` // Glob pattern for release tags (e.g. "v*")
+}
+
+// MatchesTag reports whether release notes should be posted for the given tag.
+// Returns false if the config is nil, disabled, or has no channel configured.
+func (c *ReleaseNotesConfig) MatchesTag(tag string) bool {
+	if c == nil || !c.Enabled || c.SlackChannel == "" {
+		return false
+	}
+
+	pattern := c.TagPattern
+	if pattern == "" {
+		pattern = DefaultReleaseTagPattern
+	}
+
+	matched, err := path.Match(pattern, tag)
+	return err == nil && matched
 }
 
 type WebhookJob struct {
@@ -211,17 +1047,117 @@ type WebhookJob struct {
 	Status      string     `firestore:"status"                 json:"status"`
 	RetryCount  int        `firestore:"retry_count"            json:"retry_count"`
 	LastError   string     `firestore:"last_error,omitempty"   json:"last_error,omitempty"`
+	Replay      bool       `firestore:"replay,omitempty"       json:"replay,omitempty"` // Re-injected from the webhook event log
+	// GitHub Enterprise Server host the webhook came from, from X-GitHub-Enterprise-Host; empty for github.com
+	GitHubHost string `firestore:"github_host,omitempty" json:"github_host,omitempty"`
 }
 
-// ManualLinkJob represents a job to process manually detected PR links.
-type ManualLinkJob struct {
-	ID             string `json:"id"`
-	PRNumber       int    `json:"pr_number"`
-	RepoFullName   string `json:"repo_full_name"`
-	SlackChannel   string `json:"slack_channel"`
-	SlackMessageTS string `json:"slack_message_ts"`
-	SlackTeamID    string `json:"slack_team_id"` // Slack workspace/team ID
-	TraceID        string `json:"trace_id"`
+// WebhookEvent is a received GitHub webhook kept for debugging and replay, keyed on its X-GitHub-Delivery ID.
+// The payload is stored gzip-compressed. Documents are deleted after ExpiresAt by a Firestore TTL policy.
+type WebhookEvent struct {
+	ID          string            `firestore:"id"` // X-GitHub-Delivery ID
+	EventType   string            `firestore:"event_type"`
+	Headers     map[string]string `firestore:"headers"`
+	Payload     []byte            `firestore:"payload"`      // gzip-compressed
+	PayloadSize int               `firestore:"payload_size"` // Uncompressed size in bytes
+	ReceivedAt  time.Time         `firestore:"received_at"`
+	ExpiresAt   time.Time         `firestore:"expires_at"`
+}
+
+// SetPayload compresses and stores the webhook's payload.
+func (e *WebhookEvent) SetPayload(payload []byte) error {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(payload); err != nil {
+		return fmt.Errorf("failed to compress webhook payload: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to compress webhook payload: %w", err)
+	}
+
+	e.Payload = buf.Bytes()
+	e.PayloadSize = len(payload)
+	return nil
+}
+
+// DecodePayload returns the webhook's payload as GitHub sent it.
+func (e *WebhookEvent) DecodePayload() ([]byte, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(e.Payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress webhook payload: %w", err)
+	}
+	defer func() { _ = zr.Close() }()
+
+	payload, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress webhook payload: %w", err)
+	}
+	return payload, nil
+}
+
+// Kinds of notification in the notification log.
+const (
+	NotificationKindMessage   = "message"   // Message posted to a channel, including PR messages and thread replies
+	NotificationKindDM        = "dm"        // Message sent to a user by DM
+	NotificationKindEphemeral = "ephemeral" // Message only one user in a channel sees
+	NotificationKindReaction  = "reaction"  // Reaction added to a message
+)
+
+// Outcomes of notifications in the notification log.
+const (
+	NotificationOutcomeSent   = "sent"
+	NotificationOutcomeFailed = "failed"
+)
+
+// NotificationLogEntry records a Slack message, DM or reaction the bot sent, or failed to send, for support
+// investigations. The PR and job come from the context it was sent in, so either can be missing.
+// Documents are deleted after ExpiresAt by a Firestore TTL policy.
+type NotificationLogEntry struct {
+	ID             string    `firestore:"id"                         json:"id"`
+	Kind           string    `firestore:"kind"                       json:"kind"` // One of the NotificationKind constants
+	SlackTeamID    string    `firestore:"slack_team_id"              json:"slack_team_id"`
+	SlackChannel   string    `firestore:"slack_channel"              json:"slack_channel"` // Channel ID, or user ID for DMs
+	SlackMessageTS string    `firestore:"slack_message_ts,omitempty" json:"slack_message_ts,omitempty"`
+	SlackUserID    string    `firestore:"slack_user_id,omitempty"    json:"slack_user_id,omitempty"` // Recipient of an ephemeral message
+	Emoji          string    `firestore:"emoji,omitempty"            json:"emoji,omitempty"`         // Reactions only
+	RepoFullName   string    `firestore:"repo_full_name,omitempty"   json:"repo_full_name,omitempty"`
+	PRNumber       int       `firestore:"pr_number,omitempty"        json:"pr_number,omitempty"`
+	JobID          string    `firestore:"job_id,omitempty"           json:"job_id,omitempty"`
+	JobType        string    `firestore:"job_type,omitempty"         json:"job_type,omitempty"`
+	TraceID        string    `firestore:"trace_id,omitempty"         json:"trace_id,omitempty"`
+	Outcome        string    `firestore:"outcome"                    json:"outcome"` // One of the NotificationOutcome constants
+	Error          string    `firestore:"error,omitempty"            json:"error,omitempty"`
+	SentAt         time.Time `firestore:"sent_at"                    json:"sent_at"`
+	ExpiresAt      time.Time `firestore:"expires_at"                 json:"-"`
+}
+
+// WebhookDelivery records how far a GitHub webhook delivery got, keyed on its X-GitHub-Delivery ID, so
+// redeliveries of the same event after a partial failure don't post twice.
+// Documents are deleted after ExpiresAt by a Firestore TTL policy.
+type WebhookDelivery struct {
+	ID                  string    `firestore:"id"` // X-GitHub-Delivery ID
+	EventType           string    `firestore:"event_type"`
+	Processed           bool      `firestore:"processed,omitempty"`            // Whether the webhook job succeeded
+	CompletedWorkspaces []string  `firestore:"completed_workspaces,omitempty"` // Workspaces whose PR job succeeded
+	UpdatedAt           time.Time `firestore:"updated_at"`
+	ExpiresAt           time.Time `firestore:"expires_at"`
+}
+
+// WorkspaceCompleted reports whether the delivery's PR job already succeeded for a workspace.
+func (d *WebhookDelivery) WorkspaceCompleted(workspaceID string) bool {
+	return d != nil && slices.Contains(d.CompletedWorkspaces, workspaceID)
+}
+
+// ManualLinkJob represents a job to process manually detected PR or issue links.
+type ManualLinkJob struct {
+	ID             string `json:"id"`
+	PRNumber       int    `json:"pr_number"` // PR or issue number
+	RepoFullName   string `json:"repo_full_name"`
+	ItemType       string `json:"item_type,omitempty"` // TrackedItemTypePR (default) or TrackedItemTypeIssue
+	SlackChannel   string `json:"slack_channel"`
+	SlackMessageTS string `json:"slack_message_ts"`
+	SlackTeamID    string `json:"slack_team_id"` // Slack workspace/team ID
+	TraceID        string `json:"trace_id"`
 }
 
 // Validate validates required fields for ManualLinkJob.
@@ -250,11 +1186,204 @@ func (mlj *ManualLinkJob) Validate() error {
 	return nil
 }
 
-// ReactionSyncJob represents a job to sync reactions for a PR.
+// ReactionSyncJob represents a job to sync reactions for a PR, or the closed reaction for an issue.
 type ReactionSyncJob struct {
 	ID           string `json:"id"`
-	PRNumber     int    `json:"pr_number"`
+	PRNumber     int    `json:"pr_number"` // PR or issue number
+	RepoFullName string `json:"repo_full_name"`
+	ItemType     string `json:"item_type,omitempty"` // TrackedItemTypePR (default) or TrackedItemTypeIssue
+	Reconcile    bool   `json:"reconcile,omitempty"` // Final pass after the PR's update budget ran out; also refreshes content
+	TraceID      string `json:"trace_id"`
+
+	// RequestedAt is when the sync was enqueued, so it can be skipped if a later sync already covers it.
+	// Zero for reconciliation passes and jobs enqueued before coalescing, which always run.
+	RequestedAt time.Time `json:"requested_at,omitempty"`
+}
+
+// Review comment kinds, for comments threaded under tracked PR messages.
+const (
+	ReviewCommentKindReview  = "review"        // The body of a submitted review
+	ReviewCommentKindComment = "issue_comment" // A comment on the PR's conversation
+)
+
+// ReviewCommentJob represents a job to post, edit or delete a review comment in the threads of a PR's tracked messages.
+type ReviewCommentJob struct {
+	ID               string `json:"id"`
+	PRNumber         int    `json:"pr_number"`
+	RepoFullName     string `json:"repo_full_name"`
+	ItemType         string `json:"item_type,omitempty"` // TrackedItemTypePR (default) or TrackedItemTypeIssue
+	PRAuthorGitHubID int64  `json:"pr_author_github_id"`
+	CommentKind      string `json:"comment_kind"`           // ReviewCommentKindReview or ReviewCommentKindComment
+	CommentID        int64  `json:"comment_id"`             // Review or comment ID
+	CommentAction    string `json:"comment_action"`         // "created", "edited" or "deleted"
+	CommentAuthor    string `json:"comment_author"`         // GitHub login of the commenter
+	CommentBody      string `json:"comment_body"`           // Untruncated comment body
+	CommentURL       string `json:"comment_url"`            // Link to the comment on GitHub
+	ReviewState      string `json:"review_state,omitempty"` // Review state, for review comments
+	TraceID          string `json:"trace_id"`
+}
+
+// ThreadReply tracks a review comment the bot posted in a tracked message's thread,
+// so the reply can be edited or deleted with the comment and isn't posted twice on retries.
+type ThreadReply struct {
+	ID               string    `firestore:"id"` // {tracked_message_id}#{comment_kind}#{comment_id}
+	TrackedMessageID string    `firestore:"tracked_message_id"`
+	SlackTeamID      string    `firestore:"slack_team_id"`
+	SlackChannel     string    `firestore:"slack_channel"`
+	ThreadTS         string    `firestore:"thread_ts"` // Timestamp of the tracked PR message
+	ReplyTS          string    `firestore:"reply_ts"`  // Timestamp of the bot's reply
+	CommentKind      string    `firestore:"comment_kind"`
+	CommentID        int64     `firestore:"comment_id"`
+	CreatedAt        time.Time `firestore:"created_at"`
+}
+
+// OnboardingHint records that a user was shown the onboarding hint in a channel, so it's only shown once.
+type OnboardingHint struct {
+	ID           string    `firestore:"id"` // {slack_team_id}#{slack_channel}#{slack_user_id}
+	SlackTeamID  string    `firestore:"slack_team_id"`
+	SlackChannel string    `firestore:"slack_channel"`
+	SlackUserID  string    `firestore:"slack_user_id"`
+	CreatedAt    time.Time `firestore:"created_at"`
+}
+
+// LinkInvite rate limits invitations to link a GitHub account, per GitHub username CC'd in a workspace.
+type LinkInvite struct {
+	ID             string    `firestore:"id"` // {slack_team_id}#{lowercase github_username}
+	SlackTeamID    string    `firestore:"slack_team_id"`
+	GitHubUsername string    `firestore:"github_username"`
+	InviteCount    int       `firestore:"invite_count"`           // Invitations attempted so far
+	LastInvitedAt  time.Time `firestore:"last_invited_at"`        // When the last invitation was attempted
+	OptedOut       bool      `firestore:"opted_out,omitempty"`    // Whether the invited user asked not to be invited again
+	OptedOutBy     string    `firestore:"opted_out_by,omitempty"` // Slack user ID who opted out
+}
+
+// PRSequence orders notifications for a PR. It counts the workspace PR jobs still to post the PR's messages,
+// so events that update those messages (edits, closes, reopens) wait until they've been posted.
+// The count lapses at ExpiresAt, so a job that never completes can't hold up the PR indefinitely.
+type PRSequence struct {
+	ID           string    `firestore:"id"` // {encoded_repo}#{pr_number}
+	RepoFullName string    `firestore:"repo_full_name"`
+	PRNumber     int       `firestore:"pr_number"`
+	PendingJobs  int       `firestore:"pending_jobs"`
+	ExpiresAt    time.Time `firestore:"expires_at"`
+	UpdatedAt    time.Time `firestore:"updated_at"`
+}
+
+// InFlight reports whether workspace PR jobs are still pending for the PR at the given time.
+func (s *PRSequence) InFlight(now time.Time) bool {
+	return s != nil && s.PendingJobs > 0 && now.Before(s.ExpiresAt)
+}
+
+// Reserve records jobs about to be enqueued for the PR and extends the lease.
+// Pending jobs from a lapsed lease are discarded first.
+func (s *PRSequence) Reserve(jobs int, now time.Time, lease time.Duration) {
+	if !s.InFlight(now) {
+		s.PendingJobs = 0
+	}
+	s.PendingJobs += jobs
+	s.ExpiresAt = now.Add(lease)
+	s.UpdatedAt = now
+}
+
+// Complete records that jobs have finished, returning true once none remain pending.
+func (s *PRSequence) Complete(jobs int, now time.Time) bool {
+	s.PendingJobs = max(s.PendingJobs-jobs, 0)
+	s.UpdatedAt = now
+	return s.PendingJobs == 0
+}
+
+// PRUpdateBudget caps how many Slack message updates (edits and reaction syncs) a PR triggers per window,
+// so PRs with scripted description edits or review bots posting dozens of reviews can't flood channels.
+// Updates beyond the budget are dropped in favour of one reconciliation pass when the window ends.
+type PRUpdateBudget struct {
+	ID                 string    `firestore:"id"` // {encoded_repo}#{pr_number}
+	RepoFullName       string    `firestore:"repo_full_name"`
+	PRNumber           int       `firestore:"pr_number"`
+	WindowStart        time.Time `firestore:"window_start"`
+	Updates            int       `firestore:"updates"`             // Updates made in the current window
+	ReconcileScheduled bool      `firestore:"reconcile_scheduled"` // Whether the window's reconciliation pass is scheduled
+	UpdatedAt          time.Time `firestore:"updated_at"`
+}
+
+// WindowEnd returns when the current window ends and the budget is replenished.
+func (b *PRUpdateBudget) WindowEnd(window time.Duration) time.Time {
+	return b.WindowStart.Add(window)
+}
+
+// Spend records an update at the given time, starting a new window if the current one has ended.
+// Returns false if the window's budget is already spent, and whether the caller should schedule the
+// reconciliation pass, which is only asked for once per window.
+func (b *PRUpdateBudget) Spend(limit int, now time.Time, window time.Duration) (allowed, scheduleReconcile bool) {
+	if !now.Before(b.WindowEnd(window)) {
+		b.WindowStart = now
+		b.Updates = 0
+		b.ReconcileScheduled = false
+	}
+	b.UpdatedAt = now
+
+	if b.Updates < limit {
+		b.Updates++
+		return true, false
+	}
+	if b.ReconcileScheduled {
+		return false, false
+	}
+	b.ReconcileScheduled = true
+	return false, true
+}
+
+// ReactionSyncMarker coalesces a PR's reaction sync jobs. Review activity enqueues a sync per event, but a sync
+// that fetches the PR from GitHub after an event covers it too, so jobs requested before a sync started are
+// skipped once that sync completes, or while it's still running. The running sync's claim lapses at
+// ClaimExpiresAt, so a sync that never finishes can't hold up the PR's reactions.
+type ReactionSyncMarker struct {
+	ID             string    `firestore:"id"` // {encoded_repo}#{pr_number}
+	RepoFullName   string    `firestore:"repo_full_name"`
+	PRNumber       int       `firestore:"pr_number"`
+	SyncedFrom     time.Time `firestore:"synced_from"`    // When the latest completed sync started
+	RunningJobID   string    `firestore:"running_job_id"` // Job ID of the sync holding the claim, if any
+	RunningSince   time.Time `firestore:"running_since"`
+	ClaimExpiresAt time.Time `firestore:"claim_expires_at"`
+	UpdatedAt      time.Time `firestore:"updated_at"`
+}
+
+// Claim decides whether a sync job requested at requestedAt still needs to run at now and, if so, records it as
+// the running sync. Returns false if a sync that started after the request has completed, or is still running
+// under another job's claim. Retries of the job holding the claim may run again.
+func (m *ReactionSyncMarker) Claim(jobID string, requestedAt, now time.Time, lease time.Duration) bool {
+	if !m.SyncedFrom.Before(requestedAt) {
+		return false
+	}
+	if m.RunningJobID != "" && m.RunningJobID != jobID && now.Before(m.ClaimExpiresAt) && !m.RunningSince.Before(requestedAt) {
+		return false
+	}
+
+	m.RunningJobID = jobID
+	m.RunningSince = now
+	m.ClaimExpiresAt = now.Add(lease)
+	m.UpdatedAt = now
+	return true
+}
+
+// Release records that a job's sync, started at startedAt, has finished. A successful sync covers every job
+// requested before it started; a failed one only gives up its claim, so those jobs still run.
+func (m *ReactionSyncMarker) Release(jobID string, startedAt time.Time, succeeded bool, now time.Time) {
+	if succeeded && startedAt.After(m.SyncedFrom) {
+		m.SyncedFrom = startedAt
+	}
+	if m.RunningJobID == jobID {
+		m.RunningJobID = ""
+		m.RunningSince = time.Time{}
+		m.ClaimExpiresAt = time.Time{}
+	}
+	m.UpdatedAt = now
+}
+
+// CIStatusSyncJob represents a job to sync CI state reactions for the open PRs whose head is a commit.
+type CIStatusSyncJob struct {
+	ID           string `json:"id"`
 	RepoFullName string `json:"repo_full_name"`
+	HeadSHA      string `json:"head_sha"`
 	TraceID      string `json:"trace_id"`
 }
 
@@ -267,12 +1396,51 @@ type WorkspacePRJob struct {
 	PRAction         string `json:"pr_action"` // "opened", "edited", "ready_for_review", "closed"
 	GitHubUserID     int64  `json:"github_user_id"`
 	GitHubUsername   string `json:"github_username"`
-	AnnotatedChannel string `json:"annotated_channel"` // Channel from PR description
+	AnnotatedChannel string `json:"annotated_channel"`     // Channel from PR description
+	DeliveryID       string `json:"delivery_id,omitempty"` // GitHub delivery that triggered the job, for deduplication
 	TraceID          string `json:"trace_id"`
 	// PR payload will be stored as base64-encoded JSON to avoid nested JSON issues
 	PRPayload []byte `json:"pr_payload"`
 }
 
+// ReviewRequestJob represents a job to notify a user that their review was requested on a PR, or the request removed.
+type ReviewRequestJob struct {
+	ID               string `json:"id"`
+	PRNumber         int    `json:"pr_number"`
+	RepoFullName     string `json:"repo_full_name"`
+	PRTitle          string `json:"pr_title"`
+	PRURL            string `json:"pr_url"`
+	PRAction         string `json:"pr_action"` // "review_requested" or "review_request_removed"
+	ReviewerGitHubID int64  `json:"reviewer_github_id"`
+	ReviewerLogin    string `json:"reviewer_login"`
+	RequestedTeam    string `json:"requested_team,omitempty"` // "org/team-slug" for team review requests, instead of a reviewer
+	RequestedBy      string `json:"requested_by"`             // GitHub login of whoever changed the request
+	TraceID          string `json:"trace_id"`
+}
+
+// Validate validates required fields for ReviewRequestJob.
+func (rrj *ReviewRequestJob) Validate() error {
+	if rrj.ID == "" {
+		return ErrJobIDRequired
+	}
+	if rrj.PRNumber <= 0 {
+		return ErrPRNumberRequired
+	}
+	if rrj.RepoFullName == "" {
+		return ErrRepoFullNameRequired
+	}
+	if rrj.PRAction == "" {
+		return ErrPRActionRequired
+	}
+	if rrj.ReviewerGitHubID <= 0 && rrj.RequestedTeam == "" {
+		return ErrReviewerRequired
+	}
+	if rrj.TraceID == "" {
+		return ErrTraceIDRequired
+	}
+	return nil
+}
+
 // Validate validates required fields for ReactionSyncJob.
 func (rsj *ReactionSyncJob) Validate() error {
 	if rsj.ID == "" {
@@ -290,6 +1458,43 @@ func (rsj *ReactionSyncJob) Validate() error {
 	return nil
 }
 
+// Validate validates required fields for ReviewCommentJob.
+func (rcj *ReviewCommentJob) Validate() error {
+	if rcj.ID == "" {
+		return ErrJobIDRequired
+	}
+	if rcj.PRNumber <= 0 {
+		return ErrPRNumberRequired
+	}
+	if rcj.RepoFullName == "" {
+		return ErrRepoFullNameRequired
+	}
+	if rcj.CommentID <= 0 {
+		return ErrCommentIDRequired
+	}
+	if rcj.TraceID == "" {
+		return ErrTraceIDRequired
+	}
+	return nil
+}
+
+// Validate validates required fields for CIStatusSyncJob.
+func (csj *CIStatusSyncJob) Validate() error {
+	if csj.ID == "" {
+		return ErrJobIDRequired
+	}
+	if csj.RepoFullName == "" {
+		return ErrRepoFullNameRequired
+	}
+	if csj.HeadSHA == "" {
+		return ErrHeadSHARequired
+	}
+	if csj.TraceID == "" {
+		return ErrTraceIDRequired
+	}
+	return nil
+}
+
 // Validate validates required fields for WorkspacePRJob.
 func (wpj *WorkspacePRJob) Validate() error {
 	if wpj.ID == "" {
@@ -320,6 +1525,201 @@ func (wpj *WorkspacePRJob) Validate() error {
 type ReviewState string
 
 // GitHub PR review states.
+// DeferredNotificationJob is a DM or thread note mentioning a user, held back until their quiet hours end.
+// The job is scheduled for when they end, and checks them again in case the user changed them meanwhile.
+type DeferredNotificationJob struct {
+	ID           string `json:"id"`
+	SlackTeamID  string `json:"slack_team_id"`
+	SlackUserID  string `json:"slack_user_id"`           // User whose quiet hours deferred the notification
+	SlackChannel string `json:"slack_channel,omitempty"` // Channel of the thread for thread notes; empty for DMs
+	ThreadTS     string `json:"thread_ts,omitempty"`     // Timestamp of the PR message for thread notes
+	Text         string `json:"text"`
+	TraceID      string `json:"trace_id"`
+}
+
+// Validate validates required fields for DeferredNotificationJob.
+func (dnj *DeferredNotificationJob) Validate() error {
+	if dnj.ID == "" {
+		return ErrJobIDRequired
+	}
+	if dnj.SlackTeamID == "" {
+		return ErrSlackTeamIDRequired
+	}
+	if dnj.SlackUserID == "" {
+		return ErrSlackUserIDRequired
+	}
+	if dnj.SlackChannel != "" && dnj.ThreadTS == "" {
+		return ErrSlackMessageTSRequired
+	}
+	if dnj.Text == "" {
+		return ErrNotificationTextRequired
+	}
+	if dnj.TraceID == "" {
+		return ErrTraceIDRequired
+	}
+	return nil
+}
+
+// SlackCommentJob posts a reply in a PR message's thread that starts with "!to-github" as a comment on the PR.
+type SlackCommentJob struct {
+	ID             string `json:"id"`
+	SlackTeamID    string `json:"slack_team_id"`
+	SlackChannel   string `json:"slack_channel"`
+	SlackThreadTS  string `json:"slack_thread_ts"`  // Timestamp of the PR message the reply is threaded under
+	SlackMessageTS string `json:"slack_message_ts"` // Timestamp of the reply
+	SlackUserID    string `json:"slack_user_id"`    // Who replied
+	Text           string `json:"text"`             // The reply, without the "!to-github" prefix
+	TraceID        string `json:"trace_id"`
+}
+
+// Validate validates required fields for SlackCommentJob.
+func (scj *SlackCommentJob) Validate() error {
+	if scj.ID == "" {
+		return ErrJobIDRequired
+	}
+	if scj.SlackTeamID == "" {
+		return ErrSlackTeamIDRequired
+	}
+	if scj.SlackChannel == "" {
+		return ErrSlackChannelRequired
+	}
+	if scj.SlackThreadTS == "" || scj.SlackMessageTS == "" {
+		return ErrSlackMessageTSRequired
+	}
+	if scj.SlackUserID == "" {
+		return ErrSlackUserIDRequired
+	}
+	if scj.Text == "" {
+		return ErrCommentTextRequired
+	}
+	if scj.TraceID == "" {
+		return ErrTraceIDRequired
+	}
+	return nil
+}
+
+// ReactionActionJob takes the GitHub action mapped to a reaction in REACTION_ACTIONS, as the user who reacted
+// to a PR message.
+type ReactionActionJob struct {
+	ID             string `json:"id"`
+	RepoFullName   string `json:"repo_full_name"`
+	PRNumber       int    `json:"pr_number"`
+	SlackTeamID    string `json:"slack_team_id"`
+	SlackChannel   string `json:"slack_channel"`
+	SlackMessageTS string `json:"slack_message_ts"` // Timestamp of the PR message reacted to
+	SlackUserID    string `json:"slack_user_id"`    // Who reacted
+	Action         string `json:"action"`           // e.g. "approve"
+	TraceID        string `json:"trace_id"`
+}
+
+// Validate validates required fields for ReactionActionJob.
+func (raj *ReactionActionJob) Validate() error {
+	if raj.ID == "" {
+		return ErrJobIDRequired
+	}
+	if raj.RepoFullName == "" {
+		return ErrRepoFullNameRequired
+	}
+	if raj.PRNumber <= 0 {
+		return ErrPRNumberRequired
+	}
+	if raj.SlackTeamID == "" {
+		return ErrSlackTeamIDRequired
+	}
+	if raj.SlackChannel == "" {
+		return ErrSlackChannelRequired
+	}
+	if raj.SlackMessageTS == "" {
+		return ErrSlackMessageTSRequired
+	}
+	if raj.SlackUserID == "" {
+		return ErrSlackUserIDRequired
+	}
+	if raj.Action == "" {
+		return ErrReactionActionRequired
+	}
+	if raj.TraceID == "" {
+		return ErrTraceIDRequired
+	}
+	return nil
+}
+
+// NoReviewersNudgeJob checks a PR message posted without reviewers once NO_REVIEWERS_NUDGE_AFTER has passed,
+// and nudges the PR's author in the message's thread if the PR still has none.
+type NoReviewersNudgeJob struct {
+	ID                string `json:"id"`
+	RepoFullName      string `json:"repo_full_name"`
+	PRNumber          int    `json:"pr_number"`
+	SlackTeamID       string `json:"slack_team_id"`
+	SlackChannel      string `json:"slack_channel"`
+	SlackMessageTS    string `json:"slack_message_ts"`
+	AuthorSlackUserID string `json:"author_slack_user_id,omitempty"` // Mentioned in the nudge; empty if the author hasn't linked Slack
+	TraceID           string `json:"trace_id"`
+}
+
+// Validate validates required fields for NoReviewersNudgeJob.
+func (nrj *NoReviewersNudgeJob) Validate() error {
+	if nrj.ID == "" {
+		return ErrJobIDRequired
+	}
+	if nrj.RepoFullName == "" {
+		return ErrRepoFullNameRequired
+	}
+	if nrj.PRNumber <= 0 {
+		return ErrPRNumberRequired
+	}
+	if nrj.SlackTeamID == "" {
+		return ErrSlackTeamIDRequired
+	}
+	if nrj.SlackChannel == "" {
+		return ErrSlackChannelRequired
+	}
+	if nrj.SlackMessageTS == "" {
+		return ErrSlackMessageTSRequired
+	}
+	if nrj.TraceID == "" {
+		return ErrTraceIDRequired
+	}
+	return nil
+}
+
+// AssignmentJob represents a job to notify a user that they were assigned to a PR, or unassigned.
+type AssignmentJob struct {
+	ID               string `json:"id"`
+	PRNumber         int    `json:"pr_number"`
+	RepoFullName     string `json:"repo_full_name"`
+	PRTitle          string `json:"pr_title"`
+	PRURL            string `json:"pr_url"`
+	PRAction         string `json:"pr_action"` // "assigned" or "unassigned"
+	AssigneeGitHubID int64  `json:"assignee_github_id"`
+	AssigneeLogin    string `json:"assignee_login"`
+	AssignedBy       string `json:"assigned_by"` // GitHub login of whoever changed the assignment
+	TraceID          string `json:"trace_id"`
+}
+
+// Validate validates required fields for AssignmentJob.
+func (aj *AssignmentJob) Validate() error {
+	if aj.ID == "" {
+		return ErrJobIDRequired
+	}
+	if aj.PRNumber <= 0 {
+		return ErrPRNumberRequired
+	}
+	if aj.RepoFullName == "" {
+		return ErrRepoFullNameRequired
+	}
+	if aj.PRAction == "" {
+		return ErrPRActionRequired
+	}
+	if aj.AssigneeGitHubID <= 0 {
+		return ErrAssigneeRequired
+	}
+	if aj.TraceID == "" {
+		return ErrTraceIDRequired
+	}
+	return nil
+}
+
 const (
 	ReviewStateApproved         ReviewState = "approved"
 	ReviewStateChangesRequested ReviewState = "changes_requested"
@@ -334,6 +1734,46 @@ const (
 	JobTypeReactionSync         = "reaction_sync"
 	JobTypeWorkspacePR          = "workspace_pr"
 	JobTypeDeleteTrackedMessage = "delete_tracked_message"
+	JobTypeReleaseCountdown     = "release_countdown"
+	JobTypeChannelReport        = "channel_report"
+	JobTypeDependencyRefresh    = "dependency_refresh"
+	JobTypeUserDigest           = "user_digest"
+	JobTypeChannelDigest        = "channel_digest"
+	JobTypeReviewRequest        = "review_request"
+	JobTypeAssignment           = "assignment"
+	JobTypeDailyDigest          = "daily_digest"
+	JobTypeCIStatusSync         = "ci_status_sync"
+	JobTypeReviewComment        = "review_comment"
+	JobTypeMergeConflictSync    = "merge_conflict_sync"
+	JobTypeMovePRNotification   = "move_pr_notification"
+	JobTypeRetentionSync        = "retention_sync"
+	JobTypeDeferredNotification = "deferred_notification"
+	JobTypePostPR               = "post_pr"
+	JobTypeCleanup              = "cleanup"
+	JobTypeUserDataExport       = "user_data_export"
+	JobTypeUserDataDeletion     = "user_data_deletion"
+	JobTypeNoReviewersNudge     = "no_reviewers_nudge"
+	JobTypeSlackComment         = "slack_comment"
+	JobTypeReactionAction       = "reaction_action"
+)
+
+// CIState is the combined CI state of a commit, from its commit statuses and check suites.
+type CIState string
+
+// Combined CI states. A commit with no statuses or check suites has no CI state.
+const (
+	CIStateNone    CIState = ""
+	CIStatePending CIState = "pending"
+	CIStateSuccess CIState = "success"
+	CIStateFailure CIState = "failure"
+)
+
+// Message presentations, chosen from combinations of PR states by the presentation rules.
+const (
+	PresentationDefault      = ""
+	PresentationCollapsed    = "collapsed"      // Rendered as a single line, e.g. for drafts with failing CI
+	PresentationReadyToMerge = "ready_to_merge" // Highlighted as ready to merge
+	PresentationArchived     = "archived"       // Condensed to a struck-through line once merged or closed
 )
 
 // Message source constants.
@@ -344,19 +1784,21 @@ const (
 
 // Job represents a job structure for all async processing.
 type Job struct {
-	ID      string          `json:"id"`
-	Type    string          `json:"type"`
-	TraceID string          `json:"trace_id"`
-	Payload json.RawMessage `json:"payload"`
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	TraceID   string          `json:"trace_id"`
+	Payload   json.RawMessage `json:"payload"`
+	NotBefore time.Time       `json:"-"` // When the queue should deliver the job; immediately when zero
 }
 
 // DeleteTrackedMessageJob represents a job to delete a tracked message.
 type DeleteTrackedMessageJob struct {
 	ID               string `json:"id"`
-	TrackedMessageID string `json:"tracked_message_id"` // ID of the TrackedMessage to delete
-	SlackChannel     string `json:"slack_channel"`      // Slack channel ID
-	SlackMessageTS   string `json:"slack_message_ts"`   // Slack message timestamp
-	SlackTeamID      string `json:"slack_team_id"`      // Slack workspace ID
+	TrackedMessageID string `json:"tracked_message_id"`          // ID of the TrackedMessage to delete
+	SlackChannel     string `json:"slack_channel"`               // Slack channel ID
+	SlackMessageTS   string `json:"slack_message_ts"`            // Slack message timestamp
+	SlackTeamID      string `json:"slack_team_id"`               // Slack workspace ID
+	PostedAsUserID   string `json:"posted_as_user_id,omitempty"` // Set when the message was posted with a user token
 	TraceID          string `json:"trace_id"`
 }
 
@@ -383,16 +1825,540 @@ func (dtmj *DeleteTrackedMessageJob) Validate() error {
 	return nil
 }
 
+// MovePRNotificationJob represents a job to move a bot-posted PR message to another channel,
+// requested with the "Move to another channel" message shortcut.
+type MovePRNotificationJob struct {
+	ID               string `json:"id"`
+	TrackedMessageID string `json:"tracked_message_id"` // ID of the TrackedMessage being moved
+	SlackChannel     string `json:"slack_channel"`      // Slack channel ID the message is in
+	SlackMessageTS   string `json:"slack_message_ts"`   // Slack message timestamp
+	SlackTeamID      string `json:"slack_team_id"`      // Slack workspace ID
+	TargetChannel    string `json:"target_channel"`     // Slack channel ID to move the message to
+	MovedBy          string `json:"moved_by"`           // Slack user who requested the move
+	TraceID          string `json:"trace_id"`
+}
+
+// Validate validates required fields for MovePRNotificationJob.
+func (mpnj *MovePRNotificationJob) Validate() error {
+	if mpnj.ID == "" {
+		return ErrJobIDRequired
+	}
+	if mpnj.TrackedMessageID == "" {
+		return ErrTrackedMessageIDRequired
+	}
+	if mpnj.SlackChannel == "" {
+		return ErrSlackChannelRequired
+	}
+	if mpnj.SlackMessageTS == "" {
+		return ErrSlackMessageTSRequired
+	}
+	if mpnj.SlackTeamID == "" {
+		return ErrSlackTeamIDRequired
+	}
+	if mpnj.TargetChannel == "" {
+		return ErrTargetChannelRequired
+	}
+	if mpnj.MovedBy == "" {
+		return ErrSlackUserIDRequired
+	}
+	if mpnj.TraceID == "" {
+		return ErrTraceIDRequired
+	}
+	return nil
+}
+
+// PostPRJob represents a job to post a PR's notification on demand, as requested with the "Post a PR" shortcut.
+type PostPRJob struct {
+	ID           string   `json:"id"`
+	SlackTeamID  string   `json:"slack_team_id"`         // Slack workspace ID
+	SlackChannel string   `json:"slack_channel"`         // Slack channel ID to post to
+	RepoFullName string   `json:"repo_full_name"`        // e.g., "owner/repo"
+	PRNumber     int      `json:"pr_number"`             // Pull request number
+	UsersToCC    []string `json:"users_to_cc,omitempty"` // GitHub usernames to CC, instead of the description's CCs
+	RequestedBy  string   `json:"requested_by"`          // Slack user who used the shortcut
+	TraceID      string   `json:"trace_id"`
+}
+
+// Validate validates required fields for PostPRJob.
+func (ppj *PostPRJob) Validate() error {
+	if ppj.ID == "" {
+		return ErrJobIDRequired
+	}
+	if ppj.SlackTeamID == "" {
+		return ErrSlackTeamIDRequired
+	}
+	if ppj.SlackChannel == "" {
+		return ErrSlackChannelRequired
+	}
+	if ppj.RepoFullName == "" {
+		return ErrRepoFullNameRequired
+	}
+	if ppj.PRNumber <= 0 {
+		return ErrPRNumberRequired
+	}
+	if ppj.RequestedBy == "" {
+		return ErrSlackUserIDRequired
+	}
+	if ppj.TraceID == "" {
+		return ErrTraceIDRequired
+	}
+	return nil
+}
+
+// ReleaseCountdownJob represents a scheduled job to refresh release cut countdown lines.
+// It is posted periodically by Cloud Scheduler; an empty payload refreshes all release cut channels.
+type ReleaseCountdownJob struct {
+	SlackTeamID string `json:"slack_team_id,omitempty"` // Optional: limit the refresh to one workspace
+}
+
+// MergeConflictSyncJob represents a job to check open PRs for merge conflicts.
+// Push events enqueue it for the pushed branch, checking PRs based on or headed by it. Cloud Scheduler
+// posts it periodically with an empty payload, checking the PRs of recently tracked messages.
+type MergeConflictSyncJob struct {
+	RepoFullName string `json:"repo_full_name,omitempty"`
+	Branch       string `json:"branch,omitempty"`
+}
+
+// RetentionSyncJob represents a scheduled job to find tracked messages deleted by Slack's message retention policy.
+// It is posted periodically by Cloud Scheduler; an empty payload checks recently tracked messages in all workspaces.
+type RetentionSyncJob struct {
+	SlackTeamID string `json:"slack_team_id,omitempty"` // Optional: limit the check to one workspace
+}
+
+// CleanupJob represents a scheduled job to delete data that's no longer needed: expired OAuth states,
+// tracked messages for PRs closed longer than the retention period, and installations of uninstalled workspaces.
+// It is posted periodically by Cloud Scheduler.
+type CleanupJob struct {
+	DryRun bool `json:"dry_run,omitempty"` // Count what would be deleted without deleting it
+}
+
+// Collections the cleanup job deletes from, as reported in CleanupStats.
+const (
+	CleanupCollectionOAuthStates     = "oauth_states"
+	CleanupCollectionTrackedMessages = "trackedmessages"
+	CleanupCollectionInstallations   = "github_installations"
+)
+
+// CleanupStats holds the running totals of documents deleted by the cleanup job, in the cleanup_stats collection.
+type CleanupStats struct {
+	Deleted   map[string]int64 `firestore:"deleted"`     // Documents deleted per collection, across all runs
+	LastRunAt time.Time        `firestore:"last_run_at"` // When the last non-dry run finished
+}
+
+// UserDataJob represents a user's request, from App Home, to export or delete the data stored about them.
+// The export is sent to the user as a DM.
+type UserDataJob struct {
+	ID          string `json:"id"`
+	SlackTeamID string `json:"slack_team_id"` // Slack workspace ID
+	SlackUserID string `json:"slack_user_id"` // Slack user whose data is exported or deleted
+	TraceID     string `json:"trace_id"`
+}
+
+// Validate validates required fields for UserDataJob.
+func (udj *UserDataJob) Validate() error {
+	if udj.ID == "" {
+		return ErrJobIDRequired
+	}
+	if udj.SlackTeamID == "" {
+		return ErrSlackTeamIDRequired
+	}
+	if udj.SlackUserID == "" {
+		return ErrSlackUserIDRequired
+	}
+	if udj.TraceID == "" {
+		return ErrTraceIDRequired
+	}
+	return nil
+}
+
+// UserDataExport is everything stored about a Slack user, as Firestore documents grouped by collection.
+// Secrets such as Slack user tokens are left out.
+type UserDataExport struct {
+	SlackTeamID string                              `json:"slack_team_id"`
+	SlackUserID string                              `json:"slack_user_id"`
+	ExportedAt  time.Time                           `json:"exported_at"`
+	Collections map[string][]map[string]interface{} `json:"collections"` // Documents by collection, with their fields
+}
+
+// IsEmpty returns whether nothing is stored about the user.
+func (e *UserDataExport) IsEmpty() bool {
+	return len(e.Collections) == 0
+}
+
+// UserDataDeletion reports what deleting a Slack user's data changed.
+type UserDataDeletion struct {
+	Deleted    map[string]int `json:"deleted"`    // Documents deleted per collection
+	Anonymized map[string]int `json:"anonymized"` // Documents per collection that no longer reference the user
+}
+
+// SchemaVersion records the progress of a Firestore migration in the schema_versions collection.
+type SchemaVersion struct {
+	Version          int        `firestore:"version"`               // Migration version number
+	Name             string     `firestore:"name"`                  // Migration name
+	Status           string     `firestore:"status"`                // "running" or "applied"
+	LastDocID        string     `firestore:"last_doc_id,omitempty"` // Resume cursor: last processed document ID
+	DocumentsScanned int        `firestore:"documents_scanned"`     // Documents examined so far
+	DocumentsUpdated int        `firestore:"documents_updated"`     // Documents changed so far
+	StartedAt        time.Time  `firestore:"started_at"`            // When the migration first started
+	UpdatedAt        time.Time  `firestore:"updated_at"`            // Last progress update
+	AppliedAt        *time.Time `firestore:"applied_at,omitempty"`  // When the migration completed
+}
+
+// Schema version statuses.
+const (
+	SchemaVersionStatusRunning = "running"
+	SchemaVersionStatusApplied = "applied"
+)
+
+// ChannelReportJob represents a job to build an on-demand PR report for a channel (from /pr-report).
+type ChannelReportJob struct {
+	ID           string        `json:"id"`
+	SlackTeamID  string        `json:"slack_team_id"`
+	SlackChannel string        `json:"slack_channel"` // Channel to report on
+	SlackUserID  string        `json:"slack_user_id"` // User who requested the report
+	ReplyChannel string        `json:"reply_channel"` // Channel the command was run in
+	Window       time.Duration `json:"window"`        // How far back to look
+	Post         bool          `json:"post"`          // Post to the channel instead of replying ephemerally
+	TraceID      string        `json:"trace_id"`
+}
+
+// Validate validates required fields for ChannelReportJob.
+func (crj *ChannelReportJob) Validate() error {
+	if crj.ID == "" {
+		return ErrJobIDRequired
+	}
+	if crj.SlackTeamID == "" {
+		return ErrSlackTeamIDRequired
+	}
+	if crj.SlackChannel == "" || crj.ReplyChannel == "" {
+		return ErrSlackChannelRequired
+	}
+	if crj.SlackUserID == "" {
+		return ErrSlackUserIDRequired
+	}
+	if crj.Window <= 0 {
+		return ErrReportWindowRequired
+	}
+	if crj.TraceID == "" {
+		return ErrTraceIDRequired
+	}
+	return nil
+}
+
+// ChannelReport summarizes PR activity for a Slack channel over a time window.
+type ChannelReport struct {
+	SlackChannel    string            // Channel ID the report covers
+	Window          time.Duration     // How far back the report looks
+	OpenPRs         []ChannelReportPR // PRs still open, oldest first
+	MergedCount     int               // PRs merged within the window
+	ClosedCount     int               // PRs closed without merging within the window
+	ReviewLatencies []time.Duration   // Time from PR opened to first review, for reviewed PRs
+	Engagement      *ReportEngagement // "Open PR" button clicks; nil when click tracking is disabled
+}
+
+// ReportEngagement counts how many of the PR notifications in a report were clicked through to GitHub.
+type ReportEngagement struct {
+	Notifications int // PR notifications posted by the bot within the window
+	Clicked       int // ...of which had their "Open PR" button clicked
+}
+
+// ChannelReportPR describes a single PR listed in a channel report.
+type ChannelReportPR struct {
+	RepoFullName string
+	PRNumber     int
+	Title        string
+	URL          string
+	OpenedAt     time.Time
+	ReviewState  string
+}
+
 // ChannelConfig represents per-channel configuration for manual PR tracking.
 type ChannelConfig struct {
-	ID                    string    `firestore:"id"`                      // Document ID: {slack_team_id}#{channel_id}
-	SlackTeamID           string    `firestore:"slack_team_id"`           // Slack workspace ID
-	SlackChannelID        string    `firestore:"slack_channel_id"`        // Slack channel ID
-	SlackChannelName      string    `firestore:"slack_channel_name"`      // Cached channel name for display
-	ManualTrackingEnabled bool      `firestore:"manual_tracking_enabled"` // Whether to track manual PR links
-	ConfiguredBy          string    `firestore:"configured_by"`           // Slack user ID who last updated
-	CreatedAt             time.Time `firestore:"created_at"`
-	UpdatedAt             time.Time `firestore:"updated_at"`
+	ID                    string     `firestore:"id"`                               // Document ID: {slack_team_id}#{channel_id}
+	SlackTeamID           string     `firestore:"slack_team_id"`                    // Slack workspace ID
+	SlackChannelID        string     `firestore:"slack_channel_id"`                 // Slack channel ID
+	SlackChannelName      string     `firestore:"slack_channel_name"`               // Cached channel name for display
+	ManualTrackingEnabled bool       `firestore:"manual_tracking_enabled"`          // Whether to track manual PR links
+	ReactionSet           string     `firestore:"reaction_set,omitempty"`           // Which lifecycle reactions to apply (empty means all)
+	ReleaseCutDeadline    *time.Time `firestore:"release_cut_deadline,omitempty"`   // Release cut time for countdown lines on open PRs
+	ProjectContext        bool       `firestore:"project_context,omitempty"`        // Annotate PR messages with milestone and board column
+	ReviewCommentThreads  bool       `firestore:"review_comment_threads,omitempty"` // Post review comments as replies in PR message threads
+	RepostPruned          bool       `firestore:"repost_pruned,omitempty"`          // Re-post open PRs whose messages retention deleted
+	ArchiveClosedPRs      bool       `firestore:"archive_closed_prs,omitempty"`     // Strike through and condense messages when PRs close
+	MessageFormat         string     `firestore:"message_format,omitempty"`         // Layout of PR messages posted here (empty means text)
+	Filters               *PRFilters `firestore:"filters,omitempty"`                // Which PRs are posted here (nil posts every PR)
+	ConfiguredBy          string     `firestore:"configured_by"`                    // Slack user ID who last updated
+	CreatedAt             time.Time  `firestore:"created_at"`
+	UpdatedAt             time.Time  `firestore:"updated_at"`
+
+	// PR size emoji for PRs posted here, unless their author set their own; nil inherits the workspace's
+	PRSizeConfig *PRSizeConfiguration `firestore:"pr_size_config,omitempty"`
+}
+
+// GetPRSizeConfig returns the channel's PR size emoji configuration, or nil for channels without a config.
+func (c *ChannelConfig) GetPRSizeConfig() *PRSizeConfiguration {
+	if c == nil {
+		return nil
+	}
+	return c.PRSizeConfig
+}
+
+// Reaction set values for ChannelConfig.ReactionSet.
+const (
+	ReactionSetAll       = "all"        // Review, CI and merged/closed reactions (default)
+	ReactionSetStateOnly = "state_only" // Only merged/closed reactions
+	ReactionSetNone      = "none"       // No reactions, lifecycle state is shown by editing the message text
+)
+
+// Message formats for ChannelConfig.MessageFormat and TrackedMessage.MessageFormat.
+const (
+	MessageFormatText = "text" // A single line of text (default)
+	MessageFormatRich = "rich" // Block Kit sections with a title button, author avatar, labels and reviewers
+)
+
+// RichMessagesEnabled returns whether PR messages posted in the channel use the rich Block Kit layout.
+func (c *ChannelConfig) RichMessagesEnabled() bool {
+	return c != nil && c.MessageFormat == MessageFormatRich
+}
+
+// ReviewReactionsEnabled returns whether review state reactions should be applied in the channel.
+// A nil config or empty reaction set means the channel uses the default (all reactions).
+func (c *ChannelConfig) ReviewReactionsEnabled() bool {
+	return c == nil || c.ReactionSet == "" || c.ReactionSet == ReactionSetAll
+}
+
+// PRStateReactionsEnabled returns whether merged/closed reactions should be applied in the channel.
+func (c *ChannelConfig) PRStateReactionsEnabled() bool {
+	return c == nil || c.ReactionSet != ReactionSetNone
+}
+
+// ArchivesClosedPRs returns whether messages in the channel are condensed to a struck-through line when PRs
+// are merged or closed.
+func (c *ChannelConfig) ArchivesClosedPRs() bool {
+	return c != nil && c.ArchiveClosedPRs
+}
+
+// UsesTextLifecycleState returns whether lifecycle changes should be shown by editing the message text.
+func (c *ChannelConfig) UsesTextLifecycleState() bool {
+	return c != nil && c.ReactionSet == ReactionSetNone
+}
+
+// PRFilters limits which PRs are posted to a channel. Unset fields don't filter.
+type PRFilters struct {
+	MinPRSize      int      `firestore:"min_pr_size,omitempty"`     // Fewest lines changed (additions plus deletions)
+	MaxPRSize      int      `firestore:"max_pr_size,omitempty"`     // Most lines changed
+	AllowAuthors   []string `firestore:"allow_authors,omitempty"`   // GitHub usernames; when set, only their PRs are posted
+	DenyAuthors    []string `firestore:"deny_authors,omitempty"`    // GitHub usernames whose PRs aren't posted
+	BranchPatterns []string `firestore:"branch_patterns,omitempty"` // Base branch globs, e.g. "release/*"; when set, one must match
+	TitlePattern   string   `firestore:"title_pattern,omitempty"`   // Regular expression PR titles must match
+}
+
+// IsEmpty reports whether the filters let every PR through.
+func (f *PRFilters) IsEmpty() bool {
+	return f == nil || (f.MinPRSize == 0 && f.MaxPRSize == 0 && len(f.AllowAuthors) == 0 && len(f.DenyAuthors) == 0 &&
+		len(f.BranchPatterns) == 0 && f.TitlePattern == "")
+}
+
+// Validate checks the size range and that the branch and title patterns compile.
+func (f *PRFilters) Validate() error {
+	if f.MaxPRSize > 0 && f.MinPRSize > f.MaxPRSize {
+		return ErrInvalidPRSizeRange
+	}
+	for _, pattern := range f.BranchPatterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("%w: %q", ErrInvalidBranchPattern, pattern)
+		}
+	}
+	if f.TitlePattern != "" {
+		if _, err := regexp.Compile(f.TitlePattern); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidTitlePattern, err)
+		}
+	}
+	return nil
+}
+
+// Exclusion returns why the filters keep a PR out of the channel, or "" if it's posted.
+// Authors are compared case-insensitively. Patterns that don't compile are ignored.
+func (f *PRFilters) Exclusion(author, baseBranch, title string, linesChanged int) string {
+	if f.IsEmpty() {
+		return ""
+	}
+	if linesChanged < f.MinPRSize {
+		return "smaller than the minimum size"
+	}
+	if f.MaxPRSize > 0 && linesChanged > f.MaxPRSize {
+		return "larger than the maximum size"
+	}
+
+	matchesAuthor := func(login string) bool { return strings.EqualFold(login, author) }
+	if len(f.AllowAuthors) > 0 && !slices.ContainsFunc(f.AllowAuthors, matchesAuthor) {
+		return "author not in the allow list"
+	}
+	if slices.ContainsFunc(f.DenyAuthors, matchesAuthor) {
+		return "author in the deny list"
+	}
+
+	if len(f.BranchPatterns) > 0 && !slices.ContainsFunc(f.BranchPatterns, func(pattern string) bool {
+		matched, err := path.Match(pattern, baseBranch)
+		return err == nil && matched
+	}) {
+		return "base branch doesn't match"
+	}
+
+	if f.TitlePattern != "" {
+		if re, err := regexp.Compile(f.TitlePattern); err == nil && !re.MatchString(title) {
+			return "title doesn't match"
+		}
+	}
+	return ""
+}
+
+// MaxReviewerRotationMembers is the most reviewers a channel's rotation can have.
+const MaxReviewerRotationMembers = 20
+
+// ReviewerRotation is a channel's pool of reviewers, assigned in turn to PRs posted without CCs.
+// Updated in transactions, so concurrent PRs are assigned different reviewers.
+type ReviewerRotation struct {
+	ID             string    `firestore:"id"`                      // Document ID: {slack_team_id}#{channel_id}
+	SlackTeamID    string    `firestore:"slack_team_id"`           // Slack workspace ID
+	SlackChannelID string    `firestore:"slack_channel_id"`        // Slack channel ID
+	Members        []string  `firestore:"members"`                 // Slack user IDs, in rotation order
+	NextIndex      int       `firestore:"next_index"`              // Position in Members of the next reviewer
+	LastAssigned   string    `firestore:"last_assigned,omitempty"` // Slack user ID of the most recently assigned reviewer
+	ConfiguredBy   string    `firestore:"configured_by"`           // Slack user ID who last changed the members
+	UpdatedAt      time.Time `firestore:"updated_at"`
+}
+
+// SetMembers replaces the rotation's members, continuing after the last assigned reviewer if they're still a member.
+func (r *ReviewerRotation) SetMembers(members []string) {
+	r.Members = members
+	if len(members) == 0 {
+		r.NextIndex = 0
+		return
+	}
+	if i := slices.Index(members, r.LastAssigned); i >= 0 {
+		r.NextIndex = (i + 1) % len(members)
+		return
+	}
+	r.NextIndex %= len(members)
+}
+
+// NamesUser reports whether the rotation refers to a Slack user, as a member, the last assigned reviewer,
+// or whoever last changed the members.
+func (r *ReviewerRotation) NamesUser(slackUserID string) bool {
+	return slices.Contains(r.Members, slackUserID) || r.LastAssigned == slackUserID || r.ConfiguredBy == slackUserID
+}
+
+// Assign picks the next reviewer in turn, skipping excluded members such as the PR author,
+// and advances the rotation past them. Returns false if every member is excluded.
+func (r *ReviewerRotation) Assign(excluded func(slackUserID string) bool) (string, bool) {
+	for offset := range len(r.Members) {
+		i := (r.NextIndex + offset) % len(r.Members)
+		if excluded(r.Members[i]) {
+			continue
+		}
+		r.NextIndex = (i + 1) % len(r.Members)
+		r.LastAssigned = r.Members[i]
+		return r.Members[i], true
+	}
+	return "", false
+}
+
+// TeamUserGroups maps GitHub teams to Slack user groups in a workspace, so team review requests mention the group.
+type TeamUserGroups struct {
+	ID           string          `firestore:"id"`            // Document ID: slack_team_id
+	SlackTeamID  string          `firestore:"slack_team_id"` // Slack workspace ID
+	Mappings     []TeamUserGroup `firestore:"mappings"`
+	ConfiguredBy string          `firestore:"configured_by"` // Slack user ID who last changed the mappings
+	UpdatedAt    time.Time       `firestore:"updated_at"`
+}
+
+// TeamUserGroup maps one GitHub team to a Slack user group.
+type TeamUserGroup struct {
+	GitHubTeam           string `firestore:"github_team"`             // "org/team-slug", lowercase
+	SlackUserGroupID     string `firestore:"slack_user_group_id"`     // Slack user group ID, e.g. S0123456789
+	SlackUserGroupHandle string `firestore:"slack_user_group_handle"` // Handle without the @, kept for display
+}
+
+// UserGroupFor returns the Slack user group mapped to a GitHub team ("org/team-slug"), or nil if it isn't mapped.
+func (t *TeamUserGroups) UserGroupFor(githubTeam string) *TeamUserGroup {
+	for i := range t.Mappings {
+		if strings.EqualFold(t.Mappings[i].GitHubTeam, githubTeam) {
+			return &t.Mappings[i]
+		}
+	}
+	return nil
+}
+
+// NotificationPolicy is a workspace's optional CEL policy, evaluated before each PR notification is posted.
+// Each expression is optional; see the policy package for the available variables.
+type NotificationPolicy struct {
+	WorkspaceID string    `firestore:"workspace_id"    json:"workspace_id"`
+	Skip        string    `firestore:"skip,omitempty"    json:"skip,omitempty"`    // bool: skip the notification
+	Channel     string    `firestore:"channel,omitempty" json:"channel,omitempty"` // string: route to a channel ("" keeps default)
+	CC          string    `firestore:"cc,omitempty"      json:"cc,omitempty"`      // list(string): GitHub users to CC
+	Emoji       string    `firestore:"emoji,omitempty"   json:"emoji,omitempty"`   // string: custom emoji ("" keeps default)
+	UpdatedBy   string    `firestore:"updated_by"      json:"updated_by"`
+	UpdatedAt   time.Time `firestore:"updated_at"      json:"updated_at"`
+}
+
+// IsEmpty returns whether the policy has no expressions.
+func (p *NotificationPolicy) IsEmpty() bool {
+	return strings.TrimSpace(p.Skip) == "" && strings.TrimSpace(p.Channel) == "" &&
+		strings.TrimSpace(p.CC) == "" && strings.TrimSpace(p.Emoji) == ""
+}
+
+// CacheKey identifies the policy's expressions, for caching compiled policies.
+func (p *NotificationPolicy) CacheKey() string {
+	return strings.Join([]string{p.Skip, p.Channel, p.CC, p.Emoji}, "\x00")
+}
+
+// WorkspaceStats is a snapshot of workspace activity shown to admins in the App Home.
+type WorkspaceStats struct {
+	ConnectedUsers        int64             // Users with a verified GitHub account
+	ConfiguredRepos       int64             // Repos configured for the workspace
+	NotificationsThisWeek int64             // PR notifications posted by the bot in the last 7 days
+	ClickedThisWeek       int64             // ...of which had their "Open PR" button clicked
+	TopChannels           []ChannelActivity // Channels with the most notifications in the last 7 days, busiest first
+	GeneratedAt           time.Time
+}
+
+// ChannelActivity is the number of PR notifications posted to a channel.
+type ChannelActivity struct {
+	ChannelID     string
+	Notifications int64
+}
+
+// DirectiveUsage aggregates how PR description directives are used in a workspace.
+// Counters are incremented as PR notifications are posted; document ID is the workspace ID.
+type DirectiveUsage struct {
+	WorkspaceID       string           `firestore:"workspace_id"                json:"workspace_id"`
+	PRsTotal          int64            `firestore:"prs_total"                   json:"prs_total"`           // PRs processed
+	PRsWithDirectives int64            `firestore:"prs_with_directives"         json:"prs_with_directives"` // ...of which used a directive
+	Directives        map[string]int64 `firestore:"directives,omitempty"        json:"directives"`          // Keyed by DirectiveType*
+	Channels          map[string]int64 `firestore:"channels,omitempty"          json:"channels"`            // Channel directive targets
+	CustomEmoji       map[string]int64 `firestore:"custom_emoji,omitempty"      json:"custom_emoji"`        // Custom emoji directives
+	Repos             map[string]int64 `firestore:"repos,omitempty"             json:"repos"`               // PRs using directives per repo
+	ChannelFailures   map[string]int64 `firestore:"channel_failures,omitempty"  json:"channel_failures"`    // Failed posts to a channel directive per repo
+	UpdatedAt         time.Time        `firestore:"updated_at"                  json:"updated_at"`
+}
+
+// Directive types counted in DirectiveUsage.Directives.
+const (
+	DirectiveTypeReview  = "review"  // Any !review directive, including a bare one
+	DirectiveTypeSkip    = "skip"    // !review: skip or !review-skip
+	DirectiveTypeChannel = "channel" // !review: #channel
+	DirectiveTypeCC      = "cc"      // !review: @user
+	DirectiveTypeEmoji   = "emoji"   // !review: :emoji:
+)
+
+// DirectiveUsageEvent records the directives used by a single PR notification in a workspace.
+type DirectiveUsageEvent struct {
+	RepoFullName  string
+	Types         []string // DirectiveType* values, empty when the PR had no directives
+	Channel       string   // Channel directive target, if any
+	CustomEmoji   string   // Custom emoji directive, if any
+	ChannelFailed bool     // Whether posting to the channel directive target failed
 }
 
 func (wj *WebhookJob) Validate() error {
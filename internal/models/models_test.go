@@ -1,9 +1,14 @@
 package models
 
 import (
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestUser_GetImpersonationEnabled(t *testing.T) {
@@ -204,3 +209,736 @@ func TestPRSizeConfiguration_GetCustomPRSizeEmoji(t *testing.T) {
 		})
 	}
 }
+
+func TestChannelConfig_ReactionSet(t *testing.T) {
+	tests := []struct {
+		name           string
+		config         *ChannelConfig
+		expectReview   bool
+		expectPRState  bool
+		expectTextEdit bool
+	}{
+		{
+			name:          "nil config uses all reactions",
+			config:        nil,
+			expectReview:  true,
+			expectPRState: true,
+		},
+		{
+			name:          "empty reaction set uses all reactions",
+			config:        &ChannelConfig{},
+			expectReview:  true,
+			expectPRState: true,
+		},
+		{
+			name:          "explicit all",
+			config:        &ChannelConfig{ReactionSet: ReactionSetAll},
+			expectReview:  true,
+			expectPRState: true,
+		},
+		{
+			name:          "state only",
+			config:        &ChannelConfig{ReactionSet: ReactionSetStateOnly},
+			expectReview:  false,
+			expectPRState: true,
+		},
+		{
+			name:           "none uses text edits",
+			config:         &ChannelConfig{ReactionSet: ReactionSetNone},
+			expectReview:   false,
+			expectPRState:  false,
+			expectTextEdit: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectReview, tt.config.ReviewReactionsEnabled())
+			assert.Equal(t, tt.expectPRState, tt.config.PRStateReactionsEnabled())
+			assert.Equal(t, tt.expectTextEdit, tt.config.UsesTextLifecycleState())
+		})
+	}
+}
+
+func TestChannelConfig_ArchivesClosedPRs(t *testing.T) {
+	var nilConfig *ChannelConfig
+	assert.False(t, nilConfig.ArchivesClosedPRs())
+	assert.False(t, (&ChannelConfig{}).ArchivesClosedPRs())
+	assert.True(t, (&ChannelConfig{ArchiveClosedPRs: true}).ArchivesClosedPRs())
+}
+
+func TestReleaseNotesConfig_MatchesTag(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   *ReleaseNotesConfig
+		tag      string
+		expected bool
+	}{
+		{
+			name:     "nil config",
+			config:   nil,
+			tag:      "v1.0.0",
+			expected: false,
+		},
+		{
+			name:     "disabled config",
+			config:   &ReleaseNotesConfig{Enabled: false, SlackChannel: "C123"},
+			tag:      "v1.0.0",
+			expected: false,
+		},
+		{
+			name:     "missing channel",
+			config:   &ReleaseNotesConfig{Enabled: true},
+			tag:      "v1.0.0",
+			expected: false,
+		},
+		{
+			name:     "default pattern matches v-prefixed tag",
+			config:   &ReleaseNotesConfig{Enabled: true, SlackChannel: "C123"},
+			tag:      "v1.0.0",
+			expected: true,
+		},
+		{
+			name:     "default pattern rejects other tags",
+			config:   &ReleaseNotesConfig{Enabled: true, SlackChannel: "C123"},
+			tag:      "nightly-2024-01-01",
+			expected: false,
+		},
+		{
+			name:     "custom pattern",
+			config:   &ReleaseNotesConfig{Enabled: true, SlackChannel: "C123", TagPattern: "release-*"},
+			tag:      "release-42",
+			expected: true,
+		},
+		{
+			name:     "invalid pattern never matches",
+			config:   &ReleaseNotesConfig{Enabled: true, SlackChannel: "C123", TagPattern: "["},
+			tag:      "v1.0.0",
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.config.MatchesTag(tt.tag))
+		})
+	}
+}
+
+func TestUser_WantsAuthorDM(t *testing.T) {
+	tests := []struct {
+		name     string
+		user     *User
+		event    string
+		expected bool
+	}{
+		{
+			name:     "nil user",
+			user:     nil,
+			event:    AuthorDMEventChangesRequested,
+			expected: false,
+		},
+		{
+			name:     "no preferences defaults to off",
+			user:     &User{},
+			event:    AuthorDMEventChangesRequested,
+			expected: false,
+		},
+		{
+			name:     "changes requested enabled",
+			user:     &User{AuthorDMs: &AuthorDMPreferences{ChangesRequested: true}},
+			event:    AuthorDMEventChangesRequested,
+			expected: true,
+		},
+		{
+			name:     "CI failure not selected",
+			user:     &User{AuthorDMs: &AuthorDMPreferences{ChangesRequested: true}},
+			event:    AuthorDMEventCIFailed,
+			expected: false,
+		},
+		{
+			name:     "CI failure enabled",
+			user:     &User{AuthorDMs: &AuthorDMPreferences{CIFailed: true}},
+			event:    AuthorDMEventCIFailed,
+			expected: true,
+		},
+		{
+			name:     "unknown event",
+			user:     &User{AuthorDMs: &AuthorDMPreferences{ChangesRequested: true, CIFailed: true}},
+			event:    "unknown",
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.user.WantsAuthorDM(tt.event))
+		})
+	}
+}
+
+func TestRepo_GetNotificationMode(t *testing.T) {
+	tests := []struct {
+		name     string
+		repo     *Repo
+		expected string
+	}{
+		{name: "nil repo", repo: nil, expected: NotificationModeFull},
+		{name: "unset mode defaults to full", repo: &Repo{}, expected: NotificationModeFull},
+		{name: "compact", repo: &Repo{NotificationMode: NotificationModeCompact}, expected: NotificationModeCompact},
+		{name: "digest only", repo: &Repo{NotificationMode: NotificationModeDigestOnly}, expected: NotificationModeDigestOnly},
+		{name: "unknown mode falls back to full", repo: &Repo{NotificationMode: "loud"}, expected: NotificationModeFull},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.repo.GetNotificationMode())
+		})
+	}
+}
+
+func TestUser_WantsReviewRequestNotification(t *testing.T) {
+	tests := []struct {
+		name     string
+		user     *User
+		kind     string
+		expected bool
+	}{
+		{
+			name:     "nil user",
+			user:     nil,
+			kind:     ReviewRequestNotifyDM,
+			expected: false,
+		},
+		{
+			name:     "no preferences defaults to off",
+			user:     &User{},
+			kind:     ReviewRequestNotifyDM,
+			expected: false,
+		},
+		{
+			name:     "DM enabled",
+			user:     &User{ReviewRequests: &ReviewRequestPreferences{DM: true}},
+			kind:     ReviewRequestNotifyDM,
+			expected: true,
+		},
+		{
+			name:     "thread note not selected",
+			user:     &User{ReviewRequests: &ReviewRequestPreferences{DM: true}},
+			kind:     ReviewRequestNotifyThreadNote,
+			expected: false,
+		},
+		{
+			name:     "thread note enabled",
+			user:     &User{ReviewRequests: &ReviewRequestPreferences{ThreadNote: true}},
+			kind:     ReviewRequestNotifyThreadNote,
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.user.WantsReviewRequestNotification(tt.kind))
+		})
+	}
+}
+
+func TestDailyDigestPreferences_IsDue(t *testing.T) {
+	// 08:30 UTC is 09:30 in Berlin (CET) and 03:30 in New York (EST)
+	now := time.Date(2024, 1, 15, 8, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		prefs    *DailyDigestPreferences
+		expected bool
+	}{
+		{name: "nil preferences", prefs: nil, expected: false},
+		{name: "disabled", prefs: &DailyDigestPreferences{Hour: 8}, expected: false},
+		{name: "hour reached in UTC", prefs: &DailyDigestPreferences{Enabled: true, Hour: 8}, expected: true},
+		{name: "hour not reached in UTC", prefs: &DailyDigestPreferences{Enabled: true, Hour: 9}, expected: false},
+		{
+			name:     "hour reached in timezone",
+			prefs:    &DailyDigestPreferences{Enabled: true, Hour: 9, Timezone: "Europe/Berlin"},
+			expected: true,
+		},
+		{
+			name:     "hour not reached in timezone",
+			prefs:    &DailyDigestPreferences{Enabled: true, Hour: 9, Timezone: "America/New_York"},
+			expected: false,
+		},
+		{
+			name:     "already sent today",
+			prefs:    &DailyDigestPreferences{Enabled: true, Hour: 9, Timezone: "Europe/Berlin", LastSentDate: "2024-01-15"},
+			expected: false,
+		},
+		{
+			name:     "sent yesterday",
+			prefs:    &DailyDigestPreferences{Enabled: true, Hour: 9, Timezone: "Europe/Berlin", LastSentDate: "2024-01-14"},
+			expected: true,
+		},
+		{
+			name:     "unknown timezone falls back to UTC",
+			prefs:    &DailyDigestPreferences{Enabled: true, Hour: 8, Timezone: "Mars/Olympus_Mons"},
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.prefs.IsDue(now))
+		})
+	}
+}
+
+func TestQuietHoursPreferences_EndsAt(t *testing.T) {
+	// 23:30 UTC on the 15th is 00:30 on the 16th in Berlin (CET) and 18:30 in New York (EST)
+	now := time.Date(2024, 1, 15, 23, 30, 0, 0, time.UTC)
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		prefs     *QuietHoursPreferences
+		wantQuiet bool
+		wantEnd   time.Time
+	}{
+		{name: "nil preferences", prefs: nil},
+		{name: "disabled", prefs: &QuietHoursPreferences{StartHour: 22, EndHour: 7}},
+		{name: "empty window", prefs: &QuietHoursPreferences{Enabled: true, StartHour: 9, EndHour: 9}},
+		{
+			name:      "overnight before midnight",
+			prefs:     &QuietHoursPreferences{Enabled: true, StartHour: 22, EndHour: 7},
+			wantQuiet: true,
+			wantEnd:   time.Date(2024, 1, 16, 7, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "overnight after midnight in timezone",
+			prefs:     &QuietHoursPreferences{Enabled: true, StartHour: 22, EndHour: 7, Timezone: "Europe/Berlin"},
+			wantQuiet: true,
+			wantEnd:   time.Date(2024, 1, 16, 7, 0, 0, 0, berlin),
+		},
+		{
+			name:  "overnight not started in timezone",
+			prefs: &QuietHoursPreferences{Enabled: true, StartHour: 22, EndHour: 7, Timezone: "America/New_York"},
+		},
+		{
+			name:      "same-day window in timezone",
+			prefs:     &QuietHoursPreferences{Enabled: true, StartHour: 17, EndHour: 19, Timezone: "America/New_York"},
+			wantQuiet: true,
+			wantEnd:   time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "same-day window ended",
+			prefs: &QuietHoursPreferences{Enabled: true, StartHour: 9, EndHour: 17},
+		},
+		{
+			name:      "unknown timezone falls back to UTC",
+			prefs:     &QuietHoursPreferences{Enabled: true, StartHour: 20, EndHour: 6, Timezone: "Mars/Olympus_Mons"},
+			wantQuiet: true,
+			wantEnd:   time.Date(2024, 1, 16, 6, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			end, quiet := tt.prefs.EndsAt(now)
+			assert.Equal(t, tt.wantQuiet, quiet)
+			if tt.wantQuiet {
+				assert.True(t, tt.wantEnd.Equal(end), "got %s, want %s", end, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestPRSequence(t *testing.T) {
+	now := time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+	lease := 2 * time.Minute
+
+	var missing *PRSequence
+	assert.False(t, missing.InFlight(now), "no sequence isn't in flight")
+
+	sequence := &PRSequence{}
+	sequence.Reserve(2, now, lease)
+	assert.True(t, sequence.InFlight(now))
+	assert.False(t, sequence.InFlight(now.Add(lease)), "lease lapsed")
+
+	assert.False(t, sequence.Complete(1, now))
+	assert.True(t, sequence.InFlight(now))
+	assert.True(t, sequence.Complete(1, now))
+	assert.False(t, sequence.InFlight(now))
+	assert.True(t, sequence.Complete(1, now), "completing extra jobs doesn't go negative")
+	assert.Equal(t, 0, sequence.PendingJobs)
+
+	sequence.Reserve(3, now, lease)
+	sequence.Reserve(1, now.Add(lease), lease)
+	assert.Equal(t, 1, sequence.PendingJobs, "jobs from a lapsed lease are discarded")
+	assert.Equal(t, now.Add(2*lease), sequence.ExpiresAt)
+}
+
+func TestRepo_InheritDefaults(t *testing.T) {
+	defaults := &InstallationDefaults{DefaultChannel: "C1", NotificationMode: NotificationModeCompact, Emoji: ":rocket:"}
+
+	repo := &Repo{RepoFullName: "org/api"}
+	repo.InheritDefaults(defaults)
+	assert.Equal(t, "C1", repo.DefaultChannel)
+	assert.Equal(t, NotificationModeCompact, repo.GetNotificationMode())
+	assert.Equal(t, ":rocket:", repo.Emoji)
+
+	configured := &Repo{RepoFullName: "org/web", DefaultChannel: "C2", NotificationMode: NotificationModeDigestOnly}
+	configured.InheritDefaults(defaults)
+	assert.Equal(t, "C2", configured.DefaultChannel, "repository settings take precedence")
+	assert.Equal(t, NotificationModeDigestOnly, configured.NotificationMode)
+	assert.Equal(t, ":rocket:", configured.Emoji, "unset settings are inherited")
+
+	unconfigured := &Repo{RepoFullName: "org/cli"}
+	unconfigured.InheritDefaults(nil)
+	assert.Equal(t, &Repo{RepoFullName: "org/cli"}, unconfigured)
+
+	assert.True(t, (*InstallationDefaults)(nil).IsEmpty())
+	assert.True(t, (&InstallationDefaults{UpdatedBy: "U1"}).IsEmpty())
+	assert.False(t, defaults.IsEmpty())
+}
+
+func TestWebhookDelivery_WorkspaceCompleted(t *testing.T) {
+	var missing *WebhookDelivery
+	assert.False(t, missing.WorkspaceCompleted("T1"), "an unrecorded delivery has no completed workspaces")
+
+	delivery := &WebhookDelivery{ID: "delivery-1", CompletedWorkspaces: []string{"T1"}}
+	assert.True(t, delivery.WorkspaceCompleted("T1"))
+	assert.False(t, delivery.WorkspaceCompleted("T2"))
+}
+
+func TestWebhookEvent_Payload(t *testing.T) {
+	payload := []byte(`{"action":"opened","number":42,"pull_request":{"title":"` + strings.Repeat("a", 1000) + `"}}`)
+
+	event := &WebhookEvent{}
+	require.NoError(t, event.SetPayload(payload))
+	assert.Equal(t, len(payload), event.PayloadSize)
+	assert.Less(t, len(event.Payload), len(payload), "payloads are stored compressed")
+
+	decoded, err := event.DecodePayload()
+	require.NoError(t, err)
+	assert.Equal(t, payload, decoded)
+
+	_, err = (&WebhookEvent{Payload: payload}).DecodePayload()
+	assert.Error(t, err, "uncompressed payloads can't be decoded")
+}
+
+func TestPRUpdateBudget_Spend(t *testing.T) {
+	now := time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+	window := time.Hour
+
+	budget := &PRUpdateBudget{}
+	for i := 0; i < 2; i++ {
+		allowed, reconcile := budget.Spend(2, now.Add(time.Duration(i)*time.Minute), window)
+		assert.True(t, allowed)
+		assert.False(t, reconcile)
+	}
+	assert.Equal(t, now, budget.WindowStart, "the window starts with its first update")
+
+	allowed, reconcile := budget.Spend(2, now.Add(10*time.Minute), window)
+	assert.False(t, allowed, "budget spent")
+	assert.True(t, reconcile, "the first dropped update schedules the reconciliation pass")
+
+	allowed, reconcile = budget.Spend(2, now.Add(20*time.Minute), window)
+	assert.False(t, allowed)
+	assert.False(t, reconcile, "the reconciliation pass is only scheduled once per window")
+
+	allowed, reconcile = budget.Spend(2, now.Add(window), window)
+	assert.True(t, allowed, "budget replenished once the window ends")
+	assert.False(t, reconcile)
+	assert.Equal(t, now.Add(window), budget.WindowStart)
+	assert.Equal(t, 1, budget.Updates)
+	assert.False(t, budget.ReconcileScheduled)
+}
+
+func TestReactionSyncMarker(t *testing.T) {
+	now := time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+	lease := 2 * time.Minute
+
+	marker := &ReactionSyncMarker{}
+	assert.True(t, marker.Claim("job-1", now, now.Add(time.Second), lease), "the first sync for a PR runs")
+	assert.False(t, marker.Claim("job-2", now.Add(500*time.Millisecond), now.Add(2*time.Second), lease),
+		"a sync requested before the running sync started is covered by it")
+	assert.True(t, marker.Claim("job-1", now, now.Add(3*time.Second), lease), "retries of the running sync run")
+	assert.True(t, marker.Claim("job-3", now.Add(4*time.Second), now.Add(5*time.Second), lease),
+		"a sync requested after the running sync started runs alongside it")
+
+	marker.Release("job-1", now.Add(3*time.Second), true, now.Add(6*time.Second))
+	assert.Equal(t, now.Add(3*time.Second), marker.SyncedFrom)
+	assert.Equal(t, "job-3", marker.RunningJobID, "releasing another job's sync leaves the claim")
+
+	marker.Release("job-3", now.Add(5*time.Second), false, now.Add(7*time.Second))
+	assert.Equal(t, now.Add(3*time.Second), marker.SyncedFrom, "failed syncs don't cover anything")
+	assert.Empty(t, marker.RunningJobID)
+
+	assert.False(t, marker.Claim("job-4", now.Add(2*time.Second), now.Add(8*time.Second), lease),
+		"a sync requested before a completed sync started is skipped")
+	assert.True(t, marker.Claim("job-5", now.Add(4*time.Second), now.Add(8*time.Second), lease))
+	assert.True(t, marker.Claim("job-6", now.Add(7*time.Second), now.Add(8*time.Second).Add(lease), lease),
+		"a lapsed claim doesn't hold up later syncs")
+}
+
+func TestUser_ChannelsForRepo(t *testing.T) {
+	legacy := &User{DefaultChannel: "C1"}
+	channels, overridden := legacy.ChannelsForRepo("org/api")
+	assert.Equal(t, []string{"C1"}, channels, "a single default channel from before multiple channels is still used")
+	assert.False(t, overridden)
+
+	user := &User{}
+	user.SetDefaultChannels([]string{"C1", "C2"})
+	assert.Equal(t, "C1", user.DefaultChannel)
+	user.RepoChannels = []RepoChannelOverride{{RepoFullName: "org/api", Channels: []string{"backend"}}}
+
+	channels, overridden = user.ChannelsForRepo("Org/API")
+	assert.Equal(t, []string{"backend"}, channels)
+	assert.True(t, overridden)
+
+	channels, overridden = user.ChannelsForRepo("org/web")
+	assert.Equal(t, []string{"C1", "C2"}, channels)
+	assert.False(t, overridden)
+
+	user.SetDefaultChannels(nil)
+	assert.Empty(t, user.DefaultChannel)
+	assert.Empty(t, user.GetDefaultChannels())
+}
+
+func TestUser_RepoMutes(t *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	var none *User
+	assert.False(t, none.RepoMuted("org/api", now), "PRs of users without an account aren't muted")
+
+	user := &User{MutedRepos: []RepoMute{{RepoFullName: "org/old", Until: now.Add(-time.Minute)}}}
+	user.MuteRepo("org/api", now.Add(24*time.Hour), now)
+	user.MuteRepo("org/web", now.Add(time.Hour), now)
+	assert.True(t, user.RepoMuted("Org/API", now))
+	assert.False(t, user.RepoMuted("org/old", now), "expired mutes don't apply")
+	assert.False(t, user.RepoMuted("org/api", now.Add(24*time.Hour)))
+	assert.Len(t, user.MutedRepos, 2, "muting drops expired mutes")
+
+	user.MuteRepo("org/api", now.Add(time.Minute), now)
+	assert.Equal(t, []RepoMute{
+		{RepoFullName: "org/api", Until: now.Add(time.Minute)},
+		{RepoFullName: "org/web", Until: now.Add(time.Hour)},
+	}, user.ActiveRepoMutes(now), "muting again replaces the mute")
+
+	user.UnmuteRepo("ORG/WEB", now)
+	assert.False(t, user.RepoMuted("org/web", now))
+	assert.Len(t, user.MutedRepos, 1)
+}
+
+func TestPRFilters(t *testing.T) {
+	var none *PRFilters
+	assert.True(t, none.IsEmpty())
+	assert.Empty(t, none.Exclusion("octocat", "main", "Fix bug", 10), "channels without filters post every PR")
+
+	filters := &PRFilters{
+		MinPRSize:      5,
+		MaxPRSize:      500,
+		DenyAuthors:    []string{"Dependabot[bot]"},
+		BranchPatterns: []string{"main", "release/*"},
+		TitlePattern:   `^(feat|fix)`,
+	}
+	require.NoError(t, filters.Validate())
+
+	tests := []struct {
+		name       string
+		author     string
+		baseBranch string
+		title      string
+		lines      int
+		excluded   bool
+	}{
+		{name: "passes every filter", author: "octocat", baseBranch: "main", title: "feat: widgets", lines: 50},
+		{name: "release branch glob", author: "octocat", baseBranch: "release/1.2", title: "fix: crash", lines: 50},
+		{name: "too small", author: "octocat", baseBranch: "main", title: "fix: typo", lines: 1, excluded: true},
+		{name: "too large", author: "octocat", baseBranch: "main", title: "feat: rewrite", lines: 501, excluded: true},
+		{name: "denied author any case", author: "dependabot[bot]", baseBranch: "main", title: "fix: bump", lines: 10, excluded: true},
+		{name: "other base branch", author: "octocat", baseBranch: "develop", title: "feat: widgets", lines: 50, excluded: true},
+		{name: "title mismatch", author: "octocat", baseBranch: "main", title: "chore: tidy", lines: 50, excluded: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason := filters.Exclusion(tt.author, tt.baseBranch, tt.title, tt.lines)
+			assert.Equal(t, tt.excluded, reason != "", "reason: %q", reason)
+		})
+	}
+
+	allow := &PRFilters{AllowAuthors: []string{"octocat"}}
+	assert.Empty(t, allow.Exclusion("OctoCat", "main", "", 0))
+	assert.NotEmpty(t, allow.Exclusion("hubot", "main", "", 0), "only allowed authors are posted")
+
+	assert.ErrorIs(t, (&PRFilters{MinPRSize: 10, MaxPRSize: 5}).Validate(), ErrInvalidPRSizeRange)
+	assert.ErrorIs(t, (&PRFilters{BranchPatterns: []string{"release/["}}).Validate(), ErrInvalidBranchPattern)
+	assert.ErrorIs(t, (&PRFilters{TitlePattern: "(unclosed"}).Validate(), ErrInvalidTitlePattern)
+}
+
+func TestReviewerRotation_Assign(t *testing.T) {
+	rotation := &ReviewerRotation{Members: []string{"U1", "U2", "U3"}}
+	noneExcluded := func(string) bool { return false }
+
+	reviewer, ok := rotation.Assign(noneExcluded)
+	assert.True(t, ok)
+	assert.Equal(t, "U1", reviewer)
+
+	// The author is skipped, and the rotation continues after whoever was assigned
+	reviewer, ok = rotation.Assign(func(id string) bool { return id == "U2" })
+	assert.True(t, ok)
+	assert.Equal(t, "U3", reviewer)
+	assert.Equal(t, 0, rotation.NextIndex, "wraps around to the start")
+
+	reviewer, ok = rotation.Assign(noneExcluded)
+	assert.True(t, ok)
+	assert.Equal(t, "U1", reviewer)
+
+	_, ok = rotation.Assign(func(string) bool { return true })
+	assert.False(t, ok)
+	assert.Equal(t, "U1", rotation.LastAssigned, "an unsuccessful assignment doesn't advance the rotation")
+
+	_, ok = (&ReviewerRotation{}).Assign(noneExcluded)
+	assert.False(t, ok)
+}
+
+func TestReviewerRotation_NamesUser(t *testing.T) {
+	rotation := &ReviewerRotation{Members: []string{"U1", "U2"}, LastAssigned: "U3", ConfiguredBy: "U4"}
+
+	for _, userID := range []string{"U1", "U2", "U3", "U4"} {
+		assert.True(t, rotation.NamesUser(userID), userID)
+	}
+	assert.False(t, rotation.NamesUser("U5"))
+}
+
+func TestReviewerRotation_SetMembers(t *testing.T) {
+	rotation := &ReviewerRotation{Members: []string{"U1", "U2", "U3"}, NextIndex: 2, LastAssigned: "U2"}
+
+	rotation.SetMembers([]string{"U4", "U2", "U1"})
+	assert.Equal(t, 2, rotation.NextIndex, "continues after the last assigned reviewer")
+
+	rotation.SetMembers([]string{"U5", "U6"})
+	assert.Equal(t, 0, rotation.NextIndex, "keeps the position when the last reviewer left")
+
+	rotation.SetMembers(nil)
+	assert.Equal(t, 0, rotation.NextIndex)
+}
+
+func TestTeamUserGroups_UserGroupFor(t *testing.T) {
+	groups := &TeamUserGroups{Mappings: []TeamUserGroup{
+		{GitHubTeam: "acme/backend", SlackUserGroupID: "S1", SlackUserGroupHandle: "backend-devs"},
+		{GitHubTeam: "acme/web", SlackUserGroupID: "S2", SlackUserGroupHandle: "web"},
+	}}
+
+	group := groups.UserGroupFor("Acme/Backend")
+	require.NotNil(t, group, "matches ignoring case")
+	assert.Equal(t, "S1", group.SlackUserGroupID)
+
+	assert.Nil(t, groups.UserGroupFor("acme/mobile"))
+	assert.Nil(t, groups.UserGroupFor("other/backend"))
+}
+
+func TestReviewRequestJob_Validate_Team(t *testing.T) {
+	job := &ReviewRequestJob{
+		ID:           "job-1",
+		PRNumber:     1,
+		RepoFullName: "acme/widgets",
+		PRAction:     "review_requested",
+		TraceID:      "trace-1",
+	}
+	require.ErrorIs(t, job.Validate(), ErrReviewerRequired)
+
+	job.RequestedTeam = "acme/backend"
+	require.NoError(t, job.Validate())
+}
+
+func TestUserDataJob_Validate(t *testing.T) {
+	job := &UserDataJob{ID: "job-1", SlackTeamID: "T123", TraceID: "trace-1"}
+	require.ErrorIs(t, job.Validate(), ErrSlackUserIDRequired)
+
+	job.SlackUserID = "U123"
+	require.NoError(t, job.Validate())
+}
+
+func TestNoReviewersNudgeJob_Validate(t *testing.T) {
+	job := &NoReviewersNudgeJob{
+		ID:           "job-1",
+		RepoFullName: "acme/widgets",
+		PRNumber:     1,
+		SlackTeamID:  "T123",
+		SlackChannel: "C123",
+		TraceID:      "trace-1",
+	}
+	require.ErrorIs(t, job.Validate(), ErrSlackMessageTSRequired)
+
+	job.SlackMessageTS = "1234.5678"
+	require.NoError(t, job.Validate(), "the author's Slack user is optional")
+}
+
+func TestSlackCommentJob_Validate(t *testing.T) {
+	job := &SlackCommentJob{
+		ID:             "job-1",
+		SlackTeamID:    "T123",
+		SlackChannel:   "C123",
+		SlackThreadTS:  "1234.5678",
+		SlackMessageTS: "1234.9999",
+		SlackUserID:    "U123",
+		TraceID:        "trace-1",
+	}
+	require.ErrorIs(t, job.Validate(), ErrCommentTextRequired)
+
+	job.Text = "Looks good once the migration is split out"
+	require.NoError(t, job.Validate())
+}
+
+func TestReactionActionJob_Validate(t *testing.T) {
+	job := &ReactionActionJob{
+		ID:             "job-1",
+		RepoFullName:   "owner/repo",
+		PRNumber:       42,
+		SlackTeamID:    "T123",
+		SlackChannel:   "C123",
+		SlackMessageTS: "1234.5678",
+		SlackUserID:    "U123",
+		TraceID:        "trace-1",
+	}
+	require.ErrorIs(t, job.Validate(), ErrReactionActionRequired)
+
+	job.Action = "approve"
+	require.NoError(t, job.Validate())
+}
+
+func TestSlackEnterpriseInstallation_WorkspaceFor(t *testing.T) {
+	installation := &SlackEnterpriseInstallation{ID: "E123", AccessToken: "xoxb-org", BotUserID: "U999"}
+
+	workspace := installation.WorkspaceFor("T123", "")
+	assert.Equal(t, "T123", workspace.TeamName, "falls back to the team ID when the name isn't known")
+	assert.Equal(t, "xoxb-org", workspace.AccessToken)
+	assert.Equal(t, "E123", workspace.EnterpriseID)
+	assert.True(t, workspace.IsEnterpriseInstall)
+	require.NoError(t, workspace.Validate())
+}
+
+func TestGitHubInstallation_GitHubHost(t *testing.T) {
+	assert.Empty(t, (&GitHubInstallation{}).GitHubHost(), "github.com installations have no API base URL")
+
+	installation := &GitHubInstallation{APIBaseURL: GitHubEnterpriseAPIBaseURL("github.example.com")}
+	assert.Equal(t, "https://github.example.com/api/v3/", installation.APIBaseURL)
+	assert.Equal(t, "github.example.com", installation.GitHubHost())
+	assert.Empty(t, GitHubEnterpriseAPIBaseURL(""))
+}
+
+func TestJobErrorClasses(t *testing.T) {
+	cause := errors.New("boom")
+
+	permanent := fmt.Errorf("failed to unmarshal job: %w", Permanent(cause))
+	assert.ErrorIs(t, permanent, ErrPermanent)
+	assert.ErrorIs(t, permanent, cause)
+	assert.NotErrorIs(t, permanent, ErrRetryable)
+	assert.Equal(t, "failed to unmarshal job: boom", permanent.Error())
+
+	retryable := Retryable(cause)
+	assert.ErrorIs(t, retryable, ErrRetryable)
+	assert.NotErrorIs(t, retryable, ErrPermanent)
+	assert.Equal(t, "boom", retryable.Error())
+
+	assert.NoError(t, Permanent(nil))
+	assert.NoError(t, Retryable(nil))
+}
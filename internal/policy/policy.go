@@ -0,0 +1,280 @@
+// Package policy evaluates per-workspace notification policies written in CEL.
+//
+// A policy is a set of optional CEL expressions over PR attributes, evaluated before a PR notification
+// is posted. Each expression decides one aspect of the notification and is type-checked on save:
+//
+//	skip:    bool          e.g. `draft || author.endsWith("[bot]")`
+//	channel: string        e.g. `"security" in labels ? "security-reviews" : ""`  ("" keeps the default)
+//	cc:      list(string)  e.g. `size > 1000 ? ["tech-lead"] : []`
+//	emoji:   string        e.g. `files.exists(f, f.startsWith("db/")) ? ":floppy_disk:" : ""`
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/cel-go/cel"
+
+	"github-slack-notifier/internal/models"
+)
+
+// maxEvaluationCost bounds the work a single expression evaluation may do.
+const maxEvaluationCost = 100000
+
+var (
+	ErrInvalidExpression = errors.New("invalid policy expression")
+	ErrEmptyPolicy       = errors.New("policy has no expressions")
+)
+
+// Input holds the PR attributes a policy can reference. Each field is exposed as a top-level CEL variable.
+type Input struct {
+	Repo         string    // repo: "org/name"
+	Author       string    // author: GitHub login
+	Title        string    // title
+	BaseBranch   string    // base_branch
+	Labels       []string  // labels
+	Files        []string  // files: changed file paths (only fetched when the policy references them)
+	Additions    int       // additions
+	Deletions    int       // deletions; size is additions + deletions
+	ChangedFiles int       // changed_files
+	Draft        bool      // draft
+	Time         time.Time // hour (0-23) and weekday (0 = Sunday), in UTC
+}
+
+// Decision is the outcome of evaluating a policy.
+type Decision struct {
+	Skip    bool
+	Channel string   // Channel name or ID without a leading "#", empty to keep the default
+	CC      []string // GitHub usernames to CC in addition to any directives
+	Emoji   string   // Custom emoji, empty to keep the default
+}
+
+// Policy is a compiled notification policy.
+type Policy struct {
+	skip      cel.Program
+	channel   cel.Program
+	cc        cel.Program
+	emoji     cel.Program
+	usesFiles bool
+}
+
+// UsesFiles returns whether the policy references the changed file list, which costs an extra GitHub API call.
+func (p *Policy) UsesFiles() bool {
+	return p.usesFiles
+}
+
+// newEnv creates the CEL environment declaring the policy input variables.
+func newEnv() (*cel.Env, error) {
+	return cel.NewEnv(
+		cel.Variable("repo", cel.StringType),
+		cel.Variable("author", cel.StringType),
+		cel.Variable("title", cel.StringType),
+		cel.Variable("base_branch", cel.StringType),
+		cel.Variable("labels", cel.ListType(cel.StringType)),
+		cel.Variable("files", cel.ListType(cel.StringType)),
+		cel.Variable("additions", cel.IntType),
+		cel.Variable("deletions", cel.IntType),
+		cel.Variable("size", cel.IntType),
+		cel.Variable("changed_files", cel.IntType),
+		cel.Variable("draft", cel.BoolType),
+		cel.Variable("hour", cel.IntType),
+		cel.Variable("weekday", cel.IntType),
+	)
+}
+
+// Compile parses and type-checks each expression of a policy.
+// Errors name the offending expression so they can be shown to whoever saved the policy.
+func Compile(notificationPolicy *models.NotificationPolicy) (*Policy, error) {
+	if notificationPolicy.IsEmpty() {
+		return nil, ErrEmptyPolicy
+	}
+
+	env, err := newEnv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create policy environment: %w", err)
+	}
+
+	compiled := &Policy{}
+	expressions := []struct {
+		name       string
+		expression string
+		outputType *cel.Type
+		program    *cel.Program
+	}{
+		{"skip", notificationPolicy.Skip, cel.BoolType, &compiled.skip},
+		{"channel", notificationPolicy.Channel, cel.StringType, &compiled.channel},
+		{"cc", notificationPolicy.CC, cel.ListType(cel.StringType), &compiled.cc},
+		{"emoji", notificationPolicy.Emoji, cel.StringType, &compiled.emoji},
+	}
+
+	for _, e := range expressions {
+		if strings.TrimSpace(e.expression) == "" {
+			continue
+		}
+
+		ast, issues := env.Compile(e.expression)
+		if issues != nil && issues.Err() != nil {
+			return nil, fmt.Errorf("%w: %s: %s", ErrInvalidExpression, e.name, issues.Err().Error())
+		}
+		if !ast.OutputType().IsExactType(e.outputType) {
+			return nil, fmt.Errorf("%w: %s must return %s, got %s", ErrInvalidExpression, e.name, e.outputType, ast.OutputType())
+		}
+
+		program, err := env.Program(ast, cel.CostLimit(maxEvaluationCost))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %s", ErrInvalidExpression, e.name, err.Error())
+		}
+		*e.program = program
+
+		if referencesFiles(ast) {
+			compiled.usesFiles = true
+		}
+	}
+
+	return compiled, nil
+}
+
+// referencesFiles returns whether a checked expression references the files variable.
+func referencesFiles(ast *cel.Ast) bool {
+	checked, err := cel.AstToCheckedExpr(ast)
+	if err != nil {
+		return false
+	}
+	for _, reference := range checked.GetReferenceMap() {
+		if reference.GetName() == "files" {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate compiles a policy and evaluates it against a sample PR, so runtime errors
+// (e.g. exceeding the cost limit) are caught before the policy is saved.
+func Validate(notificationPolicy *models.NotificationPolicy) error {
+	compiled, err := Compile(notificationPolicy)
+	if err != nil {
+		return err
+	}
+
+	_, err = compiled.Evaluate(&Input{
+		Repo:         "org/repo",
+		Author:       "octocat",
+		Title:        "Sample PR",
+		BaseBranch:   "main",
+		Labels:       []string{"sample"},
+		Files:        []string{"README.md"},
+		Additions:    10,
+		Deletions:    2,
+		ChangedFiles: 1,
+		Time:         time.Now(),
+	})
+	return err
+}
+
+// Evaluate runs the policy against a PR.
+func (p *Policy) Evaluate(input *Input) (*Decision, error) {
+	labels := input.Labels
+	if labels == nil {
+		labels = []string{}
+	}
+	files := input.Files
+	if files == nil {
+		files = []string{}
+	}
+	now := input.Time.UTC()
+
+	vars := map[string]any{
+		"repo":          input.Repo,
+		"author":        input.Author,
+		"title":         input.Title,
+		"base_branch":   input.BaseBranch,
+		"labels":        labels,
+		"files":         files,
+		"additions":     input.Additions,
+		"deletions":     input.Deletions,
+		"size":          input.Additions + input.Deletions,
+		"changed_files": input.ChangedFiles,
+		"draft":         input.Draft,
+		"hour":          now.Hour(),
+		"weekday":       int(now.Weekday()),
+	}
+
+	decision := &Decision{}
+	if err := evalInto(p.skip, vars, "skip", &decision.Skip); err != nil {
+		return nil, err
+	}
+	if err := evalInto(p.channel, vars, "channel", &decision.Channel); err != nil {
+		return nil, err
+	}
+	if err := evalInto(p.cc, vars, "cc", &decision.CC); err != nil {
+		return nil, err
+	}
+	if err := evalInto(p.emoji, vars, "emoji", &decision.Emoji); err != nil {
+		return nil, err
+	}
+	decision.Channel = strings.TrimPrefix(decision.Channel, "#")
+
+	return decision, nil
+}
+
+// evalInto evaluates an optional program and stores its native result in target.
+func evalInto[T any](program cel.Program, vars map[string]any, name string, target *T) error {
+	if program == nil {
+		return nil
+	}
+
+	out, _, err := program.Eval(vars)
+	if err != nil {
+		return fmt.Errorf("failed to evaluate policy %s: %w", name, err)
+	}
+
+	native, err := out.ConvertToNative(reflectTypeOf[T]())
+	if err != nil {
+		return fmt.Errorf("failed to convert policy %s result: %w", name, err)
+	}
+	value, ok := native.(T)
+	if !ok {
+		return fmt.Errorf("%w: %s returned %T", ErrInvalidExpression, name, native)
+	}
+	*target = value
+	return nil
+}
+
+// reflectTypeOf returns the reflect.Type of T.
+func reflectTypeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// Engine caches compiled policies, so each workspace policy is compiled once per instance.
+type Engine struct {
+	mu       sync.Mutex
+	policies map[string]*Policy
+}
+
+// NewEngine creates a new policy engine.
+func NewEngine() *Engine {
+	return &Engine{policies: make(map[string]*Policy)}
+}
+
+// Get returns the compiled policy, compiling it on first use.
+func (e *Engine) Get(notificationPolicy *models.NotificationPolicy) (*Policy, error) {
+	key := notificationPolicy.CacheKey()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if compiled, ok := e.policies[key]; ok {
+		return compiled, nil
+	}
+
+	compiled, err := Compile(notificationPolicy)
+	if err != nil {
+		return nil, err
+	}
+	e.policies[key] = compiled
+	return compiled, nil
+}
@@ -0,0 +1,152 @@
+package policy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github-slack-notifier/internal/models"
+)
+
+func TestPolicy_Evaluate(t *testing.T) {
+	input := &Input{
+		Repo:         "org/api",
+		Author:       "alice",
+		Title:        "Add endpoint",
+		BaseBranch:   "main",
+		Labels:       []string{"security"},
+		Files:        []string{"db/migrations/001.sql", "api/handler.go"},
+		Additions:    900,
+		Deletions:    200,
+		ChangedFiles: 2,
+		Time:         time.Date(2024, 1, 6, 22, 0, 0, 0, time.UTC), // Saturday
+	}
+
+	tests := []struct {
+		name     string
+		policy   *models.NotificationPolicy
+		expected *Decision
+	}{
+		{
+			name:     "skip by author",
+			policy:   &models.NotificationPolicy{Skip: `author == "alice"`},
+			expected: &Decision{Skip: true},
+		},
+		{
+			name:     "route by label",
+			policy:   &models.NotificationPolicy{Channel: `"security" in labels ? "#security-reviews" : ""`},
+			expected: &Decision{Channel: "security-reviews"},
+		},
+		{
+			name: "cc and emoji on large PRs",
+			policy: &models.NotificationPolicy{
+				CC:    `size > 1000 ? ["tech-lead"] : []`,
+				Emoji: `size > 1000 ? ":whale:" : ""`,
+			},
+			expected: &Decision{CC: []string{"tech-lead"}, Emoji: ":whale:"},
+		},
+		{
+			name:     "route by file path",
+			policy:   &models.NotificationPolicy{Channel: `files.exists(f, f.startsWith("db/migrations/")) ? "dba" : ""`},
+			expected: &Decision{Channel: "dba"},
+		},
+		{
+			name:     "skip outside working hours",
+			policy:   &models.NotificationPolicy{Skip: `weekday == 0 || weekday == 6 || hour >= 20`},
+			expected: &Decision{Skip: true},
+		},
+		{
+			name:     "no change",
+			policy:   &models.NotificationPolicy{Skip: `draft`, Channel: `base_branch == "release" ? "releases" : ""`},
+			expected: &Decision{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			compiled, err := Compile(tt.policy)
+			require.NoError(t, err)
+
+			decision, err := compiled.Evaluate(input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, decision)
+		})
+	}
+}
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      *models.NotificationPolicy
+		expectErr   error
+		expectFiles bool
+	}{
+		{
+			name:      "empty policy",
+			policy:    &models.NotificationPolicy{Skip: "  "},
+			expectErr: ErrEmptyPolicy,
+		},
+		{
+			name:      "syntax error",
+			policy:    &models.NotificationPolicy{Skip: `size >`},
+			expectErr: ErrInvalidExpression,
+		},
+		{
+			name:      "unknown variable",
+			policy:    &models.NotificationPolicy{Skip: `reviewers.size() > 2`},
+			expectErr: ErrInvalidExpression,
+		},
+		{
+			name:      "type error",
+			policy:    &models.NotificationPolicy{Skip: `size > "big"`},
+			expectErr: ErrInvalidExpression,
+		},
+		{
+			name:      "wrong output type",
+			policy:    &models.NotificationPolicy{Channel: `size > 10`},
+			expectErr: ErrInvalidExpression,
+		},
+		{
+			name:        "references files",
+			policy:      &models.NotificationPolicy{Skip: `files.size() > 0`},
+			expectFiles: true,
+		},
+		{
+			name:        "doesn't reference files",
+			policy:      &models.NotificationPolicy{Skip: `changed_files > 10`},
+			expectFiles: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			compiled, err := Compile(tt.policy)
+			if tt.expectErr != nil {
+				require.ErrorIs(t, err, tt.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectFiles, compiled.UsesFiles())
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	require.NoError(t, Validate(&models.NotificationPolicy{Skip: `draft`, CC: `["alice"]`}))
+	require.ErrorIs(t, Validate(&models.NotificationPolicy{CC: `"alice"`}), ErrInvalidExpression)
+}
+
+func TestEngine_Get(t *testing.T) {
+	engine := NewEngine()
+
+	first, err := engine.Get(&models.NotificationPolicy{Skip: `draft`})
+	require.NoError(t, err)
+	second, err := engine.Get(&models.NotificationPolicy{Skip: `draft`})
+	require.NoError(t, err)
+	assert.Same(t, first, second)
+
+	_, err = engine.Get(&models.NotificationPolicy{Skip: `size >`})
+	require.ErrorIs(t, err, ErrInvalidExpression)
+}
@@ -0,0 +1,156 @@
+// Package presentation resolves how a tracked PR message is presented from a combination of PR states.
+//
+// Rules map state combinations to a presentation, and the first matching rule wins. Rules are written
+// one per semicolon-separated entry, with conditions joined by "&&":
+//
+//	ci=failure && draft -> collapsed
+//	approvals>=2 && ci=success && !draft -> ready_to_merge
+//
+// Conditions are `draft`, `!draft`, `ci=<success|failure|pending|none>` and `approvals>=N`.
+// Closed PRs always use the default presentation.
+package presentation
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github-slack-notifier/internal/models"
+)
+
+// DefaultRules are the rules used when PRESENTATION_RULES isn't set: drafts with failing CI are collapsed,
+// and PRs with two approvals and passing CI are highlighted as ready to merge.
+const DefaultRules = "ci=failure && draft -> collapsed; approvals>=2 && ci=success && !draft -> ready_to_merge"
+
+// DisabledRules turns presentation rules off, so messages always use the default presentation.
+const DisabledRules = "none"
+
+var (
+	ErrInvalidRule         = errors.New("invalid presentation rule")
+	ErrUnknownCondition    = errors.New("unknown presentation condition")
+	ErrUnknownPresentation = errors.New("unknown presentation")
+)
+
+// State is the combination of PR states a rule can match.
+type State struct {
+	Closed    bool
+	Draft     bool
+	CIState   models.CIState
+	Approvals int
+}
+
+// Rule maps a combination of PR states to a presentation.
+type Rule struct {
+	Draft        *bool           // Matches the PR's draft status, or any status when nil
+	CIState      *models.CIState // Matches the CI state, or any state when nil
+	MinApprovals int             // Matches at least this many approvals
+	Presentation string
+}
+
+// Matches reports whether the rule's conditions all hold for the state.
+func (r Rule) Matches(state State) bool {
+	if r.Draft != nil && *r.Draft != state.Draft {
+		return false
+	}
+	if r.CIState != nil && *r.CIState != state.CIState {
+		return false
+	}
+	return state.Approvals >= r.MinApprovals
+}
+
+// Resolver chooses the presentation of PR messages from their state.
+type Resolver struct {
+	rules []Rule
+}
+
+// NewResolver creates a resolver for the given rules. A resolver without rules always
+// resolves to the default presentation.
+func NewResolver(rules []Rule) *Resolver {
+	return &Resolver{rules: rules}
+}
+
+// Resolve returns the presentation for the state, from the first matching rule.
+func (r *Resolver) Resolve(state State) string {
+	if r == nil || state.Closed {
+		return models.PresentationDefault
+	}
+	for _, rule := range r.rules {
+		if rule.Matches(state) {
+			return rule.Presentation
+		}
+	}
+	return models.PresentationDefault
+}
+
+// ParseRules parses semicolon-separated presentation rules. DisabledRules or empty text means no rules.
+func ParseRules(text string) ([]Rule, error) {
+	text = strings.TrimSpace(text)
+	if text == "" || strings.EqualFold(text, DisabledRules) {
+		return nil, nil
+	}
+
+	var rules []Rule
+	for _, entry := range strings.Split(text, ";") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		rule, err := parseRule(entry)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", entry, err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// parseRule parses a single "conditions -> presentation" rule.
+func parseRule(entry string) (Rule, error) {
+	conditions, presentation, found := strings.Cut(entry, "->")
+	if !found || strings.TrimSpace(conditions) == "" {
+		return Rule{}, fmt.Errorf("%w: expected \"<conditions> -> <presentation>\"", ErrInvalidRule)
+	}
+
+	rule := Rule{Presentation: strings.TrimSpace(presentation)}
+	switch rule.Presentation {
+	case models.PresentationCollapsed, models.PresentationReadyToMerge:
+	default:
+		return Rule{}, fmt.Errorf("%w: %q (must be %s or %s)", ErrUnknownPresentation, rule.Presentation,
+			models.PresentationCollapsed, models.PresentationReadyToMerge)
+	}
+
+	for _, condition := range strings.Split(conditions, "&&") {
+		if err := rule.addCondition(strings.TrimSpace(condition)); err != nil {
+			return Rule{}, err
+		}
+	}
+	return rule, nil
+}
+
+// addCondition adds a single condition to the rule.
+func (r *Rule) addCondition(condition string) error {
+	switch {
+	case condition == "draft" || condition == "!draft":
+		draft := condition == "draft"
+		r.Draft = &draft
+	case strings.HasPrefix(condition, "ci="):
+		state := models.CIState(strings.TrimPrefix(condition, "ci="))
+		switch state {
+		case models.CIStateSuccess, models.CIStateFailure, models.CIStatePending:
+		case "none":
+			state = models.CIStateNone
+		default:
+			return fmt.Errorf("%w: %q (CI state must be success, failure, pending or none)", ErrUnknownCondition, condition)
+		}
+		r.CIState = &state
+	case strings.HasPrefix(condition, "approvals>="):
+		count, err := strconv.Atoi(strings.TrimPrefix(condition, "approvals>="))
+		if err != nil || count < 0 {
+			return fmt.Errorf("%w: %q (approvals must be a non-negative number)", ErrUnknownCondition, condition)
+		}
+		r.MinApprovals = count
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownCondition, condition)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package presentation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github-slack-notifier/internal/models"
+)
+
+func TestResolver_DefaultRules(t *testing.T) {
+	rules, err := ParseRules(DefaultRules)
+	require.NoError(t, err)
+	resolver := NewResolver(rules)
+
+	tests := []struct {
+		name     string
+		state    State
+		expected string
+	}{
+		{
+			name:     "draft with failing CI is collapsed",
+			state:    State{Draft: true, CIState: models.CIStateFailure},
+			expected: models.PresentationCollapsed,
+		},
+		{
+			name:     "failing CI alone keeps the default",
+			state:    State{CIState: models.CIStateFailure},
+			expected: models.PresentationDefault,
+		},
+		{
+			name:     "draft alone keeps the default",
+			state:    State{Draft: true, CIState: models.CIStatePending},
+			expected: models.PresentationDefault,
+		},
+		{
+			name:     "two approvals with green CI is ready to merge",
+			state:    State{CIState: models.CIStateSuccess, Approvals: 2},
+			expected: models.PresentationReadyToMerge,
+		},
+		{
+			name:     "one approval isn't enough",
+			state:    State{CIState: models.CIStateSuccess, Approvals: 1},
+			expected: models.PresentationDefault,
+		},
+		{
+			name:     "approvals with failing CI",
+			state:    State{CIState: models.CIStateFailure, Approvals: 3},
+			expected: models.PresentationDefault,
+		},
+		{
+			name:     "closed PRs use the default",
+			state:    State{Closed: true, CIState: models.CIStateSuccess, Approvals: 2},
+			expected: models.PresentationDefault,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, resolver.Resolve(tt.state))
+		})
+	}
+}
+
+func TestResolver_FirstMatchWins(t *testing.T) {
+	rules, err := ParseRules("approvals>=1 -> ready_to_merge; ci=none -> collapsed")
+	require.NoError(t, err)
+
+	assert.Equal(t, models.PresentationReadyToMerge, NewResolver(rules).Resolve(State{Approvals: 1}))
+	assert.Equal(t, models.PresentationCollapsed, NewResolver(rules).Resolve(State{}))
+}
+
+func TestResolver_NoRules(t *testing.T) {
+	for _, text := range []string{"", "none", " NONE "} {
+		rules, err := ParseRules(text)
+		require.NoError(t, err)
+		assert.Empty(t, rules)
+	}
+
+	var nilResolver *Resolver
+	assert.Equal(t, models.PresentationDefault, nilResolver.Resolve(State{Draft: true, CIState: models.CIStateFailure}))
+	assert.Equal(t, models.PresentationDefault, NewResolver(nil).Resolve(State{Draft: true, CIState: models.CIStateFailure}))
+}
+
+func TestParseRules_Invalid(t *testing.T) {
+	tests := []struct {
+		text        string
+		expectedErr error
+	}{
+		{text: "draft collapsed", expectedErr: ErrInvalidRule},
+		{text: " -> collapsed", expectedErr: ErrInvalidRule},
+		{text: "draft -> hidden", expectedErr: ErrUnknownPresentation},
+		{text: "ci=red -> collapsed", expectedErr: ErrUnknownCondition},
+		{text: "approvals>=two -> ready_to_merge", expectedErr: ErrUnknownCondition},
+		{text: "draft && author=bot -> collapsed", expectedErr: ErrUnknownCondition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			_, err := ParseRules("draft -> collapsed; " + tt.text)
+			require.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+}
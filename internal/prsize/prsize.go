@@ -0,0 +1,171 @@
+// Package prsize parses, validates and resolves the PR size emoji configurations that pick the emoji
+// shown on PR messages by lines changed.
+//
+// Configurations can be set by a PR's author, by the channel a PR is posted to, and for the whole workspace.
+// The most specific one that's enabled wins: user, then channel, then workspace, then the default animal emoji.
+package prsize
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github-slack-notifier/internal/models"
+	"github-slack-notifier/internal/utils"
+)
+
+// Level is where the PR size emoji configuration that applies to a message was set.
+type Level string
+
+const (
+	LevelUser      Level = "user"
+	LevelChannel   Level = "channel"
+	LevelWorkspace Level = "workspace"
+	LevelDefault   Level = "default" // No configuration is enabled, so the default animal emoji are used
+)
+
+var (
+	// ErrInvalidFormat is returned for lines that aren't an emoji followed by a line count.
+	ErrInvalidFormat = errors.New("invalid PR size threshold format")
+	// ErrInvalidEmoji is returned for emoji that are neither :name: aliases nor Unicode emoji.
+	ErrInvalidEmoji = errors.New("invalid PR size emoji")
+	// ErrInvalidMaxLines is returned for line counts that aren't positive whole numbers.
+	ErrInvalidMaxLines = errors.New("invalid PR size max lines")
+	// ErrThresholdsNotAscending is returned when a threshold's line count isn't above the previous one.
+	ErrThresholdsNotAscending = errors.New("PR size thresholds must be in ascending order")
+)
+
+// thresholdParts is the number of fields on each configuration line: the emoji and its max lines.
+const thresholdParts = 2
+
+// unicodeEmojiPattern matches the most common Unicode emoji ranges.
+var unicodeEmojiPattern = regexp.MustCompile(
+	`^[\x{1F600}-\x{1F64F}]|[\x{1F300}-\x{1F5FF}]|[\x{1F680}-\x{1F6FF}]|` +
+		`[\x{1F1E0}-\x{1F1FF}]|[\x{2600}-\x{26FF}]|[\x{2700}-\x{27BF}]$`,
+)
+
+// ParseError describes the line of a PR size configuration that failed validation.
+// Its message is written to be shown under the modal input the configuration was entered in.
+type ParseError struct {
+	Line   int    // 1-based, counting only non-empty lines
+	Reason string // What's wrong with the line
+	Err    error  // One of the Err* sentinels
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("Line %d: %s", e.Line, e.Reason)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Parse parses a PR size configuration entered as one `emoji max_lines` pair per line, in ascending order
+// of max lines. Empty text, or text with only blank lines, returns a disabled configuration, which inherits
+// the next level's. Returns a *ParseError for the first invalid line.
+func Parse(text string) (*models.PRSizeConfiguration, error) {
+	var thresholds []models.PRSizeThreshold
+	lineNum := 0
+	lastMaxLines := 0
+
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		lineNum++
+
+		parts := strings.Fields(line)
+		if len(parts) != thresholdParts {
+			return nil, &ParseError{Line: lineNum, Reason: "Format must be 'emoji max_lines' (e.g., ':ant: 5')", Err: ErrInvalidFormat}
+		}
+		emoji := parts[0]
+		if !IsValidEmoji(emoji) {
+			return nil, &ParseError{Line: lineNum, Reason: "Invalid emoji format. Use ':emoji_name:' or Unicode emoji", Err: ErrInvalidEmoji}
+		}
+		maxLines, err := strconv.Atoi(parts[1])
+		if err != nil || maxLines <= 0 {
+			return nil, &ParseError{Line: lineNum, Reason: "Max lines must be a positive number", Err: ErrInvalidMaxLines}
+		}
+		if maxLines <= lastMaxLines {
+			return nil, &ParseError{
+				Line:   lineNum,
+				Reason: fmt.Sprintf("Max lines (%d) must be greater than previous (%d)", maxLines, lastMaxLines),
+				Err:    ErrThresholdsNotAscending,
+			}
+		}
+
+		thresholds = append(thresholds, models.PRSizeThreshold{MaxLines: maxLines, Emoji: emoji})
+		lastMaxLines = maxLines
+	}
+
+	if len(thresholds) == 0 {
+		return &models.PRSizeConfiguration{Enabled: false}, nil
+	}
+	return &models.PRSizeConfiguration{Enabled: true, Thresholds: thresholds}, nil
+}
+
+// IsValidEmoji reports whether a string is an emoji in :emoji_name: format or a Unicode emoji.
+func IsValidEmoji(emoji string) bool {
+	if strings.HasPrefix(emoji, ":") && strings.HasSuffix(emoji, ":") && strings.Trim(emoji, ":") != "" {
+		return true
+	}
+	return unicodeEmojiPattern.MatchString(emoji)
+}
+
+// IsSet reports whether a configuration is enabled with thresholds, rather than inheriting the next level's.
+func IsSet(config *models.PRSizeConfiguration) bool {
+	return config != nil && config.Enabled && len(config.Thresholds) > 0
+}
+
+// Resolve returns the configuration that applies to a PR message and the level it was set at,
+// taking the user's, then the channel's, then the workspace's. Returns nil and LevelDefault if none is set.
+func Resolve(user, channel, workspace *models.PRSizeConfiguration) (*models.PRSizeConfiguration, Level) {
+	switch {
+	case IsSet(user):
+		return user, LevelUser
+	case IsSet(channel):
+		return channel, LevelChannel
+	case IsSet(workspace):
+		return workspace, LevelWorkspace
+	default:
+		return nil, LevelDefault
+	}
+}
+
+// Emoji returns the emoji for a PR's lines changed under a resolved configuration,
+// or the default animal emoji when config is nil.
+func Emoji(linesChanged int, config *models.PRSizeConfiguration) string {
+	if emoji := config.GetCustomPRSizeEmoji(linesChanged); emoji != "" {
+		return emoji
+	}
+	return utils.GetPRSizeEmoji(linesChanged)
+}
+
+// Describe summarises a configuration for settings screens, e.g. "Custom emoji (4 thresholds)".
+func Describe(config *models.PRSizeConfiguration) string {
+	if !IsSet(config) {
+		return "Not set"
+	}
+	if len(config.Thresholds) == 1 {
+		return "Custom emoji (1 threshold)"
+	}
+	return fmt.Sprintf("Custom emoji (%d thresholds)", len(config.Thresholds))
+}
+
+// IsExcludedPath reports whether a changed file is left out of a PR's lines changed by the exclude patterns.
+// Patterns without a slash match the file's name in any directory, e.g. "*.lock"; others match the whole
+// path, with "**" matching any number of directories, e.g. "vendor/**".
+func IsExcludedPath(patterns []string, file string) bool {
+	for _, pattern := range patterns {
+		if !strings.Contains(pattern, "/") {
+			pattern = "**/" + pattern
+		}
+		if utils.MatchPathGlob(pattern, file) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package prsize
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github-slack-notifier/internal/models"
+)
+
+func config(emoji string) *models.PRSizeConfiguration {
+	return &models.PRSizeConfiguration{
+		Enabled:    true,
+		Thresholds: []models.PRSizeThreshold{{MaxLines: 100, Emoji: emoji}},
+	}
+}
+
+func TestResolve(t *testing.T) {
+	user := config(":user:")
+	channel := config(":channel:")
+	workspace := config(":workspace:")
+	disabled := &models.PRSizeConfiguration{Enabled: false, Thresholds: user.Thresholds}
+
+	tests := []struct {
+		name      string
+		user      *models.PRSizeConfiguration
+		channel   *models.PRSizeConfiguration
+		workspace *models.PRSizeConfiguration
+		expected  *models.PRSizeConfiguration
+		level     Level
+	}{
+		{name: "user wins over channel and workspace", user: user, channel: channel, workspace: workspace, expected: user, level: LevelUser},
+		{name: "channel wins over workspace", channel: channel, workspace: workspace, expected: channel, level: LevelChannel},
+		{name: "disabled user config inherits channel", user: disabled, channel: channel, expected: channel, level: LevelChannel},
+		{name: "workspace used when nothing more specific", user: disabled, workspace: workspace, expected: workspace, level: LevelWorkspace},
+		{
+			name: "empty channel config inherits workspace", channel: &models.PRSizeConfiguration{Enabled: true},
+			workspace: workspace, expected: workspace, level: LevelWorkspace,
+		},
+		{name: "nothing set uses defaults", level: LevelDefault},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolved, level := Resolve(tt.user, tt.channel, tt.workspace)
+			assert.Same(t, tt.expected, resolved)
+			assert.Equal(t, tt.level, level)
+		})
+	}
+}
+
+func TestEmoji(t *testing.T) {
+	assert.Equal(t, ":channel:", Emoji(50, config(":channel:")))
+	// Larger PRs than the last threshold still get its emoji
+	assert.Equal(t, ":channel:", Emoji(5000, config(":channel:")))
+	assert.Equal(t, ":raccoon:", Emoji(50, nil))
+}
+
+func TestParse(t *testing.T) {
+	parsed, err := Parse(":ant: 5\n\n🐭 20\n:custom_large: 500")
+	require.NoError(t, err)
+	assert.True(t, parsed.Enabled)
+	assert.Equal(t, []models.PRSizeThreshold{
+		{MaxLines: 5, Emoji: ":ant:"},
+		{MaxLines: 20, Emoji: "🐭"},
+		{MaxLines: 500, Emoji: ":custom_large:"},
+	}, parsed.Thresholds)
+
+	parsed, err = Parse("  \n ")
+	require.NoError(t, err)
+	assert.False(t, IsSet(parsed))
+
+	_, err = Parse(":ant: 5\n:mouse2: 5")
+	var parseErr *ParseError
+	require.ErrorAs(t, err, &parseErr)
+	assert.Equal(t, 2, parseErr.Line)
+	assert.True(t, errors.Is(err, ErrThresholdsNotAscending))
+	assert.Equal(t, "Line 2: Max lines (5) must be greater than previous (5)", err.Error())
+
+	_, err = Parse("ant 5")
+	require.ErrorIs(t, err, ErrInvalidEmoji)
+	_, err = Parse(":ant: many")
+	require.ErrorIs(t, err, ErrInvalidMaxLines)
+	_, err = Parse(":ant:")
+	require.ErrorIs(t, err, ErrInvalidFormat)
+}
+
+func TestDescribe(t *testing.T) {
+	assert.Equal(t, "Not set", Describe(nil))
+	assert.Equal(t, "Not set", Describe(&models.PRSizeConfiguration{Enabled: false}))
+	assert.Equal(t, "Custom emoji (1 threshold)", Describe(config(":ant:")))
+}
+
+func TestIsExcludedPath(t *testing.T) {
+	patterns := []string{"vendor/**", "*.lock", "generated/**", "api/*.pb.go"}
+
+	tests := []struct {
+		file     string
+		expected bool
+	}{
+		{file: "vendor/github.com/pkg/errors/errors.go", expected: true},
+		{file: "yarn.lock", expected: true},
+		{file: "web/package.lock", expected: true},
+		{file: "generated/client.go", expected: true},
+		{file: "api/service.pb.go", expected: true},
+		{file: "api/v1/service.pb.go", expected: false},
+		{file: "internal/vendor.go", expected: false},
+		{file: "cmd/main.go", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			assert.Equal(t, tt.expected, IsExcludedPath(patterns, tt.file))
+		})
+	}
+	assert.False(t, IsExcludedPath(nil, "yarn.lock"))
+}
@@ -0,0 +1,87 @@
+// Package routes registers the application's HTTP routes.
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github-slack-notifier/internal/config"
+	"github-slack-notifier/internal/handlers"
+	"github-slack-notifier/internal/middleware"
+)
+
+// APIVersionPrefix is the path prefix for the current API version.
+const APIVersionPrefix = "/v1"
+
+// Handlers bundles the HTTP handlers served by the application.
+type Handlers struct {
+	GitHub *handlers.GitHubHandler
+	Slack  *handlers.SlackHandler
+	Jobs   *handlers.JobProcessor
+	OAuth  *handlers.OAuthHandler
+	Admin  *handlers.AdminHandler  // Optional, only needed when the admin API is enabled
+	Health *handlers.HealthHandler // Optional, /readyz is only served when set
+}
+
+// Register registers all application routes under the versioned prefix.
+// The same routes remain available at their original unversioned paths, marked deprecated,
+// so existing GitHub webhook, Slack app, and Cloud Scheduler configurations keep working.
+func Register(router *gin.Engine, h *Handlers, cfg *config.Config) {
+	registerAPIRoutes(router.Group(APIVersionPrefix), h, cfg)
+	registerAPIRoutes(router.Group("", middleware.DeprecationMiddleware(APIVersionPrefix)), h, cfg)
+
+	// Unversioned infrastructure endpoints
+	router.GET("/healthz", handlers.HandleLiveness)
+	router.GET("/health", handlers.HandleLiveness) // Original liveness path, kept for existing probes
+	if h.Health != nil {
+		router.GET("/readyz", h.Health.HandleReadiness)
+	}
+	if cfg.IsAdminAPIEnabled() {
+		router.GET("/metrics", middleware.AdminAuthMiddleware(cfg), h.Admin.HandleMetrics)
+	}
+}
+
+// registerAPIRoutes registers the versionable API routes on a route group.
+func registerAPIRoutes(group *gin.RouterGroup, h *Handlers, cfg *config.Config) {
+	// Webhook routes
+	group.POST("/webhooks/github", h.GitHub.HandleWebhook)
+	group.POST("/webhooks/slack/events", h.Slack.HandleEvent)
+	group.POST("/webhooks/slack/interactions", h.Slack.HandleInteraction)
+	group.POST("/webhooks/slack/commands", h.Slack.HandleSlashCommand)
+
+	// Job processing route, authenticated as the configured job queue
+	jobAuth := middleware.JobAuthMiddleware(middleware.NewJobVerifier(cfg))
+	group.POST("/jobs/process", jobAuth, h.Jobs.ProcessJob)
+	group.POST("/jobs/cleanup", jobAuth, h.Slack.HandleCleanupTrigger) // Cloud Scheduler entry point for the cleanup job
+
+	// OAuth routes
+	group.GET("/auth/github/link", h.OAuth.HandleGitHubLink)
+	group.GET("/auth/github/callback", h.OAuth.HandleGitHubCallback)
+	if len(cfg.GitHubEnterpriseServers) > 0 {
+		group.GET("/auth/github/install", h.OAuth.HandleGitHubInstall)
+	}
+	if cfg.IsSlackOAuthEnabled() {
+		group.GET("/auth/slack/install", h.OAuth.HandleSlackInstall)
+		group.GET("/auth/slack/callback", h.OAuth.HandleSlackOAuthCallback)
+	}
+	if cfg.SlackUserTokenPosting {
+		group.GET("/auth/slack/user", h.OAuth.HandleSlackUserTokenAuthorize)
+		group.GET("/auth/slack/user/callback", h.OAuth.HandleSlackUserTokenCallback)
+	}
+
+	// Admin API routes (if an admin API key is configured)
+	if cfg.IsAdminAPIEnabled() {
+		admin := group.Group("/admin", middleware.AdminAuthMiddleware(cfg))
+		admin.GET("/directive-usage", h.Admin.HandleDirectiveUsage)
+		admin.GET("/notifications", h.Admin.HandleListNotifications)
+		admin.GET("/workspaces", h.Admin.HandleListWorkspaces)
+		admin.GET("/workspaces/:workspace_id/policy", h.Admin.HandleGetNotificationPolicy)
+		admin.PUT("/workspaces/:workspace_id/policy", h.Admin.HandlePutNotificationPolicy)
+		admin.DELETE("/workspaces/:workspace_id/policy", h.Admin.HandleDeleteNotificationPolicy)
+		admin.GET("/workspaces/:workspace_id/tracked-messages", h.Admin.HandleListTrackedMessages)
+		admin.GET("/workspaces/:workspace_id/users/:user_id/data", h.Admin.HandleExportUserData)
+		admin.DELETE("/workspaces/:workspace_id/users/:user_id/data", h.Admin.HandleDeleteUserData)
+
+		// Routing simulation for policy and routing rule editors
+		group.POST("/api/simulate-routing", middleware.AdminAuthMiddleware(cfg), h.GitHub.HandleSimulateRouting)
+	}
+}
@@ -0,0 +1,130 @@
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github-slack-notifier/internal/config"
+	"github-slack-notifier/internal/handlers"
+)
+
+func TestRegister(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := &config.Config{CloudTasksSecret: "secret", AdminAPIKey: "admin-key"}
+
+	router := gin.New()
+	Register(router, &Handlers{
+		GitHub: &handlers.GitHubHandler{},
+		Slack:  &handlers.SlackHandler{},
+		Jobs:   &handlers.JobProcessor{},
+		OAuth:  &handlers.OAuthHandler{},
+		Admin:  &handlers.AdminHandler{},
+	}, cfg)
+
+	tests := []struct {
+		name              string
+		method            string
+		path              string
+		expectStatus      int
+		expectDeprecation bool
+	}{
+		{
+			name:              "versioned route",
+			method:            http.MethodPost,
+			path:              "/v1/jobs/process",
+			expectStatus:      http.StatusUnauthorized,
+			expectDeprecation: false,
+		},
+		{
+			name:              "legacy route is aliased and deprecated",
+			method:            http.MethodPost,
+			path:              "/jobs/process",
+			expectStatus:      http.StatusUnauthorized,
+			expectDeprecation: true,
+		},
+		{
+			name:              "cleanup trigger requires job queue credentials",
+			method:            http.MethodPost,
+			path:              "/v1/jobs/cleanup",
+			expectStatus:      http.StatusUnauthorized,
+			expectDeprecation: false,
+		},
+		{
+			name:              "versioned admin route",
+			method:            http.MethodGet,
+			path:              "/v1/admin/directive-usage",
+			expectStatus:      http.StatusUnauthorized,
+			expectDeprecation: false,
+		},
+		{
+			name:              "user data deletion requires admin key",
+			method:            http.MethodDelete,
+			path:              "/v1/admin/workspaces/T123/users/U123/data",
+			expectStatus:      http.StatusUnauthorized,
+			expectDeprecation: false,
+		},
+		{
+			name:              "routing simulation requires admin key",
+			method:            http.MethodPost,
+			path:              "/v1/api/simulate-routing",
+			expectStatus:      http.StatusUnauthorized,
+			expectDeprecation: false,
+		},
+		{
+			name:              "user token routes are disabled by default",
+			method:            http.MethodGet,
+			path:              "/v1/auth/slack/user",
+			expectStatus:      http.StatusNotFound,
+			expectDeprecation: false,
+		},
+		{
+			name:              "GitHub host chooser for installs is disabled without GitHub Enterprise Server",
+			method:            http.MethodGet,
+			path:              "/v1/auth/github/install",
+			expectStatus:      http.StatusNotFound,
+			expectDeprecation: false,
+		},
+		{
+			name:              "liveness is unversioned",
+			method:            http.MethodGet,
+			path:              "/healthz",
+			expectStatus:      http.StatusOK,
+			expectDeprecation: false,
+		},
+		{
+			name:              "readiness is only served with a health handler",
+			method:            http.MethodGet,
+			path:              "/readyz",
+			expectStatus:      http.StatusNotFound,
+			expectDeprecation: false,
+		},
+		{
+			name:              "unknown route",
+			method:            http.MethodPost,
+			path:              "/v2/jobs/process",
+			expectStatus:      http.StatusNotFound,
+			expectDeprecation: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectStatus, w.Code)
+			if tt.expectDeprecation {
+				assert.Equal(t, "true", w.Header().Get("Deprecation"))
+				assert.Equal(t, "</v1"+tt.path+">; rel=\"successor-version\"", w.Header().Get("Link"))
+			} else {
+				assert.Empty(t, w.Header().Get("Deprecation"))
+			}
+		})
+	}
+}
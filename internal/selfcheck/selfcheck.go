@@ -0,0 +1,135 @@
+// Package selfcheck runs dependency checks and reports the results as a structured log,
+// both at startup and for the /readyz readiness endpoint.
+//
+// Each check is either hard (the service cannot work without it, e.g. Firestore) or soft
+// (a degraded dependency, e.g. one workspace's revoked Slack token). Callers decide whether
+// hard failures should stop the service from serving traffic.
+package selfcheck
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github-slack-notifier/internal/log"
+)
+
+// Status is the outcome of a single check.
+type Status string
+
+const (
+	StatusOK   Status = "ok"
+	StatusWarn Status = "warn" // A soft check failed
+	StatusFail Status = "fail" // A hard check failed
+)
+
+// Check is a single named dependency check.
+type Check struct {
+	Name string
+	Hard bool // Hard failures report StatusFail; soft failures report StatusWarn
+	Run  func(ctx context.Context) error
+}
+
+// Result is the outcome of running a check.
+type Result struct {
+	Name     string
+	Status   Status
+	Detail   string
+	Duration time.Duration
+}
+
+// Report holds the results of a self-check run.
+type Report struct {
+	Results []Result
+}
+
+// Run executes the checks sequentially, giving each its own timeout.
+func Run(ctx context.Context, checks []Check, timeout time.Duration) *Report {
+	report := &Report{Results: make([]Result, 0, len(checks))}
+	for _, check := range checks {
+		report.Results = append(report.Results, runCheck(ctx, check, timeout))
+	}
+	return report
+}
+
+// RunConcurrent executes the checks in parallel, giving each its own timeout, so the whole run takes
+// about as long as the slowest check. Results are in the same order as the checks.
+func RunConcurrent(ctx context.Context, checks []Check, timeout time.Duration) *Report {
+	report := &Report{Results: make([]Result, len(checks))}
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			report.Results[i] = runCheck(ctx, check, timeout)
+		}()
+	}
+	wg.Wait()
+	return report
+}
+
+func runCheck(ctx context.Context, check Check, timeout time.Duration) Result {
+	checkCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check.Run(checkCtx)
+	result := Result{Name: check.Name, Status: StatusOK, Duration: time.Since(start)}
+	if err != nil {
+		result.Detail = err.Error()
+		result.Status = StatusWarn
+		if check.Hard {
+			result.Status = StatusFail
+		}
+	}
+	return result
+}
+
+// HasHardFailures reports whether any hard check failed.
+func (r *Report) HasHardFailures() bool {
+	for _, result := range r.Results {
+		if result.Status == StatusFail {
+			return true
+		}
+	}
+	return false
+}
+
+// counts returns the number of results per status.
+func (r *Report) counts() (ok, warn, fail int) {
+	for _, result := range r.Results {
+		switch result.Status {
+		case StatusOK:
+			ok++
+		case StatusWarn:
+			warn++
+		case StatusFail:
+			fail++
+		}
+	}
+	return ok, warn, fail
+}
+
+// Log writes one line per check followed by a summary line.
+func (r *Report) Log(ctx context.Context) {
+	ctx = log.WithFields(ctx, log.LogFields{"component": "self_check"})
+
+	for _, result := range r.Results {
+		attrs := []any{
+			"check", result.Name,
+			"status", string(result.Status),
+			"duration_ms", result.Duration.Milliseconds(),
+		}
+		switch result.Status {
+		case StatusOK:
+			log.Info(ctx, "Self-check passed", attrs...)
+		case StatusWarn:
+			log.Warn(ctx, "Self-check degraded", append(attrs, "detail", result.Detail)...)
+		case StatusFail:
+			log.Error(ctx, "Self-check failed", append(attrs, "detail", result.Detail)...)
+		}
+	}
+
+	ok, warn, fail := r.counts()
+	log.Info(ctx, "Self-check complete", "ok", ok, "warn", warn, "fail", fail)
+}
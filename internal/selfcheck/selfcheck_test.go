@@ -0,0 +1,73 @@
+package selfcheck
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errUnavailable = errors.New("unavailable")
+
+func TestRun(t *testing.T) {
+	checks := []Check{
+		{Name: "ok", Hard: true, Run: func(context.Context) error { return nil }},
+		{Name: "soft", Run: func(context.Context) error { return errUnavailable }},
+		{Name: "hard", Hard: true, Run: func(context.Context) error { return errUnavailable }},
+		{Name: "slow", Run: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}},
+	}
+
+	report := Run(context.Background(), checks, 10*time.Millisecond)
+	require.Len(t, report.Results, 4)
+
+	assert.Equal(t, StatusOK, report.Results[0].Status)
+	assert.Empty(t, report.Results[0].Detail)
+	assert.Equal(t, StatusWarn, report.Results[1].Status)
+	assert.Equal(t, "unavailable", report.Results[1].Detail)
+	assert.Equal(t, StatusFail, report.Results[2].Status)
+	assert.Equal(t, StatusWarn, report.Results[3].Status)
+	assert.Contains(t, report.Results[3].Detail, "deadline exceeded")
+	assert.True(t, report.HasHardFailures())
+}
+
+func TestReport_HasHardFailures_SoftOnly(t *testing.T) {
+	report := Run(context.Background(), []Check{
+		{Name: "soft", Run: func(context.Context) error { return errUnavailable }},
+	}, time.Second)
+
+	assert.False(t, report.HasHardFailures())
+}
+
+func TestRunConcurrent(t *testing.T) {
+	started := make(chan struct{})
+	checks := []Check{
+		// Only finishes once the second check has started, so it would time out if run sequentially
+		{Name: "waits", Hard: true, Run: func(ctx context.Context) error {
+			select {
+			case <-started:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}},
+		{Name: "starts", Run: func(context.Context) error {
+			close(started)
+			return errUnavailable
+		}},
+	}
+
+	report := RunConcurrent(context.Background(), checks, time.Second)
+	require.Len(t, report.Results, 2)
+
+	assert.Equal(t, "waits", report.Results[0].Name)
+	assert.Equal(t, StatusOK, report.Results[0].Status)
+	assert.Equal(t, "starts", report.Results[1].Name)
+	assert.Equal(t, StatusWarn, report.Results[1].Status)
+	assert.False(t, report.HasHardFailures())
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/tracing"
+)
+
+// apiLoggingTransport logs every outbound API call with the trace ID and log fields of the request context,
+// and records it as a span of the request context's trace.
+// API calls must be made with the caller's context (e.g. the *Context Slack methods) for the trace to carry through.
+type apiLoggingTransport struct {
+	api  string            // "slack" or "github"
+	base http.RoundTripper // Underlying transport; http.DefaultTransport if nil
+}
+
+// newAPILoggingTransport wraps a transport with outbound API call logging.
+func newAPILoggingTransport(api string, base http.RoundTripper) http.RoundTripper {
+	return &apiLoggingTransport{api: api, base: base}
+}
+
+// RoundTrip performs the request and logs its outcome.
+func (t *apiLoggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	ctx, span := tracing.Start(req.Context(), t.api+" "+req.Method+" "+req.URL.Path, trace.SpanKindClient,
+		semconv.HTTPMethod(req.Method),
+		semconv.ServerAddress(req.URL.Host),
+		semconv.URLPath(req.URL.Path),
+	)
+	startTime := time.Now()
+	resp, err := base.RoundTrip(req.WithContext(ctx))
+	duration := time.Since(startTime)
+
+	if err != nil {
+		tracing.End(span, err)
+		log.Warn(ctx, "Outbound API call failed",
+			"api", t.api,
+			"method", req.Method,
+			"host", req.URL.Host,
+			"path", req.URL.Path,
+			"duration_ms", duration.Milliseconds(),
+			"error", err,
+		)
+		return resp, err
+	}
+
+	span.SetAttributes(semconv.HTTPStatusCode(resp.StatusCode))
+	var statusErr error
+	if resp.StatusCode >= http.StatusBadRequest {
+		statusErr = fmt.Errorf("%s API returned HTTP %d", t.api, resp.StatusCode)
+	}
+	tracing.End(span, statusErr)
+
+	log.Debug(ctx, "Outbound API call",
+		"api", t.api,
+		"method", req.Method,
+		"host", req.URL.Host,
+		"path", req.URL.Path,
+		"status", resp.StatusCode,
+		"duration_ms", duration.Milliseconds(),
+	)
+	return resp, nil
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github-slack-notifier/internal/log"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestAPILoggingTransport(t *testing.T) {
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	errNetwork := errors.New("connection reset")
+	transport := newAPILoggingTransport("slack", roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Path == "/api/fail" {
+			return nil, errNetwork
+		}
+		return &http.Response{StatusCode: http.StatusOK, Request: req}, nil
+	}))
+
+	ctx := log.WithTraceID(context.Background(), "trace-123")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://slack.com/api/chat.postMessage", nil)
+	require.NoError(t, err)
+	resp, err := transport.RoundTrip(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, buf.String(), "trace_id=trace-123")
+	assert.Contains(t, buf.String(), "path=/api/chat.postMessage")
+	assert.Contains(t, buf.String(), "status=200")
+
+	buf.Reset()
+	req, err = http.NewRequestWithContext(ctx, http.MethodPost, "https://slack.com/api/fail", nil)
+	require.NoError(t, err)
+	_, err = transport.RoundTrip(req) //nolint:bodyclose // No response on error
+	require.ErrorIs(t, err, errNetwork)
+	assert.Contains(t, buf.String(), "Outbound API call failed")
+	assert.Contains(t, buf.String(), "trace_id=trace-123")
+}
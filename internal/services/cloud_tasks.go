@@ -11,6 +11,7 @@ import (
 	"github-slack-notifier/internal/config"
 	"github-slack-notifier/internal/log"
 	"github-slack-notifier/internal/models"
+	"github-slack-notifier/internal/tracing"
 	"google.golang.org/api/option"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
@@ -95,22 +96,29 @@ func (cts *CloudTasksService) EnqueueJob(ctx context.Context, job *models.Job) e
 	queuePath := fmt.Sprintf("projects/%s/locations/%s/queues/%s",
 		cts.projectID, cts.location, cts.queueName)
 
+	scheduleTime := timestamppb.Now()
+	if !job.NotBefore.IsZero() {
+		scheduleTime = timestamppb.New(job.NotBefore)
+	}
+
 	task := &cloudtaskspb.Task{
 		MessageType: &cloudtaskspb.Task_HttpRequest{
 			HttpRequest: &cloudtaskspb.HttpRequest{
 				HttpMethod: cloudtaskspb.HttpMethod_POST,
 				Url:        cts.config.JobProcessorURL(),
 				Headers: map[string]string{
-					"Content-Type":         "application/json",
-					"X-Job-ID":             job.ID,
-					"X-Trace-ID":           job.TraceID,
-					"X-Cloud-Tasks-Secret": cts.config.CloudTasksSecret,
+					"Content-Type": "application/json",
+					"X-Job-ID":     job.ID,
+					"X-Trace-ID":   job.TraceID,
 				},
 				Body: payload,
 			},
 		},
-		ScheduleTime: timestamppb.Now(),
+		ScheduleTime: scheduleTime,
 	}
+	cts.addJobAuth(task.GetHttpRequest())
+	// Continue this trace in the job, so the job's spans and outbound calls join the originating request's trace
+	tracing.InjectHeaders(ctx, task.GetHttpRequest().Headers)
 
 	req := &cloudtaskspb.CreateTaskRequest{
 		Parent: queuePath,
@@ -149,3 +157,30 @@ func (cts *CloudTasksService) EnqueueJob(ctx context.Context, job *models.Job) e
 
 	return nil
 }
+
+// CheckQueue verifies that the configured Cloud Tasks queue exists and is reachable.
+func (cts *CloudTasksService) CheckQueue(ctx context.Context) error {
+	queuePath := fmt.Sprintf("projects/%s/locations/%s/queues/%s", cts.projectID, cts.location, cts.queueName)
+	if _, err := cts.client.GetQueue(ctx, &cloudtaskspb.GetQueueRequest{Name: queuePath}); err != nil {
+		return fmt.Errorf("failed to get queue %s: %w", queuePath, err)
+	}
+	return nil
+}
+
+// addJobAuth adds the credentials the job processor authenticates tasks with: the shared secret, or an OIDC token
+// Cloud Tasks mints for the configured service account.
+func (cts *CloudTasksService) addJobAuth(httpRequest *cloudtaskspb.HttpRequest) {
+	switch cts.config.JobAuth.Method {
+	case config.JobAuthOIDC:
+		httpRequest.AuthorizationHeader = &cloudtaskspb.HttpRequest_OidcToken{
+			OidcToken: &cloudtaskspb.OidcToken{
+				ServiceAccountEmail: cts.config.JobAuth.OIDCServiceAccount,
+				Audience:            cts.config.JobAuthAudience(),
+			},
+		}
+	case config.JobAuthMTLS:
+		// Cloud Tasks can't present client certificates; mTLS is for self-hosted queues
+	default:
+		httpRequest.Headers["X-Cloud-Tasks-Secret"] = cts.config.CloudTasksSecret
+	}
+}
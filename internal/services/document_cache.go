@@ -0,0 +1,100 @@
+package services
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// documentCacheTTL is how long cached Firestore documents are used before they're read again.
+// Writes made by this instance invalidate them sooner; writes by other instances show up within the TTL.
+const documentCacheTTL = 30 * time.Second
+
+// documentCacheCapacity is the most documents of each kind kept in memory; the least recently used are evicted.
+const documentCacheCapacity = 2000
+
+// documentCache is an in-process LRU cache of Firestore documents whose entries expire after a TTL,
+// for hot documents that are read far more often than they change, e.g. repositories and users.
+type documentCache[K comparable, V any] struct {
+	mu       sync.Mutex
+	capacity int
+	ttl      time.Duration
+	now      func() time.Time
+	order    *list.List // Most recently used at the front
+	entries  map[K]*list.Element
+}
+
+// documentCacheEntry is a cached document, with when it was read.
+type documentCacheEntry[K comparable, V any] struct {
+	key      K
+	value    V
+	cachedAt time.Time
+}
+
+// newDocumentCache creates an empty cache holding at most capacity documents for ttl each.
+func newDocumentCache[K comparable, V any](capacity int, ttl time.Duration) *documentCache[K, V] {
+	return &documentCache[K, V]{
+		capacity: capacity,
+		ttl:      ttl,
+		now:      time.Now,
+		order:    list.New(),
+		entries:  make(map[K]*list.Element),
+	}
+}
+
+// get returns the cached document for a key, if there is one that hasn't expired.
+func (c *documentCache[K, V]) get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var zero V
+	element, ok := c.entries[key]
+	if !ok {
+		return zero, false
+	}
+	entry := element.Value.(*documentCacheEntry[K, V]) //nolint:forcetypeassert // Only entries are stored
+	if c.now().Sub(entry.cachedAt) > c.ttl {
+		c.order.Remove(element)
+		delete(c.entries, key)
+		return zero, false
+	}
+	c.order.MoveToFront(element)
+	return entry.value, true
+}
+
+// set caches a document, evicting the least recently used one if the cache is full.
+func (c *documentCache[K, V]) set(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		c.order.Remove(element)
+	}
+	c.entries[key] = c.order.PushFront(&documentCacheEntry[K, V]{key: key, value: value, cachedAt: c.now()})
+
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*documentCacheEntry[K, V]).key) //nolint:forcetypeassert // Only entries are stored
+	}
+}
+
+// delete drops a cached document, e.g. after it's written.
+func (c *documentCache[K, V]) delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		c.order.Remove(element)
+		delete(c.entries, key)
+	}
+}
+
+// clear drops every cached document, for writes that can't tell which documents they changed.
+func (c *documentCache[K, V]) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.order.Init()
+	clear(c.entries)
+}
@@ -0,0 +1,39 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocumentCache(t *testing.T) {
+	now := time.Date(2025, 1, 10, 9, 0, 0, 0, time.UTC)
+	cache := newDocumentCache[string, int](2, 30*time.Second)
+	cache.now = func() time.Time { return now }
+
+	cache.set("a", 1)
+	cache.set("b", 2)
+	value, ok := cache.get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+
+	cache.set("c", 3)
+	_, ok = cache.get("b")
+	assert.False(t, ok, "the least recently used document is evicted")
+	_, ok = cache.get("a")
+	assert.True(t, ok)
+
+	cache.delete("a")
+	_, ok = cache.get("a")
+	assert.False(t, ok, "deleted documents are read again")
+
+	now = now.Add(31 * time.Second)
+	_, ok = cache.get("c")
+	assert.False(t, ok, "documents expire after the TTL")
+
+	cache.set("d", 4)
+	cache.clear()
+	_, ok = cache.get("d")
+	assert.False(t, ok)
+}
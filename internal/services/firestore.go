@@ -6,9 +6,11 @@ import (
 	"fmt"
 	"net/url"
 	"sort"
+	"strings"
 	"time"
 
 	"cloud.google.com/go/firestore"
+	firestorepb "cloud.google.com/go/firestore/apiv1/firestorepb"
 	"github-slack-notifier/internal/log"
 	"github-slack-notifier/internal/models"
 	"google.golang.org/api/iterator"
@@ -18,23 +20,44 @@ import (
 
 // Sentinel errors for not found cases.
 var (
-	ErrUserNotFound               = errors.New("user not found")
-	ErrTrackedMessageNotFound     = errors.New("tracked message not found")
-	ErrRepoNotFound               = errors.New("repository not found")
-	ErrRepoAlreadyExists          = errors.New("repository already exists")
-	ErrOAuthStateNotFound         = errors.New("OAuth state not found")
-	ErrGitHubInstallationNotFound = errors.New("GitHub installation not found")
-	ErrInvalidMessageID           = errors.New("message ID is required for update")
+	ErrUserNotFound                = errors.New("user not found")
+	ErrTrackedMessageNotFound      = errors.New("tracked message not found")
+	ErrRepoNotFound                = errors.New("repository not found")
+	ErrRepoAlreadyExists           = errors.New("repository already exists")
+	ErrOAuthStateNotFound          = errors.New("OAuth state not found")
+	ErrGitHubInstallationNotFound  = errors.New("GitHub installation not found")
+	ErrInvalidMessageID            = errors.New("message ID is required for update")
+	ErrUnexpectedAggregationResult = errors.New("unexpected aggregation result type")
 )
 
 // FirestoreService provides database operations for Firestore.
 type FirestoreService struct {
 	client *firestore.Client
+
+	// Recently read repositories and users for hot read paths, keyed by repo document ID and GitHub user ID.
+	// Writes through this service invalidate them.
+	repoCache *documentCache[string, *models.Repo]
+	userCache *documentCache[int64, *models.User]
 }
 
 // NewFirestoreService creates a new FirestoreService with the provided client.
 func NewFirestoreService(client *firestore.Client) *FirestoreService {
-	return &FirestoreService{client: client}
+	return &FirestoreService{
+		client:    client,
+		repoCache: newDocumentCache[string, *models.Repo](documentCacheCapacity, documentCacheTTL),
+		userCache: newDocumentCache[int64, *models.User](documentCacheCapacity, documentCacheTTL),
+	}
+}
+
+// Ping verifies that Firestore is reachable by reading at most one document.
+func (fs *FirestoreService) Ping(ctx context.Context) error {
+	iter := fs.client.Collection("users").Limit(1).Documents(ctx)
+	defer iter.Stop()
+
+	if _, err := iter.Next(); err != nil && !errors.Is(err, iterator.Done) {
+		return fmt.Errorf("failed to read from Firestore: %w", err)
+	}
+	return nil
 }
 
 // GetUserBySlackID retrieves a user by their Slack user ID.
@@ -170,6 +193,7 @@ func (fs *FirestoreService) CreateOrUpdateUser(ctx context.Context, user *models
 		user.CreatedAt = time.Now()
 	}
 
+	defer fs.userCache.clear()
 	_, err := fs.client.Collection("users").Doc(user.ID).Set(ctx, user)
 	if err != nil {
 		log.Error(ctx, "Failed to create or update user",
@@ -222,6 +246,7 @@ func (fs *FirestoreService) CreateRepo(ctx context.Context, repo *models.Repo) e
 	// WorkspaceID should already be set by caller
 
 	docID := fs.encodeRepoDocID(repo.WorkspaceID, repo.RepoFullName)
+	defer fs.repoCache.delete(docID)
 	_, err := fs.client.Collection("repos").Doc(docID).Set(ctx, repo)
 
 	if err != nil {
@@ -287,35 +312,15 @@ func (fs *FirestoreService) CreateRepoIfNotExists(ctx context.Context, repo *mod
 	})
 }
 
-// TrackedMessage operations for the new manual PR link tracking system.
-
-// GetTrackedMessages retrieves all tracked messages for a specific PR in a channel.
-// If messageSource is provided, filters by message source ("bot" or "manual").
-func (fs *FirestoreService) GetTrackedMessages(
-	ctx context.Context,
-	repoFullName string,
-	prNumber int,
-	slackChannel string,
-	slackTeamID string,
-	messageSource string,
+// GetRecentTrackedMessagesForChannel retrieves tracked messages posted in a channel since the given time.
+func (fs *FirestoreService) GetRecentTrackedMessagesForChannel(
+	ctx context.Context, slackTeamID, slackChannel string, since time.Time,
 ) ([]*models.TrackedMessage, error) {
-	query := fs.client.Collection("trackedmessages").
-		Where("repo_full_name", "==", repoFullName).
-		Where("pr_number", "==", prNumber)
-
-	if slackTeamID != "" {
-		query = query.Where("slack_team_id", "==", slackTeamID)
-	}
-
-	if slackChannel != "" {
-		query = query.Where("slack_channel", "==", slackChannel)
-	}
-
-	if messageSource != "" {
-		query = query.Where("message_source", "==", messageSource)
-	}
-
-	iter := query.Documents(ctx)
+	iter := fs.client.Collection("trackedmessages").
+		Where("slack_team_id", "==", slackTeamID).
+		Where("slack_channel", "==", slackChannel).
+		Where("created_at", ">=", since).
+		Documents(ctx)
 	defer iter.Stop()
 
 	var messages []*models.TrackedMessage
@@ -325,16 +330,7 @@ func (fs *FirestoreService) GetTrackedMessages(
 			if errors.Is(err, iterator.Done) {
 				break
 			}
-			log.Error(ctx, "Failed to query tracked messages",
-				"error", err,
-				"repo", repoFullName,
-				"pr_number", prNumber,
-				"slack_channel", slackChannel,
-				"slack_team_id", slackTeamID,
-				"message_source", messageSource,
-				"operation", "query_tracked_messages",
-			)
-			return nil, fmt.Errorf("failed to query tracked messages for repo %s PR %d team %s: %w", repoFullName, prNumber, slackTeamID, err)
+			return nil, fmt.Errorf("failed to query recent tracked messages for channel %s team %s: %w", slackChannel, slackTeamID, err)
 		}
 
 		var message models.TrackedMessage
@@ -354,6 +350,16 @@ func (fs *FirestoreService) GetTrackedMessages(
 	return messages, nil
 }
 
+// ListRecentTrackedMessages retrieves tracked messages created since the given time, across all workspaces.
+func (fs *FirestoreService) ListRecentTrackedMessages(ctx context.Context, since time.Time) ([]*models.TrackedMessage, error) {
+	query := fs.client.Collection("trackedmessages").Where("created_at", ">=", since)
+	messages, err := fs.queryTrackedMessages(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query recent tracked messages: %w", err)
+	}
+	return messages, nil
+}
+
 // GetTrackedMessageBySlackMessage retrieves a tracked message by its Slack message details.
 func (fs *FirestoreService) GetTrackedMessageBySlackMessage(
 	ctx context.Context,
@@ -481,6 +487,245 @@ func (fs *FirestoreService) MarkTrackedMessageDeleted(ctx context.Context, messa
 	return nil
 }
 
+// RecordTrackedMessageClick counts a click on a tracked message's "Open PR" button,
+// stamping the time of the first click.
+func (fs *FirestoreService) RecordTrackedMessageClick(ctx context.Context, message *models.TrackedMessage, clickedAt time.Time) error {
+	if message.ID == "" {
+		return ErrInvalidMessageID
+	}
+
+	updates := []firestore.Update{
+		{Path: "link_clicks", Value: firestore.Increment(1)},
+	}
+	if message.FirstClickedAt == nil {
+		updates = append(updates, firestore.Update{Path: "first_clicked_at", Value: clickedAt})
+	}
+
+	_, err := fs.client.Collection("trackedmessages").Doc(message.ID).Update(ctx, updates)
+	if err != nil {
+		log.Error(ctx, "Failed to record tracked message click",
+			"error", err,
+			"message_id", message.ID,
+			"operation", "record_tracked_message_click",
+		)
+		return fmt.Errorf("failed to record click for tracked message %s: %w", message.ID, err)
+	}
+
+	return nil
+}
+
+// UpdateTrackedMessageDependencies stores the dependency states for a tracked message.
+func (fs *FirestoreService) UpdateTrackedMessageDependencies(ctx context.Context, message *models.TrackedMessage) error {
+	if message.ID == "" {
+		return ErrInvalidMessageID
+	}
+
+	docRef := fs.client.Collection("trackedmessages").Doc(message.ID)
+	_, err := docRef.Update(ctx, []firestore.Update{
+		{Path: "dependencies", Value: message.Dependencies},
+		{Path: "dependency_keys", Value: message.DependencyKeys},
+		{Path: "has_open_dependencies", Value: message.HasOpenDependencies},
+	})
+	if err != nil {
+		log.Error(ctx, "Failed to update tracked message dependencies",
+			"error", err,
+			"message_id", message.ID,
+			"operation", "update_tracked_message_dependencies",
+		)
+		return fmt.Errorf("failed to update dependencies for tracked message %s: %w", message.ID, err)
+	}
+
+	return nil
+}
+
+// UpdateTrackedMessageProjectColumn stores the project board column for a tracked message.
+func (fs *FirestoreService) UpdateTrackedMessageProjectColumn(ctx context.Context, messageID, column string) error {
+	if messageID == "" {
+		return ErrInvalidMessageID
+	}
+
+	docRef := fs.client.Collection("trackedmessages").Doc(messageID)
+	_, err := docRef.Update(ctx, []firestore.Update{
+		{Path: "project_column", Value: column},
+	})
+	if err != nil {
+		log.Error(ctx, "Failed to update tracked message project column",
+			"error", err,
+			"message_id", messageID,
+			"operation", "update_tracked_message_project_column",
+		)
+		return fmt.Errorf("failed to update project column for tracked message %s: %w", messageID, err)
+	}
+
+	return nil
+}
+
+// UpdateTrackedMessageMovedBy records the Slack user who moved a tracked message to its channel.
+func (fs *FirestoreService) UpdateTrackedMessageMovedBy(ctx context.Context, messageID, movedBy string) error {
+	if messageID == "" {
+		return ErrInvalidMessageID
+	}
+
+	docRef := fs.client.Collection("trackedmessages").Doc(messageID)
+	_, err := docRef.Update(ctx, []firestore.Update{
+		{Path: "moved_by", Value: movedBy},
+	})
+	if err != nil {
+		log.Error(ctx, "Failed to update tracked message moved by",
+			"error", err,
+			"message_id", messageID,
+			"operation", "update_tracked_message_moved_by",
+		)
+		return fmt.Errorf("failed to update moved by for tracked message %s: %w", messageID, err)
+	}
+
+	return nil
+}
+
+// UpdateTrackedMessageMergeConflict records whether a tracked message's PR was last seen conflicting with its base branch.
+func (fs *FirestoreService) UpdateTrackedMessageMergeConflict(ctx context.Context, messageID string, conflicting bool) error {
+	if messageID == "" {
+		return ErrInvalidMessageID
+	}
+
+	docRef := fs.client.Collection("trackedmessages").Doc(messageID)
+	_, err := docRef.Update(ctx, []firestore.Update{
+		{Path: "merge_conflict", Value: conflicting},
+	})
+	if err != nil {
+		log.Error(ctx, "Failed to update tracked message merge conflict",
+			"error", err,
+			"message_id", messageID,
+			"operation", "update_tracked_message_merge_conflict",
+		)
+		return fmt.Errorf("failed to update merge conflict for tracked message %s: %w", messageID, err)
+	}
+
+	return nil
+}
+
+// UpdateTrackedMessageNoReviewersWarning records whether a tracked message shows the "No reviewers requested" line.
+func (fs *FirestoreService) UpdateTrackedMessageNoReviewersWarning(ctx context.Context, messageID string, shown bool) error {
+	if messageID == "" {
+		return ErrInvalidMessageID
+	}
+
+	docRef := fs.client.Collection("trackedmessages").Doc(messageID)
+	_, err := docRef.Update(ctx, []firestore.Update{
+		{Path: "no_reviewers_warning", Value: shown},
+	})
+	if err != nil {
+		log.Error(ctx, "Failed to update tracked message no reviewers warning",
+			"error", err,
+			"message_id", messageID,
+			"operation", "update_tracked_message_no_reviewers_warning",
+		)
+		return fmt.Errorf("failed to update no reviewers warning for tracked message %s: %w", messageID, err)
+	}
+
+	return nil
+}
+
+// UpdateTrackedMessagePresentation records the PR state a tracked message's presentation was resolved from,
+// and the presentation it's rendered with.
+func (fs *FirestoreService) UpdateTrackedMessagePresentation(ctx context.Context, message *models.TrackedMessage) error {
+	if message.ID == "" {
+		return ErrInvalidMessageID
+	}
+
+	docRef := fs.client.Collection("trackedmessages").Doc(message.ID)
+	_, err := docRef.Update(ctx, []firestore.Update{
+		{Path: "ci_state", Value: message.CIState},
+		{Path: "approvals", Value: message.Approvals},
+		{Path: "required_approvals", Value: message.RequiredApprovals},
+		{Path: "presentation", Value: message.Presentation},
+	})
+	if err != nil {
+		log.Error(ctx, "Failed to update tracked message presentation",
+			"error", err,
+			"message_id", message.ID,
+			"operation", "update_tracked_message_presentation",
+		)
+		return fmt.Errorf("failed to update presentation for tracked message %s: %w", message.ID, err)
+	}
+
+	return nil
+}
+
+// GetTrackedMessagesByDependency retrieves tracked messages for PRs that depend on the given "owner/repo#N" key.
+func (fs *FirestoreService) GetTrackedMessagesByDependency(ctx context.Context, dependencyKey string) ([]*models.TrackedMessage, error) {
+	query := fs.client.Collection("trackedmessages").Where("dependency_keys", "array-contains", dependencyKey)
+	messages, err := fs.queryTrackedMessages(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tracked messages depending on %s: %w", dependencyKey, err)
+	}
+	return messages, nil
+}
+
+// ListTrackedMessagesWithOpenDependencies retrieves tracked messages whose dependencies haven't all resolved.
+func (fs *FirestoreService) ListTrackedMessagesWithOpenDependencies(ctx context.Context) ([]*models.TrackedMessage, error) {
+	query := fs.client.Collection("trackedmessages").Where("has_open_dependencies", "==", true)
+	messages, err := fs.queryTrackedMessages(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tracked messages with open dependencies: %w", err)
+	}
+	return messages, nil
+}
+
+// queryTrackedMessages runs a tracked message query, skipping documents that fail to unmarshal.
+func (fs *FirestoreService) queryTrackedMessages(ctx context.Context, query firestore.Query) ([]*models.TrackedMessage, error) {
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	var messages []*models.TrackedMessage
+	for {
+		doc, err := iter.Next()
+		if err != nil {
+			if errors.Is(err, iterator.Done) {
+				break
+			}
+			return nil, err
+		}
+
+		var message models.TrackedMessage
+		if err := doc.DataTo(&message); err != nil {
+			log.Error(ctx, "Failed to unmarshal tracked message data",
+				"error", err,
+				"doc_id", doc.Ref.ID,
+				"operation", "unmarshal_tracked_message_data",
+			)
+			continue
+		}
+
+		messages = append(messages, &message)
+	}
+
+	return messages, nil
+}
+
+// MarkTrackedMessageSuperseded links a tracked message to the PR that superseded it.
+func (fs *FirestoreService) MarkTrackedMessageSuperseded(ctx context.Context, messageID string, supersededByPR int) error {
+	if messageID == "" {
+		return ErrInvalidMessageID
+	}
+
+	docRef := fs.client.Collection("trackedmessages").Doc(messageID)
+	_, err := docRef.Update(ctx, []firestore.Update{
+		{Path: "superseded_by_pr", Value: supersededByPR},
+	})
+	if err != nil {
+		log.Error(ctx, "Failed to mark tracked message as superseded",
+			"error", err,
+			"message_id", messageID,
+			"superseded_by_pr", supersededByPR,
+			"operation", "mark_tracked_message_superseded",
+		)
+		return fmt.Errorf("failed to mark tracked message %s as superseded: %w", messageID, err)
+	}
+
+	return nil
+}
+
 // DeleteTrackedMessages deletes multiple tracked messages by their IDs.
 func (fs *FirestoreService) DeleteTrackedMessages(ctx context.Context, messageIDs []string) error {
 	if len(messageIDs) == 0 {
@@ -512,6 +757,28 @@ func (fs *FirestoreService) DeleteTrackedMessages(ctx context.Context, messageID
 	return nil
 }
 
+// UpdateTrackedMessagesRepo points tracked messages at a repository's new name, after it was renamed or transferred.
+func (fs *FirestoreService) UpdateTrackedMessagesRepo(ctx context.Context, messageIDs []string, repoFullName string) error {
+	if len(messageIDs) == 0 {
+		return nil
+	}
+
+	err := fs.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		for _, messageID := range messageIDs {
+			docRef := fs.client.Collection("trackedmessages").Doc(messageID)
+			err := tx.Update(docRef, []firestore.Update{{Path: "repo_full_name", Value: repoFullName}})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update repository of %d tracked messages: %w", len(messageIDs), err)
+	}
+	return nil
+}
+
 // GetUser retrieves a user by their document ID (Slack user ID).
 func (fs *FirestoreService) GetUser(ctx context.Context, userID string) (*models.User, error) {
 	doc, err := fs.client.Collection("users").Doc(userID).Get(ctx)
@@ -548,6 +815,7 @@ func (fs *FirestoreService) SaveUser(ctx context.Context, user *models.User) err
 		user.CreatedAt = time.Now()
 	}
 
+	defer fs.userCache.clear()
 	_, err := fs.client.Collection("users").Doc(user.ID).Set(ctx, user)
 	if err != nil {
 		log.Error(ctx, "Failed to save user",
@@ -674,6 +942,7 @@ func (fs *FirestoreService) GetReposForAllWorkspaces(ctx context.Context, repoFu
 // DeleteRepo removes a repository configuration.
 func (fs *FirestoreService) DeleteRepo(ctx context.Context, repoFullName, workspaceID string) error {
 	docID := fs.encodeRepoDocID(workspaceID, repoFullName)
+	defer fs.repoCache.delete(docID)
 	_, err := fs.client.Collection("repos").Doc(docID).Delete(ctx)
 
 	if err != nil {
@@ -688,45 +957,1297 @@ func (fs *FirestoreService) DeleteRepo(ctx context.Context, repoFullName, worksp
 	return nil
 }
 
-// GetChannelConfig retrieves channel configuration.
-func (fs *FirestoreService) GetChannelConfig(ctx context.Context, slackTeamID, channelID string) (*models.ChannelConfig, error) {
-	docID := slackTeamID + "#" + channelID
-	doc, err := fs.client.Collection("channel_configs").Doc(docID).Get(ctx)
-	if err != nil {
-		if status.Code(err) == codes.NotFound {
-			return nil, nil // No config means use defaults
+// RenameRepo moves a repository's configuration in every workspace to its new name, after the repository was renamed
+// or transferred on GitHub. Repository documents are keyed by name, so each is re-created under the new name, and a
+// workspace which already has a configuration under the new name keeps it.
+// Returns the IDs of the workspaces whose configuration was moved.
+func (fs *FirestoreService) RenameRepo(ctx context.Context, oldFullName, newFullName string) ([]string, error) {
+	var workspaceIDs []string
+	err := fs.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		workspaceIDs = nil
+		docs, err := tx.Documents(fs.client.Collection("repos").Where("repo_full_name", "==", oldFullName)).GetAll()
+		if err != nil {
+			return err
 		}
-		return nil, fmt.Errorf("failed to get channel config: %w", err)
-	}
 
-	var config models.ChannelConfig
-	err = doc.DataTo(&config)
+		// Transactions must do all their reads before writing
+		type move struct {
+			oldRef, newRef *firestore.DocumentRef
+			repo           models.Repo
+			exists         bool
+		}
+		moves := make([]move, 0, len(docs))
+		for _, doc := range docs {
+			var repo models.Repo
+			if err := doc.DataTo(&repo); err != nil {
+				return fmt.Errorf("failed to unmarshal repository %s: %w", doc.Ref.ID, err)
+			}
+			newRef := fs.client.Collection("repos").Doc(fs.encodeRepoDocID(repo.WorkspaceID, newFullName))
+			_, err := tx.Get(newRef)
+			if err != nil && status.Code(err) != codes.NotFound {
+				return err
+			}
+			moves = append(moves, move{oldRef: doc.Ref, newRef: newRef, repo: repo, exists: err == nil})
+		}
+
+		for _, m := range moves {
+			if !m.exists {
+				m.repo.ID = newFullName
+				m.repo.RepoFullName = newFullName
+				if err := tx.Set(m.newRef, m.repo); err != nil {
+					return err
+				}
+			}
+			if err := tx.Delete(m.oldRef); err != nil {
+				return err
+			}
+			workspaceIDs = append(workspaceIDs, m.repo.WorkspaceID)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to unmarshal channel config: %w", err)
+		return nil, fmt.Errorf("failed to rename repo %s to %s: %w", oldFullName, newFullName, err)
 	}
 
-	return &config, nil
+	for _, workspaceID := range workspaceIDs {
+		fs.repoCache.delete(fs.encodeRepoDocID(workspaceID, oldFullName))
+		fs.repoCache.delete(fs.encodeRepoDocID(workspaceID, newFullName))
+	}
+	log.Info(ctx, "Repository renamed",
+		"old_repo", oldFullName,
+		"new_repo", newFullName,
+		"workspace_count", len(workspaceIDs),
+	)
+	return workspaceIDs, nil
 }
 
-// SaveChannelConfig creates or updates channel configuration.
-func (fs *FirestoreService) SaveChannelConfig(ctx context.Context, config *models.ChannelConfig) error {
-	config.UpdatedAt = time.Now()
-	if config.CreatedAt.IsZero() {
-		config.CreatedAt = time.Now()
+// UpdateRepoReleaseNotes sets the draft release notes configuration for a repository in a workspace.
+// A nil config removes the release notes configuration.
+func (fs *FirestoreService) UpdateRepoReleaseNotes(
+	ctx context.Context, repoFullName, workspaceID string, config *models.ReleaseNotesConfig,
+) error {
+	docID := fs.encodeRepoDocID(workspaceID, repoFullName)
+
+	var value interface{} = config
+	if config == nil {
+		value = firestore.Delete
 	}
 
-	docID := config.SlackTeamID + "#" + config.SlackChannelID
-	_, err := fs.client.Collection("channel_configs").Doc(docID).Set(ctx, config)
+	defer fs.repoCache.delete(docID)
+	_, err := fs.client.Collection("repos").Doc(docID).Update(ctx, []firestore.Update{
+		{Path: "release_notes", Value: value},
+	})
 	if err != nil {
-		return fmt.Errorf("failed to save channel config: %w", err)
+		return fmt.Errorf("failed to update release notes config for repo %s team %s: %w",
+			repoFullName, workspaceID, err)
 	}
 
+	log.Info(ctx, "Repository release notes configuration updated",
+		"repo", repoFullName,
+		"workspace_id", workspaceID,
+		"enabled", config != nil && config.Enabled,
+	)
 	return nil
 }
 
-// ListChannelConfigs retrieves all channel configurations for a workspace.
-func (fs *FirestoreService) ListChannelConfigs(ctx context.Context, slackTeamID string) ([]*models.ChannelConfig, error) {
-	iter := fs.client.Collection("channel_configs").
+// UpdateRepoMechanicalPRs sets how revert and back-merge PRs from a repository are announced in a workspace.
+// A nil config announces them like any other PR.
+func (fs *FirestoreService) UpdateRepoMechanicalPRs(
+	ctx context.Context, repoFullName, workspaceID string, config *models.MechanicalPRConfig,
+) error {
+	docID := fs.encodeRepoDocID(workspaceID, repoFullName)
+
+	var value interface{} = config
+	if config == nil {
+		value = firestore.Delete
+	}
+
+	defer fs.repoCache.delete(docID)
+	_, err := fs.client.Collection("repos").Doc(docID).Update(ctx, []firestore.Update{
+		{Path: "mechanical_prs", Value: value},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update mechanical PR config for repo %s team %s: %w",
+			repoFullName, workspaceID, err)
+	}
+
+	handling := ""
+	if config != nil {
+		handling = config.Handling
+	}
+	log.Info(ctx, "Repository mechanical PR configuration updated",
+		"repo", repoFullName,
+		"workspace_id", workspaceID,
+		"handling", handling,
+	)
+	return nil
+}
+
+// UpdateRepoRoutingRules sets the path and label routing rules for a repository in a workspace.
+// Empty rules remove the field, so the repository goes back to the author's default channel.
+func (fs *FirestoreService) UpdateRepoRoutingRules(
+	ctx context.Context, repoFullName, workspaceID string, rules []models.RoutingRule,
+) error {
+	docID := fs.encodeRepoDocID(workspaceID, repoFullName)
+
+	var value interface{} = rules
+	if len(rules) == 0 {
+		value = firestore.Delete
+	}
+
+	defer fs.repoCache.delete(docID)
+	_, err := fs.client.Collection("repos").Doc(docID).Update(ctx, []firestore.Update{
+		{Path: "routing_rules", Value: value},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update routing rules for repo %s team %s: %w",
+			repoFullName, workspaceID, err)
+	}
+
+	log.Info(ctx, "Repository routing rules updated",
+		"repo", repoFullName,
+		"workspace_id", workspaceID,
+		"rule_count", len(rules),
+	)
+	return nil
+}
+
+// ListReposForWorkspace returns the repositories configured in a workspace, sorted by name.
+func (fs *FirestoreService) ListReposForWorkspace(ctx context.Context, workspaceID string) ([]*models.Repo, error) {
+	iter := fs.client.Collection("repos").
+		Where("workspace_id", "==", workspaceID).
+		Documents(ctx)
+	defer iter.Stop()
+
+	var repos []*models.Repo
+	for {
+		doc, err := iter.Next()
+		if err != nil {
+			if errors.Is(err, iterator.Done) {
+				break
+			}
+			return nil, fmt.Errorf("failed to query repos for workspace %s: %w", workspaceID, err)
+		}
+
+		var repo models.Repo
+		if err := doc.DataTo(&repo); err != nil {
+			log.Error(ctx, "Failed to unmarshal repository",
+				"error", err,
+				"doc_id", doc.Ref.ID,
+			)
+			continue
+		}
+		repos = append(repos, &repo)
+	}
+
+	sort.Slice(repos, func(i, j int) bool { return repos[i].RepoFullName < repos[j].RepoFullName })
+	return repos, nil
+}
+
+// UpdateRepoNotificationMode sets how PRs from a repository are announced in a workspace.
+func (fs *FirestoreService) UpdateRepoNotificationMode(ctx context.Context, repoFullName, workspaceID, mode string) error {
+	docID := fs.encodeRepoDocID(workspaceID, repoFullName)
+
+	defer fs.repoCache.delete(docID)
+	_, err := fs.client.Collection("repos").Doc(docID).Update(ctx, []firestore.Update{
+		{Path: "notification_mode", Value: mode},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update notification mode for repo %s team %s: %w",
+			repoFullName, workspaceID, err)
+	}
+
+	log.Info(ctx, "Repository notification mode updated",
+		"repo", repoFullName,
+		"workspace_id", workspaceID,
+		"notification_mode", mode,
+	)
+	return nil
+}
+
+// UpdateRepoSettings saves the settings editable from the App Home: whether the repository is enabled,
+// its default channel, notification mode and mechanical PR handling. Empty or nil settings are removed.
+func (fs *FirestoreService) UpdateRepoSettings(ctx context.Context, repo *models.Repo) error {
+	docID := fs.encodeRepoDocID(repo.WorkspaceID, repo.RepoFullName)
+
+	var defaultChannel interface{} = repo.DefaultChannel
+	if repo.DefaultChannel == "" {
+		defaultChannel = firestore.Delete
+	}
+	var mechanicalPRs interface{} = repo.MechanicalPRs
+	if repo.MechanicalPRs == nil {
+		mechanicalPRs = firestore.Delete
+	}
+	var emoji interface{} = repo.Emoji
+	if repo.Emoji == "" {
+		emoji = firestore.Delete
+	}
+
+	defer fs.repoCache.delete(docID)
+	_, err := fs.client.Collection("repos").Doc(docID).Update(ctx, []firestore.Update{
+		{Path: "enabled", Value: repo.Enabled},
+		{Path: "default_channel", Value: defaultChannel},
+		{Path: "notification_mode", Value: repo.GetNotificationMode()},
+		{Path: "mechanical_prs", Value: mechanicalPRs},
+		{Path: "emoji", Value: emoji},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update settings for repo %s team %s: %w",
+			repo.RepoFullName, repo.WorkspaceID, err)
+	}
+
+	log.Info(ctx, "Repository settings updated",
+		"repo", repo.RepoFullName,
+		"workspace_id", repo.WorkspaceID,
+		"enabled", repo.Enabled,
+		"default_channel", repo.DefaultChannel,
+		"notification_mode", repo.GetNotificationMode(),
+	)
+	return nil
+}
+
+// UpdateRepoCodeOwnersCC enables or disables CC'ing a repository's code owners on new PR messages.
+func (fs *FirestoreService) UpdateRepoCodeOwnersCC(ctx context.Context, repoFullName, workspaceID string, enabled bool) error {
+	docID := fs.encodeRepoDocID(workspaceID, repoFullName)
+
+	defer fs.repoCache.delete(docID)
+	_, err := fs.client.Collection("repos").Doc(docID).Update(ctx, []firestore.Update{
+		{Path: "codeowners_cc", Value: enabled},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update CODEOWNERS CC for repo %s team %s: %w",
+			repoFullName, workspaceID, err)
+	}
+
+	log.Info(ctx, "Repository CODEOWNERS CC updated",
+		"repo", repoFullName,
+		"workspace_id", workspaceID,
+		"codeowners_cc", enabled,
+	)
+	return nil
+}
+
+// AddDigestEntry buffers an event for a digest mode user until the next digest flush.
+func (fs *FirestoreService) AddDigestEntry(ctx context.Context, entry *models.DigestEntry) error {
+	entry.CreatedAt = time.Now()
+	docRef := fs.client.Collection("digest_entries").NewDoc()
+	entry.ID = docRef.ID
+
+	if _, err := docRef.Set(ctx, entry); err != nil {
+		log.Error(ctx, "Failed to add digest entry",
+			"error", err,
+			"slack_user_id", entry.SlackUserID,
+			"event", entry.Event,
+			"operation", "add_digest_entry",
+		)
+		return fmt.Errorf("failed to add digest entry for user %s: %w", entry.SlackUserID, err)
+	}
+	return nil
+}
+
+// ListDigestEntries retrieves all buffered digest entries created before the given time.
+func (fs *FirestoreService) ListDigestEntries(ctx context.Context, before time.Time) ([]*models.DigestEntry, error) {
+	return fs.listDigestEntries(ctx, "digest_entries", before)
+}
+
+// DeleteDigestEntries deletes flushed digest entries by their IDs.
+func (fs *FirestoreService) DeleteDigestEntries(ctx context.Context, entryIDs []string) error {
+	return fs.deleteDigestEntries(ctx, "digest_entries", entryIDs)
+}
+
+// AddChannelDigestEntry buffers a new PR from a digest-only repository until the channel's next daily digest.
+// Entries are keyed by channel and PR, so re-processing the same PR doesn't list it twice.
+func (fs *FirestoreService) AddChannelDigestEntry(ctx context.Context, entry *models.DigestEntry) error {
+	entry.CreatedAt = time.Now()
+	entry.ID = fmt.Sprintf("%s#%s#%s#%d",
+		entry.SlackTeamID, entry.SlackChannel, fs.encodeRepoName(entry.RepoFullName), entry.PRNumber)
+
+	if _, err := fs.client.Collection("channel_digest_entries").Doc(entry.ID).Set(ctx, entry); err != nil {
+		log.Error(ctx, "Failed to add channel digest entry",
+			"error", err,
+			"slack_channel", entry.SlackChannel,
+			"operation", "add_channel_digest_entry",
+		)
+		return fmt.Errorf("failed to add channel digest entry for channel %s: %w", entry.SlackChannel, err)
+	}
+	return nil
+}
+
+// ListChannelDigestEntries retrieves all buffered channel digest entries created before the given time.
+func (fs *FirestoreService) ListChannelDigestEntries(ctx context.Context, before time.Time) ([]*models.DigestEntry, error) {
+	return fs.listDigestEntries(ctx, "channel_digest_entries", before)
+}
+
+// DeleteChannelDigestEntries deletes posted channel digest entries by their IDs.
+func (fs *FirestoreService) DeleteChannelDigestEntries(ctx context.Context, entryIDs []string) error {
+	return fs.deleteDigestEntries(ctx, "channel_digest_entries", entryIDs)
+}
+
+// listDigestEntries retrieves the entries in a digest collection created before the given time.
+func (fs *FirestoreService) listDigestEntries(ctx context.Context, collection string, before time.Time) ([]*models.DigestEntry, error) {
+	iter := fs.client.Collection(collection).Where("created_at", "<", before).Documents(ctx)
+	defer iter.Stop()
+
+	var entries []*models.DigestEntry
+	for {
+		doc, err := iter.Next()
+		if err != nil {
+			if errors.Is(err, iterator.Done) {
+				break
+			}
+			return nil, fmt.Errorf("failed to query %s: %w", collection, err)
+		}
+
+		var entry models.DigestEntry
+		if err := doc.DataTo(&entry); err != nil {
+			log.Error(ctx, "Failed to unmarshal digest entry",
+				"error", err,
+				"collection", collection,
+				"doc_id", doc.Ref.ID,
+			)
+			continue
+		}
+		entries = append(entries, &entry)
+	}
+
+	return entries, nil
+}
+
+// deleteDigestEntries deletes entries from a digest collection by their IDs.
+func (fs *FirestoreService) deleteDigestEntries(ctx context.Context, collection string, entryIDs []string) error {
+	if len(entryIDs) == 0 {
+		return nil
+	}
+
+	err := fs.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		for _, entryID := range entryIDs {
+			if err := tx.Delete(fs.client.Collection(collection).Doc(entryID)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete %d entries from %s: %w", len(entryIDs), collection, err)
+	}
+
+	return nil
+}
+
+// ListDailyDigestUsers retrieves all users with the daily PR digest enabled.
+func (fs *FirestoreService) ListDailyDigestUsers(ctx context.Context) ([]*models.User, error) {
+	iter := fs.client.Collection("users").Where("daily_digest.enabled", "==", true).Documents(ctx)
+	defer iter.Stop()
+
+	var users []*models.User
+	for {
+		doc, err := iter.Next()
+		if err != nil {
+			if errors.Is(err, iterator.Done) {
+				break
+			}
+			return nil, fmt.Errorf("failed to query daily digest users: %w", err)
+		}
+
+		var user models.User
+		if err := doc.DataTo(&user); err != nil {
+			log.Error(ctx, "Failed to unmarshal user data",
+				"error", err,
+				"doc_id", doc.Ref.ID,
+				"operation", "unmarshal_user_data",
+			)
+			continue
+		}
+		users = append(users, &user)
+	}
+
+	return users, nil
+}
+
+// MarkDailyDigestSent records the local date a user's daily digest was sent, so it's sent once per day.
+// Only the date is updated, so concurrent settings changes aren't overwritten.
+func (fs *FirestoreService) MarkDailyDigestSent(ctx context.Context, userID, localDate string) error {
+	_, err := fs.client.Collection("users").Doc(userID).Update(ctx, []firestore.Update{
+		{Path: "daily_digest.last_sent_date", Value: localDate},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to mark daily digest sent for user %s: %w", userID, err)
+	}
+	return nil
+}
+
+// GetRecentTrackedMessagesByAuthor retrieves bot messages posted since the given time for PRs by a GitHub user.
+func (fs *FirestoreService) GetRecentTrackedMessagesByAuthor(
+	ctx context.Context, slackTeamID string, githubUserID int64, since time.Time,
+) ([]*models.TrackedMessage, error) {
+	query := fs.client.Collection("trackedmessages").
+		Where("slack_team_id", "==", slackTeamID).
+		Where("pr_author_github_id", "==", githubUserID).
+		Where("created_at", ">=", since)
+	messages, err := fs.queryTrackedMessages(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tracked messages by author %d team %s: %w", githubUserID, slackTeamID, err)
+	}
+	return messages, nil
+}
+
+// pendingReviewDocID returns the document ID of a reviewer's pending review on a PR.
+func (fs *FirestoreService) pendingReviewDocID(reviewerGitHubID int64, repoFullName string, prNumber int) string {
+	return fmt.Sprintf("%d#%s#%d", reviewerGitHubID, fs.encodeRepoName(repoFullName), prNumber)
+}
+
+// SavePendingReview records an outstanding review request. Saving the same request again replaces it.
+func (fs *FirestoreService) SavePendingReview(ctx context.Context, review *models.PendingReview) error {
+	review.ID = fs.pendingReviewDocID(review.ReviewerGitHubID, review.RepoFullName, review.PRNumber)
+	review.CreatedAt = time.Now()
+
+	if _, err := fs.client.Collection("pending_reviews").Doc(review.ID).Set(ctx, review); err != nil {
+		return fmt.Errorf("failed to save pending review %s: %w", review.ID, err)
+	}
+	return nil
+}
+
+// DeletePendingReview removes a reviewer's pending review on a PR, if there is one.
+func (fs *FirestoreService) DeletePendingReview(ctx context.Context, reviewerGitHubID int64, repoFullName string, prNumber int) error {
+	docID := fs.pendingReviewDocID(reviewerGitHubID, repoFullName, prNumber)
+	if _, err := fs.client.Collection("pending_reviews").Doc(docID).Delete(ctx); err != nil {
+		return fmt.Errorf("failed to delete pending review %s: %w", docID, err)
+	}
+	return nil
+}
+
+// ListPendingReviews retrieves a reviewer's outstanding review requests.
+func (fs *FirestoreService) ListPendingReviews(ctx context.Context, reviewerGitHubID int64) ([]*models.PendingReview, error) {
+	iter := fs.client.Collection("pending_reviews").Where("reviewer_github_id", "==", reviewerGitHubID).Documents(ctx)
+	defer iter.Stop()
+
+	var reviews []*models.PendingReview
+	for {
+		doc, err := iter.Next()
+		if err != nil {
+			if errors.Is(err, iterator.Done) {
+				break
+			}
+			return nil, fmt.Errorf("failed to query pending reviews for reviewer %d: %w", reviewerGitHubID, err)
+		}
+
+		var review models.PendingReview
+		if err := doc.DataTo(&review); err != nil {
+			log.Error(ctx, "Failed to unmarshal pending review",
+				"error", err,
+				"doc_id", doc.Ref.ID,
+			)
+			continue
+		}
+		reviews = append(reviews, &review)
+	}
+
+	return reviews, nil
+}
+
+// onboardingHintDocID returns the document ID recording a user's onboarding hint in a channel.
+func onboardingHintDocID(teamID, channel, userID string) string {
+	return fmt.Sprintf("%s#%s#%s", teamID, channel, userID)
+}
+
+// ClaimOnboardingHint atomically records that a user is being shown the onboarding hint in a channel.
+// Returns false if they've already been shown it there.
+func (fs *FirestoreService) ClaimOnboardingHint(ctx context.Context, teamID, channel, userID string) (bool, error) {
+	hint := &models.OnboardingHint{
+		ID:           onboardingHintDocID(teamID, channel, userID),
+		SlackTeamID:  teamID,
+		SlackChannel: channel,
+		SlackUserID:  userID,
+		CreatedAt:    time.Now(),
+	}
+
+	if _, err := fs.client.Collection("onboarding_hints").Doc(hint.ID).Create(ctx, hint); err != nil {
+		if status.Code(err) == codes.AlreadyExists {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to claim onboarding hint %s: %w", hint.ID, err)
+	}
+	return true, nil
+}
+
+// ReleaseOnboardingHint removes a user's onboarding hint record for a channel, so it's shown again next time.
+func (fs *FirestoreService) ReleaseOnboardingHint(ctx context.Context, teamID, channel, userID string) error {
+	docID := onboardingHintDocID(teamID, channel, userID)
+	if _, err := fs.client.Collection("onboarding_hints").Doc(docID).Delete(ctx); err != nil {
+		return fmt.Errorf("failed to release onboarding hint %s: %w", docID, err)
+	}
+	return nil
+}
+
+// linkInviteDocID returns the document ID of the link invitations for a GitHub username in a workspace.
+func linkInviteDocID(teamID, githubUsername string) string {
+	return teamID + "#" + strings.ToLower(githubUsername)
+}
+
+// ClaimLinkInvite atomically records an invitation to link the GitHub account of a CC'd username.
+// Returns false if the username's invitations were opted out of, the last was within cooldown,
+// or maxInvites have already been attempted.
+func (fs *FirestoreService) ClaimLinkInvite(
+	ctx context.Context, teamID, githubUsername string, cooldown time.Duration, maxInvites int,
+) (bool, error) {
+	docID := linkInviteDocID(teamID, githubUsername)
+	docRef := fs.client.Collection("link_invites").Doc(docID)
+	now := time.Now()
+
+	claimed := false
+	err := fs.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		claimed = false
+		invite := models.LinkInvite{ID: docID, SlackTeamID: teamID, GitHubUsername: githubUsername}
+		doc, err := tx.Get(docRef)
+		if err != nil && status.Code(err) != codes.NotFound {
+			return err
+		}
+		if err == nil {
+			if err := doc.DataTo(&invite); err != nil {
+				return err
+			}
+			if invite.OptedOut || invite.InviteCount >= maxInvites || now.Sub(invite.LastInvitedAt) < cooldown {
+				return nil
+			}
+		}
+
+		invite.InviteCount++
+		invite.LastInvitedAt = now
+		claimed = true
+		return tx.Set(docRef, &invite)
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to claim link invite %s: %w", docID, err)
+	}
+	return claimed, nil
+}
+
+// OptOutLinkInvites stops invitations to link the GitHub account of a username in a workspace.
+func (fs *FirestoreService) OptOutLinkInvites(ctx context.Context, teamID, githubUsername, slackUserID string) error {
+	docID := linkInviteDocID(teamID, githubUsername)
+	_, err := fs.client.Collection("link_invites").Doc(docID).Set(ctx, map[string]interface{}{
+		"id":              docID,
+		"slack_team_id":   teamID,
+		"github_username": githubUsername,
+		"opted_out":       true,
+		"opted_out_by":    slackUserID,
+	}, firestore.MergeAll)
+	if err != nil {
+		return fmt.Errorf("failed to opt out of link invites %s: %w", docID, err)
+	}
+	return nil
+}
+
+// GetWebhookDelivery retrieves the processing record of a GitHub webhook delivery.
+// Returns nil if the delivery hasn't been processed, or its record has expired.
+func (fs *FirestoreService) GetWebhookDelivery(ctx context.Context, deliveryID string) (*models.WebhookDelivery, error) {
+	doc, err := fs.client.Collection("webhook_deliveries").Doc(deliveryID).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get webhook delivery %s: %w", deliveryID, err)
+	}
+
+	var delivery models.WebhookDelivery
+	if err := doc.DataTo(&delivery); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal webhook delivery %s: %w", deliveryID, err)
+	}
+	return &delivery, nil
+}
+
+// RecordWebhookEvent stores a received GitHub webhook in the webhook event log.
+func (fs *FirestoreService) RecordWebhookEvent(ctx context.Context, event *models.WebhookEvent) error {
+	_, err := fs.client.Collection("webhook_events").Doc(event.ID).Set(ctx, event)
+	if err != nil {
+		return fmt.Errorf("failed to record webhook event %s: %w", event.ID, err)
+	}
+	return nil
+}
+
+// GetWebhookEvent retrieves a GitHub webhook from the webhook event log by its delivery ID.
+// Returns nil if the delivery wasn't recorded, or its record has expired.
+func (fs *FirestoreService) GetWebhookEvent(ctx context.Context, deliveryID string) (*models.WebhookEvent, error) {
+	doc, err := fs.client.Collection("webhook_events").Doc(deliveryID).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get webhook event %s: %w", deliveryID, err)
+	}
+
+	var event models.WebhookEvent
+	if err := doc.DataTo(&event); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal webhook event %s: %w", deliveryID, err)
+	}
+	return &event, nil
+}
+
+// MarkWebhookDeliveryProcessed records that a GitHub webhook delivery's job succeeded, keeping the record for ttl.
+func (fs *FirestoreService) MarkWebhookDeliveryProcessed(ctx context.Context, deliveryID, eventType string, ttl time.Duration) error {
+	now := time.Now()
+	_, err := fs.client.Collection("webhook_deliveries").Doc(deliveryID).Set(ctx, map[string]interface{}{
+		"id":         deliveryID,
+		"event_type": eventType,
+		"processed":  true,
+		"updated_at": now,
+		"expires_at": now.Add(ttl),
+	}, firestore.MergeAll)
+	if err != nil {
+		return fmt.Errorf("failed to mark webhook delivery %s processed: %w", deliveryID, err)
+	}
+	return nil
+}
+
+// MarkWebhookDeliveryWorkspaceCompleted records that a GitHub webhook delivery's PR job succeeded for a workspace,
+// keeping the record for ttl.
+func (fs *FirestoreService) MarkWebhookDeliveryWorkspaceCompleted(
+	ctx context.Context, deliveryID, workspaceID string, ttl time.Duration,
+) error {
+	now := time.Now()
+	_, err := fs.client.Collection("webhook_deliveries").Doc(deliveryID).Set(ctx, map[string]interface{}{
+		"id":                   deliveryID,
+		"completed_workspaces": firestore.ArrayUnion(workspaceID),
+		"updated_at":           now,
+		"expires_at":           now.Add(ttl),
+	}, firestore.MergeAll)
+	if err != nil {
+		return fmt.Errorf("failed to mark webhook delivery %s completed for workspace %s: %w", deliveryID, workspaceID, err)
+	}
+	return nil
+}
+
+// prSequenceDocID returns the document ID of a PR's notification sequence.
+func (fs *FirestoreService) prSequenceDocID(repoFullName string, prNumber int) string {
+	return fmt.Sprintf("%s#%d", fs.encodeRepoName(repoFullName), prNumber)
+}
+
+// GetPRSequence retrieves a PR's notification sequence. Returns nil if no jobs have been reserved for the PR.
+func (fs *FirestoreService) GetPRSequence(ctx context.Context, repoFullName string, prNumber int) (*models.PRSequence, error) {
+	docID := fs.prSequenceDocID(repoFullName, prNumber)
+	doc, err := fs.client.Collection("pr_sequences").Doc(docID).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get PR sequence %s: %w", docID, err)
+	}
+
+	var sequence models.PRSequence
+	if err := doc.DataTo(&sequence); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal PR sequence %s: %w", docID, err)
+	}
+	return &sequence, nil
+}
+
+// ReservePRSequence atomically records workspace PR jobs about to be enqueued for a PR,
+// holding later events for the PR until they complete or the lease lapses.
+func (fs *FirestoreService) ReservePRSequence(
+	ctx context.Context, repoFullName string, prNumber, jobs int, lease time.Duration,
+) error {
+	docID := fs.prSequenceDocID(repoFullName, prNumber)
+	docRef := fs.client.Collection("pr_sequences").Doc(docID)
+
+	err := fs.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		sequence := models.PRSequence{ID: docID, RepoFullName: repoFullName, PRNumber: prNumber}
+		doc, err := tx.Get(docRef)
+		if err != nil && status.Code(err) != codes.NotFound {
+			return err
+		}
+		if err == nil {
+			if err := doc.DataTo(&sequence); err != nil {
+				return err
+			}
+		}
+
+		sequence.Reserve(jobs, time.Now(), lease)
+		return tx.Set(docRef, &sequence)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to reserve PR sequence %s: %w", docID, err)
+	}
+	return nil
+}
+
+// CompletePRSequenceJobs atomically records that workspace PR jobs for a PR have finished,
+// deleting the sequence once none remain pending.
+func (fs *FirestoreService) CompletePRSequenceJobs(ctx context.Context, repoFullName string, prNumber, jobs int) error {
+	docID := fs.prSequenceDocID(repoFullName, prNumber)
+	docRef := fs.client.Collection("pr_sequences").Doc(docID)
+
+	err := fs.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(docRef)
+		if err != nil {
+			if status.Code(err) == codes.NotFound {
+				return nil
+			}
+			return err
+		}
+
+		var sequence models.PRSequence
+		if err := doc.DataTo(&sequence); err != nil {
+			return err
+		}
+		if sequence.Complete(jobs, time.Now()) {
+			return tx.Delete(docRef)
+		}
+		return tx.Set(docRef, &sequence)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to complete PR sequence jobs %s: %w", docID, err)
+	}
+	return nil
+}
+
+// SpendPRUpdateBudget atomically records a Slack message update for a PR against its update budget.
+// Returns whether the update is allowed and, for the first update dropped in a window, when the window ends
+// and the reconciliation pass should run; reconcileAt is zero otherwise.
+func (fs *FirestoreService) SpendPRUpdateBudget(
+	ctx context.Context, repoFullName string, prNumber, limit int, window time.Duration,
+) (allowed bool, reconcileAt time.Time, err error) {
+	docID := fmt.Sprintf("%s#%d", fs.encodeRepoName(repoFullName), prNumber)
+	docRef := fs.client.Collection("pr_update_budgets").Doc(docID)
+
+	err = fs.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		allowed, reconcileAt = false, time.Time{}
+		budget := models.PRUpdateBudget{ID: docID, RepoFullName: repoFullName, PRNumber: prNumber}
+		doc, err := tx.Get(docRef)
+		if err != nil && status.Code(err) != codes.NotFound {
+			return err
+		}
+		if err == nil {
+			if err := doc.DataTo(&budget); err != nil {
+				return err
+			}
+		}
+
+		var scheduleReconcile bool
+		allowed, scheduleReconcile = budget.Spend(limit, time.Now(), window)
+		if scheduleReconcile {
+			reconcileAt = budget.WindowEnd(window)
+		}
+		return tx.Set(docRef, &budget)
+	})
+	if err != nil {
+		return false, time.Time{}, fmt.Errorf("failed to spend PR update budget %s: %w", docID, err)
+	}
+	return allowed, reconcileAt, nil
+}
+
+// ClaimReactionSync atomically decides whether a PR's reaction sync job, requested at requestedAt, still needs to
+// run, and if so records it as the PR's running sync from startedAt until it's released or the lease lapses.
+func (fs *FirestoreService) ClaimReactionSync(
+	ctx context.Context, repoFullName string, prNumber int, jobID string, requestedAt, startedAt time.Time, lease time.Duration,
+) (claimed bool, err error) {
+	docID := fmt.Sprintf("%s#%d", fs.encodeRepoName(repoFullName), prNumber)
+	docRef := fs.client.Collection("reaction_sync_markers").Doc(docID)
+
+	err = fs.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		marker := models.ReactionSyncMarker{ID: docID, RepoFullName: repoFullName, PRNumber: prNumber}
+		doc, err := tx.Get(docRef)
+		if err != nil && status.Code(err) != codes.NotFound {
+			return err
+		}
+		if err == nil {
+			if err := doc.DataTo(&marker); err != nil {
+				return err
+			}
+		}
+
+		claimed = marker.Claim(jobID, requestedAt, startedAt, lease)
+		if !claimed {
+			return nil
+		}
+		return tx.Set(docRef, &marker)
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to claim reaction sync %s: %w", docID, err)
+	}
+	return claimed, nil
+}
+
+// ReleaseReactionSync atomically records that a PR's reaction sync job, started at startedAt, has finished.
+func (fs *FirestoreService) ReleaseReactionSync(
+	ctx context.Context, repoFullName string, prNumber int, jobID string, startedAt time.Time, succeeded bool,
+) error {
+	docID := fmt.Sprintf("%s#%d", fs.encodeRepoName(repoFullName), prNumber)
+	docRef := fs.client.Collection("reaction_sync_markers").Doc(docID)
+
+	err := fs.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		marker := models.ReactionSyncMarker{ID: docID, RepoFullName: repoFullName, PRNumber: prNumber}
+		doc, err := tx.Get(docRef)
+		if err != nil && status.Code(err) != codes.NotFound {
+			return err
+		}
+		if err == nil {
+			if err := doc.DataTo(&marker); err != nil {
+				return err
+			}
+		}
+
+		marker.Release(jobID, startedAt, succeeded, time.Now())
+		return tx.Set(docRef, &marker)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to release reaction sync %s: %w", docID, err)
+	}
+	return nil
+}
+
+// threadReplyDocID returns the document ID of a review comment's reply in a tracked message's thread.
+func threadReplyDocID(trackedMessageID, commentKind string, commentID int64) string {
+	return fmt.Sprintf("%s#%s#%d", trackedMessageID, commentKind, commentID)
+}
+
+// GetThreadReply retrieves the bot's reply for a review comment in a tracked message's thread.
+// Returns nil if the comment wasn't posted to the thread.
+func (fs *FirestoreService) GetThreadReply(
+	ctx context.Context, trackedMessageID, commentKind string, commentID int64,
+) (*models.ThreadReply, error) {
+	docID := threadReplyDocID(trackedMessageID, commentKind, commentID)
+	doc, err := fs.client.Collection("thread_replies").Doc(docID).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get thread reply %s: %w", docID, err)
+	}
+
+	var reply models.ThreadReply
+	if err := doc.DataTo(&reply); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal thread reply %s: %w", docID, err)
+	}
+	return &reply, nil
+}
+
+// SaveThreadReply records the bot's reply for a review comment in a tracked message's thread.
+func (fs *FirestoreService) SaveThreadReply(ctx context.Context, reply *models.ThreadReply) error {
+	reply.ID = threadReplyDocID(reply.TrackedMessageID, reply.CommentKind, reply.CommentID)
+	reply.CreatedAt = time.Now()
+
+	if _, err := fs.client.Collection("thread_replies").Doc(reply.ID).Set(ctx, reply); err != nil {
+		return fmt.Errorf("failed to save thread reply %s: %w", reply.ID, err)
+	}
+	return nil
+}
+
+// DeleteThreadReply removes the record of a review comment's reply, once the reply is deleted.
+func (fs *FirestoreService) DeleteThreadReply(ctx context.Context, replyID string) error {
+	if _, err := fs.client.Collection("thread_replies").Doc(replyID).Delete(ctx); err != nil {
+		return fmt.Errorf("failed to delete thread reply %s: %w", replyID, err)
+	}
+	return nil
+}
+
+// GetNotificationPolicy retrieves a workspace's notification policy.
+// Returns nil if the workspace has no policy.
+func (fs *FirestoreService) GetNotificationPolicy(ctx context.Context, workspaceID string) (*models.NotificationPolicy, error) {
+	doc, err := fs.client.Collection("notification_policies").Doc(workspaceID).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get notification policy for workspace %s: %w", workspaceID, err)
+	}
+
+	var notificationPolicy models.NotificationPolicy
+	if err := doc.DataTo(&notificationPolicy); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal notification policy: %w", err)
+	}
+	return &notificationPolicy, nil
+}
+
+// SaveNotificationPolicy creates or replaces a workspace's notification policy.
+// Callers must validate the policy first.
+func (fs *FirestoreService) SaveNotificationPolicy(ctx context.Context, notificationPolicy *models.NotificationPolicy) error {
+	notificationPolicy.UpdatedAt = time.Now()
+	_, err := fs.client.Collection("notification_policies").Doc(notificationPolicy.WorkspaceID).Set(ctx, notificationPolicy)
+	if err != nil {
+		return fmt.Errorf("failed to save notification policy for workspace %s: %w", notificationPolicy.WorkspaceID, err)
+	}
+	return nil
+}
+
+// DeleteNotificationPolicy removes a workspace's notification policy.
+func (fs *FirestoreService) DeleteNotificationPolicy(ctx context.Context, workspaceID string) error {
+	_, err := fs.client.Collection("notification_policies").Doc(workspaceID).Delete(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to delete notification policy for workspace %s: %w", workspaceID, err)
+	}
+	return nil
+}
+
+// RecordDirectiveUsage increments the directive usage counters for a workspace.
+// Map keys are written as single field path segments, so repo names containing dots are safe.
+func (fs *FirestoreService) RecordDirectiveUsage(ctx context.Context, workspaceID string, event *models.DirectiveUsageEvent) error {
+	data := map[string]interface{}{
+		"workspace_id": workspaceID,
+		"updated_at":   time.Now(),
+	}
+
+	switch {
+	case event.ChannelFailed:
+		data["channel_failures"] = map[string]interface{}{event.RepoFullName: firestore.Increment(1)}
+	case len(event.Types) == 0:
+		data["prs_total"] = firestore.Increment(1)
+	default:
+		data["prs_total"] = firestore.Increment(1)
+		data["prs_with_directives"] = firestore.Increment(1)
+		data["repos"] = map[string]interface{}{event.RepoFullName: firestore.Increment(1)}
+
+		directives := make(map[string]interface{}, len(event.Types))
+		for _, directiveType := range event.Types {
+			directives[directiveType] = firestore.Increment(1)
+		}
+		data["directives"] = directives
+
+		if event.Channel != "" {
+			data["channels"] = map[string]interface{}{event.Channel: firestore.Increment(1)}
+		}
+		if event.CustomEmoji != "" {
+			data["custom_emoji"] = map[string]interface{}{event.CustomEmoji: firestore.Increment(1)}
+		}
+	}
+
+	_, err := fs.client.Collection("directive_usage").Doc(workspaceID).Set(ctx, data, firestore.MergeAll)
+	if err != nil {
+		return fmt.Errorf("failed to record directive usage for workspace %s: %w", workspaceID, err)
+	}
+	return nil
+}
+
+// GetDirectiveUsage retrieves the directive usage aggregates for a workspace.
+// Returns nil if no usage has been recorded.
+func (fs *FirestoreService) GetDirectiveUsage(ctx context.Context, workspaceID string) (*models.DirectiveUsage, error) {
+	doc, err := fs.client.Collection("directive_usage").Doc(workspaceID).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get directive usage for workspace %s: %w", workspaceID, err)
+	}
+
+	var usage models.DirectiveUsage
+	if err := doc.DataTo(&usage); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal directive usage: %w", err)
+	}
+	return &usage, nil
+}
+
+// ListDirectiveUsage retrieves the directive usage aggregates for all workspaces.
+func (fs *FirestoreService) ListDirectiveUsage(ctx context.Context) ([]*models.DirectiveUsage, error) {
+	iter := fs.client.Collection("directive_usage").Documents(ctx)
+	defer iter.Stop()
+
+	var usages []*models.DirectiveUsage
+	for {
+		doc, err := iter.Next()
+		if err != nil {
+			if errors.Is(err, iterator.Done) {
+				break
+			}
+			return nil, fmt.Errorf("failed to list directive usage: %w", err)
+		}
+
+		var usage models.DirectiveUsage
+		if err := doc.DataTo(&usage); err != nil {
+			log.Error(ctx, "Failed to unmarshal directive usage",
+				"error", err,
+				"doc_id", doc.Ref.ID,
+			)
+			continue
+		}
+		usages = append(usages, &usage)
+	}
+
+	return usages, nil
+}
+
+// GetWorkspaceStats counts connected users, configured repos, and bot notifications posted since the given time,
+// how many of those notifications were clicked, and the topChannels channels that received the most of them.
+func (fs *FirestoreService) GetWorkspaceStats(
+	ctx context.Context, workspaceID string, since time.Time, topChannels int,
+) (*models.WorkspaceStats, error) {
+	stats := &models.WorkspaceStats{GeneratedAt: time.Now()}
+
+	var err error
+	stats.ConnectedUsers, err = fs.countQuery(ctx, fs.client.Collection("users").
+		Where("slack_team_id", "==", workspaceID).
+		Where("verified", "==", true))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count users for workspace %s: %w", workspaceID, err)
+	}
+
+	stats.ConfiguredRepos, err = fs.countQuery(ctx, fs.client.Collection("repos").
+		Where("workspace_id", "==", workspaceID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count repos for workspace %s: %w", workspaceID, err)
+	}
+
+	// Only the channel and clicks are needed, so avoid reading whole tracked messages
+	iter := fs.client.Collection("trackedmessages").
+		Where("slack_team_id", "==", workspaceID).
+		Where("message_source", "==", models.MessageSourceBot).
+		Where("created_at", ">=", since).
+		Select("slack_channel", "link_clicks").
+		Documents(ctx)
+	defer iter.Stop()
+
+	channelCounts := make(map[string]int64)
+	for {
+		doc, err := iter.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to query notifications for workspace %s: %w", workspaceID, err)
+		}
+
+		stats.NotificationsThisWeek++
+		if clicks, ok := doc.Data()["link_clicks"].(int64); ok && clicks > 0 {
+			stats.ClickedThisWeek++
+		}
+		if channel, ok := doc.Data()["slack_channel"].(string); ok && channel != "" {
+			channelCounts[channel]++
+		}
+	}
+
+	for channelID, count := range channelCounts {
+		stats.TopChannels = append(stats.TopChannels, models.ChannelActivity{ChannelID: channelID, Notifications: count})
+	}
+	sort.Slice(stats.TopChannels, func(i, j int) bool {
+		if stats.TopChannels[i].Notifications != stats.TopChannels[j].Notifications {
+			return stats.TopChannels[i].Notifications > stats.TopChannels[j].Notifications
+		}
+		return stats.TopChannels[i].ChannelID < stats.TopChannels[j].ChannelID
+	})
+	if len(stats.TopChannels) > topChannels {
+		stats.TopChannels = stats.TopChannels[:topChannels]
+	}
+
+	return stats, nil
+}
+
+// CountDocuments returns the number of documents in a collection, counted server-side.
+func (fs *FirestoreService) CountDocuments(ctx context.Context, collectionName string) (int64, error) {
+	count, err := fs.countQuery(ctx, fs.client.Collection(collectionName).Query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count collection %s: %w", collectionName, err)
+	}
+	return count, nil
+}
+
+// countQuery runs a server-side count aggregation for a query.
+func (fs *FirestoreService) countQuery(ctx context.Context, query firestore.Query) (int64, error) {
+	const countAlias = "count"
+	result, err := query.NewAggregationQuery().WithCount(countAlias).Get(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	count, ok := result[countAlias].(*firestorepb.Value)
+	if !ok {
+		return 0, fmt.Errorf("%w: %T", ErrUnexpectedAggregationResult, result[countAlias])
+	}
+	return count.GetIntegerValue(), nil
+}
+
+// GetChannelConfig retrieves channel configuration.
+func (fs *FirestoreService) GetChannelConfig(ctx context.Context, slackTeamID, channelID string) (*models.ChannelConfig, error) {
+	docID := slackTeamID + "#" + channelID
+	doc, err := fs.client.Collection("channel_configs").Doc(docID).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil // No config means use defaults
+		}
+		return nil, fmt.Errorf("failed to get channel config: %w", err)
+	}
+
+	var config models.ChannelConfig
+	err = doc.DataTo(&config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal channel config: %w", err)
+	}
+
+	return &config, nil
+}
+
+// SaveChannelConfig creates or updates channel configuration.
+func (fs *FirestoreService) SaveChannelConfig(ctx context.Context, config *models.ChannelConfig) error {
+	config.UpdatedAt = time.Now()
+	if config.CreatedAt.IsZero() {
+		config.CreatedAt = time.Now()
+	}
+
+	docID := config.SlackTeamID + "#" + config.SlackChannelID
+	_, err := fs.client.Collection("channel_configs").Doc(docID).Set(ctx, config)
+	if err != nil {
+		return fmt.Errorf("failed to save channel config: %w", err)
+	}
+
+	return nil
+}
+
+// GetReviewerRotation retrieves a channel's reviewer rotation. Returns nil if the channel has none.
+func (fs *FirestoreService) GetReviewerRotation(ctx context.Context, slackTeamID, channelID string) (*models.ReviewerRotation, error) {
+	docID := slackTeamID + "#" + channelID
+	doc, err := fs.client.Collection("reviewer_rotations").Doc(docID).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get reviewer rotation: %w", err)
+	}
+
+	var rotation models.ReviewerRotation
+	if err := doc.DataTo(&rotation); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal reviewer rotation: %w", err)
+	}
+	return &rotation, nil
+}
+
+// SaveReviewerRotationMembers atomically replaces a channel's reviewer rotation members, keeping its position
+// in the rotation. Saving no members deletes the rotation.
+func (fs *FirestoreService) SaveReviewerRotationMembers(
+	ctx context.Context, slackTeamID, channelID string, members []string, configuredBy string,
+) error {
+	docID := slackTeamID + "#" + channelID
+	docRef := fs.client.Collection("reviewer_rotations").Doc(docID)
+
+	err := fs.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		if len(members) == 0 {
+			return tx.Delete(docRef)
+		}
+
+		rotation := models.ReviewerRotation{ID: docID, SlackTeamID: slackTeamID, SlackChannelID: channelID}
+		doc, err := tx.Get(docRef)
+		if err != nil && status.Code(err) != codes.NotFound {
+			return err
+		}
+		if err == nil {
+			if err := doc.DataTo(&rotation); err != nil {
+				return err
+			}
+		}
+
+		rotation.SetMembers(members)
+		rotation.ConfiguredBy = configuredBy
+		rotation.UpdatedAt = time.Now()
+		return tx.Set(docRef, &rotation)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save reviewer rotation %s: %w", docID, err)
+	}
+	return nil
+}
+
+// AssignNextReviewer atomically picks the next reviewer from a channel's rotation, skipping excluded members,
+// and advances the rotation. Returns "" if the channel has no rotation or every member is excluded.
+func (fs *FirestoreService) AssignNextReviewer(
+	ctx context.Context, slackTeamID, channelID string, excluded func(slackUserID string) bool,
+) (string, error) {
+	docID := slackTeamID + "#" + channelID
+	docRef := fs.client.Collection("reviewer_rotations").Doc(docID)
+
+	var reviewer string
+	err := fs.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		reviewer = ""
+		doc, err := tx.Get(docRef)
+		if err != nil {
+			if status.Code(err) == codes.NotFound {
+				return nil
+			}
+			return err
+		}
+
+		var rotation models.ReviewerRotation
+		if err := doc.DataTo(&rotation); err != nil {
+			return err
+		}
+		assigned, ok := rotation.Assign(excluded)
+		if !ok {
+			return nil
+		}
+		reviewer = assigned
+		return tx.Update(docRef, []firestore.Update{
+			{Path: "next_index", Value: rotation.NextIndex},
+			{Path: "last_assigned", Value: rotation.LastAssigned},
+		})
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to assign reviewer from rotation %s: %w", docID, err)
+	}
+	return reviewer, nil
+}
+
+// GetTeamUserGroups retrieves a workspace's GitHub team to Slack user group mappings. Returns nil if it has none.
+func (fs *FirestoreService) GetTeamUserGroups(ctx context.Context, slackTeamID string) (*models.TeamUserGroups, error) {
+	doc, err := fs.client.Collection("team_user_groups").Doc(slackTeamID).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get team user groups: %w", err)
+	}
+
+	var groups models.TeamUserGroups
+	if err := doc.DataTo(&groups); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal team user groups: %w", err)
+	}
+	return &groups, nil
+}
+
+// SaveTeamUserGroups replaces a workspace's GitHub team to Slack user group mappings. Saving no mappings deletes them.
+func (fs *FirestoreService) SaveTeamUserGroups(ctx context.Context, groups *models.TeamUserGroups) error {
+	docRef := fs.client.Collection("team_user_groups").Doc(groups.SlackTeamID)
+	if len(groups.Mappings) == 0 {
+		if _, err := docRef.Delete(ctx); err != nil {
+			return fmt.Errorf("failed to delete team user groups: %w", err)
+		}
+		return nil
+	}
+
+	groups.ID = groups.SlackTeamID
+	groups.UpdatedAt = time.Now()
+	if _, err := docRef.Set(ctx, groups); err != nil {
+		return fmt.Errorf("failed to save team user groups: %w", err)
+	}
+	return nil
+}
+
+// ListReleaseCutChannelConfigs retrieves channel configurations with a release cut deadline after the given time.
+// Used by the scheduled release countdown job across all workspaces.
+func (fs *FirestoreService) ListReleaseCutChannelConfigs(ctx context.Context, after time.Time) ([]*models.ChannelConfig, error) {
+	iter := fs.client.Collection("channel_configs").
+		Where("release_cut_deadline", ">", after).
+		Documents(ctx)
+	defer iter.Stop()
+
+	var configs []*models.ChannelConfig
+	for {
+		doc, err := iter.Next()
+		if err != nil {
+			if errors.Is(err, iterator.Done) {
+				break
+			}
+			return nil, fmt.Errorf("failed to list release cut channel configs: %w", err)
+		}
+
+		var config models.ChannelConfig
+		err = doc.DataTo(&config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal channel config: %w", err)
+		}
+
+		configs = append(configs, &config)
+	}
+
+	return configs, nil
+}
+
+// ListChannelConfigs retrieves all channel configurations for a workspace.
+func (fs *FirestoreService) ListChannelConfigs(ctx context.Context, slackTeamID string) ([]*models.ChannelConfig, error) {
+	iter := fs.client.Collection("channel_configs").
 		Where("slack_team_id", "==", slackTeamID).
 		Documents(ctx)
 	defer iter.Stop()
@@ -872,6 +2393,27 @@ func (fs *FirestoreService) UpdateGitHubInstallation(ctx context.Context, instal
 	return nil
 }
 
+// UpdateGitHubInstallationDefaults sets or, when defaults is nil, clears the settings repositories auto-registered
+// from an installation inherit.
+func (fs *FirestoreService) UpdateGitHubInstallationDefaults(
+	ctx context.Context, installationID int64, defaults *models.InstallationDefaults,
+) error {
+	var value interface{} = defaults
+	if defaults == nil {
+		value = firestore.Delete
+	}
+
+	docID := fmt.Sprintf("%d", installationID)
+	_, err := fs.client.Collection("github_installations").Doc(docID).Update(ctx, []firestore.Update{
+		{Path: "defaults", Value: value},
+		{Path: "updated_at", Value: time.Now()},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update defaults for GitHub installation %d: %w", installationID, err)
+	}
+	return nil
+}
+
 // DeleteGitHubInstallation deletes a GitHub installation record.
 func (fs *FirestoreService) DeleteGitHubInstallation(ctx context.Context, installationID int64) error {
 	docID := fmt.Sprintf("%d", installationID)
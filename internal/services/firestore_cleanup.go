@@ -0,0 +1,234 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+)
+
+// cleanupStatsDocID is the document in the cleanup_stats collection holding the running totals.
+const cleanupStatsDocID = "totals"
+
+// DeleteExpiredOAuthStates deletes up to limit OAuth states that expired before now, returning how many it deleted.
+// With dryRun, it only counts them.
+func (fs *FirestoreService) DeleteExpiredOAuthStates(ctx context.Context, now time.Time, limit int, dryRun bool) (int, error) {
+	query := fs.client.Collection("oauth_states").Where("expires_at", "<", now)
+	deleted, err := fs.deleteQueryDocuments(ctx, query, limit, dryRun)
+	if err != nil {
+		return deleted, fmt.Errorf("failed to delete expired OAuth states: %w", err)
+	}
+	return deleted, nil
+}
+
+// DeleteClosedTrackedMessages deletes up to limit tracked messages for PRs that were merged or closed before
+// closedBefore, returning how many it deleted. Messages tracked before closed_at was recorded are never
+// deleted, since whether their PR is closed isn't known. With dryRun, it only counts them.
+func (fs *FirestoreService) DeleteClosedTrackedMessages(
+	ctx context.Context, closedBefore time.Time, limit int, dryRun bool,
+) (int, error) {
+	query := fs.client.Collection("trackedmessages").Where("closed_at", "<", closedBefore)
+	deleted, err := fs.deleteQueryDocuments(ctx, query, limit, dryRun)
+	if err != nil {
+		return deleted, fmt.Errorf("failed to delete closed tracked messages: %w", err)
+	}
+	return deleted, nil
+}
+
+// DeleteOrphanedInstallations deletes up to limit GitHub App installations owned by a Slack workspace that's
+// no longer installed, i.e. has neither an installation record nor configured repositories, returning how
+// many it deleted. Installations without an owning workspace predate ownership and are kept.
+// With dryRun, it only counts them.
+func (fs *FirestoreService) DeleteOrphanedInstallations(ctx context.Context, limit int, dryRun bool) (int, error) {
+	knownWorkspaces, err := fs.knownWorkspaceIDs(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	iter := fs.client.Collection("github_installations").Select("slack_workspace_id").Documents(ctx)
+	defer iter.Stop()
+
+	var orphaned []*firestore.DocumentRef
+	for len(orphaned) < limit {
+		doc, err := iter.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to list GitHub installations: %w", err)
+		}
+		workspaceID, _ := doc.Data()["slack_workspace_id"].(string)
+		if workspaceID == "" || knownWorkspaces[workspaceID] {
+			continue
+		}
+		log.Info(ctx, "Found GitHub installation of uninstalled workspace",
+			"installation_doc_id", doc.Ref.ID,
+			"team_id", workspaceID,
+			"dry_run", dryRun,
+		)
+		orphaned = append(orphaned, doc.Ref)
+	}
+
+	if dryRun {
+		return len(orphaned), nil
+	}
+	return fs.deleteDocuments(ctx, orphaned)
+}
+
+// knownWorkspaceIDs returns the Slack team IDs with an installation record or configured repositories.
+// Workspaces with repositories count as installed so deployments using a single bot token keep their data.
+func (fs *FirestoreService) knownWorkspaceIDs(ctx context.Context) (map[string]bool, error) {
+	known := make(map[string]bool)
+
+	workspaces := fs.client.Collection("slack_workspaces").Select().Documents(ctx)
+	defer workspaces.Stop()
+	for {
+		doc, err := workspaces.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list Slack workspaces: %w", err)
+		}
+		known[doc.Ref.ID] = true
+	}
+
+	repos := fs.client.Collection("repos").Select("workspace_id").Documents(ctx)
+	defer repos.Stop()
+	for {
+		doc, err := repos.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list repos: %w", err)
+		}
+		if workspaceID, _ := doc.Data()["workspace_id"].(string); workspaceID != "" {
+			known[workspaceID] = true
+		}
+	}
+
+	return known, nil
+}
+
+// deleteQueryDocuments deletes up to limit documents matching a query, or only counts them with dryRun.
+func (fs *FirestoreService) deleteQueryDocuments(ctx context.Context, query firestore.Query, limit int, dryRun bool) (int, error) {
+	iter := query.Select().Limit(limit).Documents(ctx)
+	defer iter.Stop()
+
+	var refs []*firestore.DocumentRef
+	for {
+		doc, err := iter.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+		refs = append(refs, doc.Ref)
+	}
+
+	if dryRun {
+		return len(refs), nil
+	}
+	return fs.deleteDocuments(ctx, refs)
+}
+
+// deleteDocuments deletes documents in bulk, returning how many were deleted before the first failure.
+func (fs *FirestoreService) deleteDocuments(ctx context.Context, refs []*firestore.DocumentRef) (int, error) {
+	if len(refs) == 0 {
+		return 0, nil
+	}
+
+	bulkWriter := fs.client.BulkWriter(ctx)
+	jobs := make([]*firestore.BulkWriterJob, 0, len(refs))
+	for _, ref := range refs {
+		job, err := bulkWriter.Delete(ref)
+		if err != nil {
+			bulkWriter.End()
+			return 0, fmt.Errorf("failed to queue delete of %s: %w", ref.Path, err)
+		}
+		jobs = append(jobs, job)
+	}
+	bulkWriter.End()
+
+	deleted := 0
+	var firstErr error
+	for i, job := range jobs {
+		if _, err := job.Results(); err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to delete %s: %w", refs[i].Path, err)
+			}
+			continue
+		}
+		deleted++
+	}
+	return deleted, firstErr
+}
+
+// RecordCleanup adds a cleanup run's deletions to the running totals in the cleanup_stats collection.
+func (fs *FirestoreService) RecordCleanup(ctx context.Context, deleted map[string]int, finishedAt time.Time) error {
+	totals := make(map[string]interface{}, len(deleted))
+	for collectionName, count := range deleted {
+		totals[collectionName] = firestore.Increment(count)
+	}
+
+	_, err := fs.client.Collection("cleanup_stats").Doc(cleanupStatsDocID).Set(ctx, map[string]interface{}{
+		"deleted":     totals,
+		"last_run_at": finishedAt,
+	}, firestore.MergeAll)
+	if err != nil {
+		return fmt.Errorf("failed to record cleanup stats: %w", err)
+	}
+	return nil
+}
+
+// GetCleanupStats retrieves the cleanup job's running totals. Returns nil if it has never run.
+func (fs *FirestoreService) GetCleanupStats(ctx context.Context) (*models.CleanupStats, error) {
+	doc, err := fs.client.Collection("cleanup_stats").Doc(cleanupStatsDocID).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get cleanup stats: %w", err)
+	}
+
+	var stats models.CleanupStats
+	if err := doc.DataTo(&stats); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal cleanup stats: %w", err)
+	}
+	return &stats, nil
+}
+
+// SetTrackedMessagesClosedAt records when the PR of each tracked message was merged or closed,
+// or clears it with nil when the PR is reopened. Messages already in that state are skipped.
+func (fs *FirestoreService) SetTrackedMessagesClosedAt(
+	ctx context.Context, messages []*models.TrackedMessage, closedAt *time.Time,
+) error {
+	var value interface{} = firestore.Delete
+	if closedAt != nil {
+		value = *closedAt
+	}
+
+	for _, message := range messages {
+		if message.ID == "" || (closedAt == nil && message.ClosedAt == nil) || (closedAt != nil && message.ClosedAt != nil) {
+			continue
+		}
+		_, err := fs.client.Collection("trackedmessages").Doc(message.ID).Update(ctx, []firestore.Update{
+			{Path: "closed_at", Value: value},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update closed_at for tracked message %s: %w", message.ID, err)
+		}
+		message.ClosedAt = closedAt
+	}
+	return nil
+}
@@ -0,0 +1,408 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github-slack-notifier/internal/models"
+)
+
+// userDataSubject identifies whose data a user data export or deletion covers.
+type userDataSubject struct {
+	teamID         string
+	userID         string
+	user           *firestore.DocumentSnapshot // The user document, nil if the user never set up PR Bot
+	githubUserID   int64
+	githubUsername string
+}
+
+// loadUserDataSubject reads the user document, ignoring one that belongs to another workspace.
+func (fs *FirestoreService) loadUserDataSubject(ctx context.Context, teamID, userID string) (*userDataSubject, error) {
+	subject := &userDataSubject{teamID: teamID, userID: userID}
+
+	doc, err := fs.client.Collection("users").Doc(userID).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return subject, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user %s: %w", userID, err)
+	}
+
+	var user models.User
+	if err := doc.DataTo(&user); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal user %s: %w", userID, err)
+	}
+	if user.SlackTeamID != teamID {
+		return subject, nil
+	}
+
+	subject.user = doc
+	subject.githubUserID = user.GitHubUserID
+	subject.githubUsername = user.GitHubUsername
+	return subject, nil
+}
+
+// ownedQueries returns the queries for documents that belong to the user alone.
+func (fs *FirestoreService) ownedQueries(subject *userDataSubject) map[string]firestore.Query {
+	queries := make(map[string]firestore.Query)
+	for _, collectionName := range []string{"oauth_states", "digest_entries", "onboarding_hints"} {
+		queries[collectionName] = fs.client.Collection(collectionName).
+			Where("slack_team_id", "==", subject.teamID).
+			Where("slack_user_id", "==", subject.userID)
+	}
+	return queries
+}
+
+// ownedDocRefs returns the documents stored by ID that belong to the user.
+func (fs *FirestoreService) ownedDocRefs(subject *userDataSubject) map[string]*firestore.DocumentRef {
+	refs := map[string]*firestore.DocumentRef{
+		"slack_user_tokens":  fs.client.Collection("slack_user_tokens").Doc(userTokenDocID(subject.teamID, subject.userID)),
+		"github_user_tokens": fs.client.Collection("github_user_tokens").Doc(userTokenDocID(subject.teamID, subject.userID)),
+	}
+	if subject.githubUsername != "" {
+		refs["link_invites"] = fs.client.Collection("link_invites").Doc(linkInviteDocID(subject.teamID, subject.githubUsername))
+	}
+	return refs
+}
+
+// authoredTrackedMessages returns the query for the workspace's tracked messages of PRs the user authored.
+func (fs *FirestoreService) authoredTrackedMessages(subject *userDataSubject) firestore.Query {
+	return fs.client.Collection("trackedmessages").
+		Where("slack_team_id", "==", subject.teamID).
+		Where("pr_author_github_id", "==", subject.githubUserID)
+}
+
+// pendingReviews returns the query for PRs waiting on the user's review. These are keyed by GitHub account,
+// not workspace, so they're shared by every workspace the account is linked in.
+func (fs *FirestoreService) pendingReviews(subject *userDataSubject) firestore.Query {
+	return fs.client.Collection("pending_reviews").Where("reviewer_github_id", "==", subject.githubUserID)
+}
+
+// receivedNotifications returns the queries for the workspace's notification log entries of messages sent to
+// the user: ephemeral messages, which record the user as recipient, and DMs, which record them as the channel.
+func (fs *FirestoreService) receivedNotifications(subject *userDataSubject) []firestore.Query {
+	notificationLog := fs.client.Collection("notification_log").Where("slack_team_id", "==", subject.teamID)
+	return []firestore.Query{
+		notificationLog.Where("slack_user_id", "==", subject.userID),
+		notificationLog.Where("slack_channel", "==", subject.userID),
+	}
+}
+
+// reviewerRotations returns the workspace's reviewer rotations that name the user as a member, the last
+// assigned reviewer, or whoever configured them.
+func (fs *FirestoreService) reviewerRotations(ctx context.Context, subject *userDataSubject) ([]*firestore.DocumentSnapshot, error) {
+	iter := fs.client.Collection("reviewer_rotations").Where("slack_team_id", "==", subject.teamID).Documents(ctx)
+	defer iter.Stop()
+
+	var docs []*firestore.DocumentSnapshot
+	for {
+		doc, err := iter.Next()
+		if errors.Is(err, iterator.Done) {
+			return docs, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get reviewer rotations: %w", err)
+		}
+
+		var rotation models.ReviewerRotation
+		if err := doc.DataTo(&rotation); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal reviewer rotation %s: %w", doc.Ref.ID, err)
+		}
+		if rotation.NamesUser(subject.userID) {
+			docs = append(docs, doc)
+		}
+	}
+}
+
+// ExportUserData collects everything stored about a Slack user in a workspace: their user document,
+// OAuth states, buffered digest entries, onboarding hints, link invitations, pending reviews, the
+// tracked messages of PRs they authored, the notifications sent to them, and the reviewer rotations
+// naming them. Stored Slack and GitHub tokens themselves are left out.
+func (fs *FirestoreService) ExportUserData(ctx context.Context, teamID, userID string) (*models.UserDataExport, error) {
+	subject, err := fs.loadUserDataSubject(ctx, teamID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &models.UserDataExport{
+		SlackTeamID: teamID,
+		SlackUserID: userID,
+		ExportedAt:  time.Now(),
+		Collections: make(map[string][]map[string]interface{}),
+	}
+	if subject.user != nil {
+		export.Collections["users"] = []map[string]interface{}{subject.user.Data()}
+	}
+
+	for collectionName, query := range fs.ownedQueries(subject) {
+		if err := fs.exportQuery(ctx, export, collectionName, query); err != nil {
+			return nil, err
+		}
+	}
+	for _, query := range fs.receivedNotifications(subject) {
+		if err := fs.exportQuery(ctx, export, "notification_log", query); err != nil {
+			return nil, err
+		}
+	}
+	rotations, err := fs.reviewerRotations(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+	for _, doc := range rotations {
+		export.Collections["reviewer_rotations"] = append(export.Collections["reviewer_rotations"], doc.Data())
+	}
+	if subject.githubUserID != 0 {
+		if err := fs.exportQuery(ctx, export, "pending_reviews", fs.pendingReviews(subject)); err != nil {
+			return nil, err
+		}
+		if err := fs.exportQuery(ctx, export, "trackedmessages", fs.authoredTrackedMessages(subject)); err != nil {
+			return nil, err
+		}
+	}
+
+	for collectionName, ref := range fs.ownedDocRefs(subject) {
+		doc, err := ref.Get(ctx)
+		if status.Code(err) == codes.NotFound {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to export %s: %w", ref.Path, err)
+		}
+		data := doc.Data()
+		delete(data, "access_token")
+		delete(data, "refresh_token")
+		export.Collections[collectionName] = append(export.Collections[collectionName], data)
+	}
+
+	return export, nil
+}
+
+// exportQuery adds the documents matching a query to an export.
+func (fs *FirestoreService) exportQuery(
+	ctx context.Context, export *models.UserDataExport, collectionName string, query firestore.Query,
+) error {
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+	for {
+		doc, err := iter.Next()
+		if errors.Is(err, iterator.Done) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to export %s: %w", collectionName, err)
+		}
+		export.Collections[collectionName] = append(export.Collections[collectionName], doc.Data())
+	}
+}
+
+// DeleteUserData deletes everything stored about a Slack user in a workspace, including the notifications
+// sent to them, and removes the user as author from the workspace's tracked messages and from its reviewer
+// rotations. Pending reviews are kept while the same GitHub account is linked in another workspace.
+// The user document is deleted last, so a failed deletion can be retried.
+func (fs *FirestoreService) DeleteUserData(ctx context.Context, teamID, userID string) (*models.UserDataDeletion, error) {
+	subject, err := fs.loadUserDataSubject(ctx, teamID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.UserDataDeletion{Deleted: make(map[string]int), Anonymized: make(map[string]int)}
+
+	for collectionName, query := range fs.ownedQueries(subject) {
+		if result.Deleted[collectionName], err = fs.deleteAllQueryDocuments(ctx, query); err != nil {
+			return result, fmt.Errorf("failed to delete %s: %w", collectionName, err)
+		}
+	}
+	for collectionName, ref := range fs.ownedDocRefs(subject) {
+		count, err := fs.deleteIfExists(ctx, ref)
+		if err != nil {
+			return result, err
+		}
+		result.Deleted[collectionName] = count
+	}
+	for _, query := range fs.receivedNotifications(subject) {
+		count, err := fs.deleteAllQueryDocuments(ctx, query)
+		result.Deleted["notification_log"] += count
+		if err != nil {
+			return result, fmt.Errorf("failed to delete notification_log: %w", err)
+		}
+	}
+	if err := fs.removeReviewerRotationMember(ctx, subject, result); err != nil {
+		return result, err
+	}
+
+	if subject.githubUserID != 0 {
+		shared, err := fs.isGitHubAccountLinkedElsewhere(ctx, subject)
+		if err != nil {
+			return result, err
+		}
+		if !shared {
+			if result.Deleted["pending_reviews"], err = fs.deleteAllQueryDocuments(ctx, fs.pendingReviews(subject)); err != nil {
+				return result, fmt.Errorf("failed to delete pending_reviews: %w", err)
+			}
+		}
+
+		if result.Anonymized["trackedmessages"], err = fs.removeTrackedMessageAuthor(ctx, subject); err != nil {
+			return result, err
+		}
+	}
+
+	if subject.user != nil {
+		defer fs.userCache.clear()
+		if _, err := subject.user.Ref.Delete(ctx); err != nil {
+			return result, fmt.Errorf("failed to delete user %s: %w", userID, err)
+		}
+		result.Deleted["users"] = 1
+	}
+
+	return result, nil
+}
+
+// deleteAllQueryDocuments deletes every document matching a query.
+func (fs *FirestoreService) deleteAllQueryDocuments(ctx context.Context, query firestore.Query) (int, error) {
+	refs, err := fs.queryDocumentRefs(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	return fs.deleteDocuments(ctx, refs)
+}
+
+// queryDocumentRefs returns the references of every document matching a query.
+func (fs *FirestoreService) queryDocumentRefs(ctx context.Context, query firestore.Query) ([]*firestore.DocumentRef, error) {
+	iter := query.Select().Documents(ctx)
+	defer iter.Stop()
+
+	var refs []*firestore.DocumentRef
+	for {
+		doc, err := iter.Next()
+		if errors.Is(err, iterator.Done) {
+			return refs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, doc.Ref)
+	}
+}
+
+// deleteIfExists deletes a document, returning 1 if it existed.
+func (fs *FirestoreService) deleteIfExists(ctx context.Context, ref *firestore.DocumentRef) (int, error) {
+	if _, err := ref.Delete(ctx, firestore.Exists); err != nil {
+		if status.Code(err) == codes.NotFound {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to delete %s: %w", ref.Path, err)
+	}
+	return 1, nil
+}
+
+// isGitHubAccountLinkedElsewhere reports whether another user document links the same GitHub account.
+func (fs *FirestoreService) isGitHubAccountLinkedElsewhere(ctx context.Context, subject *userDataSubject) (bool, error) {
+	refs, err := fs.queryDocumentRefs(ctx,
+		fs.client.Collection("users").Where("github_user_id", "==", subject.githubUserID).Limit(2))
+	if err != nil {
+		return false, fmt.Errorf("failed to find other users linking GitHub account %d: %w", subject.githubUserID, err)
+	}
+	for _, ref := range refs {
+		if ref.ID != subject.userID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// removeTrackedMessageAuthor removes the user's GitHub ID from the workspace's tracked messages of PRs they
+// authored. The messages stay tracked, but can no longer be matched to the user.
+func (fs *FirestoreService) removeTrackedMessageAuthor(ctx context.Context, subject *userDataSubject) (int, error) {
+	refs, err := fs.queryDocumentRefs(ctx, fs.authoredTrackedMessages(subject))
+	if err != nil {
+		return 0, fmt.Errorf("failed to find tracked messages authored by the user: %w", err)
+	}
+	if len(refs) == 0 {
+		return 0, nil
+	}
+
+	bulkWriter := fs.client.BulkWriter(ctx)
+	jobs := make([]*firestore.BulkWriterJob, 0, len(refs))
+	for _, ref := range refs {
+		job, err := bulkWriter.Update(ref, []firestore.Update{{Path: "pr_author_github_id", Value: firestore.Delete}})
+		if err != nil {
+			bulkWriter.End()
+			return 0, fmt.Errorf("failed to queue update of %s: %w", ref.Path, err)
+		}
+		jobs = append(jobs, job)
+	}
+	bulkWriter.End()
+
+	updated := 0
+	for i, job := range jobs {
+		if _, err := job.Results(); err != nil {
+			return updated, fmt.Errorf("failed to remove author from %s: %w", refs[i].Path, err)
+		}
+		updated++
+	}
+	return updated, nil
+}
+
+// removeReviewerRotationMember removes the user from the workspace's reviewer rotations, keeping each rotation's
+// position, and from who last configured or was assigned by them. Rotations left without members are deleted,
+// as when they're saved without members.
+func (fs *FirestoreService) removeReviewerRotationMember(
+	ctx context.Context, subject *userDataSubject, result *models.UserDataDeletion,
+) error {
+	rotations, err := fs.reviewerRotations(ctx, subject)
+	if err != nil {
+		return err
+	}
+
+	for _, rotationDoc := range rotations {
+		deleted := false
+		err := fs.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+			deleted = false
+			doc, err := tx.Get(rotationDoc.Ref)
+			if status.Code(err) == codes.NotFound {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			var rotation models.ReviewerRotation
+			if err := doc.DataTo(&rotation); err != nil {
+				return err
+			}
+			members := slices.DeleteFunc(slices.Clone(rotation.Members), func(member string) bool {
+				return member == subject.userID
+			})
+			if len(members) == 0 {
+				deleted = true
+				return tx.Delete(rotationDoc.Ref)
+			}
+
+			rotation.SetMembers(members)
+			if rotation.LastAssigned == subject.userID {
+				rotation.LastAssigned = ""
+			}
+			if rotation.ConfiguredBy == subject.userID {
+				rotation.ConfiguredBy = ""
+			}
+			return tx.Set(rotationDoc.Ref, &rotation)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to remove user from reviewer rotation %s: %w", rotationDoc.Ref.ID, err)
+		}
+		if deleted {
+			result.Deleted["reviewer_rotations"]++
+		} else {
+			result.Anonymized["reviewer_rotations"]++
+		}
+	}
+	return nil
+}
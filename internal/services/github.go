@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github-slack-notifier/internal/config"
 	"github-slack-notifier/internal/log"
@@ -20,9 +21,43 @@ import (
 type GitHubService struct {
 	config           *config.Config
 	firestoreService *FirestoreService
-	privateKeyBytes  []byte
-	clientCache      map[int64]*github.Client // Cache clients by installation ID
-	transport        http.RoundTripper        // Custom transport for testing
+	apps             map[string]*githubApp              // GitHub App on each host by hostname; "" is github.com
+	clientCache      map[installationKey]*github.Client // Cache clients by host and installation ID
+	transport        http.RoundTripper                  // Custom transport for testing
+	codeOwners       *codeOwnersCache
+	excludedLines    *excludedLinesCache
+}
+
+// githubApp is the GitHub App registered on one GitHub host.
+type githubApp struct {
+	host            string // GitHub Enterprise Server hostname; empty for github.com
+	id              int64
+	privateKeyBytes []byte
+}
+
+// transportBaseURL returns the API base URL for the app's installation and JWT transports.
+func (app *githubApp) transportBaseURL() string {
+	return strings.TrimSuffix(models.GitHubEnterpriseAPIBaseURL(app.host), "/")
+}
+
+// newClient creates a GitHub client for the app's host using transport to authenticate.
+func (app *githubApp) newClient(transport http.RoundTripper) (*github.Client, error) {
+	client := github.NewClient(&http.Client{Transport: transport})
+	if app.host == "" {
+		return client, nil
+	}
+
+	client, err := client.WithEnterpriseURLs(models.GitHubEnterpriseAPIBaseURL(app.host), "https://"+app.host+"/api/uploads/")
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure GitHub client for %s: %w", app.host, err)
+	}
+	return client, nil
+}
+
+// installationKey identifies an installation across GitHub hosts, whose installation IDs are assigned independently.
+type installationKey struct {
+	host string
+	id   int64
 }
 
 // NewGitHubService creates a new GitHubService instance.
@@ -34,11 +69,19 @@ func NewGitHubService(cfg *config.Config, firestoreService *FirestoreService) (*
 func NewGitHubServiceWithTransport(
 	cfg *config.Config, firestoreService *FirestoreService, transport http.RoundTripper,
 ) (*GitHubService, error) {
-	// Decode the base64 encoded private key
+	// Decode the base64 encoded private keys
 	privateKeyBytes, err := base64.StdEncoding.DecodeString(cfg.GitHubPrivateKeyBase64)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode GitHub private key: %w", err)
 	}
+	apps := map[string]*githubApp{"": {id: cfg.GitHubAppID, privateKeyBytes: privateKeyBytes}}
+	for host, server := range cfg.GitHubEnterpriseServers {
+		serverKeyBytes, err := base64.StdEncoding.DecodeString(server.PrivateKeyBase64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode GitHub private key for %s: %w", host, err)
+		}
+		apps[host] = &githubApp{host: host, id: server.AppID, privateKeyBytes: serverKeyBytes}
+	}
 
 	// Use default transport if none provided
 	if transport == nil {
@@ -48,9 +91,11 @@ func NewGitHubServiceWithTransport(
 	return &GitHubService{
 		config:           cfg,
 		firestoreService: firestoreService,
-		privateKeyBytes:  privateKeyBytes,
-		clientCache:      make(map[int64]*github.Client),
+		apps:             apps,
+		clientCache:      make(map[installationKey]*github.Client),
 		transport:        transport,
+		codeOwners:       newCodeOwnersCache(),
+		excludedLines:    newExcludedLinesCache(),
 	}, nil
 }
 
@@ -61,11 +106,19 @@ var (
 	ErrInstallationNotFound = errors.New("GitHub installation not found for repository owner")
 	// ErrNoWorkspaceConfigurations is returned when no workspace configurations are found for a repository.
 	ErrNoWorkspaceConfigurations = errors.New("no workspace configurations found for repository")
+	// ErrNotPullRequestNode is returned when a GraphQL node ID doesn't refer to a pull request.
+	ErrNotPullRequestNode = errors.New("node is not a pull request")
+	// ErrUnknownGitHubHost is returned for installations on a GitHub Enterprise Server instance that isn't configured.
+	ErrUnknownGitHubHost = errors.New("GitHub Enterprise Server host not configured")
 )
 
 const (
-	expectedRepoParts = 2
-	maxReviewsPerPage = 100
+	expectedRepoParts      = 2
+	maxReviewsPerPage      = 100
+	maxFilesPerPage        = 100
+	maxPullRequestsPerPage = 100
+	maxCheckSuitesPerPage  = 100
+	maxPullRequestFiles    = 1000 // Enough for policy path matching without paging through huge PRs
 )
 
 // ClientForRepoWithWorkspace returns a GitHub client configured for the given repository with workspace validation.
@@ -124,80 +177,95 @@ func (s *GitHubService) ValidateWorkspaceInstallationAccess(
 func (s *GitHubService) createAndCacheClient(
 	ctx context.Context, installation *models.GitHubInstallation, repoFullName string,
 ) (*github.Client, error) {
+	key := installationKey{host: installation.GitHubHost(), id: installation.ID}
+
 	// Check if we have a cached client for this installation
-	if client, exists := s.clientCache[installation.ID]; exists {
+	if client, exists := s.clientCache[key]; exists {
 		return client, nil
 	}
 
 	// Create new client for this installation
-	client, err := s.createClientForInstallation(installation.ID)
+	client, err := s.createClientForInstallation(key)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create GitHub client for installation %d: %w", installation.ID, err)
 	}
 
 	// Cache the client
-	s.clientCache[installation.ID] = client
+	s.clientCache[key] = client
 
 	log.Debug(ctx, "Created GitHub client for repository",
 		"repo", repoFullName,
 		"installation_id", installation.ID,
+		"github_host", key.host,
 	)
 
 	return client, nil
 }
 
-// createClientForInstallation creates a GitHub client for a specific installation.
-func (s *GitHubService) createClientForInstallation(installationID int64) (*github.Client, error) {
+// createClientForInstallation creates a GitHub client for a specific installation, authenticating as the
+// GitHub App registered on the installation's host.
+func (s *GitHubService) createClientForInstallation(key installationKey) (*github.Client, error) {
+	app, ok := s.apps[key.host]
+	if !ok {
+		return nil, models.Permanent(fmt.Errorf("%w: %s", ErrUnknownGitHubHost, key.host))
+	}
+
 	// Create the installation transport
 	itr, err := ghinstallation.New(
-		s.transport,
-		s.config.GitHubAppID,
-		installationID,
-		s.privateKeyBytes,
+		newAPILoggingTransport("github", s.transport),
+		app.id,
+		key.id,
+		app.privateKeyBytes,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create GitHub App installation transport: %w", err)
 	}
 
+	if app.host != "" {
+		itr.BaseURL = app.transportBaseURL()
+	}
+
 	// Create GitHub client with the installation transport
-	client := github.NewClient(&http.Client{Transport: itr})
-	return client, nil
+	return app.newClient(itr)
+}
+
+// PRReviewSummary summarizes the reviews on a pull request.
+type PRReviewSummary struct {
+	State     string // Overall review state, e.g. "approved", or empty if there are no reviews
+	Approvals int    // Number of reviewers whose review state is an approval
+	Reviewers int    // Number of reviewers other than the author who approved or requested changes
 }
 
 // GetPullRequestWithReviews fetches a pull request and its review states.
 func (s *GitHubService) GetPullRequestWithReviews(
 	ctx context.Context, repoFullName string, prNumber int,
 ) (*github.PullRequest, string, error) {
-	parts := strings.Split(repoFullName, "/")
-	if len(parts) != expectedRepoParts {
-		return nil, "", fmt.Errorf("%w: %s", ErrInvalidRepoFormat, repoFullName)
-	}
-	owner, repo := parts[0], parts[1]
-
-	// Get any workspace that has this repository configured
-	repos, err := s.firestoreService.GetReposForAllWorkspaces(ctx, repoFullName)
+	pr, summary, err := s.GetPullRequestReviewSummary(ctx, repoFullName, prNumber)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to get repository configurations: %w", err)
-	}
-	if len(repos) == 0 {
-		return nil, "", fmt.Errorf("%w: %s", ErrNoWorkspaceConfigurations, repoFullName)
+		return nil, "", err
 	}
+	return pr, summary.State, nil
+}
 
-	// Use the first workspace's installation (any valid one will work for reading PR data)
-	client, err := s.ClientForRepoWithWorkspace(ctx, repoFullName, repos[0].WorkspaceID)
+// GetPullRequestReviewSummary fetches a pull request with its overall review state and approval count.
+// Closed PRs aren't checked for reviews and have an empty summary.
+func (s *GitHubService) GetPullRequestReviewSummary(
+	ctx context.Context, repoFullName string, prNumber int,
+) (*github.PullRequest, *PRReviewSummary, error) {
+	client, owner, repo, err := s.readClientForRepo(ctx, repoFullName)
 	if err != nil {
-		return nil, "", err
+		return nil, nil, err
 	}
 
 	// Fetch PR details
 	pr, _, err := client.PullRequests.Get(ctx, owner, repo, prNumber)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to fetch PR: %w", err)
+		return nil, nil, fmt.Errorf("failed to fetch PR: %w", err)
 	}
 
 	// If PR is closed or merged, no need to check reviews
 	if pr.GetState() != "open" {
-		return pr, "", nil
+		return pr, &PRReviewSummary{}, nil
 	}
 
 	// Fetch PR reviews
@@ -206,7 +274,7 @@ func (s *GitHubService) GetPullRequestWithReviews(
 	})
 	if err != nil {
 		log.Error(ctx, "Failed to fetch PR reviews", "error", err)
-		return nil, "", fmt.Errorf("failed to fetch PR reviews: %w", err)
+		return nil, nil, fmt.Errorf("failed to fetch PR reviews: %w", err)
 	}
 
 	// Get PR author's user ID for filtering their comments
@@ -242,17 +310,476 @@ func (s *GitHubService) GetPullRequestWithReviews(
 	}
 
 	// Determine overall review state based on all reviews, excluding PR author's comments
-	currentReviewState := determineOverallReviewState(userReviewStates, prAuthorID)
+	summary := &PRReviewSummary{
+		State:     determineOverallReviewState(userReviewStates, prAuthorID),
+		Approvals: countApprovals(userReviewStates),
+		Reviewers: countDecidedReviewers(userReviewStates, prAuthorID),
+	}
 
 	log.Debug(ctx, "Fetched PR with reviews",
 		"repo", repoFullName,
 		"pr_number", prNumber,
 		"pr_state", pr.GetState(),
-		"review_state", currentReviewState,
+		"review_state", summary.State,
+		"approvals", summary.Approvals,
 		"review_count", len(reviews),
 	)
 
-	return pr, currentReviewState, nil
+	return pr, summary, nil
+}
+
+// GetFirstReviewTime returns when the first review from someone other than the PR author was submitted.
+// Returns nil if the PR has no reviews yet.
+func (s *GitHubService) GetFirstReviewTime(
+	ctx context.Context, repoFullName string, prNumber int, prAuthorID int64,
+) (*time.Time, error) {
+	client, owner, repo, err := s.readClientForRepo(ctx, repoFullName)
+	if err != nil {
+		return nil, err
+	}
+
+	reviews, _, err := client.PullRequests.ListReviews(ctx, owner, repo, prNumber, &github.ListOptions{
+		PerPage: maxReviewsPerPage,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch PR reviews: %w", err)
+	}
+
+	var firstReview *time.Time
+	for _, review := range reviews {
+		if review.User.GetID() == prAuthorID || review.SubmittedAt == nil {
+			continue
+		}
+		submittedAt := review.GetSubmittedAt().Time
+		if firstReview == nil || submittedAt.Before(*firstReview) {
+			firstReview = &submittedAt
+		}
+	}
+
+	return firstReview, nil
+}
+
+// GenerateReleaseNotes generates draft release notes for a tag using GitHub's release notes API.
+// Requires the GitHub App to have contents write permission on the repository.
+func (s *GitHubService) GenerateReleaseNotes(ctx context.Context, repoFullName, tagName string) (*github.RepositoryReleaseNotes, error) {
+	client, owner, repo, err := s.readClientForRepo(ctx, repoFullName)
+	if err != nil {
+		return nil, err
+	}
+
+	notes, _, err := client.Repositories.GenerateReleaseNotes(ctx, owner, repo, &github.GenerateNotesOptions{
+		TagName: tagName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate release notes for %s: %w", tagName, err)
+	}
+
+	return notes, nil
+}
+
+// GetRequiredApprovals returns the most approving reviews required by the rulesets that apply to a branch,
+// or 0 if none require approvals. Classic branch protection rules aren't visible without admin access,
+// so they're not counted.
+func (s *GitHubService) GetRequiredApprovals(ctx context.Context, repoFullName, branch string) (int, error) {
+	client, owner, repo, err := s.readClientForRepo(ctx, repoFullName)
+	if err != nil {
+		return 0, err
+	}
+
+	rules, _, err := client.Repositories.GetRulesForBranch(ctx, owner, repo, branch, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rules for branch %s: %w", branch, err)
+	}
+
+	required := 0
+	for _, rule := range rules.PullRequest {
+		required = max(required, rule.Parameters.RequiredApprovingReviewCount)
+	}
+	return required, nil
+}
+
+// GetPullRequest fetches a pull request without its reviews.
+func (s *GitHubService) GetPullRequest(ctx context.Context, repoFullName string, prNumber int) (*github.PullRequest, error) {
+	client, owner, repo, err := s.readClientForRepo(ctx, repoFullName)
+	if err != nil {
+		return nil, err
+	}
+
+	pr, _, err := client.PullRequests.Get(ctx, owner, repo, prNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch PR: %w", err)
+	}
+
+	return pr, nil
+}
+
+// GetIssue fetches an issue, for syncing the state of issue links tracked in Slack.
+func (s *GitHubService) GetIssue(ctx context.Context, repoFullName string, issueNumber int) (*github.Issue, error) {
+	client, owner, repo, err := s.readClientForRepo(ctx, repoFullName)
+	if err != nil {
+		return nil, err
+	}
+
+	issue, _, err := client.Issues.Get(ctx, owner, repo, issueNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch issue: %w", err)
+	}
+
+	return issue, nil
+}
+
+// RequestReviewers requests reviews on a pull request from GitHub users, using the workspace's installation.
+// Requires the Pull requests: Read & write permission.
+func (s *GitHubService) RequestReviewers(
+	ctx context.Context, repoFullName, workspaceID string, prNumber int, logins []string,
+) error {
+	owner, repo, ok := strings.Cut(repoFullName, "/")
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrInvalidRepoFormat, repoFullName)
+	}
+	client, err := s.ClientForRepoWithWorkspace(ctx, repoFullName, workspaceID)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = client.PullRequests.RequestReviewers(ctx, owner, repo, prNumber, github.ReviewersRequest{Reviewers: logins})
+	if err != nil {
+		return fmt.Errorf("failed to request reviewers: %w", err)
+	}
+	return nil
+}
+
+// CreateIssueComment comments on a pull request or issue as the app, using the workspace's installation, and returns
+// the comment's URL. Requires the Pull requests: Read & write permission, or Issues for issues.
+func (s *GitHubService) CreateIssueComment(
+	ctx context.Context, repoFullName, workspaceID string, number int, body string,
+) (string, error) {
+	owner, repo, ok := strings.Cut(repoFullName, "/")
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrInvalidRepoFormat, repoFullName)
+	}
+	client, err := s.ClientForRepoWithWorkspace(ctx, repoFullName, workspaceID)
+	if err != nil {
+		return "", err
+	}
+
+	comment, _, err := client.Issues.CreateComment(ctx, owner, repo, number, &github.IssueComment{Body: github.Ptr(body)})
+	if err != nil {
+		return "", fmt.Errorf("failed to create comment: %w", err)
+	}
+	return comment.GetHTMLURL(), nil
+}
+
+// ListPullRequestFiles returns the paths of files changed in a pull request, up to maxPullRequestFiles.
+func (s *GitHubService) ListPullRequestFiles(ctx context.Context, repoFullName string, prNumber int) ([]string, error) {
+	client, owner, repo, err := s.readClientForRepo(ctx, repoFullName)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	opts := &github.ListOptions{PerPage: maxFilesPerPage}
+	for len(paths) < maxPullRequestFiles {
+		files, resp, err := client.PullRequests.ListFiles(ctx, owner, repo, prNumber, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list PR files: %w", err)
+		}
+		for _, file := range files {
+			paths = append(paths, file.GetFilename())
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return paths, nil
+}
+
+// GetPullRequestState returns the state of a pull request: "open", "merged", or "closed".
+// Used for cross-repo dependency lookups, so the repository must be configured in at least one workspace.
+func (s *GitHubService) GetPullRequestState(ctx context.Context, repoFullName string, prNumber int) (string, error) {
+	pr, err := s.GetPullRequest(ctx, repoFullName, prNumber)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case pr.GetMerged():
+		return models.PRDependencyStateMerged, nil
+	case pr.GetState() == "closed":
+		return models.PRDependencyStateClosed, nil
+	default:
+		return models.PRDependencyStateOpen, nil
+	}
+}
+
+// ListOpenPullRequestsForCommit returns the open pull requests whose head is the given commit.
+// PRs that only contain the commit further back in their history are left out, since their CI state is newer.
+func (s *GitHubService) ListOpenPullRequestsForCommit(ctx context.Context, repoFullName, sha string) ([]*github.PullRequest, error) {
+	client, owner, repo, err := s.readClientForRepo(ctx, repoFullName)
+	if err != nil {
+		return nil, err
+	}
+
+	prs, _, err := client.PullRequests.ListPullRequestsWithCommit(ctx, owner, repo, sha, &github.ListOptions{PerPage: maxPullRequestsPerPage})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list PRs for commit: %w", err)
+	}
+
+	var open []*github.PullRequest
+	for _, pr := range prs {
+		if pr.GetState() == "open" && pr.GetHead().GetSHA() == sha {
+			open = append(open, pr)
+		}
+	}
+	return open, nil
+}
+
+// ListOpenPullRequestsForBranch returns the open pull requests whose base or head is the given branch,
+// i.e. the PRs whose mergeability can change when the branch is pushed to.
+func (s *GitHubService) ListOpenPullRequestsForBranch(ctx context.Context, repoFullName, branch string) ([]*github.PullRequest, error) {
+	client, owner, repo, err := s.readClientForRepo(ctx, repoFullName)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*github.PullRequest
+	seen := make(map[int]bool)
+	for _, opts := range []*github.PullRequestListOptions{
+		{State: "open", Base: branch, ListOptions: github.ListOptions{PerPage: maxPullRequestsPerPage}},
+		{State: "open", Head: owner + ":" + branch, ListOptions: github.ListOptions{PerPage: maxPullRequestsPerPage}},
+	} {
+		prs, _, err := client.PullRequests.List(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list PRs for branch %s: %w", branch, err)
+		}
+		for _, pr := range prs {
+			if !seen[pr.GetNumber()] {
+				seen[pr.GetNumber()] = true
+				result = append(result, pr)
+			}
+		}
+	}
+	return result, nil
+}
+
+// ListOpenPullRequests returns every open pull request in a repository, oldest first,
+// using the workspace's GitHub App installation.
+func (s *GitHubService) ListOpenPullRequests(ctx context.Context, repoFullName, workspaceID string) ([]*github.PullRequest, error) {
+	owner, repo, found := strings.Cut(repoFullName, "/")
+	if !found {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRepoFormat, repoFullName)
+	}
+
+	client, err := s.ClientForRepoWithWorkspace(ctx, repoFullName, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &github.PullRequestListOptions{
+		State:       "open",
+		Sort:        "created",
+		Direction:   "asc",
+		ListOptions: github.ListOptions{PerPage: maxPullRequestsPerPage},
+	}
+	var result []*github.PullRequest
+	for {
+		prs, resp, err := client.PullRequests.List(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list open PRs: %w", err)
+		}
+		result = append(result, prs...)
+		if resp.NextPage == 0 {
+			return result, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// GetCIState returns the combined CI state of a commit from both its commit statuses and its check suites.
+func (s *GitHubService) GetCIState(ctx context.Context, repoFullName, sha string) (models.CIState, error) {
+	client, owner, repo, err := s.readClientForRepo(ctx, repoFullName)
+	if err != nil {
+		return models.CIStateNone, err
+	}
+
+	var states []models.CIState
+
+	combined, _, err := client.Repositories.GetCombinedStatus(ctx, owner, repo, sha, nil)
+	if err != nil {
+		return models.CIStateNone, fmt.Errorf("failed to get combined status: %w", err)
+	}
+	if combined.GetTotalCount() > 0 {
+		states = append(states, commitStatusCIState(combined.GetState()))
+	}
+
+	suites, _, err := client.Checks.ListCheckSuitesForRef(ctx, owner, repo, sha,
+		&github.ListCheckSuiteOptions{ListOptions: github.ListOptions{PerPage: maxCheckSuitesPerPage}})
+	if err != nil {
+		return models.CIStateNone, fmt.Errorf("failed to list check suites: %w", err)
+	}
+	for _, suite := range suites.CheckSuites {
+		// GitHub creates a queued suite for every installed checks app, which stays queued if the app runs nothing
+		if suite.GetLatestCheckRunsCount() == 0 {
+			continue
+		}
+		states = append(states, checkSuiteCIState(suite.GetStatus(), suite.GetConclusion()))
+	}
+
+	return combineCIStates(states), nil
+}
+
+// commitStatusCIState maps a combined commit status state to a CI state.
+func commitStatusCIState(state string) models.CIState {
+	switch state {
+	case "success":
+		return models.CIStateSuccess
+	case "failure", "error":
+		return models.CIStateFailure
+	default:
+		return models.CIStatePending
+	}
+}
+
+// checkSuiteCIState maps a check suite's status and conclusion to a CI state.
+func checkSuiteCIState(status, conclusion string) models.CIState {
+	if status != "completed" {
+		return models.CIStatePending
+	}
+	switch conclusion {
+	case "success", "neutral", "skipped":
+		return models.CIStateSuccess
+	case "stale":
+		// Stale suites are superseded by a newer run that GitHub will report separately
+		return models.CIStatePending
+	default:
+		return models.CIStateFailure
+	}
+}
+
+// combineCIStates combines CI states, where any failure fails the commit and anything still running keeps it pending.
+func combineCIStates(states []models.CIState) models.CIState {
+	combined := models.CIStateNone
+	for _, state := range states {
+		switch {
+		case state == models.CIStateFailure:
+			return models.CIStateFailure
+		case state == models.CIStatePending:
+			combined = models.CIStatePending
+		case state == models.CIStateSuccess && combined == models.CIStateNone:
+			combined = models.CIStateSuccess
+		}
+	}
+	return combined
+}
+
+// PullRequestNode identifies a pull request resolved from a GraphQL node ID.
+type PullRequestNode struct {
+	RepoFullName string
+	Number       int
+	Milestone    string // Milestone title, empty if none
+}
+
+// pullRequestNodeQuery resolves a pull request node ID, as sent in projects_v2_item events.
+const pullRequestNodeQuery = `query($id: ID!) {
+  node(id: $id) {
+    ... on PullRequest {
+      number
+      repository { nameWithOwner }
+      milestone { title }
+    }
+  }
+}`
+
+// ResolvePullRequestNode looks up the repository, number and milestone of a pull request by its GraphQL node ID.
+// Uses the installation that sent the webhook, since project item events aren't tied to a repository.
+func (s *GitHubService) ResolvePullRequestNode(ctx context.Context, installationID int64, nodeID string) (*PullRequestNode, error) {
+	key := installationKey{id: installationID}
+	installation, err := s.firestoreService.GetGitHubInstallationByID(ctx, installationID)
+	if err != nil && !errors.Is(err, ErrGitHubInstallationNotFound) {
+		return nil, err
+	}
+	if installation != nil {
+		key.host = installation.GitHubHost()
+	}
+
+	client, exists := s.clientCache[key]
+	if !exists {
+		client, err = s.createClientForInstallation(key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create GitHub client for installation %d: %w", installationID, err)
+		}
+		s.clientCache[key] = client
+	}
+
+	// GitHub Enterprise Server serves GraphQL at /api/graphql rather than under the REST API's /api/v3/
+	graphqlURL := "graphql"
+	if key.host != "" {
+		graphqlURL = "https://" + key.host + "/api/graphql"
+	}
+
+	req, err := client.NewRequest(http.MethodPost, graphqlURL, map[string]any{
+		"query":     pullRequestNodeQuery,
+		"variables": map[string]string{"id": nodeID},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build GraphQL request: %w", err)
+	}
+
+	var result struct {
+		Data struct {
+			Node struct {
+				Number     int `json:"number"`
+				Repository struct {
+					NameWithOwner string `json:"nameWithOwner"`
+				} `json:"repository"`
+				Milestone *struct {
+					Title string `json:"title"`
+				} `json:"milestone"`
+			} `json:"node"`
+		} `json:"data"`
+	}
+	if _, err := client.Do(ctx, req, &result); err != nil {
+		return nil, fmt.Errorf("failed to resolve pull request node %s: %w", nodeID, err)
+	}
+
+	node := result.Data.Node
+	if node.Number == 0 || node.Repository.NameWithOwner == "" {
+		return nil, fmt.Errorf("%w: %s", ErrNotPullRequestNode, nodeID)
+	}
+
+	resolved := &PullRequestNode{RepoFullName: node.Repository.NameWithOwner, Number: node.Number}
+	if node.Milestone != nil {
+		resolved.Milestone = node.Milestone.Title
+	}
+	return resolved, nil
+}
+
+// readClientForRepo returns a GitHub client suitable for reading data from a repository,
+// using the installation of any workspace that has the repository configured.
+func (s *GitHubService) readClientForRepo(ctx context.Context, repoFullName string) (*github.Client, string, string, error) {
+	parts := strings.Split(repoFullName, "/")
+	if len(parts) != expectedRepoParts {
+		return nil, "", "", fmt.Errorf("%w: %s", ErrInvalidRepoFormat, repoFullName)
+	}
+
+	// Get any workspace that has this repository configured
+	repos, err := s.firestoreService.GetReposForAllWorkspaces(ctx, repoFullName)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("failed to get repository configurations: %w", err)
+	}
+	if len(repos) == 0 {
+		return nil, "", "", fmt.Errorf("%w: %s", ErrNoWorkspaceConfigurations, repoFullName)
+	}
+
+	// Use the first workspace's installation (any valid one will work for reading PR data)
+	client, err := s.ClientForRepoWithWorkspace(ctx, repoFullName, repos[0].WorkspaceID)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	return client, parts[0], parts[1], nil
 }
 
 // Review state priority constants.
@@ -343,6 +870,32 @@ func determineOverallReviewState(userReviewStates map[int64]string, prAuthorID i
 	return highestPriorityState
 }
 
+// countApprovals returns the number of reviewers whose standing review state is an approval.
+func countApprovals(userReviewStates map[int64]string) int {
+	approvals := 0
+	for _, state := range userReviewStates {
+		if models.ReviewState(state) == models.ReviewStateApproved {
+			approvals++
+		}
+	}
+	return approvals
+}
+
+// countDecidedReviewers returns the number of reviewers other than the PR author whose standing review state
+// is an approval or a request for changes.
+func countDecidedReviewers(userReviewStates map[int64]string, prAuthorID int64) int {
+	reviewers := 0
+	for userID, state := range userReviewStates {
+		switch models.ReviewState(state) {
+		case models.ReviewStateApproved, models.ReviewStateChangesRequested:
+			if userID != prAuthorID {
+				reviewers++
+			}
+		}
+	}
+	return reviewers
+}
+
 // parseGitHubReviewState converts a GitHub review state string to our ReviewState type.
 // Returns the parsed state and true if the state is recognized, false otherwise.
 func parseGitHubReviewState(state string) (models.ReviewState, bool) {
@@ -359,3 +912,42 @@ func parseGitHubReviewState(state string) (models.ReviewState, bool) {
 		return "", false
 	}
 }
+
+// CheckAppCredentials verifies that the credentials of the GitHub App on every configured host can mint a JWT
+// that the host accepts.
+func (s *GitHubService) CheckAppCredentials(ctx context.Context) error {
+	for _, app := range s.apps {
+		if err := s.checkAppCredentials(ctx, app); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkAppCredentials authenticates as the GitHub App on one host.
+func (s *GitHubService) checkAppCredentials(ctx context.Context, app *githubApp) error {
+	atr, err := ghinstallation.NewAppsTransport(
+		newAPILoggingTransport("github", s.transport),
+		app.id,
+		app.privateKeyBytes,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create GitHub App transport: %w", err)
+	}
+
+	if app.host != "" {
+		atr.BaseURL = app.transportBaseURL()
+	}
+
+	client, err := app.newClient(atr)
+	if err != nil {
+		return err
+	}
+	if _, _, err := client.Apps.Get(ctx, ""); err != nil {
+		if app.host != "" {
+			return fmt.Errorf("failed to authenticate as GitHub App on %s: %w", app.host, err)
+		}
+		return fmt.Errorf("failed to authenticate as GitHub App: %w", err)
+	}
+	return nil
+}
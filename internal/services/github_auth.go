@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"sort"
 	"time"
 
 	"github-slack-notifier/internal/config"
@@ -21,10 +22,9 @@ const (
 	oauthStateTimeout = 15 * time.Minute
 	httpClientTimeout = 30 * time.Second
 
-	// GitHub OAuth endpoints.
-	// #nosec G101 -- Public GitHub OAuth endpoint, not credentials
-	githubTokenURL = "https://github.com/login/oauth/access_token"
-	githubUserURL  = "https://api.github.com/user"
+	// github.com web and API URLs; GitHub Enterprise Server serves its API under /api/v3 on the instance's host.
+	githubWebURL  = "https://github.com"
+	githubUserURL = "https://api.github.com/user"
 )
 
 var (
@@ -52,18 +52,65 @@ type GitHubUser struct {
 type GitHubAuthService struct {
 	config           *config.Config
 	firestoreService *FirestoreService
+	encrypter        *TokenEncrypter // Encrypts stored user access tokens; nil stores them in plain text
 	httpClient       *http.Client
 }
 
-// NewGitHubAuthService creates a new GitHub authentication service.
-func NewGitHubAuthService(cfg *config.Config, firestoreService *FirestoreService) *GitHubAuthService {
+// githubOAuthApp is the OAuth client and URLs for authorizing with the GitHub App on one GitHub host.
+type githubOAuthApp struct {
+	clientID     string
+	clientSecret string
+	appSlug      string
+	webURL       string // e.g. https://github.com, for the authorize, token and installation pages
+	userURL      string // API endpoint for the authorized user
+}
+
+// NewGitHubAuthService creates a new GitHub authentication service. User access tokens stored for
+// REACTION_ACTIONS are encrypted with encrypter, unless it's nil.
+func NewGitHubAuthService(cfg *config.Config, firestoreService *FirestoreService, encrypter *TokenEncrypter) *GitHubAuthService {
 	return &GitHubAuthService{
 		config:           cfg,
 		firestoreService: firestoreService,
+		encrypter:        encrypter,
 		httpClient:       &http.Client{Timeout: httpClientTimeout},
 	}
 }
 
+// GitHubEnterpriseHosts returns the hostnames of the configured GitHub Enterprise Server instances, sorted.
+func (s *GitHubAuthService) GitHubEnterpriseHosts() []string {
+	hosts := make([]string, 0, len(s.config.GitHubEnterpriseServers))
+	for host := range s.config.GitHubEnterpriseServers {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+// oauthApp returns the OAuth client and URLs for a GitHub host, "" being github.com.
+func (s *GitHubAuthService) oauthApp(host string) (*githubOAuthApp, error) {
+	if host == "" {
+		return &githubOAuthApp{
+			clientID:     s.config.GitHubClientID,
+			clientSecret: s.config.GitHubClientSecret,
+			appSlug:      s.config.GitHubAppSlug,
+			webURL:       githubWebURL,
+			userURL:      githubUserURL,
+		}, nil
+	}
+
+	server, ok := s.config.GitHubEnterpriseServers[host]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownGitHubHost, host)
+	}
+	return &githubOAuthApp{
+		clientID:     server.ClientID,
+		clientSecret: server.ClientSecret,
+		appSlug:      server.AppSlug,
+		webURL:       "https://" + host,
+		userURL:      models.GitHubEnterpriseAPIBaseURL(host) + "user",
+	}, nil
+}
+
 // CreateOAuthState creates a new OAuth state for CSRF protection.
 func (s *GitHubAuthService) CreateOAuthState(
 	ctx context.Context, slackUserID, slackTeamID, slackChannel string,
@@ -95,29 +142,45 @@ func (s *GitHubAuthService) SaveOAuthState(ctx context.Context, state *models.OA
 	return s.firestoreService.CreateOAuthState(ctx, state)
 }
 
-// GetOAuthURL builds the GitHub OAuth authorization URL.
-func (s *GitHubAuthService) GetOAuthURL(stateID string) string {
-	baseURL := "https://github.com/login/oauth/authorize"
+// GetOAuthURL builds the GitHub OAuth authorization URL on the state's GitHub host.
+func (s *GitHubAuthService) GetOAuthURL(state *models.OAuthState) (string, error) {
+	app, err := s.oauthApp(state.GitHubHost)
+	if err != nil {
+		return "", err
+	}
+
+	baseURL := app.webURL + "/login/oauth/authorize"
 	params := url.Values{
-		"client_id":    {s.config.GitHubClientID},
+		"client_id":    {app.clientID},
 		"redirect_uri": {s.config.GitHubOAuthRedirectURL()},
 		"scope":        {"read:user user:email"},
-		"state":        {stateID},
+		"state":        {state.ID},
 	}
 
-	return fmt.Sprintf("%s?%s", baseURL, params.Encode())
+	return fmt.Sprintf("%s?%s", baseURL, params.Encode()), nil
 }
 
-// GetAppInstallationURL generates a GitHub App installation URL that supports combined OAuth + installation.
-func (s *GitHubAuthService) GetAppInstallationURL(stateID string) string {
+// GetAppInstallationURL generates a GitHub App installation URL on the state's GitHub host that supports
+// combined OAuth + installation.
+func (s *GitHubAuthService) GetAppInstallationURL(state *models.OAuthState) (string, error) {
+	app, err := s.oauthApp(state.GitHubHost)
+	if err != nil {
+		return "", err
+	}
+
 	// Use the GitHub App's direct installation URL
 	// Format: https://github.com/apps/{app-name}/installations/new?state={state}
-	baseURL := fmt.Sprintf("https://github.com/apps/%s/installations/new", s.config.GitHubAppSlug)
+	// GitHub Enterprise Server uses https://{host}/github-apps/{app-name}/installations/new
+	appsPath := "apps"
+	if state.GitHubHost != "" {
+		appsPath = "github-apps"
+	}
+	baseURL := fmt.Sprintf("%s/%s/%s/installations/new", app.webURL, appsPath, app.appSlug)
 	params := url.Values{
-		"state": {stateID},
+		"state": {state.ID},
 	}
 
-	return fmt.Sprintf("%s?%s", baseURL, params.Encode())
+	return fmt.Sprintf("%s?%s", baseURL, params.Encode()), nil
 }
 
 // ValidateAndConsumeState validates OAuth state and returns associated user info.
@@ -148,40 +211,77 @@ func (s *GitHubAuthService) ValidateAndConsumeState(ctx context.Context, stateID
 	return state, nil
 }
 
-// ExchangeCodeForUser exchanges OAuth code for GitHub user information.
-func (s *GitHubAuthService) ExchangeCodeForUser(ctx context.Context, code string) (*GitHubUser, error) {
+// ExchangeCodeForUser exchanges OAuth code for GitHub user information on a GitHub host, "" being github.com.
+func (s *GitHubAuthService) ExchangeCodeForUser(ctx context.Context, code, host string) (*GitHubUser, error) {
 	if code == "" {
 		return nil, ErrCodeRequired
 	}
 
+	app, err := s.oauthApp(host)
+	if err != nil {
+		return nil, err
+	}
+
+	user, _, err := s.exchangeCodeForUser(ctx, app, code)
+	return user, err
+}
+
+// exchangeCodeForUser exchanges OAuth code for an access token and the GitHub user it belongs to.
+func (s *GitHubAuthService) exchangeCodeForUser(
+	ctx context.Context, app *githubOAuthApp, code string,
+) (*GitHubUser, *userAccessToken, error) {
 	// Exchange code for access token
-	accessToken, err := s.exchangeCodeForToken(ctx, code)
+	token, err := s.requestToken(ctx, app, url.Values{"code": {code}})
 	if err != nil {
-		return nil, fmt.Errorf("failed to exchange code for token: %w", err)
+		return nil, nil, fmt.Errorf("failed to exchange code for token: %w", err)
 	}
 
 	// Fetch user information using access token
-	user, err := s.fetchGitHubUser(ctx, accessToken)
+	user, err := s.fetchGitHubUser(ctx, app, token.AccessToken)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch GitHub user: %w", err)
+		return nil, nil, fmt.Errorf("failed to fetch GitHub user: %w", err)
 	}
 
-	return user, nil
+	return user, token, nil
+}
+
+// userAccessToken is a token response from GitHub. Refresh tokens and expiry times are only returned for
+// GitHub App user access tokens, when the app has token expiration enabled.
+type userAccessToken struct {
+	AccessToken           string `json:"access_token"`
+	TokenType             string `json:"token_type"`
+	ExpiresIn             int    `json:"expires_in"`
+	RefreshToken          string `json:"refresh_token"`
+	RefreshTokenExpiresIn int    `json:"refresh_token_expires_in"`
+	Error                 string `json:"error"`
+	ErrorDesc             string `json:"error_description"`
 }
 
 // exchangeCodeForToken exchanges authorization code for access token.
-func (s *GitHubAuthService) exchangeCodeForToken(ctx context.Context, code string) (string, error) {
-	tokenURL := githubTokenURL
+func (s *GitHubAuthService) exchangeCodeForToken(ctx context.Context, app *githubOAuthApp, code string) (string, error) {
+	token, err := s.requestToken(ctx, app, url.Values{"code": {code}})
+	if err != nil {
+		return "", err
+	}
+	return token.AccessToken, nil
+}
+
+// requestToken requests an access token from a GitHub host's token endpoint, for an authorization code or a
+// refresh token grant given in params.
+func (s *GitHubAuthService) requestToken(ctx context.Context, app *githubOAuthApp, params url.Values) (*userAccessToken, error) {
+	tokenURL := app.webURL + "/login/oauth/access_token"
 
 	data := url.Values{
-		"client_id":     {s.config.GitHubClientID},
-		"client_secret": {s.config.GitHubClientSecret},
-		"code":          {code},
+		"client_id":     {app.clientID},
+		"client_secret": {app.clientSecret},
+	}
+	for key, values := range params {
+		data[key] = values
 	}
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, nil)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	req.Header.Set("Accept", "application/json")
@@ -190,39 +290,33 @@ func (s *GitHubAuthService) exchangeCodeForToken(ctx context.Context, code strin
 
 	resp, err := s.httpClient.Do(req)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("%w: status %d", ErrTokenExchangeFailed, resp.StatusCode)
-	}
-
-	var tokenResp struct {
-		AccessToken string `json:"access_token"`
-		TokenType   string `json:"token_type"`
-		Error       string `json:"error"`
-		ErrorDesc   string `json:"error_description"`
+		return nil, fmt.Errorf("%w: status %d", ErrTokenExchangeFailed, resp.StatusCode)
 	}
 
+	var tokenResp userAccessToken
 	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
-		return "", fmt.Errorf("failed to decode token response: %w", err)
+		return nil, fmt.Errorf("failed to decode token response: %w", err)
 	}
 
 	if tokenResp.Error != "" {
-		return "", fmt.Errorf("%w: %s - %s", ErrGitHubOAuthError, tokenResp.Error, tokenResp.ErrorDesc)
+		return nil, fmt.Errorf("%w: %s - %s", ErrGitHubOAuthError, tokenResp.Error, tokenResp.ErrorDesc)
 	}
 
 	if tokenResp.AccessToken == "" {
-		return "", ErrNoAccessToken
+		return nil, ErrNoAccessToken
 	}
 
-	return tokenResp.AccessToken, nil
+	return &tokenResp, nil
 }
 
 // fetchGitHubUser fetches user information from GitHub API.
-func (s *GitHubAuthService) fetchGitHubUser(ctx context.Context, accessToken string) (*GitHubUser, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, githubUserURL, nil)
+func (s *GitHubAuthService) fetchGitHubUser(ctx context.Context, app *githubOAuthApp, accessToken string) (*GitHubUser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, app.userURL, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -249,20 +343,25 @@ func (s *GitHubAuthService) fetchGitHubUser(ctx context.Context, accessToken str
 	return &user, nil
 }
 
-// ExchangeCodeForUserAndToken exchanges OAuth code for both GitHub user information and access token.
+// ExchangeCodeForUserAndToken exchanges OAuth code for both GitHub user information and access token on github.com.
 func (s *GitHubAuthService) ExchangeCodeForUserAndToken(ctx context.Context, code string) (*GitHubUser, string, error) {
 	if code == "" {
 		return nil, "", ErrCodeRequired
 	}
 
+	app, err := s.oauthApp("")
+	if err != nil {
+		return nil, "", err
+	}
+
 	// Exchange code for access token
-	accessToken, err := s.exchangeCodeForToken(ctx, code)
+	accessToken, err := s.exchangeCodeForToken(ctx, app, code)
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to exchange code for token: %w", err)
 	}
 
 	// Fetch user information using access token
-	user, err := s.fetchGitHubUser(ctx, accessToken)
+	user, err := s.fetchGitHubUser(ctx, app, accessToken)
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to fetch GitHub user: %w", err)
 	}
@@ -270,7 +369,11 @@ func (s *GitHubAuthService) ExchangeCodeForUserAndToken(ctx context.Context, cod
 	return user, accessToken, nil
 }
 
-// ExchangeCodeForToken exchanges authorization code for access token (public wrapper).
+// ExchangeCodeForToken exchanges authorization code for access token on github.com (public wrapper).
 func (s *GitHubAuthService) ExchangeCodeForToken(ctx context.Context, code string) (string, error) {
-	return s.exchangeCodeForToken(ctx, code)
+	app, err := s.oauthApp("")
+	if err != nil {
+		return "", err
+	}
+	return s.exchangeCodeForToken(ctx, app, code)
 }
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v74/github"
+)
+
+// codeOwnersTTL is how long a repository's CODEOWNERS file is cached before it's fetched again.
+const codeOwnersTTL = 10 * time.Minute
+
+// codeOwnersPaths are the locations GitHub reads a CODEOWNERS file from, in order of precedence.
+var codeOwnersPaths = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// codeOwnersCache caches the CODEOWNERS content of each repository, keyed by full name.
+// Repositories without a CODEOWNERS file are cached as empty, so they aren't looked up on every PR event.
+type codeOwnersCache struct {
+	mu      sync.Mutex
+	entries map[string]*codeOwnersEntry
+	now     func() time.Time
+}
+
+// codeOwnersEntry is a repository's CODEOWNERS content, with when it was fetched.
+type codeOwnersEntry struct {
+	content   string
+	fetchedAt time.Time
+}
+
+// newCodeOwnersCache creates an empty CODEOWNERS cache.
+func newCodeOwnersCache() *codeOwnersCache {
+	return &codeOwnersCache{
+		entries: make(map[string]*codeOwnersEntry),
+		now:     time.Now,
+	}
+}
+
+// get returns the cached CODEOWNERS content for a repository, and whether there was an unexpired entry.
+func (c *codeOwnersCache) get(repoFullName string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[repoFullName]
+	if !ok || c.now().Sub(entry.fetchedAt) > codeOwnersTTL {
+		return "", false
+	}
+	return entry.content, true
+}
+
+// set caches the CODEOWNERS content for a repository.
+func (c *codeOwnersCache) set(repoFullName, content string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[repoFullName] = &codeOwnersEntry{content: content, fetchedAt: c.now()}
+}
+
+// GetCodeOwners returns the content of a repository's CODEOWNERS file from its default branch,
+// or "" if it has none. Results are cached for codeOwnersTTL.
+func (s *GitHubService) GetCodeOwners(ctx context.Context, repoFullName string) (string, error) {
+	if content, ok := s.codeOwners.get(repoFullName); ok {
+		return content, nil
+	}
+
+	client, owner, repo, err := s.readClientForRepo(ctx, repoFullName)
+	if err != nil {
+		return "", err
+	}
+
+	var content string
+	for _, path := range codeOwnersPaths {
+		file, _, _, err := client.Repositories.GetContents(ctx, owner, repo, path, nil)
+		if err != nil {
+			var errResp *github.ErrorResponse
+			if errors.As(err, &errResp) && errResp.Response != nil && errResp.Response.StatusCode == http.StatusNotFound {
+				continue
+			}
+			return "", fmt.Errorf("failed to get %s: %w", path, err)
+		}
+		if file == nil {
+			continue
+		}
+		content, err = file.GetContent()
+		if err != nil {
+			return "", fmt.Errorf("failed to decode %s: %w", path, err)
+		}
+		break
+	}
+
+	s.codeOwners.set(repoFullName, content)
+	return content, nil
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v74/github"
+
+	"github-slack-notifier/internal/prsize"
+)
+
+// excludedLinesTTL is how long the excluded lines of a PR's head commit are cached. A head commit's files never
+// change, so this only bounds how long entries for commits that have been superseded are kept.
+const excludedLinesTTL = 24 * time.Hour
+
+// excludedLinesCache caches the lines changed in a PR's files matching PR_SIZE_EXCLUDE_PATHS, keyed by repository,
+// PR number and head SHA, so a PR's files are listed once per push rather than on every event that updates it.
+type excludedLinesCache struct {
+	mu      sync.Mutex
+	entries map[excludedLinesKey]*excludedLinesEntry
+	now     func() time.Time
+}
+
+// excludedLinesKey identifies a PR at one head commit.
+type excludedLinesKey struct {
+	repoFullName string
+	prNumber     int
+	headSHA      string
+}
+
+// excludedLinesEntry is the excluded lines of a PR at one head commit, with when they were counted.
+type excludedLinesEntry struct {
+	lines     int
+	fetchedAt time.Time
+}
+
+// newExcludedLinesCache creates an empty excluded lines cache.
+func newExcludedLinesCache() *excludedLinesCache {
+	return &excludedLinesCache{
+		entries: make(map[excludedLinesKey]*excludedLinesEntry),
+		now:     time.Now,
+	}
+}
+
+// get returns the cached excluded lines for a PR's head commit, and whether there was an unexpired entry.
+func (c *excludedLinesCache) get(key excludedLinesKey) (int, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || c.now().Sub(entry.fetchedAt) > excludedLinesTTL {
+		return 0, false
+	}
+	return entry.lines, true
+}
+
+// set caches the excluded lines for a PR's head commit, dropping expired entries.
+func (c *excludedLinesCache) set(key excludedLinesKey, lines int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	for k, entry := range c.entries {
+		if now.Sub(entry.fetchedAt) > excludedLinesTTL {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = &excludedLinesEntry{lines: lines, fetchedAt: now}
+}
+
+// PullRequestSize returns a PR's lines changed for picking its size emoji: its additions plus deletions, less
+// those in files matching PR_SIZE_EXCLUDE_PATHS. The PR's files are only listed when patterns are configured,
+// and are counted once per head commit. Only the first maxPullRequestFiles files are checked, so excluded files
+// beyond them still count.
+func (s *GitHubService) PullRequestSize(ctx context.Context, repoFullName string, pr *github.PullRequest) (int, error) {
+	size := pr.GetAdditions() + pr.GetDeletions()
+	if len(s.config.PRSizeExcludePaths) == 0 {
+		return size, nil
+	}
+
+	key := excludedLinesKey{repoFullName: repoFullName, prNumber: pr.GetNumber(), headSHA: pr.GetHead().GetSHA()}
+	excluded, ok := s.excludedLines.get(key)
+	if !ok {
+		var err error
+		excluded, err = s.countExcludedLines(ctx, repoFullName, pr.GetNumber())
+		if err != nil {
+			return size, err
+		}
+		if key.headSHA != "" {
+			s.excludedLines.set(key, excluded)
+		}
+	}
+
+	return max(size-excluded, 0), nil
+}
+
+// countExcludedLines returns the lines changed in a PR's files matching PR_SIZE_EXCLUDE_PATHS.
+func (s *GitHubService) countExcludedLines(ctx context.Context, repoFullName string, prNumber int) (int, error) {
+	client, owner, repo, err := s.readClientForRepo(ctx, repoFullName)
+	if err != nil {
+		return 0, err
+	}
+
+	excluded := 0
+	listed := 0
+	opts := &github.ListOptions{PerPage: maxFilesPerPage}
+	for listed < maxPullRequestFiles {
+		files, resp, err := client.PullRequests.ListFiles(ctx, owner, repo, prNumber, opts)
+		if err != nil {
+			return 0, fmt.Errorf("failed to list PR files: %w", err)
+		}
+		for _, file := range files {
+			if prsize.IsExcludedPath(s.config.PRSizeExcludePaths, file.GetFilename()) {
+				excluded += file.GetAdditions() + file.GetDeletions()
+			}
+		}
+		listed += len(files)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return excluded, nil
+}
@@ -1,11 +1,21 @@
 package services
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
 	"testing"
+	"time"
 
+	"github-slack-notifier/internal/config"
 	"github-slack-notifier/internal/models"
 
+	"github.com/google/go-github/v74/github"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDetermineOverallReviewState_PRAuthorCommentFiltering(t *testing.T) {
@@ -227,3 +237,167 @@ func TestDetermineOverallReviewState_MultipleStatesPerUser(t *testing.T) {
 		})
 	}
 }
+
+func TestCombineCIStates(t *testing.T) {
+	tests := []struct {
+		name     string
+		states   []models.CIState
+		expected models.CIState
+	}{
+		{
+			name:     "no CI",
+			states:   nil,
+			expected: models.CIStateNone,
+		},
+		{
+			name:     "all passed",
+			states:   []models.CIState{models.CIStateSuccess, models.CIStateSuccess},
+			expected: models.CIStateSuccess,
+		},
+		{
+			name:     "still running",
+			states:   []models.CIState{models.CIStateSuccess, models.CIStatePending},
+			expected: models.CIStatePending,
+		},
+		{
+			name:     "failure wins over pending",
+			states:   []models.CIState{models.CIStatePending, models.CIStateFailure, models.CIStateSuccess},
+			expected: models.CIStateFailure,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, combineCIStates(tt.states))
+		})
+	}
+}
+
+func TestCheckSuiteCIState(t *testing.T) {
+	assert.Equal(t, models.CIStatePending, checkSuiteCIState("in_progress", ""))
+	assert.Equal(t, models.CIStateSuccess, checkSuiteCIState("completed", "success"))
+	assert.Equal(t, models.CIStateSuccess, checkSuiteCIState("completed", "skipped"))
+	assert.Equal(t, models.CIStateFailure, checkSuiteCIState("completed", "timed_out"))
+	assert.Equal(t, models.CIStateFailure, checkSuiteCIState("completed", "cancelled"))
+	assert.Equal(t, models.CIStateFailure, commitStatusCIState("error"))
+}
+
+func TestCountApprovals(t *testing.T) {
+	assert.Equal(t, 0, countApprovals(map[int64]string{}))
+	assert.Equal(t, 2, countApprovals(map[int64]string{
+		1: string(models.ReviewStateApproved),
+		2: string(models.ReviewStateChangesRequested),
+		3: string(models.ReviewStateApproved),
+		4: string(models.ReviewStateCommented),
+	}))
+}
+
+func TestCountDecidedReviewers(t *testing.T) {
+	assert.Equal(t, 0, countDecidedReviewers(map[int64]string{}, 1))
+	assert.Equal(t, 2, countDecidedReviewers(map[int64]string{
+		1: string(models.ReviewStateApproved), // PR author
+		2: string(models.ReviewStateChangesRequested),
+		3: string(models.ReviewStateApproved),
+		4: string(models.ReviewStateCommented),
+		5: string(models.ReviewStateDismissed),
+	}, 1))
+}
+
+func TestGitHubService_GetCodeOwners_Cached(t *testing.T) {
+	now := time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)
+	s := &GitHubService{codeOwners: newCodeOwnersCache()}
+	s.codeOwners.now = func() time.Time { return now }
+	s.codeOwners.set("org/repo", "* @octocat\n")
+	s.codeOwners.set("org/no-owners", "")
+
+	content, err := s.GetCodeOwners(context.Background(), "org/repo")
+	require.NoError(t, err)
+	assert.Equal(t, "* @octocat\n", content)
+
+	content, err = s.GetCodeOwners(context.Background(), "org/no-owners")
+	require.NoError(t, err, "repositories without CODEOWNERS are cached too")
+	assert.Empty(t, content)
+
+	now = now.Add(codeOwnersTTL + time.Second)
+	_, ok := s.codeOwners.get("org/repo")
+	assert.False(t, ok, "expired CODEOWNERS content is refetched")
+}
+
+func TestGitHubService_PullRequestSize(t *testing.T) {
+	now := time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)
+	s := &GitHubService{config: &config.Config{}, excludedLines: newExcludedLinesCache()}
+	s.excludedLines.now = func() time.Time { return now }
+	pr := &github.PullRequest{
+		Number:    github.Ptr(7),
+		Additions: github.Ptr(900),
+		Deletions: github.Ptr(100),
+		Head:      &github.PullRequestBranch{SHA: github.Ptr("abc123")},
+	}
+
+	size, err := s.PullRequestSize(context.Background(), "org/repo", pr)
+	require.NoError(t, err)
+	assert.Equal(t, 1000, size, "every file counts without exclude patterns")
+
+	s.config.PRSizeExcludePaths = []string{"vendor/**"}
+	s.excludedLines.set(excludedLinesKey{repoFullName: "org/repo", prNumber: 7, headSHA: "abc123"}, 850)
+	size, err = s.PullRequestSize(context.Background(), "org/repo", pr)
+	require.NoError(t, err)
+	assert.Equal(t, 150, size)
+
+	now = now.Add(excludedLinesTTL + time.Second)
+	_, ok := s.excludedLines.get(excludedLinesKey{repoFullName: "org/repo", prNumber: 7, headSHA: "abc123"})
+	assert.False(t, ok, "expired entries are recounted")
+}
+
+func TestGitHubService_CreateClientForInstallation_EnterpriseServer(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	privateKeyBase64 := base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	}))
+
+	s, err := NewGitHubService(&config.Config{
+		GitHubAppID:            1,
+		GitHubPrivateKeyBase64: privateKeyBase64,
+		GitHubEnterpriseServers: map[string]config.GitHubEnterpriseServer{
+			"github.example.com": {Host: "github.example.com", AppID: 2, PrivateKeyBase64: privateKeyBase64},
+		},
+	}, nil)
+	require.NoError(t, err)
+
+	client, err := s.createClientForInstallation(installationKey{id: 42})
+	require.NoError(t, err)
+	assert.Equal(t, "https://api.github.com/", client.BaseURL.String())
+
+	client, err = s.createClientForInstallation(installationKey{host: "github.example.com", id: 42})
+	require.NoError(t, err)
+	assert.Equal(t, "https://github.example.com/api/v3/", client.BaseURL.String())
+	assert.Equal(t, "https://github.example.com/api/uploads/", client.UploadURL.String())
+
+	_, err = s.createClientForInstallation(installationKey{host: "unknown.example.com", id: 42})
+	require.ErrorIs(t, err, ErrUnknownGitHubHost)
+}
+
+func TestSetUserTokenFields(t *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	userToken := &models.GitHubUserToken{}
+
+	setUserTokenFields(userToken, &userAccessToken{
+		AccessToken:           "ghu_new",
+		RefreshToken:          "ghr_new",
+		ExpiresIn:             28800,
+		RefreshTokenExpiresIn: 15897600,
+	}, now)
+	assert.Equal(t, "ghu_new", userToken.AccessToken)
+	assert.Equal(t, "ghr_new", userToken.RefreshToken)
+	require.NotNil(t, userToken.ExpiresAt)
+	assert.Equal(t, now.Add(8*time.Hour), *userToken.ExpiresAt)
+	require.NotNil(t, userToken.RefreshExpiresAt)
+	assert.Equal(t, now.Add(184*24*time.Hour), *userToken.RefreshExpiresAt)
+
+	setUserTokenFields(userToken, &userAccessToken{AccessToken: "gho_forever"}, now)
+	assert.Empty(t, userToken.RefreshToken)
+	assert.Nil(t, userToken.ExpiresAt, "tokens without expiry don't expire")
+	assert.Nil(t, userToken.RefreshExpiresAt)
+}
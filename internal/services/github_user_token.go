@@ -0,0 +1,219 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/google/go-github/v74/github"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+)
+
+// userTokenRefreshMargin is how long before it expires a GitHub user access token is refreshed, so it doesn't
+// expire mid-request.
+const userTokenRefreshMargin = 5 * time.Minute
+
+var (
+	ErrGitHubUserTokenNotFound = errors.New("GitHub user token not found")
+	ErrGitHubUserTokenExpired  = errors.New("GitHub user token expired and can't be refreshed")
+)
+
+// ExchangeCodeForUserAndSaveToken exchanges OAuth code for GitHub user information on the state's GitHub host,
+// and stores the user access token for the Slack user when REACTION_ACTIONS is configured, so reactions can act
+// on GitHub as them. Failing to store the token is logged rather than failing the link.
+func (s *GitHubAuthService) ExchangeCodeForUserAndSaveToken(
+	ctx context.Context, code string, state *models.OAuthState,
+) (*GitHubUser, error) {
+	if code == "" {
+		return nil, ErrCodeRequired
+	}
+
+	app, err := s.oauthApp(state.GitHubHost)
+	if err != nil {
+		return nil, err
+	}
+
+	user, token, err := s.exchangeCodeForUser(ctx, app, code)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(s.config.ReactionActions) > 0 {
+		userToken := &models.GitHubUserToken{
+			SlackUserID:  state.SlackUserID,
+			SlackTeamID:  state.SlackTeamID,
+			GitHubUserID: user.ID,
+			GitHubHost:   state.GitHubHost,
+		}
+		setUserTokenFields(userToken, token, time.Now())
+		if err := s.saveUserToken(ctx, userToken); err != nil {
+			log.Warn(ctx, "Failed to save GitHub user token for reaction actions", "error", err)
+		}
+	}
+
+	return user, nil
+}
+
+// setUserTokenFields copies a token response onto a stored user token, working out when it expires from now.
+func setUserTokenFields(userToken *models.GitHubUserToken, token *userAccessToken, now time.Time) {
+	userToken.AccessToken = token.AccessToken
+	userToken.RefreshToken = token.RefreshToken
+	userToken.ExpiresAt = nil
+	userToken.RefreshExpiresAt = nil
+	if token.ExpiresIn > 0 {
+		expiresAt := now.Add(time.Duration(token.ExpiresIn) * time.Second)
+		userToken.ExpiresAt = &expiresAt
+	}
+	if token.RefreshTokenExpiresIn > 0 {
+		refreshExpiresAt := now.Add(time.Duration(token.RefreshTokenExpiresIn) * time.Second)
+		userToken.RefreshExpiresAt = &refreshExpiresAt
+	}
+}
+
+// saveUserToken stores a user's GitHub user access token, encrypting the access and refresh tokens.
+func (s *GitHubAuthService) saveUserToken(ctx context.Context, token *models.GitHubUserToken) error {
+	token.ID = userTokenDocID(token.SlackTeamID, token.SlackUserID)
+	if token.CreatedAt.IsZero() {
+		token.CreatedAt = time.Now()
+	}
+
+	stored := *token
+	var err error
+	if stored.AccessToken, err = s.encrypter.Encrypt(ctx, token.AccessToken); err != nil {
+		return fmt.Errorf("failed to encrypt GitHub user token: %w", err)
+	}
+	if token.RefreshToken != "" {
+		if stored.RefreshToken, err = s.encrypter.Encrypt(ctx, token.RefreshToken); err != nil {
+			return fmt.Errorf("failed to encrypt GitHub refresh token: %w", err)
+		}
+	}
+
+	return s.firestoreService.SaveGitHubUserToken(ctx, &stored)
+}
+
+// DeleteUserToken removes a user's stored GitHub user access token. Deleting a missing token is not an error.
+func (s *GitHubAuthService) DeleteUserToken(ctx context.Context, teamID, slackUserID string) error {
+	return s.firestoreService.DeleteGitHubUserToken(ctx, teamID, slackUserID)
+}
+
+// UserClient returns a GitHub client acting as a Slack user with the GitHub user access token they granted when
+// linking their account, refreshing it first if it has expired. Returns ErrGitHubUserTokenNotFound when no token
+// is stored, and ErrGitHubUserTokenExpired when it can no longer be refreshed; either way the user has to link
+// their GitHub account again.
+func (s *GitHubAuthService) UserClient(ctx context.Context, teamID, slackUserID string) (*github.Client, error) {
+	token, err := s.firestoreService.GetGitHubUserToken(ctx, teamID, slackUserID)
+	if err != nil {
+		return nil, err
+	}
+	if token == nil {
+		return nil, models.Permanent(ErrGitHubUserTokenNotFound)
+	}
+
+	if token.AccessToken, err = s.encrypter.Decrypt(ctx, token.AccessToken); err != nil {
+		return nil, fmt.Errorf("failed to decrypt GitHub user token: %w", err)
+	}
+	if token.RefreshToken != "" {
+		if token.RefreshToken, err = s.encrypter.Decrypt(ctx, token.RefreshToken); err != nil {
+			return nil, fmt.Errorf("failed to decrypt GitHub refresh token: %w", err)
+		}
+	}
+
+	now := time.Now()
+	if token.ExpiresAt != nil && now.After(token.ExpiresAt.Add(-userTokenRefreshMargin)) {
+		if err := s.refreshUserToken(ctx, token, now); err != nil {
+			return nil, err
+		}
+	}
+
+	client := github.NewClient(nil).WithAuthToken(token.AccessToken)
+	if token.GitHubHost == "" {
+		return client, nil
+	}
+	return client.WithEnterpriseURLs(models.GitHubEnterpriseAPIBaseURL(token.GitHubHost), "https://"+token.GitHubHost+"/api/uploads/")
+}
+
+// refreshUserToken exchanges a user's refresh token for a new access token and stores it.
+func (s *GitHubAuthService) refreshUserToken(ctx context.Context, token *models.GitHubUserToken, now time.Time) error {
+	if token.RefreshToken == "" || (token.RefreshExpiresAt != nil && now.After(*token.RefreshExpiresAt)) {
+		return models.Permanent(ErrGitHubUserTokenExpired)
+	}
+
+	app, err := s.oauthApp(token.GitHubHost)
+	if err != nil {
+		return err
+	}
+
+	refreshed, err := s.requestToken(ctx, app, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {token.RefreshToken},
+	})
+	if errors.Is(err, ErrGitHubOAuthError) {
+		// GitHub rejects refresh tokens that have been revoked or already used
+		return models.Permanent(fmt.Errorf("%w: %w", ErrGitHubUserTokenExpired, err))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to refresh GitHub user token: %w", err)
+	}
+
+	setUserTokenFields(token, refreshed, now)
+	if err := s.saveUserToken(ctx, token); err != nil {
+		return err
+	}
+
+	log.Debug(ctx, "Refreshed GitHub user token", "slack_user_id", token.SlackUserID)
+	return nil
+}
+
+// SaveGitHubUserToken stores a user's GitHub user access token as given; the caller encrypts it.
+func (fs *FirestoreService) SaveGitHubUserToken(ctx context.Context, token *models.GitHubUserToken) error {
+	_, err := fs.client.Collection("github_user_tokens").Doc(token.ID).Set(ctx, token)
+	if err != nil {
+		log.Error(ctx, "Failed to save GitHub user token",
+			"error", err,
+			"team_id", token.SlackTeamID,
+			"user_id", token.SlackUserID,
+			"operation", "save_github_user_token",
+		)
+		return fmt.Errorf("failed to save GitHub user token: %w", err)
+	}
+	return nil
+}
+
+// GetGitHubUserToken retrieves a user's stored GitHub user access token, still encrypted. Returns nil if there
+// isn't one.
+func (fs *FirestoreService) GetGitHubUserToken(ctx context.Context, teamID, slackUserID string) (*models.GitHubUserToken, error) {
+	doc, err := fs.client.Collection("github_user_tokens").Doc(userTokenDocID(teamID, slackUserID)).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get GitHub user token: %w", err)
+	}
+
+	var token models.GitHubUserToken
+	if err := doc.DataTo(&token); err != nil {
+		return nil, fmt.Errorf("failed to decode GitHub user token: %w", err)
+	}
+	return &token, nil
+}
+
+// DeleteGitHubUserToken removes a user's stored GitHub user access token. Deleting a missing token is not an error.
+func (fs *FirestoreService) DeleteGitHubUserToken(ctx context.Context, teamID, slackUserID string) error {
+	_, err := fs.client.Collection("github_user_tokens").Doc(userTokenDocID(teamID, slackUserID)).Delete(ctx)
+	if err != nil && status.Code(err) != codes.NotFound {
+		log.Error(ctx, "Failed to delete GitHub user token",
+			"error", err,
+			"team_id", teamID,
+			"user_id", slackUserID,
+			"operation", "delete_github_user_token",
+		)
+		return fmt.Errorf("failed to delete GitHub user token: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"google.golang.org/api/cloudkms/v1"
+)
+
+// KMSKeyWrapper wraps token data keys with a Cloud KMS symmetric encryption key.
+// Data keys are unwrapped with whichever key version wrapped them, so rotating the KMS key doesn't break
+// existing tokens, and `toolbox rotate-encryption-key` re-wraps them with the new primary version.
+type KMSKeyWrapper struct {
+	keys    *cloudkms.ProjectsLocationsKeyRingsCryptoKeysService
+	keyName string // projects/{project}/locations/{location}/keyRings/{key_ring}/cryptoKeys/{key}
+}
+
+// NewKMSKeyWrapper creates a KMSKeyWrapper for a KMS key, authenticating with the default credentials.
+func NewKMSKeyWrapper(ctx context.Context, keyName string) (*KMSKeyWrapper, error) {
+	service, err := cloudkms.NewService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create KMS client: %w", err)
+	}
+	return &KMSKeyWrapper{keys: service.Projects.Locations.KeyRings.CryptoKeys, keyName: keyName}, nil
+}
+
+// WrapKey encrypts a data key with the KMS key's primary version.
+func (w *KMSKeyWrapper) WrapKey(ctx context.Context, dataKey []byte) ([]byte, string, error) {
+	resp, err := w.keys.Encrypt(w.keyName, &cloudkms.EncryptRequest{
+		Plaintext: base64.StdEncoding.EncodeToString(dataKey),
+	}).Context(ctx).Do()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to encrypt with KMS key %s: %w", w.keyName, err)
+	}
+
+	wrapped, err := base64.StdEncoding.DecodeString(resp.Ciphertext)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decode KMS ciphertext: %w", err)
+	}
+	return wrapped, resp.Name, nil
+}
+
+// UnwrapKey decrypts a data key wrapped by WrapKey.
+func (w *KMSKeyWrapper) UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error) {
+	resp, err := w.keys.Decrypt(w.keyName, &cloudkms.DecryptRequest{
+		Ciphertext: base64.StdEncoding.EncodeToString(wrapped),
+	}).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt with KMS key %s: %w", w.keyName, err)
+	}
+
+	dataKey, err := base64.StdEncoding.DecodeString(resp.Plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode KMS plaintext: %w", err)
+	}
+	return dataKey, nil
+}
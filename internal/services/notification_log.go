@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+)
+
+const (
+	// DefaultNotificationLogLimit is the number of entries returned when a notification log query sets no limit.
+	DefaultNotificationLogLimit = 100
+	// MaxNotificationLogLimit is the most entries a notification log query can return.
+	MaxNotificationLogLimit = 1000
+)
+
+// ErrInvalidNotificationLogQuery is returned for notification log queries that can't be run.
+var ErrInvalidNotificationLogQuery = errors.New("invalid notification log query")
+
+// NotificationLogQuery selects notification log entries, newest first. Empty fields don't filter.
+// Either RepoFullName or SlackTeamID must be set, so queries never scan the whole collection.
+type NotificationLogQuery struct {
+	RepoFullName string
+	PRNumber     int // Requires RepoFullName
+	SlackTeamID  string
+	Limit        int // Defaults to DefaultNotificationLogLimit
+}
+
+// SetNotificationLog turns on recording the messages, DMs and reactions the bot sends in the notification log,
+// keeping each entry for retention. A retention of 0 leaves the log off.
+func (s *SlackService) SetNotificationLog(firestoreService *FirestoreService, retention time.Duration) {
+	s.notificationLog = firestoreService
+	s.notificationLogRetention = retention
+}
+
+// recordNotification adds a notification the bot sent, or failed to send with err, to the notification log.
+// The PR and job are taken from the context's log fields unless the entry sets them.
+// Failures are logged rather than returned, as the log mustn't stop notifications being sent.
+func (s *SlackService) recordNotification(ctx context.Context, entry *models.NotificationLogEntry, err error) {
+	if s.notificationLog == nil || s.notificationLogRetention <= 0 {
+		return
+	}
+
+	fields := log.GetLogFields(ctx)
+	if entry.RepoFullName == "" {
+		entry.RepoFullName, _ = fields["repo"].(string)
+	}
+	if entry.PRNumber == 0 {
+		entry.PRNumber, _ = fields["pr_number"].(int)
+	}
+	entry.JobID, _ = fields["job_id"].(string)
+	entry.JobType, _ = fields["job_type"].(string)
+	entry.TraceID = log.TraceID(ctx)
+
+	entry.Outcome = models.NotificationOutcomeSent
+	if err != nil {
+		entry.Outcome = models.NotificationOutcomeFailed
+		entry.Error = err.Error()
+	}
+	entry.SentAt = time.Now()
+	entry.ExpiresAt = entry.SentAt.Add(s.notificationLogRetention)
+
+	if err := s.notificationLog.RecordNotification(ctx, entry); err != nil {
+		log.Warn(ctx, "Failed to record notification in the notification log", "error", err, "kind", entry.Kind)
+	}
+}
+
+// notificationKindFor returns the kind of a message posted to a conversation: a DM when it's posted to a user
+// or DM channel, otherwise a channel message.
+func notificationKindFor(channel string) string {
+	if strings.HasPrefix(channel, "U") || strings.HasPrefix(channel, "W") || strings.HasPrefix(channel, "D") {
+		return models.NotificationKindDM
+	}
+	return models.NotificationKindMessage
+}
+
+// RecordNotification stores an entry in the notification log, under a generated ID.
+func (fs *FirestoreService) RecordNotification(ctx context.Context, entry *models.NotificationLogEntry) error {
+	doc := fs.client.Collection("notification_log").NewDoc()
+	entry.ID = doc.ID
+	if _, err := doc.Set(ctx, entry); err != nil {
+		return fmt.Errorf("failed to record notification: %w", err)
+	}
+	return nil
+}
+
+// QueryNotificationLog returns the notification log entries matching the query, newest first.
+func (fs *FirestoreService) QueryNotificationLog(ctx context.Context, query NotificationLogQuery) ([]*models.NotificationLogEntry, error) {
+	if query.RepoFullName == "" && query.SlackTeamID == "" {
+		return nil, fmt.Errorf("%w: a repository or workspace is required", ErrInvalidNotificationLogQuery)
+	}
+	if query.PRNumber != 0 && query.RepoFullName == "" {
+		return nil, fmt.Errorf("%w: a PR number requires a repository", ErrInvalidNotificationLogQuery)
+	}
+	if query.Limit < 0 || query.Limit > MaxNotificationLogLimit {
+		return nil, fmt.Errorf("%w: limit must be between 0 and %d", ErrInvalidNotificationLogQuery, MaxNotificationLogLimit)
+	}
+	if query.Limit == 0 {
+		query.Limit = DefaultNotificationLogLimit
+	}
+
+	// Repository queries are filtered by workspace while reading, so they only need the repository's indexes
+	firestoreQuery := fs.client.Collection("notification_log").Query
+	filterTeam := false
+	switch {
+	case query.RepoFullName != "":
+		firestoreQuery = firestoreQuery.Where("repo_full_name", "==", query.RepoFullName)
+		if query.PRNumber != 0 {
+			firestoreQuery = firestoreQuery.Where("pr_number", "==", query.PRNumber)
+		}
+		filterTeam = query.SlackTeamID != ""
+	default:
+		firestoreQuery = firestoreQuery.Where("slack_team_id", "==", query.SlackTeamID)
+	}
+	firestoreQuery = firestoreQuery.OrderBy("sent_at", firestore.Desc)
+	if !filterTeam {
+		firestoreQuery = firestoreQuery.Limit(query.Limit)
+	}
+
+	iter := firestoreQuery.Documents(ctx)
+	defer iter.Stop()
+
+	var entries []*models.NotificationLogEntry
+	for len(entries) < query.Limit {
+		doc, err := iter.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to query notification log for repo %s PR %d team %s: %w",
+				query.RepoFullName, query.PRNumber, query.SlackTeamID, err)
+		}
+
+		var entry models.NotificationLogEntry
+		if err := doc.DataTo(&entry); err != nil {
+			log.Warn(ctx, "Failed to unmarshal notification log entry", "error", err, "doc_id", doc.Ref.ID)
+			continue
+		}
+		if filterTeam && entry.SlackTeamID != query.SlackTeamID {
+			continue
+		}
+		entries = append(entries, &entry)
+	}
+	return entries, nil
+}
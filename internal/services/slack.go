@@ -7,15 +7,21 @@ import (
 	"fmt"
 	"net/http"
 	"regexp"
+	"slices"
 	"strings"
+	"text/template"
+	"time"
 
 	"github-slack-notifier/internal/config"
 	"github-slack-notifier/internal/log"
 	"github-slack-notifier/internal/models"
+	"github-slack-notifier/internal/prsize"
 	"github-slack-notifier/internal/ui"
 	"github-slack-notifier/internal/utils"
 
 	"github.com/slack-go/slack"
+	"golang.org/x/text/unicode/norm"
+	"golang.org/x/text/width"
 )
 
 // ErrReactionNotFound indicates a reaction doesn't exist (expected behavior).
@@ -30,11 +36,33 @@ var ErrPrivateChannelNotSupported = errors.New("private_channel_not_supported")
 // ErrCannotJoinChannel indicates the bot cannot join the specified channel.
 var ErrCannotJoinChannel = errors.New("cannot_join_channel")
 
+// localizedReviewKeywords are accepted in place of "review" in PR directives, e.g. `!レビュー: #チーム`.
+var localizedReviewKeywords = []string{
+	"レビュー",        // Japanese
+	"리뷰",          // Korean
+	"评审",          // Simplified Chinese
+	"評審",          // Traditional Chinese
+	"revisión",    // Spanish
+	"revisão",     // Portuguese
+	"revue",       // French
+	"überprüfung", // German
+}
+
+// localizedSkipKeywords are accepted in place of "skip" in PR directives.
+var localizedSkipKeywords = []string{"skip", "no", "スキップ", "건너뛰기", "跳过", "跳過", "omitir", "ignorer", "überspringen"}
+
 var (
-	directiveRegex          = regexp.MustCompile(`(?i)!reviews?:?\s*(.*)`)
+	directiveRegex          = regexp.MustCompile(`(?i)!(?:reviews?|` + strings.Join(localizedReviewKeywords, "|") + `):?\s*(.*)`)
 	skipDirectiveRegex      = regexp.MustCompile(`(?i)!review-skip`)
-	channelValidationRegex  = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+	channelValidationRegex  = regexp.MustCompile(`^[\p{L}\p{M}\p{N}_-]+$`)
 	usernameValidationRegex = regexp.MustCompile(`^[a-zA-Z0-9._-]+$`)
+	lifecycleStateRegex     = regexp.MustCompile(` · _[a-z]+_$`)
+	countdownLineRegex      = regexp.MustCompile(`\n:hourglass_flowing_sand: [^\n·]*[^\n· ]`)
+	supersededLineRegex     = regexp.MustCompile(`\n:recycle: Superseded by <[^>\n]*>`)
+	dependencyLineRegex     = regexp.MustCompile(`\n:(?:no_entry|link): (?:Blocked by|Depends on) [^\n]*\)`)
+	projectContextLineRegex = regexp.MustCompile(`\n:card_index_dividers: [^\n·]*[^\n· ]`)
+	approvalQuorumLineRegex = regexp.MustCompile(`\n:(?:white_check_mark|ballot_box_with_check): \d+/\d+ approvals`)
+	noReviewersLineRegex    = regexp.MustCompile(regexp.QuoteMeta(noReviewersLine))
 	emojiRegex              = regexp.MustCompile(
 		`[\x{1F300}-\x{1F9FF}]|[\x{2600}-\x{27BF}]|[\x{1F000}-\x{1F02F}]|` +
 			`[\x{1F900}-\x{1F9FF}]|[\x{2190}-\x{21FF}]|[\x{2300}-\x{23FF}]|` +
@@ -44,6 +72,40 @@ var (
 
 const minMatchesRequired = 2
 
+// countdownLinePrefix starts the release cut countdown line appended to PR messages.
+const countdownLinePrefix = "\n:hourglass_flowing_sand: "
+
+// projectContextLinePrefix starts the milestone and project board line appended to PR messages.
+const projectContextLinePrefix = "\n:card_index_dividers: "
+
+// noReviewersLine is the line added to messages for PRs posted without anyone asked to review them.
+const noReviewersLine = "\n:bust_in_silhouette: _No reviewers requested_"
+
+// supersededLineFormat is the line appended to messages for PRs replaced by a newer PR.
+const supersededLineFormat = "\n:recycle: Superseded by <%s|#%d>"
+
+// readyToMergeLine highlights messages whose PR is ready to merge.
+const readyToMergeLine = "\n:rocket: *Ready to merge*"
+
+// collapsedPrefix marks messages collapsed by a presentation rule.
+const collapsedPrefix = ":zzz: "
+
+// ShowPRDetailsActionID is the action ID of the "Show more" button on PR messages.
+const ShowPRDetailsActionID = "show_pr_details"
+
+// OpenPRActionID is the action ID of the "Open PR" link button on PR messages, whose clicks are recorded.
+const OpenPRActionID = "open_pr"
+
+// LinkInviteOptOutActionID is the action ID of the "Don't ask again" button on invitations to link a GitHub account.
+const LinkInviteOptOutActionID = "link_invite_opt_out"
+
+// ChannelIntroConfigureActionID is the action ID of the "Configure channel" button on the bot's intro message
+// in channels it's invited to. Its value is the channel ID.
+const ChannelIntroConfigureActionID = "channel_intro_configure"
+
+// slackButtonValueMaxLength is Slack's limit on the length of a button's value.
+const slackButtonValueMaxLength = 2000
+
 // SlackService provides methods for interacting with Slack API including message posting, reactions, and workspace management.
 type SlackService struct {
 	workspaceService *SlackWorkspaceService // Service to get workspace-specific tokens
@@ -51,6 +113,12 @@ type SlackService struct {
 	uiBuilder        *ui.HomeViewBuilder
 	config           *config.Config
 	httpClient       *http.Client
+	clientPool       *slackClientPool     // Per-workspace Slack clients, keyed by team ID
+	emojiCache       *workspaceEmojiCache // Per-workspace emoji names, for validating configured emoji
+	rateLimiter      *slackRateLimiter    // Paces API calls per workspace and method; nil when disabled
+
+	notificationLog          *FirestoreService // Records sent notifications; nil when the log is off
+	notificationLogRetention time.Duration
 }
 
 // NewSlackService creates a new SlackService with the provided dependencies.
@@ -60,38 +128,117 @@ func NewSlackService(
 	config *config.Config,
 	httpClient *http.Client,
 ) *SlackService {
-	return &SlackService{
+	s := &SlackService{
 		workspaceService: workspaceService,
 		emojiConfig:      emojiConfig,
 		uiBuilder:        ui.NewHomeViewBuilder(),
 		config:           config,
 		httpClient:       httpClient,
 	}
+	s.clientPool = newSlackClientPool(s.newSlackClient)
+	s.emojiCache = newWorkspaceEmojiCache()
+	if config != nil {
+		s.uiBuilder.UserTokenPosting = config.SlackUserTokenPosting
+		s.uiBuilder.OpenPRButton = config.OpenPRButton
+		if config.SlackRateLimitEnabled {
+			s.rateLimiter = newSlackRateLimiter(config.SlackRateLimitMaxWait)
+		}
+	}
+	return s
 }
 
 // getSlackClient returns the appropriate Slack client for the given team ID.
 func (s *SlackService) getSlackClient(ctx context.Context, teamID string) (*slack.Client, error) {
 	// Get workspace-specific token
-	token, err := s.workspaceService.GetWorkspaceToken(ctx, teamID)
+	workspace, err := s.workspaceService.GetWorkspace(ctx, teamID)
 	if err != nil {
 		if errors.Is(err, ErrWorkspaceNotFound) {
-			return nil, fmt.Errorf("%w: %s", ErrWorkspaceNotInstalled, teamID)
+			// Retrying can't help until the workspace installs the app again
+			return nil, models.Permanent(fmt.Errorf("%w: %s", ErrWorkspaceNotInstalled, teamID))
 		}
 		return nil, fmt.Errorf("failed to get workspace token: %w", err)
 	}
-	return slack.New(token, slack.OptionHTTPClient(s.httpClient)), nil
+	if workspace.IsRevoked() {
+		// Nor can it for a revoked token, which reinstalling the app replaces
+		return nil, models.Permanent(fmt.Errorf("%w: %s", ErrWorkspaceRevoked, teamID))
+	}
+	return s.clientPool.get(teamID, workspace.AccessToken), nil
+}
+
+// newSlackClient builds a Slack client for a workspace token, given the client pool key: the team ID,
+// followed by "#" and the user ID for user token clients.
+// Bot token clients mark the workspace revoked when Slack rejects the token for good.
+// Clients are built lazily so a transport swapped in after construction (e.g. by httpmock) is still used.
+func (s *SlackService) newSlackClient(key, token string) *slack.Client {
+	teamID, userID, _ := strings.Cut(key, "#") // User token calls count towards the workspace's rate limits
+	httpClient := s.loggingHTTPClient(teamID)
+	if userID == "" && s.workspaceService != nil {
+		httpClient.Transport = &slackRevocationTransport{
+			teamID:    teamID,
+			token:     token,
+			onRevoked: s.markWorkspaceRevoked,
+			base:      httpClient.Transport,
+		}
+	}
+	return slack.New(token, slack.OptionHTTPClient(httpClient))
+}
+
+// loggingHTTPClient returns an HTTP client for a workspace's Slack API calls that logs each request,
+// paced by the rate limiter if it's enabled.
+func (s *SlackService) loggingHTTPClient(teamID string) *http.Client {
+	transport := newAPILoggingTransport("slack", s.httpClient.Transport)
+	if s.rateLimiter != nil {
+		transport = &slackRateLimitTransport{teamID: teamID, limiter: s.rateLimiter, base: transport}
+	}
+	return &http.Client{
+		Transport: transport,
+		Timeout:   s.httpClient.Timeout,
+	}
+}
+
+// getUserSlackClient returns a client authenticated with the Slack user token a user granted in a workspace.
+func (s *SlackService) getUserSlackClient(ctx context.Context, teamID, userID string) (*slack.Client, error) {
+	token, err := s.workspaceService.GetUserToken(ctx, teamID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.clientPool.get(teamID+"#"+userID, token), nil
+}
+
+// clientForMessage returns the client allowed to edit or delete a message: the bot client for bot-posted
+// messages, or the posting user's client for messages posted with their user token.
+func (s *SlackService) clientForMessage(ctx context.Context, teamID string, botClient *slack.Client, postedBy string) (*slack.Client, error) {
+	if postedBy == "" {
+		return botClient, nil
+	}
+	client, err := s.getUserSlackClient(ctx, teamID, postedBy)
+	if err != nil {
+		return nil, fmt.Errorf("message was posted with a user token that is no longer available: %w", err)
+	}
+	return client, nil
+}
+
+// userTokenPostingEnabled reports whether posting with users' own Slack tokens is turned on.
+func (s *SlackService) userTokenPostingEnabled() bool {
+	return s.config != nil && s.config.SlackUserTokenPosting
 }
 
 // PostPRMessage posts a pull request notification message to Slack, attempting impersonation first if enabled.
-// Returns the message timestamp and resolved channel ID for tracking.
+// Impersonation uses the author's own Slack user token when they granted one, otherwise a username/icon override.
+// Messages are laid out as Block Kit when rich is set, with the text as their notification fallback.
+// metadata holds the PR's labels and milestone, shown in the rich layout and, when enabled, in text.
+// ccDelegates maps CC'd GitHub usernames (lowercase) who are out of office to the Slack user CC'd in their place.
+// Returns the message timestamp, resolved channel ID, and the Slack user ID whose token posted the message
+// (empty when the bot token was used) for tracking.
 func (s *SlackService) PostPRMessage(
 	ctx context.Context, teamID, channel, repoName, prTitle, prAuthor, prDescription, prURL string, prSize int,
 	authorSlackUserID string, usersToCC []string, usersCCSlackIDs []string, customEmoji string, impersonationEnabled, userTaggingEnabled bool,
-	user *models.User,
-) (string, string, error) {
+	user *models.User, channelSizeConfig *models.PRSizeConfiguration, compact bool, rich *RichPRMessage, metadata *PRMetadata,
+	ccDelegates map[string]string,
+) (string, string, string, error) {
 	client, err := s.getSlackClient(ctx, teamID)
 	if err != nil {
-		return "", "", err
+		return "", "", "", err
 	}
 
 	// Resolve channel name to channel ID if needed
@@ -103,40 +250,223 @@ func (s *SlackService) PostPRMessage(
 			"team_id", teamID,
 			"operation", "post_pr_message",
 		)
-		return "", "", fmt.Errorf("failed to resolve channel %s for team %s: %w", channel, teamID, err)
+		return "", "", "", fmt.Errorf("failed to resolve channel %s for team %s: %w", channel, teamID, err)
 	}
 
 	// Build message text once - use bot mode format since it includes everything we need
+	sizeConfig := s.prSizeConfig(ctx, teamID, user, channelSizeConfig)
 	messageText := s.buildMessageText(
-		customEmoji, prSize, prURL, prTitle, prAuthor, usersToCC, usersCCSlackIDs,
-		authorSlackUserID, userTaggingEnabled, user,
+		s.messageTemplate(ctx, teamID), customEmoji, prSize, repoName, prURL, prTitle, prAuthor, usersToCC, usersCCSlackIDs,
+		authorSlackUserID, userTaggingEnabled, sizeConfig, compact, metadata, ccDelegates,
 	)
+	if metadata != nil && metadata.NoReviewers && !compact {
+		messageText = ApplyNoReviewersWarningToText(messageText, true)
+	}
+	blocks := s.buildMessageBlocks(rich, messageText, customEmoji, prSize, repoName, prURL, prTitle, prAuthor,
+		usersToCC, usersCCSlackIDs, authorSlackUserID, userTaggingEnabled, sizeConfig, compact, metadata, ccDelegates)
+	attachments := s.buildMessageAttachments(prTitle, prDescription, prURL, compact, blocks != nil)
+
+	record := func(timestamp string, err error) {
+		s.recordNotification(ctx, &models.NotificationLogEntry{
+			Kind:           models.NotificationKindMessage,
+			SlackTeamID:    teamID,
+			SlackChannel:   channelID,
+			SlackMessageTS: timestamp,
+			RepoFullName:   repoName,
+		}, err)
+	}
 
 	// Try impersonation first if enabled
 	if authorSlackUserID != "" && impersonationEnabled {
+		if user != nil && user.UserTokenPosting && s.userTokenPostingEnabled() {
+			if timestamp, posted := s.postMessageWithUserToken(ctx, teamID, channelID, messageText, authorSlackUserID, blocks); posted {
+				record(timestamp, nil)
+				return timestamp, channelID, authorSlackUserID, nil
+			}
+		}
+
 		timestamp, posted, err := s.postMessageAsUser(
-			ctx, client, teamID, channelID, messageText, authorSlackUserID,
+			ctx, client, teamID, channelID, messageText, authorSlackUserID, blocks, attachments,
 		)
 		if err != nil {
-			return "", "", err
+			record("", err)
+			return "", "", "", err
 		}
 		if posted {
-			return timestamp, channelID, nil
+			record(timestamp, nil)
+			return timestamp, channelID, "", nil
 		}
 	}
 
 	// Fallback: Post as bot
 	timestamp, err := s.postMessageAsBot(
 		ctx, client, teamID, channelID, repoName, prTitle, prAuthor, prURL,
-		messageText,
+		messageText, blocks, attachments,
+	)
+	record(timestamp, err)
+	return timestamp, channelID, "", err
+}
+
+// truncation returns the configured message truncation settings; the zero value disables truncation.
+func (s *SlackService) truncation() config.TruncationConfig {
+	if s.config == nil {
+		return config.TruncationConfig{}
+	}
+	return s.config.Truncation
+}
+
+// ApprovalQuorumEnabled reports whether PR messages show the PR's approvals against the approvals it needs.
+func (s *SlackService) ApprovalQuorumEnabled() bool {
+	return s.config != nil && s.config.ApprovalQuorum
+}
+
+// NoReviewers returns the configuration of the warning on messages for PRs posted without reviewers.
+func (s *SlackService) NoReviewers() config.NoReviewersConfig {
+	if s.config == nil {
+		return config.NoReviewersConfig{}
+	}
+	return s.config.NoReviewers
+}
+
+// OpenPRButtonEnabled reports whether PR messages carry an "Open PR" button whose clicks are recorded.
+func (s *SlackService) OpenPRButtonEnabled() bool {
+	return s.config != nil && s.config.OpenPRButton
+}
+
+// buildMessageAttachments returns the button attachment for a PR message, or nil if it has no buttons.
+// Messages get an "Open PR" button when enabled, and a "Show more" button when the title was truncated
+// or there is a description to expand. Compact messages get neither, and rich messages have "Open PR" in their blocks.
+func (s *SlackService) buildMessageAttachments(prTitle, prDescription, prURL string, compact, rich bool) []slack.Attachment {
+	if compact {
+		return nil
+	}
+
+	var elements []slack.BlockElement
+	if s.OpenPRButtonEnabled() && prURL != "" && !rich {
+		elements = append(elements, newOpenPRButton(prURL))
+	}
+	if button := s.buildShowMoreButton(prTitle, prDescription); button != nil {
+		elements = append(elements, button)
+	}
+	if len(elements) == 0 {
+		return nil
+	}
+
+	return []slack.Attachment{{
+		Blocks: slack.Blocks{BlockSet: []slack.Block{slack.NewActionBlock("", elements...)}},
+	}}
+}
+
+// buildShowMoreButton returns the "Show more" button for a PR message whose title was truncated or
+// that has a description, or nil if the button is disabled or there is nothing to expand.
+// The button value carries the full title and the truncated description, as interactions can't fetch the PR.
+func (s *SlackService) buildShowMoreButton(prTitle, prDescription string) *slack.ButtonBlockElement {
+	truncation := s.truncation()
+	if !truncation.ShowMoreButton {
+		return nil
+	}
+
+	_, titleTruncated := utils.TruncateText(prTitle, truncation.MaxTitleLength, truncation.Ellipsis)
+	description := strings.TrimSpace(prDescription)
+	if !titleTruncated && description == "" {
+		return nil
+	}
+
+	details := prTitle
+	if description != "" {
+		description, _ = utils.TruncateText(description, truncation.MaxDescriptionLength, truncation.Ellipsis)
+		details += "\n\n" + description
+	}
+	details, _ = utils.TruncateText(details, slackButtonValueMaxLength, truncation.Ellipsis)
+
+	return slack.NewButtonBlockElement(ShowPRDetailsActionID, details,
+		slack.NewTextBlockObject(slack.PlainTextType, "Show more", false, false))
+}
+
+// newOpenPRButton returns the "Open PR" link button. Slack opens the URL and also sends the click
+// as a block action, which is how clicks are recorded.
+func newOpenPRButton(prURL string) *slack.ButtonBlockElement {
+	button := slack.NewButtonBlockElement(OpenPRActionID, "", slack.NewTextBlockObject(slack.PlainTextType, "Open PR", false, false))
+	button.URL = prURL
+	return button
+}
+
+// PostPRDetails expands a PR message's "Show more" details into its thread, then replaces the button
+// with a note so the details aren't posted twice. The "Open PR" button is kept if prURL is set.
+func (s *SlackService) PostPRDetails(ctx context.Context, teamID, channel, messageTS, details, prURL string) error {
+	client, err := s.getSlackClient(ctx, teamID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.PostThreadReply(ctx, teamID, channel, messageTS, utils.EscapeSlackText(details)); err != nil {
+		return err
+	}
+
+	blocks := []slack.Block{slack.NewContextBlock("",
+		slack.NewTextBlockObject(slack.MarkdownType, "Details posted in thread", false, false),
+	)}
+	if prURL != "" {
+		blocks = append([]slack.Block{slack.NewActionBlock("", newOpenPRButton(prURL))}, blocks...)
+	}
+	note := slack.Attachment{Blocks: slack.Blocks{BlockSet: blocks}}
+	if _, _, _, err := client.UpdateMessageContext(ctx, channel, messageTS, slack.MsgOptionAttachments(note)); err != nil {
+		// The details are already in the thread, so a lingering button is only cosmetic
+		log.Warn(ctx, "Failed to remove Show more button after expanding PR details",
+			"error", err,
+			"channel", channel,
+			"message_ts", messageTS,
+		)
+	}
+
+	return nil
+}
+
+// postMessageWithUserToken attempts to post as the author using the Slack user token they granted,
+// so the message is theirs to edit and delete natively.
+// Returns (timestamp, posted); any failure is logged and reported as not posted so the caller falls back.
+func (s *SlackService) postMessageWithUserToken(
+	ctx context.Context, teamID, channel, messageText, authorSlackUserID string, blocks []slack.Block,
+) (string, bool) {
+	client, err := s.getUserSlackClient(ctx, teamID, authorSlackUserID)
+	if err != nil {
+		log.Warn(ctx, "Slack user token unavailable, falling back to impersonation override",
+			"error", err,
+			"author_slack_user_id", authorSlackUserID,
+		)
+		return "", false
+	}
+
+	_, timestamp, err := client.PostMessageContext(ctx, channel,
+		slack.MsgOptionText(messageText, false),
+		slack.MsgOptionBlocks(blocks...),
+		slack.MsgOptionDisableLinkUnfurl(),
+	)
+	if err != nil {
+		// Typically not_in_channel (user tokens can only post where the user is a member) or a revoked token
+		log.Warn(ctx, "Failed to post PR message with Slack user token, falling back to impersonation override",
+			"error", err,
+			"channel", channel,
+			"team_id", teamID,
+			"author_slack_user_id", authorSlackUserID,
+		)
+		return "", false
+	}
+
+	log.Info(ctx, "Posted PR message with author's Slack user token",
+		"channel", channel,
+		"team_id", teamID,
+		"author_slack_user_id", authorSlackUserID,
 	)
-	return timestamp, channelID, err
+
+	return timestamp, true
 }
 
-// formatEmoji formats the emoji for Slack message display.
-func (s *SlackService) formatEmoji(customEmoji string, prSize int, user *models.User) string {
+// formatEmoji formats the emoji for Slack message display: the custom emoji if set, otherwise the PR size emoji
+// from the resolved PR size configuration.
+func (s *SlackService) formatEmoji(customEmoji string, prSize int, sizeConfig *models.PRSizeConfiguration) string {
 	if customEmoji == "" {
-		return utils.GetPRSizeEmojiWithConfig(prSize, user)
+		return prsize.Emoji(prSize, sizeConfig)
 	}
 	// customEmoji should only be set if it's already a valid emoji
 	// (either :emoji_name: format or Unicode emoji character)
@@ -148,6 +478,7 @@ func (s *SlackService) formatEmoji(customEmoji string, prSize int, user *models.
 // Returns (timestamp, posted, error) where posted indicates if the message was successfully posted.
 func (s *SlackService) postMessageAsUser(
 	ctx context.Context, client *slack.Client, teamID, channel, messageText, authorSlackUserID string,
+	blocks []slack.Block, attachments []slack.Attachment,
 ) (string, bool, error) {
 	user, err := s.GetUserInfo(ctx, teamID, authorSlackUserID)
 	if err != nil {
@@ -165,12 +496,14 @@ func (s *SlackService) postMessageAsUser(
 
 	msgOptions := []slack.MsgOption{
 		slack.MsgOptionText(messageText, false),
+		slack.MsgOptionBlocks(blocks...),
 		slack.MsgOptionDisableLinkUnfurl(),
 		slack.MsgOptionUsername(name),
 		slack.MsgOptionIconURL(user.Profile.Image72),
+		slack.MsgOptionAttachments(attachments...),
 	}
 
-	_, timestamp, err := client.PostMessage(channel, msgOptions...)
+	_, timestamp, err := client.PostMessageContext(ctx, channel, msgOptions...)
 	if err != nil {
 		log.Error(ctx, "Failed to post PR message as user to Slack",
 			"error", err,
@@ -195,10 +528,13 @@ func (s *SlackService) postMessageAsUser(
 // postMessageAsBot posts the PR message as the bot.
 func (s *SlackService) postMessageAsBot(
 	ctx context.Context, client *slack.Client, teamID, channel, repoName, prTitle, prAuthor, prURL, messageText string,
+	blocks []slack.Block, attachments []slack.Attachment,
 ) (string, error) {
-	_, timestamp, err := client.PostMessage(channel,
+	_, timestamp, err := client.PostMessageContext(ctx, channel,
 		slack.MsgOptionText(messageText, false),
+		slack.MsgOptionBlocks(blocks...),
 		slack.MsgOptionDisableLinkUnfurl(),
+		slack.MsgOptionAttachments(attachments...),
 	)
 	if err != nil {
 		log.Error(ctx, "Failed to post PR message to Slack",
@@ -218,36 +554,182 @@ func (s *SlackService) postMessageAsBot(
 }
 
 // buildMessageText constructs the message text for both impersonation and bot modes.
+// Full messages are laid out with the workspace's message template, or the default template if nil.
+// Labels and the milestone are only shown when label chips are enabled.
 func (s *SlackService) buildMessageText(
-	customEmoji string, prSize int, prURL, prTitle, prAuthor string, usersToCC []string, usersCCSlackIDs []string, authorSlackUserID string,
-	userTaggingEnabled bool, user *models.User,
+	tmpl *template.Template, customEmoji string, prSize int, repoName, prURL, prTitle, prAuthor string,
+	usersToCC []string, usersCCSlackIDs []string, authorSlackUserID string, userTaggingEnabled bool,
+	sizeConfig *models.PRSizeConfiguration, compact bool, metadata *PRMetadata, ccDelegates map[string]string,
 ) string {
-	emoji := s.formatEmoji(customEmoji, prSize, user)
-	text := fmt.Sprintf("%s <%s|%s>", emoji, prURL, prTitle)
+	if !s.MessageLabelsEnabled() {
+		metadata = nil
+	}
+	data := s.buildMessageData(customEmoji, prSize, repoName, prURL, prTitle, prAuthor, usersToCC, usersCCSlackIDs,
+		authorSlackUserID, userTaggingEnabled, sizeConfig, metadata, ccDelegates)
+
+	if compact {
+		// Compact mode repos are high-churn, so drop the size emoji and never ping the author
+		text := fmt.Sprintf("%s · %s", data.Link, utils.EscapeSlackText(prAuthor))
+		if data.CC != "" {
+			text += fmt.Sprintf(" (cc: %s)", data.CC)
+		}
+		return text
+	}
+
+	// Templates are checked against a sample PR when they're saved, so this falls back only if one slipped through
+	text, err := utils.RenderMessageTemplate(tmpl, data)
+	if err != nil || text == "" {
+		text, _ = utils.RenderMessageTemplate(nil, data)
+	}
+	return text
+}
+
+// buildMessageData returns the escaped values a PR message is laid out with, in either text or rich layout.
+func (s *SlackService) buildMessageData(
+	customEmoji string, prSize int, repoName, prURL, prTitle, prAuthor string,
+	usersToCC []string, usersCCSlackIDs []string, authorSlackUserID string, userTaggingEnabled bool,
+	sizeConfig *models.PRSizeConfiguration, metadata *PRMetadata, ccDelegates map[string]string,
+) utils.MessageTemplateData {
+	truncation := s.truncation()
+	prTitle, _ = utils.TruncateText(prTitle, truncation.MaxTitleLength, truncation.Ellipsis)
+	prTitle = utils.EscapeSlackText(prTitle)
+
+	// Add user CC if specified - use Slack user ID if available, otherwise fallback to plain text.
+	// Users who are out of office aren't tagged, and their delegate is CC'd instead.
+	var ccMentions []string
+	for i, username := range usersToCC {
+		delegate, outOfOffice := ccDelegates[strings.ToLower(username)]
+		switch {
+		case outOfOffice && delegate != "":
+			ccMentions = append(ccMentions, fmt.Sprintf("<@%s> (for @%s, out of office)", delegate, utils.EscapeSlackText(username)))
+		case outOfOffice:
+			ccMentions = append(ccMentions, fmt.Sprintf("@%s (out of office)", utils.EscapeSlackText(username)))
+		case i < len(usersCCSlackIDs) && usersCCSlackIDs[i] != "":
+			ccMentions = append(ccMentions, fmt.Sprintf("<@%s>", usersCCSlackIDs[i]))
+		default:
+			ccMentions = append(ccMentions, fmt.Sprintf("@%s", utils.EscapeSlackText(username)))
+		}
+	}
 
-	// If we haven't been able to resolve a GH user to a Slack user (which really
-	// shouldn't happen), then always use the PR author name, regardless of tagging.
+	data := utils.MessageTemplateData{
+		Emoji: s.formatEmoji(customEmoji, prSize, sizeConfig),
+		Repo:  utils.EscapeSlackText(repoName),
+		Title: prTitle,
+		URL:   prURL,
+		Link:  fmt.Sprintf("<%s|%s>", prURL, prTitle),
+		CC:    strings.Join(ccMentions, ", "),
+	}
 	if authorSlackUserID == "" {
-		text += fmt.Sprintf(" by %s", prAuthor)
+		// If we haven't been able to resolve a GH user to a Slack user (which really
+		// shouldn't happen), then always use the PR author name, regardless of tagging.
+		data.Author = utils.EscapeSlackText(prAuthor)
 	} else if userTaggingEnabled {
 		// Add user tag if tagging is enabled
-		text += fmt.Sprintf(" by <@%s>", authorSlackUserID)
+		data.Author = fmt.Sprintf("<@%s>", authorSlackUserID)
 	}
+	if metadata != nil {
+		data.Labels = strings.Join(s.labelChips(metadata), "  ")
+		data.Milestone = utils.EscapeSlackText(utils.SanitizeMessageLine(metadata.Milestone))
+	}
+	return data
+}
 
-	// Add user CC if specified - use Slack user ID if available, otherwise fallback to plain text
-	if len(usersToCC) > 0 {
-		var ccMentions []string
-		for i, username := range usersToCC {
-			if i < len(usersCCSlackIDs) && usersCCSlackIDs[i] != "" {
-				ccMentions = append(ccMentions, fmt.Sprintf("<@%s>", usersCCSlackIDs[i]))
-			} else {
-				ccMentions = append(ccMentions, fmt.Sprintf("@%s", username))
-			}
+// PRMetadata holds a PR's labels, milestone and approval count, for showing on its messages.
+type PRMetadata struct {
+	Labels         []PRLabel
+	Milestone      string // Milestone title; empty without one
+	ApprovalQuorum string // Approval count line from utils.FormatApprovalQuorum; empty to leave it out
+	NoReviewers    bool   // Show the "No reviewers requested" line
+}
+
+// PRLabel is a GitHub label on a PR.
+type PRLabel struct {
+	Name  string
+	Color string // Hex color, e.g. "d73a4a"
+}
+
+// MessageLabelsEnabled reports whether text PR messages show label chips and the milestone.
+func (s *SlackService) MessageLabelsEnabled() bool {
+	return s.config != nil && s.config.Labels.Enabled
+}
+
+// labelChips returns the chips shown for a PR's labels, with the configured emoji or a square in each label's color.
+func (s *SlackService) labelChips(metadata *PRMetadata) []string {
+	var labelEmoji map[string]string
+	if s.config != nil {
+		labelEmoji = s.config.Labels.Emoji
+	}
+	chips := make([]string, 0, len(metadata.Labels))
+	for _, label := range metadata.Labels {
+		chips = append(chips, utils.FormatLabelChip(label.Name, label.Color, labelEmoji))
+	}
+	return chips
+}
+
+// buildMessageBlocks returns the Block Kit layout of a rich PR message, or nil for text messages.
+// Compact messages, including collapsed ones, are one line of text in either layout.
+func (s *SlackService) buildMessageBlocks(
+	rich *RichPRMessage, messageText, customEmoji string, prSize int, repoName, prURL, prTitle, prAuthor string,
+	usersToCC []string, usersCCSlackIDs []string, authorSlackUserID string, userTaggingEnabled bool,
+	sizeConfig *models.PRSizeConfiguration, compact bool, metadata *PRMetadata, ccDelegates map[string]string,
+) []slack.Block {
+	if rich == nil || compact {
+		return nil
+	}
+	data := s.buildMessageData(customEmoji, prSize, repoName, prURL, prTitle, prAuthor, usersToCC, usersCCSlackIDs,
+		authorSlackUserID, userTaggingEnabled, sizeConfig, metadata, ccDelegates)
+	var chips []string
+	if metadata != nil {
+		chips = s.labelChips(metadata)
+	}
+	return buildRichMessageBlocks(data, prAuthor, rich, chips, messageText)
+}
+
+// prSizeConfig resolves the PR size emoji configuration for a message: the author's, then the channel's,
+// then the workspace's. Returns nil for the default emoji. Workspaces without an installation record,
+// e.g. when using a single bot token, have no workspace configuration.
+func (s *SlackService) prSizeConfig(
+	ctx context.Context, teamID string, user *models.User, channelSizeConfig *models.PRSizeConfiguration,
+) *models.PRSizeConfiguration {
+	var userSizeConfig, workspaceSizeConfig *models.PRSizeConfiguration
+	if user != nil {
+		userSizeConfig = user.PRSizeConfig
+	}
+	if s.workspaceService != nil && !prsize.IsSet(userSizeConfig) && !prsize.IsSet(channelSizeConfig) {
+		workspace, err := s.workspaceService.GetWorkspace(ctx, teamID)
+		switch {
+		case err == nil:
+			workspaceSizeConfig = workspace.PRSizeConfig
+		case !errors.Is(err, ErrWorkspaceNotFound):
+			log.Warn(ctx, "Failed to get workspace PR size emoji, using defaults", "error", err, "team_id", teamID)
 		}
-		text += fmt.Sprintf(" (cc: %s)", strings.Join(ccMentions, ", "))
 	}
+	sizeConfig, _ := prsize.Resolve(userSizeConfig, channelSizeConfig, workspaceSizeConfig)
+	return sizeConfig
+}
 
-	return text
+// messageTemplate returns a workspace's parsed PR message template, or nil to use the default.
+// Workspaces without an installation record, e.g. when using a single bot token, use the default.
+func (s *SlackService) messageTemplate(ctx context.Context, teamID string) *template.Template {
+	if s.workspaceService == nil {
+		return nil
+	}
+	workspace, err := s.workspaceService.GetWorkspace(ctx, teamID)
+	if err != nil {
+		if !errors.Is(err, ErrWorkspaceNotFound) {
+			log.Warn(ctx, "Failed to get workspace message template, using default", "error", err, "team_id", teamID)
+		}
+		return nil
+	}
+	if workspace.MessageTemplate == "" {
+		return nil
+	}
+	tmpl, err := utils.ParseMessageTemplate(workspace.MessageTemplate)
+	if err != nil {
+		log.Warn(ctx, "Invalid workspace message template, using default", "error", err, "team_id", teamID)
+		return nil
+	}
+	return tmpl
 }
 
 // SendEphemeralMessage sends an ephemeral message visible only to a specific user.
@@ -257,10 +739,16 @@ func (s *SlackService) SendEphemeralMessage(ctx context.Context, teamID, channel
 		return err
 	}
 
-	_, err = client.PostEphemeral(channel, userID,
+	_, err = client.PostEphemeralContext(ctx, channel, userID,
 		slack.MsgOptionText(text, false),
 		slack.MsgOptionDisableLinkUnfurl(),
 	)
+	s.recordNotification(ctx, &models.NotificationLogEntry{
+		Kind:         models.NotificationKindEphemeral,
+		SlackTeamID:  teamID,
+		SlackChannel: channel,
+		SlackUserID:  userID,
+	}, err)
 	if err != nil {
 		log.Error(ctx, "Failed to send ephemeral message to Slack",
 			"error", err,
@@ -275,15 +763,162 @@ func (s *SlackService) SendEphemeralMessage(ctx context.Context, teamID, channel
 	return nil
 }
 
+// PostMessage posts a plain bot message to a channel and returns its timestamp.
+func (s *SlackService) PostMessage(ctx context.Context, teamID, channel, text string) (string, error) {
+	client, err := s.getSlackClient(ctx, teamID)
+	if err != nil {
+		return "", err
+	}
+
+	_, timestamp, err := client.PostMessageContext(ctx, channel,
+		slack.MsgOptionText(text, false),
+		slack.MsgOptionDisableLinkUnfurl(),
+	)
+	s.recordNotification(ctx, &models.NotificationLogEntry{
+		Kind:           notificationKindFor(channel),
+		SlackTeamID:    teamID,
+		SlackChannel:   channel,
+		SlackMessageTS: timestamp,
+	}, err)
+	if err != nil {
+		log.Error(ctx, "Failed to post message to Slack",
+			"error", err,
+			"channel", channel,
+			"team_id", teamID,
+			"operation", "post_message",
+		)
+		return "", fmt.Errorf("failed to post message to channel %s for team %s: %w", channel, teamID, err)
+	}
+
+	return timestamp, nil
+}
+
+// PostDailyDigest DMs a user their daily PR digest as Block Kit, with a plain text fallback for notifications.
+func (s *SlackService) PostDailyDigest(ctx context.Context, teamID, userID string, digest *models.DailyDigest, now time.Time) error {
+	client, err := s.getSlackClient(ctx, teamID)
+	if err != nil {
+		return err
+	}
+
+	fallback := fmt.Sprintf("Your daily PR digest: %d waiting on your review, %d open PRs",
+		len(digest.PendingReviews), len(digest.OpenPRs))
+	_, _, err = client.PostMessageContext(ctx, userID,
+		slack.MsgOptionText(fallback, false),
+		slack.MsgOptionBlocks(s.uiBuilder.BuildDailyDigestBlocks(digest, now)...),
+		slack.MsgOptionDisableLinkUnfurl(),
+	)
+	s.recordNotification(ctx, &models.NotificationLogEntry{
+		Kind:         models.NotificationKindDM,
+		SlackTeamID:  teamID,
+		SlackChannel: userID,
+	}, err)
+	if err != nil {
+		log.Error(ctx, "Failed to post daily digest to Slack",
+			"error", err,
+			"user_id", userID,
+			"team_id", teamID,
+			"operation", "post_daily_digest",
+		)
+		return fmt.Errorf("failed to post daily digest to user %s for team %s: %w", userID, teamID, err)
+	}
+
+	return nil
+}
+
+// GetPermalink returns a permanent link to a Slack message.
+func (s *SlackService) GetPermalink(ctx context.Context, teamID, channel, timestamp string) (string, error) {
+	client, err := s.getSlackClient(ctx, teamID)
+	if err != nil {
+		return "", err
+	}
+
+	permalink, err := client.GetPermalinkContext(ctx, &slack.PermalinkParameters{Channel: channel, Ts: timestamp})
+	if err != nil {
+		return "", fmt.Errorf("failed to get permalink for message %s in channel %s for team %s: %w", timestamp, channel, teamID, err)
+	}
+
+	return permalink, nil
+}
+
+// PostThreadReply posts a plain bot message as a reply in a message's thread, returning the reply's timestamp.
+func (s *SlackService) PostThreadReply(ctx context.Context, teamID, channel, threadTS, text string) (string, error) {
+	client, err := s.getSlackClient(ctx, teamID)
+	if err != nil {
+		return "", err
+	}
+
+	_, replyTS, err := client.PostMessageContext(ctx, channel,
+		slack.MsgOptionText(text, false),
+		slack.MsgOptionTS(threadTS),
+		slack.MsgOptionDisableLinkUnfurl(),
+	)
+	s.recordNotification(ctx, &models.NotificationLogEntry{
+		Kind:           models.NotificationKindMessage,
+		SlackTeamID:    teamID,
+		SlackChannel:   channel,
+		SlackMessageTS: replyTS,
+	}, err)
+	if err != nil {
+		log.Error(ctx, "Failed to post thread reply to Slack",
+			"error", err,
+			"channel", channel,
+			"thread_ts", threadTS,
+			"team_id", teamID,
+			"operation", "post_thread_reply",
+		)
+		return "", fmt.Errorf("failed to post reply to thread %s in channel %s for team %s: %w", threadTS, channel, teamID, err)
+	}
+
+	return replyTS, nil
+}
+
+// UpdateThreadReply replaces the text of a bot reply posted with PostThreadReply.
+func (s *SlackService) UpdateThreadReply(ctx context.Context, teamID, channel, replyTS, text string) error {
+	client, err := s.getSlackClient(ctx, teamID)
+	if err != nil {
+		return err
+	}
+
+	_, _, _, err = client.UpdateMessageContext(ctx, channel, replyTS,
+		slack.MsgOptionText(text, false),
+		slack.MsgOptionDisableLinkUnfurl(),
+	)
+	if err != nil {
+		log.Error(ctx, "Failed to update thread reply in Slack",
+			"error", err,
+			"channel", channel,
+			"reply_ts", replyTS,
+			"team_id", teamID,
+			"operation", "update_thread_reply",
+		)
+		return fmt.Errorf("failed to update reply %s in channel %s for team %s: %w", replyTS, channel, teamID, err)
+	}
+
+	return nil
+}
+
 // AddReaction adds an emoji reaction to a Slack message, handling "already_reacted" as success.
 func (s *SlackService) AddReaction(ctx context.Context, teamID, channel, timestamp, emoji string) error {
+	err := s.addReaction(ctx, teamID, channel, timestamp, emoji)
+	s.recordNotification(ctx, &models.NotificationLogEntry{
+		Kind:           models.NotificationKindReaction,
+		SlackTeamID:    teamID,
+		SlackChannel:   channel,
+		SlackMessageTS: timestamp,
+		Emoji:          emoji,
+	}, err)
+	return err
+}
+
+// addReaction adds an emoji reaction to a Slack message for AddReaction.
+func (s *SlackService) addReaction(ctx context.Context, teamID, channel, timestamp, emoji string) error {
 	client, err := s.getSlackClient(ctx, teamID)
 	if err != nil {
 		return err
 	}
 
 	msgRef := slack.NewRefToMessage(channel, timestamp)
-	err = client.AddReaction(emoji, msgRef)
+	err = client.AddReactionContext(ctx, emoji, msgRef)
 	if err != nil {
 		// Handle "already_reacted" as success - this is the most common case for retries
 		errMsg := err.Error()
@@ -348,7 +983,7 @@ func (s *SlackService) ValidateChannel(ctx context.Context, teamID, channel stri
 	}
 
 	// Check if channel exists and get info including membership status
-	channelInfo, err := client.GetConversationInfo(&slack.GetConversationInfoInput{
+	channelInfo, err := client.GetConversationInfoContext(ctx, &slack.GetConversationInfoInput{
 		ChannelID: channelID,
 	})
 	if err != nil {
@@ -380,7 +1015,7 @@ func (s *SlackService) ValidateChannel(ctx context.Context, teamID, channel stri
 		)
 
 		// Join the public channel
-		_, _, _, err := client.JoinConversation(channelID)
+		_, _, _, err := client.JoinConversationContext(ctx, channelID)
 		if err != nil {
 			log.Error(ctx, "Failed to join channel",
 				"error", err,
@@ -453,7 +1088,7 @@ func (s *SlackService) RemoveReaction(ctx context.Context, teamID, channel, time
 		return err
 	}
 
-	err = client.RemoveReaction(emoji, slack.ItemRef{
+	err = client.RemoveReactionContext(ctx, emoji, slack.ItemRef{
 		Channel:   channel,
 		Timestamp: timestamp,
 	})
@@ -543,10 +1178,11 @@ func (s *SlackService) SyncReviewReactions(
 		return nil
 	}
 
+	emojiConfig := s.EmojiConfig(ctx, teamID)
 	reviewEmojis := []string{
-		s.emojiConfig.Approved,
-		s.emojiConfig.ChangesRequested,
-		s.emojiConfig.Commented,
+		emojiConfig.Approved,
+		emojiConfig.ChangesRequested,
+		emojiConfig.Commented,
 	}
 
 	// Remove all existing review reactions
@@ -563,7 +1199,7 @@ func (s *SlackService) SyncReviewReactions(
 	}
 
 	// Add current review state reaction if applicable
-	currentEmoji := utils.GetEmojiForReviewState(models.ReviewState(currentReviewState), s.emojiConfig)
+	currentEmoji := utils.GetEmojiForReviewState(models.ReviewState(currentReviewState), emojiConfig)
 	if currentEmoji != "" {
 		err := s.AddReactionToMultipleMessages(ctx, teamID, messages, currentEmoji)
 		if err != nil {
@@ -585,20 +1221,60 @@ func (s *SlackService) SyncReviewReactions(
 	return nil
 }
 
-// RemovePRStateReactions removes PR state reactions (closed/merged emojis).
-func (s *SlackService) RemovePRStateReactions(
-	ctx context.Context, teamID string, messages []MessageRef,
-) error {
+// SyncCIReactions replaces CI state reactions on messages with the reaction for the current CI state.
+// Pending CI clears the reactions, so a rerun doesn't keep showing the previous result.
+func (s *SlackService) SyncCIReactions(ctx context.Context, teamID string, messages []MessageRef, state models.CIState) error {
 	if len(messages) == 0 {
 		return nil
 	}
 
-	prStateEmojis := []string{
-		s.emojiConfig.Closed,
-		s.emojiConfig.Merged,
+	currentEmoji := utils.GetEmojiForCIState(state, s.emojiConfig)
+	for _, emoji := range []string{s.emojiConfig.CIPassed, s.emojiConfig.CIFailed} {
+		if emoji == "" || emoji == currentEmoji {
+			continue
+		}
+		if err := s.RemoveReactionFromMultipleMessages(ctx, teamID, messages, emoji); err != nil {
+			log.Warn(ctx, "Failed to remove some CI reactions during sync",
+				"error", err,
+				"emoji", emoji,
+			)
+		}
 	}
 
-	for _, emoji := range prStateEmojis {
+	if currentEmoji != "" {
+		if err := s.AddReactionToMultipleMessages(ctx, teamID, messages, currentEmoji); err != nil {
+			log.Error(ctx, "Failed to add CI reaction during sync",
+				"error", err,
+				"emoji", currentEmoji,
+				"ci_state", state,
+			)
+			return err
+		}
+	}
+
+	log.Info(ctx, "CI reactions synchronized",
+		"ci_state", state,
+		"emoji", currentEmoji,
+		"message_count", len(messages),
+	)
+	return nil
+}
+
+// RemovePRStateReactions removes PR state reactions (closed/merged emojis).
+func (s *SlackService) RemovePRStateReactions(
+	ctx context.Context, teamID string, messages []MessageRef,
+) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	emojiConfig := s.EmojiConfig(ctx, teamID)
+	prStateEmojis := []string{
+		emojiConfig.Closed,
+		emojiConfig.Merged,
+	}
+
+	for _, emoji := range prStateEmojis {
 		if emoji != "" {
 			err := s.RemoveReactionFromMultipleMessages(ctx, teamID, messages, emoji)
 			if err != nil {
@@ -613,8 +1289,265 @@ func (s *SlackService) RemovePRStateReactions(
 	return nil
 }
 
-// DeleteMessage deletes a Slack message.
-func (s *SlackService) DeleteMessage(ctx context.Context, teamID, channel, timestamp string) error {
+// ApplyLifecycleStateToText returns message text with the lifecycle state suffix replaced by state.
+// An empty state removes any existing suffix, e.g. when a PR is reopened.
+func ApplyLifecycleStateToText(text, state string) string {
+	text = lifecycleStateRegex.ReplaceAllString(text, "")
+	if state == "" {
+		return text
+	}
+	return text + " · _" + state + "_"
+}
+
+// ApplyCountdownToText returns message text with the release cut countdown line replaced by line.
+// The countdown is kept ahead of any lifecycle state suffix. An empty line removes the countdown.
+func ApplyCountdownToText(text, line string) string {
+	text = countdownLineRegex.ReplaceAllString(text, "")
+	if line == "" {
+		return text
+	}
+
+	suffix := lifecycleStateRegex.FindString(text)
+	base := strings.TrimSuffix(text, suffix)
+	return base + countdownLinePrefix + line + suffix
+}
+
+// ApplySupersededToText returns message text annotated with a link to the PR that superseded it.
+// The annotation is kept ahead of any lifecycle state suffix and replaces any existing annotation.
+func ApplySupersededToText(text, newPRURL string, newPRNumber int) string {
+	text = supersededLineRegex.ReplaceAllString(text, "")
+
+	suffix := lifecycleStateRegex.FindString(text)
+	base := strings.TrimSuffix(text, suffix)
+	return base + fmt.Sprintf(supersededLineFormat, newPRURL, newPRNumber) + suffix
+}
+
+// ApplyDependencyLineToText returns message text with the dependency status line replaced by line.
+// The line is kept ahead of any lifecycle state suffix. An empty line removes the dependency status.
+func ApplyDependencyLineToText(text, line string) string {
+	text = dependencyLineRegex.ReplaceAllString(text, "")
+	if line == "" {
+		return text
+	}
+
+	suffix := lifecycleStateRegex.FindString(text)
+	base := strings.TrimSuffix(text, suffix)
+	return base + "\n" + line + suffix
+}
+
+// ApplyProjectContextToText returns message text with the milestone and project board line replaced by line.
+// The line is kept ahead of any lifecycle state suffix. An empty line removes the annotation.
+func ApplyProjectContextToText(text, line string) string {
+	text = projectContextLineRegex.ReplaceAllString(text, "")
+	if line == "" {
+		return text
+	}
+
+	suffix := lifecycleStateRegex.FindString(text)
+	base := strings.TrimSuffix(text, suffix)
+	return base + projectContextLinePrefix + line + suffix
+}
+
+// ApplyApprovalQuorumToText returns message text with the approval count line replaced by line, as built by
+// utils.FormatApprovalQuorum. The line is kept ahead of any lifecycle state suffix. An empty line removes it.
+func ApplyApprovalQuorumToText(text, line string) string {
+	text = approvalQuorumLineRegex.ReplaceAllString(text, "")
+	if line == "" {
+		return text
+	}
+
+	suffix := lifecycleStateRegex.FindString(text)
+	base := strings.TrimSuffix(text, suffix)
+	return base + "\n" + line + suffix
+}
+
+// ApplyNoReviewersWarningToText returns message text with the "No reviewers requested" line added, or removed
+// when show is false. The line is kept ahead of any lifecycle state suffix.
+func ApplyNoReviewersWarningToText(text string, show bool) string {
+	text = noReviewersLineRegex.ReplaceAllString(text, "")
+	if !show {
+		return text
+	}
+
+	suffix := lifecycleStateRegex.FindString(text)
+	base := strings.TrimSuffix(text, suffix)
+	return base + noReviewersLine + suffix
+}
+
+// ApplyPresentationToText returns freshly built message text marked for its presentation.
+// Collapsed messages get a leading marker, messages ready to merge get a highlight line, and archived messages
+// are struck through.
+func ApplyPresentationToText(text, presentation string) string {
+	switch presentation {
+	case models.PresentationCollapsed:
+		return collapsedPrefix + text
+	case models.PresentationArchived:
+		return "~" + text + "~"
+	case models.PresentationReadyToMerge:
+		return text + readyToMergeLine
+	default:
+		return text
+	}
+}
+
+// SetLifecycleStateText edits tracked messages to show the PR lifecycle state (e.g. merged, closed) as text.
+// Used for channels which opt out of reactions. An empty state clears the existing state suffix.
+func (s *SlackService) SetLifecycleStateText(ctx context.Context, teamID string, messages []MessageRef, state string) error {
+	return s.editMessagesText(ctx, teamID, messages, func(text string) string {
+		return ApplyLifecycleStateToText(text, state)
+	})
+}
+
+// SetCountdownText edits tracked messages to show a release cut countdown line.
+// An empty line clears the existing countdown.
+func (s *SlackService) SetCountdownText(ctx context.Context, teamID string, messages []MessageRef, line string) error {
+	return s.editMessagesText(ctx, teamID, messages, func(text string) string {
+		return ApplyCountdownToText(text, line)
+	})
+}
+
+// SetSupersededText edits tracked messages to link to the PR that superseded them.
+func (s *SlackService) SetSupersededText(
+	ctx context.Context, teamID string, messages []MessageRef, newPRURL string, newPRNumber int,
+) error {
+	return s.editMessagesText(ctx, teamID, messages, func(text string) string {
+		return ApplySupersededToText(text, newPRURL, newPRNumber)
+	})
+}
+
+// SetDependencyText edits tracked messages to show the status of the PRs they depend on.
+// An empty line clears the existing dependency status.
+func (s *SlackService) SetDependencyText(ctx context.Context, teamID string, messages []MessageRef, line string) error {
+	return s.editMessagesText(ctx, teamID, messages, func(text string) string {
+		return ApplyDependencyLineToText(text, line)
+	})
+}
+
+// SetProjectContextText edits tracked messages to show the PR's milestone and project board column.
+// An empty line clears the existing annotation.
+func (s *SlackService) SetProjectContextText(ctx context.Context, teamID string, messages []MessageRef, line string) error {
+	return s.editMessagesText(ctx, teamID, messages, func(text string) string {
+		return ApplyProjectContextToText(text, line)
+	})
+}
+
+// SetApprovalQuorumText edits tracked messages to show the PR's approvals against the approvals it needs.
+// An empty line clears the existing approval count.
+func (s *SlackService) SetApprovalQuorumText(ctx context.Context, teamID string, messages []MessageRef, line string) error {
+	return s.editMessagesText(ctx, teamID, messages, func(text string) string {
+		return ApplyApprovalQuorumToText(text, line)
+	})
+}
+
+// SetNoReviewersWarningText edits tracked messages to add or remove the "No reviewers requested" line.
+func (s *SlackService) SetNoReviewersWarningText(ctx context.Context, teamID string, messages []MessageRef, show bool) error {
+	return s.editMessagesText(ctx, teamID, messages, func(text string) string {
+		return ApplyNoReviewersWarningToText(text, show)
+	})
+}
+
+// editMessagesText fetches the current text of each message, applies transform, and updates
+// the message if the text changed. Deleted messages are skipped.
+func (s *SlackService) editMessagesText(
+	ctx context.Context, teamID string, messages []MessageRef, transform func(string) string,
+) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	client, err := s.getSlackClient(ctx, teamID)
+	if err != nil {
+		return err
+	}
+
+	var lastError error
+	for _, msg := range messages {
+		history, err := client.GetConversationHistoryContext(ctx, &slack.GetConversationHistoryParameters{
+			ChannelID: msg.Channel,
+			Oldest:    msg.Timestamp,
+			Latest:    msg.Timestamp,
+			Inclusive: true,
+			Limit:     1,
+		})
+		if err != nil {
+			log.Warn(ctx, "Failed to fetch Slack message for text edit",
+				"error", err,
+				"channel", msg.Channel,
+				"message_ts", msg.Timestamp,
+			)
+			lastError = err
+			continue
+		}
+		// Without the message, Slack can return the one before it, which mustn't be edited
+		if len(history.Messages) == 0 || history.Messages[0].Timestamp != msg.Timestamp {
+			continue // Message was deleted
+		}
+
+		currentText := history.Messages[0].Text
+		updatedText := transform(currentText)
+		if updatedText == currentText {
+			continue
+		}
+
+		// Rich messages show annotations in their blocks as well as their fallback text
+		options := []slack.MsgOption{slack.MsgOptionText(updatedText, false)}
+		if blocks := history.Messages[0].Blocks.BlockSet; IsRichPRMessage(blocks) {
+			options = append(options, slack.MsgOptionBlocks(ApplyAnnotationsToBlocks(blocks, updatedText)...))
+		}
+
+		editClient, err := s.clientForMessage(ctx, teamID, client, msg.PostedBy)
+		if err != nil {
+			log.Warn(ctx, "Cannot edit Slack message text",
+				"error", err,
+				"channel", msg.Channel,
+				"message_ts", msg.Timestamp,
+			)
+			lastError = err
+			continue
+		}
+
+		_, _, _, err = editClient.UpdateMessageContext(ctx, msg.Channel, msg.Timestamp, options...)
+		if err != nil {
+			log.Error(ctx, "Failed to edit Slack message text",
+				"error", err,
+				"channel", msg.Channel,
+				"message_ts", msg.Timestamp,
+				"operation", "edit_message_text",
+			)
+			lastError = err
+		}
+	}
+
+	if lastError != nil {
+		return fmt.Errorf("failed to edit message text for team %s: %w", teamID, lastError)
+	}
+
+	return nil
+}
+
+// MessageExists reports whether a message is still in its channel's history. Messages deleted by a user or
+// removed by the workspace's message retention policy are both missing; a channel the bot can't read is an error.
+func (s *SlackService) MessageExists(ctx context.Context, teamID, channel, timestamp string) (bool, error) {
+	client, err := s.getSlackClient(ctx, teamID)
+	if err != nil {
+		return false, err
+	}
+
+	history, err := client.GetConversationHistoryContext(ctx, &slack.GetConversationHistoryParameters{
+		ChannelID: channel,
+		Oldest:    timestamp,
+		Latest:    timestamp,
+		Inclusive: true,
+		Limit:     1,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to fetch message %s in channel %s: %w", timestamp, channel, err)
+	}
+	return len(history.Messages) > 0 && history.Messages[0].Timestamp == timestamp, nil
+}
+
+// DeleteMessage deletes a Slack message. postedBy is the Slack user whose token posted it, or empty for the bot.
+func (s *SlackService) DeleteMessage(ctx context.Context, teamID, channel, timestamp, postedBy string) error {
 	client, err := s.getSlackClient(ctx, teamID)
 	if err != nil {
 		return err
@@ -631,7 +1564,12 @@ func (s *SlackService) DeleteMessage(ctx context.Context, teamID, channel, times
 		channelID = channel // Fallback to original value
 	}
 
-	_, _, err = client.DeleteMessage(channelID, timestamp)
+	deleteClient, err := s.clientForMessage(ctx, teamID, client, postedBy)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = deleteClient.DeleteMessageContext(ctx, channelID, timestamp)
 	if err != nil {
 		log.Error(ctx, "Failed to delete Slack message",
 			"error", err,
@@ -662,7 +1600,7 @@ func (s *SlackService) DeleteMultipleMessages(ctx context.Context, teamID string
 	successCount := 0
 
 	for _, msg := range messages {
-		err := s.DeleteMessage(ctx, teamID, msg.Channel, msg.Timestamp)
+		err := s.DeleteMessage(ctx, teamID, msg.Channel, msg.Timestamp, msg.PostedBy)
 		if err != nil {
 			log.Error(ctx, "Failed to delete tracked message",
 				"error", err,
@@ -694,6 +1632,7 @@ func (s *SlackService) DeleteMultipleMessages(ctx context.Context, teamID string
 type MessageRef struct {
 	Channel   string
 	Timestamp string
+	PostedBy  string // Slack user whose token posted the message; empty for bot-posted messages
 }
 
 // ResolveChannelID converts a channel name to channel ID if needed.
@@ -708,7 +1647,7 @@ func (s *SlackService) ResolveChannelID(ctx context.Context, teamID, channel str
 
 // resolveChannelID converts a channel name to channel ID if needed.
 // If the input is already a channel ID (starts with 'C'), returns it as-is.
-func (s *SlackService) resolveChannelID(ctx context.Context, _ string, client *slack.Client, channel string) (string, error) {
+func (s *SlackService) resolveChannelID(ctx context.Context, teamID string, client *slack.Client, channel string) (string, error) {
 	// If already a channel ID (starts with 'C'), return as-is
 	if strings.HasPrefix(channel, "C") {
 		return channel, nil
@@ -720,6 +1659,7 @@ func (s *SlackService) resolveChannelID(ctx context.Context, _ string, client *s
 		ExcludeArchived: true,
 		Limit:           maxConversationsPerPage,
 		Types:           []string{"public_channel", "private_channel"},
+		TeamID:          s.enterpriseTeamID(ctx, teamID),
 	}
 
 	for {
@@ -752,6 +1692,28 @@ type PRDirectives struct {
 	HasReviewDirective bool // Whether any !review directive was found (even if empty)
 }
 
+// UsedTypes returns the directive types present, for usage metrics.
+func (d *PRDirectives) UsedTypes() []string {
+	if !d.HasReviewDirective {
+		return nil
+	}
+
+	types := []string{models.DirectiveTypeReview}
+	if d.Skip {
+		types = append(types, models.DirectiveTypeSkip)
+	}
+	if d.Channel != "" {
+		types = append(types, models.DirectiveTypeChannel)
+	}
+	if len(d.UsersToCC) > 0 {
+		types = append(types, models.DirectiveTypeCC)
+	}
+	if d.CustomEmoji != "" {
+		types = append(types, models.DirectiveTypeEmoji)
+	}
+	return types
+}
+
 // !review[s]: [skip|no] [#channel_name] [@user1 @user2 ...].
 // ParsePRDirectives parses PR description for directive commands like !review: skip #channel @user1 @user2 :emoji:.
 // Returns parsed directives with all users accumulated from all directive occurrences.
@@ -759,7 +1721,7 @@ func (s *SlackService) ParsePRDirectives(description string) *PRDirectives {
 	directives := &PRDirectives{}
 
 	// Replace !review-skip with !review: skip to normalize all skip directives
-	normalizedDescription := skipDirectiveRegex.ReplaceAllString(description, "!review: skip")
+	normalizedDescription := skipDirectiveRegex.ReplaceAllString(normalizeDirectiveText(description), "!review: skip")
 
 	// Find all matches - last directive wins
 	allMatches := directiveRegex.FindAllStringSubmatch(normalizedDescription, -1)
@@ -778,6 +1740,12 @@ func (s *SlackService) ParsePRDirectives(description string) *PRDirectives {
 	return directives
 }
 
+// normalizeDirectiveText folds full-width characters to their ASCII forms (so `！ｒｅｖｉｅｗ：` reads as `!review:`)
+// and applies NFC normalization, so channel names typed with decomposed characters match Slack's composed names.
+func normalizeDirectiveText(text string) string {
+	return norm.NFC.String(width.Fold.String(text))
+}
+
 // processDirectiveMatch processes a single directive match and updates the directives.
 func (s *SlackService) processDirectiveMatch(content string, directives *PRDirectives) {
 	// Mark that we have a valid review directive (even if content is empty)
@@ -810,9 +1778,11 @@ func (s *SlackService) processDirectiveMatch(content string, directives *PRDirec
 // processDirectivePartWithUserList processes a single part of a directive with a local user list.
 func (s *SlackService) processDirectivePartWithUserList(part string, directives *PRDirectives, usersInThisDirective *[]string) {
 	// Check for skip directive
-	if strings.EqualFold(part, "skip") || strings.EqualFold(part, "no") {
-		directives.Skip = true
-		return
+	for _, keyword := range localizedSkipKeywords {
+		if strings.EqualFold(part, keyword) {
+			directives.Skip = true
+			return
+		}
 	}
 
 	// Check for emoji directive (format :emoji_name:)
@@ -844,7 +1814,7 @@ func (s *SlackService) processDirectivePartWithUserList(part string, directives
 
 // processChannelDirective processes a channel directive part.
 func (s *SlackService) processChannelDirective(part string, directives *PRDirectives) {
-	// Validate channel name format: alphanumeric, hyphens, underscores
+	// Validate channel name format: letters and digits in any script, hyphens, underscores
 	channelName := strings.TrimPrefix(part, "#")
 	if channelValidationRegex.MatchString(channelName) {
 		directives.Channel = channelName
@@ -893,6 +1863,133 @@ func (s *SlackService) GetUserInfo(ctx context.Context, teamID, userID string) (
 	return user, nil
 }
 
+// FindUserByGitHubUsername returns the ID of the one active Slack user whose username, display name or real name
+// matches a GitHub username, or "" if no user or more than one user matches.
+func (s *SlackService) FindUserByGitHubUsername(ctx context.Context, teamID, githubUsername string) (string, error) {
+	client, err := s.getSlackClient(ctx, teamID)
+	if err != nil {
+		return "", err
+	}
+
+	var options []slack.GetUsersOption
+	if enterpriseTeamID := s.enterpriseTeamID(ctx, teamID); enterpriseTeamID != "" {
+		options = append(options, slack.GetUsersOptionTeamID(enterpriseTeamID))
+	}
+	users, err := client.GetUsersContext(ctx, options...)
+	if err != nil {
+		log.Error(ctx, "Failed to list Slack users",
+			"error", err,
+			"team_id", teamID,
+			"operation", "find_user_by_github_username",
+		)
+		return "", fmt.Errorf("failed to list users for team %s: %w", teamID, err)
+	}
+
+	matchedUserID := ""
+	for _, user := range users {
+		if user.Deleted || user.IsBot || user.ID == "USLACKBOT" {
+			continue
+		}
+		if !utils.NameMatchesGitHubUsername(githubUsername, user.Name, user.Profile.DisplayName, user.RealName) {
+			continue
+		}
+		if matchedUserID != "" {
+			// Ambiguous, so don't guess
+			return "", nil
+		}
+		matchedUserID = user.ID
+	}
+	return matchedUserID, nil
+}
+
+// PostLinkInvite invites a Slack user who was likely CC'd on a PR under an unlinked GitHub username to link
+// their account, either by DM or as a message only they see in the PR's channel.
+func (s *SlackService) PostLinkInvite(
+	ctx context.Context, teamID, channel, slackUserID, githubUsername, prTitle, prURL string, ephemeral bool,
+) error {
+	client, err := s.getSlackClient(ctx, teamID)
+	if err != nil {
+		return err
+	}
+
+	text := utils.FormatLinkInvite(githubUsername, prTitle, prURL)
+	options := []slack.MsgOption{
+		slack.MsgOptionText(text, false),
+		slack.MsgOptionBlocks(linkInviteBlocks(text, githubUsername)...),
+		slack.MsgOptionDisableLinkUnfurl(),
+	}
+	entry := &models.NotificationLogEntry{Kind: models.NotificationKindDM, SlackTeamID: teamID, SlackChannel: slackUserID}
+	if ephemeral {
+		_, err = client.PostEphemeralContext(ctx, channel, slackUserID, options...)
+		entry.Kind = models.NotificationKindEphemeral
+		entry.SlackChannel = channel
+		entry.SlackUserID = slackUserID
+	} else {
+		_, entry.SlackMessageTS, err = client.PostMessageContext(ctx, slackUserID, options...)
+	}
+	s.recordNotification(ctx, entry, err)
+	if err != nil {
+		log.Error(ctx, "Failed to post link invite to Slack",
+			"error", err,
+			"team_id", teamID,
+			"user_id", slackUserID,
+			"ephemeral", ephemeral,
+			"operation", "post_link_invite",
+		)
+		return fmt.Errorf("failed to post link invite to user %s for team %s: %w", slackUserID, teamID, err)
+	}
+
+	return nil
+}
+
+// linkInviteBlocks builds an invitation to link a GitHub account, with a button to stop further invitations.
+func linkInviteBlocks(text, githubUsername string) []slack.Block {
+	optOut := slack.NewButtonBlockElement(LinkInviteOptOutActionID, githubUsername,
+		slack.NewTextBlockObject(slack.PlainTextType, "Don't ask again", false, false))
+	return []slack.Block{
+		slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, text, false, false), nil, nil),
+		slack.NewActionBlock("link_invite_actions", optOut),
+	}
+}
+
+// PostChannelIntro posts the bot's introduction to a channel it was invited to, explaining how to post PRs there
+// with directives and that PR links are tracked, with a button to configure the channel.
+func (s *SlackService) PostChannelIntro(ctx context.Context, teamID, channelID, channelName string) error {
+	client, err := s.getSlackClient(ctx, teamID)
+	if err != nil {
+		return err
+	}
+
+	text := utils.FormatChannelIntro(channelName)
+	configure := slack.NewButtonBlockElement(ChannelIntroConfigureActionID, channelID,
+		slack.NewTextBlockObject(slack.PlainTextType, "⚙️ Configure channel", false, false))
+	_, timestamp, err := client.PostMessageContext(ctx, channelID,
+		slack.MsgOptionText(text, false),
+		slack.MsgOptionBlocks(
+			slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, text, false, false), nil, nil),
+			slack.NewActionBlock("channel_intro_actions", configure),
+		),
+		slack.MsgOptionDisableLinkUnfurl(),
+	)
+	s.recordNotification(ctx, &models.NotificationLogEntry{
+		Kind:           models.NotificationKindMessage,
+		SlackTeamID:    teamID,
+		SlackChannel:   channelID,
+		SlackMessageTS: timestamp,
+	}, err)
+	if err != nil {
+		log.Error(ctx, "Failed to post channel intro to Slack",
+			"error", err,
+			"channel", channelID,
+			"team_id", teamID,
+			"operation", "post_channel_intro",
+		)
+		return fmt.Errorf("failed to post channel intro to channel %s for team %s: %w", channelID, teamID, err)
+	}
+
+	return nil
+}
+
 // PublishHomeView publishes the home tab view for a user.
 func (s *SlackService) PublishHomeView(ctx context.Context, teamID, userID string, view slack.HomeTabViewRequest) error {
 	client, err := s.getSlackClient(ctx, teamID)
@@ -995,6 +2092,124 @@ func (s *SlackService) BuildHomeView(
 	return s.uiBuilder.BuildHomeView(user, hasGitHubInstallations, installations)
 }
 
+// BuildWorkspaceStatsSection builds the App Home workspace activity section for admins.
+func (s *SlackService) BuildWorkspaceStatsSection(stats *models.WorkspaceStats, timeFormat utils.LocalTimeFormat) []slack.Block {
+	return s.uiBuilder.BuildWorkspaceStatsSection(stats, timeFormat)
+}
+
+// AddActivePRsSection inserts the App Home "Active PRs" section, listing one page of a user's open PRs, into a built view.
+func (s *SlackService) AddActivePRsSection(
+	view *slack.HomeTabViewRequest, prs []models.ActivePR, page, pageCount int, now time.Time,
+) {
+	s.uiBuilder.AddActivePRsSection(view, prs, page, pageCount, now)
+}
+
+// ActivePRsPageSize returns how many PRs the "Active PRs" section can list per page in a Home view of blocksInView blocks.
+func (s *SlackService) ActivePRsPageSize(blocksInView int) int {
+	return s.uiBuilder.ActivePRsPageSize(blocksInView)
+}
+
+// BuildWorkspaceSettingsSection builds the App Home workspace timezone and locale controls for admins.
+func (s *SlackService) BuildWorkspaceSettingsSection(workspace *models.SlackWorkspace) []slack.Block {
+	return s.uiBuilder.BuildWorkspaceSettingsSection(workspace)
+}
+
+// IsWorkspaceInstalled reports whether the app is installed in a workspace.
+func (s *SlackService) IsWorkspaceInstalled(ctx context.Context, teamID string) (bool, error) {
+	return s.workspaceService.IsWorkspaceInstalled(ctx, teamID)
+}
+
+// GetWorkspace retrieves a workspace installation, including its timezone and locale settings.
+func (s *SlackService) GetWorkspace(ctx context.Context, teamID string) (*models.SlackWorkspace, error) {
+	return s.workspaceService.GetWorkspace(ctx, teamID)
+}
+
+// UpdateWorkspaceLocale sets the timezone and locale used for a workspace's digests, countdowns and dates.
+func (s *SlackService) UpdateWorkspaceLocale(ctx context.Context, teamID, timezone, locale string) error {
+	return s.workspaceService.UpdateWorkspaceLocale(ctx, teamID, timezone, locale)
+}
+
+// UpdateWorkspaceLinkInvites sets how a workspace invites CC'd GitHub users without a linked Slack account to link it.
+func (s *SlackService) UpdateWorkspaceLinkInvites(ctx context.Context, teamID, mode string) error {
+	return s.workspaceService.UpdateWorkspaceLinkInvites(ctx, teamID, mode)
+}
+
+// UpdateWorkspaceSlackToGitHubComments sets whether a workspace posts "!to-github" thread replies as PR comments.
+func (s *SlackService) UpdateWorkspaceSlackToGitHubComments(ctx context.Context, teamID string, enabled bool) error {
+	return s.workspaceService.UpdateWorkspaceSlackToGitHubComments(ctx, teamID, enabled)
+}
+
+// UpdateWorkspaceReactionEmoji sets the review state reaction emoji a workspace overrides; nil clears them.
+func (s *SlackService) UpdateWorkspaceReactionEmoji(ctx context.Context, teamID string, emoji *models.WorkspaceEmoji) error {
+	return s.workspaceService.UpdateWorkspaceReactionEmoji(ctx, teamID, emoji)
+}
+
+// UpdateWorkspaceMessageTemplate sets the template a workspace's PR messages are laid out with; empty clears it.
+func (s *SlackService) UpdateWorkspaceMessageTemplate(ctx context.Context, teamID, messageTemplate string) error {
+	return s.workspaceService.UpdateWorkspaceMessageTemplate(ctx, teamID, messageTemplate)
+}
+
+// UpdateWorkspacePRSizeConfig sets the PR size emoji used across a workspace; nil clears it.
+func (s *SlackService) UpdateWorkspacePRSizeConfig(ctx context.Context, teamID string, prSizeConfig *models.PRSizeConfiguration) error {
+	return s.workspaceService.UpdateWorkspacePRSizeConfig(ctx, teamID, prSizeConfig)
+}
+
+// EmojiConfig returns the reaction emoji for a workspace: the environment defaults with the workspace's overrides.
+// Workspaces without an installation record, e.g. when using a single bot token, use the defaults.
+func (s *SlackService) EmojiConfig(ctx context.Context, teamID string) config.EmojiConfig {
+	if s.workspaceService == nil {
+		return s.emojiConfig
+	}
+	workspace, err := s.workspaceService.GetWorkspace(ctx, teamID)
+	if err != nil {
+		if !errors.Is(err, ErrWorkspaceNotFound) {
+			log.Warn(ctx, "Failed to get workspace emoji, using defaults", "error", err, "team_id", teamID)
+		}
+		return s.emojiConfig
+	}
+	return utils.ResolveEmojiConfig(s.emojiConfig, workspace.ReactionEmoji)
+}
+
+// GetWorkspaceTimeFormat returns how to format dates and times for a workspace.
+// Falls back to UTC and the default locale if the workspace can't be loaded.
+func (s *SlackService) GetWorkspaceTimeFormat(ctx context.Context, teamID string) utils.LocalTimeFormat {
+	workspace, err := s.workspaceService.GetWorkspace(ctx, teamID)
+	if err != nil {
+		log.Warn(ctx, "Failed to get workspace time settings, using UTC", "error", err, "team_id", teamID)
+		return utils.LocalTimeFormat{}
+	}
+	return utils.WorkspaceTimeFormat(workspace)
+}
+
+// IsWorkspaceAdmin reports whether a user is an admin or owner of the workspace.
+func (s *SlackService) IsWorkspaceAdmin(ctx context.Context, teamID, userID string) (bool, error) {
+	user, err := s.GetUserInfo(ctx, teamID, userID)
+	if err != nil {
+		return false, err
+	}
+	return user.IsAdmin || user.IsOwner || user.IsPrimaryOwner, nil
+}
+
+// ListUserGroups lists the workspace's user groups, for mapping GitHub teams to them.
+// Needs the usergroups:read scope, which installations from before team mappings have to be reinstalled to grant.
+func (s *SlackService) ListUserGroups(ctx context.Context, teamID string) ([]slack.UserGroup, error) {
+	client, err := s.getSlackClient(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	groups, err := client.GetUserGroupsContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user groups for team %s: %w", teamID, err)
+	}
+	return groups, nil
+}
+
+// BuildSlackUserTokenModal builds the modal linking to the Slack user token authorization.
+func (s *SlackService) BuildSlackUserTokenModal(oauthURL string) slack.ModalViewRequest {
+	return s.uiBuilder.BuildSlackUserTokenModal(oauthURL)
+}
+
 // BuildOAuthModal builds the OAuth connection modal.
 func (s *SlackService) BuildOAuthModal(oauthURL string) slack.ModalViewRequest {
 	return s.uiBuilder.BuildOAuthModal(oauthURL)
@@ -1013,8 +2228,8 @@ func (s *SlackService) BuildGitHubInstallationsModal(
 }
 
 // BuildChannelSelectorModal builds the channel selector modal.
-func (s *SlackService) BuildChannelSelectorModal() slack.ModalViewRequest {
-	return s.uiBuilder.BuildChannelSelectorModal()
+func (s *SlackService) BuildChannelSelectorModal(user *models.User) slack.ModalViewRequest {
+	return s.uiBuilder.BuildChannelSelectorModal(user)
 }
 
 // BuildPRSizeConfigModal builds the PR size emoji configuration modal.
@@ -1022,14 +2237,116 @@ func (s *SlackService) BuildPRSizeConfigModal(user *models.User) slack.ModalView
 	return s.uiBuilder.BuildPRSizeConfigModal(user)
 }
 
+// BuildChannelPRSizeConfigModal builds the PR size emoji configuration modal for a channel.
+func (s *SlackService) BuildChannelPRSizeConfigModal(channelID string, config *models.PRSizeConfiguration) slack.ModalViewRequest {
+	return s.uiBuilder.BuildChannelPRSizeConfigModal(channelID, config)
+}
+
+// BuildWorkspacePRSizeConfigModal builds the PR size emoji configuration modal for a workspace.
+func (s *SlackService) BuildWorkspacePRSizeConfigModal(config *models.PRSizeConfiguration) slack.ModalViewRequest {
+	return s.uiBuilder.BuildWorkspacePRSizeConfigModal(config)
+}
+
 // BuildChannelTrackingModal builds the channel tracking configuration modal.
 func (s *SlackService) BuildChannelTrackingModal(configs []*models.ChannelConfig) slack.ModalViewRequest {
 	return s.uiBuilder.BuildChannelTrackingModal(configs)
 }
 
+// BuildChannelFiltersModal builds the editor for the filters limiting which PRs are posted to a channel.
+func (s *SlackService) BuildChannelFiltersModal(channelID string, filters *models.PRFilters) slack.ModalViewRequest {
+	return s.uiBuilder.BuildChannelFiltersModal(channelID, filters)
+}
+
 // BuildChannelTrackingConfigModal builds the modal for configuring a specific channel's tracking settings.
-func (s *SlackService) BuildChannelTrackingConfigModal(channelID, channelName string, currentlyEnabled bool) slack.ModalViewRequest {
-	return s.uiBuilder.BuildChannelTrackingConfigModal(channelID, channelName, currentlyEnabled)
+func (s *SlackService) BuildChannelTrackingConfigModal(
+	channelID, channelName string, currentConfig *models.ChannelConfig,
+) slack.ModalViewRequest {
+	return s.uiBuilder.BuildChannelTrackingConfigModal(channelID, channelName, currentConfig)
+}
+
+// BuildRoutingRulesRepoModal builds the modal for picking which repository's routing rules to edit.
+func (s *SlackService) BuildRoutingRulesRepoModal(repos []*models.Repo) slack.ModalViewRequest {
+	return s.uiBuilder.BuildRoutingRulesRepoModal(repos)
+}
+
+// BuildRoutingRulesModal builds the editor for a repository's routing rules.
+func (s *SlackService) BuildRoutingRulesModal(repo *models.Repo) slack.ModalViewRequest {
+	return s.uiBuilder.BuildRoutingRulesModal(repo)
+}
+
+// BuildTeamUserGroupsModal builds the editor for a workspace's GitHub team to Slack user group mappings.
+func (s *SlackService) BuildTeamUserGroupsModal(mappings []models.TeamUserGroup) slack.ModalViewRequest {
+	return s.uiBuilder.BuildTeamUserGroupsModal(mappings)
+}
+
+// BuildReviewerRotationChannelModal builds the modal for picking which channel's reviewer rotation to edit.
+func (s *SlackService) BuildReviewerRotationChannelModal() slack.ModalViewRequest {
+	return s.uiBuilder.BuildReviewerRotationChannelModal()
+}
+
+// BuildReviewerRotationModal builds the editor for a channel's reviewer pool.
+func (s *SlackService) BuildReviewerRotationModal(
+	channelID, channelName string, rotation *models.ReviewerRotation,
+) slack.ModalViewRequest {
+	return s.uiBuilder.BuildReviewerRotationModal(channelID, channelName, rotation)
+}
+
+// BuildMovePRNotificationModal builds the channel picker for moving a PR message with the message shortcut.
+func (s *SlackService) BuildMovePRNotificationModal(message *models.TrackedMessage) slack.ModalViewRequest {
+	return s.uiBuilder.BuildMovePRNotificationModal(message)
+}
+
+// BuildMuteRepoModal builds the form for muting a repository, asking for its name when repoFullName is empty.
+func (s *SlackService) BuildMuteRepoModal(repoFullName string) slack.ModalViewRequest {
+	return s.uiBuilder.BuildMuteRepoModal(repoFullName)
+}
+
+// BuildPostPRModal builds the form for posting a PR's notification with the "Post a PR" global shortcut.
+func (s *SlackService) BuildPostPRModal() slack.ModalViewRequest {
+	return s.uiBuilder.BuildPostPRModal()
+}
+
+// BuildRepositoriesSection builds the App Home section listing a workspace's repositories for admins.
+func (s *SlackService) BuildRepositoriesSection(repos []*models.Repo) []slack.Block {
+	return s.uiBuilder.BuildRepositoriesSection(repos)
+}
+
+// BuildRevokedWorkspacesSection builds the App Home warning about revoked workspaces sharing repositories.
+func (s *SlackService) BuildRevokedWorkspacesSection(
+	workspaces []*models.SlackWorkspace, sharedRepos map[string][]string, reinstallURL string,
+) []slack.Block {
+	return s.uiBuilder.BuildRevokedWorkspacesSection(workspaces, sharedRepos, reinstallURL)
+}
+
+// BuildInstallationDefaultsModal builds the editor for the settings an installation's new repositories start with.
+func (s *SlackService) BuildInstallationDefaultsModal(installation *models.GitHubInstallation) slack.ModalViewRequest {
+	return s.uiBuilder.BuildInstallationDefaultsModal(installation)
+}
+
+// BuildRepoSettingsModal builds the editor for a repository's settings.
+func (s *SlackService) BuildRepoSettingsModal(repo *models.Repo) slack.ModalViewRequest {
+	return s.uiBuilder.BuildRepoSettingsModal(repo)
+}
+
+// BuildDeleteRepoModal builds the confirmation for deleting a repository's configuration.
+func (s *SlackService) BuildDeleteRepoModal(repoFullName string) slack.ModalViewRequest {
+	return s.uiBuilder.BuildDeleteRepoModal(repoFullName)
+}
+
+// BuildMessageTemplateModal builds the editor for a workspace's PR message template.
+func (s *SlackService) BuildMessageTemplateModal(messageTemplate string) slack.ModalViewRequest {
+	return s.uiBuilder.BuildMessageTemplateModal(messageTemplate)
+}
+
+// BuildReactionEmojiModal builds the editor for a workspace's reaction emoji, showing the environment defaults.
+func (s *SlackService) BuildReactionEmojiModal(overrides *models.WorkspaceEmoji) slack.ModalViewRequest {
+	return s.uiBuilder.BuildReactionEmojiModal(overrides, &models.WorkspaceEmoji{
+		Approved:         s.emojiConfig.Approved,
+		ChangesRequested: s.emojiConfig.ChangesRequested,
+		Commented:        s.emojiConfig.Commented,
+		Merged:           s.emojiConfig.Merged,
+		Closed:           s.emojiConfig.Closed,
+	})
 }
 
 // UpdateView updates an existing modal view.
@@ -1058,7 +2375,7 @@ func (s *SlackService) GetChannelName(ctx context.Context, teamID, channelID str
 		return "", err
 	}
 
-	channel, err := client.GetConversationInfo(&slack.GetConversationInfoInput{
+	channel, err := client.GetConversationInfoContext(ctx, &slack.GetConversationInfoInput{
 		ChannelID: channelID,
 	})
 	if err != nil {
@@ -1074,25 +2391,95 @@ func (s *SlackService) GetChannelName(ctx context.Context, teamID, channelID str
 	return channel.Name, nil
 }
 
+// IsChannelMember reports whether a user is a member of a channel. Private channels the bot hasn't been added
+// to can't be seen, so their members are reported as not members too.
+func (s *SlackService) IsChannelMember(ctx context.Context, teamID, channelID, userID string) (bool, error) {
+	client, err := s.getSlackClient(ctx, teamID)
+	if err != nil {
+		return false, err
+	}
+
+	const maxMembersPerPage = 1000 // Slack's max limit per page
+	params := &slack.GetUsersInConversationParameters{ChannelID: channelID, Limit: maxMembersPerPage}
+	for {
+		members, nextCursor, err := client.GetUsersInConversationContext(ctx, params)
+		if err != nil {
+			if err.Error() == "channel_not_found" {
+				return false, nil
+			}
+			return false, fmt.Errorf("failed to list members of channel %s in team %s: %w", channelID, teamID, err)
+		}
+		if slices.Contains(members, userID) {
+			return true, nil
+		}
+		if nextCursor == "" {
+			return false, nil
+		}
+		params.Cursor = nextCursor
+	}
+}
+
 // UpdatePRMessage updates an existing PR message in Slack with new content.
-// Used to update CC mentions when PR description directives change.
+// Used to update CC mentions when PR description directives change, and to render the message in a new
+// presentation: collapsed messages are rendered on one line, as in compact mode, and archived messages on one
+// struck-through line without their CCs.
+// Rich messages (rich set) have their blocks rebuilt too, and collapsing one removes its blocks until it's expanded.
+// metadata holds the PR's current labels and milestone, e.g. after it was labeled, and ccDelegates the delegates
+// CC'd for out-of-office users, as for PostPRMessage.
 func (s *SlackService) UpdatePRMessage(
 	ctx context.Context, teamID, channelID, messageTS, repoName, prTitle, prAuthor, prDescription, prURL string, prSize int,
 	authorSlackUserID string, usersToCC []string, usersCCSlackIDs []string, customEmoji string, userTaggingEnabled bool, user *models.User,
+	channelSizeConfig *models.PRSizeConfiguration, compact bool, postedBy, presentation string, rich *RichPRMessage, metadata *PRMetadata,
+	ccDelegates map[string]string,
 ) error {
-	client, err := s.getSlackClient(ctx, teamID)
+	botClient, err := s.getSlackClient(ctx, teamID)
+	if err != nil {
+		return err
+	}
+	client, err := s.clientForMessage(ctx, teamID, botClient, postedBy)
 	if err != nil {
 		return err
 	}
 
-	// Build the updated message text using the same logic as PostPRMessage
-	messageText := s.buildMessageText(
-		customEmoji, prSize, prURL, prTitle, prAuthor, usersToCC, usersCCSlackIDs,
-		authorSlackUserID, userTaggingEnabled, user,
-	)
+	// Build the updated message text using the same logic as PostPRMessage, in the resolved presentation
+	compact = compact || presentation == models.PresentationCollapsed || presentation == models.PresentationArchived
+	if presentation == models.PresentationArchived {
+		// Nobody needs pinging about a closed PR, and the CCs would clutter the condensed line
+		usersToCC, usersCCSlackIDs, ccDelegates = nil, nil, nil
+	}
+	sizeConfig := s.prSizeConfig(ctx, teamID, user, channelSizeConfig)
+	messageText := ApplyPresentationToText(s.buildMessageText(
+		s.messageTemplate(ctx, teamID), customEmoji, prSize, repoName, prURL, prTitle, prAuthor, usersToCC, usersCCSlackIDs,
+		authorSlackUserID, userTaggingEnabled, sizeConfig, compact, metadata, ccDelegates,
+	), presentation)
+	if metadata != nil && metadata.ApprovalQuorum != "" && !compact {
+		// Unlike labels, the approval count isn't part of the template, so it's kept like the other status lines
+		messageText = ApplyApprovalQuorumToText(messageText, metadata.ApprovalQuorum)
+	}
+	if metadata != nil && metadata.NoReviewers && !compact {
+		messageText = ApplyNoReviewersWarningToText(messageText, true)
+	}
+
+	msgOptions := []slack.MsgOption{slack.MsgOptionText(messageText, false)}
+	blocks := s.buildMessageBlocks(rich, messageText, customEmoji, prSize, repoName, prURL, prTitle, prAuthor,
+		usersToCC, usersCCSlackIDs, authorSlackUserID, userTaggingEnabled, sizeConfig, compact, metadata, ccDelegates)
+	if rich != nil {
+		// An empty list removes the blocks of a rich message shown on one line
+		msgOptions = append(msgOptions, slack.MsgOptionBlocks(append([]slack.Block{}, blocks...)...))
+	}
+
+	// Refresh the buttons too, clearing "Show more" if there's no longer anything to expand.
+	// Messages posted with a user token never carry buttons.
+	if (s.truncation().ShowMoreButton || s.OpenPRButtonEnabled()) && postedBy == "" {
+		attachments := s.buildMessageAttachments(prTitle, prDescription, prURL, compact, blocks != nil)
+		if attachments == nil {
+			attachments = []slack.Attachment{}
+		}
+		msgOptions = append(msgOptions, slack.MsgOptionAttachments(attachments...))
+	}
 
 	// Update the message using Slack's chat.update API
-	_, _, responseTS, err := client.UpdateMessage(channelID, messageTS, slack.MsgOptionText(messageText, false))
+	_, _, responseTS, err := client.UpdateMessageContext(ctx, channelID, messageTS, msgOptions...)
 	_ = responseTS // Ignore the response timestamp
 	if err != nil {
 		log.Error(ctx, "Failed to update PR message in Slack",
@@ -1114,3 +2501,35 @@ func (s *SlackService) UpdatePRMessage(
 
 	return nil
 }
+
+// RevokeUserToken revokes a user's Slack user token and deletes it from storage.
+// Revocation is best-effort: the stored token is deleted even if Slack rejects the revoke call.
+func (s *SlackService) RevokeUserToken(ctx context.Context, teamID, userID string) error {
+	client, err := s.getUserSlackClient(ctx, teamID, userID)
+	if err != nil && !errors.Is(err, ErrUserTokenNotFound) {
+		return err
+	}
+	if client != nil {
+		if _, err := client.SendAuthRevokeContext(ctx, ""); err != nil {
+			log.Warn(ctx, "Failed to revoke Slack user token",
+				"error", err,
+				"team_id", teamID,
+				"user_id", userID,
+			)
+		}
+	}
+
+	return s.workspaceService.DeleteUserToken(ctx, teamID, userID)
+}
+
+// AuthTest verifies that the stored bot token for a workspace is still valid.
+func (s *SlackService) AuthTest(ctx context.Context, teamID string) error {
+	client, err := s.getSlackClient(ctx, teamID)
+	if err != nil {
+		return err
+	}
+	if _, err := client.AuthTestContext(ctx); err != nil {
+		return fmt.Errorf("auth.test failed for workspace %s: %w", teamID, err)
+	}
+	return nil
+}
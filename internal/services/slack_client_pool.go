@@ -0,0 +1,51 @@
+package services
+
+import (
+	"sync"
+
+	"github.com/slack-go/slack"
+)
+
+// slackClientPool holds one Slack client per workspace, keyed by team ID.
+// Clients are reused across requests and rebuilt when the workspace's token changes (e.g. on reinstall).
+type slackClientPool struct {
+	mu        sync.RWMutex
+	clients   map[string]*pooledSlackClient
+	newClient func(key, token string) *slack.Client
+}
+
+// pooledSlackClient is a client together with the token it was built from.
+type pooledSlackClient struct {
+	token  string
+	client *slack.Client
+}
+
+// newSlackClientPool creates an empty pool that builds clients with newClient, given the key and token.
+func newSlackClientPool(newClient func(key, token string) *slack.Client) *slackClientPool {
+	return &slackClientPool{
+		clients:   make(map[string]*pooledSlackClient),
+		newClient: newClient,
+	}
+}
+
+// get returns the pooled client for a workspace, building a new one if none exists or the token has changed.
+func (p *slackClientPool) get(teamID, token string) *slack.Client {
+	p.mu.RLock()
+	entry, ok := p.clients[teamID]
+	p.mu.RUnlock()
+	if ok && entry.token == token {
+		return entry.client
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Another goroutine may have built the client while we waited for the lock
+	if entry, ok := p.clients[teamID]; ok && entry.token == token {
+		return entry.client
+	}
+
+	entry = &pooledSlackClient{token: token, client: p.newClient(teamID, token)}
+	p.clients[teamID] = entry
+	return entry.client
+}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlackClientPool_Get(t *testing.T) {
+	var built atomic.Int32
+	pool := newSlackClientPool(func(_, token string) *slack.Client {
+		built.Add(1)
+		return slack.New(token)
+	})
+
+	first := pool.get("T1", "xoxb-1")
+	assert.Same(t, first, pool.get("T1", "xoxb-1"), "same workspace and token reuses the client")
+	assert.NotSame(t, first, pool.get("T2", "xoxb-2"), "workspaces get separate clients")
+
+	rotated := pool.get("T1", "xoxb-1-rotated")
+	assert.NotSame(t, first, rotated, "a changed token rebuilds the client")
+	assert.Same(t, rotated, pool.get("T1", "xoxb-1-rotated"))
+	assert.Equal(t, int32(3), built.Load())
+}
+
+func TestSlackClientPool_ConcurrentGet(t *testing.T) {
+	var built atomic.Int32
+	pool := newSlackClientPool(func(_, token string) *slack.Client {
+		built.Add(1)
+		return slack.New(token)
+	})
+
+	const goroutines = 50
+	clients := make([]*slack.Client, goroutines)
+	var wg sync.WaitGroup
+	for i := range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			clients[i] = pool.get("T1", "xoxb-1")
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), built.Load())
+	for _, client := range clients {
+		assert.Same(t, clients[0], client)
+	}
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// slackEmojiListURL is called directly because slack-go's GetEmoji doesn't support include_categories,
+	// which is needed to get the standard emoji names alongside the workspace's custom ones.
+	slackEmojiListURL = "https://slack.com/api/emoji.list"
+	// workspaceEmojiTTL is how long a workspace's emoji names are cached before emoji.list is called again.
+	workspaceEmojiTTL = 10 * time.Minute
+)
+
+// ErrEmojiListFailed is returned when Slack rejects an emoji.list call.
+var ErrEmojiListFailed = errors.New("emoji.list failed")
+
+// workspaceEmojiCache caches the set of emoji names usable in each workspace, keyed by team ID.
+type workspaceEmojiCache struct {
+	mu      sync.Mutex
+	entries map[string]*workspaceEmojiSet
+	now     func() time.Time
+}
+
+// workspaceEmojiSet is the standard and custom emoji names of a workspace, with when they were fetched.
+type workspaceEmojiSet struct {
+	names     map[string]bool
+	fetchedAt time.Time
+}
+
+// newWorkspaceEmojiCache creates an empty emoji cache.
+func newWorkspaceEmojiCache() *workspaceEmojiCache {
+	return &workspaceEmojiCache{
+		entries: make(map[string]*workspaceEmojiSet),
+		now:     time.Now,
+	}
+}
+
+// get returns the cached emoji names for a workspace, or nil if there are none or they have expired.
+func (c *workspaceEmojiCache) get(teamID string) map[string]bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[teamID]
+	if !ok || c.now().Sub(entry.fetchedAt) > workspaceEmojiTTL {
+		return nil
+	}
+	return entry.names
+}
+
+// set caches the emoji names for a workspace.
+func (c *workspaceEmojiCache) set(teamID string, names map[string]bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[teamID] = &workspaceEmojiSet{names: names, fetchedAt: c.now()}
+}
+
+// emojiListResponse is the emoji.list response with include_categories set.
+type emojiListResponse struct {
+	OK         bool              `json:"ok"`
+	Error      string            `json:"error"`
+	Emoji      map[string]string `json:"emoji"`
+	Categories []struct {
+		EmojiNames []string `json:"emoji_names"`
+	} `json:"categories"`
+}
+
+// UnknownEmojiAliases returns the :alias: emojis that don't exist in a workspace, as either standard or custom emoji.
+// Unicode emoji characters are always considered known. Skin tone suffixes (e.g. :wave::skin-tone-2:) are ignored.
+func (s *SlackService) UnknownEmojiAliases(ctx context.Context, teamID string, emojis []string) ([]string, error) {
+	var aliases []string
+	for _, emoji := range emojis {
+		if strings.HasPrefix(emoji, ":") && strings.HasSuffix(emoji, ":") {
+			aliases = append(aliases, emoji)
+		}
+	}
+	if len(aliases) == 0 {
+		return nil, nil
+	}
+
+	known, err := s.workspaceEmojiNames(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	var unknown []string
+	for _, alias := range aliases {
+		if !known[emojiAliasName(alias)] {
+			unknown = append(unknown, alias)
+		}
+	}
+	return unknown, nil
+}
+
+// emojiAliasName returns the lowercased emoji name of an :alias:, without any skin tone suffix.
+func emojiAliasName(alias string) string {
+	name, _, _ := strings.Cut(strings.Trim(alias, ":"), "::")
+	return strings.ToLower(name)
+}
+
+// workspaceEmojiNames returns the set of standard and custom emoji names in a workspace, cached per workspace.
+func (s *SlackService) workspaceEmojiNames(ctx context.Context, teamID string) (map[string]bool, error) {
+	if names := s.emojiCache.get(teamID); names != nil {
+		return names, nil
+	}
+
+	token, err := s.workspaceService.GetWorkspaceToken(ctx, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workspace token: %w", err)
+	}
+
+	form := url.Values{"include_categories": {"true"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, slackEmojiListURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create emoji.list request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := s.loggingHTTPClient(teamID).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call emoji.list for team %s: %w", teamID, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var body emojiListResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode emoji.list response for team %s: %w", teamID, err)
+	}
+	if !body.OK {
+		return nil, fmt.Errorf("%w for team %s: %s", ErrEmojiListFailed, teamID, body.Error)
+	}
+
+	names := make(map[string]bool, len(body.Emoji))
+	for name := range body.Emoji {
+		names[strings.ToLower(name)] = true
+	}
+	for _, category := range body.Categories {
+		for _, name := range category.EmojiNames {
+			names[strings.ToLower(name)] = true
+		}
+	}
+
+	s.emojiCache.set(teamID, names)
+	return names, nil
+}
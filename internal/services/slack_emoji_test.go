@@ -0,0 +1,35 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlackService_UnknownEmojiAliases(t *testing.T) {
+	s := &SlackService{emojiCache: newWorkspaceEmojiCache()}
+	s.emojiCache.set("T1", map[string]bool{"ant": true, "wave": true, "shipit": true})
+
+	unknown, err := s.UnknownEmojiAliases(context.Background(), "T1",
+		[]string{":ant:", ":ShipIt:", ":wave::skin-tone-2:", "🐋", ":not_an_emoji:"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{":not_an_emoji:"}, unknown)
+
+	unknown, err = s.UnknownEmojiAliases(context.Background(), "T2", []string{"🐋"})
+	require.NoError(t, err, "unicode emoji don't need the workspace's emoji list")
+	assert.Empty(t, unknown)
+}
+
+func TestWorkspaceEmojiCache_Expiry(t *testing.T) {
+	now := time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)
+	c := newWorkspaceEmojiCache()
+	c.now = func() time.Time { return now }
+	c.set("T1", map[string]bool{"ant": true})
+
+	assert.NotNil(t, c.get("T1"))
+	now = now.Add(workspaceEmojiTTL + time.Second)
+	assert.Nil(t, c.get("T1"), "expired emoji names are refetched")
+}
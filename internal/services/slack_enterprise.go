@@ -0,0 +1,217 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/slack-go/slack"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+)
+
+// ErrEnterpriseInstallationNotFound is returned when an Enterprise Grid organization has no org-wide install.
+var ErrEnterpriseInstallationNotFound = errors.New("enterprise installation not found")
+
+// SaveEnterpriseInstallation saves an org-wide installation, and updates the token of every workspace it
+// already covers, since reinstalling issues a new token. Those marked revoked are restored.
+func (sws *SlackWorkspaceService) SaveEnterpriseInstallation(ctx context.Context, installation *models.SlackEnterpriseInstallation) error {
+	if err := installation.Validate(); err != nil {
+		return fmt.Errorf("invalid enterprise installation: %w", err)
+	}
+
+	installation.UpdatedAt = time.Now()
+
+	stored := *installation
+	accessToken, err := sws.encrypter.Encrypt(ctx, installation.AccessToken)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt enterprise token: %w", err)
+	}
+	stored.AccessToken = accessToken
+
+	if _, err := sws.client.Collection("slack_enterprise_installations").Doc(installation.ID).Set(ctx, &stored); err != nil {
+		log.Error(ctx, "Failed to save enterprise installation",
+			"error", err,
+			"enterprise_id", installation.ID,
+			"operation", "save_enterprise_installation",
+		)
+		return fmt.Errorf("failed to save enterprise installation: %w", err)
+	}
+
+	iter := sws.client.Collection("slack_workspaces").
+		Where("enterprise_id", "==", installation.ID).
+		Where("is_enterprise_install", "==", true).
+		Documents(ctx)
+	defer iter.Stop()
+
+	updated := 0
+	for {
+		doc, err := iter.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to list workspaces of enterprise %s: %w", installation.ID, err)
+		}
+
+		_, err = doc.Ref.Update(ctx, []firestore.Update{
+			{Path: "access_token", Value: accessToken},
+			{Path: "scope", Value: installation.Scope},
+			{Path: "app_id", Value: installation.AppID},
+			{Path: "bot_user_id", Value: installation.BotUserID},
+			{Path: "revoked_at", Value: firestore.Delete},
+			{Path: "revoked_reason", Value: firestore.Delete},
+			{Path: "updated_at", Value: installation.UpdatedAt},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update token of workspace %s: %w", doc.Ref.ID, err)
+		}
+
+		// Reload on next access
+		sws.cacheMutex.Lock()
+		delete(sws.tokenCache, doc.Ref.ID)
+		sws.cacheMutex.Unlock()
+		updated++
+	}
+
+	log.Info(ctx, "Enterprise installation saved successfully",
+		"enterprise_id", installation.ID,
+		"enterprise_name", installation.EnterpriseName,
+		"installed_by", installation.InstalledBy,
+		"updated_workspaces", updated,
+	)
+	return nil
+}
+
+// GetEnterpriseInstallation retrieves an Enterprise Grid organization's org-wide installation.
+func (sws *SlackWorkspaceService) GetEnterpriseInstallation(
+	ctx context.Context, enterpriseID string,
+) (*models.SlackEnterpriseInstallation, error) {
+	doc, err := sws.client.Collection("slack_enterprise_installations").Doc(enterpriseID).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, ErrEnterpriseInstallationNotFound
+		}
+		return nil, fmt.Errorf("failed to get enterprise installation: %w", err)
+	}
+
+	var installation models.SlackEnterpriseInstallation
+	if err := doc.DataTo(&installation); err != nil {
+		return nil, fmt.Errorf("failed to decode enterprise installation: %w", err)
+	}
+	if installation.AccessToken, err = sws.encrypter.Decrypt(ctx, installation.AccessToken); err != nil {
+		return nil, fmt.Errorf("failed to decrypt enterprise token: %w", err)
+	}
+	return &installation, nil
+}
+
+// SaveEnterpriseInstallation saves an org-wide installation and adds a workspace record for each workspace
+// in the organization the app has been granted to, returning those added. Workspaces granted later are
+// added when their first Slack event arrives, by EnsureEnterpriseWorkspace.
+func (s *SlackService) SaveEnterpriseInstallation(
+	ctx context.Context, installation *models.SlackEnterpriseInstallation,
+) ([]*models.SlackWorkspace, error) {
+	if err := s.workspaceService.SaveEnterpriseInstallation(ctx, installation); err != nil {
+		return nil, err
+	}
+
+	client := s.newSlackClient(installation.ID, installation.AccessToken)
+	var added []*models.SlackWorkspace
+	params := slack.ListTeamsParameters{}
+	for {
+		teams, nextCursor, err := client.ListTeamsContext(ctx, params)
+		if err != nil {
+			return added, fmt.Errorf("failed to list workspaces of enterprise %s: %w", installation.ID, err)
+		}
+
+		for _, team := range teams {
+			workspace, err := s.addEnterpriseWorkspace(ctx, installation, team.ID, team.Name)
+			if err != nil {
+				return added, err
+			}
+			if workspace != nil {
+				added = append(added, workspace)
+			}
+		}
+
+		if nextCursor == "" {
+			return added, nil
+		}
+		params.Cursor = nextCursor
+	}
+}
+
+// EnsureEnterpriseWorkspace adds a workspace record for a workspace covered by its organization's org-wide
+// install, so its token can be looked up by team ID. It's called for every Slack event, interaction and command
+// from an Enterprise Grid organization, and does nothing when the workspace is already known or the
+// organization has no org-wide install.
+func (s *SlackService) EnsureEnterpriseWorkspace(ctx context.Context, teamID, enterpriseID string) error {
+	if teamID == "" || enterpriseID == "" {
+		return nil
+	}
+
+	_, err := s.workspaceService.GetWorkspace(ctx, teamID)
+	if err == nil || !errors.Is(err, ErrWorkspaceNotFound) {
+		return err
+	}
+
+	installation, err := s.workspaceService.GetEnterpriseInstallation(ctx, enterpriseID)
+	if errors.Is(err, ErrEnterpriseInstallationNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	teamName := ""
+	client := s.newSlackClient(installation.ID, installation.AccessToken)
+	if team, err := client.GetOtherTeamInfoContext(ctx, teamID); err != nil {
+		log.Warn(ctx, "Failed to get name of workspace covered by org-wide install", "error", err, "team_id", teamID)
+	} else {
+		teamName = team.Name
+	}
+
+	_, err = s.addEnterpriseWorkspace(ctx, installation, teamID, teamName)
+	return err
+}
+
+// addEnterpriseWorkspace saves the workspace record of a workspace an org-wide install covers, unless the
+// workspace already has one, e.g. from installing the app in the workspace before the organization.
+// Returns nil if the workspace already had a record.
+func (s *SlackService) addEnterpriseWorkspace(
+	ctx context.Context, installation *models.SlackEnterpriseInstallation, teamID, teamName string,
+) (*models.SlackWorkspace, error) {
+	if _, err := s.workspaceService.GetWorkspace(ctx, teamID); err == nil {
+		return nil, nil
+	} else if !errors.Is(err, ErrWorkspaceNotFound) {
+		return nil, err
+	}
+
+	workspace := installation.WorkspaceFor(teamID, teamName)
+	if err := s.workspaceService.SaveWorkspace(ctx, workspace); err != nil {
+		return nil, err
+	}
+
+	log.Info(ctx, "Added workspace covered by org-wide install",
+		"team_id", teamID,
+		"team_name", workspace.TeamName,
+		"enterprise_id", installation.ID,
+	)
+	return workspace, nil
+}
+
+// enterpriseTeamID returns the team ID to pass to Slack methods that list a workspace's channels or users,
+// which org-level tokens need to know which workspace is meant. It's empty for workspace-level installs.
+func (s *SlackService) enterpriseTeamID(ctx context.Context, teamID string) string {
+	workspace, err := s.workspaceService.GetWorkspace(ctx, teamID)
+	if err != nil || !workspace.IsEnterpriseInstall {
+		return ""
+	}
+	return teamID
+}
@@ -0,0 +1,254 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"path"
+	"strconv"
+	"sync"
+	"time"
+
+	"github-slack-notifier/internal/log"
+)
+
+// slackDefaultMethodRate is the calls per minute allowed for Slack methods without a known rate limit (Tier 3).
+const slackDefaultMethodRate = 50
+
+// slackMethodRates are the calls per minute Slack allows each app per workspace for the methods we call most,
+// from Slack's rate limit tiers. chat.postMessage has no published workspace-wide limit, only about one
+// message per second per channel, so it's set high enough for PR fan-out to many channels at once.
+var slackMethodRates = map[string]float64{
+	"chat.postMessage":   300,
+	"chat.postEphemeral": 100, // Tier 4
+	"chat.update":        50,  // Tier 3
+	"chat.delete":        50,  // Tier 3
+	"chat.getPermalink":  100, // Tier 4
+	"reactions.add":      50,  // Tier 3
+	"reactions.remove":   50,  // Tier 3
+	"conversations.info": 50,  // Tier 3
+	"conversations.list": 20,  // Tier 2
+	"users.info":         100, // Tier 4
+	"users.list":         20,  // Tier 2
+	"usergroups.list":    20,  // Tier 2
+	"emoji.list":         20,  // Tier 2
+	"team.info":          20,  // Tier 2
+	"views.open":         100, // Tier 4
+	"views.push":         100, // Tier 4
+	"views.update":       100, // Tier 4
+	"views.publish":      100, // Tier 4
+}
+
+// slackBurstSeconds is how many seconds' worth of calls a method's bucket holds, so short bursts go out at once.
+const slackBurstSeconds = 10
+
+// slackRateLimiter paces Slack API calls with a token bucket per workspace and method, shared by every client
+// in the process, and pauses a workspace's method after Slack responds with Retry-After.
+type slackRateLimiter struct {
+	maxWait time.Duration // Longest a call waits for its bucket before failing with a rate limit
+	now     func() time.Time
+
+	mu      sync.Mutex
+	buckets map[string]*slackTokenBucket
+}
+
+// slackTokenBucket holds the calls a workspace can make to one method right now.
+type slackTokenBucket struct {
+	tokens       float64
+	refilledAt   time.Time
+	blockedUntil time.Time // Set from Slack's Retry-After; no calls before then
+}
+
+// newSlackRateLimiter creates a rate limiter whose calls wait at most maxWait.
+func newSlackRateLimiter(maxWait time.Duration) *slackRateLimiter {
+	return &slackRateLimiter{
+		maxWait: maxWait,
+		now:     time.Now,
+		buckets: make(map[string]*slackTokenBucket),
+	}
+}
+
+// methodRatePerSecond returns the calls per second allowed for a Slack method.
+func methodRatePerSecond(method string) float64 {
+	rate, ok := slackMethodRates[method]
+	if !ok {
+		rate = slackDefaultMethodRate
+	}
+	return rate / time.Minute.Seconds()
+}
+
+// bucket returns the token bucket for a workspace's method, creating a full one if needed. Callers hold mu.
+func (l *slackRateLimiter) bucket(teamID, method string, now time.Time) *slackTokenBucket {
+	key := teamID + "/" + method
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &slackTokenBucket{tokens: methodRatePerSecond(method) * slackBurstSeconds, refilledAt: now}
+		l.buckets[key] = b
+	}
+	return b
+}
+
+// reserve takes a call from a workspace's bucket for a method, returning how long the caller must wait before
+// making it. If the wait would be longer than maxWait, nothing is taken and ok is false.
+func (l *slackRateLimiter) reserve(teamID, method string) (wait time.Duration, ok bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	b := l.bucket(teamID, method, now)
+	perSecond := methodRatePerSecond(method)
+	b.tokens = math.Min(b.tokens+now.Sub(b.refilledAt).Seconds()*perSecond, perSecond*slackBurstSeconds)
+	b.refilledAt = now
+
+	if b.tokens < 1 {
+		wait = time.Duration((1 - b.tokens) / perSecond * float64(time.Second))
+	}
+	if blocked := b.blockedUntil.Sub(now); blocked > wait {
+		wait = blocked
+	}
+	if wait > l.maxWait {
+		return wait, false
+	}
+
+	b.tokens--
+	return wait, true
+}
+
+// block pauses a workspace's calls to a method until Slack's Retry-After has passed.
+func (l *slackRateLimiter) block(teamID, method string, retryAfter time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	b := l.bucket(teamID, method, now)
+	b.tokens = 0
+	b.refilledAt = now
+	if until := now.Add(retryAfter); until.After(b.blockedUntil) {
+		b.blockedUntil = until
+	}
+}
+
+// slackRateLimitTransport paces a workspace's Slack API calls with the shared rate limiter.
+// Calls that would wait longer than the limiter allows fail at once with HTTP 429 and a Retry-After header,
+// which the Slack client returns as a *slack.RateLimitedError, so jobs are retried later instead of failing.
+// A 429 from Slack pauses the method for the workspace, and the call is retried once if the pause is short.
+type slackRateLimitTransport struct {
+	teamID  string
+	limiter *slackRateLimiter
+	base    http.RoundTripper // Underlying transport; http.DefaultTransport if nil
+}
+
+// RoundTrip waits for the method's rate limit, then performs the request.
+func (t *slackRateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	method := path.Base(req.URL.Path)
+
+	for attempt := 0; ; attempt++ {
+		wait, ok := t.limiter.reserve(t.teamID, method)
+		if !ok {
+			log.Warn(req.Context(), "Slack rate limit reached, deferring call",
+				"team_id", t.teamID,
+				"slack_method", method,
+				"retry_after_ms", wait.Milliseconds(),
+			)
+			return rateLimitedResponse(req, wait), nil
+		}
+		if err := sleepContext(req.Context(), wait); err != nil {
+			return nil, err
+		}
+
+		resp, err := base.RoundTrip(req)
+		if err != nil || resp.StatusCode != http.StatusTooManyRequests {
+			return resp, err
+		}
+
+		retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"))
+		t.limiter.block(t.teamID, method, retryAfter)
+		log.Warn(req.Context(), "Slack rate limited call",
+			"team_id", t.teamID,
+			"slack_method", method,
+			"retry_after_ms", retryAfter.Milliseconds(),
+			"attempt", attempt+1,
+		)
+
+		// Retry once if the pause is short and the body can be sent again; the next reservation waits it out
+		if attempt > 0 || retryAfter > t.limiter.maxWait {
+			return resp, nil
+		}
+		retry, ok := rewindRequest(req)
+		if !ok {
+			return resp, nil
+		}
+		_ = resp.Body.Close()
+		req = retry
+	}
+}
+
+// rewindRequest returns a copy of a request with a fresh body to send it again, or false if the body can't be
+// read again.
+func rewindRequest(req *http.Request) (*http.Request, bool) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return req, true
+	}
+	if req.GetBody == nil {
+		return nil, false
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, false
+	}
+	retry := req.Clone(req.Context())
+	retry.Body = body
+	return retry, true
+}
+
+// parseRetryAfter parses a Retry-After header in seconds, defaulting to one second.
+func parseRetryAfter(value string) time.Duration {
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds < 1 {
+		return time.Second
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// rateLimitedResponse builds the HTTP 429 Slack would send, with Retry-After rounded up to whole seconds.
+func rateLimitedResponse(req *http.Request, retryAfter time.Duration) *http.Response {
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+	const body = `{"ok":false,"error":"ratelimited"}`
+	header := make(http.Header)
+	header.Set("Content-Type", "application/json")
+	header.Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	return &http.Response{
+		Status:        strconv.Itoa(http.StatusTooManyRequests) + " " + http.StatusText(http.StatusTooManyRequests),
+		StatusCode:    http.StatusTooManyRequests,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewBufferString(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// sleepContext waits for d, or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlackRateLimiter_Reserve(t *testing.T) {
+	now := time.Date(2025, 1, 10, 9, 0, 0, 0, time.UTC)
+	limiter := newSlackRateLimiter(time.Second)
+	limiter.now = func() time.Time { return now }
+
+	// chat.update allows 50 calls a minute, with a burst of 10 seconds' worth: 8⅓ calls
+	for range 8 {
+		wait, ok := limiter.reserve("T1", "chat.update")
+		require.True(t, ok)
+		require.Zero(t, wait)
+	}
+
+	wait, ok := limiter.reserve("T1", "chat.update")
+	assert.True(t, ok)
+	assert.InDelta(t, 800*time.Millisecond, wait, float64(time.Millisecond), "the next call waits for the rest of a token")
+
+	_, ok = limiter.reserve("T1", "chat.update")
+	assert.False(t, ok, "a wait beyond the maximum is refused")
+
+	wait, ok = limiter.reserve("T2", "chat.update")
+	assert.True(t, ok)
+	assert.Zero(t, wait, "workspaces have separate buckets")
+	wait, ok = limiter.reserve("T1", "chat.postMessage")
+	assert.True(t, ok)
+	assert.Zero(t, wait, "methods have separate buckets")
+
+	now = now.Add(time.Minute)
+	wait, ok = limiter.reserve("T1", "chat.update")
+	assert.True(t, ok)
+	assert.Zero(t, wait, "buckets refill over time")
+}
+
+func TestSlackRateLimiter_Block(t *testing.T) {
+	now := time.Date(2025, 1, 10, 9, 0, 0, 0, time.UTC)
+	limiter := newSlackRateLimiter(5 * time.Second)
+	limiter.now = func() time.Time { return now }
+
+	limiter.block("T1", "chat.postMessage", 3*time.Second)
+	wait, ok := limiter.reserve("T1", "chat.postMessage")
+	assert.True(t, ok)
+	assert.Equal(t, 3*time.Second, wait, "calls wait out Slack's Retry-After")
+
+	limiter.block("T1", "chat.postMessage", 30*time.Second)
+	wait, ok = limiter.reserve("T1", "chat.postMessage")
+	assert.False(t, ok)
+	assert.Equal(t, 30*time.Second, wait)
+}
+
+func TestSlackRateLimitTransport(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/chat.update") && calls.Add(1) == 1:
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"ok":false,"error":"ratelimited"}`))
+		case strings.HasSuffix(r.URL.Path, "/chat.delete"):
+			w.Header().Set("Retry-After", "30")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"ok":false,"error":"ratelimited"}`))
+		default:
+			_, _ = w.Write([]byte(`{"ok":true,"channel":"C1","ts":"1.2"}`))
+		}
+	}))
+	defer server.Close()
+
+	limiter := newSlackRateLimiter(2 * time.Second)
+	client := slack.New("xoxb-test", slack.OptionAPIURL(server.URL+"/api/"), slack.OptionHTTPClient(&http.Client{
+		Transport: &slackRateLimitTransport{teamID: "T1", limiter: limiter},
+	}))
+
+	_, _, _, err := client.UpdateMessage("C1", "1.2", slack.MsgOptionText("updated", false))
+	require.NoError(t, err, "a short Retry-After is waited out and the call retried")
+	assert.Equal(t, int32(2), calls.Load())
+
+	_, _, err = client.DeleteMessage("C1", "1.2")
+	var rateLimitErr *slack.RateLimitedError
+	require.True(t, errors.As(err, &rateLimitErr), "a long Retry-After is returned as a rate limit")
+	assert.Equal(t, 30*time.Second, rateLimitErr.RetryAfter)
+
+	_, _, err = client.DeleteMessage("C1", "1.2")
+	require.True(t, errors.As(err, &rateLimitErr), "later calls are refused without calling Slack")
+	assert.Equal(t, 30*time.Second, rateLimitErr.RetryAfter)
+}
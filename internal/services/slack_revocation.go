@@ -0,0 +1,149 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+)
+
+// revokedTokenErrors are the Slack API errors meaning a workspace's bot token will never work again,
+// e.g. because the app was uninstalled or the workspace was deleted.
+var revokedTokenErrors = map[string]bool{
+	"invalid_auth":     true,
+	"account_inactive": true,
+	"token_revoked":    true,
+}
+
+// slackRevocationTransport watches a workspace's bot token Slack API calls for errors meaning the token
+// has been revoked, and reports them with onRevoked. Responses are passed on unchanged.
+type slackRevocationTransport struct {
+	teamID    string
+	token     string // Bot token the calls are made with, so a call with a replaced token doesn't revoke the new one
+	onRevoked func(ctx context.Context, teamID, token, reason string)
+	base      http.RoundTripper // Underlying transport; http.DefaultTransport if nil
+}
+
+// RoundTrip performs the request, then checks the response for a revoked token error.
+func (t *slackRevocationTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	resp, err := base.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Slack API response: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	var result struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(body, &result) == nil && !result.OK && revokedTokenErrors[result.Error] {
+		t.onRevoked(req.Context(), t.teamID, t.token, result.Error)
+	}
+	return resp, nil
+}
+
+// markWorkspaceRevoked records that Slack revoked a workspace's bot token, pausing posting to it.
+// Failures are logged, as the call that found the revoked token fails either way.
+func (s *SlackService) markWorkspaceRevoked(ctx context.Context, teamID, token, reason string) {
+	if err := s.workspaceService.MarkWorkspaceRevoked(ctx, teamID, token, reason); err != nil {
+		log.Error(ctx, "Failed to mark workspace revoked", "error", err, "team_id", teamID, "reason", reason)
+	}
+}
+
+// ListRevokedWorkspaces returns the workspaces whose bot token Slack has revoked.
+func (s *SlackService) ListRevokedWorkspaces(ctx context.Context) ([]*models.SlackWorkspace, error) {
+	return s.workspaceService.ListRevokedWorkspaces(ctx)
+}
+
+// MarkWorkspaceRevoked records that Slack rejected a workspace's bot token with reason, so nothing more is posted
+// to it until the app is reinstalled there. Nothing changes if the workspace has since been reinstalled with
+// another token, or is already marked revoked.
+func (sws *SlackWorkspaceService) MarkWorkspaceRevoked(ctx context.Context, teamID, revokedToken, reason string) error {
+	workspace, err := sws.GetWorkspace(ctx, teamID)
+	if errors.Is(err, ErrWorkspaceNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if workspace.IsRevoked() || workspace.AccessToken != revokedToken {
+		return nil
+	}
+
+	now := time.Now()
+	_, err = sws.client.Collection("slack_workspaces").Doc(teamID).Update(ctx, []firestore.Update{
+		{Path: "revoked_at", Value: now},
+		{Path: "revoked_reason", Value: reason},
+		{Path: "updated_at", Value: now},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to mark workspace revoked: %w", err)
+	}
+
+	// Reload on next access
+	sws.cacheMutex.Lock()
+	delete(sws.tokenCache, teamID)
+	sws.cacheMutex.Unlock()
+
+	log.Warn(ctx, "Slack revoked workspace token, pausing notifications until the app is reinstalled",
+		"team_id", teamID,
+		"team_name", workspace.TeamName,
+		"reason", reason,
+	)
+	return nil
+}
+
+// ListRevokedWorkspaces returns the workspaces whose bot token Slack has revoked, most recently revoked first.
+// Their tokens aren't decrypted, as they no longer work, and are left empty.
+func (sws *SlackWorkspaceService) ListRevokedWorkspaces(ctx context.Context) ([]*models.SlackWorkspace, error) {
+	iter := sws.client.Collection("slack_workspaces").
+		Where("revoked_at", ">", time.Time{}).
+		OrderBy("revoked_at", firestore.Desc).
+		Documents(ctx)
+	defer iter.Stop()
+
+	var workspaces []*models.SlackWorkspace
+	for {
+		doc, err := iter.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to query revoked workspaces: %w", err)
+		}
+
+		var workspace models.SlackWorkspace
+		if err := doc.DataTo(&workspace); err != nil {
+			log.Error(ctx, "Failed to decode workspace",
+				"error", err,
+				"doc_id", doc.Ref.ID,
+				"operation", "decode_revoked_workspace",
+			)
+			continue
+		}
+		workspace.AccessToken = ""
+		workspaces = append(workspaces, &workspace)
+	}
+	return workspaces, nil
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlackRevocationTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/chat.postMessage"):
+			_, _ = w.Write([]byte(`{"ok":false,"error":"account_inactive"}`))
+		case strings.HasSuffix(r.URL.Path, "/chat.update"):
+			_, _ = w.Write([]byte(`{"ok":false,"error":"channel_not_found"}`))
+		default:
+			_, _ = w.Write([]byte(`{"ok":true,"channel":"C1","ts":"1.2"}`))
+		}
+	}))
+	defer server.Close()
+
+	var revoked []string
+	client := slack.New("xoxb-test", slack.OptionAPIURL(server.URL+"/api/"), slack.OptionHTTPClient(&http.Client{
+		Transport: &slackRevocationTransport{
+			teamID: "T1",
+			token:  "xoxb-test",
+			onRevoked: func(_ context.Context, teamID, token, reason string) {
+				revoked = append(revoked, teamID+" "+token+" "+reason)
+			},
+		},
+	}))
+
+	_, _, err := client.DeleteMessage("C1", "1.2")
+	require.NoError(t, err)
+	_, _, _, err = client.UpdateMessage("C1", "1.2", slack.MsgOptionText("updated", false))
+	require.Error(t, err)
+	assert.Empty(t, revoked, "other errors don't revoke the token")
+
+	_, _, err = client.PostMessage("C1", slack.MsgOptionText("hello", false))
+	require.EqualError(t, err, "account_inactive", "the response still reaches the client")
+	assert.Equal(t, []string{"T1 xoxb-test account_inactive"}, revoked)
+}
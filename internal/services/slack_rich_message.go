@@ -0,0 +1,154 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/slack-go/slack"
+
+	"github-slack-notifier/internal/utils"
+)
+
+// Block IDs of rich PR messages. Edits recognise rich messages by their title block.
+const (
+	richMessageTitleBlockID       = "pr_message_title"
+	richMessageAuthorBlockID      = "pr_message_author"
+	richMessageLabelsBlockID      = "pr_message_labels"
+	richMessageReviewersBlockID   = "pr_message_reviewers"
+	richMessageAnnotationsBlockID = "pr_message_annotations"
+)
+
+// maxContextElements is the most elements Slack allows in a context block.
+const maxContextElements = 10
+
+// RichPRMessage holds the PR details only shown in the rich Block Kit layout, for channels that use it.
+// A nil *RichPRMessage means the message is laid out as text.
+type RichPRMessage struct {
+	AuthorAvatarURL string // GitHub avatar of the PR author
+}
+
+// annotationRegexes match the lines and suffix edits add to PR message text, which rich messages repeat in a
+// context block below the PR details.
+var annotationRegexes = []*regexp.Regexp{
+	countdownLineRegex,
+	supersededLineRegex,
+	dependencyLineRegex,
+	projectContextLineRegex,
+	approvalQuorumLineRegex,
+	noReviewersLineRegex,
+	regexp.MustCompile(regexp.QuoteMeta(readyToMergeLine)),
+	lifecycleStateRegex,
+}
+
+// buildRichMessageBlocks lays out a PR message as Block Kit: the linked title with an "Open PR" button,
+// the author's avatar and the repository, the PR's label chips and milestone, its reviewers, and any
+// annotations in text. text is the message's text, which stays as the notification fallback.
+func buildRichMessageBlocks(
+	data utils.MessageTemplateData, prAuthor string, rich *RichPRMessage, labelChips []string, text string,
+) []slack.Block {
+	blocks := []slack.Block{
+		slack.NewSectionBlock(
+			slack.NewTextBlockObject(slack.MarkdownType, fmt.Sprintf("%s *%s*", data.Emoji, data.Link), false, false),
+			nil,
+			slack.NewAccessory(newOpenPRButton(data.URL)),
+			slack.SectionBlockOptionBlockID(richMessageTitleBlockID),
+		),
+	}
+
+	author := data.Author
+	if author == "" {
+		author = utils.EscapeSlackText(prAuthor)
+	}
+	var authorElements []slack.MixedElement
+	if rich.AuthorAvatarURL != "" {
+		authorElements = append(authorElements, slack.NewImageBlockElement(rich.AuthorAvatarURL, prAuthor))
+	}
+	authorElements = append(authorElements,
+		slack.NewTextBlockObject(slack.MarkdownType, fmt.Sprintf("%s · %s", author, data.Repo), false, false))
+	blocks = append(blocks, slack.NewContextBlock(richMessageAuthorBlockID, authorElements...))
+
+	// The milestone always fits; labels fill the rest of the context block
+	var labelElements []slack.MixedElement
+	if data.Milestone != "" {
+		labelElements = append(labelElements, slack.NewTextBlockObject(slack.MarkdownType, ":dart: "+data.Milestone, false, false))
+	}
+	for _, chip := range labelChips[:min(len(labelChips), maxContextElements-len(labelElements))] {
+		labelElements = append(labelElements, slack.NewTextBlockObject(slack.MarkdownType, chip, false, false))
+	}
+	if len(labelElements) > 0 {
+		blocks = append(blocks, slack.NewContextBlock(richMessageLabelsBlockID, labelElements...))
+	}
+
+	if data.CC != "" {
+		blocks = append(blocks, slack.NewSectionBlock(nil, []*slack.TextBlockObject{
+			slack.NewTextBlockObject(slack.MarkdownType, "*Reviewers*\n"+data.CC, false, false),
+		}, nil, slack.SectionBlockOptionBlockID(richMessageReviewersBlockID)))
+	}
+
+	return ApplyAnnotationsToBlocks(blocks, text)
+}
+
+// IsRichPRMessage reports whether a Slack message's blocks are a rich PR message layout.
+func IsRichPRMessage(blocks []slack.Block) bool {
+	for _, block := range blocks {
+		if richBlockID(block) == richMessageTitleBlockID {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyAnnotationsToBlocks returns a rich PR message's blocks with its annotations block rebuilt from the
+// message text, so edits that annotate the text (e.g. lifecycle state, countdowns) show in the layout too.
+func ApplyAnnotationsToBlocks(blocks []slack.Block, text string) []slack.Block {
+	updated := make([]slack.Block, 0, len(blocks)+1)
+	for _, block := range blocks {
+		if richBlockID(block) != richMessageAnnotationsBlockID {
+			updated = append(updated, block)
+		}
+	}
+
+	annotations := messageAnnotations(text)
+	if len(annotations) == 0 {
+		return updated
+	}
+	return append(updated, slack.NewContextBlock(richMessageAnnotationsBlockID,
+		slack.NewTextBlockObject(slack.MarkdownType, strings.Join(annotations, "\n"), false, false),
+	))
+}
+
+// richBlockID returns the block ID of a block in a rich PR message, which are only sections and context blocks.
+func richBlockID(block slack.Block) string {
+	switch b := block.(type) {
+	case *slack.SectionBlock:
+		return b.BlockID
+	case *slack.ContextBlock:
+		return b.BlockID
+	default:
+		return ""
+	}
+}
+
+// messageAnnotations returns the annotation lines and lifecycle state in PR message text, in the order they appear.
+func messageAnnotations(text string) []string {
+	type annotation struct {
+		offset int
+		text   string
+	}
+	var found []annotation
+	for _, re := range annotationRegexes {
+		for _, match := range re.FindAllStringIndex(text, -1) {
+			line := strings.TrimLeft(text[match[0]:match[1]], "\n ·")
+			found = append(found, annotation{offset: match[0], text: strings.TrimSpace(line)})
+		}
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].offset < found[j].offset })
+
+	annotations := make([]string, 0, len(found))
+	for _, a := range found {
+		annotations = append(annotations, a.text)
+	}
+	return annotations
+}
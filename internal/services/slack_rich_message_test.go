@@ -0,0 +1,40 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyAnnotationsToBlocks(t *testing.T) {
+	url := "https://github.com/o/r/pull/1"
+	s := &SlackService{}
+	text := s.buildMessageText(nil, "", 1, "o/r", url, "Fix bug", "alice", nil, nil, "", false, nil, false, nil, nil)
+	blocks := s.buildMessageBlocks(&RichPRMessage{}, text, "", 1, "o/r", url, "Fix bug", "alice", nil, nil, "", false, nil, false, nil, nil)
+	require.True(t, IsRichPRMessage(blocks))
+	require.Len(t, blocks, 2, "title and author, without labels, reviewers or annotations")
+
+	text = ApplyCountdownToText(text, "Release cut in 2 hours")
+	text = ApplyLifecycleStateToText(ApplySupersededToText(text, "https://github.com/o/r/pull/2", 2), "closed")
+	blocks = ApplyAnnotationsToBlocks(blocks, text)
+	require.Len(t, blocks, 3)
+	annotations, ok := blocks[2].(*slack.ContextBlock)
+	require.True(t, ok)
+	element, ok := annotations.ContextElements.Elements[0].(*slack.TextBlockObject)
+	require.True(t, ok)
+	assert.Equal(t, ":hourglass_flowing_sand: Release cut in 2 hours\n"+
+		":recycle: Superseded by <https://github.com/o/r/pull/2|#2>\n_closed_", element.Text)
+
+	blocks = ApplyAnnotationsToBlocks(blocks, ApplyLifecycleStateToText(text, "merged"))
+	require.Len(t, blocks, 3, "the annotations block is replaced, not repeated")
+
+	assert.Len(t, ApplyAnnotationsToBlocks(blocks, "plain text"), 2, "annotations removed from the text are removed")
+	assert.False(t, IsRichPRMessage([]slack.Block{slack.NewDividerBlock()}))
+	assert.Nil(t, s.buildMessageBlocks(nil, text, "", 1, "o/r", url, "Fix bug", "alice", nil, nil, "", false, nil, false, nil, nil),
+		"text messages have no blocks")
+	assert.Nil(t, s.buildMessageBlocks(&RichPRMessage{}, text, "", 1, "o/r", url, "Fix bug", "alice",
+		nil, nil, "", false, nil, true, nil, nil),
+		"compact messages stay on one line")
+}
@@ -1,9 +1,21 @@
 package services
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
 
+	"github.com/slack-go/slack"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github-slack-notifier/internal/config"
+	"github-slack-notifier/internal/models"
+	snapshotTesting "github-slack-notifier/internal/testing"
+	"github-slack-notifier/internal/utils"
 )
 
 func TestSlackService_ParsePRDirectives(t *testing.T) {
@@ -464,6 +476,56 @@ More details about the PR.`,
 				UsersToCC:          []string{"user"},
 			},
 		},
+		{
+			name:        "Full-width directive",
+			description: "！ｒｅｖｉｅｗ：　＃ｄｅｖ－ｔｅａｍ　＠ｊｏｈｎ．ｄｏｅ",
+			expected: &PRDirectives{
+				HasReviewDirective: true,
+				Channel:            "dev-team",
+				UsersToCC:          []string{"john.doe"},
+			},
+		},
+		{
+			name:        "Unicode channel name",
+			description: "!review: #開発チーム @tanaka",
+			expected: &PRDirectives{
+				HasReviewDirective: true,
+				Channel:            "開発チーム",
+				UsersToCC:          []string{"tanaka"},
+			},
+		},
+		{
+			name:        "Decomposed channel name is NFC normalized",
+			description: "!review: #e\u0301quipe",
+			expected: &PRDirectives{
+				HasReviewDirective: true,
+				Channel:            "\u00e9quipe",
+			},
+		},
+		{
+			name:        "Japanese keyword",
+			description: "！レビュー：＃バックエンド",
+			expected: &PRDirectives{
+				HasReviewDirective: true,
+				Channel:            "バックエンド",
+			},
+		},
+		{
+			name:        "Korean keyword with localized skip",
+			description: "!리뷰: 건너뛰기",
+			expected: &PRDirectives{
+				HasReviewDirective: true,
+				Skip:               true,
+			},
+		},
+		{
+			name:        "Spanish keyword is case insensitive",
+			description: "!REVISIÓN: #equipo-backend",
+			expected: &PRDirectives{
+				HasReviewDirective: true,
+				Channel:            "equipo-backend",
+			},
+		},
 	}
 
 	// Create a minimal SlackService just for testing the parsing function
@@ -538,3 +600,582 @@ func TestSlackService_ExtractChannelAndDirectives(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyLifecycleStateToText(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		state    string
+		expected string
+	}{
+		{
+			name:     "appends state",
+			text:     ":ant: <https://github.com/o/r/pull/1|Fix bug>",
+			state:    "merged",
+			expected: ":ant: <https://github.com/o/r/pull/1|Fix bug> · _merged_",
+		},
+		{
+			name:     "replaces existing state",
+			text:     ":ant: <https://github.com/o/r/pull/1|Fix bug> · _closed_",
+			state:    "merged",
+			expected: ":ant: <https://github.com/o/r/pull/1|Fix bug> · _merged_",
+		},
+		{
+			name:     "empty state clears suffix",
+			text:     ":ant: <https://github.com/o/r/pull/1|Fix bug> · _closed_",
+			state:    "",
+			expected: ":ant: <https://github.com/o/r/pull/1|Fix bug>",
+		},
+		{
+			name:     "empty state without suffix is unchanged",
+			text:     ":ant: <https://github.com/o/r/pull/1|Fix bug>",
+			state:    "",
+			expected: ":ant: <https://github.com/o/r/pull/1|Fix bug>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ApplyLifecycleStateToText(tt.text, tt.state))
+		})
+	}
+}
+
+func TestApplyCountdownToText(t *testing.T) {
+	base := ":ant: <https://github.com/o/r/pull/1|Fix bug>"
+
+	tests := []struct {
+		name     string
+		text     string
+		line     string
+		expected string
+	}{
+		{
+			name:     "appends countdown",
+			text:     base,
+			line:     "Release cut in 6h — needs review",
+			expected: base + "\n:hourglass_flowing_sand: Release cut in 6h — needs review",
+		},
+		{
+			name:     "replaces existing countdown",
+			text:     base + "\n:hourglass_flowing_sand: Release cut in 6h — needs review",
+			line:     "Release cut in 5h — approved, ready to merge",
+			expected: base + "\n:hourglass_flowing_sand: Release cut in 5h — approved, ready to merge",
+		},
+		{
+			name:     "keeps lifecycle suffix last",
+			text:     base + "\n:hourglass_flowing_sand: Release cut in 6h — needs review · _closed_",
+			line:     "Release cut passed — needs review",
+			expected: base + "\n:hourglass_flowing_sand: Release cut passed — needs review · _closed_",
+		},
+		{
+			name:     "empty line clears countdown and keeps lifecycle suffix",
+			text:     base + "\n:hourglass_flowing_sand: Release cut in 6h — needs review · _merged_",
+			line:     "",
+			expected: base + " · _merged_",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ApplyCountdownToText(tt.text, tt.line))
+		})
+	}
+}
+
+func TestApplySupersededToText(t *testing.T) {
+	base := ":ant: <https://github.com/o/r/pull/1|Fix bug>"
+	annotation := "\n:recycle: Superseded by <https://github.com/o/r/pull/2|#2>"
+
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{
+			name:     "appends annotation",
+			text:     base,
+			expected: base + annotation,
+		},
+		{
+			name:     "replaces existing annotation",
+			text:     base + "\n:recycle: Superseded by <https://github.com/o/r/pull/9|#9>",
+			expected: base + annotation,
+		},
+		{
+			name:     "keeps lifecycle suffix last",
+			text:     base + " · _closed_",
+			expected: base + annotation + " · _closed_",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ApplySupersededToText(tt.text, "https://github.com/o/r/pull/2", 2))
+		})
+	}
+}
+
+func TestApplyDependencyLineToText(t *testing.T) {
+	base := ":ant: <https://github.com/o/r/pull/1|Fix bug>"
+	blocked := ":no_entry: Blocked by <https://github.com/o/api/pull/2|o/api#2> (open)"
+	resolved := ":link: Depends on <https://github.com/o/api/pull/2|o/api#2> (merged)"
+
+	tests := []struct {
+		name     string
+		text     string
+		line     string
+		expected string
+	}{
+		{
+			name:     "appends line",
+			text:     base,
+			line:     blocked,
+			expected: base + "\n" + blocked,
+		},
+		{
+			name:     "replaces existing line",
+			text:     base + "\n" + blocked,
+			line:     resolved,
+			expected: base + "\n" + resolved,
+		},
+		{
+			name:     "keeps lifecycle suffix last",
+			text:     base + "\n" + blocked + " · _closed_",
+			line:     resolved,
+			expected: base + "\n" + resolved + " · _closed_",
+		},
+		{
+			name:     "empty line clears dependency status",
+			text:     base + "\n" + blocked,
+			line:     "",
+			expected: base,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ApplyDependencyLineToText(tt.text, tt.line))
+		})
+	}
+}
+
+func TestPRDirectives_UsedTypes(t *testing.T) {
+	tests := []struct {
+		name       string
+		directives *PRDirectives
+		expected   []string
+	}{
+		{
+			name:       "no directive",
+			directives: &PRDirectives{},
+			expected:   nil,
+		},
+		{
+			name:       "bare directive",
+			directives: &PRDirectives{HasReviewDirective: true},
+			expected:   []string{models.DirectiveTypeReview},
+		},
+		{
+			name: "all components",
+			directives: &PRDirectives{
+				HasReviewDirective: true,
+				Skip:               true,
+				Channel:            "backend",
+				UsersToCC:          []string{"alice"},
+				CustomEmoji:        ":rocket:",
+			},
+			expected: []string{
+				models.DirectiveTypeReview,
+				models.DirectiveTypeSkip,
+				models.DirectiveTypeChannel,
+				models.DirectiveTypeCC,
+				models.DirectiveTypeEmoji,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.directives.UsedTypes())
+		})
+	}
+}
+
+func TestApplyProjectContextToText(t *testing.T) {
+	base := ":ant: <https://github.com/o/r/pull/1|Fix bug>"
+	countdown := "\n:hourglass_flowing_sand: Release cut in 6h — needs review"
+
+	tests := []struct {
+		name     string
+		text     string
+		line     string
+		expected string
+	}{
+		{
+			name:     "appends line",
+			text:     base,
+			line:     "Sprint 42 • In Review",
+			expected: base + "\n:card_index_dividers: Sprint 42 • In Review",
+		},
+		{
+			name:     "replaces existing line",
+			text:     base + "\n:card_index_dividers: Sprint 42 • In Review",
+			line:     "Sprint 42 • Done",
+			expected: base + "\n:card_index_dividers: Sprint 42 • Done",
+		},
+		{
+			name:     "keeps lifecycle suffix last",
+			text:     base + "\n:card_index_dividers: Sprint 42 • In Review · _merged_",
+			line:     "Sprint 42 • Done",
+			expected: base + "\n:card_index_dividers: Sprint 42 • Done · _merged_",
+		},
+		{
+			name:     "leaves other annotations alone",
+			text:     base + "\n:card_index_dividers: Sprint 42" + countdown,
+			line:     "Sprint 43",
+			expected: base + countdown + "\n:card_index_dividers: Sprint 43",
+		},
+		{
+			name:     "empty line clears annotation",
+			text:     base + "\n:card_index_dividers: Sprint 42",
+			line:     "",
+			expected: base,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ApplyProjectContextToText(tt.text, tt.line))
+		})
+	}
+}
+
+func TestApplyApprovalQuorumToText(t *testing.T) {
+	base := ":ant: <https://github.com/o/r/pull/1|Fix bug>"
+	projectContext := "\n:card_index_dividers: Sprint 42"
+
+	text := ApplyApprovalQuorumToText(base, ":ballot_box_with_check: 1/2 approvals")
+	assert.Equal(t, base+"\n:ballot_box_with_check: 1/2 approvals", text)
+
+	text = ApplyApprovalQuorumToText(text+projectContext, ":white_check_mark: 2/2 approvals")
+	assert.Equal(t, base+projectContext+"\n:white_check_mark: 2/2 approvals", text, "replaces the line, leaving other annotations")
+
+	assert.Equal(t, base+"\n:white_check_mark: 2/2 approvals · _merged_",
+		ApplyApprovalQuorumToText(base+"\n:ballot_box_with_check: 1/2 approvals · _merged_", ":white_check_mark: 2/2 approvals"),
+		"keeps lifecycle suffix last")
+	assert.Equal(t, base+projectContext, ApplyApprovalQuorumToText(text, ""), "empty line clears the approval count")
+}
+
+func TestApplyNoReviewersWarningToText(t *testing.T) {
+	base := ":ant: <https://github.com/o/r/pull/1|Fix bug>"
+
+	text := ApplyNoReviewersWarningToText(base, true)
+	assert.Equal(t, base+"\n:bust_in_silhouette: _No reviewers requested_", text)
+	assert.Equal(t, text, ApplyNoReviewersWarningToText(text, true), "the line is only added once")
+
+	assert.Equal(t, base+"\n:bust_in_silhouette: _No reviewers requested_ · _merged_",
+		ApplyNoReviewersWarningToText(base+" · _merged_", true), "keeps lifecycle suffix last")
+	assert.Equal(t, base, ApplyNoReviewersWarningToText(text, false))
+	assert.Equal(t, []string{":bust_in_silhouette: _No reviewers requested_"}, messageAnnotations(text),
+		"rich messages show the line with their annotations")
+}
+
+func TestApplyPresentationToText(t *testing.T) {
+	base := ":ant: <https://github.com/o/r/pull/1|Fix bug>"
+
+	assert.Equal(t, base, ApplyPresentationToText(base, models.PresentationDefault))
+	assert.Equal(t, ":zzz: "+base, ApplyPresentationToText(base, models.PresentationCollapsed))
+
+	ready := ApplyPresentationToText(base, models.PresentationReadyToMerge)
+	assert.Equal(t, base+"\n:rocket: *Ready to merge*", ready)
+
+	// Later annotations leave the highlight alone
+	assert.Equal(t, ready+"\n:hourglass_flowing_sand: Release cut in 6h", ApplyCountdownToText(ready, "Release cut in 6h"))
+	assert.Equal(t, ready+" · _merged_", ApplyLifecycleStateToText(ready, "merged"))
+
+	// Archived messages keep the lifecycle state after the struck-through line
+	archived := ApplyPresentationToText(base, models.PresentationArchived)
+	assert.Equal(t, "~"+base+"~", archived)
+	assert.Equal(t, archived+" · _merged_", ApplyLifecycleStateToText(archived, "merged"))
+}
+
+func TestSlackService_buildMessageText(t *testing.T) {
+	s := &SlackService{}
+	url := "https://github.com/o/r/pull/1"
+
+	tests := []struct {
+		name              string
+		authorSlackUserID string
+		tagging           bool
+		usersToCC         []string
+		ccSlackIDs        []string
+		ccDelegates       map[string]string
+		compact           bool
+		expected          string
+	}{
+		{
+			name:              "tagged author",
+			authorSlackUserID: "U1",
+			tagging:           true,
+			expected:          ":ant: <" + url + "|Fix bug> by <@U1>",
+		},
+		{
+			name:     "unresolved author uses GitHub login",
+			expected: ":ant: <" + url + "|Fix bug> by alice",
+		},
+		{
+			name:              "compact skips emoji and author ping",
+			authorSlackUserID: "U1",
+			tagging:           true,
+			compact:           true,
+			expected:          "<" + url + "|Fix bug> · alice",
+		},
+		{
+			name:       "compact keeps CC mentions",
+			usersToCC:  []string{"bob"},
+			ccSlackIDs: []string{"U2"},
+			compact:    true,
+			expected:   "<" + url + "|Fix bug> · alice (cc: <@U2>)",
+		},
+		{
+			name:        "out of office CCs are replaced by their delegate",
+			usersToCC:   []string{"Bob", "carol", "dave"},
+			ccSlackIDs:  []string{"", "", "U4"},
+			ccDelegates: map[string]string{"bob": "U9", "carol": ""},
+			expected:    ":ant: <" + url + "|Fix bug> by alice (cc: <@U9> (for @Bob, out of office), @carol (out of office), <@U4>)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text := s.buildMessageText(nil, "", 1, "o/r", url, "Fix bug", "alice", tt.usersToCC, tt.ccSlackIDs,
+				tt.authorSlackUserID, tt.tagging, nil, tt.compact, nil, tt.ccDelegates)
+			assert.Equal(t, tt.expected, text)
+		})
+	}
+}
+
+func TestSlackService_buildMessageText_Template(t *testing.T) {
+	s := &SlackService{}
+	url := "https://github.com/o/r/pull/1"
+	tmpl, err := utils.ParseMessageTemplate("{{.Author}} opened {{.Link}} in {{.Repo}} {{.Emoji}}{{if .CC}}\ncc {{.CC}}{{end}}")
+	require.NoError(t, err)
+
+	text := s.buildMessageText(tmpl, "", 1, "o/r", url, "Fix <b> & <!channel>", "alice", []string{"bob"}, []string{"U2"},
+		"U1", true, nil, false, nil, nil)
+	assert.Equal(t, "<@U1> opened <"+url+"|Fix &lt;b&gt; &amp; &lt;!channel&gt;> in o/r :ant:\ncc <@U2>", text,
+		"titles are escaped so they can't break the link or ping anyone")
+
+	text = s.buildMessageText(tmpl, "", 1, "o/r", url, "Fix bug", "alice", nil, nil, "U1", false, nil, false, nil, nil)
+	assert.Equal(t, "opened <"+url+"|Fix bug> in o/r :ant:", text, "empty fields are trimmed")
+
+	text = s.buildMessageText(tmpl, "", 1, "o/r", url, "Fix bug", "alice", nil, nil, "U1", true, nil, true, nil, nil)
+	assert.Equal(t, "<"+url+"|Fix bug> · alice", text, "compact messages keep their format")
+}
+
+func TestSlackService_buildMessageText_Labels(t *testing.T) {
+	s := &SlackService{config: &config.Config{}}
+	url := "https://github.com/o/r/pull/1"
+	metadata := &PRMetadata{
+		Labels:    []PRLabel{{Name: "bug", Color: "d73a4a"}, {Name: "Security", Color: "ffffff"}},
+		Milestone: "Sprint <42>",
+	}
+
+	text := s.buildMessageText(nil, "", 1, "o/r", url, "Fix bug", "alice", nil, nil, "", false, nil, false, metadata, nil)
+	assert.Equal(t, ":ant: <"+url+"|Fix bug> by alice", text, "label chips are off by default")
+
+	s.config.Labels = config.LabelConfig{Enabled: true, Emoji: map[string]string{"security": ":lock:"}}
+	text = s.buildMessageText(nil, "", 1, "o/r", url, "Fix bug", "alice", nil, nil, "", false, nil, false, metadata, nil)
+	assert.Equal(t, ":ant: <"+url+"|Fix bug> by alice\n:large_red_square: bug  :lock: Security\n:dart: Sprint &lt;42&gt;", text)
+
+	text = s.buildMessageText(nil, "", 1, "o/r", url, "Fix bug", "alice", nil, nil, "", false, nil, true, metadata, nil)
+	assert.Equal(t, "<"+url+"|Fix bug> · alice", text, "compact messages stay on one line")
+}
+
+func TestSlackService_MessageTruncation(t *testing.T) {
+	s := &SlackService{config: &config.Config{Truncation: config.TruncationConfig{
+		MaxTitleLength:       10,
+		MaxDescriptionLength: 10,
+		Ellipsis:             "…",
+		ShowMoreButton:       true,
+	}}}
+	url := "https://github.com/o/r/pull/1"
+	title := "Refactor the notification pipeline"
+
+	text := s.buildMessageText(nil, "", 1, "o/r", url, title, "alice", nil, nil, "", false, nil, false, nil, nil)
+	assert.Equal(t, ":ant: <"+url+"|Refactor…> by alice", text)
+
+	attachments := s.buildMessageAttachments(title, "Moves posting into a queue.", url, false, false)
+	require.Len(t, attachments, 1)
+	actions, ok := attachments[0].Blocks.BlockSet[0].(*slack.ActionBlock)
+	require.True(t, ok)
+	button, ok := actions.Elements.ElementSet[0].(*slack.ButtonBlockElement)
+	require.True(t, ok)
+	assert.Equal(t, ShowPRDetailsActionID, button.ActionID)
+	assert.Equal(t, title+"\n\nMoves pos…", button.Value)
+
+	assert.Nil(t, s.buildMessageAttachments("Fix bug", "  ", url, false, false), "nothing to expand")
+	assert.Nil(t, s.buildMessageAttachments(title, "Details", url, true, false), "compact messages have no button")
+}
+
+func TestSlackService_OpenPRButton(t *testing.T) {
+	s := &SlackService{config: &config.Config{OpenPRButton: true}}
+	url := "https://github.com/o/r/pull/1"
+
+	attachments := s.buildMessageAttachments("Fix bug", "Details", url, false, false)
+	require.Len(t, attachments, 1)
+	actions, ok := attachments[0].Blocks.BlockSet[0].(*slack.ActionBlock)
+	require.True(t, ok)
+	require.Len(t, actions.Elements.ElementSet, 1, "Show more is disabled")
+	button, ok := actions.Elements.ElementSet[0].(*slack.ButtonBlockElement)
+	require.True(t, ok)
+	assert.Equal(t, OpenPRActionID, button.ActionID)
+	assert.Equal(t, url, button.URL)
+
+	s.config.Truncation = config.TruncationConfig{MaxTitleLength: 150, ShowMoreButton: true}
+	attachments = s.buildMessageAttachments("Fix bug", "Details", url, false, false)
+	require.Len(t, attachments, 1)
+	actions, ok = attachments[0].Blocks.BlockSet[0].(*slack.ActionBlock)
+	require.True(t, ok)
+	require.Len(t, actions.Elements.ElementSet, 2, "Open PR and Show more share a row")
+
+	assert.Nil(t, s.buildMessageAttachments("Fix bug", "Details", url, true, false), "compact messages have no buttons")
+}
+
+func TestSlackService_PRMessage_Snapshots(t *testing.T) {
+	s := &SlackService{config: &config.Config{
+		OpenPRButton: true,
+		Truncation:   config.TruncationConfig{MaxTitleLength: 40, Ellipsis: "…", ShowMoreButton: true},
+	}}
+	url := "https://github.com/octo-org/widgets/pull/12"
+	title := "Add widget caching with a longer title than fits"
+	description := "Caches widgets between requests.\n\n!review: @hubot"
+
+	type prMessage struct {
+		Text        string             `json:"text"`
+		Attachments []slack.Attachment `json:"attachments,omitempty"`
+	}
+
+	snapshotTesting.MatchSnapshot(t, "pr_message_full", prMessage{
+		Text: s.buildMessageText(nil, "", 120, "octo-org/widgets", url, title, "octocat",
+			[]string{"hubot", "monalisa"}, []string{"U456", ""}, "U123", true, nil, false, nil, nil),
+		Attachments: s.buildMessageAttachments(title, description, url, false, false),
+	})
+	richText := ApplyLifecycleStateToText(s.buildMessageText(nil, "", 120, "octo-org/widgets", url, title, "octocat",
+		[]string{"hubot"}, []string{"U456"}, "U123", true, nil, false, nil, nil)+readyToMergeLine, "merged")
+	rich := &RichPRMessage{AuthorAvatarURL: "https://avatars.githubusercontent.com/u/583231"}
+	metadata := &PRMetadata{
+		Labels:    []PRLabel{{Name: "enhancement", Color: "a2eeef"}, {Name: "cache", Color: "fbca04"}},
+		Milestone: "v2.0",
+	}
+	snapshotTesting.MatchSnapshot(t, "pr_message_rich", struct {
+		Text        string             `json:"text"`
+		Blocks      []slack.Block      `json:"blocks"`
+		Attachments []slack.Attachment `json:"attachments,omitempty"`
+	}{
+		Text: richText,
+		Blocks: s.buildMessageBlocks(rich, richText, "", 120, "octo-org/widgets", url, title, "octocat",
+			[]string{"hubot"}, []string{"U456"}, "U123", true, nil, false, metadata, nil),
+		Attachments: s.buildMessageAttachments(title, description, url, false, true),
+	})
+	snapshotTesting.MatchSnapshot(t, "pr_message_compact", prMessage{
+		Text: s.buildMessageText(nil, "", 120, "octo-org/widgets", url, title, "octocat",
+			nil, nil, "U123", true, nil, true, nil, nil),
+		Attachments: s.buildMessageAttachments(title, description, url, true, false),
+	})
+}
+
+func TestNotificationKindFor(t *testing.T) {
+	assert.Equal(t, models.NotificationKindMessage, notificationKindFor("C0123456789"))
+	assert.Equal(t, models.NotificationKindMessage, notificationKindFor("G0123456789"))
+	assert.Equal(t, models.NotificationKindDM, notificationKindFor("U0123456789"))
+	assert.Equal(t, models.NotificationKindDM, notificationKindFor("W0123456789"))
+	assert.Equal(t, models.NotificationKindDM, notificationKindFor("D0123456789"))
+}
+
+// newTestSlackServiceForAPI returns a SlackService whose workspace T1 calls the Slack API served at apiURL.
+func newTestSlackServiceForAPI(apiURL string) *SlackService {
+	s := &SlackService{workspaceService: &SlackWorkspaceService{tokenCache: map[string]*models.SlackWorkspace{
+		"T1": {ID: "T1", TeamName: "Test", AccessToken: "xoxb-test"},
+	}}}
+	s.clientPool = newSlackClientPool(func(_, token string) *slack.Client {
+		return slack.New(token, slack.OptionAPIURL(apiURL+"/api/"))
+	})
+	return s
+}
+
+func TestSlackService_editMessagesText_SkipsOtherMessages(t *testing.T) {
+	var historyTS atomic.Value
+	var updates atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/conversations.history"):
+			_, _ = w.Write([]byte(`{"ok":true,"messages":[{"type":"message","ts":"` + historyTS.Load().(string) +
+				`","text":"PR text"}]}`))
+		case strings.HasSuffix(r.URL.Path, "/chat.update"):
+			updates.Add(1)
+			_, _ = w.Write([]byte(`{"ok":true,"channel":"C1","ts":"2.2"}`))
+		default:
+			t.Errorf("unexpected Slack call %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	s := newTestSlackServiceForAPI(server.URL)
+	messages := []MessageRef{{Channel: "C1", Timestamp: "2.2"}}
+
+	historyTS.Store("1.1")
+	require.NoError(t, s.SetLifecycleStateText(context.Background(), "T1", messages, "merged"))
+	assert.Zero(t, updates.Load(), "an older message returned in place of a deleted one isn't edited")
+
+	historyTS.Store("2.2")
+	require.NoError(t, s.SetLifecycleStateText(context.Background(), "T1", messages, "merged"))
+	assert.Equal(t, int32(1), updates.Load())
+}
+
+func TestSlackService_IsChannelMember(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/conversations.members") {
+			t.Errorf("unexpected Slack call %s", r.URL.Path)
+			return
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("invalid request: %v", err)
+			return
+		}
+		switch r.Form.Get("channel") + "/" + r.Form.Get("cursor") {
+		case "C1/":
+			_, _ = w.Write([]byte(`{"ok":true,"members":["U1","U2"],"response_metadata":{"next_cursor":"page2"}}`))
+		case "C1/page2":
+			_, _ = w.Write([]byte(`{"ok":true,"members":["U3"],"response_metadata":{"next_cursor":""}}`))
+		case "CPRIVATE/":
+			_, _ = w.Write([]byte(`{"ok":false,"error":"channel_not_found"}`))
+		default:
+			_, _ = w.Write([]byte(`{"ok":false,"error":"internal_error"}`))
+		}
+	}))
+	defer server.Close()
+
+	s := newTestSlackServiceForAPI(server.URL)
+	ctx := context.Background()
+
+	isMember, err := s.IsChannelMember(ctx, "T1", "C1", "U1")
+	require.NoError(t, err)
+	assert.True(t, isMember)
+
+	isMember, err = s.IsChannelMember(ctx, "T1", "C1", "U3")
+	require.NoError(t, err)
+	assert.True(t, isMember, "members on later pages are found")
+
+	isMember, err = s.IsChannelMember(ctx, "T1", "C1", "U9")
+	require.NoError(t, err)
+	assert.False(t, isMember)
+
+	isMember, err = s.IsChannelMember(ctx, "T1", "CPRIVATE", "U1")
+	require.NoError(t, err)
+	assert.False(t, isMember, "private channels the bot isn't in can't be seen")
+
+	_, err = s.IsChannelMember(ctx, "T1", "CERROR", "U1")
+	require.Error(t, err)
+}
@@ -18,20 +18,25 @@ import (
 var (
 	ErrWorkspaceNotFound      = errors.New("workspace not found")
 	ErrWorkspaceNotInstalled  = errors.New("workspace not installed")
+	ErrWorkspaceRevoked       = errors.New("workspace token revoked")
 	ErrNoSlackClientAvailable = errors.New("no Slack client available")
+	ErrUserTokenNotFound      = errors.New("slack user token not found")
 )
 
 // SlackWorkspaceService manages Slack workspace installations and tokens.
 type SlackWorkspaceService struct {
 	client     *firestore.Client
+	encrypter  *TokenEncrypter                   // Encrypts stored tokens; nil stores them in plain text
 	tokenCache map[string]*models.SlackWorkspace // Cache workspace tokens by team ID
 	cacheMutex sync.RWMutex                      // Protects token cache
 }
 
 // NewSlackWorkspaceService creates a new SlackWorkspaceService.
-func NewSlackWorkspaceService(client *firestore.Client) *SlackWorkspaceService {
+// Bot and user tokens are encrypted with encrypter when they're saved, unless it's nil.
+func NewSlackWorkspaceService(client *firestore.Client, encrypter *TokenEncrypter) *SlackWorkspaceService {
 	return &SlackWorkspaceService{
 		client:     client,
+		encrypter:  encrypter,
 		tokenCache: make(map[string]*models.SlackWorkspace),
 	}
 }
@@ -44,8 +49,16 @@ func (sws *SlackWorkspaceService) SaveWorkspace(ctx context.Context, workspace *
 
 	workspace.UpdatedAt = time.Now()
 
+	// The cached workspace keeps the plain text token
+	stored := *workspace
+	accessToken, err := sws.encrypter.Encrypt(ctx, workspace.AccessToken)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt workspace token: %w", err)
+	}
+	stored.AccessToken = accessToken
+
 	// Save to Firestore using team ID as document ID
-	_, err := sws.client.Collection("slack_workspaces").Doc(workspace.ID).Set(ctx, workspace)
+	_, err = sws.client.Collection("slack_workspaces").Doc(workspace.ID).Set(ctx, &stored)
 	if err != nil {
 		log.Error(ctx, "Failed to save workspace",
 			"error", err,
@@ -103,6 +116,14 @@ func (sws *SlackWorkspaceService) GetWorkspace(ctx context.Context, teamID strin
 		)
 		return nil, fmt.Errorf("failed to decode workspace: %w", err)
 	}
+	if workspace.AccessToken, err = sws.encrypter.Decrypt(ctx, workspace.AccessToken); err != nil {
+		log.Error(ctx, "Failed to decrypt workspace token",
+			"error", err,
+			"team_id", teamID,
+			"operation", "decrypt_workspace_token",
+		)
+		return nil, fmt.Errorf("failed to decrypt workspace token: %w", err)
+	}
 
 	// Update cache
 	sws.cacheMutex.Lock()
@@ -145,6 +166,175 @@ func (sws *SlackWorkspaceService) DeleteWorkspace(ctx context.Context, teamID st
 	return nil
 }
 
+// UpdateWorkspaceLocale sets the timezone and locale used for a workspace's digests, countdowns and dates.
+func (sws *SlackWorkspaceService) UpdateWorkspaceLocale(ctx context.Context, teamID, timezone, locale string) error {
+	_, err := sws.client.Collection("slack_workspaces").Doc(teamID).Update(ctx, []firestore.Update{
+		{Path: "timezone", Value: timezone},
+		{Path: "locale", Value: locale},
+		{Path: "updated_at", Value: time.Now()},
+	})
+	if err != nil {
+		log.Error(ctx, "Failed to update workspace locale",
+			"error", err,
+			"team_id", teamID,
+			"operation", "update_workspace_locale",
+		)
+		return fmt.Errorf("failed to update workspace locale: %w", err)
+	}
+
+	// Reload on next access
+	sws.cacheMutex.Lock()
+	delete(sws.tokenCache, teamID)
+	sws.cacheMutex.Unlock()
+
+	log.Info(ctx, "Workspace locale updated",
+		"team_id", teamID,
+		"timezone", timezone,
+		"locale", locale,
+	)
+	return nil
+}
+
+// UpdateWorkspaceLinkInvites sets how a workspace invites CC'd GitHub users without a linked Slack account to link it.
+func (sws *SlackWorkspaceService) UpdateWorkspaceLinkInvites(ctx context.Context, teamID, mode string) error {
+	_, err := sws.client.Collection("slack_workspaces").Doc(teamID).Update(ctx, []firestore.Update{
+		{Path: "link_invites", Value: mode},
+		{Path: "updated_at", Value: time.Now()},
+	})
+	if err != nil {
+		log.Error(ctx, "Failed to update workspace link invites",
+			"error", err,
+			"team_id", teamID,
+			"operation", "update_workspace_link_invites",
+		)
+		return fmt.Errorf("failed to update workspace link invites: %w", err)
+	}
+
+	// Reload on next access
+	sws.cacheMutex.Lock()
+	delete(sws.tokenCache, teamID)
+	sws.cacheMutex.Unlock()
+
+	log.Info(ctx, "Workspace link invites updated",
+		"team_id", teamID,
+		"link_invites", mode,
+	)
+	return nil
+}
+
+// UpdateWorkspaceSlackToGitHubComments sets whether a workspace posts "!to-github" thread replies as PR comments.
+func (sws *SlackWorkspaceService) UpdateWorkspaceSlackToGitHubComments(ctx context.Context, teamID string, enabled bool) error {
+	_, err := sws.client.Collection("slack_workspaces").Doc(teamID).Update(ctx, []firestore.Update{
+		{Path: "slack_to_github_comments", Value: enabled},
+		{Path: "updated_at", Value: time.Now()},
+	})
+	if err != nil {
+		log.Error(ctx, "Failed to update workspace Slack to GitHub comments",
+			"error", err,
+			"team_id", teamID,
+			"operation", "update_workspace_slack_to_github_comments",
+		)
+		return fmt.Errorf("failed to update workspace Slack to GitHub comments: %w", err)
+	}
+
+	// Reload on next access
+	sws.cacheMutex.Lock()
+	delete(sws.tokenCache, teamID)
+	sws.cacheMutex.Unlock()
+
+	log.Info(ctx, "Workspace Slack to GitHub comments updated",
+		"team_id", teamID,
+		"enabled", enabled,
+	)
+	return nil
+}
+
+// UpdateWorkspaceReactionEmoji sets the review state reaction emoji a workspace overrides; nil clears them.
+func (sws *SlackWorkspaceService) UpdateWorkspaceReactionEmoji(ctx context.Context, teamID string, emoji *models.WorkspaceEmoji) error {
+	var value interface{} = firestore.Delete
+	if emoji != nil {
+		value = emoji
+	}
+	_, err := sws.client.Collection("slack_workspaces").Doc(teamID).Update(ctx, []firestore.Update{
+		{Path: "reaction_emoji", Value: value},
+		{Path: "updated_at", Value: time.Now()},
+	})
+	if err != nil {
+		log.Error(ctx, "Failed to update workspace reaction emoji",
+			"error", err,
+			"team_id", teamID,
+			"operation", "update_workspace_reaction_emoji",
+		)
+		return fmt.Errorf("failed to update workspace reaction emoji: %w", err)
+	}
+
+	// Reload on next access
+	sws.cacheMutex.Lock()
+	delete(sws.tokenCache, teamID)
+	sws.cacheMutex.Unlock()
+
+	log.Info(ctx, "Workspace reaction emoji updated", "team_id", teamID, "cleared", emoji == nil)
+	return nil
+}
+
+// UpdateWorkspaceMessageTemplate sets the template a workspace's PR messages are laid out with; empty clears it.
+func (sws *SlackWorkspaceService) UpdateWorkspaceMessageTemplate(ctx context.Context, teamID, messageTemplate string) error {
+	var value interface{} = firestore.Delete
+	if messageTemplate != "" {
+		value = messageTemplate
+	}
+	_, err := sws.client.Collection("slack_workspaces").Doc(teamID).Update(ctx, []firestore.Update{
+		{Path: "message_template", Value: value},
+		{Path: "updated_at", Value: time.Now()},
+	})
+	if err != nil {
+		log.Error(ctx, "Failed to update workspace message template",
+			"error", err,
+			"team_id", teamID,
+			"operation", "update_workspace_message_template",
+		)
+		return fmt.Errorf("failed to update workspace message template: %w", err)
+	}
+
+	// Reload on next access
+	sws.cacheMutex.Lock()
+	delete(sws.tokenCache, teamID)
+	sws.cacheMutex.Unlock()
+
+	log.Info(ctx, "Workspace message template updated", "team_id", teamID, "cleared", messageTemplate == "")
+	return nil
+}
+
+// UpdateWorkspacePRSizeConfig sets the PR size emoji used across a workspace; nil clears it so the defaults are used.
+func (sws *SlackWorkspaceService) UpdateWorkspacePRSizeConfig(
+	ctx context.Context, teamID string, prSizeConfig *models.PRSizeConfiguration,
+) error {
+	var value interface{} = firestore.Delete
+	if prSizeConfig != nil {
+		value = prSizeConfig
+	}
+	_, err := sws.client.Collection("slack_workspaces").Doc(teamID).Update(ctx, []firestore.Update{
+		{Path: "pr_size_config", Value: value},
+		{Path: "updated_at", Value: time.Now()},
+	})
+	if err != nil {
+		log.Error(ctx, "Failed to update workspace PR size emoji",
+			"error", err,
+			"team_id", teamID,
+			"operation", "update_workspace_pr_size_config",
+		)
+		return fmt.Errorf("failed to update workspace PR size config: %w", err)
+	}
+
+	// Reload on next access
+	sws.cacheMutex.Lock()
+	delete(sws.tokenCache, teamID)
+	sws.cacheMutex.Unlock()
+
+	log.Info(ctx, "Workspace PR size emoji updated", "team_id", teamID, "cleared", prSizeConfig == nil)
+	return nil
+}
+
 // ListWorkspaces returns all installed workspaces.
 func (sws *SlackWorkspaceService) ListWorkspaces(ctx context.Context) ([]*models.SlackWorkspace, error) {
 	iter := sws.client.Collection("slack_workspaces").Documents(ctx)
@@ -173,6 +363,14 @@ func (sws *SlackWorkspaceService) ListWorkspaces(ctx context.Context) ([]*models
 			)
 			continue
 		}
+		if workspace.AccessToken, err = sws.encrypter.Decrypt(ctx, workspace.AccessToken); err != nil {
+			log.Error(ctx, "Failed to decrypt workspace token",
+				"error", err,
+				"doc_id", doc.Ref.ID,
+				"operation", "decode_workspace_list",
+			)
+			continue
+		}
 
 		workspaces = append(workspaces, &workspace)
 	}
@@ -191,3 +389,72 @@ func (sws *SlackWorkspaceService) IsWorkspaceInstalled(ctx context.Context, team
 	}
 	return true, nil
 }
+
+// userTokenDocID returns the slack_user_tokens document ID for a user in a workspace.
+func userTokenDocID(teamID, userID string) string {
+	return teamID + "#" + userID
+}
+
+// SaveUserToken stores a Slack user token granted for posting PR notifications as the user.
+func (sws *SlackWorkspaceService) SaveUserToken(ctx context.Context, token *models.SlackUserToken) error {
+	token.ID = userTokenDocID(token.SlackTeamID, token.SlackUserID)
+	token.CreatedAt = time.Now()
+
+	stored := *token
+	accessToken, err := sws.encrypter.Encrypt(ctx, token.AccessToken)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt Slack user token: %w", err)
+	}
+	stored.AccessToken = accessToken
+
+	_, err = sws.client.Collection("slack_user_tokens").Doc(token.ID).Set(ctx, &stored)
+	if err != nil {
+		log.Error(ctx, "Failed to save Slack user token",
+			"error", err,
+			"team_id", token.SlackTeamID,
+			"user_id", token.SlackUserID,
+			"operation", "save_user_token",
+		)
+		return fmt.Errorf("failed to save Slack user token: %w", err)
+	}
+
+	return nil
+}
+
+// GetUserToken retrieves the Slack user token a user granted in a workspace.
+func (sws *SlackWorkspaceService) GetUserToken(ctx context.Context, teamID, userID string) (string, error) {
+	doc, err := sws.client.Collection("slack_user_tokens").Doc(userTokenDocID(teamID, userID)).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return "", ErrUserTokenNotFound
+		}
+		return "", fmt.Errorf("failed to get Slack user token: %w", err)
+	}
+
+	var token models.SlackUserToken
+	if err := doc.DataTo(&token); err != nil {
+		return "", fmt.Errorf("failed to decode Slack user token: %w", err)
+	}
+
+	accessToken, err := sws.encrypter.Decrypt(ctx, token.AccessToken)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt Slack user token: %w", err)
+	}
+	return accessToken, nil
+}
+
+// DeleteUserToken removes a user's stored Slack user token. Deleting a missing token is not an error.
+func (sws *SlackWorkspaceService) DeleteUserToken(ctx context.Context, teamID, userID string) error {
+	_, err := sws.client.Collection("slack_user_tokens").Doc(userTokenDocID(teamID, userID)).Delete(ctx)
+	if err != nil && status.Code(err) != codes.NotFound {
+		log.Error(ctx, "Failed to delete Slack user token",
+			"error", err,
+			"team_id", teamID,
+			"user_id", userID,
+			"operation", "delete_user_token",
+		)
+		return fmt.Errorf("failed to delete Slack user token: %w", err)
+	}
+
+	return nil
+}
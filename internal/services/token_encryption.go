@@ -0,0 +1,246 @@
+package services
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github-slack-notifier/internal/config"
+)
+
+const (
+	// encryptedTokenPrefix marks tokens encrypted with the local TOKEN_ENCRYPTION_KEY.
+	encryptedTokenPrefix = "enc:v1:"
+	// envelopeTokenPrefix marks tokens encrypted with a data key wrapped by Cloud KMS. It's followed by the KMS key
+	// version that wrapped the data key, the wrapped data key and the encrypted token, separated by colons.
+	envelopeTokenPrefix = "enc:kms:"
+	// envelopeTokenParts is the number of colon-separated fields after envelopeTokenPrefix.
+	envelopeTokenParts = 3
+)
+
+// TokenEncryptionKeySize is the size of the AES-256 keys tokens are encrypted with.
+const TokenEncryptionKeySize = 32
+
+// LocalTokenKeyVersion is the key version reported for tokens encrypted with the local TOKEN_ENCRYPTION_KEY.
+const LocalTokenKeyVersion = "local"
+
+var (
+	// ErrInvalidTokenEncryptionKey is returned for keys that aren't base64 encoded 32 byte keys.
+	ErrInvalidTokenEncryptionKey = errors.New("token encryption key must be 32 bytes, base64 encoded")
+	// ErrTokenEncryptionKeyMissing is returned when reading an encrypted token without its key configured.
+	ErrTokenEncryptionKeyMissing = errors.New("token is encrypted but its key isn't configured")
+	// ErrMalformedEncryptedToken is returned for encrypted tokens that can't be decoded or fail authentication.
+	ErrMalformedEncryptedToken = errors.New("malformed encrypted token")
+)
+
+// KeyWrapper wraps the data keys tokens are encrypted with, using a key management service.
+type KeyWrapper interface {
+	// WrapKey encrypts a data key, returning it with the name of the key version that encrypted it.
+	WrapKey(ctx context.Context, dataKey []byte) (wrapped []byte, keyVersion string, err error)
+	// UnwrapKey decrypts a data key encrypted by WrapKey.
+	UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error)
+}
+
+// TokenEncrypter encrypts the OAuth tokens stored in Firestore with AES-256-GCM.
+// With a KeyWrapper, each token is encrypted with its own data key, which is stored wrapped by Cloud KMS alongside
+// it (envelope encryption). Otherwise tokens are encrypted with the local key. Tokens encrypted either way, and tokens
+// stored in plain text before encryption was turned on, can all be read. A nil TokenEncrypter stores plain text.
+type TokenEncrypter struct {
+	local   cipher.AEAD // Encrypts with TOKEN_ENCRYPTION_KEY; nil when it isn't set
+	wrapper KeyWrapper  // Wraps data keys with Cloud KMS; nil when no KMS key is set
+
+	mu       sync.Mutex
+	dataKeys map[string]cipher.AEAD // Unwrapped data keys by wrapped key, so reads don't call KMS every time
+}
+
+// NewTokenEncrypter creates a TokenEncrypter from a base64 encoded AES-256 key and a KMS key wrapper, either of
+// which can be empty. New tokens are encrypted with KMS when there's a wrapper. Returns nil if there's neither.
+func NewTokenEncrypter(key string, wrapper KeyWrapper) (*TokenEncrypter, error) {
+	if key == "" && wrapper == nil {
+		return nil, nil
+	}
+
+	encrypter := &TokenEncrypter{wrapper: wrapper, dataKeys: make(map[string]cipher.AEAD)}
+	if key != "" {
+		decoded, err := base64.StdEncoding.DecodeString(key)
+		if err != nil || len(decoded) != TokenEncryptionKeySize {
+			return nil, ErrInvalidTokenEncryptionKey
+		}
+		if encrypter.local, err = newTokenAEAD(decoded); err != nil {
+			return nil, err
+		}
+	}
+	return encrypter, nil
+}
+
+// NewTokenEncrypterFromConfig creates the TokenEncrypter for the configured local key and Cloud KMS key.
+// Returns nil if neither is set.
+func NewTokenEncrypterFromConfig(ctx context.Context, cfg *config.Config) (*TokenEncrypter, error) {
+	var wrapper KeyWrapper
+	if cfg.TokenKMSKey != "" {
+		kmsWrapper, err := NewKMSKeyWrapper(ctx, cfg.TokenKMSKey)
+		if err != nil {
+			return nil, err
+		}
+		wrapper = kmsWrapper
+	}
+	return NewTokenEncrypter(cfg.TokenEncryptionKey, wrapper)
+}
+
+// newTokenAEAD creates the AES-256-GCM cipher for a key.
+func newTokenAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token cipher: %w", err)
+	}
+	return aead, nil
+}
+
+// Encrypt returns the token encrypted for storage, or the token unchanged when e is nil.
+func (e *TokenEncrypter) Encrypt(ctx context.Context, token string) (string, error) {
+	if e == nil || token == "" {
+		return token, nil
+	}
+	if e.wrapper == nil {
+		sealed, err := seal(e.local, token)
+		if err != nil {
+			return "", err
+		}
+		return encryptedTokenPrefix + sealed, nil
+	}
+
+	dataKey := make([]byte, TokenEncryptionKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", fmt.Errorf("failed to generate data key: %w", err)
+	}
+	wrapped, keyVersion, err := e.wrapper.WrapKey(ctx, dataKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to wrap data key: %w", err)
+	}
+	aead, err := newTokenAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := seal(aead, token)
+	if err != nil {
+		return "", err
+	}
+	return envelopeTokenPrefix + keyVersion + ":" + base64.StdEncoding.EncodeToString(wrapped) + ":" + sealed, nil
+}
+
+// Decrypt returns the plain text of a stored token. Tokens stored unencrypted are returned unchanged.
+func (e *TokenEncrypter) Decrypt(ctx context.Context, stored string) (string, error) {
+	if encoded, ok := strings.CutPrefix(stored, encryptedTokenPrefix); ok {
+		if e == nil || e.local == nil {
+			return "", ErrTokenEncryptionKeyMissing
+		}
+		return open(e.local, encoded)
+	}
+
+	envelope, ok := strings.CutPrefix(stored, envelopeTokenPrefix)
+	if !ok {
+		return stored, nil
+	}
+	if e == nil || e.wrapper == nil {
+		return "", ErrTokenEncryptionKeyMissing
+	}
+	parts := strings.Split(envelope, ":")
+	if len(parts) != envelopeTokenParts {
+		return "", ErrMalformedEncryptedToken
+	}
+	aead, err := e.dataKey(ctx, parts[1])
+	if err != nil {
+		return "", err
+	}
+	return open(aead, parts[2])
+}
+
+// dataKey returns the cipher for a wrapped data key, unwrapping it with KMS the first time it's seen.
+func (e *TokenEncrypter) dataKey(ctx context.Context, encodedWrapped string) (cipher.AEAD, error) {
+	e.mu.Lock()
+	aead, ok := e.dataKeys[encodedWrapped]
+	e.mu.Unlock()
+	if ok {
+		return aead, nil
+	}
+
+	wrapped, err := base64.StdEncoding.DecodeString(encodedWrapped)
+	if err != nil {
+		return nil, ErrMalformedEncryptedToken
+	}
+	dataKey, err := e.wrapper.UnwrapKey(ctx, wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+	if aead, err = newTokenAEAD(dataKey); err != nil {
+		return nil, err
+	}
+
+	e.mu.Lock()
+	e.dataKeys[encodedWrapped] = aead
+	e.mu.Unlock()
+	return aead, nil
+}
+
+// CurrentKeyVersion returns the key version new tokens are encrypted with: the primary version of the KMS key,
+// found by wrapping a throwaway data key, or LocalTokenKeyVersion. Returns "" when e is nil.
+func (e *TokenEncrypter) CurrentKeyVersion(ctx context.Context) (string, error) {
+	switch {
+	case e == nil:
+		return "", nil
+	case e.wrapper == nil:
+		return LocalTokenKeyVersion, nil
+	}
+
+	_, keyVersion, err := e.wrapper.WrapKey(ctx, make([]byte, TokenEncryptionKeySize))
+	if err != nil {
+		return "", fmt.Errorf("failed to find current KMS key version: %w", err)
+	}
+	return keyVersion, nil
+}
+
+// StoredTokenKeyVersion returns the key version a stored token is encrypted with: the KMS key version for envelope
+// encrypted tokens, LocalTokenKeyVersion for tokens encrypted with the local key, or "" for plain text.
+func StoredTokenKeyVersion(stored string) string {
+	if strings.HasPrefix(stored, encryptedTokenPrefix) {
+		return LocalTokenKeyVersion
+	}
+	if envelope, ok := strings.CutPrefix(stored, envelopeTokenPrefix); ok {
+		keyVersion, _, _ := strings.Cut(envelope, ":")
+		return keyVersion
+	}
+	return ""
+}
+
+// seal encrypts plain text with a fresh nonce, returning the base64 encoded nonce and ciphertext.
+func seal(aead cipher.AEAD, plaintext string) (string, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate token nonce: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(aead.Seal(nonce, nonce, []byte(plaintext), nil)), nil
+}
+
+// open decrypts the output of seal.
+func open(aead cipher.AEAD, encoded string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", ErrMalformedEncryptedToken
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", ErrMalformedEncryptedToken
+	}
+	return string(plaintext), nil
+}
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testTokenEncrypter(t *testing.T, keyByte byte) *TokenEncrypter {
+	t.Helper()
+	key := base64.StdEncoding.EncodeToString([]byte(strings.Repeat(string(keyByte), TokenEncryptionKeySize)))
+	encrypter, err := NewTokenEncrypter(key, nil)
+	require.NoError(t, err)
+	require.NotNil(t, encrypter)
+	return encrypter
+}
+
+// fakeKeyWrapper wraps data keys by XORing them with a per-version byte, counting unwraps.
+type fakeKeyWrapper struct {
+	version string
+	keys    map[string]byte
+	unwraps int
+}
+
+func newFakeKeyWrapper(version string) *fakeKeyWrapper {
+	return &fakeKeyWrapper{version: version, keys: map[string]byte{"v1": 0x11, "v2": 0x22}}
+}
+
+func (w *fakeKeyWrapper) WrapKey(_ context.Context, dataKey []byte) ([]byte, string, error) {
+	return append([]byte{w.keys[w.version]}, xorBytes(dataKey, w.keys[w.version])...), w.version, nil
+}
+
+func (w *fakeKeyWrapper) UnwrapKey(_ context.Context, wrapped []byte) ([]byte, error) {
+	w.unwraps++
+	if len(wrapped) == 0 {
+		return nil, ErrMalformedEncryptedToken
+	}
+	return xorBytes(wrapped[1:], wrapped[0]), nil
+}
+
+func xorBytes(data []byte, key byte) []byte {
+	out := make([]byte, len(data))
+	for i, b := range data {
+		out[i] = b ^ key
+	}
+	return out
+}
+
+func TestNewTokenEncrypter(t *testing.T) {
+	encrypter, err := NewTokenEncrypter("", nil)
+	require.NoError(t, err)
+	assert.Nil(t, encrypter)
+
+	_, err = NewTokenEncrypter("not base64!", nil)
+	require.ErrorIs(t, err, ErrInvalidTokenEncryptionKey)
+
+	_, err = NewTokenEncrypter(base64.StdEncoding.EncodeToString([]byte("too short")), nil)
+	require.ErrorIs(t, err, ErrInvalidTokenEncryptionKey)
+}
+
+func TestTokenEncrypter_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	encrypter := testTokenEncrypter(t, 'k')
+
+	stored, err := encrypter.Encrypt(ctx, "xoxb-secret")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(stored, encryptedTokenPrefix))
+	assert.NotContains(t, stored, "xoxb-secret")
+
+	// Each encryption uses a fresh nonce
+	again, err := encrypter.Encrypt(ctx, "xoxb-secret")
+	require.NoError(t, err)
+	assert.NotEqual(t, stored, again)
+
+	token, err := encrypter.Decrypt(ctx, stored)
+	require.NoError(t, err)
+	assert.Equal(t, "xoxb-secret", token)
+}
+
+func TestTokenEncrypter_PlainTextTokens(t *testing.T) {
+	ctx := context.Background()
+	var disabled *TokenEncrypter
+
+	stored, err := disabled.Encrypt(ctx, "xoxb-secret")
+	require.NoError(t, err)
+	assert.Equal(t, "xoxb-secret", stored)
+
+	// Tokens saved before encryption was turned on are still readable
+	token, err := testTokenEncrypter(t, 'k').Decrypt(ctx, "xoxb-legacy")
+	require.NoError(t, err)
+	assert.Equal(t, "xoxb-legacy", token)
+}
+
+func TestTokenEncrypter_DecryptErrors(t *testing.T) {
+	ctx := context.Background()
+	stored, err := testTokenEncrypter(t, 'k').Encrypt(ctx, "xoxb-secret")
+	require.NoError(t, err)
+
+	var disabled *TokenEncrypter
+	_, err = disabled.Decrypt(ctx, stored)
+	require.ErrorIs(t, err, ErrTokenEncryptionKeyMissing)
+
+	_, err = testTokenEncrypter(t, 'x').Decrypt(ctx, stored)
+	require.ErrorIs(t, err, ErrMalformedEncryptedToken)
+
+	_, err = testTokenEncrypter(t, 'k').Decrypt(ctx, encryptedTokenPrefix+"!!!")
+	require.ErrorIs(t, err, ErrMalformedEncryptedToken)
+}
+
+func TestTokenEncrypter_Envelope(t *testing.T) {
+	ctx := context.Background()
+	wrapper := newFakeKeyWrapper("v1")
+	encrypter, err := NewTokenEncrypter("", wrapper)
+	require.NoError(t, err)
+
+	stored, err := encrypter.Encrypt(ctx, "xoxb-secret")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(stored, envelopeTokenPrefix+"v1:"))
+	assert.NotContains(t, stored, "xoxb-secret")
+	assert.Equal(t, "v1", StoredTokenKeyVersion(stored))
+
+	// Data keys are unwrapped once, then cached
+	for range 2 {
+		token, err := encrypter.Decrypt(ctx, stored)
+		require.NoError(t, err)
+		assert.Equal(t, "xoxb-secret", token)
+	}
+	assert.Equal(t, 1, wrapper.unwraps)
+
+	// Tokens wrapped by an older key version still decrypt after the key is rotated
+	wrapper.version = "v2"
+	keyVersion, err := encrypter.CurrentKeyVersion(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "v2", keyVersion)
+	token, err := encrypter.Decrypt(ctx, stored)
+	require.NoError(t, err)
+	assert.Equal(t, "xoxb-secret", token)
+}
+
+func TestTokenEncrypter_EnvelopeReadsLocalTokens(t *testing.T) {
+	ctx := context.Background()
+	key := base64.StdEncoding.EncodeToString([]byte(strings.Repeat("k", TokenEncryptionKeySize)))
+
+	local := testTokenEncrypter(t, 'k')
+	stored, err := local.Encrypt(ctx, "xoxb-secret")
+	require.NoError(t, err)
+	assert.Equal(t, LocalTokenKeyVersion, StoredTokenKeyVersion(stored))
+
+	encrypter, err := NewTokenEncrypter(key, newFakeKeyWrapper("v1"))
+	require.NoError(t, err)
+	token, err := encrypter.Decrypt(ctx, stored)
+	require.NoError(t, err)
+	assert.Equal(t, "xoxb-secret", token)
+
+	// Without the local key, tokens encrypted with it can't be read
+	kmsOnly, err := NewTokenEncrypter("", newFakeKeyWrapper("v1"))
+	require.NoError(t, err)
+	_, err = kmsOnly.Decrypt(ctx, stored)
+	require.ErrorIs(t, err, ErrTokenEncryptionKeyMissing)
+
+	// And envelope encrypted tokens need the KMS key
+	envelope, err := kmsOnly.Encrypt(ctx, "xoxb-secret")
+	require.NoError(t, err)
+	_, err = local.Decrypt(ctx, envelope)
+	require.ErrorIs(t, err, ErrTokenEncryptionKeyMissing)
+	_, err = kmsOnly.Decrypt(ctx, envelopeTokenPrefix+"v1:missing-parts")
+	require.ErrorIs(t, err, ErrMalformedEncryptedToken)
+}
+
+func TestStoredTokenKeyVersion(t *testing.T) {
+	assert.Empty(t, StoredTokenKeyVersion("xoxb-plain"))
+	assert.Equal(t, LocalTokenKeyVersion, StoredTokenKeyVersion(encryptedTokenPrefix+"abc"))
+	assert.Equal(t, "projects/p/locations/l/keyRings/r/cryptoKeys/k/cryptoKeyVersions/3",
+		StoredTokenKeyVersion(envelopeTokenPrefix+"projects/p/locations/l/keyRings/r/cryptoKeys/k/cryptoKeyVersions/3:a:b"))
+}
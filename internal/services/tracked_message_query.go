@@ -0,0 +1,280 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+
+	"github-slack-notifier/internal/log"
+	"github-slack-notifier/internal/models"
+)
+
+const (
+	// DefaultTrackedMessagePageSize is the page size used when a paginated query sets no limit.
+	DefaultTrackedMessagePageSize = 100
+	// MaxTrackedMessagePageSize is the largest page a paginated query can request.
+	MaxTrackedMessagePageSize = 500
+)
+
+// Tracked message states for TrackedMessageQuery.State.
+// They're filtered after reading, since the underlying fields are omitted when false.
+const (
+	TrackedMessageStateAny        = ""           // All messages (default)
+	TrackedMessageStateActive     = "active"     // Not deleted by a user and not superseded by another PR
+	TrackedMessageStateDeleted    = "deleted"    // Deleted by a user
+	TrackedMessageStateSuperseded = "superseded" // Replaced by another PR
+)
+
+// Tracked message orderings for TrackedMessageQuery.Order.
+const (
+	TrackedMessageOrderDocumentID  = ""             // Stable document order, which needs no composite index (default)
+	TrackedMessageOrderOldestFirst = "oldest_first" // By creation time, oldest first
+	TrackedMessageOrderNewestFirst = "newest_first" // By creation time, newest first
+)
+
+var (
+	// ErrInvalidTrackedMessageQuery is returned for tracked message queries that can't be run.
+	ErrInvalidTrackedMessageQuery = errors.New("invalid tracked message query")
+	// ErrInvalidPageToken is returned when a page token is malformed or came from a differently ordered query.
+	ErrInvalidPageToken = errors.New("invalid page token")
+)
+
+// TrackedMessageQuery selects tracked messages. Empty fields don't filter.
+// Either RepoFullName or SlackTeamID must be set, so queries never scan the whole collection.
+type TrackedMessageQuery struct {
+	RepoFullName  string
+	PRNumber      int // Requires RepoFullName
+	SlackTeamID   string
+	SlackChannel  string
+	MessageSource string // models.MessageSourceBot or models.MessageSourceManual
+	State         string // One of the TrackedMessageState constants
+
+	CreatedAfter  time.Time // Inclusive
+	CreatedBefore time.Time // Exclusive
+
+	// Order is one of the TrackedMessageOrder constants. Creation time filters need a creation time
+	// ordering, so they default to oldest first.
+	Order string
+
+	// Limit caps the number of messages returned. QueryTrackedMessages defaults to DefaultTrackedMessagePageSize,
+	// and GetTrackedMessages returns every match when it's 0.
+	Limit int
+	// PageToken continues a paginated query from the NextPageToken of the previous page.
+	PageToken string
+}
+
+// TrackedMessagePage is a page of tracked messages from QueryTrackedMessages.
+type TrackedMessagePage struct {
+	Messages []*models.TrackedMessage `json:"messages"`
+	// NextPageToken fetches the next page, and is empty on the last page.
+	// A page filtered by State can occasionally be followed by an empty last page.
+	NextPageToken string `json:"next_page_token,omitempty"`
+}
+
+// trackedMessageCursor is the decoded form of a page token: the position of the last document read.
+type trackedMessageCursor struct {
+	Order     string    `json:"o"`
+	CreatedAt time.Time `json:"c,omitempty"`
+	DocID     string    `json:"d"`
+}
+
+// validate checks the query can be run, defaulting the ordering for creation time filters.
+func (q *TrackedMessageQuery) validate() error {
+	if q.RepoFullName == "" && q.SlackTeamID == "" {
+		return fmt.Errorf("%w: a repository or workspace is required", ErrInvalidTrackedMessageQuery)
+	}
+	if q.PRNumber != 0 && q.RepoFullName == "" {
+		return fmt.Errorf("%w: a PR number requires a repository", ErrInvalidTrackedMessageQuery)
+	}
+	if q.Limit < 0 {
+		return fmt.Errorf("%w: limit can't be negative", ErrInvalidTrackedMessageQuery)
+	}
+
+	switch q.State {
+	case TrackedMessageStateAny, TrackedMessageStateActive, TrackedMessageStateDeleted, TrackedMessageStateSuperseded:
+	default:
+		return fmt.Errorf("%w: unknown state %q", ErrInvalidTrackedMessageQuery, q.State)
+	}
+
+	switch q.Order {
+	case TrackedMessageOrderDocumentID:
+		if !q.CreatedAfter.IsZero() || !q.CreatedBefore.IsZero() {
+			q.Order = TrackedMessageOrderOldestFirst
+		}
+	case TrackedMessageOrderOldestFirst, TrackedMessageOrderNewestFirst:
+	default:
+		return fmt.Errorf("%w: unknown order %q", ErrInvalidTrackedMessageQuery, q.Order)
+	}
+	return nil
+}
+
+// matchesState reports whether a message is in the query's state.
+func (q *TrackedMessageQuery) matchesState(msg *models.TrackedMessage) bool {
+	switch q.State {
+	case TrackedMessageStateActive:
+		return !msg.DeletedByUser && msg.SupersededByPR == 0
+	case TrackedMessageStateDeleted:
+		return msg.DeletedByUser
+	case TrackedMessageStateSuperseded:
+		return msg.SupersededByPR != 0
+	default:
+		return true
+	}
+}
+
+// build converts the query to a Firestore query, starting after the cursor if there is one.
+func (q *TrackedMessageQuery) build(collection *firestore.CollectionRef, cursor *trackedMessageCursor) firestore.Query {
+	query := collection.Query
+	if q.RepoFullName != "" {
+		query = query.Where("repo_full_name", "==", q.RepoFullName)
+	}
+	if q.PRNumber != 0 {
+		query = query.Where("pr_number", "==", q.PRNumber)
+	}
+	if q.SlackTeamID != "" {
+		query = query.Where("slack_team_id", "==", q.SlackTeamID)
+	}
+	if q.SlackChannel != "" {
+		query = query.Where("slack_channel", "==", q.SlackChannel)
+	}
+	if q.MessageSource != "" {
+		query = query.Where("message_source", "==", q.MessageSource)
+	}
+	if !q.CreatedAfter.IsZero() {
+		query = query.Where("created_at", ">=", q.CreatedAfter)
+	}
+	if !q.CreatedBefore.IsZero() {
+		query = query.Where("created_at", "<", q.CreatedBefore)
+	}
+
+	switch q.Order {
+	case TrackedMessageOrderOldestFirst:
+		query = query.OrderBy("created_at", firestore.Asc).OrderBy(firestore.DocumentID, firestore.Asc)
+		if cursor != nil {
+			query = query.StartAfter(cursor.CreatedAt, cursor.DocID)
+		}
+	case TrackedMessageOrderNewestFirst:
+		query = query.OrderBy("created_at", firestore.Desc).OrderBy(firestore.DocumentID, firestore.Desc)
+		if cursor != nil {
+			query = query.StartAfter(cursor.CreatedAt, cursor.DocID)
+		}
+	default:
+		query = query.OrderBy(firestore.DocumentID, firestore.Asc)
+		if cursor != nil {
+			query = query.StartAfter(cursor.DocID)
+		}
+	}
+	return query
+}
+
+// encodePageToken encodes the position of the last document read as an opaque page token.
+func encodePageToken(cursor *trackedMessageCursor) string {
+	data, _ := json.Marshal(cursor) //nolint:errchkjson // A struct of strings and a time always marshals
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodePageToken decodes a page token, checking it was issued for the same ordering.
+func decodePageToken(token, order string) (*trackedMessageCursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidPageToken
+	}
+	var cursor trackedMessageCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.DocID == "" || cursor.Order != order {
+		return nil, ErrInvalidPageToken
+	}
+	return &cursor, nil
+}
+
+// QueryTrackedMessages returns a page of tracked messages matching the query.
+// Pass the returned NextPageToken as the next query's PageToken to continue.
+func (fs *FirestoreService) QueryTrackedMessages(ctx context.Context, query TrackedMessageQuery) (*TrackedMessagePage, error) {
+	if query.Limit == 0 {
+		query.Limit = DefaultTrackedMessagePageSize
+	}
+	if query.Limit > MaxTrackedMessagePageSize {
+		return nil, fmt.Errorf("%w: limit can't exceed %d", ErrInvalidTrackedMessageQuery, MaxTrackedMessagePageSize)
+	}
+	return fs.runTrackedMessageQuery(ctx, &query)
+}
+
+// GetTrackedMessages returns the tracked messages matching the query, up to its limit if it has one.
+// Most callers look up a single PR's messages, which are few enough to read in one go.
+func (fs *FirestoreService) GetTrackedMessages(ctx context.Context, query TrackedMessageQuery) ([]*models.TrackedMessage, error) {
+	page, err := fs.runTrackedMessageQuery(ctx, &query)
+	if err != nil {
+		return nil, err
+	}
+	return page.Messages, nil
+}
+
+// runTrackedMessageQuery reads tracked messages matching the query until its limit is reached, if it has one.
+// State filters are applied while reading, so the next page token points past everything already read.
+func (fs *FirestoreService) runTrackedMessageQuery(ctx context.Context, query *TrackedMessageQuery) (*TrackedMessagePage, error) {
+	if err := query.validate(); err != nil {
+		return nil, err
+	}
+	cursor, err := decodePageToken(query.PageToken, query.Order)
+	if err != nil {
+		return nil, err
+	}
+
+	iter := query.build(fs.client.Collection("trackedmessages"), cursor).Documents(ctx)
+	defer iter.Stop()
+
+	page := &TrackedMessagePage{}
+	var last *trackedMessageCursor
+	for {
+		doc, err := iter.Next()
+		if err != nil {
+			if errors.Is(err, iterator.Done) {
+				return page, nil
+			}
+			log.Error(ctx, "Failed to query tracked messages",
+				"error", err,
+				"repo", query.RepoFullName,
+				"pr_number", query.PRNumber,
+				"slack_channel", query.SlackChannel,
+				"slack_team_id", query.SlackTeamID,
+				"message_source", query.MessageSource,
+				"operation", "query_tracked_messages",
+			)
+			return nil, fmt.Errorf("failed to query tracked messages for repo %s PR %d team %s: %w",
+				query.RepoFullName, query.PRNumber, query.SlackTeamID, err)
+		}
+
+		// The page is full and another document exists, so there's a next page
+		if query.Limit > 0 && len(page.Messages) == query.Limit {
+			page.NextPageToken = encodePageToken(last)
+			return page, nil
+		}
+
+		var message models.TrackedMessage
+		if err := doc.DataTo(&message); err != nil {
+			log.Error(ctx, "Failed to unmarshal tracked message data",
+				"error", err,
+				"doc_id", doc.Ref.ID,
+				"operation", "unmarshal_tracked_message_data",
+			)
+			continue
+		}
+
+		last = &trackedMessageCursor{Order: query.Order, DocID: doc.Ref.ID}
+		if query.Order != TrackedMessageOrderDocumentID {
+			last.CreatedAt = message.CreatedAt
+		}
+		if query.matchesState(&message) {
+			page.Messages = append(page.Messages, &message)
+		}
+	}
+}
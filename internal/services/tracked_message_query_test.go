@@ -0,0 +1,94 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github-slack-notifier/internal/models"
+)
+
+func TestTrackedMessageQuery_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       TrackedMessageQuery
+		expectErr   bool
+		expectOrder string
+	}{
+		{name: "PR lookup", query: TrackedMessageQuery{RepoFullName: "org/repo", PRNumber: 1}},
+		{name: "workspace listing", query: TrackedMessageQuery{SlackTeamID: "T1", State: TrackedMessageStateActive}},
+		{name: "no repository or workspace", query: TrackedMessageQuery{MessageSource: models.MessageSourceBot}, expectErr: true},
+		{name: "PR number without repository", query: TrackedMessageQuery{SlackTeamID: "T1", PRNumber: 1}, expectErr: true},
+		{name: "unknown state", query: TrackedMessageQuery{SlackTeamID: "T1", State: "archived"}, expectErr: true},
+		{name: "unknown order", query: TrackedMessageQuery{SlackTeamID: "T1", Order: "random"}, expectErr: true},
+		{name: "negative limit", query: TrackedMessageQuery{SlackTeamID: "T1", Limit: -1}, expectErr: true},
+		{
+			name:        "creation time filter defaults to oldest first",
+			query:       TrackedMessageQuery{SlackTeamID: "T1", CreatedAfter: time.Now()},
+			expectOrder: TrackedMessageOrderOldestFirst,
+		},
+		{
+			name:        "creation time filter keeps an explicit order",
+			query:       TrackedMessageQuery{SlackTeamID: "T1", CreatedBefore: time.Now(), Order: TrackedMessageOrderNewestFirst},
+			expectOrder: TrackedMessageOrderNewestFirst,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.query.validate()
+			if tt.expectErr {
+				require.ErrorIs(t, err, ErrInvalidTrackedMessageQuery)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectOrder, tt.query.Order)
+		})
+	}
+}
+
+func TestTrackedMessageQuery_MatchesState(t *testing.T) {
+	active := &models.TrackedMessage{}
+	deleted := &models.TrackedMessage{DeletedByUser: true}
+	superseded := &models.TrackedMessage{SupersededByPR: 42}
+
+	query := TrackedMessageQuery{State: TrackedMessageStateActive}
+	assert.True(t, query.matchesState(active))
+	assert.False(t, query.matchesState(deleted))
+	assert.False(t, query.matchesState(superseded))
+
+	query.State = TrackedMessageStateDeleted
+	assert.True(t, query.matchesState(deleted))
+	assert.False(t, query.matchesState(active))
+
+	query.State = TrackedMessageStateSuperseded
+	assert.True(t, query.matchesState(superseded))
+
+	query.State = TrackedMessageStateAny
+	assert.True(t, query.matchesState(deleted))
+}
+
+func TestPageToken_RoundTrip(t *testing.T) {
+	cursor := &trackedMessageCursor{
+		Order:     TrackedMessageOrderNewestFirst,
+		CreatedAt: time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC),
+		DocID:     "abc123",
+	}
+	token := encodePageToken(cursor)
+
+	decoded, err := decodePageToken(token, TrackedMessageOrderNewestFirst)
+	require.NoError(t, err)
+	assert.Equal(t, cursor, decoded)
+
+	_, err = decodePageToken(token, TrackedMessageOrderOldestFirst)
+	require.ErrorIs(t, err, ErrInvalidPageToken, "tokens can't be reused with a different order")
+
+	_, err = decodePageToken("not a token!", TrackedMessageOrderNewestFirst)
+	require.ErrorIs(t, err, ErrInvalidPageToken)
+
+	decoded, err = decodePageToken("", TrackedMessageOrderDocumentID)
+	require.NoError(t, err)
+	assert.Nil(t, decoded, "an empty token starts from the beginning")
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"cloud.google.com/go/firestore"
+	"golang.org/x/sync/errgroup"
+
+	"github-slack-notifier/internal/models"
+)
+
+// WorkspacePRDocuments are the documents a workspace PR job reads before posting a PR's notification.
+type WorkspacePRDocuments struct {
+	Delivery *models.WebhookDelivery // Processing record of the webhook delivery; nil if it has none
+	Repo     *models.Repo            // Repository configuration for the workspace; nil if it isn't configured
+	User     *models.User            // PR author; nil if they haven't linked their GitHub account
+}
+
+// GetWorkspacePRDocuments reads a workspace PR job's documents together instead of one after another:
+// the webhook delivery record and repository in one batched read, and the author, which needs a query,
+// in parallel with it. Repositories and users read in the last few seconds are served from memory.
+// An empty delivery ID or a GitHub user ID of zero skips that document.
+func (fs *FirestoreService) GetWorkspacePRDocuments(
+	ctx context.Context, deliveryID, repoFullName, teamID string, githubUserID int64,
+) (*WorkspacePRDocuments, error) {
+	docs := &WorkspacePRDocuments{}
+	group, groupCtx := errgroup.WithContext(ctx)
+
+	group.Go(func() error {
+		user, err := fs.getCachedUserByGitHubUserID(groupCtx, githubUserID)
+		docs.User = user
+		return err
+	})
+
+	group.Go(func() error {
+		repoDocID := fs.encodeRepoDocID(teamID, repoFullName)
+		var refs []*firestore.DocumentRef
+		if repo, ok := fs.repoCache.get(repoDocID); ok {
+			docs.Repo = copyRepo(repo)
+		} else {
+			refs = append(refs, fs.client.Collection("repos").Doc(repoDocID))
+		}
+		if deliveryID != "" {
+			refs = append(refs, fs.client.Collection("webhook_deliveries").Doc(deliveryID))
+		}
+		if len(refs) == 0 {
+			return nil
+		}
+
+		snapshots, err := fs.client.GetAll(groupCtx, refs)
+		if err != nil {
+			return fmt.Errorf("failed to get repo %s and webhook delivery %s for team %s: %w", repoFullName, deliveryID, teamID, err)
+		}
+		for _, snapshot := range snapshots {
+			if !snapshot.Exists() {
+				continue
+			}
+			switch snapshot.Ref.Parent.ID {
+			case "repos":
+				var repo models.Repo
+				if err := snapshot.DataTo(&repo); err != nil {
+					return fmt.Errorf("failed to unmarshal repo data for %s team %s: %w", repoFullName, teamID, err)
+				}
+				fs.repoCache.set(repoDocID, copyRepo(&repo))
+				docs.Repo = &repo
+			case "webhook_deliveries":
+				var delivery models.WebhookDelivery
+				if err := snapshot.DataTo(&delivery); err != nil {
+					return fmt.Errorf("failed to unmarshal webhook delivery %s: %w", deliveryID, err)
+				}
+				docs.Delivery = &delivery
+			}
+		}
+		return nil
+	})
+
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+// getCachedUserByGitHubUserID returns the user linked to a GitHub user ID, from the cache if they were read recently.
+// Returns nil for a GitHub user ID of zero or one no user is linked to; those aren't cached, so new links apply at once.
+func (fs *FirestoreService) getCachedUserByGitHubUserID(ctx context.Context, githubUserID int64) (*models.User, error) {
+	if githubUserID <= 0 {
+		return nil, nil
+	}
+	if user, ok := fs.userCache.get(githubUserID); ok {
+		copied := *user
+		return &copied, nil
+	}
+
+	user, err := fs.GetUserByGitHubUserID(ctx, githubUserID)
+	if err != nil || user == nil {
+		return user, err
+	}
+	copied := *user
+	fs.userCache.set(githubUserID, &copied)
+	return user, nil
+}
+
+// ClearCache drops the repositories and users cached in memory, e.g. after they're changed outside this service.
+func (fs *FirestoreService) ClearCache() {
+	fs.repoCache.clear()
+	fs.userCache.clear()
+}
+
+// copyRepo returns a shallow copy of a repository, so callers can't change the cached one.
+func copyRepo(repo *models.Repo) *models.Repo {
+	copied := *repo
+	return &copied
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github-slack-notifier/internal/models"
+)
+
+const (
+	// workspaceStatsTTL is how long workspace stats are served from cache, keeping app_home_opened fast.
+	workspaceStatsTTL = 15 * time.Minute
+	// workspaceStatsWindow is the period notification counts cover.
+	workspaceStatsWindow = 7 * 24 * time.Hour
+	// workspaceStatsTopChannels is how many of the most active channels are listed.
+	workspaceStatsTopChannels = 3
+)
+
+// WorkspaceStatsService serves per-workspace activity stats, cached in memory per team ID.
+type WorkspaceStatsService struct {
+	firestoreService *FirestoreService
+	mu               sync.Mutex
+	cache            map[string]*models.WorkspaceStats
+	now              func() time.Time
+}
+
+// NewWorkspaceStatsService creates a new WorkspaceStatsService.
+func NewWorkspaceStatsService(firestoreService *FirestoreService) *WorkspaceStatsService {
+	return &WorkspaceStatsService{
+		firestoreService: firestoreService,
+		cache:            make(map[string]*models.WorkspaceStats),
+		now:              time.Now,
+	}
+}
+
+// GetWorkspaceStats returns the activity stats for a workspace, recomputing them once the cached copy expires.
+func (s *WorkspaceStatsService) GetWorkspaceStats(ctx context.Context, teamID string) (*models.WorkspaceStats, error) {
+	if stats := s.cached(teamID); stats != nil {
+		return stats, nil
+	}
+
+	stats, err := s.firestoreService.GetWorkspaceStats(ctx, teamID, s.now().Add(-workspaceStatsWindow), workspaceStatsTopChannels)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.cache[teamID] = stats
+	s.mu.Unlock()
+
+	return stats, nil
+}
+
+// cached returns the cached stats for a workspace, or nil if there are none or they have expired.
+func (s *WorkspaceStatsService) cached(teamID string) *models.WorkspaceStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats, ok := s.cache[teamID]
+	if !ok || s.now().Sub(stats.GeneratedAt) > workspaceStatsTTL {
+		return nil
+	}
+	return stats
+}
@@ -0,0 +1,25 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github-slack-notifier/internal/models"
+)
+
+func TestWorkspaceStatsService_cached(t *testing.T) {
+	now := time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)
+	s := NewWorkspaceStatsService(nil)
+	s.now = func() time.Time { return now }
+
+	fresh := &models.WorkspaceStats{ConnectedUsers: 4, GeneratedAt: now.Add(-time.Minute)}
+	stale := &models.WorkspaceStats{ConnectedUsers: 2, GeneratedAt: now.Add(-workspaceStatsTTL - time.Second)}
+	s.cache["T1"] = fresh
+	s.cache["T2"] = stale
+
+	assert.Same(t, fresh, s.cached("T1"))
+	assert.Nil(t, s.cached("T2"), "expired stats are recomputed")
+	assert.Nil(t, s.cached("T3"), "unknown workspace")
+}
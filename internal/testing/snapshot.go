@@ -0,0 +1,71 @@
+package testing
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// updateSnapshotsEnv is the environment variable that rewrites golden files instead of comparing against them.
+const updateSnapshotsEnv = "UPDATE_SNAPSHOTS"
+
+// snapshotDir is where golden files are kept, relative to the package under test.
+const snapshotDir = "testdata/snapshots"
+
+// MatchSnapshot compares the JSON form of a built Slack view or message, e.g. a slack.HomeTabViewRequest or
+// []slack.Block, with the golden file testdata/snapshots/<name>.json of the package under test.
+// Object keys are sorted and the JSON indented, so changes to the block structure show up as readable diffs.
+// Run the tests with UPDATE_SNAPSHOTS=1 to write or update the golden files, then review the diff.
+func MatchSnapshot(t *testing.T, name string, value interface{}) {
+	t.Helper()
+
+	got, err := SnapshotJSON(value)
+	require.NoError(t, err, "failed to serialise snapshot %s", name)
+
+	path := filepath.Join(snapshotDir, name+".json")
+	if os.Getenv(updateSnapshotsEnv) != "" {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+		require.NoError(t, os.WriteFile(path, got, 0o600))
+		return
+	}
+
+	want, err := os.ReadFile(path) // #nosec G304 -- path is built from the test's snapshot name
+	if os.IsNotExist(err) {
+		t.Fatalf("snapshot %s doesn't exist; run the test with %s=1 to create it", path, updateSnapshotsEnv)
+	}
+	require.NoError(t, err, "failed to read snapshot %s", path)
+
+	assert.Equal(t, string(want), string(got),
+		"%s doesn't match; if the change is intended, run the test with %s=1 to update it", path, updateSnapshotsEnv)
+}
+
+// SnapshotJSON returns the stable JSON form of a value used for snapshots: indented, with object keys sorted
+// and a trailing newline.
+func SnapshotJSON(value interface{}) ([]byte, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decoding into generic values and encoding again sorts object keys, whatever order the types declare them in
+	var generic interface{}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(&generic); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(generic); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,175 @@
+// Package tracing records OpenTelemetry traces of webhook handling, job processing and outbound Slack, GitHub and
+// Firestore calls. The trace context is carried from a webhook through Cloud Tasks to the jobs it enqueues, so a
+// webhook and everything it caused show up as one trace. Spans are exported over OTLP/HTTP when an endpoint is
+// configured, and are otherwise no-ops.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+
+	"github-slack-notifier/internal/config"
+)
+
+// tracerName identifies the spans this application creates.
+const tracerName = "github-slack-notifier"
+
+// serviceName is the service.name resource attribute of exported spans.
+const serviceName = "github-slack-notifier"
+
+// propagator carries trace context in W3C traceparent and tracestate headers.
+var propagator = propagation.TraceContext{}
+
+// Setup installs the global tracer provider, exporting sampled spans to the configured OTLP endpoint.
+// The exporter reads the endpoint, headers and timeout from the standard OTEL_EXPORTER_OTLP_* variables.
+// Returns a function that flushes and stops the exporter, to call on shutdown.
+// Trace context is propagated even when tracing is disabled, so traces stay connected across instances.
+func Setup(ctx context.Context, cfg config.TracingConfig) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagator)
+	if !cfg.Enabled() {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(),
+		resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(serviceName)))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		// Traces started upstream, e.g. by Cloud Run, keep their sampling decision
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SamplePercent/100))), //nolint:mnd // Percent
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Start starts a span as a child of any span in ctx, returning a context carrying the new span.
+func Start(ctx context.Context, name string, kind trace.SpanKind, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithSpanKind(kind), trace.WithAttributes(attrs...))
+}
+
+// End ends a span, marking it failed if err is non-nil.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// EndHTTP ends a server span with the HTTP status of its response, marking it failed for error statuses.
+func EndHTTP(span trace.Span, status int) {
+	span.SetAttributes(semconv.HTTPStatusCode(status))
+	if status >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, http.StatusText(status))
+	}
+	span.End()
+}
+
+// InjectHeaders adds the trace context of ctx to outgoing request headers, e.g. those of a Cloud Tasks task.
+func InjectHeaders(ctx context.Context, headers map[string]string) {
+	carrier := propagation.MapCarrier{}
+	propagator.Inject(ctx, carrier)
+	for key, value := range carrier {
+		headers[key] = value
+	}
+}
+
+// Extract returns ctx with the remote trace context of incoming request headers, if they carry one.
+func Extract(ctx context.Context, header http.Header) context.Context {
+	return propagator.Extract(ctx, propagation.HeaderCarrier(header))
+}
+
+// FirestoreOptions returns client options that record a span for each Firestore call, or none if tracing is disabled.
+func FirestoreOptions(cfg config.TracingConfig) []option.ClientOption {
+	if !cfg.Enabled() {
+		return nil
+	}
+	return []option.ClientOption{
+		option.WithGRPCDialOption(grpc.WithChainUnaryInterceptor(firestoreUnaryInterceptor)),
+		option.WithGRPCDialOption(grpc.WithChainStreamInterceptor(firestoreStreamInterceptor)),
+	}
+}
+
+// firestoreUnaryInterceptor records a span for a unary Firestore call, e.g. a document write or commit.
+func firestoreUnaryInterceptor(
+	ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption,
+) error {
+	ctx, span := startFirestoreSpan(ctx, method)
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	End(span, err)
+	return err
+}
+
+// firestoreStreamInterceptor records a span for a streaming Firestore call, e.g. a query or document read.
+// The span ends when the stream does, or when its context is done if the caller stops reading early.
+func firestoreStreamInterceptor(
+	ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	ctx, span := startFirestoreSpan(ctx, method)
+	stream, err := streamer(ctx, desc, cc, method, opts...)
+	if err != nil {
+		End(span, err)
+		return nil, err
+	}
+
+	traced := &tracedClientStream{ClientStream: stream, span: span}
+	context.AfterFunc(ctx, func() { traced.end(nil) })
+	return traced, nil
+}
+
+// startFirestoreSpan starts a client span for a Firestore gRPC method, e.g. "/google.firestore.v1.Firestore/Commit".
+func startFirestoreSpan(ctx context.Context, method string) (context.Context, trace.Span) {
+	service, name, _ := strings.Cut(strings.TrimPrefix(method, "/"), "/")
+	return Start(ctx, "firestore "+name, trace.SpanKindClient,
+		semconv.RPCSystemGRPC, semconv.RPCService(service), semconv.RPCMethod(name))
+}
+
+// tracedClientStream ends its span when the stream finishes.
+type tracedClientStream struct {
+	grpc.ClientStream
+	span trace.Span
+	once sync.Once
+}
+
+// RecvMsg receives a message, ending the span when the stream ends or fails.
+func (s *tracedClientStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			s.end(nil)
+		} else {
+			s.end(err)
+		}
+	}
+	return err
+}
+
+// end ends the span the first time it's called.
+func (s *tracedClientStream) end(err error) {
+	s.once.Do(func() { End(s.span, err) })
+}
@@ -0,0 +1,54 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+
+	"github-slack-notifier/internal/config"
+)
+
+func TestInjectHeadersAndExtract(t *testing.T) {
+	traceID, err := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	require.NoError(t, err)
+	spanID, err := trace.SpanIDFromHex("00f067aa0ba902b7")
+	require.NoError(t, err)
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+
+	headers := map[string]string{"Content-Type": "application/json"}
+	InjectHeaders(ctx, headers)
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", headers["traceparent"])
+	assert.Equal(t, "application/json", headers["Content-Type"], "existing headers are kept")
+
+	header := http.Header{}
+	for key, value := range headers {
+		header.Set(key, value)
+	}
+	extracted := trace.SpanContextFromContext(Extract(context.Background(), header))
+	assert.True(t, extracted.IsRemote())
+	assert.Equal(t, traceID, extracted.TraceID())
+	assert.Equal(t, spanID, extracted.SpanID())
+
+	untraced := map[string]string{}
+	InjectHeaders(context.Background(), untraced)
+	assert.Empty(t, untraced, "nothing is injected without a trace")
+}
+
+func TestFirestoreOptions(t *testing.T) {
+	assert.Nil(t, FirestoreOptions(config.TracingConfig{}), "no interceptors when tracing is disabled")
+	assert.Len(t, FirestoreOptions(config.TracingConfig{OTLPEndpoint: "http://localhost:4318", SamplePercent: 100}), 2)
+}
+
+func TestSetup_Disabled(t *testing.T) {
+	shutdown, err := Setup(context.Background(), config.TracingConfig{})
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+}
@@ -3,15 +3,34 @@ package ui
 
 import (
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github-slack-notifier/internal/models"
+	"github-slack-notifier/internal/prsize"
 	"github-slack-notifier/internal/utils"
 
 	"github.com/slack-go/slack"
 )
 
+// appSetupBlockID is the block ID of the App Home "App setup" header, which the "Active PRs" section is inserted
+// before once the rest of the view is built.
+const appSetupBlockID = "app_setup"
+
+const (
+	maxHomeViewBlocks      = 100 // Most blocks Slack allows in a Home tab view
+	maxActivePRsPerPage    = 5
+	activePRsSectionBlocks = 4 // Header, context, pagination and divider around the listed PRs
+	blocksPerActivePR      = 2 // Summary section and buttons
+)
+
 // HomeViewBuilder builds the App Home view blocks.
-type HomeViewBuilder struct{}
+type HomeViewBuilder struct {
+	UserTokenPosting bool // Offer posting PRs with the user's own Slack token
+	OpenPRButton     bool // PR messages carry an "Open PR" button, so click-through engagement is shown in stats
+}
 
 // NewHomeViewBuilder creates a new home view builder.
 func NewHomeViewBuilder() *HomeViewBuilder {
@@ -41,6 +60,7 @@ func (b *HomeViewBuilder) BuildHomeView(
 	blocks = append(blocks,
 		slack.NewHeaderBlock(
 			slack.NewTextBlockObject(slack.PlainTextType, "🔧 App setup", false, false),
+			slack.HeaderBlockOptionBlockID(appSetupBlockID),
 		),
 		slack.NewContextBlock(
 			"",
@@ -62,6 +82,12 @@ func (b *HomeViewBuilder) BuildHomeView(
 	// PR size emoji configuration section
 	blocks = append(blocks, b.buildPRSizeConfigSection(user)...)
 
+	// Data export and deletion (only once there's data stored about the user)
+	if user != nil {
+		blocks = append(blocks, slack.NewDividerBlock())
+		blocks = append(blocks, b.buildYourDataSection()...)
+	}
+
 	// Global Options section
 	blocks = append(blocks,
 		slack.NewDividerBlock(),
@@ -94,6 +120,42 @@ func (b *HomeViewBuilder) BuildHomeView(
 	}
 }
 
+// BuildWorkspaceStatsSection builds the read-only "Workspace activity" section shown to workspace admins.
+// GeneratedAt is shown in the workspace's timezone and date format.
+func (b *HomeViewBuilder) BuildWorkspaceStatsSection(stats *models.WorkspaceStats, timeFormat utils.LocalTimeFormat) []slack.Block {
+	summary := fmt.Sprintf("*%d* connected users • *%d* configured repos • *%d* notifications this week",
+		stats.ConnectedUsers, stats.ConfiguredRepos, stats.NotificationsThisWeek)
+	if b.OpenPRButton && stats.NotificationsThisWeek > 0 {
+		const percent = 100
+		summary += fmt.Sprintf(" (*%d%%* clicked, %d ignored)",
+			stats.ClickedThisWeek*percent/stats.NotificationsThisWeek, stats.NotificationsThisWeek-stats.ClickedThisWeek)
+	}
+
+	channels := "_No notifications posted this week_"
+	if len(stats.TopChannels) > 0 {
+		lines := make([]string, 0, len(stats.TopChannels))
+		for _, channel := range stats.TopChannels {
+			lines = append(lines, fmt.Sprintf("• <#%s> — %d", channel.ChannelID, channel.Notifications))
+		}
+		channels = "*Most active channels*\n" + strings.Join(lines, "\n")
+	}
+
+	return []slack.Block{
+		slack.NewDividerBlock(),
+		slack.NewHeaderBlock(
+			slack.NewTextBlockObject(slack.PlainTextType, "📊 Workspace activity", false, false),
+		),
+		slack.NewContextBlock(
+			"",
+			slack.NewTextBlockObject(slack.MarkdownType,
+				fmt.Sprintf("_Visible to workspace admins • Updated %s_", timeFormat.DateTime(stats.GeneratedAt)),
+				false, false),
+		),
+		slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, summary, false, false), nil, nil),
+		slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, channels, false, false), nil, nil),
+	}
+}
+
 // buildGitHubConnectionSection builds the GitHub connection status section.
 func (b *HomeViewBuilder) buildGitHubConnectionSection(user *models.User) []slack.Block {
 	blocks := []slack.Block{
@@ -208,35 +270,51 @@ func (b *HomeViewBuilder) buildChannelConfigSection(user *models.User) []slack.B
 		blocks = append(blocks, b.buildImpersonationSection(user)...)
 	}
 
+	// Author DM preferences, review request notifications, and digest mode - only show if GitHub is connected
+	if githubConnected {
+		blocks = append(blocks, b.buildAuthorDMSection(user)...)
+		blocks = append(blocks, b.buildReviewRequestSection(user)...)
+		blocks = append(blocks, b.buildAssignmentDMSection(user)...)
+		blocks = append(blocks, b.buildReviewCommentThreadsSection(user)...)
+		blocks = append(blocks, b.buildDigestModeSection(user)...)
+		blocks = append(blocks, b.buildDailyDigestSection(user)...)
+		blocks = append(blocks, b.buildQuietHoursSection(user)...)
+		blocks = append(blocks, b.buildOutOfOfficeSection(user)...)
+		blocks = append(blocks, b.buildMutedReposSection(user, time.Now())...)
+	}
+
 	// Channel selection - always show but with different states
 	var channelSectionText string
 	var channelAccessory *slack.Accessory
 
 	if !githubConnected {
 		// GitHub not connected - show pending state
-		channelSectionText = "Set your default channel\n_⏳ Pending - Connect GitHub first_"
+		channelSectionText = "Set your default channels\n_⏳ Pending - Connect GitHub first_"
 	} else if user != nil && !user.NotificationsEnabled {
 		// GitHub connected but notifications disabled
-		channelSectionText = "Set your default channel\n_⏳ Pending - Enable notifications first_"
-	} else if user != nil && user.DefaultChannel != "" {
-		// Everything enabled and channel set
-		channelSectionText = fmt.Sprintf("Set your default channel\n_✅ Current: <#%s> - This is where your PRs will be posted, "+
-			"unless specified otherwise in the PR description_", user.DefaultChannel)
+		channelSectionText = "Set your default channels\n_⏳ Pending - Enable notifications first_"
+	} else if user != nil && len(user.GetDefaultChannels()) > 0 {
+		// Everything enabled and channels set
+		channelSectionText = fmt.Sprintf("Set your default channels\n_✅ Current: %s - This is where your PRs will be posted, "+
+			"unless specified otherwise in the PR description_", formatChannelMentions(user.GetDefaultChannels()))
+		if len(user.RepoChannels) > 0 {
+			channelSectionText += fmt.Sprintf("\n_%d repo(s) are posted to their own channels instead_", len(user.RepoChannels))
+		}
 		channelAccessory = slack.NewAccessory(
 			slack.NewButtonBlockElement(
 				"select_channel",
 				"change_channel",
-				slack.NewTextBlockObject(slack.PlainTextType, "Change channel", false, false),
+				slack.NewTextBlockObject(slack.PlainTextType, "Change channels", false, false),
 			),
 		)
 	} else {
 		// Everything enabled but no channel set
-		channelSectionText = "Set your default channel\n_:warning: No channel selected - Choose where your PRs should be posted_"
+		channelSectionText = "Set your default channels\n_:warning: No channel selected - Choose where your PRs should be posted_"
 		channelAccessory = slack.NewAccessory(
 			slack.NewButtonBlockElement(
 				"select_channel",
 				"select_channel",
-				slack.NewTextBlockObject(slack.PlainTextType, "Select channel", false, false),
+				slack.NewTextBlockObject(slack.PlainTextType, "Select channels", false, false),
 			).WithStyle(slack.StylePrimary),
 		)
 	}
@@ -250,6 +328,15 @@ func (b *HomeViewBuilder) buildChannelConfigSection(user *models.User) []slack.B
 	return blocks
 }
 
+// formatChannelMentions formats channel IDs as a comma-separated list of channel mentions.
+func formatChannelMentions(channels []string) string {
+	mentions := make([]string, 0, len(channels))
+	for _, channel := range channels {
+		mentions = append(mentions, fmt.Sprintf("<#%s>", channel))
+	}
+	return strings.Join(mentions, ", ")
+}
+
 // buildUserTaggingSection builds the user tagging toggle section.
 func (b *HomeViewBuilder) buildUserTaggingSection(user *models.User) []slack.Block {
 	var taggingStatus string
@@ -303,43 +390,1446 @@ func (b *HomeViewBuilder) buildImpersonationSection(user *models.User) []slack.B
 	var impersonationToggleStyle slack.Style
 	var impersonationAccessory *slack.Accessory
 
-	if user != nil && !user.NotificationsEnabled {
-		// Notifications disabled - show pending state
-		impersonationStatus = "⏳ Pending - Enable notifications first"
-	} else {
-		// Determine impersonation status - default to enabled for backward compatibility
-		impersonationEnabled := user == nil || user.GetImpersonationEnabled()
+	if user != nil && !user.NotificationsEnabled {
+		// Notifications disabled - show pending state
+		impersonationStatus = "⏳ Pending - Enable notifications first"
+	} else {
+		// Determine impersonation status - default to enabled for backward compatibility
+		impersonationEnabled := user == nil || user.GetImpersonationEnabled()
+
+		if impersonationEnabled {
+			impersonationStatus = "✅ Enabled - Your PRs appear to come from you"
+			impersonationToggleText = "Disable impersonation"
+			impersonationToggleStyle = slack.StyleDanger
+		} else {
+			impersonationStatus = "❌ Disabled - Your PRs come from the bot"
+			impersonationToggleText = "Enable impersonation"
+			impersonationToggleStyle = slack.StylePrimary
+		}
+
+		// Only show toggle if notifications are enabled
+		if user != nil && user.NotificationsEnabled {
+			impersonationAccessory = slack.NewAccessory(
+				slack.NewButtonBlockElement(
+					"toggle_impersonation",
+					"toggle_impersonation",
+					slack.NewTextBlockObject(slack.PlainTextType, impersonationToggleText, false, false),
+				).WithStyle(impersonationToggleStyle),
+			)
+		}
+	}
+
+	impersonationSectionText := slack.NewTextBlockObject(slack.MarkdownType,
+		fmt.Sprintf("Control message appearance\n_%s - When enabled, PR notifications "+
+			"appear to be posted by you instead of the bot_", impersonationStatus),
+		false, false)
+
+	blocks := []slack.Block{
+		slack.NewSectionBlock(impersonationSectionText, nil, impersonationAccessory),
+	}
+
+	if b.UserTokenPosting && user != nil && user.NotificationsEnabled && user.GetImpersonationEnabled() {
+		blocks = append(blocks, b.buildUserTokenPostingSection(user)...)
+	}
+
+	return blocks
+}
+
+// buildUserTokenPostingSection builds the section for posting PRs with the user's own Slack token.
+func (b *HomeViewBuilder) buildUserTokenPostingSection(user *models.User) []slack.Block {
+	var status string
+	var button *slack.ButtonBlockElement
+
+	if user.UserTokenPosting {
+		status = "✅ Connected - PR messages are posted by your account, so you can edit or delete them"
+		button = slack.NewButtonBlockElement(
+			"disconnect_slack_user_token",
+			"disconnect_slack_user_token",
+			slack.NewTextBlockObject(slack.PlainTextType, "Disconnect", false, false),
+		).WithStyle(slack.StyleDanger)
+	} else {
+		status = "Optional - Authorize PR Bot to post as your account instead of using your name and avatar"
+		button = slack.NewButtonBlockElement(
+			"connect_slack_user_token",
+			"connect_slack_user_token",
+			slack.NewTextBlockObject(slack.PlainTextType, "Connect", false, false),
+		).WithStyle(slack.StylePrimary)
+	}
+
+	sectionText := slack.NewTextBlockObject(slack.MarkdownType,
+		fmt.Sprintf("Post with your Slack account\n_%s_", status),
+		false, false)
+
+	return []slack.Block{
+		slack.NewSectionBlock(sectionText, nil, slack.NewAccessory(button)),
+	}
+}
+
+// buildAuthorDMSection builds the direct message preferences section for events on the user's own PRs.
+func (b *HomeViewBuilder) buildAuthorDMSection(user *models.User) []slack.Block {
+	changesRequestedOption := slack.NewOptionBlockObject(
+		models.AuthorDMEventChangesRequested,
+		slack.NewTextBlockObject(slack.PlainTextType, "Changes requested", false, false),
+		nil,
+	)
+	ciFailedOption := slack.NewOptionBlockObject(
+		models.AuthorDMEventCIFailed,
+		slack.NewTextBlockObject(slack.PlainTextType, "CI failed", false, false),
+		nil,
+	)
+
+	mergeConflictOption := slack.NewOptionBlockObject(
+		models.AuthorDMEventMergeConflict,
+		slack.NewTextBlockObject(slack.PlainTextType, "Merge conflict", false, false),
+		nil,
+	)
+
+	checkboxes := slack.NewCheckboxGroupsBlockElement("author_dm_preferences", changesRequestedOption, ciFailedOption, mergeConflictOption)
+	if user.WantsAuthorDM(models.AuthorDMEventChangesRequested) {
+		checkboxes.InitialOptions = append(checkboxes.InitialOptions, changesRequestedOption)
+	}
+	if user.WantsAuthorDM(models.AuthorDMEventCIFailed) {
+		checkboxes.InitialOptions = append(checkboxes.InitialOptions, ciFailedOption)
+	}
+	if user.WantsAuthorDM(models.AuthorDMEventMergeConflict) {
+		checkboxes.InitialOptions = append(checkboxes.InitialOptions, mergeConflictOption)
+	}
+
+	sectionText := slack.NewTextBlockObject(slack.MarkdownType,
+		"Direct messages for your PRs\n_Get a DM when these happen on your PRs, even if the channel is busy_",
+		false, false)
+
+	return []slack.Block{
+		slack.NewSectionBlock(sectionText, nil, slack.NewAccessory(checkboxes)),
+	}
+}
+
+// buildReviewRequestSection builds the notification preferences section for review requests on other people's PRs.
+func (b *HomeViewBuilder) buildReviewRequestSection(user *models.User) []slack.Block {
+	dmOption := slack.NewOptionBlockObject(
+		models.ReviewRequestNotifyDM,
+		slack.NewTextBlockObject(slack.PlainTextType, "Direct message", false, false),
+		nil,
+	)
+	threadNoteOption := slack.NewOptionBlockObject(
+		models.ReviewRequestNotifyThreadNote,
+		slack.NewTextBlockObject(slack.PlainTextType, "Note in the PR's thread", false, false),
+		nil,
+	)
+
+	checkboxes := slack.NewCheckboxGroupsBlockElement("review_request_preferences", dmOption, threadNoteOption)
+	if user.WantsReviewRequestNotification(models.ReviewRequestNotifyDM) {
+		checkboxes.InitialOptions = append(checkboxes.InitialOptions, dmOption)
+	}
+	if user.WantsReviewRequestNotification(models.ReviewRequestNotifyThreadNote) {
+		checkboxes.InitialOptions = append(checkboxes.InitialOptions, threadNoteOption)
+	}
+
+	sectionText := slack.NewTextBlockObject(slack.MarkdownType,
+		"Review requests\n_Get notified when someone requests your review on a PR, or removes the request_",
+		false, false)
+
+	return []slack.Block{
+		slack.NewSectionBlock(sectionText, nil, slack.NewAccessory(checkboxes)),
+	}
+}
+
+// buildAssignmentDMSection builds the toggle for DMs when the user is assigned to a PR or unassigned.
+func (b *HomeViewBuilder) buildAssignmentDMSection(user *models.User) []slack.Block {
+	status := "❌ Disabled - Assignments are only noted in the PR's thread"
+	toggleText := "Enable assignment DMs"
+	toggleStyle := slack.StylePrimary
+	if user != nil && user.AssignmentDMs {
+		status = "✅ Enabled - You get a DM when you're assigned to a PR or unassigned, and are mentioned in its thread"
+		toggleText = "Disable assignment DMs"
+		toggleStyle = slack.StyleDanger
+	}
+
+	sectionText := slack.NewTextBlockObject(slack.MarkdownType,
+		fmt.Sprintf("Assignments\n_%s_", status), false, false)
+	accessory := slack.NewAccessory(
+		slack.NewButtonBlockElement(
+			"toggle_assignment_dms",
+			"toggle_assignment_dms",
+			slack.NewTextBlockObject(slack.PlainTextType, toggleText, false, false),
+		).WithStyle(toggleStyle),
+	)
+
+	return []slack.Block{
+		slack.NewSectionBlock(sectionText, nil, accessory),
+	}
+}
+
+// buildReviewCommentThreadsSection builds the toggle for threading review comments under the user's PR messages.
+func (b *HomeViewBuilder) buildReviewCommentThreadsSection(user *models.User) []slack.Block {
+	status := "❌ Disabled - Review comments on your PRs aren't posted to Slack"
+	toggleText := "Enable review comment threads"
+	toggleStyle := slack.StylePrimary
+	if user.GetReviewCommentThreadsEnabled() {
+		status = "✅ Enabled - Review comments on your PRs are posted in the thread, in channels that allow it"
+		toggleText = "Disable review comment threads"
+		toggleStyle = slack.StyleDanger
+	}
+
+	sectionText := slack.NewTextBlockObject(slack.MarkdownType,
+		fmt.Sprintf("Review comment threads\n_%s_", status), false, false)
+	accessory := slack.NewAccessory(
+		slack.NewButtonBlockElement(
+			"toggle_review_comment_threads",
+			"toggle_review_comment_threads",
+			slack.NewTextBlockObject(slack.PlainTextType, toggleText, false, false),
+		).WithStyle(toggleStyle),
+	)
+
+	return []slack.Block{
+		slack.NewSectionBlock(sectionText, nil, accessory),
+	}
+}
+
+// buildDigestModeSection builds the digest mode toggle section.
+func (b *HomeViewBuilder) buildDigestModeSection(user *models.User) []slack.Block {
+	digestStatus := "❌ Disabled - You're mentioned as events happen"
+	toggleText := "Enable digest mode"
+	toggleStyle := slack.StylePrimary
+	if user != nil && user.DigestMode {
+		digestStatus = "✅ Enabled - CC mentions and PR DMs arrive as an hourly digest"
+		toggleText = "Disable digest mode"
+		toggleStyle = slack.StyleDanger
+	}
+
+	sectionText := slack.NewTextBlockObject(slack.MarkdownType,
+		fmt.Sprintf("Digest mode\n_%s_", digestStatus), false, false)
+	accessory := slack.NewAccessory(
+		slack.NewButtonBlockElement(
+			"toggle_digest_mode",
+			"toggle_digest_mode",
+			slack.NewTextBlockObject(slack.PlainTextType, toggleText, false, false),
+		).WithStyle(toggleStyle),
+	)
+
+	return []slack.Block{
+		slack.NewSectionBlock(sectionText, nil, accessory),
+	}
+}
+
+// timezoneChoices are the timezones offered for the daily digest and workspace settings. The current timezone
+// is added to the list if it's missing, e.g. when it was taken from a Slack profile.
+var timezoneChoices = []string{
+	"UTC",
+	"America/Los_Angeles",
+	"America/Denver",
+	"America/Chicago",
+	"America/New_York",
+	"America/Sao_Paulo",
+	"Europe/London",
+	"Europe/Dublin",
+	"Europe/Lisbon",
+	"Europe/Paris",
+	"Europe/Berlin",
+	"Europe/Amsterdam",
+	"Europe/Madrid",
+	"Europe/Stockholm",
+	"Europe/Warsaw",
+	"Europe/Athens",
+	"Europe/Kyiv",
+	"Europe/Istanbul",
+	"Asia/Dubai",
+	"Asia/Kolkata",
+	"Asia/Singapore",
+	"Asia/Shanghai",
+	"Asia/Tokyo",
+	"Asia/Seoul",
+	"Australia/Perth",
+	"Australia/Sydney",
+	"Pacific/Auckland",
+}
+
+// hoursPerDay is the number of hour options offered for the daily digest and quiet hours.
+const hoursPerDay = 24
+
+// buildDailyDigestSection builds the daily PR digest toggle, with its time and timezone when enabled.
+func (b *HomeViewBuilder) buildDailyDigestSection(user *models.User) []slack.Block {
+	status := "❌ Disabled"
+	toggleText := "Enable daily digest"
+	toggleStyle := slack.StylePrimary
+	enabled := user.DailyDigest != nil && user.DailyDigest.Enabled
+	if enabled {
+		status = fmt.Sprintf("✅ Enabled - Sent at %02d:00 %s", user.DailyDigest.Hour, user.DailyDigest.Location())
+		toggleText = "Disable daily digest"
+		toggleStyle = slack.StyleDanger
+	}
+
+	sectionText := slack.NewTextBlockObject(slack.MarkdownType,
+		fmt.Sprintf("Daily PR digest\n_%s - A morning DM of your open PRs and the reviews waiting on you_", status), false, false)
+	blocks := []slack.Block{
+		slack.NewSectionBlock(sectionText, nil, slack.NewAccessory(
+			slack.NewButtonBlockElement(
+				"toggle_daily_digest",
+				"toggle_daily_digest",
+				slack.NewTextBlockObject(slack.PlainTextType, toggleText, false, false),
+			).WithStyle(toggleStyle),
+		)),
+	}
+	if !enabled {
+		return blocks
+	}
+
+	hourSelect := buildHourSelect("daily_digest_hour", "Time", user.DailyDigest.Hour)
+	timezoneSelect := buildTimezoneSelect("daily_digest_timezone", user.DailyDigest.Location().String())
+
+	return append(blocks, slack.NewActionBlock("daily_digest_schedule", hourSelect, timezoneSelect))
+}
+
+// buildQuietHoursSection builds the quiet hours toggle, with their start, end and timezone when enabled.
+func (b *HomeViewBuilder) buildQuietHoursSection(user *models.User) []slack.Block {
+	status := "❌ Disabled"
+	toggleText := "Enable quiet hours"
+	toggleStyle := slack.StylePrimary
+	enabled := user.QuietHours != nil && user.QuietHours.Enabled
+	if enabled {
+		status = fmt.Sprintf("✅ Enabled - %02d:00 to %02d:00 %s",
+			user.QuietHours.StartHour, user.QuietHours.EndHour, user.QuietHours.Location())
+		toggleText = "Disable quiet hours"
+		toggleStyle = slack.StyleDanger
+	}
+
+	sectionText := slack.NewTextBlockObject(slack.MarkdownType,
+		fmt.Sprintf("Quiet hours\n_%s - DMs and thread mentions are held until quiet hours end_", status), false, false)
+	blocks := []slack.Block{
+		slack.NewSectionBlock(sectionText, nil, slack.NewAccessory(
+			slack.NewButtonBlockElement(
+				"toggle_quiet_hours",
+				"toggle_quiet_hours",
+				slack.NewTextBlockObject(slack.PlainTextType, toggleText, false, false),
+			).WithStyle(toggleStyle),
+		)),
+	}
+	if !enabled {
+		return blocks
+	}
+
+	startSelect := buildHourSelect("quiet_hours_start", "From", user.QuietHours.StartHour)
+	endSelect := buildHourSelect("quiet_hours_end", "Until", user.QuietHours.EndHour)
+	timezoneSelect := buildTimezoneSelect("quiet_hours_timezone", user.QuietHours.Location().String())
+
+	return append(blocks, slack.NewActionBlock("quiet_hours_schedule", startSelect, endSelect, timezoneSelect))
+}
+
+// buildOutOfOfficeSection builds the out of office toggle, with the delegate CC'd in the user's place when enabled.
+func (b *HomeViewBuilder) buildOutOfOfficeSection(user *models.User) []slack.Block {
+	status := "❌ Disabled - You're tagged when CC'd on PRs"
+	toggleText := "I'm out of office"
+	toggleStyle := slack.StylePrimary
+	if user.OutOfOffice {
+		status = "✅ Out of office - You aren't tagged when CC'd on PRs, and your delegate is CC'd instead"
+		if user.OOODelegate == "" {
+			status = "✅ Out of office - You aren't tagged when CC'd on PRs. Pick a delegate to CC instead"
+		}
+		toggleText = "I'm back"
+		toggleStyle = slack.StyleDanger
+	}
+
+	sectionText := slack.NewTextBlockObject(slack.MarkdownType,
+		fmt.Sprintf("Out of office\n_%s_", status), false, false)
+	blocks := []slack.Block{
+		slack.NewSectionBlock(sectionText, nil, slack.NewAccessory(
+			slack.NewButtonBlockElement(
+				"toggle_out_of_office",
+				"toggle_out_of_office",
+				slack.NewTextBlockObject(slack.PlainTextType, toggleText, false, false),
+			).WithStyle(toggleStyle),
+		)),
+	}
+	if !user.OutOfOffice {
+		return blocks
+	}
+
+	delegateSelect := slack.NewOptionsSelectBlockElement(slack.OptTypeUser,
+		slack.NewTextBlockObject(slack.PlainTextType, "Delegate", false, false), "ooo_delegate")
+	delegateSelect.InitialUser = user.OOODelegate
+	return append(blocks, slack.NewActionBlock("out_of_office_delegate", delegateSelect))
+}
+
+// buildMutedReposSection builds the list of repositories the user has muted, with a button to mute another
+// and a select to unmute one.
+func (b *HomeViewBuilder) buildMutedReposSection(user *models.User, now time.Time) []slack.Block {
+	mutes := user.ActiveRepoMutes(now)
+	text := "Muted repositories\n_None - You're mentioned and DMed about PRs in every repository. " +
+		"Mute one here or with *Mute this repository* on a PR message_"
+	if len(mutes) > 0 {
+		text = "Muted repositories\n_You aren't mentioned or DMed about PRs in these repositories:_"
+		for _, mute := range mutes {
+			text += fmt.Sprintf("\n• `%s` until %s", mute.RepoFullName, formatSlackDate(mute.Until))
+		}
+	}
+
+	sectionText := slack.NewTextBlockObject(slack.MarkdownType, text, false, false)
+	blocks := []slack.Block{
+		slack.NewSectionBlock(sectionText, nil, slack.NewAccessory(
+			slack.NewButtonBlockElement(
+				"open_mute_repo",
+				"open_mute_repo",
+				slack.NewTextBlockObject(slack.PlainTextType, "Mute a repository", false, false),
+			),
+		)),
+	}
+	if len(mutes) == 0 {
+		return blocks
+	}
+
+	options := make([]*slack.OptionBlockObject, 0, len(mutes))
+	for _, mute := range mutes {
+		options = append(options, slack.NewOptionBlockObject(mute.RepoFullName,
+			slack.NewTextBlockObject(slack.PlainTextType, mute.RepoFullName, false, false), nil))
+	}
+	unmuteSelect := slack.NewOptionsSelectBlockElement(slack.OptTypeStatic,
+		slack.NewTextBlockObject(slack.PlainTextType, "Unmute a repository", false, false), "unmute_repo", options...)
+	return append(blocks, slack.NewActionBlock("muted_repos", unmuteSelect))
+}
+
+// formatSlackDate formats t as a Slack date, shown in each reader's timezone, e.g. "Mar 2 at 9:00 AM".
+func formatSlackDate(t time.Time) string {
+	return fmt.Sprintf("<!date^%d^{date_short_pretty} at {time}|%s>", t.Unix(), t.UTC().Format("Jan 2 15:04 UTC"))
+}
+
+// BuildMuteRepoModal builds the form for muting a repository, with the repository's name to fill in when it's
+// muted from App Home rather than one of its PR messages.
+func (b *HomeViewBuilder) BuildMuteRepoModal(repoFullName string) slack.ModalViewRequest {
+	var blocks []slack.Block
+	if repoFullName == "" {
+		blocks = append(blocks, slack.NewInputBlock(
+			"mute_repo_name_input",
+			slack.NewTextBlockObject(slack.PlainTextType, "Repository", false, false),
+			nil,
+			slack.NewPlainTextInputBlockElement(
+				slack.NewTextBlockObject(slack.PlainTextType, "owner/repo", false, false), "mute_repo_name"),
+		))
+	} else {
+		blocks = append(blocks, slack.NewSectionBlock(
+			slack.NewTextBlockObject(slack.MarkdownType,
+				fmt.Sprintf("Stop mentions and DMs about PRs in *%s*. PRs are still posted to channels.", repoFullName),
+				false, false),
+			nil, nil,
+		))
+	}
+
+	options := make([]*slack.OptionBlockObject, 0, len(models.RepoMuteDurations))
+	for _, duration := range models.RepoMuteDurations {
+		options = append(options, slack.NewOptionBlockObject(duration.String(),
+			slack.NewTextBlockObject(slack.PlainTextType, formatMuteDuration(duration), false, false), nil))
+	}
+	durationSelect := slack.NewOptionsSelectBlockElement(slack.OptTypeStatic,
+		slack.NewTextBlockObject(slack.PlainTextType, "Choose how long", false, false), "mute_repo_duration", options...)
+	durationSelect.InitialOption = options[0]
+	blocks = append(blocks, slack.NewInputBlock(
+		"mute_repo_duration_input",
+		slack.NewTextBlockObject(slack.PlainTextType, "Mute for", false, false),
+		nil,
+		durationSelect,
+	))
+
+	return slack.ModalViewRequest{
+		Type:            slack.VTModal,
+		Title:           slack.NewTextBlockObject(slack.PlainTextType, "Mute Repository", false, false),
+		Close:           slack.NewTextBlockObject(slack.PlainTextType, "Cancel", false, false),
+		Submit:          slack.NewTextBlockObject(slack.PlainTextType, "Mute", false, false),
+		CallbackID:      "mute_repo",
+		PrivateMetadata: repoFullName,
+		Blocks:          slack.Blocks{BlockSet: blocks},
+	}
+}
+
+// formatMuteDuration formats a duration to mute a repository for, e.g. "1 week".
+func formatMuteDuration(duration time.Duration) string {
+	switch duration {
+	case models.RepoMuteDay:
+		return "1 day"
+	case models.RepoMuteWeek:
+		return "1 week"
+	case models.RepoMuteMonth:
+		return "30 days"
+	default:
+		return duration.String()
+	}
+}
+
+// buildHourSelect builds a select of the hours of the day, with the current hour selected if it's valid.
+func buildHourSelect(actionID, placeholder string, currentHour int) *slack.SelectBlockElement {
+	hourOptions := make([]*slack.OptionBlockObject, 0, hoursPerDay)
+	for hour := range hoursPerDay {
+		hourOptions = append(hourOptions, slack.NewOptionBlockObject(
+			strconv.Itoa(hour),
+			slack.NewTextBlockObject(slack.PlainTextType, fmt.Sprintf("%02d:00", hour), false, false),
+			nil,
+		))
+	}
+	hourSelect := slack.NewOptionsSelectBlockElement(slack.OptTypeStatic,
+		slack.NewTextBlockObject(slack.PlainTextType, placeholder, false, false),
+		actionID, hourOptions...)
+	if models.IsValidLocalHour(currentHour) {
+		hourSelect.InitialOption = hourOptions[currentHour]
+	}
+	return hourSelect
+}
+
+// buildTimezoneSelect builds a timezone select with the current timezone selected.
+func buildTimezoneSelect(actionID, currentTimezone string) *slack.SelectBlockElement {
+	timezones := timezoneChoices
+	if !slices.Contains(timezones, currentTimezone) {
+		timezones = append([]string{currentTimezone}, timezones...)
+	}
+	timezoneOptions := make([]*slack.OptionBlockObject, 0, len(timezones))
+	for _, timezone := range timezones {
+		option := slack.NewOptionBlockObject(timezone,
+			slack.NewTextBlockObject(slack.PlainTextType, strings.ReplaceAll(timezone, "_", " "), false, false), nil)
+		timezoneOptions = append(timezoneOptions, option)
+	}
+	timezoneSelect := slack.NewOptionsSelectBlockElement(slack.OptTypeStatic,
+		slack.NewTextBlockObject(slack.PlainTextType, "Timezone", false, false),
+		actionID, timezoneOptions...)
+	timezoneSelect.InitialOption = timezoneOptions[slices.Index(timezones, currentTimezone)]
+	return timezoneSelect
+}
+
+// BuildWorkspaceSettingsSection builds the workspace timezone and locale controls shown to workspace admins.
+func (b *HomeViewBuilder) BuildWorkspaceSettingsSection(workspace *models.SlackWorkspace) []slack.Block {
+	currentLocale := workspace.GetLocale()
+	localeOptions := make([]*slack.OptionBlockObject, 0, len(models.WorkspaceLocales)+1)
+	var initialLocale *slack.OptionBlockObject
+	for _, choice := range models.WorkspaceLocales {
+		option := slack.NewOptionBlockObject(choice.Code,
+			slack.NewTextBlockObject(slack.PlainTextType, choice.Name, false, false), nil)
+		localeOptions = append(localeOptions, option)
+		if choice.Code == currentLocale {
+			initialLocale = option
+		}
+	}
+	if initialLocale == nil {
+		// Keep a locale detected from Slack that isn't in the list
+		initialLocale = slack.NewOptionBlockObject(currentLocale,
+			slack.NewTextBlockObject(slack.PlainTextType, currentLocale, false, false), nil)
+		localeOptions = append([]*slack.OptionBlockObject{initialLocale}, localeOptions...)
+	}
+	localeSelect := slack.NewOptionsSelectBlockElement(slack.OptTypeStatic,
+		slack.NewTextBlockObject(slack.PlainTextType, "Date format", false, false),
+		"workspace_locale", localeOptions...)
+	localeSelect.InitialOption = initialLocale
+
+	linkInviteOptions := []*slack.OptionBlockObject{
+		slack.NewOptionBlockObject(models.LinkInvitesOff,
+			slack.NewTextBlockObject(slack.PlainTextType, "Off", false, false), nil),
+		slack.NewOptionBlockObject(models.LinkInvitesDM,
+			slack.NewTextBlockObject(slack.PlainTextType, "Send a DM", false, false), nil),
+		slack.NewOptionBlockObject(models.LinkInvitesEphemeral,
+			slack.NewTextBlockObject(slack.PlainTextType, "Show in the PR's channel", false, false), nil),
+	}
+	linkInviteSelect := slack.NewOptionsSelectBlockElement(slack.OptTypeStatic,
+		slack.NewTextBlockObject(slack.PlainTextType, "Invitations", false, false),
+		"workspace_link_invites", linkInviteOptions...)
+	for _, option := range linkInviteOptions {
+		if option.Value == workspace.GetLinkInvites() {
+			linkInviteSelect.InitialOption = option
+		}
+	}
+
+	slackCommentsStatus := "❌ Off - Replies in PR message threads stay in Slack"
+	slackCommentsToggle := slack.NewButtonBlockElement("toggle_workspace_slack_comments", "toggle_workspace_slack_comments",
+		slack.NewTextBlockObject(slack.PlainTextType, "Turn on", false, false)).WithStyle(slack.StylePrimary)
+	if workspace.SlackToGitHubComments {
+		slackCommentsStatus = "✅ On - Replies in PR message threads starting with `!to-github` are posted as comments on the PR"
+		slackCommentsToggle = slack.NewButtonBlockElement("toggle_workspace_slack_comments", "toggle_workspace_slack_comments",
+			slack.NewTextBlockObject(slack.PlainTextType, "Turn off", false, false)).WithStyle(slack.StyleDanger)
+	}
+
+	timeFormat := utils.WorkspaceTimeFormat(workspace)
+	return []slack.Block{
+		slack.NewSectionBlock(
+			slack.NewTextBlockObject(slack.MarkdownType,
+				fmt.Sprintf("*Workspace timezone and date format*\n_Used for channel digests, release cut countdowns and dates in messages, "+
+					"e.g. %s_", timeFormat.DateTime(time.Now())),
+				false, false),
+			nil, nil,
+		),
+		slack.NewActionBlock("workspace_locale_settings",
+			buildTimezoneSelect("workspace_timezone", workspace.Location().String()), localeSelect),
+		slack.NewSectionBlock(
+			slack.NewTextBlockObject(slack.MarkdownType,
+				"*Routing rules*\n_Send a repository's PRs to a channel based on the files they change or their labels_",
+				false, false),
+			nil,
+			slack.NewAccessory(
+				slack.NewButtonBlockElement(
+					"manage_routing_rules",
+					"manage_routing_rules",
+					slack.NewTextBlockObject(slack.PlainTextType, "Edit routing rules", false, false),
+				),
+			),
+		),
+		slack.NewSectionBlock(
+			slack.NewTextBlockObject(slack.MarkdownType,
+				"*Reviewer rotation*\n_Assign a channel's PRs to reviewers in turn when their authors don't CC anyone_",
+				false, false),
+			nil,
+			slack.NewAccessory(
+				slack.NewButtonBlockElement(
+					"manage_reviewer_rotation",
+					"manage_reviewer_rotation",
+					slack.NewTextBlockObject(slack.PlainTextType, "Edit reviewer rotation", false, false),
+				),
+			),
+		),
+		slack.NewSectionBlock(
+			slack.NewTextBlockObject(slack.MarkdownType,
+				"*Team review requests*\n_Mention a Slack user group when a GitHub team is requested to review a PR_",
+				false, false),
+			nil,
+			slack.NewAccessory(
+				slack.NewButtonBlockElement(
+					"manage_team_user_groups",
+					"manage_team_user_groups",
+					slack.NewTextBlockObject(slack.PlainTextType, "Edit team mappings", false, false),
+				),
+			),
+		),
+		slack.NewSectionBlock(
+			slack.NewTextBlockObject(slack.MarkdownType,
+				"*Reaction emoji*\n_Choose the reactions added to PR messages when PRs are reviewed, merged or closed_",
+				false, false),
+			nil,
+			slack.NewAccessory(
+				slack.NewButtonBlockElement(
+					"manage_reaction_emoji",
+					"manage_reaction_emoji",
+					slack.NewTextBlockObject(slack.PlainTextType, "Edit reaction emoji", false, false),
+				),
+			),
+		),
+		slack.NewSectionBlock(
+			slack.NewTextBlockObject(slack.MarkdownType,
+				"*Message format*\n_Choose how PR messages are laid out, e.g. the order of the emoji, title, author and CCs_",
+				false, false),
+			nil,
+			slack.NewAccessory(
+				slack.NewButtonBlockElement(
+					"manage_message_template",
+					"manage_message_template",
+					slack.NewTextBlockObject(slack.PlainTextType, "Edit message format", false, false),
+				),
+			),
+		),
+		slack.NewSectionBlock(
+			slack.NewTextBlockObject(slack.MarkdownType,
+				fmt.Sprintf("*PR size emoji*\n_Choose the size emoji for PRs whose channel and author haven't chosen their own_ · %s",
+					prsize.Describe(workspace.PRSizeConfig)),
+				false, false),
+			nil,
+			slack.NewAccessory(
+				slack.NewButtonBlockElement(
+					"manage_workspace_pr_size",
+					"manage_workspace_pr_size",
+					slack.NewTextBlockObject(slack.PlainTextType, "Edit PR size emoji", false, false),
+				),
+			),
+		),
+		slack.NewSectionBlock(
+			slack.NewTextBlockObject(slack.MarkdownType,
+				"*Invite unlinked CCs*\n_When a PR CCs a GitHub user who hasn't linked their account, "+
+					"invite the Slack user with the same name to link it_",
+				false, false),
+			nil,
+			slack.NewAccessory(linkInviteSelect),
+		),
+		slack.NewSectionBlock(
+			slack.NewTextBlockObject(slack.MarkdownType,
+				fmt.Sprintf("*Comment on GitHub from Slack*\n_%s_", slackCommentsStatus), false, false),
+			nil,
+			slack.NewAccessory(slackCommentsToggle),
+		),
+	}
+}
+
+// reactionEmojiLabels are the labels of the reaction emoji inputs, keyed by models.WorkspaceEmojiStates.
+var reactionEmojiLabels = map[string]string{
+	"approved":          "Approved",
+	"changes_requested": "Changes requested",
+	"commented":         "Commented",
+	"merged":            "Merged",
+	"closed":            "Closed without merging",
+}
+
+// BuildReactionEmojiModal builds the editor for a workspace's reaction emoji.
+// Each input shows the workspace's override, with the environment default as its placeholder.
+func (b *HomeViewBuilder) BuildReactionEmojiModal(overrides, defaults *models.WorkspaceEmoji) slack.ModalViewRequest {
+	if overrides == nil {
+		overrides = &models.WorkspaceEmoji{}
+	}
+
+	blocks := []slack.Block{
+		slack.NewSectionBlock(
+			slack.NewTextBlockObject(slack.MarkdownType,
+				"Choose the emoji reactions added to PR messages in this workspace. "+
+					"Leave a field empty to use the default shown.\n\n"+
+					"Messages keep the reactions they already have; new reactions use these emoji.",
+				false, false),
+			nil, nil,
+		),
+	}
+	for _, state := range models.WorkspaceEmojiStates {
+		placeholder := "No reaction"
+		if emoji := *defaults.Field(state); emoji != "" {
+			placeholder = ":" + emoji + ":"
+		}
+		blocks = append(blocks, &slack.InputBlock{
+			Type:     slack.MBTInput,
+			BlockID:  "reaction_emoji_" + state + "_input",
+			Label:    slack.NewTextBlockObject(slack.PlainTextType, reactionEmojiLabels[state], false, false),
+			Optional: true,
+			Element: &slack.PlainTextInputBlockElement{
+				Type:         slack.METPlainTextInput,
+				ActionID:     "reaction_emoji_" + state,
+				Placeholder:  slack.NewTextBlockObject(slack.PlainTextType, placeholder, false, false),
+				InitialValue: *overrides.Field(state),
+			},
+		})
+	}
+
+	return slack.ModalViewRequest{
+		Type:       slack.VTModal,
+		Title:      slack.NewTextBlockObject(slack.PlainTextType, "Reaction Emoji", false, false),
+		CallbackID: "save_reaction_emoji",
+		Submit:     slack.NewTextBlockObject(slack.PlainTextType, "Save", false, false),
+		Close:      slack.NewTextBlockObject(slack.PlainTextType, "Cancel", false, false),
+		Blocks:     slack.Blocks{BlockSet: blocks},
+	}
+}
+
+// BuildMessageTemplateModal builds the editor for a workspace's PR message template, starting from the default
+// template if the workspace hasn't set one. Lists the placeholders and previews the current template with a sample PR.
+func (b *HomeViewBuilder) BuildMessageTemplateModal(messageTemplate string) slack.ModalViewRequest {
+	if messageTemplate == "" {
+		messageTemplate = utils.DefaultMessageTemplate
+	}
+
+	placeholders := make([]string, 0, len(utils.MessageTemplatePlaceholders))
+	for _, placeholder := range utils.MessageTemplatePlaceholders {
+		placeholders = append(placeholders, fmt.Sprintf("• `%s` %s", placeholder.Name, placeholder.Description))
+	}
+
+	blocks := []slack.Block{
+		slack.NewSectionBlock(
+			slack.NewTextBlockObject(slack.MarkdownType,
+				"Choose how PR messages are laid out in this workspace, using Go template syntax. "+
+					"Compact messages keep their one-line format.\n\n"+
+					"Messages already posted keep their layout until they're next updated.",
+				false, false),
+			nil, nil,
+		),
+		&slack.InputBlock{
+			Type:    slack.MBTInput,
+			BlockID: "message_template_input",
+			Label:   slack.NewTextBlockObject(slack.PlainTextType, "Template", false, false),
+			Hint: slack.NewTextBlockObject(slack.PlainTextType,
+				"Use {{if .CC}}…{{end}} to leave out optional parts. Clear it to go back to the default.", false, false),
+			Optional: true,
+			Element: &slack.PlainTextInputBlockElement{
+				Type:         slack.METPlainTextInput,
+				ActionID:     "message_template",
+				Multiline:    true,
+				MaxLength:    utils.MaxMessageTemplateLength,
+				InitialValue: messageTemplate,
+			},
+		},
+		slack.NewContextBlock("message_template_placeholders",
+			slack.NewTextBlockObject(slack.MarkdownType, "*Placeholders*\n"+strings.Join(placeholders, "\n"), false, false),
+		),
+	}
+	if tmpl, err := utils.ParseMessageTemplate(messageTemplate); err == nil {
+		blocks = append(blocks, slack.NewContextBlock("message_template_preview",
+			slack.NewTextBlockObject(slack.MarkdownType, "*Current preview*\n"+utils.PreviewMessageTemplate(tmpl), false, false),
+		))
+	}
+
+	return slack.ModalViewRequest{
+		Type:       slack.VTModal,
+		Title:      slack.NewTextBlockObject(slack.PlainTextType, "Message Format", false, false),
+		CallbackID: "save_message_template",
+		Submit:     slack.NewTextBlockObject(slack.PlainTextType, "Save", false, false),
+		Close:      slack.NewTextBlockObject(slack.PlainTextType, "Cancel", false, false),
+		Blocks:     slack.Blocks{BlockSet: blocks},
+	}
+}
+
+// maxRoutingRulesRepoOptions is the most options a Slack static select can show.
+const maxRoutingRulesRepoOptions = 100
+
+// maxListedRepos is how many repositories the App Home lists; the rest can still be picked to edit or delete.
+const maxListedRepos = 10
+
+// notificationModeLabels are the display names of the repository notification modes.
+var notificationModeLabels = map[string]string{
+	models.NotificationModeFull:       "Full messages",
+	models.NotificationModeCompact:    "Compact",
+	models.NotificationModeDigestOnly: "Channel digest only",
+}
+
+// BuildRepositoriesSection builds the App Home section listing a workspace's repositories for admins,
+// with selects for editing or deleting one.
+func (b *HomeViewBuilder) BuildRepositoriesSection(repos []*models.Repo) []slack.Block {
+	blocks := []slack.Block{
+		slack.NewDividerBlock(),
+		slack.NewHeaderBlock(slack.NewTextBlockObject(slack.PlainTextType, "📦 Repositories", false, false)),
+	}
+	if len(repos) == 0 {
+		return append(blocks, slack.NewContextBlock("", slack.NewTextBlockObject(slack.MarkdownType,
+			"_No repositories yet. Repositories are added when a connected author's PR is first posted._", false, false)))
+	}
+
+	lines := make([]string, 0, min(len(repos), maxListedRepos)+1)
+	for i, repo := range repos {
+		if i == maxListedRepos {
+			lines = append(lines, fmt.Sprintf("_…and %d more_", len(repos)-maxListedRepos))
+			break
+		}
+		lines = append(lines, "• "+describeRepoSettings(repo))
+	}
+	blocks = append(blocks, slack.NewSectionBlock(
+		slack.NewTextBlockObject(slack.MarkdownType, strings.Join(lines, "\n"), false, false), nil, nil))
+
+	options := make([]*slack.OptionBlockObject, 0, min(len(repos), maxRoutingRulesRepoOptions))
+	for _, repo := range repos {
+		if len(options) == maxRoutingRulesRepoOptions {
+			break
+		}
+		options = append(options, slack.NewOptionBlockObject(repo.RepoFullName,
+			slack.NewTextBlockObject(slack.PlainTextType, repo.RepoFullName, false, false), nil))
+	}
+	editSelect := slack.NewOptionsSelectBlockElement(slack.OptTypeStatic,
+		slack.NewTextBlockObject(slack.PlainTextType, "Edit a repository", false, false),
+		"edit_repo_settings", options...)
+	deleteSelect := slack.NewOptionsSelectBlockElement(slack.OptTypeStatic,
+		slack.NewTextBlockObject(slack.PlainTextType, "Delete a repository", false, false),
+		"delete_repo", options...)
+
+	return append(blocks, slack.NewActionBlock("repo_settings_actions", editSelect, deleteSelect))
+}
+
+// describeRepoSettings summarizes a repository's settings on one line, e.g. "`org/api` — Compact · <#C1>".
+func describeRepoSettings(repo *models.Repo) string {
+	parts := []string{notificationModeLabels[repo.GetNotificationMode()]}
+	if repo.DefaultChannel != "" {
+		parts = append(parts, fmt.Sprintf("<#%s>", repo.DefaultChannel))
+	}
+	if repo.MechanicalPRs != nil {
+		parts = append(parts, "reverts "+repo.MechanicalPRs.Handling)
+	}
+	if repo.Emoji != "" {
+		parts = append(parts, repo.Emoji)
+	}
+	if !repo.Enabled {
+		parts = append(parts, "*disabled*")
+	}
+	return fmt.Sprintf("`%s` — %s", repo.RepoFullName, strings.Join(parts, " · "))
+}
+
+// BuildRevokedWorkspacesSection builds the App Home warning about other workspaces sharing repositories with this
+// one whose Slack token was revoked, given each one's shared repositories by team ID. Nobody in a revoked workspace
+// can see it in Slack, so it's shown where someone who can reach its admins might.
+// reinstallURL links to reinstalling the app; it's left out when empty.
+func (b *HomeViewBuilder) BuildRevokedWorkspacesSection(
+	workspaces []*models.SlackWorkspace, sharedRepos map[string][]string, reinstallURL string,
+) []slack.Block {
+	if len(workspaces) == 0 {
+		return nil
+	}
+
+	lines := make([]string, 0, len(workspaces))
+	for _, workspace := range workspaces {
+		repos := sharedRepos[workspace.ID]
+		names := make([]string, 0, min(len(repos), maxListedRepos))
+		for _, repo := range repos[:min(len(repos), maxListedRepos)] {
+			names = append(names, fmt.Sprintf("`%s`", repo))
+		}
+		if len(repos) > maxListedRepos {
+			names = append(names, fmt.Sprintf("%d more", len(repos)-maxListedRepos))
+		}
+		lines = append(lines, fmt.Sprintf("• *%s* since %s (`%s`), for %s",
+			workspace.TeamName, formatSlackDate(*workspace.RevokedAt), workspace.RevokedReason, strings.Join(names, ", ")))
+	}
+
+	text := "*Disconnected workspaces*\n_Slack revoked my access to these workspaces, so PR notifications aren't posted " +
+		"there until one of their admins reinstalls the app"
+	if reinstallURL != "" {
+		text += fmt.Sprintf(" from <%s|this link>", reinstallURL)
+	}
+	text += ":_\n" + strings.Join(lines, "\n")
+
+	return []slack.Block{
+		slack.NewDividerBlock(),
+		slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, text, false, false), nil, nil),
+	}
+}
+
+// mechanicalPRAnnounce is the option value for announcing revert and back-merge PRs like any other PR.
+const mechanicalPRAnnounce = "announce"
+
+// BuildRepoSettingsModal builds the editor for a repository's settings.
+func (b *HomeViewBuilder) BuildRepoSettingsModal(repo *models.Repo) slack.ModalViewRequest {
+	enabledOption := slack.NewOptionBlockObject("enabled",
+		slack.NewTextBlockObject(slack.PlainTextType, "Post notifications for this repository", false, false), nil)
+	enabledCheckbox := slack.NewCheckboxGroupsBlockElement("repo_settings_enabled", enabledOption)
+	if repo.Enabled {
+		enabledCheckbox.InitialOptions = []*slack.OptionBlockObject{enabledOption}
+	}
+
+	channelSelect := slack.NewOptionsSelectBlockElement(slack.OptTypeChannels,
+		slack.NewTextBlockObject(slack.PlainTextType, "Authors' channels", false, false),
+		"repo_settings_channel_select")
+	channelSelect.InitialChannel = repo.DefaultChannel
+
+	modeOptions := make([]*slack.OptionBlockObject, 0, len(notificationModeLabels))
+	var initialMode *slack.OptionBlockObject
+	for _, mode := range []string{models.NotificationModeFull, models.NotificationModeCompact, models.NotificationModeDigestOnly} {
+		option := slack.NewOptionBlockObject(mode,
+			slack.NewTextBlockObject(slack.PlainTextType, notificationModeLabels[mode], false, false), nil)
+		modeOptions = append(modeOptions, option)
+		if mode == repo.GetNotificationMode() {
+			initialMode = option
+		}
+	}
+	modeRadio := slack.NewRadioButtonsBlockElement("repo_settings_mode", modeOptions...)
+	modeRadio.InitialOption = initialMode
+
+	mechanicalOptions := []*slack.OptionBlockObject{
+		slack.NewOptionBlockObject(mechanicalPRAnnounce,
+			slack.NewTextBlockObject(slack.PlainTextType, "Announce like any other PR", false, false), nil),
+		slack.NewOptionBlockObject(models.MechanicalPRHandlingCompact,
+			slack.NewTextBlockObject(slack.PlainTextType, "Post as a one-line message", false, false), nil),
+		slack.NewOptionBlockObject(models.MechanicalPRHandlingSkip,
+			slack.NewTextBlockObject(slack.PlainTextType, "Don't announce", false, false), nil),
+	}
+	initialMechanical := mechanicalOptions[0]
+	if repo.MechanicalPRs != nil {
+		switch repo.MechanicalPRs.Handling {
+		case models.MechanicalPRHandlingCompact:
+			initialMechanical = mechanicalOptions[1]
+		case models.MechanicalPRHandlingSkip:
+			initialMechanical = mechanicalOptions[2]
+		case models.MechanicalPRHandlingChannel:
+			// Only set with the toolbox; keep it selectable so saving doesn't lose it
+			initialMechanical = slack.NewOptionBlockObject(models.MechanicalPRHandlingChannel,
+				slack.NewTextBlockObject(slack.PlainTextType, "Post to "+repo.MechanicalPRs.Channel, false, false), nil)
+			mechanicalOptions = append(mechanicalOptions, initialMechanical)
+		}
+	}
+	mechanicalSelect := slack.NewOptionsSelectBlockElement(slack.OptTypeStatic,
+		slack.NewTextBlockObject(slack.PlainTextType, "Revert and back-merge PRs", false, false),
+		"repo_settings_mechanical_select", mechanicalOptions...)
+	mechanicalSelect.InitialOption = initialMechanical
+
+	return slack.ModalViewRequest{
+		Type:            slack.VTModal,
+		Title:           slack.NewTextBlockObject(slack.PlainTextType, "Repository Settings", false, false),
+		CallbackID:      "save_repo_settings",
+		Submit:          slack.NewTextBlockObject(slack.PlainTextType, "Save", false, false),
+		Close:           slack.NewTextBlockObject(slack.PlainTextType, "Cancel", false, false),
+		PrivateMetadata: repo.RepoFullName, // Store repo name in private metadata
+		Blocks: slack.Blocks{
+			BlockSet: []slack.Block{
+				slack.NewSectionBlock(
+					slack.NewTextBlockObject(slack.MarkdownType, fmt.Sprintf("*%s*", repo.RepoFullName), false, false),
+					nil, nil,
+				),
+				&slack.InputBlock{
+					Type:     slack.MBTInput,
+					BlockID:  "repo_settings_enabled_input",
+					Label:    slack.NewTextBlockObject(slack.PlainTextType, "Notifications", false, false),
+					Optional: true,
+					Element:  enabledCheckbox,
+				},
+				&slack.InputBlock{
+					Type:    slack.MBTInput,
+					BlockID: "repo_settings_channel_input",
+					Label:   slack.NewTextBlockObject(slack.PlainTextType, "Default channel", false, false),
+					Hint: slack.NewTextBlockObject(slack.PlainTextType,
+						"Used instead of each author's default channels. Leave empty to use the authors' channels.", false, false),
+					Optional: true,
+					Element:  channelSelect,
+				},
+				slack.NewInputBlock(
+					"repo_settings_mode_input",
+					slack.NewTextBlockObject(slack.PlainTextType, "Notification mode", false, false),
+					slack.NewTextBlockObject(slack.PlainTextType,
+						"Compact messages are one line without reactions. Digest only posts nothing until the channel's daily digest.",
+						false, false),
+					modeRadio,
+				),
+				slack.NewInputBlock(
+					"repo_settings_mechanical_input",
+					slack.NewTextBlockObject(slack.PlainTextType, "Revert and back-merge PRs", false, false),
+					slack.NewTextBlockObject(slack.PlainTextType, "Quiet down routine PRs that don't need review", false, false),
+					mechanicalSelect,
+				),
+				&slack.InputBlock{
+					Type:    slack.MBTInput,
+					BlockID: "repo_settings_emoji_input",
+					Label:   slack.NewTextBlockObject(slack.PlainTextType, "Emoji", false, false),
+					Hint: slack.NewTextBlockObject(slack.PlainTextType,
+						"Shown on PR messages instead of the PR size emoji. Leave empty to use the size emoji.", false, false),
+					Optional: true,
+					Element: &slack.PlainTextInputBlockElement{
+						Type:         slack.METPlainTextInput,
+						ActionID:     "repo_settings_emoji",
+						Placeholder:  slack.NewTextBlockObject(slack.PlainTextType, ":rocket:", false, false),
+						InitialValue: repo.Emoji,
+					},
+				},
+			},
+		},
+	}
+}
+
+// BuildDeleteRepoModal builds the confirmation for deleting a repository's configuration.
+func (b *HomeViewBuilder) BuildDeleteRepoModal(repoFullName string) slack.ModalViewRequest {
+	return slack.ModalViewRequest{
+		Type:            slack.VTModal,
+		Title:           slack.NewTextBlockObject(slack.PlainTextType, "Delete Repository", false, false),
+		CallbackID:      "confirm_delete_repo",
+		Submit:          slack.NewTextBlockObject(slack.PlainTextType, "Delete", false, false),
+		Close:           slack.NewTextBlockObject(slack.PlainTextType, "Cancel", false, false),
+		PrivateMetadata: repoFullName, // Store repo name in private metadata
+		Blocks: slack.Blocks{
+			BlockSet: []slack.Block{
+				slack.NewSectionBlock(
+					slack.NewTextBlockObject(slack.MarkdownType,
+						fmt.Sprintf("Delete *%s* and its settings and routing rules from this workspace?\n\n", repoFullName)+
+							"It's added back with default settings the next time a connected author's PR is posted. "+
+							"To stop its notifications for good, disable it instead.",
+						false, false),
+					nil, nil,
+				),
+			},
+		},
+	}
+}
+
+// BuildRoutingRulesRepoModal builds the modal for picking which repository's routing rules to edit.
+func (b *HomeViewBuilder) BuildRoutingRulesRepoModal(repos []*models.Repo) slack.ModalViewRequest {
+	if len(repos) == 0 {
+		return slack.ModalViewRequest{
+			Type:  slack.VTModal,
+			Title: slack.NewTextBlockObject(slack.PlainTextType, "Routing Rules", false, false),
+			Close: slack.NewTextBlockObject(slack.PlainTextType, "Close", false, false),
+			Blocks: slack.Blocks{
+				BlockSet: []slack.Block{
+					slack.NewSectionBlock(
+						slack.NewTextBlockObject(slack.MarkdownType,
+							"No repositories are set up in this workspace yet. "+
+								"Repositories are added when a connected author's PR is first posted.",
+							false, false),
+						nil, nil,
+					),
+				},
+			},
+		}
+	}
+
+	options := make([]*slack.OptionBlockObject, 0, min(len(repos), maxRoutingRulesRepoOptions))
+	for _, repo := range repos {
+		if len(options) == maxRoutingRulesRepoOptions {
+			break
+		}
+		label := repo.RepoFullName
+		if count := len(repo.RoutingRules); count > 0 {
+			label = fmt.Sprintf("%s (%d rules)", repo.RepoFullName, count)
+		}
+		options = append(options, slack.NewOptionBlockObject(repo.RepoFullName,
+			slack.NewTextBlockObject(slack.PlainTextType, label, false, false), nil))
+	}
+
+	return slack.ModalViewRequest{
+		Type:       slack.VTModal,
+		Title:      slack.NewTextBlockObject(slack.PlainTextType, "Routing Rules", false, false),
+		Close:      slack.NewTextBlockObject(slack.PlainTextType, "Cancel", false, false),
+		Submit:     slack.NewTextBlockObject(slack.PlainTextType, "Next", false, false),
+		CallbackID: "routing_rules_repo_selector",
+		Blocks: slack.Blocks{
+			BlockSet: []slack.Block{
+				slack.NewInputBlock(
+					"routing_rules_repo_input",
+					slack.NewTextBlockObject(slack.PlainTextType, "Repository", false, false),
+					nil,
+					slack.NewOptionsSelectBlockElement(slack.OptTypeStatic,
+						slack.NewTextBlockObject(slack.PlainTextType, "Choose a repository", false, false),
+						"routing_rules_repo_select", options...),
+				),
+			},
+		},
+	}
+}
+
+// BuildRoutingRulesModal builds the editor for a repository's routing rules.
+func (b *HomeViewBuilder) BuildRoutingRulesModal(repo *models.Repo) slack.ModalViewRequest {
+	return slack.ModalViewRequest{
+		Type:            slack.VTModal,
+		Title:           slack.NewTextBlockObject(slack.PlainTextType, "Routing Rules", false, false),
+		CallbackID:      "save_routing_rules",
+		Submit:          slack.NewTextBlockObject(slack.PlainTextType, "Save", false, false),
+		Close:           slack.NewTextBlockObject(slack.PlainTextType, "Back", false, false),
+		PrivateMetadata: repo.RepoFullName, // Store repo name in private metadata
+		Blocks: slack.Blocks{
+			BlockSet: []slack.Block{
+				slack.NewSectionBlock(
+					slack.NewTextBlockObject(slack.MarkdownType,
+						fmt.Sprintf("*Routing rules for %s*\n\n", repo.RepoFullName)+
+							"Route PRs to a channel by the files they change or their labels. "+
+							"Rules are checked in order and the first match wins.\n\n"+
+							"*Format:*\n"+
+							"• `paths: api/**, proto/** -> #backend` — any changed file matches a pattern\n"+
+							"• `label: infra -> #platform` — the PR has the label\n\n"+
+							"*Tips:*\n"+
+							"• `**` matches any number of directories, e.g. `**/*.proto`\n"+
+							"• A channel in the PR description or notification policy takes precedence\n"+
+							"• PRs matching no rule go to the author's default channel\n"+
+							"• The bot must be in each channel",
+						false, false),
+					nil, nil,
+				),
+				&slack.InputBlock{
+					Type:     slack.MBTInput,
+					BlockID:  "routing_rules_input",
+					Label:    slack.NewTextBlockObject(slack.PlainTextType, "Rules", false, false),
+					Hint:     slack.NewTextBlockObject(slack.PlainTextType, "One rule per line. Leave empty to remove all rules.", false, false),
+					Optional: true,
+					Element: &slack.PlainTextInputBlockElement{
+						Type:         slack.METPlainTextInput,
+						ActionID:     "routing_rules_text",
+						Placeholder:  slack.NewTextBlockObject(slack.PlainTextType, "paths: api/** -> #backend", false, false),
+						Multiline:    true,
+						InitialValue: utils.FormatRoutingRules(repo.RoutingRules),
+					},
+				},
+			},
+		},
+	}
+}
+
+// BuildTeamUserGroupsModal builds the editor for a workspace's GitHub team to Slack user group mappings.
+func (b *HomeViewBuilder) BuildTeamUserGroupsModal(mappings []models.TeamUserGroup) slack.ModalViewRequest {
+	return slack.ModalViewRequest{
+		Type:       slack.VTModal,
+		Title:      slack.NewTextBlockObject(slack.PlainTextType, "Team Review Requests", false, false),
+		CallbackID: "save_team_user_groups",
+		Submit:     slack.NewTextBlockObject(slack.PlainTextType, "Save", false, false),
+		Close:      slack.NewTextBlockObject(slack.PlainTextType, "Cancel", false, false),
+		Blocks: slack.Blocks{
+			BlockSet: []slack.Block{
+				slack.NewSectionBlock(
+					slack.NewTextBlockObject(slack.MarkdownType,
+						"When a GitHub team is requested to review a PR, its Slack user group is mentioned "+
+							"in the thread of the PR's message.\n\n"+
+							"*Format:*\n"+
+							"• `acme/backend -> @backend-devs` — the team's organization and slug, then the user group's handle\n\n"+
+							"*Tips:*\n"+
+							"• The team slug is the last part of its URL, e.g. `github.com/orgs/acme/teams/backend`\n"+
+							"• Requests for teams with no mapping aren't posted",
+						false, false),
+					nil, nil,
+				),
+				&slack.InputBlock{
+					Type:     slack.MBTInput,
+					BlockID:  "team_user_groups_input",
+					Label:    slack.NewTextBlockObject(slack.PlainTextType, "Team mappings", false, false),
+					Hint:     slack.NewTextBlockObject(slack.PlainTextType, "One team per line. Leave empty to remove all mappings.", false, false),
+					Optional: true,
+					Element: &slack.PlainTextInputBlockElement{
+						Type:         slack.METPlainTextInput,
+						ActionID:     "team_user_groups_text",
+						Placeholder:  slack.NewTextBlockObject(slack.PlainTextType, "acme/backend -> @backend-devs", false, false),
+						Multiline:    true,
+						InitialValue: utils.FormatTeamUserGroups(mappings),
+					},
+				},
+			},
+		},
+	}
+}
+
+// BuildMovePRNotificationModal builds the channel picker for moving a PR message with the message shortcut.
+func (b *HomeViewBuilder) BuildMovePRNotificationModal(message *models.TrackedMessage) slack.ModalViewRequest {
+	return slack.ModalViewRequest{
+		Type:       slack.VTModal,
+		Title:      slack.NewTextBlockObject(slack.PlainTextType, "Move PR Notification", false, false),
+		Close:      slack.NewTextBlockObject(slack.PlainTextType, "Cancel", false, false),
+		Submit:     slack.NewTextBlockObject(slack.PlainTextType, "Move", false, false),
+		CallbackID: "move_pr_notification",
+		Blocks: slack.Blocks{
+			BlockSet: []slack.Block{
+				slack.NewSectionBlock(
+					slack.NewTextBlockObject(slack.MarkdownType,
+						fmt.Sprintf("Move the notification for *%s#%d* from <#%s>. "+
+							"It's posted again in the new channel and deleted here.",
+							message.RepoFullName, message.PRNumber, message.SlackChannel),
+						false, false),
+					nil, nil,
+				),
+				slack.NewInputBlock(
+					"move_pr_channel_input",
+					slack.NewTextBlockObject(slack.PlainTextType, "New channel", false, false),
+					nil,
+					slack.NewOptionsSelectBlockElement(slack.OptTypeChannels,
+						slack.NewTextBlockObject(slack.PlainTextType, "Choose a channel", false, false),
+						"move_pr_channel_select"),
+				),
+			},
+		},
+	}
+}
+
+// BuildPostPRModal builds the form for posting a PR's notification with the "Post a PR" global shortcut.
+func (b *HomeViewBuilder) BuildPostPRModal() slack.ModalViewRequest {
+	urlInput := slack.NewPlainTextInputBlockElement(
+		slack.NewTextBlockObject(slack.PlainTextType, "https://github.com/owner/repo/pull/123", false, false),
+		"post_pr_url")
+	ccSelect := slack.NewOptionsMultiSelectBlockElement(slack.MultiOptTypeUser,
+		slack.NewTextBlockObject(slack.PlainTextType, "Choose people", false, false),
+		"post_pr_cc_select")
+	ccInput := slack.NewInputBlock(
+		"post_pr_cc_input",
+		slack.NewTextBlockObject(slack.PlainTextType, "CC", false, false),
+		slack.NewTextBlockObject(slack.PlainTextType,
+			"Optional. Replaces the CCs in the PR description. People must have connected GitHub", false, false),
+		ccSelect,
+	)
+	ccInput.Optional = true
+
+	return slack.ModalViewRequest{
+		Type:       slack.VTModal,
+		Title:      slack.NewTextBlockObject(slack.PlainTextType, "Post a PR", false, false),
+		Close:      slack.NewTextBlockObject(slack.PlainTextType, "Cancel", false, false),
+		Submit:     slack.NewTextBlockObject(slack.PlainTextType, "Post", false, false),
+		CallbackID: "post_pr",
+		Blocks: slack.Blocks{
+			BlockSet: []slack.Block{
+				slack.NewInputBlock(
+					"post_pr_url_input",
+					slack.NewTextBlockObject(slack.PlainTextType, "Pull request URL", false, false),
+					nil,
+					urlInput,
+				),
+				slack.NewInputBlock(
+					"post_pr_channel_input",
+					slack.NewTextBlockObject(slack.PlainTextType, "Channel", false, false),
+					nil,
+					slack.NewOptionsSelectBlockElement(slack.OptTypeChannels,
+						slack.NewTextBlockObject(slack.PlainTextType, "Choose a channel", false, false),
+						"post_pr_channel_select"),
+				),
+				ccInput,
+			},
+		},
+	}
+}
+
+// BuildReviewerRotationChannelModal builds the modal for picking which channel's reviewer rotation to edit.
+func (b *HomeViewBuilder) BuildReviewerRotationChannelModal() slack.ModalViewRequest {
+	return slack.ModalViewRequest{
+		Type:       slack.VTModal,
+		Title:      slack.NewTextBlockObject(slack.PlainTextType, "Reviewer Rotation", false, false),
+		Close:      slack.NewTextBlockObject(slack.PlainTextType, "Cancel", false, false),
+		Submit:     slack.NewTextBlockObject(slack.PlainTextType, "Next", false, false),
+		CallbackID: "reviewer_rotation_channel_selector",
+		Blocks: slack.Blocks{
+			BlockSet: []slack.Block{
+				slack.NewInputBlock(
+					"reviewer_rotation_channel_input",
+					slack.NewTextBlockObject(slack.PlainTextType, "Channel", false, false),
+					nil,
+					slack.NewOptionsSelectBlockElement(slack.OptTypeChannels,
+						slack.NewTextBlockObject(slack.PlainTextType, "Choose a channel", false, false),
+						"reviewer_rotation_channel_select"),
+				),
+			},
+		},
+	}
+}
+
+// BuildReviewerRotationModal builds the editor for a channel's reviewer pool.
+func (b *HomeViewBuilder) BuildReviewerRotationModal(
+	channelID, channelName string, rotation *models.ReviewerRotation,
+) slack.ModalViewRequest {
+	maxMembers := models.MaxReviewerRotationMembers
+	membersSelect := slack.NewOptionsMultiSelectBlockElement(slack.MultiOptTypeUser,
+		slack.NewTextBlockObject(slack.PlainTextType, "Choose reviewers", false, false),
+		"reviewer_rotation_members_select")
+	membersSelect.MaxSelectedItems = &maxMembers
+	if rotation != nil && len(rotation.Members) > 0 {
+		membersSelect.InitialUsers = rotation.Members
+	}
+
+	nextText := "No reviewers have been assigned yet."
+	if rotation != nil && len(rotation.Members) > 0 {
+		nextText = fmt.Sprintf("Next up: <@%s>", rotation.Members[rotation.NextIndex%len(rotation.Members)])
+	}
+
+	return slack.ModalViewRequest{
+		Type:            slack.VTModal,
+		Title:           slack.NewTextBlockObject(slack.PlainTextType, "Reviewer Rotation", false, false),
+		CallbackID:      "save_reviewer_rotation",
+		Submit:          slack.NewTextBlockObject(slack.PlainTextType, "Save", false, false),
+		Close:           slack.NewTextBlockObject(slack.PlainTextType, "Back", false, false),
+		PrivateMetadata: channelID, // Store channel ID in private metadata
+		Blocks: slack.Blocks{
+			BlockSet: []slack.Block{
+				slack.NewSectionBlock(
+					slack.NewTextBlockObject(slack.MarkdownType,
+						fmt.Sprintf("*Reviewer rotation for #%s*\n\n", channelName)+
+							"When a PR is posted here without anyone CC'd, the next reviewer in the pool is mentioned "+
+							"and asked to review it on GitHub. Authors are skipped on their own PRs.\n\n"+
+							"Reviewers must have connected their GitHub account. "+
+							"The GitHub App needs *Pull requests: Read & write* to request reviews.\n\n"+
+							nextText,
+						false, false),
+					nil, nil,
+				),
+				&slack.InputBlock{
+					Type:    slack.MBTInput,
+					BlockID: "reviewer_rotation_members_input",
+					Label:   slack.NewTextBlockObject(slack.PlainTextType, "Reviewers", false, false),
+					Hint: slack.NewTextBlockObject(slack.PlainTextType,
+						"Reviewers are assigned in this order. Leave empty to turn the rotation off.", false, false),
+					Optional: true,
+					Element:  membersSelect,
+				},
+			},
+		},
+	}
+}
+
+// BuildDailyDigestBlocks builds the daily PR digest DM, with reviews waiting on the user first.
+func (b *HomeViewBuilder) BuildDailyDigestBlocks(digest *models.DailyDigest, now time.Time) []slack.Block {
+	blocks := []slack.Block{
+		slack.NewHeaderBlock(slack.NewTextBlockObject(slack.PlainTextType, "☀️ Your daily PR digest", false, false)),
+	}
+
+	sections := []struct {
+		title string
+		prs   []models.DailyDigestPR
+	}{
+		{"Waiting on your review", digest.PendingReviews},
+		{"Your open PRs", digest.OpenPRs},
+	}
+	for _, section := range sections {
+		if len(section.prs) == 0 {
+			continue
+		}
+		text := fmt.Sprintf("*%s* (%d)\n%s", section.title, len(section.prs), utils.FormatDailyDigestPRs(section.prs, now))
+		blocks = append(blocks, slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, text, false, false), nil, nil))
+	}
+
+	return append(blocks, slack.NewContextBlock("",
+		slack.NewTextBlockObject(slack.MarkdownType, "_Change the time or turn off this digest in the app's Home tab_", false, false),
+	))
+}
+
+// ActivePRsPageSize returns how many PRs the "Active PRs" section can list per page without taking a Home view
+// that already has blocksInView blocks past Slack's block limit.
+func (b *HomeViewBuilder) ActivePRsPageSize(blocksInView int) int {
+	return max(min(maxActivePRsPerPage, (maxHomeViewBlocks-blocksInView-activePRsSectionBlocks)/blocksPerActivePR), 0)
+}
+
+// AddActivePRsSection inserts the "Active PRs" section before the "App setup" header of a built Home view.
+func (b *HomeViewBuilder) AddActivePRsSection(
+	view *slack.HomeTabViewRequest, prs []models.ActivePR, page, pageCount int, now time.Time,
+) {
+	blocks := view.Blocks.BlockSet
+	at := slices.IndexFunc(blocks, func(block slack.Block) bool {
+		header, ok := block.(*slack.HeaderBlock)
+		return ok && header.BlockID == appSetupBlockID
+	})
+	if at < 0 {
+		at = len(blocks)
+	}
+	view.Blocks.BlockSet = slices.Insert(blocks, at, b.BuildActivePRsSection(prs, page, pageCount, now)...)
+}
+
+// BuildActivePRsSection builds the App Home "Active PRs" section listing one page of a user's open PRs, each with
+// its channels, review status and age, and buttons to jump to its Slack message or GitHub.
+func (b *HomeViewBuilder) BuildActivePRsSection(prs []models.ActivePR, page, pageCount int, now time.Time) []slack.Block {
+	summary := "_Your open PRs with notifications from the last 90 days_"
+	if pageCount > 1 {
+		summary = fmt.Sprintf("_Your open PRs with notifications from the last 90 days • Page %d of %d_", page+1, pageCount)
+	}
+
+	blocks := []slack.Block{
+		slack.NewHeaderBlock(slack.NewTextBlockObject(slack.PlainTextType, "📋 Active PRs", false, false)),
+		slack.NewContextBlock("", slack.NewTextBlockObject(slack.MarkdownType, summary, false, false)),
+	}
+
+	if len(prs) == 0 {
+		blocks = append(blocks, slack.NewSectionBlock(
+			slack.NewTextBlockObject(slack.MarkdownType, "_You have no open PRs right now_", false, false), nil, nil,
+		))
+	}
 
-		if impersonationEnabled {
-			impersonationStatus = "✅ Enabled - Your PRs appear to come from you"
-			impersonationToggleText = "Disable impersonation"
-			impersonationToggleStyle = slack.StyleDanger
-		} else {
-			impersonationStatus = "❌ Disabled - Your PRs come from the bot"
-			impersonationToggleText = "Enable impersonation"
-			impersonationToggleStyle = slack.StylePrimary
+	for _, pr := range prs {
+		msg := pr.Message
+		prURL := fmt.Sprintf("https://github.com/%s/pull/%d", msg.RepoFullName, msg.PRNumber)
+		text := fmt.Sprintf("*<%s|%s#%d>* %s\n%s • %s • opened %s ago",
+			prURL, msg.RepoFullName, msg.PRNumber, utils.EscapeSlackText(msg.PRTitle),
+			formatChannelMentions(pr.Channels), describeActivePRStatus(msg), utils.FormatElapsed(now.Sub(msg.CreatedAt)))
+
+		buttons := []slack.BlockElement{}
+		if pr.Permalink != "" {
+			viewMessage := slack.NewButtonBlockElement("active_pr_view_message", "",
+				slack.NewTextBlockObject(slack.PlainTextType, "💬 View message", false, false))
+			viewMessage.URL = pr.Permalink
+			buttons = append(buttons, viewMessage)
 		}
+		openOnGitHub := slack.NewButtonBlockElement("active_pr_open_github", "",
+			slack.NewTextBlockObject(slack.PlainTextType, "🔗 Open on GitHub", false, false))
+		openOnGitHub.URL = prURL
+		buttons = append(buttons, openOnGitHub)
 
-		// Only show toggle if notifications are enabled
-		if user != nil && user.NotificationsEnabled {
-			impersonationAccessory = slack.NewAccessory(
-				slack.NewButtonBlockElement(
-					"toggle_impersonation",
-					"toggle_impersonation",
-					slack.NewTextBlockObject(slack.PlainTextType, impersonationToggleText, false, false),
-				).WithStyle(impersonationToggleStyle),
-			)
+		blocks = append(blocks,
+			slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, text, false, false), nil, nil),
+			slack.NewActionBlock("", buttons...),
+		)
+	}
+
+	if pageCount > 1 {
+		pagination := []slack.BlockElement{}
+		if page > 0 {
+			pagination = append(pagination, slack.NewButtonBlockElement("active_prs_previous", strconv.Itoa(page-1),
+				slack.NewTextBlockObject(slack.PlainTextType, "← Previous", false, false)))
+		}
+		if page+1 < pageCount {
+			pagination = append(pagination, slack.NewButtonBlockElement("active_prs_next", strconv.Itoa(page+1),
+				slack.NewTextBlockObject(slack.PlainTextType, "Next →", false, false)))
 		}
+		blocks = append(blocks, slack.NewActionBlock("active_prs_pagination", pagination...))
 	}
 
-	impersonationSectionText := slack.NewTextBlockObject(slack.MarkdownType,
-		fmt.Sprintf("Control message appearance\n_%s - When enabled, PR notifications "+
-			"appear to be posted by you instead of the bot_", impersonationStatus),
-		false, false)
+	return append(blocks, slack.NewDividerBlock())
+}
 
-	return []slack.Block{
-		slack.NewSectionBlock(impersonationSectionText, nil, impersonationAccessory),
+// describeActivePRStatus summarizes an active PR's review status, CI failures and merge conflicts.
+func describeActivePRStatus(msg *models.TrackedMessage) string {
+	status := []string{"👀 Awaiting review"}
+	switch {
+	case msg.Approvals == 1:
+		status[0] = "✅ 1 approval"
+	case msg.Approvals > 1:
+		status[0] = fmt.Sprintf("✅ %d approvals", msg.Approvals)
+	case msg.NoReviewersWarning:
+		status[0] = "👤 No reviewers requested"
+	}
+	if msg.CIState == models.CIStateFailure {
+		status = append(status, "🔴 CI failing")
 	}
+	if msg.MergeConflict {
+		status = append(status, "⚠️ Merge conflict")
+	}
+	return strings.Join(status, " • ")
 }
 
 // buildChannelTrackingSection builds the channel tracking settings section.
@@ -399,6 +1889,28 @@ func (b *HomeViewBuilder) BuildOAuthModal(oauthURL string) slack.ModalViewReques
 	}
 }
 
+// BuildSlackUserTokenModal builds the modal linking to the Slack user token authorization.
+func (b *HomeViewBuilder) BuildSlackUserTokenModal(oauthURL string) slack.ModalViewRequest {
+	return slack.ModalViewRequest{
+		Type:  slack.VTModal,
+		Title: slack.NewTextBlockObject(slack.PlainTextType, "Post with your account", false, false),
+		Blocks: slack.Blocks{
+			BlockSet: []slack.Block{
+				slack.NewSectionBlock(
+					slack.NewTextBlockObject(slack.MarkdownType,
+						"*Authorise PR Bot to post PR notifications as you*\n\n"+
+							fmt.Sprintf("<%s|:point_right: Authorise with Slack>\n\n", oauthURL)+
+							"PR Bot only asks to send messages as you. PRs posted to channels you're not a member of "+
+							"still use your name and avatar.\n\n"+
+							"_This link expires in 15 minutes._",
+						false, false),
+					nil, nil,
+				),
+			},
+		},
+	}
+}
+
 // BuildGitHubInstallationModal builds the GitHub App installation modal.
 func (b *HomeViewBuilder) BuildGitHubInstallationModal(oauthURL string) slack.ModalViewRequest {
 	return slack.ModalViewRequest{
@@ -426,32 +1938,60 @@ func (b *HomeViewBuilder) BuildGitHubInstallationModal(oauthURL string) slack.Mo
 	}
 }
 
-// BuildChannelSelectorModal builds the channel selector modal.
-func (b *HomeViewBuilder) BuildChannelSelectorModal() slack.ModalViewRequest {
+// BuildChannelSelectorModal builds the channel selector modal, with the user's default channels
+// and per-repository channels filled in.
+func (b *HomeViewBuilder) BuildChannelSelectorModal(user *models.User) slack.ModalViewRequest {
+	maxChannels := models.MaxDefaultChannels
+	channelSelect := slack.NewOptionsMultiSelectBlockElement(
+		slack.MultiOptTypeChannels,
+		slack.NewTextBlockObject(slack.PlainTextType, "Choose public channels", false, false),
+		"channel_select",
+	)
+	channelSelect.MaxSelectedItems = &maxChannels
+
+	var repoChannels string
+	if user != nil {
+		channelSelect.InitialChannels = user.GetDefaultChannels()
+		repoChannels = utils.FormatRepoChannelOverrides(user.RepoChannels)
+	}
+
 	return slack.ModalViewRequest{
 		Type:       slack.VTModal,
-		Title:      slack.NewTextBlockObject(slack.PlainTextType, "Select channel", false, false),
+		Title:      slack.NewTextBlockObject(slack.PlainTextType, "Select channels", false, false),
 		CallbackID: "channel_selector",
 		Submit:     slack.NewTextBlockObject(slack.PlainTextType, "Save", false, false),
 		Blocks: slack.Blocks{
 			BlockSet: []slack.Block{
 				slack.NewSectionBlock(
-					slack.NewTextBlockObject(slack.MarkdownType, "Select default channel for PRs to be posted to:\n\n"+
-						":information_source: The bot will automatically join public channels when selected.\n"+
-						":warning: Private channels are not supported for security reasons.",
+					slack.NewTextBlockObject(slack.MarkdownType,
+						fmt.Sprintf("Select up to %d default channels for PRs to be posted to:\n\n", models.MaxDefaultChannels)+
+							":information_source: The bot will automatically join public channels when selected.\n"+
+							":warning: Private channels are not supported for security reasons.",
 						false, false),
 					nil, nil,
 				),
 				slack.NewInputBlock(
 					"channel_input",
-					slack.NewTextBlockObject(slack.PlainTextType, "Channel", false, false),
+					slack.NewTextBlockObject(slack.PlainTextType, "Channels", false, false),
 					nil, // No hint text
-					slack.NewOptionsSelectBlockElement(
-						slack.OptTypeChannels,
-						slack.NewTextBlockObject(slack.PlainTextType, "Choose a public channel", false, false),
-						"channel_select",
-					),
+					channelSelect,
 				),
+				&slack.InputBlock{
+					Type:    slack.MBTInput,
+					BlockID: "repo_channels_input",
+					Label:   slack.NewTextBlockObject(slack.PlainTextType, "Per-repository channels", false, false),
+					Hint: slack.NewTextBlockObject(slack.PlainTextType,
+						"One repository per line, e.g. owner/api -> #backend, #api-reviews. "+
+							"PRs in these repositories go to their channels instead of the defaults.", false, false),
+					Optional: true,
+					Element: &slack.PlainTextInputBlockElement{
+						Type:         slack.METPlainTextInput,
+						ActionID:     "repo_channels_text",
+						Placeholder:  slack.NewTextBlockObject(slack.PlainTextType, "owner/repo -> #channel", false, false),
+						Multiline:    true,
+						InitialValue: repoChannels,
+					},
+				},
 			},
 		},
 	}
@@ -493,6 +2033,21 @@ func (b *HomeViewBuilder) BuildChannelTrackingModal(configs []*models.ChannelCon
 			if !config.ManualTrackingEnabled {
 				status = "❌ Tracking Disabled"
 			}
+			if config.ReactionSet != "" && config.ReactionSet != models.ReactionSetAll {
+				status += fmt.Sprintf(" · Reactions: %s", reactionSetDisplayName(config.ReactionSet))
+			}
+			if config.ProjectContext {
+				status += " · Project context"
+			}
+			if config.ReviewCommentThreads {
+				status += " · Review comment threads"
+			}
+			if config.RepostPruned {
+				status += " · Repost after retention"
+			}
+			if config.ArchiveClosedPRs {
+				status += " · Archive closed PRs"
+			}
 			blocks = append(blocks, slack.NewContextBlock(
 				"",
 				slack.NewTextBlockObject(slack.MarkdownType,
@@ -520,12 +2075,96 @@ func (b *HomeViewBuilder) BuildChannelTrackingModal(configs []*models.ChannelCon
 }
 
 // BuildChannelTrackingConfigModal builds the modal for configuring a specific channel's tracking settings.
-func (b *HomeViewBuilder) BuildChannelTrackingConfigModal(channelID, channelName string, currentlyEnabled bool) slack.ModalViewRequest {
+// A nil currentConfig means the channel uses the defaults.
+func (b *HomeViewBuilder) BuildChannelTrackingConfigModal(
+	channelID, channelName string, currentConfig *models.ChannelConfig,
+) slack.ModalViewRequest {
+	// Default to enabled with all reactions if no config exists
+	currentlyEnabled := true
+	reactionSet := models.ReactionSetAll
+	var releaseCutDeadline *time.Time
+	projectContext := false
+	reviewCommentThreads := false
+	repostPruned := false
+	archiveClosedPRs := false
+	richMessages := false
+	var filters *models.PRFilters
+	var prSizeConfig *models.PRSizeConfiguration
+	if currentConfig != nil {
+		currentlyEnabled = currentConfig.ManualTrackingEnabled
+		if currentConfig.ReactionSet != "" {
+			reactionSet = currentConfig.ReactionSet
+		}
+		releaseCutDeadline = currentConfig.ReleaseCutDeadline
+		projectContext = currentConfig.ProjectContext
+		reviewCommentThreads = currentConfig.ReviewCommentThreads
+		repostPruned = currentConfig.RepostPruned
+		archiveClosedPRs = currentConfig.ArchiveClosedPRs
+		richMessages = currentConfig.RichMessagesEnabled()
+		filters = currentConfig.Filters
+		prSizeConfig = currentConfig.PRSizeConfig
+	}
+
 	currentSettingText := "Enabled"
 	if !currentlyEnabled {
 		currentSettingText = "Disabled"
 	}
 
+	releaseCutPicker := slack.NewDateTimePickerBlockElement("release_cut_datetime")
+	if releaseCutDeadline != nil {
+		releaseCutPicker.InitialDateTime = releaseCutDeadline.Unix()
+	}
+
+	projectContextOption := slack.NewOptionBlockObject(
+		"enabled",
+		slack.NewTextBlockObject(slack.PlainTextType, "Show milestone and project board column", false, false),
+		slack.NewTextBlockObject(slack.PlainTextType, "PR messages show e.g. \"Sprint 42 • In Review\"", false, false),
+	)
+	projectContextCheckbox := slack.NewCheckboxGroupsBlockElement("project_context_checkbox", projectContextOption)
+	if projectContext {
+		projectContextCheckbox.InitialOptions = []*slack.OptionBlockObject{projectContextOption}
+	}
+
+	reviewCommentThreadsOption := slack.NewOptionBlockObject(
+		"enabled",
+		slack.NewTextBlockObject(slack.PlainTextType, "Post review comments in the PR's thread", false, false),
+		slack.NewTextBlockObject(slack.PlainTextType, "PR authors can turn this off for their own PRs", false, false),
+	)
+	reviewCommentThreadsCheckbox := slack.NewCheckboxGroupsBlockElement("review_comment_threads_checkbox", reviewCommentThreadsOption)
+	if reviewCommentThreads {
+		reviewCommentThreadsCheckbox.InitialOptions = []*slack.OptionBlockObject{reviewCommentThreadsOption}
+	}
+
+	repostPrunedOption := slack.NewOptionBlockObject(
+		"enabled",
+		slack.NewTextBlockObject(slack.PlainTextType, "Repost open PRs removed by message retention", false, false),
+		slack.NewTextBlockObject(slack.PlainTextType, "Otherwise the bot stops tracking them", false, false),
+	)
+	repostPrunedCheckbox := slack.NewCheckboxGroupsBlockElement("repost_pruned_checkbox", repostPrunedOption)
+	if repostPruned {
+		repostPrunedCheckbox.InitialOptions = []*slack.OptionBlockObject{repostPrunedOption}
+	}
+
+	archiveClosedPRsOption := slack.NewOptionBlockObject(
+		"enabled",
+		slack.NewTextBlockObject(slack.PlainTextType, "Condense messages of merged and closed PRs", false, false),
+		slack.NewTextBlockObject(slack.PlainTextType, "Edited to a single struck-through line", false, false),
+	)
+	archiveClosedPRsCheckbox := slack.NewCheckboxGroupsBlockElement("archive_closed_prs_checkbox", archiveClosedPRsOption)
+	if archiveClosedPRs {
+		archiveClosedPRsCheckbox.InitialOptions = []*slack.OptionBlockObject{archiveClosedPRsOption}
+	}
+
+	richMessagesOption := slack.NewOptionBlockObject(
+		models.MessageFormatRich,
+		slack.NewTextBlockObject(slack.PlainTextType, "Use the rich message layout", false, false),
+		slack.NewTextBlockObject(slack.PlainTextType, "Title button, author avatar, labels and reviewers", false, false),
+	)
+	richMessagesCheckbox := slack.NewCheckboxGroupsBlockElement("message_format_checkbox", richMessagesOption)
+	if richMessages {
+		richMessagesCheckbox.InitialOptions = []*slack.OptionBlockObject{richMessagesOption}
+	}
+
 	// Truncate channel name if needed to fit in title (max 24 chars)
 	const maxChannelNameLength = 15
 	const truncatedLength = 12
@@ -544,7 +2183,7 @@ func (b *HomeViewBuilder) BuildChannelTrackingConfigModal(channelID, channelName
 			BlockSet: []slack.Block{
 				slack.NewSectionBlock(
 					slack.NewTextBlockObject(slack.MarkdownType,
-						"*Manual PR Link Tracking:*",
+						"*Manual PR and Issue Link Tracking:*",
 						false, false),
 					nil, nil,
 				),
@@ -557,12 +2196,14 @@ func (b *HomeViewBuilder) BuildChannelTrackingConfigModal(channelID, channelName
 						slack.NewOptionBlockObject(
 							"true",
 							slack.NewTextBlockObject(slack.PlainTextType, "Enabled (Default)", false, false),
-							slack.NewTextBlockObject(slack.PlainTextType, "The bot will track GitHub PR links posted by users in this channel", false, false),
+							slack.NewTextBlockObject(slack.PlainTextType,
+								"The bot will track GitHub PR and issue links posted by users in this channel", false, false),
 						),
 						slack.NewOptionBlockObject(
 							"false",
 							slack.NewTextBlockObject(slack.PlainTextType, "Disabled", false, false),
-							slack.NewTextBlockObject(slack.PlainTextType, "The bot will ignore GitHub PR links posted by users in this channel", false, false),
+							slack.NewTextBlockObject(slack.PlainTextType,
+								"The bot will ignore GitHub PR and issue links posted by users in this channel", false, false),
 						),
 					),
 				),
@@ -572,11 +2213,222 @@ func (b *HomeViewBuilder) BuildChannelTrackingConfigModal(channelID, channelName
 						fmt.Sprintf("_Current Setting: %s_", currentSettingText),
 						false, false),
 				),
+				slack.NewDividerBlock(),
+				slack.NewSectionBlock(
+					slack.NewTextBlockObject(slack.MarkdownType,
+						"*PR Status Reactions:*",
+						false, false),
+					nil, nil,
+				),
+				slack.NewInputBlock(
+					"reaction_set_input",
+					slack.NewTextBlockObject(slack.PlainTextType, "Reactions", false, false),
+					slack.NewTextBlockObject(slack.PlainTextType, "Choose reactions", false, false),
+					slack.NewRadioButtonsBlockElement(
+						"reaction_set_radio",
+						slack.NewOptionBlockObject(
+							models.ReactionSetAll,
+							slack.NewTextBlockObject(slack.PlainTextType, "All (Default)", false, false),
+							slack.NewTextBlockObject(slack.PlainTextType, "Review, CI and merged/closed reactions", false, false),
+						),
+						slack.NewOptionBlockObject(
+							models.ReactionSetStateOnly,
+							slack.NewTextBlockObject(slack.PlainTextType, "Merged/closed only", false, false),
+							slack.NewTextBlockObject(slack.PlainTextType, "Skip review and CI reactions", false, false),
+						),
+						slack.NewOptionBlockObject(
+							models.ReactionSetNone,
+							slack.NewTextBlockObject(slack.PlainTextType, "None", false, false),
+							slack.NewTextBlockObject(slack.PlainTextType, "No reactions, the message text shows merged/closed instead", false, false),
+						),
+					),
+				),
+				slack.NewContextBlock(
+					"",
+					slack.NewTextBlockObject(slack.MarkdownType,
+						fmt.Sprintf("_Current Setting: %s_", reactionSetDisplayName(reactionSet)),
+						false, false),
+				),
+				slack.NewDividerBlock(),
+				&slack.InputBlock{
+					Type:    slack.MBTInput,
+					BlockID: "release_cut_input",
+					Label:   slack.NewTextBlockObject(slack.PlainTextType, "Release cut deadline", false, false),
+					Hint: slack.NewTextBlockObject(slack.PlainTextType,
+						"Open PRs show a countdown to this time. Pick a past time to turn it off.", false, false),
+					Optional: true,
+					Element:  releaseCutPicker,
+				},
+				&slack.InputBlock{
+					Type:     slack.MBTInput,
+					BlockID:  "project_context_input",
+					Label:    slack.NewTextBlockObject(slack.PlainTextType, "Project context", false, false),
+					Optional: true,
+					Element:  projectContextCheckbox,
+				},
+				&slack.InputBlock{
+					Type:     slack.MBTInput,
+					BlockID:  "review_comment_threads_input",
+					Label:    slack.NewTextBlockObject(slack.PlainTextType, "Review comments", false, false),
+					Optional: true,
+					Element:  reviewCommentThreadsCheckbox,
+				},
+				&slack.InputBlock{
+					Type:     slack.MBTInput,
+					BlockID:  "repost_pruned_input",
+					Label:    slack.NewTextBlockObject(slack.PlainTextType, "Message retention", false, false),
+					Optional: true,
+					Element:  repostPrunedCheckbox,
+				},
+				&slack.InputBlock{
+					Type:     slack.MBTInput,
+					BlockID:  "archive_closed_prs_input",
+					Label:    slack.NewTextBlockObject(slack.PlainTextType, "Closed PRs", false, false),
+					Optional: true,
+					Element:  archiveClosedPRsCheckbox,
+				},
+				&slack.InputBlock{
+					Type:     slack.MBTInput,
+					BlockID:  "message_format_input",
+					Label:    slack.NewTextBlockObject(slack.PlainTextType, "Message layout", false, false),
+					Optional: true,
+					Element:  richMessagesCheckbox,
+				},
+				slack.NewDividerBlock(),
+				slack.NewSectionBlock(
+					slack.NewTextBlockObject(slack.MarkdownType,
+						"*PR Filters:*\n"+channelFiltersSummary(filters),
+						false, false),
+					nil,
+					slack.NewAccessory(slack.NewButtonBlockElement(
+						"edit_channel_filters",
+						channelID,
+						slack.NewTextBlockObject(slack.PlainTextType, "Edit filters", false, false),
+					)),
+				),
+				slack.NewSectionBlock(
+					slack.NewTextBlockObject(slack.MarkdownType,
+						"*PR size emoji:*\n"+prsize.Describe(prSizeConfig),
+						false, false),
+					nil,
+					slack.NewAccessory(slack.NewButtonBlockElement(
+						"edit_channel_pr_size",
+						channelID,
+						slack.NewTextBlockObject(slack.PlainTextType, "Edit PR size emoji", false, false),
+					)),
+				),
+			},
+		},
+	}
+}
+
+// channelFiltersSummary describes which PRs a channel's filters let through.
+func channelFiltersSummary(filters *models.PRFilters) string {
+	if filters.IsEmpty() {
+		return "Every PR routed here is posted."
+	}
+
+	var lines []string
+	switch {
+	case filters.MinPRSize > 0 && filters.MaxPRSize > 0:
+		lines = append(lines, fmt.Sprintf("• %d to %d lines changed", filters.MinPRSize, filters.MaxPRSize))
+	case filters.MinPRSize > 0:
+		lines = append(lines, fmt.Sprintf("• At least %d lines changed", filters.MinPRSize))
+	case filters.MaxPRSize > 0:
+		lines = append(lines, fmt.Sprintf("• At most %d lines changed", filters.MaxPRSize))
+	}
+	if len(filters.AllowAuthors) > 0 {
+		lines = append(lines, "• Only PRs by "+strings.Join(filters.AllowAuthors, ", "))
+	}
+	if len(filters.DenyAuthors) > 0 {
+		lines = append(lines, "• Not PRs by "+strings.Join(filters.DenyAuthors, ", "))
+	}
+	if len(filters.BranchPatterns) > 0 {
+		lines = append(lines, "• Base branch matches `"+strings.Join(filters.BranchPatterns, "`, `")+"`")
+	}
+	if filters.TitlePattern != "" {
+		lines = append(lines, "• Title matches `"+filters.TitlePattern+"`")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// BuildChannelFiltersModal builds the editor for the filters limiting which PRs are posted to a channel.
+// Pushed over the channel's tracking settings; every field is optional.
+func (b *HomeViewBuilder) BuildChannelFiltersModal(channelID string, filters *models.PRFilters) slack.ModalViewRequest {
+	if filters == nil {
+		filters = &models.PRFilters{}
+	}
+
+	sizeValue := func(size int) string {
+		if size == 0 {
+			return ""
+		}
+		return strconv.Itoa(size)
+	}
+	textInput := func(blockID, actionID, label, hint, placeholder, initialValue string) *slack.InputBlock {
+		return &slack.InputBlock{
+			Type:     slack.MBTInput,
+			BlockID:  blockID,
+			Label:    slack.NewTextBlockObject(slack.PlainTextType, label, false, false),
+			Hint:     slack.NewTextBlockObject(slack.PlainTextType, hint, false, false),
+			Optional: true,
+			Element: &slack.PlainTextInputBlockElement{
+				Type:         slack.METPlainTextInput,
+				ActionID:     actionID,
+				Placeholder:  slack.NewTextBlockObject(slack.PlainTextType, placeholder, false, false),
+				InitialValue: initialValue,
+			},
+		}
+	}
+
+	return slack.ModalViewRequest{
+		Type:            slack.VTModal,
+		Title:           slack.NewTextBlockObject(slack.PlainTextType, "PR Filters", false, false),
+		CallbackID:      "save_channel_filters",
+		Submit:          slack.NewTextBlockObject(slack.PlainTextType, "Save", false, false),
+		Close:           slack.NewTextBlockObject(slack.PlainTextType, "Back", false, false),
+		PrivateMetadata: channelID, // Store channel ID in private metadata
+		Blocks: slack.Blocks{
+			BlockSet: []slack.Block{
+				slack.NewSectionBlock(
+					slack.NewTextBlockObject(slack.MarkdownType,
+						fmt.Sprintf("PRs routed to <#%s> are only posted if they pass every filter set here. "+
+							"Leave a field empty to not filter on it.", channelID),
+						false, false),
+					nil, nil,
+				),
+				textInput("channel_filters_min_size_input", "channel_filters_min_size", "Minimum PR size",
+					"Lines changed (additions plus deletions).", "e.g. 10", sizeValue(filters.MinPRSize)),
+				textInput("channel_filters_max_size_input", "channel_filters_max_size", "Maximum PR size",
+					"Lines changed (additions plus deletions).", "e.g. 500", sizeValue(filters.MaxPRSize)),
+				textInput("channel_filters_allow_authors_input", "channel_filters_allow_authors", "Only these authors",
+					"GitHub usernames, separated by commas or spaces.", "e.g. octocat, hubot",
+					strings.Join(filters.AllowAuthors, ", ")),
+				textInput("channel_filters_deny_authors_input", "channel_filters_deny_authors", "Not these authors",
+					"GitHub usernames, separated by commas or spaces. Useful for bots.", "e.g. dependabot[bot]",
+					strings.Join(filters.DenyAuthors, ", ")),
+				textInput("channel_filters_branches_input", "channel_filters_branches", "Base branches",
+					"Globs the PR's base branch must match one of, separated by commas or spaces.", "e.g. main, release/*",
+					strings.Join(filters.BranchPatterns, ", ")),
+				textInput("channel_filters_title_input", "channel_filters_title", "Title pattern",
+					"A regular expression the PR title must match.", "e.g. ^(feat|fix)", filters.TitlePattern),
 			},
 		},
 	}
 }
 
+// reactionSetDisplayName returns a human-readable name for a channel reaction set.
+func reactionSetDisplayName(reactionSet string) string {
+	switch reactionSet {
+	case models.ReactionSetStateOnly:
+		return "Merged/closed only"
+	case models.ReactionSetNone:
+		return "None"
+	default:
+		return "All"
+	}
+}
+
 // buildIntroductionSection builds the introduction section explaining what PR Bot does.
 func (b *HomeViewBuilder) buildIntroductionSection(user *models.User) []slack.Block {
 	// Show different intro based on GitHub connection status
@@ -728,13 +2580,17 @@ func (b *HomeViewBuilder) BuildGitHubInstallationsModal(
 		)
 	} else {
 		for _, installation := range installations {
-			// Build the management URL for this installation
+			// Build the management URL for this installation, on its GitHub Enterprise Server instance if it has one
+			webURL := "https://github.com"
+			if host := installation.GitHubHost(); host != "" {
+				webURL = "https://" + host
+			}
 			var managementURL string
 			if installation.AccountType == "Organization" {
-				managementURL = fmt.Sprintf("https://github.com/organizations/%s/settings/installations/%d",
-					installation.AccountLogin, installation.ID)
+				managementURL = fmt.Sprintf("%s/organizations/%s/settings/installations/%d",
+					webURL, installation.AccountLogin, installation.ID)
 			} else {
-				managementURL = fmt.Sprintf("https://github.com/settings/installations/%d", installation.ID)
+				managementURL = fmt.Sprintf("%s/settings/installations/%d", webURL, installation.ID)
 			}
 
 			// Build repository info
@@ -748,14 +2604,22 @@ func (b *HomeViewBuilder) BuildGitHubInstallationsModal(
 			blocks = append(blocks,
 				slack.NewSectionBlock(
 					slack.NewTextBlockObject(slack.MarkdownType,
-						fmt.Sprintf("*%s* (%s)\n%s • Installed %s\n<%s|:point_right: Manage on GitHub>",
+						fmt.Sprintf("*%s* (%s)\n%s • Installed %s\nNew repositories: %s\n<%s|:point_right: Manage on GitHub>",
 							installation.AccountLogin,
 							installation.AccountType,
 							repoInfo,
 							installation.InstalledAt.Format("Jan 2, 2006"),
+							describeInstallationDefaults(installation.Defaults),
 							managementURL),
 						false, false),
-					nil, nil,
+					nil,
+					slack.NewAccessory(
+						slack.NewButtonBlockElement(
+							"edit_installation_defaults",
+							strconv.FormatInt(installation.ID, 10),
+							slack.NewTextBlockObject(slack.PlainTextType, "Defaults", false, false),
+						),
+					),
 				),
 			)
 		}
@@ -798,6 +2662,136 @@ func (b *HomeViewBuilder) BuildGitHubInstallationsModal(
 	}
 }
 
+// describeInstallationDefaults summarizes the settings an installation's new repositories start with,
+// e.g. "Compact · <#C1> · :rocket:".
+func describeInstallationDefaults(defaults *models.InstallationDefaults) string {
+	if defaults.IsEmpty() {
+		return "workspace defaults"
+	}
+	parts := []string{}
+	if defaults.NotificationMode != "" {
+		parts = append(parts, notificationModeLabels[defaults.NotificationMode])
+	}
+	if defaults.DefaultChannel != "" {
+		parts = append(parts, fmt.Sprintf("<#%s>", defaults.DefaultChannel))
+	}
+	if defaults.Emoji != "" {
+		parts = append(parts, defaults.Emoji)
+	}
+	return strings.Join(parts, " · ")
+}
+
+// BuildInstallationDefaultsModal builds the editor for the settings repositories auto-registered from an
+// installation start with. It's pushed on top of the installations modal.
+func (b *HomeViewBuilder) BuildInstallationDefaultsModal(installation *models.GitHubInstallation) slack.ModalViewRequest {
+	defaults := installation.Defaults
+	if defaults == nil {
+		defaults = &models.InstallationDefaults{}
+	}
+
+	channelSelect := slack.NewOptionsSelectBlockElement(slack.OptTypeChannels,
+		slack.NewTextBlockObject(slack.PlainTextType, "Authors' channels", false, false),
+		"installation_defaults_channel_select")
+	channelSelect.InitialChannel = defaults.DefaultChannel
+
+	modeOptions := make([]*slack.OptionBlockObject, 0, len(notificationModeLabels))
+	var initialMode *slack.OptionBlockObject
+	for _, mode := range []string{models.NotificationModeFull, models.NotificationModeCompact, models.NotificationModeDigestOnly} {
+		option := slack.NewOptionBlockObject(mode,
+			slack.NewTextBlockObject(slack.PlainTextType, notificationModeLabels[mode], false, false), nil)
+		modeOptions = append(modeOptions, option)
+		if mode == defaults.NotificationMode {
+			initialMode = option
+		}
+	}
+	modeSelect := slack.NewOptionsSelectBlockElement(slack.OptTypeStatic,
+		slack.NewTextBlockObject(slack.PlainTextType, notificationModeLabels[models.NotificationModeFull], false, false),
+		"installation_defaults_mode_select", modeOptions...)
+	modeSelect.InitialOption = initialMode
+
+	return slack.ModalViewRequest{
+		Type:            slack.VTModal,
+		Title:           slack.NewTextBlockObject(slack.PlainTextType, "Installation Defaults", false, false),
+		CallbackID:      "save_installation_defaults",
+		Submit:          slack.NewTextBlockObject(slack.PlainTextType, "Save", false, false),
+		Close:           slack.NewTextBlockObject(slack.PlainTextType, "Back", false, false),
+		PrivateMetadata: strconv.FormatInt(installation.ID, 10), // Store installation ID in private metadata
+		Blocks: slack.Blocks{
+			BlockSet: []slack.Block{
+				slack.NewSectionBlock(
+					slack.NewTextBlockObject(slack.MarkdownType,
+						fmt.Sprintf("*%s*\nRepositories registered automatically from this installation start with these settings. "+
+							"Repositories that are already registered keep their own settings.", installation.AccountLogin),
+						false, false),
+					nil, nil,
+				),
+				&slack.InputBlock{
+					Type:     slack.MBTInput,
+					BlockID:  "installation_defaults_channel_input",
+					Label:    slack.NewTextBlockObject(slack.PlainTextType, "Default channel", false, false),
+					Hint:     slack.NewTextBlockObject(slack.PlainTextType, "Leave empty to use the authors' channels.", false, false),
+					Optional: true,
+					Element:  channelSelect,
+				},
+				&slack.InputBlock{
+					Type:     slack.MBTInput,
+					BlockID:  "installation_defaults_mode_input",
+					Label:    slack.NewTextBlockObject(slack.PlainTextType, "Notification mode", false, false),
+					Optional: true,
+					Element:  modeSelect,
+				},
+				&slack.InputBlock{
+					Type:    slack.MBTInput,
+					BlockID: "installation_defaults_emoji_input",
+					Label:   slack.NewTextBlockObject(slack.PlainTextType, "Emoji", false, false),
+					Hint: slack.NewTextBlockObject(slack.PlainTextType,
+						"Shown on PR messages instead of the PR size emoji. Leave empty to use the size emoji.", false, false),
+					Optional: true,
+					Element: &slack.PlainTextInputBlockElement{
+						Type:         slack.METPlainTextInput,
+						ActionID:     "installation_defaults_emoji",
+						Placeholder:  slack.NewTextBlockObject(slack.PlainTextType, ":rocket:", false, false),
+						InitialValue: defaults.Emoji,
+					},
+				},
+			},
+		},
+	}
+}
+
+// buildYourDataSection builds the section for exporting or deleting the data stored about the user.
+func (b *HomeViewBuilder) buildYourDataSection() []slack.Block {
+	return []slack.Block{
+		slack.NewSectionBlock(
+			slack.NewTextBlockObject(slack.MarkdownType,
+				"*Your data*\n_Get a copy of everything PR Bot stores about you as a DM, or delete it_", false, false),
+			nil, nil,
+		),
+		slack.NewActionBlock(
+			"your_data",
+			slack.NewButtonBlockElement(
+				"export_my_data",
+				"export",
+				slack.NewTextBlockObject(slack.PlainTextType, "Export my data", false, false),
+			),
+			slack.NewButtonBlockElement(
+				"delete_my_data",
+				"delete",
+				slack.NewTextBlockObject(slack.PlainTextType, "Delete my data", false, false),
+			).WithStyle(slack.StyleDanger).WithConfirm(
+				slack.NewConfirmationBlockObject(
+					slack.NewTextBlockObject(slack.PlainTextType, "Delete your data?", false, false),
+					slack.NewTextBlockObject(slack.MarkdownType,
+						"This disconnects your GitHub account, removes your settings, and stops your PRs being linked to you. "+
+							"It can't be undone.", false, false),
+					slack.NewTextBlockObject(slack.PlainTextType, "Yes, delete", false, false),
+					slack.NewTextBlockObject(slack.PlainTextType, "Cancel", false, false),
+				),
+			),
+		),
+	}
+}
+
 // buildPRSizeConfigSection builds the PR size emoji configuration section.
 func (b *HomeViewBuilder) buildPRSizeConfigSection(user *models.User) []slack.Block {
 	blocks := []slack.Block{
@@ -817,7 +2811,7 @@ func (b *HomeViewBuilder) buildPRSizeConfigSection(user *models.User) []slack.Bl
 		buttonText = "Configure PR emojis"
 		buttonStyle = slack.StyleDefault
 	} else {
-		configStatus = ":no_good: Using default animal emojis"
+		configStatus = ":no_good: Using your channel's or workspace's emojis, or the default animal emojis"
 		buttonText = "Configure PR emojis"
 		buttonStyle = slack.StylePrimary
 	}
@@ -841,13 +2835,41 @@ func (b *HomeViewBuilder) buildPRSizeConfigSection(user *models.User) []slack.Bl
 	return blocks
 }
 
-// BuildPRSizeConfigModal builds the PR size emoji configuration modal.
+// BuildPRSizeConfigModal builds the PR size emoji configuration modal for a user's own PRs.
 func (b *HomeViewBuilder) BuildPRSizeConfigModal(user *models.User) slack.ModalViewRequest {
+	var config *models.PRSizeConfiguration
+	if user != nil {
+		config = user.PRSizeConfig
+	}
+	return b.buildPRSizeConfigModal("Configure PR Emojis", "pr_size_config", "", config,
+		"Your emoji are used on your PRs wherever they're posted, ahead of any channel or workspace emoji.",
+		"To go back to the channel's or workspace's emoji, or the default animal emojis: ")
+}
+
+// BuildChannelPRSizeConfigModal builds the PR size emoji configuration modal for a channel's PRs.
+// The channel ID is stored in the modal's private metadata.
+func (b *HomeViewBuilder) BuildChannelPRSizeConfigModal(channelID string, config *models.PRSizeConfiguration) slack.ModalViewRequest {
+	return b.buildPRSizeConfigModal("Channel PR Emojis", "save_channel_pr_size", channelID, config,
+		fmt.Sprintf("These emoji are used on PRs posted to <#%s>, unless their author chose their own.", channelID),
+		"To use the workspace's emoji again: ")
+}
+
+// BuildWorkspacePRSizeConfigModal builds the PR size emoji configuration modal for the whole workspace.
+func (b *HomeViewBuilder) BuildWorkspacePRSizeConfigModal(config *models.PRSizeConfiguration) slack.ModalViewRequest {
+	return b.buildPRSizeConfigModal("Workspace PR Emojis", "save_workspace_pr_size", "", config,
+		"These emoji are used in every channel, unless the channel or the PR's author chose their own.",
+		"To go back to the default animal emojis: ")
+}
+
+// buildPRSizeConfigModal builds the PR size emoji editor shared by the user, channel and workspace levels,
+// starting from the level's configuration, or the default thresholds as an example if it hasn't set one.
+func (b *HomeViewBuilder) buildPRSizeConfigModal(
+	title, callbackID, privateMetadata string, config *models.PRSizeConfiguration, scope, reset string,
+) slack.ModalViewRequest {
 	// Prepare current configuration as text for the input
 	var currentConfig string
-	if user != nil && user.PRSizeConfig != nil && user.PRSizeConfig.Enabled && len(user.PRSizeConfig.Thresholds) > 0 {
-		// Use user's custom configuration
-		currentConfig = utils.FormatPRSizeThresholds(user.PRSizeConfig.Thresholds)
+	if prsize.IsSet(config) {
+		currentConfig = utils.FormatPRSizeThresholds(config.Thresholds)
 	} else {
 		// Show default config as an example
 		defaultThresholds := utils.GetDefaultPRSizeThresholds()
@@ -855,18 +2877,19 @@ func (b *HomeViewBuilder) BuildPRSizeConfigModal(user *models.User) slack.ModalV
 	}
 
 	return slack.ModalViewRequest{
-		Type:       slack.VTModal,
-		Title:      slack.NewTextBlockObject(slack.PlainTextType, "Configure PR Emojis", false, false),
-		CallbackID: "pr_size_config",
-		Submit:     slack.NewTextBlockObject(slack.PlainTextType, "Save", false, false),
-		Close:      slack.NewTextBlockObject(slack.PlainTextType, "Cancel", false, false),
+		Type:            slack.VTModal,
+		Title:           slack.NewTextBlockObject(slack.PlainTextType, title, false, false),
+		CallbackID:      callbackID,
+		PrivateMetadata: privateMetadata,
+		Submit:          slack.NewTextBlockObject(slack.PlainTextType, "Save", false, false),
+		Close:           slack.NewTextBlockObject(slack.PlainTextType, "Cancel", false, false),
 		Blocks: slack.Blocks{
 			BlockSet: []slack.Block{
 				slack.NewSectionBlock(
 					slack.NewTextBlockObject(slack.MarkdownType,
 						"*Customize PR size emojis and thresholds*\n\n"+
 							"Configure which emoji appears based on number of lines changed in a PR. "+
-							"Each line must contain an emoji and a *maximum* line count.\n\n"+
+							"Each line must contain an emoji and a *maximum* line count. "+scope+"\n\n"+
 							"*Format:* `:emoji_name: max_lines`\n"+
 							"*Examples:*\n"+
 							"• `:ant: 5` — PRs with ≤5 lines get 🐜\n"+
@@ -897,8 +2920,7 @@ func (b *HomeViewBuilder) BuildPRSizeConfigModal(user *models.User) slack.ModalV
 				},
 				slack.NewSectionBlock(
 					slack.NewTextBlockObject(slack.MarkdownType,
-						"*Reset to defaults*\nTo go back to the default animal emojis: "+
-							"*delete all text in the box*, and then save.",
+						"*Reset*\n"+reset+"*delete all text in the box*, and then save.",
 						false, false),
 					nil, nil,
 				),
@@ -0,0 +1,173 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github-slack-notifier/internal/models"
+	snapshotTesting "github-slack-notifier/internal/testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHomeViewBuilder_BuildHomeView_Snapshots(t *testing.T) {
+	b := NewHomeViewBuilder()
+
+	t.Run("new user without installations", func(t *testing.T) {
+		snapshotTesting.MatchSnapshot(t, "home_view_new_user", b.BuildHomeView(nil, false, nil))
+	})
+
+	t.Run("connected user", func(t *testing.T) {
+		user := &models.User{
+			GitHubUsername:       "octocat",
+			GitHubUserID:         1,
+			Verified:             true,
+			SlackUserID:          "U123",
+			SlackTeamID:          "T123",
+			DefaultChannel:       "C123",
+			DefaultChannels:      []string{"C123"},
+			NotificationsEnabled: true,
+			TaggingEnabled:       true,
+		}
+		installations := []*models.GitHubInstallation{{
+			ID:                  42,
+			AccountLogin:        "octo-org",
+			AccountType:         "Organization",
+			RepositorySelection: "all",
+		}}
+		snapshotTesting.MatchSnapshot(t, "home_view_connected_user", b.BuildHomeView(user, true, installations))
+	})
+}
+
+func TestHomeViewBuilder_Modal_Snapshots(t *testing.T) {
+	b := NewHomeViewBuilder()
+
+	snapshotTesting.MatchSnapshot(t, "repo_settings_modal", b.BuildRepoSettingsModal(&models.Repo{
+		RepoFullName:     "octo-org/widgets",
+		WorkspaceID:      "T123",
+		Enabled:          true,
+		DefaultChannel:   "C123",
+		NotificationMode: models.NotificationModeCompact,
+	}))
+	snapshotTesting.MatchSnapshot(t, "channel_tracking_config_modal", b.BuildChannelTrackingConfigModal("C123", "reviews", nil))
+	snapshotTesting.MatchSnapshot(t, "channel_filters_modal", b.BuildChannelFiltersModal("C123", &models.PRFilters{
+		MaxPRSize:      500,
+		DenyAuthors:    []string{"dependabot[bot]"},
+		BranchPatterns: []string{"main", "release/*"},
+	}))
+	snapshotTesting.MatchSnapshot(t, "channel_pr_size_config_modal", b.BuildChannelPRSizeConfigModal("C123", &models.PRSizeConfiguration{
+		Enabled:    true,
+		Thresholds: []models.PRSizeThreshold{{MaxLines: 50, Emoji: ":small_blue_diamond:"}, {MaxLines: 500, Emoji: ":large_blue_diamond:"}},
+	}))
+	snapshotTesting.MatchSnapshot(t, "message_template_modal",
+		b.BuildMessageTemplateModal("{{.Link}} from {{.Repo}}{{if .CC}} — {{.CC}} please review{{end}}"))
+	snapshotTesting.MatchSnapshot(t, "installation_defaults_modal", b.BuildInstallationDefaultsModal(&models.GitHubInstallation{
+		ID:           42,
+		AccountLogin: "octo-org",
+		Defaults: &models.InstallationDefaults{
+			DefaultChannel:   "C123",
+			NotificationMode: models.NotificationModeCompact,
+			Emoji:            ":rocket:",
+		},
+	}))
+	snapshotTesting.MatchSnapshot(t, "post_pr_modal", b.BuildPostPRModal())
+	snapshotTesting.MatchSnapshot(t, "team_user_groups_modal", b.BuildTeamUserGroupsModal([]models.TeamUserGroup{
+		{GitHubTeam: "octo-org/backend", SlackUserGroupID: "S123", SlackUserGroupHandle: "backend-devs"},
+	}))
+	snapshotTesting.MatchSnapshot(t, "mute_repo_modal", b.BuildMuteRepoModal("octo-org/widgets"))
+	snapshotTesting.MatchSnapshot(t, "mute_repo_modal_from_app_home", b.BuildMuteRepoModal(""))
+}
+
+func TestHomeViewBuilder_BuildMutedReposSection(t *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	user := &models.User{MutedRepos: []models.RepoMute{
+		{RepoFullName: "octo-org/widgets", Until: now.Add(models.RepoMuteWeek)},
+		{RepoFullName: "octo-org/expired", Until: now.Add(-time.Hour)},
+	}}
+
+	snapshotTesting.MatchSnapshot(t, "muted_repos_section", NewHomeViewBuilder().buildMutedReposSection(user, now))
+}
+
+func TestHomeViewBuilder_BuildRevokedWorkspacesSection(t *testing.T) {
+	revokedAt := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	workspaces := []*models.SlackWorkspace{
+		{ID: "T2", TeamName: "Acme Design", RevokedAt: &revokedAt, RevokedReason: "account_inactive"},
+	}
+	sharedRepos := map[string][]string{"T2": {"octo-org/widgets", "octo-org/gadgets"}}
+
+	b := NewHomeViewBuilder()
+	assert.Empty(t, b.BuildRevokedWorkspacesSection(nil, nil, "https://notifier.example.com/auth/slack/install"))
+	snapshotTesting.MatchSnapshot(t, "revoked_workspaces_section",
+		b.BuildRevokedWorkspacesSection(workspaces, sharedRepos, "https://notifier.example.com/auth/slack/install"))
+}
+
+func TestHomeViewBuilder_BuildDailyDigestBlocks_Snapshot(t *testing.T) {
+	now := time.Date(2025, 1, 10, 9, 0, 0, 0, time.UTC)
+	digest := &models.DailyDigest{
+		OpenPRs: []models.DailyDigestPR{{
+			RepoFullName: "octo-org/widgets",
+			PRNumber:     12,
+			Title:        "Add widget caching",
+			URL:          "https://github.com/octo-org/widgets/pull/12",
+			Since:        now.Add(-26 * time.Hour),
+		}},
+		PendingReviews: []models.DailyDigestPR{{
+			RepoFullName: "octo-org/widgets",
+			PRNumber:     15,
+			Title:        "Fix quoting in widget names",
+			URL:          "https://github.com/octo-org/widgets/pull/15",
+			Since:        now.Add(-3 * time.Hour),
+			RequestedBy:  "hubot",
+		}},
+	}
+
+	snapshotTesting.MatchSnapshot(t, "daily_digest", NewHomeViewBuilder().BuildDailyDigestBlocks(digest, now))
+}
+
+func TestHomeViewBuilder_BuildActivePRsSection_Snapshot(t *testing.T) {
+	now := time.Date(2025, 1, 10, 9, 0, 0, 0, time.UTC)
+	prs := []models.ActivePR{
+		{
+			Message: &models.TrackedMessage{
+				RepoFullName: "octo-org/widgets",
+				PRNumber:     12,
+				PRTitle:      "Add widget caching",
+				CreatedAt:    now.Add(-26 * time.Hour),
+				Approvals:    2,
+			},
+			Channels:  []string{"C123", "C456"},
+			Permalink: "https://example.slack.com/archives/C123/p1736414400000100",
+		},
+		{
+			Message: &models.TrackedMessage{
+				RepoFullName:       "octo-org/widgets",
+				PRNumber:           15,
+				PRTitle:            "Fix <quoting> in widget names",
+				CreatedAt:          now.Add(-3 * time.Hour),
+				NoReviewersWarning: true,
+				CIState:            models.CIStateFailure,
+				MergeConflict:      true,
+			},
+			Channels: []string{"C123"},
+		},
+	}
+
+	snapshotTesting.MatchSnapshot(t, "active_prs_section", NewHomeViewBuilder().BuildActivePRsSection(prs, 1, 3, now))
+}
+
+func TestHomeViewBuilder_ActivePRsPageSize(t *testing.T) {
+	b := NewHomeViewBuilder()
+
+	tests := []struct {
+		blocksInView int
+		want         int
+	}{
+		{blocksInView: 40, want: 5},
+		{blocksInView: 88, want: 4},
+		{blocksInView: 95, want: 0},
+		{blocksInView: 100, want: 0},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, b.ActivePRsPageSize(tt.blocksInView), "blocks in view: %d", tt.blocksInView)
+	}
+}
@@ -0,0 +1,16 @@
+package utils
+
+import "fmt"
+
+// FormatApprovalQuorum returns the approval count line shown on PR messages, e.g. ":white_check_mark: 2/2 approvals",
+// marked with a check once the PR has the approvals it needs. An empty result means there's nothing to show.
+func FormatApprovalQuorum(approvals, required int) string {
+	if required <= 0 {
+		return ""
+	}
+	emoji := ":ballot_box_with_check:"
+	if approvals >= required {
+		emoji = ":white_check_mark:"
+	}
+	return fmt.Sprintf("%s %d/%d approvals", emoji, approvals, required)
+}
@@ -0,0 +1,15 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatApprovalQuorum(t *testing.T) {
+	assert.Equal(t, ":ballot_box_with_check: 0/2 approvals", FormatApprovalQuorum(0, 2))
+	assert.Equal(t, ":ballot_box_with_check: 1/2 approvals", FormatApprovalQuorum(1, 2))
+	assert.Equal(t, ":white_check_mark: 2/2 approvals", FormatApprovalQuorum(2, 2))
+	assert.Equal(t, ":white_check_mark: 3/2 approvals", FormatApprovalQuorum(3, 2))
+	assert.Empty(t, FormatApprovalQuorum(1, 0), "nothing to show without required approvals")
+}
@@ -0,0 +1,19 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FormatChannelIntro returns the message the bot posts when it's invited to a channel, explaining how to have PRs
+// posted there with description directives and that PR links shared there are tracked.
+func FormatChannelIntro(channelName string) string {
+	return strings.Join([]string{
+		":wave: Thanks for adding me! I post GitHub pull requests to Slack and keep their messages up to date " +
+			"with reactions as they're reviewed, merged or closed.",
+		fmt.Sprintf("• *Post PRs here:* pick this channel as your default in my App Home, or add `!review: #%s` "+
+			"to a PR's description. `!review: @username` CCs someone, and `!review-skip` keeps a PR out of Slack.", channelName),
+		"• *Shared links:* PRs linked in messages here are tracked too, and get the same reactions.",
+		"Use *Configure channel* to turn off link tracking or change which reactions are synced here.",
+	}, "\n")
+}
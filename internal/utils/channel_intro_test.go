@@ -0,0 +1,15 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatChannelIntro(t *testing.T) {
+	intro := FormatChannelIntro("backend-reviews")
+
+	assert.Contains(t, intro, "`!review: #backend-reviews`")
+	assert.Contains(t, intro, "`!review-skip`")
+	assert.Contains(t, intro, "*Configure channel*")
+}
@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"slices"
+	"strings"
+)
+
+// CodeOwnersRule is a CODEOWNERS line: a path pattern and the owners of matching files.
+type CodeOwnersRule struct {
+	Pattern string
+	Owners  []string
+}
+
+// ParseCodeOwners parses the rules of a CODEOWNERS file, in file order.
+// Comments and blank lines are skipped. A pattern with no owners is kept, since it clears ownership.
+func ParseCodeOwners(content string) []CodeOwnersRule {
+	var rules []CodeOwnersRule
+	for _, line := range strings.Split(content, "\n") {
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		rules = append(rules, CodeOwnersRule{Pattern: fields[0], Owners: fields[1:]})
+	}
+	return rules
+}
+
+// CodeOwnersFor returns the GitHub usernames owning any of the changed files, without the leading "@",
+// in the order they're first found. As on GitHub, the last matching rule for a file wins.
+// Team owners (@org/team) and email owners are skipped, since they can't be CC'd as a single user.
+func CodeOwnersFor(rules []CodeOwnersRule, files []string) []string {
+	var owners []string
+	for _, file := range files {
+		for i := len(rules) - 1; i >= 0; i-- {
+			if !matchCodeOwnersPattern(rules[i].Pattern, file) {
+				continue
+			}
+			for _, owner := range rules[i].Owners {
+				username, ok := strings.CutPrefix(owner, "@")
+				if !ok || strings.Contains(username, "/") || slices.Contains(owners, username) {
+					continue
+				}
+				owners = append(owners, username)
+			}
+			break
+		}
+	}
+	return owners
+}
+
+// matchCodeOwnersPattern reports whether a file path matches a CODEOWNERS pattern.
+// Patterns follow gitignore rules: a pattern without a leading or inner slash matches at any depth,
+// a trailing slash matches a directory's contents, and a pattern matching a directory covers everything under it.
+func matchCodeOwnersPattern(pattern, file string) bool {
+	if pattern == "*" {
+		return true
+	}
+
+	anchored := strings.HasPrefix(pattern, "/") || strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	if strings.HasSuffix(pattern, "/") {
+		pattern += "**"
+	}
+	if !anchored {
+		pattern = "**/" + pattern
+	}
+
+	return MatchPathGlob(pattern, file) || MatchPathGlob(pattern+"/**", file)
+}
@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCodeOwners(t *testing.T) {
+	content := `# Default owners
+*       @octocat
+
+/docs/  @docs-writer @org/docs   # team owners are kept here
+*.go    @gopher
+/vendor/
+`
+
+	rules := ParseCodeOwners(content)
+
+	assert.Equal(t, []CodeOwnersRule{
+		{Pattern: "*", Owners: []string{"@octocat"}},
+		{Pattern: "/docs/", Owners: []string{"@docs-writer", "@org/docs"}},
+		{Pattern: "*.go", Owners: []string{"@gopher"}},
+		{Pattern: "/vendor/", Owners: []string{}},
+	}, rules)
+}
+
+func TestCodeOwnersFor(t *testing.T) {
+	rules := ParseCodeOwners(`*                 @octocat
+*.go              @gopher
+/docs/            @docs-writer @org/docs writer@example.com
+apps/api          @api-owner
+/vendor/
+`)
+
+	tests := []struct {
+		name     string
+		files    []string
+		expected []string
+	}{
+		{
+			name:     "default owner",
+			files:    []string{"README.md"},
+			expected: []string{"octocat"},
+		},
+		{
+			name:     "unanchored pattern matches at any depth",
+			files:    []string{"internal/handlers/github.go"},
+			expected: []string{"gopher"},
+		},
+		{
+			name:     "teams and emails are skipped",
+			files:    []string{"docs/guides/setup.md"},
+			expected: []string{"docs-writer"},
+		},
+		{
+			name:     "path pattern covers the directory's contents",
+			files:    []string{"apps/api/server.ts"},
+			expected: []string{"api-owner"},
+		},
+		{
+			name:     "last matching rule wins, even when it has no owners",
+			files:    []string{"vendor/lib/lib.go"},
+			expected: nil,
+		},
+		{
+			name:     "owners are deduplicated across files",
+			files:    []string{"main.go", "README.md", "cmd/toolbox/main.go"},
+			expected: []string{"gopher", "octocat"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, CodeOwnersFor(rules, tt.files))
+		})
+	}
+}
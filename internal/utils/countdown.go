@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+
+	"github-slack-notifier/internal/models"
+)
+
+const hoursPerDay = 24
+
+// FormatReleaseCountdown returns the countdown line shown on open PR messages in release cut channels,
+// e.g. "Release cut in 6h (Fri, Jan 10 3:00 PM PST) — needs review", with the cut time in the workspace's timezone.
+// Once the deadline has passed the line reports the missed cut.
+func FormatReleaseCountdown(deadline, now time.Time, reviewState string, timeFormat LocalTimeFormat) string {
+	status := releaseCountdownStatus(reviewState)
+
+	remaining := deadline.Sub(now)
+	if remaining <= 0 {
+		return "Release cut passed — " + status
+	}
+
+	return fmt.Sprintf("Release cut in %s (%s) — %s", formatCountdownDuration(remaining), timeFormat.DateTime(deadline), status)
+}
+
+// releaseCountdownStatus describes what an open PR still needs before the release cut.
+func releaseCountdownStatus(reviewState string) string {
+	switch models.ReviewState(reviewState) {
+	case models.ReviewStateApproved:
+		return "approved, ready to merge"
+	case models.ReviewStateChangesRequested:
+		return "changes requested"
+	default:
+		return "needs review"
+	}
+}
+
+// FormatElapsed formats how long ago something happened as a compact duration (e.g. "2d 4h", "6h", "45m").
+func FormatElapsed(d time.Duration) string {
+	return formatCountdownDuration(d)
+}
+
+// formatCountdownDuration formats a positive duration as a compact countdown (e.g. "2d 4h", "6h", "45m").
+func formatCountdownDuration(d time.Duration) string {
+	hours := int(d / time.Hour)
+	switch {
+	case hours >= hoursPerDay:
+		return fmt.Sprintf("%dd %dh", hours/hoursPerDay, hours%hoursPerDay)
+	case hours >= 1:
+		return fmt.Sprintf("%dh", hours)
+	default:
+		minutes := int(d / time.Minute)
+		if minutes < 1 {
+			minutes = 1
+		}
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github-slack-notifier/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatReleaseCountdown(t *testing.T) {
+	now := time.Date(2025, 1, 10, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		deadline    time.Time
+		reviewState string
+		expected    string
+	}{
+		{
+			name:        "hours remaining without reviews",
+			deadline:    now.Add(6 * time.Hour),
+			reviewState: "",
+			expected:    "Release cut in 6h (Fri, Jan 10 3:00 PM UTC) — needs review",
+		},
+		{
+			name:        "days remaining and approved",
+			deadline:    now.Add(52 * time.Hour),
+			reviewState: string(models.ReviewStateApproved),
+			expected:    "Release cut in 2d 4h (Sun, Jan 12 1:00 PM UTC) — approved, ready to merge",
+		},
+		{
+			name:        "minutes remaining with changes requested",
+			deadline:    now.Add(45 * time.Minute),
+			reviewState: string(models.ReviewStateChangesRequested),
+			expected:    "Release cut in 45m (Fri, Jan 10 9:45 AM UTC) — changes requested",
+		},
+		{
+			name:        "under a minute rounds up",
+			deadline:    now.Add(20 * time.Second),
+			reviewState: string(models.ReviewStateCommented),
+			expected:    "Release cut in 1m (Fri, Jan 10 9:00 AM UTC) — needs review",
+		},
+		{
+			name:        "deadline passed",
+			deadline:    now.Add(-time.Hour),
+			reviewState: "",
+			expected:    "Release cut passed — needs review",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, FormatReleaseCountdown(tt.deadline, now, tt.reviewState, LocalTimeFormat{}))
+		})
+	}
+}
+
+func TestFormatReleaseCountdown_WorkspaceTimeFormat(t *testing.T) {
+	now := time.Date(2025, 1, 10, 9, 0, 0, 0, time.UTC)
+	timeFormat := WorkspaceTimeFormat(&models.SlackWorkspace{Timezone: "Europe/Berlin", Locale: "en-GB"})
+
+	assert.Equal(t, "Release cut in 6h (Fri 10 Jan 16:00 CET) — needs review",
+		FormatReleaseCountdown(now.Add(6*time.Hour), now, "", timeFormat))
+}
@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"strings"
+	"time"
+
+	"github-slack-notifier/internal/models"
+)
+
+// Date and time layouts for the locale styles shown in messages.
+const (
+	usDateLayout        = "Mon, Jan 2"
+	usDateTimeLayout    = "Mon, Jan 2 3:04 PM MST"
+	dayFirstDateLayout  = "Mon 2 Jan"
+	dayFirstTimeLayout  = "Mon 2 Jan 15:04 MST"
+	yearFirstDateLayout = "2006-01-02 (Mon)"
+	yearFirstTimeLayout = "2006-01-02 (Mon) 15:04 MST"
+)
+
+// LocalTimeFormat formats dates and times shown in messages for a workspace's timezone and locale.
+// The zero value formats in UTC with the default (en-US) style.
+type LocalTimeFormat struct {
+	Location *time.Location
+	Locale   string // Slack locale, e.g. "en-GB"
+}
+
+// WorkspaceTimeFormat returns the time format for a workspace's timezone and locale settings.
+func WorkspaceTimeFormat(workspace *models.SlackWorkspace) LocalTimeFormat {
+	if workspace == nil {
+		return LocalTimeFormat{}
+	}
+	return LocalTimeFormat{Location: workspace.Location(), Locale: workspace.GetLocale()}
+}
+
+// Date formats the date of t, e.g. "Mon, Jan 2" for en-US or "Mon 2 Jan" for en-GB.
+func (f LocalTimeFormat) Date(t time.Time) string {
+	dateLayout, _ := f.layouts()
+	return t.In(f.location()).Format(dateLayout)
+}
+
+// DateTime formats the date and time of t, e.g. "Mon, Jan 2 3:04 PM PST" for en-US or "Mon 2 Jan 15:04 GMT" for en-GB.
+func (f LocalTimeFormat) DateTime(t time.Time) string {
+	_, dateTimeLayout := f.layouts()
+	return t.In(f.location()).Format(dateTimeLayout)
+}
+
+func (f LocalTimeFormat) location() *time.Location {
+	if f.Location == nil {
+		return time.UTC
+	}
+	return f.Location
+}
+
+// layouts picks the date and date-time layouts for the locale: month first for US English,
+// year first for Chinese, Japanese and Korean, and day first otherwise.
+func (f LocalTimeFormat) layouts() (string, string) {
+	locale := f.Locale
+	if locale == "" {
+		locale = models.DefaultWorkspaceLocale
+	}
+
+	language, _, _ := strings.Cut(locale, "-")
+	switch {
+	case strings.EqualFold(locale, "en-US"):
+		return usDateLayout, usDateTimeLayout
+	case language == "ja", language == "ko", language == "zh":
+		return yearFirstDateLayout, yearFirstTimeLayout
+	default:
+		return dayFirstDateLayout, dayFirstTimeLayout
+	}
+}
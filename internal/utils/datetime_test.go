@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github-slack-notifier/internal/models"
+)
+
+func TestLocalTimeFormat(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	assert.NoError(t, err)
+	moment := time.Date(2025, 1, 10, 16, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name             string
+		format           LocalTimeFormat
+		expectedDate     string
+		expectedDateTime string
+	}{
+		{
+			name:             "zero value is UTC and en-US",
+			format:           LocalTimeFormat{},
+			expectedDate:     "Fri, Jan 10",
+			expectedDateTime: "Fri, Jan 10 4:30 PM UTC",
+		},
+		{
+			name:             "day first locale in timezone",
+			format:           LocalTimeFormat{Location: berlin, Locale: "de-DE"},
+			expectedDate:     "Fri 10 Jan",
+			expectedDateTime: "Fri 10 Jan 17:30 CET",
+		},
+		{
+			name:             "year first locale",
+			format:           LocalTimeFormat{Locale: "ja-JP"},
+			expectedDate:     "2025-01-10 (Fri)",
+			expectedDateTime: "2025-01-10 (Fri) 16:30 UTC",
+		},
+		{
+			name:             "workspace settings",
+			format:           WorkspaceTimeFormat(&models.SlackWorkspace{Timezone: "Europe/Berlin", Locale: "en-GB"}),
+			expectedDate:     "Fri 10 Jan",
+			expectedDateTime: "Fri 10 Jan 17:30 CET",
+		},
+		{
+			name:             "workspace without settings",
+			format:           WorkspaceTimeFormat(&models.SlackWorkspace{}),
+			expectedDate:     "Fri, Jan 10",
+			expectedDateTime: "Fri, Jan 10 4:30 PM UTC",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedDate, tt.format.Date(moment))
+			assert.Equal(t, tt.expectedDateTime, tt.format.DateTime(moment))
+		})
+	}
+}
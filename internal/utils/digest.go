@@ -0,0 +1,118 @@
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github-slack-notifier/internal/models"
+)
+
+const (
+	// maxDigestEntries limits how many events or PRs are listed individually in a digest.
+	maxDigestEntries = 20
+	// maxDailyDigestPRs limits how many PRs are listed in each daily digest section, keeping it within
+	// Slack's section text limit.
+	maxDailyDigestPRs = 10
+)
+
+// FormatUserDigest formats buffered digest entries as a single Slack DM, oldest first.
+func FormatUserDigest(entries []*models.DigestEntry) string {
+	sorted := make([]*models.DigestEntry, len(entries))
+	copy(sorted, entries)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].CreatedAt.Before(sorted[j].CreatedAt)
+	})
+
+	var b strings.Builder
+	noun := "updates"
+	if len(sorted) == 1 {
+		noun = "update"
+	}
+	fmt.Fprintf(&b, "*Your PR digest* — %d %s since the last digest\n", len(sorted), noun)
+
+	for i, entry := range sorted {
+		if i == maxDigestEntries {
+			fmt.Fprintf(&b, "_…and %d more_\n", len(sorted)-maxDigestEntries)
+			break
+		}
+		fmt.Fprintf(&b, "• %s <%s|%s#%d %s>\n",
+			describeDigestEvent(entry), entry.PRURL, entry.RepoFullName, entry.PRNumber, entry.PRTitle)
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// FormatChannelDigest formats the daily channel digest of new PRs from digest-only repositories, oldest first.
+// date is the digest's day, formatted for the workspace.
+func FormatChannelDigest(entries []*models.DigestEntry, date string) string {
+	sorted := make([]*models.DigestEntry, len(entries))
+	copy(sorted, entries)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].CreatedAt.Before(sorted[j].CreatedAt)
+	})
+
+	var b strings.Builder
+	noun := "PRs"
+	if len(sorted) == 1 {
+		noun = "PR"
+	}
+	fmt.Fprintf(&b, "*Daily PR digest for %s* — %d new %s\n", date, len(sorted), noun)
+
+	for i, entry := range sorted {
+		if i == maxDigestEntries {
+			fmt.Fprintf(&b, "_…and %d more_\n", len(sorted)-maxDigestEntries)
+			break
+		}
+		fmt.Fprintf(&b, "• <%s|%s#%d %s> by %s\n", entry.PRURL, entry.RepoFullName, entry.PRNumber, entry.PRTitle, entry.Actor)
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// FormatDailyDigestPRs formats a section of the daily PR digest as mrkdwn list lines, oldest first.
+// Pending reviews say who requested them; open PRs say how long they've been open.
+func FormatDailyDigestPRs(prs []models.DailyDigestPR, now time.Time) string {
+	sorted := make([]models.DailyDigestPR, len(prs))
+	copy(sorted, prs)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Since.Before(sorted[j].Since)
+	})
+
+	var b strings.Builder
+	for i, pr := range sorted {
+		if i == maxDailyDigestPRs {
+			fmt.Fprintf(&b, "_…and %d more_\n", len(sorted)-maxDailyDigestPRs)
+			break
+		}
+		fmt.Fprintf(&b, "• <%s|%s#%d %s>", pr.URL, pr.RepoFullName, pr.PRNumber, pr.Title)
+		if pr.RequestedBy != "" {
+			fmt.Fprintf(&b, " — requested by %s %s ago\n", pr.RequestedBy, formatCountdownDuration(now.Sub(pr.Since)))
+		} else {
+			fmt.Fprintf(&b, " — open %s\n", formatCountdownDuration(now.Sub(pr.Since)))
+		}
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// describeDigestEvent returns the digest line prefix describing what happened.
+func describeDigestEvent(entry *models.DigestEntry) string {
+	switch entry.Event {
+	case models.DigestEventCC:
+		return fmt.Sprintf("%s CC'd you on", entry.Actor)
+	case models.AuthorDMEventChangesRequested:
+		return fmt.Sprintf("%s requested changes on your PR", entry.Actor)
+	case models.AuthorDMEventCIFailed:
+		return fmt.Sprintf("CI failed (%s) on your PR", entry.Actor)
+	case models.AuthorDMEventMergeConflict:
+		return "Merge conflict on your PR"
+	case models.DigestEventReviewRequested:
+		return fmt.Sprintf("%s requested your review on", entry.Actor)
+	case models.DigestEventAssigned:
+		return fmt.Sprintf("%s assigned you to", entry.Actor)
+	default:
+		return "Update on"
+	}
+}
@@ -0,0 +1,98 @@
+package utils
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github-slack-notifier/internal/models"
+)
+
+func TestFormatUserDigest(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	entries := []*models.DigestEntry{
+		{
+			Event: models.AuthorDMEventCIFailed, Actor: "GitHub Actions",
+			RepoFullName: "o/r", PRNumber: 2, PRTitle: "Add API", PRURL: "https://github.com/o/r/pull/2",
+			CreatedAt: now.Add(-10 * time.Minute),
+		},
+		{
+			Event: models.DigestEventCC, Actor: "alice",
+			RepoFullName: "o/r", PRNumber: 1, PRTitle: "Fix bug", PRURL: "https://github.com/o/r/pull/1",
+			CreatedAt: now.Add(-50 * time.Minute),
+		},
+		{
+			Event: models.AuthorDMEventChangesRequested, Actor: "bob",
+			RepoFullName: "o/r", PRNumber: 2, PRTitle: "Add API", PRURL: "https://github.com/o/r/pull/2",
+			CreatedAt: now.Add(-30 * time.Minute),
+		},
+	}
+
+	expected := "*Your PR digest* — 3 updates since the last digest\n" +
+		"• alice CC'd you on <https://github.com/o/r/pull/1|o/r#1 Fix bug>\n" +
+		"• bob requested changes on your PR <https://github.com/o/r/pull/2|o/r#2 Add API>\n" +
+		"• CI failed (GitHub Actions) on your PR <https://github.com/o/r/pull/2|o/r#2 Add API>"
+	assert.Equal(t, expected, FormatUserDigest(entries))
+}
+
+func TestFormatUserDigest_Truncates(t *testing.T) {
+	var entries []*models.DigestEntry
+	for i := range maxDigestEntries + 5 {
+		entries = append(entries, &models.DigestEntry{
+			Event: models.DigestEventCC, Actor: "alice",
+			RepoFullName: "o/r", PRNumber: i + 1, PRTitle: fmt.Sprintf("PR %d", i+1),
+		})
+	}
+
+	assert.Contains(t, FormatUserDigest(entries), "_…and 5 more_")
+}
+
+func TestFormatUserDigest_Assigned(t *testing.T) {
+	entries := []*models.DigestEntry{{
+		Event: models.DigestEventAssigned, Actor: "bob",
+		RepoFullName: "o/r", PRNumber: 3, PRTitle: "Fix CI", PRURL: "https://github.com/o/r/pull/3",
+	}}
+
+	assert.Contains(t, FormatUserDigest(entries), "• bob assigned you to <https://github.com/o/r/pull/3|o/r#3 Fix CI>")
+}
+
+func TestFormatChannelDigest(t *testing.T) {
+	now := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	entries := []*models.DigestEntry{
+		{
+			Event: models.DigestEventPROpened, Actor: "bob",
+			RepoFullName: "o/mono", PRNumber: 8, PRTitle: "Bump deps", PRURL: "https://github.com/o/mono/pull/8",
+			CreatedAt: now.Add(-2 * time.Hour),
+		},
+		{
+			Event: models.DigestEventPROpened, Actor: "alice",
+			RepoFullName: "o/mono", PRNumber: 7, PRTitle: "Fix lint", PRURL: "https://github.com/o/mono/pull/7",
+			CreatedAt: now.Add(-20 * time.Hour),
+		},
+	}
+
+	expected := "*Daily PR digest for Mon, Jan 1* — 2 new PRs\n" +
+		"• <https://github.com/o/mono/pull/7|o/mono#7 Fix lint> by alice\n" +
+		"• <https://github.com/o/mono/pull/8|o/mono#8 Bump deps> by bob"
+	assert.Equal(t, expected, FormatChannelDigest(entries, LocalTimeFormat{}.Date(now)))
+}
+
+func TestFormatDailyDigestPRs(t *testing.T) {
+	now := time.Date(2024, 1, 3, 9, 0, 0, 0, time.UTC)
+	prs := []models.DailyDigestPR{
+		{
+			RepoFullName: "o/r", PRNumber: 5, Title: "Add API", URL: "https://github.com/o/r/pull/5",
+			Since: now.Add(-3 * time.Hour), RequestedBy: "alice",
+		},
+		{
+			RepoFullName: "o/r", PRNumber: 2, Title: "Fix bug", URL: "https://github.com/o/r/pull/2",
+			Since: now.Add(-50 * time.Hour),
+		},
+	}
+
+	expected := "• <https://github.com/o/r/pull/2|o/r#2 Fix bug> — open 2d 2h\n" +
+		"• <https://github.com/o/r/pull/5|o/r#5 Add API> — requested by alice 3h ago"
+	assert.Equal(t, expected, FormatDailyDigestPRs(prs, now))
+}
@@ -70,6 +70,31 @@ func GetPRSizeEmoji(linesChanged int) string {
 	return "🐋" // fallback
 }
 
+// ResolveEmojiConfig returns the emoji configuration for a workspace: the environment defaults,
+// with any review state reactions the workspace overrides.
+func ResolveEmojiConfig(defaults config.EmojiConfig, overrides *models.WorkspaceEmoji) config.EmojiConfig {
+	if overrides == nil {
+		return defaults
+	}
+
+	resolved := defaults
+	for _, field := range []struct {
+		target   *string
+		override string
+	}{
+		{&resolved.Approved, overrides.Approved},
+		{&resolved.ChangesRequested, overrides.ChangesRequested},
+		{&resolved.Commented, overrides.Commented},
+		{&resolved.Merged, overrides.Merged},
+		{&resolved.Closed, overrides.Closed},
+	} {
+		if field.override != "" {
+			*field.target = field.override
+		}
+	}
+	return resolved
+}
+
 // GetEmojiForReviewState returns the appropriate emoji for a GitHub PR review state.
 // It maps review states to configured emojis: approved uses emojiConfig.Approved,
 // changes_requested uses emojiConfig.ChangesRequested, commented uses emojiConfig.Commented.
@@ -100,3 +125,18 @@ func GetEmojiForPRState(state string, merged bool, emojiConfig config.EmojiConfi
 	}
 	return emojiConfig.Closed
 }
+
+// GetEmojiForCIState returns the emoji for a commit's combined CI state.
+// Pending and missing CI have no emoji, so reactions are cleared while CI runs.
+func GetEmojiForCIState(state models.CIState, emojiConfig config.EmojiConfig) string {
+	switch state {
+	case models.CIStateSuccess:
+		return emojiConfig.CIPassed
+	case models.CIStateFailure:
+		return emojiConfig.CIFailed
+	case models.CIStateNone, models.CIStatePending:
+		return ""
+	default:
+		return ""
+	}
+}
@@ -3,6 +3,7 @@ package utils
 import (
 	"testing"
 
+	"github-slack-notifier/internal/config"
 	"github-slack-notifier/internal/models"
 	"github.com/stretchr/testify/assert"
 )
@@ -350,3 +351,23 @@ func TestFormatPRSizeThresholds(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveEmojiConfig(t *testing.T) {
+	defaults := config.EmojiConfig{
+		Approved:         "white_check_mark",
+		ChangesRequested: "arrows_counterclockwise",
+		Commented:        "speech_balloon",
+		Merged:           "tada",
+		Closed:           "x",
+		CIPassed:         "large_green_circle",
+	}
+
+	assert.Equal(t, defaults, ResolveEmojiConfig(defaults, nil))
+
+	resolved := ResolveEmojiConfig(defaults, &models.WorkspaceEmoji{Approved: "shipit", Merged: "rocket"})
+	assert.Equal(t, "shipit", resolved.Approved)
+	assert.Equal(t, "rocket", resolved.Merged)
+	assert.Equal(t, "arrows_counterclockwise", resolved.ChangesRequested, "unset overrides keep the default")
+	assert.Equal(t, "x", resolved.Closed)
+	assert.Equal(t, "large_green_circle", resolved.CIPassed, "CI emoji aren't overridable")
+}
@@ -1,49 +1,146 @@
 package utils
 
 import (
+	"fmt"
 	"regexp"
 	"strconv"
+	"strings"
+
+	"github-slack-notifier/internal/models"
 )
 
-// PRLink represents a parsed GitHub pull request link with extracted components.
-// It contains all the necessary information to identify and work with a specific PR.
+// supersedesRegex matches references to PRs replaced by the current PR, e.g. "Replaces #123" or "Supersedes: #123".
+var supersedesRegex = regexp.MustCompile(`(?i)\b(?:replaces|supersedes)\s*:?\s*#(\d+)\b`)
+
+// dependsOnRegex matches dependency references such as "Depends on org/repo#12" or "Depends on #12".
+var dependsOnRegex = regexp.MustCompile(`(?i)\bdepends\s+on\s*:?\s*([\w.-]+/[\w.-]+)?#(\d+)\b`)
+
+// PRLink represents a parsed GitHub pull request or issue link with extracted components.
+// It contains all the necessary information to identify and work with a specific PR or issue.
 type PRLink struct {
-	URL          string // Complete GitHub PR URL (e.g., "https://github.com/owner/repo/pull/123")
+	URL          string // Complete GitHub URL (e.g., "https://github.com/owner/repo/pull/123")
 	Owner        string // Repository owner/organization name
 	Repo         string // Repository name
-	PRNumber     int    // Pull request number
+	PRNumber     int    // Pull request or issue number
 	FullRepoName string // Combined "owner/repo" format for convenience
+	ItemType     string // models.TrackedItemTypePR or models.TrackedItemTypeIssue
 }
 
-// ExtractPRLinks parses GitHub pull request URLs from the given message text and returns
+// IsIssue reports whether the link is to a GitHub issue rather than a pull request.
+func (l PRLink) IsIssue() bool {
+	return l.ItemType == models.TrackedItemTypeIssue
+}
+
+// ExtractPRLinks parses GitHub pull request and issue URLs from the given message text and returns
 // a slice of PRLink structs containing the extracted information.
 //
-// The function uses a regex pattern to match GitHub PR URLs in the format:
+// The function uses a regex pattern to match GitHub URLs in the formats:
 // https://github.com/owner/repo/pull/number
+// https://github.com/owner/repo/issues/number
 //
-// If multiple PR URLs are found in the text, it returns nil to ignore the message
+// If multiple URLs are found in the text, it returns nil to ignore the message
 // as per the application's business logic to avoid ambiguous notifications.
-// If a single PR URL is found, it returns a slice with one PRLink element.
-// If no PR URLs are found, it returns an empty slice.
+// If a single URL is found, it returns a slice with one PRLink element.
+// If no URLs are found, it returns an empty slice.
 func ExtractPRLinks(text string) []PRLink {
-	pattern := regexp.MustCompile(`https://github\.com/([^/\s]+)/([^/\s]+)/pull/(\d+)`)
+	pattern := regexp.MustCompile(`https://github\.com/([^/\s]+)/([^/\s]+)/(pull|issues)/(\d+)`)
 	matches := pattern.FindAllStringSubmatch(text, -1)
 
-	// Ignore messages containing multiple PR URLs
+	// Ignore messages containing multiple PR or issue URLs
 	if len(matches) > 1 {
 		return nil
 	}
 
 	links := make([]PRLink, 0, len(matches))
 	for _, match := range matches {
-		prNumber, _ := strconv.Atoi(match[3])
+		prNumber, _ := strconv.Atoi(match[4])
+		itemType := models.TrackedItemTypePR
+		if match[3] == "issues" {
+			itemType = models.TrackedItemTypeIssue
+		}
 		links = append(links, PRLink{
 			URL:          match[0],
 			Owner:        match[1],
 			Repo:         match[2],
 			PRNumber:     prNumber,
 			FullRepoName: match[1] + "/" + match[2],
+			ItemType:     itemType,
 		})
 	}
 	return links
 }
+
+// ExtractSupersededPRNumbers returns the PR numbers referenced by "Replaces #N" or "Supersedes #N"
+// in a PR description. Used for fork workflows where a closed PR is reopened under a new number.
+// Duplicates and references to currentPRNumber are ignored.
+func ExtractSupersededPRNumbers(body string, currentPRNumber int) []int {
+	matches := supersedesRegex.FindAllStringSubmatch(body, -1)
+
+	var prNumbers []int
+	seen := make(map[int]bool)
+	for _, match := range matches {
+		prNumber, err := strconv.Atoi(match[1])
+		if err != nil || prNumber <= 0 || prNumber == currentPRNumber || seen[prNumber] {
+			continue
+		}
+		seen[prNumber] = true
+		prNumbers = append(prNumbers, prNumber)
+	}
+	return prNumbers
+}
+
+// ExtractPRDependencies returns the PRs referenced by "Depends on org/repo#N" in a PR description.
+// References without a repository ("Depends on #N") refer to currentRepo. Duplicates and self references are ignored.
+func ExtractPRDependencies(body, currentRepo string, currentPRNumber int) []models.PRDependency {
+	matches := dependsOnRegex.FindAllStringSubmatch(body, -1)
+
+	var dependencies []models.PRDependency
+	seen := make(map[string]bool)
+	for _, match := range matches {
+		prNumber, err := strconv.Atoi(match[2])
+		if err != nil || prNumber <= 0 {
+			continue
+		}
+		repoFullName := match[1]
+		if repoFullName == "" {
+			repoFullName = currentRepo
+		}
+		if strings.EqualFold(repoFullName, currentRepo) && prNumber == currentPRNumber {
+			continue
+		}
+
+		dependency := models.PRDependency{RepoFullName: repoFullName, PRNumber: prNumber}
+		if seen[dependency.Key()] {
+			continue
+		}
+		seen[dependency.Key()] = true
+		dependencies = append(dependencies, dependency)
+	}
+	return dependencies
+}
+
+// FormatDependencyLine formats the dependency status line shown on PR messages.
+// Returns an empty string if there are no dependencies.
+func FormatDependencyLine(dependencies []models.PRDependency) string {
+	if len(dependencies) == 0 {
+		return ""
+	}
+
+	blocked := false
+	items := make([]string, 0, len(dependencies))
+	for _, dependency := range dependencies {
+		state := dependency.State
+		if state == "" {
+			state = "unknown"
+		}
+		if !dependency.IsResolved() {
+			blocked = true
+		}
+		items = append(items, fmt.Sprintf("<%s|%s> (%s)", dependency.URL(), dependency.Key(), state))
+	}
+
+	if blocked {
+		return ":no_entry: Blocked by " + strings.Join(items, ", ")
+	}
+	return ":link: Depends on " + strings.Join(items, ", ")
+}
@@ -1,7 +1,10 @@
 package utils
 
 import (
+	"reflect"
 	"testing"
+
+	"github-slack-notifier/internal/models"
 )
 
 func TestExtractPRLinks(t *testing.T) {
@@ -69,6 +72,25 @@ func TestExtractPRLinks(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "single issue link",
+			text: "Tracking this in https://github.com/owner/repo/issues/77",
+			expected: []PRLink{
+				{
+					URL:          "https://github.com/owner/repo/issues/77",
+					Owner:        "owner",
+					Repo:         "repo",
+					PRNumber:     77,
+					FullRepoName: "owner/repo",
+					ItemType:     models.TrackedItemTypeIssue,
+				},
+			},
+		},
+		{
+			name:     "PR and issue links - should ignore",
+			text:     "https://github.com/owner/repo/pull/123 fixes https://github.com/owner/repo/issues/77",
+			expected: nil,
+		},
 		{
 			name:     "malformed PR URL",
 			text:     "https://github.com/owner/repo/pull/not-a-number",
@@ -102,6 +124,163 @@ func TestExtractPRLinks(t *testing.T) {
 				if link.FullRepoName != expected.FullRepoName {
 					t.Errorf("FullRepoName mismatch: got %s, expected %s", link.FullRepoName, expected.FullRepoName)
 				}
+				expectedType := expected.ItemType
+				if expectedType == "" {
+					expectedType = models.TrackedItemTypePR
+				}
+				if link.ItemType != expectedType {
+					t.Errorf("ItemType mismatch: got %s, expected %s", link.ItemType, expectedType)
+				}
+			}
+		})
+	}
+}
+
+func TestExtractSupersededPRNumbers(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		current  int
+		expected []int
+	}{
+		{
+			name:     "no references",
+			body:     "Fixes a bug in the parser",
+			current:  456,
+			expected: nil,
+		},
+		{
+			name:     "replaces reference",
+			body:     "Replaces #123 which was opened from a deleted fork",
+			current:  456,
+			expected: []int{123},
+		},
+		{
+			name:     "supersedes reference with colon",
+			body:     "supersedes: #123",
+			current:  456,
+			expected: []int{123},
+		},
+		{
+			name:     "multiple references deduplicated",
+			body:     "Replaces #123\nSupersedes #124\nReplaces #123",
+			current:  456,
+			expected: []int{123, 124},
+		},
+		{
+			name:     "ignores self reference",
+			body:     "Replaces #456",
+			current:  456,
+			expected: nil,
+		},
+		{
+			name:     "ignores unrelated issue references",
+			body:     "Fixes #123",
+			current:  456,
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ExtractSupersededPRNumbers(tt.body, tt.current)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("ExtractSupersededPRNumbers() = %v, expected %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExtractPRDependencies(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected []models.PRDependency
+	}{
+		{
+			name:     "no dependencies",
+			body:     "Adds a new endpoint",
+			expected: nil,
+		},
+		{
+			name: "cross-repo dependency",
+			body: "Depends on org/api#12",
+			expected: []models.PRDependency{
+				{RepoFullName: "org/api", PRNumber: 12},
+			},
+		},
+		{
+			name: "same-repo dependency with colon",
+			body: "depends on: #7",
+			expected: []models.PRDependency{
+				{RepoFullName: "org/web", PRNumber: 7},
+			},
+		},
+		{
+			name: "multiple dependencies deduplicated",
+			body: "Depends on org/api#12\nDepends on org/api#12\nDepends on org/lib.go#3",
+			expected: []models.PRDependency{
+				{RepoFullName: "org/api", PRNumber: 12},
+				{RepoFullName: "org/lib.go", PRNumber: 3},
+			},
+		},
+		{
+			name:     "ignores self reference",
+			body:     "Depends on #99",
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ExtractPRDependencies(tt.body, "org/web", 99)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("ExtractPRDependencies() = %v, expected %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormatDependencyLine(t *testing.T) {
+	tests := []struct {
+		name         string
+		dependencies []models.PRDependency
+		expected     string
+	}{
+		{
+			name:         "no dependencies",
+			dependencies: nil,
+			expected:     "",
+		},
+		{
+			name: "open dependency blocks",
+			dependencies: []models.PRDependency{
+				{RepoFullName: "org/api", PRNumber: 12, State: models.PRDependencyStateOpen},
+				{RepoFullName: "org/lib", PRNumber: 3, State: models.PRDependencyStateMerged},
+			},
+			expected: ":no_entry: Blocked by <https://github.com/org/api/pull/12|org/api#12> (open), " +
+				"<https://github.com/org/lib/pull/3|org/lib#3> (merged)",
+		},
+		{
+			name: "unknown state blocks",
+			dependencies: []models.PRDependency{
+				{RepoFullName: "org/api", PRNumber: 12},
+			},
+			expected: ":no_entry: Blocked by <https://github.com/org/api/pull/12|org/api#12> (unknown)",
+		},
+		{
+			name: "all resolved",
+			dependencies: []models.PRDependency{
+				{RepoFullName: "org/api", PRNumber: 12, State: models.PRDependencyStateMerged},
+			},
+			expected: ":link: Depends on <https://github.com/org/api/pull/12|org/api#12> (merged)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatDependencyLine(tt.dependencies); got != tt.expected {
+				t.Errorf("FormatDependencyLine() = %q, expected %q", got, tt.expected)
 			}
 		})
 	}
@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"github-slack-notifier/internal/models"
+)
+
+// DescribeInstallationRepositories describes which repositories a GitHub App installation can access,
+// e.g. "all repositories" or "3 selected repositories".
+func DescribeInstallationRepositories(installation *models.GitHubInstallation) string {
+	if installation.RepositorySelection != "selected" {
+		return "all repositories"
+	}
+	switch len(installation.Repositories) {
+	case 0:
+		return "no repositories yet"
+	case 1:
+		return "1 selected repository"
+	default:
+		return fmt.Sprintf("%d selected repositories", len(installation.Repositories))
+	}
+}
+
+// FormatInstallationConfirmation returns the DM sent to the Slack user who completed a GitHub App installation,
+// confirming what was installed and linked, and what to do next in the App Home at appHomeURL.
+func FormatInstallationConfirmation(installation *models.GitHubInstallation, githubUsername, appHomeURL string) string {
+	lines := []string{
+		fmt.Sprintf(":tada: PR Bot is installed on *%s* with access to %s, and your GitHub account *@%s* is linked.",
+			installation.AccountLogin, DescribeInstallationRepositories(installation), githubUsername),
+		"*Next steps*",
+		fmt.Sprintf("• Choose the channel your PRs are posted to in my <%s|App Home>.", appHomeURL),
+		"• Invite teammates to link their GitHub accounts there too, so they're mentioned on reviews.",
+		"• Open a PR in one of these repositories and I'll post it to your channel.",
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github-slack-notifier/internal/models"
+)
+
+func TestDescribeInstallationRepositories(t *testing.T) {
+	tests := []struct {
+		name         string
+		installation *models.GitHubInstallation
+		expected     string
+	}{
+		{"all repositories", &models.GitHubInstallation{RepositorySelection: "all"}, "all repositories"},
+		{"none selected", &models.GitHubInstallation{RepositorySelection: "selected"}, "no repositories yet"},
+		{"one selected", &models.GitHubInstallation{RepositorySelection: "selected", Repositories: []string{"o/a"}}, "1 selected repository"},
+		{
+			"several selected",
+			&models.GitHubInstallation{RepositorySelection: "selected", Repositories: []string{"o/a", "o/b"}},
+			"2 selected repositories",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, DescribeInstallationRepositories(tt.installation))
+		})
+	}
+}
+
+func TestFormatInstallationConfirmation(t *testing.T) {
+	installation := &models.GitHubInstallation{AccountLogin: "octo-org", RepositorySelection: "all"}
+	assert.Equal(t, ":tada: PR Bot is installed on *octo-org* with access to all repositories, "+
+		"and your GitHub account *@octocat* is linked.\n"+
+		"*Next steps*\n"+
+		"• Choose the channel your PRs are posted to in my <https://slack.com/app_redirect?app=A1&team=T1|App Home>.\n"+
+		"• Invite teammates to link their GitHub accounts there too, so they're mentioned on reviews.\n"+
+		"• Open a PR in one of these repositories and I'll post it to your channel.",
+		FormatInstallationConfirmation(installation, "octocat", "https://slack.com/app_redirect?app=A1&team=T1"))
+}
@@ -0,0 +1,98 @@
+package utils
+
+import (
+	"encoding/hex"
+	"math"
+	"strings"
+)
+
+// labelHueEmojis are the colored square emoji label chips are drawn with, by the hue (in degrees) each one
+// covers up to. Hues past the last entry wrap around to red.
+var labelHueEmojis = []struct {
+	maxHue float64
+	emoji  string
+}{
+	{15, ":large_red_square:"},
+	{45, ":large_orange_square:"},
+	{70, ":large_yellow_square:"},
+	{165, ":large_green_square:"},
+	{245, ":large_blue_square:"},
+	{330, ":large_purple_square:"},
+	{360, ":large_red_square:"},
+}
+
+// Thresholds on 0-255 color channels for picking label emoji.
+const (
+	labelGreyChroma   = 40  // Colors less saturated than this are drawn black or white
+	labelDarkMax      = 128 // Grey colors darker than this are drawn black
+	labelBrownMax     = 160 // Orange colors darker than this are drawn brown
+	degreesPerHueSide = 60
+	fullCircleDegrees = 360
+)
+
+// Emoji for labels whose colors don't map to a hue.
+const (
+	defaultLabelEmoji = ":label:"
+	darkLabelEmoji    = ":black_large_square:"
+	lightLabelEmoji   = ":white_large_square:"
+	brownLabelEmoji   = ":large_brown_square:"
+	orangeLabelEmoji  = ":large_orange_square:"
+)
+
+// rgbBytes is the length of a decoded hex RGB color.
+const rgbBytes = 3
+
+// LabelColorEmoji returns the colored square emoji closest to a GitHub label's hex color, e.g. "d73a4a".
+func LabelColorEmoji(color string) string {
+	rgb, err := hex.DecodeString(strings.TrimPrefix(color, "#"))
+	if err != nil || len(rgb) != rgbBytes {
+		return defaultLabelEmoji
+	}
+
+	r, g, b := float64(rgb[0]), float64(rgb[1]), float64(rgb[2])
+	hi, lo := max(r, g, b), min(r, g, b)
+	chroma := hi - lo
+	if chroma < labelGreyChroma {
+		if hi < labelDarkMax {
+			return darkLabelEmoji
+		}
+		return lightLabelEmoji
+	}
+
+	var hue float64
+	switch hi {
+	case r:
+		hue = math.Mod((g-b)/chroma+6, 6) * degreesPerHueSide //nolint:mnd // Six sides of the hue hexagon
+	case g:
+		hue = ((b-r)/chroma + 2) * degreesPerHueSide //nolint:mnd // Green is the second third of the circle
+	default:
+		hue = ((r-g)/chroma + 4) * degreesPerHueSide //nolint:mnd // Blue is the last third of the circle
+	}
+	hue = math.Mod(hue, fullCircleDegrees)
+
+	for _, h := range labelHueEmojis {
+		if hue < h.maxHue {
+			if h.emoji == orangeLabelEmoji && hi < labelBrownMax {
+				return brownLabelEmoji
+			}
+			return h.emoji
+		}
+	}
+	return defaultLabelEmoji
+}
+
+// FormatLabelChip returns the chip shown for a GitHub label on PR messages: the label's configured emoji,
+// or a square in its color, followed by its escaped name. labelEmoji is keyed by lowercase label name.
+func FormatLabelChip(name, color string, labelEmoji map[string]string) string {
+	emoji, ok := labelEmoji[strings.ToLower(name)]
+	if !ok {
+		emoji = LabelColorEmoji(color)
+	}
+	return emoji + " " + EscapeSlackText(SanitizeMessageLine(name))
+}
+
+// SanitizeMessageLine strips characters from GitHub text that would break a PR message annotation line
+// or its lifecycle suffix, e.g. a label or milestone name.
+func SanitizeMessageLine(text string) string {
+	return strings.TrimSpace(projectContextSanitizer.Replace(text))
+}
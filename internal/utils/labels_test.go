@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLabelColorEmoji(t *testing.T) {
+	tests := []struct {
+		color    string
+		expected string
+	}{
+		{"d73a4a", ":large_red_square:"},    // GitHub's "bug"
+		{"a2eeef", ":large_blue_square:"},   // GitHub's "enhancement"
+		{"#0e8a16", ":large_green_square:"}, // Leading # is allowed
+		{"7057ff", ":large_purple_square:"}, // GitHub's "good first issue"
+		{"fbca04", ":large_yellow_square:"},
+		{"ffa500", ":large_orange_square:"},
+		{"8b4513", ":large_brown_square:"},
+		{"ffffff", ":white_large_square:"},
+		{"000000", ":black_large_square:"},
+		{"", ":label:"},
+		{"zzzzzz", ":label:"},
+		{"fff", ":label:"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.color, func(t *testing.T) {
+			assert.Equal(t, tt.expected, LabelColorEmoji(tt.color))
+		})
+	}
+}
+
+func TestFormatLabelChip(t *testing.T) {
+	labelEmoji := map[string]string{"security": ":lock:"}
+
+	assert.Equal(t, ":lock: Security", FormatLabelChip("Security", "d73a4a", labelEmoji), "configured emoji by lowercase name")
+	assert.Equal(t, ":large_red_square: bug", FormatLabelChip("bug", "d73a4a", labelEmoji))
+	assert.Equal(t, ":large_red_square: a &lt;b&gt; - c", FormatLabelChip("a <b> · c\n", "d73a4a", nil),
+		"names are escaped and kept on one line")
+}
@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// minMatchableHandleLength is the shortest normalized GitHub username matched against Slack names,
+// so short handles like "al" don't match unrelated people.
+const minMatchableHandleLength = 4
+
+// NameMatchesGitHubUsername reports whether any of a Slack user's names likely belongs to a GitHub username.
+// Names match when they're equal ignoring case, spaces and punctuation, e.g. "Jane Doe" and "jane-doe".
+func NameMatchesGitHubUsername(githubUsername string, names ...string) bool {
+	handle := normalizeHandle(githubUsername)
+	if len(handle) < minMatchableHandleLength {
+		return false
+	}
+	for _, name := range names {
+		if normalizeHandle(name) == handle {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeHandle lowercases a name and drops everything but letters and digits.
+func normalizeHandle(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+}
+
+// FormatLinkInvite returns the invitation sent to a Slack user who was likely CC'd on a PR
+// under a GitHub username that isn't linked to a Slack account.
+func FormatLinkInvite(githubUsername, prTitle, prURL string) string {
+	return fmt.Sprintf(":wave: Are you *@%s* on GitHub? You were CC'd on <%s|%s>. "+
+		"Link your GitHub account in my App Home and I'll mention you directly next time.",
+		githubUsername, prURL, EscapeSlackText(prTitle))
+}
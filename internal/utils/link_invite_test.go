@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNameMatchesGitHubUsername(t *testing.T) {
+	tests := []struct {
+		name     string
+		username string
+		names    []string
+		expected bool
+	}{
+		{"display name with space", "jane-doe", []string{"", "Jane Doe"}, true},
+		{"case and punctuation", "JDoe_42", []string{"jdoe.42"}, true},
+		{"no match", "jane-doe", []string{"John Doe", "jdoe"}, false},
+		{"short handle never matches", "al", []string{"Al"}, false},
+		{"empty names", "jane-doe", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, NameMatchesGitHubUsername(tt.username, tt.names...))
+		})
+	}
+}
+
+func TestFormatLinkInvite(t *testing.T) {
+	assert.Equal(t, ":wave: Are you *@jane-doe* on GitHub? You were CC'd on <https://github.com/o/r/pull/1|Fix &lt;thing&gt;>. "+
+		"Link your GitHub account in my App Home and I'll mention you directly next time.",
+		FormatLinkInvite("jane-doe", "Fix <thing>", "https://github.com/o/r/pull/1"))
+}
@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Kinds of mechanical PRs returned by DetectMechanicalPR.
+const (
+	MechanicalPRRevert    = "revert"
+	MechanicalPRBackMerge = "back_merge"
+)
+
+// revertTitleRegex matches revert titles, e.g. `Revert "Add login page"`.
+var revertTitleRegex = regexp.MustCompile(`(?i)^revert\b`)
+
+// revertBodyRegex matches the "Reverts owner/repo#123" line GitHub adds to PRs created with its Revert button.
+var revertBodyRegex = regexp.MustCompile(`(?m)^Reverts [\w.-]+/[\w.-]+#\d+`)
+
+// backMergeTitleRegex matches titles of PRs that merge a branch back, e.g. "Back-merge release/1.2 into develop".
+var backMergeTitleRegex = regexp.MustCompile(`(?i)^back[- ]?merge\b`)
+
+// DetectMechanicalPR returns the kind of routine mechanical PR, or "" for a regular PR.
+// Reverts are detected from the title or GitHub's revert metadata in the description. Back-merges are
+// detected from the title, or for automated authors, from a default or release branch merged into another branch.
+func DetectMechanicalPR(title, body, headRef, baseRef, defaultBranch string, automated bool) string {
+	if revertTitleRegex.MatchString(strings.TrimSpace(title)) || revertBodyRegex.MatchString(body) {
+		return MechanicalPRRevert
+	}
+	if backMergeTitleRegex.MatchString(strings.TrimSpace(title)) {
+		return MechanicalPRBackMerge
+	}
+
+	fromMainline := headRef == defaultBranch || strings.HasPrefix(headRef, "release/") || strings.HasPrefix(headRef, "hotfix/")
+	if automated && defaultBranch != "" && fromMainline && baseRef != defaultBranch && baseRef != headRef {
+		return MechanicalPRBackMerge
+	}
+	return ""
+}
@@ -0,0 +1,86 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectMechanicalPR(t *testing.T) {
+	tests := []struct {
+		name      string
+		title     string
+		body      string
+		headRef   string
+		baseRef   string
+		automated bool
+		expected  string
+	}{
+		{
+			name:     "regular PR",
+			title:    "Add login page",
+			headRef:  "feature/login",
+			baseRef:  "main",
+			expected: "",
+		},
+		{
+			name:     "revert title",
+			title:    `Revert "Add login page"`,
+			headRef:  "revert-12-feature/login",
+			baseRef:  "main",
+			expected: MechanicalPRRevert,
+		},
+		{
+			name:     "revert metadata in description",
+			title:    "Undo login page",
+			body:     "Reverts acme/web#12\n\nBroke sign-in.",
+			headRef:  "undo-login",
+			baseRef:  "main",
+			expected: MechanicalPRRevert,
+		},
+		{
+			name:     "reverts mentioned mid-sentence is not a revert",
+			title:    "Fix login page",
+			body:     "This fix means nobody reverts acme/web#12",
+			headRef:  "fix-login",
+			baseRef:  "main",
+			expected: "",
+		},
+		{
+			name:     "back-merge title",
+			title:    "Back-merge release/1.2 into develop",
+			headRef:  "backmerge-1.2",
+			baseRef:  "develop",
+			expected: MechanicalPRBackMerge,
+		},
+		{
+			name:      "automated release branch merged back",
+			title:     "Sync release/1.2",
+			headRef:   "release/1.2",
+			baseRef:   "develop",
+			automated: true,
+			expected:  MechanicalPRBackMerge,
+		},
+		{
+			name:     "human merging a release branch is not automated",
+			title:    "Sync release/1.2",
+			headRef:  "release/1.2",
+			baseRef:  "develop",
+			expected: "",
+		},
+		{
+			name:      "automated PR into the default branch",
+			title:     "Bump lodash",
+			headRef:   "release/1.2",
+			baseRef:   "main",
+			automated: true,
+			expected:  "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, DetectMechanicalPR(tt.title, tt.body, tt.headRef, tt.baseRef, "main", tt.automated))
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+)
+
+// DefaultMessageTemplate lays out PR messages for workspaces without a template of their own.
+const DefaultMessageTemplate = "{{.Emoji}} {{.Link}}{{if .Author}} by {{.Author}}{{end}}{{if .CC}} (cc: {{.CC}}){{end}}" +
+	"{{if .Labels}}\n{{.Labels}}{{end}}{{if .Milestone}}\n:dart: {{.Milestone}}{{end}}"
+
+// MaxMessageTemplateLength is the longest message template, in characters, a workspace can save.
+const MaxMessageTemplateLength = 500
+
+// Message template validation errors.
+var (
+	ErrMessageTemplateEmpty       = errors.New("message template renders an empty message")
+	ErrMessageTemplateTooLong     = errors.New("message template is too long")
+	ErrMessageTemplateMissingLink = errors.New("message template doesn't link to the PR")
+)
+
+// MessageTemplatePlaceholders are the fields a message template can use, with what they're replaced with.
+var MessageTemplatePlaceholders = []struct {
+	Name        string
+	Description string
+}{
+	{"{{.Emoji}}", "the PR size emoji, or the repository's emoji"},
+	{"{{.Link}}", "the PR title, linked to the PR"},
+	{"{{.Title}}", "the PR title"},
+	{"{{.URL}}", "the PR's URL"},
+	{"{{.Repo}}", "the repository, e.g. octo-org/widgets"},
+	{"{{.Author}}", "the author's mention, or GitHub username if they haven't linked Slack; empty without tagging"},
+	{"{{.CC}}", "the CC'd reviewers' mentions, separated by commas; empty without CCs"},
+	{"{{.Labels}}", "the PR's labels, each with its emoji; empty without labels or when label chips are off"},
+	{"{{.Milestone}}", "the PR's milestone; empty without one or when label chips are off"},
+}
+
+// MessageTemplateData holds the values a message template is rendered with.
+// Every field is Slack mrkdwn with text from GitHub already escaped, so templates can't inject links or mentions.
+type MessageTemplateData struct {
+	Emoji     string
+	Repo      string
+	Title     string
+	URL       string
+	Link      string
+	Author    string
+	CC        string
+	Labels    string
+	Milestone string
+}
+
+// sampleMessageTemplateData is the PR message templates are checked and previewed with.
+var sampleMessageTemplateData = MessageTemplateData{
+	Emoji:     ":ant:",
+	Repo:      "octo-org/widgets",
+	Title:     "Add widget caching",
+	URL:       "https://github.com/octo-org/widgets/pull/12",
+	Link:      "<https://github.com/octo-org/widgets/pull/12|Add widget caching>",
+	Author:    "@octocat",
+	CC:        "@hubot, @monalisa",
+	Labels:    ":large_blue_square: enhancement  :large_yellow_square: performance",
+	Milestone: "v2.0",
+}
+
+var defaultMessageTemplate = template.Must(template.New("message").Option("missingkey=error").Parse(DefaultMessageTemplate))
+
+// ParseMessageTemplate parses a workspace's message template, checking it renders a sample PR message
+// that links to the PR. Placeholders that don't exist are rejected when the sample is rendered.
+func ParseMessageTemplate(text string) (*template.Template, error) {
+	if utf8.RuneCountInString(text) > MaxMessageTemplateLength {
+		return nil, fmt.Errorf("%w: at most %d characters", ErrMessageTemplateTooLong, MaxMessageTemplateLength)
+	}
+
+	tmpl, err := template.New("message").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse message template: %w", err)
+	}
+
+	preview, err := RenderMessageTemplate(tmpl, sampleMessageTemplateData)
+	if err != nil {
+		return nil, err
+	}
+	if preview == "" {
+		return nil, ErrMessageTemplateEmpty
+	}
+	if !strings.Contains(preview, sampleMessageTemplateData.URL) {
+		return nil, ErrMessageTemplateMissingLink
+	}
+	return tmpl, nil
+}
+
+// RenderMessageTemplate renders a PR message with a parsed message template, or the default template if nil.
+// Surrounding whitespace is trimmed, so optional fields can be left out cleanly.
+func RenderMessageTemplate(tmpl *template.Template, data MessageTemplateData) (string, error) {
+	if tmpl == nil {
+		tmpl = defaultMessageTemplate
+	}
+	var text strings.Builder
+	if err := tmpl.Execute(&text, data); err != nil {
+		return "", fmt.Errorf("failed to render message template: %w", err)
+	}
+	return strings.TrimSpace(text.String()), nil
+}
+
+// PreviewMessageTemplate renders a message template with a sample PR, for showing admins what it looks like.
+func PreviewMessageTemplate(tmpl *template.Template) string {
+	preview, err := RenderMessageTemplate(tmpl, sampleMessageTemplateData)
+	if err != nil {
+		return ""
+	}
+	return preview
+}
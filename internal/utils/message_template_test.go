@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMessageTemplate(t *testing.T) {
+	tests := []struct {
+		name        string
+		template    string
+		expectedErr error
+	}{
+		{name: "default", template: DefaultMessageTemplate},
+		{name: "reordered", template: "{{.Link}} ({{.Repo}}) by {{.Author}} {{.Emoji}}"},
+		{name: "bare URL", template: "{{.Title}}: {{.URL}}"},
+		{name: "no link", template: "{{.Emoji}} {{.Title}}", expectedErr: ErrMessageTemplateMissingLink},
+		{name: "blank", template: "{{if false}}{{.Link}}{{end}}  ", expectedErr: ErrMessageTemplateEmpty},
+		{name: "too long", template: "{{.Link}}" + strings.Repeat(" ", MaxMessageTemplateLength), expectedErr: ErrMessageTemplateTooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := ParseMessageTemplate(tt.template)
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, tmpl)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotNil(t, tmpl)
+		})
+	}
+
+	_, err := ParseMessageTemplate("{{.Link}} {{.Reviewers}}")
+	assert.ErrorContains(t, err, "can't evaluate field Reviewers", "unknown placeholders are rejected")
+	_, err = ParseMessageTemplate("{{.Link}")
+	assert.ErrorContains(t, err, "failed to parse message template")
+}
+
+func TestRenderMessageTemplate(t *testing.T) {
+	data := MessageTemplateData{
+		Emoji: ":ant:",
+		Repo:  "o/r",
+		Title: "Fix bug",
+		URL:   "https://github.com/o/r/pull/1",
+		Link:  "<https://github.com/o/r/pull/1|Fix bug>",
+	}
+
+	text, err := RenderMessageTemplate(nil, data)
+	require.NoError(t, err)
+	assert.Equal(t, ":ant: <https://github.com/o/r/pull/1|Fix bug>", text, "optional parts of the default are left out")
+
+	data.Author = "<@U1>"
+	data.CC = "<@U2>, @bob"
+	text, err = RenderMessageTemplate(nil, data)
+	require.NoError(t, err)
+	assert.Equal(t, ":ant: <https://github.com/o/r/pull/1|Fix bug> by <@U1> (cc: <@U2>, @bob)", text)
+
+	tmpl, err := ParseMessageTemplate("  {{.Repo}}: {{.Link}}\n")
+	require.NoError(t, err)
+	text, err = RenderMessageTemplate(tmpl, data)
+	require.NoError(t, err)
+	assert.Equal(t, "o/r: <https://github.com/o/r/pull/1|Fix bug>", text, "surrounding whitespace is trimmed")
+
+	assert.Equal(t, "octo-org/widgets: <https://github.com/octo-org/widgets/pull/12|Add widget caching>", PreviewMessageTemplate(tmpl))
+}
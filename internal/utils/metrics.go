@@ -0,0 +1,110 @@
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github-slack-notifier/internal/models"
+)
+
+// metricsPrefix namespaces all exported metrics.
+const metricsPrefix = "pr_bot_"
+
+// labelValueEscaper escapes label values per the Prometheus text exposition format.
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// FormatDirectiveUsageMetrics renders directive usage aggregates in the Prometheus text exposition format.
+// Output is sorted by workspace and label so scrapes are stable.
+func FormatDirectiveUsageMetrics(usages []*models.DirectiveUsage) string {
+	sorted := make([]*models.DirectiveUsage, len(usages))
+	copy(sorted, usages)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].WorkspaceID < sorted[j].WorkspaceID })
+
+	var b strings.Builder
+
+	writeMetricHeader(&b, "prs_total", "PR notifications processed per workspace.")
+	for _, usage := range sorted {
+		writeMetric(&b, "prs_total", usage.WorkspaceID, "", "", usage.PRsTotal)
+	}
+
+	writeMetricHeader(&b, "prs_with_directives_total", "PR notifications that used at least one directive.")
+	for _, usage := range sorted {
+		writeMetric(&b, "prs_with_directives_total", usage.WorkspaceID, "", "", usage.PRsWithDirectives)
+	}
+
+	labeledCounters := []struct {
+		name  string
+		help  string
+		label string
+		get   func(*models.DirectiveUsage) map[string]int64
+	}{
+		{"directive_usage_total", "PR directive usage by directive type.", "directive",
+			func(u *models.DirectiveUsage) map[string]int64 { return u.Directives }},
+		{"directive_channel_usage_total", "Channel directive targets.", "channel",
+			func(u *models.DirectiveUsage) map[string]int64 { return u.Channels }},
+		{"directive_emoji_usage_total", "Custom emoji directives.", "emoji",
+			func(u *models.DirectiveUsage) map[string]int64 { return u.CustomEmoji }},
+		{"directive_repo_usage_total", "PR notifications using directives per repository.", "repo",
+			func(u *models.DirectiveUsage) map[string]int64 { return u.Repos }},
+		{"directive_channel_failures_total", "Failed posts to a channel directive target per repository.", "repo",
+			func(u *models.DirectiveUsage) map[string]int64 { return u.ChannelFailures }},
+	}
+
+	for _, counter := range labeledCounters {
+		writeMetricHeader(&b, counter.name, counter.help)
+		for _, usage := range sorted {
+			counts := counter.get(usage)
+			keys := make([]string, 0, len(counts))
+			for key := range counts {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				writeMetric(&b, counter.name, usage.WorkspaceID, counter.label, key, counts[key])
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// FormatCleanupMetrics renders the cleanup job's running totals in the Prometheus text exposition format.
+// Returns an empty string if the cleanup job has never run.
+func FormatCleanupMetrics(stats *models.CleanupStats) string {
+	if stats == nil {
+		return ""
+	}
+
+	collections := make([]string, 0, len(stats.Deleted))
+	for collection := range stats.Deleted {
+		collections = append(collections, collection)
+	}
+	sort.Strings(collections)
+
+	var b strings.Builder
+	writeMetricHeader(&b, "cleanup_deleted_total", "Documents deleted by the cleanup job per collection.")
+	for _, collection := range collections {
+		fmt.Fprintf(&b, "%scleanup_deleted_total{collection=\"%s\"} %d\n",
+			metricsPrefix, labelValueEscaper.Replace(collection), stats.Deleted[collection])
+	}
+	fmt.Fprintf(&b, "# HELP %scleanup_last_run_timestamp_seconds When the cleanup job last finished.\n", metricsPrefix)
+	fmt.Fprintf(&b, "# TYPE %scleanup_last_run_timestamp_seconds gauge\n", metricsPrefix)
+	fmt.Fprintf(&b, "%scleanup_last_run_timestamp_seconds %d\n", metricsPrefix, stats.LastRunAt.Unix())
+	return b.String()
+}
+
+// writeMetricHeader writes the HELP and TYPE lines for a counter.
+func writeMetricHeader(b *strings.Builder, name, help string) {
+	fmt.Fprintf(b, "# HELP %s%s %s\n", metricsPrefix, name, help)
+	fmt.Fprintf(b, "# TYPE %s%s counter\n", metricsPrefix, name)
+}
+
+// writeMetric writes a single counter sample labeled with the workspace and an optional extra label.
+func writeMetric(b *strings.Builder, name, workspaceID, label, value string, count int64) {
+	labels := fmt.Sprintf(`workspace="%s"`, labelValueEscaper.Replace(workspaceID))
+	if label != "" {
+		labels += fmt.Sprintf(`,%s="%s"`, label, labelValueEscaper.Replace(value))
+	}
+	fmt.Fprintf(b, "%s%s{%s} %d\n", metricsPrefix, name, labels, count)
+}
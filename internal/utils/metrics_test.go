@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github-slack-notifier/internal/models"
+)
+
+func TestFormatDirectiveUsageMetrics(t *testing.T) {
+	usages := []*models.DirectiveUsage{
+		{
+			WorkspaceID:       "T2",
+			PRsTotal:          1,
+			PRsWithDirectives: 0,
+		},
+		{
+			WorkspaceID:       "T1",
+			PRsTotal:          10,
+			PRsWithDirectives: 4,
+			Directives:        map[string]int64{"review": 4, "channel": 3},
+			Channels:          map[string]int64{"backend": 3},
+			CustomEmoji:       map[string]int64{":rocket:": 1},
+			Repos:             map[string]int64{"org/api": 4},
+			ChannelFailures:   map[string]int64{`org/"weird"`: 2},
+		},
+	}
+
+	result := FormatDirectiveUsageMetrics(usages)
+
+	assert.Contains(t, result, "# TYPE pr_bot_prs_total counter\n"+
+		"pr_bot_prs_total{workspace=\"T1\"} 10\n"+
+		"pr_bot_prs_total{workspace=\"T2\"} 1\n")
+	assert.Contains(t, result, "pr_bot_prs_with_directives_total{workspace=\"T1\"} 4\n")
+	assert.Contains(t, result, "pr_bot_directive_usage_total{workspace=\"T1\",directive=\"channel\"} 3\n"+
+		"pr_bot_directive_usage_total{workspace=\"T1\",directive=\"review\"} 4\n")
+	assert.Contains(t, result, "pr_bot_directive_channel_usage_total{workspace=\"T1\",channel=\"backend\"} 3\n")
+	assert.Contains(t, result, "pr_bot_directive_emoji_usage_total{workspace=\"T1\",emoji=\":rocket:\"} 1\n")
+	assert.Contains(t, result, "pr_bot_directive_repo_usage_total{workspace=\"T1\",repo=\"org/api\"} 4\n")
+	assert.Contains(t, result, `pr_bot_directive_channel_failures_total{workspace="T1",repo="org/\"weird\""} 2`)
+}
+
+func TestFormatDirectiveUsageMetrics_Empty(t *testing.T) {
+	result := FormatDirectiveUsageMetrics(nil)
+
+	assert.Contains(t, result, "# TYPE pr_bot_prs_total counter\n")
+	assert.NotContains(t, result, "workspace=")
+}
+
+func TestFormatCleanupMetrics(t *testing.T) {
+	assert.Empty(t, FormatCleanupMetrics(nil))
+
+	result := FormatCleanupMetrics(&models.CleanupStats{
+		Deleted:   map[string]int64{"trackedmessages": 12, "oauth_states": 3},
+		LastRunAt: time.Unix(1700000000, 0),
+	})
+
+	assert.Equal(t, "# HELP pr_bot_cleanup_deleted_total Documents deleted by the cleanup job per collection.\n"+
+		"# TYPE pr_bot_cleanup_deleted_total counter\n"+
+		"pr_bot_cleanup_deleted_total{collection=\"oauth_states\"} 3\n"+
+		"pr_bot_cleanup_deleted_total{collection=\"trackedmessages\"} 12\n"+
+		"# HELP pr_bot_cleanup_last_run_timestamp_seconds When the cleanup job last finished.\n"+
+		"# TYPE pr_bot_cleanup_last_run_timestamp_seconds gauge\n"+
+		"pr_bot_cleanup_last_run_timestamp_seconds 1700000000\n", result)
+}
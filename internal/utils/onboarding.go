@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"github-slack-notifier/internal/config"
+)
+
+// FormatOnboardingHint returns the hint shown to a PR author the first time the bot posts their PR in a channel,
+// explaining what the reactions on the message mean and how to delete it.
+func FormatOnboardingHint(emoji config.EmojiConfig) string {
+	reactions := []struct {
+		emoji   string
+		meaning string
+	}{
+		{emoji.Approved, "approved"},
+		{emoji.ChangesRequested, "changes requested"},
+		{emoji.Commented, "commented"},
+		{emoji.Merged, "merged"},
+		{emoji.Closed, "closed without merging"},
+		{emoji.CIPassed, "CI passed"},
+		{emoji.CIFailed, "CI failed"},
+		{emoji.MergeConflict, "merge conflict"},
+	}
+
+	lines := []string{
+		":wave: I just posted your PR here. I'll keep the message up to date with reactions as things happen:",
+	}
+	for _, reaction := range reactions {
+		if reaction.emoji == "" {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("• :%s: %s", reaction.emoji, reaction.meaning))
+	}
+	lines = append(lines,
+		"React with :wastebasket: to delete the message. Only you, as the PR author, can do this.",
+		"_You'll only see this once per channel._",
+	)
+	return strings.Join(lines, "\n")
+}
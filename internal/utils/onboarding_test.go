@@ -0,0 +1,22 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github-slack-notifier/internal/config"
+)
+
+func TestFormatOnboardingHint(t *testing.T) {
+	hint := FormatOnboardingHint(config.EmojiConfig{
+		Approved: "white_check_mark",
+		Merged:   "tada",
+	})
+
+	assert.Equal(t, ":wave: I just posted your PR here. I'll keep the message up to date with reactions as things happen:\n"+
+		"• :white_check_mark: approved\n"+
+		"• :tada: merged\n"+
+		"React with :wastebasket: to delete the message. Only you, as the PR author, can do this.\n"+
+		"_You'll only see this once per channel._", hint)
+}
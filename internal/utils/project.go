@@ -0,0 +1,19 @@
+package utils
+
+import "strings"
+
+// projectContextSanitizer strips characters that would break the single-line annotation or its lifecycle suffix.
+var projectContextSanitizer = strings.NewReplacer("\n", " ", "·", "-")
+
+// FormatProjectContext returns the project tracking line shown on PR messages, e.g. "Sprint 42 • In Review".
+// Either part may be empty; an empty result means there's nothing to show.
+func FormatProjectContext(milestone, column string) string {
+	var parts []string
+	for _, part := range []string{milestone, column} {
+		part = strings.TrimSpace(projectContextSanitizer.Replace(part))
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, " • ")
+}
@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatProjectContext(t *testing.T) {
+	tests := []struct {
+		name      string
+		milestone string
+		column    string
+		expected  string
+	}{
+		{
+			name:      "milestone and column",
+			milestone: "Sprint 42",
+			column:    "In Review",
+			expected:  "Sprint 42 • In Review",
+		},
+		{
+			name:      "milestone only",
+			milestone: "Sprint 42",
+			expected:  "Sprint 42",
+		},
+		{
+			name:     "column only",
+			column:   "Done",
+			expected: "Done",
+		},
+		{
+			name:     "nothing to show",
+			expected: "",
+		},
+		{
+			name:      "sanitizes separators and newlines",
+			milestone: "Q3 · Launch\n",
+			column:    " In Review ",
+			expected:  "Q3 - Launch • In Review",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, FormatProjectContext(tt.milestone, tt.column))
+		})
+	}
+}
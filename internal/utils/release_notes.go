@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// maxReleaseNotesLength limits the release notes body posted to Slack, keeping messages readable.
+const maxReleaseNotesLength = 3000
+
+var (
+	markdownBoldRegex    = regexp.MustCompile(`\*\*([^*\n]+)\*\*`)
+	markdownHeadingRegex = regexp.MustCompile(`(?m)^#{1,6}\s+(.+)$`)
+	markdownBulletRegex  = regexp.MustCompile(`(?m)^(\s*)[*-]\s+`)
+	markdownLinkRegex    = regexp.MustCompile(`\[([^\]\n]+)\]\((https?://[^)\s]+)\)`)
+)
+
+// FormatReleaseNotes formats GitHub generated release notes as a Slack mrkdwn draft for human review.
+// Long notes are truncated with a pointer to the full draft on GitHub.
+func FormatReleaseNotes(repoFullName, repoURL, tagName, body string) string {
+	draftURL := fmt.Sprintf("%s/releases/new?tag=%s", repoURL, url.QueryEscape(tagName))
+
+	notes := MarkdownToSlack(body)
+	if len(notes) > maxReleaseNotesLength {
+		notes = strings.ToValidUTF8(notes[:maxReleaseNotesLength], "")
+		if idx := strings.LastIndex(notes, "\n"); idx > 0 {
+			notes = notes[:idx]
+		}
+		notes += "\n_…truncated, see the full draft on GitHub_"
+	}
+	if strings.TrimSpace(notes) == "" {
+		notes = "_No changes found since the previous release._"
+	}
+
+	return fmt.Sprintf(":memo: *Draft release notes for %s %s* (<%s|create release>)\n\n%s",
+		repoFullName, tagName, draftURL, notes)
+}
+
+// MarkdownToSlack converts the subset of GitHub markdown used in generated release notes to Slack mrkdwn.
+func MarkdownToSlack(markdown string) string {
+	text := strings.ReplaceAll(markdown, "\r\n", "\n")
+	text = markdownBoldRegex.ReplaceAllString(text, "*$1*")
+	text = markdownHeadingRegex.ReplaceAllString(text, "*$1*")
+	text = markdownBulletRegex.ReplaceAllString(text, "$1• ")
+	text = markdownLinkRegex.ReplaceAllString(text, "<$2|$1>")
+	return strings.TrimSpace(text)
+}
@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkdownToSlack(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		expected string
+	}{
+		{
+			name:     "headings become bold",
+			markdown: "## What's Changed",
+			expected: "*What's Changed*",
+		},
+		{
+			name:     "bullets and bold",
+			markdown: "* Fix bug by @dev in https://github.com/o/r/pull/1\n\n**Full Changelog**: https://github.com/o/r/compare/v1...v2",
+			expected: "• Fix bug by @dev in https://github.com/o/r/pull/1\n\n*Full Changelog*: https://github.com/o/r/compare/v1...v2",
+		},
+		{
+			name:     "links",
+			markdown: "- See [the docs](https://example.com/docs)",
+			expected: "• See <https://example.com/docs|the docs>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, MarkdownToSlack(tt.markdown))
+		})
+	}
+}
+
+func TestFormatReleaseNotes(t *testing.T) {
+	text := FormatReleaseNotes("o/r", "https://github.com/o/r", "v1.2.0", "## What's Changed\n* Fix bug")
+	assert.Equal(t,
+		":memo: *Draft release notes for o/r v1.2.0* (<https://github.com/o/r/releases/new?tag=v1.2.0|create release>)\n\n"+
+			"*What's Changed*\n• Fix bug",
+		text)
+
+	empty := FormatReleaseNotes("o/r", "https://github.com/o/r", "v1.2.0", "")
+	assert.Contains(t, empty, "No changes found")
+
+	long := FormatReleaseNotes("o/r", "https://github.com/o/r", "v1.2.0", strings.Repeat("* Change\n", 1000))
+	assert.Contains(t, long, "truncated")
+	assert.Less(t, len(long), maxReleaseNotesLength+200)
+}
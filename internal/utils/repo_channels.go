@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github-slack-notifier/internal/models"
+)
+
+// MaxRepoChannelOverrides is the most per-repository channel overrides a user can have.
+const MaxRepoChannelOverrides = 50
+
+var (
+	// ErrInvalidRepoChannelOverride indicates a repository channel line that can't be parsed.
+	ErrInvalidRepoChannelOverride = errors.New("invalid repository channels")
+	// ErrTooManyRepoChannelOverrides indicates more than MaxRepoChannelOverrides overrides.
+	ErrTooManyRepoChannelOverrides = errors.New("too many repository channel overrides")
+)
+
+// ParseRepoChannelOverrides parses per-repository channels written one per line, as edited in the App Home:
+//
+//	owner/api -> #backend, #api-reviews
+//
+// Blank lines are ignored. Errors name the offending line.
+func ParseRepoChannelOverrides(text string) ([]models.RepoChannelOverride, error) {
+	var overrides []models.RepoChannelOverride
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		override, err := parseRepoChannelOverride(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		for _, existing := range overrides {
+			if strings.EqualFold(existing.RepoFullName, override.RepoFullName) {
+				return nil, fmt.Errorf("line %d: %w: %s is listed more than once",
+					i+1, ErrInvalidRepoChannelOverride, override.RepoFullName)
+			}
+		}
+		overrides = append(overrides, override)
+	}
+
+	if len(overrides) > MaxRepoChannelOverrides {
+		return nil, fmt.Errorf("%w: %d repositories, at most %d are allowed",
+			ErrTooManyRepoChannelOverrides, len(overrides), MaxRepoChannelOverrides)
+	}
+	return overrides, nil
+}
+
+// parseRepoChannelOverride parses a single "owner/repo -> #channel, #channel" line.
+func parseRepoChannelOverride(line string) (models.RepoChannelOverride, error) {
+	repoFullName, channels, found := strings.Cut(line, "->")
+	repoFullName = strings.TrimSpace(repoFullName)
+	if !found || strings.Count(repoFullName, "/") != 1 || strings.ContainsAny(repoFullName, " \t") {
+		return models.RepoChannelOverride{}, fmt.Errorf("%w: expected \"owner/repo -> #channel, #channel\"",
+			ErrInvalidRepoChannelOverride)
+	}
+
+	override := models.RepoChannelOverride{RepoFullName: repoFullName}
+	for _, channel := range strings.Split(channels, ",") {
+		channel = strings.TrimPrefix(strings.TrimSpace(channel), "#")
+		if channel == "" {
+			continue
+		}
+		if strings.ContainsAny(channel, " \t") {
+			return models.RepoChannelOverride{}, fmt.Errorf("%w: separate channels with commas", ErrInvalidRepoChannelOverride)
+		}
+		override.Channels = append(override.Channels, channel)
+	}
+
+	if len(override.Channels) == 0 {
+		return models.RepoChannelOverride{}, fmt.Errorf("%w: expected at least one channel after ->", ErrInvalidRepoChannelOverride)
+	}
+	if len(override.Channels) > models.MaxDefaultChannels {
+		return models.RepoChannelOverride{}, fmt.Errorf("%w: at most %d channels per repository",
+			ErrInvalidRepoChannelOverride, models.MaxDefaultChannels)
+	}
+	return override, nil
+}
+
+// FormatRepoChannelOverrides formats per-repository channels one per line, in the form ParseRepoChannelOverrides reads.
+func FormatRepoChannelOverrides(overrides []models.RepoChannelOverride) string {
+	lines := make([]string, 0, len(overrides))
+	for _, override := range overrides {
+		channels := make([]string, 0, len(override.Channels))
+		for _, channel := range override.Channels {
+			channels = append(channels, "#"+channel)
+		}
+		lines = append(lines, fmt.Sprintf("%s -> %s", override.RepoFullName, strings.Join(channels, ", ")))
+	}
+	return strings.Join(lines, "\n")
+}
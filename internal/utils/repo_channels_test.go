@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github-slack-notifier/internal/models"
+)
+
+func TestParseRepoChannelOverrides(t *testing.T) {
+	text := "owner/api -> #backend, #api-reviews\n\n  owner/web -> frontend  \n"
+
+	overrides, err := ParseRepoChannelOverrides(text)
+	require.NoError(t, err)
+	assert.Equal(t, []models.RepoChannelOverride{
+		{RepoFullName: "owner/api", Channels: []string{"backend", "api-reviews"}},
+		{RepoFullName: "owner/web", Channels: []string{"frontend"}},
+	}, overrides)
+
+	assert.Equal(t, "owner/api -> #backend, #api-reviews\nowner/web -> #frontend", FormatRepoChannelOverrides(overrides))
+
+	reparsed, err := ParseRepoChannelOverrides(FormatRepoChannelOverrides(overrides))
+	require.NoError(t, err)
+	assert.Equal(t, overrides, reparsed)
+}
+
+func TestParseRepoChannelOverrides_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+	}{
+		{name: "missing arrow", text: "owner/api #backend"},
+		{name: "not a repository", text: "api -> #backend"},
+		{name: "no channels", text: "owner/api -> "},
+		{name: "channels not comma separated", text: "owner/api -> #backend #api"},
+		{name: "too many channels", text: "owner/api -> #a, #b, #c, #d, #e, #f"},
+		{name: "repository listed twice", text: "owner/api -> #a\nOwner/API -> #b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseRepoChannelOverrides(tt.text)
+			require.ErrorIs(t, err, ErrInvalidRepoChannelOverride)
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github-slack-notifier/internal/models"
+)
+
+const (
+	// maxReportOpenPRs limits how many open PRs are listed individually in a report.
+	maxReportOpenPRs = 10
+	// halves is used to find the middle of a sorted slice.
+	halves = 2
+)
+
+// FormatChannelReport formats a channel PR report as Slack mrkdwn text.
+// Shared by the /pr-report command and scheduled digests.
+func FormatChannelReport(report *models.ChannelReport, now time.Time) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "*PR report for <#%s> — last %s*\n", report.SlackChannel, FormatReportWindow(report.Window))
+	fmt.Fprintf(&b, "• Open PRs: %d\n", len(report.OpenPRs))
+	fmt.Fprintf(&b, "• Merged: %d · Closed without merging: %d\n", report.MergedCount, report.ClosedCount)
+
+	if len(report.ReviewLatencies) > 0 {
+		fmt.Fprintf(&b, "• Median time to first review: %s (%d reviewed PRs)\n",
+			formatCountdownDuration(MedianDuration(report.ReviewLatencies)), len(report.ReviewLatencies))
+	} else {
+		b.WriteString("• Median time to first review: no reviews yet\n")
+	}
+
+	if engagement := report.Engagement; engagement != nil && engagement.Notifications > 0 {
+		fmt.Fprintf(&b, "• Notifications clicked: %d of %d (%d ignored)\n",
+			engagement.Clicked, engagement.Notifications, engagement.Notifications-engagement.Clicked)
+	}
+
+	if len(report.OpenPRs) == 0 {
+		return strings.TrimSuffix(b.String(), "\n")
+	}
+
+	b.WriteString("\n*Open PRs*\n")
+	for i, pr := range report.OpenPRs {
+		if i == maxReportOpenPRs {
+			fmt.Fprintf(&b, "_…and %d more_\n", len(report.OpenPRs)-maxReportOpenPRs)
+			break
+		}
+		fmt.Fprintf(&b, "• <%s|%s#%d %s> — open %s, %s\n",
+			pr.URL, pr.RepoFullName, pr.PRNumber, pr.Title,
+			formatCountdownDuration(now.Sub(pr.OpenedAt)), releaseCountdownStatus(pr.ReviewState))
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// FormatReportWindow formats a report window as days when it is a whole number of days, otherwise as hours.
+func FormatReportWindow(window time.Duration) string {
+	day := hoursPerDay * time.Hour
+	if window >= day && window%day == 0 {
+		return fmt.Sprintf("%dd", int(window/day))
+	}
+	return fmt.Sprintf("%dh", int(window/time.Hour))
+}
+
+// MedianDuration returns the median of the given durations, or zero if there are none.
+func MedianDuration(durations []time.Duration) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+
+	sorted := make([]time.Duration, len(durations))
+	copy(sorted, durations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	mid := len(sorted) / halves
+	if len(sorted)%halves == 0 {
+		return (sorted[mid-1] + sorted[mid]) / halves
+	}
+	return sorted[mid]
+}
@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github-slack-notifier/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMedianDuration(t *testing.T) {
+	assert.Equal(t, time.Duration(0), MedianDuration(nil))
+	assert.Equal(t, 2*time.Hour, MedianDuration([]time.Duration{3 * time.Hour, time.Hour, 2 * time.Hour}))
+	assert.Equal(t, 90*time.Minute, MedianDuration([]time.Duration{2 * time.Hour, time.Hour}))
+}
+
+func TestFormatReportWindow(t *testing.T) {
+	assert.Equal(t, "7d", FormatReportWindow(7*24*time.Hour))
+	assert.Equal(t, "12h", FormatReportWindow(12*time.Hour))
+	assert.Equal(t, "36h", FormatReportWindow(36*time.Hour))
+}
+
+func TestFormatChannelReport(t *testing.T) {
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	t.Run("empty report", func(t *testing.T) {
+		report := &models.ChannelReport{SlackChannel: "C123", Window: 7 * 24 * time.Hour}
+		expected := "*PR report for <#C123> — last 7d*\n" +
+			"• Open PRs: 0\n" +
+			"• Merged: 0 · Closed without merging: 0\n" +
+			"• Median time to first review: no reviews yet"
+		assert.Equal(t, expected, FormatChannelReport(report, now))
+	})
+
+	t.Run("report with open PRs", func(t *testing.T) {
+		report := &models.ChannelReport{
+			SlackChannel: "C123",
+			Window:       24 * time.Hour,
+			OpenPRs: []models.ChannelReportPR{
+				{
+					RepoFullName: "org/repo",
+					PRNumber:     42,
+					Title:        "Add feature",
+					URL:          "https://github.com/org/repo/pull/42",
+					OpenedAt:     now.Add(-5 * time.Hour),
+					ReviewState:  string(models.ReviewStateApproved),
+				},
+			},
+			MergedCount:     3,
+			ClosedCount:     1,
+			ReviewLatencies: []time.Duration{time.Hour, 3 * time.Hour},
+		}
+		expected := "*PR report for <#C123> — last 1d*\n" +
+			"• Open PRs: 1\n" +
+			"• Merged: 3 · Closed without merging: 1\n" +
+			"• Median time to first review: 2h (2 reviewed PRs)\n" +
+			"\n*Open PRs*\n" +
+			"• <https://github.com/org/repo/pull/42|org/repo#42 Add feature> — open 5h, approved, ready to merge"
+		assert.Equal(t, expected, FormatChannelReport(report, now))
+	})
+
+	t.Run("report with engagement", func(t *testing.T) {
+		report := &models.ChannelReport{
+			SlackChannel: "C123",
+			Window:       7 * 24 * time.Hour,
+			Engagement:   &models.ReportEngagement{Notifications: 5, Clicked: 3},
+		}
+		expected := "*PR report for <#C123> — last 7d*\n" +
+			"• Open PRs: 0\n" +
+			"• Merged: 0 · Closed without merging: 0\n" +
+			"• Median time to first review: no reviews yet\n" +
+			"• Notifications clicked: 3 of 5 (2 ignored)"
+		assert.Equal(t, expected, FormatChannelReport(report, now))
+	})
+}
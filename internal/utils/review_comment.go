@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"github-slack-notifier/internal/models"
+)
+
+// maxThreadedCommentLength is the most characters of a review comment body shown in a thread reply.
+const maxThreadedCommentLength = 600
+
+// FormatReviewCommentReply returns the thread reply text for a review or PR comment, e.g.
+// "✅ *octocat* approved (View on GitHub)" followed by the comment body, quoted and truncated.
+// reviewState is empty for PR conversation comments.
+func FormatReviewCommentReply(author, reviewState, body, url string) string {
+	var action string
+	switch models.ReviewState(strings.ToLower(reviewState)) {
+	case models.ReviewStateApproved:
+		action = "✅ *%s* approved"
+	case models.ReviewStateChangesRequested:
+		action = "🔄 *%s* requested changes"
+	case models.ReviewStateCommented, models.ReviewStateDismissed:
+		action = "💬 *%s* reviewed"
+	default:
+		action = "💬 *%s* commented"
+	}
+
+	text := fmt.Sprintf(action+" (<%s|View on GitHub>)", EscapeSlackText(author), url)
+
+	body, _ = TruncateText(strings.TrimSpace(body), maxThreadedCommentLength, "…")
+	if body == "" {
+		return text
+	}
+	return text + "\n>" + strings.ReplaceAll(EscapeSlackText(body), "\n", "\n>")
+}
@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatReviewCommentReply(t *testing.T) {
+	tests := []struct {
+		name        string
+		author      string
+		reviewState string
+		body        string
+		expected    string
+	}{
+		{
+			name:     "PR comment",
+			author:   "octocat",
+			body:     "Looks good",
+			expected: "💬 *octocat* commented (<https://github.com/o/r/pull/1|View on GitHub>)\n>Looks good",
+		},
+		{
+			name:        "approval without a body",
+			author:      "octocat",
+			reviewState: "APPROVED",
+			expected:    "✅ *octocat* approved (<https://github.com/o/r/pull/1|View on GitHub>)",
+		},
+		{
+			name:        "multi-line body is quoted and escaped",
+			author:      "octocat",
+			reviewState: "changes_requested",
+			body:        "Please fix:\n<script> & co",
+			expected: "🔄 *octocat* requested changes (<https://github.com/o/r/pull/1|View on GitHub>)\n" +
+				">Please fix:\n>&lt;script&gt; &amp; co",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := FormatReviewCommentReply(tt.author, tt.reviewState, tt.body, "https://github.com/o/r/pull/1")
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestFormatReviewCommentReply_Truncates(t *testing.T) {
+	result := FormatReviewCommentReply("octocat", "", strings.Repeat("word ", 500), "https://github.com/o/r/pull/1")
+	assert.True(t, strings.HasSuffix(result, "…"))
+	assert.Less(t, len(result), 800)
+}
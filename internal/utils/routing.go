@@ -0,0 +1,164 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github-slack-notifier/internal/models"
+)
+
+// MaxRoutingRules is the most routing rules a repository can have.
+const MaxRoutingRules = 50
+
+var (
+	// ErrInvalidRoutingRule indicates a routing rule line that can't be parsed.
+	ErrInvalidRoutingRule = errors.New("invalid routing rule")
+	// ErrTooManyRoutingRules indicates more than MaxRoutingRules rules.
+	ErrTooManyRoutingRules = errors.New("too many routing rules")
+)
+
+// ParseRoutingRules parses routing rules written one per line, as edited in the App Home:
+//
+//	paths: api/**, proto/** -> #backend
+//	label: infra -> #platform
+//
+// Blank lines are ignored. Errors name the offending line.
+func ParseRoutingRules(text string) ([]models.RoutingRule, error) {
+	var rules []models.RoutingRule
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		rule, err := parseRoutingRule(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		rules = append(rules, rule)
+	}
+
+	if len(rules) > MaxRoutingRules {
+		return nil, fmt.Errorf("%w: %d rules, at most %d are allowed", ErrTooManyRoutingRules, len(rules), MaxRoutingRules)
+	}
+	return rules, nil
+}
+
+// parseRoutingRule parses a single "paths: ... -> #channel" or "label: ... -> #channel" line.
+func parseRoutingRule(line string) (models.RoutingRule, error) {
+	condition, channel, found := strings.Cut(line, "->")
+	if !found {
+		return models.RoutingRule{}, fmt.Errorf("%w: expected \"paths: <globs> -> #channel\" or \"label: <label> -> #channel\"",
+			ErrInvalidRoutingRule)
+	}
+
+	rule := models.RoutingRule{Channel: strings.TrimPrefix(strings.TrimSpace(channel), "#")}
+	if rule.Channel == "" || strings.ContainsAny(rule.Channel, " \t,") {
+		return models.RoutingRule{}, fmt.Errorf("%w: expected a single channel after ->", ErrInvalidRoutingRule)
+	}
+
+	kind, value, found := strings.Cut(condition, ":")
+	value = strings.TrimSpace(value)
+	switch strings.ToLower(strings.TrimSpace(kind)) {
+	case "paths", "path":
+		for _, pattern := range strings.Split(value, ",") {
+			if pattern = strings.Trim(strings.TrimSpace(pattern), "/"); pattern != "" {
+				rule.Paths = append(rule.Paths, pattern)
+			}
+		}
+		for _, pattern := range rule.Paths {
+			if _, err := path.Match(strings.ReplaceAll(pattern, "**", "*"), ""); err != nil {
+				return models.RoutingRule{}, fmt.Errorf("%w: bad path pattern %q", ErrInvalidRoutingRule, pattern)
+			}
+		}
+	case "label":
+		rule.Label = value
+	}
+
+	if !found || !rule.IsValid() {
+		return models.RoutingRule{}, fmt.Errorf("%w: expected \"paths:\" with at least one glob or \"label:\" with a label name",
+			ErrInvalidRoutingRule)
+	}
+	return rule, nil
+}
+
+// FormatRoutingRules formats routing rules one per line, in the form ParseRoutingRules reads.
+func FormatRoutingRules(rules []models.RoutingRule) string {
+	lines := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		if len(rule.Paths) > 0 {
+			lines = append(lines, fmt.Sprintf("paths: %s -> #%s", strings.Join(rule.Paths, ", "), rule.Channel))
+		} else {
+			lines = append(lines, fmt.Sprintf("label: %s -> #%s", rule.Label, rule.Channel))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// RoutingRulesNeedFiles reports whether any rule matches on changed paths, so the PR's files must be listed.
+func RoutingRulesNeedFiles(rules []models.RoutingRule) bool {
+	for _, rule := range rules {
+		if len(rule.Paths) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchRoutingRule returns the first rule matching a PR's labels or changed files, or nil if none match.
+func MatchRoutingRule(rules []models.RoutingRule, labels, files []string) *models.RoutingRule {
+	for i := range rules {
+		rule := &rules[i]
+		if !rule.IsValid() {
+			continue
+		}
+		if rule.Label != "" {
+			for _, label := range labels {
+				if strings.EqualFold(label, rule.Label) {
+					return rule
+				}
+			}
+			continue
+		}
+		for _, file := range files {
+			for _, pattern := range rule.Paths {
+				if MatchPathGlob(pattern, file) {
+					return rule
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// MatchPathGlob reports whether a slash-separated file path matches a glob pattern.
+// Segments are matched with path.Match, and a "**" segment matches any number of directories,
+// so "api/**" matches everything under api/ and "**/*.proto" matches proto files anywhere.
+func MatchPathGlob(pattern, name string) bool {
+	return matchGlobSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchGlobSegments(patterns, names []string) bool {
+	for len(patterns) > 0 {
+		if patterns[0] == "**" {
+			// Try "**" spanning each possible number of segments, including none
+			for skip := 0; skip <= len(names); skip++ {
+				if matchGlobSegments(patterns[1:], names[skip:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(names) == 0 {
+			return false
+		}
+		if matched, err := path.Match(patterns[0], names[0]); err != nil || !matched {
+			return false
+		}
+		patterns, names = patterns[1:], names[1:]
+	}
+	return len(names) == 0
+}
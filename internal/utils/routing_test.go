@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github-slack-notifier/internal/models"
+)
+
+func TestMatchPathGlob(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		name     string
+		expected bool
+	}{
+		{pattern: "api/**", name: "api/handlers/user.go", expected: true},
+		{pattern: "api/**", name: "api/main.go", expected: true},
+		{pattern: "api/**", name: "web/api/main.go", expected: false},
+		{pattern: "**/*.proto", name: "proto/v1/user.proto", expected: true},
+		{pattern: "**/*.proto", name: "user.proto", expected: true},
+		{pattern: "**/*.proto", name: "proto/v1/user.go", expected: false},
+		{pattern: "docs/*.md", name: "docs/README.md", expected: true},
+		{pattern: "docs/*.md", name: "docs/guides/setup.md", expected: false},
+		{pattern: "services/**/migrations/*", name: "services/billing/db/migrations/001.sql", expected: true},
+		{pattern: "Makefile", name: "Makefile", expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, MatchPathGlob(tt.pattern, tt.name))
+		})
+	}
+}
+
+func TestParseRoutingRules(t *testing.T) {
+	rules, err := ParseRoutingRules("paths: api/**, proto/** -> #backend\n\n  label: Infra -> platform  \n")
+	require.NoError(t, err)
+	assert.Equal(t, []models.RoutingRule{
+		{Paths: []string{"api/**", "proto/**"}, Channel: "backend"},
+		{Label: "Infra", Channel: "platform"},
+	}, rules)
+	assert.Equal(t, "paths: api/**, proto/** -> #backend\nlabel: Infra -> #platform", FormatRoutingRules(rules))
+
+	rules, err = ParseRoutingRules("")
+	require.NoError(t, err)
+	assert.Empty(t, rules)
+
+	for _, invalid := range []string{
+		"api/** #backend",
+		"paths: api/** -> ",
+		"paths: -> #backend",
+		"label: infra -> #platform #ops",
+		"owner: alice -> #backend",
+		"paths: api/[ -> #backend",
+	} {
+		_, err := ParseRoutingRules("label: ok -> #fine\n" + invalid)
+		require.ErrorIs(t, err, ErrInvalidRoutingRule, invalid)
+		assert.Contains(t, err.Error(), "line 2", invalid)
+	}
+}
+
+func TestMatchRoutingRule(t *testing.T) {
+	rules := []models.RoutingRule{
+		{Label: "infra", Channel: "platform"},
+		{Paths: []string{"api/**"}, Channel: "backend"},
+		{Paths: []string{"**/*.ts"}, Channel: "frontend"},
+	}
+
+	tests := []struct {
+		name     string
+		labels   []string
+		files    []string
+		expected string
+	}{
+		{name: "label match is case-insensitive", labels: []string{"Infra"}, files: []string{"web/app.ts"}, expected: "platform"},
+		{name: "earlier rule wins", files: []string{"web/app.ts", "api/main.go"}, expected: "backend"},
+		{name: "later rule", files: []string{"web/app.ts"}, expected: "frontend"},
+		{name: "no match", labels: []string{"docs"}, files: []string{"README.md"}, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := MatchRoutingRule(rules, tt.labels, tt.files)
+			if tt.expected == "" {
+				assert.Nil(t, rule)
+				return
+			}
+			require.NotNil(t, rule)
+			assert.Equal(t, tt.expected, rule.Channel)
+		})
+	}
+}
@@ -0,0 +1,11 @@
+package utils
+
+import "strings"
+
+// minChannelIDLength is the shortest length of a Slack channel ID.
+const minChannelIDLength = 9
+
+// IsChannelID checks if a string looks like a Slack channel ID (e.g., "C0964H95F6C").
+func IsChannelID(s string) bool {
+	return len(s) >= minChannelIDLength && s[0] == 'C' && strings.ToUpper(s) == s
+}
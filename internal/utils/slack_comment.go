@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// slackCommentPrefix matches the "!to-github" prefix of a thread reply to be posted as a PR comment, with an
+// optional colon, capturing the rest of the reply.
+var slackCommentPrefix = regexp.MustCompile(`(?is)^\s*!to-github(?::|\s|$)\s*(.*)$`)
+
+// slackControlSequence matches Slack's <...> control sequences: links, user and channel mentions, and broadcasts.
+var slackControlSequence = regexp.MustCompile(`<([^<>]*)>`)
+
+var slackTextUnescaper = strings.NewReplacer("&lt;", "<", "&gt;", ">", "&amp;", "&")
+
+// ParseSlackComment returns the text of a thread reply after its "!to-github" prefix, and whether it had one.
+func ParseSlackComment(text string) (string, bool) {
+	match := slackCommentPrefix.FindStringSubmatch(text)
+	if match == nil {
+		return "", false
+	}
+	return strings.TrimSpace(match[1]), true
+}
+
+// FormatSlackCommentForGitHub returns the body of the PR comment for a "!to-github" thread reply, attributing it to
+// the Slack user who wrote it and linking back to the reply. Slack's links and mentions are converted to Markdown;
+// user mentions keep only their label, so they never @mention anyone on GitHub.
+func FormatSlackCommentForGitHub(authorName, text, permalink string) string {
+	body := slackControlSequence.ReplaceAllStringFunc(text, func(sequence string) string {
+		inner := sequence[1 : len(sequence)-1]
+		target, label, hasLabel := strings.Cut(inner, "|")
+		switch {
+		case strings.HasPrefix(target, "@"):
+			if hasLabel {
+				return label
+			}
+			return "someone"
+		case strings.HasPrefix(target, "#"):
+			if hasLabel {
+				return "#" + label
+			}
+			return "a channel"
+		case strings.HasPrefix(target, "!"):
+			return strings.TrimPrefix(target, "!")
+		case hasLabel:
+			return fmt.Sprintf("[%s](%s)", label, target)
+		default:
+			return target
+		}
+	})
+
+	attribution := fmt.Sprintf("**%s** commented in Slack:", authorName)
+	if permalink != "" {
+		attribution = fmt.Sprintf("**%s** [commented in Slack](%s):", authorName, permalink)
+	}
+	return attribution + "\n\n" + slackTextUnescaper.Replace(body)
+}
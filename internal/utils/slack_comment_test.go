@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSlackComment(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+		ok       bool
+	}{
+		{"prefix", "!to-github Please split the migration out", "Please split the migration out", true},
+		{"prefix with colon", "  !TO-GITHUB: LGTM", "LGTM", true},
+		{"multiline", "!to-github\nFirst\nSecond", "First\nSecond", true},
+		{"prefix only", "!to-github", "", true},
+		{"longer word", "!to-githubby nope", "", false},
+		{"not at start", "please !to-github this", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, ok := ParseSlackComment(tt.text)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, text)
+		})
+	}
+}
+
+func TestFormatSlackCommentForGitHub(t *testing.T) {
+	text := "Ask <@U123|jane> or <@U456> in <#C123|backend>, see <https://example.com/doc|the doc> &amp; " +
+		"<https://example.com> &lt;3 <!here>"
+
+	assert.Equal(t,
+		"**Sam Smith** [commented in Slack](https://example.slack.com/archives/C1/p1):\n\n"+
+			"Ask jane or someone in #backend, see [the doc](https://example.com/doc) & https://example.com <3 here",
+		FormatSlackCommentForGitHub("Sam Smith", text, "https://example.slack.com/archives/C1/p1"))
+	assert.Equal(t, "**Sam Smith** commented in Slack:\n\nLGTM", FormatSlackCommentForGitHub("Sam Smith", "LGTM", ""))
+}
@@ -0,0 +1,82 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github-slack-notifier/internal/models"
+)
+
+// MaxTeamUserGroups is the most GitHub team to Slack user group mappings a workspace can have.
+const MaxTeamUserGroups = 100
+
+var (
+	// ErrInvalidTeamUserGroup indicates a team mapping line that can't be parsed.
+	ErrInvalidTeamUserGroup = errors.New("invalid team mapping")
+	// ErrTooManyTeamUserGroups indicates more than MaxTeamUserGroups mappings.
+	ErrTooManyTeamUserGroups = errors.New("too many team mappings")
+)
+
+// ParseTeamUserGroups parses GitHub team to Slack user group mappings written one per line, as edited in the App Home:
+//
+//	acme/backend -> @backend-devs
+//	acme/web-platform -> @web
+//
+// Teams are lowercased and only the user group handles are filled in; resolving them to user group IDs
+// needs the workspace's user groups. Blank lines are ignored. Errors name the offending line.
+func ParseTeamUserGroups(text string) ([]models.TeamUserGroup, error) {
+	var mappings []models.TeamUserGroup
+	seen := make(map[string]bool)
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		mapping, err := parseTeamUserGroup(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		if seen[mapping.GitHubTeam] {
+			return nil, fmt.Errorf("line %d: %w: %s is already mapped", i+1, ErrInvalidTeamUserGroup, mapping.GitHubTeam)
+		}
+		seen[mapping.GitHubTeam] = true
+		mappings = append(mappings, mapping)
+	}
+
+	if len(mappings) > MaxTeamUserGroups {
+		return nil, fmt.Errorf("%w: %d mappings, at most %d are allowed", ErrTooManyTeamUserGroups, len(mappings), MaxTeamUserGroups)
+	}
+	return mappings, nil
+}
+
+// parseTeamUserGroup parses a single "org/team-slug -> @handle" line.
+func parseTeamUserGroup(line string) (models.TeamUserGroup, error) {
+	team, handle, found := strings.Cut(line, "->")
+	if !found {
+		return models.TeamUserGroup{}, fmt.Errorf("%w: expected \"org/team-slug -> @user-group\"", ErrInvalidTeamUserGroup)
+	}
+
+	team = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(team), "@"))
+	org, slug, found := strings.Cut(team, "/")
+	if !found || org == "" || slug == "" || strings.ContainsAny(team, " \t,") || strings.Count(team, "/") > 1 {
+		return models.TeamUserGroup{}, fmt.Errorf("%w: expected a GitHub team like \"org/team-slug\" before ->", ErrInvalidTeamUserGroup)
+	}
+
+	handle = strings.TrimPrefix(strings.TrimSpace(handle), "@")
+	if handle == "" || strings.ContainsAny(handle, " \t,@") {
+		return models.TeamUserGroup{}, fmt.Errorf("%w: expected a single user group like \"@backend-devs\" after ->", ErrInvalidTeamUserGroup)
+	}
+
+	return models.TeamUserGroup{GitHubTeam: team, SlackUserGroupHandle: handle}, nil
+}
+
+// FormatTeamUserGroups formats team mappings one per line, in the form ParseTeamUserGroups reads.
+func FormatTeamUserGroups(mappings []models.TeamUserGroup) string {
+	lines := make([]string, 0, len(mappings))
+	for _, mapping := range mappings {
+		lines = append(lines, fmt.Sprintf("%s -> @%s", mapping.GitHubTeam, mapping.SlackUserGroupHandle))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github-slack-notifier/internal/models"
+)
+
+func TestParseTeamUserGroups(t *testing.T) {
+	mappings, err := ParseTeamUserGroups("Acme/Backend -> @backend-devs\n\n  @acme/web -> web  \n")
+	require.NoError(t, err)
+	assert.Equal(t, []models.TeamUserGroup{
+		{GitHubTeam: "acme/backend", SlackUserGroupHandle: "backend-devs"},
+		{GitHubTeam: "acme/web", SlackUserGroupHandle: "web"},
+	}, mappings)
+	assert.Equal(t, "acme/backend -> @backend-devs\nacme/web -> @web", FormatTeamUserGroups(mappings))
+
+	mappings, err = ParseTeamUserGroups("")
+	require.NoError(t, err)
+	assert.Empty(t, mappings)
+
+	for _, invalid := range []string{
+		"acme/backend @backend-devs",
+		"backend -> @backend-devs",
+		"acme/ -> @backend-devs",
+		"acme/backend/api -> @backend-devs",
+		"acme/backend -> ",
+		"acme/backend -> @backend @web",
+		"acme/ok -> @other",
+	} {
+		_, err := ParseTeamUserGroups("acme/ok -> @fine\n" + invalid)
+		require.ErrorIs(t, err, ErrInvalidTeamUserGroup, invalid)
+		assert.Contains(t, err.Error(), "line 2", invalid)
+	}
+}
@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// wordBoundaryWindow is the fraction of the kept text searched backwards for a space to cut at,
+// so truncation avoids splitting a word without throwing away most of the text.
+const wordBoundaryWindow = 4
+
+// TruncateText shortens text to at most maxLength runes, including the ellipsis, and reports whether it was cut.
+// Text is cut at a word boundary when one falls near the limit, and never in the middle of a multi-byte character.
+// A maxLength of zero or less disables truncation.
+func TruncateText(text string, maxLength int, ellipsis string) (string, bool) {
+	if maxLength <= 0 || utf8.RuneCountInString(text) <= maxLength {
+		return text, false
+	}
+
+	keep := maxLength - utf8.RuneCountInString(ellipsis)
+	if keep <= 0 {
+		// No room for the ellipsis, so hard cut instead
+		return string([]rune(text)[:maxLength]), true
+	}
+
+	runes := []rune(text)[:keep]
+	for i := len(runes) - 1; i >= keep-keep/wordBoundaryWindow && i > 0; i-- {
+		if unicode.IsSpace(runes[i]) {
+			runes = runes[:i]
+			break
+		}
+	}
+
+	return strings.TrimRightFunc(string(runes), unicode.IsSpace) + ellipsis, true
+}
+
+// EscapeSlackText escapes the characters Slack treats as control sequences in message text.
+func EscapeSlackText(text string) string {
+	return slackTextEscaper.Replace(text)
+}
+
+var slackTextEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTruncateText(t *testing.T) {
+	tests := []struct {
+		name          string
+		text          string
+		maxLength     int
+		ellipsis      string
+		expected      string
+		wantTruncated bool
+	}{
+		{
+			name:      "short text is unchanged",
+			text:      "Fix login bug",
+			maxLength: 20,
+			ellipsis:  "…",
+			expected:  "Fix login bug",
+		},
+		{
+			name:      "zero limit disables truncation",
+			text:      "Fix login bug",
+			maxLength: 0,
+			ellipsis:  "…",
+			expected:  "Fix login bug",
+		},
+		{
+			name:          "cuts at word boundary near the limit",
+			text:          "Bump version to v2.0.1",
+			maxLength:     18,
+			ellipsis:      "…",
+			expected:      "Bump version to…",
+			wantTruncated: true,
+		},
+		{
+			name:          "hard cuts a long word",
+			text:          "Supercalifragilisticexpialidocious",
+			maxLength:     10,
+			ellipsis:      "...",
+			expected:      "Superca...",
+			wantTruncated: true,
+		},
+		{
+			name:          "never splits multi-byte characters",
+			text:          "日本語のタイトルです",
+			maxLength:     5,
+			ellipsis:      "…",
+			expected:      "日本語の…",
+			wantTruncated: true,
+		},
+		{
+			name:          "hard cuts without ellipsis when it does not fit",
+			text:          "Fix login bug",
+			maxLength:     2,
+			ellipsis:      "...",
+			expected:      "Fi",
+			wantTruncated: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, truncated := TruncateText(tt.text, tt.maxLength, tt.ellipsis)
+			assert.Equal(t, tt.expected, result)
+			assert.Equal(t, tt.wantTruncated, truncated)
+		})
+	}
+}
+
+func TestEscapeSlackText(t *testing.T) {
+	assert.Equal(t, "a &lt;b&gt; &amp; c", EscapeSlackText("a <b> & c"))
+}
@@ -0,0 +1,142 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github-slack-notifier/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	reportTestTeamID        = "T123456789"
+	reportTestUserID        = "U123456789"
+	reportTestReplyChannel  = "C123456789" // general, where the command is run
+	reportTestTargetChannel = "C987654321" // test-channel, reported on
+)
+
+func TestChannelReportAccessIntegration(t *testing.T) {
+	// Setup test harness - this starts the real application
+	harness := NewTestHarness(t)
+	defer harness.Cleanup()
+
+	// Context for database operations
+	ctx := context.Background()
+
+	// setup resets state, with the given users as the members of the reported channel
+	setup := func(t *testing.T, members ...string) *ephemeralCapture {
+		t.Helper()
+		require.NoError(t, harness.ResetForTest(ctx))
+		setupTestWorkspace(t, harness, reportTestUserID)
+		mockConversationMembers(members)
+		return captureEphemeralMessages()
+	}
+
+	t.Run("report on a channel the user isn't in is rejected", func(t *testing.T) {
+		ephemerals := setup(t, "U000000001", "U000000002")
+
+		require.NoError(t, enqueueChannelReportJob(ctx, harness, reportTestTargetChannel, true))
+
+		assert.Empty(t, harness.SlackRequestCapture().GetPostMessageRequests(), "Nothing is posted to the channel")
+		texts := ephemerals.Texts()
+		require.Len(t, texts, 1)
+		assert.Contains(t, texts[0], "You can only report on channels you're a member of")
+	})
+
+	t.Run("members can post a report to the channel", func(t *testing.T) {
+		ephemerals := setup(t, "U000000001", reportTestUserID)
+
+		require.NoError(t, enqueueChannelReportJob(ctx, harness, reportTestTargetChannel, true))
+
+		posts := harness.SlackRequestCapture().GetPostMessageRequests()
+		require.Len(t, posts, 1)
+		assert.Equal(t, reportTestTargetChannel, posts[0].Channel)
+		assert.Empty(t, ephemerals.Texts())
+	})
+
+	t.Run("report on the current channel doesn't check membership", func(t *testing.T) {
+		ephemerals := setup(t)
+
+		require.NoError(t, enqueueChannelReportJob(ctx, harness, reportTestReplyChannel, false))
+
+		assert.Zero(t, httpmock.GetCallCountInfo()["POST https://slack.com/api/conversations.members"])
+		require.Len(t, ephemerals.Texts(), 1, "Expected the report to be sent to the user")
+	})
+}
+
+// Helper functions
+
+// ephemeralCapture records the text of chat.postEphemeral requests.
+type ephemeralCapture struct {
+	mu    sync.Mutex
+	texts []string
+}
+
+// Texts returns the text of each ephemeral message sent.
+func (c *ephemeralCapture) Texts() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.texts...)
+}
+
+// captureEphemeralMessages mocks chat.postEphemeral, recording the messages sent.
+func captureEphemeralMessages() *ephemeralCapture {
+	capture := &ephemeralCapture{}
+	httpmock.RegisterResponder("POST", "https://slack.com/api/chat.postEphemeral",
+		func(req *http.Request) (*http.Response, error) {
+			if err := req.ParseForm(); err != nil {
+				return nil, err
+			}
+			capture.mu.Lock()
+			capture.texts = append(capture.texts, req.PostForm.Get("text"))
+			capture.mu.Unlock()
+			return httpmock.NewJsonResponse(200, map[string]interface{}{
+				"ok":         true,
+				"message_ts": "1234567890.123456",
+			})
+		})
+	return capture
+}
+
+// mockConversationMembers mocks the member list of every channel.
+func mockConversationMembers(members []string) {
+	httpmock.RegisterResponder("POST", "https://slack.com/api/conversations.members",
+		httpmock.NewJsonResponderOrPanic(200, map[string]interface{}{
+			"ok":                true,
+			"members":           append([]string{}, members...),
+			"response_metadata": map[string]interface{}{"next_cursor": ""},
+		}))
+}
+
+// enqueueChannelReportJob runs a week's report on a channel, requested from the reply channel.
+func enqueueChannelReportJob(ctx context.Context, harness *TestHarness, channel string, post bool) error {
+	traceID := uuid.New().String()
+	reportJob := models.ChannelReportJob{
+		ID:           uuid.New().String(),
+		SlackTeamID:  reportTestTeamID,
+		SlackChannel: channel,
+		SlackUserID:  reportTestUserID,
+		ReplyChannel: reportTestReplyChannel,
+		Window:       7 * 24 * time.Hour,
+		Post:         post,
+		TraceID:      traceID,
+	}
+	payload, err := json.Marshal(reportJob)
+	if err != nil {
+		return err
+	}
+	return harness.FakeCloudTasks().EnqueueJob(ctx, &models.Job{
+		ID:      reportJob.ID,
+		Type:    models.JobTypeChannelReport,
+		TraceID: traceID,
+		Payload: payload,
+	})
+}
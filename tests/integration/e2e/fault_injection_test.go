@@ -0,0 +1,38 @@
+package e2e
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github-slack-notifier/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFaultInjectionIntegration(t *testing.T) {
+	t.Run("Slack rate limits leave no tracked message behind", func(t *testing.T) {
+		harness := NewTestHarnessWithFaults(t, config.FaultInjectionConfig{SlackRateLimitPercent: 100})
+		defer harness.Cleanup()
+		harness.SetupMockResponses()
+
+		ctx := context.Background()
+		require.NoError(t, harness.ResetForTest(ctx))
+		setupTestWorkspace(t, harness, "U123456789")
+		setupTestUser(t, harness, "test-user", "U123456789", "test-channel")
+		setupTestRepo(t, harness, "test-channel")
+		setupGitHubInstallation(t, harness)
+
+		payload := buildPROpenedPayload("testorg/testrepo", 123, "Add new feature", "test-user")
+		resp := sendGitHubWebhook(t, harness, "pull_request", payload)
+		defer func() { _ = resp.Body.Close() }()
+		assert.NotEqual(t, http.StatusOK, resp.StatusCode, "the failed job should be reported for Cloud Tasks to retry")
+
+		// Nothing reached Slack, and nothing was tracked that would make a retry skip the post
+		assert.Empty(t, harness.SlackRequestCapture().GetPostMessageRequests())
+		docs, err := harness.FirestoreClient().Collection("trackedmessages").Documents(ctx).GetAll()
+		require.NoError(t, err)
+		assert.Empty(t, docs)
+	})
+}
@@ -14,10 +14,12 @@ import (
 	"time"
 
 	"github-slack-notifier/internal/config"
+	"github-slack-notifier/internal/faults"
 	"github-slack-notifier/internal/handlers"
 	"github-slack-notifier/internal/log"
 	"github-slack-notifier/internal/middleware"
 	"github-slack-notifier/internal/models"
+	"github-slack-notifier/internal/routes"
 	"github-slack-notifier/internal/services"
 	firestoreTesting "github-slack-notifier/internal/testing"
 
@@ -47,6 +49,7 @@ type TestHarness struct {
 
 	// Services (exposed for testing)
 	SlackWorkspaceService *services.SlackWorkspaceService
+	FirestoreService      *services.FirestoreService
 	Router                *gin.Engine
 
 	// Test database with isolation
@@ -68,6 +71,13 @@ type TestHarness struct {
 // NewTestHarness creates a new test harness that runs the real application.
 func NewTestHarness(t *testing.T) *TestHarness {
 	t.Helper()
+	return NewTestHarnessWithFaults(t, config.FaultInjectionConfig{})
+}
+
+// NewTestHarnessWithFaults creates a test harness whose Slack and GitHub API calls fail at the given rates,
+// to validate retry and deduplication behaviour. Faults are injected in front of the mocked APIs.
+func NewTestHarnessWithFaults(t *testing.T, faultInjection config.FaultInjectionConfig) *TestHarness {
+	t.Helper()
 
 	// Setup test context
 	_, cancel := context.WithCancel(context.Background())
@@ -120,6 +130,7 @@ func NewTestHarness(t *testing.T) *TestHarness {
 		ServerWriteTimeout:       30 * time.Second,
 		ServerShutdownTimeout:    10 * time.Second,
 		WebhookProcessingTimeout: 5 * time.Second, // Reasonable timeout for tests
+		FaultInjection:           faultInjection,
 	}
 
 	// Create per-test HTTP client with isolated mocking
@@ -156,6 +167,7 @@ func NewTestHarness(t *testing.T) *TestHarness {
 		baseURL:               baseURL,
 		config:                cfg,
 		SlackWorkspaceService: services.slackWorkspaceService,
+		FirestoreService:      services.firestoreService,
 		Router:                services.router,
 		testDB:                testDB,
 		fakeCloudTasks:        fakeCloudTasks,
@@ -179,6 +191,7 @@ func NewTestHarness(t *testing.T) *TestHarness {
 // appServices holds services that need to be exposed to tests.
 type appServices struct {
 	slackWorkspaceService *services.SlackWorkspaceService
+	firestoreService      *services.FirestoreService
 	router                *gin.Engine
 }
 
@@ -195,12 +208,17 @@ func startApplication(
 	// Create services
 	firestoreService := services.NewFirestoreService(firestoreClient)
 
+	// Inject any configured faults in front of the mocked transport
+	faultInjector := faults.NewInjector(cfg.FaultInjection)
+	slackHTTPClient := &http.Client{Transport: faultInjector.SlackTransport(httpClient.Transport), Timeout: httpClient.Timeout}
+
 	// Create Slack service with OAuth support
-	slackWorkspaceService := services.NewSlackWorkspaceService(firestoreClient)
-	slackService := services.NewSlackService(slackWorkspaceService, cfg.Emoji, cfg, httpClient)
+	slackWorkspaceService := services.NewSlackWorkspaceService(firestoreClient, nil)
+	slackService := services.NewSlackService(slackWorkspaceService, cfg.Emoji, cfg, slackHTTPClient)
 
 	// Create GitHub API service with mocked transport
-	githubService, err := services.NewGitHubServiceWithTransport(cfg, firestoreService, httpClient.Transport)
+	githubService, err := services.NewGitHubServiceWithTransport(cfg, firestoreService,
+		faultInjector.GitHubTransport(httpClient.Transport))
 	if err != nil {
 		panic(fmt.Sprintf("failed to create GitHub service: %v", err))
 	}
@@ -213,9 +231,18 @@ func startApplication(
 		githubService,
 		cfg.GitHubWebhookSecret,
 		cfg.Emoji,
+		handlers.GitHubHandlerOptions{
+			PreviousWebhookSecret:    cfg.GitHubWebhookSecretPrevious,
+			EnterpriseWebhookSecrets: cfg.GitHubEnterpriseWebhookSecrets(),
+			StrictChannelMatching:    cfg.StrictChannelMatching,
+			PresentationRules:        cfg.PresentationRules,
+			PRUpdateBudget:           cfg.PRUpdateBudget,
+			WebhookEventRetention:    cfg.WebhookEventRetention,
+			SkipLabel:                cfg.SkipLabel,
+		},
 	)
 
-	githubAuthService := services.NewGitHubAuthService(cfg, firestoreService)
+	githubAuthService := services.NewGitHubAuthService(cfg, firestoreService, nil)
 	oauthHandler := handlers.NewOAuthHandler(githubAuthService, firestoreService, slackService, slackWorkspaceService, cfg, httpClient)
 
 	slackHandler := handlers.NewSlackHandler(
@@ -230,26 +257,17 @@ func startApplication(
 	router.Use(middleware.LoggingMiddleware())
 
 	// Configure routes
-	router.POST("/webhooks/github", githubHandler.HandleWebhook)
-	router.POST("/jobs/process", middleware.CloudTasksAuthMiddleware(cfg), jobProcessor.ProcessJob)
-	router.GET("/auth/github/link", oauthHandler.HandleGitHubLink)
-	router.GET("/auth/github/callback", oauthHandler.HandleGitHubCallback)
-
-	// Configure Slack OAuth routes (if enabled)
-	if cfg.IsSlackOAuthEnabled() {
-		router.GET("/auth/slack/install", oauthHandler.HandleSlackInstall)
-		router.GET("/auth/slack/callback", oauthHandler.HandleSlackOAuthCallback)
-	}
-
-	router.POST("/webhooks/slack/events", slackHandler.HandleEvent)
-	router.POST("/webhooks/slack/interactions", slackHandler.HandleInteraction)
-	router.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"status": "healthy"})
-	})
+	routes.Register(router, &routes.Handlers{
+		GitHub: githubHandler,
+		Slack:  slackHandler,
+		Jobs:   jobProcessor,
+		OAuth:  oauthHandler,
+	}, cfg)
 
 	// Send services to the test harness
 	servicesChan <- &appServices{
 		slackWorkspaceService: slackWorkspaceService,
+		firestoreService:      firestoreService,
 		router:                router,
 	}
 
@@ -340,8 +358,9 @@ func (h *TestHarness) FirestoreClient() *firestore.Client {
 	return h.testDB.Client()
 }
 
-// ClearFirestore clears all data from the test database.
+// ClearFirestore clears all data from the test database, and the documents the application cached from it.
 func (h *TestHarness) ClearFirestore(ctx context.Context) error {
+	h.FirestoreService.ClearCache()
 	return h.testDB.Cleanup(ctx)
 }
 
@@ -403,6 +422,7 @@ func (h *TestHarness) SetupUser(ctx context.Context, githubUsername, slackUserID
 		"tagging_enabled":       true,         // Enable tagging for test users
 	}
 	_, err := h.testDB.Collection("users").Doc(githubUsername).Set(ctx, user)
+	h.FirestoreService.ClearCache() // Written behind the application's back
 	return err
 }
 
@@ -424,6 +444,7 @@ func (h *TestHarness) SetupRepo(ctx context.Context, repoFullName, channelID, te
 		"slack_team_id":  teamID,
 	}
 	_, err := h.testDB.Collection("repos").Doc(docID).Set(ctx, repo)
+	h.FirestoreService.ClearCache() // Written behind the application's back
 	return err
 }
 
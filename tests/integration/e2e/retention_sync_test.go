@@ -0,0 +1,203 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github-slack-notifier/internal/models"
+	"github-slack-notifier/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	retentionTestChannel = "C987654321" // test-channel
+	retentionTestRepo    = "testorg/testrepo"
+	retentionTestTeamID  = "T123456789"
+	retentionTestTS      = "1700000000.000100" // Differs from the timestamp the chat.postMessage mock returns
+)
+
+func TestRetentionSyncIntegration(t *testing.T) {
+	// Setup test harness - this starts the real application
+	harness := NewTestHarness(t)
+	defer harness.Cleanup()
+
+	// Setup mock responses for external APIs
+	harness.SetupMockResponses()
+
+	// Context for database operations
+	ctx := context.Background()
+
+	// setup resets state and tracks a bot message for the PR in a channel with repost set as given
+	setup := func(t *testing.T, prNumber int, repostPruned bool) {
+		t.Helper()
+		require.NoError(t, harness.ResetForTest(ctx))
+
+		setupTestWorkspace(t, harness, "U123456789")
+		setupTestUser(t, harness, testUserLogin, "U123456789", retentionTestChannel)
+		setupTestRepo(t, harness, retentionTestChannel)
+		setupGitHubInstallation(t, harness)
+		require.NoError(t, harness.FirestoreService.SaveChannelConfig(ctx, &models.ChannelConfig{
+			SlackTeamID:    retentionTestTeamID,
+			SlackChannelID: retentionTestChannel,
+			RepostPruned:   repostPruned,
+		}))
+		require.NoError(t, harness.SetupTrackedMessage(ctx, retentionTestRepo, prNumber, retentionTestChannel,
+			retentionTestTeamID, retentionTestTS))
+	}
+
+	t.Run("message still in channel is left alone", func(t *testing.T) {
+		setup(t, 5101, true)
+		mockConversationHistory(t, true)
+
+		require.NoError(t, enqueueRetentionSyncJob(ctx, harness))
+
+		messages := getRetentionTestMessages(ctx, t, harness, 5101)
+		require.Len(t, messages, 1)
+		assert.Equal(t, retentionTestTS, messages[0].SlackMessageTS)
+		assert.Empty(t, harness.SlackRequestCapture().GetPostMessageRequests())
+	})
+
+	t.Run("pruned open PR is reposted when enabled", func(t *testing.T) {
+		setup(t, 5102, true)
+		mockConversationHistory(t, false)
+		mockRetentionTestPR(5102, "open")
+
+		require.NoError(t, enqueueRetentionSyncJob(ctx, harness))
+
+		posts := harness.SlackRequestCapture().GetPostMessageRequests()
+		require.Len(t, posts, 1, "Expected the pruned PR to be reposted")
+		assert.Equal(t, retentionTestChannel, posts[0].Channel)
+		assert.Contains(t, posts[0].Text, "https://github.com/testorg/testrepo/pull/5102")
+
+		messages := getRetentionTestMessages(ctx, t, harness, 5102)
+		require.Len(t, messages, 1, "Expected the repost to replace the pruned message's tracking")
+		assert.NotEqual(t, retentionTestTS, messages[0].SlackMessageTS)
+		assert.Equal(t, models.MessageSourceBot, messages[0].MessageSource)
+	})
+
+	t.Run("pruned message tracking is dropped when repost is disabled", func(t *testing.T) {
+		setup(t, 5103, false)
+		mockConversationHistory(t, false)
+		mockRetentionTestPR(5103, "open")
+
+		require.NoError(t, enqueueRetentionSyncJob(ctx, harness))
+
+		assert.Empty(t, getRetentionTestMessages(ctx, t, harness, 5103))
+		assert.Empty(t, harness.SlackRequestCapture().GetPostMessageRequests())
+	})
+
+	t.Run("pruned closed PR is not reposted", func(t *testing.T) {
+		setup(t, 5104, true)
+		mockConversationHistory(t, false)
+		mockRetentionTestPR(5104, "closed")
+
+		require.NoError(t, enqueueRetentionSyncJob(ctx, harness))
+
+		assert.Empty(t, getRetentionTestMessages(ctx, t, harness, 5104))
+		assert.Empty(t, harness.SlackRequestCapture().GetPostMessageRequests())
+	})
+
+	t.Run("Slack lookup error keeps tracking and fails the job", func(t *testing.T) {
+		setup(t, 5105, true)
+		httpmock.RegisterResponder("POST", "https://slack.com/api/conversations.history",
+			httpmock.NewJsonResponderOrPanic(200, map[string]interface{}{
+				"ok":    false,
+				"error": "internal_error",
+			}))
+
+		require.Error(t, enqueueRetentionSyncJob(ctx, harness))
+
+		messages := getRetentionTestMessages(ctx, t, harness, 5105)
+		require.Len(t, messages, 1)
+		assert.Equal(t, retentionTestTS, messages[0].SlackMessageTS)
+		assert.Empty(t, harness.SlackRequestCapture().GetPostMessageRequests())
+	})
+}
+
+// Helper functions
+
+// mockConversationHistory mocks the Slack history lookup for a tracked message, returning the requested message
+// if it still exists or nothing if retention deleted it.
+func mockConversationHistory(t *testing.T, exists bool) {
+	t.Helper()
+	httpmock.RegisterResponder("POST", "https://slack.com/api/conversations.history",
+		func(req *http.Request) (*http.Response, error) {
+			if err := req.ParseForm(); err != nil {
+				return nil, err
+			}
+			messages := []map[string]interface{}{}
+			if exists {
+				messages = append(messages, map[string]interface{}{
+					"type": "message",
+					"ts":   req.PostForm.Get("latest"),
+					"text": "PR message",
+				})
+			}
+			return httpmock.NewJsonResponse(200, map[string]interface{}{
+				"ok":       true,
+				"messages": messages,
+				"has_more": false,
+			})
+		})
+}
+
+// mockRetentionTestPR mocks fetching the test PR with the given state.
+func mockRetentionTestPR(prNumber int, state string) {
+	httpmock.RegisterResponder("GET", fmt.Sprintf("https://api.github.com/repos/%s/pulls/%d", retentionTestRepo, prNumber),
+		httpmock.NewJsonResponderOrPanic(200, map[string]interface{}{
+			"number":    prNumber,
+			"title":     "Retention test PR",
+			"html_url":  fmt.Sprintf("https://github.com/%s/pull/%d", retentionTestRepo, prNumber),
+			"state":     state,
+			"additions": 10,
+			"deletions": 5,
+			"user": map[string]interface{}{
+				"id":    100001,
+				"login": testUserLogin,
+			},
+			"base": map[string]interface{}{
+				"ref": "main",
+				"repo": map[string]interface{}{
+					"name":      "testrepo",
+					"full_name": retentionTestRepo,
+					"html_url":  "https://github.com/" + retentionTestRepo,
+					"owner": map[string]interface{}{
+						"login": "testorg",
+					},
+				},
+			},
+		}))
+}
+
+// enqueueRetentionSyncJob runs the scheduled retention sweep, returning an error if the job failed.
+func enqueueRetentionSyncJob(ctx context.Context, harness *TestHarness) error {
+	payload, err := json.Marshal(models.RetentionSyncJob{})
+	if err != nil {
+		return err
+	}
+	return harness.FakeCloudTasks().EnqueueJob(ctx, &models.Job{
+		ID:      uuid.New().String(),
+		Type:    models.JobTypeRetentionSync,
+		TraceID: uuid.New().String(),
+		Payload: payload,
+	})
+}
+
+// getRetentionTestMessages returns the tracked messages for a test PR.
+func getRetentionTestMessages(ctx context.Context, t *testing.T, harness *TestHarness, prNumber int) []*models.TrackedMessage {
+	t.Helper()
+	messages, err := harness.FirestoreService.GetTrackedMessages(ctx, services.TrackedMessageQuery{
+		RepoFullName: retentionTestRepo,
+		PRNumber:     prNumber,
+		SlackTeamID:  retentionTestTeamID,
+	})
+	require.NoError(t, err)
+	return messages
+}
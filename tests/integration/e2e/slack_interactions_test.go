@@ -87,8 +87,8 @@ func createChannelSelectionViewSubmissionPayload(userID, channelID, teamID strin
 				"values": map[string]interface{}{
 					"channel_input": map[string]interface{}{
 						"channel_select": map[string]interface{}{
-							"type":             "channels_select",
-							"selected_channel": channelID,
+							"type":              "multi_channels_select",
+							"selected_channels": []string{channelID},
 						},
 					},
 				},
@@ -0,0 +1,143 @@
+package e2e
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github-slack-notifier/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const deliveryTestTeamID = "T123456789"
+
+func TestWebhookDeliveryDeduplicationIntegration(t *testing.T) {
+	// Setup test harness - this starts the real application
+	harness := NewTestHarness(t)
+	defer harness.Cleanup()
+
+	// Context for database operations
+	ctx := context.Background()
+
+	setup := func(t *testing.T) {
+		t.Helper()
+		require.NoError(t, harness.ResetForTest(ctx))
+		setupTestWorkspace(t, harness, "U123456789")
+		setupTestUser(t, harness, "test-user", "U123456789", "test-channel")
+		setupTestRepo(t, harness, "test-channel")
+		setupGitHubInstallation(t, harness)
+	}
+
+	// seedDelivery writes a delivery record as an earlier attempt at the delivery would have left it
+	seedDelivery := func(t *testing.T, deliveryID string, data map[string]interface{}) {
+		t.Helper()
+		_, err := harness.FirestoreClient().Collection("webhook_deliveries").Doc(deliveryID).Set(ctx, data)
+		require.NoError(t, err)
+	}
+
+	payload := buildPROpenedPayload("testorg/testrepo", 123, "Add new feature", "test-user")
+
+	t.Run("redelivery of a processed delivery is skipped", func(t *testing.T) {
+		setup(t)
+
+		resp := sendGitHubWebhookWithDeliveryID(t, harness, "pull_request", payload, "delivery-redelivered")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Len(t, harness.SlackRequestCapture().GetPostMessageRequests(), 1)
+
+		delivery := getWebhookDelivery(t, harness, "delivery-redelivered")
+		assert.True(t, delivery.Processed)
+		assert.Equal(t, []string{deliveryTestTeamID}, delivery.CompletedWorkspaces)
+		assert.True(t, delivery.ExpiresAt.After(time.Now()), "Record should expire in the future")
+
+		harness.ResetForNextStep()
+
+		resp = sendGitHubWebhookWithDeliveryID(t, harness, "pull_request", payload, "delivery-redelivered")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		jobs := harness.FakeCloudTasks().GetExecutedJobs()
+		require.Len(t, jobs, 1, "No workspace PR job should be fanned out")
+		assert.Equal(t, models.JobTypeGitHubWebhook, jobs[0].Type)
+		assert.Empty(t, harness.SlackRequestCapture().GetPostMessageRequests())
+	})
+
+	t.Run("retried delivery skips workspaces already posted to", func(t *testing.T) {
+		setup(t)
+		seedDelivery(t, "delivery-retried", map[string]interface{}{
+			"id":                   "delivery-retried",
+			"completed_workspaces": []string{deliveryTestTeamID},
+			"updated_at":           time.Now(),
+			"expires_at":           time.Now().Add(time.Hour),
+		})
+
+		resp := sendGitHubWebhookWithDeliveryID(t, harness, "pull_request", payload, "delivery-retried")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		jobs := harness.FakeCloudTasks().GetExecutedJobs()
+		require.Len(t, jobs, 2)
+		assert.Equal(t, models.JobTypeWorkspacePR, jobs[1].Type)
+		assert.Empty(t, harness.SlackRequestCapture().GetPostMessageRequests())
+
+		docs, err := harness.FirestoreClient().Collection("trackedmessages").Documents(ctx).GetAll()
+		require.NoError(t, err)
+		assert.Empty(t, docs)
+
+		assert.True(t, getWebhookDelivery(t, harness, "delivery-retried").Processed)
+	})
+
+	t.Run("delivery is processed when its record can't be read", func(t *testing.T) {
+		setup(t)
+		setupTrackedMessage(t, harness, 456, "test-channel")
+		// A processed flag of the wrong type fails to unmarshal, as a failed lookup would
+		seedDelivery(t, "delivery-unreadable", map[string]interface{}{
+			"id":        "delivery-unreadable",
+			"processed": "yes",
+		})
+
+		reviewPayload := buildReviewSubmittedPayload("testorg/testrepo", 456, "test-user", "approved")
+		resp := sendGitHubWebhookWithDeliveryID(t, harness, "pull_request_review", reviewPayload, "delivery-unreadable")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		// The review's reaction sync is fanned out, and the record is overwritten once the delivery succeeds
+		jobs := harness.FakeCloudTasks().GetExecutedJobs()
+		require.Len(t, jobs, 2)
+		assert.Equal(t, models.JobTypeReactionSync, jobs[1].Type)
+		assert.True(t, getWebhookDelivery(t, harness, "delivery-unreadable").Processed)
+	})
+}
+
+// Helper functions
+
+// sendGitHubWebhookWithDeliveryID sends a GitHub webhook with a fixed X-GitHub-Delivery ID, as GitHub does on redelivery.
+func sendGitHubWebhookWithDeliveryID(t *testing.T, harness *TestHarness, eventType string, payload []byte, deliveryID string) *http.Response {
+	t.Helper()
+
+	signature := generateWebhookSignature(payload, harness.Config().GitHubWebhookSecret)
+	req := buildWebhookRequest(t, harness.BaseURL()+"/webhooks/github", eventType, payload, signature)
+	req.Header.Set("X-Github-Delivery", deliveryID)
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to send request: %v", err)
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	return resp
+}
+
+// getWebhookDelivery reads a delivery's processing record from Firestore.
+func getWebhookDelivery(t *testing.T, harness *TestHarness, deliveryID string) *models.WebhookDelivery {
+	t.Helper()
+
+	doc, err := harness.FirestoreClient().Collection("webhook_deliveries").Doc(deliveryID).Get(context.Background())
+	require.NoError(t, err)
+	var delivery models.WebhookDelivery
+	require.NoError(t, doc.DataTo(&delivery))
+	return &delivery
+}
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github-slack-notifier/internal/models"
+	"github-slack-notifier/internal/services"
 	"github-slack-notifier/tests/integration/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -192,14 +193,13 @@ func TestJobQueueManagement(t *testing.T) {
 
 		// Verify all jobs created their respective tracked messages
 		for i := 1; i <= 5; i++ {
-			trackedMessages, err := app.FirestoreService.GetTrackedMessages(
-				ctx,
-				constants.DefaultRepoFullName,
-				100+i,
-				constants.DefaultSlackChannel,
-				constants.DefaultSlackTeamID,
-				"manual",
-			)
+			trackedMessages, err := app.FirestoreService.GetTrackedMessages(ctx, services.TrackedMessageQuery{
+				RepoFullName:  constants.DefaultRepoFullName,
+				PRNumber:      100 + i,
+				SlackChannel:  constants.DefaultSlackChannel,
+				SlackTeamID:   constants.DefaultSlackTeamID,
+				MessageSource: "manual",
+			})
 			require.NoError(t, err)
 			assert.Len(t, trackedMessages, 1, "Expected 1 tracked message for PR %d", 100+i)
 		}
@@ -240,15 +240,23 @@ func TestJobQueueManagement(t *testing.T) {
 		assert.Len(t, errors, 2, "Expected 2 errors from invalid jobs")
 
 		// Verify the valid jobs succeeded
-		trackedMessages300, err := app.FirestoreService.GetTrackedMessages(
-			ctx, constants.DefaultRepoFullName, 300, constants.DefaultSlackChannel, constants.DefaultSlackTeamID, "manual",
-		)
+		trackedMessages300, err := app.FirestoreService.GetTrackedMessages(ctx, services.TrackedMessageQuery{
+			RepoFullName:  constants.DefaultRepoFullName,
+			PRNumber:      300,
+			SlackChannel:  constants.DefaultSlackChannel,
+			SlackTeamID:   constants.DefaultSlackTeamID,
+			MessageSource: "manual",
+		})
 		require.NoError(t, err)
 		assert.Len(t, trackedMessages300, 1, "Valid job 1 should have created tracked message")
 
-		trackedMessages400, err := app.FirestoreService.GetTrackedMessages(
-			ctx, constants.DefaultRepoFullName, 400, constants.DefaultSlackChannel, constants.DefaultSlackTeamID, "manual",
-		)
+		trackedMessages400, err := app.FirestoreService.GetTrackedMessages(ctx, services.TrackedMessageQuery{
+			RepoFullName:  constants.DefaultRepoFullName,
+			PRNumber:      400,
+			SlackChannel:  constants.DefaultSlackChannel,
+			SlackTeamID:   constants.DefaultSlackTeamID,
+			MessageSource: "manual",
+		})
 		require.NoError(t, err)
 		assert.Len(t, trackedMessages400, 1, "Valid job 2 should have created tracked message")
 	})
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github-slack-notifier/internal/models"
+	"github-slack-notifier/internal/services"
 	"github-slack-notifier/tests/integration/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -58,14 +59,13 @@ func TestMixedWorkflow(t *testing.T) {
 		assert.Empty(t, errors)
 
 		// Verify manual PR link was tracked
-		trackedMessages, err := app.FirestoreService.GetTrackedMessages(
-			ctx,
-			constants.DefaultRepoFullName,
-			prNumber,
-			constants.DefaultSlackChannel,
-			constants.DefaultSlackTeamID,
-			"manual",
-		)
+		trackedMessages, err := app.FirestoreService.GetTrackedMessages(ctx, services.TrackedMessageQuery{
+			RepoFullName:  constants.DefaultRepoFullName,
+			PRNumber:      prNumber,
+			SlackChannel:  constants.DefaultSlackChannel,
+			SlackTeamID:   constants.DefaultSlackTeamID,
+			MessageSource: "manual",
+		})
 		require.NoError(t, err)
 		require.Len(t, trackedMessages, 1)
 		assert.Equal(t, slackMessageTS, trackedMessages[0].SlackMessageTS)
@@ -135,14 +135,13 @@ func TestMixedWorkflow(t *testing.T) {
 		}
 
 		// Verify final state: Manual PR link still tracked
-		finalTrackedMessages, err := app.FirestoreService.GetTrackedMessages(
-			ctx,
-			constants.DefaultRepoFullName,
-			prNumber,
-			constants.DefaultSlackChannel,
-			constants.DefaultSlackTeamID,
-			"manual",
-		)
+		finalTrackedMessages, err := app.FirestoreService.GetTrackedMessages(ctx, services.TrackedMessageQuery{
+			RepoFullName:  constants.DefaultRepoFullName,
+			PRNumber:      prNumber,
+			SlackChannel:  constants.DefaultSlackChannel,
+			SlackTeamID:   constants.DefaultSlackTeamID,
+			MessageSource: "manual",
+		})
 		require.NoError(t, err)
 		assert.Len(t, finalTrackedMessages, 1, "Manual PR link should still be tracked")
 	})
@@ -209,14 +208,14 @@ func TestMixedWorkflow(t *testing.T) {
 		assert.Len(t, errors, 1, "Expected 1 error from invalid job")
 
 		// Verify the valid jobs succeeded by checking database state
-		trackedMessages, err := app.FirestoreService.GetTrackedMessages(
-			ctx,
-			constants.DefaultRepoFullName,
-			100, // First PR from Slack message
-			constants.DefaultSlackChannel,
-			constants.DefaultSlackTeamID,
-			"manual",
-		)
+		// First PR from Slack message
+		trackedMessages, err := app.FirestoreService.GetTrackedMessages(ctx, services.TrackedMessageQuery{
+			RepoFullName:  constants.DefaultRepoFullName,
+			PRNumber:      100,
+			SlackChannel:  constants.DefaultSlackChannel,
+			SlackTeamID:   constants.DefaultSlackTeamID,
+			MessageSource: "manual",
+		})
 		require.NoError(t, err)
 		assert.Len(t, trackedMessages, 1, "Valid Slack job should have created tracked message")
 	})
@@ -279,15 +278,23 @@ func TestMixedWorkflow(t *testing.T) {
 		assert.Empty(t, errors)
 
 		// Verify workspace isolation: each workspace has its own tracked message
-		team1Messages, err := app.FirestoreService.GetTrackedMessages(
-			ctx, constants.DefaultRepoFullName, prNumber, channel1ID, team1ID, "manual",
-		)
+		team1Messages, err := app.FirestoreService.GetTrackedMessages(ctx, services.TrackedMessageQuery{
+			RepoFullName:  constants.DefaultRepoFullName,
+			PRNumber:      prNumber,
+			SlackChannel:  channel1ID,
+			SlackTeamID:   team1ID,
+			MessageSource: "manual",
+		})
 		require.NoError(t, err)
 		assert.Len(t, team1Messages, 1, "Team 1 should have 1 tracked message")
 
-		team2Messages, err := app.FirestoreService.GetTrackedMessages(
-			ctx, constants.DefaultRepoFullName, prNumber, channel2ID, team2ID, "manual",
-		)
+		team2Messages, err := app.FirestoreService.GetTrackedMessages(ctx, services.TrackedMessageQuery{
+			RepoFullName:  constants.DefaultRepoFullName,
+			PRNumber:      prNumber,
+			SlackChannel:  channel2ID,
+			SlackTeamID:   team2ID,
+			MessageSource: "manual",
+		})
 		require.NoError(t, err)
 		assert.Len(t, team2Messages, 1, "Team 2 should have 1 tracked message")
 
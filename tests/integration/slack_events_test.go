@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github-slack-notifier/internal/models"
+	"github-slack-notifier/internal/services"
 	"github-slack-notifier/tests/integration/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -58,14 +59,13 @@ func TestSlackEventProcessing(t *testing.T) {
 		assert.Empty(t, errors)
 
 		// Verify database state - tracked message should exist
-		trackedMessages, err := app.FirestoreService.GetTrackedMessages(
-			ctx,
-			constants.DefaultRepoFullName,
-			constants.DefaultPRNumber,
-			constants.DefaultSlackChannel,
-			constants.DefaultSlackTeamID,
-			"manual",
-		)
+		trackedMessages, err := app.FirestoreService.GetTrackedMessages(ctx, services.TrackedMessageQuery{
+			RepoFullName:  constants.DefaultRepoFullName,
+			PRNumber:      constants.DefaultPRNumber,
+			SlackChannel:  constants.DefaultSlackChannel,
+			SlackTeamID:   constants.DefaultSlackTeamID,
+			MessageSource: "manual",
+		})
 		require.NoError(t, err)
 		assert.Len(t, trackedMessages, 1)
 
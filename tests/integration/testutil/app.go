@@ -63,7 +63,7 @@ func SetupTestApp(t *testing.T) (*TestApp, context.Context, func()) {
 	firestoreService := services.NewFirestoreService(emulator.Client)
 
 	// Real Slack service - will fail API calls without valid workspace tokens
-	slackWorkspaceService := services.NewSlackWorkspaceService(emulator.Client)
+	slackWorkspaceService := services.NewSlackWorkspaceService(emulator.Client, nil)
 	slackHTTPClient := &http.Client{Timeout: 30 * time.Second}
 	realSlackService := services.NewSlackService(slackWorkspaceService, cfg.Emoji, cfg, slackHTTPClient)
 
@@ -78,7 +78,7 @@ func SetupTestApp(t *testing.T) (*TestApp, context.Context, func()) {
 
 	// For integration tests, we'll use mock Cloud Tasks service
 	// to capture jobs and process them in-memory for testing
-	realGitHubAuthService := services.NewGitHubAuthService(cfg, firestoreService)
+	realGitHubAuthService := services.NewGitHubAuthService(cfg, firestoreService, nil)
 
 	// Create handlers with services for testing
 	githubHandler := NewTestGitHubHandler(
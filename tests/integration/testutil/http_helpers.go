@@ -120,7 +120,7 @@ func (h HTTPTestHelpers) CreateGitHubHTTPRequest(payload []byte, eventType, webh
 	req := httptest.NewRequest(http.MethodPost, "/webhooks/github", bytes.NewReader(payload))
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("X-Github-Event", eventType)
-	req.Header.Set("X-Github-Delivery", "test-delivery-"+fmt.Sprintf("%d", time.Now().Unix()))
+	req.Header.Set("X-Github-Delivery", "test-delivery-"+fmt.Sprintf("%d", time.Now().UnixNano()))
 	req.Header.Set("User-Agent", "GitHub-Hookshot/test")
 
 	if webhookSecret != "" {
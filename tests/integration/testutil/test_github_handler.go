@@ -51,6 +51,7 @@ func NewTestGitHubHandler(
 		githubService,
 		webhookSecret,
 		emojiConfig,
+		handlers.GitHubHandlerOptions{},
 	)
 
 	return &TestGitHubHandler{